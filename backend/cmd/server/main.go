@@ -19,6 +19,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/setup"
 	"github.com/Wei-Shaw/sub2api/internal/web"
@@ -140,6 +141,18 @@ func runMainServer() {
 		log.Println("⚠️  WARNING: Running in SIMPLE mode - billing and quota checks are DISABLED")
 	}
 
+	shutdownTracing, err := tracing.Init(context.Background(), tracingOptions(cfg.Tracing))
+	if err != nil {
+		log.Fatalf("Failed to initialize tracing: %v", err)
+	}
+	defer func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := shutdownTracing(ctx); err != nil {
+			log.Printf("Failed to flush traces: %v", err)
+		}
+	}()
+
 	buildInfo := handler.BuildInfo{
 		Version:   Version,
 		BuildType: BuildType,
@@ -176,3 +189,18 @@ func runMainServer() {
 
 	log.Println("Server exited")
 }
+
+// tracingOptions 将配置映射为 tracing 包的启动参数，保持 tracing 包不依赖 config。
+func tracingOptions(cfg config.TracingConfig) tracing.Options {
+	return tracing.Options{
+		Enabled:        cfg.Enabled,
+		Protocol:       cfg.Protocol,
+		Endpoint:       cfg.Endpoint,
+		URLPath:        cfg.URLPath,
+		Insecure:       cfg.Insecure,
+		Headers:        cfg.Headers,
+		ServiceName:    cfg.ServiceName,
+		ServiceVersion: Version,
+		SampleRatio:    cfg.SampleRatio,
+	}
+}
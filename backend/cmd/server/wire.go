@@ -67,6 +67,9 @@ func provideCleanup(
 	opsAggregation *service.OpsAggregationService,
 	opsAlertEvaluator *service.OpsAlertEvaluatorService,
 	opsCleanup *service.OpsCleanupService,
+	adminAuditCleanup *service.AdminAuditCleanupService,
+	statement *service.StatementService,
+	proxyPool *service.ProxyPoolService,
 	opsScheduledReport *service.OpsScheduledReportService,
 	opsSystemLogSink *service.OpsSystemLogSink,
 	soraMediaCleanup *service.SoraMediaCleanupService,
@@ -76,6 +79,12 @@ func provideCleanup(
 	subscriptionExpiry *service.SubscriptionExpiryService,
 	subscriptionOrderExpiry *service.SubscriptionOrderExpiryService,
 	usageCleanup *service.UsageCleanupService,
+	usageExport *service.UsageExportService,
+	soraGenerationJob *service.SoraGenerationJobService,
+	userWebhook *service.UserWebhookService,
+	userNotification *service.UserNotificationService,
+	messageBatch *service.MessageBatchService,
+	openaiBatch *service.OpenAIBatchService,
 	idempotencyCleanup *service.IdempotencyCleanupService,
 	pricing *service.PricingService,
 	emailQueue *service.EmailQueueService,
@@ -112,6 +121,24 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"AdminAuditCleanupService", func() error {
+				if adminAuditCleanup != nil {
+					adminAuditCleanup.Stop()
+				}
+				return nil
+			}},
+			{"StatementService", func() error {
+				if statement != nil {
+					statement.Stop()
+				}
+				return nil
+			}},
+			{"ProxyPoolService", func() error {
+				if proxyPool != nil {
+					proxyPool.Stop()
+				}
+				return nil
+			}},
 			{"OpsSystemLogSink", func() error {
 				if opsSystemLogSink != nil {
 					opsSystemLogSink.Stop()
@@ -154,6 +181,42 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"UsageExportService", func() error {
+				if usageExport != nil {
+					usageExport.Stop()
+				}
+				return nil
+			}},
+			{"SoraGenerationJobService", func() error {
+				if soraGenerationJob != nil {
+					soraGenerationJob.Stop()
+				}
+				return nil
+			}},
+			{"UserWebhookService", func() error {
+				if userWebhook != nil {
+					userWebhook.Stop()
+				}
+				return nil
+			}},
+			{"UserNotificationService", func() error {
+				if userNotification != nil {
+					userNotification.Stop()
+				}
+				return nil
+			}},
+			{"MessageBatchService", func() error {
+				if messageBatch != nil {
+					messageBatch.Stop()
+				}
+				return nil
+			}},
+			{"OpenAIBatchService", func() error {
+				if openaiBatch != nil {
+					openaiBatch.Stop()
+				}
+				return nil
+			}},
 			{"IdempotencyCleanupService", func() error {
 				if idempotencyCleanup != nil {
 					idempotencyCleanup.Stop()
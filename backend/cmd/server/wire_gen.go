@@ -59,12 +59,16 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	billingCache := repository.NewBillingCache(redisClient)
 	userSubscriptionRepository := repository.NewUserSubscriptionRepository(client)
 	apiKeyRepository := repository.NewAPIKeyRepository(client, db)
-	billingCacheService := service.NewBillingCacheService(billingCache, userRepository, userSubscriptionRepository, apiKeyRepository, configConfig)
+	organizationRepository := repository.NewOrganizationRepository(client, db)
+	billingCacheService := service.NewBillingCacheService(billingCache, userRepository, userSubscriptionRepository, apiKeyRepository, organizationRepository, configConfig)
 	userGroupRateRepository := repository.NewUserGroupRateRepository(db)
 	apiKeyCache := repository.NewAPIKeyCache(redisClient)
 	apiKeyService := service.NewAPIKeyService(apiKeyRepository, userRepository, groupRepository, userSubscriptionRepository, userGroupRateRepository, apiKeyCache, configConfig)
 	apiKeyService.SetRateLimitCacheInvalidator(billingCache)
 	apiKeyAuthCacheInvalidator := service.ProvideAPIKeyAuthCacheInvalidator(apiKeyService)
+	organizationService := service.ProvideOrganizationService(organizationRepository, userRepository, apiKeyService, billingCacheService, apiKeyAuthCacheInvalidator)
+	adminAPITokenRepository := repository.NewAdminAPITokenRepository(db)
+	adminAPITokenService := service.NewAdminAPITokenService(adminAPITokenRepository)
 	promoService := service.NewPromoService(promoCodeRepository, userRepository, billingCacheService, client, apiKeyAuthCacheInvalidator)
 	subscriptionService := service.NewSubscriptionService(groupRepository, userSubscriptionRepository, billingCacheService, client, configConfig)
 	authService := service.NewAuthService(userRepository, redeemCodeRepository, refreshTokenCache, configConfig, settingService, emailService, turnstileService, emailQueueService, promoService, subscriptionService)
@@ -82,7 +86,6 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
 	usageLogRepository := repository.NewUsageLogRepository(client, db)
 	usageService := service.NewUsageService(usageLogRepository, userRepository, client, apiKeyAuthCacheInvalidator)
-	usageHandler := handler.NewUsageHandler(usageService, apiKeyService)
 	redeemHandler := handler.NewRedeemHandler(redeemService)
 	subscriptionHandler := handler.NewSubscriptionHandler(subscriptionService)
 	announcementRepository := repository.NewAnnouncementRepository(client)
@@ -91,7 +94,7 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	announcementHandler := handler.NewAnnouncementHandler(announcementService)
 	subscriptionOrderRepository := repository.NewSubscriptionOrderRepository(client)
 	xunhuPayClient := service.NewXunhuPayClient()
-	subscriptionOrderService := service.NewSubscriptionOrderService(client, groupRepository, subscriptionOrderRepository, subscriptionService, settingService, xunhuPayClient)
+	subscriptionOrderService := service.NewSubscriptionOrderService(client, groupRepository, subscriptionOrderRepository, userRepository, subscriptionService, billingCacheService, apiKeyAuthCacheInvalidator, settingService, xunhuPayClient)
 	purchaseHandler := handler.NewPurchaseHandler(subscriptionOrderService)
 	paymentHandler := handler.NewPaymentHandler(subscriptionOrderService)
 	dashboardAggregationRepository := repository.NewDashboardAggregationRepository(db)
@@ -104,7 +107,12 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	dashboardAggregationService := service.ProvideDashboardAggregationService(dashboardAggregationRepository, timingWheelService, configConfig)
 	dashboardHandler := admin.NewDashboardHandler(dashboardService, dashboardAggregationService)
 	schedulerCache := repository.NewSchedulerCache(redisClient)
-	accountRepository := repository.NewAccountRepository(client, db, schedulerCache)
+	credentialKeyRing, err := repository.NewCredentialKeyRing(client, configConfig)
+	if err != nil {
+		return nil, err
+	}
+	credentialCipher := repository.ProvideCredentialCipher(credentialKeyRing)
+	accountRepository := repository.NewAccountRepository(client, db, schedulerCache, credentialCipher)
 	soraAccountRepository := repository.NewSoraAccountRepository(db)
 	proxyRepository := repository.NewProxyRepository(client, db)
 	proxyExitInfoProber := repository.NewProxyExitInfoProber(configConfig)
@@ -128,8 +136,11 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	timeoutCounterCache := repository.NewTimeoutCounterCache(redisClient)
 	geminiTokenCache := repository.NewGeminiTokenCache(redisClient)
 	compositeTokenCacheInvalidator := service.NewCompositeTokenCacheInvalidator(geminiTokenCache)
-	rateLimitService := service.ProvideRateLimitService(accountRepository, usageLogRepository, configConfig, geminiQuotaService, tempUnschedCache, timeoutCounterCache, settingService, compositeTokenCacheInvalidator)
-	httpUpstream := repository.NewHTTPUpstream(configConfig)
+	accountHealthScorer := service.NewAccountHealthScorer(configConfig)
+	rateLimitService := service.ProvideRateLimitService(accountRepository, usageLogRepository, configConfig, geminiQuotaService, tempUnschedCache, timeoutCounterCache, settingService, compositeTokenCacheInvalidator, accountHealthScorer)
+	proxyPoolRepository := repository.NewProxyPoolRepository(db)
+	proxyPoolService := service.ProvideProxyPoolService(proxyPoolRepository, proxyRepository, proxyExitInfoProber, proxyLatencyCache, configConfig)
+	httpUpstream := repository.ProvideHTTPUpstream(configConfig, proxyPoolService)
 	claudeUsageFetcher := repository.NewClaudeUsageFetcher(httpUpstream)
 	antigravityQuotaFetcher := service.NewAntigravityQuotaFetcher(proxyRepository)
 	usageCache := service.NewUsageCache()
@@ -145,6 +156,7 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	crsSyncService := service.NewCRSSyncService(accountRepository, proxyRepository, oAuthService, openAIOAuthService, geminiOAuthService, configConfig)
 	sessionLimitCache := repository.ProvideSessionLimitCache(redisClient, configConfig)
 	rpmCache := repository.NewRPMCache(redisClient)
+	apiKeyThrottleService := service.ProvideAPIKeyThrottleService(concurrencyService, rpmCache, apiKeyService)
 	accountHandler := admin.NewAccountHandler(adminService, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, rateLimitService, accountUsageService, accountTestService, concurrencyService, crsSyncService, sessionLimitCache, rpmCache, compositeTokenCacheInvalidator)
 	adminAnnouncementHandler := admin.NewAnnouncementHandler(announcementService)
 	dataManagementService := service.NewDataManagementService()
@@ -166,10 +178,10 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	identityService := service.NewIdentityService(identityCache)
 	deferredService := service.ProvideDeferredService(accountRepository, timingWheelService)
 	claudeTokenProvider := service.NewClaudeTokenProvider(accountRepository, geminiTokenCache, oAuthService)
-	digestSessionStore := service.NewDigestSessionStore()
-	gatewayService := service.NewGatewayService(accountRepository, groupRepository, usageLogRepository, userRepository, userSubscriptionRepository, userGroupRateRepository, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, identityService, httpUpstream, deferredService, claudeTokenProvider, sessionLimitCache, rpmCache, digestSessionStore)
+	digestSessionCache := repository.ProvideDigestSessionCache(redisClient, configConfig)
+	gatewayService := service.NewGatewayService(accountRepository, groupRepository, usageLogRepository, userRepository, userSubscriptionRepository, userGroupRateRepository, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, identityService, httpUpstream, deferredService, claudeTokenProvider, sessionLimitCache, rpmCache, digestSessionCache, accountHealthScorer)
 	openAITokenProvider := service.NewOpenAITokenProvider(accountRepository, geminiTokenCache, openAIOAuthService)
-	openAIGatewayService := service.NewOpenAIGatewayService(accountRepository, usageLogRepository, userRepository, userSubscriptionRepository, userGroupRateRepository, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, httpUpstream, deferredService, openAITokenProvider)
+	openAIGatewayService := service.NewOpenAIGatewayService(accountRepository, usageLogRepository, userRepository, userSubscriptionRepository, userGroupRateRepository, gatewayCache, configConfig, schedulerSnapshotService, concurrencyService, billingService, rateLimitService, billingCacheService, httpUpstream, deferredService, openAITokenProvider, accountHealthScorer)
 	geminiMessagesCompatService := service.NewGeminiMessagesCompatService(accountRepository, groupRepository, gatewayCache, schedulerSnapshotService, geminiTokenProvider, rateLimitService, httpUpstream, antigravityGatewayService, configConfig)
 	opsSystemLogSink := service.ProvideOpsSystemLogSink(opsRepository)
 	opsService := service.NewOpsService(opsRepository, settingRepository, configConfig, accountRepository, userRepository, concurrencyService, gatewayService, openAIGatewayService, geminiMessagesCompatService, antigravityGatewayService, opsSystemLogSink)
@@ -190,7 +202,10 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	adminSubscriptionHandler := admin.NewSubscriptionHandler(subscriptionService)
 	usageCleanupRepository := repository.NewUsageCleanupRepository(client, db)
 	usageCleanupService := service.ProvideUsageCleanupService(usageCleanupRepository, timingWheelService, dashboardAggregationService, configConfig)
-	adminUsageHandler := admin.NewUsageHandler(usageService, apiKeyService, adminService, usageCleanupService)
+	usageExportRepository := repository.NewUsageExportRepository(db)
+	usageExportService := service.ProvideUsageExportService(usageExportRepository, usageLogRepository, soraS3Storage, timingWheelService, configConfig)
+	adminUsageHandler := admin.NewUsageHandler(usageService, apiKeyService, adminService, usageCleanupService, usageExportService)
+	usageHandler := handler.NewUsageHandler(usageService, apiKeyService, usageExportService)
 	userAttributeDefinitionRepository := repository.NewUserAttributeDefinitionRepository(client)
 	userAttributeValueRepository := repository.NewUserAttributeValueRepository(client)
 	userAttributeService := service.NewUserAttributeService(userAttributeDefinitionRepository, userAttributeValueRepository)
@@ -205,38 +220,77 @@ func initializeApplication(buildInfo handler.BuildInfo) (*Application, error) {
 	scheduledTestService := service.ProvideScheduledTestService(scheduledTestPlanRepository, scheduledTestResultRepository)
 	scheduledTestHandler := admin.NewScheduledTestHandler(scheduledTestService)
 	orderHandler := admin.NewOrderHandler(subscriptionOrderService)
-	adminHandlers := handler.ProvideAdminHandlers(dashboardHandler, adminUserHandler, groupHandler, accountHandler, adminAnnouncementHandler, dataManagementHandler, oAuthHandler, openAIOAuthHandler, geminiOAuthHandler, antigravityOAuthHandler, proxyHandler, adminRedeemHandler, promoHandler, settingHandler, opsHandler, systemHandler, adminSubscriptionHandler, adminUsageHandler, userAttributeHandler, errorPassthroughHandler, adminAPIKeyHandler, scheduledTestHandler, orderHandler)
+	adminOrganizationHandler := admin.NewOrganizationHandler(organizationService)
+	adminAPITokenHandler := admin.NewAdminAPITokenHandler(adminAPITokenService)
+	adminAuditRepository := repository.NewAdminAuditRepository(db)
+	adminAuditService := service.ProvideAdminAuditService(adminAuditRepository, adminService, settingService, promoService, subscriptionService, errorPassthroughService, configConfig)
+	auditLogHandler := admin.NewAuditLogHandler(adminAuditService)
+	statementRepository := repository.NewStatementRepository(db)
+	statementService := service.ProvideStatementService(statementRepository, userRepository, emailService, settingService, db, redisClient, configConfig)
+	statementHandler := admin.NewStatementHandler(statementService)
+	proxyPoolHandler := admin.NewProxyPoolHandler(proxyPoolService)
+	accountCredentialRepository := repository.NewAccountCredentialRepository(db)
+	credentialEncryptionService := service.NewCredentialEncryptionService(credentialKeyRing, accountCredentialRepository)
+	credentialEncryptionHandler := admin.NewCredentialEncryptionHandler(credentialEncryptionService)
+	adminHandlers := handler.ProvideAdminHandlers(dashboardHandler, adminUserHandler, groupHandler, accountHandler, adminAnnouncementHandler, dataManagementHandler, oAuthHandler, openAIOAuthHandler, geminiOAuthHandler, antigravityOAuthHandler, proxyHandler, adminRedeemHandler, promoHandler, settingHandler, opsHandler, systemHandler, adminSubscriptionHandler, adminUsageHandler, userAttributeHandler, errorPassthroughHandler, adminAPIKeyHandler, scheduledTestHandler, orderHandler, adminOrganizationHandler, adminAPITokenHandler, auditLogHandler, statementHandler, proxyPoolHandler, credentialEncryptionHandler)
 	usageRecordWorkerPool := service.NewUsageRecordWorkerPool(configConfig)
 	userMsgQueueCache := repository.NewUserMsgQueueCache(redisClient)
 	userMessageQueueService := service.ProvideUserMessageQueueService(userMsgQueueCache, rpmCache, configConfig)
-	gatewayHandler := handler.NewGatewayHandler(gatewayService, geminiMessagesCompatService, antigravityGatewayService, userService, concurrencyService, billingCacheService, usageService, apiKeyService, usageRecordWorkerPool, errorPassthroughService, userMessageQueueService, configConfig, settingService)
-	openAIGatewayHandler := handler.NewOpenAIGatewayHandler(openAIGatewayService, concurrencyService, billingCacheService, apiKeyService, usageRecordWorkerPool, errorPassthroughService, configConfig)
+	responseCache := repository.NewResponseCache(redisClient)
+	responseCacheService := service.NewResponseCacheService(responseCache, billingService, usageLogRepository, userRepository, userSubscriptionRepository, userGroupRateRepository, billingCacheService, configConfig)
+	gatewayHandler := handler.NewGatewayHandler(gatewayService, geminiMessagesCompatService, antigravityGatewayService, userService, concurrencyService, billingCacheService, usageService, apiKeyService, usageRecordWorkerPool, errorPassthroughService, userMessageQueueService, responseCacheService, configConfig, settingService)
+	openAIGatewayHandler := handler.NewOpenAIGatewayHandler(openAIGatewayService, concurrencyService, billingCacheService, apiKeyService, usageRecordWorkerPool, errorPassthroughService, responseCacheService, configConfig)
 	soraSDKClient := service.ProvideSoraSDKClient(configConfig, httpUpstream, openAITokenProvider, accountRepository, soraAccountRepository)
 	soraMediaStorage := service.ProvideSoraMediaStorage(configConfig)
 	soraGatewayService := service.NewSoraGatewayService(soraSDKClient, rateLimitService, httpUpstream, configConfig)
-	soraClientHandler := handler.NewSoraClientHandler(soraGenerationService, soraQuotaService, soraS3Storage, soraGatewayService, gatewayService, soraMediaStorage, apiKeyService)
+	soraGenerationJobRepository := repository.NewSoraGenerationJobRepository(db)
+	soraGenerationJobService := service.ProvideSoraGenerationJobService(soraGenerationJobRepository, soraGenerationService, soraQuotaService, soraS3Storage, soraMediaStorage, configConfig)
+	soraClientHandler := handler.NewSoraClientHandler(soraGenerationService, soraQuotaService, soraS3Storage, soraGatewayService, gatewayService, soraMediaStorage, apiKeyService, soraGenerationJobService)
 	soraGatewayHandler := handler.NewSoraGatewayHandler(gatewayService, soraGatewayService, concurrencyService, billingCacheService, usageRecordWorkerPool, configConfig)
 	handlerSettingHandler := handler.ProvideSettingHandler(settingService, buildInfo)
 	totpHandler := handler.NewTotpHandler(totpService)
 	idempotencyCoordinator := service.ProvideIdempotencyCoordinator(idempotencyRepository, configConfig)
 	idempotencyCleanupService := service.ProvideIdempotencyCleanupService(idempotencyRepository, configConfig)
-	handlers := handler.ProvideHandlers(authHandler, userHandler, apiKeyHandler, usageHandler, redeemHandler, subscriptionHandler, announcementHandler, purchaseHandler, paymentHandler, adminHandlers, gatewayHandler, openAIGatewayHandler, soraGatewayHandler, soraClientHandler, handlerSettingHandler, totpHandler, idempotencyCoordinator, idempotencyCleanupService)
+	prometheusService := service.NewPrometheusService(configConfig, openAIGatewayService, usageRecordWorkerPool, schedulerSnapshotService, billingCacheService, concurrencyService, accountHealthScorer)
+	metricsHandler := handler.NewMetricsHandler(prometheusService, configConfig)
+	organizationHandler := handler.NewOrganizationHandler(organizationService)
+	userIdentityRepository := repository.NewUserIdentityRepository(db)
+	ssoService := service.NewSSOService(configConfig, userIdentityRepository, userRepository, authService, totpService)
+	ssoHandler := handler.NewSSOHandler(ssoService)
+	handlerStatementHandler := handler.NewStatementHandler(statementService)
+	userWebhookRepository := repository.NewUserWebhookRepository(db)
+	userWebhookService := service.ProvideUserWebhookService(userWebhookRepository, userRepository, apiKeyRepository, userSubscriptionRepository, soraGenerationService, subscriptionOrderService, timingWheelService, configConfig)
+	userWebhookHandler := handler.NewUserWebhookHandler(userWebhookService)
+	userNotificationRepository := repository.NewUserNotificationRepository(db)
+	userNotificationService := service.ProvideUserNotificationService(userNotificationRepository, userRepository, apiKeyRepository, userSubscriptionRepository, emailService, emailQueueService, settingService, timingWheelService, configConfig)
+	userNotificationHandler := handler.NewUserNotificationHandler(userNotificationService)
+	messageBatchRepository := repository.NewMessageBatchRepository(db)
+	messageBatchService := service.ProvideMessageBatchService(messageBatchRepository, apiKeyRepository, organizationRepository, gatewayService, antigravityGatewayService, concurrencyService, subscriptionService, billingCacheService, apiKeyService, timingWheelService, configConfig)
+	messageBatchHandler := handler.NewMessageBatchHandler(messageBatchService, billingCacheService)
+	openAIFileRepository := repository.NewOpenAIFileRepository(db)
+	openAIFileService := service.NewOpenAIFileService(openAIFileRepository, soraS3Storage, configConfig)
+	openAIBatchRepository := repository.NewOpenAIBatchRepository(db)
+	openAIBatchService := service.ProvideOpenAIBatchService(openAIBatchRepository, openAIFileService, apiKeyRepository, organizationRepository, openAIGatewayService, concurrencyService, subscriptionService, billingCacheService, apiKeyService, timingWheelService, configConfig)
+	openAIBatchHandler := handler.NewOpenAIBatchHandler(openAIBatchService, openAIFileService, billingCacheService)
+	handlers := handler.ProvideHandlers(authHandler, userHandler, apiKeyHandler, usageHandler, redeemHandler, subscriptionHandler, announcementHandler, purchaseHandler, paymentHandler, adminHandlers, gatewayHandler, openAIGatewayHandler, soraGatewayHandler, soraClientHandler, handlerSettingHandler, totpHandler, metricsHandler, organizationHandler, ssoHandler, handlerStatementHandler, userWebhookHandler, userNotificationHandler, messageBatchHandler, openAIBatchHandler, idempotencyCoordinator, idempotencyCleanupService)
 	jwtAuthMiddleware := middleware.NewJWTAuthMiddleware(authService, userService)
-	adminAuthMiddleware := middleware.NewAdminAuthMiddleware(authService, userService, settingService)
+	adminAuthMiddleware := middleware.NewAdminAuthMiddleware(authService, userService, settingService, adminAPITokenService)
+	adminAuditMiddleware := middleware.NewAdminAuditMiddleware(adminAuditService)
 	apiKeyAuthMiddleware := middleware.NewAPIKeyAuthMiddleware(apiKeyService, subscriptionService, configConfig)
-	engine := server.ProvideRouter(configConfig, handlers, jwtAuthMiddleware, adminAuthMiddleware, apiKeyAuthMiddleware, apiKeyService, subscriptionService, opsService, settingService, redisClient)
+	engine := server.ProvideRouter(configConfig, handlers, jwtAuthMiddleware, adminAuthMiddleware, adminAuditMiddleware, apiKeyAuthMiddleware, apiKeyService, apiKeyThrottleService, subscriptionService, opsService, settingService, redisClient)
 	httpServer := server.ProvideHTTPServer(configConfig, engine)
 	opsMetricsCollector := service.ProvideOpsMetricsCollector(opsRepository, settingRepository, accountRepository, concurrencyService, db, redisClient, configConfig)
 	opsAggregationService := service.ProvideOpsAggregationService(opsRepository, settingRepository, db, redisClient, configConfig)
 	opsAlertEvaluatorService := service.ProvideOpsAlertEvaluatorService(opsService, opsRepository, emailService, redisClient, configConfig)
 	opsCleanupService := service.ProvideOpsCleanupService(opsRepository, db, redisClient, configConfig)
+	adminAuditCleanupService := service.ProvideAdminAuditCleanupService(db, redisClient, configConfig)
 	opsScheduledReportService := service.ProvideOpsScheduledReportService(opsService, userService, emailService, redisClient, configConfig)
 	soraMediaCleanupService := service.ProvideSoraMediaCleanupService(soraMediaStorage, configConfig)
-	tokenRefreshService := service.ProvideTokenRefreshService(accountRepository, soraAccountRepository, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, compositeTokenCacheInvalidator, schedulerCache, configConfig, tempUnschedCache)
+	tokenRefreshService := service.ProvideTokenRefreshService(accountRepository, soraAccountRepository, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, compositeTokenCacheInvalidator, schedulerCache, configConfig, tempUnschedCache, accountHealthScorer)
 	accountExpiryService := service.ProvideAccountExpiryService(accountRepository)
 	subscriptionExpiryService := service.ProvideSubscriptionExpiryService(userSubscriptionRepository)
 	scheduledTestRunnerService := service.ProvideScheduledTestRunnerService(scheduledTestPlanRepository, scheduledTestService, accountTestService, configConfig)
-	v := provideCleanup(client, redisClient, opsMetricsCollector, opsAggregationService, opsAlertEvaluatorService, opsCleanupService, opsScheduledReportService, opsSystemLogSink, soraMediaCleanupService, schedulerSnapshotService, tokenRefreshService, accountExpiryService, subscriptionExpiryService, usageCleanupService, idempotencyCleanupService, pricingService, emailQueueService, billingCacheService, usageRecordWorkerPool, subscriptionService, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, openAIGatewayService, scheduledTestRunnerService)
+	v := provideCleanup(client, redisClient, opsMetricsCollector, opsAggregationService, opsAlertEvaluatorService, opsCleanupService, adminAuditCleanupService, statementService, proxyPoolService, opsScheduledReportService, opsSystemLogSink, soraMediaCleanupService, schedulerSnapshotService, tokenRefreshService, accountExpiryService, subscriptionExpiryService, usageCleanupService, usageExportService, soraGenerationJobService, userWebhookService, userNotificationService, messageBatchService, openAIBatchService, idempotencyCleanupService, pricingService, emailQueueService, billingCacheService, usageRecordWorkerPool, subscriptionService, oAuthService, openAIOAuthService, geminiOAuthService, antigravityOAuthService, openAIGatewayService, scheduledTestRunnerService)
 	application := &Application{
 		Server:  httpServer,
 		Cleanup: v,
@@ -265,6 +319,9 @@ func provideCleanup(
 	opsAggregation *service.OpsAggregationService,
 	opsAlertEvaluator *service.OpsAlertEvaluatorService,
 	opsCleanup *service.OpsCleanupService,
+	adminAuditCleanup *service.AdminAuditCleanupService,
+	statement *service.StatementService,
+	proxyPool *service.ProxyPoolService,
 	opsScheduledReport *service.OpsScheduledReportService,
 	opsSystemLogSink *service.OpsSystemLogSink,
 	soraMediaCleanup *service.SoraMediaCleanupService,
@@ -273,6 +330,12 @@ func provideCleanup(
 	accountExpiry *service.AccountExpiryService,
 	subscriptionExpiry *service.SubscriptionExpiryService,
 	usageCleanup *service.UsageCleanupService,
+	usageExport *service.UsageExportService,
+	soraGenerationJob *service.SoraGenerationJobService,
+	userWebhook *service.UserWebhookService,
+	userNotification *service.UserNotificationService,
+	messageBatch *service.MessageBatchService,
+	openaiBatch *service.OpenAIBatchService,
 	idempotencyCleanup *service.IdempotencyCleanupService,
 	pricing *service.PricingService,
 	emailQueue *service.EmailQueueService,
@@ -308,6 +371,24 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"AdminAuditCleanupService", func() error {
+				if adminAuditCleanup != nil {
+					adminAuditCleanup.Stop()
+				}
+				return nil
+			}},
+			{"StatementService", func() error {
+				if statement != nil {
+					statement.Stop()
+				}
+				return nil
+			}},
+			{"ProxyPoolService", func() error {
+				if proxyPool != nil {
+					proxyPool.Stop()
+				}
+				return nil
+			}},
 			{"OpsSystemLogSink", func() error {
 				if opsSystemLogSink != nil {
 					opsSystemLogSink.Stop()
@@ -350,6 +431,42 @@ func provideCleanup(
 				}
 				return nil
 			}},
+			{"UsageExportService", func() error {
+				if usageExport != nil {
+					usageExport.Stop()
+				}
+				return nil
+			}},
+			{"SoraGenerationJobService", func() error {
+				if soraGenerationJob != nil {
+					soraGenerationJob.Stop()
+				}
+				return nil
+			}},
+			{"UserWebhookService", func() error {
+				if userWebhook != nil {
+					userWebhook.Stop()
+				}
+				return nil
+			}},
+			{"UserNotificationService", func() error {
+				if userNotification != nil {
+					userNotification.Stop()
+				}
+				return nil
+			}},
+			{"MessageBatchService", func() error {
+				if messageBatch != nil {
+					messageBatch.Stop()
+				}
+				return nil
+			}},
+			{"OpenAIBatchService", func() error {
+				if openaiBatch != nil {
+					openaiBatch.Stop()
+				}
+				return nil
+			}},
 			{"IdempotencyCleanupService", func() error {
 				if idempotencyCleanup != nil {
 					idempotencyCleanup.Stop()
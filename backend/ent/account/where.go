@@ -95,6 +95,11 @@ func ProxyID(v int64) predicate.Account {
 	return predicate.Account(sql.FieldEQ(FieldProxyID, v))
 }
 
+// ProxyPoolID applies equality check predicate on the "proxy_pool_id" field. It's identical to ProxyPoolIDEQ.
+func ProxyPoolID(v int64) predicate.Account {
+	return predicate.Account(sql.FieldEQ(FieldProxyPoolID, v))
+}
+
 // Concurrency applies equality check predicate on the "concurrency" field. It's identical to ConcurrencyEQ.
 func Concurrency(v int) predicate.Account {
 	return predicate.Account(sql.FieldEQ(FieldConcurrency, v))
@@ -615,6 +620,56 @@ func ProxyIDNotNil() predicate.Account {
 	return predicate.Account(sql.FieldNotNull(FieldProxyID))
 }
 
+// ProxyPoolIDEQ applies the EQ predicate on the "proxy_pool_id" field.
+func ProxyPoolIDEQ(v int64) predicate.Account {
+	return predicate.Account(sql.FieldEQ(FieldProxyPoolID, v))
+}
+
+// ProxyPoolIDNEQ applies the NEQ predicate on the "proxy_pool_id" field.
+func ProxyPoolIDNEQ(v int64) predicate.Account {
+	return predicate.Account(sql.FieldNEQ(FieldProxyPoolID, v))
+}
+
+// ProxyPoolIDIn applies the In predicate on the "proxy_pool_id" field.
+func ProxyPoolIDIn(vs ...int64) predicate.Account {
+	return predicate.Account(sql.FieldIn(FieldProxyPoolID, vs...))
+}
+
+// ProxyPoolIDNotIn applies the NotIn predicate on the "proxy_pool_id" field.
+func ProxyPoolIDNotIn(vs ...int64) predicate.Account {
+	return predicate.Account(sql.FieldNotIn(FieldProxyPoolID, vs...))
+}
+
+// ProxyPoolIDGT applies the GT predicate on the "proxy_pool_id" field.
+func ProxyPoolIDGT(v int64) predicate.Account {
+	return predicate.Account(sql.FieldGT(FieldProxyPoolID, v))
+}
+
+// ProxyPoolIDGTE applies the GTE predicate on the "proxy_pool_id" field.
+func ProxyPoolIDGTE(v int64) predicate.Account {
+	return predicate.Account(sql.FieldGTE(FieldProxyPoolID, v))
+}
+
+// ProxyPoolIDLT applies the LT predicate on the "proxy_pool_id" field.
+func ProxyPoolIDLT(v int64) predicate.Account {
+	return predicate.Account(sql.FieldLT(FieldProxyPoolID, v))
+}
+
+// ProxyPoolIDLTE applies the LTE predicate on the "proxy_pool_id" field.
+func ProxyPoolIDLTE(v int64) predicate.Account {
+	return predicate.Account(sql.FieldLTE(FieldProxyPoolID, v))
+}
+
+// ProxyPoolIDIsNil applies the IsNil predicate on the "proxy_pool_id" field.
+func ProxyPoolIDIsNil() predicate.Account {
+	return predicate.Account(sql.FieldIsNull(FieldProxyPoolID))
+}
+
+// ProxyPoolIDNotNil applies the NotNil predicate on the "proxy_pool_id" field.
+func ProxyPoolIDNotNil() predicate.Account {
+	return predicate.Account(sql.FieldNotNull(FieldProxyPoolID))
+}
+
 // ConcurrencyEQ applies the EQ predicate on the "concurrency" field.
 func ConcurrencyEQ(v int) predicate.Account {
 	return predicate.Account(sql.FieldEQ(FieldConcurrency, v))
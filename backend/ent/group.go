@@ -11,6 +11,7 @@ import (
 	"entgo.io/ent"
 	"entgo.io/ent/dialect/sql"
 	"github.com/Wei-Shaw/sub2api/ent/group"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
 )
 
 // Group is the model entity for the Group schema.
@@ -88,6 +89,14 @@ type Group struct {
 	AllowMessagesDispatch bool `json:"allow_messages_dispatch,omitempty"`
 	// 默认映射模型 ID，当账号级映射找不到时使用此值
 	DefaultMappedModel string `json:"default_mapped_model,omitempty"`
+	// 是否对确定性请求（temperature=0）启用精确响应缓存
+	ResponseCacheEnabled bool `json:"response_cache_enabled,omitempty"`
+	// 响应缓存 TTL（秒），0 表示使用全局默认值
+	ResponseCacheTTLSeconds int `json:"response_cache_ttl_seconds,omitempty"`
+	// 缓存命中计费比例（相对正常价格），0 表示免费
+	ResponseCachePriceRatio float64 `json:"response_cache_price_ratio,omitempty"`
+	// 分组内有序模型降级链：所有账号限流/过载/prompt 过长时按序切换模型
+	ModelFallbackChains []domain.ModelFallbackChain `json:"model_fallback_chains,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
 	// The values are being populated by the GroupQuery when eager-loading is set.
 	Edges        GroupEdges `json:"edges"`
@@ -205,13 +214,13 @@ func (*Group) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case group.FieldModelRouting, group.FieldSupportedModelScopes:
+		case group.FieldModelRouting, group.FieldSupportedModelScopes, group.FieldModelFallbackChains:
 			values[i] = new([]byte)
-		case group.FieldIsExclusive, group.FieldPurchaseEnabled, group.FieldClaudeCodeOnly, group.FieldModelRoutingEnabled, group.FieldMcpXMLInject, group.FieldAllowMessagesDispatch:
+		case group.FieldIsExclusive, group.FieldPurchaseEnabled, group.FieldClaudeCodeOnly, group.FieldModelRoutingEnabled, group.FieldMcpXMLInject, group.FieldAllowMessagesDispatch, group.FieldResponseCacheEnabled:
 			values[i] = new(sql.NullBool)
-		case group.FieldRateMultiplier, group.FieldDailyLimitUsd, group.FieldWeeklyLimitUsd, group.FieldMonthlyLimitUsd, group.FieldPurchasePrice, group.FieldImagePrice1k, group.FieldImagePrice2k, group.FieldImagePrice4k, group.FieldSoraImagePrice360, group.FieldSoraImagePrice540, group.FieldSoraVideoPricePerRequest, group.FieldSoraVideoPricePerRequestHd:
+		case group.FieldRateMultiplier, group.FieldDailyLimitUsd, group.FieldWeeklyLimitUsd, group.FieldMonthlyLimitUsd, group.FieldPurchasePrice, group.FieldImagePrice1k, group.FieldImagePrice2k, group.FieldImagePrice4k, group.FieldSoraImagePrice360, group.FieldSoraImagePrice540, group.FieldSoraVideoPricePerRequest, group.FieldSoraVideoPricePerRequestHd, group.FieldResponseCachePriceRatio:
 			values[i] = new(sql.NullFloat64)
-		case group.FieldID, group.FieldDefaultValidityDays, group.FieldPurchaseDisplayOrder, group.FieldSoraStorageQuotaBytes, group.FieldFallbackGroupID, group.FieldFallbackGroupIDOnInvalidRequest, group.FieldSortOrder:
+		case group.FieldID, group.FieldDefaultValidityDays, group.FieldPurchaseDisplayOrder, group.FieldSoraStorageQuotaBytes, group.FieldFallbackGroupID, group.FieldFallbackGroupIDOnInvalidRequest, group.FieldSortOrder, group.FieldResponseCacheTTLSeconds:
 			values[i] = new(sql.NullInt64)
 		case group.FieldName, group.FieldDescription, group.FieldStatus, group.FieldPlatform, group.FieldSubscriptionType, group.FieldDefaultMappedModel:
 			values[i] = new(sql.NullString)
@@ -467,6 +476,32 @@ func (_m *Group) assignValues(columns []string, values []any) error {
 			} else if value.Valid {
 				_m.DefaultMappedModel = value.String
 			}
+		case group.FieldResponseCacheEnabled:
+			if value, ok := values[i].(*sql.NullBool); !ok {
+				return fmt.Errorf("unexpected type %T for field response_cache_enabled", values[i])
+			} else if value.Valid {
+				_m.ResponseCacheEnabled = value.Bool
+			}
+		case group.FieldResponseCacheTTLSeconds:
+			if value, ok := values[i].(*sql.NullInt64); !ok {
+				return fmt.Errorf("unexpected type %T for field response_cache_ttl_seconds", values[i])
+			} else if value.Valid {
+				_m.ResponseCacheTTLSeconds = int(value.Int64)
+			}
+		case group.FieldResponseCachePriceRatio:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field response_cache_price_ratio", values[i])
+			} else if value.Valid {
+				_m.ResponseCachePriceRatio = value.Float64
+			}
+		case group.FieldModelFallbackChains:
+			if value, ok := values[i].(*[]byte); !ok {
+				return fmt.Errorf("unexpected type %T for field model_fallback_chains", values[i])
+			} else if value != nil && len(*value) > 0 {
+				if err := json.Unmarshal(*value, &_m.ModelFallbackChains); err != nil {
+					return fmt.Errorf("unmarshal field model_fallback_chains: %w", err)
+				}
+			}
 		default:
 			_m.selectValues.Set(columns[i], values[i])
 		}
@@ -682,6 +717,18 @@ func (_m *Group) String() string {
 	builder.WriteString(", ")
 	builder.WriteString("default_mapped_model=")
 	builder.WriteString(_m.DefaultMappedModel)
+	builder.WriteString(", ")
+	builder.WriteString("response_cache_enabled=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ResponseCacheEnabled))
+	builder.WriteString(", ")
+	builder.WriteString("response_cache_ttl_seconds=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ResponseCacheTTLSeconds))
+	builder.WriteString(", ")
+	builder.WriteString("response_cache_price_ratio=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ResponseCachePriceRatio))
+	builder.WriteString(", ")
+	builder.WriteString("model_fallback_chains=")
+	builder.WriteString(fmt.Sprintf("%v", _m.ModelFallbackChains))
 	builder.WriteByte(')')
 	return builder.String()
 }
@@ -85,6 +85,14 @@ const (
 	FieldAllowMessagesDispatch = "allow_messages_dispatch"
 	// FieldDefaultMappedModel holds the string denoting the default_mapped_model field in the database.
 	FieldDefaultMappedModel = "default_mapped_model"
+	// FieldResponseCacheEnabled holds the string denoting the response_cache_enabled field in the database.
+	FieldResponseCacheEnabled = "response_cache_enabled"
+	// FieldResponseCacheTTLSeconds holds the string denoting the response_cache_ttl_seconds field in the database.
+	FieldResponseCacheTTLSeconds = "response_cache_ttl_seconds"
+	// FieldResponseCachePriceRatio holds the string denoting the response_cache_price_ratio field in the database.
+	FieldResponseCachePriceRatio = "response_cache_price_ratio"
+	// FieldModelFallbackChains holds the string denoting the model_fallback_chains field in the database.
+	FieldModelFallbackChains = "model_fallback_chains"
 	// EdgeAPIKeys holds the string denoting the api_keys edge name in mutations.
 	EdgeAPIKeys = "api_keys"
 	// EdgeRedeemCodes holds the string denoting the redeem_codes edge name in mutations.
@@ -204,6 +212,10 @@ var Columns = []string{
 	FieldSortOrder,
 	FieldAllowMessagesDispatch,
 	FieldDefaultMappedModel,
+	FieldResponseCacheEnabled,
+	FieldResponseCacheTTLSeconds,
+	FieldResponseCachePriceRatio,
+	FieldModelFallbackChains,
 }
 
 var (
@@ -281,6 +293,12 @@ var (
 	DefaultDefaultMappedModel string
 	// DefaultMappedModelValidator is a validator for the "default_mapped_model" field. It is called by the builders before save.
 	DefaultMappedModelValidator func(string) error
+	// DefaultResponseCacheEnabled holds the default value on creation for the "response_cache_enabled" field.
+	DefaultResponseCacheEnabled bool
+	// DefaultResponseCacheTTLSeconds holds the default value on creation for the "response_cache_ttl_seconds" field.
+	DefaultResponseCacheTTLSeconds int
+	// DefaultResponseCachePriceRatio holds the default value on creation for the "response_cache_price_ratio" field.
+	DefaultResponseCachePriceRatio float64
 )
 
 // OrderOption defines the ordering options for the Group queries.
@@ -456,6 +474,21 @@ func ByDefaultMappedModel(opts ...sql.OrderTermOption) OrderOption {
 	return sql.OrderByField(FieldDefaultMappedModel, opts...).ToFunc()
 }
 
+// ByResponseCacheEnabled orders the results by the response_cache_enabled field.
+func ByResponseCacheEnabled(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldResponseCacheEnabled, opts...).ToFunc()
+}
+
+// ByResponseCacheTTLSeconds orders the results by the response_cache_ttl_seconds field.
+func ByResponseCacheTTLSeconds(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldResponseCacheTTLSeconds, opts...).ToFunc()
+}
+
+// ByResponseCachePriceRatio orders the results by the response_cache_price_ratio field.
+func ByResponseCachePriceRatio(opts ...sql.OrderTermOption) OrderOption {
+	return sql.OrderByField(FieldResponseCachePriceRatio, opts...).ToFunc()
+}
+
 // ByAPIKeysCount orders the results by api_keys count.
 func ByAPIKeysCount(opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
@@ -220,6 +220,21 @@ func DefaultMappedModel(v string) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldDefaultMappedModel, v))
 }
 
+// ResponseCacheEnabled applies equality check predicate on the "response_cache_enabled" field. It's identical to ResponseCacheEnabledEQ.
+func ResponseCacheEnabled(v bool) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheEnabled, v))
+}
+
+// ResponseCacheTTLSeconds applies equality check predicate on the "response_cache_ttl_seconds" field. It's identical to ResponseCacheTTLSecondsEQ.
+func ResponseCacheTTLSeconds(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCachePriceRatio applies equality check predicate on the "response_cache_price_ratio" field. It's identical to ResponseCachePriceRatioEQ.
+func ResponseCachePriceRatio(v float64) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCachePriceRatio, v))
+}
+
 // CreatedAtEQ applies the EQ predicate on the "created_at" field.
 func CreatedAtEQ(v time.Time) predicate.Group {
 	return predicate.Group(sql.FieldEQ(FieldCreatedAt, v))
@@ -1670,6 +1685,106 @@ func DefaultMappedModelContainsFold(v string) predicate.Group {
 	return predicate.Group(sql.FieldContainsFold(FieldDefaultMappedModel, v))
 }
 
+// ResponseCacheEnabledEQ applies the EQ predicate on the "response_cache_enabled" field.
+func ResponseCacheEnabledEQ(v bool) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheEnabled, v))
+}
+
+// ResponseCacheEnabledNEQ applies the NEQ predicate on the "response_cache_enabled" field.
+func ResponseCacheEnabledNEQ(v bool) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldResponseCacheEnabled, v))
+}
+
+// ResponseCacheTTLSecondsEQ applies the EQ predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsNEQ applies the NEQ predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsNEQ(v int) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsIn applies the In predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldResponseCacheTTLSeconds, vs...))
+}
+
+// ResponseCacheTTLSecondsNotIn applies the NotIn predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsNotIn(vs ...int) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldResponseCacheTTLSeconds, vs...))
+}
+
+// ResponseCacheTTLSecondsGT applies the GT predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsGT(v int) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsGTE applies the GTE predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsGTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsLT applies the LT predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsLT(v int) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCacheTTLSecondsLTE applies the LTE predicate on the "response_cache_ttl_seconds" field.
+func ResponseCacheTTLSecondsLTE(v int) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldResponseCacheTTLSeconds, v))
+}
+
+// ResponseCachePriceRatioEQ applies the EQ predicate on the "response_cache_price_ratio" field.
+func ResponseCachePriceRatioEQ(v float64) predicate.Group {
+	return predicate.Group(sql.FieldEQ(FieldResponseCachePriceRatio, v))
+}
+
+// ResponseCachePriceRatioNEQ applies the NEQ predicate on the "response_cache_price_ratio" field.
+func ResponseCachePriceRatioNEQ(v float64) predicate.Group {
+	return predicate.Group(sql.FieldNEQ(FieldResponseCachePriceRatio, v))
+}
+
+// ResponseCachePriceRatioIn applies the In predicate on the "response_cache_price_ratio" field.
+func ResponseCachePriceRatioIn(vs ...float64) predicate.Group {
+	return predicate.Group(sql.FieldIn(FieldResponseCachePriceRatio, vs...))
+}
+
+// ResponseCachePriceRatioNotIn applies the NotIn predicate on the "response_cache_price_ratio" field.
+func ResponseCachePriceRatioNotIn(vs ...float64) predicate.Group {
+	return predicate.Group(sql.FieldNotIn(FieldResponseCachePriceRatio, vs...))
+}
+
+// ResponseCachePriceRatioGT applies the GT predicate on the "response_cache_price_ratio" field.
+func ResponseCachePriceRatioGT(v float64) predicate.Group {
+	return predicate.Group(sql.FieldGT(FieldResponseCachePriceRatio, v))
+}
+
+// ResponseCachePriceRatioGTE applies the GTE predicate on the "response_cache_price_ratio" field.
+func ResponseCachePriceRatioGTE(v float64) predicate.Group {
+	return predicate.Group(sql.FieldGTE(FieldResponseCachePriceRatio, v))
+}
+
+// ResponseCachePriceRatioLT applies the LT predicate on the "response_cache_price_ratio" field.
+func ResponseCachePriceRatioLT(v float64) predicate.Group {
+	return predicate.Group(sql.FieldLT(FieldResponseCachePriceRatio, v))
+}
+
+// ResponseCachePriceRatioLTE applies the LTE predicate on the "response_cache_price_ratio" field.
+func ResponseCachePriceRatioLTE(v float64) predicate.Group {
+	return predicate.Group(sql.FieldLTE(FieldResponseCachePriceRatio, v))
+}
+
+// ModelFallbackChainsIsNil applies the IsNil predicate on the "model_fallback_chains" field.
+func ModelFallbackChainsIsNil() predicate.Group {
+	return predicate.Group(sql.FieldIsNull(FieldModelFallbackChains))
+}
+
+// ModelFallbackChainsNotNil applies the NotNil predicate on the "model_fallback_chains" field.
+func ModelFallbackChainsNotNil() predicate.Group {
+	return predicate.Group(sql.FieldNotNull(FieldModelFallbackChains))
+}
+
 // HasAPIKeys applies the HasEdge predicate on the "api_keys" edge.
 func HasAPIKeys() predicate.Group {
 	return predicate.Group(func(s *sql.Selector) {
@@ -19,6 +19,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
 )
 
 // GroupCreate is the builder for creating a Group entity.
@@ -495,6 +496,54 @@ func (_c *GroupCreate) SetNillableDefaultMappedModel(v *string) *GroupCreate {
 	return _c
 }
 
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (_c *GroupCreate) SetResponseCacheEnabled(v bool) *GroupCreate {
+	_c.mutation.SetResponseCacheEnabled(v)
+	return _c
+}
+
+// SetNillableResponseCacheEnabled sets the "response_cache_enabled" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableResponseCacheEnabled(v *bool) *GroupCreate {
+	if v != nil {
+		_c.SetResponseCacheEnabled(*v)
+	}
+	return _c
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (_c *GroupCreate) SetResponseCacheTTLSeconds(v int) *GroupCreate {
+	_c.mutation.SetResponseCacheTTLSeconds(v)
+	return _c
+}
+
+// SetNillableResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableResponseCacheTTLSeconds(v *int) *GroupCreate {
+	if v != nil {
+		_c.SetResponseCacheTTLSeconds(*v)
+	}
+	return _c
+}
+
+// SetResponseCachePriceRatio sets the "response_cache_price_ratio" field.
+func (_c *GroupCreate) SetResponseCachePriceRatio(v float64) *GroupCreate {
+	_c.mutation.SetResponseCachePriceRatio(v)
+	return _c
+}
+
+// SetNillableResponseCachePriceRatio sets the "response_cache_price_ratio" field if the given value is not nil.
+func (_c *GroupCreate) SetNillableResponseCachePriceRatio(v *float64) *GroupCreate {
+	if v != nil {
+		_c.SetResponseCachePriceRatio(*v)
+	}
+	return _c
+}
+
+// SetModelFallbackChains sets the "model_fallback_chains" field.
+func (_c *GroupCreate) SetModelFallbackChains(v []domain.ModelFallbackChain) *GroupCreate {
+	_c.mutation.SetModelFallbackChains(v)
+	return _c
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by IDs.
 func (_c *GroupCreate) AddAPIKeyIDs(ids ...int64) *GroupCreate {
 	_c.mutation.AddAPIKeyIDs(ids...)
@@ -715,6 +764,18 @@ func (_c *GroupCreate) defaults() error {
 		v := group.DefaultDefaultMappedModel
 		_c.mutation.SetDefaultMappedModel(v)
 	}
+	if _, ok := _c.mutation.ResponseCacheEnabled(); !ok {
+		v := group.DefaultResponseCacheEnabled
+		_c.mutation.SetResponseCacheEnabled(v)
+	}
+	if _, ok := _c.mutation.ResponseCacheTTLSeconds(); !ok {
+		v := group.DefaultResponseCacheTTLSeconds
+		_c.mutation.SetResponseCacheTTLSeconds(v)
+	}
+	if _, ok := _c.mutation.ResponseCachePriceRatio(); !ok {
+		v := group.DefaultResponseCachePriceRatio
+		_c.mutation.SetResponseCachePriceRatio(v)
+	}
 	return nil
 }
 
@@ -802,6 +863,15 @@ func (_c *GroupCreate) check() error {
 			return &ValidationError{Name: "default_mapped_model", err: fmt.Errorf(`ent: validator failed for field "Group.default_mapped_model": %w`, err)}
 		}
 	}
+	if _, ok := _c.mutation.ResponseCacheEnabled(); !ok {
+		return &ValidationError{Name: "response_cache_enabled", err: errors.New(`ent: missing required field "Group.response_cache_enabled"`)}
+	}
+	if _, ok := _c.mutation.ResponseCacheTTLSeconds(); !ok {
+		return &ValidationError{Name: "response_cache_ttl_seconds", err: errors.New(`ent: missing required field "Group.response_cache_ttl_seconds"`)}
+	}
+	if _, ok := _c.mutation.ResponseCachePriceRatio(); !ok {
+		return &ValidationError{Name: "response_cache_price_ratio", err: errors.New(`ent: missing required field "Group.response_cache_price_ratio"`)}
+	}
 	return nil
 }
 
@@ -969,6 +1039,22 @@ func (_c *GroupCreate) createSpec() (*Group, *sqlgraph.CreateSpec) {
 		_spec.SetField(group.FieldDefaultMappedModel, field.TypeString, value)
 		_node.DefaultMappedModel = value
 	}
+	if value, ok := _c.mutation.ResponseCacheEnabled(); ok {
+		_spec.SetField(group.FieldResponseCacheEnabled, field.TypeBool, value)
+		_node.ResponseCacheEnabled = value
+	}
+	if value, ok := _c.mutation.ResponseCacheTTLSeconds(); ok {
+		_spec.SetField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+		_node.ResponseCacheTTLSeconds = value
+	}
+	if value, ok := _c.mutation.ResponseCachePriceRatio(); ok {
+		_spec.SetField(group.FieldResponseCachePriceRatio, field.TypeFloat64, value)
+		_node.ResponseCachePriceRatio = value
+	}
+	if value, ok := _c.mutation.ModelFallbackChains(); ok {
+		_spec.SetField(group.FieldModelFallbackChains, field.TypeJSON, value)
+		_node.ModelFallbackChains = value
+	}
 	if nodes := _c.mutation.APIKeysIDs(); len(nodes) > 0 {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -1753,6 +1839,72 @@ func (u *GroupUpsert) UpdateDefaultMappedModel() *GroupUpsert {
 	return u
 }
 
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (u *GroupUpsert) SetResponseCacheEnabled(v bool) *GroupUpsert {
+	u.Set(group.FieldResponseCacheEnabled, v)
+	return u
+}
+
+// UpdateResponseCacheEnabled sets the "response_cache_enabled" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateResponseCacheEnabled() *GroupUpsert {
+	u.SetExcluded(group.FieldResponseCacheEnabled)
+	return u
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (u *GroupUpsert) SetResponseCacheTTLSeconds(v int) *GroupUpsert {
+	u.Set(group.FieldResponseCacheTTLSeconds, v)
+	return u
+}
+
+// UpdateResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateResponseCacheTTLSeconds() *GroupUpsert {
+	u.SetExcluded(group.FieldResponseCacheTTLSeconds)
+	return u
+}
+
+// AddResponseCacheTTLSeconds adds v to the "response_cache_ttl_seconds" field.
+func (u *GroupUpsert) AddResponseCacheTTLSeconds(v int) *GroupUpsert {
+	u.Add(group.FieldResponseCacheTTLSeconds, v)
+	return u
+}
+
+// SetResponseCachePriceRatio sets the "response_cache_price_ratio" field.
+func (u *GroupUpsert) SetResponseCachePriceRatio(v float64) *GroupUpsert {
+	u.Set(group.FieldResponseCachePriceRatio, v)
+	return u
+}
+
+// UpdateResponseCachePriceRatio sets the "response_cache_price_ratio" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateResponseCachePriceRatio() *GroupUpsert {
+	u.SetExcluded(group.FieldResponseCachePriceRatio)
+	return u
+}
+
+// AddResponseCachePriceRatio adds v to the "response_cache_price_ratio" field.
+func (u *GroupUpsert) AddResponseCachePriceRatio(v float64) *GroupUpsert {
+	u.Add(group.FieldResponseCachePriceRatio, v)
+	return u
+}
+
+// SetModelFallbackChains sets the "model_fallback_chains" field.
+func (u *GroupUpsert) SetModelFallbackChains(v []domain.ModelFallbackChain) *GroupUpsert {
+	u.Set(group.FieldModelFallbackChains, v)
+	return u
+}
+
+// UpdateModelFallbackChains sets the "model_fallback_chains" field to the value that was provided on create.
+func (u *GroupUpsert) UpdateModelFallbackChains() *GroupUpsert {
+	u.SetExcluded(group.FieldModelFallbackChains)
+	return u
+}
+
+// ClearModelFallbackChains clears the value of the "model_fallback_chains" field.
+func (u *GroupUpsert) ClearModelFallbackChains() *GroupUpsert {
+	u.SetNull(group.FieldModelFallbackChains)
+	return u
+}
+
 // UpdateNewValues updates the mutable fields using the new values that were set on create.
 // Using this option is equivalent to using:
 //
@@ -2512,6 +2664,83 @@ func (u *GroupUpsertOne) UpdateDefaultMappedModel() *GroupUpsertOne {
 	})
 }
 
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (u *GroupUpsertOne) SetResponseCacheEnabled(v bool) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheEnabled(v)
+	})
+}
+
+// UpdateResponseCacheEnabled sets the "response_cache_enabled" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateResponseCacheEnabled() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheEnabled()
+	})
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (u *GroupUpsertOne) SetResponseCacheTTLSeconds(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheTTLSeconds(v)
+	})
+}
+
+// AddResponseCacheTTLSeconds adds v to the "response_cache_ttl_seconds" field.
+func (u *GroupUpsertOne) AddResponseCacheTTLSeconds(v int) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCacheTTLSeconds(v)
+	})
+}
+
+// UpdateResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateResponseCacheTTLSeconds() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheTTLSeconds()
+	})
+}
+
+// SetResponseCachePriceRatio sets the "response_cache_price_ratio" field.
+func (u *GroupUpsertOne) SetResponseCachePriceRatio(v float64) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCachePriceRatio(v)
+	})
+}
+
+// AddResponseCachePriceRatio adds v to the "response_cache_price_ratio" field.
+func (u *GroupUpsertOne) AddResponseCachePriceRatio(v float64) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCachePriceRatio(v)
+	})
+}
+
+// UpdateResponseCachePriceRatio sets the "response_cache_price_ratio" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateResponseCachePriceRatio() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCachePriceRatio()
+	})
+}
+
+// SetModelFallbackChains sets the "model_fallback_chains" field.
+func (u *GroupUpsertOne) SetModelFallbackChains(v []domain.ModelFallbackChain) *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetModelFallbackChains(v)
+	})
+}
+
+// UpdateModelFallbackChains sets the "model_fallback_chains" field to the value that was provided on create.
+func (u *GroupUpsertOne) UpdateModelFallbackChains() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateModelFallbackChains()
+	})
+}
+
+// ClearModelFallbackChains clears the value of the "model_fallback_chains" field.
+func (u *GroupUpsertOne) ClearModelFallbackChains() *GroupUpsertOne {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearModelFallbackChains()
+	})
+}
+
 // Exec executes the query.
 func (u *GroupUpsertOne) Exec(ctx context.Context) error {
 	if len(u.create.conflict) == 0 {
@@ -3437,6 +3666,83 @@ func (u *GroupUpsertBulk) UpdateDefaultMappedModel() *GroupUpsertBulk {
 	})
 }
 
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (u *GroupUpsertBulk) SetResponseCacheEnabled(v bool) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheEnabled(v)
+	})
+}
+
+// UpdateResponseCacheEnabled sets the "response_cache_enabled" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateResponseCacheEnabled() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheEnabled()
+	})
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (u *GroupUpsertBulk) SetResponseCacheTTLSeconds(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCacheTTLSeconds(v)
+	})
+}
+
+// AddResponseCacheTTLSeconds adds v to the "response_cache_ttl_seconds" field.
+func (u *GroupUpsertBulk) AddResponseCacheTTLSeconds(v int) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCacheTTLSeconds(v)
+	})
+}
+
+// UpdateResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateResponseCacheTTLSeconds() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCacheTTLSeconds()
+	})
+}
+
+// SetResponseCachePriceRatio sets the "response_cache_price_ratio" field.
+func (u *GroupUpsertBulk) SetResponseCachePriceRatio(v float64) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetResponseCachePriceRatio(v)
+	})
+}
+
+// AddResponseCachePriceRatio adds v to the "response_cache_price_ratio" field.
+func (u *GroupUpsertBulk) AddResponseCachePriceRatio(v float64) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.AddResponseCachePriceRatio(v)
+	})
+}
+
+// UpdateResponseCachePriceRatio sets the "response_cache_price_ratio" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateResponseCachePriceRatio() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateResponseCachePriceRatio()
+	})
+}
+
+// SetModelFallbackChains sets the "model_fallback_chains" field.
+func (u *GroupUpsertBulk) SetModelFallbackChains(v []domain.ModelFallbackChain) *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.SetModelFallbackChains(v)
+	})
+}
+
+// UpdateModelFallbackChains sets the "model_fallback_chains" field to the value that was provided on create.
+func (u *GroupUpsertBulk) UpdateModelFallbackChains() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.UpdateModelFallbackChains()
+	})
+}
+
+// ClearModelFallbackChains clears the value of the "model_fallback_chains" field.
+func (u *GroupUpsertBulk) ClearModelFallbackChains() *GroupUpsertBulk {
+	return u.Update(func(s *GroupUpsert) {
+		s.ClearModelFallbackChains()
+	})
+}
+
 // Exec executes the query.
 func (u *GroupUpsertBulk) Exec(ctx context.Context) error {
 	if u.create.err != nil {
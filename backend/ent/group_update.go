@@ -21,6 +21,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/usagelog"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/ent/usersubscription"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
 )
 
 // GroupUpdate is the builder for updating Group entities.
@@ -716,6 +717,80 @@ func (_u *GroupUpdate) SetNillableDefaultMappedModel(v *string) *GroupUpdate {
 	return _u
 }
 
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (_u *GroupUpdate) SetResponseCacheEnabled(v bool) *GroupUpdate {
+	_u.mutation.SetResponseCacheEnabled(v)
+	return _u
+}
+
+// SetNillableResponseCacheEnabled sets the "response_cache_enabled" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableResponseCacheEnabled(v *bool) *GroupUpdate {
+	if v != nil {
+		_u.SetResponseCacheEnabled(*v)
+	}
+	return _u
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (_u *GroupUpdate) SetResponseCacheTTLSeconds(v int) *GroupUpdate {
+	_u.mutation.ResetResponseCacheTTLSeconds()
+	_u.mutation.SetResponseCacheTTLSeconds(v)
+	return _u
+}
+
+// SetNillableResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableResponseCacheTTLSeconds(v *int) *GroupUpdate {
+	if v != nil {
+		_u.SetResponseCacheTTLSeconds(*v)
+	}
+	return _u
+}
+
+// AddResponseCacheTTLSeconds adds value to the "response_cache_ttl_seconds" field.
+func (_u *GroupUpdate) AddResponseCacheTTLSeconds(v int) *GroupUpdate {
+	_u.mutation.AddResponseCacheTTLSeconds(v)
+	return _u
+}
+
+// SetResponseCachePriceRatio sets the "response_cache_price_ratio" field.
+func (_u *GroupUpdate) SetResponseCachePriceRatio(v float64) *GroupUpdate {
+	_u.mutation.ResetResponseCachePriceRatio()
+	_u.mutation.SetResponseCachePriceRatio(v)
+	return _u
+}
+
+// SetNillableResponseCachePriceRatio sets the "response_cache_price_ratio" field if the given value is not nil.
+func (_u *GroupUpdate) SetNillableResponseCachePriceRatio(v *float64) *GroupUpdate {
+	if v != nil {
+		_u.SetResponseCachePriceRatio(*v)
+	}
+	return _u
+}
+
+// AddResponseCachePriceRatio adds value to the "response_cache_price_ratio" field.
+func (_u *GroupUpdate) AddResponseCachePriceRatio(v float64) *GroupUpdate {
+	_u.mutation.AddResponseCachePriceRatio(v)
+	return _u
+}
+
+// SetModelFallbackChains sets the "model_fallback_chains" field.
+func (_u *GroupUpdate) SetModelFallbackChains(v []domain.ModelFallbackChain) *GroupUpdate {
+	_u.mutation.SetModelFallbackChains(v)
+	return _u
+}
+
+// AppendModelFallbackChains appends value to the "model_fallback_chains" field.
+func (_u *GroupUpdate) AppendModelFallbackChains(v []domain.ModelFallbackChain) *GroupUpdate {
+	_u.mutation.AppendModelFallbackChains(v)
+	return _u
+}
+
+// ClearModelFallbackChains clears the value of the "model_fallback_chains" field.
+func (_u *GroupUpdate) ClearModelFallbackChains() *GroupUpdate {
+	_u.mutation.ClearModelFallbackChains()
+	return _u
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by IDs.
 func (_u *GroupUpdate) AddAPIKeyIDs(ids ...int64) *GroupUpdate {
 	_u.mutation.AddAPIKeyIDs(ids...)
@@ -1266,6 +1341,32 @@ func (_u *GroupUpdate) sqlSave(ctx context.Context) (_node int, err error) {
 	if value, ok := _u.mutation.DefaultMappedModel(); ok {
 		_spec.SetField(group.FieldDefaultMappedModel, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.ResponseCacheEnabled(); ok {
+		_spec.SetField(group.FieldResponseCacheEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheTTLSeconds(); ok {
+		_spec.SetField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCacheTTLSeconds(); ok {
+		_spec.AddField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ResponseCachePriceRatio(); ok {
+		_spec.SetField(group.FieldResponseCachePriceRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCachePriceRatio(); ok {
+		_spec.AddField(group.FieldResponseCachePriceRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.ModelFallbackChains(); ok {
+		_spec.SetField(group.FieldModelFallbackChains, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedModelFallbackChains(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, group.FieldModelFallbackChains, value)
+		})
+	}
+	if _u.mutation.ModelFallbackChainsCleared() {
+		_spec.ClearField(group.FieldModelFallbackChains, field.TypeJSON)
+	}
 	if _u.mutation.APIKeysCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
@@ -2305,6 +2406,80 @@ func (_u *GroupUpdateOne) SetNillableDefaultMappedModel(v *string) *GroupUpdateO
 	return _u
 }
 
+// SetResponseCacheEnabled sets the "response_cache_enabled" field.
+func (_u *GroupUpdateOne) SetResponseCacheEnabled(v bool) *GroupUpdateOne {
+	_u.mutation.SetResponseCacheEnabled(v)
+	return _u
+}
+
+// SetNillableResponseCacheEnabled sets the "response_cache_enabled" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableResponseCacheEnabled(v *bool) *GroupUpdateOne {
+	if v != nil {
+		_u.SetResponseCacheEnabled(*v)
+	}
+	return _u
+}
+
+// SetResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field.
+func (_u *GroupUpdateOne) SetResponseCacheTTLSeconds(v int) *GroupUpdateOne {
+	_u.mutation.ResetResponseCacheTTLSeconds()
+	_u.mutation.SetResponseCacheTTLSeconds(v)
+	return _u
+}
+
+// SetNillableResponseCacheTTLSeconds sets the "response_cache_ttl_seconds" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableResponseCacheTTLSeconds(v *int) *GroupUpdateOne {
+	if v != nil {
+		_u.SetResponseCacheTTLSeconds(*v)
+	}
+	return _u
+}
+
+// AddResponseCacheTTLSeconds adds value to the "response_cache_ttl_seconds" field.
+func (_u *GroupUpdateOne) AddResponseCacheTTLSeconds(v int) *GroupUpdateOne {
+	_u.mutation.AddResponseCacheTTLSeconds(v)
+	return _u
+}
+
+// SetResponseCachePriceRatio sets the "response_cache_price_ratio" field.
+func (_u *GroupUpdateOne) SetResponseCachePriceRatio(v float64) *GroupUpdateOne {
+	_u.mutation.ResetResponseCachePriceRatio()
+	_u.mutation.SetResponseCachePriceRatio(v)
+	return _u
+}
+
+// SetNillableResponseCachePriceRatio sets the "response_cache_price_ratio" field if the given value is not nil.
+func (_u *GroupUpdateOne) SetNillableResponseCachePriceRatio(v *float64) *GroupUpdateOne {
+	if v != nil {
+		_u.SetResponseCachePriceRatio(*v)
+	}
+	return _u
+}
+
+// AddResponseCachePriceRatio adds value to the "response_cache_price_ratio" field.
+func (_u *GroupUpdateOne) AddResponseCachePriceRatio(v float64) *GroupUpdateOne {
+	_u.mutation.AddResponseCachePriceRatio(v)
+	return _u
+}
+
+// SetModelFallbackChains sets the "model_fallback_chains" field.
+func (_u *GroupUpdateOne) SetModelFallbackChains(v []domain.ModelFallbackChain) *GroupUpdateOne {
+	_u.mutation.SetModelFallbackChains(v)
+	return _u
+}
+
+// AppendModelFallbackChains appends value to the "model_fallback_chains" field.
+func (_u *GroupUpdateOne) AppendModelFallbackChains(v []domain.ModelFallbackChain) *GroupUpdateOne {
+	_u.mutation.AppendModelFallbackChains(v)
+	return _u
+}
+
+// ClearModelFallbackChains clears the value of the "model_fallback_chains" field.
+func (_u *GroupUpdateOne) ClearModelFallbackChains() *GroupUpdateOne {
+	_u.mutation.ClearModelFallbackChains()
+	return _u
+}
+
 // AddAPIKeyIDs adds the "api_keys" edge to the APIKey entity by IDs.
 func (_u *GroupUpdateOne) AddAPIKeyIDs(ids ...int64) *GroupUpdateOne {
 	_u.mutation.AddAPIKeyIDs(ids...)
@@ -2885,6 +3060,32 @@ func (_u *GroupUpdateOne) sqlSave(ctx context.Context) (_node *Group, err error)
 	if value, ok := _u.mutation.DefaultMappedModel(); ok {
 		_spec.SetField(group.FieldDefaultMappedModel, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.ResponseCacheEnabled(); ok {
+		_spec.SetField(group.FieldResponseCacheEnabled, field.TypeBool, value)
+	}
+	if value, ok := _u.mutation.ResponseCacheTTLSeconds(); ok {
+		_spec.SetField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCacheTTLSeconds(); ok {
+		_spec.AddField(group.FieldResponseCacheTTLSeconds, field.TypeInt, value)
+	}
+	if value, ok := _u.mutation.ResponseCachePriceRatio(); ok {
+		_spec.SetField(group.FieldResponseCachePriceRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedResponseCachePriceRatio(); ok {
+		_spec.AddField(group.FieldResponseCachePriceRatio, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.ModelFallbackChains(); ok {
+		_spec.SetField(group.FieldModelFallbackChains, field.TypeJSON, value)
+	}
+	if value, ok := _u.mutation.AppendedModelFallbackChains(); ok {
+		_spec.AddModifier(func(u *sql.UpdateBuilder) {
+			sqljson.Append(u, group.FieldModelFallbackChains, value)
+		})
+	}
+	if _u.mutation.ModelFallbackChainsCleared() {
+		_spec.ClearField(group.FieldModelFallbackChains, field.TypeJSON)
+	}
 	if _u.mutation.APIKeysCleared() {
 		edge := &sqlgraph.EdgeSpec{
 			Rel:     sqlgraph.O2M,
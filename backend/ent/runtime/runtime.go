@@ -13,6 +13,8 @@ import (
 	"github.com/Wei-Shaw/sub2api/ent/errorpassthroughrule"
 	"github.com/Wei-Shaw/sub2api/ent/group"
 	"github.com/Wei-Shaw/sub2api/ent/idempotencyrecord"
+	"github.com/Wei-Shaw/sub2api/ent/organization"
+	"github.com/Wei-Shaw/sub2api/ent/organizationmember"
 	"github.com/Wei-Shaw/sub2api/ent/promocode"
 	"github.com/Wei-Shaw/sub2api/ent/promocodeusage"
 	"github.com/Wei-Shaw/sub2api/ent/proxy"
@@ -53,26 +55,32 @@ func init() {
 	apikey.DefaultUpdatedAt = apikeyDescUpdatedAt.Default.(func() time.Time)
 	// apikey.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
 	apikey.UpdateDefaultUpdatedAt = apikeyDescUpdatedAt.UpdateDefault.(func() time.Time)
-	// apikeyDescKey is the schema descriptor for key field.
-	apikeyDescKey := apikeyFields[1].Descriptor()
-	// apikey.KeyValidator is a validator for the "key" field. It is called by the builders before save.
-	apikey.KeyValidator = func() func(string) error {
-		validators := apikeyDescKey.Validators
+	// apikeyDescKeyHash is the schema descriptor for key_hash field.
+	apikeyDescKeyHash := apikeyFields[1].Descriptor()
+	// apikey.KeyHashValidator is a validator for the "key_hash" field. It is called by the builders before save.
+	apikey.KeyHashValidator = func() func(string) error {
+		validators := apikeyDescKeyHash.Validators
 		fns := [...]func(string) error{
 			validators[0].(func(string) error),
 			validators[1].(func(string) error),
 		}
-		return func(key string) error {
+		return func(key_hash string) error {
 			for _, fn := range fns {
-				if err := fn(key); err != nil {
+				if err := fn(key_hash); err != nil {
 					return err
 				}
 			}
 			return nil
 		}
 	}()
+	// apikeyDescKeyPrefix is the schema descriptor for key_prefix field.
+	apikeyDescKeyPrefix := apikeyFields[2].Descriptor()
+	// apikey.DefaultKeyPrefix holds the default value on creation for the key_prefix field.
+	apikey.DefaultKeyPrefix = apikeyDescKeyPrefix.Default.(string)
+	// apikey.KeyPrefixValidator is a validator for the "key_prefix" field. It is called by the builders before save.
+	apikey.KeyPrefixValidator = apikeyDescKeyPrefix.Validators[0].(func(string) error)
 	// apikeyDescName is the schema descriptor for name field.
-	apikeyDescName := apikeyFields[2].Descriptor()
+	apikeyDescName := apikeyFields[3].Descriptor()
 	// apikey.NameValidator is a validator for the "name" field. It is called by the builders before save.
 	apikey.NameValidator = func() func(string) error {
 		validators := apikeyDescName.Validators
@@ -90,43 +98,55 @@ func init() {
 		}
 	}()
 	// apikeyDescStatus is the schema descriptor for status field.
-	apikeyDescStatus := apikeyFields[4].Descriptor()
+	apikeyDescStatus := apikeyFields[6].Descriptor()
 	// apikey.DefaultStatus holds the default value on creation for the status field.
 	apikey.DefaultStatus = apikeyDescStatus.Default.(string)
 	// apikey.StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	apikey.StatusValidator = apikeyDescStatus.Validators[0].(func(string) error)
 	// apikeyDescQuota is the schema descriptor for quota field.
-	apikeyDescQuota := apikeyFields[8].Descriptor()
+	apikeyDescQuota := apikeyFields[13].Descriptor()
 	// apikey.DefaultQuota holds the default value on creation for the quota field.
 	apikey.DefaultQuota = apikeyDescQuota.Default.(float64)
 	// apikeyDescQuotaUsed is the schema descriptor for quota_used field.
-	apikeyDescQuotaUsed := apikeyFields[9].Descriptor()
+	apikeyDescQuotaUsed := apikeyFields[14].Descriptor()
 	// apikey.DefaultQuotaUsed holds the default value on creation for the quota_used field.
 	apikey.DefaultQuotaUsed = apikeyDescQuotaUsed.Default.(float64)
 	// apikeyDescRateLimit5h is the schema descriptor for rate_limit_5h field.
-	apikeyDescRateLimit5h := apikeyFields[11].Descriptor()
+	apikeyDescRateLimit5h := apikeyFields[16].Descriptor()
 	// apikey.DefaultRateLimit5h holds the default value on creation for the rate_limit_5h field.
 	apikey.DefaultRateLimit5h = apikeyDescRateLimit5h.Default.(float64)
 	// apikeyDescRateLimit1d is the schema descriptor for rate_limit_1d field.
-	apikeyDescRateLimit1d := apikeyFields[12].Descriptor()
+	apikeyDescRateLimit1d := apikeyFields[17].Descriptor()
 	// apikey.DefaultRateLimit1d holds the default value on creation for the rate_limit_1d field.
 	apikey.DefaultRateLimit1d = apikeyDescRateLimit1d.Default.(float64)
 	// apikeyDescRateLimit7d is the schema descriptor for rate_limit_7d field.
-	apikeyDescRateLimit7d := apikeyFields[13].Descriptor()
+	apikeyDescRateLimit7d := apikeyFields[18].Descriptor()
 	// apikey.DefaultRateLimit7d holds the default value on creation for the rate_limit_7d field.
 	apikey.DefaultRateLimit7d = apikeyDescRateLimit7d.Default.(float64)
 	// apikeyDescUsage5h is the schema descriptor for usage_5h field.
-	apikeyDescUsage5h := apikeyFields[14].Descriptor()
+	apikeyDescUsage5h := apikeyFields[19].Descriptor()
 	// apikey.DefaultUsage5h holds the default value on creation for the usage_5h field.
 	apikey.DefaultUsage5h = apikeyDescUsage5h.Default.(float64)
 	// apikeyDescUsage1d is the schema descriptor for usage_1d field.
-	apikeyDescUsage1d := apikeyFields[15].Descriptor()
+	apikeyDescUsage1d := apikeyFields[20].Descriptor()
 	// apikey.DefaultUsage1d holds the default value on creation for the usage_1d field.
 	apikey.DefaultUsage1d = apikeyDescUsage1d.Default.(float64)
 	// apikeyDescUsage7d is the schema descriptor for usage_7d field.
-	apikeyDescUsage7d := apikeyFields[16].Descriptor()
+	apikeyDescUsage7d := apikeyFields[21].Descriptor()
 	// apikey.DefaultUsage7d holds the default value on creation for the usage_7d field.
 	apikey.DefaultUsage7d = apikeyDescUsage7d.Default.(float64)
+	// apikeyDescRpmLimit is the schema descriptor for rpm_limit field.
+	apikeyDescRpmLimit := apikeyFields[25].Descriptor()
+	// apikey.DefaultRpmLimit holds the default value on creation for the rpm_limit field.
+	apikey.DefaultRpmLimit = apikeyDescRpmLimit.Default.(int)
+	// apikeyDescTpmLimit is the schema descriptor for tpm_limit field.
+	apikeyDescTpmLimit := apikeyFields[26].Descriptor()
+	// apikey.DefaultTpmLimit holds the default value on creation for the tpm_limit field.
+	apikey.DefaultTpmLimit = apikeyDescTpmLimit.Default.(int)
+	// apikeyDescMaxConcurrency is the schema descriptor for max_concurrency field.
+	apikeyDescMaxConcurrency := apikeyFields[27].Descriptor()
+	// apikey.DefaultMaxConcurrency holds the default value on creation for the max_concurrency field.
+	apikey.DefaultMaxConcurrency = apikeyDescMaxConcurrency.Default.(int)
 	accountMixin := schema.Account{}.Mixin()
 	accountMixinHooks1 := accountMixin[1].Hooks()
 	account.Hooks[0] = accountMixinHooks1[0]
@@ -209,33 +229,33 @@ func init() {
 	// account.DefaultExtra holds the default value on creation for the extra field.
 	account.DefaultExtra = accountDescExtra.Default.(func() map[string]interface{})
 	// accountDescConcurrency is the schema descriptor for concurrency field.
-	accountDescConcurrency := accountFields[7].Descriptor()
+	accountDescConcurrency := accountFields[8].Descriptor()
 	// account.DefaultConcurrency holds the default value on creation for the concurrency field.
 	account.DefaultConcurrency = accountDescConcurrency.Default.(int)
 	// accountDescPriority is the schema descriptor for priority field.
-	accountDescPriority := accountFields[9].Descriptor()
+	accountDescPriority := accountFields[10].Descriptor()
 	// account.DefaultPriority holds the default value on creation for the priority field.
 	account.DefaultPriority = accountDescPriority.Default.(int)
 	// accountDescRateMultiplier is the schema descriptor for rate_multiplier field.
-	accountDescRateMultiplier := accountFields[10].Descriptor()
+	accountDescRateMultiplier := accountFields[11].Descriptor()
 	// account.DefaultRateMultiplier holds the default value on creation for the rate_multiplier field.
 	account.DefaultRateMultiplier = accountDescRateMultiplier.Default.(float64)
 	// accountDescStatus is the schema descriptor for status field.
-	accountDescStatus := accountFields[11].Descriptor()
+	accountDescStatus := accountFields[12].Descriptor()
 	// account.DefaultStatus holds the default value on creation for the status field.
 	account.DefaultStatus = accountDescStatus.Default.(string)
 	// account.StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	account.StatusValidator = accountDescStatus.Validators[0].(func(string) error)
 	// accountDescAutoPauseOnExpired is the schema descriptor for auto_pause_on_expired field.
-	accountDescAutoPauseOnExpired := accountFields[15].Descriptor()
+	accountDescAutoPauseOnExpired := accountFields[16].Descriptor()
 	// account.DefaultAutoPauseOnExpired holds the default value on creation for the auto_pause_on_expired field.
 	account.DefaultAutoPauseOnExpired = accountDescAutoPauseOnExpired.Default.(bool)
 	// accountDescSchedulable is the schema descriptor for schedulable field.
-	accountDescSchedulable := accountFields[16].Descriptor()
+	accountDescSchedulable := accountFields[17].Descriptor()
 	// account.DefaultSchedulable holds the default value on creation for the schedulable field.
 	account.DefaultSchedulable = accountDescSchedulable.Default.(bool)
 	// accountDescSessionWindowStatus is the schema descriptor for session_window_status field.
-	accountDescSessionWindowStatus := accountFields[24].Descriptor()
+	accountDescSessionWindowStatus := accountFields[25].Descriptor()
 	// account.SessionWindowStatusValidator is a validator for the "session_window_status" field. It is called by the builders before save.
 	account.SessionWindowStatusValidator = accountDescSessionWindowStatus.Validators[0].(func(string) error)
 	accountgroupFields := schema.AccountGroup{}.Fields()
@@ -472,6 +492,18 @@ func init() {
 	group.DefaultDefaultMappedModel = groupDescDefaultMappedModel.Default.(string)
 	// group.DefaultMappedModelValidator is a validator for the "default_mapped_model" field. It is called by the builders before save.
 	group.DefaultMappedModelValidator = groupDescDefaultMappedModel.Validators[0].(func(string) error)
+	// groupDescResponseCacheEnabled is the schema descriptor for response_cache_enabled field.
+	groupDescResponseCacheEnabled := groupFields[32].Descriptor()
+	// group.DefaultResponseCacheEnabled holds the default value on creation for the response_cache_enabled field.
+	group.DefaultResponseCacheEnabled = groupDescResponseCacheEnabled.Default.(bool)
+	// groupDescResponseCacheTTLSeconds is the schema descriptor for response_cache_ttl_seconds field.
+	groupDescResponseCacheTTLSeconds := groupFields[33].Descriptor()
+	// group.DefaultResponseCacheTTLSeconds holds the default value on creation for the response_cache_ttl_seconds field.
+	group.DefaultResponseCacheTTLSeconds = groupDescResponseCacheTTLSeconds.Default.(int)
+	// groupDescResponseCachePriceRatio is the schema descriptor for response_cache_price_ratio field.
+	groupDescResponseCachePriceRatio := groupFields[34].Descriptor()
+	// group.DefaultResponseCachePriceRatio holds the default value on creation for the response_cache_price_ratio field.
+	group.DefaultResponseCachePriceRatio = groupDescResponseCachePriceRatio.Default.(float64)
 	idempotencyrecordMixin := schema.IdempotencyRecord{}.Mixin()
 	idempotencyrecordMixinFields0 := idempotencyrecordMixin[0].Fields()
 	_ = idempotencyrecordMixinFields0
@@ -507,6 +539,82 @@ func init() {
 	idempotencyrecordDescErrorReason := idempotencyrecordFields[6].Descriptor()
 	// idempotencyrecord.ErrorReasonValidator is a validator for the "error_reason" field. It is called by the builders before save.
 	idempotencyrecord.ErrorReasonValidator = idempotencyrecordDescErrorReason.Validators[0].(func(string) error)
+	organizationMixin := schema.Organization{}.Mixin()
+	organizationMixinHooks1 := organizationMixin[1].Hooks()
+	organization.Hooks[0] = organizationMixinHooks1[0]
+	organizationMixinInters1 := organizationMixin[1].Interceptors()
+	organization.Interceptors[0] = organizationMixinInters1[0]
+	organizationMixinFields0 := organizationMixin[0].Fields()
+	_ = organizationMixinFields0
+	organizationFields := schema.Organization{}.Fields()
+	_ = organizationFields
+	// organizationDescCreatedAt is the schema descriptor for created_at field.
+	organizationDescCreatedAt := organizationMixinFields0[0].Descriptor()
+	// organization.DefaultCreatedAt holds the default value on creation for the created_at field.
+	organization.DefaultCreatedAt = organizationDescCreatedAt.Default.(func() time.Time)
+	// organizationDescUpdatedAt is the schema descriptor for updated_at field.
+	organizationDescUpdatedAt := organizationMixinFields0[1].Descriptor()
+	// organization.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	organization.DefaultUpdatedAt = organizationDescUpdatedAt.Default.(func() time.Time)
+	// organization.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	organization.UpdateDefaultUpdatedAt = organizationDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// organizationDescName is the schema descriptor for name field.
+	organizationDescName := organizationFields[0].Descriptor()
+	// organization.NameValidator is a validator for the "name" field. It is called by the builders before save.
+	organization.NameValidator = func() func(string) error {
+		validators := organizationDescName.Validators
+		fns := [...]func(string) error{
+			validators[0].(func(string) error),
+			validators[1].(func(string) error),
+		}
+		return func(name string) error {
+			for _, fn := range fns {
+				if err := fn(name); err != nil {
+					return err
+				}
+			}
+			return nil
+		}
+	}()
+	// organizationDescBalance is the schema descriptor for balance field.
+	organizationDescBalance := organizationFields[2].Descriptor()
+	// organization.DefaultBalance holds the default value on creation for the balance field.
+	organization.DefaultBalance = organizationDescBalance.Default.(float64)
+	// organizationDescStatus is the schema descriptor for status field.
+	organizationDescStatus := organizationFields[3].Descriptor()
+	// organization.DefaultStatus holds the default value on creation for the status field.
+	organization.DefaultStatus = organizationDescStatus.Default.(string)
+	// organization.StatusValidator is a validator for the "status" field. It is called by the builders before save.
+	organization.StatusValidator = organizationDescStatus.Validators[0].(func(string) error)
+	organizationmemberMixin := schema.OrganizationMember{}.Mixin()
+	organizationmemberMixinFields0 := organizationmemberMixin[0].Fields()
+	_ = organizationmemberMixinFields0
+	organizationmemberFields := schema.OrganizationMember{}.Fields()
+	_ = organizationmemberFields
+	// organizationmemberDescCreatedAt is the schema descriptor for created_at field.
+	organizationmemberDescCreatedAt := organizationmemberMixinFields0[0].Descriptor()
+	// organizationmember.DefaultCreatedAt holds the default value on creation for the created_at field.
+	organizationmember.DefaultCreatedAt = organizationmemberDescCreatedAt.Default.(func() time.Time)
+	// organizationmemberDescUpdatedAt is the schema descriptor for updated_at field.
+	organizationmemberDescUpdatedAt := organizationmemberMixinFields0[1].Descriptor()
+	// organizationmember.DefaultUpdatedAt holds the default value on creation for the updated_at field.
+	organizationmember.DefaultUpdatedAt = organizationmemberDescUpdatedAt.Default.(func() time.Time)
+	// organizationmember.UpdateDefaultUpdatedAt holds the default value on update for the updated_at field.
+	organizationmember.UpdateDefaultUpdatedAt = organizationmemberDescUpdatedAt.UpdateDefault.(func() time.Time)
+	// organizationmemberDescRole is the schema descriptor for role field.
+	organizationmemberDescRole := organizationmemberFields[2].Descriptor()
+	// organizationmember.DefaultRole holds the default value on creation for the role field.
+	organizationmember.DefaultRole = organizationmemberDescRole.Default.(string)
+	// organizationmember.RoleValidator is a validator for the "role" field. It is called by the builders before save.
+	organizationmember.RoleValidator = organizationmemberDescRole.Validators[0].(func(string) error)
+	// organizationmemberDescMonthlyLimitUsd is the schema descriptor for monthly_limit_usd field.
+	organizationmemberDescMonthlyLimitUsd := organizationmemberFields[3].Descriptor()
+	// organizationmember.DefaultMonthlyLimitUsd holds the default value on creation for the monthly_limit_usd field.
+	organizationmember.DefaultMonthlyLimitUsd = organizationmemberDescMonthlyLimitUsd.Default.(float64)
+	// organizationmemberDescMonthlyUsageUsd is the schema descriptor for monthly_usage_usd field.
+	organizationmemberDescMonthlyUsageUsd := organizationmemberFields[4].Descriptor()
+	// organizationmember.DefaultMonthlyUsageUsd holds the default value on creation for the monthly_usage_usd field.
+	organizationmember.DefaultMonthlyUsageUsd = organizationmemberDescMonthlyUsageUsd.Default.(float64)
 	promocodeFields := schema.PromoCode{}.Fields()
 	_ = promocodeFields
 	// promocodeDescCode is the schema descriptor for code field.
@@ -788,44 +896,54 @@ func init() {
 			return nil
 		}
 	}()
+	// subscriptionorderDescOrderType is the schema descriptor for order_type field.
+	subscriptionorderDescOrderType := subscriptionorderFields[3].Descriptor()
+	// subscriptionorder.DefaultOrderType holds the default value on creation for the order_type field.
+	subscriptionorder.DefaultOrderType = subscriptionorderDescOrderType.Default.(string)
+	// subscriptionorder.OrderTypeValidator is a validator for the "order_type" field. It is called by the builders before save.
+	subscriptionorder.OrderTypeValidator = subscriptionorderDescOrderType.Validators[0].(func(string) error)
 	// subscriptionorderDescPaymentProvider is the schema descriptor for payment_provider field.
-	subscriptionorderDescPaymentProvider := subscriptionorderFields[4].Descriptor()
+	subscriptionorderDescPaymentProvider := subscriptionorderFields[5].Descriptor()
 	// subscriptionorder.DefaultPaymentProvider holds the default value on creation for the payment_provider field.
 	subscriptionorder.DefaultPaymentProvider = subscriptionorderDescPaymentProvider.Default.(string)
 	// subscriptionorder.PaymentProviderValidator is a validator for the "payment_provider" field. It is called by the builders before save.
 	subscriptionorder.PaymentProviderValidator = subscriptionorderDescPaymentProvider.Validators[0].(func(string) error)
 	// subscriptionorderDescPaymentOpenOrderID is the schema descriptor for payment_open_order_id field.
-	subscriptionorderDescPaymentOpenOrderID := subscriptionorderFields[7].Descriptor()
+	subscriptionorderDescPaymentOpenOrderID := subscriptionorderFields[8].Descriptor()
 	// subscriptionorder.PaymentOpenOrderIDValidator is a validator for the "payment_open_order_id" field. It is called by the builders before save.
 	subscriptionorder.PaymentOpenOrderIDValidator = subscriptionorderDescPaymentOpenOrderID.Validators[0].(func(string) error)
 	// subscriptionorderDescPaymentTransactionID is the schema descriptor for payment_transaction_id field.
-	subscriptionorderDescPaymentTransactionID := subscriptionorderFields[8].Descriptor()
+	subscriptionorderDescPaymentTransactionID := subscriptionorderFields[9].Descriptor()
 	// subscriptionorder.PaymentTransactionIDValidator is a validator for the "payment_transaction_id" field. It is called by the builders before save.
 	subscriptionorder.PaymentTransactionIDValidator = subscriptionorderDescPaymentTransactionID.Validators[0].(func(string) error)
 	// subscriptionorderDescPaymentPlugin is the schema descriptor for payment_plugin field.
-	subscriptionorderDescPaymentPlugin := subscriptionorderFields[9].Descriptor()
+	subscriptionorderDescPaymentPlugin := subscriptionorderFields[10].Descriptor()
 	// subscriptionorder.PaymentPluginValidator is a validator for the "payment_plugin" field. It is called by the builders before save.
 	subscriptionorder.PaymentPluginValidator = subscriptionorderDescPaymentPlugin.Validators[0].(func(string) error)
 	// subscriptionorderDescStatus is the schema descriptor for status field.
-	subscriptionorderDescStatus := subscriptionorderFields[10].Descriptor()
+	subscriptionorderDescStatus := subscriptionorderFields[11].Descriptor()
 	// subscriptionorder.DefaultStatus holds the default value on creation for the status field.
 	subscriptionorder.DefaultStatus = subscriptionorderDescStatus.Default.(string)
 	// subscriptionorder.StatusValidator is a validator for the "status" field. It is called by the builders before save.
 	subscriptionorder.StatusValidator = subscriptionorderDescStatus.Validators[0].(func(string) error)
 	// subscriptionorderDescAmount is the schema descriptor for amount field.
-	subscriptionorderDescAmount := subscriptionorderFields[11].Descriptor()
+	subscriptionorderDescAmount := subscriptionorderFields[12].Descriptor()
 	// subscriptionorder.DefaultAmount holds the default value on creation for the amount field.
 	subscriptionorder.DefaultAmount = subscriptionorderDescAmount.Default.(float64)
 	// subscriptionorderDescCurrency is the schema descriptor for currency field.
-	subscriptionorderDescCurrency := subscriptionorderFields[12].Descriptor()
+	subscriptionorderDescCurrency := subscriptionorderFields[13].Descriptor()
 	// subscriptionorder.DefaultCurrency holds the default value on creation for the currency field.
 	subscriptionorder.DefaultCurrency = subscriptionorderDescCurrency.Default.(string)
 	// subscriptionorder.CurrencyValidator is a validator for the "currency" field. It is called by the builders before save.
 	subscriptionorder.CurrencyValidator = subscriptionorderDescCurrency.Validators[0].(func(string) error)
 	// subscriptionorderDescValidityDays is the schema descriptor for validity_days field.
-	subscriptionorderDescValidityDays := subscriptionorderFields[13].Descriptor()
+	subscriptionorderDescValidityDays := subscriptionorderFields[14].Descriptor()
 	// subscriptionorder.DefaultValidityDays holds the default value on creation for the validity_days field.
 	subscriptionorder.DefaultValidityDays = subscriptionorderDescValidityDays.Default.(int)
+	// subscriptionorderDescCreditAmount is the schema descriptor for credit_amount field.
+	subscriptionorderDescCreditAmount := subscriptionorderFields[15].Descriptor()
+	// subscriptionorder.DefaultCreditAmount holds the default value on creation for the credit_amount field.
+	subscriptionorder.DefaultCreditAmount = subscriptionorderDescCreditAmount.Default.(float64)
 	usagecleanuptaskMixin := schema.UsageCleanupTask{}.Mixin()
 	usagecleanuptaskMixinFields0 := usagecleanuptaskMixin[0].Fields()
 	_ = usagecleanuptaskMixinFields0
@@ -34,16 +34,25 @@ func (APIKey) Mixin() []ent.Mixin {
 func (APIKey) Fields() []ent.Field {
 	return []ent.Field{
 		field.Int64("user_id"),
-		field.String("key").
-			MaxLen(128).
+		field.String("key_hash").
+			MaxLen(64).
 			NotEmpty().
-			Unique(),
+			Unique().
+			Comment("HMAC-SHA256 of the API key (hex); plaintext is never stored"),
+		field.String("key_prefix").
+			MaxLen(16).
+			Default("").
+			Comment("Leading characters of the API key, for display and search"),
 		field.String("name").
 			MaxLen(100).
 			NotEmpty(),
 		field.Int64("group_id").
 			Optional().
 			Nillable(),
+		field.Int64("organization_id").
+			Optional().
+			Nillable().
+			Comment("Owning organization; usage is billed against the organization balance"),
 		field.String("status").
 			MaxLen(20).
 			Default(domain.StatusActive),
@@ -58,6 +67,17 @@ func (APIKey) Fields() []ent.Field {
 			Optional().
 			Comment("Blocked IPs/CIDRs"),
 
+		// ========== Model policy fields ==========
+		field.JSON("allowed_models", []string{}).
+			Optional().
+			Comment("Allowed model patterns (* wildcard), empty = all models, e.g. [\"claude-*-haiku*\"]"),
+		field.JSON("denied_models", []string{}).
+			Optional().
+			Comment("Denied model patterns (* wildcard), checked before allowed_models"),
+		field.JSON("model_aliases", map[string]string{}).
+			Optional().
+			Comment("Per-key model aliases, e.g. {\"fast\": \"claude-haiku-4-5\"}"),
+
 		// ========== Quota fields ==========
 		// Quota limit in USD (0 = unlimited)
 		field.Float("quota").
@@ -115,6 +135,18 @@ func (APIKey) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			Comment("Start time of the current 7d rate limit window"),
+
+		// ========== Throughput limit fields ==========
+		// Enforced per request via Redis (0 = unlimited)
+		field.Int("rpm_limit").
+			Default(0).
+			Comment("Max requests per minute for this API key (0 = unlimited)"),
+		field.Int("tpm_limit").
+			Default(0).
+			Comment("Max tokens per minute for this API key (0 = unlimited)"),
+		field.Int("max_concurrency").
+			Default(0).
+			Comment("Max in-flight requests for this API key (0 = unlimited)"),
 	}
 }
 
@@ -129,15 +161,21 @@ func (APIKey) Edges() []ent.Edge {
 			Ref("api_keys").
 			Field("group_id").
 			Unique(),
+		edge.From("organization", Organization.Type).
+			Ref("api_keys").
+			Field("organization_id").
+			Unique(),
 		edge.To("usage_logs", UsageLog.Type),
 	}
 }
 
 func (APIKey) Indexes() []ent.Index {
 	return []ent.Index{
-		// key 字段已在 Fields() 中声明 Unique()，无需重复索引
+		// key_hash 字段已在 Fields() 中声明 Unique()，无需重复索引
+		index.Fields("key_prefix"),
 		index.Fields("user_id"),
 		index.Fields("group_id"),
+		index.Fields("organization_id"),
 		index.Fields("status"),
 		index.Fields("deleted_at"),
 		index.Fields("last_used_at"),
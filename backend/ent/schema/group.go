@@ -167,6 +167,24 @@ func (Group) Fields() []ent.Field {
 			MaxLen(100).
 			Default("").
 			Comment("默认映射模型 ID，当账号级映射找不到时使用此值"),
+
+		// 精确响应缓存配置 (added by migration 085)
+		field.Bool("response_cache_enabled").
+			Default(false).
+			Comment("是否对确定性请求（temperature=0）启用精确响应缓存"),
+		field.Int("response_cache_ttl_seconds").
+			Default(0).
+			Comment("响应缓存 TTL（秒），0 表示使用全局默认值"),
+		field.Float("response_cache_price_ratio").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(10,4)"}).
+			Default(0.1).
+			Comment("缓存命中计费比例（相对正常价格），0 表示免费"),
+
+		// 模型降级链 (added by migration 087)
+		field.JSON("model_fallback_chains", []domain.ModelFallbackChain{}).
+			Optional().
+			SchemaType(map[string]string{dialect.Postgres: "jsonb"}).
+			Comment("分组内有序模型降级链：所有账号限流/过载/prompt 过长时按序切换模型"),
 	}
 }
 
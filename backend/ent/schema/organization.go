@@ -0,0 +1,72 @@
+package schema
+
+import (
+	"github.com/Wei-Shaw/sub2api/ent/schema/mixins"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// Organization holds the schema definition for the Organization entity.
+//
+// 组织（团队）：拥有共享余额，成员通过组织 Key 消费组织余额。
+// 订阅类分组的组织 Key 使用 owner 名下的订阅。
+type Organization struct {
+	ent.Schema
+}
+
+func (Organization) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "organizations"},
+	}
+}
+
+func (Organization) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixins.TimeMixin{},
+		mixins.SoftDeleteMixin{},
+	}
+}
+
+func (Organization) Fields() []ent.Field {
+	return []ent.Field{
+		field.String("name").
+			MaxLen(100).
+			NotEmpty(),
+		field.Int64("owner_user_id").
+			Comment("Owner user; subscription groups are billed against the owner's subscriptions"),
+		field.Float("balance").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+			Default(0).
+			Comment("Shared balance in USD consumed by organization API keys"),
+		field.String("status").
+			MaxLen(20).
+			Default(domain.StatusActive),
+	}
+}
+
+func (Organization) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("owner", User.Type).
+			Ref("owned_organizations").
+			Field("owner_user_id").
+			Unique().
+			Required(),
+		edge.To("members", OrganizationMember.Type),
+		edge.To("api_keys", APIKey.Type),
+	}
+}
+
+func (Organization) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("owner_user_id"),
+		index.Fields("status"),
+		index.Fields("deleted_at"),
+	}
+}
@@ -0,0 +1,78 @@
+package schema
+
+import (
+	"github.com/Wei-Shaw/sub2api/ent/schema/mixins"
+
+	"entgo.io/ent"
+	"entgo.io/ent/dialect"
+	"entgo.io/ent/dialect/entsql"
+	"entgo.io/ent/schema"
+	"entgo.io/ent/schema/edge"
+	"entgo.io/ent/schema/field"
+	"entgo.io/ent/schema/index"
+)
+
+// OrganizationMember holds the schema definition for the OrganizationMember entity.
+//
+// 组织成员：角色（owner/admin/member）与按自然月统计的消费上限。
+type OrganizationMember struct {
+	ent.Schema
+}
+
+func (OrganizationMember) Annotations() []schema.Annotation {
+	return []schema.Annotation{
+		entsql.Annotation{Table: "organization_members"},
+	}
+}
+
+func (OrganizationMember) Mixin() []ent.Mixin {
+	return []ent.Mixin{
+		mixins.TimeMixin{},
+	}
+}
+
+func (OrganizationMember) Fields() []ent.Field {
+	return []ent.Field{
+		field.Int64("organization_id"),
+		field.Int64("user_id"),
+		field.String("role").
+			MaxLen(20).
+			Default("member").
+			Comment("owner / admin / member"),
+		field.Float("monthly_limit_usd").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+			Default(0).
+			Comment("Monthly spending cap in USD for this member (0 = unlimited)"),
+		field.Float("monthly_usage_usd").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,10)"}).
+			Default(0).
+			Comment("Spending in USD within the current calendar month"),
+		field.Time("monthly_window_start").
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}).
+			Comment("Start of the calendar month monthly_usage_usd belongs to"),
+	}
+}
+
+func (OrganizationMember) Edges() []ent.Edge {
+	return []ent.Edge{
+		edge.From("organization", Organization.Type).
+			Ref("members").
+			Field("organization_id").
+			Unique().
+			Required(),
+		edge.From("user", User.Type).
+			Ref("organization_memberships").
+			Field("user_id").
+			Unique().
+			Required(),
+	}
+}
+
+func (OrganizationMember) Indexes() []ent.Index {
+	return []ent.Index{
+		index.Fields("organization_id", "user_id").Unique(),
+		index.Fields("user_id"),
+	}
+}
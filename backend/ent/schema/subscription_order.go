@@ -36,7 +36,13 @@ func (SubscriptionOrder) Fields() []ent.Field {
 			NotEmpty().
 			Unique(),
 		field.Int64("user_id"),
-		field.Int64("group_id"),
+		field.Int64("group_id").
+			Optional().
+			Nillable(),
+		// order_type: subscription（购买订阅）/ balance（余额充值）
+		field.String("order_type").
+			MaxLen(20).
+			Default("subscription"),
 		field.Int64("subscription_id").
 			Optional().
 			Nillable(),
@@ -74,6 +80,10 @@ func (SubscriptionOrder) Fields() []ent.Field {
 			Default("CNY"),
 		field.Int("validity_days").
 			Default(30),
+		// credit_amount: 充值订单支付成功后为用户增加的余额
+		field.Float("credit_amount").
+			SchemaType(map[string]string{dialect.Postgres: "decimal(20,8)"}).
+			Default(0),
 		field.Time("paid_at").
 			Optional().
 			Nillable().
@@ -82,6 +92,10 @@ func (SubscriptionOrder) Fields() []ent.Field {
 			Optional().
 			Nillable().
 			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}),
+		field.Time("refunded_at").
+			Optional().
+			Nillable().
+			SchemaType(map[string]string{dialect.Postgres: "timestamptz"}),
 		field.String("notes").
 			Optional().
 			Nillable().
@@ -99,8 +113,7 @@ func (SubscriptionOrder) Edges() []ent.Edge {
 		edge.From("group", Group.Type).
 			Ref("subscription_orders").
 			Field("group_id").
-			Unique().
-			Required(),
+			Unique(),
 		edge.From("subscription", UserSubscription.Type).
 			Ref("orders").
 			Field("subscription_id").
@@ -113,6 +126,7 @@ func (SubscriptionOrder) Indexes() []ent.Index {
 		index.Fields("user_id"),
 		index.Fields("group_id"),
 		index.Fields("status"),
+		index.Fields("order_type"),
 		index.Fields("created_at"),
 	}
 }
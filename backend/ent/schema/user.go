@@ -94,6 +94,8 @@ func (User) Edges() []ent.Edge {
 		edge.To("usage_logs", UsageLog.Type),
 		edge.To("attribute_values", UserAttributeValue.Type),
 		edge.To("promo_code_usages", PromoCodeUsage.Type),
+		edge.To("owned_organizations", Organization.Type),
+		edge.To("organization_memberships", OrganizationMember.Type),
 	}
 }
 
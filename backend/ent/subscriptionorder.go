@@ -29,7 +29,9 @@ type SubscriptionOrder struct {
 	// UserID holds the value of the "user_id" field.
 	UserID int64 `json:"user_id,omitempty"`
 	// GroupID holds the value of the "group_id" field.
-	GroupID int64 `json:"group_id,omitempty"`
+	GroupID *int64 `json:"group_id,omitempty"`
+	// OrderType holds the value of the "order_type" field.
+	OrderType string `json:"order_type,omitempty"`
 	// SubscriptionID holds the value of the "subscription_id" field.
 	SubscriptionID *int64 `json:"subscription_id,omitempty"`
 	// PaymentProvider holds the value of the "payment_provider" field.
@@ -52,10 +54,14 @@ type SubscriptionOrder struct {
 	Currency string `json:"currency,omitempty"`
 	// ValidityDays holds the value of the "validity_days" field.
 	ValidityDays int `json:"validity_days,omitempty"`
+	// CreditAmount holds the value of the "credit_amount" field.
+	CreditAmount float64 `json:"credit_amount,omitempty"`
 	// PaidAt holds the value of the "paid_at" field.
 	PaidAt *time.Time `json:"paid_at,omitempty"`
 	// CanceledAt holds the value of the "canceled_at" field.
 	CanceledAt *time.Time `json:"canceled_at,omitempty"`
+	// RefundedAt holds the value of the "refunded_at" field.
+	RefundedAt *time.Time `json:"refunded_at,omitempty"`
 	// Notes holds the value of the "notes" field.
 	Notes *string `json:"notes,omitempty"`
 	// Edges holds the relations/edges for other nodes in the graph.
@@ -115,13 +121,13 @@ func (*SubscriptionOrder) scanValues(columns []string) ([]any, error) {
 	values := make([]any, len(columns))
 	for i := range columns {
 		switch columns[i] {
-		case subscriptionorder.FieldAmount:
+		case subscriptionorder.FieldAmount, subscriptionorder.FieldCreditAmount:
 			values[i] = new(sql.NullFloat64)
 		case subscriptionorder.FieldID, subscriptionorder.FieldUserID, subscriptionorder.FieldGroupID, subscriptionorder.FieldSubscriptionID, subscriptionorder.FieldValidityDays:
 			values[i] = new(sql.NullInt64)
-		case subscriptionorder.FieldOrderNo, subscriptionorder.FieldPaymentProvider, subscriptionorder.FieldPaymentURL, subscriptionorder.FieldPaymentQrcode, subscriptionorder.FieldPaymentOpenOrderID, subscriptionorder.FieldPaymentTransactionID, subscriptionorder.FieldPaymentPlugin, subscriptionorder.FieldStatus, subscriptionorder.FieldCurrency, subscriptionorder.FieldNotes:
+		case subscriptionorder.FieldOrderNo, subscriptionorder.FieldOrderType, subscriptionorder.FieldPaymentProvider, subscriptionorder.FieldPaymentURL, subscriptionorder.FieldPaymentQrcode, subscriptionorder.FieldPaymentOpenOrderID, subscriptionorder.FieldPaymentTransactionID, subscriptionorder.FieldPaymentPlugin, subscriptionorder.FieldStatus, subscriptionorder.FieldCurrency, subscriptionorder.FieldNotes:
 			values[i] = new(sql.NullString)
-		case subscriptionorder.FieldCreatedAt, subscriptionorder.FieldUpdatedAt, subscriptionorder.FieldPaidAt, subscriptionorder.FieldCanceledAt:
+		case subscriptionorder.FieldCreatedAt, subscriptionorder.FieldUpdatedAt, subscriptionorder.FieldPaidAt, subscriptionorder.FieldCanceledAt, subscriptionorder.FieldRefundedAt:
 			values[i] = new(sql.NullTime)
 		default:
 			values[i] = new(sql.UnknownType)
@@ -172,7 +178,14 @@ func (_m *SubscriptionOrder) assignValues(columns []string, values []any) error
 			if value, ok := values[i].(*sql.NullInt64); !ok {
 				return fmt.Errorf("unexpected type %T for field group_id", values[i])
 			} else if value.Valid {
-				_m.GroupID = value.Int64
+				_m.GroupID = new(int64)
+				*_m.GroupID = value.Int64
+			}
+		case subscriptionorder.FieldOrderType:
+			if value, ok := values[i].(*sql.NullString); !ok {
+				return fmt.Errorf("unexpected type %T for field order_type", values[i])
+			} else if value.Valid {
+				_m.OrderType = value.String
 			}
 		case subscriptionorder.FieldSubscriptionID:
 			if value, ok := values[i].(*sql.NullInt64); !ok {
@@ -246,6 +259,12 @@ func (_m *SubscriptionOrder) assignValues(columns []string, values []any) error
 			} else if value.Valid {
 				_m.ValidityDays = int(value.Int64)
 			}
+		case subscriptionorder.FieldCreditAmount:
+			if value, ok := values[i].(*sql.NullFloat64); !ok {
+				return fmt.Errorf("unexpected type %T for field credit_amount", values[i])
+			} else if value.Valid {
+				_m.CreditAmount = value.Float64
+			}
 		case subscriptionorder.FieldPaidAt:
 			if value, ok := values[i].(*sql.NullTime); !ok {
 				return fmt.Errorf("unexpected type %T for field paid_at", values[i])
@@ -260,6 +279,13 @@ func (_m *SubscriptionOrder) assignValues(columns []string, values []any) error
 				_m.CanceledAt = new(time.Time)
 				*_m.CanceledAt = value.Time
 			}
+		case subscriptionorder.FieldRefundedAt:
+			if value, ok := values[i].(*sql.NullTime); !ok {
+				return fmt.Errorf("unexpected type %T for field refunded_at", values[i])
+			} else if value.Valid {
+				_m.RefundedAt = new(time.Time)
+				*_m.RefundedAt = value.Time
+			}
 		case subscriptionorder.FieldNotes:
 			if value, ok := values[i].(*sql.NullString); !ok {
 				return fmt.Errorf("unexpected type %T for field notes", values[i])
@@ -330,8 +356,13 @@ func (_m *SubscriptionOrder) String() string {
 	builder.WriteString("user_id=")
 	builder.WriteString(fmt.Sprintf("%v", _m.UserID))
 	builder.WriteString(", ")
-	builder.WriteString("group_id=")
-	builder.WriteString(fmt.Sprintf("%v", _m.GroupID))
+	if v := _m.GroupID; v != nil {
+		builder.WriteString("group_id=")
+		builder.WriteString(fmt.Sprintf("%v", *v))
+	}
+	builder.WriteString(", ")
+	builder.WriteString("order_type=")
+	builder.WriteString(_m.OrderType)
 	builder.WriteString(", ")
 	if v := _m.SubscriptionID; v != nil {
 		builder.WriteString("subscription_id=")
@@ -378,6 +409,9 @@ func (_m *SubscriptionOrder) String() string {
 	builder.WriteString("validity_days=")
 	builder.WriteString(fmt.Sprintf("%v", _m.ValidityDays))
 	builder.WriteString(", ")
+	builder.WriteString("credit_amount=")
+	builder.WriteString(fmt.Sprintf("%v", _m.CreditAmount))
+	builder.WriteString(", ")
 	if v := _m.PaidAt; v != nil {
 		builder.WriteString("paid_at=")
 		builder.WriteString(v.Format(time.ANSIC))
@@ -388,6 +422,11 @@ func (_m *SubscriptionOrder) String() string {
 		builder.WriteString(v.Format(time.ANSIC))
 	}
 	builder.WriteString(", ")
+	if v := _m.RefundedAt; v != nil {
+		builder.WriteString("refunded_at=")
+		builder.WriteString(v.Format(time.ANSIC))
+	}
+	builder.WriteString(", ")
 	if v := _m.Notes; v != nil {
 		builder.WriteString("notes=")
 		builder.WriteString(*v)
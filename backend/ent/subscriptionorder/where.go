@@ -80,6 +80,11 @@ func GroupID(v int64) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldEQ(FieldGroupID, v))
 }
 
+// OrderType applies equality check predicate on the "order_type" field. It's identical to OrderTypeEQ.
+func OrderType(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldEQ(FieldOrderType, v))
+}
+
 // SubscriptionID applies equality check predicate on the "subscription_id" field. It's identical to SubscriptionIDEQ.
 func SubscriptionID(v int64) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldEQ(FieldSubscriptionID, v))
@@ -135,6 +140,11 @@ func ValidityDays(v int) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldEQ(FieldValidityDays, v))
 }
 
+// CreditAmount applies equality check predicate on the "credit_amount" field. It's identical to CreditAmountEQ.
+func CreditAmount(v float64) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldEQ(FieldCreditAmount, v))
+}
+
 // PaidAt applies equality check predicate on the "paid_at" field. It's identical to PaidAtEQ.
 func PaidAt(v time.Time) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldEQ(FieldPaidAt, v))
@@ -145,6 +155,11 @@ func CanceledAt(v time.Time) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldEQ(FieldCanceledAt, v))
 }
 
+// RefundedAt applies equality check predicate on the "refunded_at" field. It's identical to RefundedAtEQ.
+func RefundedAt(v time.Time) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldEQ(FieldRefundedAt, v))
+}
+
 // Notes applies equality check predicate on the "notes" field. It's identical to NotesEQ.
 func Notes(v string) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldEQ(FieldNotes, v))
@@ -335,6 +350,81 @@ func GroupIDNotIn(vs ...int64) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldNotIn(FieldGroupID, vs...))
 }
 
+// GroupIDIsNil applies the IsNil predicate on the "group_id" field.
+func GroupIDIsNil() predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldIsNull(FieldGroupID))
+}
+
+// GroupIDNotNil applies the NotNil predicate on the "group_id" field.
+func GroupIDNotNil() predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldNotNull(FieldGroupID))
+}
+
+// OrderTypeEQ applies the EQ predicate on the "order_type" field.
+func OrderTypeEQ(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldEQ(FieldOrderType, v))
+}
+
+// OrderTypeNEQ applies the NEQ predicate on the "order_type" field.
+func OrderTypeNEQ(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldNEQ(FieldOrderType, v))
+}
+
+// OrderTypeIn applies the In predicate on the "order_type" field.
+func OrderTypeIn(vs ...string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldIn(FieldOrderType, vs...))
+}
+
+// OrderTypeNotIn applies the NotIn predicate on the "order_type" field.
+func OrderTypeNotIn(vs ...string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldNotIn(FieldOrderType, vs...))
+}
+
+// OrderTypeGT applies the GT predicate on the "order_type" field.
+func OrderTypeGT(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldGT(FieldOrderType, v))
+}
+
+// OrderTypeGTE applies the GTE predicate on the "order_type" field.
+func OrderTypeGTE(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldGTE(FieldOrderType, v))
+}
+
+// OrderTypeLT applies the LT predicate on the "order_type" field.
+func OrderTypeLT(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldLT(FieldOrderType, v))
+}
+
+// OrderTypeLTE applies the LTE predicate on the "order_type" field.
+func OrderTypeLTE(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldLTE(FieldOrderType, v))
+}
+
+// OrderTypeContains applies the Contains predicate on the "order_type" field.
+func OrderTypeContains(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldContains(FieldOrderType, v))
+}
+
+// OrderTypeHasPrefix applies the HasPrefix predicate on the "order_type" field.
+func OrderTypeHasPrefix(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldHasPrefix(FieldOrderType, v))
+}
+
+// OrderTypeHasSuffix applies the HasSuffix predicate on the "order_type" field.
+func OrderTypeHasSuffix(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldHasSuffix(FieldOrderType, v))
+}
+
+// OrderTypeEqualFold applies the EqualFold predicate on the "order_type" field.
+func OrderTypeEqualFold(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldEqualFold(FieldOrderType, v))
+}
+
+// OrderTypeContainsFold applies the ContainsFold predicate on the "order_type" field.
+func OrderTypeContainsFold(v string) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldContainsFold(FieldOrderType, v))
+}
+
 // SubscriptionIDEQ applies the EQ predicate on the "subscription_id" field.
 func SubscriptionIDEQ(v int64) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldEQ(FieldSubscriptionID, v))
@@ -1015,6 +1105,46 @@ func ValidityDaysLTE(v int) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldLTE(FieldValidityDays, v))
 }
 
+// CreditAmountEQ applies the EQ predicate on the "credit_amount" field.
+func CreditAmountEQ(v float64) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldEQ(FieldCreditAmount, v))
+}
+
+// CreditAmountNEQ applies the NEQ predicate on the "credit_amount" field.
+func CreditAmountNEQ(v float64) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldNEQ(FieldCreditAmount, v))
+}
+
+// CreditAmountIn applies the In predicate on the "credit_amount" field.
+func CreditAmountIn(vs ...float64) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldIn(FieldCreditAmount, vs...))
+}
+
+// CreditAmountNotIn applies the NotIn predicate on the "credit_amount" field.
+func CreditAmountNotIn(vs ...float64) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldNotIn(FieldCreditAmount, vs...))
+}
+
+// CreditAmountGT applies the GT predicate on the "credit_amount" field.
+func CreditAmountGT(v float64) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldGT(FieldCreditAmount, v))
+}
+
+// CreditAmountGTE applies the GTE predicate on the "credit_amount" field.
+func CreditAmountGTE(v float64) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldGTE(FieldCreditAmount, v))
+}
+
+// CreditAmountLT applies the LT predicate on the "credit_amount" field.
+func CreditAmountLT(v float64) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldLT(FieldCreditAmount, v))
+}
+
+// CreditAmountLTE applies the LTE predicate on the "credit_amount" field.
+func CreditAmountLTE(v float64) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldLTE(FieldCreditAmount, v))
+}
+
 // PaidAtEQ applies the EQ predicate on the "paid_at" field.
 func PaidAtEQ(v time.Time) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldEQ(FieldPaidAt, v))
@@ -1115,6 +1245,56 @@ func CanceledAtNotNil() predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldNotNull(FieldCanceledAt))
 }
 
+// RefundedAtEQ applies the EQ predicate on the "refunded_at" field.
+func RefundedAtEQ(v time.Time) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldEQ(FieldRefundedAt, v))
+}
+
+// RefundedAtNEQ applies the NEQ predicate on the "refunded_at" field.
+func RefundedAtNEQ(v time.Time) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldNEQ(FieldRefundedAt, v))
+}
+
+// RefundedAtIn applies the In predicate on the "refunded_at" field.
+func RefundedAtIn(vs ...time.Time) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldIn(FieldRefundedAt, vs...))
+}
+
+// RefundedAtNotIn applies the NotIn predicate on the "refunded_at" field.
+func RefundedAtNotIn(vs ...time.Time) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldNotIn(FieldRefundedAt, vs...))
+}
+
+// RefundedAtGT applies the GT predicate on the "refunded_at" field.
+func RefundedAtGT(v time.Time) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldGT(FieldRefundedAt, v))
+}
+
+// RefundedAtGTE applies the GTE predicate on the "refunded_at" field.
+func RefundedAtGTE(v time.Time) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldGTE(FieldRefundedAt, v))
+}
+
+// RefundedAtLT applies the LT predicate on the "refunded_at" field.
+func RefundedAtLT(v time.Time) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldLT(FieldRefundedAt, v))
+}
+
+// RefundedAtLTE applies the LTE predicate on the "refunded_at" field.
+func RefundedAtLTE(v time.Time) predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldLTE(FieldRefundedAt, v))
+}
+
+// RefundedAtIsNil applies the IsNil predicate on the "refunded_at" field.
+func RefundedAtIsNil() predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldIsNull(FieldRefundedAt))
+}
+
+// RefundedAtNotNil applies the NotNil predicate on the "refunded_at" field.
+func RefundedAtNotNil() predicate.SubscriptionOrder {
+	return predicate.SubscriptionOrder(sql.FieldNotNull(FieldRefundedAt))
+}
+
 // NotesEQ applies the EQ predicate on the "notes" field.
 func NotesEQ(v string) predicate.SubscriptionOrder {
 	return predicate.SubscriptionOrder(sql.FieldEQ(FieldNotes, v))
@@ -71,6 +71,28 @@ func (_c *SubscriptionOrderCreate) SetGroupID(v int64) *SubscriptionOrderCreate
 	return _c
 }
 
+// SetNillableGroupID sets the "group_id" field if the given value is not nil.
+func (_c *SubscriptionOrderCreate) SetNillableGroupID(v *int64) *SubscriptionOrderCreate {
+	if v != nil {
+		_c.SetGroupID(*v)
+	}
+	return _c
+}
+
+// SetOrderType sets the "order_type" field.
+func (_c *SubscriptionOrderCreate) SetOrderType(v string) *SubscriptionOrderCreate {
+	_c.mutation.SetOrderType(v)
+	return _c
+}
+
+// SetNillableOrderType sets the "order_type" field if the given value is not nil.
+func (_c *SubscriptionOrderCreate) SetNillableOrderType(v *string) *SubscriptionOrderCreate {
+	if v != nil {
+		_c.SetOrderType(*v)
+	}
+	return _c
+}
+
 // SetSubscriptionID sets the "subscription_id" field.
 func (_c *SubscriptionOrderCreate) SetSubscriptionID(v int64) *SubscriptionOrderCreate {
 	_c.mutation.SetSubscriptionID(v)
@@ -225,6 +247,20 @@ func (_c *SubscriptionOrderCreate) SetNillableValidityDays(v *int) *Subscription
 	return _c
 }
 
+// SetCreditAmount sets the "credit_amount" field.
+func (_c *SubscriptionOrderCreate) SetCreditAmount(v float64) *SubscriptionOrderCreate {
+	_c.mutation.SetCreditAmount(v)
+	return _c
+}
+
+// SetNillableCreditAmount sets the "credit_amount" field if the given value is not nil.
+func (_c *SubscriptionOrderCreate) SetNillableCreditAmount(v *float64) *SubscriptionOrderCreate {
+	if v != nil {
+		_c.SetCreditAmount(*v)
+	}
+	return _c
+}
+
 // SetPaidAt sets the "paid_at" field.
 func (_c *SubscriptionOrderCreate) SetPaidAt(v time.Time) *SubscriptionOrderCreate {
 	_c.mutation.SetPaidAt(v)
@@ -253,6 +289,20 @@ func (_c *SubscriptionOrderCreate) SetNillableCanceledAt(v *time.Time) *Subscrip
 	return _c
 }
 
+// SetRefundedAt sets the "refunded_at" field.
+func (_c *SubscriptionOrderCreate) SetRefundedAt(v time.Time) *SubscriptionOrderCreate {
+	_c.mutation.SetRefundedAt(v)
+	return _c
+}
+
+// SetNillableRefundedAt sets the "refunded_at" field if the given value is not nil.
+func (_c *SubscriptionOrderCreate) SetNillableRefundedAt(v *time.Time) *SubscriptionOrderCreate {
+	if v != nil {
+		_c.SetRefundedAt(*v)
+	}
+	return _c
+}
+
 // SetNotes sets the "notes" field.
 func (_c *SubscriptionOrderCreate) SetNotes(v string) *SubscriptionOrderCreate {
 	_c.mutation.SetNotes(v)
@@ -325,6 +375,10 @@ func (_c *SubscriptionOrderCreate) defaults() {
 		v := subscriptionorder.DefaultUpdatedAt()
 		_c.mutation.SetUpdatedAt(v)
 	}
+	if _, ok := _c.mutation.OrderType(); !ok {
+		v := subscriptionorder.DefaultOrderType
+		_c.mutation.SetOrderType(v)
+	}
 	if _, ok := _c.mutation.PaymentProvider(); !ok {
 		v := subscriptionorder.DefaultPaymentProvider
 		_c.mutation.SetPaymentProvider(v)
@@ -345,6 +399,10 @@ func (_c *SubscriptionOrderCreate) defaults() {
 		v := subscriptionorder.DefaultValidityDays
 		_c.mutation.SetValidityDays(v)
 	}
+	if _, ok := _c.mutation.CreditAmount(); !ok {
+		v := subscriptionorder.DefaultCreditAmount
+		_c.mutation.SetCreditAmount(v)
+	}
 }
 
 // check runs all checks and user-defined validators on the builder.
@@ -366,8 +424,13 @@ func (_c *SubscriptionOrderCreate) check() error {
 	if _, ok := _c.mutation.UserID(); !ok {
 		return &ValidationError{Name: "user_id", err: errors.New(`ent: missing required field "SubscriptionOrder.user_id"`)}
 	}
-	if _, ok := _c.mutation.GroupID(); !ok {
-		return &ValidationError{Name: "group_id", err: errors.New(`ent: missing required field "SubscriptionOrder.group_id"`)}
+	if _, ok := _c.mutation.OrderType(); !ok {
+		return &ValidationError{Name: "order_type", err: errors.New(`ent: missing required field "SubscriptionOrder.order_type"`)}
+	}
+	if v, ok := _c.mutation.OrderType(); ok {
+		if err := subscriptionorder.OrderTypeValidator(v); err != nil {
+			return &ValidationError{Name: "order_type", err: fmt.Errorf(`ent: validator failed for field "SubscriptionOrder.order_type": %w`, err)}
+		}
 	}
 	if _, ok := _c.mutation.PaymentProvider(); !ok {
 		return &ValidationError{Name: "payment_provider", err: errors.New(`ent: missing required field "SubscriptionOrder.payment_provider"`)}
@@ -414,12 +477,12 @@ func (_c *SubscriptionOrderCreate) check() error {
 	if _, ok := _c.mutation.ValidityDays(); !ok {
 		return &ValidationError{Name: "validity_days", err: errors.New(`ent: missing required field "SubscriptionOrder.validity_days"`)}
 	}
+	if _, ok := _c.mutation.CreditAmount(); !ok {
+		return &ValidationError{Name: "credit_amount", err: errors.New(`ent: missing required field "SubscriptionOrder.credit_amount"`)}
+	}
 	if len(_c.mutation.UserIDs()) == 0 {
 		return &ValidationError{Name: "user", err: errors.New(`ent: missing required edge "SubscriptionOrder.user"`)}
 	}
-	if len(_c.mutation.GroupIDs()) == 0 {
-		return &ValidationError{Name: "group", err: errors.New(`ent: missing required edge "SubscriptionOrder.group"`)}
-	}
 	return nil
 }
 
@@ -459,6 +522,10 @@ func (_c *SubscriptionOrderCreate) createSpec() (*SubscriptionOrder, *sqlgraph.C
 		_spec.SetField(subscriptionorder.FieldOrderNo, field.TypeString, value)
 		_node.OrderNo = value
 	}
+	if value, ok := _c.mutation.OrderType(); ok {
+		_spec.SetField(subscriptionorder.FieldOrderType, field.TypeString, value)
+		_node.OrderType = value
+	}
 	if value, ok := _c.mutation.PaymentProvider(); ok {
 		_spec.SetField(subscriptionorder.FieldPaymentProvider, field.TypeString, value)
 		_node.PaymentProvider = value
@@ -499,6 +566,10 @@ func (_c *SubscriptionOrderCreate) createSpec() (*SubscriptionOrder, *sqlgraph.C
 		_spec.SetField(subscriptionorder.FieldValidityDays, field.TypeInt, value)
 		_node.ValidityDays = value
 	}
+	if value, ok := _c.mutation.CreditAmount(); ok {
+		_spec.SetField(subscriptionorder.FieldCreditAmount, field.TypeFloat64, value)
+		_node.CreditAmount = value
+	}
 	if value, ok := _c.mutation.PaidAt(); ok {
 		_spec.SetField(subscriptionorder.FieldPaidAt, field.TypeTime, value)
 		_node.PaidAt = &value
@@ -507,6 +578,10 @@ func (_c *SubscriptionOrderCreate) createSpec() (*SubscriptionOrder, *sqlgraph.C
 		_spec.SetField(subscriptionorder.FieldCanceledAt, field.TypeTime, value)
 		_node.CanceledAt = &value
 	}
+	if value, ok := _c.mutation.RefundedAt(); ok {
+		_spec.SetField(subscriptionorder.FieldRefundedAt, field.TypeTime, value)
+		_node.RefundedAt = &value
+	}
 	if value, ok := _c.mutation.Notes(); ok {
 		_spec.SetField(subscriptionorder.FieldNotes, field.TypeString, value)
 		_node.Notes = &value
@@ -542,7 +617,7 @@ func (_c *SubscriptionOrderCreate) createSpec() (*SubscriptionOrder, *sqlgraph.C
 		for _, k := range nodes {
 			edge.Target.Nodes = append(edge.Target.Nodes, k)
 		}
-		_node.GroupID = nodes[0]
+		_node.GroupID = &nodes[0]
 		_spec.Edges = append(_spec.Edges, edge)
 	}
 	if nodes := _c.mutation.SubscriptionIDs(); len(nodes) > 0 {
@@ -662,6 +737,24 @@ func (u *SubscriptionOrderUpsert) UpdateGroupID() *SubscriptionOrderUpsert {
 	return u
 }
 
+// ClearGroupID clears the value of the "group_id" field.
+func (u *SubscriptionOrderUpsert) ClearGroupID() *SubscriptionOrderUpsert {
+	u.SetNull(subscriptionorder.FieldGroupID)
+	return u
+}
+
+// SetOrderType sets the "order_type" field.
+func (u *SubscriptionOrderUpsert) SetOrderType(v string) *SubscriptionOrderUpsert {
+	u.Set(subscriptionorder.FieldOrderType, v)
+	return u
+}
+
+// UpdateOrderType sets the "order_type" field to the value that was provided on create.
+func (u *SubscriptionOrderUpsert) UpdateOrderType() *SubscriptionOrderUpsert {
+	u.SetExcluded(subscriptionorder.FieldOrderType)
+	return u
+}
+
 // SetSubscriptionID sets the "subscription_id" field.
 func (u *SubscriptionOrderUpsert) SetSubscriptionID(v int64) *SubscriptionOrderUpsert {
 	u.Set(subscriptionorder.FieldSubscriptionID, v)
@@ -842,6 +935,24 @@ func (u *SubscriptionOrderUpsert) AddValidityDays(v int) *SubscriptionOrderUpser
 	return u
 }
 
+// SetCreditAmount sets the "credit_amount" field.
+func (u *SubscriptionOrderUpsert) SetCreditAmount(v float64) *SubscriptionOrderUpsert {
+	u.Set(subscriptionorder.FieldCreditAmount, v)
+	return u
+}
+
+// UpdateCreditAmount sets the "credit_amount" field to the value that was provided on create.
+func (u *SubscriptionOrderUpsert) UpdateCreditAmount() *SubscriptionOrderUpsert {
+	u.SetExcluded(subscriptionorder.FieldCreditAmount)
+	return u
+}
+
+// AddCreditAmount adds v to the "credit_amount" field.
+func (u *SubscriptionOrderUpsert) AddCreditAmount(v float64) *SubscriptionOrderUpsert {
+	u.Add(subscriptionorder.FieldCreditAmount, v)
+	return u
+}
+
 // SetPaidAt sets the "paid_at" field.
 func (u *SubscriptionOrderUpsert) SetPaidAt(v time.Time) *SubscriptionOrderUpsert {
 	u.Set(subscriptionorder.FieldPaidAt, v)
@@ -878,6 +989,24 @@ func (u *SubscriptionOrderUpsert) ClearCanceledAt() *SubscriptionOrderUpsert {
 	return u
 }
 
+// SetRefundedAt sets the "refunded_at" field.
+func (u *SubscriptionOrderUpsert) SetRefundedAt(v time.Time) *SubscriptionOrderUpsert {
+	u.Set(subscriptionorder.FieldRefundedAt, v)
+	return u
+}
+
+// UpdateRefundedAt sets the "refunded_at" field to the value that was provided on create.
+func (u *SubscriptionOrderUpsert) UpdateRefundedAt() *SubscriptionOrderUpsert {
+	u.SetExcluded(subscriptionorder.FieldRefundedAt)
+	return u
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (u *SubscriptionOrderUpsert) ClearRefundedAt() *SubscriptionOrderUpsert {
+	u.SetNull(subscriptionorder.FieldRefundedAt)
+	return u
+}
+
 // SetNotes sets the "notes" field.
 func (u *SubscriptionOrderUpsert) SetNotes(v string) *SubscriptionOrderUpsert {
 	u.Set(subscriptionorder.FieldNotes, v)
@@ -997,6 +1126,27 @@ func (u *SubscriptionOrderUpsertOne) UpdateGroupID() *SubscriptionOrderUpsertOne
 	})
 }
 
+// ClearGroupID clears the value of the "group_id" field.
+func (u *SubscriptionOrderUpsertOne) ClearGroupID() *SubscriptionOrderUpsertOne {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.ClearGroupID()
+	})
+}
+
+// SetOrderType sets the "order_type" field.
+func (u *SubscriptionOrderUpsertOne) SetOrderType(v string) *SubscriptionOrderUpsertOne {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.SetOrderType(v)
+	})
+}
+
+// UpdateOrderType sets the "order_type" field to the value that was provided on create.
+func (u *SubscriptionOrderUpsertOne) UpdateOrderType() *SubscriptionOrderUpsertOne {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.UpdateOrderType()
+	})
+}
+
 // SetSubscriptionID sets the "subscription_id" field.
 func (u *SubscriptionOrderUpsertOne) SetSubscriptionID(v int64) *SubscriptionOrderUpsertOne {
 	return u.Update(func(s *SubscriptionOrderUpsert) {
@@ -1207,6 +1357,27 @@ func (u *SubscriptionOrderUpsertOne) UpdateValidityDays() *SubscriptionOrderUpse
 	})
 }
 
+// SetCreditAmount sets the "credit_amount" field.
+func (u *SubscriptionOrderUpsertOne) SetCreditAmount(v float64) *SubscriptionOrderUpsertOne {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.SetCreditAmount(v)
+	})
+}
+
+// AddCreditAmount adds v to the "credit_amount" field.
+func (u *SubscriptionOrderUpsertOne) AddCreditAmount(v float64) *SubscriptionOrderUpsertOne {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.AddCreditAmount(v)
+	})
+}
+
+// UpdateCreditAmount sets the "credit_amount" field to the value that was provided on create.
+func (u *SubscriptionOrderUpsertOne) UpdateCreditAmount() *SubscriptionOrderUpsertOne {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.UpdateCreditAmount()
+	})
+}
+
 // SetPaidAt sets the "paid_at" field.
 func (u *SubscriptionOrderUpsertOne) SetPaidAt(v time.Time) *SubscriptionOrderUpsertOne {
 	return u.Update(func(s *SubscriptionOrderUpsert) {
@@ -1249,6 +1420,27 @@ func (u *SubscriptionOrderUpsertOne) ClearCanceledAt() *SubscriptionOrderUpsertO
 	})
 }
 
+// SetRefundedAt sets the "refunded_at" field.
+func (u *SubscriptionOrderUpsertOne) SetRefundedAt(v time.Time) *SubscriptionOrderUpsertOne {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.SetRefundedAt(v)
+	})
+}
+
+// UpdateRefundedAt sets the "refunded_at" field to the value that was provided on create.
+func (u *SubscriptionOrderUpsertOne) UpdateRefundedAt() *SubscriptionOrderUpsertOne {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.UpdateRefundedAt()
+	})
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (u *SubscriptionOrderUpsertOne) ClearRefundedAt() *SubscriptionOrderUpsertOne {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.ClearRefundedAt()
+	})
+}
+
 // SetNotes sets the "notes" field.
 func (u *SubscriptionOrderUpsertOne) SetNotes(v string) *SubscriptionOrderUpsertOne {
 	return u.Update(func(s *SubscriptionOrderUpsert) {
@@ -1537,6 +1729,27 @@ func (u *SubscriptionOrderUpsertBulk) UpdateGroupID() *SubscriptionOrderUpsertBu
 	})
 }
 
+// ClearGroupID clears the value of the "group_id" field.
+func (u *SubscriptionOrderUpsertBulk) ClearGroupID() *SubscriptionOrderUpsertBulk {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.ClearGroupID()
+	})
+}
+
+// SetOrderType sets the "order_type" field.
+func (u *SubscriptionOrderUpsertBulk) SetOrderType(v string) *SubscriptionOrderUpsertBulk {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.SetOrderType(v)
+	})
+}
+
+// UpdateOrderType sets the "order_type" field to the value that was provided on create.
+func (u *SubscriptionOrderUpsertBulk) UpdateOrderType() *SubscriptionOrderUpsertBulk {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.UpdateOrderType()
+	})
+}
+
 // SetSubscriptionID sets the "subscription_id" field.
 func (u *SubscriptionOrderUpsertBulk) SetSubscriptionID(v int64) *SubscriptionOrderUpsertBulk {
 	return u.Update(func(s *SubscriptionOrderUpsert) {
@@ -1747,6 +1960,27 @@ func (u *SubscriptionOrderUpsertBulk) UpdateValidityDays() *SubscriptionOrderUps
 	})
 }
 
+// SetCreditAmount sets the "credit_amount" field.
+func (u *SubscriptionOrderUpsertBulk) SetCreditAmount(v float64) *SubscriptionOrderUpsertBulk {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.SetCreditAmount(v)
+	})
+}
+
+// AddCreditAmount adds v to the "credit_amount" field.
+func (u *SubscriptionOrderUpsertBulk) AddCreditAmount(v float64) *SubscriptionOrderUpsertBulk {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.AddCreditAmount(v)
+	})
+}
+
+// UpdateCreditAmount sets the "credit_amount" field to the value that was provided on create.
+func (u *SubscriptionOrderUpsertBulk) UpdateCreditAmount() *SubscriptionOrderUpsertBulk {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.UpdateCreditAmount()
+	})
+}
+
 // SetPaidAt sets the "paid_at" field.
 func (u *SubscriptionOrderUpsertBulk) SetPaidAt(v time.Time) *SubscriptionOrderUpsertBulk {
 	return u.Update(func(s *SubscriptionOrderUpsert) {
@@ -1789,6 +2023,27 @@ func (u *SubscriptionOrderUpsertBulk) ClearCanceledAt() *SubscriptionOrderUpsert
 	})
 }
 
+// SetRefundedAt sets the "refunded_at" field.
+func (u *SubscriptionOrderUpsertBulk) SetRefundedAt(v time.Time) *SubscriptionOrderUpsertBulk {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.SetRefundedAt(v)
+	})
+}
+
+// UpdateRefundedAt sets the "refunded_at" field to the value that was provided on create.
+func (u *SubscriptionOrderUpsertBulk) UpdateRefundedAt() *SubscriptionOrderUpsertBulk {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.UpdateRefundedAt()
+	})
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (u *SubscriptionOrderUpsertBulk) ClearRefundedAt() *SubscriptionOrderUpsertBulk {
+	return u.Update(func(s *SubscriptionOrderUpsert) {
+		s.ClearRefundedAt()
+	})
+}
+
 // SetNotes sets the "notes" field.
 func (u *SubscriptionOrderUpsertBulk) SetNotes(v string) *SubscriptionOrderUpsertBulk {
 	return u.Update(func(s *SubscriptionOrderUpsert) {
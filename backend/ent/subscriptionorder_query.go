@@ -525,7 +525,10 @@ func (_q *SubscriptionOrderQuery) loadGroup(ctx context.Context, query *GroupQue
 	ids := make([]int64, 0, len(nodes))
 	nodeids := make(map[int64][]*SubscriptionOrder)
 	for i := range nodes {
-		fk := nodes[i].GroupID
+		if nodes[i].GroupID == nil {
+			continue
+		}
+		fk := *nodes[i].GroupID
 		if _, ok := nodeids[fk]; !ok {
 			ids = append(ids, fk)
 		}
@@ -79,6 +79,26 @@ func (_u *SubscriptionOrderUpdate) SetNillableGroupID(v *int64) *SubscriptionOrd
 	return _u
 }
 
+// ClearGroupID clears the value of the "group_id" field.
+func (_u *SubscriptionOrderUpdate) ClearGroupID() *SubscriptionOrderUpdate {
+	_u.mutation.ClearGroupID()
+	return _u
+}
+
+// SetOrderType sets the "order_type" field.
+func (_u *SubscriptionOrderUpdate) SetOrderType(v string) *SubscriptionOrderUpdate {
+	_u.mutation.SetOrderType(v)
+	return _u
+}
+
+// SetNillableOrderType sets the "order_type" field if the given value is not nil.
+func (_u *SubscriptionOrderUpdate) SetNillableOrderType(v *string) *SubscriptionOrderUpdate {
+	if v != nil {
+		_u.SetOrderType(*v)
+	}
+	return _u
+}
+
 // SetSubscriptionID sets the "subscription_id" field.
 func (_u *SubscriptionOrderUpdate) SetSubscriptionID(v int64) *SubscriptionOrderUpdate {
 	_u.mutation.SetSubscriptionID(v)
@@ -283,6 +303,27 @@ func (_u *SubscriptionOrderUpdate) AddValidityDays(v int) *SubscriptionOrderUpda
 	return _u
 }
 
+// SetCreditAmount sets the "credit_amount" field.
+func (_u *SubscriptionOrderUpdate) SetCreditAmount(v float64) *SubscriptionOrderUpdate {
+	_u.mutation.ResetCreditAmount()
+	_u.mutation.SetCreditAmount(v)
+	return _u
+}
+
+// SetNillableCreditAmount sets the "credit_amount" field if the given value is not nil.
+func (_u *SubscriptionOrderUpdate) SetNillableCreditAmount(v *float64) *SubscriptionOrderUpdate {
+	if v != nil {
+		_u.SetCreditAmount(*v)
+	}
+	return _u
+}
+
+// AddCreditAmount adds value to the "credit_amount" field.
+func (_u *SubscriptionOrderUpdate) AddCreditAmount(v float64) *SubscriptionOrderUpdate {
+	_u.mutation.AddCreditAmount(v)
+	return _u
+}
+
 // SetPaidAt sets the "paid_at" field.
 func (_u *SubscriptionOrderUpdate) SetPaidAt(v time.Time) *SubscriptionOrderUpdate {
 	_u.mutation.SetPaidAt(v)
@@ -323,6 +364,26 @@ func (_u *SubscriptionOrderUpdate) ClearCanceledAt() *SubscriptionOrderUpdate {
 	return _u
 }
 
+// SetRefundedAt sets the "refunded_at" field.
+func (_u *SubscriptionOrderUpdate) SetRefundedAt(v time.Time) *SubscriptionOrderUpdate {
+	_u.mutation.SetRefundedAt(v)
+	return _u
+}
+
+// SetNillableRefundedAt sets the "refunded_at" field if the given value is not nil.
+func (_u *SubscriptionOrderUpdate) SetNillableRefundedAt(v *time.Time) *SubscriptionOrderUpdate {
+	if v != nil {
+		_u.SetRefundedAt(*v)
+	}
+	return _u
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (_u *SubscriptionOrderUpdate) ClearRefundedAt() *SubscriptionOrderUpdate {
+	_u.mutation.ClearRefundedAt()
+	return _u
+}
+
 // SetNotes sets the "notes" field.
 func (_u *SubscriptionOrderUpdate) SetNotes(v string) *SubscriptionOrderUpdate {
 	_u.mutation.SetNotes(v)
@@ -424,6 +485,11 @@ func (_u *SubscriptionOrderUpdate) check() error {
 			return &ValidationError{Name: "order_no", err: fmt.Errorf(`ent: validator failed for field "SubscriptionOrder.order_no": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.OrderType(); ok {
+		if err := subscriptionorder.OrderTypeValidator(v); err != nil {
+			return &ValidationError{Name: "order_type", err: fmt.Errorf(`ent: validator failed for field "SubscriptionOrder.order_type": %w`, err)}
+		}
+	}
 	if v, ok := _u.mutation.PaymentProvider(); ok {
 		if err := subscriptionorder.PaymentProviderValidator(v); err != nil {
 			return &ValidationError{Name: "payment_provider", err: fmt.Errorf(`ent: validator failed for field "SubscriptionOrder.payment_provider": %w`, err)}
@@ -457,9 +523,6 @@ func (_u *SubscriptionOrderUpdate) check() error {
 	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "SubscriptionOrder.user"`)
 	}
-	if _u.mutation.GroupCleared() && len(_u.mutation.GroupIDs()) > 0 {
-		return errors.New(`ent: clearing a required unique edge "SubscriptionOrder.group"`)
-	}
 	return nil
 }
 
@@ -481,6 +544,9 @@ func (_u *SubscriptionOrderUpdate) sqlSave(ctx context.Context) (_node int, err
 	if value, ok := _u.mutation.OrderNo(); ok {
 		_spec.SetField(subscriptionorder.FieldOrderNo, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.OrderType(); ok {
+		_spec.SetField(subscriptionorder.FieldOrderType, field.TypeString, value)
+	}
 	if value, ok := _u.mutation.PaymentProvider(); ok {
 		_spec.SetField(subscriptionorder.FieldPaymentProvider, field.TypeString, value)
 	}
@@ -532,6 +598,12 @@ func (_u *SubscriptionOrderUpdate) sqlSave(ctx context.Context) (_node int, err
 	if value, ok := _u.mutation.AddedValidityDays(); ok {
 		_spec.AddField(subscriptionorder.FieldValidityDays, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.CreditAmount(); ok {
+		_spec.SetField(subscriptionorder.FieldCreditAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedCreditAmount(); ok {
+		_spec.AddField(subscriptionorder.FieldCreditAmount, field.TypeFloat64, value)
+	}
 	if value, ok := _u.mutation.PaidAt(); ok {
 		_spec.SetField(subscriptionorder.FieldPaidAt, field.TypeTime, value)
 	}
@@ -544,6 +616,12 @@ func (_u *SubscriptionOrderUpdate) sqlSave(ctx context.Context) (_node int, err
 	if _u.mutation.CanceledAtCleared() {
 		_spec.ClearField(subscriptionorder.FieldCanceledAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.RefundedAt(); ok {
+		_spec.SetField(subscriptionorder.FieldRefundedAt, field.TypeTime, value)
+	}
+	if _u.mutation.RefundedAtCleared() {
+		_spec.ClearField(subscriptionorder.FieldRefundedAt, field.TypeTime)
+	}
 	if value, ok := _u.mutation.Notes(); ok {
 		_spec.SetField(subscriptionorder.FieldNotes, field.TypeString, value)
 	}
@@ -705,6 +783,26 @@ func (_u *SubscriptionOrderUpdateOne) SetNillableGroupID(v *int64) *Subscription
 	return _u
 }
 
+// ClearGroupID clears the value of the "group_id" field.
+func (_u *SubscriptionOrderUpdateOne) ClearGroupID() *SubscriptionOrderUpdateOne {
+	_u.mutation.ClearGroupID()
+	return _u
+}
+
+// SetOrderType sets the "order_type" field.
+func (_u *SubscriptionOrderUpdateOne) SetOrderType(v string) *SubscriptionOrderUpdateOne {
+	_u.mutation.SetOrderType(v)
+	return _u
+}
+
+// SetNillableOrderType sets the "order_type" field if the given value is not nil.
+func (_u *SubscriptionOrderUpdateOne) SetNillableOrderType(v *string) *SubscriptionOrderUpdateOne {
+	if v != nil {
+		_u.SetOrderType(*v)
+	}
+	return _u
+}
+
 // SetSubscriptionID sets the "subscription_id" field.
 func (_u *SubscriptionOrderUpdateOne) SetSubscriptionID(v int64) *SubscriptionOrderUpdateOne {
 	_u.mutation.SetSubscriptionID(v)
@@ -909,6 +1007,27 @@ func (_u *SubscriptionOrderUpdateOne) AddValidityDays(v int) *SubscriptionOrderU
 	return _u
 }
 
+// SetCreditAmount sets the "credit_amount" field.
+func (_u *SubscriptionOrderUpdateOne) SetCreditAmount(v float64) *SubscriptionOrderUpdateOne {
+	_u.mutation.ResetCreditAmount()
+	_u.mutation.SetCreditAmount(v)
+	return _u
+}
+
+// SetNillableCreditAmount sets the "credit_amount" field if the given value is not nil.
+func (_u *SubscriptionOrderUpdateOne) SetNillableCreditAmount(v *float64) *SubscriptionOrderUpdateOne {
+	if v != nil {
+		_u.SetCreditAmount(*v)
+	}
+	return _u
+}
+
+// AddCreditAmount adds value to the "credit_amount" field.
+func (_u *SubscriptionOrderUpdateOne) AddCreditAmount(v float64) *SubscriptionOrderUpdateOne {
+	_u.mutation.AddCreditAmount(v)
+	return _u
+}
+
 // SetPaidAt sets the "paid_at" field.
 func (_u *SubscriptionOrderUpdateOne) SetPaidAt(v time.Time) *SubscriptionOrderUpdateOne {
 	_u.mutation.SetPaidAt(v)
@@ -949,6 +1068,26 @@ func (_u *SubscriptionOrderUpdateOne) ClearCanceledAt() *SubscriptionOrderUpdate
 	return _u
 }
 
+// SetRefundedAt sets the "refunded_at" field.
+func (_u *SubscriptionOrderUpdateOne) SetRefundedAt(v time.Time) *SubscriptionOrderUpdateOne {
+	_u.mutation.SetRefundedAt(v)
+	return _u
+}
+
+// SetNillableRefundedAt sets the "refunded_at" field if the given value is not nil.
+func (_u *SubscriptionOrderUpdateOne) SetNillableRefundedAt(v *time.Time) *SubscriptionOrderUpdateOne {
+	if v != nil {
+		_u.SetRefundedAt(*v)
+	}
+	return _u
+}
+
+// ClearRefundedAt clears the value of the "refunded_at" field.
+func (_u *SubscriptionOrderUpdateOne) ClearRefundedAt() *SubscriptionOrderUpdateOne {
+	_u.mutation.ClearRefundedAt()
+	return _u
+}
+
 // SetNotes sets the "notes" field.
 func (_u *SubscriptionOrderUpdateOne) SetNotes(v string) *SubscriptionOrderUpdateOne {
 	_u.mutation.SetNotes(v)
@@ -1063,6 +1202,11 @@ func (_u *SubscriptionOrderUpdateOne) check() error {
 			return &ValidationError{Name: "order_no", err: fmt.Errorf(`ent: validator failed for field "SubscriptionOrder.order_no": %w`, err)}
 		}
 	}
+	if v, ok := _u.mutation.OrderType(); ok {
+		if err := subscriptionorder.OrderTypeValidator(v); err != nil {
+			return &ValidationError{Name: "order_type", err: fmt.Errorf(`ent: validator failed for field "SubscriptionOrder.order_type": %w`, err)}
+		}
+	}
 	if v, ok := _u.mutation.PaymentProvider(); ok {
 		if err := subscriptionorder.PaymentProviderValidator(v); err != nil {
 			return &ValidationError{Name: "payment_provider", err: fmt.Errorf(`ent: validator failed for field "SubscriptionOrder.payment_provider": %w`, err)}
@@ -1096,9 +1240,6 @@ func (_u *SubscriptionOrderUpdateOne) check() error {
 	if _u.mutation.UserCleared() && len(_u.mutation.UserIDs()) > 0 {
 		return errors.New(`ent: clearing a required unique edge "SubscriptionOrder.user"`)
 	}
-	if _u.mutation.GroupCleared() && len(_u.mutation.GroupIDs()) > 0 {
-		return errors.New(`ent: clearing a required unique edge "SubscriptionOrder.group"`)
-	}
 	return nil
 }
 
@@ -1137,6 +1278,9 @@ func (_u *SubscriptionOrderUpdateOne) sqlSave(ctx context.Context) (_node *Subsc
 	if value, ok := _u.mutation.OrderNo(); ok {
 		_spec.SetField(subscriptionorder.FieldOrderNo, field.TypeString, value)
 	}
+	if value, ok := _u.mutation.OrderType(); ok {
+		_spec.SetField(subscriptionorder.FieldOrderType, field.TypeString, value)
+	}
 	if value, ok := _u.mutation.PaymentProvider(); ok {
 		_spec.SetField(subscriptionorder.FieldPaymentProvider, field.TypeString, value)
 	}
@@ -1188,6 +1332,12 @@ func (_u *SubscriptionOrderUpdateOne) sqlSave(ctx context.Context) (_node *Subsc
 	if value, ok := _u.mutation.AddedValidityDays(); ok {
 		_spec.AddField(subscriptionorder.FieldValidityDays, field.TypeInt, value)
 	}
+	if value, ok := _u.mutation.CreditAmount(); ok {
+		_spec.SetField(subscriptionorder.FieldCreditAmount, field.TypeFloat64, value)
+	}
+	if value, ok := _u.mutation.AddedCreditAmount(); ok {
+		_spec.AddField(subscriptionorder.FieldCreditAmount, field.TypeFloat64, value)
+	}
 	if value, ok := _u.mutation.PaidAt(); ok {
 		_spec.SetField(subscriptionorder.FieldPaidAt, field.TypeTime, value)
 	}
@@ -1200,6 +1350,12 @@ func (_u *SubscriptionOrderUpdateOne) sqlSave(ctx context.Context) (_node *Subsc
 	if _u.mutation.CanceledAtCleared() {
 		_spec.ClearField(subscriptionorder.FieldCanceledAt, field.TypeTime)
 	}
+	if value, ok := _u.mutation.RefundedAt(); ok {
+		_spec.SetField(subscriptionorder.FieldRefundedAt, field.TypeTime, value)
+	}
+	if _u.mutation.RefundedAtCleared() {
+		_spec.ClearField(subscriptionorder.FieldRefundedAt, field.TypeTime)
+	}
 	if value, ok := _u.mutation.Notes(); ok {
 		_spec.SetField(subscriptionorder.FieldNotes, field.TypeString, value)
 	}
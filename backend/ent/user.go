@@ -77,11 +77,15 @@ type UserEdges struct {
 	AttributeValues []*UserAttributeValue `json:"attribute_values,omitempty"`
 	// PromoCodeUsages holds the value of the promo_code_usages edge.
 	PromoCodeUsages []*PromoCodeUsage `json:"promo_code_usages,omitempty"`
+	// OwnedOrganizations holds the value of the owned_organizations edge.
+	OwnedOrganizations []*Organization `json:"owned_organizations,omitempty"`
+	// OrganizationMemberships holds the value of the organization_memberships edge.
+	OrganizationMemberships []*OrganizationMember `json:"organization_memberships,omitempty"`
 	// UserAllowedGroups holds the value of the user_allowed_groups edge.
 	UserAllowedGroups []*UserAllowedGroup `json:"user_allowed_groups,omitempty"`
 	// loadedTypes holds the information for reporting if a
 	// type was loaded (or requested) in eager-loading or not.
-	loadedTypes [11]bool
+	loadedTypes [13]bool
 }
 
 // APIKeysOrErr returns the APIKeys value or an error if the edge
@@ -174,10 +178,28 @@ func (e UserEdges) PromoCodeUsagesOrErr() ([]*PromoCodeUsage, error) {
 	return nil, &NotLoadedError{edge: "promo_code_usages"}
 }
 
+// OwnedOrganizationsOrErr returns the OwnedOrganizations value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) OwnedOrganizationsOrErr() ([]*Organization, error) {
+	if e.loadedTypes[10] {
+		return e.OwnedOrganizations, nil
+	}
+	return nil, &NotLoadedError{edge: "owned_organizations"}
+}
+
+// OrganizationMembershipsOrErr returns the OrganizationMemberships value or an error if the edge
+// was not loaded in eager-loading.
+func (e UserEdges) OrganizationMembershipsOrErr() ([]*OrganizationMember, error) {
+	if e.loadedTypes[11] {
+		return e.OrganizationMemberships, nil
+	}
+	return nil, &NotLoadedError{edge: "organization_memberships"}
+}
+
 // UserAllowedGroupsOrErr returns the UserAllowedGroups value or an error if the edge
 // was not loaded in eager-loading.
 func (e UserEdges) UserAllowedGroupsOrErr() ([]*UserAllowedGroup, error) {
-	if e.loadedTypes[10] {
+	if e.loadedTypes[12] {
 		return e.UserAllowedGroups, nil
 	}
 	return nil, &NotLoadedError{edge: "user_allowed_groups"}
@@ -381,6 +403,16 @@ func (_m *User) QueryPromoCodeUsages() *PromoCodeUsageQuery {
 	return NewUserClient(_m.config).QueryPromoCodeUsages(_m)
 }
 
+// QueryOwnedOrganizations queries the "owned_organizations" edge of the User entity.
+func (_m *User) QueryOwnedOrganizations() *OrganizationQuery {
+	return NewUserClient(_m.config).QueryOwnedOrganizations(_m)
+}
+
+// QueryOrganizationMemberships queries the "organization_memberships" edge of the User entity.
+func (_m *User) QueryOrganizationMemberships() *OrganizationMemberQuery {
+	return NewUserClient(_m.config).QueryOrganizationMemberships(_m)
+}
+
 // QueryUserAllowedGroups queries the "user_allowed_groups" edge of the User entity.
 func (_m *User) QueryUserAllowedGroups() *UserAllowedGroupQuery {
 	return NewUserClient(_m.config).QueryUserAllowedGroups(_m)
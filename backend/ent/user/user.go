@@ -67,6 +67,10 @@ const (
 	EdgeAttributeValues = "attribute_values"
 	// EdgePromoCodeUsages holds the string denoting the promo_code_usages edge name in mutations.
 	EdgePromoCodeUsages = "promo_code_usages"
+	// EdgeOwnedOrganizations holds the string denoting the owned_organizations edge name in mutations.
+	EdgeOwnedOrganizations = "owned_organizations"
+	// EdgeOrganizationMemberships holds the string denoting the organization_memberships edge name in mutations.
+	EdgeOrganizationMemberships = "organization_memberships"
 	// EdgeUserAllowedGroups holds the string denoting the user_allowed_groups edge name in mutations.
 	EdgeUserAllowedGroups = "user_allowed_groups"
 	// Table holds the table name of the user in the database.
@@ -139,6 +143,20 @@ const (
 	PromoCodeUsagesInverseTable = "promo_code_usages"
 	// PromoCodeUsagesColumn is the table column denoting the promo_code_usages relation/edge.
 	PromoCodeUsagesColumn = "user_id"
+	// OwnedOrganizationsTable is the table that holds the owned_organizations relation/edge.
+	OwnedOrganizationsTable = "organizations"
+	// OwnedOrganizationsInverseTable is the table name for the Organization entity.
+	// It exists in this package in order to avoid circular dependency with the "organization" package.
+	OwnedOrganizationsInverseTable = "organizations"
+	// OwnedOrganizationsColumn is the table column denoting the owned_organizations relation/edge.
+	OwnedOrganizationsColumn = "owner_user_id"
+	// OrganizationMembershipsTable is the table that holds the organization_memberships relation/edge.
+	OrganizationMembershipsTable = "organization_members"
+	// OrganizationMembershipsInverseTable is the table name for the OrganizationMember entity.
+	// It exists in this package in order to avoid circular dependency with the "organizationmember" package.
+	OrganizationMembershipsInverseTable = "organization_members"
+	// OrganizationMembershipsColumn is the table column denoting the organization_memberships relation/edge.
+	OrganizationMembershipsColumn = "user_id"
 	// UserAllowedGroupsTable is the table that holds the user_allowed_groups relation/edge.
 	UserAllowedGroupsTable = "user_allowed_groups"
 	// UserAllowedGroupsInverseTable is the table name for the UserAllowedGroup entity.
@@ -457,6 +475,34 @@ func ByPromoCodeUsages(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
 	}
 }
 
+// ByOwnedOrganizationsCount orders the results by owned_organizations count.
+func ByOwnedOrganizationsCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newOwnedOrganizationsStep(), opts...)
+	}
+}
+
+// ByOwnedOrganizations orders the results by owned_organizations terms.
+func ByOwnedOrganizations(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newOwnedOrganizationsStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
+// ByOrganizationMembershipsCount orders the results by organization_memberships count.
+func ByOrganizationMembershipsCount(opts ...sql.OrderTermOption) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborsCount(s, newOrganizationMembershipsStep(), opts...)
+	}
+}
+
+// ByOrganizationMemberships orders the results by organization_memberships terms.
+func ByOrganizationMemberships(term sql.OrderTerm, terms ...sql.OrderTerm) OrderOption {
+	return func(s *sql.Selector) {
+		sqlgraph.OrderByNeighborTerms(s, newOrganizationMembershipsStep(), append([]sql.OrderTerm{term}, terms...)...)
+	}
+}
+
 // ByUserAllowedGroupsCount orders the results by user_allowed_groups count.
 func ByUserAllowedGroupsCount(opts ...sql.OrderTermOption) OrderOption {
 	return func(s *sql.Selector) {
@@ -540,6 +586,20 @@ func newPromoCodeUsagesStep() *sqlgraph.Step {
 		sqlgraph.Edge(sqlgraph.O2M, false, PromoCodeUsagesTable, PromoCodeUsagesColumn),
 	)
 }
+func newOwnedOrganizationsStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(OwnedOrganizationsInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, false, OwnedOrganizationsTable, OwnedOrganizationsColumn),
+	)
+}
+func newOrganizationMembershipsStep() *sqlgraph.Step {
+	return sqlgraph.NewStep(
+		sqlgraph.From(Table, FieldID),
+		sqlgraph.To(OrganizationMembershipsInverseTable, FieldID),
+		sqlgraph.Edge(sqlgraph.O2M, false, OrganizationMembershipsTable, OrganizationMembershipsColumn),
+	)
+}
 func newUserAllowedGroupsStep() *sqlgraph.Step {
 	return sqlgraph.NewStep(
 		sqlgraph.From(Table, FieldID),
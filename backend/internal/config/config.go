@@ -62,9 +62,13 @@ type Config struct {
 	Database                DatabaseConfig                `mapstructure:"database"`
 	Redis                   RedisConfig                   `mapstructure:"redis"`
 	Ops                     OpsConfig                     `mapstructure:"ops"`
+	Metrics                 MetricsConfig                 `mapstructure:"metrics"`
+	Tracing                 TracingConfig                 `mapstructure:"tracing"`
 	JWT                     JWTConfig                     `mapstructure:"jwt"`
 	Totp                    TotpConfig                    `mapstructure:"totp"`
+	CredentialEncryption    CredentialEncryptionConfig    `mapstructure:"credential_encryption"`
 	LinuxDo                 LinuxDoConnectConfig          `mapstructure:"linuxdo_connect"`
+	SSO                     SSOConfig                     `mapstructure:"sso"`
 	Default                 DefaultConfig                 `mapstructure:"default"`
 	RateLimit               RateLimitConfig               `mapstructure:"rate_limit"`
 	Pricing                 PricingConfig                 `mapstructure:"pricing"`
@@ -75,6 +79,15 @@ type Config struct {
 	Dashboard               DashboardCacheConfig          `mapstructure:"dashboard_cache"`
 	DashboardAgg            DashboardAggregationConfig    `mapstructure:"dashboard_aggregation"`
 	UsageCleanup            UsageCleanupConfig            `mapstructure:"usage_cleanup"`
+	UsageExport             UsageExportConfig             `mapstructure:"usage_export"`
+	AdminAudit              AdminAuditConfig              `mapstructure:"admin_audit"`
+	Statement               StatementConfig               `mapstructure:"statement"`
+	ProxyPool               ProxyPoolConfig               `mapstructure:"proxy_pool"`
+	Webhook                 WebhookConfig                 `mapstructure:"webhook"`
+	Notification            NotificationConfig            `mapstructure:"notification"`
+	ResponseCache           ResponseCacheConfig           `mapstructure:"response_cache"`
+	MessageBatch            MessageBatchConfig            `mapstructure:"message_batch"`
+	OpenAIBatch             OpenAIBatchConfig             `mapstructure:"openai_batch"`
 	Concurrency             ConcurrencyConfig             `mapstructure:"concurrency"`
 	TokenRefresh            TokenRefreshConfig            `mapstructure:"token_refresh"`
 	Sora                    SoraConfig                    `mapstructure:"sora"`
@@ -185,6 +198,71 @@ type LinuxDoConnectConfig struct {
 	UserInfoUsernamePath string `mapstructure:"userinfo_username_path"`
 }
 
+// 使用记录导出文件存储位置
+const (
+	UsageExportStorageLocal = "local"
+	UsageExportStorageS3    = "s3"
+)
+
+// OpenAI Files 文件存储位置
+const (
+	OpenAIFileStorageLocal = "local"
+	OpenAIFileStorageS3    = "s3"
+)
+
+// SSO 提供方类型
+const (
+	SSOProviderTypeOIDC   = "oidc"
+	SSOProviderTypeGitHub = "github"
+	SSOProviderTypeGoogle = "google"
+)
+
+// SSOConfig 第三方单点登录配置（与 LinuxDo Connect 并存），可同时启用多个提供方。
+type SSOConfig struct {
+	Providers []SSOProviderConfig `mapstructure:"providers"`
+}
+
+// SSOProviderConfig 单个 SSO 提供方配置。
+// type=oidc 通过 issuer 的 discovery 文档获取端点并校验 ID Token；
+// type=google 为 issuer 固定为 Google 的 OIDC 预设；type=github 为 GitHub OAuth App 预设。
+type SSOProviderConfig struct {
+	ID           string `mapstructure:"id"`           // 路由标识（小写字母/数字/-/_），如 okta
+	Type         string `mapstructure:"type"`         // oidc / github / google
+	DisplayName  string `mapstructure:"display_name"` // 登录按钮显示名称
+	Enabled      bool   `mapstructure:"enabled"`
+	ClientID     string `mapstructure:"client_id"`
+	ClientSecret string `mapstructure:"client_secret"` // oidc 公共客户端可留空（仅 PKCE）
+	Issuer       string `mapstructure:"issuer"`
+	Scopes       string `mapstructure:"scopes"` // 空格分隔；为空时按类型使用默认值
+	// TokenAuthMethod: client_secret_basic / client_secret_post / none。
+	// 为空时：配置了 client_secret 则使用 client_secret_basic（github 使用 client_secret_post），否则 none。
+	TokenAuthMethod string `mapstructure:"token_auth_method"`
+
+	// 可选：覆盖端点地址。oidc 为空时使用 discovery 结果；github 为空时使用官方地址。
+	AuthorizeURL string `mapstructure:"authorize_url"`
+	TokenURL     string `mapstructure:"token_url"`
+	UserInfoURL  string `mapstructure:"userinfo_url"`
+	JWKSURL      string `mapstructure:"jwks_url"`
+	// APIBaseURL 仅 github 使用（查询邮箱/组织），默认 https://api.github.com
+	APIBaseURL string `mapstructure:"api_base_url"`
+
+	RedirectURL         string `mapstructure:"redirect_url"`          // 后端回调地址：.../api/v1/auth/sso/<id>/callback
+	FrontendRedirectURL string `mapstructure:"frontend_redirect_url"` // 前端接收 token 的路由（默认：/auth/sso/callback）
+
+	// 声明映射（gjson 路径，作用于 ID Token 与 userinfo 合并后的声明）。
+	EmailClaim    string `mapstructure:"email_claim"`    // 默认 email
+	UsernameClaim string `mapstructure:"username_claim"` // 默认 preferred_username，其次 name
+	GroupsClaim   string `mapstructure:"groups_claim"`   // 默认 groups；github 使用所属组织
+
+	// AllowedDomains 非空时仅允许这些邮箱域名（需已验证邮箱）登录。
+	AllowedDomains []string `mapstructure:"allowed_domains"`
+	// LinkExistingUsers 为 true 时，已验证邮箱与本地用户邮箱一致则自动绑定到该用户。
+	// 为 false 时需要用户登录后在个人资料页手动绑定。
+	LinkExistingUsers bool `mapstructure:"link_existing_users"`
+	// GroupMappings 将提供方的组（不区分大小写）映射为 sub2api 分组 ID，登录时加入用户的可用专属分组。
+	GroupMappings map[string][]int64 `mapstructure:"group_mappings"`
+}
+
 // TokenRefreshConfig OAuth token自动刷新配置
 type TokenRefreshConfig struct {
 	// 是否启用自动刷新
@@ -249,6 +327,9 @@ type SecurityConfig struct {
 	CSP             CSPConfig            `mapstructure:"csp"`
 	ProxyFallback   ProxyFallbackConfig  `mapstructure:"proxy_fallback"`
 	ProxyProbe      ProxyProbeConfig     `mapstructure:"proxy_probe"`
+	// APIKeyHashSecret 计算 API Key 存储哈希（HMAC-SHA256）的密钥。
+	// 为空时启动阶段自动生成并持久化到数据库；一旦投入使用不可更改，否则所有 Key 失效。
+	APIKeyHashSecret string `mapstructure:"api_key_hash_secret"`
 }
 
 type URLAllowlistConfig struct {
@@ -307,6 +388,30 @@ type ConcurrencyConfig struct {
 type SoraConfig struct {
 	Client  SoraClientConfig  `mapstructure:"client"`
 	Storage SoraStorageConfig `mapstructure:"storage"`
+	Jobs    SoraJobsConfig    `mapstructure:"jobs"`
+}
+
+// SoraJobsConfig Sora 客户端生成任务的持久化队列配置
+type SoraJobsConfig struct {
+	// Workers: 每个实例并发执行的任务数
+	Workers int `mapstructure:"workers"`
+	// PollIntervalSeconds: 空闲时拉取新任务的间隔（秒）
+	PollIntervalSeconds int `mapstructure:"poll_interval_seconds"`
+	// LeaseSeconds: 任务租约时长（秒），心跳按 1/3 租约间隔续期；实例崩溃后租约过期即可被其他实例接管
+	LeaseSeconds int `mapstructure:"lease_seconds"`
+	// TaskTimeoutSeconds: 单次执行超时（秒）
+	TaskTimeoutSeconds int `mapstructure:"task_timeout_seconds"`
+	// MaxAttempts: 最大执行次数（含首次），超过后标记失败并退还配额
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryBackoffBaseSeconds / RetryBackoffMaxSeconds: 指数退避的基数与上限（秒）
+	RetryBackoffBaseSeconds int `mapstructure:"retry_backoff_base_seconds"`
+	RetryBackoffMaxSeconds  int `mapstructure:"retry_backoff_max_seconds"`
+	// ReapIntervalSeconds: 清理僵死任务的间隔（秒）
+	ReapIntervalSeconds int `mapstructure:"reap_interval_seconds"`
+	// OrphanTimeoutMinutes: 没有队列任务却仍处于 pending/generating 的记录超过该时长后标记失败
+	OrphanTimeoutMinutes int `mapstructure:"orphan_timeout_minutes"`
+	// RetentionDays: 已结束任务保留天数
+	RetentionDays int `mapstructure:"retention_days"`
 }
 
 // SoraClientConfig 直连 Sora 客户端配置
@@ -453,6 +558,9 @@ type GatewayConfig struct {
 	// Scheduling: 账号调度相关配置
 	Scheduling GatewaySchedulingConfig `mapstructure:"scheduling"`
 
+	// DigestSession: 内容摘要会话（Gemini/Anthropic Fallback 粘性）存储配置
+	DigestSession GatewayDigestSessionConfig `mapstructure:"digest_session"`
+
 	// TLSFingerprint: TLS指纹伪装配置
 	TLSFingerprint TLSFingerprintConfig `mapstructure:"tls_fingerprint"`
 
@@ -669,6 +777,15 @@ type TLSProfileConfig struct {
 	PointFormats []uint8 `mapstructure:"point_formats"`
 }
 
+// GatewayDigestSessionConfig 摘要会话存储配置。
+// 多副本部署时应使用 redis，否则后续请求落到其他副本会丢失会话到账号的粘性。
+type GatewayDigestSessionConfig struct {
+	// Store: memory（进程内，默认）或 redis
+	Store string `mapstructure:"store"`
+	// TTLSeconds: 会话条目过期时间（秒）
+	TTLSeconds int `mapstructure:"ttl_seconds"`
+}
+
 // GatewaySchedulingConfig accounts scheduling configuration.
 type GatewaySchedulingConfig struct {
 	// 粘性会话排队配置
@@ -710,6 +827,30 @@ type GatewaySchedulingConfig struct {
 	// 全量重建周期配置
 	// 全量重建周期（秒），0 表示禁用
 	FullRebuildIntervalSeconds int `mapstructure:"full_rebuild_interval_seconds"`
+
+	// 运行时健康评分（Anthropic / Gemini / Antigravity 负载感知层，与 OpenAI 调度器共享统计）
+	HealthScore GatewaySchedulingHealthScoreConfig `mapstructure:"health_score"`
+}
+
+// GatewaySchedulingHealthScoreConfig 账号运行时健康评分配置。
+// 同一优先级内按加权得分排序；priority 仍是硬分层，不参与加权。
+type GatewaySchedulingHealthScoreConfig struct {
+	// Enabled: 关闭时负载感知层退回 负载率 -> LRU 排序
+	Enabled bool `mapstructure:"enabled"`
+	// DecayHalfLifeSeconds: 429/529 密度与 token 刷新失败计数的衰减半衰期（秒）
+	DecayHalfLifeSeconds int `mapstructure:"decay_half_life_seconds"`
+	// Weights: 各因子权重（非负，不可全为 0）
+	Weights GatewaySchedulingHealthScoreWeights `mapstructure:"weights"`
+}
+
+// GatewaySchedulingHealthScoreWeights 健康评分各因子权重。
+type GatewaySchedulingHealthScoreWeights struct {
+	Load         float64 `mapstructure:"load"`
+	Queue        float64 `mapstructure:"queue"`
+	ErrorRate    float64 `mapstructure:"error_rate"`
+	TTFT         float64 `mapstructure:"ttft"`
+	Overload     float64 `mapstructure:"overload"`
+	TokenRefresh float64 `mapstructure:"token_refresh"`
 }
 
 func (s *ServerConfig) Address() string {
@@ -814,6 +955,40 @@ type OpsConfig struct {
 	Aggregation OpsAggregationConfig `mapstructure:"aggregation"`
 }
 
+// MetricsConfig controls the Prometheus scrape endpoint.
+type MetricsConfig struct {
+	// Enabled exposes the endpoint; off by default.
+	Enabled bool `mapstructure:"enabled"`
+	// Path is the scrape path (default /metrics).
+	Path string `mapstructure:"path"`
+	// Token must be presented as "Authorization: Bearer <token>". Required when enabled.
+	Token string `mapstructure:"token"`
+	// AccountLabel adds an account_id label to per-request series. Disable it on
+	// deployments with many accounts to bound series cardinality.
+	AccountLabel bool `mapstructure:"account_label"`
+}
+
+// TracingConfig controls OpenTelemetry (OTLP) distributed tracing.
+type TracingConfig struct {
+	// Enabled turns on span export; off by default (spans become no-ops).
+	Enabled bool `mapstructure:"enabled"`
+	// Protocol is the OTLP transport: "grpc" or "http".
+	Protocol string `mapstructure:"protocol"`
+	// Endpoint is the collector address, e.g. "localhost:4317" (grpc) or "localhost:4318" (http).
+	Endpoint string `mapstructure:"endpoint"`
+	// URLPath overrides the OTLP/HTTP traces path (default /v1/traces).
+	URLPath string `mapstructure:"url_path"`
+	// Insecure disables TLS towards the collector.
+	Insecure bool `mapstructure:"insecure"`
+	// Headers are sent with every export request (e.g. vendor API keys).
+	Headers map[string]string `mapstructure:"headers"`
+	// ServiceName is reported as the service.name resource attribute.
+	ServiceName string `mapstructure:"service_name"`
+	// SampleRatio is the head sampling ratio for new root traces (0-1).
+	// Requests carrying a sampled W3C traceparent are always recorded.
+	SampleRatio float64 `mapstructure:"sample_ratio"`
+}
+
 type OpsCleanupConfig struct {
 	Enabled  bool   `mapstructure:"enabled"`
 	Schedule string `mapstructure:"schedule"`
@@ -858,6 +1033,58 @@ type TotpConfig struct {
 	EncryptionKeyConfigured bool `mapstructure:"-"`
 }
 
+// CredentialEncryptionConfig 上游账号凭证静态加密配置
+type CredentialEncryptionConfig struct {
+	// Enabled 启用后新写入的敏感凭证字段（access_token、refresh_token、api_key 等）加密存储；
+	// 关闭时仍可解密已加密的数据
+	Enabled bool `mapstructure:"enabled"`
+	// Keys 密钥环，格式 "id:hex,id:hex"（每个密钥 32 字节 hex 编码），便于通过环境变量注入；
+	// 为空时使用 security_secrets 表中自动生成的密钥
+	Keys string `mapstructure:"keys"`
+	// ActiveKeyID 用于加密新数据的密钥 ID；为空时使用数据库中记录的当前密钥
+	ActiveKeyID string `mapstructure:"active_key_id"`
+}
+
+// CredentialEncryptionKeys 解析密钥环配置，返回 keyID -> 密钥
+func (c CredentialEncryptionConfig) CredentialEncryptionKeys() (map[string][]byte, error) {
+	keys := make(map[string][]byte)
+	for _, item := range strings.Split(c.Keys, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+		id, encoded, ok := strings.Cut(item, ":")
+		id = strings.TrimSpace(id)
+		if !ok || !IsValidCredentialKeyID(id) {
+			return nil, fmt.Errorf("credential_encryption.keys: invalid key id in %q", id)
+		}
+		key, err := hex.DecodeString(strings.TrimSpace(encoded))
+		if err != nil || len(key) != 32 {
+			return nil, fmt.Errorf("credential_encryption.keys: key %q must be 32 bytes (64 hex chars)", id)
+		}
+		if _, exists := keys[id]; exists {
+			return nil, fmt.Errorf("credential_encryption.keys: duplicate key id %q", id)
+		}
+		keys[id] = key
+	}
+	return keys, nil
+}
+
+// IsValidCredentialKeyID 密钥 ID 仅允许字母、数字、下划线与短横线（不超过 32 个字符）
+func IsValidCredentialKeyID(id string) bool {
+	if id == "" || len(id) > 32 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z', r >= 'A' && r <= 'Z', r >= '0' && r <= '9', r == '_', r == '-':
+		default:
+			return false
+		}
+	}
+	return true
+}
+
 type TurnstileConfig struct {
 	Required bool `mapstructure:"required"`
 }
@@ -953,6 +1180,172 @@ type UsageCleanupConfig struct {
 	TaskTimeoutSeconds int `mapstructure:"task_timeout_seconds"`
 }
 
+// UsageExportConfig 使用记录导出配置
+type UsageExportConfig struct {
+	// Enabled: 是否启用导出（同步流式导出与异步导出任务）
+	Enabled bool `mapstructure:"enabled"`
+	// Storage: 异步导出文件存储位置（local/s3，s3 使用系统设置中激活的 S3 配置）
+	Storage string `mapstructure:"storage"`
+	// LocalDir: 本地存储目录
+	LocalDir string `mapstructure:"local_dir"`
+	// MaxSyncRangeDays: 同步流式导出允许的最大时间跨度（天），更大范围需创建异步任务
+	MaxSyncRangeDays int `mapstructure:"max_sync_range_days"`
+	// MaxRangeDays: 异步导出任务允许的最大时间跨度（天）
+	MaxRangeDays int `mapstructure:"max_range_days"`
+	// BatchSize: 单批读取记录数
+	BatchSize int `mapstructure:"batch_size"`
+	// WorkerIntervalSeconds: 后台任务轮询间隔（秒）
+	WorkerIntervalSeconds int `mapstructure:"worker_interval_seconds"`
+	// TaskTimeoutSeconds: 单次任务最大执行时长（秒）
+	TaskTimeoutSeconds int `mapstructure:"task_timeout_seconds"`
+	// LinkTTLSeconds: 下载链接有效期（秒）
+	LinkTTLSeconds int `mapstructure:"link_ttl_seconds"`
+	// RetentionHours: 导出文件保留时长（小时），到期后删除文件
+	RetentionHours int `mapstructure:"retention_hours"`
+}
+
+// AdminAuditConfig 管理员操作审计日志配置
+type AdminAuditConfig struct {
+	// Enabled: 是否记录管理员写操作审计日志
+	Enabled bool `mapstructure:"enabled"`
+	// RetentionDays: 审计日志保留天数（0 表示永久保留）
+	RetentionDays int `mapstructure:"retention_days"`
+	// CleanupSchedule: 过期日志清理的 cron 表达式（5 段）
+	CleanupSchedule string `mapstructure:"cleanup_schedule"`
+}
+
+// StatementConfig 月度账单配置
+type StatementConfig struct {
+	// Enabled: 是否自动生成月度账单
+	Enabled bool `mapstructure:"enabled"`
+	// CloseSchedule: 账期结算检查的 cron 表达式（5 段，按 timezone 配置）；
+	// 每次执行为上一自然月尚无账单的用户补齐账单，重复执行不会重复生成
+	CloseSchedule string `mapstructure:"close_schedule"`
+	// EmailEnabled: 生成账单后是否通过 SMTP 发送给用户
+	EmailEnabled bool `mapstructure:"email_enabled"`
+}
+
+// ProxyPoolConfig 代理池健康检查配置
+type ProxyPoolConfig struct {
+	// Enabled: 是否启用代理池（关闭后绑定代理池的账号按 proxy_id 或直连发送）
+	Enabled bool `mapstructure:"enabled"`
+	// CheckIntervalSeconds: 成员健康探测间隔（秒）
+	CheckIntervalSeconds int `mapstructure:"check_interval_seconds"`
+	// ProbeTimeoutSeconds: 单个代理探测超时（秒）
+	ProbeTimeoutSeconds int `mapstructure:"probe_timeout_seconds"`
+	// ProbeConcurrency: 同时探测的代理数量上限
+	ProbeConcurrency int `mapstructure:"probe_concurrency"`
+	// FailureThreshold: 连续探测失败多少次后标记为不健康（请求链路上的代理连接错误立即生效）
+	FailureThreshold int `mapstructure:"failure_threshold"`
+}
+
+// WebhookConfig 用户出站 Webhook 配置
+type WebhookConfig struct {
+	// Enabled: 是否启用用户 Webhook（关闭后不再产生事件与投递）
+	Enabled bool `mapstructure:"enabled"`
+	// MaxPerUser: 每个用户最多可创建的 Webhook 数量
+	MaxPerUser int `mapstructure:"max_per_user"`
+	// WorkerIntervalSeconds: 投递队列轮询间隔（秒）
+	WorkerIntervalSeconds int `mapstructure:"worker_interval_seconds"`
+	// BatchSize: 单次轮询最多投递的记录数
+	BatchSize int `mapstructure:"batch_size"`
+	// Concurrency: 单次轮询内并发投递数
+	Concurrency int `mapstructure:"concurrency"`
+	// RequestTimeoutSeconds: 单次投递 HTTP 超时（秒）
+	RequestTimeoutSeconds int `mapstructure:"request_timeout_seconds"`
+	// MaxAttempts: 单条投递最大尝试次数（含首次）
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryBackoffBaseSeconds / RetryBackoffMaxSeconds: 失败重试的指数退避基数与上限（秒）
+	RetryBackoffBaseSeconds int `mapstructure:"retry_backoff_base_seconds"`
+	RetryBackoffMaxSeconds  int `mapstructure:"retry_backoff_max_seconds"`
+	// EvaluateIntervalSeconds: 阈值类事件（余额不足、额度将尽、订阅到期）的检查间隔（秒）
+	EvaluateIntervalSeconds int `mapstructure:"evaluate_interval_seconds"`
+	// RetentionDays: 投递日志保留天数
+	RetentionDays int `mapstructure:"retention_days"`
+}
+
+// NotificationConfig 用户通知邮件（余额不足、额度将尽、订阅到期）配置
+type NotificationConfig struct {
+	// Enabled: 是否启用通知邮件（用户仍需在个人设置中开启）
+	Enabled bool `mapstructure:"enabled"`
+	// EvaluateIntervalSeconds: 阈值检查间隔（秒），检查在后台进行，不占用计费热路径
+	EvaluateIntervalSeconds int `mapstructure:"evaluate_interval_seconds"`
+	// MaxEmailsPerRun: 单轮检查最多入队的邮件数，超出部分留到下一轮
+	MaxEmailsPerRun int `mapstructure:"max_emails_per_run"`
+}
+
+// ResponseCacheConfig 精确响应缓存配置（分组需单独开启）
+type ResponseCacheConfig struct {
+	// Enabled: 全局开关，关闭后所有分组均不读写缓存
+	Enabled bool `mapstructure:"enabled"`
+	// DefaultTTLSeconds: 分组未配置 TTL 时的默认缓存时长（秒）
+	DefaultTTLSeconds int `mapstructure:"default_ttl_seconds"`
+	// MaxTTLSeconds: 分组 TTL 上限（秒）
+	MaxTTLSeconds int `mapstructure:"max_ttl_seconds"`
+	// MaxEntryBytes: 单条缓存响应体的大小上限，超出则不缓存
+	MaxEntryBytes int `mapstructure:"max_entry_bytes"`
+}
+
+// MessageBatchConfig Anthropic Message Batches 兼容配置
+type MessageBatchConfig struct {
+	// Enabled: 是否开放 /v1/messages/batches
+	Enabled bool `mapstructure:"enabled"`
+	// MaxRequestsPerBatch: 单个批次最多包含的请求数
+	MaxRequestsPerBatch int `mapstructure:"max_requests_per_batch"`
+	// WorkerIntervalSeconds: 执行队列轮询间隔（秒）
+	WorkerIntervalSeconds int `mapstructure:"worker_interval_seconds"`
+	// ClaimSize: 单次轮询最多领取的请求数
+	ClaimSize int `mapstructure:"claim_size"`
+	// Concurrency: 单次轮询内并发执行数
+	Concurrency int `mapstructure:"concurrency"`
+	// MaxAccountLoadPercent: 低优先级阈值，仅当账号其余请求的负载不超过该百分比且无排队时才执行
+	MaxAccountLoadPercent int `mapstructure:"max_account_load_percent"`
+	// RequestTimeoutSeconds: 单个请求的上游超时（秒）
+	RequestTimeoutSeconds int `mapstructure:"request_timeout_seconds"`
+	// MaxAttempts: 单个请求因上游错误失败的最大次数，超出后记为 errored
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryDelaySeconds: 账号繁忙或上游失败后的重新调度间隔（秒）
+	RetryDelaySeconds int `mapstructure:"retry_delay_seconds"`
+	// ExpireHours: 批次创建后多久未完成的请求记为 expired
+	ExpireHours int `mapstructure:"expire_hours"`
+	// RetentionDays: 已结束批次及结果的保留天数
+	RetentionDays int `mapstructure:"retention_days"`
+	// PriceMultiplier: 批处理计费折扣，叠加在分组/用户费率倍数之上（1 = 不打折）
+	PriceMultiplier float64 `mapstructure:"price_multiplier"`
+}
+
+// OpenAIBatchConfig OpenAI Files 与 Batch API 配置
+type OpenAIBatchConfig struct {
+	// Enabled: 是否为 OpenAI 分组开放 /v1/files 与 /v1/batches
+	Enabled bool `mapstructure:"enabled"`
+	// Storage: 文件存储位置（local/s3，s3 使用系统设置中激活的 S3 配置）
+	Storage string `mapstructure:"storage"`
+	// LocalDir: 本地存储目录（s3 模式下也用作生成结果文件的临时目录）
+	LocalDir string `mapstructure:"local_dir"`
+	// MaxFileSizeMB: 单个上传文件的最大大小（MB）
+	MaxFileSizeMB int `mapstructure:"max_file_size_mb"`
+	// MaxRequestsPerBatch: 单个批次输入文件最多包含的请求行数
+	MaxRequestsPerBatch int `mapstructure:"max_requests_per_batch"`
+	// WorkerIntervalSeconds: 执行队列轮询间隔（秒）
+	WorkerIntervalSeconds int `mapstructure:"worker_interval_seconds"`
+	// ClaimSize: 单次轮询最多领取的请求数
+	ClaimSize int `mapstructure:"claim_size"`
+	// Concurrency: 单次轮询内并发执行数
+	Concurrency int `mapstructure:"concurrency"`
+	// MaxAccountLoadPercent: 低优先级阈值，仅当账号其余请求的负载不超过该百分比且无排队时才执行
+	MaxAccountLoadPercent int `mapstructure:"max_account_load_percent"`
+	// RequestTimeoutSeconds: 单个请求的上游超时（秒）
+	RequestTimeoutSeconds int `mapstructure:"request_timeout_seconds"`
+	// MaxAttempts: 单个请求因上游错误失败的最大次数，超出后写入错误文件
+	MaxAttempts int `mapstructure:"max_attempts"`
+	// RetryDelaySeconds: 账号繁忙或上游失败后的重新调度间隔（秒）
+	RetryDelaySeconds int `mapstructure:"retry_delay_seconds"`
+	// RetentionDays: 文件（上传与结果文件）及已结束批次的保留天数
+	RetentionDays int `mapstructure:"retention_days"`
+	// PriceMultiplier: 批处理计费折扣，叠加在分组/用户费率倍数之上（1 = 不打折）
+	PriceMultiplier float64 `mapstructure:"price_multiplier"`
+}
+
 func NormalizeRunMode(value string) string {
 	normalized := strings.ToLower(strings.TrimSpace(value))
 	switch normalized {
@@ -1019,6 +1412,7 @@ func load(allowMissingJWTSecret bool) (*Config, error) {
 	}
 	cfg.Server.FrontendURL = strings.TrimSpace(cfg.Server.FrontendURL)
 	cfg.JWT.Secret = strings.TrimSpace(cfg.JWT.Secret)
+	cfg.Security.APIKeyHashSecret = strings.TrimSpace(cfg.Security.APIKeyHashSecret)
 	cfg.LinuxDo.ClientID = strings.TrimSpace(cfg.LinuxDo.ClientID)
 	cfg.LinuxDo.ClientSecret = strings.TrimSpace(cfg.LinuxDo.ClientSecret)
 	cfg.LinuxDo.AuthorizeURL = strings.TrimSpace(cfg.LinuxDo.AuthorizeURL)
@@ -1031,7 +1425,27 @@ func load(allowMissingJWTSecret bool) (*Config, error) {
 	cfg.LinuxDo.UserInfoEmailPath = strings.TrimSpace(cfg.LinuxDo.UserInfoEmailPath)
 	cfg.LinuxDo.UserInfoIDPath = strings.TrimSpace(cfg.LinuxDo.UserInfoIDPath)
 	cfg.LinuxDo.UserInfoUsernamePath = strings.TrimSpace(cfg.LinuxDo.UserInfoUsernamePath)
+	normalizeSSOConfig(&cfg.SSO)
+	cfg.UsageExport.Storage = strings.ToLower(strings.TrimSpace(cfg.UsageExport.Storage))
+	cfg.UsageExport.LocalDir = strings.TrimSpace(cfg.UsageExport.LocalDir)
+	cfg.OpenAIBatch.Storage = strings.ToLower(strings.TrimSpace(cfg.OpenAIBatch.Storage))
+	cfg.OpenAIBatch.LocalDir = strings.TrimSpace(cfg.OpenAIBatch.LocalDir)
 	cfg.Dashboard.KeyPrefix = strings.TrimSpace(cfg.Dashboard.KeyPrefix)
+	cfg.Metrics.Path = strings.TrimSpace(cfg.Metrics.Path)
+	if cfg.Metrics.Path == "" {
+		cfg.Metrics.Path = "/metrics"
+	}
+	cfg.Metrics.Token = strings.TrimSpace(cfg.Metrics.Token)
+	cfg.Tracing.Protocol = strings.ToLower(strings.TrimSpace(cfg.Tracing.Protocol))
+	if cfg.Tracing.Protocol == "" {
+		cfg.Tracing.Protocol = "grpc"
+	}
+	cfg.Tracing.Endpoint = strings.TrimSpace(cfg.Tracing.Endpoint)
+	cfg.Tracing.URLPath = strings.TrimSpace(cfg.Tracing.URLPath)
+	cfg.Tracing.ServiceName = strings.TrimSpace(cfg.Tracing.ServiceName)
+	if cfg.Tracing.ServiceName == "" {
+		cfg.Tracing.ServiceName = "sub2api"
+	}
 	cfg.CORS.AllowedOrigins = normalizeStringSlice(cfg.CORS.AllowedOrigins)
 	cfg.Security.ResponseHeaders.AdditionalAllowed = normalizeStringSlice(cfg.Security.ResponseHeaders.AdditionalAllowed)
 	cfg.Security.ResponseHeaders.ForceRemove = normalizeStringSlice(cfg.Security.ResponseHeaders.ForceRemove)
@@ -1172,6 +1586,7 @@ func setDefaults() {
 	viper.SetDefault("security.csp.enabled", true)
 	viper.SetDefault("security.csp.policy", DefaultCSPPolicy)
 	viper.SetDefault("security.proxy_probe.insecure_skip_verify", false)
+	viper.SetDefault("security.api_key_hash_secret", "")
 
 	// Security - disable direct fallback on proxy error
 	viper.SetDefault("security.proxy_fallback.allow_direct_on_error", false)
@@ -1239,6 +1654,21 @@ func setDefaults() {
 	// TTL should be slightly larger than collection interval (1m) to maximize cross-replica cache hits.
 	viper.SetDefault("ops.metrics_collector_cache.ttl", 65*time.Second)
 
+	// Prometheus metrics
+	viper.SetDefault("metrics.enabled", false)
+	viper.SetDefault("metrics.path", "/metrics")
+	viper.SetDefault("metrics.token", "")
+	viper.SetDefault("metrics.account_label", true)
+
+	// Tracing
+	viper.SetDefault("tracing.enabled", false)
+	viper.SetDefault("tracing.protocol", "grpc")
+	viper.SetDefault("tracing.endpoint", "localhost:4317")
+	viper.SetDefault("tracing.url_path", "")
+	viper.SetDefault("tracing.insecure", true)
+	viper.SetDefault("tracing.service_name", "sub2api")
+	viper.SetDefault("tracing.sample_ratio", 1.0)
+
 	// JWT
 	viper.SetDefault("jwt.secret", "")
 	viper.SetDefault("jwt.expire_hour", 24)
@@ -1249,6 +1679,11 @@ func setDefaults() {
 	// TOTP
 	viper.SetDefault("totp.encryption_key", "")
 
+	// Credential encryption
+	viper.SetDefault("credential_encryption.enabled", false)
+	viper.SetDefault("credential_encryption.keys", "")
+	viper.SetDefault("credential_encryption.active_key_id", "")
+
 	// Default
 	// Admin credentials are created via the setup flow (web wizard / CLI / AUTO_SETUP).
 	// Do not ship fixed defaults here to avoid insecure "known credentials" in production.
@@ -1312,6 +1747,84 @@ func setDefaults() {
 	viper.SetDefault("usage_cleanup.worker_interval_seconds", 10)
 	viper.SetDefault("usage_cleanup.task_timeout_seconds", 1800)
 
+	// Admin audit log
+	viper.SetDefault("usage_export.enabled", true)
+	viper.SetDefault("usage_export.storage", UsageExportStorageLocal)
+	viper.SetDefault("usage_export.local_dir", "./data/exports")
+	viper.SetDefault("usage_export.max_sync_range_days", 31)
+	viper.SetDefault("usage_export.max_range_days", 366)
+	viper.SetDefault("usage_export.batch_size", 5000)
+	viper.SetDefault("usage_export.worker_interval_seconds", 10)
+	viper.SetDefault("usage_export.task_timeout_seconds", 3600)
+	viper.SetDefault("usage_export.link_ttl_seconds", 86400)
+	viper.SetDefault("usage_export.retention_hours", 72)
+
+	viper.SetDefault("admin_audit.enabled", true)
+	viper.SetDefault("admin_audit.retention_days", 180)
+	viper.SetDefault("admin_audit.cleanup_schedule", "30 3 * * *")
+
+	viper.SetDefault("statement.enabled", true)
+	viper.SetDefault("statement.close_schedule", "20 0 * * *")
+	viper.SetDefault("statement.email_enabled", false)
+
+	viper.SetDefault("proxy_pool.enabled", true)
+	viper.SetDefault("proxy_pool.check_interval_seconds", 60)
+	viper.SetDefault("proxy_pool.probe_timeout_seconds", 15)
+	viper.SetDefault("proxy_pool.probe_concurrency", 8)
+	viper.SetDefault("proxy_pool.failure_threshold", 2)
+
+	viper.SetDefault("webhook.enabled", true)
+	viper.SetDefault("webhook.max_per_user", 10)
+	viper.SetDefault("webhook.worker_interval_seconds", 5)
+	viper.SetDefault("webhook.batch_size", 50)
+	viper.SetDefault("webhook.concurrency", 8)
+	viper.SetDefault("webhook.request_timeout_seconds", 10)
+	viper.SetDefault("webhook.max_attempts", 8)
+	viper.SetDefault("webhook.retry_backoff_base_seconds", 30)
+	viper.SetDefault("webhook.retry_backoff_max_seconds", 21600)
+	viper.SetDefault("webhook.evaluate_interval_seconds", 300)
+	viper.SetDefault("webhook.retention_days", 14)
+
+	viper.SetDefault("notification.enabled", true)
+	viper.SetDefault("notification.evaluate_interval_seconds", 600)
+	viper.SetDefault("notification.max_emails_per_run", 200)
+
+	// Response cache
+	viper.SetDefault("response_cache.enabled", true)
+	viper.SetDefault("response_cache.default_ttl_seconds", 3600)
+	viper.SetDefault("response_cache.max_ttl_seconds", 604800)
+	viper.SetDefault("response_cache.max_entry_bytes", 1<<20)
+
+	// Message batches
+	viper.SetDefault("message_batch.enabled", true)
+	viper.SetDefault("message_batch.max_requests_per_batch", 10000)
+	viper.SetDefault("message_batch.worker_interval_seconds", 5)
+	viper.SetDefault("message_batch.claim_size", 20)
+	viper.SetDefault("message_batch.concurrency", 4)
+	viper.SetDefault("message_batch.max_account_load_percent", 50)
+	viper.SetDefault("message_batch.request_timeout_seconds", 600)
+	viper.SetDefault("message_batch.max_attempts", 5)
+	viper.SetDefault("message_batch.retry_delay_seconds", 30)
+	viper.SetDefault("message_batch.expire_hours", 24)
+	viper.SetDefault("message_batch.retention_days", 29)
+	viper.SetDefault("message_batch.price_multiplier", 0.5)
+
+	// OpenAI files & batches
+	viper.SetDefault("openai_batch.enabled", true)
+	viper.SetDefault("openai_batch.storage", OpenAIFileStorageLocal)
+	viper.SetDefault("openai_batch.local_dir", "./data/openai_files")
+	viper.SetDefault("openai_batch.max_file_size_mb", 200)
+	viper.SetDefault("openai_batch.max_requests_per_batch", 50000)
+	viper.SetDefault("openai_batch.worker_interval_seconds", 5)
+	viper.SetDefault("openai_batch.claim_size", 20)
+	viper.SetDefault("openai_batch.concurrency", 4)
+	viper.SetDefault("openai_batch.max_account_load_percent", 50)
+	viper.SetDefault("openai_batch.request_timeout_seconds", 600)
+	viper.SetDefault("openai_batch.max_attempts", 5)
+	viper.SetDefault("openai_batch.retry_delay_seconds", 30)
+	viper.SetDefault("openai_batch.retention_days", 30)
+	viper.SetDefault("openai_batch.price_multiplier", 0.5)
+
 	// Idempotency
 	viper.SetDefault("idempotency.observe_only", true)
 	viper.SetDefault("idempotency.default_ttl_seconds", 86400)
@@ -1403,6 +1916,8 @@ func setDefaults() {
 	viper.SetDefault("gateway.stream_data_interval_timeout", 180)
 	viper.SetDefault("gateway.stream_keepalive_interval", 10)
 	viper.SetDefault("gateway.max_line_size", 40*1024*1024)
+	viper.SetDefault("gateway.digest_session.store", "memory")
+	viper.SetDefault("gateway.digest_session.ttl_seconds", 300)
 	viper.SetDefault("gateway.scheduling.sticky_session_max_waiting", 3)
 	viper.SetDefault("gateway.scheduling.sticky_session_wait_timeout", 120*time.Second)
 	viper.SetDefault("gateway.scheduling.fallback_wait_timeout", 30*time.Second)
@@ -1419,6 +1934,14 @@ func setDefaults() {
 	viper.SetDefault("gateway.scheduling.outbox_lag_rebuild_failures", 3)
 	viper.SetDefault("gateway.scheduling.outbox_backlog_rebuild_rows", 10000)
 	viper.SetDefault("gateway.scheduling.full_rebuild_interval_seconds", 300)
+	viper.SetDefault("gateway.scheduling.health_score.enabled", false)
+	viper.SetDefault("gateway.scheduling.health_score.decay_half_life_seconds", 300)
+	viper.SetDefault("gateway.scheduling.health_score.weights.load", 1.0)
+	viper.SetDefault("gateway.scheduling.health_score.weights.queue", 0.7)
+	viper.SetDefault("gateway.scheduling.health_score.weights.error_rate", 0.8)
+	viper.SetDefault("gateway.scheduling.health_score.weights.ttft", 0.5)
+	viper.SetDefault("gateway.scheduling.health_score.weights.overload", 1.0)
+	viper.SetDefault("gateway.scheduling.health_score.weights.token_refresh", 0.6)
 	viper.SetDefault("gateway.usage_record.worker_count", 128)
 	viper.SetDefault("gateway.usage_record.queue_size", 16384)
 	viper.SetDefault("gateway.usage_record.task_timeout_seconds", 5)
@@ -1479,6 +2002,17 @@ func setDefaults() {
 	viper.SetDefault("sora.storage.cleanup.retention_days", 7)
 	viper.SetDefault("sora.storage.cleanup.schedule", "0 3 * * *")
 
+	viper.SetDefault("sora.jobs.workers", 4)
+	viper.SetDefault("sora.jobs.poll_interval_seconds", 2)
+	viper.SetDefault("sora.jobs.lease_seconds", 60)
+	viper.SetDefault("sora.jobs.task_timeout_seconds", 1800)
+	viper.SetDefault("sora.jobs.max_attempts", 3)
+	viper.SetDefault("sora.jobs.retry_backoff_base_seconds", 10)
+	viper.SetDefault("sora.jobs.retry_backoff_max_seconds", 300)
+	viper.SetDefault("sora.jobs.reap_interval_seconds", 60)
+	viper.SetDefault("sora.jobs.orphan_timeout_minutes", 60)
+	viper.SetDefault("sora.jobs.retention_days", 7)
+
 	// TokenRefresh
 	viper.SetDefault("token_refresh.enabled", true)
 	viper.SetDefault("token_refresh.check_interval_minutes", 5)        // 每5分钟检查一次
@@ -1674,6 +2208,9 @@ func (c *Config) Validate() error {
 		warnIfInsecureURL("linuxdo_connect.redirect_url", c.LinuxDo.RedirectURL)
 		warnIfInsecureURL("linuxdo_connect.frontend_redirect_url", c.LinuxDo.FrontendRedirectURL)
 	}
+	if err := validateSSOConfig(&c.SSO); err != nil {
+		return err
+	}
 	if c.Billing.CircuitBreaker.Enabled {
 		if c.Billing.CircuitBreaker.FailureThreshold <= 0 {
 			return fmt.Errorf("billing.circuit_breaker.failure_threshold must be positive")
@@ -1790,6 +2327,161 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("dashboard_aggregation.recompute_days must be non-negative")
 		}
 	}
+	if c.AdminAudit.RetentionDays < 0 {
+		return fmt.Errorf("admin_audit.retention_days must be non-negative")
+	}
+	if c.ProxyPool.Enabled {
+		if c.ProxyPool.CheckIntervalSeconds <= 0 {
+			return fmt.Errorf("proxy_pool.check_interval_seconds must be positive")
+		}
+		if c.ProxyPool.ProbeTimeoutSeconds <= 0 {
+			return fmt.Errorf("proxy_pool.probe_timeout_seconds must be positive")
+		}
+		if c.ProxyPool.ProbeConcurrency <= 0 {
+			return fmt.Errorf("proxy_pool.probe_concurrency must be positive")
+		}
+		if c.ProxyPool.FailureThreshold <= 0 {
+			return fmt.Errorf("proxy_pool.failure_threshold must be positive")
+		}
+	}
+	if c.Webhook.Enabled {
+		if c.Webhook.MaxPerUser <= 0 {
+			return fmt.Errorf("webhook.max_per_user must be positive")
+		}
+		if c.Webhook.WorkerIntervalSeconds <= 0 {
+			return fmt.Errorf("webhook.worker_interval_seconds must be positive")
+		}
+		if c.Webhook.BatchSize <= 0 {
+			return fmt.Errorf("webhook.batch_size must be positive")
+		}
+		if c.Webhook.Concurrency <= 0 {
+			return fmt.Errorf("webhook.concurrency must be positive")
+		}
+		if c.Webhook.RequestTimeoutSeconds <= 0 {
+			return fmt.Errorf("webhook.request_timeout_seconds must be positive")
+		}
+		if c.Webhook.MaxAttempts <= 0 {
+			return fmt.Errorf("webhook.max_attempts must be positive")
+		}
+		if c.Webhook.RetryBackoffBaseSeconds <= 0 {
+			return fmt.Errorf("webhook.retry_backoff_base_seconds must be positive")
+		}
+		if c.Webhook.EvaluateIntervalSeconds <= 0 {
+			return fmt.Errorf("webhook.evaluate_interval_seconds must be positive")
+		}
+		if c.Webhook.RetentionDays <= 0 {
+			return fmt.Errorf("webhook.retention_days must be positive")
+		}
+		if c.Webhook.RetryBackoffMaxSeconds < c.Webhook.RetryBackoffBaseSeconds {
+			return fmt.Errorf("webhook.retry_backoff_max_seconds must be >= webhook.retry_backoff_base_seconds")
+		}
+	}
+	if c.Notification.Enabled {
+		if c.Notification.EvaluateIntervalSeconds <= 0 {
+			return fmt.Errorf("notification.evaluate_interval_seconds must be positive")
+		}
+		if c.Notification.MaxEmailsPerRun <= 0 {
+			return fmt.Errorf("notification.max_emails_per_run must be positive")
+		}
+	}
+	credentialKeys, err := c.CredentialEncryption.CredentialEncryptionKeys()
+	if err != nil {
+		return err
+	}
+	if activeKeyID := strings.TrimSpace(c.CredentialEncryption.ActiveKeyID); activeKeyID != "" {
+		if _, ok := credentialKeys[activeKeyID]; !ok {
+			return fmt.Errorf("credential_encryption.active_key_id %q is not in credential_encryption.keys", activeKeyID)
+		}
+	}
+	if c.ResponseCache.Enabled {
+		if c.ResponseCache.DefaultTTLSeconds <= 0 {
+			return fmt.Errorf("response_cache.default_ttl_seconds must be positive")
+		}
+		if c.ResponseCache.MaxTTLSeconds < c.ResponseCache.DefaultTTLSeconds {
+			return fmt.Errorf("response_cache.max_ttl_seconds must be >= response_cache.default_ttl_seconds")
+		}
+		if c.ResponseCache.MaxEntryBytes <= 0 {
+			return fmt.Errorf("response_cache.max_entry_bytes must be positive")
+		}
+	}
+	if c.MessageBatch.Enabled {
+		if c.MessageBatch.MaxRequestsPerBatch <= 0 {
+			return fmt.Errorf("message_batch.max_requests_per_batch must be positive")
+		}
+		if c.MessageBatch.WorkerIntervalSeconds <= 0 {
+			return fmt.Errorf("message_batch.worker_interval_seconds must be positive")
+		}
+		if c.MessageBatch.ClaimSize <= 0 {
+			return fmt.Errorf("message_batch.claim_size must be positive")
+		}
+		if c.MessageBatch.Concurrency <= 0 {
+			return fmt.Errorf("message_batch.concurrency must be positive")
+		}
+		if c.MessageBatch.MaxAccountLoadPercent < 0 || c.MessageBatch.MaxAccountLoadPercent > 100 {
+			return fmt.Errorf("message_batch.max_account_load_percent must be between 0 and 100")
+		}
+		if c.MessageBatch.RequestTimeoutSeconds <= 0 {
+			return fmt.Errorf("message_batch.request_timeout_seconds must be positive")
+		}
+		if c.MessageBatch.MaxAttempts <= 0 {
+			return fmt.Errorf("message_batch.max_attempts must be positive")
+		}
+		if c.MessageBatch.RetryDelaySeconds <= 0 {
+			return fmt.Errorf("message_batch.retry_delay_seconds must be positive")
+		}
+		if c.MessageBatch.ExpireHours <= 0 {
+			return fmt.Errorf("message_batch.expire_hours must be positive")
+		}
+		if c.MessageBatch.RetentionDays <= 0 {
+			return fmt.Errorf("message_batch.retention_days must be positive")
+		}
+		if c.MessageBatch.PriceMultiplier <= 0 || c.MessageBatch.PriceMultiplier > 1 {
+			return fmt.Errorf("message_batch.price_multiplier must be in (0, 1]")
+		}
+	}
+	if c.OpenAIBatch.Enabled {
+		switch c.OpenAIBatch.Storage {
+		case OpenAIFileStorageLocal, OpenAIFileStorageS3:
+		default:
+			return fmt.Errorf("openai_batch.storage must be one of: local/s3")
+		}
+		if strings.TrimSpace(c.OpenAIBatch.LocalDir) == "" {
+			return fmt.Errorf("openai_batch.local_dir is required")
+		}
+		if c.OpenAIBatch.MaxFileSizeMB <= 0 {
+			return fmt.Errorf("openai_batch.max_file_size_mb must be positive")
+		}
+		if c.OpenAIBatch.MaxRequestsPerBatch <= 0 {
+			return fmt.Errorf("openai_batch.max_requests_per_batch must be positive")
+		}
+		if c.OpenAIBatch.WorkerIntervalSeconds <= 0 {
+			return fmt.Errorf("openai_batch.worker_interval_seconds must be positive")
+		}
+		if c.OpenAIBatch.ClaimSize <= 0 {
+			return fmt.Errorf("openai_batch.claim_size must be positive")
+		}
+		if c.OpenAIBatch.Concurrency <= 0 {
+			return fmt.Errorf("openai_batch.concurrency must be positive")
+		}
+		if c.OpenAIBatch.MaxAccountLoadPercent < 0 || c.OpenAIBatch.MaxAccountLoadPercent > 100 {
+			return fmt.Errorf("openai_batch.max_account_load_percent must be between 0 and 100")
+		}
+		if c.OpenAIBatch.RequestTimeoutSeconds <= 0 {
+			return fmt.Errorf("openai_batch.request_timeout_seconds must be positive")
+		}
+		if c.OpenAIBatch.MaxAttempts <= 0 {
+			return fmt.Errorf("openai_batch.max_attempts must be positive")
+		}
+		if c.OpenAIBatch.RetryDelaySeconds <= 0 {
+			return fmt.Errorf("openai_batch.retry_delay_seconds must be positive")
+		}
+		if c.OpenAIBatch.RetentionDays <= 0 {
+			return fmt.Errorf("openai_batch.retention_days must be positive")
+		}
+		if c.OpenAIBatch.PriceMultiplier <= 0 || c.OpenAIBatch.PriceMultiplier > 1 {
+			return fmt.Errorf("openai_batch.price_multiplier must be in (0, 1]")
+		}
+	}
 	if c.UsageCleanup.Enabled {
 		if c.UsageCleanup.MaxRangeDays <= 0 {
 			return fmt.Errorf("usage_cleanup.max_range_days must be positive")
@@ -1817,6 +2509,57 @@ func (c *Config) Validate() error {
 			return fmt.Errorf("usage_cleanup.task_timeout_seconds must be non-negative")
 		}
 	}
+	if c.UsageExport.Enabled {
+		switch c.UsageExport.Storage {
+		case UsageExportStorageLocal:
+			if strings.TrimSpace(c.UsageExport.LocalDir) == "" {
+				return fmt.Errorf("usage_export.local_dir is required when usage_export.storage is local")
+			}
+		case UsageExportStorageS3:
+		default:
+			return fmt.Errorf("usage_export.storage must be one of: local/s3")
+		}
+		if c.UsageExport.MaxSyncRangeDays <= 0 {
+			return fmt.Errorf("usage_export.max_sync_range_days must be positive")
+		}
+		if c.UsageExport.MaxRangeDays < c.UsageExport.MaxSyncRangeDays {
+			return fmt.Errorf("usage_export.max_range_days must be >= usage_export.max_sync_range_days")
+		}
+		if c.UsageExport.BatchSize <= 0 {
+			return fmt.Errorf("usage_export.batch_size must be positive")
+		}
+		if c.UsageExport.WorkerIntervalSeconds <= 0 {
+			return fmt.Errorf("usage_export.worker_interval_seconds must be positive")
+		}
+		if c.UsageExport.TaskTimeoutSeconds <= 0 {
+			return fmt.Errorf("usage_export.task_timeout_seconds must be positive")
+		}
+		if c.UsageExport.LinkTTLSeconds <= 0 {
+			return fmt.Errorf("usage_export.link_ttl_seconds must be positive")
+		}
+		if c.UsageExport.RetentionHours <= 0 {
+			return fmt.Errorf("usage_export.retention_hours must be positive")
+		}
+	}
+	if c.Metrics.Enabled {
+		if strings.TrimSpace(c.Metrics.Token) == "" {
+			return fmt.Errorf("metrics.token is required when metrics.enabled is true")
+		}
+		if !strings.HasPrefix(c.Metrics.Path, "/") {
+			return fmt.Errorf("metrics.path must start with /")
+		}
+	}
+	if c.Tracing.Enabled {
+		if c.Tracing.Protocol != "grpc" && c.Tracing.Protocol != "http" {
+			return fmt.Errorf("tracing.protocol must be one of: grpc/http")
+		}
+		if c.Tracing.Endpoint == "" {
+			return fmt.Errorf("tracing.endpoint is required when tracing.enabled is true")
+		}
+		if c.Tracing.SampleRatio < 0 || c.Tracing.SampleRatio > 1 {
+			return fmt.Errorf("tracing.sample_ratio must be between 0 and 1")
+		}
+	}
 	if c.Idempotency.DefaultTTLSeconds <= 0 {
 		return fmt.Errorf("idempotency.default_ttl_seconds must be positive")
 	}
@@ -1912,6 +2655,36 @@ func (c *Config) Validate() error {
 	if c.Sora.Storage.MaxDownloadBytes < 0 {
 		return fmt.Errorf("sora.storage.max_download_bytes must be non-negative")
 	}
+	if c.Sora.Jobs.Workers < 0 {
+		return fmt.Errorf("sora.jobs.workers must be non-negative")
+	}
+	if c.Sora.Jobs.PollIntervalSeconds < 0 {
+		return fmt.Errorf("sora.jobs.poll_interval_seconds must be non-negative")
+	}
+	if c.Sora.Jobs.LeaseSeconds < 0 {
+		return fmt.Errorf("sora.jobs.lease_seconds must be non-negative")
+	}
+	if c.Sora.Jobs.TaskTimeoutSeconds < 0 {
+		return fmt.Errorf("sora.jobs.task_timeout_seconds must be non-negative")
+	}
+	if c.Sora.Jobs.MaxAttempts < 0 {
+		return fmt.Errorf("sora.jobs.max_attempts must be non-negative")
+	}
+	if c.Sora.Jobs.RetryBackoffBaseSeconds < 0 {
+		return fmt.Errorf("sora.jobs.retry_backoff_base_seconds must be non-negative")
+	}
+	if c.Sora.Jobs.RetryBackoffMaxSeconds < 0 {
+		return fmt.Errorf("sora.jobs.retry_backoff_max_seconds must be non-negative")
+	}
+	if c.Sora.Jobs.ReapIntervalSeconds < 0 {
+		return fmt.Errorf("sora.jobs.reap_interval_seconds must be non-negative")
+	}
+	if c.Sora.Jobs.OrphanTimeoutMinutes < 0 {
+		return fmt.Errorf("sora.jobs.orphan_timeout_minutes must be non-negative")
+	}
+	if c.Sora.Jobs.RetentionDays < 0 {
+		return fmt.Errorf("sora.jobs.retention_days must be non-negative")
+	}
 	if c.Sora.Storage.Cleanup.Enabled {
 		if c.Sora.Storage.Cleanup.RetentionDays <= 0 {
 			return fmt.Errorf("sora.storage.cleanup.retention_days must be positive")
@@ -2157,6 +2930,14 @@ func (c *Config) Validate() error {
 	if c.Gateway.ModelsListCacheTTLSeconds < 10 || c.Gateway.ModelsListCacheTTLSeconds > 30 {
 		return fmt.Errorf("gateway.models_list_cache_ttl_seconds must be between 10-30")
 	}
+	switch strings.ToLower(strings.TrimSpace(c.Gateway.DigestSession.Store)) {
+	case "", "memory", "redis":
+	default:
+		return fmt.Errorf("gateway.digest_session.store must be one of: memory/redis")
+	}
+	if c.Gateway.DigestSession.TTLSeconds < 0 {
+		return fmt.Errorf("gateway.digest_session.ttl_seconds must be non-negative")
+	}
 	if c.Gateway.Scheduling.StickySessionMaxWaiting <= 0 {
 		return fmt.Errorf("gateway.scheduling.sticky_session_max_waiting must be positive")
 	}
@@ -2201,6 +2982,18 @@ func (c *Config) Validate() error {
 		c.Gateway.Scheduling.OutboxLagRebuildSeconds < c.Gateway.Scheduling.OutboxLagWarnSeconds {
 		return fmt.Errorf("gateway.scheduling.outbox_lag_rebuild_seconds must be >= outbox_lag_warn_seconds")
 	}
+	if c.Gateway.Scheduling.HealthScore.Enabled {
+		w := c.Gateway.Scheduling.HealthScore.Weights
+		if c.Gateway.Scheduling.HealthScore.DecayHalfLifeSeconds <= 0 {
+			return fmt.Errorf("gateway.scheduling.health_score.decay_half_life_seconds must be positive")
+		}
+		if w.Load < 0 || w.Queue < 0 || w.ErrorRate < 0 || w.TTFT < 0 || w.Overload < 0 || w.TokenRefresh < 0 {
+			return fmt.Errorf("gateway.scheduling.health_score.weights.* must be non-negative")
+		}
+		if w.Load+w.Queue+w.ErrorRate+w.TTFT+w.Overload+w.TokenRefresh <= 0 {
+			return fmt.Errorf("gateway.scheduling.health_score.weights must not all be zero")
+		}
+	}
 	if c.Ops.MetricsCollectorCache.TTL < 0 {
 		return fmt.Errorf("ops.metrics_collector_cache.ttl must be non-negative")
 	}
@@ -2366,3 +3159,199 @@ func warnIfInsecureURL(field, raw string) {
 		slog.Warn("url uses http scheme; use https in production to avoid token leakage", "field", field)
 	}
 }
+
+const (
+	defaultSSOFrontendRedirectURL = "/auth/sso/callback"
+	defaultGoogleIssuer           = "https://accounts.google.com"
+	defaultGitHubAuthorizeURL     = "https://github.com/login/oauth/authorize"
+	defaultGitHubTokenURL         = "https://github.com/login/oauth/access_token"
+	defaultGitHubAPIBaseURL       = "https://api.github.com"
+)
+
+func normalizeSSOConfig(sso *SSOConfig) {
+	for i := range sso.Providers {
+		p := &sso.Providers[i]
+		p.ID = strings.ToLower(strings.TrimSpace(p.ID))
+		p.Type = strings.ToLower(strings.TrimSpace(p.Type))
+		p.DisplayName = strings.TrimSpace(p.DisplayName)
+		p.ClientID = strings.TrimSpace(p.ClientID)
+		p.ClientSecret = strings.TrimSpace(p.ClientSecret)
+		p.Issuer = strings.TrimRight(strings.TrimSpace(p.Issuer), "/")
+		p.Scopes = strings.TrimSpace(p.Scopes)
+		p.TokenAuthMethod = strings.ToLower(strings.TrimSpace(p.TokenAuthMethod))
+		p.AuthorizeURL = strings.TrimSpace(p.AuthorizeURL)
+		p.TokenURL = strings.TrimSpace(p.TokenURL)
+		p.UserInfoURL = strings.TrimSpace(p.UserInfoURL)
+		p.JWKSURL = strings.TrimSpace(p.JWKSURL)
+		p.APIBaseURL = strings.TrimRight(strings.TrimSpace(p.APIBaseURL), "/")
+		p.RedirectURL = strings.TrimSpace(p.RedirectURL)
+		p.FrontendRedirectURL = strings.TrimSpace(p.FrontendRedirectURL)
+		p.EmailClaim = strings.TrimSpace(p.EmailClaim)
+		p.UsernameClaim = strings.TrimSpace(p.UsernameClaim)
+		p.GroupsClaim = strings.TrimSpace(p.GroupsClaim)
+
+		if p.FrontendRedirectURL == "" {
+			p.FrontendRedirectURL = defaultSSOFrontendRedirectURL
+		}
+		if p.EmailClaim == "" {
+			p.EmailClaim = "email"
+		}
+		if p.GroupsClaim == "" {
+			p.GroupsClaim = "groups"
+		}
+		domains := make([]string, 0, len(p.AllowedDomains))
+		for _, d := range p.AllowedDomains {
+			d = strings.ToLower(strings.TrimPrefix(strings.TrimSpace(d), "@"))
+			if d != "" {
+				domains = append(domains, d)
+			}
+		}
+		p.AllowedDomains = domains
+
+		switch p.Type {
+		case SSOProviderTypeGoogle:
+			if p.Issuer == "" {
+				p.Issuer = defaultGoogleIssuer
+			}
+			if p.Scopes == "" {
+				p.Scopes = "openid email profile"
+			}
+			if p.DisplayName == "" {
+				p.DisplayName = "Google"
+			}
+		case SSOProviderTypeGitHub:
+			if p.AuthorizeURL == "" {
+				p.AuthorizeURL = defaultGitHubAuthorizeURL
+			}
+			if p.TokenURL == "" {
+				p.TokenURL = defaultGitHubTokenURL
+			}
+			if p.APIBaseURL == "" {
+				p.APIBaseURL = defaultGitHubAPIBaseURL
+			}
+			if p.Scopes == "" {
+				p.Scopes = "read:user user:email"
+				if len(p.GroupMappings) > 0 {
+					p.Scopes += " read:org"
+				}
+			}
+			if p.DisplayName == "" {
+				p.DisplayName = "GitHub"
+			}
+		case SSOProviderTypeOIDC:
+			if p.Scopes == "" {
+				p.Scopes = "openid email profile"
+			}
+		}
+		if p.DisplayName == "" {
+			p.DisplayName = p.ID
+		}
+		if p.TokenAuthMethod == "" {
+			switch {
+			case p.ClientSecret == "":
+				p.TokenAuthMethod = "none"
+			case p.Type == SSOProviderTypeGitHub:
+				p.TokenAuthMethod = "client_secret_post"
+			default:
+				p.TokenAuthMethod = "client_secret_basic"
+			}
+		}
+	}
+}
+
+func validateSSOConfig(sso *SSOConfig) error {
+	seen := make(map[string]struct{}, len(sso.Providers))
+	for i := range sso.Providers {
+		p := &sso.Providers[i]
+		if !p.Enabled {
+			continue
+		}
+		prefix := fmt.Sprintf("sso.providers[%d]", i)
+		if !isValidSSOProviderID(p.ID) {
+			return fmt.Errorf("%s.id must be 1-32 chars of lowercase letters, digits, '-' or '_'", prefix)
+		}
+		if _, ok := seen[p.ID]; ok {
+			return fmt.Errorf("%s.id %q is duplicated", prefix, p.ID)
+		}
+		seen[p.ID] = struct{}{}
+		if p.ClientID == "" {
+			return fmt.Errorf("%s.client_id is required", prefix)
+		}
+		switch p.Type {
+		case SSOProviderTypeOIDC, SSOProviderTypeGoogle:
+			if p.Issuer == "" {
+				return fmt.Errorf("%s.issuer is required for type=%s", prefix, p.Type)
+			}
+			if err := ValidateAbsoluteHTTPURL(p.Issuer); err != nil {
+				return fmt.Errorf("%s.issuer invalid: %w", prefix, err)
+			}
+			if !strings.Contains(" "+p.Scopes+" ", " openid ") {
+				return fmt.Errorf("%s.scopes must include openid for type=%s", prefix, p.Type)
+			}
+			if p.Type == SSOProviderTypeGoogle && p.ClientSecret == "" {
+				return fmt.Errorf("%s.client_secret is required for type=google", prefix)
+			}
+		case SSOProviderTypeGitHub:
+			if p.ClientSecret == "" {
+				return fmt.Errorf("%s.client_secret is required for type=github", prefix)
+			}
+		default:
+			return fmt.Errorf("%s.type must be one of: oidc/github/google", prefix)
+		}
+		switch p.TokenAuthMethod {
+		case "client_secret_basic", "client_secret_post":
+			if p.ClientSecret == "" {
+				return fmt.Errorf("%s.client_secret is required when token_auth_method=%s", prefix, p.TokenAuthMethod)
+			}
+		case "none":
+		default:
+			return fmt.Errorf("%s.token_auth_method must be one of: client_secret_basic/client_secret_post/none", prefix)
+		}
+		if p.RedirectURL == "" {
+			return fmt.Errorf("%s.redirect_url is required", prefix)
+		}
+		for field, raw := range map[string]string{
+			"redirect_url":  p.RedirectURL,
+			"authorize_url": p.AuthorizeURL,
+			"token_url":     p.TokenURL,
+			"userinfo_url":  p.UserInfoURL,
+			"jwks_url":      p.JWKSURL,
+			"api_base_url":  p.APIBaseURL,
+		} {
+			if raw == "" {
+				continue
+			}
+			if err := ValidateAbsoluteHTTPURL(raw); err != nil {
+				return fmt.Errorf("%s.%s invalid: %w", prefix, field, err)
+			}
+			warnIfInsecureURL(prefix+"."+field, raw)
+		}
+		if err := ValidateFrontendRedirectURL(p.FrontendRedirectURL); err != nil {
+			return fmt.Errorf("%s.frontend_redirect_url invalid: %w", prefix, err)
+		}
+		for group, ids := range p.GroupMappings {
+			for _, id := range ids {
+				if id <= 0 {
+					return fmt.Errorf("%s.group_mappings[%s] contains invalid group id %d", prefix, group, id)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func isValidSSOProviderID(id string) bool {
+	if id == "" || len(id) > 32 {
+		return false
+	}
+	for _, r := range id {
+		switch {
+		case r >= 'a' && r <= 'z':
+		case r >= '0' && r <= '9':
+		case r == '-' || r == '_':
+		default:
+			return false
+		}
+	}
+	return true
+}
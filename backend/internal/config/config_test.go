@@ -351,6 +351,93 @@ func TestValidateLinuxDoPKCERequiredForPublicClient(t *testing.T) {
 	}
 }
 
+func TestNormalizeSSOConfigPresets(t *testing.T) {
+	sso := SSOConfig{Providers: []SSOProviderConfig{
+		{ID: " GitHub ", Type: "GitHub", ClientSecret: "s", GroupMappings: map[string][]int64{"org": {1}}},
+		{ID: "google", Type: "google", ClientSecret: "s", AllowedDomains: []string{" @Example.COM "}},
+		{ID: "corp", Type: "oidc", Issuer: "https://idp.example.com/"},
+	}}
+	normalizeSSOConfig(&sso)
+
+	gh := sso.Providers[0]
+	if gh.ID != "github" || gh.Type != SSOProviderTypeGitHub {
+		t.Fatalf("github id/type not normalized: %q/%q", gh.ID, gh.Type)
+	}
+	if gh.TokenURL != defaultGitHubTokenURL || gh.APIBaseURL != defaultGitHubAPIBaseURL {
+		t.Fatalf("github endpoints not defaulted: %+v", gh)
+	}
+	if gh.Scopes != "read:user user:email read:org" || gh.TokenAuthMethod != "client_secret_post" {
+		t.Fatalf("github scopes/auth method = %q/%q", gh.Scopes, gh.TokenAuthMethod)
+	}
+
+	google := sso.Providers[1]
+	if google.Issuer != defaultGoogleIssuer || google.TokenAuthMethod != "client_secret_basic" {
+		t.Fatalf("google preset not applied: %+v", google)
+	}
+	if len(google.AllowedDomains) != 1 || google.AllowedDomains[0] != "example.com" {
+		t.Fatalf("AllowedDomains = %v, want [example.com]", google.AllowedDomains)
+	}
+
+	corp := sso.Providers[2]
+	if corp.Issuer != "https://idp.example.com" || corp.TokenAuthMethod != "none" {
+		t.Fatalf("oidc issuer/auth method = %q/%q", corp.Issuer, corp.TokenAuthMethod)
+	}
+	if corp.FrontendRedirectURL != defaultSSOFrontendRedirectURL || corp.DisplayName != "corp" {
+		t.Fatalf("oidc defaults not applied: %+v", corp)
+	}
+}
+
+func TestValidateSSOConfig(t *testing.T) {
+	valid := func() SSOConfig {
+		sso := SSOConfig{Providers: []SSOProviderConfig{{
+			ID:          "corp",
+			Type:        "oidc",
+			Enabled:     true,
+			ClientID:    "client",
+			Issuer:      "https://idp.example.com",
+			RedirectURL: "https://example.com/api/v1/auth/sso/corp/callback",
+		}}}
+		normalizeSSOConfig(&sso)
+		return sso
+	}
+
+	sso := valid()
+	if err := validateSSOConfig(&sso); err != nil {
+		t.Fatalf("validateSSOConfig() unexpected error: %v", err)
+	}
+
+	cases := map[string]struct {
+		mutate func(*SSOConfig)
+		want   string
+	}{
+		"bad id":           {func(c *SSOConfig) { c.Providers[0].ID = "Bad ID" }, "sso.providers[0].id"},
+		"duplicate id":     {func(c *SSOConfig) { c.Providers = append(c.Providers, c.Providers[0]) }, "duplicated"},
+		"missing openid":   {func(c *SSOConfig) { c.Providers[0].Scopes = "email" }, "openid"},
+		"unknown type":     {func(c *SSOConfig) { c.Providers[0].Type = "saml" }, "sso.providers[0].type"},
+		"github secret":    {func(c *SSOConfig) { c.Providers[0].Type = SSOProviderTypeGitHub }, "client_secret"},
+		"frontend scheme":  {func(c *SSOConfig) { c.Providers[0].FrontendRedirectURL = "javascript:alert(1)" }, "frontend_redirect_url"},
+		"bad group id":     {func(c *SSOConfig) { c.Providers[0].GroupMappings = map[string][]int64{"x": {0}} }, "group_mappings"},
+		"missing redirect": {func(c *SSOConfig) { c.Providers[0].RedirectURL = "" }, "redirect_url"},
+	}
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			sso := valid()
+			tc.mutate(&sso)
+			err := validateSSOConfig(&sso)
+			if err == nil || !strings.Contains(err.Error(), tc.want) {
+				t.Fatalf("validateSSOConfig() error = %v, want containing %q", err, tc.want)
+			}
+		})
+	}
+
+	disabled := valid()
+	disabled.Providers[0].Enabled = false
+	disabled.Providers[0].ClientID = ""
+	if err := validateSSOConfig(&disabled); err != nil {
+		t.Fatalf("disabled provider should not be validated: %v", err)
+	}
+}
+
 func TestLoadDefaultDashboardCacheConfig(t *testing.T) {
 	resetViperWithJWTSecret(t)
 
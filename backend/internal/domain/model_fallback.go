@@ -0,0 +1,112 @@
+package domain
+
+import (
+	"net/http"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// 模型降级触发条件
+const (
+	// ModelFallbackConditionRateLimited 分组内该模型的账号全部限流/不可调度
+	ModelFallbackConditionRateLimited = "rate_limited"
+	// ModelFallbackConditionOverloaded 上游过载（529 / 503 容量不足）且换号耗尽
+	ModelFallbackConditionOverloaded = "overloaded"
+	// ModelFallbackConditionPromptTooLong 上游返回 prompt 过长
+	ModelFallbackConditionPromptTooLong = "prompt_too_long"
+)
+
+const (
+	maxModelFallbackChains      = 20
+	maxModelFallbackChainLength = 10
+)
+
+var ErrModelFallbackChainInvalid = infraerrors.BadRequest("MODEL_FALLBACK_CHAIN_INVALID", "invalid model fallback chain")
+
+func invalidModelFallbackChain(format string, a ...any) error {
+	return infraerrors.Newf(http.StatusBadRequest, ErrModelFallbackChainInvalid.Reason, format, a...)
+}
+
+// ModelFallbackChain 分组内的有序模型降级链，如 claude-opus-* → claude-sonnet-4-5 → claude-haiku-4-5。
+type ModelFallbackChain struct {
+	// Models 有序模型列表：请求模型命中第 i 项时依次降级到 i+1、i+2...
+	// 仅首项允许使用末尾 * 通配，后续项必须是具体模型 ID。
+	Models []string `json:"models"`
+	// Conditions 触发降级的条件，为空表示任一条件均触发。
+	Conditions []string `json:"conditions,omitempty"`
+}
+
+// AllowsCondition 判断降级链是否响应指定条件
+func (c ModelFallbackChain) AllowsCondition(condition string) bool {
+	if condition == "" {
+		return false
+	}
+	if len(c.Conditions) == 0 {
+		return true
+	}
+	for _, item := range c.Conditions {
+		if item == condition {
+			return true
+		}
+	}
+	return false
+}
+
+// NormalizeModelFallbackChains 去除空白、校验降级链配置；nil/空输入返回 nil。
+func NormalizeModelFallbackChains(chains []ModelFallbackChain) ([]ModelFallbackChain, error) {
+	if len(chains) == 0 {
+		return nil, nil
+	}
+	if len(chains) > maxModelFallbackChains {
+		return nil, invalidModelFallbackChain("at most %d model fallback chains are allowed", maxModelFallbackChains)
+	}
+
+	out := make([]ModelFallbackChain, 0, len(chains))
+	for n, chain := range chains {
+		if len(chain.Models) < 2 || len(chain.Models) > maxModelFallbackChainLength {
+			return nil, invalidModelFallbackChain("chain %d must contain 2-%d models", n+1, maxModelFallbackChainLength)
+		}
+		models := make([]string, 0, len(chain.Models))
+		seen := make(map[string]struct{}, len(chain.Models))
+		for i, raw := range chain.Models {
+			model := strings.TrimSpace(raw)
+			if model == "" {
+				return nil, invalidModelFallbackChain("chain %d contains an empty model", n+1)
+			}
+			// 通配只用于匹配请求模型，降级目标必须可直接发往上游
+			if idx := strings.Index(model, "*"); idx >= 0 && (i > 0 || idx != len(model)-1) {
+				return nil, invalidModelFallbackChain("chain %d: only the first model may end with a * wildcard", n+1)
+			}
+			if _, dup := seen[model]; dup {
+				return nil, invalidModelFallbackChain("chain %d contains duplicate model %q", n+1, model)
+			}
+			seen[model] = struct{}{}
+			models = append(models, model)
+		}
+
+		var conditions []string
+		for _, raw := range chain.Conditions {
+			condition := strings.TrimSpace(raw)
+			switch condition {
+			case ModelFallbackConditionRateLimited, ModelFallbackConditionOverloaded, ModelFallbackConditionPromptTooLong:
+			default:
+				return nil, invalidModelFallbackChain("chain %d has unknown condition %q", n+1, condition)
+			}
+			if !containsString(conditions, condition) {
+				conditions = append(conditions, condition)
+			}
+		}
+		out = append(out, ModelFallbackChain{Models: models, Conditions: conditions})
+	}
+	return out, nil
+}
+
+func containsString(items []string, target string) bool {
+	for _, item := range items {
+		if item == target {
+			return true
+		}
+	}
+	return false
+}
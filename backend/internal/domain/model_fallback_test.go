@@ -0,0 +1,47 @@
+package domain
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestNormalizeModelFallbackChains(t *testing.T) {
+	out, err := NormalizeModelFallbackChains(nil)
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	out, err = NormalizeModelFallbackChains([]ModelFallbackChain{{
+		Models:     []string{" claude-opus-* ", "claude-sonnet-4-5", "claude-haiku-4-5"},
+		Conditions: []string{"overloaded", " overloaded", "rate_limited"},
+	}})
+	require.NoError(t, err)
+	require.Equal(t, []ModelFallbackChain{{
+		Models:     []string{"claude-opus-*", "claude-sonnet-4-5", "claude-haiku-4-5"},
+		Conditions: []string{"overloaded", "rate_limited"},
+	}}, out)
+
+	invalid := [][]ModelFallbackChain{
+		{{Models: []string{"claude-opus-4"}}},
+		{{Models: []string{"claude-opus-4", ""}}},
+		{{Models: []string{"claude-opus-*", "claude-sonnet-*"}}},
+		{{Models: []string{"claude-*-4", "claude-sonnet-4"}}},
+		{{Models: []string{"claude-opus-4", "claude-opus-4"}}},
+		{{Models: []string{"claude-opus-4", "claude-sonnet-4"}, Conditions: []string{"timeout"}}},
+	}
+	for _, chains := range invalid {
+		_, err := NormalizeModelFallbackChains(chains)
+		require.True(t, errors.Is(err, ErrModelFallbackChainInvalid), "chains=%v", chains)
+	}
+}
+
+func TestModelFallbackChainAllowsCondition(t *testing.T) {
+	all := ModelFallbackChain{Models: []string{"a", "b"}}
+	require.True(t, all.AllowsCondition(ModelFallbackConditionPromptTooLong))
+	require.False(t, all.AllowsCondition(""))
+
+	limited := ModelFallbackChain{Models: []string{"a", "b"}, Conditions: []string{ModelFallbackConditionOverloaded}}
+	require.True(t, limited.AllowsCondition(ModelFallbackConditionOverloaded))
+	require.False(t, limited.AllowsCondition(ModelFallbackConditionRateLimited))
+}
@@ -18,14 +18,19 @@ const (
 	legacyDataType = "sub2api-bundle"
 	dataVersion    = 1
 	dataPageCap    = 1000
+
+	// dataExportPassphraseHeader 导出口令通过请求头传递，避免出现在 URL 与访问日志中
+	dataExportPassphraseHeader = "X-Export-Passphrase"
 )
 
 type DataPayload struct {
-	Type       string        `json:"type,omitempty"`
-	Version    int           `json:"version,omitempty"`
-	ExportedAt string        `json:"exported_at"`
-	Proxies    []DataProxy   `json:"proxies"`
-	Accounts   []DataAccount `json:"accounts"`
+	Type       string `json:"type,omitempty"`
+	Version    int    `json:"version,omitempty"`
+	ExportedAt string `json:"exported_at"`
+	// Encryption 非空时账号凭证以口令加密，存放在 encrypted_credentials 中
+	Encryption *service.AccountExportEncryption `json:"encryption,omitempty"`
+	Proxies    []DataProxy                      `json:"proxies"`
+	Accounts   []DataAccount                    `json:"accounts"`
 }
 
 type DataProxy struct {
@@ -40,23 +45,27 @@ type DataProxy struct {
 }
 
 type DataAccount struct {
-	Name               string         `json:"name"`
-	Notes              *string        `json:"notes,omitempty"`
-	Platform           string         `json:"platform"`
-	Type               string         `json:"type"`
-	Credentials        map[string]any `json:"credentials"`
-	Extra              map[string]any `json:"extra,omitempty"`
-	ProxyKey           *string        `json:"proxy_key,omitempty"`
-	Concurrency        int            `json:"concurrency"`
-	Priority           int            `json:"priority"`
-	RateMultiplier     *float64       `json:"rate_multiplier,omitempty"`
-	ExpiresAt          *int64         `json:"expires_at,omitempty"`
-	AutoPauseOnExpired *bool          `json:"auto_pause_on_expired,omitempty"`
+	Name        string         `json:"name"`
+	Notes       *string        `json:"notes,omitempty"`
+	Platform    string         `json:"platform"`
+	Type        string         `json:"type"`
+	Credentials map[string]any `json:"credentials,omitempty"`
+	// EncryptedCredentials 口令加密后的凭证（base64），与 Credentials 二选一
+	EncryptedCredentials string         `json:"encrypted_credentials,omitempty"`
+	Extra                map[string]any `json:"extra,omitempty"`
+	ProxyKey             *string        `json:"proxy_key,omitempty"`
+	Concurrency          int            `json:"concurrency"`
+	Priority             int            `json:"priority"`
+	RateMultiplier       *float64       `json:"rate_multiplier,omitempty"`
+	ExpiresAt            *int64         `json:"expires_at,omitempty"`
+	AutoPauseOnExpired   *bool          `json:"auto_pause_on_expired,omitempty"`
 }
 
 type DataImportRequest struct {
 	Data                 DataPayload `json:"data"`
 	SkipDefaultGroupBind *bool       `json:"skip_default_group_bind"`
+	// Passphrase 导入加密导出文件时使用的口令
+	Passphrase string `json:"passphrase,omitempty"`
 }
 
 type DataImportResult struct {
@@ -100,6 +109,15 @@ func (h *AccountHandler) ExportData(c *gin.Context) {
 		return
 	}
 
+	var sealer *service.AccountExportSealer
+	if passphrase := c.GetHeader(dataExportPassphraseHeader); passphrase != "" {
+		sealer, err = service.NewAccountExportSealer(passphrase)
+		if err != nil {
+			response.ErrorFrom(c, err)
+			return
+		}
+	}
+
 	var proxies []service.Proxy
 	if includeProxies {
 		proxies, err = h.resolveExportProxies(ctx, accounts)
@@ -143,19 +161,30 @@ func (h *AccountHandler) ExportData(c *gin.Context) {
 			v := acc.ExpiresAt.Unix()
 			expiresAt = &v
 		}
+		credentials := acc.Credentials
+		var encryptedCredentials string
+		if sealer != nil {
+			encryptedCredentials, err = sealer.SealCredentials(acc.Credentials)
+			if err != nil {
+				response.ErrorFrom(c, err)
+				return
+			}
+			credentials = nil
+		}
 		dataAccounts = append(dataAccounts, DataAccount{
-			Name:               acc.Name,
-			Notes:              acc.Notes,
-			Platform:           acc.Platform,
-			Type:               acc.Type,
-			Credentials:        acc.Credentials,
-			Extra:              acc.Extra,
-			ProxyKey:           proxyKey,
-			Concurrency:        acc.Concurrency,
-			Priority:           acc.Priority,
-			RateMultiplier:     acc.RateMultiplier,
-			ExpiresAt:          expiresAt,
-			AutoPauseOnExpired: &acc.AutoPauseOnExpired,
+			Name:                 acc.Name,
+			Notes:                acc.Notes,
+			Platform:             acc.Platform,
+			Type:                 acc.Type,
+			Credentials:          credentials,
+			EncryptedCredentials: encryptedCredentials,
+			Extra:                acc.Extra,
+			ProxyKey:             proxyKey,
+			Concurrency:          acc.Concurrency,
+			Priority:             acc.Priority,
+			RateMultiplier:       acc.RateMultiplier,
+			ExpiresAt:            expiresAt,
+			AutoPauseOnExpired:   &acc.AutoPauseOnExpired,
 		})
 	}
 
@@ -164,6 +193,9 @@ func (h *AccountHandler) ExportData(c *gin.Context) {
 		Proxies:    dataProxies,
 		Accounts:   dataAccounts,
 	}
+	if sealer != nil {
+		payload.Encryption = sealer.Params()
+	}
 
 	response.Success(c, payload)
 }
@@ -194,6 +226,10 @@ func (h *AccountHandler) importData(ctx context.Context, req DataImportRequest)
 	dataPayload := req.Data
 	result := DataImportResult{}
 
+	if err := decryptDataAccounts(&dataPayload, req.Passphrase); err != nil {
+		return result, err
+	}
+
 	existingProxies, err := h.listAllProxies(ctx)
 	if err != nil {
 		return result, err
@@ -324,6 +360,32 @@ func (h *AccountHandler) importData(ctx context.Context, req DataImportRequest)
 	return result, nil
 }
 
+// decryptDataAccounts 使用口令解密加密导出文件中的账号凭证；口令错误时整体失败
+func decryptDataAccounts(payload *DataPayload, passphrase string) error {
+	if payload.Encryption == nil {
+		return nil
+	}
+	sealer, err := service.OpenAccountExportSealer(passphrase, payload.Encryption)
+	if err != nil {
+		return err
+	}
+	accounts := make([]DataAccount, len(payload.Accounts))
+	copy(accounts, payload.Accounts)
+	for i := range accounts {
+		if accounts[i].EncryptedCredentials == "" {
+			continue
+		}
+		credentials, err := sealer.OpenCredentials(accounts[i].EncryptedCredentials)
+		if err != nil {
+			return err
+		}
+		accounts[i].Credentials = credentials
+		accounts[i].EncryptedCredentials = ""
+	}
+	payload.Accounts = accounts
+	return nil
+}
+
 func (h *AccountHandler) listAllProxies(ctx context.Context) ([]service.Proxy, error) {
 	page := 1
 	pageSize := dataPageCap
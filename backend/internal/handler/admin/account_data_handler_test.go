@@ -230,3 +230,52 @@ func TestImportDataReusesProxyAndSkipsDefaultGroup(t *testing.T) {
 	require.Len(t, adminSvc.createdAccounts, 1)
 	require.True(t, adminSvc.createdAccounts[0].SkipDefaultGroupBind)
 }
+
+func TestExportImportDataWithPassphrase(t *testing.T) {
+	router, adminSvc := setupAccountDataRouter()
+	adminSvc.accounts = []service.Account{
+		{
+			ID:          31,
+			Name:        "encrypted",
+			Platform:    service.PlatformAnthropic,
+			Type:        service.AccountTypeAPIKey,
+			Credentials: map[string]any{"api_key": "sk-secret"},
+			Concurrency: 1,
+		},
+	}
+
+	rec := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodGet, "/api/v1/admin/accounts/data?include_proxies=false", nil)
+	req.Header.Set(dataExportPassphraseHeader, "correct horse battery")
+	router.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.NotContains(t, rec.Body.String(), "sk-secret")
+
+	var exported struct {
+		Data DataPayload `json:"data"`
+	}
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &exported))
+	require.NotNil(t, exported.Data.Encryption)
+	require.Len(t, exported.Data.Accounts, 1)
+	require.Empty(t, exported.Data.Accounts[0].Credentials)
+	require.NotEmpty(t, exported.Data.Accounts[0].EncryptedCredentials)
+
+	importWith := func(passphrase string) *httptest.ResponseRecorder {
+		body, err := json.Marshal(DataImportRequest{Data: exported.Data, Passphrase: passphrase})
+		require.NoError(t, err)
+		rec := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/api/v1/admin/accounts/data", bytes.NewReader(body))
+		req.Header.Set("Content-Type", "application/json")
+		router.ServeHTTP(rec, req)
+		return rec
+	}
+
+	rec = importWith("wrong passphrase")
+	require.Equal(t, http.StatusBadRequest, rec.Code)
+	require.Len(t, adminSvc.createdAccounts, 0)
+
+	rec = importWith("correct horse battery")
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Len(t, adminSvc.createdAccounts, 1)
+	require.Equal(t, "sk-secret", adminSvc.createdAccounts[0].Credentials["api_key"])
+}
@@ -0,0 +1,130 @@
+package admin
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AdminAPITokenHandler handles named, scoped admin API tokens
+type AdminAPITokenHandler struct {
+	tokenService *service.AdminAPITokenService
+}
+
+// NewAdminAPITokenHandler creates a new admin API token handler
+func NewAdminAPITokenHandler(tokenService *service.AdminAPITokenService) *AdminAPITokenHandler {
+	return &AdminAPITokenHandler{tokenService: tokenService}
+}
+
+// AdminAPITokenRequest represents the create/update token payload (update replaces all fields)
+type AdminAPITokenRequest struct {
+	Name        string     `json:"name" binding:"required,max=100"`
+	Scopes      []string   `json:"scopes" binding:"required,min=1"`
+	IPWhitelist []string   `json:"ip_whitelist"`
+	ExpiresAt   *time.Time `json:"expires_at"` // null = 永不过期
+}
+
+func (r *AdminAPITokenRequest) toInput() service.AdminAPITokenInput {
+	return service.AdminAPITokenInput{
+		Name:        r.Name,
+		Scopes:      r.Scopes,
+		IPWhitelist: r.IPWhitelist,
+		ExpiresAt:   r.ExpiresAt,
+	}
+}
+
+// Scopes returns the grantable scope resources
+// GET /api/v1/admin/settings/admin-tokens/scopes
+func (h *AdminAPITokenHandler) Scopes(c *gin.Context) {
+	response.Success(c, gin.H{
+		"resources": service.AdminScopeResources,
+	})
+}
+
+// List handles listing admin API tokens
+// GET /api/v1/admin/settings/admin-tokens
+func (h *AdminAPITokenHandler) List(c *gin.Context) {
+	tokens, err := h.tokenService.List(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminAPIToken, 0, len(tokens))
+	for i := range tokens {
+		out = append(out, *dto.AdminAPITokenFromService(&tokens[i]))
+	}
+	response.Success(c, out)
+}
+
+// Create handles creating an admin API token
+// POST /api/v1/admin/settings/admin-tokens
+func (h *AdminAPITokenHandler) Create(c *gin.Context) {
+	var req AdminAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	token, plaintext, err := h.tokenService.Create(c.Request.Context(), subject.UserID, req.toInput())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	response.Success(c, gin.H{
+		"token": dto.AdminAPITokenFromService(token),
+		"key":   plaintext, // 完整 key 只在创建时返回一次
+	})
+}
+
+// Update handles updating an admin API token
+// PUT /api/v1/admin/settings/admin-tokens/:id
+func (h *AdminAPITokenHandler) Update(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid token ID")
+		return
+	}
+
+	var req AdminAPITokenRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	token, err := h.tokenService.Update(c.Request.Context(), id, req.toInput())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.AdminAPITokenFromService(token))
+}
+
+// Delete handles revoking an admin API token
+// DELETE /api/v1/admin/settings/admin-tokens/:id
+func (h *AdminAPITokenHandler) Delete(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid token ID")
+		return
+	}
+
+	if err := h.tokenService.Delete(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Admin API token deleted"})
+}
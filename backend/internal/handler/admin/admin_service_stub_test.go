@@ -425,6 +425,25 @@ func (s *stubAdminService) AdminUpdateAPIKeyGroupID(ctx context.Context, keyID i
 	return nil, service.ErrAPIKeyNotFound
 }
 
+func (s *stubAdminService) AdminUpdateAPIKeyModelPolicy(ctx context.Context, keyID int64, update service.APIKeyModelPolicyUpdate) (*service.APIKey, error) {
+	for i := range s.apiKeys {
+		if s.apiKeys[i].ID == keyID {
+			k := s.apiKeys[i]
+			if update.AllowedModels != nil {
+				k.AllowedModels = *update.AllowedModels
+			}
+			if update.DeniedModels != nil {
+				k.DeniedModels = *update.DeniedModels
+			}
+			if update.ModelAliases != nil {
+				k.ModelAliases = *update.ModelAliases
+			}
+			return &k, nil
+		}
+	}
+	return nil, service.ErrAPIKeyNotFound
+}
+
 func (s *stubAdminService) ResetAccountQuota(ctx context.Context, id int64) error {
 	return nil
 }
@@ -22,12 +22,17 @@ func NewAdminAPIKeyHandler(adminService service.AdminService) *AdminAPIKeyHandle
 	}
 }
 
-// AdminUpdateAPIKeyGroupRequest represents the request to update an API key's group
+// AdminUpdateAPIKeyGroupRequest represents the request to update an API key's group and model policy
 type AdminUpdateAPIKeyGroupRequest struct {
 	GroupID *int64 `json:"group_id"` // nil=不修改, 0=解绑, >0=绑定到目标分组
+
+	// 模型策略：nil=不修改, 空数组/对象=清空
+	AllowedModels *[]string          `json:"allowed_models"`
+	DeniedModels  *[]string          `json:"denied_models"`
+	ModelAliases  *map[string]string `json:"model_aliases"`
 }
 
-// UpdateGroup handles updating an API key's group binding
+// UpdateGroup handles updating an API key's group binding and model policy
 // PUT /api/v1/admin/api-keys/:id
 func (h *AdminAPIKeyHandler) UpdateGroup(c *gin.Context) {
 	keyID, err := strconv.ParseInt(c.Param("id"), 10, 64)
@@ -48,6 +53,20 @@ func (h *AdminAPIKeyHandler) UpdateGroup(c *gin.Context) {
 		return
 	}
 
+	policyUpdate := service.APIKeyModelPolicyUpdate{
+		AllowedModels: req.AllowedModels,
+		DeniedModels:  req.DeniedModels,
+		ModelAliases:  req.ModelAliases,
+	}
+	if !policyUpdate.IsEmpty() {
+		apiKey, err := h.adminService.AdminUpdateAPIKeyModelPolicy(c.Request.Context(), keyID, policyUpdate)
+		if err != nil {
+			response.ErrorFrom(c, err)
+			return
+		}
+		result.APIKey = apiKey
+	}
+
 	resp := struct {
 		APIKey                 *dto.APIKey `json:"api_key"`
 		AutoGrantedGroupAccess bool        `json:"auto_granted_group_access"`
@@ -0,0 +1,175 @@
+package admin
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// auditLogExportMaxRows 单次导出的最大记录数
+const auditLogExportMaxRows = 50000
+
+// AuditLogHandler handles the admin audit log
+type AuditLogHandler struct {
+	auditService *service.AdminAuditService
+}
+
+// NewAuditLogHandler creates a new admin audit log handler
+func NewAuditLogHandler(auditService *service.AdminAuditService) *AuditLogHandler {
+	return &AuditLogHandler{auditService: auditService}
+}
+
+// parseFilter parses the shared list/export query parameters
+func (h *AuditLogHandler) parseFilter(c *gin.Context) (service.AdminAuditLogFilter, bool) {
+	filter := service.AdminAuditLogFilter{
+		ActorType:  c.Query("actor_type"),
+		TargetType: c.Query("target_type"),
+		TargetID:   c.Query("target_id"),
+		Method:     c.Query("method"),
+	}
+
+	if v := c.Query("actor_user_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil {
+			response.BadRequest(c, "Invalid actor_user_id")
+			return filter, false
+		}
+		filter.ActorUserID = &id
+	}
+
+	userTZ := c.Query("timezone")
+	if v := c.Query("start_date"); v != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", v, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid start_date format, use YYYY-MM-DD")
+			return filter, false
+		}
+		filter.StartTime = &t
+	}
+	if v := c.Query("end_date"); v != "" {
+		t, err := timezone.ParseInUserLocation("2006-01-02", v, userTZ)
+		if err != nil {
+			response.BadRequest(c, "Invalid end_date format, use YYYY-MM-DD")
+			return filter, false
+		}
+		// Set end time to end of day
+		t = t.Add(24*time.Hour - time.Nanosecond)
+		filter.EndTime = &t
+	}
+	return filter, true
+}
+
+// List handles listing audit logs with pagination
+// GET /api/v1/admin/audit-logs
+func (h *AuditLogHandler) List(c *gin.Context) {
+	filter, ok := h.parseFilter(c)
+	if !ok {
+		return
+	}
+	page, pageSize := response.ParsePagination(c)
+
+	logs, result, err := h.auditService.List(c.Request.Context(), pagination.PaginationParams{Page: page, PageSize: pageSize}, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.AdminAuditLog, 0, len(logs))
+	for i := range logs {
+		out = append(out, *dto.AdminAuditLogFromService(&logs[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// Export handles exporting audit logs as CSV or JSONL (oldest first, capped at auditLogExportMaxRows)
+// GET /api/v1/admin/audit-logs/export?format=csv|jsonl
+func (h *AuditLogHandler) Export(c *gin.Context) {
+	filter, ok := h.parseFilter(c)
+	if !ok {
+		return
+	}
+	format := c.DefaultQuery("format", "csv")
+	if format != "csv" && format != "jsonl" {
+		response.BadRequest(c, "Invalid format, use csv or jsonl")
+		return
+	}
+
+	var buf bytes.Buffer
+	var write func(*service.AdminAuditLog) error
+	if format == "jsonl" {
+		enc := json.NewEncoder(&buf)
+		write = func(l *service.AdminAuditLog) error {
+			return enc.Encode(dto.AdminAuditLogFromService(l))
+		}
+	} else {
+		writer := csv.NewWriter(&buf)
+		if err := writer.Write([]string{
+			"id", "created_at", "actor_type", "actor_user_id", "actor_token_id", "actor_token_name",
+			"method", "route", "path", "target_type", "target_id", "status_code", "client_ip", "request_body", "diff",
+		}); err != nil {
+			response.InternalError(c, "Failed to export audit logs: "+err.Error())
+			return
+		}
+		write = func(l *service.AdminAuditLog) error {
+			diff := ""
+			if len(l.Diff) > 0 {
+				raw, err := json.Marshal(dto.AdminAuditLogFromService(l).Diff)
+				if err != nil {
+					return err
+				}
+				diff = string(raw)
+			}
+			if err := writer.Write([]string{
+				strconv.FormatInt(l.ID, 10),
+				l.CreatedAt.Format(time.RFC3339),
+				l.ActorType,
+				formatOptionalID(l.ActorUserID),
+				formatOptionalID(l.ActorTokenID),
+				l.ActorTokenName,
+				l.Method,
+				l.Route,
+				l.Path,
+				l.TargetType,
+				l.TargetID,
+				strconv.Itoa(l.StatusCode),
+				l.ClientIP,
+				string(l.RequestBody),
+				diff,
+			}); err != nil {
+				return err
+			}
+			writer.Flush()
+			return writer.Error()
+		}
+	}
+
+	if err := h.auditService.Export(c.Request.Context(), filter, auditLogExportMaxRows, write); err != nil {
+		response.InternalError(c, "Failed to export audit logs: "+err.Error())
+		return
+	}
+
+	contentType := "text/csv"
+	if format == "jsonl" {
+		contentType = "application/x-ndjson"
+	}
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=audit_logs.%s", format))
+	c.Data(200, contentType, buf.Bytes())
+}
+
+func formatOptionalID(id *int64) string {
+	if id == nil {
+		return ""
+	}
+	return strconv.FormatInt(*id, 10)
+}
@@ -0,0 +1,51 @@
+package admin
+
+import (
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// CredentialEncryptionHandler handles upstream credential key rotation
+type CredentialEncryptionHandler struct {
+	credentialEncryptionService *service.CredentialEncryptionService
+}
+
+// NewCredentialEncryptionHandler creates a new credential encryption handler
+func NewCredentialEncryptionHandler(credentialEncryptionService *service.CredentialEncryptionService) *CredentialEncryptionHandler {
+	return &CredentialEncryptionHandler{credentialEncryptionService: credentialEncryptionService}
+}
+
+// GetStatus 查询凭证加密状态
+// GET /api/v1/admin/settings/credential-encryption
+func (h *CredentialEncryptionHandler) GetStatus(c *gin.Context) {
+	status, err := h.credentialEncryptionService.Status(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, status)
+}
+
+// Rotate 生成新密钥并重加密全部账号凭证
+// POST /api/v1/admin/settings/credential-encryption/rotate
+func (h *CredentialEncryptionHandler) Rotate(c *gin.Context) {
+	result, err := h.credentialEncryptionService.Rotate(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, result)
+}
+
+// Reencrypt 按当前密钥与开关重写全部账号凭证（加密历史明文、完成轮换或关闭后解密）
+// POST /api/v1/admin/settings/credential-encryption/reencrypt
+func (h *CredentialEncryptionHandler) Reencrypt(c *gin.Context) {
+	result, err := h.credentialEncryptionService.Reencrypt(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, result)
+}
@@ -60,6 +60,12 @@ type CreateGroupRequest struct {
 	// OpenAI Messages 调度配置（仅 openai 平台使用）
 	AllowMessagesDispatch bool   `json:"allow_messages_dispatch"`
 	DefaultMappedModel    string `json:"default_mapped_model"`
+	// 精确响应缓存配置（temperature=0 的确定性请求）
+	ResponseCacheEnabled    bool     `json:"response_cache_enabled"`
+	ResponseCacheTTLSeconds int      `json:"response_cache_ttl_seconds" binding:"omitempty,min=0"`
+	ResponseCachePriceRatio *float64 `json:"response_cache_price_ratio" binding:"omitempty,min=0"`
+	// 模型降级链
+	ModelFallbackChains []service.ModelFallbackChain `json:"model_fallback_chains"`
 	// 从指定分组复制账号（创建后自动绑定）
 	CopyAccountsFromGroupIDs []int64 `json:"copy_accounts_from_group_ids"`
 }
@@ -102,6 +108,12 @@ type UpdateGroupRequest struct {
 	// OpenAI Messages 调度配置（仅 openai 平台使用）
 	AllowMessagesDispatch *bool   `json:"allow_messages_dispatch"`
 	DefaultMappedModel    *string `json:"default_mapped_model"`
+	// 精确响应缓存配置（temperature=0 的确定性请求）
+	ResponseCacheEnabled    *bool    `json:"response_cache_enabled"`
+	ResponseCacheTTLSeconds *int     `json:"response_cache_ttl_seconds" binding:"omitempty,min=0"`
+	ResponseCachePriceRatio *float64 `json:"response_cache_price_ratio" binding:"omitempty,min=0"`
+	// 模型降级链（不传表示不修改，空数组表示清除）
+	ModelFallbackChains *[]service.ModelFallbackChain `json:"model_fallback_chains"`
 	// 从指定分组复制账号（同步操作：先清空当前分组的账号绑定，再绑定源分组的账号）
 	CopyAccountsFromGroupIDs []int64 `json:"copy_accounts_from_group_ids"`
 }
@@ -223,6 +235,10 @@ func (h *GroupHandler) Create(c *gin.Context) {
 		SoraStorageQuotaBytes:           req.SoraStorageQuotaBytes,
 		AllowMessagesDispatch:           req.AllowMessagesDispatch,
 		DefaultMappedModel:              req.DefaultMappedModel,
+		ResponseCacheEnabled:            req.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds:         req.ResponseCacheTTLSeconds,
+		ResponseCachePriceRatio:         req.ResponseCachePriceRatio,
+		ModelFallbackChains:             req.ModelFallbackChains,
 		CopyAccountsFromGroupIDs:        req.CopyAccountsFromGroupIDs,
 	})
 	if err != nil {
@@ -280,6 +296,10 @@ func (h *GroupHandler) Update(c *gin.Context) {
 		SoraStorageQuotaBytes:           req.SoraStorageQuotaBytes,
 		AllowMessagesDispatch:           req.AllowMessagesDispatch,
 		DefaultMappedModel:              req.DefaultMappedModel,
+		ResponseCacheEnabled:            req.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds:         req.ResponseCacheTTLSeconds,
+		ResponseCachePriceRatio:         req.ResponseCachePriceRatio,
+		ModelFallbackChains:             req.ModelFallbackChains,
 		CopyAccountsFromGroupIDs:        req.CopyAccountsFromGroupIDs,
 	})
 	if err != nil {
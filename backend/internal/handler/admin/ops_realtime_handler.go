@@ -156,6 +156,35 @@ func (h *OpsHandler) GetAccountAvailability(c *gin.Context) {
 	response.Success(c, payload)
 }
 
+// GetSchedulerMetrics returns account scheduler decision metrics and runtime health scores.
+// GET /api/v1/admin/ops/scheduler-metrics
+//
+// Query params:
+// - limit: optional, max accounts in the health list (default: 50)
+func (h *OpsHandler) GetSchedulerMetrics(c *gin.Context) {
+	if h.opsService == nil {
+		response.Error(c, http.StatusServiceUnavailable, "Ops service not available")
+		return
+	}
+
+	limit := 0
+	if v := strings.TrimSpace(c.Query("limit")); v != "" {
+		n, err := strconv.Atoi(v)
+		if err != nil || n <= 0 || n > 500 {
+			response.BadRequest(c, "Invalid limit")
+			return
+		}
+		limit = n
+	}
+
+	metrics, err := h.opsService.GetSchedulerMetrics(c.Request.Context(), limit)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, metrics)
+}
+
 func parseOpsRealtimeWindow(v string) (time.Duration, string, bool) {
 	switch strings.ToLower(strings.TrimSpace(v)) {
 	case "", "1min", "1m":
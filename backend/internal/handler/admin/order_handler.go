@@ -42,10 +42,11 @@ func (h *OrderHandler) List(c *gin.Context) {
 	}
 
 	orders, pagination, err := h.orderService.ListOrders(c.Request.Context(), page, pageSize, service.SubscriptionOrderFilters{
-		OrderNo: orderNo,
-		Status:  status,
-		UserID:  userID,
-		GroupID: groupID,
+		OrderNo:   orderNo,
+		OrderType: c.Query("order_type"),
+		Status:    status,
+		UserID:    userID,
+		GroupID:   groupID,
 	})
 	if err != nil {
 		response.ErrorFrom(c, err)
@@ -106,3 +107,26 @@ func (h *OrderHandler) Cancel(c *gin.Context) {
 	}
 	response.Success(c, dto.SubscriptionOrderFromServiceAdmin(order))
 }
+
+// Refund handles refunding a paid order through its payment provider
+// POST /api/v1/admin/orders/:id/refund
+func (h *OrderHandler) Refund(c *gin.Context) {
+	orderID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid order ID")
+		return
+	}
+	var req struct {
+		Reason string `json:"reason"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil && c.Request.ContentLength > 0 {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	order, err := h.orderService.Refund(c.Request.Context(), orderID, strings.TrimSpace(req.Reason))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.SubscriptionOrderFromServiceAdmin(order))
+}
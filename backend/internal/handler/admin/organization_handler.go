@@ -0,0 +1,160 @@
+package admin
+
+import (
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHandler handles admin organization management.
+type OrganizationHandler struct {
+	orgService *service.OrganizationService
+}
+
+// NewOrganizationHandler creates a new admin organization handler.
+func NewOrganizationHandler(orgService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+// UpdateOrganizationBalanceRequest represents the balance adjustment payload
+type UpdateOrganizationBalanceRequest struct {
+	Amount    float64 `json:"amount" binding:"min=0"`
+	Operation string  `json:"operation" binding:"required,oneof=set add subtract"`
+}
+
+// UpdateOrganizationStatusRequest represents the status update payload
+type UpdateOrganizationStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=active disabled"`
+}
+
+// List handles listing all organizations with pagination
+// GET /api/v1/admin/organizations
+func (h *OrganizationHandler) List(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	search := strings.TrimSpace(c.Query("search"))
+	if len(search) > 100 {
+		search = search[:100]
+	}
+
+	orgs, result, err := h.orgService.AdminList(c.Request.Context(), pagination.PaginationParams{Page: page, PageSize: pageSize}, service.OrganizationListFilters{
+		Search: search,
+		Status: c.Query("status"),
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.Organization, 0, len(orgs))
+	for i := range orgs {
+		out = append(out, *dto.OrganizationFromService(&orgs[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetByID handles getting an organization by ID
+// GET /api/v1/admin/organizations/:id
+func (h *OrganizationHandler) GetByID(c *gin.Context) {
+	orgID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID")
+		return
+	}
+	org, err := h.orgService.AdminGet(c.Request.Context(), orgID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.OrganizationFromService(org))
+}
+
+// ListMembers handles listing members of an organization
+// GET /api/v1/admin/organizations/:id/members
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	orgID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID")
+		return
+	}
+	members, err := h.orgService.AdminListMembers(c.Request.Context(), orgID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.OrganizationMember, 0, len(members))
+	for i := range members {
+		out = append(out, *dto.OrganizationMemberFromService(&members[i]))
+	}
+	response.Success(c, out)
+}
+
+// UpdateBalance handles adjusting the shared organization balance
+// POST /api/v1/admin/organizations/:id/balance
+func (h *OrganizationHandler) UpdateBalance(c *gin.Context) {
+	orgID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID")
+		return
+	}
+
+	var req UpdateOrganizationBalanceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	org, err := h.orgService.AdminUpdateBalance(c.Request.Context(), orgID, req.Amount, req.Operation)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.OrganizationFromService(org))
+}
+
+// UpdateStatus handles enabling/disabling an organization
+// PUT /api/v1/admin/organizations/:id/status
+func (h *OrganizationHandler) UpdateStatus(c *gin.Context) {
+	orgID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID")
+		return
+	}
+
+	var req UpdateOrganizationStatusRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	org, err := h.orgService.AdminUpdateStatus(c.Request.Context(), orgID, req.Status)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.OrganizationFromService(org))
+}
+
+// Usage handles the organization usage dashboard
+// GET /api/v1/admin/organizations/:id/usage?start_date=&end_date=&timezone=
+func (h *OrganizationHandler) Usage(c *gin.Context) {
+	orgID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID")
+		return
+	}
+
+	startTime, endTime := parseTimeRange(c)
+	summary, err := h.orgService.AdminGetUsageSummary(c.Request.Context(), orgID, startTime, endTime)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, summary)
+}
@@ -0,0 +1,186 @@
+package admin
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ProxyPoolHandler handles admin proxy pool management
+type ProxyPoolHandler struct {
+	proxyPoolService *service.ProxyPoolService
+}
+
+// NewProxyPoolHandler creates a new admin proxy pool handler
+func NewProxyPoolHandler(proxyPoolService *service.ProxyPoolService) *ProxyPoolHandler {
+	return &ProxyPoolHandler{proxyPoolService: proxyPoolService}
+}
+
+// CreateProxyPoolRequest represents create proxy pool request
+type CreateProxyPoolRequest struct {
+	Name        string  `json:"name" binding:"required"`
+	Description string  `json:"description"`
+	ProxyIDs    []int64 `json:"proxy_ids"`
+}
+
+// UpdateProxyPoolRequest represents update proxy pool request
+type UpdateProxyPoolRequest struct {
+	Name        *string  `json:"name"`
+	Description *string  `json:"description"`
+	Status      *string  `json:"status" binding:"omitempty,oneof=active disabled"`
+	ProxyIDs    *[]int64 `json:"proxy_ids"`
+}
+
+// AssignProxyPoolAccountsRequest represents bind/unbind accounts request
+type AssignProxyPoolAccountsRequest struct {
+	AccountIDs []int64 `json:"account_ids" binding:"required,min=1"`
+}
+
+// List handles listing proxy pools
+// GET /api/v1/admin/proxy-pools?search=
+func (h *ProxyPoolHandler) List(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	pools, result, err := h.proxyPoolService.List(c.Request.Context(), pagination.PaginationParams{Page: page, PageSize: pageSize}, c.Query("search"))
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	out := make([]dto.ProxyPool, 0, len(pools))
+	for i := range pools {
+		out = append(out, *dto.ProxyPoolFromService(&pools[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetByID handles getting a proxy pool with members
+// GET /api/v1/admin/proxy-pools/:id
+func (h *ProxyPoolHandler) GetByID(c *gin.Context) {
+	id, ok := parseProxyPoolID(c)
+	if !ok {
+		return
+	}
+	pool, err := h.proxyPoolService.Get(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.ProxyPoolFromService(pool))
+}
+
+// Create handles creating a proxy pool
+// POST /api/v1/admin/proxy-pools
+func (h *ProxyPoolHandler) Create(c *gin.Context) {
+	var req CreateProxyPoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	pool, err := h.proxyPoolService.Create(c.Request.Context(), service.CreateProxyPoolInput{
+		Name:        req.Name,
+		Description: req.Description,
+		ProxyIDs:    req.ProxyIDs,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.ProxyPoolFromService(pool))
+}
+
+// Update handles updating a proxy pool (name, status, members)
+// PUT /api/v1/admin/proxy-pools/:id
+func (h *ProxyPoolHandler) Update(c *gin.Context) {
+	id, ok := parseProxyPoolID(c)
+	if !ok {
+		return
+	}
+	var req UpdateProxyPoolRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	pool, err := h.proxyPoolService.Update(c.Request.Context(), id, service.UpdateProxyPoolInput{
+		Name:        req.Name,
+		Description: req.Description,
+		Status:      req.Status,
+		ProxyIDs:    req.ProxyIDs,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.ProxyPoolFromService(pool))
+}
+
+// Delete handles deleting a proxy pool; bound accounts are unbound
+// DELETE /api/v1/admin/proxy-pools/:id
+func (h *ProxyPoolHandler) Delete(c *gin.Context) {
+	id, ok := parseProxyPoolID(c)
+	if !ok {
+		return
+	}
+	if err := h.proxyPoolService.Delete(c.Request.Context(), id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Proxy pool deleted successfully"})
+}
+
+// Check handles running a health check immediately
+// POST /api/v1/admin/proxy-pools/:id/check
+func (h *ProxyPoolHandler) Check(c *gin.Context) {
+	id, ok := parseProxyPoolID(c)
+	if !ok {
+		return
+	}
+	pool, err := h.proxyPoolService.CheckNow(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.ProxyPoolFromService(pool))
+}
+
+// BindAccounts handles binding accounts to a proxy pool
+// POST /api/v1/admin/proxy-pools/:id/accounts
+func (h *ProxyPoolHandler) BindAccounts(c *gin.Context) {
+	id, ok := parseProxyPoolID(c)
+	if !ok {
+		return
+	}
+	h.assignAccounts(c, &id)
+}
+
+// UnbindAccounts handles removing the proxy pool from accounts
+// POST /api/v1/admin/proxy-pools/unbind-accounts
+func (h *ProxyPoolHandler) UnbindAccounts(c *gin.Context) {
+	h.assignAccounts(c, nil)
+}
+
+func (h *ProxyPoolHandler) assignAccounts(c *gin.Context, poolID *int64) {
+	var req AssignProxyPoolAccountsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	affected, err := h.proxyPoolService.AssignAccounts(c.Request.Context(), poolID, req.AccountIDs)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"affected": affected})
+}
+
+func parseProxyPoolID(c *gin.Context) (int64, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid proxy pool ID")
+		return 0, false
+	}
+	return id, true
+}
@@ -117,6 +117,28 @@ func (h *SettingHandler) GetSettings(c *gin.Context) {
 		XunhuPayNotifyURL:                    settings.XunhuPayNotifyURL,
 		XunhuPayReturnURL:                    settings.XunhuPayReturnURL,
 		XunhuPayPlugins:                      settings.XunhuPayPlugins,
+		StripeSecretKeyConfigured:            settings.StripeSecretKeyConfigured,
+		StripeWebhookSecretConfigured:        settings.StripeWebhookSecretConfigured,
+		StripeCurrency:                       settings.StripeCurrency,
+		StripeSuccessURL:                     settings.StripeSuccessURL,
+		StripeCancelURL:                      settings.StripeCancelURL,
+		AlipayAppID:                          settings.AlipayAppID,
+		AlipayPrivateKeyConfigured:           settings.AlipayPrivateKeyConfigured,
+		AlipayPublicKey:                      settings.AlipayPublicKey,
+		AlipayGateway:                        settings.AlipayGateway,
+		AlipayNotifyURL:                      settings.AlipayNotifyURL,
+		AlipayReturnURL:                      settings.AlipayReturnURL,
+		WeChatPayAppID:                       settings.WeChatPayAppID,
+		WeChatPayMchID:                       settings.WeChatPayMchID,
+		WeChatPaySerialNo:                    settings.WeChatPaySerialNo,
+		WeChatPayPrivateKeyConfigured:        settings.WeChatPayPrivateKeyConfigured,
+		WeChatPayAPIv3KeyConfigured:          settings.WeChatPayAPIv3KeyConfigured,
+		WeChatPayPlatformPublicKey:           settings.WeChatPayPlatformPublicKey,
+		WeChatPayNotifyURL:                   settings.WeChatPayNotifyURL,
+		BalanceTopUpEnabled:                  settings.BalanceTopUpEnabled,
+		BalanceTopUpMinAmount:                settings.BalanceTopUpMinAmount,
+		BalanceTopUpMaxAmount:                settings.BalanceTopUpMaxAmount,
+		BalanceTopUpRate:                     settings.BalanceTopUpRate,
 		DefaultConcurrency:                   settings.DefaultConcurrency,
 		DefaultBalance:                       settings.DefaultBalance,
 		DefaultSubscriptions:                 defaultSubscriptions,
@@ -189,6 +211,30 @@ type UpdateSettingsRequest struct {
 	SoraClientEnabled           bool                  `json:"sora_client_enabled"`
 	CustomMenuItems             *[]dto.CustomMenuItem `json:"custom_menu_items"`
 
+	// Stripe / 支付宝 / 微信支付（nil 表示保持原值；密钥留空表示不修改）
+	StripeSecretKey            string   `json:"stripe_secret_key"`
+	StripeWebhookSecret        string   `json:"stripe_webhook_secret"`
+	StripeCurrency             *string  `json:"stripe_currency"`
+	StripeSuccessURL           *string  `json:"stripe_success_url"`
+	StripeCancelURL            *string  `json:"stripe_cancel_url"`
+	AlipayAppID                *string  `json:"alipay_app_id"`
+	AlipayPrivateKey           string   `json:"alipay_private_key"`
+	AlipayPublicKey            *string  `json:"alipay_public_key"`
+	AlipayGateway              *string  `json:"alipay_gateway"`
+	AlipayNotifyURL            *string  `json:"alipay_notify_url"`
+	AlipayReturnURL            *string  `json:"alipay_return_url"`
+	WeChatPayAppID             *string  `json:"wechatpay_app_id"`
+	WeChatPayMchID             *string  `json:"wechatpay_mch_id"`
+	WeChatPaySerialNo          *string  `json:"wechatpay_serial_no"`
+	WeChatPayPrivateKey        string   `json:"wechatpay_private_key"`
+	WeChatPayAPIv3Key          string   `json:"wechatpay_api_v3_key"`
+	WeChatPayPlatformPublicKey *string  `json:"wechatpay_platform_public_key"`
+	WeChatPayNotifyURL         *string  `json:"wechatpay_notify_url"`
+	BalanceTopUpEnabled        *bool    `json:"balance_topup_enabled"`
+	BalanceTopUpMinAmount      *float64 `json:"balance_topup_min_amount"`
+	BalanceTopUpMaxAmount      *float64 `json:"balance_topup_max_amount"`
+	BalanceTopUpRate           *float64 `json:"balance_topup_rate"`
+
 	// 默认配置
 	DefaultConcurrency   int                              `json:"default_concurrency"`
 	DefaultBalance       float64                          `json:"default_balance"`
@@ -358,7 +404,7 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 	if paymentProvider == "" {
 		paymentProvider = service.PaymentProviderManual
 	}
-	if paymentProvider != service.PaymentProviderManual && paymentProvider != service.PaymentProviderXunhuPay {
+	if paymentProvider != service.PaymentProviderManual && !service.IsOnlinePaymentProvider(paymentProvider) {
 		response.BadRequest(c, "Payment provider invalid")
 		return
 	}
@@ -395,6 +441,139 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		}
 	}
 
+	stripeSecretKey := strings.TrimSpace(req.StripeSecretKey)
+	if stripeSecretKey == "" {
+		stripeSecretKey = previousSettings.StripeSecretKey
+	}
+	stripeWebhookSecret := strings.TrimSpace(req.StripeWebhookSecret)
+	if stripeWebhookSecret == "" {
+		stripeWebhookSecret = previousSettings.StripeWebhookSecret
+	}
+	stripeCurrency := strings.ToLower(trimmedOrPrevious(req.StripeCurrency, previousSettings.StripeCurrency))
+	stripeSuccessURL := trimmedOrPrevious(req.StripeSuccessURL, previousSettings.StripeSuccessURL)
+	stripeCancelURL := trimmedOrPrevious(req.StripeCancelURL, previousSettings.StripeCancelURL)
+	alipayAppID := trimmedOrPrevious(req.AlipayAppID, previousSettings.AlipayAppID)
+	alipayPrivateKey := strings.TrimSpace(req.AlipayPrivateKey)
+	if alipayPrivateKey == "" {
+		alipayPrivateKey = previousSettings.AlipayPrivateKey
+	}
+	alipayPublicKey := trimmedOrPrevious(req.AlipayPublicKey, previousSettings.AlipayPublicKey)
+	alipayGateway := trimmedOrPrevious(req.AlipayGateway, previousSettings.AlipayGateway)
+	alipayNotifyURL := trimmedOrPrevious(req.AlipayNotifyURL, previousSettings.AlipayNotifyURL)
+	alipayReturnURL := trimmedOrPrevious(req.AlipayReturnURL, previousSettings.AlipayReturnURL)
+	wechatAppID := trimmedOrPrevious(req.WeChatPayAppID, previousSettings.WeChatPayAppID)
+	wechatMchID := trimmedOrPrevious(req.WeChatPayMchID, previousSettings.WeChatPayMchID)
+	wechatSerialNo := trimmedOrPrevious(req.WeChatPaySerialNo, previousSettings.WeChatPaySerialNo)
+	wechatPrivateKey := strings.TrimSpace(req.WeChatPayPrivateKey)
+	if wechatPrivateKey == "" {
+		wechatPrivateKey = previousSettings.WeChatPayPrivateKey
+	}
+	wechatAPIv3Key := strings.TrimSpace(req.WeChatPayAPIv3Key)
+	if wechatAPIv3Key == "" {
+		wechatAPIv3Key = previousSettings.WeChatPayAPIv3Key
+	}
+	wechatPlatformPublicKey := trimmedOrPrevious(req.WeChatPayPlatformPublicKey, previousSettings.WeChatPayPlatformPublicKey)
+	wechatNotifyURL := trimmedOrPrevious(req.WeChatPayNotifyURL, previousSettings.WeChatPayNotifyURL)
+
+	switch paymentProvider {
+	case service.PaymentProviderStripe:
+		if stripeSecretKey == "" || stripeWebhookSecret == "" {
+			response.BadRequest(c, "Stripe Secret Key and Webhook Secret are required when enabled")
+			return
+		}
+		if err := config.ValidateAbsoluteHTTPURL(stripeSuccessURL); err != nil {
+			response.BadRequest(c, "Stripe Success URL must be an absolute http(s) URL")
+			return
+		}
+		if stripeCancelURL != "" {
+			if err := config.ValidateAbsoluteHTTPURL(stripeCancelURL); err != nil {
+				response.BadRequest(c, "Stripe Cancel URL must be an absolute http(s) URL")
+				return
+			}
+		}
+	case service.PaymentProviderAlipay:
+		if alipayAppID == "" || alipayPrivateKey == "" || alipayPublicKey == "" {
+			response.BadRequest(c, "Alipay App ID, private key and Alipay public key are required when enabled")
+			return
+		}
+		if err := config.ValidateAbsoluteHTTPURL(alipayGateway); err != nil {
+			response.BadRequest(c, "Alipay Gateway URL must be an absolute http(s) URL")
+			return
+		}
+		if err := config.ValidateAbsoluteHTTPURL(alipayNotifyURL); err != nil {
+			response.BadRequest(c, "Alipay Notify URL must be an absolute http(s) URL")
+			return
+		}
+		if alipayReturnURL != "" {
+			if err := config.ValidateAbsoluteHTTPURL(alipayReturnURL); err != nil {
+				response.BadRequest(c, "Alipay Return URL must be an absolute http(s) URL")
+				return
+			}
+		}
+	case service.PaymentProviderWeChatPay:
+		if wechatAppID == "" || wechatMchID == "" || wechatSerialNo == "" || wechatPrivateKey == "" || wechatPlatformPublicKey == "" {
+			response.BadRequest(c, "WeChat Pay App ID, merchant ID, certificate serial, private key and platform public key are required when enabled")
+			return
+		}
+		if len(wechatAPIv3Key) != 32 {
+			response.BadRequest(c, "WeChat Pay APIv3 key must be 32 characters")
+			return
+		}
+		if err := config.ValidateAbsoluteHTTPURL(wechatNotifyURL); err != nil {
+			response.BadRequest(c, "WeChat Pay Notify URL must be an absolute http(s) URL")
+			return
+		}
+	}
+	// 尝试构建一次支付渠道，提前发现密钥格式错误
+	if err := service.ValidatePaymentProviderSettings(paymentProvider, &service.SystemSettings{
+		StripeSecretKey:            stripeSecretKey,
+		StripeWebhookSecret:        stripeWebhookSecret,
+		StripeCurrency:             stripeCurrency,
+		StripeSuccessURL:           stripeSuccessURL,
+		StripeCancelURL:            stripeCancelURL,
+		AlipayAppID:                alipayAppID,
+		AlipayPrivateKey:           alipayPrivateKey,
+		AlipayPublicKey:            alipayPublicKey,
+		AlipayGateway:              alipayGateway,
+		AlipayNotifyURL:            alipayNotifyURL,
+		AlipayReturnURL:            alipayReturnURL,
+		WeChatPayAppID:             wechatAppID,
+		WeChatPayMchID:             wechatMchID,
+		WeChatPaySerialNo:          wechatSerialNo,
+		WeChatPayPrivateKey:        wechatPrivateKey,
+		WeChatPayAPIv3Key:          wechatAPIv3Key,
+		WeChatPayPlatformPublicKey: wechatPlatformPublicKey,
+		WeChatPayNotifyURL:         wechatNotifyURL,
+	}); err != nil {
+		response.BadRequest(c, "Payment provider keys invalid: "+err.Error())
+		return
+	}
+
+	topUpEnabled := previousSettings.BalanceTopUpEnabled
+	if req.BalanceTopUpEnabled != nil {
+		topUpEnabled = *req.BalanceTopUpEnabled
+	}
+	topUpMin := previousSettings.BalanceTopUpMinAmount
+	if req.BalanceTopUpMinAmount != nil {
+		topUpMin = *req.BalanceTopUpMinAmount
+	}
+	topUpMax := previousSettings.BalanceTopUpMaxAmount
+	if req.BalanceTopUpMaxAmount != nil {
+		topUpMax = *req.BalanceTopUpMaxAmount
+	}
+	topUpRate := previousSettings.BalanceTopUpRate
+	if req.BalanceTopUpRate != nil {
+		topUpRate = *req.BalanceTopUpRate
+	}
+	if topUpMin < 0 || topUpMax < 0 || (topUpMax > 0 && topUpMax < topUpMin) {
+		response.BadRequest(c, "Top-up amount range invalid")
+		return
+	}
+	if topUpRate <= 0 {
+		response.BadRequest(c, "Top-up rate must be greater than 0")
+		return
+	}
+
 	// 自定义菜单项验证
 	const (
 		maxCustomMenuItems    = 20
@@ -540,6 +719,28 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		XunhuPayNotifyURL:                xunhuNotifyURL,
 		XunhuPayReturnURL:                xunhuReturnURL,
 		XunhuPayPlugins:                  xunhuPlugins,
+		StripeSecretKey:                  stripeSecretKey,
+		StripeWebhookSecret:              stripeWebhookSecret,
+		StripeCurrency:                   stripeCurrency,
+		StripeSuccessURL:                 stripeSuccessURL,
+		StripeCancelURL:                  stripeCancelURL,
+		AlipayAppID:                      alipayAppID,
+		AlipayPrivateKey:                 alipayPrivateKey,
+		AlipayPublicKey:                  alipayPublicKey,
+		AlipayGateway:                    alipayGateway,
+		AlipayNotifyURL:                  alipayNotifyURL,
+		AlipayReturnURL:                  alipayReturnURL,
+		WeChatPayAppID:                   wechatAppID,
+		WeChatPayMchID:                   wechatMchID,
+		WeChatPaySerialNo:                wechatSerialNo,
+		WeChatPayPrivateKey:              wechatPrivateKey,
+		WeChatPayAPIv3Key:                wechatAPIv3Key,
+		WeChatPayPlatformPublicKey:       wechatPlatformPublicKey,
+		WeChatPayNotifyURL:               wechatNotifyURL,
+		BalanceTopUpEnabled:              topUpEnabled,
+		BalanceTopUpMinAmount:            topUpMin,
+		BalanceTopUpMaxAmount:            topUpMax,
+		BalanceTopUpRate:                 topUpRate,
 		SoraClientEnabled:                req.SoraClientEnabled,
 		CustomMenuItems:                  customMenuJSON,
 		DefaultConcurrency:               req.DefaultConcurrency,
@@ -644,6 +845,28 @@ func (h *SettingHandler) UpdateSettings(c *gin.Context) {
 		XunhuPayNotifyURL:                    updatedSettings.XunhuPayNotifyURL,
 		XunhuPayReturnURL:                    updatedSettings.XunhuPayReturnURL,
 		XunhuPayPlugins:                      updatedSettings.XunhuPayPlugins,
+		StripeSecretKeyConfigured:            updatedSettings.StripeSecretKeyConfigured,
+		StripeWebhookSecretConfigured:        updatedSettings.StripeWebhookSecretConfigured,
+		StripeCurrency:                       updatedSettings.StripeCurrency,
+		StripeSuccessURL:                     updatedSettings.StripeSuccessURL,
+		StripeCancelURL:                      updatedSettings.StripeCancelURL,
+		AlipayAppID:                          updatedSettings.AlipayAppID,
+		AlipayPrivateKeyConfigured:           updatedSettings.AlipayPrivateKeyConfigured,
+		AlipayPublicKey:                      updatedSettings.AlipayPublicKey,
+		AlipayGateway:                        updatedSettings.AlipayGateway,
+		AlipayNotifyURL:                      updatedSettings.AlipayNotifyURL,
+		AlipayReturnURL:                      updatedSettings.AlipayReturnURL,
+		WeChatPayAppID:                       updatedSettings.WeChatPayAppID,
+		WeChatPayMchID:                       updatedSettings.WeChatPayMchID,
+		WeChatPaySerialNo:                    updatedSettings.WeChatPaySerialNo,
+		WeChatPayPrivateKeyConfigured:        updatedSettings.WeChatPayPrivateKeyConfigured,
+		WeChatPayAPIv3KeyConfigured:          updatedSettings.WeChatPayAPIv3KeyConfigured,
+		WeChatPayPlatformPublicKey:           updatedSettings.WeChatPayPlatformPublicKey,
+		WeChatPayNotifyURL:                   updatedSettings.WeChatPayNotifyURL,
+		BalanceTopUpEnabled:                  updatedSettings.BalanceTopUpEnabled,
+		BalanceTopUpMinAmount:                updatedSettings.BalanceTopUpMinAmount,
+		BalanceTopUpMaxAmount:                updatedSettings.BalanceTopUpMaxAmount,
+		BalanceTopUpRate:                     updatedSettings.BalanceTopUpRate,
 		DefaultConcurrency:                   updatedSettings.DefaultConcurrency,
 		DefaultBalance:                       updatedSettings.DefaultBalance,
 		DefaultSubscriptions:                 updatedDefaultSubscriptions,
@@ -796,6 +1019,72 @@ func diffSettings(before *service.SystemSettings, after *service.SystemSettings,
 	if before.XunhuPayPlugins != after.XunhuPayPlugins {
 		changed = append(changed, "xunhupay_plugins")
 	}
+	if req.StripeSecretKey != "" {
+		changed = append(changed, "stripe_secret_key")
+	}
+	if req.StripeWebhookSecret != "" {
+		changed = append(changed, "stripe_webhook_secret")
+	}
+	if before.StripeCurrency != after.StripeCurrency {
+		changed = append(changed, "stripe_currency")
+	}
+	if before.StripeSuccessURL != after.StripeSuccessURL {
+		changed = append(changed, "stripe_success_url")
+	}
+	if before.StripeCancelURL != after.StripeCancelURL {
+		changed = append(changed, "stripe_cancel_url")
+	}
+	if before.AlipayAppID != after.AlipayAppID {
+		changed = append(changed, "alipay_app_id")
+	}
+	if req.AlipayPrivateKey != "" {
+		changed = append(changed, "alipay_private_key")
+	}
+	if before.AlipayPublicKey != after.AlipayPublicKey {
+		changed = append(changed, "alipay_public_key")
+	}
+	if before.AlipayGateway != after.AlipayGateway {
+		changed = append(changed, "alipay_gateway")
+	}
+	if before.AlipayNotifyURL != after.AlipayNotifyURL {
+		changed = append(changed, "alipay_notify_url")
+	}
+	if before.AlipayReturnURL != after.AlipayReturnURL {
+		changed = append(changed, "alipay_return_url")
+	}
+	if before.WeChatPayAppID != after.WeChatPayAppID {
+		changed = append(changed, "wechatpay_app_id")
+	}
+	if before.WeChatPayMchID != after.WeChatPayMchID {
+		changed = append(changed, "wechatpay_mch_id")
+	}
+	if before.WeChatPaySerialNo != after.WeChatPaySerialNo {
+		changed = append(changed, "wechatpay_serial_no")
+	}
+	if req.WeChatPayPrivateKey != "" {
+		changed = append(changed, "wechatpay_private_key")
+	}
+	if req.WeChatPayAPIv3Key != "" {
+		changed = append(changed, "wechatpay_api_v3_key")
+	}
+	if before.WeChatPayPlatformPublicKey != after.WeChatPayPlatformPublicKey {
+		changed = append(changed, "wechatpay_platform_public_key")
+	}
+	if before.WeChatPayNotifyURL != after.WeChatPayNotifyURL {
+		changed = append(changed, "wechatpay_notify_url")
+	}
+	if before.BalanceTopUpEnabled != after.BalanceTopUpEnabled {
+		changed = append(changed, "balance_topup_enabled")
+	}
+	if before.BalanceTopUpMinAmount != after.BalanceTopUpMinAmount {
+		changed = append(changed, "balance_topup_min_amount")
+	}
+	if before.BalanceTopUpMaxAmount != after.BalanceTopUpMaxAmount {
+		changed = append(changed, "balance_topup_max_amount")
+	}
+	if before.BalanceTopUpRate != after.BalanceTopUpRate {
+		changed = append(changed, "balance_topup_rate")
+	}
 	if before.DefaultConcurrency != after.DefaultConcurrency {
 		changed = append(changed, "default_concurrency")
 	}
@@ -1513,3 +1802,11 @@ func (h *SettingHandler) UpdateStreamTimeoutSettings(c *gin.Context) {
 		ThresholdWindowMinutes: updatedSettings.ThresholdWindowMinutes,
 	})
 }
+
+// trimmedOrPrevious 请求字段为 nil 时保持原值，否则返回去除首尾空白后的新值
+func trimmedOrPrevious(value *string, previous string) string {
+	if value == nil {
+		return previous
+	}
+	return strings.TrimSpace(*value)
+}
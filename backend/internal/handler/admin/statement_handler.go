@@ -0,0 +1,149 @@
+package admin
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatementHandler handles monthly billing statements for admins
+type StatementHandler struct {
+	statementService *service.StatementService
+}
+
+// NewStatementHandler creates a new admin statement handler
+func NewStatementHandler(statementService *service.StatementService) *StatementHandler {
+	return &StatementHandler{statementService: statementService}
+}
+
+// GenerateStatementsRequest represents the manual close payload
+type GenerateStatementsRequest struct {
+	Period string `json:"period" binding:"required"` // YYYY-MM，必须是已结束的月份
+	UserID *int64 `json:"user_id"`                   // 为空时结算该账期内所有待生成用户
+}
+
+// GenerateStatementsResponse is the result of a manual close
+type GenerateStatementsResponse struct {
+	Period    string         `json:"period"`
+	Created   int            `json:"created"`
+	Statement *dto.Statement `json:"statement,omitempty"`
+}
+
+// List handles listing statements
+// GET /api/v1/admin/statements?user_id=&period=
+func (h *StatementHandler) List(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	filter := service.StatementFilter{Period: strings.TrimSpace(c.Query("period"))}
+	if v := c.Query("user_id"); v != "" {
+		id, err := strconv.ParseInt(v, 10, 64)
+		if err != nil || id <= 0 {
+			response.BadRequest(c, "Invalid user_id")
+			return
+		}
+		filter.UserID = &id
+	}
+
+	statements, result, err := h.statementService.List(c.Request.Context(), pagination.PaginationParams{Page: page, PageSize: pageSize}, filter)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.Statement, 0, len(statements))
+	for i := range statements {
+		out = append(out, *dto.StatementFromService(&statements[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetByID handles getting a statement with line items
+// GET /api/v1/admin/statements/:id
+func (h *StatementHandler) GetByID(c *gin.Context) {
+	statement, ok := h.load(c)
+	if !ok {
+		return
+	}
+	response.Success(c, dto.StatementFromService(statement))
+}
+
+// HTML renders a statement as a printable HTML document
+// GET /api/v1/admin/statements/:id/html
+func (h *StatementHandler) HTML(c *gin.Context) {
+	statement, ok := h.load(c)
+	if !ok {
+		return
+	}
+	body, err := h.statementService.RenderHTML(c.Request.Context(), statement)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(body))
+}
+
+// Generate handles closing a period manually (all pending users, or a single user)
+// POST /api/v1/admin/statements/generate
+func (h *StatementHandler) Generate(c *gin.Context) {
+	var req GenerateStatementsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	period := strings.TrimSpace(req.Period)
+
+	if req.UserID != nil && *req.UserID > 0 {
+		statement, err := h.statementService.GenerateForUser(c.Request.Context(), *req.UserID, period)
+		if err != nil {
+			response.ErrorFrom(c, err)
+			return
+		}
+		response.Success(c, GenerateStatementsResponse{Period: period, Created: 1, Statement: dto.StatementFromService(statement)})
+		return
+	}
+
+	created, err := h.statementService.ClosePeriod(c.Request.Context(), period)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, GenerateStatementsResponse{Period: period, Created: created})
+}
+
+// SendEmail handles (re)sending a statement to the user's email
+// POST /api/v1/admin/statements/:id/send-email
+func (h *StatementHandler) SendEmail(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid statement ID")
+		return
+	}
+	statement, err := h.statementService.SendEmail(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	statement.Lines = nil
+	response.Success(c, dto.StatementFromService(statement))
+}
+
+func (h *StatementHandler) load(c *gin.Context) (*service.Statement, bool) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid statement ID")
+		return nil, false
+	}
+	statement, err := h.statementService.Get(c.Request.Context(), id)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return nil, false
+	}
+	return statement, true
+}
@@ -0,0 +1,199 @@
+package admin
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UsageExportRequest represents usage export parameters
+// (query string for streaming export, JSON body for async export jobs)
+type UsageExportRequest struct {
+	Format      string  `json:"format" form:"format"`
+	StartDate   string  `json:"start_date" form:"start_date"`
+	EndDate     string  `json:"end_date" form:"end_date"`
+	UserID      *int64  `json:"user_id" form:"user_id"`
+	APIKeyID    *int64  `json:"api_key_id" form:"api_key_id"`
+	AccountID   *int64  `json:"account_id" form:"account_id"`
+	GroupID     *int64  `json:"group_id" form:"group_id"`
+	Model       *string `json:"model" form:"model"`
+	RequestType *string `json:"request_type" form:"request_type"`
+	Stream      *bool   `json:"stream" form:"stream"`
+	BillingType *int8   `json:"billing_type" form:"billing_type"`
+	Timezone    string  `json:"timezone" form:"timezone"`
+}
+
+// toFilters converts the request to export filters; returns false after writing a 400 response
+func (req *UsageExportRequest) toFilters(c *gin.Context) (service.UsageExportFilters, bool) {
+	var filters service.UsageExportFilters
+	startDate := strings.TrimSpace(req.StartDate)
+	endDate := strings.TrimSpace(req.EndDate)
+	if startDate == "" || endDate == "" {
+		response.BadRequest(c, "start_date and end_date are required")
+		return filters, false
+	}
+	startTime, err := timezone.ParseInUserLocation("2006-01-02", startDate, req.Timezone)
+	if err != nil {
+		response.BadRequest(c, "Invalid start_date format, use YYYY-MM-DD")
+		return filters, false
+	}
+	endTime, err := timezone.ParseInUserLocation("2006-01-02", endDate, req.Timezone)
+	if err != nil {
+		response.BadRequest(c, "Invalid end_date format, use YYYY-MM-DD")
+		return filters, false
+	}
+	filters.StartTime = startTime
+	filters.EndTime = endTime.Add(24*time.Hour - time.Nanosecond)
+
+	positive := func(id *int64) *int64 {
+		if id == nil || *id <= 0 {
+			return nil
+		}
+		return id
+	}
+	filters.UserID = positive(req.UserID)
+	filters.APIKeyID = positive(req.APIKeyID)
+	filters.AccountID = positive(req.AccountID)
+	filters.GroupID = positive(req.GroupID)
+	if req.Model != nil {
+		if model := strings.TrimSpace(*req.Model); model != "" {
+			filters.Model = &model
+		}
+	}
+	if req.RequestType != nil && strings.TrimSpace(*req.RequestType) != "" {
+		parsed, err := service.ParseUsageRequestType(*req.RequestType)
+		if err != nil {
+			response.BadRequest(c, err.Error())
+			return filters, false
+		}
+		value := int16(parsed)
+		filters.RequestType = &value
+	} else {
+		filters.Stream = req.Stream
+	}
+	filters.BillingType = req.BillingType
+	return filters, true
+}
+
+func usageExportFormat(format string) string {
+	format = strings.ToLower(strings.TrimSpace(format))
+	if format == "" {
+		return service.UsageExportFormatCSV
+	}
+	return format
+}
+
+// Export streams usage records as CSV or JSONL (oldest first)
+// GET /api/v1/admin/usage/export?format=csv|jsonl&start_date=...&end_date=...
+func (h *UsageHandler) Export(c *gin.Context) {
+	var req UsageExportRequest
+	if err := c.ShouldBindQuery(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	filters, ok := req.toFilters(c)
+	if !ok {
+		return
+	}
+	format := usageExportFormat(req.Format)
+	if err := h.exportService.ValidateSyncExport(format, filters); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	contentType := "text/csv; charset=utf-8"
+	if format == service.UsageExportFormatJSONL {
+		contentType = "application/x-ndjson"
+	}
+	c.Header("Content-Type", contentType)
+	c.Header("Content-Disposition", fmt.Sprintf("attachment; filename=usage_%s_%s.%s",
+		filters.StartTime.Format("20060102"), filters.EndTime.Format("20060102"), format))
+	c.Header("Cache-Control", "no-store")
+	c.Status(200)
+
+	rows, err := h.exportService.Stream(c.Request.Context(), c.Writer, service.UsageExportScopeAdmin, format, filters)
+	if err != nil {
+		// 响应头已发送，只能中断连接并记录日志
+		logger.LegacyPrintf("handler.admin.usage", "[UsageExport] 流式导出中断: rows=%d err=%v", rows, err)
+		c.Abort()
+	}
+}
+
+// CreateExportJob handles creating an async usage export job
+// POST /api/v1/admin/usage/exports
+func (h *UsageHandler) CreateExportJob(c *gin.Context) {
+	subject, ok := middleware.GetAuthSubjectFromContext(c)
+	if !ok || subject.UserID <= 0 {
+		response.Unauthorized(c, "Unauthorized")
+		return
+	}
+	var req UsageExportRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+	filters, ok := req.toFilters(c)
+	if !ok {
+		return
+	}
+
+	job, err := h.exportService.CreateJob(c.Request.Context(), service.UsageExportScopeAdmin, usageExportFormat(req.Format), filters, subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.UsageExportJobFromService(job))
+}
+
+// ListExportJobs handles listing async usage export jobs
+// GET /api/v1/admin/usage/exports
+func (h *UsageHandler) ListExportJobs(c *gin.Context) {
+	page, pageSize := response.ParsePagination(c)
+	jobs, result, err := h.exportService.ListJobs(c.Request.Context(), pagination.PaginationParams{Page: page, PageSize: pageSize}, service.UsageExportScopeAdmin, 0)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	out := make([]dto.UsageExportJob, 0, len(jobs))
+	for i := range jobs {
+		out = append(out, *dto.UsageExportJobFromService(&jobs[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetExportJob handles getting an async usage export job (with a download link once finished)
+// GET /api/v1/admin/usage/exports/:id
+func (h *UsageHandler) GetExportJob(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid export job ID")
+		return
+	}
+	job, err := h.exportService.GetJob(c.Request.Context(), id, service.UsageExportScopeAdmin, 0)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	out := dto.UsageExportJobFromService(job)
+	if job.Status == service.UsageExportStatusSucceeded {
+		link, expiresAt, err := h.exportService.DownloadURL(c.Request.Context(), job)
+		if err != nil {
+			response.ErrorFrom(c, err)
+			return
+		}
+		out.DownloadURL = link
+		out.DownloadURLExpiresAt = expiresAt
+	}
+	response.Success(c, out)
+}
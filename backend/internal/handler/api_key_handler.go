@@ -42,6 +42,19 @@ type CreateAPIKeyRequest struct {
 	RateLimit5h *float64 `json:"rate_limit_5h"`
 	RateLimit1d *float64 `json:"rate_limit_1d"`
 	RateLimit7d *float64 `json:"rate_limit_7d"`
+
+	// Throughput limit fields (0 = unlimited)
+	RPMLimit       *int `json:"rpm_limit" binding:"omitempty,min=0"`       // 每分钟请求数上限
+	TPMLimit       *int `json:"tpm_limit" binding:"omitempty,min=0"`       // 每分钟 Token 数上限
+	MaxConcurrency *int `json:"max_concurrency" binding:"omitempty,min=0"` // 最大并发请求数
+
+	// Model policy fields (empty = no restriction)
+	AllowedModels []string          `json:"allowed_models"` // 模型白名单（支持 * 通配符）
+	DeniedModels  []string          `json:"denied_models"`  // 模型黑名单（支持 * 通配符）
+	ModelAliases  map[string]string `json:"model_aliases"`  // 模型别名 alias -> model
+
+	// 组织 Key：用量从组织共享余额扣费（nil = 个人 Key）
+	OrganizationID *int64 `json:"organization_id"`
 }
 
 // UpdateAPIKeyRequest represents the update API key request payload
@@ -60,6 +73,16 @@ type UpdateAPIKeyRequest struct {
 	RateLimit1d         *float64 `json:"rate_limit_1d"`
 	RateLimit7d         *float64 `json:"rate_limit_7d"`
 	ResetRateLimitUsage *bool    `json:"reset_rate_limit_usage"` // 重置限速用量
+
+	// Throughput limit fields (nil = no change, 0 = unlimited)
+	RPMLimit       *int `json:"rpm_limit" binding:"omitempty,min=0"`
+	TPMLimit       *int `json:"tpm_limit" binding:"omitempty,min=0"`
+	MaxConcurrency *int `json:"max_concurrency" binding:"omitempty,min=0"`
+
+	// Model policy fields (nil = no change, empty = clear)
+	AllowedModels *[]string          `json:"allowed_models"`
+	DeniedModels  *[]string          `json:"denied_models"`
+	ModelAliases  *map[string]string `json:"model_aliases"`
 }
 
 // List handles listing user's API keys with pagination
@@ -154,7 +177,12 @@ func (h *APIKeyHandler) Create(c *gin.Context) {
 		CustomKey:     req.CustomKey,
 		IPWhitelist:   req.IPWhitelist,
 		IPBlacklist:   req.IPBlacklist,
+		AllowedModels: req.AllowedModels,
+		DeniedModels:  req.DeniedModels,
+		ModelAliases:  req.ModelAliases,
 		ExpiresInDays: req.ExpiresInDays,
+
+		OrganizationID: req.OrganizationID,
 	}
 	if req.Quota != nil {
 		svcReq.Quota = *req.Quota
@@ -168,6 +196,15 @@ func (h *APIKeyHandler) Create(c *gin.Context) {
 	if req.RateLimit7d != nil {
 		svcReq.RateLimit7d = *req.RateLimit7d
 	}
+	if req.RPMLimit != nil {
+		svcReq.RPMLimit = *req.RPMLimit
+	}
+	if req.TPMLimit != nil {
+		svcReq.TPMLimit = *req.TPMLimit
+	}
+	if req.MaxConcurrency != nil {
+		svcReq.MaxConcurrency = *req.MaxConcurrency
+	}
 
 	executeUserIdempotentJSON(c, "user.api_keys.create", req, service.DefaultWriteIdempotencyTTL(), func(ctx context.Context) (any, error) {
 		key, err := h.apiKeyService.Create(ctx, subject.UserID, svcReq)
@@ -208,6 +245,12 @@ func (h *APIKeyHandler) Update(c *gin.Context) {
 		RateLimit1d:         req.RateLimit1d,
 		RateLimit7d:         req.RateLimit7d,
 		ResetRateLimitUsage: req.ResetRateLimitUsage,
+		RPMLimit:            req.RPMLimit,
+		TPMLimit:            req.TPMLimit,
+		MaxConcurrency:      req.MaxConcurrency,
+		AllowedModels:       req.AllowedModels,
+		DeniedModels:        req.DeniedModels,
+		ModelAliases:        req.ModelAliases,
 	}
 	if req.Name != "" {
 		svcReq.Name = &req.Name
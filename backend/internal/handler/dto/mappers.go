@@ -75,6 +75,7 @@ func APIKeyFromService(k *service.APIKey) *APIKey {
 		ID:            k.ID,
 		UserID:        k.UserID,
 		Key:           k.Key,
+		KeyPrefix:     k.KeyPrefix,
 		Name:          k.Name,
 		GroupID:       k.GroupID,
 		Status:        k.Status,
@@ -95,8 +96,18 @@ func APIKeyFromService(k *service.APIKey) *APIKey {
 		Window5hStart: k.Window5hStart,
 		Window1dStart: k.Window1dStart,
 		Window7dStart: k.Window7dStart,
+		AllowedModels: k.AllowedModels,
+		DeniedModels:  k.DeniedModels,
+		ModelAliases:  k.ModelAliases,
 		User:          UserFromServiceShallow(k.User),
 		Group:         GroupFromServiceShallow(k.Group),
+
+		RPMLimit:       k.RPMLimit,
+		TPMLimit:       k.TPMLimit,
+		MaxConcurrency: k.MaxConcurrency,
+
+		OrganizationID: k.OrganizationID,
+		Organization:   OrganizationFromService(k.Organization),
 	}
 }
 
@@ -122,14 +133,18 @@ func GroupFromServiceAdmin(g *service.Group) *AdminGroup {
 		return nil
 	}
 	out := &AdminGroup{
-		Group:                groupFromServiceBase(g),
-		ModelRouting:         g.ModelRouting,
-		ModelRoutingEnabled:  g.ModelRoutingEnabled,
-		MCPXMLInject:       g.MCPXMLInject,
-		DefaultMappedModel: g.DefaultMappedModel,
-		SupportedModelScopes:  g.SupportedModelScopes,
-		AccountCount:         g.AccountCount,
-		SortOrder:            g.SortOrder,
+		Group:                   groupFromServiceBase(g),
+		ModelRouting:            g.ModelRouting,
+		ModelRoutingEnabled:     g.ModelRoutingEnabled,
+		ModelFallbackChains:     g.ModelFallbackChains,
+		MCPXMLInject:            g.MCPXMLInject,
+		ResponseCacheEnabled:    g.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds: g.ResponseCacheTTLSeconds,
+		ResponseCachePriceRatio: g.ResponseCachePriceRatio,
+		DefaultMappedModel:      g.DefaultMappedModel,
+		SupportedModelScopes:    g.SupportedModelScopes,
+		AccountCount:            g.AccountCount,
+		SortOrder:               g.SortOrder,
 	}
 	if len(g.AccountGroups) > 0 {
 		out.AccountGroups = make([]AccountGroup, 0, len(g.AccountGroups))
@@ -188,6 +203,7 @@ func AccountFromServiceShallow(a *service.Account) *Account {
 		Credentials:             a.Credentials,
 		Extra:                   a.Extra,
 		ProxyID:                 a.ProxyID,
+		ProxyPoolID:             a.ProxyPoolID,
 		Concurrency:             a.Concurrency,
 		LoadFactor:              a.LoadFactor,
 		Priority:                a.Priority,
@@ -663,6 +679,7 @@ func SubscriptionOrderFromService(o *service.SubscriptionOrder) *SubscriptionOrd
 	return &SubscriptionOrder{
 		ID:                   o.ID,
 		OrderNo:              o.OrderNo,
+		OrderType:            o.OrderType,
 		UserID:               o.UserID,
 		GroupID:              o.GroupID,
 		SubscriptionID:       o.SubscriptionID,
@@ -676,8 +693,10 @@ func SubscriptionOrderFromService(o *service.SubscriptionOrder) *SubscriptionOrd
 		Amount:               o.Amount,
 		Currency:             o.Currency,
 		ValidityDays:         o.ValidityDays,
+		CreditAmount:         o.CreditAmount,
 		PaidAt:               o.PaidAt,
 		CanceledAt:           o.CanceledAt,
+		RefundedAt:           o.RefundedAt,
 		Notes:                o.Notes,
 		CreatedAt:            o.CreatedAt,
 		UpdatedAt:            o.UpdatedAt,
@@ -727,3 +746,333 @@ func PromoCodeUsageFromService(u *service.PromoCodeUsage) *PromoCodeUsage {
 		User:        UserFromServiceShallow(u.User),
 	}
 }
+
+func OrganizationFromService(o *service.Organization) *Organization {
+	if o == nil {
+		return nil
+	}
+	return &Organization{
+		ID:          o.ID,
+		Name:        o.Name,
+		OwnerUserID: o.OwnerUserID,
+		Balance:     o.Balance,
+		Status:      o.Status,
+		MemberCount: o.MemberCount,
+		CreatedAt:   o.CreatedAt,
+		UpdatedAt:   o.UpdatedAt,
+		Owner:       OrganizationUserFromService(o.Owner),
+	}
+}
+
+func OrganizationMemberFromService(m *service.OrganizationMember) *OrganizationMember {
+	if m == nil {
+		return nil
+	}
+	return &OrganizationMember{
+		ID:                  m.ID,
+		OrganizationID:      m.OrganizationID,
+		UserID:              m.UserID,
+		Role:                m.Role,
+		MonthlyLimitUSD:     m.MonthlyLimitUSD,
+		CurrentMonthlyUsage: m.CurrentMonthlyUsage(time.Now()),
+		CreatedAt:           m.CreatedAt,
+		UpdatedAt:           m.UpdatedAt,
+		User:                OrganizationUserFromService(m.User),
+		Organization:        OrganizationFromService(m.Organization),
+	}
+}
+
+func OrganizationUserFromService(u *service.User) *OrganizationUser {
+	if u == nil {
+		return nil
+	}
+	return &OrganizationUser{
+		ID:       u.ID,
+		Email:    u.Email,
+		Username: u.Username,
+	}
+}
+
+func OrganizationAPIKeyFromService(k *service.APIKey) *OrganizationAPIKey {
+	if k == nil {
+		return nil
+	}
+	return &OrganizationAPIKey{
+		ID:         k.ID,
+		UserID:     k.UserID,
+		Name:       k.Name,
+		MaskedKey:  maskAPIKey(k.KeyPrefix),
+		GroupID:    k.GroupID,
+		Status:     k.Status,
+		Quota:      k.Quota,
+		QuotaUsed:  k.QuotaUsed,
+		LastUsedAt: k.LastUsedAt,
+		CreatedAt:  k.CreatedAt,
+		User:       OrganizationUserFromService(k.User),
+		Group:      GroupFromServiceShallow(k.Group),
+	}
+}
+
+// maskAPIKey 以存储的展示前缀拼接掩码（库中不保存完整 key）
+func maskAPIKey(keyPrefix string) string {
+	if keyPrefix == "" {
+		return "****"
+	}
+	return keyPrefix + "..."
+}
+
+func AdminAPITokenFromService(t *service.AdminAPIToken) *AdminAPIToken {
+	if t == nil {
+		return nil
+	}
+	return &AdminAPIToken{
+		ID:          t.ID,
+		Name:        t.Name,
+		TokenPrefix: t.TokenPrefix,
+		Scopes:      t.Scopes,
+		IPWhitelist: t.IPWhitelist,
+		ExpiresAt:   t.ExpiresAt,
+		LastUsedAt:  t.LastUsedAt,
+		LastUsedIP:  t.LastUsedIP,
+		CreatedBy:   t.CreatedBy,
+		CreatedAt:   t.CreatedAt,
+		UpdatedAt:   t.UpdatedAt,
+	}
+}
+
+func AdminAuditLogFromService(l *service.AdminAuditLog) *AdminAuditLog {
+	if l == nil {
+		return nil
+	}
+	var diff map[string]AdminAuditChange
+	if len(l.Diff) > 0 {
+		diff = make(map[string]AdminAuditChange, len(l.Diff))
+		for path, change := range l.Diff {
+			diff[path] = AdminAuditChange{Before: change.Before, After: change.After}
+		}
+	}
+	return &AdminAuditLog{
+		ID:             l.ID,
+		ActorType:      l.ActorType,
+		ActorUserID:    l.ActorUserID,
+		ActorTokenID:   l.ActorTokenID,
+		ActorTokenName: l.ActorTokenName,
+		Method:         l.Method,
+		Route:          l.Route,
+		Path:           l.Path,
+		TargetType:     l.TargetType,
+		TargetID:       l.TargetID,
+		StatusCode:     l.StatusCode,
+		ClientIP:       l.ClientIP,
+		RequestBody:    l.RequestBody,
+		Diff:           diff,
+		CreatedAt:      l.CreatedAt,
+	}
+}
+
+func UserIdentityFromService(identity *service.UserIdentity, providerName string) *UserIdentity {
+	if identity == nil {
+		return nil
+	}
+	return &UserIdentity{
+		ID:           identity.ID,
+		Provider:     identity.Provider,
+		ProviderName: providerName,
+		Email:        identity.Email,
+		CreatedAt:    identity.CreatedAt,
+		LastLoginAt:  identity.LastLoginAt,
+	}
+}
+
+func UsageExportJobFromService(job *service.UsageExportJob) *UsageExportJob {
+	if job == nil {
+		return nil
+	}
+	return &UsageExportJob{
+		ID:     job.ID,
+		Scope:  job.Scope,
+		Format: job.Format,
+		Status: job.Status,
+		Filters: UsageExportFilters{
+			StartTime:   job.Filters.StartTime,
+			EndTime:     job.Filters.EndTime,
+			UserID:      job.Filters.UserID,
+			APIKeyID:    job.Filters.APIKeyID,
+			AccountID:   job.Filters.AccountID,
+			GroupID:     job.Filters.GroupID,
+			Model:       job.Filters.Model,
+			RequestType: requestTypeStringPtr(job.Filters.RequestType),
+			Stream:      job.Filters.Stream,
+			BillingType: job.Filters.BillingType,
+		},
+		CreatedBy:    job.CreatedBy,
+		RowCount:     job.RowCount,
+		FileSize:     job.FileSize,
+		Storage:      job.Storage,
+		ErrorMessage: job.ErrorMsg,
+		StartedAt:    job.StartedAt,
+		FinishedAt:   job.FinishedAt,
+		ExpiresAt:    job.ExpiresAt,
+		CreatedAt:    job.CreatedAt,
+	}
+}
+
+// StatementFromService converts a statement; lines are included when loaded.
+func StatementFromService(st *service.Statement) *Statement {
+	if st == nil {
+		return nil
+	}
+	out := &Statement{
+		ID:                    st.ID,
+		UserID:                st.UserID,
+		Period:                st.Period,
+		PeriodStart:           st.PeriodStart,
+		PeriodEnd:             st.PeriodEnd,
+		Timezone:              st.Timezone,
+		Currency:              st.Currency,
+		UserEmail:             st.UserEmail,
+		Username:              st.Username,
+		RequestCount:          st.RequestCount,
+		UsageTotalCost:        st.UsageTotalCost,
+		UsageBalanceCost:      st.UsageBalanceCost,
+		UsageSubscriptionCost: st.UsageSubscriptionCost,
+		SubscriptionFees:      st.SubscriptionFees,
+		TopUps:                st.TopUps,
+		Refunds:               st.Refunds,
+		Credits:               st.Credits,
+		NetPayments:           st.NetPayments(),
+		LineCount:             st.LineCount,
+		EmailedAt:             st.EmailedAt,
+		CreatedAt:             st.CreatedAt,
+	}
+	if len(st.Lines) > 0 {
+		out.Lines = make([]StatementLine, 0, len(st.Lines))
+		for _, line := range st.Lines {
+			out.Lines = append(out.Lines, StatementLine{
+				LineNo:              line.LineNo,
+				LineType:            line.LineType,
+				Description:         line.Description,
+				GroupID:             line.GroupID,
+				GroupName:           line.GroupName,
+				Model:               line.Model,
+				BillingType:         line.BillingType,
+				Reference:           line.Reference,
+				RequestCount:        line.RequestCount,
+				InputTokens:         line.InputTokens,
+				OutputTokens:        line.OutputTokens,
+				CacheCreationTokens: line.CacheCreationTokens,
+				CacheReadTokens:     line.CacheReadTokens,
+				ImageCount:          line.ImageCount,
+				VideoCount:          line.VideoCount,
+				TotalCost:           line.TotalCost,
+				Amount:              line.Amount,
+				OccurredAt:          line.OccurredAt,
+			})
+		}
+	}
+	return out
+}
+
+func ProxyPoolFromService(p *service.ProxyPool) *ProxyPool {
+	if p == nil {
+		return nil
+	}
+	out := &ProxyPool{
+		ID:           p.ID,
+		Name:         p.Name,
+		Description:  p.Description,
+		Status:       p.Status,
+		AccountCount: p.AccountCount,
+		MemberCount:  len(p.Members),
+		Members:      make([]ProxyPoolMember, 0, len(p.Members)),
+		CreatedAt:    p.CreatedAt,
+		UpdatedAt:    p.UpdatedAt,
+	}
+	for i := range p.Members {
+		m := &p.Members[i]
+		if m.Healthy {
+			out.HealthyCount++
+		}
+		out.Members = append(out.Members, ProxyPoolMember{
+			ProxyID:       m.ProxyID,
+			Proxy:         ProxyFromService(m.Proxy),
+			Healthy:       m.Healthy,
+			FailCount:     m.FailCount,
+			LatencyMs:     m.LatencyMs,
+			LastError:     m.LastError,
+			LastCheckedAt: m.LastCheckedAt,
+		})
+	}
+	return out
+}
+
+// UserWebhookFromService converts a webhook; the full secret is only included when revealSecret is set.
+func UserWebhookFromService(w *service.UserWebhook, revealSecret bool) *UserWebhook {
+	if w == nil {
+		return nil
+	}
+	out := &UserWebhook{
+		ID:                    w.ID,
+		APIKeyID:              w.APIKeyID,
+		Name:                  w.Name,
+		URL:                   w.URL,
+		Events:                w.Events,
+		Enabled:               w.Enabled,
+		BalanceThreshold:      w.BalanceThreshold,
+		UsageThresholdPercent: w.UsagePercentThreshold,
+		ExpiryNoticeDays:      w.ExpiryNoticeDays,
+		LastDeliveryAt:        w.LastDeliveryAt,
+		LastDeliveryStatus:    w.LastDeliveryStatus,
+		CreatedAt:             w.CreatedAt,
+		UpdatedAt:             w.UpdatedAt,
+	}
+	if out.Events == nil {
+		out.Events = []string{}
+	}
+	if revealSecret {
+		out.Secret = w.Secret
+	}
+	if n := len(w.Secret); n > 4 {
+		out.SecretHint = "****" + w.Secret[n-4:]
+	}
+	return out
+}
+
+func UserWebhookDeliveryFromService(d *service.UserWebhookDelivery) *UserWebhookDelivery {
+	if d == nil {
+		return nil
+	}
+	out := &UserWebhookDelivery{
+		ID:             d.ID,
+		WebhookID:      d.WebhookID,
+		EventID:        d.EventID,
+		EventType:      d.EventType,
+		Payload:        d.Payload,
+		Status:         d.Status,
+		Attempts:       d.Attempts,
+		MaxAttempts:    d.MaxAttempts,
+		ResponseStatus: d.ResponseStatus,
+		LastError:      d.LastError,
+		DurationMs:     d.DurationMs,
+		DeliveredAt:    d.DeliveredAt,
+		CreatedAt:      d.CreatedAt,
+	}
+	if d.Status == service.UserWebhookDeliveryPending {
+		next := d.NextAttemptAt
+		out.NextAttemptAt = &next
+	}
+	return out
+}
+
+func UserNotificationSettingsFromService(s *service.UserNotificationSettings) *UserNotificationSettings {
+	if s == nil {
+		return nil
+	}
+	return &UserNotificationSettings{
+		Enabled:                  s.Enabled,
+		BalanceThreshold:         s.BalanceThreshold,
+		APIKeyQuotaPercent:       s.APIKeyQuotaPercent,
+		SubscriptionUsagePercent: s.SubscriptionUsagePercent,
+		ExpiryNoticeDays:         s.ExpiryNoticeDays,
+	}
+}
@@ -64,6 +64,29 @@ type SystemSettings struct {
 	SoraClientEnabled           bool             `json:"sora_client_enabled"`
 	CustomMenuItems             []CustomMenuItem `json:"custom_menu_items"`
 
+	StripeSecretKeyConfigured     bool    `json:"stripe_secret_key_configured"`
+	StripeWebhookSecretConfigured bool    `json:"stripe_webhook_secret_configured"`
+	StripeCurrency                string  `json:"stripe_currency"`
+	StripeSuccessURL              string  `json:"stripe_success_url"`
+	StripeCancelURL               string  `json:"stripe_cancel_url"`
+	AlipayAppID                   string  `json:"alipay_app_id"`
+	AlipayPrivateKeyConfigured    bool    `json:"alipay_private_key_configured"`
+	AlipayPublicKey               string  `json:"alipay_public_key"`
+	AlipayGateway                 string  `json:"alipay_gateway"`
+	AlipayNotifyURL               string  `json:"alipay_notify_url"`
+	AlipayReturnURL               string  `json:"alipay_return_url"`
+	WeChatPayAppID                string  `json:"wechatpay_app_id"`
+	WeChatPayMchID                string  `json:"wechatpay_mch_id"`
+	WeChatPaySerialNo             string  `json:"wechatpay_serial_no"`
+	WeChatPayPrivateKeyConfigured bool    `json:"wechatpay_private_key_configured"`
+	WeChatPayAPIv3KeyConfigured   bool    `json:"wechatpay_api_v3_key_configured"`
+	WeChatPayPlatformPublicKey    string  `json:"wechatpay_platform_public_key"`
+	WeChatPayNotifyURL            string  `json:"wechatpay_notify_url"`
+	BalanceTopUpEnabled           bool    `json:"balance_topup_enabled"`
+	BalanceTopUpMinAmount         float64 `json:"balance_topup_min_amount"`
+	BalanceTopUpMaxAmount         float64 `json:"balance_topup_max_amount"`
+	BalanceTopUpRate              float64 `json:"balance_topup_rate"`
+
 	DefaultConcurrency   int                          `json:"default_concurrency"`
 	DefaultBalance       float64                      `json:"default_balance"`
 	DefaultSubscriptions []DefaultSubscriptionSetting `json:"default_subscriptions"`
@@ -119,10 +142,18 @@ type PublicSettings struct {
 	PurchaseInstructions             string           `json:"purchase_instructions"`
 	CustomMenuItems                  []CustomMenuItem `json:"custom_menu_items"`
 	LinuxDoOAuthEnabled              bool             `json:"linuxdo_oauth_enabled"`
+	SSOProviders                     []SSOProvider    `json:"sso_providers"`
 	SoraClientEnabled                bool             `json:"sora_client_enabled"`
 	Version                          string           `json:"version"`
 }
 
+// SSOProvider 登录页展示的 SSO 提供方
+type SSOProvider struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
 // SoraS3Settings Sora S3 存储配置 DTO（响应用，不含敏感字段）
 type SoraS3Settings struct {
 	Enabled                   bool   `json:"enabled"`
@@ -1,6 +1,11 @@
 package dto
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
 
 type User struct {
 	ID            int64     `json:"id"`
@@ -34,7 +39,8 @@ type AdminUser struct {
 type APIKey struct {
 	ID          int64      `json:"id"`
 	UserID      int64      `json:"user_id"`
-	Key         string     `json:"key"`
+	Key         string     `json:"key,omitempty"` // 完整 key 只在创建时返回一次
+	KeyPrefix   string     `json:"key_prefix"`
 	Name        string     `json:"name"`
 	GroupID     *int64     `json:"group_id"`
 	Status      string     `json:"status"`
@@ -58,8 +64,22 @@ type APIKey struct {
 	Window1dStart *time.Time `json:"window_1d_start"`
 	Window7dStart *time.Time `json:"window_7d_start"`
 
+	// Throughput limit fields (0 = unlimited)
+	RPMLimit       int `json:"rpm_limit"`
+	TPMLimit       int `json:"tpm_limit"`
+	MaxConcurrency int `json:"max_concurrency"`
+
+	// Model policy fields
+	AllowedModels []string          `json:"allowed_models"`
+	DeniedModels  []string          `json:"denied_models"`
+	ModelAliases  map[string]string `json:"model_aliases"`
+
 	User  *User  `json:"user,omitempty"`
 	Group *Group `json:"group,omitempty"`
+
+	// Organization fields (nil = personal key)
+	OrganizationID *int64        `json:"organization_id"`
+	Organization   *Organization `json:"organization,omitempty"`
 }
 
 type Group struct {
@@ -116,9 +136,17 @@ type AdminGroup struct {
 	ModelRouting        map[string][]int64 `json:"model_routing"`
 	ModelRoutingEnabled bool               `json:"model_routing_enabled"`
 
+	// 模型降级链（所有账号限流/过载/prompt 过长时按序切换模型）
+	ModelFallbackChains []service.ModelFallbackChain `json:"model_fallback_chains"`
+
 	// MCP XML 协议注入（仅 antigravity 平台使用）
 	MCPXMLInject bool `json:"mcp_xml_inject"`
 
+	// 精确响应缓存配置（命中按比例计费）
+	ResponseCacheEnabled    bool    `json:"response_cache_enabled"`
+	ResponseCacheTTLSeconds int     `json:"response_cache_ttl_seconds"`
+	ResponseCachePriceRatio float64 `json:"response_cache_price_ratio"`
+
 	// OpenAI Messages 调度配置（仅 openai 平台使用）
 	DefaultMappedModel string `json:"default_mapped_model"`
 
@@ -140,6 +168,7 @@ type Account struct {
 	Credentials        map[string]any `json:"credentials"`
 	Extra              map[string]any `json:"extra"`
 	ProxyID            *int64         `json:"proxy_id"`
+	ProxyPoolID        *int64         `json:"proxy_pool_id,omitempty"`
 	Concurrency        int            `json:"concurrency"`
 	LoadFactor         *int           `json:"load_factor,omitempty"`
 	Priority           int            `json:"priority"`
@@ -410,6 +439,28 @@ type UsageCleanupTask struct {
 	UpdatedAt    time.Time           `json:"updated_at"`
 }
 
+// UsageExportFilters shares the usage cleanup filter shape.
+type UsageExportFilters = UsageCleanupFilters
+
+type UsageExportJob struct {
+	ID                   int64              `json:"id"`
+	Scope                string             `json:"scope"`
+	Format               string             `json:"format"`
+	Status               string             `json:"status"`
+	Filters              UsageExportFilters `json:"filters"`
+	CreatedBy            int64              `json:"created_by"`
+	RowCount             int64              `json:"row_count"`
+	FileSize             int64              `json:"file_size"`
+	Storage              string             `json:"storage,omitempty"`
+	ErrorMessage         *string            `json:"error_message,omitempty"`
+	StartedAt            *time.Time         `json:"started_at,omitempty"`
+	FinishedAt           *time.Time         `json:"finished_at,omitempty"`
+	ExpiresAt            *time.Time         `json:"expires_at,omitempty"`
+	CreatedAt            time.Time          `json:"created_at"`
+	DownloadURL          string             `json:"download_url,omitempty"`
+	DownloadURLExpiresAt *time.Time         `json:"download_url_expires_at,omitempty"`
+}
+
 // AccountSummary is a minimal account info for usage log display.
 // It intentionally excludes sensitive fields like Credentials, Proxy, etc.
 type AccountSummary struct {
@@ -463,8 +514,9 @@ type AdminUserSubscription struct {
 type SubscriptionOrder struct {
 	ID                   int64      `json:"id"`
 	OrderNo              string     `json:"order_no"`
+	OrderType            string     `json:"order_type"`
 	UserID               int64      `json:"user_id"`
-	GroupID              int64      `json:"group_id"`
+	GroupID              *int64     `json:"group_id"`
 	SubscriptionID       *int64     `json:"subscription_id,omitempty"`
 	PaymentProvider      string     `json:"payment_provider"`
 	PaymentURL           string     `json:"payment_url"`
@@ -476,8 +528,10 @@ type SubscriptionOrder struct {
 	Amount               float64    `json:"amount"`
 	Currency             string     `json:"currency"`
 	ValidityDays         int        `json:"validity_days"`
+	CreditAmount         float64    `json:"credit_amount"`
 	PaidAt               *time.Time `json:"paid_at,omitempty"`
 	CanceledAt           *time.Time `json:"canceled_at,omitempty"`
+	RefundedAt           *time.Time `json:"refunded_at,omitempty"`
 	Notes                string     `json:"notes"`
 	CreatedAt            time.Time  `json:"created_at"`
 	UpdatedAt            time.Time  `json:"updated_at"`
@@ -486,6 +540,15 @@ type SubscriptionOrder struct {
 	Subscription *UserSubscription `json:"subscription,omitempty"`
 }
 
+// TopUpConfig describes the balance top-up options.
+type TopUpConfig struct {
+	Enabled   bool    `json:"enabled"`
+	MinAmount float64 `json:"min_amount"`
+	MaxAmount float64 `json:"max_amount"`
+	Rate      float64 `json:"rate"`
+	Currency  string  `json:"currency"`
+}
+
 // AdminSubscriptionOrder adds user info for admin views.
 type AdminSubscriptionOrder struct {
 	SubscriptionOrder
@@ -526,3 +589,221 @@ type PromoCodeUsage struct {
 
 	User *User `json:"user,omitempty"`
 }
+
+// Organization 组织（团队）
+type Organization struct {
+	ID          int64     `json:"id"`
+	Name        string    `json:"name"`
+	OwnerUserID int64     `json:"owner_user_id"`
+	Balance     float64   `json:"balance"`
+	Status      string    `json:"status"`
+	MemberCount int       `json:"member_count,omitempty"`
+	CreatedAt   time.Time `json:"created_at"`
+	UpdatedAt   time.Time `json:"updated_at"`
+
+	Owner *OrganizationUser `json:"owner,omitempty"`
+}
+
+// OrganizationUser 组织内可见的用户信息（不含余额等个人字段）
+type OrganizationUser struct {
+	ID       int64  `json:"id"`
+	Email    string `json:"email"`
+	Username string `json:"username"`
+}
+
+// OrganizationMember 组织成员
+type OrganizationMember struct {
+	ID                  int64     `json:"id"`
+	OrganizationID      int64     `json:"organization_id"`
+	UserID              int64     `json:"user_id"`
+	Role                string    `json:"role"`
+	MonthlyLimitUSD     float64   `json:"monthly_limit_usd"`     // 0 = 不限制
+	CurrentMonthlyUsage float64   `json:"current_monthly_usage"` // 当前自然月消费
+	CreatedAt           time.Time `json:"created_at"`
+	UpdatedAt           time.Time `json:"updated_at"`
+
+	User         *OrganizationUser `json:"user,omitempty"`
+	Organization *Organization     `json:"organization,omitempty"`
+}
+
+// OrganizationAPIKey 组织 Key（Key 明文仅对创建者可见，这里只返回脱敏值）
+type OrganizationAPIKey struct {
+	ID         int64             `json:"id"`
+	UserID     int64             `json:"user_id"`
+	Name       string            `json:"name"`
+	MaskedKey  string            `json:"masked_key"`
+	GroupID    *int64            `json:"group_id"`
+	Status     string            `json:"status"`
+	Quota      float64           `json:"quota"`
+	QuotaUsed  float64           `json:"quota_used"`
+	LastUsedAt *time.Time        `json:"last_used_at"`
+	CreatedAt  time.Time         `json:"created_at"`
+	User       *OrganizationUser `json:"user,omitempty"`
+	Group      *Group            `json:"group,omitempty"`
+}
+
+// AdminAPIToken 管理员 API Token（不含明文与哈希）
+type AdminAPIToken struct {
+	ID          int64      `json:"id"`
+	Name        string     `json:"name"`
+	TokenPrefix string     `json:"token_prefix"`
+	Scopes      []string   `json:"scopes"`
+	IPWhitelist []string   `json:"ip_whitelist"`
+	ExpiresAt   *time.Time `json:"expires_at"`
+	LastUsedAt  *time.Time `json:"last_used_at"`
+	LastUsedIP  string     `json:"last_used_ip"`
+	CreatedBy   *int64     `json:"created_by"`
+	CreatedAt   time.Time  `json:"created_at"`
+	UpdatedAt   time.Time  `json:"updated_at"`
+}
+
+// AdminAuditLog 管理员写操作审计记录（请求体与 diff 已脱敏）
+type AdminAuditLog struct {
+	ID             int64                       `json:"id"`
+	ActorType      string                      `json:"actor_type"`
+	ActorUserID    *int64                      `json:"actor_user_id"`
+	ActorTokenID   *int64                      `json:"actor_token_id"`
+	ActorTokenName string                      `json:"actor_token_name"`
+	Method         string                      `json:"method"`
+	Route          string                      `json:"route"`
+	Path           string                      `json:"path"`
+	TargetType     string                      `json:"target_type"`
+	TargetID       string                      `json:"target_id"`
+	StatusCode     int                         `json:"status_code"`
+	ClientIP       string                      `json:"client_ip"`
+	RequestBody    json.RawMessage             `json:"request_body"`
+	Diff           map[string]AdminAuditChange `json:"diff"`
+	CreatedAt      time.Time                   `json:"created_at"`
+}
+
+// AdminAuditChange 单个字段的变更（敏感字段值为 "[REDACTED]"）
+type AdminAuditChange struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// UserIdentity 用户绑定的第三方 SSO 身份
+type UserIdentity struct {
+	ID           int64      `json:"id"`
+	Provider     string     `json:"provider"`
+	ProviderName string     `json:"provider_name"`
+	Email        string     `json:"email"`
+	CreatedAt    time.Time  `json:"created_at"`
+	LastLoginAt  *time.Time `json:"last_login_at"`
+}
+
+// Statement is a closed monthly billing statement. Lines are only included in detail responses.
+type Statement struct {
+	ID                    int64           `json:"id"`
+	UserID                int64           `json:"user_id"`
+	Period                string          `json:"period"`
+	PeriodStart           time.Time       `json:"period_start"`
+	PeriodEnd             time.Time       `json:"period_end"`
+	Timezone              string          `json:"timezone"`
+	Currency              string          `json:"currency"`
+	UserEmail             string          `json:"user_email"`
+	Username              string          `json:"username"`
+	RequestCount          int64           `json:"request_count"`
+	UsageTotalCost        float64         `json:"usage_total_cost"`
+	UsageBalanceCost      float64         `json:"usage_balance_cost"`
+	UsageSubscriptionCost float64         `json:"usage_subscription_cost"`
+	SubscriptionFees      float64         `json:"subscription_fees"`
+	TopUps                float64         `json:"top_ups"`
+	Refunds               float64         `json:"refunds"`
+	Credits               float64         `json:"credits"`
+	NetPayments           float64         `json:"net_payments"`
+	LineCount             int             `json:"line_count"`
+	EmailedAt             *time.Time      `json:"emailed_at,omitempty"`
+	CreatedAt             time.Time       `json:"created_at"`
+	Lines                 []StatementLine `json:"lines,omitempty"`
+}
+
+type StatementLine struct {
+	LineNo              int        `json:"line_no"`
+	LineType            string     `json:"line_type"`
+	Description         string     `json:"description"`
+	GroupID             *int64     `json:"group_id,omitempty"`
+	GroupName           string     `json:"group_name,omitempty"`
+	Model               string     `json:"model,omitempty"`
+	BillingType         *int8      `json:"billing_type,omitempty"`
+	Reference           string     `json:"reference,omitempty"`
+	RequestCount        int64      `json:"request_count"`
+	InputTokens         int64      `json:"input_tokens"`
+	OutputTokens        int64      `json:"output_tokens"`
+	CacheCreationTokens int64      `json:"cache_creation_tokens"`
+	CacheReadTokens     int64      `json:"cache_read_tokens"`
+	ImageCount          int64      `json:"image_count"`
+	VideoCount          int64      `json:"video_count"`
+	TotalCost           float64    `json:"total_cost"`
+	Amount              float64    `json:"amount"`
+	OccurredAt          *time.Time `json:"occurred_at,omitempty"`
+}
+
+// ProxyPool 代理池（成员不含代理密码）
+type ProxyPool struct {
+	ID           int64             `json:"id"`
+	Name         string            `json:"name"`
+	Description  string            `json:"description"`
+	Status       string            `json:"status"`
+	AccountCount int64             `json:"account_count"`
+	MemberCount  int               `json:"member_count"`
+	HealthyCount int               `json:"healthy_count"`
+	Members      []ProxyPoolMember `json:"members"`
+	CreatedAt    time.Time         `json:"created_at"`
+	UpdatedAt    time.Time         `json:"updated_at"`
+}
+
+type ProxyPoolMember struct {
+	ProxyID       int64      `json:"proxy_id"`
+	Proxy         *Proxy     `json:"proxy,omitempty"`
+	Healthy       bool       `json:"healthy"`
+	FailCount     int        `json:"fail_count"`
+	LatencyMs     *int64     `json:"latency_ms,omitempty"`
+	LastError     string     `json:"last_error,omitempty"`
+	LastCheckedAt *time.Time `json:"last_checked_at,omitempty"`
+}
+
+// UserWebhook is a user-configured outbound webhook. Secret is only returned on create and rotate.
+type UserWebhook struct {
+	ID                    int64      `json:"id"`
+	APIKeyID              *int64     `json:"api_key_id"`
+	Name                  string     `json:"name"`
+	URL                   string     `json:"url"`
+	Secret                string     `json:"secret,omitempty"`
+	SecretHint            string     `json:"secret_hint"`
+	Events                []string   `json:"events"`
+	Enabled               bool       `json:"enabled"`
+	BalanceThreshold      float64    `json:"balance_threshold"`
+	UsageThresholdPercent int        `json:"usage_threshold_percent"`
+	ExpiryNoticeDays      int        `json:"expiry_notice_days"`
+	LastDeliveryAt        *time.Time `json:"last_delivery_at,omitempty"`
+	LastDeliveryStatus    string     `json:"last_delivery_status,omitempty"`
+	CreatedAt             time.Time  `json:"created_at"`
+	UpdatedAt             time.Time  `json:"updated_at"`
+}
+
+type UserWebhookDelivery struct {
+	ID             int64           `json:"id"`
+	WebhookID      int64           `json:"webhook_id"`
+	EventID        string          `json:"event_id"`
+	EventType      string          `json:"event_type"`
+	Payload        json.RawMessage `json:"payload"`
+	Status         string          `json:"status"`
+	Attempts       int             `json:"attempts"`
+	MaxAttempts    int             `json:"max_attempts"`
+	NextAttemptAt  *time.Time      `json:"next_attempt_at,omitempty"`
+	ResponseStatus int             `json:"response_status"`
+	LastError      string          `json:"last_error"`
+	DurationMs     int64           `json:"duration_ms"`
+	DeliveredAt    *time.Time      `json:"delivered_at,omitempty"`
+	CreatedAt      time.Time       `json:"created_at"`
+}
+
+// UserNotificationSettings is the user's alert email settings (0 disables a threshold).
+type UserNotificationSettings struct {
+	Enabled                  bool    `json:"enabled"`
+	BalanceThreshold         float64 `json:"balance_threshold"`
+	APIKeyQuotaPercent       int     `json:"api_key_quota_percent"`
+	SubscriptionUsagePercent int     `json:"subscription_usage_percent"`
+	ExpiryNoticeDays         int     `json:"expiry_notice_days"`
+}
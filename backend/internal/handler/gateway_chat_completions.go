@@ -0,0 +1,306 @@
+package handler
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/apicompat"
+	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+)
+
+// ChatCompletions handles OpenAI Chat Completions requests for Anthropic,
+// Gemini and Antigravity groups. The request is converted to an Anthropic
+// Messages request and served by the regular Messages pipeline (scheduling,
+// failover, billing); the response is converted back on the fly.
+// POST /v1/chat/completions
+func (h *GatewayHandler) ChatCompletions(c *gin.Context) {
+	body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			chatCompletionsErrorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		chatCompletionsErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request body")
+		return
+	}
+	if len(body) == 0 {
+		chatCompletionsErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Request body is empty")
+		return
+	}
+
+	var chatReq apicompat.ChatCompletionsRequest
+	if err := json.Unmarshal(body, &chatReq); err != nil {
+		chatCompletionsErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to parse request body")
+		return
+	}
+	if chatReq.Model == "" {
+		chatCompletionsErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "model is required")
+		return
+	}
+	if len(chatReq.Messages) == 0 {
+		chatCompletionsErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "messages is required")
+		return
+	}
+
+	anthropicReq, err := apicompat.ChatCompletionsToAnthropic(&chatReq)
+	if err != nil {
+		chatCompletionsErrorResponse(c, http.StatusBadRequest, "invalid_request_error", "Invalid request: "+err.Error())
+		return
+	}
+	anthropicBody, err := json.Marshal(anthropicReq)
+	if err != nil {
+		chatCompletionsErrorResponse(c, http.StatusInternalServerError, "api_error", "Failed to convert request")
+		return
+	}
+
+	// Hand the converted body to the Messages pipeline and translate whatever
+	// it writes (JSON, SSE or errors) back into Chat Completions format.
+	c.Request.Body = io.NopCloser(bytes.NewReader(anthropicBody))
+	c.Request.ContentLength = int64(len(anthropicBody))
+
+	includeUsage := chatReq.StreamOptions != nil && chatReq.StreamOptions.IncludeUsage
+	w := newChatCompletionsResponseWriter(c.Writer, chatReq.Stream, includeUsage, chatReq.Model)
+	c.Writer = w
+	defer func() {
+		w.finish()
+		if c.Writer == w {
+			c.Writer = w.ResponseWriter
+		}
+	}()
+
+	h.Messages(c)
+}
+
+// chatCompletionsErrorResponse writes an error in OpenAI Chat Completions format.
+func chatCompletionsErrorResponse(c *gin.Context, status int, errType, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+			"param":   nil,
+			"code":    nil,
+		},
+	})
+}
+
+// chatCompletionsResponseWriter translates Anthropic Messages output written
+// by the Messages pipeline into Chat Completions output.
+//
+// Non-streaming responses and error responses are buffered and converted in
+// finish(); successful streams are converted event by event as they arrive.
+type chatCompletionsResponseWriter struct {
+	gin.ResponseWriter
+
+	stream bool
+	model  string
+
+	// buffered holds the body of non-streaming or error responses.
+	buffered  bytes.Buffer
+	buffering bool
+	decided   bool
+
+	// pending holds an incomplete SSE event between writes.
+	pending  []byte
+	state    *apicompat.AnthropicEventToChatState
+	errored  bool
+	finished bool
+}
+
+func newChatCompletionsResponseWriter(rw gin.ResponseWriter, stream, includeUsage bool, model string) *chatCompletionsResponseWriter {
+	state := apicompat.NewAnthropicEventToChatState()
+	state.Model = model
+	state.IncludeUsage = includeUsage
+	return &chatCompletionsResponseWriter{
+		ResponseWriter: rw,
+		stream:         stream,
+		model:          model,
+		state:          state,
+	}
+}
+
+// decide picks buffering vs. live conversion on the first body write, once
+// the status code is known.
+func (w *chatCompletionsResponseWriter) decide() {
+	if w.decided {
+		return
+	}
+	w.decided = true
+	w.buffering = !w.stream || w.ResponseWriter.Status() >= http.StatusBadRequest
+}
+
+func (w *chatCompletionsResponseWriter) Write(b []byte) (int, error) {
+	w.decide()
+	if w.buffering {
+		return w.buffered.Write(b)
+	}
+	w.pending = append(w.pending, b...)
+	if err := w.drainEvents(false); err != nil {
+		return 0, err
+	}
+	return len(b), nil
+}
+
+func (w *chatCompletionsResponseWriter) WriteString(s string) (int, error) {
+	return w.Write([]byte(s))
+}
+
+// Written reports true once the pipeline has produced any output, so that
+// fallback error writers do not append a second response.
+func (w *chatCompletionsResponseWriter) Written() bool {
+	return w.decided || w.ResponseWriter.Written()
+}
+
+func (w *chatCompletionsResponseWriter) Flush() {
+	if w.decided && w.buffering {
+		return
+	}
+	if !w.stream {
+		return
+	}
+	w.ResponseWriter.Flush()
+}
+
+// drainEvents converts every complete SSE event in pending. With final set,
+// a trailing event without the blank-line terminator is processed as well.
+func (w *chatCompletionsResponseWriter) drainEvents(final bool) error {
+	if bytes.Contains(w.pending, []byte("\r\n")) {
+		w.pending = bytes.ReplaceAll(w.pending, []byte("\r\n"), []byte("\n"))
+	}
+	for {
+		idx := bytes.Index(w.pending, []byte("\n\n"))
+		var block []byte
+		if idx >= 0 {
+			block = w.pending[:idx]
+			w.pending = w.pending[idx+2:]
+		} else {
+			if !final || len(bytes.TrimSpace(w.pending)) == 0 {
+				return nil
+			}
+			block = w.pending
+			w.pending = nil
+		}
+		if err := w.convertEvent(block); err != nil {
+			return err
+		}
+	}
+}
+
+func (w *chatCompletionsResponseWriter) convertEvent(block []byte) error {
+	var eventName string
+	var data []byte
+	for _, line := range strings.Split(string(block), "\n") {
+		switch {
+		case strings.HasPrefix(line, "event:"):
+			eventName = strings.TrimSpace(strings.TrimPrefix(line, "event:"))
+		case strings.HasPrefix(line, "data:"):
+			data = []byte(strings.TrimSpace(strings.TrimPrefix(line, "data:")))
+		}
+	}
+	if len(data) == 0 {
+		return nil
+	}
+
+	eventType := gjson.GetBytes(data, "type").String()
+	if eventName == "error" || eventType == "error" {
+		w.errored = true
+		payload, _ := json.Marshal(anthropicErrorToChatError(data))
+		return w.writeRaw("data: " + string(payload) + "\n\n")
+	}
+	if eventType == "ping" {
+		return w.writeRaw(": ping\n\n")
+	}
+
+	var evt apicompat.AnthropicStreamEvent
+	if err := json.Unmarshal(data, &evt); err != nil {
+		return nil
+	}
+	for _, chunk := range apicompat.AnthropicEventToChatChunks(&evt, w.state) {
+		sse, err := apicompat.ChatChunkToSSE(chunk)
+		if err != nil {
+			continue
+		}
+		if err := w.writeRaw(sse); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (w *chatCompletionsResponseWriter) writeRaw(s string) error {
+	_, err := w.ResponseWriter.WriteString(s)
+	return err
+}
+
+// finish converts buffered output and terminates an open stream.
+func (w *chatCompletionsResponseWriter) finish() {
+	if w.finished {
+		return
+	}
+	w.finished = true
+
+	if !w.decided {
+		return
+	}
+	if !w.buffering {
+		_ = w.drainEvents(true)
+		if !w.errored {
+			for _, chunk := range apicompat.FinalizeAnthropicChatStream(w.state) {
+				if sse, err := apicompat.ChatChunkToSSE(chunk); err == nil {
+					_ = w.writeRaw(sse)
+				}
+			}
+		}
+		_ = w.writeRaw(apicompat.ChatStreamDone)
+		w.ResponseWriter.Flush()
+		return
+	}
+
+	raw := w.buffered.Bytes()
+	w.ResponseWriter.Header().Del("Content-Length")
+	w.ResponseWriter.Header().Set("Content-Type", "application/json; charset=utf-8")
+
+	var out any
+	if w.ResponseWriter.Status() >= http.StatusBadRequest {
+		out = anthropicErrorToChatError(raw)
+	} else {
+		var resp apicompat.AnthropicResponse
+		if err := json.Unmarshal(raw, &resp); err != nil {
+			_, _ = w.ResponseWriter.Write(raw)
+			return
+		}
+		out = apicompat.AnthropicToChatCompletions(&resp, w.model)
+	}
+	payload, err := json.Marshal(out)
+	if err != nil {
+		_, _ = w.ResponseWriter.Write(raw)
+		return
+	}
+	_, _ = w.ResponseWriter.Write(payload)
+}
+
+// anthropicErrorToChatError maps {"type":"error","error":{"type","message"}}
+// (or any body carrying error.message) to the OpenAI error shape.
+func anthropicErrorToChatError(raw []byte) gin.H {
+	errType := gjson.GetBytes(raw, "error.type").String()
+	if errType == "" {
+		errType = "api_error"
+	}
+	message := gjson.GetBytes(raw, "error.message").String()
+	if message == "" {
+		message = strings.TrimSpace(string(raw))
+	}
+	return gin.H{
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+			"param":   nil,
+			"code":    nil,
+		},
+	}
+}
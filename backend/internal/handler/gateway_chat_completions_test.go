@@ -0,0 +1,105 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newChatCompletionsWriterTestContext(stream bool) (*gin.Context, *httptest.ResponseRecorder, *chatCompletionsResponseWriter) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/chat/completions", nil)
+	w := newChatCompletionsResponseWriter(c.Writer, stream, true, "claude-alias")
+	c.Writer = w
+	return c, rec, w
+}
+
+func TestChatCompletionsResponseWriter_NonStreaming(t *testing.T) {
+	c, rec, w := newChatCompletionsWriterTestContext(false)
+
+	c.JSON(http.StatusOK, gin.H{
+		"id":          "msg_1",
+		"type":        "message",
+		"role":        "assistant",
+		"model":       "claude-sonnet-4-5",
+		"content":     []gin.H{{"type": "text", "text": "Hello"}},
+		"stop_reason": "end_turn",
+		"usage":       gin.H{"input_tokens": 3, "output_tokens": 2},
+	})
+	require.True(t, w.Written())
+	require.Empty(t, rec.Body.String(), "non-streaming output must be buffered until finish")
+
+	w.finish()
+	require.Equal(t, http.StatusOK, rec.Code)
+
+	var resp map[string]any
+	require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+	require.Equal(t, "chat.completion", resp["object"])
+	require.Equal(t, "claude-alias", resp["model"])
+	choice := resp["choices"].([]any)[0].(map[string]any)
+	require.Equal(t, "stop", choice["finish_reason"])
+	require.Equal(t, "Hello", choice["message"].(map[string]any)["content"])
+}
+
+func TestChatCompletionsResponseWriter_ErrorShape(t *testing.T) {
+	c, rec, w := newChatCompletionsWriterTestContext(true)
+
+	c.JSON(http.StatusTooManyRequests, gin.H{
+		"type":  "error",
+		"error": gin.H{"type": "rate_limit_error", "message": "slow down"},
+	})
+	w.finish()
+
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+	require.JSONEq(t, `{"error":{"type":"rate_limit_error","message":"slow down","param":null,"code":null}}`, rec.Body.String())
+}
+
+func TestChatCompletionsResponseWriter_Streaming(t *testing.T) {
+	c, rec, w := newChatCompletionsWriterTestContext(true)
+
+	c.Writer.Header().Set("Content-Type", "text/event-stream")
+	c.Writer.WriteHeader(http.StatusOK)
+	events := []string{
+		"data: {\"type\": \"ping\"}\n\n",
+		"event: message_start\ndata: {\"type\":\"message_start\",\"message\":{\"id\":\"msg_1\",\"model\":\"claude-sonnet-4-5\",\"usage\":{\"input_tokens\":4,\"output_tokens\":0}}}\n\n",
+		"event: content_block_start\ndata: {\"type\":\"content_block_start\",\"index\":0,\"content_block\":{\"type\":\"text\",\"text\":\"\"}}\n\n",
+		// Split one event across two writes to exercise buffering.
+		"event: content_block_delta\ndata: {\"type\":\"content_block_delta\",\"index\":0,",
+		"\"delta\":{\"type\":\"text_delta\",\"text\":\"Hi\"}}\n\n",
+		"event: message_delta\ndata: {\"type\":\"message_delta\",\"delta\":{\"stop_reason\":\"end_turn\"},\"usage\":{\"output_tokens\":1}}\n\n",
+		"event: message_stop\ndata: {\"type\":\"message_stop\"}\n\n",
+	}
+	for _, e := range events {
+		_, err := c.Writer.WriteString(e)
+		require.NoError(t, err)
+	}
+	w.finish()
+
+	body := rec.Body.String()
+	require.True(t, strings.HasPrefix(body, ": ping\n\n"))
+	require.Contains(t, body, `"object":"chat.completion.chunk"`)
+	require.Contains(t, body, `"content":"Hi"`)
+	require.Contains(t, body, `"finish_reason":"stop"`)
+	require.Contains(t, body, `"prompt_tokens":4`)
+	require.True(t, strings.HasSuffix(body, "data: [DONE]\n\n"))
+	require.NotContains(t, body, "message_start")
+}
+
+func TestChatCompletionsResponseWriter_StreamingError(t *testing.T) {
+	c, rec, w := newChatCompletionsWriterTestContext(true)
+
+	c.Writer.WriteHeader(http.StatusOK)
+	_, _ = c.Writer.WriteString("event: error\ndata: {\"type\":\"error\",\"error\":{\"type\":\"overloaded_error\",\"message\":\"busy\"}}\n\n")
+	w.finish()
+
+	body := rec.Body.String()
+	require.Contains(t, body, `data: {"error":{"code":null,"message":"busy","param":null,"type":"overloaded_error"}}`)
+	require.NotContains(t, body, "finish_reason")
+}
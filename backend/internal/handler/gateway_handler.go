@@ -23,6 +23,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/openai"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
@@ -47,6 +48,7 @@ type GatewayHandler struct {
 	errorPassthroughService   *service.ErrorPassthroughService
 	concurrencyHelper         *ConcurrencyHelper
 	userMsgQueueHelper        *UserMsgQueueHelper
+	responseCacheService      *service.ResponseCacheService
 	maxAccountSwitches        int
 	maxAccountSwitchesGemini  int
 	cfg                       *config.Config
@@ -66,6 +68,7 @@ func NewGatewayHandler(
 	usageRecordWorkerPool *service.UsageRecordWorkerPool,
 	errorPassthroughService *service.ErrorPassthroughService,
 	userMsgQueueService *service.UserMessageQueueService,
+	responseCacheService *service.ResponseCacheService,
 	cfg *config.Config,
 	settingService *service.SettingService,
 ) *GatewayHandler {
@@ -100,6 +103,7 @@ func NewGatewayHandler(
 		errorPassthroughService:   errorPassthroughService,
 		concurrencyHelper:         NewConcurrencyHelper(concurrencyService, SSEPingFormatClaude, pingInterval),
 		userMsgQueueHelper:        umqHelper,
+		responseCacheService:      responseCacheService,
 		maxAccountSwitches:        maxAccountSwitches,
 		maxAccountSwitchesGemini:  maxAccountSwitchesGemini,
 		cfg:                       cfg,
@@ -244,6 +248,14 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 		return
 	}
 
+	// 精确响应缓存：确定性请求命中时直接返回，不占用上游账号
+	cacheReq := h.responseCacheService.Prepare(apiKey.Group, service.ResponseCacheEndpointMessages, body)
+	if serveResponseCacheHit(c, h.responseCacheService, cacheReq, apiKey, subscription, reqStream, h.apiKeyService, h.submitUsageRecordTask, reqLog) {
+		return
+	}
+	cacheCapture, restoreWriter := installResponseCacheCapture(c, h.responseCacheService, cacheReq, reqStream)
+	defer restoreWriter()
+
 	// 计算粘性会话hash
 	parsedReq.SessionContext = &service.SessionContext{
 		ClientIP:  ip.GetClientIP(c),
@@ -402,6 +414,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			if err != nil {
 				var failoverErr *service.UpstreamFailoverError
 				if errors.As(err, &failoverErr) {
+					h.gatewayService.ReportAccountScheduleResult(account.ID, false, nil)
 					action := fs.HandleFailoverError(c.Request.Context(), h.gatewayService, account.ID, account.Platform, failoverErr)
 					switch action {
 					case FailoverContinue:
@@ -421,6 +434,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				)
 				return
 			}
+			h.gatewayService.ReportAccountScheduleResult(account.ID, true, result.FirstTokenMs)
 
 			// RPM 计数递增（Forward 成功后）
 			// 注意：TOCTOU 竞态是已知且可接受的设计权衡，与 WindowCost 一致的 soft-limit 模式。
@@ -431,12 +445,14 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				}
 			}
 
+			storeResponseCache(c.Request.Context(), h.responseCacheService, cacheReq, cacheCapture, account.ID, result.RequestID, result.Model, claudeResponseCacheUsage(result.Usage))
+
 			// 捕获请求信息（用于异步记录，避免在 goroutine 中访问 gin.Context）
 			userAgent := c.GetHeader("User-Agent")
 			clientIP := ip.GetClientIP(c)
 
 			// 使用量记录通过有界 worker 池提交，避免请求热路径创建无界 goroutine。
-			h.submitUsageRecordTask(func(ctx context.Context) {
+			h.submitUsageRecordTask(tracing.WithParentSpan(c.Request.Context(), func(ctx context.Context) {
 				if err := h.gatewayService.RecordUsage(ctx, &service.RecordUsageInput{
 					Result:            result,
 					APIKey:            apiKey,
@@ -457,7 +473,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 						zap.Int64("account_id", account.ID),
 					).Error("gateway.record_usage_failed", zap.Error(err))
 				}
-			})
+			}))
 			return
 		}
 	}
@@ -469,6 +485,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 		fallbackGroupID = apiKey.Group.FallbackGroupIDOnInvalidRequest
 	}
 	fallbackUsed := false
+	modelFallback := newModelFallbackState(apiKey.Group, reqModel)
 
 	// 单账号分组提前设置 SingleAccountRetry 标记，让 Service 层首次 503 就不设模型限流标记。
 	// 避免单账号分组收到 503 (MODEL_CAPACITY_EXHAUSTED) 时设 29s 限流，导致后续请求连续快速失败。
@@ -486,6 +503,12 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			selection, err := h.gatewayService.SelectAccountWithLoadAwareness(c.Request.Context(), currentAPIKey.GroupID, sessionKey, reqModel, fs.FailedAccountIDs, parsedReq.MetadataUserID)
 			if err != nil {
 				if len(fs.FailedAccountIDs) == 0 {
+					// 该模型无可调度账号（通常为全部限流）：按分组降级链切换模型
+					if modelFallback.advance(c, currentAPIKey, parsedReq, service.ModelFallbackConditionRateLimited, reqLog) {
+						reqModel, body = parsedReq.Model, parsedReq.Body
+						retryWithFallback = true
+						break
+					}
 					h.handleStreamingAwareError(c, http.StatusServiceUnavailable, "api_error", "No available accounts: "+err.Error(), streamStarted)
 					return
 				}
@@ -498,6 +521,11 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				case FailoverCanceled:
 					return
 				default: // FailoverExhausted
+					if modelFallback.advance(c, currentAPIKey, parsedReq, modelFallbackConditionFromFailover(fs.LastFailoverErr), reqLog) {
+						reqModel, body = parsedReq.Model, parsedReq.Body
+						retryWithFallback = true
+						break
+					}
 					if fs.LastFailoverErr != nil {
 						h.handleFailoverExhausted(c, fs.LastFailoverErr, platform, streamStarted)
 					} else {
@@ -505,6 +533,9 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 					}
 					return
 				}
+				if retryWithFallback {
+					break
+				}
 			}
 			account := selection.Account
 			setOpsSelectedAccount(c, account.ID, account.Platform)
@@ -654,6 +685,12 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 			if err != nil {
 				var promptTooLongErr *service.PromptTooLongError
 				if errors.As(err, &promptTooLongErr) {
+					// 优先在本分组内降级模型，其次才使用无效请求兜底分组
+					if modelFallback.advance(c, currentAPIKey, parsedReq, service.ModelFallbackConditionPromptTooLong, reqLog) {
+						reqModel, body = parsedReq.Model, parsedReq.Body
+						retryWithFallback = true
+						break
+					}
 					reqLog.Warn("gateway.prompt_too_long_from_antigravity",
 						zap.Any("current_group_id", currentAPIKey.GroupID),
 						zap.Any("fallback_group_id", fallbackGroupID),
@@ -689,6 +726,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 						currentAPIKey = fallbackAPIKey
 						currentSubscription = nil
 						fallbackUsed = true
+						modelFallback.resetGroup(fallbackGroup, reqModel)
 						retryWithFallback = true
 						break
 					}
@@ -697,16 +735,25 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				}
 				var failoverErr *service.UpstreamFailoverError
 				if errors.As(err, &failoverErr) {
+					h.gatewayService.ReportAccountScheduleResult(account.ID, false, nil)
 					action := fs.HandleFailoverError(c.Request.Context(), h.gatewayService, account.ID, account.Platform, failoverErr)
 					switch action {
 					case FailoverContinue:
 						continue
 					case FailoverExhausted:
+						if modelFallback.advance(c, currentAPIKey, parsedReq, modelFallbackConditionFromFailover(fs.LastFailoverErr), reqLog) {
+							reqModel, body = parsedReq.Model, parsedReq.Body
+							retryWithFallback = true
+							break
+						}
 						h.handleFailoverExhausted(c, fs.LastFailoverErr, account.Platform, streamStarted)
 						return
 					case FailoverCanceled:
 						return
 					}
+					if retryWithFallback {
+						break
+					}
 				}
 				wroteFallback := h.ensureForwardErrorResponse(c, streamStarted)
 				reqLog.Error("gateway.forward_failed",
@@ -716,6 +763,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				)
 				return
 			}
+			h.gatewayService.ReportAccountScheduleResult(account.ID, true, result.FirstTokenMs)
 
 			// RPM 计数递增（Forward 成功后）
 			// 注意：TOCTOU 竞态是已知且可接受的设计权衡，与 WindowCost 一致的 soft-limit 模式。
@@ -726,12 +774,17 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 				}
 			}
 
+			// 兜底分组/降级模型的响应与原请求不等价，不写入本分组缓存
+			if !fallbackUsed && !modelFallback.used {
+				storeResponseCache(c.Request.Context(), h.responseCacheService, cacheReq, cacheCapture, account.ID, result.RequestID, result.Model, claudeResponseCacheUsage(result.Usage))
+			}
+
 			// 捕获请求信息（用于异步记录，避免在 goroutine 中访问 gin.Context）
 			userAgent := c.GetHeader("User-Agent")
 			clientIP := ip.GetClientIP(c)
 
 			// 使用量记录通过有界 worker 池提交，避免请求热路径创建无界 goroutine。
-			h.submitUsageRecordTask(func(ctx context.Context) {
+			h.submitUsageRecordTask(tracing.WithParentSpan(c.Request.Context(), func(ctx context.Context) {
 				if err := h.gatewayService.RecordUsage(ctx, &service.RecordUsageInput{
 					Result:            result,
 					APIKey:            currentAPIKey,
@@ -752,7 +805,7 @@ func (h *GatewayHandler) Messages(c *gin.Context) {
 						zap.Int64("account_id", account.ID),
 					).Error("gateway.record_usage_failed", zap.Error(err))
 				}
-			})
+			}))
 			return
 		}
 		if !retryWithFallback {
@@ -782,7 +835,7 @@ func (h *GatewayHandler) Models(c *gin.Context) {
 	if platform == service.PlatformSora {
 		c.JSON(http.StatusOK, gin.H{
 			"object": "list",
-			"data":   service.DefaultSoraModels(h.cfg),
+			"data":   filterOpenAIModelsForAPIKey(apiKey, service.DefaultSoraModels(h.cfg)),
 		})
 		return
 	}
@@ -794,16 +847,11 @@ func (h *GatewayHandler) Models(c *gin.Context) {
 		// Build model list from whitelist
 		models := make([]claude.Model, 0, len(availableModels))
 		for _, modelID := range availableModels {
-			models = append(models, claude.Model{
-				ID:          modelID,
-				Type:        "model",
-				DisplayName: modelID,
-				CreatedAt:   "2024-01-01T00:00:00Z",
-			})
+			models = append(models, newClaudeModelEntry(modelID))
 		}
 		c.JSON(http.StatusOK, gin.H{
 			"object": "list",
-			"data":   models,
+			"data":   filterClaudeModelsForAPIKey(apiKey, models),
 		})
 		return
 	}
@@ -812,23 +860,49 @@ func (h *GatewayHandler) Models(c *gin.Context) {
 	if platform == "openai" {
 		c.JSON(http.StatusOK, gin.H{
 			"object": "list",
-			"data":   openai.DefaultModels,
+			"data":   filterOpenAIModelsForAPIKey(apiKey, openai.DefaultModels),
 		})
 		return
 	}
 
 	c.JSON(http.StatusOK, gin.H{
 		"object": "list",
-		"data":   claude.DefaultModels,
+		"data":   filterClaudeModelsForAPIKey(apiKey, claude.DefaultModels),
+	})
+}
+
+func newClaudeModelEntry(modelID string) claude.Model {
+	return claude.Model{
+		ID:          modelID,
+		Type:        "model",
+		DisplayName: modelID,
+		CreatedAt:   "2024-01-01T00:00:00Z",
+	}
+}
+
+func filterClaudeModelsForAPIKey(apiKey *service.APIKey, models []claude.Model) []claude.Model {
+	return filterModelsForAPIKey(apiKey, models, func(m claude.Model) string { return m.ID }, newClaudeModelEntry)
+}
+
+func filterOpenAIModelsForAPIKey(apiKey *service.APIKey, models []openai.Model) []openai.Model {
+	return filterModelsForAPIKey(apiKey, models, func(m openai.Model) string { return m.ID }, func(alias string) openai.Model {
+		return openai.Model{ID: alias, Object: "model", Type: "model", DisplayName: alias, OwnedBy: "sub2api"}
 	})
 }
 
 // AntigravityModels 返回 Antigravity 支持的全部模型
 // GET /antigravity/models
 func (h *GatewayHandler) AntigravityModels(c *gin.Context) {
+	apiKey, _ := middleware2.GetAPIKeyFromContext(c)
+	models := filterModelsForAPIKey(apiKey, antigravity.DefaultModels(),
+		func(m antigravity.ClaudeModel) string { return m.ID },
+		func(alias string) antigravity.ClaudeModel {
+			return antigravity.ClaudeModel{ID: alias, Type: "model", DisplayName: alias, CreatedAt: "2024-01-01T00:00:00Z"}
+		},
+	)
 	c.JSON(http.StatusOK, gin.H{
 		"object": "list",
-		"data":   antigravity.DefaultModels(),
+		"data":   models,
 	})
 }
 
@@ -1599,6 +1673,10 @@ func billingErrorDetails(err error) (status int, code, message string) {
 		msg := pkgerrors.Message(err)
 		return http.StatusTooManyRequests, "rate_limit_exceeded", msg
 	}
+	if errors.Is(err, service.ErrOrganizationMemberLimitExceeded) {
+		msg := pkgerrors.Message(err)
+		return http.StatusTooManyRequests, "rate_limit_exceeded", msg
+	}
 	msg := pkgerrors.Message(err)
 	if msg == "" {
 		logger.L().With(
@@ -3,6 +3,7 @@ package handler
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"math/rand/v2"
 	"net/http"
@@ -10,9 +11,12 @@ import (
 	"sync"
 	"time"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
 	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
 )
 
 // claudeCodeValidator is a singleton validator for Claude Code client detection
@@ -218,7 +222,9 @@ func (h *ConcurrencyHelper) DecrementAccountWaitCount(ctx context.Context, accou
 // TryAcquireUserSlot 尝试立即获取用户并发槽位。
 // 返回值: (releaseFunc, acquired, error)
 func (h *ConcurrencyHelper) TryAcquireUserSlot(ctx context.Context, userID int64, maxConcurrency int) (func(), bool, error) {
+	ctx, span := startConcurrencySpan(ctx, "concurrency.acquire_slot", "user", userID, maxConcurrency)
 	result, err := h.concurrencyService.AcquireUserSlot(ctx, userID, maxConcurrency)
+	endConcurrencySpan(span, result != nil && result.Acquired, err)
 	if err != nil {
 		return nil, false, err
 	}
@@ -231,7 +237,9 @@ func (h *ConcurrencyHelper) TryAcquireUserSlot(ctx context.Context, userID int64
 // TryAcquireAccountSlot 尝试立即获取账号并发槽位。
 // 返回值: (releaseFunc, acquired, error)
 func (h *ConcurrencyHelper) TryAcquireAccountSlot(ctx context.Context, accountID int64, maxConcurrency int) (func(), bool, error) {
+	ctx, span := startConcurrencySpan(ctx, "concurrency.acquire_slot", "account", accountID, maxConcurrency)
 	result, err := h.concurrencyService.AcquireAccountSlot(ctx, accountID, maxConcurrency)
+	endConcurrencySpan(span, result != nil && result.Acquired, err)
 	if err != nil {
 		return nil, false, err
 	}
@@ -288,10 +296,13 @@ func (h *ConcurrencyHelper) waitForSlotWithPing(c *gin.Context, slotType string,
 }
 
 // waitForSlotWithPingTimeout waits for a concurrency slot with a custom timeout.
-func (h *ConcurrencyHelper) waitForSlotWithPingTimeout(c *gin.Context, slotType string, id int64, maxConcurrency int, timeout time.Duration, isStream bool, streamStarted *bool, tryImmediate bool) (func(), error) {
+func (h *ConcurrencyHelper) waitForSlotWithPingTimeout(c *gin.Context, slotType string, id int64, maxConcurrency int, timeout time.Duration, isStream bool, streamStarted *bool, tryImmediate bool) (release func(), err error) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 	defer cancel()
 
+	ctx, span := startConcurrencySpan(ctx, "concurrency.wait_slot", slotType, id, maxConcurrency)
+	defer func() { endConcurrencySpan(span, release != nil, err) }()
+
 	acquireSlot := func() (*service.AcquireResult, error) {
 		if slotType == "user" {
 			return h.concurrencyService.AcquireUserSlot(ctx, id, maxConcurrency)
@@ -376,6 +387,25 @@ func (h *ConcurrencyHelper) AcquireAccountSlotWithWaitTimeout(c *gin.Context, ac
 	return h.waitForSlotWithPingTimeout(c, "account", accountID, maxConcurrency, timeout, isStream, streamStarted, true)
 }
 
+func startConcurrencySpan(ctx context.Context, name, slotType string, id int64, maxConcurrency int) (context.Context, trace.Span) {
+	return tracing.Start(ctx, name,
+		attribute.String("concurrency.scope", slotType),
+		attribute.Int64("concurrency.id", id),
+		attribute.Int("concurrency.max", maxConcurrency),
+	)
+}
+
+func endConcurrencySpan(span trace.Span, acquired bool, err error) {
+	if span.IsRecording() {
+		span.SetAttributes(attribute.Bool("concurrency.acquired", acquired))
+		var concurrencyErr *ConcurrencyError
+		if errors.As(err, &concurrencyErr) && concurrencyErr.IsTimeout {
+			span.SetAttributes(attribute.Bool("concurrency.timeout", true))
+		}
+	}
+	tracing.End(span, err)
+}
+
 // nextBackoff 计算下一次退避时间
 // 性能优化：使用指数退避 + 随机抖动，避免惊群效应
 // current: 当前退避时间
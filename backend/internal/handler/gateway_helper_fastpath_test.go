@@ -65,6 +65,14 @@ func (m *concurrencyCacheMock) ReleaseUserSlot(ctx context.Context, userID int64
 	return nil
 }
 
+func (m *concurrencyCacheMock) AcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error) {
+	return true, nil
+}
+
+func (m *concurrencyCacheMock) ReleaseAPIKeySlot(ctx context.Context, apiKeyID int64, requestID string) error {
+	return nil
+}
+
 func (m *concurrencyCacheMock) GetUserConcurrency(ctx context.Context, userID int64) (int, error) {
 	return 0, nil
 }
@@ -6,29 +6,35 @@ import (
 
 // AdminHandlers contains all admin-related HTTP handlers
 type AdminHandlers struct {
-	Dashboard        *admin.DashboardHandler
-	User             *admin.UserHandler
-	Group            *admin.GroupHandler
-	Account          *admin.AccountHandler
-	Announcement     *admin.AnnouncementHandler
-	DataManagement   *admin.DataManagementHandler
-	OAuth            *admin.OAuthHandler
-	OpenAIOAuth      *admin.OpenAIOAuthHandler
-	GeminiOAuth      *admin.GeminiOAuthHandler
-	AntigravityOAuth *admin.AntigravityOAuthHandler
-	Proxy            *admin.ProxyHandler
-	Redeem           *admin.RedeemHandler
-	Promo            *admin.PromoHandler
-	Setting          *admin.SettingHandler
-	Ops              *admin.OpsHandler
-	System           *admin.SystemHandler
-	Subscription     *admin.SubscriptionHandler
-	Usage            *admin.UsageHandler
-	UserAttribute    *admin.UserAttributeHandler
-	ErrorPassthrough *admin.ErrorPassthroughHandler
-	APIKey           *admin.AdminAPIKeyHandler
-	ScheduledTest    *admin.ScheduledTestHandler
-	Order            *admin.OrderHandler
+	Dashboard            *admin.DashboardHandler
+	User                 *admin.UserHandler
+	Group                *admin.GroupHandler
+	Account              *admin.AccountHandler
+	Announcement         *admin.AnnouncementHandler
+	DataManagement       *admin.DataManagementHandler
+	OAuth                *admin.OAuthHandler
+	OpenAIOAuth          *admin.OpenAIOAuthHandler
+	GeminiOAuth          *admin.GeminiOAuthHandler
+	AntigravityOAuth     *admin.AntigravityOAuthHandler
+	Proxy                *admin.ProxyHandler
+	Redeem               *admin.RedeemHandler
+	Promo                *admin.PromoHandler
+	Setting              *admin.SettingHandler
+	Ops                  *admin.OpsHandler
+	System               *admin.SystemHandler
+	Subscription         *admin.SubscriptionHandler
+	Usage                *admin.UsageHandler
+	UserAttribute        *admin.UserAttributeHandler
+	ErrorPassthrough     *admin.ErrorPassthroughHandler
+	APIKey               *admin.AdminAPIKeyHandler
+	ScheduledTest        *admin.ScheduledTestHandler
+	Order                *admin.OrderHandler
+	Organization         *admin.OrganizationHandler
+	AdminToken           *admin.AdminAPITokenHandler
+	AuditLog             *admin.AuditLogHandler
+	Statement            *admin.StatementHandler
+	ProxyPool            *admin.ProxyPoolHandler
+	CredentialEncryption *admin.CredentialEncryptionHandler
 }
 
 // Handlers contains all HTTP handlers
@@ -49,6 +55,14 @@ type Handlers struct {
 	SoraClient    *SoraClientHandler
 	Setting       *SettingHandler
 	Totp          *TotpHandler
+	Metrics       *MetricsHandler
+	Organization  *OrganizationHandler
+	SSO           *SSOHandler
+	Statement     *StatementHandler
+	Webhook       *UserWebhookHandler
+	Notification  *UserNotificationHandler
+	MessageBatch  *MessageBatchHandler
+	OpenAIBatch   *OpenAIBatchHandler
 }
 
 // BuildInfo contains build-time information
@@ -0,0 +1,270 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	pkgerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// MessageBatchHandler 提供 Anthropic Message Batches API 兼容端点
+type MessageBatchHandler struct {
+	batchService        *service.MessageBatchService
+	billingCacheService *service.BillingCacheService
+}
+
+// NewMessageBatchHandler 创建 Message Batches 处理器
+func NewMessageBatchHandler(batchService *service.MessageBatchService, billingCacheService *service.BillingCacheService) *MessageBatchHandler {
+	return &MessageBatchHandler{
+		batchService:        batchService,
+		billingCacheService: billingCacheService,
+	}
+}
+
+// messageBatchResponse Message Batch 对象（与 Anthropic API 字段一致）
+type messageBatchResponse struct {
+	ID                string                            `json:"id"`
+	Type              string                            `json:"type"`
+	ProcessingStatus  string                            `json:"processing_status"`
+	RequestCounts     service.MessageBatchRequestCounts `json:"request_counts"`
+	EndedAt           *time.Time                        `json:"ended_at"`
+	CreatedAt         time.Time                         `json:"created_at"`
+	ExpiresAt         time.Time                         `json:"expires_at"`
+	ArchivedAt        *time.Time                        `json:"archived_at"`
+	CancelInitiatedAt *time.Time                        `json:"cancel_initiated_at"`
+	ResultsURL        *string                           `json:"results_url"`
+}
+
+func messageBatchToResponse(c *gin.Context, batch *service.MessageBatch) messageBatchResponse {
+	resp := messageBatchResponse{
+		ID:                batch.ID,
+		Type:              "message_batch",
+		ProcessingStatus:  batch.ProcessingStatus,
+		RequestCounts:     batch.RequestCounts,
+		EndedAt:           batch.EndedAt,
+		CreatedAt:         batch.CreatedAt.UTC(),
+		ExpiresAt:         batch.ExpiresAt.UTC(),
+		CancelInitiatedAt: batch.CancelInitiatedAt,
+	}
+	if batch.ProcessingStatus == service.MessageBatchStatusEnded {
+		// SDK 直接请求 results_url，需返回本网关的绝对地址
+		scheme := "http"
+		if isRequestHTTPS(c) {
+			scheme = "https"
+		}
+		url := scheme + "://" + c.Request.Host + "/v1/messages/batches/" + batch.ID + "/results"
+		resp.ResultsURL = &url
+	}
+	return resp
+}
+
+// Create 创建批次
+// POST /v1/messages/batches
+func (h *MessageBatchHandler) Create(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	reqLog := requestLogger(c, "handler.message_batch.create",
+		zap.Int64("api_key_id", apiKey.ID),
+		zap.Any("group_id", apiKey.GroupID),
+	)
+
+	body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			h.errorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request body")
+		return
+	}
+	var req struct {
+		Requests []service.MessageBatchRequest `json:"requests"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to parse request body")
+		return
+	}
+
+	subscription, _ := middleware2.GetSubscriptionFromContext(c)
+	if h.billingCacheService != nil {
+		if err := h.billingCacheService.CheckBillingEligibility(c.Request.Context(), apiKey.User, apiKey, apiKey.Group, subscription); err != nil {
+			reqLog.Info("message_batch.billing_eligibility_check_failed", zap.Error(err))
+			status, code, message := billingErrorDetails(err)
+			h.errorResponse(c, status, code, message)
+			return
+		}
+	}
+
+	batch, err := h.batchService.Create(c.Request.Context(), service.CreateMessageBatchInput{
+		APIKey:        apiKey,
+		Requests:      req.Requests,
+		AnthropicBeta: c.GetHeader("anthropic-beta"),
+	})
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	reqLog.Info("message_batch.created", zap.String("batch_id", batch.ID), zap.Int("requests", len(req.Requests)))
+	c.JSON(http.StatusOK, messageBatchToResponse(c, batch))
+}
+
+// Get 查询批次
+// GET /v1/messages/batches/:id
+func (h *MessageBatchHandler) Get(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	batch, err := h.batchService.Get(c.Request.Context(), apiKey.UserID, c.Param("id"))
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, messageBatchToResponse(c, batch))
+}
+
+// List 列出批次（按创建时间倒序）
+// GET /v1/messages/batches
+func (h *MessageBatchHandler) List(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	params := service.MessageBatchListParams{
+		BeforeID: c.Query("before_id"),
+		AfterID:  c.Query("after_id"),
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > service.MessageBatchListMaxLimit {
+			h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "limit must be between 1 and 1000")
+			return
+		}
+		params.Limit = limit
+	}
+
+	batches, hasMore, err := h.batchService.List(c.Request.Context(), apiKey.UserID, params)
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	data := make([]messageBatchResponse, 0, len(batches))
+	for i := range batches {
+		data = append(data, messageBatchToResponse(c, &batches[i]))
+	}
+	var firstID, lastID *string
+	if len(data) > 0 {
+		firstID, lastID = &data[0].ID, &data[len(data)-1].ID
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"data":     data,
+		"has_more": hasMore,
+		"first_id": firstID,
+		"last_id":  lastID,
+	})
+}
+
+// Cancel 取消批次
+// POST /v1/messages/batches/:id/cancel
+func (h *MessageBatchHandler) Cancel(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	batch, err := h.batchService.Cancel(c.Request.Context(), apiKey.UserID, c.Param("id"))
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, messageBatchToResponse(c, batch))
+}
+
+// Results 以 JSONL 流式返回批次结果
+// GET /v1/messages/batches/:id/results
+func (h *MessageBatchHandler) Results(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	started := false
+	err := h.batchService.Results(c.Request.Context(), apiKey.UserID, c.Param("id"), func(result service.MessageBatchResult) error {
+		line, err := json.Marshal(result)
+		if err != nil {
+			return err
+		}
+		if !started {
+			started = true
+			c.Header("Content-Type", "application/x-jsonl")
+			c.Status(http.StatusOK)
+		}
+		if _, err := c.Writer.Write(append(line, '\n')); err != nil {
+			return err
+		}
+		return nil
+	})
+	if err != nil {
+		if started {
+			// 已开始输出，只能中断响应
+			requestLogger(c, "handler.message_batch.results").Warn("message_batch.results_stream_failed", zap.Error(err))
+			return
+		}
+		h.serviceError(c, err)
+		return
+	}
+	if !started {
+		c.Header("Content-Type", "application/x-jsonl")
+		c.Status(http.StatusOK)
+	}
+}
+
+// serviceError 将服务层错误映射为 Messages API 错误格式
+func (h *MessageBatchHandler) serviceError(c *gin.Context, err error) {
+	status := pkgerrors.Code(err)
+	message := pkgerrors.Message(err)
+	if errors.Is(err, service.ErrMessageBatchNotFound) {
+		message = "Message batch not found"
+	}
+	h.errorResponse(c, status, messageBatchErrorType(status), message)
+}
+
+func messageBatchErrorType(status int) string {
+	switch status {
+	case http.StatusBadRequest:
+		return "invalid_request_error"
+	case http.StatusUnauthorized:
+		return "authentication_error"
+	case http.StatusForbidden:
+		return "permission_error"
+	case http.StatusNotFound:
+		return "not_found_error"
+	case http.StatusTooManyRequests:
+		return "rate_limit_error"
+	default:
+		return "api_error"
+	}
+}
+
+func (h *MessageBatchHandler) errorResponse(c *gin.Context, status int, errType, message string) {
+	c.JSON(status, gin.H{
+		"type": "error",
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,60 @@
+package handler
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestMessageBatchToResponse_ResultsURLOnlyWhenEnded(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	c, _ := gin.CreateTestContext(httptest.NewRecorder())
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/messages/batches/msgbatch_1", nil)
+	c.Request.Host = "gw.example.com"
+	c.Request.Header.Set("X-Forwarded-Proto", "https")
+
+	batch := &service.MessageBatch{
+		ID:               "msgbatch_1",
+		ProcessingStatus: service.MessageBatchStatusInProgress,
+		CreatedAt:        time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC),
+		ExpiresAt:        time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC),
+	}
+	resp := messageBatchToResponse(c, batch)
+	require.Equal(t, "message_batch", resp.Type)
+	require.Nil(t, resp.ResultsURL)
+
+	batch.ProcessingStatus = service.MessageBatchStatusEnded
+	resp = messageBatchToResponse(c, batch)
+	require.NotNil(t, resp.ResultsURL)
+	require.Equal(t, "https://gw.example.com/v1/messages/batches/msgbatch_1/results", *resp.ResultsURL)
+}
+
+func TestMessageBatchServiceError_UsesMessagesErrorFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+
+	(&MessageBatchHandler{}).serviceError(c, service.ErrMessageBatchNotFound)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, "error", body["type"])
+	errObj := body["error"].(map[string]any)
+	require.Equal(t, "not_found_error", errObj["type"])
+	require.Equal(t, "Message batch not found", errObj["message"])
+}
+
+func TestMessageBatchErrorType(t *testing.T) {
+	require.Equal(t, "invalid_request_error", messageBatchErrorType(http.StatusBadRequest))
+	require.Equal(t, "permission_error", messageBatchErrorType(http.StatusForbidden))
+	require.Equal(t, "rate_limit_error", messageBatchErrorType(http.StatusTooManyRequests))
+	require.Equal(t, "api_error", messageBatchErrorType(http.StatusServiceUnavailable))
+}
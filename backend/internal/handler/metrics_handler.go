@@ -0,0 +1,111 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// MetricsHandler serves the Prometheus scrape endpoint and records gateway request metrics.
+type MetricsHandler struct {
+	prometheusService *service.PrometheusService
+	token             string
+	scrape            http.Handler
+}
+
+// NewMetricsHandler creates a new MetricsHandler
+func NewMetricsHandler(prometheusService *service.PrometheusService, cfg *config.Config) *MetricsHandler {
+	h := &MetricsHandler{prometheusService: prometheusService}
+	if cfg != nil {
+		h.token = cfg.Metrics.Token
+	}
+	if prometheusService.Enabled() {
+		h.scrape = prometheusService.Handler()
+	}
+	return h
+}
+
+// Enabled reports whether the /metrics endpoint and gateway metrics are active.
+func (h *MetricsHandler) Enabled() bool {
+	return h != nil && h.scrape != nil
+}
+
+// Scrape handles GET /metrics (Authorization: Bearer <metrics.token>).
+func (h *MetricsHandler) Scrape(c *gin.Context) {
+	if !h.Enabled() {
+		c.Status(http.StatusNotFound)
+		return
+	}
+	if !h.authorized(c.GetHeader("Authorization")) {
+		c.Header("WWW-Authenticate", `Bearer realm="metrics"`)
+		c.String(http.StatusUnauthorized, "unauthorized")
+		return
+	}
+	h.scrape.ServeHTTP(c.Writer, c.Request)
+}
+
+func (h *MetricsHandler) authorized(header string) bool {
+	if h.token == "" {
+		return false
+	}
+	scheme, token, ok := strings.Cut(strings.TrimSpace(header), " ")
+	if !ok || !strings.EqualFold(scheme, "Bearer") {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(strings.TrimSpace(token)), []byte(h.token)) == 1
+}
+
+// GatewayMiddleware records request count/latency/TTFT/upstream errors for gateway routes.
+// It reads the same context keys that the ops error logger relies on, so it must be
+// mounted on the gateway groups (after auth is fine, the values are read after c.Next()).
+func (h *MetricsHandler) GatewayMiddleware() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !h.Enabled() {
+			c.Next()
+			return
+		}
+		start := time.Now()
+		c.Next()
+
+		obs := service.GatewayRequestObservation{
+			Status:   c.Writer.Status(),
+			Duration: time.Since(start),
+		}
+
+		apiKey, _ := middleware2.GetAPIKeyFromContext(c)
+		if apiKey != nil && apiKey.GroupID != nil {
+			obs.GroupID = *apiKey.GroupID
+		}
+		if p, ok := c.Request.Context().Value(ctxkey.Platform).(string); ok && strings.TrimSpace(p) != "" {
+			obs.Platform = p
+		} else {
+			obs.Platform = resolveOpsPlatform(apiKey, guessPlatformFromPath(c.Request.URL.Path))
+		}
+		if v, ok := c.Get(opsModelKey); ok {
+			obs.Model, _ = v.(string)
+		}
+		if v, ok := c.Get(opsStreamKey); ok {
+			obs.Stream, _ = v.(bool)
+		}
+		if v, ok := c.Get(opsAccountIDKey); ok {
+			obs.AccountID, _ = v.(int64)
+		}
+		if ms := getContextLatencyMs(c, service.OpsTimeToFirstTokenMsKey); ms != nil {
+			ttft := time.Duration(*ms) * time.Millisecond
+			obs.TTFT = &ttft
+		}
+		if v, ok := c.Get(service.OpsUpstreamErrorsKey); ok {
+			obs.UpstreamErrors, _ = v.([]*service.OpsUpstreamErrorEvent)
+		}
+
+		h.prometheusService.ObserveGatewayRequest(obs)
+	}
+}
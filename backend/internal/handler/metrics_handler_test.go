@@ -0,0 +1,73 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestMetricsRouter(t *testing.T, enabled bool) *gin.Engine {
+	t.Helper()
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{}
+	cfg.Metrics.Enabled = enabled
+	cfg.Metrics.Token = "scrape-token"
+	cfg.Metrics.AccountLabel = true
+	h := NewMetricsHandler(service.NewPrometheusService(cfg, nil, nil, nil, nil, nil, nil), cfg)
+
+	r := gin.New()
+	r.GET("/metrics", h.Scrape)
+	r.POST("/v1/messages", h.GatewayMiddleware(), func(c *gin.Context) {
+		c.Set(opsModelKey, "claude-sonnet-4-5")
+		c.Set(opsStreamKey, false)
+		setOpsSelectedAccount(c, 42, service.PlatformAnthropic)
+		c.Status(http.StatusTooManyRequests)
+	})
+	return r
+}
+
+func TestMetricsHandler_RequiresToken(t *testing.T) {
+	r := newTestMetricsRouter(t, true)
+
+	for _, header := range []string{"", "Bearer wrong", "Basic scrape-token"} {
+		req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+		if header != "" {
+			req.Header.Set("Authorization", header)
+		}
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		require.Equal(t, http.StatusUnauthorized, rec.Code, header)
+	}
+}
+
+func TestMetricsHandler_RecordsGatewayRequests(t *testing.T) {
+	r := newTestMetricsRouter(t, true)
+	r.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodPost, "/v1/messages", nil))
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape-token")
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+
+	require.Equal(t, http.StatusOK, rec.Code)
+	require.Contains(t, rec.Body.String(), `sub2api_gateway_requests_total{account_id="42",group_id="",model="claude-sonnet-4-5",platform="anthropic",status="429",stream="false"} 1`)
+}
+
+func TestMetricsHandler_Disabled(t *testing.T) {
+	r := newTestMetricsRouter(t, false)
+	rec := httptest.NewRecorder()
+	r.ServeHTTP(rec, httptest.NewRequest(http.MethodPost, "/v1/messages", nil))
+	require.Equal(t, http.StatusTooManyRequests, rec.Code)
+
+	req := httptest.NewRequest(http.MethodGet, "/metrics", nil)
+	req.Header.Set("Authorization", "Bearer scrape-token")
+	rec = httptest.NewRecorder()
+	r.ServeHTTP(rec, req)
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
@@ -0,0 +1,81 @@
+package handler
+
+import (
+	"net/http"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// modelFallbackFromHeader 发生模型降级时返回客户端原始请求的模型
+	modelFallbackFromHeader = "X-Model-Fallback-From"
+	// modelFallbackReasonHeader 触发降级的条件（rate_limited / overloaded / prompt_too_long）
+	modelFallbackReasonHeader = "X-Model-Fallback-Reason"
+)
+
+// modelFallbackState 记录单次请求内分组模型降级链的进度
+type modelFallbackState struct {
+	plan           *service.ModelFallbackPlan
+	requestedModel string
+	used           bool
+}
+
+func newModelFallbackState(group *service.Group, model string) *modelFallbackState {
+	return &modelFallbackState{
+		plan:           group.NewModelFallbackPlan(model),
+		requestedModel: model,
+	}
+}
+
+// resetGroup 切换到兜底分组后改用该分组的降级链
+func (s *modelFallbackState) resetGroup(group *service.Group, currentModel string) {
+	s.plan = group.NewModelFallbackPlan(currentModel)
+}
+
+// advance 在给定条件下切换到降级链中的下一个模型：改写请求体并写入提示响应头。
+// 返回 false 表示未配置降级链、条件不匹配或链已耗尽。
+func (s *modelFallbackState) advance(c *gin.Context, apiKey *service.APIKey, parsedReq *service.ParsedRequest, condition string, reqLog *zap.Logger) bool {
+	if s == nil || s.plan == nil || condition == "" {
+		return false
+	}
+	var allowed func(string) bool
+	if apiKey != nil {
+		allowed = apiKey.IsModelAllowed
+	}
+	fromModel := parsedReq.Model
+	nextModel, ok := s.plan.Next(condition, allowed)
+	if !ok {
+		return false
+	}
+	if err := parsedReq.ReplaceModel(nextModel); err != nil {
+		reqLog.Warn("gateway.model_fallback_rewrite_failed", zap.String("fallback_model", nextModel), zap.Error(err))
+		return false
+	}
+	s.used = true
+	c.Header(modelFallbackFromHeader, s.requestedModel)
+	c.Header(modelFallbackReasonHeader, condition)
+	setOpsRequestContext(c, nextModel, parsedReq.Stream, parsedReq.Body)
+	reqLog.Warn("gateway.model_fallback",
+		zap.String("from_model", fromModel),
+		zap.String("to_model", nextModel),
+		zap.String("condition", condition),
+	)
+	return true
+}
+
+// modelFallbackConditionFromFailover 将换号耗尽时的最后一个上游错误映射为降级条件
+func modelFallbackConditionFromFailover(failoverErr *service.UpstreamFailoverError) string {
+	if failoverErr == nil {
+		return service.ModelFallbackConditionRateLimited
+	}
+	switch failoverErr.StatusCode {
+	case http.StatusTooManyRequests:
+		return service.ModelFallbackConditionRateLimited
+	case 529, http.StatusServiceUnavailable:
+		return service.ModelFallbackConditionOverloaded
+	default:
+		return ""
+	}
+}
@@ -0,0 +1,46 @@
+package handler
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+)
+
+func TestModelFallbackState_Advance(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	rec := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(rec)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/messages", nil)
+
+	group := &service.Group{ModelFallbackChains: []service.ModelFallbackChain{
+		{Models: []string{"claude-opus-*", "claude-sonnet-4-5", "claude-haiku-4-5"}, Conditions: []string{service.ModelFallbackConditionRateLimited}},
+	}}
+	parsed, err := service.ParseGatewayRequest([]byte(`{"model":"claude-opus-4-1","messages":[]}`), "anthropic")
+	require.NoError(t, err)
+
+	state := newModelFallbackState(group, parsed.Model)
+	require.False(t, state.advance(c, nil, parsed, service.ModelFallbackConditionOverloaded, zap.NewNop()))
+	require.False(t, state.used)
+
+	apiKey := &service.APIKey{DeniedModels: []string{"claude-sonnet-4-5"}}
+	require.True(t, state.advance(c, apiKey, parsed, service.ModelFallbackConditionRateLimited, zap.NewNop()))
+	require.True(t, state.used)
+	require.Equal(t, "claude-haiku-4-5", parsed.Model)
+	require.Equal(t, "claude-opus-4-1", rec.Header().Get(modelFallbackFromHeader))
+	require.Equal(t, service.ModelFallbackConditionRateLimited, rec.Header().Get(modelFallbackReasonHeader))
+
+	require.False(t, state.advance(c, apiKey, parsed, service.ModelFallbackConditionRateLimited, zap.NewNop()))
+}
+
+func TestModelFallbackConditionFromFailover(t *testing.T) {
+	require.Equal(t, service.ModelFallbackConditionRateLimited, modelFallbackConditionFromFailover(nil))
+	require.Equal(t, service.ModelFallbackConditionRateLimited, modelFallbackConditionFromFailover(&service.UpstreamFailoverError{StatusCode: http.StatusTooManyRequests}))
+	require.Equal(t, service.ModelFallbackConditionOverloaded, modelFallbackConditionFromFailover(&service.UpstreamFailoverError{StatusCode: 529}))
+	require.Equal(t, service.ModelFallbackConditionOverloaded, modelFallbackConditionFromFailover(&service.UpstreamFailoverError{StatusCode: http.StatusServiceUnavailable}))
+	require.Empty(t, modelFallbackConditionFromFailover(&service.UpstreamFailoverError{StatusCode: http.StatusInternalServerError}))
+}
@@ -0,0 +1,29 @@
+package handler
+
+import "github.com/Wei-Shaw/sub2api/internal/service"
+
+// filterModelsForAPIKey 按 API Key 的模型策略过滤模型列表：
+// 移除被白/黑名单拦截的模型，并把目标模型可用的别名追加到列表末尾。
+// 未配置模型策略时原样返回。
+func filterModelsForAPIKey[T any](apiKey *service.APIKey, models []T, idOf func(T) string, newAlias func(alias string) T) []T {
+	if !apiKey.HasModelPolicy() {
+		return models
+	}
+	out := make([]T, 0, len(models)+len(apiKey.ModelAliases))
+	seen := make(map[string]struct{}, len(models))
+	for _, m := range models {
+		id := idOf(m)
+		if !apiKey.IsModelAllowed(id) {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, m)
+	}
+	for _, alias := range apiKey.VisibleModelAliases() {
+		if _, ok := seen[alias]; ok {
+			continue
+		}
+		out = append(out, newAlias(alias))
+	}
+	return out
+}
@@ -0,0 +1,447 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	pkgerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// OpenAIBatchHandler 提供 OpenAI Files 与 Batch API 兼容端点
+type OpenAIBatchHandler struct {
+	batchService        *service.OpenAIBatchService
+	fileService         *service.OpenAIFileService
+	billingCacheService *service.BillingCacheService
+}
+
+// NewOpenAIBatchHandler 创建 OpenAI Files/Batch 处理器
+func NewOpenAIBatchHandler(
+	batchService *service.OpenAIBatchService,
+	fileService *service.OpenAIFileService,
+	billingCacheService *service.BillingCacheService,
+) *OpenAIBatchHandler {
+	return &OpenAIBatchHandler{
+		batchService:        batchService,
+		fileService:         fileService,
+		billingCacheService: billingCacheService,
+	}
+}
+
+// openAIFileResponse File 对象（与 OpenAI API 字段一致）
+type openAIFileResponse struct {
+	ID        string `json:"id"`
+	Object    string `json:"object"`
+	Bytes     int64  `json:"bytes"`
+	CreatedAt int64  `json:"created_at"`
+	ExpiresAt int64  `json:"expires_at"`
+	Filename  string `json:"filename"`
+	Purpose   string `json:"purpose"`
+	Status    string `json:"status"`
+}
+
+func openAIFileToResponse(file *service.OpenAIFile) openAIFileResponse {
+	return openAIFileResponse{
+		ID:        file.ID,
+		Object:    "file",
+		Bytes:     file.Bytes,
+		CreatedAt: file.CreatedAt.Unix(),
+		ExpiresAt: file.ExpiresAt.Unix(),
+		Filename:  file.Filename,
+		Purpose:   file.Purpose,
+		Status:    "processed",
+	}
+}
+
+// openAIBatchErrorsResponse 批次校验错误列表
+type openAIBatchErrorsResponse struct {
+	Object string                     `json:"object"`
+	Data   []service.OpenAIBatchError `json:"data"`
+}
+
+// openAIBatchResponse Batch 对象（与 OpenAI API 字段一致，时间为 Unix 秒）
+type openAIBatchResponse struct {
+	ID               string                           `json:"id"`
+	Object           string                           `json:"object"`
+	Endpoint         string                           `json:"endpoint"`
+	Errors           *openAIBatchErrorsResponse       `json:"errors"`
+	InputFileID      string                           `json:"input_file_id"`
+	CompletionWindow string                           `json:"completion_window"`
+	Status           string                           `json:"status"`
+	OutputFileID     *string                          `json:"output_file_id"`
+	ErrorFileID      *string                          `json:"error_file_id"`
+	CreatedAt        int64                            `json:"created_at"`
+	InProgressAt     *int64                           `json:"in_progress_at"`
+	ExpiresAt        int64                            `json:"expires_at"`
+	FinalizingAt     *int64                           `json:"finalizing_at"`
+	CompletedAt      *int64                           `json:"completed_at"`
+	FailedAt         *int64                           `json:"failed_at"`
+	ExpiredAt        *int64                           `json:"expired_at"`
+	CancellingAt     *int64                           `json:"cancelling_at"`
+	CancelledAt      *int64                           `json:"cancelled_at"`
+	RequestCounts    service.OpenAIBatchRequestCounts `json:"request_counts"`
+	Metadata         map[string]string                `json:"metadata"`
+}
+
+func openAIBatchToResponse(batch *service.OpenAIBatch) openAIBatchResponse {
+	resp := openAIBatchResponse{
+		ID:               batch.ID,
+		Object:           "batch",
+		Endpoint:         batch.Endpoint,
+		InputFileID:      batch.InputFileID,
+		CompletionWindow: batch.CompletionWindow,
+		Status:           batch.Status,
+		OutputFileID:     batch.OutputFileID,
+		ErrorFileID:      batch.ErrorFileID,
+		CreatedAt:        batch.CreatedAt.Unix(),
+		InProgressAt:     unixSecondsPtr(batch.InProgressAt),
+		ExpiresAt:        batch.ExpiresAt.Unix(),
+		FinalizingAt:     unixSecondsPtr(batch.FinalizingAt),
+		CompletedAt:      unixSecondsPtr(batch.CompletedAt),
+		FailedAt:         unixSecondsPtr(batch.FailedAt),
+		ExpiredAt:        unixSecondsPtr(batch.ExpiredAt),
+		CancellingAt:     unixSecondsPtr(batch.CancellingAt),
+		CancelledAt:      unixSecondsPtr(batch.CancelledAt),
+		RequestCounts:    batch.RequestCounts,
+		Metadata:         batch.Metadata,
+	}
+	if len(batch.Errors) > 0 {
+		resp.Errors = &openAIBatchErrorsResponse{Object: "list", Data: batch.Errors}
+	}
+	return resp
+}
+
+func unixSecondsPtr(t *time.Time) *int64 {
+	if t == nil {
+		return nil
+	}
+	v := t.Unix()
+	return &v
+}
+
+// UploadFile 上传批处理输入文件（multipart/form-data，字段 file 与 purpose）
+// POST /v1/files
+func (h *OpenAIBatchHandler) UploadFile(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	if !h.fileService.Enabled() {
+		h.serviceError(c, service.ErrOpenAIBatchDisabled)
+		return
+	}
+	reqLog := requestLogger(c, "handler.openai_batch.upload_file",
+		zap.Int64("api_key_id", apiKey.ID),
+		zap.Any("group_id", apiKey.GroupID),
+	)
+
+	fileHeader, err := c.FormFile("file")
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			h.errorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "A multipart form with a 'file' field is required")
+		return
+	}
+	content, err := fileHeader.Open()
+	if err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to read uploaded file")
+		return
+	}
+	defer func() { _ = content.Close() }()
+
+	file, err := h.fileService.Upload(c.Request.Context(), service.UploadOpenAIFileInput{
+		UserID:   apiKey.UserID,
+		Purpose:  c.PostForm("purpose"),
+		Filename: fileHeader.Filename,
+		Content:  content,
+	})
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	reqLog.Info("openai_file.uploaded", zap.String("file_id", file.ID), zap.Int64("bytes", file.Bytes))
+	c.JSON(http.StatusOK, openAIFileToResponse(file))
+}
+
+// ListFiles 列出文件
+// GET /v1/files
+func (h *OpenAIBatchHandler) ListFiles(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	params := service.OpenAIFileListParams{
+		Purpose: c.Query("purpose"),
+		After:   c.Query("after"),
+		Order:   c.Query("order"),
+	}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > service.OpenAIFileListMaxLimit {
+			h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "limit must be between 1 and 10000")
+			return
+		}
+		params.Limit = limit
+	}
+
+	files, hasMore, err := h.fileService.List(c.Request.Context(), apiKey.UserID, params)
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	data := make([]openAIFileResponse, 0, len(files))
+	for i := range files {
+		data = append(data, openAIFileToResponse(&files[i]))
+	}
+	c.JSON(http.StatusOK, openAIListResponse(data, hasMore, func(i int) string { return data[i].ID }))
+}
+
+// GetFile 查询文件
+// GET /v1/files/:id
+func (h *OpenAIBatchHandler) GetFile(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	file, err := h.fileService.Get(c.Request.Context(), apiKey.UserID, c.Param("id"))
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, openAIFileToResponse(file))
+}
+
+// FileContent 下载文件内容
+// GET /v1/files/:id/content
+func (h *OpenAIBatchHandler) FileContent(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	file, content, err := h.fileService.Open(c.Request.Context(), apiKey.UserID, c.Param("id"))
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	defer func() { _ = content.Close() }()
+
+	c.Header("Content-Type", "application/octet-stream")
+	c.Header("Content-Length", strconv.FormatInt(file.Bytes, 10))
+	c.Status(http.StatusOK)
+	if _, err := io.Copy(c.Writer, content); err != nil {
+		// 已开始输出，只能中断响应
+		requestLogger(c, "handler.openai_batch.file_content").Warn("openai_file.content_stream_failed",
+			zap.String("file_id", file.ID), zap.Error(err))
+	}
+}
+
+// DeleteFile 删除文件
+// DELETE /v1/files/:id
+func (h *OpenAIBatchHandler) DeleteFile(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	id := c.Param("id")
+	if err := h.fileService.Delete(c.Request.Context(), apiKey.UserID, id); err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"id":      id,
+		"object":  "file",
+		"deleted": true,
+	})
+}
+
+// CreateBatch 创建批次
+// POST /v1/batches
+func (h *OpenAIBatchHandler) CreateBatch(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	reqLog := requestLogger(c, "handler.openai_batch.create",
+		zap.Int64("api_key_id", apiKey.ID),
+		zap.Any("group_id", apiKey.GroupID),
+	)
+
+	body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
+	if err != nil {
+		if maxErr, ok := extractMaxBytesError(err); ok {
+			h.errorResponse(c, http.StatusRequestEntityTooLarge, "invalid_request_error", buildBodyTooLargeMessage(maxErr.Limit))
+			return
+		}
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to read request body")
+		return
+	}
+	var req struct {
+		InputFileID      string            `json:"input_file_id"`
+		Endpoint         string            `json:"endpoint"`
+		CompletionWindow string            `json:"completion_window"`
+		Metadata         map[string]string `json:"metadata"`
+	}
+	if err := json.Unmarshal(body, &req); err != nil {
+		h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "Failed to parse request body")
+		return
+	}
+
+	subscription, _ := middleware2.GetSubscriptionFromContext(c)
+	if h.billingCacheService != nil {
+		if err := h.billingCacheService.CheckBillingEligibility(c.Request.Context(), apiKey.User, apiKey, apiKey.Group, subscription); err != nil {
+			reqLog.Info("openai_batch.billing_eligibility_check_failed", zap.Error(err))
+			status, code, message := billingErrorDetails(err)
+			h.errorResponse(c, status, code, message)
+			return
+		}
+	}
+
+	batch, err := h.batchService.Create(c.Request.Context(), service.CreateOpenAIBatchInput{
+		APIKey:           apiKey,
+		InputFileID:      req.InputFileID,
+		Endpoint:         req.Endpoint,
+		CompletionWindow: req.CompletionWindow,
+		Metadata:         req.Metadata,
+	})
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	reqLog.Info("openai_batch.created", zap.String("batch_id", batch.ID), zap.String("input_file_id", batch.InputFileID))
+	c.JSON(http.StatusOK, openAIBatchToResponse(batch))
+}
+
+// GetBatch 查询批次
+// GET /v1/batches/:id
+func (h *OpenAIBatchHandler) GetBatch(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	batch, err := h.batchService.Get(c.Request.Context(), apiKey.UserID, c.Param("id"))
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, openAIBatchToResponse(batch))
+}
+
+// ListBatches 列出批次（按创建时间倒序）
+// GET /v1/batches
+func (h *OpenAIBatchHandler) ListBatches(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	params := service.OpenAIBatchListParams{After: c.Query("after")}
+	if raw := c.Query("limit"); raw != "" {
+		limit, err := strconv.Atoi(raw)
+		if err != nil || limit < 1 || limit > service.OpenAIBatchListMaxLimit {
+			h.errorResponse(c, http.StatusBadRequest, "invalid_request_error", "limit must be between 1 and 100")
+			return
+		}
+		params.Limit = limit
+	}
+
+	batches, hasMore, err := h.batchService.List(c.Request.Context(), apiKey.UserID, params)
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	data := make([]openAIBatchResponse, 0, len(batches))
+	for i := range batches {
+		data = append(data, openAIBatchToResponse(&batches[i]))
+	}
+	c.JSON(http.StatusOK, openAIListResponse(data, hasMore, func(i int) string { return data[i].ID }))
+}
+
+// CancelBatch 取消批次
+// POST /v1/batches/:id/cancel
+func (h *OpenAIBatchHandler) CancelBatch(c *gin.Context) {
+	apiKey, ok := middleware2.GetAPIKeyFromContext(c)
+	if !ok {
+		h.errorResponse(c, http.StatusUnauthorized, "authentication_error", "Invalid API key")
+		return
+	}
+	batch, err := h.batchService.Cancel(c.Request.Context(), apiKey.UserID, c.Param("id"))
+	if err != nil {
+		h.serviceError(c, err)
+		return
+	}
+	c.JSON(http.StatusOK, openAIBatchToResponse(batch))
+}
+
+// openAIListResponse 构造 OpenAI 列表对象
+func openAIListResponse[T any](data []T, hasMore bool, idAt func(int) string) gin.H {
+	var firstID, lastID *string
+	if len(data) > 0 {
+		first, last := idAt(0), idAt(len(data)-1)
+		firstID, lastID = &first, &last
+	}
+	return gin.H{
+		"object":   "list",
+		"data":     data,
+		"first_id": firstID,
+		"last_id":  lastID,
+		"has_more": hasMore,
+	}
+}
+
+// serviceError 将服务层错误映射为 OpenAI API 错误格式
+func (h *OpenAIBatchHandler) serviceError(c *gin.Context, err error) {
+	status := pkgerrors.Code(err)
+	message := pkgerrors.Message(err)
+	switch {
+	case errors.Is(err, service.ErrOpenAIFileNotFound):
+		message = "No such File object: " + c.Param("id")
+	case errors.Is(err, service.ErrOpenAIBatchNotFound):
+		message = "No such Batch object: " + c.Param("id")
+	}
+	if status >= http.StatusInternalServerError && status != http.StatusServiceUnavailable {
+		requestLogger(c, "handler.openai_batch").Error("openai_batch.internal_error", zap.Error(err))
+		message = "Internal server error"
+	}
+	h.errorResponse(c, status, openAIBatchErrorType(status), message)
+}
+
+func openAIBatchErrorType(status int) string {
+	switch status {
+	case http.StatusBadRequest, http.StatusNotFound, http.StatusRequestEntityTooLarge:
+		return "invalid_request_error"
+	case http.StatusUnauthorized:
+		return "authentication_error"
+	case http.StatusForbidden:
+		return "permission_error"
+	case http.StatusTooManyRequests:
+		return "rate_limit_error"
+	default:
+		return "api_error"
+	}
+}
+
+func (h *OpenAIBatchHandler) errorResponse(c *gin.Context, status int, errType, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{
+			"type":    errType,
+			"message": message,
+		},
+	})
+}
@@ -0,0 +1,94 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func TestOpenAIBatchToResponse(t *testing.T) {
+	created := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	inProgress := created.Add(time.Minute)
+	batch := &service.OpenAIBatch{
+		ID:               "batch_1",
+		Endpoint:         service.OpenAIBatchEndpointResponses,
+		InputFileID:      "file-in",
+		CompletionWindow: "24h",
+		Status:           service.OpenAIBatchStatusInProgress,
+		RequestCounts:    service.OpenAIBatchRequestCounts{Total: 3, Completed: 1},
+		InProgressAt:     &inProgress,
+		CreatedAt:        created,
+		ExpiresAt:        created.Add(24 * time.Hour),
+	}
+
+	raw, err := json.Marshal(openAIBatchToResponse(batch))
+	require.NoError(t, err)
+	var got map[string]any
+	require.NoError(t, json.Unmarshal(raw, &got))
+	require.Equal(t, "batch", got["object"])
+	require.Nil(t, got["errors"])
+	require.Nil(t, got["output_file_id"])
+	require.Nil(t, got["completed_at"])
+	require.Equal(t, float64(created.Unix()), got["created_at"])
+	require.Equal(t, float64(inProgress.Unix()), got["in_progress_at"])
+	require.Equal(t, map[string]any{"total": float64(3), "completed": float64(1), "failed": float64(0)}, got["request_counts"])
+
+	line := 2
+	batch.Status = service.OpenAIBatchStatusFailed
+	batch.Errors = []service.OpenAIBatchError{{Code: "invalid_json_line", Message: "bad", Line: &line}}
+	resp := openAIBatchToResponse(batch)
+	require.NotNil(t, resp.Errors)
+	require.Equal(t, "list", resp.Errors.Object)
+	require.Len(t, resp.Errors.Data, 1)
+}
+
+func TestOpenAIListResponse(t *testing.T) {
+	files := []openAIFileResponse{{ID: "file-a"}, {ID: "file-b"}}
+	resp := openAIListResponse(files, true, func(i int) string { return files[i].ID })
+	require.Equal(t, "list", resp["object"])
+	require.Equal(t, "file-a", *resp["first_id"].(*string))
+	require.Equal(t, "file-b", *resp["last_id"].(*string))
+	require.Equal(t, true, resp["has_more"])
+
+	empty := openAIListResponse([]openAIFileResponse{}, false, nil)
+	require.Nil(t, empty["first_id"])
+	require.Nil(t, empty["last_id"])
+}
+
+func TestOpenAIBatchServiceError_UsesOpenAIErrorFormat(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/v1/files/file-x", nil)
+	c.Params = gin.Params{{Key: "id", Value: "file-x"}}
+
+	(&OpenAIBatchHandler{}).serviceError(c, service.ErrOpenAIFileNotFound)
+
+	require.Equal(t, http.StatusNotFound, w.Code)
+	var body map[string]map[string]string
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &body))
+	require.Equal(t, "invalid_request_error", body["error"]["type"])
+	require.Equal(t, "No such File object: file-x", body["error"]["message"])
+
+	w = httptest.NewRecorder()
+	c, _ = gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodPost, "/v1/batches", nil)
+	(&OpenAIBatchHandler{}).serviceError(c, errors.New("pq: connection refused"))
+	require.Equal(t, http.StatusInternalServerError, w.Code)
+	require.NotContains(t, w.Body.String(), "pq:")
+}
+
+func TestOpenAIBatchErrorType(t *testing.T) {
+	require.Equal(t, "invalid_request_error", openAIBatchErrorType(http.StatusBadRequest))
+	require.Equal(t, "invalid_request_error", openAIBatchErrorType(http.StatusNotFound))
+	require.Equal(t, "invalid_request_error", openAIBatchErrorType(http.StatusRequestEntityTooLarge))
+	require.Equal(t, "permission_error", openAIBatchErrorType(http.StatusForbidden))
+	require.Equal(t, "api_error", openAIBatchErrorType(http.StatusServiceUnavailable))
+}
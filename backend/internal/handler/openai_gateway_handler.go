@@ -15,12 +15,14 @@ import (
 	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
 	coderws "github.com/coder/websocket"
 	"github.com/gin-gonic/gin"
 	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
 	"go.uber.org/zap"
 )
 
@@ -31,6 +33,7 @@ type OpenAIGatewayHandler struct {
 	apiKeyService           *service.APIKeyService
 	usageRecordWorkerPool   *service.UsageRecordWorkerPool
 	errorPassthroughService *service.ErrorPassthroughService
+	responseCacheService    *service.ResponseCacheService
 	concurrencyHelper       *ConcurrencyHelper
 	maxAccountSwitches      int
 	cfg                     *config.Config
@@ -44,6 +47,7 @@ func NewOpenAIGatewayHandler(
 	apiKeyService *service.APIKeyService,
 	usageRecordWorkerPool *service.UsageRecordWorkerPool,
 	errorPassthroughService *service.ErrorPassthroughService,
+	responseCacheService *service.ResponseCacheService,
 	cfg *config.Config,
 ) *OpenAIGatewayHandler {
 	pingInterval := time.Duration(0)
@@ -60,6 +64,7 @@ func NewOpenAIGatewayHandler(
 		apiKeyService:           apiKeyService,
 		usageRecordWorkerPool:   usageRecordWorkerPool,
 		errorPassthroughService: errorPassthroughService,
+		responseCacheService:    responseCacheService,
 		concurrencyHelper:       NewConcurrencyHelper(concurrencyService, SSEPingFormatComment, pingInterval),
 		maxAccountSwitches:      maxAccountSwitches,
 		cfg:                     cfg,
@@ -206,6 +211,17 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 		return
 	}
 
+	// 精确响应缓存：确定性请求命中时直接返回，不占用上游账号（compact 请求不缓存）
+	var cacheReq *service.ResponseCacheRequest
+	if !service.IsOpenAIResponsesCompactPathForTest(c) {
+		cacheReq = h.responseCacheService.Prepare(apiKey.Group, service.ResponseCacheEndpointResponses, body)
+	}
+	if serveResponseCacheHit(c, h.responseCacheService, cacheReq, apiKey, subscription, reqStream, h.apiKeyService, h.submitUsageRecordTask, reqLog) {
+		return
+	}
+	cacheCapture, restoreWriter := installResponseCacheCapture(c, h.responseCacheService, cacheReq, reqStream)
+	defer restoreWriter()
+
 	// Generate session hash (header first; fallback to prompt_cache_key)
 	sessionHash := h.gatewayService.GenerateSessionHash(c, sessionHashBody)
 
@@ -326,13 +342,14 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 		} else {
 			h.gatewayService.ReportOpenAIAccountScheduleResult(account.ID, true, nil)
 		}
+		storeOpenAIResponseCache(c.Request.Context(), h.responseCacheService, cacheReq, cacheCapture, account.ID, result)
 
 		// 捕获请求信息（用于异步记录，避免在 goroutine 中访问 gin.Context）
 		userAgent := c.GetHeader("User-Agent")
 		clientIP := ip.GetClientIP(c)
 
 		// 使用量记录通过有界 worker 池提交，避免请求热路径创建无界 goroutine。
-		h.submitUsageRecordTask(func(ctx context.Context) {
+		h.submitUsageRecordTask(tracing.WithParentSpan(c.Request.Context(), func(ctx context.Context) {
 			if err := h.gatewayService.RecordUsage(ctx, &service.OpenAIRecordUsageInput{
 				Result:        result,
 				APIKey:        apiKey,
@@ -352,7 +369,7 @@ func (h *OpenAIGatewayHandler) Responses(c *gin.Context) {
 					zap.Int64("account_id", account.ID),
 				).Error("openai.record_usage_failed", zap.Error(err))
 			}
-		})
+		}))
 		reqLog.Debug("openai.request_completed",
 			zap.Int64("account_id", account.ID),
 			zap.Int("switch_count", switchCount),
@@ -535,6 +552,14 @@ func (h *OpenAIGatewayHandler) Messages(c *gin.Context) {
 		return
 	}
 
+	// 精确响应缓存：确定性请求命中时直接返回，不占用上游账号
+	cacheReq := h.responseCacheService.Prepare(apiKey.Group, service.ResponseCacheEndpointMessages, body)
+	if serveResponseCacheHit(c, h.responseCacheService, cacheReq, apiKey, subscription, reqStream, h.apiKeyService, h.submitUsageRecordTask, reqLog) {
+		return
+	}
+	cacheCapture, restoreWriter := installResponseCacheCapture(c, h.responseCacheService, cacheReq, reqStream)
+	defer restoreWriter()
+
 	sessionHash := h.gatewayService.GenerateSessionHash(c, body)
 	promptCacheKey := h.gatewayService.ExtractSessionID(c, body)
 
@@ -671,11 +696,15 @@ func (h *OpenAIGatewayHandler) Messages(c *gin.Context) {
 		} else {
 			h.gatewayService.ReportOpenAIAccountScheduleResult(account.ID, true, nil)
 		}
+		// 降级到默认映射模型时响应与请求模型不一致，不写入缓存
+		if c.GetString("openai_messages_fallback_model") == "" {
+			storeOpenAIResponseCache(c.Request.Context(), h.responseCacheService, cacheReq, cacheCapture, account.ID, result)
+		}
 
 		userAgent := c.GetHeader("User-Agent")
 		clientIP := ip.GetClientIP(c)
 
-		h.submitUsageRecordTask(func(ctx context.Context) {
+		h.submitUsageRecordTask(tracing.WithParentSpan(c.Request.Context(), func(ctx context.Context) {
 			if err := h.gatewayService.RecordUsage(ctx, &service.OpenAIRecordUsageInput{
 				Result:        result,
 				APIKey:        apiKey,
@@ -695,7 +724,7 @@ func (h *OpenAIGatewayHandler) Messages(c *gin.Context) {
 					zap.Int64("account_id", account.ID),
 				).Error("openai_messages.record_usage_failed", zap.Error(err))
 			}
-		})
+		}))
 		reqLog.Debug("openai_messages.request_completed",
 			zap.Int64("account_id", account.ID),
 			zap.Int("switch_count", switchCount),
@@ -1012,6 +1041,18 @@ func (h *OpenAIGatewayHandler) ResponsesWebSocket(c *gin.Context) {
 		closeOpenAIClientWS(wsConn, coderws.StatusPolicyViolation, "model is required in first response.create payload")
 		return
 	}
+	// WebSocket 握手时模型尚未确定，认证中间件无法检查，这里对首条消息补充执行 Key 模型策略
+	resolvedModel := apiKey.ResolveModelAlias(reqModel)
+	if !apiKey.IsModelAllowed(resolvedModel) {
+		closeOpenAIClientWS(wsConn, coderws.StatusPolicyViolation, "model is not allowed for this API key")
+		return
+	}
+	if resolvedModel != reqModel {
+		if rewritten, setErr := sjson.SetBytes(firstMessage, "model", resolvedModel); setErr == nil {
+			firstMessage = rewritten
+			reqModel = resolvedModel
+		}
+	}
 	previousResponseID := strings.TrimSpace(gjson.GetBytes(firstMessage, "previous_response_id").String())
 	previousResponseIDKind := service.ClassifyOpenAIPreviousResponseIDKind(previousResponseID)
 	if previousResponseID != "" && previousResponseIDKind == service.OpenAIPreviousResponseIDKindMessageID {
@@ -0,0 +1,296 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// OrganizationHandler handles organization (team) requests for end users
+type OrganizationHandler struct {
+	orgService *service.OrganizationService
+}
+
+// NewOrganizationHandler creates a new OrganizationHandler
+func NewOrganizationHandler(orgService *service.OrganizationService) *OrganizationHandler {
+	return &OrganizationHandler{orgService: orgService}
+}
+
+// CreateOrganizationRequest represents the create/rename organization payload
+type CreateOrganizationRequest struct {
+	Name string `json:"name" binding:"required,max=100"`
+}
+
+// AddOrganizationMemberRequest represents the add member payload
+type AddOrganizationMemberRequest struct {
+	Email           string  `json:"email" binding:"required,email"`
+	Role            string  `json:"role" binding:"omitempty,oneof=admin member"`
+	MonthlyLimitUSD float64 `json:"monthly_limit_usd" binding:"min=0"` // 0 = 不限制
+}
+
+// UpdateOrganizationMemberRequest represents the update member payload
+type UpdateOrganizationMemberRequest struct {
+	Role            *string  `json:"role" binding:"omitempty,oneof=admin member"`
+	MonthlyLimitUSD *float64 `json:"monthly_limit_usd" binding:"omitempty,min=0"`
+}
+
+// OrganizationDetail is the response of GET /organizations/:id
+type OrganizationDetail struct {
+	Organization *dto.Organization       `json:"organization"`
+	Membership   *dto.OrganizationMember `json:"membership"`
+}
+
+// List handles listing organizations the current user belongs to
+// GET /api/v1/organizations
+func (h *OrganizationHandler) List(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	memberships, err := h.orgService.ListForUser(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.OrganizationMember, 0, len(memberships))
+	for i := range memberships {
+		out = append(out, *dto.OrganizationMemberFromService(&memberships[i]))
+	}
+	response.Success(c, out)
+}
+
+// Create handles creating an organization owned by the current user
+// POST /api/v1/organizations
+func (h *OrganizationHandler) Create(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	org, err := h.orgService.Create(c.Request.Context(), subject.UserID, req.Name)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.OrganizationFromService(org))
+}
+
+// GetByID handles getting an organization together with the caller's membership
+// GET /api/v1/organizations/:id
+func (h *OrganizationHandler) GetByID(c *gin.Context) {
+	subject, orgID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	org, member, err := h.orgService.Get(c.Request.Context(), subject.UserID, orgID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, OrganizationDetail{
+		Organization: dto.OrganizationFromService(org),
+		Membership:   dto.OrganizationMemberFromService(member),
+	})
+}
+
+// Update handles renaming an organization
+// PUT /api/v1/organizations/:id
+func (h *OrganizationHandler) Update(c *gin.Context) {
+	subject, orgID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	var req CreateOrganizationRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	org, err := h.orgService.Rename(c.Request.Context(), subject.UserID, orgID, req.Name)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.OrganizationFromService(org))
+}
+
+// Delete handles deleting an organization (owner only)
+// DELETE /api/v1/organizations/:id
+func (h *OrganizationHandler) Delete(c *gin.Context) {
+	subject, orgID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.orgService.Delete(c.Request.Context(), subject.UserID, orgID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Organization deleted successfully"})
+}
+
+// ListMembers handles listing organization members
+// GET /api/v1/organizations/:id/members
+func (h *OrganizationHandler) ListMembers(c *gin.Context) {
+	subject, orgID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	members, err := h.orgService.ListMembers(c.Request.Context(), subject.UserID, orgID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.OrganizationMember, 0, len(members))
+	for i := range members {
+		out = append(out, *dto.OrganizationMemberFromService(&members[i]))
+	}
+	response.Success(c, out)
+}
+
+// AddMember handles adding a registered user to the organization by email
+// POST /api/v1/organizations/:id/members
+func (h *OrganizationHandler) AddMember(c *gin.Context) {
+	subject, orgID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	var req AddOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	member, err := h.orgService.AddMember(c.Request.Context(), subject.UserID, orgID, service.AddOrganizationMemberInput{
+		Email:           req.Email,
+		Role:            req.Role,
+		MonthlyLimitUSD: req.MonthlyLimitUSD,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.OrganizationMemberFromService(member))
+}
+
+// UpdateMember handles changing a member's role or monthly spending cap
+// PUT /api/v1/organizations/:id/members/:user_id
+func (h *OrganizationHandler) UpdateMember(c *gin.Context) {
+	subject, orgID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+	memberUserID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	var req UpdateOrganizationMemberRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	member, err := h.orgService.UpdateMember(c.Request.Context(), subject.UserID, orgID, memberUserID, service.UpdateOrganizationMemberInput{
+		Role:            req.Role,
+		MonthlyLimitUSD: req.MonthlyLimitUSD,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.OrganizationMemberFromService(member))
+}
+
+// RemoveMember handles removing a member (or leaving the organization)
+// DELETE /api/v1/organizations/:id/members/:user_id
+func (h *OrganizationHandler) RemoveMember(c *gin.Context) {
+	subject, orgID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+	memberUserID, err := strconv.ParseInt(c.Param("user_id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid user ID")
+		return
+	}
+
+	if err := h.orgService.RemoveMember(c.Request.Context(), subject.UserID, orgID, memberUserID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Member removed successfully"})
+}
+
+// ListAPIKeys handles listing organization keys (managers see all, members see their own)
+// GET /api/v1/organizations/:id/keys
+func (h *OrganizationHandler) ListAPIKeys(c *gin.Context) {
+	subject, orgID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	keys, err := h.orgService.ListAPIKeys(c.Request.Context(), subject.UserID, orgID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.OrganizationAPIKey, 0, len(keys))
+	for i := range keys {
+		out = append(out, *dto.OrganizationAPIKeyFromService(&keys[i]))
+	}
+	response.Success(c, out)
+}
+
+// Usage handles the organization usage dashboard
+// GET /api/v1/organizations/:id/usage?start_date=&end_date=&timezone=
+func (h *OrganizationHandler) Usage(c *gin.Context) {
+	subject, orgID, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	startTime, endTime := parseUserTimeRange(c)
+	summary, err := h.orgService.GetUsageSummary(c.Request.Context(), subject.UserID, orgID, startTime, endTime)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, summary)
+}
+
+func (h *OrganizationHandler) parseRequest(c *gin.Context) (middleware2.AuthSubject, int64, bool) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return middleware2.AuthSubject{}, 0, false
+	}
+	orgID, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid organization ID")
+		return middleware2.AuthSubject{}, 0, false
+	}
+	return subject, orgID, true
+}
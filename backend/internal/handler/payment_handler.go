@@ -1,12 +1,18 @@
 package handler
 
 import (
+	"io"
 	"net/http"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
 )
 
+// paymentNotifyMaxBodySize 回调请求体上限
+const paymentNotifyMaxBodySize = 64 << 10
+
 // PaymentHandler handles payment callbacks.
 type PaymentHandler struct {
 	orderService *service.SubscriptionOrderService
@@ -17,27 +23,25 @@ func NewPaymentHandler(orderService *service.SubscriptionOrderService) *PaymentH
 	return &PaymentHandler{orderService: orderService}
 }
 
-// XunhuPayNotify handles XunhuPay notify callbacks.
-// POST /api/v1/payment/xunhupay/notify
-func (h *PaymentHandler) XunhuPayNotify(c *gin.Context) {
-	payload := service.XunhuPayNotifyPayload{
-		AppID:         c.PostForm("appid"),
-		TradeOrderID:  c.PostForm("trade_order_id"),
-		TotalFee:      c.PostForm("total_fee"),
-		TransactionID: c.PostForm("transaction_id"),
-		OpenOrderID:   c.PostForm("open_order_id"),
-		OrderTitle:    c.PostForm("order_title"),
-		Status:        c.PostForm("status"),
-		Plugins:       c.PostForm("plugins"),
-		Attach:        c.PostForm("attach"),
-		Time:          c.PostForm("time"),
-		NonceStr:      c.PostForm("nonce_str"),
-		Hash:          c.PostForm("hash"),
-	}
-
-	if err := h.orderService.HandleXunhuPayNotify(c.Request.Context(), payload); err != nil {
+// Notify handles payment provider notify callbacks (xunhupay/stripe/alipay/wechatpay).
+// POST /api/v1/payment/:provider/notify
+func (h *PaymentHandler) Notify(c *gin.Context) {
+	provider := c.Param("provider")
+	body, err := io.ReadAll(io.LimitReader(c.Request.Body, paymentNotifyMaxBodySize))
+	if err != nil {
 		c.String(http.StatusBadRequest, "fail")
 		return
 	}
-	c.String(http.StatusOK, "success")
+
+	ack, err := h.orderService.HandlePaymentNotify(c.Request.Context(), provider, &service.PaymentNotifyRequest{
+		Header: c.Request.Header,
+		Body:   body,
+	})
+	if err != nil {
+		logger.FromContext(c.Request.Context()).Warn("payment.notify_rejected",
+			zap.String("provider", provider),
+			zap.Error(err),
+		)
+	}
+	c.Data(ack.StatusCode, ack.ContentType, []byte(ack.Body))
 }
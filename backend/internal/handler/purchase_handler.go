@@ -62,6 +62,49 @@ func (h *PurchaseHandler) CreateOrder(c *gin.Context) {
 	response.Success(c, dto.SubscriptionOrderFromService(order))
 }
 
+// GetTopUpConfig handles getting the balance top-up options
+// GET /api/v1/purchase/topup
+func (h *PurchaseHandler) GetTopUpConfig(c *gin.Context) {
+	cfg, err := h.orderService.GetTopUpConfig(c.Request.Context())
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.TopUpConfig{
+		Enabled:   cfg.Enabled,
+		MinAmount: cfg.MinAmount,
+		MaxAmount: cfg.MaxAmount,
+		Rate:      cfg.Rate,
+		Currency:  cfg.Currency,
+	})
+}
+
+// CreateTopUpOrder handles creating a balance top-up order
+// POST /api/v1/purchase/topup
+func (h *PurchaseHandler) CreateTopUpOrder(c *gin.Context) {
+	subject, ok := middleware.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not found in context")
+		return
+	}
+
+	var req struct {
+		Amount float64 `json:"amount" binding:"required,gt=0"`
+		Notes  string  `json:"notes"`
+	}
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	order, err := h.orderService.CreateTopUpOrder(c.Request.Context(), subject.UserID, req.Amount, req.Notes)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.SubscriptionOrderFromService(order))
+}
+
 // ListOrders handles listing current user's orders
 // GET /api/v1/purchase/orders
 func (h *PurchaseHandler) ListOrders(c *gin.Context) {
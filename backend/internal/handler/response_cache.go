@@ -0,0 +1,219 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+// responseCacheCaptureWriter 在写给客户端的同时旁路保存成功的非流式响应体，
+// 超过大小上限后放弃保存（不影响正常输出）。
+type responseCacheCaptureWriter struct {
+	gin.ResponseWriter
+	limit    int
+	buf      bytes.Buffer
+	overflow bool
+}
+
+func newResponseCacheCaptureWriter(rw gin.ResponseWriter, limit int) *responseCacheCaptureWriter {
+	return &responseCacheCaptureWriter{ResponseWriter: rw, limit: limit}
+}
+
+func (w *responseCacheCaptureWriter) capture(b []byte) {
+	if w.overflow || w.ResponseWriter.Status() != http.StatusOK {
+		return
+	}
+	if w.buf.Len()+len(b) > w.limit {
+		w.overflow = true
+		w.buf = bytes.Buffer{}
+		return
+	}
+	_, _ = w.buf.Write(b)
+}
+
+func (w *responseCacheCaptureWriter) Write(b []byte) (int, error) {
+	w.capture(b)
+	return w.ResponseWriter.Write(b)
+}
+
+func (w *responseCacheCaptureWriter) WriteString(s string) (int, error) {
+	w.capture([]byte(s))
+	return w.ResponseWriter.WriteString(s)
+}
+
+// body 返回完整保存的响应体；非 200 或超限时返回 nil
+func (w *responseCacheCaptureWriter) body() []byte {
+	if w.overflow || w.ResponseWriter.Status() != http.StatusOK || w.buf.Len() == 0 {
+		return nil
+	}
+	return bytes.Clone(w.buf.Bytes())
+}
+
+// installResponseCacheCapture 为可缓存的非流式请求安装响应捕获；返回的 restore 用于还原 Writer
+func installResponseCacheCapture(c *gin.Context, cacheService *service.ResponseCacheService, cacheReq *service.ResponseCacheRequest, stream bool) (*responseCacheCaptureWriter, func()) {
+	if cacheReq == nil || stream {
+		return nil, func() {}
+	}
+	original := c.Writer
+	capture := newResponseCacheCaptureWriter(original, cacheService.MaxEntryBytes())
+	c.Writer = capture
+	return capture, func() {
+		if c.Writer == capture {
+			c.Writer = original
+		}
+	}
+}
+
+// writeResponseCacheHit 向客户端返回缓存响应：非流式原样返回，流式回放为合成 SSE
+func writeResponseCacheHit(c *gin.Context, entry *service.ResponseCacheEntry, stream bool) error {
+	c.Header(service.ResponseCacheHitHeader, "HIT")
+	if !stream {
+		c.Data(http.StatusOK, "application/json", entry.Body)
+		return nil
+	}
+	events, err := service.RenderResponseCacheSSE(entry.Endpoint, entry.Body)
+	if err != nil {
+		return err
+	}
+	c.Header("Content-Type", "text/event-stream")
+	c.Header("Cache-Control", "no-cache")
+	c.Header("Connection", "keep-alive")
+	c.Header("X-Accel-Buffering", "no")
+	c.Status(http.StatusOK)
+	if _, err := c.Writer.Write(events); err != nil {
+		return err
+	}
+	c.Writer.Flush()
+	return nil
+}
+
+// serveResponseCacheHit 查询缓存并在命中时直接响应、异步记录命中使用量。
+// 返回 true 表示请求已处理完毕。
+func serveResponseCacheHit(
+	c *gin.Context,
+	cacheService *service.ResponseCacheService,
+	cacheReq *service.ResponseCacheRequest,
+	apiKey *service.APIKey,
+	subscription *service.UserSubscription,
+	stream bool,
+	apiKeyService *service.APIKeyService,
+	submit func(service.UsageRecordTask),
+	reqLog *zap.Logger,
+) bool {
+	if cacheReq == nil {
+		return false
+	}
+	entry := cacheService.Lookup(c.Request.Context(), cacheReq)
+	if entry == nil {
+		return false
+	}
+	if err := writeResponseCacheHit(c, entry, stream); err != nil {
+		// 合成 SSE 失败时回退到正常转发；已写出内容时只能结束请求
+		reqLog.Warn("response_cache.replay_failed", zap.Error(err))
+		if !c.Writer.Written() {
+			c.Writer.Header().Del(service.ResponseCacheHitHeader)
+			return false
+		}
+	}
+	reqLog.Debug("response_cache.hit", zap.Int64("origin_account_id", entry.AccountID))
+
+	userAgent := c.GetHeader("User-Agent")
+	clientIP := ip.GetClientIP(c)
+	submit(func(ctx context.Context) {
+		if err := cacheService.RecordHit(ctx, &service.RecordResponseCacheHitInput{
+			Entry:         entry,
+			APIKey:        apiKey,
+			User:          apiKey.User,
+			Subscription:  subscription,
+			Stream:        stream,
+			UserAgent:     userAgent,
+			IPAddress:     clientIP,
+			APIKeyService: apiKeyService,
+		}); err != nil {
+			logger.L().With(
+				zap.String("component", "handler.response_cache"),
+				zap.Int64("api_key_id", apiKey.ID),
+				zap.Any("group_id", apiKey.GroupID),
+			).Error("response_cache.record_hit_failed", zap.Error(err))
+		}
+	})
+	return true
+}
+
+// storeResponseCache 保存上游成功返回的非流式响应
+func storeResponseCache(
+	ctx context.Context,
+	cacheService *service.ResponseCacheService,
+	cacheReq *service.ResponseCacheRequest,
+	capture *responseCacheCaptureWriter,
+	accountID int64,
+	requestID string,
+	model string,
+	usage service.UsageTokens,
+) {
+	if cacheReq == nil || capture == nil {
+		return
+	}
+	body := capture.body()
+	if body == nil {
+		return
+	}
+	cacheService.Store(ctx, cacheReq, &service.ResponseCacheEntry{
+		Model:     model,
+		Body:      body,
+		AccountID: accountID,
+		RequestID: requestID,
+		Usage:     usage,
+	})
+}
+
+// storeOpenAIResponseCache 保存 OpenAI 网关的成功响应（计费模型优先使用 BillingModel）
+func storeOpenAIResponseCache(
+	ctx context.Context,
+	cacheService *service.ResponseCacheService,
+	cacheReq *service.ResponseCacheRequest,
+	capture *responseCacheCaptureWriter,
+	accountID int64,
+	result *service.OpenAIForwardResult,
+) {
+	if result == nil {
+		return
+	}
+	model := result.Model
+	if result.BillingModel != "" {
+		model = result.BillingModel
+	}
+	storeResponseCache(ctx, cacheService, cacheReq, capture, accountID, result.RequestID, model, openAIResponseCacheUsage(result.Usage))
+}
+
+// claudeResponseCacheUsage 将 Messages 转发结果的 usage 转为计费口径
+func claudeResponseCacheUsage(usage service.ClaudeUsage) service.UsageTokens {
+	return service.UsageTokens{
+		InputTokens:           usage.InputTokens,
+		OutputTokens:          usage.OutputTokens,
+		CacheCreationTokens:   usage.CacheCreationInputTokens,
+		CacheReadTokens:       usage.CacheReadInputTokens,
+		CacheCreation5mTokens: usage.CacheCreation5mTokens,
+		CacheCreation1hTokens: usage.CacheCreation1hTokens,
+	}
+}
+
+// openAIResponseCacheUsage 将 Responses 转发结果的 usage 转为计费口径（input_tokens 含缓存读取部分）
+func openAIResponseCacheUsage(usage service.OpenAIUsage) service.UsageTokens {
+	inputTokens := usage.InputTokens - usage.CacheReadInputTokens
+	if inputTokens < 0 {
+		inputTokens = 0
+	}
+	return service.UsageTokens{
+		InputTokens:         inputTokens,
+		OutputTokens:        usage.OutputTokens,
+		CacheCreationTokens: usage.CacheCreationInputTokens,
+		CacheReadTokens:     usage.CacheReadInputTokens,
+	}
+}
@@ -54,7 +54,16 @@ func (h *SettingHandler) GetPublicSettings(c *gin.Context) {
 		PurchaseInstructions:             settings.PurchaseInstructions,
 		CustomMenuItems:                  dto.ParseUserVisibleMenuItems(settings.CustomMenuItems),
 		LinuxDoOAuthEnabled:              settings.LinuxDoOAuthEnabled,
+		SSOProviders:                     ssoProvidersToDTO(settings.SSOProviders),
 		SoraClientEnabled:                settings.SoraClientEnabled,
 		Version:                          h.version,
 	})
 }
+
+func ssoProvidersToDTO(providers []service.SSOProviderInfo) []dto.SSOProvider {
+	out := make([]dto.SSOProvider, 0, len(providers))
+	for _, p := range providers {
+		out = append(out, dto.SSOProvider{ID: p.ID, Name: p.Name, Type: p.Type})
+	}
+	return out
+}
@@ -36,6 +36,7 @@ type SoraClientHandler struct {
 	gatewayService     *service.GatewayService
 	mediaStorage       *service.SoraMediaStorage
 	apiKeyService      *service.APIKeyService
+	jobService         *service.SoraGenerationJobService
 
 	// 上游模型缓存
 	modelCacheMu       sync.RWMutex
@@ -53,8 +54,9 @@ func NewSoraClientHandler(
 	gatewayService *service.GatewayService,
 	mediaStorage *service.SoraMediaStorage,
 	apiKeyService *service.APIKeyService,
+	jobService *service.SoraGenerationJobService,
 ) *SoraClientHandler {
-	return &SoraClientHandler{
+	h := &SoraClientHandler{
 		genService:         genService,
 		quotaService:       quotaService,
 		s3Storage:          s3Storage,
@@ -62,7 +64,10 @@ func NewSoraClientHandler(
 		gatewayService:     gatewayService,
 		mediaStorage:       mediaStorage,
 		apiKeyService:      apiKeyService,
+		jobService:         jobService,
 	}
+	jobService.SetExecutor(h)
+	return h
 }
 
 // GenerateRequest 生成请求。
@@ -157,8 +162,18 @@ func (h *SoraClientHandler) Generate(c *gin.Context) {
 		return
 	}
 
-	// 启动后台异步生成 goroutine
-	go h.processGeneration(gen.ID, userID, groupID, req.Model, req.Prompt, req.MediaType, req.ImageInput, req.VideoCount)
+	if h.jobService.Enabled() {
+		// 持久化队列：服务重启/升级后任务由任意实例接管
+		if _, err := h.jobService.Enqueue(c.Request.Context(), gen, groupID, req.ImageInput, req.VideoCount); err != nil {
+			logger.LegacyPrintf("handler.sora_client", "[SoraClient] 任务入队失败 id=%d err=%v", gen.ID, err)
+			_ = h.genService.MarkFailed(c.Request.Context(), gen.ID, "任务入队失败")
+			response.ErrorFrom(c, err)
+			return
+		}
+	} else {
+		// 未启用队列时回退到进程内 goroutine
+		go h.processGeneration(gen.ID, userID, groupID, req.Model, req.Prompt, req.MediaType, req.ImageInput, req.VideoCount)
+	}
 
 	response.Success(c, gin.H{
 		"generation_id": gen.ID,
@@ -166,8 +181,7 @@ func (h *SoraClientHandler) Generate(c *gin.Context) {
 	})
 }
 
-// processGeneration 后台异步执行 Sora 生成任务。
-// 流程：选择账号 → Forward → 提取媒体 URL → 三层降级存储（S3 → 本地 → 上游）→ 更新记录。
+// processGeneration 进程内执行 Sora 生成任务（未启用持久化队列时使用）。
 func (h *SoraClientHandler) processGeneration(genID int64, userID int64, groupID *int64, model, prompt, mediaType, imageInput string, videoCount int) {
 	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Minute)
 	defer cancel()
@@ -182,6 +196,45 @@ func (h *SoraClientHandler) processGeneration(genID int64, userID int64, groupID
 		return
 	}
 
+	job := &service.SoraGenerationJob{
+		GenerationID: genID,
+		UserID:       userID,
+		GroupID:      groupID,
+		Model:        model,
+		Prompt:       prompt,
+		MediaType:    mediaType,
+		ImageInput:   imageInput,
+		VideoCount:   videoCount,
+	}
+	if err := h.runGeneration(ctx, job, noopSoraJobProgress{}); err != nil {
+		_ = h.genService.MarkFailed(ctx, genID, err.Error())
+	}
+}
+
+// ExecuteSoraGenerationJob 实现 service.SoraGenerationJobExecutor，由持久化队列调用。
+// 与 processGeneration 不同：失败不直接写生成记录，而是返回错误交给队列决定重试或标记失败。
+func (h *SoraClientHandler) ExecuteSoraGenerationJob(ctx context.Context, job *service.SoraGenerationJob, progress service.SoraGenerationJobProgress) error {
+	if err := h.genService.MarkGenerating(ctx, job.GenerationID, job.UpstreamTaskID); err != nil {
+		if !errors.Is(err, service.ErrSoraGenerationStateConflict) {
+			return service.NewSoraGenerationRetryableError(fmt.Errorf("标记生成中失败: %w", err))
+		}
+		// 重试/接管时记录已处于 generating，继续执行；其余状态（取消/完成/失败）直接结束
+		gen, getErr := h.genService.GetByID(ctx, job.GenerationID, job.UserID)
+		if getErr != nil || gen.Status != service.SoraGenStatusGenerating {
+			logger.LegacyPrintf("handler.sora_client", "[SoraClient] 任务状态已变化，跳过生成 id=%d", job.GenerationID)
+			return nil
+		}
+	}
+	return h.runGeneration(ctx, job, progress)
+}
+
+// runGeneration 执行 Sora 生成（调用方已将记录标记为 generating）。
+// 流程：选择账号 → Forward（或恢复轮询已提交的上游任务）→ 提取媒体 URL → 三层降级存储（S3 → 本地 → 上游）→ 更新记录。
+// 返回 nil 表示已完成或已取消；返回错误时调用方负责将记录标记为失败。
+func (h *SoraClientHandler) runGeneration(ctx context.Context, job *service.SoraGenerationJob, progress service.SoraGenerationJobProgress) error {
+	genID, userID, groupID := job.GenerationID, job.UserID, job.GroupID
+	model, mediaType := job.Model, job.MediaType
+
 	logger.LegacyPrintf(
 		"handler.sora_client",
 		"[SoraClient] 开始生成 id=%d user=%d group=%d model=%s media_type=%s video_count=%d has_image=%v prompt_len=%d",
@@ -190,9 +243,9 @@ func (h *SoraClientHandler) processGeneration(genID int64, userID int64, groupID
 		groupIDForLog(groupID),
 		model,
 		mediaType,
-		videoCount,
-		strings.TrimSpace(imageInput) != "",
-		len(strings.TrimSpace(prompt)),
+		job.VideoCount,
+		strings.TrimSpace(job.ImageInput) != "",
+		len(strings.TrimSpace(job.Prompt)),
 	)
 
 	// 有 groupID 时由分组决定平台，无 groupID 时用 ForcePlatform 兜底
@@ -201,10 +254,91 @@ func (h *SoraClientHandler) processGeneration(genID int64, userID int64, groupID
 	}
 
 	if h.gatewayService == nil {
-		_ = h.genService.MarkFailed(ctx, genID, "内部错误: gatewayService 未初始化")
-		return
+		return errors.New("内部错误: gatewayService 未初始化")
 	}
 
+	var (
+		mediaURL  string
+		mediaURLs []string
+		err       error
+	)
+	if job.Resumable() {
+		mediaURL, mediaURLs, err = h.resumeUpstreamTask(ctx, job)
+	} else {
+		mediaURL, mediaURLs, err = h.submitUpstreamTask(ctx, job, progress)
+	}
+	if err != nil {
+		// 检查是否已取消
+		gen, _ := h.genService.GetByID(ctx, genID, userID)
+		if gen != nil && gen.Status == service.SoraGenStatusCancelled {
+			return nil
+		}
+		return err
+	}
+	if mediaURL == "" {
+		return errors.New("未获取到媒体 URL")
+	}
+
+	// 检查任务是否已被取消
+	gen, _ := h.genService.GetByID(ctx, genID, userID)
+	if gen != nil && gen.Status == service.SoraGenStatusCancelled {
+		logger.LegacyPrintf("handler.sora_client", "[SoraClient] 任务已取消，跳过存储 id=%d", genID)
+		return nil
+	}
+
+	// 三层降级存储：S3 → 本地 → 上游临时 URL
+	storedURL, storedURLs, storageType, s3Keys, fileSize := h.storeMediaWithDegradation(ctx, userID, mediaType, mediaURL, mediaURLs)
+	progress.MediaStored(storageType, storedPaths(storageType, s3Keys, storedURLs), fileSize, false)
+
+	usageAdded := false
+	if (storageType == service.SoraStorageTypeS3 || storageType == service.SoraStorageTypeLocal) && fileSize > 0 && h.quotaService != nil {
+		if err := h.quotaService.AddUsage(ctx, userID, fileSize); err != nil {
+			h.cleanupStoredMedia(ctx, storageType, s3Keys, storedURLs)
+			progress.MediaStored("", nil, 0, false)
+			var quotaErr *service.QuotaExceededError
+			if errors.As(err, &quotaErr) {
+				return errors.New("存储配额已满，请删除不需要的作品释放空间")
+			}
+			return fmt.Errorf("存储配额更新失败: %w", err)
+		}
+		usageAdded = true
+		progress.MediaStored(storageType, storedPaths(storageType, s3Keys, storedURLs), fileSize, true)
+	}
+
+	rollback := func() {
+		h.cleanupStoredMedia(ctx, storageType, s3Keys, storedURLs)
+		if usageAdded && h.quotaService != nil {
+			_ = h.quotaService.ReleaseUsage(ctx, userID, fileSize)
+		}
+		progress.MediaStored("", nil, 0, false)
+	}
+
+	// 存储完成后再做一次取消检查，防止取消被 completed 覆盖。
+	gen, _ = h.genService.GetByID(ctx, genID, userID)
+	if gen != nil && gen.Status == service.SoraGenStatusCancelled {
+		logger.LegacyPrintf("handler.sora_client", "[SoraClient] 存储后检测到任务已取消，回滚存储 id=%d", genID)
+		rollback()
+		return nil
+	}
+
+	// 标记完成
+	if err := h.genService.MarkCompleted(ctx, genID, storedURL, storedURLs, storageType, s3Keys, fileSize); err != nil {
+		rollback()
+		if errors.Is(err, service.ErrSoraGenerationStateConflict) {
+			return nil
+		}
+		logger.LegacyPrintf("handler.sora_client", "[SoraClient] 标记完成失败 id=%d err=%v", genID, err)
+		return service.NewSoraGenerationRetryableError(fmt.Errorf("标记完成失败: %w", err))
+	}
+
+	logger.LegacyPrintf("handler.sora_client", "[SoraClient] 生成完成 id=%d storage=%s size=%d", genID, storageType, fileSize)
+	return nil
+}
+
+// submitUpstreamTask 选择账号并提交上游任务，上游任务创建后通过 progress 持久化任务 ID。
+func (h *SoraClientHandler) submitUpstreamTask(ctx context.Context, job *service.SoraGenerationJob, progress service.SoraGenerationJobProgress) (string, []string, error) {
+	genID, userID, groupID, model := job.GenerationID, job.UserID, job.GroupID, job.Model
+
 	// 选择 Sora 账号
 	account, err := h.gatewayService.SelectAccountForModel(ctx, groupID, "", model)
 	if err != nil {
@@ -217,8 +351,7 @@ func (h *SoraClientHandler) processGeneration(genID int64, userID int64, groupID
 			model,
 			err,
 		)
-		_ = h.genService.MarkFailed(ctx, genID, "选择账号失败: "+err.Error())
-		return
+		return "", nil, service.NewSoraGenerationRetryableError(fmt.Errorf("选择账号失败: %w", err))
 	}
 	logger.LegacyPrintf(
 		"handler.sora_client",
@@ -234,13 +367,18 @@ func (h *SoraClientHandler) processGeneration(genID int64, userID int64, groupID
 	)
 
 	// 构建 chat completions 请求体（非流式）
-	body := buildAsyncRequestBody(model, prompt, imageInput, normalizeVideoCount(mediaType, videoCount))
+	body := buildAsyncRequestBody(model, job.Prompt, job.ImageInput, normalizeVideoCount(job.MediaType, job.VideoCount))
 
 	if h.soraGatewayService == nil {
-		_ = h.genService.MarkFailed(ctx, genID, "内部错误: soraGatewayService 未初始化")
-		return
+		return "", nil, errors.New("内部错误: soraGatewayService 未初始化")
 	}
 
+	taskCreated := false
+	ctx = context.WithValue(ctx, ctxkey.SoraTaskCreatedHook, func(taskID string) {
+		taskCreated = true
+		progress.UpstreamTaskCreated(account.ID, taskID)
+	})
+
 	// 创建 mock gin 上下文用于 Forward（捕获响应以提取媒体 URL）
 	recorder := httptest.NewRecorder()
 	mockGinCtx, _ := gin.CreateTestContext(recorder)
@@ -259,13 +397,12 @@ func (h *SoraClientHandler) processGeneration(genID int64, userID int64, groupID
 			trimForLog(recorder.Body.String(), 400),
 			err,
 		)
-		// 检查是否已取消
-		gen, _ := h.genService.GetByID(ctx, genID, userID)
-		if gen != nil && gen.Status == service.SoraGenStatusCancelled {
-			return
+		err = fmt.Errorf("生成失败: %w", err)
+		// 上游尚未受理且为限流/服务端错误时可换账号重试；已提交的任务失败视为终态
+		if !taskCreated && isRetryableSoraForwardStatus(recorder.Code) {
+			return "", nil, service.NewSoraGenerationRetryableError(err)
 		}
-		_ = h.genService.MarkFailed(ctx, genID, "生成失败: "+err.Error())
-		return
+		return "", nil, err
 	}
 
 	// 提取媒体 URL（优先从 ForwardResult，其次从响应体解析）
@@ -280,62 +417,53 @@ func (h *SoraClientHandler) processGeneration(genID int64, userID int64, groupID
 			recorder.Code,
 			trimForLog(recorder.Body.String(), 400),
 		)
-		_ = h.genService.MarkFailed(ctx, genID, "未获取到媒体 URL")
-		return
 	}
+	return mediaURL, mediaURLs, nil
+}
 
-	// 检查任务是否已被取消
-	gen, _ := h.genService.GetByID(ctx, genID, userID)
-	if gen != nil && gen.Status == service.SoraGenStatusCancelled {
-		logger.LegacyPrintf("handler.sora_client", "[SoraClient] 任务已取消，跳过存储 id=%d", genID)
-		return
+// resumeUpstreamTask 沿用原账号恢复轮询已提交的上游任务。
+func (h *SoraClientHandler) resumeUpstreamTask(ctx context.Context, job *service.SoraGenerationJob) (string, []string, error) {
+	if h.soraGatewayService == nil {
+		return "", nil, errors.New("内部错误: soraGatewayService 未初始化")
 	}
-
-	// 三层降级存储：S3 → 本地 → 上游临时 URL
-	storedURL, storedURLs, storageType, s3Keys, fileSize := h.storeMediaWithDegradation(ctx, userID, mediaType, mediaURL, mediaURLs)
-
-	usageAdded := false
-	if (storageType == service.SoraStorageTypeS3 || storageType == service.SoraStorageTypeLocal) && fileSize > 0 && h.quotaService != nil {
-		if err := h.quotaService.AddUsage(ctx, userID, fileSize); err != nil {
-			h.cleanupStoredMedia(ctx, storageType, s3Keys, storedURLs)
-			var quotaErr *service.QuotaExceededError
-			if errors.As(err, &quotaErr) {
-				_ = h.genService.MarkFailed(ctx, genID, "存储配额已满，请删除不需要的作品释放空间")
-				return
-			}
-			_ = h.genService.MarkFailed(ctx, genID, "存储配额更新失败: "+err.Error())
-			return
-		}
-		usageAdded = true
+	account, err := h.gatewayService.GetAccountByID(ctx, *job.AccountID)
+	if err != nil {
+		return "", nil, fmt.Errorf("恢复任务失败: 账号不可用: %w", err)
 	}
-
-	// 存储完成后再做一次取消检查，防止取消被 completed 覆盖。
-	gen, _ = h.genService.GetByID(ctx, genID, userID)
-	if gen != nil && gen.Status == service.SoraGenStatusCancelled {
-		logger.LegacyPrintf("handler.sora_client", "[SoraClient] 存储后检测到任务已取消，回滚存储 id=%d", genID)
-		h.cleanupStoredMedia(ctx, storageType, s3Keys, storedURLs)
-		if usageAdded && h.quotaService != nil {
-			_ = h.quotaService.ReleaseUsage(ctx, userID, fileSize)
-		}
-		return
+	logger.LegacyPrintf("handler.sora_client", "[SoraClient] 恢复上游任务 id=%d account_id=%d task_id=%s", job.GenerationID, account.ID, job.UpstreamTaskID)
+	urls, err := h.soraGatewayService.ResumeTask(ctx, account, job.Model, job.UpstreamTaskID)
+	if err != nil {
+		return "", nil, fmt.Errorf("生成失败: %w", err)
 	}
-
-	// 标记完成
-	if err := h.genService.MarkCompleted(ctx, genID, storedURL, storedURLs, storageType, s3Keys, fileSize); err != nil {
-		if errors.Is(err, service.ErrSoraGenerationStateConflict) {
-			h.cleanupStoredMedia(ctx, storageType, s3Keys, storedURLs)
-			if usageAdded && h.quotaService != nil {
-				_ = h.quotaService.ReleaseUsage(ctx, userID, fileSize)
-			}
-			return
-		}
-		logger.LegacyPrintf("handler.sora_client", "[SoraClient] 标记完成失败 id=%d err=%v", genID, err)
-		return
+	if len(urls) == 0 {
+		return "", nil, nil
 	}
+	return urls[0], urls, nil
+}
 
-	logger.LegacyPrintf("handler.sora_client", "[SoraClient] 生成完成 id=%d storage=%s size=%d", genID, storageType, fileSize)
+// isRetryableSoraForwardStatus 判断 Forward 失败时的响应状态是否适合重试。
+func isRetryableSoraForwardStatus(status int) bool {
+	return status == 0 || status == http.StatusTooManyRequests || status >= http.StatusInternalServerError
 }
 
+// storedPaths 返回需要随任务回滚的存储路径（S3 object key 或本地相对路径）。
+func storedPaths(storageType string, s3Keys []string, storedURLs []string) []string {
+	switch storageType {
+	case service.SoraStorageTypeS3:
+		return s3Keys
+	case service.SoraStorageTypeLocal:
+		return storedURLs
+	default:
+		return nil
+	}
+}
+
+// noopSoraJobProgress 进程内执行时不需要持久化进度。
+type noopSoraJobProgress struct{}
+
+func (noopSoraJobProgress) UpstreamTaskCreated(int64, string)         {}
+func (noopSoraJobProgress) MediaStored(string, []string, int64, bool) {}
+
 // storeMediaWithDegradation 实现三层降级存储链：S3 → 本地 → 上游。
 func (h *SoraClientHandler) storeMediaWithDegradation(
 	ctx context.Context, userID int64, mediaType string,
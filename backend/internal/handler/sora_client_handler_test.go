@@ -952,12 +952,12 @@ func (r *stubUserRepoForHandler) AddGroupToAllowedGroups(context.Context, int64,
 // ==================== NewSoraClientHandler ====================
 
 func TestNewSoraClientHandler(t *testing.T) {
-	h := NewSoraClientHandler(nil, nil, nil, nil, nil, nil, nil)
+	h := NewSoraClientHandler(nil, nil, nil, nil, nil, nil, nil, nil)
 	require.NotNil(t, h)
 }
 
 func TestNewSoraClientHandler_WithAPIKeyService(t *testing.T) {
-	h := NewSoraClientHandler(nil, nil, nil, nil, nil, nil, nil)
+	h := NewSoraClientHandler(nil, nil, nil, nil, nil, nil, nil, nil)
 	require.NotNil(t, h)
 	require.Nil(t, h.apiKeyService)
 }
@@ -985,13 +985,13 @@ func (r *stubAPIKeyRepoForHandler) GetByID(_ context.Context, id int64) (*servic
 	return nil, fmt.Errorf("api key not found: %d", id)
 }
 func (r *stubAPIKeyRepoForHandler) Create(context.Context, *service.APIKey) error { return nil }
-func (r *stubAPIKeyRepoForHandler) GetKeyAndOwnerID(_ context.Context, _ int64) (string, int64, error) {
+func (r *stubAPIKeyRepoForHandler) GetKeyHashAndOwnerID(_ context.Context, _ int64) (string, int64, error) {
 	return "", 0, nil
 }
-func (r *stubAPIKeyRepoForHandler) GetByKey(context.Context, string) (*service.APIKey, error) {
+func (r *stubAPIKeyRepoForHandler) GetByKeyHash(context.Context, string) (*service.APIKey, error) {
 	return nil, nil
 }
-func (r *stubAPIKeyRepoForHandler) GetByKeyForAuth(context.Context, string) (*service.APIKey, error) {
+func (r *stubAPIKeyRepoForHandler) GetByKeyHashForAuth(context.Context, string) (*service.APIKey, error) {
 	return nil, nil
 }
 func (r *stubAPIKeyRepoForHandler) Update(context.Context, *service.APIKey) error { return nil }
@@ -1005,7 +1005,7 @@ func (r *stubAPIKeyRepoForHandler) VerifyOwnership(context.Context, int64, []int
 func (r *stubAPIKeyRepoForHandler) CountByUserID(context.Context, int64) (int64, error) {
 	return 0, nil
 }
-func (r *stubAPIKeyRepoForHandler) ExistsByKey(context.Context, string) (bool, error) {
+func (r *stubAPIKeyRepoForHandler) ExistsByKeyHash(context.Context, string) (bool, error) {
 	return false, nil
 }
 func (r *stubAPIKeyRepoForHandler) ListByGroupID(_ context.Context, _ int64, _ pagination.PaginationParams) ([]service.APIKey, *pagination.PaginationResult, error) {
@@ -1020,10 +1020,10 @@ func (r *stubAPIKeyRepoForHandler) ClearGroupIDByGroupID(context.Context, int64)
 func (r *stubAPIKeyRepoForHandler) CountByGroupID(context.Context, int64) (int64, error) {
 	return 0, nil
 }
-func (r *stubAPIKeyRepoForHandler) ListKeysByUserID(context.Context, int64) ([]string, error) {
+func (r *stubAPIKeyRepoForHandler) ListKeyHashesByUserID(context.Context, int64) ([]string, error) {
 	return nil, nil
 }
-func (r *stubAPIKeyRepoForHandler) ListKeysByGroupID(context.Context, int64) ([]string, error) {
+func (r *stubAPIKeyRepoForHandler) ListKeyHashesByGroupID(context.Context, int64) ([]string, error) {
 	return nil, nil
 }
 func (r *stubAPIKeyRepoForHandler) IncrementQuotaUsed(_ context.Context, _ int64, _ float64) (float64, error) {
@@ -2207,7 +2207,7 @@ func (s *stubSoraClientForHandler) GetVideoTask(_ context.Context, _ *service.Ac
 func newMinimalGatewayService(accountRepo service.AccountRepository) *service.GatewayService {
 	return service.NewGatewayService(
 		accountRepo, nil, nil, nil, nil, nil, nil, nil,
-		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
+		nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil,
 	)
 }
 
@@ -2891,7 +2891,7 @@ func TestGenerate_CheckQuotaNonQuotaError(t *testing.T) {
 	userRepo := newStubUserRepoForHandler()
 	quotaService := service.NewSoraQuotaService(userRepo, nil, nil)
 
-	h := NewSoraClientHandler(genService, quotaService, nil, nil, nil, nil, nil)
+	h := NewSoraClientHandler(genService, quotaService, nil, nil, nil, nil, nil, nil)
 
 	body := `{"model":"sora2-landscape-10s","prompt":"test"}`
 	c, rec := makeGinContext("POST", "/api/v1/sora/generate", body, 1)
@@ -2920,7 +2920,7 @@ func TestGenerate_CreatePendingConcurrencyLimit(t *testing.T) {
 		limitErr:        service.ErrSoraGenerationConcurrencyLimit,
 	}
 	genService := service.NewSoraGenerationService(repo, nil, nil)
-	h := NewSoraClientHandler(genService, nil, nil, nil, nil, nil, nil)
+	h := NewSoraClientHandler(genService, nil, nil, nil, nil, nil, nil, nil)
 
 	body := `{"model":"sora2-landscape-10s","prompt":"test"}`
 	c, rec := makeGinContext("POST", "/api/v1/sora/generate", body, 1)
@@ -3159,3 +3159,55 @@ func TestCancelGeneration_AlreadyCompleted(t *testing.T) {
 	h.CancelGeneration(c)
 	require.Equal(t, http.StatusConflict, rec.Code)
 }
+
+// ==================== ExecuteSoraGenerationJob（持久化队列执行器） ====================
+
+func TestExecuteSoraGenerationJob_ResumesGeneratingRecord(t *testing.T) {
+	// 接管/重试时记录已处于 generating：继续执行，错误交给队列处理而不直接写 failed
+	repo := newStubSoraGenRepo()
+	repo.gens[1] = &service.SoraGeneration{ID: 1, UserID: 1, Status: "generating"}
+	genService := service.NewSoraGenerationService(repo, nil, nil)
+	h := &SoraClientHandler{genService: genService}
+
+	err := h.ExecuteSoraGenerationJob(context.Background(), &service.SoraGenerationJob{GenerationID: 1, UserID: 1, Model: "sora2-landscape-10s", MediaType: "video"}, noopSoraJobProgress{})
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "gatewayService")
+	require.Equal(t, "generating", repo.gens[1].Status)
+}
+
+func TestExecuteSoraGenerationJob_CancelledSkips(t *testing.T) {
+	repo := newStubSoraGenRepo()
+	repo.gens[1] = &service.SoraGeneration{ID: 1, UserID: 1, Status: "cancelled"}
+	genService := service.NewSoraGenerationService(repo, nil, nil)
+	h := &SoraClientHandler{genService: genService}
+
+	err := h.ExecuteSoraGenerationJob(context.Background(), &service.SoraGenerationJob{GenerationID: 1, UserID: 1}, noopSoraJobProgress{})
+	require.NoError(t, err)
+	require.Equal(t, "cancelled", repo.gens[1].Status)
+}
+
+func TestExecuteSoraGenerationJob_SelectAccountErrorIsRetryable(t *testing.T) {
+	repo := newStubSoraGenRepo()
+	repo.gens[1] = &service.SoraGeneration{ID: 1, UserID: 1, Status: "pending"}
+	genService := service.NewSoraGenerationService(repo, nil, nil)
+	gatewayService := newMinimalGatewayService(&stubAccountRepoForHandler{accounts: nil})
+	h := &SoraClientHandler{genService: genService, gatewayService: gatewayService}
+
+	err := h.ExecuteSoraGenerationJob(context.Background(), &service.SoraGenerationJob{GenerationID: 1, UserID: 1, Model: "sora2-landscape-10s", MediaType: "video"}, noopSoraJobProgress{})
+	require.Error(t, err)
+	require.True(t, service.IsSoraGenerationRetryable(err))
+	require.Equal(t, "generating", repo.gens[1].Status)
+}
+
+func TestIsRetryableSoraForwardStatus(t *testing.T) {
+	require.True(t, isRetryableSoraForwardStatus(0))
+	require.True(t, isRetryableSoraForwardStatus(http.StatusTooManyRequests))
+	require.True(t, isRetryableSoraForwardStatus(http.StatusBadGateway))
+	require.False(t, isRetryableSoraForwardStatus(http.StatusBadRequest))
+}
+
+func TestStoredPaths(t *testing.T) {
+	require.Equal(t, []string{"k1"}, storedPaths(service.SoraStorageTypeS3, []string{"k1"}, []string{"https://cdn/k1"}))
+	require.Equal(t, []string{"video/a.mp4"}, storedPaths(service.SoraStorageTypeLocal, nil, []string{"video/a.mp4"}))
+	require.Nil(t, storedPaths(service.SoraStorageTypeUpstream, nil, []string{"https://upstream/a.mp4"}))
+}
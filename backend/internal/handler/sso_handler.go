@@ -0,0 +1,302 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/oauth"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	ssoCookiePath          = "/api/v1/auth/sso"
+	ssoStateCookieName     = "sso_state"
+	ssoVerifierCookieName  = "sso_verifier"
+	ssoNonceCookieName     = "sso_nonce"
+	ssoRedirectCookieName  = "sso_redirect"
+	ssoLinkCookieName      = "sso_link"
+	ssoCookieMaxAgeSec     = 10 * 60 // 10 minutes
+	ssoDefaultRedirectTo   = "/dashboard"
+	ssoDefaultLinkRedirect = "/profile"
+)
+
+// SSOHandler handles generic OIDC / GitHub / Google single sign-on
+type SSOHandler struct {
+	ssoService *service.SSOService
+}
+
+// NewSSOHandler creates a new SSOHandler
+func NewSSOHandler(ssoService *service.SSOService) *SSOHandler {
+	return &SSOHandler{ssoService: ssoService}
+}
+
+// LinkSSORequest represents the start-linking payload
+type LinkSSORequest struct {
+	Redirect string `json:"redirect"`
+}
+
+// Start 启动 SSO 登录流程。
+// GET /api/v1/auth/sso/:provider/start?redirect=/dashboard
+func (h *SSOHandler) Start(c *gin.Context) {
+	authURL, _, err := h.beginAuthorization(c, c.Param("provider"), c.Query("redirect"), ssoDefaultRedirectTo)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	clearSSOCookie(c, ssoLinkCookieName, isRequestHTTPS(c))
+	c.Redirect(http.StatusFound, authURL)
+}
+
+// StartLink 为当前用户发起绑定，返回提供方授权地址（由前端跳转）。
+// POST /api/v1/user/sso/:provider/link
+func (h *SSOHandler) StartLink(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	var req LinkSSORequest
+	_ = c.ShouldBindJSON(&req)
+
+	providerID := c.Param("provider")
+	authURL, state, err := h.beginAuthorization(c, providerID, req.Redirect, ssoDefaultLinkRedirect)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	// 绑定凭证与本次 state 绑定，回调时校验，防止被他人的授权回调复用
+	intent := h.ssoService.SignLinkIntent(subject.UserID, providerID, state)
+	setSSOCookie(c, ssoLinkCookieName, encodeCookieValue(intent), isRequestHTTPS(c))
+	response.Success(c, gin.H{"auth_url": authURL})
+}
+
+// Callback 处理提供方回调：登录（或绑定）后重定向到前端。
+// GET /api/v1/auth/sso/:provider/callback?code=...&state=...
+func (h *SSOHandler) Callback(c *gin.Context) {
+	providerID := c.Param("provider")
+	provider, err := h.ssoService.Provider(providerID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	frontendCallback := provider.FrontendRedirectURL
+
+	secureCookie := isRequestHTTPS(c)
+	defer func() {
+		for _, name := range []string{ssoStateCookieName, ssoVerifierCookieName, ssoNonceCookieName, ssoRedirectCookieName, ssoLinkCookieName} {
+			clearSSOCookie(c, name, secureCookie)
+		}
+	}()
+
+	if providerErr := strings.TrimSpace(c.Query("error")); providerErr != "" {
+		redirectOAuthError(c, frontendCallback, "provider_error", providerErr, c.Query("error_description"))
+		return
+	}
+
+	code := strings.TrimSpace(c.Query("code"))
+	state := strings.TrimSpace(c.Query("state"))
+	if code == "" || state == "" {
+		redirectOAuthError(c, frontendCallback, "missing_params", "missing code/state", "")
+		return
+	}
+
+	expectedState, _ := readSSOState(c, providerID)
+	if expectedState == "" || state != expectedState {
+		redirectOAuthError(c, frontendCallback, "invalid_state", "invalid oauth state", "")
+		return
+	}
+	codeVerifier, _ := readCookieDecoded(c, ssoVerifierCookieName)
+	if codeVerifier == "" {
+		redirectOAuthError(c, frontendCallback, "missing_verifier", "missing pkce verifier", "")
+		return
+	}
+	nonce, _ := readCookieDecoded(c, ssoNonceCookieName)
+
+	linkIntent, _ := readCookieDecoded(c, ssoLinkCookieName)
+	defaultRedirect := ssoDefaultRedirectTo
+	if linkIntent != "" {
+		defaultRedirect = ssoDefaultLinkRedirect
+	}
+	redirectTo, _ := readCookieDecoded(c, ssoRedirectCookieName)
+	redirectTo = sanitizeFrontendRedirectPath(redirectTo)
+	if redirectTo == "" {
+		redirectTo = defaultRedirect
+	}
+
+	claims, err := h.ssoService.Authenticate(c.Request.Context(), providerID, code, codeVerifier, nonce)
+	if err != nil {
+		redirectOAuthError(c, frontendCallback, "authenticate_failed", infraerrors.Reason(err), infraerrors.Message(err))
+		return
+	}
+
+	if linkIntent != "" {
+		userID, err := h.ssoService.VerifyLinkIntent(linkIntent, providerID, state)
+		if err == nil {
+			_, err = h.ssoService.Link(c.Request.Context(), userID, claims)
+		}
+		if err != nil {
+			redirectOAuthError(c, frontendCallback, "link_failed", infraerrors.Reason(err), infraerrors.Message(err))
+			return
+		}
+		fragment := url.Values{}
+		fragment.Set("linked", providerID)
+		fragment.Set("redirect", redirectTo)
+		redirectWithFragment(c, frontendCallback, fragment)
+		return
+	}
+
+	result, err := h.ssoService.Login(c.Request.Context(), claims)
+	if err != nil {
+		redirectOAuthError(c, frontendCallback, "login_failed", infraerrors.Reason(err), infraerrors.Message(err))
+		return
+	}
+
+	fragment := url.Values{}
+	if result.TotpTempToken != "" {
+		// 与密码登录相同：前端使用 temp_token 调用 /auth/login/2fa 完成登录
+		fragment.Set("requires_2fa", "1")
+		fragment.Set("temp_token", result.TotpTempToken)
+		fragment.Set("user_email_masked", service.MaskEmail(result.User.Email))
+		fragment.Set("redirect", redirectTo)
+		redirectWithFragment(c, frontendCallback, fragment)
+		return
+	}
+	tokenPair := result.TokenPair
+	fragment.Set("access_token", tokenPair.AccessToken)
+	fragment.Set("refresh_token", tokenPair.RefreshToken)
+	fragment.Set("expires_in", fmt.Sprintf("%d", tokenPair.ExpiresIn))
+	fragment.Set("token_type", "Bearer")
+	fragment.Set("redirect", redirectTo)
+	redirectWithFragment(c, frontendCallback, fragment)
+}
+
+// ListIdentities 列出当前用户已绑定的 SSO 身份
+// GET /api/v1/user/sso/identities
+func (h *SSOHandler) ListIdentities(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	identities, err := h.ssoService.ListIdentities(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	out := make([]dto.UserIdentity, 0, len(identities))
+	for i := range identities {
+		name := identities[i].Provider
+		if p, err := h.ssoService.Provider(name); err == nil {
+			name = p.DisplayName
+		}
+		out = append(out, *dto.UserIdentityFromService(&identities[i], name))
+	}
+	response.Success(c, out)
+}
+
+// Unlink 解除当前用户的 SSO 身份绑定
+// DELETE /api/v1/user/sso/identities/:id
+func (h *SSOHandler) Unlink(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "Invalid identity ID")
+		return
+	}
+
+	if err := h.ssoService.Unlink(c.Request.Context(), subject.UserID, id); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Identity unlinked successfully"})
+}
+
+// beginAuthorization 生成 state/nonce/PKCE 并写入 cookie，返回提供方授权地址与 state。
+func (h *SSOHandler) beginAuthorization(c *gin.Context, providerID, redirect, defaultRedirect string) (string, string, error) {
+	if _, err := h.ssoService.Provider(providerID); err != nil {
+		return "", "", err
+	}
+	state, err := oauth.GenerateState()
+	if err != nil {
+		return "", "", infraerrors.InternalServer("OAUTH_STATE_GEN_FAILED", "failed to generate oauth state").WithCause(err)
+	}
+	nonce, err := oauth.GenerateState()
+	if err != nil {
+		return "", "", infraerrors.InternalServer("OAUTH_STATE_GEN_FAILED", "failed to generate oauth nonce").WithCause(err)
+	}
+	verifier, err := oauth.GenerateCodeVerifier()
+	if err != nil {
+		return "", "", infraerrors.InternalServer("OAUTH_PKCE_GEN_FAILED", "failed to generate pkce verifier").WithCause(err)
+	}
+
+	authURL, err := h.ssoService.AuthorizeURL(c.Request.Context(), providerID, service.SSOAuthRequest{
+		State:         state,
+		Nonce:         nonce,
+		CodeChallenge: oauth.GenerateCodeChallenge(verifier),
+	})
+	if err != nil {
+		return "", "", err
+	}
+
+	redirectTo := sanitizeFrontendRedirectPath(redirect)
+	if redirectTo == "" {
+		redirectTo = defaultRedirect
+	}
+	secureCookie := isRequestHTTPS(c)
+	setSSOCookie(c, ssoStateCookieName, encodeCookieValue(providerID+"\n"+state), secureCookie)
+	setSSOCookie(c, ssoVerifierCookieName, encodeCookieValue(verifier), secureCookie)
+	setSSOCookie(c, ssoNonceCookieName, encodeCookieValue(nonce), secureCookie)
+	setSSOCookie(c, ssoRedirectCookieName, encodeCookieValue(redirectTo), secureCookie)
+	return authURL, state, nil
+}
+
+// readSSOState 读取 state cookie；cookie 中同时记录了发起授权的提供方，防止跨提供方复用。
+func readSSOState(c *gin.Context, providerID string) (string, error) {
+	raw, err := readCookieDecoded(c, ssoStateCookieName)
+	if err != nil {
+		return "", err
+	}
+	provider, state, ok := strings.Cut(raw, "\n")
+	if !ok || provider != providerID {
+		return "", nil
+	}
+	return state, nil
+}
+
+func setSSOCookie(c *gin.Context, name string, value string, secure bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    value,
+		Path:     ssoCookiePath,
+		MaxAge:   ssoCookieMaxAgeSec,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
+
+func clearSSOCookie(c *gin.Context, name string, secure bool) {
+	http.SetCookie(c.Writer, &http.Cookie{
+		Name:     name,
+		Value:    "",
+		Path:     ssoCookiePath,
+		MaxAge:   -1,
+		HttpOnly: true,
+		Secure:   secure,
+		SameSite: http.SameSiteLaxMode,
+	})
+}
@@ -0,0 +1,96 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// StatementHandler handles monthly billing statements for the current user
+type StatementHandler struct {
+	statementService *service.StatementService
+}
+
+// NewStatementHandler creates a new StatementHandler
+func NewStatementHandler(statementService *service.StatementService) *StatementHandler {
+	return &StatementHandler{statementService: statementService}
+}
+
+// List handles listing the current user's statements (newest period first, without lines)
+// GET /api/v1/statements
+func (h *StatementHandler) List(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	userID := subject.UserID
+	statements, result, err := h.statementService.List(c.Request.Context(), pagination.PaginationParams{Page: page, PageSize: pageSize}, service.StatementFilter{
+		UserID: &userID,
+		Period: c.Query("period"),
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+
+	out := make([]dto.Statement, 0, len(statements))
+	for i := range statements {
+		out = append(out, *dto.StatementFromService(&statements[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// GetByID handles getting one of the current user's statements with line items
+// GET /api/v1/statements/:id
+func (h *StatementHandler) GetByID(c *gin.Context) {
+	statement, ok := h.load(c)
+	if !ok {
+		return
+	}
+	response.Success(c, dto.StatementFromService(statement))
+}
+
+// HTML renders one of the current user's statements as a printable HTML document
+// GET /api/v1/statements/:id/html
+func (h *StatementHandler) HTML(c *gin.Context) {
+	statement, ok := h.load(c)
+	if !ok {
+		return
+	}
+	body, err := h.statementService.RenderHTML(c.Request.Context(), statement)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(http.StatusOK, "text/html; charset=utf-8", []byte(body))
+}
+
+func (h *StatementHandler) load(c *gin.Context) (*service.Statement, bool) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return nil, false
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid statement ID")
+		return nil, false
+	}
+	statement, err := h.statementService.GetForUser(c.Request.Context(), id, subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return nil, false
+	}
+	return statement, true
+}
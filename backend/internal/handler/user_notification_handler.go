@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserNotificationHandler handles alert email settings and one-click unsubscribe
+type UserNotificationHandler struct {
+	notificationService *service.UserNotificationService
+}
+
+// NewUserNotificationHandler creates a new UserNotificationHandler
+func NewUserNotificationHandler(notificationService *service.UserNotificationService) *UserNotificationHandler {
+	return &UserNotificationHandler{notificationService: notificationService}
+}
+
+// UpdateNotificationSettingsRequest represents the update notification settings payload
+type UpdateNotificationSettingsRequest struct {
+	Enabled                  *bool    `json:"enabled"`
+	BalanceThreshold         *float64 `json:"balance_threshold" binding:"omitempty,min=0"`
+	APIKeyQuotaPercent       *int     `json:"api_key_quota_percent" binding:"omitempty,min=0,max=100"`
+	SubscriptionUsagePercent *int     `json:"subscription_usage_percent" binding:"omitempty,min=0,max=100"`
+	ExpiryNoticeDays         *int     `json:"expiry_notice_days" binding:"omitempty,min=0,max=365"`
+}
+
+// GetSettings handles getting the current user's notification settings
+// GET /api/v1/user/notifications
+func (h *UserNotificationHandler) GetSettings(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	settings, err := h.notificationService.GetSettings(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.UserNotificationSettingsFromService(settings))
+}
+
+// UpdateSettings handles updating the current user's notification settings
+// PUT /api/v1/user/notifications
+func (h *UserNotificationHandler) UpdateSettings(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req UpdateNotificationSettingsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	settings, err := h.notificationService.UpdateSettings(c.Request.Context(), subject.UserID, service.UpdateUserNotificationSettingsInput{
+		Enabled:                  req.Enabled,
+		BalanceThreshold:         req.BalanceThreshold,
+		APIKeyQuotaPercent:       req.APIKeyQuotaPercent,
+		SubscriptionUsagePercent: req.SubscriptionUsagePercent,
+		ExpiryNoticeDays:         req.ExpiryNoticeDays,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.UserNotificationSettingsFromService(settings))
+}
+
+// UnsubscribePage renders the unsubscribe confirmation page linked from alert emails
+// GET /api/v1/notifications/unsubscribe?token=
+func (h *UserNotificationHandler) UnsubscribePage(c *gin.Context) {
+	view := service.UserNotificationUnsubscribeView{
+		SiteName: h.notificationService.SiteName(c.Request.Context()),
+		Token:    c.Query("token"),
+	}
+	if view.Token == "" {
+		view.Error = service.ErrUserNotificationInvalidToken.Message
+	}
+	h.renderUnsubscribe(c, view)
+}
+
+// Unsubscribe handles the unsubscribe form submission (no login required; authorized by the token)
+// POST /api/v1/notifications/unsubscribe
+func (h *UserNotificationHandler) Unsubscribe(c *gin.Context) {
+	view := service.UserNotificationUnsubscribeView{
+		SiteName: h.notificationService.SiteName(c.Request.Context()),
+	}
+	token := c.PostForm("token")
+	if token == "" {
+		token = c.Query("token")
+	}
+	if err := h.notificationService.Unsubscribe(c.Request.Context(), token); err != nil {
+		if !errors.Is(err, service.ErrUserNotificationInvalidToken) {
+			response.ErrorFrom(c, err)
+			return
+		}
+		view.Error = service.ErrUserNotificationInvalidToken.Message
+	} else {
+		view.Done = true
+	}
+	h.renderUnsubscribe(c, view)
+}
+
+func (h *UserNotificationHandler) renderUnsubscribe(c *gin.Context, view service.UserNotificationUnsubscribeView) {
+	body, err := service.RenderUserNotificationUnsubscribePage(view)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	status := http.StatusOK
+	if view.Error != "" {
+		status = http.StatusNotFound
+	}
+	c.Header("Cache-Control", "no-store")
+	c.Data(status, "text/html; charset=utf-8", []byte(body))
+}
@@ -0,0 +1,263 @@
+package handler
+
+import (
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/handler/dto"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/response"
+	middleware2 "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+)
+
+// UserWebhookHandler handles outbound webhook configuration for the current user
+type UserWebhookHandler struct {
+	webhookService *service.UserWebhookService
+}
+
+// NewUserWebhookHandler creates a new UserWebhookHandler
+func NewUserWebhookHandler(webhookService *service.UserWebhookService) *UserWebhookHandler {
+	return &UserWebhookHandler{webhookService: webhookService}
+}
+
+// CreateUserWebhookRequest represents the create webhook payload
+type CreateUserWebhookRequest struct {
+	Name                  string   `json:"name" binding:"max=100"`
+	URL                   string   `json:"url" binding:"required"`
+	APIKeyID              *int64   `json:"api_key_id" binding:"omitempty,min=1"`
+	Events                []string `json:"events" binding:"required,min=1"`
+	Enabled               *bool    `json:"enabled"`
+	BalanceThreshold      *float64 `json:"balance_threshold" binding:"omitempty,min=0"`
+	UsageThresholdPercent *int     `json:"usage_threshold_percent" binding:"omitempty,min=1,max=100"`
+	ExpiryNoticeDays      *int     `json:"expiry_notice_days" binding:"omitempty,min=1,max=365"`
+}
+
+// UpdateUserWebhookRequest represents the update webhook payload (api_key_id = 0 switches to user level)
+type UpdateUserWebhookRequest struct {
+	Name                  *string  `json:"name" binding:"omitempty,max=100"`
+	URL                   *string  `json:"url"`
+	APIKeyID              *int64   `json:"api_key_id" binding:"omitempty,min=0"`
+	Events                []string `json:"events" binding:"omitempty,min=1"`
+	Enabled               *bool    `json:"enabled"`
+	BalanceThreshold      *float64 `json:"balance_threshold" binding:"omitempty,min=0"`
+	UsageThresholdPercent *int     `json:"usage_threshold_percent" binding:"omitempty,min=1,max=100"`
+	ExpiryNoticeDays      *int     `json:"expiry_notice_days" binding:"omitempty,min=1,max=365"`
+}
+
+// Events handles listing the event types that can be subscribed to
+// GET /api/v1/webhooks/events
+func (h *UserWebhookHandler) Events(c *gin.Context) {
+	response.Success(c, service.UserWebhookEvents)
+}
+
+// List handles listing the current user's webhooks
+// GET /api/v1/webhooks
+func (h *UserWebhookHandler) List(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	hooks, err := h.webhookService.List(c.Request.Context(), subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	out := make([]dto.UserWebhook, 0, len(hooks))
+	for i := range hooks {
+		out = append(out, *dto.UserWebhookFromService(&hooks[i], false))
+	}
+	response.Success(c, out)
+}
+
+// Create handles creating a webhook; the signing secret is only returned in this response
+// POST /api/v1/webhooks
+func (h *UserWebhookHandler) Create(c *gin.Context) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return
+	}
+
+	var req CreateUserWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	hook, err := h.webhookService.Create(c.Request.Context(), subject.UserID, service.CreateUserWebhookInput{
+		Name:                  req.Name,
+		URL:                   req.URL,
+		APIKeyID:              req.APIKeyID,
+		Events:                req.Events,
+		Enabled:               req.Enabled,
+		BalanceThreshold:      req.BalanceThreshold,
+		UsagePercentThreshold: req.UsageThresholdPercent,
+		ExpiryNoticeDays:      req.ExpiryNoticeDays,
+	})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.UserWebhookFromService(hook, true))
+}
+
+// GetByID handles getting one of the current user's webhooks
+// GET /api/v1/webhooks/:id
+func (h *UserWebhookHandler) GetByID(c *gin.Context) {
+	subject, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhookService.Get(c.Request.Context(), id, subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.UserWebhookFromService(hook, false))
+}
+
+// Update handles updating a webhook
+// PUT /api/v1/webhooks/:id
+func (h *UserWebhookHandler) Update(c *gin.Context) {
+	subject, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	var req UpdateUserWebhookRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		response.BadRequest(c, "Invalid request: "+err.Error())
+		return
+	}
+
+	in := service.UpdateUserWebhookInput{
+		Name:                  req.Name,
+		URL:                   req.URL,
+		Events:                req.Events,
+		Enabled:               req.Enabled,
+		BalanceThreshold:      req.BalanceThreshold,
+		UsagePercentThreshold: req.UsageThresholdPercent,
+		ExpiryNoticeDays:      req.ExpiryNoticeDays,
+	}
+	if req.APIKeyID != nil {
+		if *req.APIKeyID == 0 {
+			in.ClearAPIKey = true
+		} else {
+			in.APIKeyID = req.APIKeyID
+		}
+	}
+	hook, err := h.webhookService.Update(c.Request.Context(), id, subject.UserID, in)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.UserWebhookFromService(hook, false))
+}
+
+// Delete handles deleting a webhook together with its delivery log
+// DELETE /api/v1/webhooks/:id
+func (h *UserWebhookHandler) Delete(c *gin.Context) {
+	subject, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	if err := h.webhookService.Delete(c.Request.Context(), id, subject.UserID); err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, gin.H{"message": "Webhook deleted successfully"})
+}
+
+// RotateSecret handles regenerating the signing secret; the new secret is only returned in this response
+// POST /api/v1/webhooks/:id/rotate-secret
+func (h *UserWebhookHandler) RotateSecret(c *gin.Context) {
+	subject, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	hook, err := h.webhookService.RotateSecret(c.Request.Context(), id, subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.UserWebhookFromService(hook, true))
+}
+
+// SendTest handles sending a webhook.ping event synchronously and returns the delivery result
+// POST /api/v1/webhooks/:id/test
+func (h *UserWebhookHandler) SendTest(c *gin.Context) {
+	subject, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	delivery, err := h.webhookService.SendTest(c.Request.Context(), id, subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.UserWebhookDeliveryFromService(delivery))
+}
+
+// ListDeliveries handles listing a webhook's delivery log (newest first)
+// GET /api/v1/webhooks/:id/deliveries
+func (h *UserWebhookHandler) ListDeliveries(c *gin.Context) {
+	subject, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+
+	page, pageSize := response.ParsePagination(c)
+	deliveries, result, err := h.webhookService.ListDeliveries(c.Request.Context(), id, subject.UserID, pagination.PaginationParams{Page: page, PageSize: pageSize})
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	out := make([]dto.UserWebhookDelivery, 0, len(deliveries))
+	for i := range deliveries {
+		out = append(out, *dto.UserWebhookDeliveryFromService(&deliveries[i]))
+	}
+	response.Paginated(c, out, result.Total, page, pageSize)
+}
+
+// Redeliver handles re-queueing a finished delivery
+// POST /api/v1/webhooks/:id/deliveries/:delivery_id/redeliver
+func (h *UserWebhookHandler) Redeliver(c *gin.Context) {
+	subject, id, ok := h.parseRequest(c)
+	if !ok {
+		return
+	}
+	deliveryID, err := strconv.ParseInt(c.Param("delivery_id"), 10, 64)
+	if err != nil || deliveryID <= 0 {
+		response.BadRequest(c, "Invalid delivery ID")
+		return
+	}
+
+	delivery, err := h.webhookService.Redeliver(c.Request.Context(), id, deliveryID, subject.UserID)
+	if err != nil {
+		response.ErrorFrom(c, err)
+		return
+	}
+	response.Success(c, dto.UserWebhookDeliveryFromService(delivery))
+}
+
+func (h *UserWebhookHandler) parseRequest(c *gin.Context) (middleware2.AuthSubject, int64, bool) {
+	subject, ok := middleware2.GetAuthSubjectFromContext(c)
+	if !ok {
+		response.Unauthorized(c, "User not authenticated")
+		return middleware2.AuthSubject{}, 0, false
+	}
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil || id <= 0 {
+		response.BadRequest(c, "Invalid webhook ID")
+		return middleware2.AuthSubject{}, 0, false
+	}
+	return subject, id, true
+}
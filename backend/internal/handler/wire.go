@@ -32,31 +32,43 @@ func ProvideAdminHandlers(
 	apiKeyHandler *admin.AdminAPIKeyHandler,
 	scheduledTestHandler *admin.ScheduledTestHandler,
 	orderHandler *admin.OrderHandler,
+	organizationHandler *admin.OrganizationHandler,
+	adminTokenHandler *admin.AdminAPITokenHandler,
+	auditLogHandler *admin.AuditLogHandler,
+	statementHandler *admin.StatementHandler,
+	proxyPoolHandler *admin.ProxyPoolHandler,
+	credentialEncryptionHandler *admin.CredentialEncryptionHandler,
 ) *AdminHandlers {
 	return &AdminHandlers{
-		Dashboard:        dashboardHandler,
-		User:             userHandler,
-		Group:            groupHandler,
-		Account:          accountHandler,
-		Announcement:     announcementHandler,
-		DataManagement:   dataManagementHandler,
-		OAuth:            oauthHandler,
-		OpenAIOAuth:      openaiOAuthHandler,
-		GeminiOAuth:      geminiOAuthHandler,
-		AntigravityOAuth: antigravityOAuthHandler,
-		Proxy:            proxyHandler,
-		Redeem:           redeemHandler,
-		Promo:            promoHandler,
-		Setting:          settingHandler,
-		Ops:              opsHandler,
-		System:           systemHandler,
-		Subscription:     subscriptionHandler,
-		Usage:            usageHandler,
-		UserAttribute:    userAttributeHandler,
-		ErrorPassthrough: errorPassthroughHandler,
-		APIKey:           apiKeyHandler,
-		ScheduledTest:    scheduledTestHandler,
-		Order:            orderHandler,
+		Dashboard:            dashboardHandler,
+		User:                 userHandler,
+		Group:                groupHandler,
+		Account:              accountHandler,
+		Announcement:         announcementHandler,
+		DataManagement:       dataManagementHandler,
+		OAuth:                oauthHandler,
+		OpenAIOAuth:          openaiOAuthHandler,
+		GeminiOAuth:          geminiOAuthHandler,
+		AntigravityOAuth:     antigravityOAuthHandler,
+		Proxy:                proxyHandler,
+		Redeem:               redeemHandler,
+		Promo:                promoHandler,
+		Setting:              settingHandler,
+		Ops:                  opsHandler,
+		System:               systemHandler,
+		Subscription:         subscriptionHandler,
+		Usage:                usageHandler,
+		UserAttribute:        userAttributeHandler,
+		ErrorPassthrough:     errorPassthroughHandler,
+		APIKey:               apiKeyHandler,
+		ScheduledTest:        scheduledTestHandler,
+		Order:                orderHandler,
+		Organization:         organizationHandler,
+		AdminToken:           adminTokenHandler,
+		AuditLog:             auditLogHandler,
+		Statement:            statementHandler,
+		ProxyPool:            proxyPoolHandler,
+		CredentialEncryption: credentialEncryptionHandler,
 	}
 }
 
@@ -88,6 +100,14 @@ func ProvideHandlers(
 	soraClientHandler *SoraClientHandler,
 	settingHandler *SettingHandler,
 	totpHandler *TotpHandler,
+	metricsHandler *MetricsHandler,
+	organizationHandler *OrganizationHandler,
+	ssoHandler *SSOHandler,
+	statementHandler *StatementHandler,
+	webhookHandler *UserWebhookHandler,
+	notificationHandler *UserNotificationHandler,
+	messageBatchHandler *MessageBatchHandler,
+	openaiBatchHandler *OpenAIBatchHandler,
 	_ *service.IdempotencyCoordinator,
 	_ *service.IdempotencyCleanupService,
 ) *Handlers {
@@ -108,6 +128,14 @@ func ProvideHandlers(
 		SoraClient:    soraClientHandler,
 		Setting:       settingHandler,
 		Totp:          totpHandler,
+		Metrics:       metricsHandler,
+		Organization:  organizationHandler,
+		SSO:           ssoHandler,
+		Statement:     statementHandler,
+		Webhook:       webhookHandler,
+		Notification:  notificationHandler,
+		MessageBatch:  messageBatchHandler,
+		OpenAIBatch:   openaiBatchHandler,
 	}
 }
 
@@ -127,6 +155,14 @@ var ProviderSet = wire.NewSet(
 	NewOpenAIGatewayHandler,
 	NewSoraGatewayHandler,
 	NewTotpHandler,
+	NewMetricsHandler,
+	NewOrganizationHandler,
+	NewSSOHandler,
+	NewStatementHandler,
+	NewUserWebhookHandler,
+	NewMessageBatchHandler,
+	NewOpenAIBatchHandler,
+	NewUserNotificationHandler,
 	ProvideSettingHandler,
 
 	// Admin handlers
@@ -151,8 +187,14 @@ var ProviderSet = wire.NewSet(
 	admin.NewUserAttributeHandler,
 	admin.NewErrorPassthroughHandler,
 	admin.NewAdminAPIKeyHandler,
+	admin.NewAdminAPITokenHandler,
+	admin.NewAuditLogHandler,
+	admin.NewStatementHandler,
+	admin.NewProxyPoolHandler,
+	admin.NewCredentialEncryptionHandler,
 	admin.NewScheduledTestHandler,
 	admin.NewOrderHandler,
+	admin.NewOrganizationHandler,
 
 	// AdminHandlers and Handlers constructors
 	ProvideAdminHandlers,
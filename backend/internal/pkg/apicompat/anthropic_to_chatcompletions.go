@@ -0,0 +1,291 @@
+package apicompat
+
+import (
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Non-streaming: AnthropicResponse → ChatCompletionsResponse
+// ---------------------------------------------------------------------------
+
+// AnthropicToChatCompletions converts an Anthropic Messages response into a
+// Chat Completions response. Text blocks are concatenated into the message
+// content, thinking blocks into reasoning_content and tool_use blocks become
+// tool_calls.
+func AnthropicToChatCompletions(resp *AnthropicResponse, model string) *ChatCompletionsResponse {
+	msg := &ChatResponseMessage{Role: "assistant"}
+
+	var text strings.Builder
+	var reasoning strings.Builder
+	for _, b := range resp.Content {
+		switch b.Type {
+		case "text":
+			text.WriteString(b.Text)
+		case "thinking":
+			reasoning.WriteString(b.Thinking)
+		case "tool_use":
+			args := "{}"
+			if len(b.Input) > 0 {
+				args = string(b.Input)
+			}
+			msg.ToolCalls = append(msg.ToolCalls, ChatToolCall{
+				ID:   b.ID,
+				Type: "function",
+				Function: ChatFunctionCall{
+					Name:      b.Name,
+					Arguments: args,
+				},
+			})
+		}
+	}
+
+	if text.Len() > 0 || len(msg.ToolCalls) == 0 {
+		content := text.String()
+		msg.Content = &content
+	}
+	msg.ReasoningContent = reasoning.String()
+
+	if model == "" {
+		model = resp.Model
+	}
+	finishReason := anthropicStopReasonToChatFinishReason(resp.StopReason, len(msg.ToolCalls) > 0)
+	usage := anthropicUsageToChat(resp.Usage)
+
+	return &ChatCompletionsResponse{
+		ID:      toChatCompletionID(resp.ID),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   model,
+		Choices: []ChatChoice{{
+			Index:        0,
+			Message:      msg,
+			FinishReason: &finishReason,
+		}},
+		Usage: &usage,
+	}
+}
+
+func anthropicStopReasonToChatFinishReason(stopReason string, hasToolCalls bool) string {
+	switch stopReason {
+	case "max_tokens", "model_context_window_exceeded":
+		return "length"
+	case "tool_use":
+		return "tool_calls"
+	case "refusal":
+		return "content_filter"
+	}
+	if hasToolCalls {
+		return "tool_calls"
+	}
+	return "stop"
+}
+
+// anthropicUsageToChat maps Anthropic usage to Chat usage. OpenAI counts
+// cached prompt tokens inside prompt_tokens, so cache reads and writes are
+// folded back into the prompt total.
+func anthropicUsageToChat(u AnthropicUsage) ChatUsage {
+	prompt := u.InputTokens + u.CacheCreationInputTokens + u.CacheReadInputTokens
+	out := ChatUsage{
+		PromptTokens:     prompt,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      prompt + u.OutputTokens,
+	}
+	if u.CacheReadInputTokens > 0 {
+		out.PromptTokensDetails = &ChatPromptTokensDetails{CachedTokens: u.CacheReadInputTokens}
+	}
+	return out
+}
+
+// ---------------------------------------------------------------------------
+// Streaming: AnthropicStreamEvent → []ChatCompletionsChunk (stateful converter)
+// ---------------------------------------------------------------------------
+
+// AnthropicEventToChatState tracks state for converting a sequence of
+// Anthropic SSE events into Chat Completions chunks.
+type AnthropicEventToChatState struct {
+	RoleSent bool
+	Finished bool
+
+	// IncludeUsage mirrors stream_options.include_usage.
+	IncludeUsage bool
+
+	// BlockIdxToToolIdx maps Anthropic content block index → Chat tool_calls index.
+	BlockIdxToToolIdx map[int]int
+	NextToolIdx       int
+
+	StopReason string
+	Usage      AnthropicUsage
+
+	ID      string
+	Model   string
+	Created int64
+}
+
+// NewAnthropicEventToChatState returns an initialised stream state.
+func NewAnthropicEventToChatState() *AnthropicEventToChatState {
+	return &AnthropicEventToChatState{
+		BlockIdxToToolIdx: make(map[int]int),
+		Created:           time.Now().Unix(),
+	}
+}
+
+// AnthropicEventToChatChunks converts a single Anthropic SSE event into zero
+// or more Chat Completions chunks, updating state as it goes. Error and ping
+// events are not handled here.
+func AnthropicEventToChatChunks(
+	evt *AnthropicStreamEvent,
+	state *AnthropicEventToChatState,
+) []ChatCompletionsChunk {
+	switch evt.Type {
+	case "message_start":
+		return anthToChatHandleMessageStart(evt, state)
+	case "content_block_start":
+		return anthToChatHandleBlockStart(evt, state)
+	case "content_block_delta":
+		return anthToChatHandleBlockDelta(evt, state)
+	case "message_delta":
+		if evt.Delta != nil && evt.Delta.StopReason != "" {
+			state.StopReason = evt.Delta.StopReason
+		}
+		if evt.Usage != nil {
+			mergeAnthropicStreamUsage(&state.Usage, evt.Usage)
+		}
+		return nil
+	case "message_stop":
+		return anthToChatFinish(state)
+	default:
+		return nil
+	}
+}
+
+// FinalizeAnthropicChatStream emits the terminating chunks if the stream
+// ended without a message_stop event.
+func FinalizeAnthropicChatStream(state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if !state.RoleSent || state.Finished {
+		return nil
+	}
+	return anthToChatFinish(state)
+}
+
+// --- internal handlers ---
+
+func (state *AnthropicEventToChatState) chunkMeta() ChatCompletionsChunk {
+	return ChatCompletionsChunk{
+		ID:      toChatCompletionID(state.ID),
+		Object:  "chat.completion.chunk",
+		Created: state.Created,
+		Model:   state.Model,
+	}
+}
+
+func (state *AnthropicEventToChatState) withRole(delta *ChatResponseMessage) []ChatCompletionsChunk {
+	var chunks []ChatCompletionsChunk
+	if !state.RoleSent {
+		state.RoleSent = true
+		empty := ""
+		chunks = append(chunks, chatDeltaChunk(state.chunkMeta(), &ChatResponseMessage{Role: "assistant", Content: &empty}))
+	}
+	if delta != nil {
+		chunks = append(chunks, chatDeltaChunk(state.chunkMeta(), delta))
+	}
+	return chunks
+}
+
+func anthToChatHandleMessageStart(evt *AnthropicStreamEvent, state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if evt.Message != nil {
+		if state.ID == "" {
+			state.ID = evt.Message.ID
+		}
+		// Only use upstream model if no override was set (e.g. originalModel)
+		if state.Model == "" {
+			state.Model = evt.Message.Model
+		}
+		mergeAnthropicStreamUsage(&state.Usage, &evt.Message.Usage)
+	}
+	return state.withRole(nil)
+}
+
+func anthToChatHandleBlockStart(evt *AnthropicStreamEvent, state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if evt.ContentBlock == nil || evt.Index == nil || evt.ContentBlock.Type != "tool_use" {
+		return nil
+	}
+	idx := state.NextToolIdx
+	state.NextToolIdx++
+	state.BlockIdxToToolIdx[*evt.Index] = idx
+
+	return state.withRole(&ChatResponseMessage{
+		ToolCalls: []ChatToolCall{{
+			Index: &idx,
+			ID:    evt.ContentBlock.ID,
+			Type:  "function",
+			Function: ChatFunctionCall{
+				Name:      evt.ContentBlock.Name,
+				Arguments: "",
+			},
+		}},
+	})
+}
+
+func anthToChatHandleBlockDelta(evt *AnthropicStreamEvent, state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if evt.Delta == nil {
+		return nil
+	}
+	switch evt.Delta.Type {
+	case "text_delta":
+		if evt.Delta.Text == "" {
+			return nil
+		}
+		text := evt.Delta.Text
+		return state.withRole(&ChatResponseMessage{Content: &text})
+	case "thinking_delta":
+		if evt.Delta.Thinking == "" {
+			return nil
+		}
+		return state.withRole(&ChatResponseMessage{ReasoningContent: evt.Delta.Thinking})
+	case "input_json_delta":
+		if evt.Delta.PartialJSON == "" || evt.Index == nil {
+			return nil
+		}
+		idx, ok := state.BlockIdxToToolIdx[*evt.Index]
+		if !ok {
+			return nil
+		}
+		return state.withRole(&ChatResponseMessage{
+			ToolCalls: []ChatToolCall{{
+				Index:    &idx,
+				Function: ChatFunctionCall{Arguments: evt.Delta.PartialJSON},
+			}},
+		})
+	default:
+		return nil
+	}
+}
+
+func anthToChatFinish(state *AnthropicEventToChatState) []ChatCompletionsChunk {
+	if state.Finished {
+		return nil
+	}
+	chunks := state.withRole(nil)
+	finishReason := anthropicStopReasonToChatFinishReason(state.StopReason, state.NextToolIdx > 0)
+	usage := anthropicUsageToChat(state.Usage)
+	return append(chunks, chatFinishChunks(state.chunkMeta(), &state.Finished, finishReason, state.IncludeUsage, &usage)...)
+}
+
+// mergeAnthropicStreamUsage overlays non-zero counters from src onto dst.
+// message_start carries input/cache counts while message_delta carries the
+// cumulative output count (and sometimes repeats the input counts).
+func mergeAnthropicStreamUsage(dst, src *AnthropicUsage) {
+	if src.InputTokens > 0 {
+		dst.InputTokens = src.InputTokens
+	}
+	if src.OutputTokens > 0 {
+		dst.OutputTokens = src.OutputTokens
+	}
+	if src.CacheCreationInputTokens > 0 {
+		dst.CacheCreationInputTokens = src.CacheCreationInputTokens
+	}
+	if src.CacheReadInputTokens > 0 {
+		dst.CacheReadInputTokens = src.CacheReadInputTokens
+	}
+}
@@ -0,0 +1,353 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func intPtr(v int) *int { return &v }
+
+// ---------------------------------------------------------------------------
+// ChatCompletionsToResponses tests
+// ---------------------------------------------------------------------------
+
+func TestChatCompletionsToResponses_BasicText(t *testing.T) {
+	req := &ChatCompletionsRequest{
+		Model:     "gpt-5.2",
+		MaxTokens: intPtr(16),
+		Stream:    true,
+		Messages: []ChatMessage{
+			{Role: "system", Content: json.RawMessage(`"Be terse."`)},
+			{Role: "user", Content: json.RawMessage(`"Hello"`)},
+		},
+	}
+
+	resp, err := ChatCompletionsToResponses(req)
+	require.NoError(t, err)
+	assert.Equal(t, "gpt-5.2", resp.Model)
+	assert.True(t, resp.Stream)
+	assert.Equal(t, minMaxOutputTokens, *resp.MaxOutputTokens)
+	assert.False(t, *resp.Store)
+
+	var items []ResponsesInputItem
+	require.NoError(t, json.Unmarshal(resp.Input, &items))
+	require.Len(t, items, 2)
+	assert.Equal(t, "system", items[0].Role)
+	assert.Equal(t, "user", items[1].Role)
+}
+
+func TestChatCompletionsToResponses_ToolRoundTrip(t *testing.T) {
+	req := &ChatCompletionsRequest{
+		Model: "gpt-5.2",
+		Messages: []ChatMessage{
+			{Role: "user", Content: json.RawMessage(`"Weather in Paris?"`)},
+			{Role: "assistant", Content: json.RawMessage(`null`), ToolCalls: []ChatToolCall{{
+				ID:       "call_1",
+				Type:     "function",
+				Function: ChatFunctionCall{Name: "get_weather", Arguments: `{"city":"Paris"}`},
+			}}},
+			{Role: "tool", ToolCallID: "call_1", Content: json.RawMessage(`"Sunny"`)},
+		},
+		Tools: []ChatTool{{Type: "function", Function: &ChatFunction{
+			Name:       "get_weather",
+			Parameters: json.RawMessage(`{"type":"object"}`),
+		}}},
+		ToolChoice: json.RawMessage(`{"type":"function","function":{"name":"get_weather"}}`),
+	}
+
+	resp, err := ChatCompletionsToResponses(req)
+	require.NoError(t, err)
+
+	var items []ResponsesInputItem
+	require.NoError(t, json.Unmarshal(resp.Input, &items))
+	require.Len(t, items, 3)
+	assert.Equal(t, "function_call", items[1].Type)
+	assert.Equal(t, "fc_call_1", items[1].CallID)
+	assert.Equal(t, `{"city":"Paris"}`, items[1].Arguments)
+	assert.Equal(t, "function_call_output", items[2].Type)
+	assert.Equal(t, "fc_call_1", items[2].CallID)
+	assert.Equal(t, "Sunny", items[2].Output)
+
+	require.Len(t, resp.Tools, 1)
+	assert.Equal(t, "get_weather", resp.Tools[0].Name)
+	assert.JSONEq(t, `{"type":"function","name":"get_weather"}`, string(resp.ToolChoice))
+}
+
+func TestChatCompletionsToResponses_ImagesAndFormat(t *testing.T) {
+	req := &ChatCompletionsRequest{
+		Model: "gpt-5.2",
+		Messages: []ChatMessage{{Role: "user", Content: json.RawMessage(
+			`[{"type":"text","text":"What is this?"},{"type":"image_url","image_url":{"url":"https://example.com/a.png"}}]`,
+		)}},
+		ReasoningEffort: "low",
+		ResponseFormat:  json.RawMessage(`{"type":"json_schema","json_schema":{"name":"out","schema":{"type":"object"},"strict":true}}`),
+	}
+
+	resp, err := ChatCompletionsToResponses(req)
+	require.NoError(t, err)
+
+	var items []struct {
+		Role    string                 `json:"role"`
+		Content []ResponsesContentPart `json:"content"`
+	}
+	require.NoError(t, json.Unmarshal(resp.Input, &items))
+	require.Len(t, items, 1)
+	require.Len(t, items[0].Content, 2)
+	assert.Equal(t, "input_text", items[0].Content[0].Type)
+	assert.Equal(t, "input_image", items[0].Content[1].Type)
+	assert.Equal(t, "https://example.com/a.png", items[0].Content[1].ImageURL)
+
+	require.NotNil(t, resp.Reasoning)
+	assert.Equal(t, "low", resp.Reasoning.Effort)
+	require.NotNil(t, resp.Text)
+	assert.JSONEq(t, `{"type":"json_schema","name":"out","schema":{"type":"object"},"strict":true}`, string(resp.Text.Format))
+}
+
+// ---------------------------------------------------------------------------
+// ResponsesToChatCompletions tests
+// ---------------------------------------------------------------------------
+
+func TestResponsesToChatCompletions_TextAndToolCalls(t *testing.T) {
+	resp := &ResponsesResponse{
+		ID:     "resp_123",
+		Status: "completed",
+		Output: []ResponsesOutput{
+			{Type: "reasoning", Summary: []ResponsesSummary{{Type: "summary_text", Text: "thinking"}}},
+			{Type: "message", Content: []ResponsesContentPart{{Type: "output_text", Text: "Hi"}}},
+			{Type: "function_call", CallID: "fc_call_1", Name: "lookup", Arguments: `{"q":1}`},
+		},
+		Usage: &ResponsesUsage{
+			InputTokens:        10,
+			OutputTokens:       5,
+			InputTokensDetails: &ResponsesInputTokensDetails{CachedTokens: 4},
+		},
+	}
+
+	out := ResponsesToChatCompletions(resp, "gpt-5.2")
+	assert.Equal(t, "chatcmpl-123", out.ID)
+	assert.Equal(t, "chat.completion", out.Object)
+	assert.Equal(t, "gpt-5.2", out.Model)
+	require.Len(t, out.Choices, 1)
+	msg := out.Choices[0].Message
+	require.NotNil(t, msg.Content)
+	assert.Equal(t, "Hi", *msg.Content)
+	assert.Equal(t, "thinking", msg.ReasoningContent)
+	require.Len(t, msg.ToolCalls, 1)
+	assert.Equal(t, "call_1", msg.ToolCalls[0].ID)
+	assert.Equal(t, "tool_calls", *out.Choices[0].FinishReason)
+	assert.Equal(t, 15, out.Usage.TotalTokens)
+	assert.Equal(t, 4, out.Usage.PromptTokensDetails.CachedTokens)
+}
+
+func TestResponsesToChatCompletions_Length(t *testing.T) {
+	resp := &ResponsesResponse{
+		Status:            "incomplete",
+		IncompleteDetails: &ResponsesIncompleteDetails{Reason: "max_output_tokens"},
+		Output:            []ResponsesOutput{{Type: "message", Content: []ResponsesContentPart{{Type: "output_text", Text: "cut"}}}},
+	}
+	out := ResponsesToChatCompletions(resp, "gpt-5.2")
+	assert.Equal(t, "length", *out.Choices[0].FinishReason)
+	assert.Nil(t, out.Usage)
+}
+
+func TestResponsesEventToChatChunks_Stream(t *testing.T) {
+	state := NewResponsesEventToChatState()
+	state.Model = "client-model"
+	state.IncludeUsage = true
+
+	var chunks []ChatCompletionsChunk
+	events := []ResponsesStreamEvent{
+		{Type: "response.created", Response: &ResponsesResponse{ID: "resp_1", Model: "gpt-5.2"}},
+		{Type: "response.output_text.delta", Delta: "Hel"},
+		{Type: "response.output_text.delta", Delta: "lo"},
+		{Type: "response.output_item.added", OutputIndex: 1, Item: &ResponsesOutput{Type: "function_call", CallID: "fc_call_9", Name: "f"}},
+		{Type: "response.function_call_arguments.delta", OutputIndex: 1, Delta: `{"a":`},
+		{Type: "response.function_call_arguments.delta", OutputIndex: 1, Delta: `1}`},
+		{Type: "response.completed", Response: &ResponsesResponse{Status: "completed", Usage: &ResponsesUsage{InputTokens: 3, OutputTokens: 2}}},
+	}
+	for i := range events {
+		chunks = append(chunks, ResponsesEventToChatChunks(&events[i], state)...)
+	}
+	assert.Nil(t, FinalizeResponsesChatStream(state))
+
+	require.Len(t, chunks, 8)
+	assert.Equal(t, "assistant", chunks[0].Choices[0].Delta.Role)
+	assert.Equal(t, "client-model", chunks[0].Model)
+	assert.Equal(t, "chatcmpl-1", chunks[0].ID)
+	assert.Equal(t, "Hel", *chunks[1].Choices[0].Delta.Content)
+
+	toolStart := chunks[3].Choices[0].Delta.ToolCalls[0]
+	assert.Equal(t, 0, *toolStart.Index)
+	assert.Equal(t, "call_9", toolStart.ID)
+	assert.Equal(t, "f", toolStart.Function.Name)
+	assert.Equal(t, `1}`, chunks[5].Choices[0].Delta.ToolCalls[0].Function.Arguments)
+
+	assert.Equal(t, "tool_calls", *chunks[6].Choices[0].FinishReason)
+	assert.Empty(t, chunks[7].Choices)
+	require.NotNil(t, chunks[7].Usage)
+	assert.Equal(t, 5, chunks[7].Usage.TotalTokens)
+
+	sse, err := ChatChunkToSSE(chunks[1])
+	require.NoError(t, err)
+	assert.True(t, strings.HasPrefix(sse, "data: {"))
+	assert.True(t, strings.HasSuffix(sse, "\n\n"))
+}
+
+func TestFinalizeResponsesChatStream_Truncated(t *testing.T) {
+	state := NewResponsesEventToChatState()
+	ResponsesEventToChatChunks(&ResponsesStreamEvent{Type: "response.output_text.delta", Delta: "x"}, state)
+
+	final := FinalizeResponsesChatStream(state)
+	require.Len(t, final, 1)
+	assert.Equal(t, "stop", *final[0].Choices[0].FinishReason)
+	assert.Nil(t, FinalizeResponsesChatStream(state))
+}
+
+// ---------------------------------------------------------------------------
+// ChatCompletionsToAnthropic tests
+// ---------------------------------------------------------------------------
+
+func TestChatCompletionsToAnthropic_MessagesAndSystem(t *testing.T) {
+	req := &ChatCompletionsRequest{
+		Model: "claude-sonnet-4-5",
+		Messages: []ChatMessage{
+			{Role: "system", Content: json.RawMessage(`"Sys A"`)},
+			{Role: "developer", Content: json.RawMessage(`[{"type":"text","text":"Sys B"}]`)},
+			{Role: "user", Content: json.RawMessage(`"Call the tool"`)},
+			{Role: "assistant", ToolCalls: []ChatToolCall{
+				{ID: "toolu_1", Type: "function", Function: ChatFunctionCall{Name: "a", Arguments: `{"x":1}`}},
+				{ID: "toolu_2", Type: "function", Function: ChatFunctionCall{Name: "b", Arguments: `not json`}},
+			}},
+			{Role: "tool", ToolCallID: "toolu_1", Content: json.RawMessage(`"r1"`)},
+			{Role: "tool", ToolCallID: "toolu_2", Content: json.RawMessage(`"r2"`)},
+			{Role: "user", Content: json.RawMessage(`[{"type":"image_url","image_url":{"url":"data:image/png;base64,AAAA"}}]`)},
+		},
+		Stop: json.RawMessage(`"END"`),
+	}
+
+	out, err := ChatCompletionsToAnthropic(req)
+	require.NoError(t, err)
+	assert.Equal(t, defaultAnthropicMaxTokens, out.MaxTokens)
+	assert.Equal(t, []string{"END"}, out.StopSeqs)
+
+	var system string
+	require.NoError(t, json.Unmarshal(out.System, &system))
+	assert.Equal(t, "Sys A\n\nSys B", system)
+
+	require.Len(t, out.Messages, 3)
+	assert.Equal(t, "user", out.Messages[0].Role)
+	assert.Equal(t, "assistant", out.Messages[1].Role)
+	assert.Equal(t, "user", out.Messages[2].Role)
+
+	var assistant []AnthropicContentBlock
+	require.NoError(t, json.Unmarshal(out.Messages[1].Content, &assistant))
+	require.Len(t, assistant, 2)
+	assert.JSONEq(t, `{"x":1}`, string(assistant[0].Input))
+	assert.JSONEq(t, `{}`, string(assistant[1].Input))
+
+	// Both tool results and the following image are merged into one user turn.
+	var lastUser []AnthropicContentBlock
+	require.NoError(t, json.Unmarshal(out.Messages[2].Content, &lastUser))
+	require.Len(t, lastUser, 3)
+	assert.Equal(t, "tool_result", lastUser[0].Type)
+	assert.Equal(t, "toolu_2", lastUser[1].ToolUseID)
+	assert.Equal(t, "image", lastUser[2].Type)
+	assert.Equal(t, "base64", lastUser[2].Source.Type)
+	assert.Equal(t, "image/png", lastUser[2].Source.MediaType)
+}
+
+func TestChatCompletionsToAnthropic_ToolChoiceAndReasoning(t *testing.T) {
+	parallel := false
+	temp := 0.2
+	req := &ChatCompletionsRequest{
+		Model:               "claude-sonnet-4-5",
+		MaxCompletionTokens: intPtr(1000),
+		Temperature:         &temp,
+		Messages:            []ChatMessage{{Role: "user", Content: json.RawMessage(`"Hi"`)}},
+		Tools:               []ChatTool{{Type: "function", Function: &ChatFunction{Name: "f"}}},
+		ToolChoice:          json.RawMessage(`"required"`),
+		ParallelToolCalls:   &parallel,
+		ReasoningEffort:     "medium",
+	}
+
+	out, err := ChatCompletionsToAnthropic(req)
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"type":"any","disable_parallel_tool_use":true}`, string(out.ToolChoice))
+	require.Len(t, out.Tools, 1)
+	assert.JSONEq(t, `{"type":"object","properties":{}}`, string(out.Tools[0].InputSchema))
+	require.NotNil(t, out.Thinking)
+	assert.Equal(t, 8192, out.Thinking.BudgetTokens)
+	assert.Greater(t, out.MaxTokens, out.Thinking.BudgetTokens)
+	assert.Nil(t, out.Temperature)
+}
+
+// ---------------------------------------------------------------------------
+// AnthropicToChatCompletions tests
+// ---------------------------------------------------------------------------
+
+func TestAnthropicToChatCompletions_NonStreaming(t *testing.T) {
+	resp := &AnthropicResponse{
+		ID:         "msg_abc",
+		Model:      "claude-sonnet-4-5",
+		StopReason: "tool_use",
+		Content: []AnthropicContentBlock{
+			{Type: "thinking", Thinking: "hmm"},
+			{Type: "tool_use", ID: "toolu_1", Name: "f", Input: json.RawMessage(`{"a":1}`)},
+		},
+		Usage: AnthropicUsage{InputTokens: 5, OutputTokens: 7, CacheReadInputTokens: 100},
+	}
+
+	out := AnthropicToChatCompletions(resp, "alias-model")
+	assert.Equal(t, "chatcmpl-abc", out.ID)
+	assert.Equal(t, "alias-model", out.Model)
+	msg := out.Choices[0].Message
+	assert.Nil(t, msg.Content)
+	assert.Equal(t, "hmm", msg.ReasoningContent)
+	require.Len(t, msg.ToolCalls, 1)
+	assert.Equal(t, `{"a":1}`, msg.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "tool_calls", *out.Choices[0].FinishReason)
+	assert.Equal(t, 105, out.Usage.PromptTokens)
+	assert.Equal(t, 100, out.Usage.PromptTokensDetails.CachedTokens)
+	assert.Equal(t, 112, out.Usage.TotalTokens)
+
+	data, err := json.Marshal(out)
+	require.NoError(t, err)
+	assert.Contains(t, string(data), `"content":null`)
+}
+
+func TestAnthropicEventToChatChunks_Stream(t *testing.T) {
+	state := NewAnthropicEventToChatState()
+	state.IncludeUsage = true
+
+	idx0, idx1 := 0, 1
+	events := []AnthropicStreamEvent{
+		{Type: "message_start", Message: &AnthropicResponse{ID: "msg_1", Model: "claude-sonnet-4-5", Usage: AnthropicUsage{InputTokens: 9}}},
+		{Type: "content_block_start", Index: &idx0, ContentBlock: &AnthropicContentBlock{Type: "text"}},
+		{Type: "content_block_delta", Index: &idx0, Delta: &AnthropicDelta{Type: "text_delta", Text: "Hi"}},
+		{Type: "content_block_stop", Index: &idx0},
+		{Type: "content_block_start", Index: &idx1, ContentBlock: &AnthropicContentBlock{Type: "tool_use", ID: "toolu_1", Name: "f"}},
+		{Type: "content_block_delta", Index: &idx1, Delta: &AnthropicDelta{Type: "input_json_delta", PartialJSON: `{}`}},
+		{Type: "message_delta", Delta: &AnthropicDelta{StopReason: "tool_use"}, Usage: &AnthropicUsage{OutputTokens: 4}},
+		{Type: "message_stop"},
+	}
+	var chunks []ChatCompletionsChunk
+	for i := range events {
+		chunks = append(chunks, AnthropicEventToChatChunks(&events[i], state)...)
+	}
+	assert.Nil(t, FinalizeAnthropicChatStream(state))
+
+	require.Len(t, chunks, 6)
+	assert.Equal(t, "assistant", chunks[0].Choices[0].Delta.Role)
+	assert.Equal(t, "claude-sonnet-4-5", chunks[0].Model)
+	assert.Equal(t, "Hi", *chunks[1].Choices[0].Delta.Content)
+	assert.Equal(t, "toolu_1", chunks[2].Choices[0].Delta.ToolCalls[0].ID)
+	assert.Equal(t, `{}`, chunks[3].Choices[0].Delta.ToolCalls[0].Function.Arguments)
+	assert.Equal(t, "tool_calls", *chunks[4].Choices[0].FinishReason)
+	require.NotNil(t, chunks[5].Usage)
+	assert.Equal(t, 9, chunks[5].Usage.PromptTokens)
+	assert.Equal(t, 4, chunks[5].Usage.CompletionTokens)
+}
@@ -0,0 +1,304 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// chatReasoningBudgets maps Chat Completions reasoning_effort to an Anthropic
+// extended thinking budget.
+var chatReasoningBudgets = map[string]int{
+	"minimal": 1024,
+	"low":     2048,
+	"medium":  8192,
+	"high":    16384,
+}
+
+// ChatCompletionsToAnthropic converts an OpenAI Chat Completions request into
+// an Anthropic Messages request. System/developer messages are hoisted into
+// the system prompt, tool messages become tool_result blocks and consecutive
+// messages of the same role are merged so roles alternate as Anthropic
+// requires.
+func ChatCompletionsToAnthropic(req *ChatCompletionsRequest) (*AnthropicRequest, error) {
+	system, msgs, err := convertChatMessagesToAnthropic(req.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &AnthropicRequest{
+		Model:       req.Model,
+		MaxTokens:   chatMaxTokens(req),
+		Messages:    msgs,
+		Stream:      req.Stream,
+		Temperature: req.Temperature,
+		TopP:        req.TopP,
+	}
+	if out.MaxTokens <= 0 {
+		out.MaxTokens = defaultAnthropicMaxTokens
+	}
+
+	if system != "" {
+		out.System, _ = json.Marshal(system)
+	}
+
+	if len(req.Stop) > 0 {
+		stops, err := parseChatStop(req.Stop)
+		if err != nil {
+			return nil, fmt.Errorf("parse stop: %w", err)
+		}
+		out.StopSeqs = stops
+	}
+
+	if len(req.Tools) > 0 {
+		out.Tools = convertChatToolsToAnthropic(req.Tools)
+	}
+
+	if len(req.ToolChoice) > 0 || (req.ParallelToolCalls != nil && !*req.ParallelToolCalls) {
+		tc, err := convertChatToolChoiceToAnthropic(req.ToolChoice, req.ParallelToolCalls)
+		if err != nil {
+			return nil, fmt.Errorf("convert tool_choice: %w", err)
+		}
+		out.ToolChoice = tc
+	}
+
+	// reasoning_effort → extended thinking. Anthropic requires max_tokens to
+	// exceed the thinking budget and rejects custom sampling while thinking.
+	if budget, ok := chatReasoningBudgets[strings.TrimSpace(req.ReasoningEffort)]; ok {
+		out.Thinking = &AnthropicThinking{Type: "enabled", BudgetTokens: budget}
+		if out.MaxTokens <= budget {
+			out.MaxTokens = budget + out.MaxTokens
+		}
+		out.Temperature = nil
+		out.TopP = nil
+	}
+
+	return out, nil
+}
+
+// convertChatMessagesToAnthropic splits the system prompt from the message
+// list and converts the remaining messages into Anthropic messages.
+func convertChatMessagesToAnthropic(msgs []ChatMessage) (string, []AnthropicMessage, error) {
+	var systemParts []string
+	var roles []string
+	var blocks [][]AnthropicContentBlock
+
+	appendBlocks := func(role string, bs []AnthropicContentBlock) {
+		if len(bs) == 0 {
+			return
+		}
+		if n := len(roles); n > 0 && roles[n-1] == role {
+			blocks[n-1] = append(blocks[n-1], bs...)
+			return
+		}
+		roles = append(roles, role)
+		blocks = append(blocks, bs)
+	}
+
+	for _, m := range msgs {
+		switch m.Role {
+		case "system", "developer":
+			text, _, err := parseChatContent(m.Content)
+			if err != nil {
+				return "", nil, err
+			}
+			if text != "" {
+				systemParts = append(systemParts, text)
+			}
+
+		case "assistant":
+			bs, err := chatAssistantToAnthropicBlocks(m)
+			if err != nil {
+				return "", nil, err
+			}
+			appendBlocks("assistant", bs)
+
+		case "tool":
+			text, _, err := parseChatContent(m.Content)
+			if err != nil {
+				return "", nil, err
+			}
+			content, _ := json.Marshal(text)
+			appendBlocks("user", []AnthropicContentBlock{{
+				Type:      "tool_result",
+				ToolUseID: m.ToolCallID,
+				Content:   content,
+			}})
+
+		default:
+			bs, err := chatUserToAnthropicBlocks(m.Content)
+			if err != nil {
+				return "", nil, err
+			}
+			appendBlocks("user", bs)
+		}
+	}
+
+	out := make([]AnthropicMessage, 0, len(roles))
+	for i, role := range roles {
+		content, err := json.Marshal(blocks[i])
+		if err != nil {
+			return "", nil, err
+		}
+		out = append(out, AnthropicMessage{Role: role, Content: content})
+	}
+	return strings.Join(systemParts, "\n\n"), out, nil
+}
+
+// chatUserToAnthropicBlocks converts user content into text and image blocks.
+func chatUserToAnthropicBlocks(raw json.RawMessage) ([]AnthropicContentBlock, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil, nil
+		}
+		return []AnthropicContentBlock{{Type: "text", Text: s}}, nil
+	}
+
+	parts, err := parseChatContentParts(raw)
+	if err != nil {
+		return nil, err
+	}
+	var out []AnthropicContentBlock
+	for _, p := range parts {
+		switch p.Type {
+		case "text":
+			if p.Text != "" {
+				out = append(out, AnthropicContentBlock{Type: "text", Text: p.Text})
+			}
+		case "image_url":
+			if p.ImageURL == nil || p.ImageURL.URL == "" {
+				continue
+			}
+			out = append(out, AnthropicContentBlock{
+				Type:   "image",
+				Source: chatImageURLToAnthropicSource(p.ImageURL.URL),
+			})
+		}
+	}
+	return out, nil
+}
+
+// chatImageURLToAnthropicSource maps a data URI to a base64 source and any
+// other URL to a url source.
+func chatImageURLToAnthropicSource(url string) *AnthropicImageSource {
+	if rest, ok := strings.CutPrefix(url, "data:"); ok {
+		if meta, data, ok := strings.Cut(rest, ","); ok {
+			mediaType, isBase64 := strings.CutSuffix(meta, ";base64")
+			if isBase64 {
+				return &AnthropicImageSource{Type: "base64", MediaType: mediaType, Data: data}
+			}
+		}
+	}
+	return &AnthropicImageSource{Type: "url", URL: url}
+}
+
+// chatAssistantToAnthropicBlocks converts assistant content and tool_calls
+// into text and tool_use blocks.
+func chatAssistantToAnthropicBlocks(m ChatMessage) ([]AnthropicContentBlock, error) {
+	text, _, err := parseChatContent(m.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []AnthropicContentBlock
+	if text != "" {
+		out = append(out, AnthropicContentBlock{Type: "text", Text: text})
+	}
+	for _, tc := range m.ToolCalls {
+		input := json.RawMessage("{}")
+		if args := strings.TrimSpace(tc.Function.Arguments); args != "" && json.Valid([]byte(args)) {
+			input = json.RawMessage(args)
+		}
+		out = append(out, AnthropicContentBlock{
+			Type:  "tool_use",
+			ID:    tc.ID,
+			Name:  tc.Function.Name,
+			Input: input,
+		})
+	}
+	return out, nil
+}
+
+// convertChatToolsToAnthropic maps function tools to Anthropic tools.
+func convertChatToolsToAnthropic(tools []ChatTool) []AnthropicTool {
+	var out []AnthropicTool
+	for _, t := range tools {
+		if t.Type != "function" || t.Function == nil {
+			continue
+		}
+		schema := t.Function.Parameters
+		if len(schema) == 0 {
+			schema = json.RawMessage(`{"type":"object","properties":{}}`)
+		}
+		out = append(out, AnthropicTool{
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			InputSchema: schema,
+		})
+	}
+	return out
+}
+
+// convertChatToolChoiceToAnthropic maps Chat Completions tool_choice to the
+// Anthropic format.
+//
+//	"auto"                                      → {"type":"auto"}
+//	"none"                                      → {"type":"none"}
+//	"required"                                  → {"type":"any"}
+//	{"type":"function","function":{"name":"X"}} → {"type":"tool","name":"X"}
+//
+// parallel_tool_calls=false sets disable_parallel_tool_use on the result.
+func convertChatToolChoiceToAnthropic(raw json.RawMessage, parallel *bool) (json.RawMessage, error) {
+	choice := map[string]any{"type": "auto"}
+
+	if len(raw) > 0 {
+		var s string
+		if err := json.Unmarshal(raw, &s); err == nil {
+			switch s {
+			case "none":
+				choice["type"] = "none"
+			case "required":
+				choice["type"] = "any"
+			}
+		} else {
+			var tc struct {
+				Type     string `json:"type"`
+				Function struct {
+					Name string `json:"name"`
+				} `json:"function"`
+			}
+			if err := json.Unmarshal(raw, &tc); err != nil {
+				return nil, err
+			}
+			if tc.Type == "function" && tc.Function.Name != "" {
+				choice["type"] = "tool"
+				choice["name"] = tc.Function.Name
+			}
+		}
+	}
+
+	if parallel != nil && !*parallel && choice["type"] != "none" {
+		choice["disable_parallel_tool_use"] = true
+	}
+	return json.Marshal(choice)
+}
+
+// parseChatStop accepts either a single stop string or an array of strings.
+func parseChatStop(raw json.RawMessage) ([]string, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		if s == "" {
+			return nil, nil
+		}
+		return []string{s}, nil
+	}
+	var arr []string
+	if err := json.Unmarshal(raw, &arr); err != nil {
+		return nil, err
+	}
+	return arr, nil
+}
@@ -0,0 +1,341 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// ChatCompletionsToResponses converts an OpenAI Chat Completions request into
+// a Responses API request. System/developer messages become role items,
+// assistant tool_calls become function_call items and tool messages become
+// function_call_output items.
+func ChatCompletionsToResponses(req *ChatCompletionsRequest) (*ResponsesRequest, error) {
+	input, err := convertChatMessagesToResponsesInput(req.Messages)
+	if err != nil {
+		return nil, err
+	}
+
+	inputJSON, err := json.Marshal(input)
+	if err != nil {
+		return nil, err
+	}
+
+	out := &ResponsesRequest{
+		Model:             req.Model,
+		Input:             inputJSON,
+		Temperature:       req.Temperature,
+		TopP:              req.TopP,
+		Stream:            req.Stream,
+		Include:           []string{"reasoning.encrypted_content"},
+		ParallelToolCalls: req.ParallelToolCalls,
+	}
+
+	storeFalse := false
+	out.Store = &storeFalse
+
+	if maxTokens := chatMaxTokens(req); maxTokens > 0 {
+		v := maxTokens
+		if v < minMaxOutputTokens {
+			v = minMaxOutputTokens
+		}
+		out.MaxOutputTokens = &v
+	}
+
+	if len(req.Tools) > 0 {
+		out.Tools = convertChatToolsToResponses(req.Tools)
+	}
+
+	if len(req.ToolChoice) > 0 {
+		tc, err := convertChatToolChoiceToResponses(req.ToolChoice)
+		if err != nil {
+			return nil, fmt.Errorf("convert tool_choice: %w", err)
+		}
+		out.ToolChoice = tc
+	}
+
+	if effort := strings.TrimSpace(req.ReasoningEffort); effort != "" {
+		out.Reasoning = &ResponsesReasoning{Effort: effort, Summary: "auto"}
+	}
+
+	if len(req.ResponseFormat) > 0 {
+		format, err := convertChatResponseFormatToResponses(req.ResponseFormat)
+		if err != nil {
+			return nil, fmt.Errorf("convert response_format: %w", err)
+		}
+		if format != nil {
+			out.Text = &ResponsesTextConfig{Format: format}
+		}
+	}
+
+	return out, nil
+}
+
+// chatMaxTokens returns the effective output token limit of a Chat Completions
+// request, preferring max_completion_tokens over the deprecated max_tokens.
+func chatMaxTokens(req *ChatCompletionsRequest) int {
+	if req.MaxCompletionTokens != nil && *req.MaxCompletionTokens > 0 {
+		return *req.MaxCompletionTokens
+	}
+	if req.MaxTokens != nil && *req.MaxTokens > 0 {
+		return *req.MaxTokens
+	}
+	return 0
+}
+
+// convertChatMessagesToResponsesInput builds the Responses API input items
+// array from a Chat Completions message list.
+func convertChatMessagesToResponsesInput(msgs []ChatMessage) ([]ResponsesInputItem, error) {
+	var out []ResponsesInputItem
+	for _, m := range msgs {
+		switch m.Role {
+		case "system", "developer":
+			text, _, err := parseChatContent(m.Content)
+			if err != nil {
+				return nil, err
+			}
+			if text == "" {
+				continue
+			}
+			content, _ := json.Marshal(text)
+			out = append(out, ResponsesInputItem{Role: m.Role, Content: content})
+
+		case "assistant":
+			items, err := chatAssistantToResponses(m)
+			if err != nil {
+				return nil, err
+			}
+			out = append(out, items...)
+
+		case "tool":
+			text, _, err := parseChatContent(m.Content)
+			if err != nil {
+				return nil, err
+			}
+			if text == "" {
+				// OpenAI Responses API requires "output" field; use placeholder for empty results.
+				text = "(empty)"
+			}
+			out = append(out, ResponsesInputItem{
+				Type:   "function_call_output",
+				CallID: toResponsesCallID(m.ToolCallID),
+				Output: text,
+			})
+
+		default:
+			item, err := chatUserToResponses(m.Content)
+			if err != nil {
+				return nil, err
+			}
+			if item != nil {
+				out = append(out, *item)
+			}
+		}
+	}
+	return out, nil
+}
+
+// chatUserToResponses converts user message content. Plain strings stay
+// strings; multi-part content becomes input_text / input_image parts.
+func chatUserToResponses(raw json.RawMessage) (*ResponsesInputItem, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		content, _ := json.Marshal(s)
+		return &ResponsesInputItem{Role: "user", Content: content}, nil
+	}
+
+	parts, err := parseChatContentParts(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	var out []ResponsesContentPart
+	for _, p := range parts {
+		switch p.Type {
+		case "text":
+			if p.Text != "" {
+				out = append(out, ResponsesContentPart{Type: "input_text", Text: p.Text})
+			}
+		case "image_url":
+			if p.ImageURL != nil && p.ImageURL.URL != "" {
+				out = append(out, ResponsesContentPart{Type: "input_image", ImageURL: p.ImageURL.URL})
+			}
+		}
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	content, err := json.Marshal(out)
+	if err != nil {
+		return nil, err
+	}
+	return &ResponsesInputItem{Role: "user", Content: content}, nil
+}
+
+// chatAssistantToResponses converts an assistant message into an optional
+// output_text message followed by one function_call item per tool call.
+func chatAssistantToResponses(m ChatMessage) ([]ResponsesInputItem, error) {
+	text, _, err := parseChatContent(m.Content)
+	if err != nil {
+		return nil, err
+	}
+
+	var items []ResponsesInputItem
+	if text != "" {
+		parts := []ResponsesContentPart{{Type: "output_text", Text: text}}
+		partsJSON, err := json.Marshal(parts)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, ResponsesInputItem{Role: "assistant", Content: partsJSON})
+	}
+
+	for _, tc := range m.ToolCalls {
+		args := tc.Function.Arguments
+		if args == "" {
+			args = "{}"
+		}
+		fcID := toResponsesCallID(tc.ID)
+		items = append(items, ResponsesInputItem{
+			Type:      "function_call",
+			CallID:    fcID,
+			Name:      tc.Function.Name,
+			Arguments: args,
+			ID:        fcID,
+		})
+	}
+	return items, nil
+}
+
+// convertChatToolsToResponses maps Chat Completions function tools to
+// Responses API function tools. Non-function tools are dropped.
+func convertChatToolsToResponses(tools []ChatTool) []ResponsesTool {
+	var out []ResponsesTool
+	for _, t := range tools {
+		if t.Type != "function" || t.Function == nil {
+			continue
+		}
+		out = append(out, ResponsesTool{
+			Type:        "function",
+			Name:        t.Function.Name,
+			Description: t.Function.Description,
+			Parameters:  t.Function.Parameters,
+			Strict:      t.Function.Strict,
+		})
+	}
+	return out
+}
+
+// convertChatToolChoiceToResponses maps Chat Completions tool_choice to the
+// Responses format.
+//
+//	"auto" | "none" | "required"                 → unchanged
+//	{"type":"function","function":{"name":"X"}}  → {"type":"function","name":"X"}
+func convertChatToolChoiceToResponses(raw json.RawMessage) (json.RawMessage, error) {
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return raw, nil
+	}
+
+	var tc struct {
+		Type     string `json:"type"`
+		Function struct {
+			Name string `json:"name"`
+		} `json:"function"`
+	}
+	if err := json.Unmarshal(raw, &tc); err != nil {
+		return nil, err
+	}
+	if tc.Type == "function" && tc.Function.Name != "" {
+		return json.Marshal(map[string]string{
+			"type": "function",
+			"name": tc.Function.Name,
+		})
+	}
+	// Pass through unknown shapes as-is
+	return raw, nil
+}
+
+// convertChatResponseFormatToResponses maps response_format to text.format.
+// Chat nests the JSON schema under "json_schema" while Responses flattens it.
+// A nil result means the default text format and nothing needs to be sent.
+func convertChatResponseFormatToResponses(raw json.RawMessage) (json.RawMessage, error) {
+	var rf struct {
+		Type       string `json:"type"`
+		JSONSchema *struct {
+			Name        string          `json:"name"`
+			Description string          `json:"description,omitempty"`
+			Schema      json.RawMessage `json:"schema,omitempty"`
+			Strict      *bool           `json:"strict,omitempty"`
+		} `json:"json_schema,omitempty"`
+	}
+	if err := json.Unmarshal(raw, &rf); err != nil {
+		return nil, err
+	}
+
+	switch rf.Type {
+	case "", "text":
+		return nil, nil
+	case "json_object":
+		return json.Marshal(map[string]string{"type": "json_object"})
+	case "json_schema":
+		if rf.JSONSchema == nil {
+			return nil, fmt.Errorf("json_schema is required when type is json_schema")
+		}
+		format := map[string]any{
+			"type": "json_schema",
+			"name": rf.JSONSchema.Name,
+		}
+		if rf.JSONSchema.Description != "" {
+			format["description"] = rf.JSONSchema.Description
+		}
+		if len(rf.JSONSchema.Schema) > 0 {
+			format["schema"] = rf.JSONSchema.Schema
+		}
+		if rf.JSONSchema.Strict != nil {
+			format["strict"] = *rf.JSONSchema.Strict
+		}
+		return json.Marshal(format)
+	default:
+		return raw, nil
+	}
+}
+
+// parseChatContent flattens message content into its text (parts joined with
+// blank lines) and any image parts. A missing or null content yields "".
+func parseChatContent(raw json.RawMessage) (string, []ChatContentPart, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil, nil
+	}
+	var s string
+	if err := json.Unmarshal(raw, &s); err == nil {
+		return s, nil, nil
+	}
+	parts, err := parseChatContentParts(raw)
+	if err != nil {
+		return "", nil, err
+	}
+	var texts []string
+	var images []ChatContentPart
+	for _, p := range parts {
+		switch p.Type {
+		case "text":
+			if p.Text != "" {
+				texts = append(texts, p.Text)
+			}
+		case "image_url":
+			if p.ImageURL != nil && p.ImageURL.URL != "" {
+				images = append(images, p)
+			}
+		}
+	}
+	return strings.Join(texts, "\n\n"), images, nil
+}
+
+func parseChatContentParts(raw json.RawMessage) ([]ChatContentPart, error) {
+	var parts []ChatContentPart
+	if err := json.Unmarshal(raw, &parts); err != nil {
+		return nil, fmt.Errorf("parse message content: %w", err)
+	}
+	return parts, nil
+}
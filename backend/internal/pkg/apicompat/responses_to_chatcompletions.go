@@ -0,0 +1,347 @@
+package apicompat
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ---------------------------------------------------------------------------
+// Non-streaming: ResponsesResponse → ChatCompletionsResponse
+// ---------------------------------------------------------------------------
+
+// ResponsesToChatCompletions converts a Responses API response into a Chat
+// Completions response. Output text is concatenated into the message content,
+// reasoning summaries into reasoning_content and function_call items become
+// tool_calls.
+func ResponsesToChatCompletions(resp *ResponsesResponse, model string) *ChatCompletionsResponse {
+	msg := &ChatResponseMessage{Role: "assistant"}
+
+	var text strings.Builder
+	var reasoning strings.Builder
+	for _, item := range resp.Output {
+		switch item.Type {
+		case "reasoning":
+			for _, s := range item.Summary {
+				if s.Type == "summary_text" {
+					reasoning.WriteString(s.Text)
+				}
+			}
+		case "message":
+			for _, part := range item.Content {
+				if part.Type == "output_text" {
+					text.WriteString(part.Text)
+				}
+			}
+		case "function_call":
+			args := item.Arguments
+			if args == "" {
+				args = "{}"
+			}
+			msg.ToolCalls = append(msg.ToolCalls, ChatToolCall{
+				ID:   fromResponsesCallID(item.CallID),
+				Type: "function",
+				Function: ChatFunctionCall{
+					Name:      item.Name,
+					Arguments: args,
+				},
+			})
+		}
+	}
+
+	if text.Len() > 0 || len(msg.ToolCalls) == 0 {
+		content := text.String()
+		msg.Content = &content
+	}
+	msg.ReasoningContent = reasoning.String()
+
+	finishReason := responsesStatusToChatFinishReason(resp.Status, resp.IncompleteDetails, len(msg.ToolCalls) > 0)
+
+	return &ChatCompletionsResponse{
+		ID:      toChatCompletionID(resp.ID),
+		Object:  "chat.completion",
+		Created: time.Now().Unix(),
+		Model:   model,
+		Choices: []ChatChoice{{
+			Index:        0,
+			Message:      msg,
+			FinishReason: &finishReason,
+		}},
+		Usage: responsesUsageToChat(resp.Usage),
+	}
+}
+
+func responsesStatusToChatFinishReason(status string, details *ResponsesIncompleteDetails, hasToolCalls bool) string {
+	if status == "incomplete" && details != nil {
+		switch details.Reason {
+		case "max_output_tokens":
+			return "length"
+		case "content_filter":
+			return "content_filter"
+		}
+	}
+	if hasToolCalls {
+		return "tool_calls"
+	}
+	return "stop"
+}
+
+func responsesUsageToChat(u *ResponsesUsage) *ChatUsage {
+	if u == nil {
+		return nil
+	}
+	out := &ChatUsage{
+		PromptTokens:     u.InputTokens,
+		CompletionTokens: u.OutputTokens,
+		TotalTokens:      u.TotalTokens,
+	}
+	if out.TotalTokens == 0 {
+		out.TotalTokens = u.InputTokens + u.OutputTokens
+	}
+	if u.InputTokensDetails != nil && u.InputTokensDetails.CachedTokens > 0 {
+		out.PromptTokensDetails = &ChatPromptTokensDetails{CachedTokens: u.InputTokensDetails.CachedTokens}
+	}
+	if u.OutputTokensDetails != nil && u.OutputTokensDetails.ReasoningTokens > 0 {
+		out.CompletionTokensDetails = &ChatCompletionTokensDetails{ReasoningTokens: u.OutputTokensDetails.ReasoningTokens}
+	}
+	return out
+}
+
+// toChatCompletionID derives a "chatcmpl-" prefixed ID from an upstream
+// response or message ID.
+func toChatCompletionID(id string) string {
+	if strings.HasPrefix(id, "chatcmpl-") {
+		return id
+	}
+	id = strings.TrimPrefix(id, "resp_")
+	id = strings.TrimPrefix(id, "msg_")
+	if id == "" {
+		id = fmt.Sprintf("%d", time.Now().UnixNano())
+	}
+	return "chatcmpl-" + id
+}
+
+// ---------------------------------------------------------------------------
+// Streaming: ResponsesStreamEvent → []ChatCompletionsChunk (stateful converter)
+// ---------------------------------------------------------------------------
+
+// ResponsesEventToChatState tracks state for converting a sequence of
+// Responses SSE events into Chat Completions chunks.
+type ResponsesEventToChatState struct {
+	RoleSent bool
+	Finished bool
+
+	// IncludeUsage mirrors stream_options.include_usage: when set, a final
+	// chunk with empty choices and the usage totals is emitted.
+	IncludeUsage bool
+
+	// OutputIndexToToolIdx maps Responses output_index → Chat tool_calls index.
+	OutputIndexToToolIdx map[int]int
+	NextToolIdx          int
+
+	Usage *ChatUsage
+
+	ID      string
+	Model   string
+	Created int64
+}
+
+// NewResponsesEventToChatState returns an initialised stream state.
+func NewResponsesEventToChatState() *ResponsesEventToChatState {
+	return &ResponsesEventToChatState{
+		OutputIndexToToolIdx: make(map[int]int),
+		Created:              time.Now().Unix(),
+	}
+}
+
+// ResponsesEventToChatChunks converts a single Responses SSE event into zero
+// or more Chat Completions chunks, updating state as it goes.
+func ResponsesEventToChatChunks(
+	evt *ResponsesStreamEvent,
+	state *ResponsesEventToChatState,
+) []ChatCompletionsChunk {
+	switch evt.Type {
+	case "response.created":
+		return resToChatHandleCreated(evt, state)
+	case "response.output_text.delta":
+		if evt.Delta == "" {
+			return nil
+		}
+		delta := evt.Delta
+		return resToChatWithRole(state, &ChatResponseMessage{Content: &delta})
+	case "response.reasoning_summary_text.delta":
+		if evt.Delta == "" {
+			return nil
+		}
+		return resToChatWithRole(state, &ChatResponseMessage{ReasoningContent: evt.Delta})
+	case "response.output_item.added":
+		return resToChatHandleOutputItemAdded(evt, state)
+	case "response.function_call_arguments.delta":
+		return resToChatHandleFuncArgsDelta(evt, state)
+	case "response.completed", "response.incomplete", "response.failed":
+		return resToChatHandleCompleted(evt, state)
+	default:
+		return nil
+	}
+}
+
+// FinalizeResponsesChatStream emits the terminating chunks if the stream
+// ended without a proper completion event.
+func FinalizeResponsesChatStream(state *ResponsesEventToChatState) []ChatCompletionsChunk {
+	if !state.RoleSent || state.Finished {
+		return nil
+	}
+	finishReason := "stop"
+	if state.NextToolIdx > 0 {
+		finishReason = "tool_calls"
+	}
+	return chatFinishChunks(state.chunkMeta(), &state.Finished, finishReason, state.IncludeUsage, state.Usage)
+}
+
+// ChatChunkToSSE formats a ChatCompletionsChunk as an SSE data line.
+func ChatChunkToSSE(chunk ChatCompletionsChunk) (string, error) {
+	data, err := json.Marshal(chunk)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("data: %s\n\n", data), nil
+}
+
+// ChatStreamDone is the sentinel line that terminates a Chat Completions stream.
+const ChatStreamDone = "data: [DONE]\n\n"
+
+// --- internal handlers ---
+
+func (state *ResponsesEventToChatState) chunkMeta() ChatCompletionsChunk {
+	return ChatCompletionsChunk{
+		ID:      toChatCompletionID(state.ID),
+		Object:  "chat.completion.chunk",
+		Created: state.Created,
+		Model:   state.Model,
+	}
+}
+
+func resToChatHandleCreated(evt *ResponsesStreamEvent, state *ResponsesEventToChatState) []ChatCompletionsChunk {
+	if evt.Response != nil {
+		if state.ID == "" {
+			state.ID = evt.Response.ID
+		}
+		// Only use upstream model if no override was set (e.g. originalModel)
+		if state.Model == "" {
+			state.Model = evt.Response.Model
+		}
+	}
+	return resToChatWithRole(state, nil)
+}
+
+// resToChatWithRole prepends the initial role chunk when it has not been
+// sent yet and then emits delta (if any).
+func resToChatWithRole(state *ResponsesEventToChatState, delta *ChatResponseMessage) []ChatCompletionsChunk {
+	var chunks []ChatCompletionsChunk
+	if !state.RoleSent {
+		state.RoleSent = true
+		empty := ""
+		chunks = append(chunks, chatDeltaChunk(state.chunkMeta(), &ChatResponseMessage{Role: "assistant", Content: &empty}))
+	}
+	if delta != nil {
+		chunks = append(chunks, chatDeltaChunk(state.chunkMeta(), delta))
+	}
+	return chunks
+}
+
+func resToChatHandleOutputItemAdded(evt *ResponsesStreamEvent, state *ResponsesEventToChatState) []ChatCompletionsChunk {
+	if evt.Item == nil || evt.Item.Type != "function_call" {
+		return nil
+	}
+	idx := state.NextToolIdx
+	state.NextToolIdx++
+	state.OutputIndexToToolIdx[evt.OutputIndex] = idx
+
+	return resToChatWithRole(state, &ChatResponseMessage{
+		ToolCalls: []ChatToolCall{{
+			Index: &idx,
+			ID:    fromResponsesCallID(evt.Item.CallID),
+			Type:  "function",
+			Function: ChatFunctionCall{
+				Name:      evt.Item.Name,
+				Arguments: "",
+			},
+		}},
+	})
+}
+
+func resToChatHandleFuncArgsDelta(evt *ResponsesStreamEvent, state *ResponsesEventToChatState) []ChatCompletionsChunk {
+	if evt.Delta == "" {
+		return nil
+	}
+	idx, ok := state.OutputIndexToToolIdx[evt.OutputIndex]
+	if !ok {
+		return nil
+	}
+	return resToChatWithRole(state, &ChatResponseMessage{
+		ToolCalls: []ChatToolCall{{
+			Index:    &idx,
+			Function: ChatFunctionCall{Arguments: evt.Delta},
+		}},
+	})
+}
+
+func resToChatHandleCompleted(evt *ResponsesStreamEvent, state *ResponsesEventToChatState) []ChatCompletionsChunk {
+	if state.Finished {
+		return nil
+	}
+
+	var chunks []ChatCompletionsChunk
+	if !state.RoleSent {
+		chunks = append(chunks, resToChatWithRole(state, nil)...)
+	}
+
+	finishReason := "stop"
+	if state.NextToolIdx > 0 {
+		finishReason = "tool_calls"
+	}
+	if evt.Response != nil {
+		if u := responsesUsageToChat(evt.Response.Usage); u != nil {
+			state.Usage = u
+		}
+		finishReason = responsesStatusToChatFinishReason(evt.Response.Status, evt.Response.IncompleteDetails, state.NextToolIdx > 0)
+	}
+
+	return append(chunks, chatFinishChunks(state.chunkMeta(), &state.Finished, finishReason, state.IncludeUsage, state.Usage)...)
+}
+
+// ---------------------------------------------------------------------------
+// Shared chunk builders
+// ---------------------------------------------------------------------------
+
+func chatDeltaChunk(meta ChatCompletionsChunk, delta *ChatResponseMessage) ChatCompletionsChunk {
+	meta.Choices = []ChatChoice{{Index: 0, Delta: delta}}
+	return meta
+}
+
+// chatFinishChunks emits the chunk carrying finish_reason and, when
+// includeUsage is set, the trailing usage-only chunk with empty choices.
+func chatFinishChunks(meta ChatCompletionsChunk, finished *bool, finishReason string, includeUsage bool, usage *ChatUsage) []ChatCompletionsChunk {
+	*finished = true
+
+	final := meta
+	final.Choices = []ChatChoice{{
+		Index:        0,
+		Delta:        &ChatResponseMessage{},
+		FinishReason: &finishReason,
+	}}
+	chunks := []ChatCompletionsChunk{final}
+
+	if includeUsage {
+		usageChunk := meta
+		usageChunk.Choices = []ChatChoice{}
+		if usage != nil {
+			usageChunk.Usage = usage
+		} else {
+			usageChunk.Usage = &ChatUsage{}
+		}
+		chunks = append(chunks, usageChunk)
+	}
+	return chunks
+}
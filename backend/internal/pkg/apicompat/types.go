@@ -1,5 +1,6 @@
 // Package apicompat provides type definitions and conversion utilities for
-// translating between Anthropic Messages and OpenAI Responses API formats.
+// translating between Anthropic Messages, OpenAI Responses and OpenAI Chat
+// Completions API formats.
 // It enables multi-protocol support so that clients using different API
 // formats can be served through a unified gateway.
 package apicompat
@@ -56,6 +57,17 @@ type AnthropicContentBlock struct {
 	ToolUseID string          `json:"tool_use_id,omitempty"`
 	Content   json.RawMessage `json:"content,omitempty"` // string or []AnthropicContentBlock
 	IsError   bool            `json:"is_error,omitempty"`
+
+	// type=image
+	Source *AnthropicImageSource `json:"source,omitempty"`
+}
+
+// AnthropicImageSource describes the payload of an image content block.
+type AnthropicImageSource struct {
+	Type      string `json:"type"`                 // "base64" | "url"
+	MediaType string `json:"media_type,omitempty"` // e.g. "image/png" (base64 only)
+	Data      string `json:"data,omitempty"`       // base64 payload
+	URL       string `json:"url,omitempty"`        // remote image URL
 }
 
 // AnthropicTool describes a tool available to the model.
@@ -146,6 +158,14 @@ type ResponsesRequest struct {
 	Store           *bool               `json:"store,omitempty"`
 	Reasoning       *ResponsesReasoning `json:"reasoning,omitempty"`
 	ToolChoice      json.RawMessage     `json:"tool_choice,omitempty"`
+
+	ParallelToolCalls *bool                `json:"parallel_tool_calls,omitempty"`
+	Text              *ResponsesTextConfig `json:"text,omitempty"`
+}
+
+// ResponsesTextConfig configures the text output format in the Responses API.
+type ResponsesTextConfig struct {
+	Format json.RawMessage `json:"format,omitempty"` // {"type":"text"|"json_object"|"json_schema",...}
 }
 
 // ResponsesReasoning configures reasoning effort in the Responses API.
@@ -176,8 +196,9 @@ type ResponsesInputItem struct {
 
 // ResponsesContentPart is a typed content part in a Responses message.
 type ResponsesContentPart struct {
-	Type string `json:"type"` // "input_text" | "output_text" | "input_image"
-	Text string `json:"text,omitempty"`
+	Type     string `json:"type"` // "input_text" | "output_text" | "input_image"
+	Text     string `json:"text,omitempty"`
+	ImageURL string `json:"image_url,omitempty"` // type=input_image (URL or data URI)
 }
 
 // ResponsesTool describes a tool in the Responses API.
@@ -311,6 +332,144 @@ type ResponsesStreamEvent struct {
 	SequenceNumber int `json:"sequence_number,omitempty"`
 }
 
+// ---------------------------------------------------------------------------
+// OpenAI Chat Completions API types
+// ---------------------------------------------------------------------------
+
+// ChatCompletionsRequest is the request body for POST /v1/chat/completions.
+type ChatCompletionsRequest struct {
+	Model               string             `json:"model"`
+	Messages            []ChatMessage      `json:"messages"`
+	MaxTokens           *int               `json:"max_tokens,omitempty"`
+	MaxCompletionTokens *int               `json:"max_completion_tokens,omitempty"`
+	Temperature         *float64           `json:"temperature,omitempty"`
+	TopP                *float64           `json:"top_p,omitempty"`
+	Stream              bool               `json:"stream,omitempty"`
+	StreamOptions       *ChatStreamOptions `json:"stream_options,omitempty"`
+	Stop                json.RawMessage    `json:"stop,omitempty"` // string or []string
+	Tools               []ChatTool         `json:"tools,omitempty"`
+	ToolChoice          json.RawMessage    `json:"tool_choice,omitempty"` // string or {"type":"function",...}
+	ParallelToolCalls   *bool              `json:"parallel_tool_calls,omitempty"`
+	ReasoningEffort     string             `json:"reasoning_effort,omitempty"` // "minimal" | "low" | "medium" | "high"
+	ResponseFormat      json.RawMessage    `json:"response_format,omitempty"`
+	User                string             `json:"user,omitempty"`
+}
+
+// ChatStreamOptions configures streaming behaviour for Chat Completions.
+type ChatStreamOptions struct {
+	IncludeUsage bool `json:"include_usage,omitempty"`
+}
+
+// ChatMessage is a single message in a Chat Completions conversation.
+type ChatMessage struct {
+	Role       string          `json:"role"`              // "system" | "developer" | "user" | "assistant" | "tool"
+	Content    json.RawMessage `json:"content,omitempty"` // string, []ChatContentPart or null
+	Name       string          `json:"name,omitempty"`
+	ToolCalls  []ChatToolCall  `json:"tool_calls,omitempty"`   // role=assistant
+	ToolCallID string          `json:"tool_call_id,omitempty"` // role=tool
+}
+
+// ChatContentPart is one typed part of a multi-part message content.
+type ChatContentPart struct {
+	Type     string        `json:"type"` // "text" | "image_url"
+	Text     string        `json:"text,omitempty"`
+	ImageURL *ChatImageURL `json:"image_url,omitempty"`
+}
+
+// ChatImageURL references an image by URL or data URI.
+type ChatImageURL struct {
+	URL    string `json:"url"`
+	Detail string `json:"detail,omitempty"`
+}
+
+// ChatTool describes a tool available to the model.
+type ChatTool struct {
+	Type     string        `json:"type"` // "function"
+	Function *ChatFunction `json:"function,omitempty"`
+}
+
+// ChatFunction is the definition of a function tool.
+type ChatFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters,omitempty"` // JSON Schema object
+	Strict      *bool           `json:"strict,omitempty"`
+}
+
+// ChatToolCall is a tool invocation emitted by the assistant. In streaming
+// chunks Index identifies which call a partial delta belongs to.
+type ChatToolCall struct {
+	Index    *int             `json:"index,omitempty"`
+	ID       string           `json:"id,omitempty"`
+	Type     string           `json:"type,omitempty"` // "function"
+	Function ChatFunctionCall `json:"function"`
+}
+
+// ChatFunctionCall carries the function name and JSON-encoded arguments.
+type ChatFunctionCall struct {
+	Name      string `json:"name,omitempty"`
+	Arguments string `json:"arguments"`
+}
+
+// ChatCompletionsResponse is the non-streaming response from
+// POST /v1/chat/completions.
+type ChatCompletionsResponse struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"` // "chat.completion"
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []ChatChoice `json:"choices"`
+	Usage   *ChatUsage   `json:"usage,omitempty"`
+}
+
+// ChatCompletionsChunk is a single SSE chunk in the Chat Completions
+// streaming protocol.
+type ChatCompletionsChunk struct {
+	ID      string       `json:"id"`
+	Object  string       `json:"object"` // "chat.completion.chunk"
+	Created int64        `json:"created"`
+	Model   string       `json:"model"`
+	Choices []ChatChoice `json:"choices"`
+	Usage   *ChatUsage   `json:"usage,omitempty"`
+}
+
+// ChatChoice is one completion choice. Message is set for non-streaming
+// responses, Delta for streaming chunks.
+type ChatChoice struct {
+	Index        int                  `json:"index"`
+	Message      *ChatResponseMessage `json:"message,omitempty"`
+	Delta        *ChatResponseMessage `json:"delta,omitempty"`
+	FinishReason *string              `json:"finish_reason"` // "stop" | "length" | "tool_calls" | "content_filter" | null
+}
+
+// ChatResponseMessage is the assistant message (or streaming delta) of a choice.
+type ChatResponseMessage struct {
+	Role             string         `json:"role,omitempty"`
+	Content          *string        `json:"content"`
+	ReasoningContent string         `json:"reasoning_content,omitempty"`
+	ToolCalls        []ChatToolCall `json:"tool_calls,omitempty"`
+}
+
+// ChatUsage holds token counts in Chat Completions format.
+type ChatUsage struct {
+	PromptTokens     int `json:"prompt_tokens"`
+	CompletionTokens int `json:"completion_tokens"`
+	TotalTokens      int `json:"total_tokens"`
+
+	PromptTokensDetails     *ChatPromptTokensDetails     `json:"prompt_tokens_details,omitempty"`
+	CompletionTokensDetails *ChatCompletionTokensDetails `json:"completion_tokens_details,omitempty"`
+}
+
+// ChatPromptTokensDetails breaks down prompt token usage.
+type ChatPromptTokensDetails struct {
+	CachedTokens int `json:"cached_tokens"`
+}
+
+// ChatCompletionTokensDetails breaks down completion token usage.
+type ChatCompletionTokensDetails struct {
+	ReasoningTokens int `json:"reasoning_tokens"`
+}
+
 // ---------------------------------------------------------------------------
 // Shared constants
 // ---------------------------------------------------------------------------
@@ -318,3 +477,7 @@ type ResponsesStreamEvent struct {
 // minMaxOutputTokens is the floor for max_output_tokens in a Responses request.
 // Very small values may cause upstream API errors, so we enforce a minimum.
 const minMaxOutputTokens = 128
+
+// defaultAnthropicMaxTokens is used when a Chat Completions request omits
+// max_tokens, because the Anthropic Messages API requires the field.
+const defaultAnthropicMaxTokens = 8192
@@ -55,4 +55,8 @@ const (
 
 	// ClaudeCodeVersion stores the extracted Claude Code version from User-Agent (e.g. "2.1.22")
 	ClaudeCodeVersion Key = "ctx_claude_code_version"
+
+	// SoraTaskCreatedHook 上游 Sora 任务创建后的回调（值类型 func(taskID string)），
+	// 异步生成任务据此持久化 upstream_task_id，重启后可直接恢复轮询。
+	SoraTaskCreatedHook Key = "ctx_sora_task_created_hook"
 )
@@ -0,0 +1,155 @@
+// Package tracing wires OpenTelemetry (OTLP) tracing for the request pipeline.
+//
+// When tracing is disabled the global no-op provider stays in place, so Start
+// returns non-recording spans that still carry an incoming W3C traceparent.
+package tracing
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strings"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracegrpc"
+	"go.opentelemetry.io/otel/exporters/otlp/otlptrace/otlptracehttp"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const instrumentationName = "github.com/Wei-Shaw/sub2api"
+
+const (
+	ProtocolGRPC = "grpc"
+	ProtocolHTTP = "http"
+)
+
+type Options struct {
+	Enabled        bool
+	Protocol       string
+	Endpoint       string
+	URLPath        string
+	Insecure       bool
+	Headers        map[string]string
+	ServiceName    string
+	ServiceVersion string
+	SampleRatio    float64
+}
+
+// Init installs the W3C trace context propagator and, when enabled, an OTLP
+// tracer provider. The returned shutdown flushes pending spans.
+func Init(ctx context.Context, opts Options) (func(context.Context) error, error) {
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	noop := func(context.Context) error { return nil }
+	if !opts.Enabled {
+		return noop, nil
+	}
+
+	exporter, err := newExporter(ctx, opts)
+	if err != nil {
+		return noop, err
+	}
+
+	serviceName := strings.TrimSpace(opts.ServiceName)
+	if serviceName == "" {
+		serviceName = "sub2api"
+	}
+	attrs := []attribute.KeyValue{attribute.String("service.name", serviceName)}
+	if v := strings.TrimSpace(opts.ServiceVersion); v != "" {
+		attrs = append(attrs, attribute.String("service.version", v))
+	}
+
+	provider := sdktrace.NewTracerProvider(
+		sdktrace.WithBatcher(exporter),
+		sdktrace.WithResource(resource.NewSchemaless(attrs...)),
+		sdktrace.WithSampler(sdktrace.ParentBased(sdktrace.TraceIDRatioBased(opts.SampleRatio))),
+	)
+	otel.SetTracerProvider(provider)
+	return provider.Shutdown, nil
+}
+
+func newExporter(ctx context.Context, opts Options) (sdktrace.SpanExporter, error) {
+	switch strings.ToLower(strings.TrimSpace(opts.Protocol)) {
+	case ProtocolGRPC, "":
+		grpcOpts := []otlptracegrpc.Option{otlptracegrpc.WithEndpoint(opts.Endpoint)}
+		if opts.Insecure {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithInsecure())
+		}
+		if len(opts.Headers) > 0 {
+			grpcOpts = append(grpcOpts, otlptracegrpc.WithHeaders(opts.Headers))
+		}
+		return otlptracegrpc.New(ctx, grpcOpts...)
+	case ProtocolHTTP:
+		httpOpts := []otlptracehttp.Option{otlptracehttp.WithEndpoint(opts.Endpoint)}
+		if opts.Insecure {
+			httpOpts = append(httpOpts, otlptracehttp.WithInsecure())
+		}
+		if opts.URLPath != "" {
+			httpOpts = append(httpOpts, otlptracehttp.WithURLPath(opts.URLPath))
+		}
+		if len(opts.Headers) > 0 {
+			httpOpts = append(httpOpts, otlptracehttp.WithHeaders(opts.Headers))
+		}
+		return otlptracehttp.New(ctx, httpOpts...)
+	default:
+		return nil, fmt.Errorf("unsupported tracing protocol: %s", opts.Protocol)
+	}
+}
+
+// Tracer returns the application tracer from the global provider.
+func Tracer() trace.Tracer {
+	return otel.Tracer(instrumentationName)
+}
+
+// Start starts an internal span as a child of the span in ctx.
+func Start(ctx context.Context, name string, attrs ...attribute.KeyValue) (context.Context, trace.Span) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return Tracer().Start(ctx, name, trace.WithAttributes(attrs...))
+}
+
+// End records err (if any) on span and ends it.
+func End(span trace.Span, err error) {
+	if span == nil {
+		return
+	}
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
+}
+
+// Extract returns ctx carrying the remote span context from a W3C traceparent header, if any.
+func Extract(ctx context.Context, header http.Header) context.Context {
+	return otel.GetTextMapPropagator().Extract(ctx, propagation.HeaderCarrier(header))
+}
+
+// TraceIDFromContext returns the hex trace id of the span in ctx, or "" if there is none.
+func TraceIDFromContext(ctx context.Context) string {
+	if ctx == nil {
+		return ""
+	}
+	sc := trace.SpanContextFromContext(ctx)
+	if !sc.HasTraceID() {
+		return ""
+	}
+	return sc.TraceID().String()
+}
+
+// WithParentSpan re-parents an async task onto the span in parent without
+// inheriting its cancellation (the task runs after the request has finished).
+func WithParentSpan(parent context.Context, task func(ctx context.Context)) func(ctx context.Context) {
+	sc := trace.SpanContextFromContext(parent)
+	if task == nil || !sc.IsValid() {
+		return task
+	}
+	return func(ctx context.Context) {
+		task(trace.ContextWithSpanContext(ctx, sc))
+	}
+}
@@ -0,0 +1,82 @@
+package tracing
+
+import (
+	"context"
+	"net/http"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const testTraceparent = "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"
+
+func TestInit_DisabledKeepsTraceparent(t *testing.T) {
+	shutdown, err := Init(context.Background(), Options{Enabled: false})
+	require.NoError(t, err)
+	require.NoError(t, shutdown(context.Background()))
+
+	header := http.Header{}
+	header.Set("traceparent", testTraceparent)
+	ctx := Extract(context.Background(), header)
+	ctx, span := Start(ctx, "noop")
+	defer span.End()
+
+	require.False(t, span.IsRecording())
+	require.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", TraceIDFromContext(ctx))
+}
+
+func TestInit_UnsupportedProtocol(t *testing.T) {
+	_, err := Init(context.Background(), Options{Enabled: true, Protocol: "zipkin", Endpoint: "localhost:1"})
+	require.Error(t, err)
+}
+
+func TestTraceIDFromContext_Empty(t *testing.T) {
+	require.Empty(t, TraceIDFromContext(context.Background()))
+	require.Empty(t, TraceIDFromContext(nil)) //nolint:staticcheck // nil ctx is handled explicitly
+}
+
+func TestStartEnd_RecordsChildAndError(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	prev := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	t.Cleanup(func() { otel.SetTracerProvider(prev) })
+
+	ctx, parent := Start(context.Background(), "parent")
+	_, child := Start(ctx, "child")
+	End(child, context.DeadlineExceeded)
+	End(parent, nil)
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 2)
+	require.Equal(t, "child", spans[0].Name())
+	require.Equal(t, parent.SpanContext().SpanID(), spans[0].Parent().SpanID())
+	require.Equal(t, "Error", spans[0].Status().Code.String())
+	require.Len(t, spans[0].Events(), 1)
+}
+
+func TestWithParentSpan(t *testing.T) {
+	require.Nil(t, WithParentSpan(context.Background(), nil))
+
+	sc := trace.NewSpanContext(trace.SpanContextConfig{
+		TraceID:    trace.TraceID{1},
+		SpanID:     trace.SpanID{2},
+		TraceFlags: trace.FlagsSampled,
+	})
+	parent, cancel := context.WithCancel(trace.ContextWithSpanContext(context.Background(), sc))
+	cancel()
+
+	var gotTraceID string
+	var gotErr error
+	WithParentSpan(parent, func(ctx context.Context) {
+		gotTraceID = TraceIDFromContext(ctx)
+		gotErr = ctx.Err()
+	})(context.Background())
+
+	require.Equal(t, sc.TraceID().String(), gotTraceID)
+	require.NoError(t, gotErr, "task must not inherit the request cancellation")
+}
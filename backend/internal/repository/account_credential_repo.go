@@ -0,0 +1,84 @@
+package repository
+
+import (
+	"bytes"
+	"context"
+	"database/sql"
+	"encoding/json"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// accountCredentialRepository 读写未解密的账号凭证，仅供密钥轮换与重加密使用
+type accountCredentialRepository struct {
+	sql sqlExecutor
+}
+
+// NewAccountCredentialRepository 创建账号原始凭证仓储
+func NewAccountCredentialRepository(sqlDB *sql.DB) service.AccountCredentialRepository {
+	return &accountCredentialRepository{sql: sqlDB}
+}
+
+func (r *accountCredentialRepository) ListRawCredentials(ctx context.Context, afterID int64, limit int) ([]service.AccountCredentialRecord, error) {
+	rows, err := r.sql.QueryContext(ctx, `
+		SELECT id, credentials
+		FROM accounts
+		WHERE deleted_at IS NULL AND id > $1
+		ORDER BY id
+		LIMIT $2
+	`, afterID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.AccountCredentialRecord, 0, limit)
+	for rows.Next() {
+		var (
+			id  int64
+			raw []byte
+		)
+		if err := rows.Scan(&id, &raw); err != nil {
+			return nil, err
+		}
+		// UseNumber 保留原始数字精度，保证比较写入时 JSONB 相等判断可靠
+		credentials := map[string]any{}
+		if len(raw) > 0 {
+			dec := json.NewDecoder(bytes.NewReader(raw))
+			dec.UseNumber()
+			if err := dec.Decode(&credentials); err != nil {
+				return nil, err
+			}
+		}
+		out = append(out, service.AccountCredentialRecord{AccountID: id, Credentials: credentials})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// CompareAndSwapCredentials 以 JSONB 相等比较实现乐观写入，避免覆盖并发的令牌刷新
+func (r *accountCredentialRepository) CompareAndSwapCredentials(ctx context.Context, accountID int64, expected, updated map[string]any) (bool, error) {
+	expectedPayload, err := json.Marshal(normalizeJSONMap(expected))
+	if err != nil {
+		return false, err
+	}
+	updatedPayload, err := json.Marshal(normalizeJSONMap(updated))
+	if err != nil {
+		return false, err
+	}
+	result, err := r.sql.ExecContext(ctx, `
+		UPDATE accounts
+		SET credentials = $2::jsonb
+		WHERE id = $1 AND deleted_at IS NULL AND credentials = $3::jsonb
+	`, accountID, updatedPayload, expectedPayload)
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
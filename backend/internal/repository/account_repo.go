@@ -40,6 +40,7 @@ import (
 //   - client: Ent 客户端，用于类型安全的 ORM 操作
 //   - sql: 原生 SQL 执行器，用于复杂查询和批量操作
 //   - schedulerCache: 调度器缓存，用于在账号状态变更时同步快照
+//   - credentialCipher: 凭证加密器，敏感凭证字段在写入前加密、读取后解密
 type accountRepository struct {
 	client *dbent.Client // Ent ORM 客户端
 	sql    sqlExecutor   // 原生 SQL 执行接口
@@ -48,12 +49,16 @@ type accountRepository struct {
 	// Used to proactively sync account snapshot to cache when status changes,
 	// ensuring sticky sessions can promptly detect unavailable accounts.
 	schedulerCache service.SchedulerCache
+	// credentialCipher 透明加解密敏感凭证字段；为 nil 时按明文读写
+	credentialCipher service.CredentialCipher
 }
 
 // NewAccountRepository 创建账户仓储实例。
 // 这是对外暴露的构造函数，返回接口类型以便于依赖注入。
-func NewAccountRepository(client *dbent.Client, sqlDB *sql.DB, schedulerCache service.SchedulerCache) service.AccountRepository {
-	return newAccountRepositoryWithSQL(client, sqlDB, schedulerCache)
+func NewAccountRepository(client *dbent.Client, sqlDB *sql.DB, schedulerCache service.SchedulerCache, credentialCipher service.CredentialCipher) service.AccountRepository {
+	repo := newAccountRepositoryWithSQL(client, sqlDB, schedulerCache)
+	repo.credentialCipher = credentialCipher
+	return repo
 }
 
 // newAccountRepositoryWithSQL 是内部构造函数，支持依赖注入 SQL 执行器。
@@ -67,12 +72,17 @@ func (r *accountRepository) Create(ctx context.Context, account *service.Account
 		return service.ErrAccountNilInput
 	}
 
+	credentials, err := r.encryptCredentials(account.Credentials)
+	if err != nil {
+		return err
+	}
+
 	builder := r.client.Account.Create().
 		SetName(account.Name).
 		SetNillableNotes(account.Notes).
 		SetPlatform(account.Platform).
 		SetType(account.Type).
-		SetCredentials(normalizeJSONMap(account.Credentials)).
+		SetCredentials(credentials).
 		SetExtra(normalizeJSONMap(account.Extra)).
 		SetConcurrency(account.Concurrency).
 		SetPriority(account.Priority).
@@ -198,6 +208,7 @@ func (r *accountRepository) GetByIDs(ctx context.Context, ids []int64) ([]*servi
 		if out == nil {
 			continue
 		}
+		r.decryptCredentials(out)
 
 		// Prefer the preloaded proxy edge when available.
 		if entAcc.Edges.Proxy != nil {
@@ -304,12 +315,17 @@ func (r *accountRepository) Update(ctx context.Context, account *service.Account
 		return nil
 	}
 
+	credentials, err := r.encryptCredentials(account.Credentials)
+	if err != nil {
+		return err
+	}
+
 	builder := r.client.Account.UpdateOneID(account.ID).
 		SetName(account.Name).
 		SetNillableNotes(account.Notes).
 		SetPlatform(account.Platform).
 		SetType(account.Type).
-		SetCredentials(normalizeJSONMap(account.Credentials)).
+		SetCredentials(credentials).
 		SetExtra(normalizeJSONMap(account.Extra)).
 		SetConcurrency(account.Concurrency).
 		SetPriority(account.Priority).
@@ -1252,7 +1268,11 @@ func (r *accountRepository) BulkUpdate(ctx context.Context, ids []int64, updates
 	}
 	// JSONB 需要合并而非覆盖，使用 raw SQL 保持旧行为。
 	if len(updates.Credentials) > 0 {
-		payload, err := json.Marshal(updates.Credentials)
+		credentials, err := r.encryptCredentials(updates.Credentials)
+		if err != nil {
+			return 0, err
+		}
+		payload, err := json.Marshal(credentials)
 		if err != nil {
 			return 0, err
 		}
@@ -1403,6 +1423,7 @@ func (r *accountRepository) accountsToService(ctx context.Context, accounts []*d
 		if out == nil {
 			continue
 		}
+		r.decryptCredentials(out)
 		if acc.ProxyID != nil {
 			if proxy, ok := proxyMap[*acc.ProxyID]; ok {
 				out.Proxy = proxy
@@ -1543,6 +1564,28 @@ func buildSchedulerGroupPayload(groupIDs []int64) map[string]any {
 	return map[string]any{"group_ids": groupIDs}
 }
 
+// encryptCredentials 写入前加密敏感凭证字段
+func (r *accountRepository) encryptCredentials(credentials map[string]any) (map[string]any, error) {
+	credentials = normalizeJSONMap(credentials)
+	if r.credentialCipher == nil {
+		return credentials, nil
+	}
+	return r.credentialCipher.EncryptCredentials(credentials)
+}
+
+// decryptCredentials 读取后解密敏感凭证字段；无法解密的字段保留密文，
+// 避免后续整体写回时丢失数据。
+func (r *accountRepository) decryptCredentials(account *service.Account) {
+	if r.credentialCipher == nil || len(account.Credentials) == 0 {
+		return
+	}
+	decrypted, err := r.credentialCipher.DecryptCredentials(account.Credentials)
+	if err != nil {
+		logger.LegacyPrintf("repository.account", "Decrypt credentials failed: account=%d err=%v", account.ID, err)
+	}
+	account.Credentials = decrypted
+}
+
 func accountEntityToService(m *dbent.Account) *service.Account {
 	if m == nil {
 		return nil
@@ -1559,6 +1602,7 @@ func accountEntityToService(m *dbent.Account) *service.Account {
 		Credentials:             copyJSONMap(m.Credentials),
 		Extra:                   copyJSONMap(m.Extra),
 		ProxyID:                 m.ProxyID,
+		ProxyPoolID:             m.ProxyPoolID,
 		Concurrency:             m.Concurrency,
 		Priority:                m.Priority,
 		RateMultiplier:          &rateMultiplier,
@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// adminAPITokenRepository 实现 service.AdminAPITokenRepository 接口。
+// 使用原生 SQL 操作 admin_api_tokens 表。
+type adminAPITokenRepository struct {
+	sql *sql.DB
+}
+
+// NewAdminAPITokenRepository 创建管理员 API Token 仓储实例。
+func NewAdminAPITokenRepository(sqlDB *sql.DB) service.AdminAPITokenRepository {
+	return &adminAPITokenRepository{sql: sqlDB}
+}
+
+const adminAPITokenColumns = `
+	id, name, token_hash, token_prefix, scopes, ip_whitelist,
+	expires_at, last_used_at, COALESCE(last_used_ip, ''), created_by,
+	created_at, updated_at`
+
+func (r *adminAPITokenRepository) Create(ctx context.Context, token *service.AdminAPIToken) error {
+	scopesJSON, _ := json.Marshal(token.Scopes)
+	ipWhitelistJSON, _ := json.Marshal(token.IPWhitelist)
+
+	return r.sql.QueryRowContext(ctx, `
+		INSERT INTO admin_api_tokens (
+			name, token_hash, token_prefix, scopes, ip_whitelist, expires_at, created_by
+		) VALUES ($1, $2, $3, $4, $5, $6, $7)
+		RETURNING id, created_at, updated_at
+	`,
+		token.Name, token.TokenHash, token.TokenPrefix, scopesJSON, ipWhitelistJSON,
+		token.ExpiresAt, token.CreatedBy,
+	).Scan(&token.ID, &token.CreatedAt, &token.UpdatedAt)
+}
+
+func (r *adminAPITokenRepository) GetByID(ctx context.Context, id int64) (*service.AdminAPIToken, error) {
+	return r.getOne(ctx, `SELECT `+adminAPITokenColumns+` FROM admin_api_tokens WHERE id = $1`, id)
+}
+
+func (r *adminAPITokenRepository) GetByHash(ctx context.Context, tokenHash string) (*service.AdminAPIToken, error) {
+	return r.getOne(ctx, `SELECT `+adminAPITokenColumns+` FROM admin_api_tokens WHERE token_hash = $1`, tokenHash)
+}
+
+func (r *adminAPITokenRepository) getOne(ctx context.Context, query string, arg any) (*service.AdminAPIToken, error) {
+	token, err := scanAdminAPIToken(r.sql.QueryRowContext(ctx, query, arg))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, service.ErrAdminAPITokenNotFound
+		}
+		return nil, err
+	}
+	return token, nil
+}
+
+func (r *adminAPITokenRepository) List(ctx context.Context) ([]service.AdminAPIToken, error) {
+	rows, err := r.sql.QueryContext(ctx, `SELECT `+adminAPITokenColumns+` FROM admin_api_tokens ORDER BY id DESC`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	tokens := make([]service.AdminAPIToken, 0)
+	for rows.Next() {
+		token, err := scanAdminAPIToken(rows)
+		if err != nil {
+			return nil, err
+		}
+		tokens = append(tokens, *token)
+	}
+	return tokens, rows.Err()
+}
+
+func (r *adminAPITokenRepository) Update(ctx context.Context, token *service.AdminAPIToken) error {
+	scopesJSON, _ := json.Marshal(token.Scopes)
+	ipWhitelistJSON, _ := json.Marshal(token.IPWhitelist)
+
+	err := r.sql.QueryRowContext(ctx, `
+		UPDATE admin_api_tokens SET
+			name = $2, scopes = $3, ip_whitelist = $4, expires_at = $5, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, token.ID, token.Name, scopesJSON, ipWhitelistJSON, token.ExpiresAt).Scan(&token.UpdatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrAdminAPITokenNotFound
+	}
+	return err
+}
+
+func (r *adminAPITokenRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.sql.ExecContext(ctx, `DELETE FROM admin_api_tokens WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return service.ErrAdminAPITokenNotFound
+	}
+	return nil
+}
+
+func (r *adminAPITokenRepository) TouchLastUsed(ctx context.Context, id int64, usedAt time.Time, clientIP string) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE admin_api_tokens SET last_used_at = $2, last_used_ip = $3 WHERE id = $1
+	`, id, usedAt, clientIP)
+	return err
+}
+
+func scanAdminAPIToken(row scannable) (*service.AdminAPIToken, error) {
+	token := &service.AdminAPIToken{}
+	var scopesJSON, ipWhitelistJSON []byte
+	var expiresAt, lastUsedAt sql.NullTime
+	var createdBy sql.NullInt64
+
+	if err := row.Scan(
+		&token.ID, &token.Name, &token.TokenHash, &token.TokenPrefix, &scopesJSON, &ipWhitelistJSON,
+		&expiresAt, &lastUsedAt, &token.LastUsedIP, &createdBy,
+		&token.CreatedAt, &token.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if expiresAt.Valid {
+		token.ExpiresAt = &expiresAt.Time
+	}
+	if lastUsedAt.Valid {
+		token.LastUsedAt = &lastUsedAt.Time
+	}
+	if createdBy.Valid {
+		token.CreatedBy = &createdBy.Int64
+	}
+	_ = json.Unmarshal(scopesJSON, &token.Scopes)
+	_ = json.Unmarshal(ipWhitelistJSON, &token.IPWhitelist)
+	return token, nil
+}
@@ -0,0 +1,172 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// adminAuditRepository 实现 service.AdminAuditRepository 接口。
+// 使用原生 SQL 操作 admin_audit_logs 表（只追加）。
+type adminAuditRepository struct {
+	sql *sql.DB
+}
+
+// NewAdminAuditRepository 创建管理员审计日志仓储实例。
+func NewAdminAuditRepository(sqlDB *sql.DB) service.AdminAuditRepository {
+	return &adminAuditRepository{sql: sqlDB}
+}
+
+const adminAuditColumns = `
+	id, actor_type, actor_user_id, actor_token_id, COALESCE(actor_token_name, ''),
+	method, route, path, target_type, target_id, status_code, client_ip,
+	request_body, diff, created_at`
+
+func (r *adminAuditRepository) Create(ctx context.Context, log *service.AdminAuditLog) error {
+	var requestBody, diff any
+	if len(log.RequestBody) > 0 {
+		requestBody = []byte(log.RequestBody)
+	}
+	if len(log.Diff) > 0 {
+		raw, err := json.Marshal(log.Diff)
+		if err != nil {
+			return err
+		}
+		diff = raw
+	}
+	var tokenName any
+	if log.ActorTokenName != "" {
+		tokenName = log.ActorTokenName
+	}
+
+	return r.sql.QueryRowContext(ctx, `
+		INSERT INTO admin_audit_logs (
+			actor_type, actor_user_id, actor_token_id, actor_token_name,
+			method, route, path, target_type, target_id, status_code, client_ip,
+			request_body, diff
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13)
+		RETURNING id, created_at
+	`,
+		log.ActorType, log.ActorUserID, log.ActorTokenID, tokenName,
+		log.Method, log.Route, log.Path, log.TargetType, log.TargetID, log.StatusCode, log.ClientIP,
+		requestBody, diff,
+	).Scan(&log.ID, &log.CreatedAt)
+}
+
+func (r *adminAuditRepository) List(ctx context.Context, params pagination.PaginationParams, filter service.AdminAuditLogFilter) ([]service.AdminAuditLog, *pagination.PaginationResult, error) {
+	where, args := buildAdminAuditWhere(filter)
+
+	var total int64
+	if err := r.sql.QueryRowContext(ctx, `SELECT COUNT(*) FROM admin_audit_logs`+where, args...).Scan(&total); err != nil {
+		return nil, nil, err
+	}
+
+	args = append(args, params.Limit(), params.Offset())
+	query := fmt.Sprintf(`SELECT %s FROM admin_audit_logs%s ORDER BY id DESC LIMIT $%d OFFSET $%d`,
+		adminAuditColumns, where, len(args)-1, len(args))
+	logs, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return logs, paginationResultFromTotal(total, params), nil
+}
+
+func (r *adminAuditRepository) ListAfterID(ctx context.Context, filter service.AdminAuditLogFilter, afterID int64, limit int) ([]service.AdminAuditLog, error) {
+	filterWhere, args := buildAdminAuditWhere(filter)
+	args = append(args, afterID)
+	where := fmt.Sprintf(" WHERE id > $%d", len(args))
+	if filterWhere != "" {
+		where = filterWhere + fmt.Sprintf(" AND id > $%d", len(args))
+	}
+	args = append(args, limit)
+	query := fmt.Sprintf(`SELECT %s FROM admin_audit_logs%s ORDER BY id ASC LIMIT $%d`, adminAuditColumns, where, len(args))
+	return r.query(ctx, query, args...)
+}
+
+func (r *adminAuditRepository) query(ctx context.Context, query string, args ...any) ([]service.AdminAuditLog, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	logs := make([]service.AdminAuditLog, 0)
+	for rows.Next() {
+		log, err := scanAdminAuditLog(rows)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, *log)
+	}
+	return logs, rows.Err()
+}
+
+// buildAdminAuditWhere 根据过滤条件拼接 WHERE 子句（参数从 $1 开始编号）
+func buildAdminAuditWhere(filter service.AdminAuditLogFilter) (string, []any) {
+	var conds []string
+	var args []any
+	add := func(cond string, arg any) {
+		args = append(args, arg)
+		conds = append(conds, fmt.Sprintf(cond, len(args)))
+	}
+
+	if filter.ActorUserID != nil {
+		add("actor_user_id = $%d", *filter.ActorUserID)
+	}
+	if filter.ActorType != "" {
+		add("actor_type = $%d", filter.ActorType)
+	}
+	if filter.TargetType != "" {
+		add("target_type = $%d", filter.TargetType)
+	}
+	if filter.TargetID != "" {
+		add("target_id = $%d", filter.TargetID)
+	}
+	if filter.Method != "" {
+		add("method = $%d", strings.ToUpper(filter.Method))
+	}
+	if filter.StartTime != nil {
+		add("created_at >= $%d", *filter.StartTime)
+	}
+	if filter.EndTime != nil {
+		add("created_at <= $%d", *filter.EndTime)
+	}
+
+	if len(conds) == 0 {
+		return "", args
+	}
+	return " WHERE " + strings.Join(conds, " AND "), args
+}
+
+func scanAdminAuditLog(row scannable) (*service.AdminAuditLog, error) {
+	log := &service.AdminAuditLog{}
+	var actorUserID, actorTokenID sql.NullInt64
+	var requestBody, diff []byte
+
+	if err := row.Scan(
+		&log.ID, &log.ActorType, &actorUserID, &actorTokenID, &log.ActorTokenName,
+		&log.Method, &log.Route, &log.Path, &log.TargetType, &log.TargetID, &log.StatusCode, &log.ClientIP,
+		&requestBody, &diff, &log.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+
+	if actorUserID.Valid {
+		log.ActorUserID = &actorUserID.Int64
+	}
+	if actorTokenID.Valid {
+		log.ActorTokenID = &actorTokenID.Int64
+	}
+	if len(requestBody) > 0 {
+		log.RequestBody = json.RawMessage(requestBody)
+	}
+	if len(diff) > 0 {
+		_ = json.Unmarshal(diff, &log.Diff)
+	}
+	return log, nil
+}
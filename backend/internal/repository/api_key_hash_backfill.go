@@ -0,0 +1,78 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const apiKeyHashBackfillBatchSize = 500
+
+// backfillAPIKeyHashes 将历史明文 API Key 原地迁移为 HMAC 哈希 + 展示前缀，并清空明文列。
+//
+// 迁移 086 只能新增列：哈希密钥在应用侧（security_secrets），因此回填在启动阶段完成。
+// 幂等：仅处理 key_hash 为空的行；多实例同时启动时由 WHERE 条件保证每行只改写一次。
+// 已软删除的 Key 同样处理，确保库中不再残留任何明文。
+func backfillAPIKeyHashes(ctx context.Context, db sqlExecutor, secret string) (int, error) {
+	total := 0
+	for {
+		rows, err := db.QueryContext(ctx, `
+			SELECT id, key
+			FROM api_keys
+			WHERE key_hash IS NULL AND key IS NOT NULL
+			ORDER BY id
+			LIMIT $1
+		`, apiKeyHashBackfillBatchSize)
+		if err != nil {
+			return total, fmt.Errorf("query plaintext api keys: %w", err)
+		}
+		type plaintextKey struct {
+			id  int64
+			key string
+		}
+		batch := make([]plaintextKey, 0, apiKeyHashBackfillBatchSize)
+		for rows.Next() {
+			var item plaintextKey
+			if err := rows.Scan(&item.id, &item.key); err != nil {
+				_ = rows.Close()
+				return total, err
+			}
+			batch = append(batch, item)
+		}
+		if err := rows.Err(); err != nil {
+			_ = rows.Close()
+			return total, err
+		}
+		_ = rows.Close()
+		if len(batch) == 0 {
+			return total, nil
+		}
+
+		for _, item := range batch {
+			result, err := db.ExecContext(ctx, `
+				UPDATE api_keys
+				SET key_hash = $2, key_prefix = $3, key = NULL
+				WHERE id = $1 AND key_hash IS NULL
+			`, item.id, service.HashAPIKey(secret, item.key), service.APIKeyDisplayPrefix(item.key))
+			if err != nil {
+				return total, fmt.Errorf("hash api key %d: %w", item.id, err)
+			}
+			if affected, _ := result.RowsAffected(); affected > 0 {
+				total++
+			}
+		}
+	}
+}
+
+func ensureAPIKeyHashes(ctx context.Context, db sqlExecutor, secret string) error {
+	migrated, err := backfillAPIKeyHashes(ctx, db, secret)
+	if err != nil {
+		return fmt.Errorf("backfill api key hashes: %w", err)
+	}
+	if migrated > 0 {
+		log.Printf("Migrated %d plaintext API keys to hashed storage.", migrated)
+	}
+	return nil
+}
@@ -0,0 +1,38 @@
+package repository
+
+import (
+	"context"
+	"testing"
+
+	sqlmock "github.com/DATA-DOG/go-sqlmock"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBackfillAPIKeyHashes(t *testing.T) {
+	db, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	defer func() { _ = db.Close() }()
+
+	const secret = "backfill-secret"
+	mock.ExpectQuery("SELECT id, key\\s+FROM api_keys\\s+WHERE key_hash IS NULL AND key IS NOT NULL").
+		WithArgs(apiKeyHashBackfillBatchSize).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key"}).
+			AddRow(int64(1), "sk-plaintext-one").
+			AddRow(int64(2), "sk-plaintext-two"))
+	mock.ExpectExec("UPDATE api_keys\\s+SET key_hash = \\$2, key_prefix = \\$3, key = NULL\\s+WHERE id = \\$1 AND key_hash IS NULL").
+		WithArgs(int64(1), service.HashAPIKey(secret, "sk-plaintext-one"), "sk-plain").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	// 并发实例已处理的行不计数
+	mock.ExpectExec("UPDATE api_keys").
+		WithArgs(int64(2), service.HashAPIKey(secret, "sk-plaintext-two"), "sk-plain").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectQuery("SELECT id, key").
+		WithArgs(apiKeyHashBackfillBatchSize).
+		WillReturnRows(sqlmock.NewRows([]string{"id", "key"}))
+
+	migrated, err := backfillAPIKeyHashes(context.Background(), db, secret)
+	require.NoError(t, err)
+	require.Equal(t, 1, migrated)
+	require.NoError(t, mock.ExpectationsWereMet())
+}
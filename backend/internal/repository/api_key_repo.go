@@ -5,9 +5,13 @@ import (
 	"database/sql"
 	"time"
 
+	entsql "entgo.io/ent/dialect/sql"
+
 	dbent "github.com/Wei-Shaw/sub2api/ent"
 	"github.com/Wei-Shaw/sub2api/ent/apikey"
 	"github.com/Wei-Shaw/sub2api/ent/group"
+	"github.com/Wei-Shaw/sub2api/ent/organization"
+	"github.com/Wei-Shaw/sub2api/ent/predicate"
 	"github.com/Wei-Shaw/sub2api/ent/schema/mixins"
 	"github.com/Wei-Shaw/sub2api/ent/user"
 	"github.com/Wei-Shaw/sub2api/internal/service"
@@ -36,17 +40,22 @@ func (r *apiKeyRepository) activeQuery() *dbent.APIKeyQuery {
 func (r *apiKeyRepository) Create(ctx context.Context, key *service.APIKey) error {
 	builder := r.client.APIKey.Create().
 		SetUserID(key.UserID).
-		SetKey(key.Key).
+		SetKeyHash(key.KeyHash).
+		SetKeyPrefix(key.KeyPrefix).
 		SetName(key.Name).
 		SetStatus(key.Status).
 		SetNillableGroupID(key.GroupID).
+		SetNillableOrganizationID(key.OrganizationID).
 		SetNillableLastUsedAt(key.LastUsedAt).
 		SetQuota(key.Quota).
 		SetQuotaUsed(key.QuotaUsed).
 		SetNillableExpiresAt(key.ExpiresAt).
 		SetRateLimit5h(key.RateLimit5h).
 		SetRateLimit1d(key.RateLimit1d).
-		SetRateLimit7d(key.RateLimit7d)
+		SetRateLimit7d(key.RateLimit7d).
+		SetRpmLimit(key.RPMLimit).
+		SetTpmLimit(key.TPMLimit).
+		SetMaxConcurrency(key.MaxConcurrency)
 
 	if len(key.IPWhitelist) > 0 {
 		builder.SetIPWhitelist(key.IPWhitelist)
@@ -54,6 +63,15 @@ func (r *apiKeyRepository) Create(ctx context.Context, key *service.APIKey) erro
 	if len(key.IPBlacklist) > 0 {
 		builder.SetIPBlacklist(key.IPBlacklist)
 	}
+	if len(key.AllowedModels) > 0 {
+		builder.SetAllowedModels(key.AllowedModels)
+	}
+	if len(key.DeniedModels) > 0 {
+		builder.SetDeniedModels(key.DeniedModels)
+	}
+	if len(key.ModelAliases) > 0 {
+		builder.SetModelAliases(key.ModelAliases)
+	}
 
 	created, err := builder.Save(ctx)
 	if err == nil {
@@ -80,15 +98,15 @@ func (r *apiKeyRepository) GetByID(ctx context.Context, id int64) (*service.APIK
 	return apiKeyEntityToService(m), nil
 }
 
-// GetKeyAndOwnerID 根据 API Key ID 获取其 key 与所有者（用户）ID。
+// GetKeyHashAndOwnerID 根据 API Key ID 获取其 key 哈希与所有者（用户）ID。
 // 相比 GetByID，此方法性能更优，因为：
 //   - 使用 Select() 只查询必要字段，减少数据传输量
 //   - 不加载完整的 API Key 实体及其关联数据（User、Group 等）
-//   - 适用于删除等只需 key 与用户 ID 的场景
-func (r *apiKeyRepository) GetKeyAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
+//   - 适用于删除等只需 key 哈希与用户 ID 的场景
+func (r *apiKeyRepository) GetKeyHashAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
 	m, err := r.activeQuery().
 		Where(apikey.IDEQ(id)).
-		Select(apikey.FieldKey, apikey.FieldUserID).
+		Select(apikey.FieldKeyHash, apikey.FieldUserID).
 		Only(ctx)
 	if err != nil {
 		if dbent.IsNotFound(err) {
@@ -96,12 +114,12 @@ func (r *apiKeyRepository) GetKeyAndOwnerID(ctx context.Context, id int64) (stri
 		}
 		return "", 0, err
 	}
-	return m.Key, m.UserID, nil
+	return m.KeyHash, m.UserID, nil
 }
 
-func (r *apiKeyRepository) GetByKey(ctx context.Context, key string) (*service.APIKey, error) {
+func (r *apiKeyRepository) GetByKeyHash(ctx context.Context, keyHash string) (*service.APIKey, error) {
 	m, err := r.activeQuery().
-		Where(apikey.KeyEQ(key)).
+		Where(apikey.KeyHashEQ(keyHash)).
 		WithUser().
 		WithGroup().
 		Only(ctx)
@@ -114,22 +132,29 @@ func (r *apiKeyRepository) GetByKey(ctx context.Context, key string) (*service.A
 	return apiKeyEntityToService(m), nil
 }
 
-func (r *apiKeyRepository) GetByKeyForAuth(ctx context.Context, key string) (*service.APIKey, error) {
+func (r *apiKeyRepository) GetByKeyHashForAuth(ctx context.Context, keyHash string) (*service.APIKey, error) {
 	m, err := r.activeQuery().
-		Where(apikey.KeyEQ(key)).
+		Where(apikey.KeyHashEQ(keyHash)).
 		Select(
 			apikey.FieldID,
 			apikey.FieldUserID,
 			apikey.FieldGroupID,
+			apikey.FieldOrganizationID,
 			apikey.FieldStatus,
 			apikey.FieldIPWhitelist,
 			apikey.FieldIPBlacklist,
+			apikey.FieldAllowedModels,
+			apikey.FieldDeniedModels,
+			apikey.FieldModelAliases,
 			apikey.FieldQuota,
 			apikey.FieldQuotaUsed,
 			apikey.FieldExpiresAt,
 			apikey.FieldRateLimit5h,
 			apikey.FieldRateLimit1d,
 			apikey.FieldRateLimit7d,
+			apikey.FieldRpmLimit,
+			apikey.FieldTpmLimit,
+			apikey.FieldMaxConcurrency,
 		).
 		WithUser(func(q *dbent.UserQuery) {
 			q.Select(
@@ -167,6 +192,18 @@ func (r *apiKeyRepository) GetByKeyForAuth(ctx context.Context, key string) (*se
 				group.FieldSupportedModelScopes,
 			group.FieldAllowMessagesDispatch,
 			group.FieldDefaultMappedModel,
+				group.FieldResponseCacheEnabled,
+				group.FieldResponseCacheTTLSeconds,
+				group.FieldResponseCachePriceRatio,
+				group.FieldModelFallbackChains,
+			)
+		}).
+		WithOrganization(func(q *dbent.OrganizationQuery) {
+			q.Select(
+				organization.FieldID,
+				organization.FieldOwnerUserID,
+				organization.FieldBalance,
+				organization.FieldStatus,
 			)
 		}).
 		Only(ctx)
@@ -199,6 +236,9 @@ func (r *apiKeyRepository) Update(ctx context.Context, key *service.APIKey) erro
 		SetUsage5h(key.Usage5h).
 		SetUsage1d(key.Usage1d).
 		SetUsage7d(key.Usage7d).
+		SetRpmLimit(key.RPMLimit).
+		SetTpmLimit(key.TPMLimit).
+		SetMaxConcurrency(key.MaxConcurrency).
 		SetUpdatedAt(now)
 	if key.GroupID != nil {
 		builder.SetGroupID(*key.GroupID)
@@ -242,6 +282,23 @@ func (r *apiKeyRepository) Update(ctx context.Context, key *service.APIKey) erro
 		builder.ClearIPBlacklist()
 	}
 
+	// 模型策略字段
+	if len(key.AllowedModels) > 0 {
+		builder.SetAllowedModels(key.AllowedModels)
+	} else {
+		builder.ClearAllowedModels()
+	}
+	if len(key.DeniedModels) > 0 {
+		builder.SetDeniedModels(key.DeniedModels)
+	} else {
+		builder.ClearDeniedModels()
+	}
+	if len(key.ModelAliases) > 0 {
+		builder.SetModelAliases(key.ModelAliases)
+	} else {
+		builder.ClearModelAliases()
+	}
+
 	affected, err := builder.Save(ctx)
 	if err != nil {
 		return err
@@ -290,7 +347,7 @@ func (r *apiKeyRepository) ListByUserID(ctx context.Context, userID int64, param
 	if filters.Search != "" {
 		q = q.Where(apikey.Or(
 			apikey.NameContainsFold(filters.Search),
-			apikey.KeyContainsFold(filters.Search),
+			apiKeyPrefixSearch(filters.Search),
 		))
 	}
 	if filters.Status != "" {
@@ -311,6 +368,7 @@ func (r *apiKeyRepository) ListByUserID(ctx context.Context, userID int64, param
 
 	keys, err := q.
 		WithGroup().
+		WithOrganization().
 		Offset(params.Offset()).
 		Limit(params.Limit()).
 		Order(dbent.Desc(apikey.FieldID)).
@@ -346,8 +404,8 @@ func (r *apiKeyRepository) CountByUserID(ctx context.Context, userID int64) (int
 	return int64(count), err
 }
 
-func (r *apiKeyRepository) ExistsByKey(ctx context.Context, key string) (bool, error) {
-	count, err := r.activeQuery().Where(apikey.KeyEQ(key)).Count(ctx)
+func (r *apiKeyRepository) ExistsByKeyHash(ctx context.Context, keyHash string) (bool, error) {
+	count, err := r.activeQuery().Where(apikey.KeyHashEQ(keyHash)).Count(ctx)
 	return count > 0, err
 }
 
@@ -377,7 +435,7 @@ func (r *apiKeyRepository) ListByGroupID(ctx context.Context, groupID int64, par
 	return outKeys, paginationResultFromTotal(int64(total), params), nil
 }
 
-// SearchAPIKeys searches API keys by user ID and/or keyword (name)
+// SearchAPIKeys searches API keys by user ID and/or keyword (name or key prefix)
 func (r *apiKeyRepository) SearchAPIKeys(ctx context.Context, userID int64, keyword string, limit int) ([]service.APIKey, error) {
 	q := r.activeQuery()
 	if userID > 0 {
@@ -385,7 +443,10 @@ func (r *apiKeyRepository) SearchAPIKeys(ctx context.Context, userID int64, keyw
 	}
 
 	if keyword != "" {
-		q = q.Where(apikey.NameContainsFold(keyword))
+		q = q.Where(apikey.Or(
+			apikey.NameContainsFold(keyword),
+			apiKeyPrefixSearch(keyword),
+		))
 	}
 
 	keys, err := q.Limit(limit).Order(dbent.Desc(apikey.FieldID)).All(ctx)
@@ -415,10 +476,10 @@ func (r *apiKeyRepository) CountByGroupID(ctx context.Context, groupID int64) (i
 	return int64(count), err
 }
 
-func (r *apiKeyRepository) ListKeysByUserID(ctx context.Context, userID int64) ([]string, error) {
+func (r *apiKeyRepository) ListKeyHashesByUserID(ctx context.Context, userID int64) ([]string, error) {
 	keys, err := r.activeQuery().
 		Where(apikey.UserIDEQ(userID)).
-		Select(apikey.FieldKey).
+		Select(apikey.FieldKeyHash).
 		Strings(ctx)
 	if err != nil {
 		return nil, err
@@ -426,10 +487,10 @@ func (r *apiKeyRepository) ListKeysByUserID(ctx context.Context, userID int64) (
 	return keys, nil
 }
 
-func (r *apiKeyRepository) ListKeysByGroupID(ctx context.Context, groupID int64) ([]string, error) {
+func (r *apiKeyRepository) ListKeyHashesByGroupID(ctx context.Context, groupID int64) ([]string, error) {
 	keys, err := r.activeQuery().
 		Where(apikey.GroupIDEQ(groupID)).
-		Select(apikey.FieldKey).
+		Select(apikey.FieldKeyHash).
 		Strings(ctx)
 	if err != nil {
 		return nil, err
@@ -532,11 +593,15 @@ func apiKeyEntityToService(m *dbent.APIKey) *service.APIKey {
 	out := &service.APIKey{
 		ID:            m.ID,
 		UserID:        m.UserID,
-		Key:           m.Key,
+		KeyHash:       m.KeyHash,
+		KeyPrefix:     m.KeyPrefix,
 		Name:          m.Name,
 		Status:        m.Status,
 		IPWhitelist:   m.IPWhitelist,
 		IPBlacklist:   m.IPBlacklist,
+		AllowedModels: m.AllowedModels,
+		DeniedModels:  m.DeniedModels,
+		ModelAliases:  m.ModelAliases,
 		LastUsedAt:    m.LastUsedAt,
 		CreatedAt:     m.CreatedAt,
 		UpdatedAt:     m.UpdatedAt,
@@ -553,6 +618,12 @@ func apiKeyEntityToService(m *dbent.APIKey) *service.APIKey {
 		Window5hStart: m.Window5hStart,
 		Window1dStart: m.Window1dStart,
 		Window7dStart: m.Window7dStart,
+
+		RPMLimit:       m.RpmLimit,
+		TPMLimit:       m.TpmLimit,
+		MaxConcurrency: m.MaxConcurrency,
+
+		OrganizationID: m.OrganizationID,
 	}
 	if m.Edges.User != nil {
 		out.User = userEntityToService(m.Edges.User)
@@ -560,9 +631,26 @@ func apiKeyEntityToService(m *dbent.APIKey) *service.APIKey {
 	if m.Edges.Group != nil {
 		out.Group = groupEntityToService(m.Edges.Group)
 	}
+	if m.Edges.Organization != nil {
+		out.Organization = organizationEntityToService(m.Edges.Organization)
+	}
 	return out
 }
 
+// apiKeyPrefixSearch 按展示前缀搜索 Key：既支持输入前缀片段，也支持粘贴完整 Key（以其存储前缀开头即匹配）
+func apiKeyPrefixSearch(search string) predicate.APIKey {
+	return apikey.Or(
+		apikey.KeyPrefixContainsFold(search),
+		func(s *entsql.Selector) {
+			col := s.C(apikey.FieldKeyPrefix)
+			s.Where(entsql.P(func(b *entsql.Builder) {
+				b.WriteString(col).WriteString(" <> '' AND LEFT(").Arg(search).
+					WriteString(", LENGTH(").WriteString(col).WriteString(")) = ").WriteString(col)
+			}))
+		},
+	)
+}
+
 func userEntityToService(u *dbent.User) *service.User {
 	if u == nil {
 		return nil
@@ -626,6 +714,10 @@ func groupEntityToService(g *dbent.Group) *service.Group {
 		SortOrder:                       g.SortOrder,
 		AllowMessagesDispatch:           g.AllowMessagesDispatch,
 		DefaultMappedModel:              g.DefaultMappedModel,
+		ResponseCacheEnabled:            g.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds:         g.ResponseCacheTTLSeconds,
+		ResponseCachePriceRatio:         g.ResponseCachePriceRatio,
+		ModelFallbackChains:             g.ModelFallbackChains,
 		CreatedAt:                       g.CreatedAt,
 		UpdatedAt:                       g.UpdatedAt,
 	}
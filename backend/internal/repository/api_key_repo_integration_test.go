@@ -39,10 +39,10 @@ func (s *APIKeyRepoSuite) TestCreate() {
 	user := s.mustCreateUser("create@test.com")
 
 	key := &service.APIKey{
-		UserID: user.ID,
-		Key:    "sk-create-test",
-		Name:   "Test Key",
-		Status: service.StatusActive,
+		UserID:  user.ID,
+		KeyHash: testAPIKeyHash("sk-create-test"),
+		Name:    "Test Key",
+		Status:  service.StatusActive,
 	}
 
 	err := s.repo.Create(s.ctx, key)
@@ -51,7 +51,8 @@ func (s *APIKeyRepoSuite) TestCreate() {
 
 	got, err := s.repo.GetByID(s.ctx, key.ID)
 	s.Require().NoError(err, "GetByID")
-	s.Require().Equal("sk-create-test", got.Key)
+	s.Require().Equal(testAPIKeyHash("sk-create-test"), got.KeyHash)
+	s.Require().Empty(got.Key, "plaintext key must not be persisted")
 }
 
 func (s *APIKeyRepoSuite) TestGetByID_NotFound() {
@@ -65,14 +66,14 @@ func (s *APIKeyRepoSuite) TestGetByKey() {
 
 	key := &service.APIKey{
 		UserID:  user.ID,
-		Key:     "sk-getbykey",
+		KeyHash: testAPIKeyHash("sk-getbykey"),
 		Name:    "My Key",
 		GroupID: &group.ID,
 		Status:  service.StatusActive,
 	}
 	s.Require().NoError(s.repo.Create(s.ctx, key))
 
-	got, err := s.repo.GetByKey(s.ctx, key.Key)
+	got, err := s.repo.GetByKeyHash(s.ctx, key.KeyHash)
 	s.Require().NoError(err, "GetByKey")
 	s.Require().Equal(key.ID, got.ID)
 	s.Require().NotNil(got.User, "expected User preload")
@@ -82,7 +83,7 @@ func (s *APIKeyRepoSuite) TestGetByKey() {
 }
 
 func (s *APIKeyRepoSuite) TestGetByKey_NotFound() {
-	_, err := s.repo.GetByKey(s.ctx, "non-existent-key")
+	_, err := s.repo.GetByKeyHash(s.ctx, testAPIKeyHash("non-existent-key"))
 	s.Require().Error(err, "expected error for non-existent key")
 }
 
@@ -91,10 +92,10 @@ func (s *APIKeyRepoSuite) TestGetByKey_NotFound() {
 func (s *APIKeyRepoSuite) TestUpdate() {
 	user := s.mustCreateUser("update@test.com")
 	key := &service.APIKey{
-		UserID: user.ID,
-		Key:    "sk-update",
-		Name:   "Original",
-		Status: service.StatusActive,
+		UserID:  user.ID,
+		KeyHash: testAPIKeyHash("sk-update"),
+		Name:    "Original",
+		Status:  service.StatusActive,
 	}
 	s.Require().NoError(s.repo.Create(s.ctx, key))
 
@@ -105,7 +106,7 @@ func (s *APIKeyRepoSuite) TestUpdate() {
 
 	got, err := s.repo.GetByID(s.ctx, key.ID)
 	s.Require().NoError(err, "GetByID after update")
-	s.Require().Equal("sk-update", got.Key, "Update should not change key")
+	s.Require().Equal(testAPIKeyHash("sk-update"), got.KeyHash, "Update should not change key")
 	s.Require().Equal(user.ID, got.UserID, "Update should not change user_id")
 	s.Require().Equal("Renamed", got.Name)
 	s.Require().Equal(service.StatusDisabled, got.Status)
@@ -116,7 +117,7 @@ func (s *APIKeyRepoSuite) TestUpdate_ClearGroupID() {
 	group := s.mustCreateGroup("g-clear")
 	key := &service.APIKey{
 		UserID:  user.ID,
-		Key:     "sk-clear-group",
+		KeyHash: testAPIKeyHash("sk-clear-group"),
 		Name:    "Group Key",
 		GroupID: &group.ID,
 		Status:  service.StatusActive,
@@ -137,10 +138,10 @@ func (s *APIKeyRepoSuite) TestUpdate_ClearGroupID() {
 func (s *APIKeyRepoSuite) TestDelete() {
 	user := s.mustCreateUser("delete@test.com")
 	key := &service.APIKey{
-		UserID: user.ID,
-		Key:    "sk-delete",
-		Name:   "Delete Me",
-		Status: service.StatusActive,
+		UserID:  user.ID,
+		KeyHash: testAPIKeyHash("sk-delete"),
+		Name:    "Delete Me",
+		Status:  service.StatusActive,
 	}
 	s.Require().NoError(s.repo.Create(s.ctx, key))
 
@@ -215,17 +216,17 @@ func (s *APIKeyRepoSuite) TestCountByGroupID() {
 	s.Require().Equal(int64(1), count)
 }
 
-// --- ExistsByKey ---
+// --- ExistsByKeyHash ---
 
-func (s *APIKeyRepoSuite) TestExistsByKey() {
+func (s *APIKeyRepoSuite) TestExistsByKeyHash() {
 	user := s.mustCreateUser("exists@test.com")
 	s.mustCreateApiKey(user.ID, "sk-exists", "K", nil)
 
-	exists, err := s.repo.ExistsByKey(s.ctx, "sk-exists")
-	s.Require().NoError(err, "ExistsByKey")
+	exists, err := s.repo.ExistsByKeyHash(s.ctx, testAPIKeyHash("sk-exists"))
+	s.Require().NoError(err, "ExistsByKeyHash")
 	s.Require().True(exists)
 
-	notExists, err := s.repo.ExistsByKey(s.ctx, "sk-not-exists")
+	notExists, err := s.repo.ExistsByKeyHash(s.ctx, testAPIKeyHash("sk-not-exists"))
 	s.Require().NoError(err)
 	s.Require().False(notExists)
 }
@@ -243,6 +244,25 @@ func (s *APIKeyRepoSuite) TestSearchAPIKeys() {
 	s.Require().Contains(found[0].Name, "Production")
 }
 
+func (s *APIKeyRepoSuite) TestSearchAPIKeys_ByKeyPrefix() {
+	user := s.mustCreateUser("searchprefix@test.com")
+	s.mustCreateApiKey(user.ID, "sk-alpha-00000000000000000001", "A", nil)
+	s.mustCreateApiKey(user.ID, "sk-bravo-00000000000000000002", "B", nil)
+
+	// 展示前缀的片段
+	found, err := s.repo.SearchAPIKeys(s.ctx, user.ID, "alpha", 10)
+	s.Require().NoError(err)
+	s.Require().Len(found, 1)
+	s.Require().Equal("A", found[0].Name)
+
+	// 粘贴完整 Key 也能按前缀命中
+	found, err = s.repo.SearchAPIKeys(s.ctx, user.ID, "sk-bravo-00000000000000000002", 10)
+	s.Require().NoError(err)
+	s.Require().Len(found, 1)
+	s.Require().Equal("B", found[0].Name)
+	s.Require().Empty(found[0].Key)
+}
+
 func (s *APIKeyRepoSuite) TestSearchAPIKeys_NoKeyword() {
 	user := s.mustCreateUser("searchnokw@test.com")
 	s.mustCreateApiKey(user.ID, "sk-nk-1", "K1", nil)
@@ -293,7 +313,7 @@ func (s *APIKeyRepoSuite) TestCRUD_Search_ClearGroupID() {
 	key := s.mustCreateApiKey(user.ID, "sk-test-1", "My Key", &group.ID)
 	key.GroupID = &group.ID
 
-	got, err := s.repo.GetByKey(s.ctx, key.Key)
+	got, err := s.repo.GetByKeyHash(s.ctx, key.KeyHash)
 	s.Require().NoError(err, "GetByKey")
 	s.Require().Equal(key.ID, got.ID)
 	s.Require().NotNil(got.User)
@@ -308,7 +328,7 @@ func (s *APIKeyRepoSuite) TestCRUD_Search_ClearGroupID() {
 
 	got2, err := s.repo.GetByID(s.ctx, key.ID)
 	s.Require().NoError(err, "GetByID")
-	s.Require().Equal("sk-test-1", got2.Key, "Update should not change key")
+	s.Require().Equal(testAPIKeyHash("sk-test-1"), got2.KeyHash, "Update should not change key")
 	s.Require().Equal(user.ID, got2.UserID, "Update should not change user_id")
 	s.Require().Equal("Renamed", got2.Name)
 	s.Require().Equal(service.StatusDisabled, got2.Status)
@@ -319,8 +339,8 @@ func (s *APIKeyRepoSuite) TestCRUD_Search_ClearGroupID() {
 	s.Require().Equal(int64(1), page.Total)
 	s.Require().Len(keys, 1)
 
-	exists, err := s.repo.ExistsByKey(s.ctx, "sk-test-1")
-	s.Require().NoError(err, "ExistsByKey")
+	exists, err := s.repo.ExistsByKeyHash(s.ctx, testAPIKeyHash("sk-test-1"))
+	s.Require().NoError(err, "ExistsByKeyHash")
 	s.Require().True(exists, "expected key to exist")
 
 	found, err := s.repo.SearchAPIKeys(s.ctx, user.ID, "renam", 10)
@@ -377,11 +397,13 @@ func (s *APIKeyRepoSuite) mustCreateApiKey(userID int64, key, name string, group
 	s.T().Helper()
 
 	k := &service.APIKey{
-		UserID:  userID,
-		Key:     key,
-		Name:    name,
-		GroupID: groupID,
-		Status:  service.StatusActive,
+		UserID:    userID,
+		Key:       key,
+		KeyHash:   testAPIKeyHash(key),
+		KeyPrefix: service.APIKeyDisplayPrefix(key),
+		Name:      name,
+		GroupID:   groupID,
+		Status:    service.StatusActive,
 	}
 	s.Require().NoError(s.repo.Create(s.ctx, k), "create api key")
 	return k
@@ -434,10 +456,10 @@ func TestIncrementQuotaUsed_Concurrent(t *testing.T) {
 	require.NoError(t, err, "create user")
 
 	k := &service.APIKey{
-		UserID: u.ID,
-		Key:    "sk-concurrent-" + time.Now().Format(time.RFC3339Nano),
-		Name:   "Concurrent",
-		Status: service.StatusActive,
+		UserID:  u.ID,
+		KeyHash: testAPIKeyHash("sk-concurrent-" + time.Now().Format(time.RFC3339Nano)),
+		Name:    "Concurrent",
+		Status:  service.StatusActive,
 	}
 	require.NoError(t, repo.Create(ctx, k), "create api key")
 	t.Cleanup(func() {
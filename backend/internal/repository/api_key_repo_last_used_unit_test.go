@@ -53,7 +53,7 @@ func TestAPIKeyRepository_CreateWithLastUsedAt(t *testing.T) {
 	lastUsed := time.Now().UTC().Add(-time.Hour).Truncate(time.Second)
 	key := &service.APIKey{
 		UserID:     user.ID,
-		Key:        "sk-create-last-used",
+		KeyHash:    "sk-create-last-used-hash",
 		Name:       "CreateWithLastUsed",
 		Status:     service.StatusActive,
 		LastUsedAt: &lastUsed,
@@ -75,10 +75,10 @@ func TestAPIKeyRepository_UpdateLastUsed(t *testing.T) {
 	user := mustCreateAPIKeyRepoUser(t, ctx, client, "update-last-used@test.com")
 
 	key := &service.APIKey{
-		UserID: user.ID,
-		Key:    "sk-update-last-used",
-		Name:   "UpdateLastUsed",
-		Status: service.StatusActive,
+		UserID:  user.ID,
+		KeyHash: "sk-update-last-used-hash",
+		Name:    "UpdateLastUsed",
+		Status:  service.StatusActive,
 	}
 	require.NoError(t, repo.Create(ctx, key))
 
@@ -102,10 +102,10 @@ func TestAPIKeyRepository_UpdateLastUsedDeletedKey(t *testing.T) {
 	user := mustCreateAPIKeyRepoUser(t, ctx, client, "deleted-last-used@test.com")
 
 	key := &service.APIKey{
-		UserID: user.ID,
-		Key:    "sk-update-last-used-deleted",
-		Name:   "UpdateLastUsedDeleted",
-		Status: service.StatusActive,
+		UserID:  user.ID,
+		KeyHash: "sk-update-last-used-deleted-hash",
+		Name:    "UpdateLastUsedDeleted",
+		Status:  service.StatusActive,
 	}
 	require.NoError(t, repo.Create(ctx, key))
 	require.NoError(t, repo.Delete(ctx, key.ID))
@@ -120,10 +120,10 @@ func TestAPIKeyRepository_UpdateLastUsedDBError(t *testing.T) {
 	user := mustCreateAPIKeyRepoUser(t, ctx, client, "db-error-last-used@test.com")
 
 	key := &service.APIKey{
-		UserID: user.ID,
-		Key:    "sk-update-last-used-db-error",
-		Name:   "UpdateLastUsedDBError",
-		Status: service.StatusActive,
+		UserID:  user.ID,
+		KeyHash: "sk-update-last-used-db-error-hash",
+		Name:    "UpdateLastUsedDBError",
+		Status:  service.StatusActive,
 	}
 	require.NoError(t, repo.Create(ctx, key))
 
@@ -138,16 +138,16 @@ func TestAPIKeyRepository_CreateDuplicateKey(t *testing.T) {
 	user := mustCreateAPIKeyRepoUser(t, ctx, client, "duplicate-key@test.com")
 
 	first := &service.APIKey{
-		UserID: user.ID,
-		Key:    "sk-duplicate",
-		Name:   "first",
-		Status: service.StatusActive,
+		UserID:  user.ID,
+		KeyHash: "sk-duplicate-hash",
+		Name:    "first",
+		Status:  service.StatusActive,
 	}
 	second := &service.APIKey{
-		UserID: user.ID,
-		Key:    "sk-duplicate",
-		Name:   "second",
-		Status: service.StatusActive,
+		UserID:  user.ID,
+		KeyHash: "sk-duplicate-hash",
+		Name:    "second",
+		Status:  service.StatusActive,
 	}
 
 	require.NoError(t, repo.Create(ctx, first))
@@ -0,0 +1,126 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+const (
+	billingOrgBalanceKeyPrefix = "billing:org_balance:"
+	billingOrgMemberKeyPrefix  = "billing:org_member:"
+
+	orgMemberFieldLimit  = "monthly_limit"
+	orgMemberFieldUsage  = "monthly_usage"
+	orgMemberFieldWindow = "window"
+)
+
+var _ service.OrganizationBillingCache = (*billingCache)(nil)
+
+// updateOrgMemberUsageScript 累加成员月度用量；缓存窗口早于当前自然月时先清零。
+// 缓存不存在时返回 0，由下次检查回源数据库重建。
+var updateOrgMemberUsageScript = redis.NewScript(`
+	local exists = redis.call('EXISTS', KEYS[1])
+	if exists == 0 then
+		return 0
+	end
+	local window = tonumber(redis.call('HGET', KEYS[1], 'window') or '0')
+	if window < tonumber(ARGV[2]) then
+		redis.call('HSET', KEYS[1], 'monthly_usage', 0, 'window', ARGV[2])
+	end
+	redis.call('HINCRBYFLOAT', KEYS[1], 'monthly_usage', ARGV[1])
+	redis.call('EXPIRE', KEYS[1], ARGV[3])
+	return 1
+`)
+
+// billingOrgBalanceKey generates the Redis key for organization balance cache.
+func billingOrgBalanceKey(orgID int64) string {
+	return fmt.Sprintf("%s%d", billingOrgBalanceKeyPrefix, orgID)
+}
+
+// billingOrgMemberKey generates the Redis key for organization member usage cache.
+func billingOrgMemberKey(orgID, userID int64) string {
+	return fmt.Sprintf("%s%d:%d", billingOrgMemberKeyPrefix, orgID, userID)
+}
+
+func (c *billingCache) GetOrganizationBalance(ctx context.Context, orgID int64) (float64, error) {
+	val, err := c.rdb.Get(ctx, billingOrgBalanceKey(orgID)).Result()
+	if err != nil {
+		return 0, err
+	}
+	return strconv.ParseFloat(val, 64)
+}
+
+func (c *billingCache) SetOrganizationBalance(ctx context.Context, orgID int64, balance float64) error {
+	return c.rdb.Set(ctx, billingOrgBalanceKey(orgID), balance, jitteredTTL()).Err()
+}
+
+func (c *billingCache) DeductOrganizationBalance(ctx context.Context, orgID int64, amount float64) error {
+	_, err := deductBalanceScript.Run(ctx, c.rdb, []string{billingOrgBalanceKey(orgID)}, amount, int(jitteredTTL().Seconds())).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("Warning: deduct organization balance cache failed for org %d: %v", orgID, err)
+		return err
+	}
+	return nil
+}
+
+func (c *billingCache) InvalidateOrganizationBalance(ctx context.Context, orgID int64) error {
+	return c.rdb.Del(ctx, billingOrgBalanceKey(orgID)).Err()
+}
+
+func (c *billingCache) GetOrganizationMember(ctx context.Context, orgID, userID int64) (*service.OrganizationMemberCacheData, error) {
+	result, err := c.rdb.HGetAll(ctx, billingOrgMemberKey(orgID, userID)).Result()
+	if err != nil {
+		return nil, err
+	}
+	if len(result) == 0 {
+		return nil, redis.Nil
+	}
+	data := &service.OrganizationMemberCacheData{}
+	if v, ok := result[orgMemberFieldLimit]; ok {
+		data.MonthlyLimit, _ = strconv.ParseFloat(v, 64)
+	}
+	if v, ok := result[orgMemberFieldUsage]; ok {
+		data.MonthlyUsage, _ = strconv.ParseFloat(v, 64)
+	}
+	if v, ok := result[orgMemberFieldWindow]; ok {
+		data.Window, _ = strconv.ParseInt(v, 10, 64)
+	}
+	return data, nil
+}
+
+func (c *billingCache) SetOrganizationMember(ctx context.Context, orgID, userID int64, data *service.OrganizationMemberCacheData) error {
+	if data == nil {
+		return nil
+	}
+	key := billingOrgMemberKey(orgID, userID)
+	fields := map[string]any{
+		orgMemberFieldLimit:  data.MonthlyLimit,
+		orgMemberFieldUsage:  data.MonthlyUsage,
+		orgMemberFieldWindow: data.Window,
+	}
+	pipe := c.rdb.Pipeline()
+	pipe.HSet(ctx, key, fields)
+	pipe.Expire(ctx, key, jitteredTTL())
+	_, err := pipe.Exec(ctx)
+	return err
+}
+
+func (c *billingCache) UpdateOrganizationMemberUsage(ctx context.Context, orgID, userID int64, cost float64, window int64) error {
+	key := billingOrgMemberKey(orgID, userID)
+	_, err := updateOrgMemberUsageScript.Run(ctx, c.rdb, []string{key}, cost, window, int(jitteredTTL().Seconds())).Result()
+	if err != nil && !errors.Is(err, redis.Nil) {
+		log.Printf("Warning: update organization member usage cache failed for org %d user %d: %v", orgID, userID, err)
+		return err
+	}
+	return nil
+}
+
+func (c *billingCache) InvalidateOrganizationMember(ctx context.Context, orgID, userID int64) error {
+	return c.rdb.Del(ctx, billingOrgMemberKey(orgID, userID)).Err()
+}
@@ -156,6 +156,24 @@ func (s *ConcurrencyCacheSuite) TestUserSlot_TTL() {
 	s.AssertTTLWithin(ttl, 1*time.Second, testSlotTTL)
 }
 
+func (s *ConcurrencyCacheSuite) TestAPIKeySlot_AcquireAndRelease() {
+	apiKeyID := int64(77)
+	slotKey := fmt.Sprintf("%s%d", apiKeySlotKeyPrefix, apiKeyID)
+
+	ok, err := s.cache.AcquireAPIKeySlot(s.ctx, apiKeyID, 1, "req1")
+	require.NoError(s.T(), err, "AcquireAPIKeySlot")
+	require.True(s.T(), ok)
+
+	ok, err = s.cache.AcquireAPIKeySlot(s.ctx, apiKeyID, 1, "req2")
+	require.NoError(s.T(), err, "AcquireAPIKeySlot 2")
+	require.False(s.T(), ok, "expected second acquire to fail at max=1")
+
+	require.NoError(s.T(), s.cache.ReleaseAPIKeySlot(s.ctx, apiKeyID, "req1"), "ReleaseAPIKeySlot")
+	card, err := s.rdb.ZCard(s.ctx, slotKey).Result()
+	require.NoError(s.T(), err, "ZCard")
+	require.Equal(s.T(), int64(0), card, "expected no slots after release")
+}
+
 func (s *ConcurrencyCacheSuite) TestWaitQueue_IncrementAndDecrement() {
 	userID := int64(20)
 	waitKey := fmt.Sprintf("%s%d", waitQueueKeyPrefix, userID)
@@ -0,0 +1,526 @@
+package repository
+
+import (
+	"context"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/securitysecret"
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+const (
+	// security_secrets 中的凭证密钥：credential_key:<id> 存储 hex 密钥，credential_key_active 存储当前密钥 ID
+	credentialKeySecretPrefix = "credential_key:"
+	credentialActiveKeySecret = "credential_key_active"
+
+	// 其它实例轮换密钥后，最迟在该间隔内开始使用新密钥加密
+	credentialKeyRingRefreshInterval = time.Minute
+	// 遇到未知密钥 ID 时按需重新加载的最小间隔
+	credentialKeyRingMissReloadInterval = 10 * time.Second
+	credentialKeyRingLoadTimeout        = 5 * time.Second
+
+	credentialDEKAdditionalData = "sub2api:credential-dek"
+)
+
+// credentialKeyRing 账号凭证信封加密：每个敏感字段使用随机数据密钥（DEK）以 AES-256-GCM 加密，
+// DEK 再由密钥环中的主密钥（KEK）封装。轮换主密钥只需重新封装 DEK。
+type credentialKeyRing struct {
+	client       *ent.Client
+	enabled      bool
+	pinnedActive string
+	configKeys   map[string][]byte
+
+	mu           sync.RWMutex
+	keys         map[string][]byte
+	active       string
+	loadedAt     time.Time
+	missReloadAt time.Time
+}
+
+// NewCredentialKeyRing 创建凭证密钥环：合并配置中的密钥与 security_secrets 中的密钥；
+// 启用加密且没有可用密钥时自动生成并持久化一个密钥。
+func NewCredentialKeyRing(client *ent.Client, cfg *config.Config) (service.CredentialKeyRing, error) {
+	configKeys, err := cfg.CredentialEncryption.CredentialEncryptionKeys()
+	if err != nil {
+		return nil, err
+	}
+	ring := &credentialKeyRing{
+		client:       client,
+		enabled:      cfg.CredentialEncryption.Enabled,
+		pinnedActive: strings.TrimSpace(cfg.CredentialEncryption.ActiveKeyID),
+		configKeys:   configKeys,
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), credentialKeyRingLoadTimeout)
+	defer cancel()
+	if err := ring.Reload(ctx); err != nil {
+		return nil, fmt.Errorf("load credential keys: %w", err)
+	}
+	if ring.enabled && ring.ActiveKeyID() == "" {
+		if err := ring.bootstrapActiveKey(ctx); err != nil {
+			return nil, fmt.Errorf("bootstrap credential key: %w", err)
+		}
+	}
+	return ring, nil
+}
+
+func (r *credentialKeyRing) Enabled() bool {
+	return r.enabled
+}
+
+func (r *credentialKeyRing) ActiveKeyID() string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.active
+}
+
+func (r *credentialKeyRing) KeyIDs() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	out := make([]string, 0, len(r.keys))
+	for id := range r.keys {
+		out = append(out, id)
+	}
+	sort.Strings(out)
+	return out
+}
+
+// Reload 重新读取配置与数据库中的密钥。当前密钥优先级：
+// 配置 active_key_id > 数据库 credential_key_active > 唯一的配置密钥。
+func (r *credentialKeyRing) Reload(ctx context.Context) error {
+	keys := make(map[string][]byte, len(r.configKeys))
+	for id, key := range r.configKeys {
+		keys[id] = key
+	}
+	active := r.pinnedActive
+
+	if r.client != nil {
+		secrets, err := r.client.SecuritySecret.Query().
+			Where(securitysecret.Or(
+				securitysecret.KeyHasPrefix(credentialKeySecretPrefix),
+				securitysecret.KeyEQ(credentialActiveKeySecret),
+			)).
+			All(ctx)
+		if err != nil {
+			return err
+		}
+		dbActive := ""
+		for _, secret := range secrets {
+			if secret.Key == credentialActiveKeySecret {
+				dbActive = strings.TrimSpace(secret.Value)
+				continue
+			}
+			id := strings.TrimPrefix(secret.Key, credentialKeySecretPrefix)
+			key, err := hex.DecodeString(strings.TrimSpace(secret.Value))
+			if err != nil || len(key) != 32 {
+				logger.LegacyPrintf("repository.credential_key_ring", "Skip invalid stored credential key %q", id)
+				continue
+			}
+			if existing, ok := keys[id]; ok {
+				if string(existing) != string(key) {
+					return fmt.Errorf("credential key %q in config differs from security_secrets", id)
+				}
+				continue
+			}
+			keys[id] = key
+		}
+		if active == "" {
+			active = dbActive
+		}
+	}
+	if active == "" && len(r.configKeys) == 1 {
+		for id := range r.configKeys {
+			active = id
+		}
+	}
+	if active != "" {
+		if _, ok := keys[active]; !ok {
+			return fmt.Errorf("active credential key %q not found", active)
+		}
+	}
+
+	r.mu.Lock()
+	r.keys = keys
+	r.active = active
+	r.loadedAt = time.Now()
+	r.mu.Unlock()
+	return nil
+}
+
+// bootstrapActiveKey 首次启用时生成密钥；多实例同时启动时以先写入者为准
+func (r *credentialKeyRing) bootstrapActiveKey(ctx context.Context) error {
+	if r.client == nil {
+		return errors.New("no credential key configured")
+	}
+	id, err := r.createStoredKey(ctx)
+	if err != nil {
+		return err
+	}
+	if err := r.client.SecuritySecret.Create().
+		SetKey(credentialActiveKeySecret).
+		SetValue(id).
+		OnConflictColumns(securitysecret.FieldKey).
+		DoNothing().
+		Exec(ctx); err != nil && !isSQLNoRowsError(err) {
+		return err
+	}
+	if err := r.Reload(ctx); err != nil {
+		return err
+	}
+	if r.ActiveKeyID() == id {
+		logger.LegacyPrintf("repository.credential_key_ring", "Warning: credential encryption key %s auto-generated and stored in security_secrets; configure credential_encryption.keys to keep keys outside the database.", id)
+	}
+	return nil
+}
+
+// Rotate 生成新密钥并设为当前密钥；旧密钥保留用于解密
+func (r *credentialKeyRing) Rotate(ctx context.Context) (string, error) {
+	if r.pinnedActive != "" {
+		return "", service.ErrCredentialKeyPinned
+	}
+	if r.client == nil {
+		return "", errors.New("credential key storage unavailable")
+	}
+	id, err := r.createStoredKey(ctx)
+	if err != nil {
+		return "", err
+	}
+	if err := r.client.SecuritySecret.Create().
+		SetKey(credentialActiveKeySecret).
+		SetValue(id).
+		OnConflictColumns(securitysecret.FieldKey).
+		UpdateNewValues().
+		Exec(ctx); err != nil {
+		return "", err
+	}
+	if err := r.Reload(ctx); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+func (r *credentialKeyRing) createStoredKey(ctx context.Context) (string, error) {
+	suffix, err := generateHexSecret(3)
+	if err != nil {
+		return "", err
+	}
+	id := "k" + time.Now().UTC().Format("20060102150405") + "-" + suffix
+	value, err := generateHexSecret(32)
+	if err != nil {
+		return "", err
+	}
+	if err := r.client.SecuritySecret.Create().
+		SetKey(credentialKeySecretPrefix + id).
+		SetValue(value).
+		Exec(ctx); err != nil {
+		return "", err
+	}
+	return id, nil
+}
+
+// refreshIfStale 定期同步其它实例的轮换结果，失败时沿用当前密钥
+func (r *credentialKeyRing) refreshIfStale() {
+	r.mu.RLock()
+	stale := r.client != nil && time.Since(r.loadedAt) > credentialKeyRingRefreshInterval
+	r.mu.RUnlock()
+	if !stale {
+		return
+	}
+	ctx, cancel := context.WithTimeout(context.Background(), credentialKeyRingLoadTimeout)
+	defer cancel()
+	if err := r.Reload(ctx); err != nil {
+		r.mu.Lock()
+		r.loadedAt = time.Now()
+		r.mu.Unlock()
+		logger.LegacyPrintf("repository.credential_key_ring", "Refresh credential keys failed: %v", err)
+	}
+}
+
+// lookupKey 获取主密钥；未知 ID 时按需重新加载（其它实例刚轮换）
+func (r *credentialKeyRing) lookupKey(id string) ([]byte, bool) {
+	r.mu.RLock()
+	key, ok := r.keys[id]
+	canReload := r.client != nil && time.Since(r.missReloadAt) > credentialKeyRingMissReloadInterval
+	r.mu.RUnlock()
+	if ok || !canReload {
+		return key, ok
+	}
+
+	r.mu.Lock()
+	r.missReloadAt = time.Now()
+	r.mu.Unlock()
+	ctx, cancel := context.WithTimeout(context.Background(), credentialKeyRingLoadTimeout)
+	defer cancel()
+	if err := r.Reload(ctx); err != nil {
+		logger.LegacyPrintf("repository.credential_key_ring", "Reload credential keys failed: %v", err)
+		return nil, false
+	}
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok = r.keys[id]
+	return key, ok
+}
+
+func (r *credentialKeyRing) activeKey() (string, []byte, error) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	key, ok := r.keys[r.active]
+	if r.active == "" || !ok {
+		return "", nil, service.ErrCredentialKeyNotFound
+	}
+	return r.active, key, nil
+}
+
+func (r *credentialKeyRing) EncryptCredentials(credentials map[string]any) (map[string]any, error) {
+	out := copyJSONMap(credentials)
+	if !r.enabled || len(out) == 0 {
+		return out, nil
+	}
+	r.refreshIfStale()
+	keyID, kek, err := r.activeKey()
+	if err != nil {
+		return nil, err
+	}
+	for field, value := range out {
+		if !needsCredentialEncryption(field, value) {
+			continue
+		}
+		sealed, err := sealCredentialValue(keyID, kek, field, value)
+		if err != nil {
+			return nil, fmt.Errorf("encrypt credential %s: %w", field, err)
+		}
+		out[field] = sealed
+	}
+	return out, nil
+}
+
+func (r *credentialKeyRing) DecryptCredentials(credentials map[string]any) (map[string]any, error) {
+	out := copyJSONMap(credentials)
+	var errs []error
+	for field, value := range out {
+		sealed, ok := value.(string)
+		if !ok || !service.IsEncryptedCredentialValue(sealed) {
+			continue
+		}
+		plain, err := r.openCredentialValue(field, sealed)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("decrypt credential %s: %w", field, err))
+			continue
+		}
+		out[field] = plain
+	}
+	return out, errors.Join(errs...)
+}
+
+func (r *credentialKeyRing) ReencryptCredentials(credentials map[string]any) (map[string]any, bool, error) {
+	out := copyJSONMap(credentials)
+	changed := false
+	activeID, activeKEK, activeErr := r.activeKey()
+	for field, value := range out {
+		sealed, isString := value.(string)
+		encrypted := isString && service.IsEncryptedCredentialValue(sealed)
+
+		switch {
+		case !r.enabled && encrypted:
+			plain, err := r.openCredentialValue(field, sealed)
+			if err != nil {
+				return nil, false, fmt.Errorf("decrypt credential %s: %w", field, err)
+			}
+			out[field] = plain
+			changed = true
+		case r.enabled && encrypted:
+			if activeErr != nil {
+				return nil, false, activeErr
+			}
+			rewrapped, rewrappedChanged, err := r.rewrapCredentialValue(sealed, activeID, activeKEK)
+			if err != nil {
+				return nil, false, fmt.Errorf("rewrap credential %s: %w", field, err)
+			}
+			if rewrappedChanged {
+				out[field] = rewrapped
+				changed = true
+			}
+		case r.enabled && needsCredentialEncryption(field, value):
+			if activeErr != nil {
+				return nil, false, activeErr
+			}
+			sealedValue, err := sealCredentialValue(activeID, activeKEK, field, value)
+			if err != nil {
+				return nil, false, fmt.Errorf("encrypt credential %s: %w", field, err)
+			}
+			out[field] = sealedValue
+			changed = true
+		}
+	}
+	return out, changed, nil
+}
+
+func (r *credentialKeyRing) CredentialKeyID(value string) (string, bool) {
+	parts, err := splitCredentialCiphertext(value)
+	if err != nil {
+		return "", false
+	}
+	return parts.keyID, true
+}
+
+func (r *credentialKeyRing) openCredentialValue(field, sealed string) (any, error) {
+	parts, err := splitCredentialCiphertext(sealed)
+	if err != nil {
+		return nil, err
+	}
+	kek, ok := r.lookupKey(parts.keyID)
+	if !ok {
+		return nil, fmt.Errorf("%w: %s", service.ErrCredentialKeyNotFound, parts.keyID)
+	}
+	dek, err := aesGCMOpen(kek, parts.wrappedDEK, []byte(credentialDEKAdditionalData))
+	if err != nil {
+		return nil, err
+	}
+	plaintext, err := aesGCMOpen(dek, parts.payload, []byte(field))
+	if err != nil {
+		return nil, err
+	}
+	var value any
+	if err := json.Unmarshal(plaintext, &value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
+
+// rewrapCredentialValue 用当前主密钥重新封装 DEK，字段密文本身不变
+func (r *credentialKeyRing) rewrapCredentialValue(sealed, activeID string, activeKEK []byte) (string, bool, error) {
+	parts, err := splitCredentialCiphertext(sealed)
+	if err != nil {
+		return "", false, err
+	}
+	if parts.keyID == activeID {
+		return sealed, false, nil
+	}
+	kek, ok := r.lookupKey(parts.keyID)
+	if !ok {
+		return "", false, fmt.Errorf("%w: %s", service.ErrCredentialKeyNotFound, parts.keyID)
+	}
+	dek, err := aesGCMOpen(kek, parts.wrappedDEK, []byte(credentialDEKAdditionalData))
+	if err != nil {
+		return "", false, err
+	}
+	wrapped, err := aesGCMSeal(activeKEK, dek, []byte(credentialDEKAdditionalData))
+	if err != nil {
+		return "", false, err
+	}
+	return formatCredentialCiphertext(activeID, wrapped, parts.payload), true, nil
+}
+
+func needsCredentialEncryption(field string, value any) bool {
+	if !service.IsSensitiveCredentialField(field) || value == nil || service.IsEncryptedCredentialValue(value) {
+		return false
+	}
+	if s, ok := value.(string); ok && s == "" {
+		return false
+	}
+	return true
+}
+
+func sealCredentialValue(keyID string, kek []byte, field string, value any) (string, error) {
+	plaintext, err := json.Marshal(value)
+	if err != nil {
+		return "", err
+	}
+	dek := make([]byte, 32)
+	if _, err := io.ReadFull(rand.Reader, dek); err != nil {
+		return "", fmt.Errorf("generate data key: %w", err)
+	}
+	payload, err := aesGCMSeal(dek, plaintext, []byte(field))
+	if err != nil {
+		return "", err
+	}
+	wrapped, err := aesGCMSeal(kek, dek, []byte(credentialDEKAdditionalData))
+	if err != nil {
+		return "", err
+	}
+	return formatCredentialCiphertext(keyID, wrapped, payload), nil
+}
+
+type credentialCiphertext struct {
+	keyID      string
+	wrappedDEK []byte
+	payload    []byte
+}
+
+func formatCredentialCiphertext(keyID string, wrappedDEK, payload []byte) string {
+	return service.CredentialCiphertextPrefix + keyID + ":" +
+		base64.StdEncoding.EncodeToString(wrappedDEK) + ":" +
+		base64.StdEncoding.EncodeToString(payload)
+}
+
+func splitCredentialCiphertext(value string) (*credentialCiphertext, error) {
+	rest, ok := strings.CutPrefix(value, service.CredentialCiphertextPrefix)
+	if !ok {
+		return nil, service.ErrCredentialCiphertextFormat
+	}
+	parts := strings.Split(rest, ":")
+	if len(parts) != 3 || parts[0] == "" {
+		return nil, service.ErrCredentialCiphertextFormat
+	}
+	wrapped, err := base64.StdEncoding.DecodeString(parts[1])
+	if err != nil {
+		return nil, service.ErrCredentialCiphertextFormat
+	}
+	payload, err := base64.StdEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, service.ErrCredentialCiphertextFormat
+	}
+	return &credentialCiphertext{keyID: parts[0], wrappedDEK: wrapped, payload: payload}, nil
+}
+
+// aesGCMSeal 输出 nonce + ciphertext + tag
+func aesGCMSeal(key, plaintext, additionalData []byte) ([]byte, error) {
+	gcm, err := newCredentialGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	nonce := make([]byte, gcm.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return nil, fmt.Errorf("generate nonce: %w", err)
+	}
+	return gcm.Seal(nonce, nonce, plaintext, additionalData), nil
+}
+
+func aesGCMOpen(key, data, additionalData []byte) ([]byte, error) {
+	gcm, err := newCredentialGCM(key)
+	if err != nil {
+		return nil, err
+	}
+	if len(data) < gcm.NonceSize() {
+		return nil, service.ErrCredentialCiphertextFormat
+	}
+	nonce, ciphertext := data[:gcm.NonceSize()], data[gcm.NonceSize():]
+	plaintext, err := gcm.Open(nil, nonce, ciphertext, additionalData)
+	if err != nil {
+		return nil, fmt.Errorf("decrypt: %w", err)
+	}
+	return plaintext, nil
+}
+
+func newCredentialGCM(key []byte) (cipher.AEAD, error) {
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("create cipher: %w", err)
+	}
+	return cipher.NewGCM(block)
+}
@@ -0,0 +1,155 @@
+//go:build unit
+
+package repository
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestCredentialKeyRing(t *testing.T, enabled bool, keys, active string) *credentialKeyRing {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.CredentialEncryption = config.CredentialEncryptionConfig{Enabled: enabled, Keys: keys, ActiveKeyID: active}
+	ring, err := NewCredentialKeyRing(nil, cfg)
+	require.NoError(t, err)
+	return ring.(*credentialKeyRing)
+}
+
+var (
+	testCredentialKey1 = "k1:" + strings.Repeat("11", 32)
+	testCredentialKey2 = "k2:" + strings.Repeat("22", 32)
+)
+
+func TestCredentialKeyRing_EncryptDecryptRoundTrip(t *testing.T) {
+	ring := newTestCredentialKeyRing(t, true, testCredentialKey1, "")
+	require.Equal(t, "k1", ring.ActiveKeyID())
+
+	plain := map[string]any{
+		"access_token":  "at-secret",
+		"refresh_token": "rt-secret",
+		"project_id":    "proj-1",
+		"expires_at":    float64(1700000000),
+		"api_key":       "",
+	}
+	encrypted, err := ring.EncryptCredentials(plain)
+	require.NoError(t, err)
+	require.Equal(t, "at-secret", plain["access_token"], "input must not be mutated")
+
+	require.True(t, strings.HasPrefix(encrypted["access_token"].(string), "enc:v1:k1:"))
+	require.True(t, service.IsEncryptedCredentialValue(encrypted["refresh_token"]))
+	require.Equal(t, "proj-1", encrypted["project_id"])
+	require.Equal(t, float64(1700000000), encrypted["expires_at"])
+	require.Equal(t, "", encrypted["api_key"])
+
+	// 已加密的值不重复加密
+	again, err := ring.EncryptCredentials(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, encrypted["access_token"], again["access_token"])
+
+	decrypted, err := ring.DecryptCredentials(encrypted)
+	require.NoError(t, err)
+	require.Equal(t, plain, decrypted)
+}
+
+func TestCredentialKeyRing_FieldBoundCiphertext(t *testing.T) {
+	ring := newTestCredentialKeyRing(t, true, testCredentialKey1, "")
+	encrypted, err := ring.EncryptCredentials(map[string]any{"access_token": "at", "refresh_token": "rt"})
+	require.NoError(t, err)
+
+	// 密文与字段名绑定，交换字段后无法解密且保留密文
+	swapped := map[string]any{"access_token": encrypted["refresh_token"]}
+	out, err := ring.DecryptCredentials(swapped)
+	require.Error(t, err)
+	require.Equal(t, swapped["access_token"], out["access_token"])
+}
+
+func TestCredentialKeyRing_RotationRewrapsWithActiveKey(t *testing.T) {
+	oldRing := newTestCredentialKeyRing(t, true, testCredentialKey1, "")
+	encrypted, err := oldRing.EncryptCredentials(map[string]any{"api_key": "sk-1", "base_url": "https://x"})
+	require.NoError(t, err)
+
+	ring := newTestCredentialKeyRing(t, true, testCredentialKey1+","+testCredentialKey2, "k2")
+	rotated, changed, err := ring.ReencryptCredentials(encrypted)
+	require.NoError(t, err)
+	require.True(t, changed)
+	keyID, ok := ring.CredentialKeyID(rotated["api_key"].(string))
+	require.True(t, ok)
+	require.Equal(t, "k2", keyID)
+
+	// 只重新封装 DEK，字段密文不变
+	oldParts, err := splitCredentialCiphertext(encrypted["api_key"].(string))
+	require.NoError(t, err)
+	newParts, err := splitCredentialCiphertext(rotated["api_key"].(string))
+	require.NoError(t, err)
+	require.True(t, bytes.Equal(oldParts.payload, newParts.payload))
+
+	_, changed, err = ring.ReencryptCredentials(rotated)
+	require.NoError(t, err)
+	require.False(t, changed)
+
+	onlyNew := newTestCredentialKeyRing(t, true, testCredentialKey2, "")
+	decrypted, err := onlyNew.DecryptCredentials(rotated)
+	require.NoError(t, err)
+	require.Equal(t, "sk-1", decrypted["api_key"])
+}
+
+func TestCredentialKeyRing_ReencryptPlaintextAndDisable(t *testing.T) {
+	ring := newTestCredentialKeyRing(t, true, testCredentialKey1, "")
+	encrypted, changed, err := ring.ReencryptCredentials(map[string]any{"session_key": "sess", "email": "a@b.c"})
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.True(t, service.IsEncryptedCredentialValue(encrypted["session_key"]))
+	require.Equal(t, "a@b.c", encrypted["email"])
+
+	// 关闭加密后：新写入保持明文，已加密数据仍可解密，重加密会还原为明文
+	disabled := newTestCredentialKeyRing(t, false, testCredentialKey1, "")
+	written, err := disabled.EncryptCredentials(map[string]any{"session_key": "new"})
+	require.NoError(t, err)
+	require.Equal(t, "new", written["session_key"])
+
+	plain, changed, err := disabled.ReencryptCredentials(encrypted)
+	require.NoError(t, err)
+	require.True(t, changed)
+	require.Equal(t, "sess", plain["session_key"])
+}
+
+func TestCredentialKeyRing_UnknownKey(t *testing.T) {
+	ring := newTestCredentialKeyRing(t, true, testCredentialKey1, "")
+	encrypted, err := ring.EncryptCredentials(map[string]any{"api_key": "sk"})
+	require.NoError(t, err)
+
+	other := newTestCredentialKeyRing(t, true, testCredentialKey2, "")
+	_, err = other.DecryptCredentials(encrypted)
+	require.ErrorIs(t, err, service.ErrCredentialKeyNotFound)
+	_, err = other.Rotate(t.Context())
+	require.Error(t, err)
+}
+
+func TestNewCredentialKeyRing_ConfigValidation(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.CredentialEncryption = config.CredentialEncryptionConfig{Enabled: true}
+	_, err := NewCredentialKeyRing(nil, cfg)
+	require.Error(t, err, "enabled without keys and without storage")
+
+	cfg.CredentialEncryption = config.CredentialEncryptionConfig{Enabled: true, Keys: "k1:abcd"}
+	_, err = NewCredentialKeyRing(nil, cfg)
+	require.Error(t, err)
+
+	cfg.CredentialEncryption = config.CredentialEncryptionConfig{Enabled: true, Keys: testCredentialKey1, ActiveKeyID: "k9"}
+	_, err = NewCredentialKeyRing(nil, cfg)
+	require.Error(t, err)
+
+	// 关闭且无密钥时允许启动（明文模式）
+	cfg.CredentialEncryption = config.CredentialEncryptionConfig{}
+	ring, err := NewCredentialKeyRing(nil, cfg)
+	require.NoError(t, err)
+	out, err := ring.EncryptCredentials(map[string]any{"api_key": "sk"})
+	require.NoError(t, err)
+	require.Equal(t, "sk", out["api_key"])
+}
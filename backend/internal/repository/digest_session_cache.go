@@ -0,0 +1,106 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+// 摘要会话 Redis 缓存
+//
+// 设计说明：
+// 与内存版 DigestSessionStore 使用相同的 flat key 语义，多副本部署时共享会话到账号的映射：
+//   - Key: digest_session:{groupID:prefixHash}|{digestChain}
+//   - Value: "{accountID}|{uuid}"
+//   - TTL: 写入时设置，查找不续期（与内存版一致）
+//
+// 同一 namespace 的 key 使用 hash tag 落在同一 slot，
+// Find 用一次 MGET 取出全部前缀候选（兼容 Redis Cluster，不会 CROSSSLOT）。
+const digestSessionKeyPrefix = "digest_session:"
+
+type digestSessionCache struct {
+	rdb *redis.Client
+	ttl time.Duration
+}
+
+// NewDigestSessionCache 创建 Redis 摘要会话缓存
+func NewDigestSessionCache(rdb *redis.Client, ttl time.Duration) service.DigestSessionCache {
+	if ttl <= 0 {
+		ttl = 5 * time.Minute
+	}
+	return &digestSessionCache{rdb: rdb, ttl: ttl}
+}
+
+// digestSessionNS 构建 namespace 前缀（含 hash tag）
+func digestSessionNS(groupID int64, prefixHash string) string {
+	return digestSessionKeyPrefix + "{" + strconv.FormatInt(groupID, 10) + ":" + prefixHash + "}|"
+}
+
+func encodeDigestSessionValue(uuid string, accountID int64) string {
+	return strconv.FormatInt(accountID, 10) + "|" + uuid
+}
+
+func decodeDigestSessionValue(raw string) (uuid string, accountID int64, ok bool) {
+	idPart, uuidPart, found := strings.Cut(raw, "|")
+	if !found {
+		return "", 0, false
+	}
+	id, err := strconv.ParseInt(idPart, 10, 64)
+	if err != nil {
+		return "", 0, false
+	}
+	return uuidPart, id, true
+}
+
+func (c *digestSessionCache) FindDigestSession(ctx context.Context, groupID int64, prefixHash, digestChain string) (string, int64, string, bool, error) {
+	chains := service.DigestChainPrefixes(digestChain)
+	if len(chains) == 0 {
+		return "", 0, "", false, nil
+	}
+	ns := digestSessionNS(groupID, prefixHash)
+	keys := make([]string, len(chains))
+	for i, chain := range chains {
+		keys[i] = ns + chain
+	}
+
+	values, err := c.rdb.MGet(ctx, keys...).Result()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return "", 0, "", false, nil
+		}
+		return "", 0, "", false, fmt.Errorf("digest session find: %w", err)
+	}
+	// 顺序与 chains 一致（最长在前），第一个命中即最长匹配
+	for i, v := range values {
+		raw, ok := v.(string)
+		if !ok {
+			continue
+		}
+		if uuid, accountID, ok := decodeDigestSessionValue(raw); ok {
+			return uuid, accountID, chains[i], true, nil
+		}
+	}
+	return "", 0, "", false, nil
+}
+
+func (c *digestSessionCache) SaveDigestSession(ctx context.Context, groupID int64, prefixHash, digestChain, uuid string, accountID int64, oldDigestChain string) error {
+	if digestChain == "" {
+		return nil
+	}
+	ns := digestSessionNS(groupID, prefixHash)
+	pipe := c.rdb.TxPipeline()
+	pipe.Set(ctx, ns+digestChain, encodeDigestSessionValue(uuid, accountID), c.ttl)
+	if oldDigestChain != "" && oldDigestChain != digestChain {
+		pipe.Del(ctx, ns+oldDigestChain)
+	}
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("digest session save: %w", err)
+	}
+	return nil
+}
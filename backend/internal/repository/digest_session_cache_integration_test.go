@@ -0,0 +1,125 @@
+//go:build integration
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+	"github.com/stretchr/testify/suite"
+)
+
+type DigestSessionCacheSuite struct {
+	IntegrationRedisSuite
+	cache service.DigestSessionCache
+}
+
+func (s *DigestSessionCacheSuite) SetupTest() {
+	s.IntegrationRedisSuite.SetupTest()
+	s.cache = NewDigestSessionCache(s.rdb, time.Minute)
+}
+
+func (s *DigestSessionCacheSuite) TestSaveAndFind() {
+	s.RequireNoError(s.cache.SaveDigestSession(s.ctx, 1, "prefix", "s:a1-u:b2-m:c3", "uuid-1", 100, ""))
+
+	uuid, accountID, matched, found, err := s.cache.FindDigestSession(s.ctx, 1, "prefix", "s:a1-u:b2-m:c3")
+	s.RequireNoError(err)
+	require.True(s.T(), found)
+	require.Equal(s.T(), "uuid-1", uuid)
+	require.Equal(s.T(), int64(100), accountID)
+	require.Equal(s.T(), "s:a1-u:b2-m:c3", matched)
+}
+
+func (s *DigestSessionCacheSuite) TestLongestPrefixMatch() {
+	s.RequireNoError(s.cache.SaveDigestSession(s.ctx, 1, "prefix", "u:a", "uuid-1", 1, ""))
+	s.RequireNoError(s.cache.SaveDigestSession(s.ctx, 1, "prefix", "u:a-m:b", "uuid-2", 2, ""))
+	s.RequireNoError(s.cache.SaveDigestSession(s.ctx, 1, "prefix", "u:a-m:b-u:c", "uuid-3", 3, ""))
+
+	uuid, accountID, matched, found, err := s.cache.FindDigestSession(s.ctx, 1, "prefix", "u:a-m:b-u:c-m:d-u:e")
+	s.RequireNoError(err)
+	require.True(s.T(), found)
+	require.Equal(s.T(), "uuid-3", uuid)
+	require.Equal(s.T(), int64(3), accountID)
+	require.Equal(s.T(), "u:a-m:b-u:c", matched)
+
+	uuid, accountID, matched, found, err = s.cache.FindDigestSession(s.ctx, 1, "prefix", "u:a-m:b-u:x")
+	s.RequireNoError(err)
+	require.True(s.T(), found)
+	require.Equal(s.T(), "uuid-2", uuid)
+	require.Equal(s.T(), int64(2), accountID)
+	require.Equal(s.T(), "u:a-m:b", matched)
+
+	// 更短的链不会匹配更长的 key
+	_, _, _, found, err = s.cache.FindDigestSession(s.ctx, 1, "prefix", "u:x")
+	s.RequireNoError(err)
+	require.False(s.T(), found)
+}
+
+func (s *DigestSessionCacheSuite) TestSaveDeletesOldChain() {
+	s.RequireNoError(s.cache.SaveDigestSession(s.ctx, 1, "prefix", "u:a-m:b", "uuid-1", 100, ""))
+	_, _, matched, found, err := s.cache.FindDigestSession(s.ctx, 1, "prefix", "u:a-m:b-u:c-m:d")
+	s.RequireNoError(err)
+	require.True(s.T(), found)
+
+	s.RequireNoError(s.cache.SaveDigestSession(s.ctx, 1, "prefix", "u:a-m:b-u:c-m:d", "uuid-1", 100, matched))
+
+	exists, err := s.rdb.Exists(s.ctx, digestSessionNS(1, "prefix")+"u:a-m:b").Result()
+	s.RequireNoError(err)
+	require.Zero(s.T(), exists, "old chain key should be removed")
+
+	_, _, matched, found, err = s.cache.FindDigestSession(s.ctx, 1, "prefix", "u:a-m:b-u:c-m:d-u:e")
+	s.RequireNoError(err)
+	require.True(s.T(), found)
+	require.Equal(s.T(), "u:a-m:b-u:c-m:d", matched)
+}
+
+func (s *DigestSessionCacheSuite) TestNamespaceIsolation() {
+	s.RequireNoError(s.cache.SaveDigestSession(s.ctx, 1, "prefix-a", "u:a-m:b", "uuid-1", 1, ""))
+
+	_, _, _, found, err := s.cache.FindDigestSession(s.ctx, 2, "prefix-a", "u:a-m:b")
+	s.RequireNoError(err)
+	require.False(s.T(), found, "different group")
+
+	_, _, _, found, err = s.cache.FindDigestSession(s.ctx, 1, "prefix-b", "u:a-m:b")
+	s.RequireNoError(err)
+	require.False(s.T(), found, "different prefix hash")
+}
+
+func (s *DigestSessionCacheSuite) TestTTL() {
+	s.RequireNoError(s.cache.SaveDigestSession(s.ctx, 1, "prefix", "u:a", "uuid-1", 1, ""))
+
+	ttl, err := s.rdb.TTL(s.ctx, digestSessionNS(1, "prefix")+"u:a").Result()
+	s.RequireNoError(err)
+	s.AssertTTLWithin(ttl, 1*time.Second, time.Minute)
+}
+
+func (s *DigestSessionCacheSuite) TestSharedAcrossInstances() {
+	// 两个副本各自持有 cache 实例，共享同一 Redis
+	replicaA := NewDigestSessionCache(s.rdb, time.Minute)
+	replicaB := NewDigestSessionCache(s.rdb, time.Minute)
+
+	s.RequireNoError(replicaA.SaveDigestSession(s.ctx, 7, "prefix", "u:a-m:b", "uuid-1", 42, ""))
+	uuid, accountID, _, found, err := replicaB.FindDigestSession(s.ctx, 7, "prefix", "u:a-m:b-u:c")
+	s.RequireNoError(err)
+	require.True(s.T(), found)
+	require.Equal(s.T(), "uuid-1", uuid)
+	require.Equal(s.T(), int64(42), accountID)
+}
+
+func (s *DigestSessionCacheSuite) TestCorruptedValueIsIgnored() {
+	ns := digestSessionNS(1, "prefix")
+	s.RequireNoError(s.rdb.Set(s.ctx, ns+"u:a-m:b", "garbage", time.Minute).Err())
+	s.RequireNoError(s.cache.SaveDigestSession(s.ctx, 1, "prefix", "u:a", "uuid-1", 1, ""))
+
+	uuid, _, matched, found, err := s.cache.FindDigestSession(s.ctx, 1, "prefix", "u:a-m:b")
+	s.RequireNoError(err)
+	require.True(s.T(), found)
+	require.Equal(s.T(), "uuid-1", uuid)
+	require.Equal(s.T(), "u:a", matched)
+}
+
+func TestDigestSessionCacheSuite(t *testing.T) {
+	suite.Run(t, new(DigestSessionCacheSuite))
+}
@@ -0,0 +1,42 @@
+//go:build unit
+
+package repository
+
+import (
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+)
+
+func TestDigestSessionNS_UsesHashTag(t *testing.T) {
+	require.Equal(t, "digest_session:{12:abc}|", digestSessionNS(12, "abc"))
+}
+
+func TestDigestSessionValueRoundTrip(t *testing.T) {
+	raw := encodeDigestSessionValue("uuid|with|pipes", 42)
+	uuid, accountID, ok := decodeDigestSessionValue(raw)
+	require.True(t, ok)
+	require.Equal(t, "uuid|with|pipes", uuid)
+	require.Equal(t, int64(42), accountID)
+
+	_, _, ok = decodeDigestSessionValue("garbage")
+	require.False(t, ok)
+	_, _, ok = decodeDigestSessionValue("x|uuid")
+	require.False(t, ok)
+}
+
+func TestProvideDigestSessionCache_SelectsStore(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Gateway.DigestSession.TTLSeconds = 60
+
+	_, isMemory := ProvideDigestSessionCache(nil, cfg).(*service.DigestSessionStore)
+	require.True(t, isMemory, "memory is the default")
+
+	cfg.Gateway.DigestSession.Store = "Redis"
+	cache, isRedis := ProvideDigestSessionCache(nil, cfg).(*digestSessionCache)
+	require.True(t, isRedis)
+	require.Equal(t, time.Minute, cache.ttl)
+}
@@ -73,6 +73,12 @@ func InitEnt(cfg *config.Config) (*ent.Client, *sql.DB, error) {
 		return nil, nil, err
 	}
 
+	// 历史明文 API Key 迁移为哈希存储（依赖上一步得到的哈希密钥）。
+	if err := ensureAPIKeyHashes(migrationCtx, drv.DB(), cfg.Security.APIKeyHashSecret); err != nil {
+		_ = client.Close()
+		return nil, nil, err
+	}
+
 	// 在密钥补齐后执行完整配置校验，避免空 jwt.secret 导致服务运行时失败。
 	if err := cfg.Validate(); err != nil {
 		_ = client.Close()
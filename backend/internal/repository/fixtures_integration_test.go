@@ -243,6 +243,11 @@ func mustCreateAccount(t *testing.T, client *dbent.Client, a *service.Account) *
 	return a
 }
 
+// testAPIKeyHash 使用空 HMAC 密钥计算测试用 Key 哈希
+func testAPIKeyHash(key string) string {
+	return service.HashAPIKey("", key)
+}
+
 func mustCreateApiKey(t *testing.T, client *dbent.Client, k *service.APIKey) *service.APIKey {
 	t.Helper()
 	ctx := context.Background()
@@ -253,13 +258,18 @@ func mustCreateApiKey(t *testing.T, client *dbent.Client, k *service.APIKey) *se
 	if k.Key == "" {
 		k.Key = "sk-" + time.Now().Format("150405.000000")
 	}
+	if k.KeyHash == "" {
+		k.KeyHash = testAPIKeyHash(k.Key)
+		k.KeyPrefix = service.APIKeyDisplayPrefix(k.Key)
+	}
 	if k.Name == "" {
 		k.Name = "default"
 	}
 
 	create := client.APIKey.Create().
 		SetUserID(k.UserID).
-		SetKey(k.Key).
+		SetKeyHash(k.KeyHash).
+		SetKeyPrefix(k.KeyPrefix).
 		SetName(k.Name).
 		SetStatus(k.Status)
 	if k.GroupID != nil {
@@ -64,7 +64,14 @@ func (r *groupRepository) Create(ctx context.Context, groupIn *service.Group) er
 		SetMcpXMLInject(groupIn.MCPXMLInject).
 		SetSoraStorageQuotaBytes(groupIn.SoraStorageQuotaBytes).
 		SetAllowMessagesDispatch(groupIn.AllowMessagesDispatch).
-		SetDefaultMappedModel(groupIn.DefaultMappedModel)
+		SetDefaultMappedModel(groupIn.DefaultMappedModel).
+		SetResponseCacheEnabled(groupIn.ResponseCacheEnabled).
+		SetResponseCacheTTLSeconds(groupIn.ResponseCacheTTLSeconds).
+		SetResponseCachePriceRatio(groupIn.ResponseCachePriceRatio)
+
+	if len(groupIn.ModelFallbackChains) > 0 {
+		builder = builder.SetModelFallbackChains(groupIn.ModelFallbackChains)
+	}
 
 	// 设置模型路由配置
 	if groupIn.ModelRouting != nil {
@@ -135,7 +142,10 @@ func (r *groupRepository) Update(ctx context.Context, groupIn *service.Group) er
 		SetMcpXMLInject(groupIn.MCPXMLInject).
 		SetSoraStorageQuotaBytes(groupIn.SoraStorageQuotaBytes).
 		SetAllowMessagesDispatch(groupIn.AllowMessagesDispatch).
-		SetDefaultMappedModel(groupIn.DefaultMappedModel)
+		SetDefaultMappedModel(groupIn.DefaultMappedModel).
+		SetResponseCacheEnabled(groupIn.ResponseCacheEnabled).
+		SetResponseCacheTTLSeconds(groupIn.ResponseCacheTTLSeconds).
+		SetResponseCachePriceRatio(groupIn.ResponseCachePriceRatio)
 
 	// 显式处理可空字段：nil 需要 clear，非 nil 需要 set。
 	if groupIn.DailyLimitUSD != nil {
@@ -189,6 +199,13 @@ func (r *groupRepository) Update(ctx context.Context, groupIn *service.Group) er
 		builder = builder.ClearModelRouting()
 	}
 
+	// 处理 ModelFallbackChains：空时清除
+	if len(groupIn.ModelFallbackChains) > 0 {
+		builder = builder.SetModelFallbackChains(groupIn.ModelFallbackChains)
+	} else {
+		builder = builder.ClearModelFallbackChains()
+	}
+
 	// 处理 SupportedModelScopes（始终设置，空数组表示不限制）
 	builder = builder.SetSupportedModelScopes(groupIn.SupportedModelScopes)
 
@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// messageBatchRepository 实现 service.MessageBatchRepository 接口。
+// 使用原生 SQL 操作 message_batches / message_batch_items 表。
+type messageBatchRepository struct {
+	sql *sql.DB
+}
+
+// NewMessageBatchRepository 创建 Message Batches 仓储实例。
+func NewMessageBatchRepository(sqlDB *sql.DB) service.MessageBatchRepository {
+	return &messageBatchRepository{sql: sqlDB}
+}
+
+// messageBatchInsertChunk 单条 INSERT 写入的批内请求数，避免超出参数个数上限
+const messageBatchInsertChunk = 1000
+
+// messageBatchSelect 批次列与按状态实时统计的请求数
+const messageBatchSelect = `
+	SELECT b.id, b.user_id, b.api_key_id, b.group_id, b.processing_status, b.anthropic_beta, b.expires_at,
+		b.cancel_initiated_at, b.ended_at, b.created_at,
+		c.processing, c.succeeded, c.errored, c.canceled, c.expired
+	FROM message_batches b
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) FILTER (WHERE i.status = 'pending') AS processing,
+			COUNT(*) FILTER (WHERE i.status = 'succeeded') AS succeeded,
+			COUNT(*) FILTER (WHERE i.status = 'errored') AS errored,
+			COUNT(*) FILTER (WHERE i.status = 'canceled') AS canceled,
+			COUNT(*) FILTER (WHERE i.status = 'expired') AS expired
+		FROM message_batch_items i
+		WHERE i.batch_id = b.id
+	) c`
+
+const messageBatchItemColumns = `id, batch_id, custom_id, params, status, attempts, next_attempt_at, result`
+
+func (r *messageBatchRepository) Create(ctx context.Context, batch *service.MessageBatch, items []service.MessageBatchItem) error {
+	tx, err := r.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if err := tx.QueryRowContext(ctx, `
+		INSERT INTO message_batches (id, user_id, api_key_id, group_id, processing_status, anthropic_beta, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING created_at
+	`, batch.ID, batch.UserID, batch.APIKeyID, batch.GroupID, batch.ProcessingStatus, batch.AnthropicBeta,
+		batch.ExpiresAt, batch.CreatedAt,
+	).Scan(&batch.CreatedAt); err != nil {
+		return err
+	}
+
+	for start := 0; start < len(items); start += messageBatchInsertChunk {
+		end := min(start+messageBatchInsertChunk, len(items))
+		const cols = 4
+		placeholders := make([]string, 0, end-start)
+		args := make([]any, 0, (end-start)*cols)
+		for i, item := range items[start:end] {
+			base := i * cols
+			placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d)", base+1, base+2, base+3, base+4))
+			args = append(args, batch.ID, item.CustomID, []byte(item.Params), item.Status)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO message_batch_items (batch_id, custom_id, params, status)
+			VALUES `+strings.Join(placeholders, ", "), args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *messageBatchRepository) GetByID(ctx context.Context, id string) (*service.MessageBatch, error) {
+	batch, err := scanMessageBatch(r.sql.QueryRowContext(ctx, messageBatchSelect+` WHERE b.id = $1`, id))
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrMessageBatchNotFound, nil)
+	}
+	return batch, nil
+}
+
+func (r *messageBatchRepository) List(ctx context.Context, userID int64, params service.MessageBatchListParams) ([]service.MessageBatch, bool, error) {
+	var (
+		query string
+		args  []any
+	)
+	// 多取一条用于判断 has_more
+	limit := params.Limit + 1
+	switch {
+	case params.AfterID != "":
+		query = messageBatchSelect + `
+			WHERE b.user_id = $1 AND b.seq < (SELECT seq FROM message_batches WHERE id = $2 AND user_id = $1)
+			ORDER BY b.seq DESC
+			LIMIT $3`
+		args = []any{userID, params.AfterID, limit}
+	case params.BeforeID != "":
+		query = messageBatchSelect + `
+			WHERE b.user_id = $1 AND b.seq > (SELECT seq FROM message_batches WHERE id = $2 AND user_id = $1)
+			ORDER BY b.seq ASC
+			LIMIT $3`
+		args = []any{userID, params.BeforeID, limit}
+	default:
+		query = messageBatchSelect + `
+			WHERE b.user_id = $1
+			ORDER BY b.seq DESC
+			LIMIT $2`
+		args = []any{userID, limit}
+	}
+
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	batches := make([]service.MessageBatch, 0, params.Limit)
+	for rows.Next() {
+		batch, err := scanMessageBatch(rows)
+		if err != nil {
+			return nil, false, err
+		}
+		batches = append(batches, *batch)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, false, err
+	}
+
+	hasMore := len(batches) > params.Limit
+	if hasMore {
+		batches = batches[:params.Limit]
+	}
+	if params.BeforeID != "" {
+		// before_id 按升序读取离游标最近的一页，返回前恢复为倒序
+		for i, j := 0, len(batches)-1; i < j; i, j = i+1, j-1 {
+			batches[i], batches[j] = batches[j], batches[i]
+		}
+	}
+	return batches, hasMore, nil
+}
+
+func (r *messageBatchRepository) RequestCancel(ctx context.Context, id string) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE message_batches
+		SET processing_status = $2, cancel_initiated_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND processing_status = $3
+	`, id, service.MessageBatchStatusCanceling, service.MessageBatchStatusInProgress)
+	return err
+}
+
+func (r *messageBatchRepository) ListItems(ctx context.Context, batchID string, afterID int64, limit int) ([]service.MessageBatchItem, error) {
+	return r.queryItems(ctx, `
+		SELECT `+messageBatchItemColumns+`
+		FROM message_batch_items
+		WHERE batch_id = $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3
+	`, batchID, afterID, limit)
+}
+
+func (r *messageBatchRepository) ClaimDueItems(ctx context.Context, limit int, lease time.Duration) ([]service.MessageBatchItem, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	return r.queryItems(ctx, `
+		WITH due AS (
+			SELECT i.id
+			FROM message_batch_items i
+			JOIN message_batches b ON b.id = i.batch_id
+			WHERE i.status = $1 AND i.next_attempt_at <= NOW()
+				AND b.processing_status = $2 AND b.expires_at > NOW()
+			ORDER BY i.next_attempt_at ASC, i.id ASC
+			LIMIT $3
+			FOR UPDATE OF i SKIP LOCKED
+		)
+		UPDATE message_batch_items AS i
+		SET attempts = i.attempts + 1,
+			next_attempt_at = NOW() + ($4 * interval '1 second'),
+			updated_at = NOW()
+		FROM due
+		WHERE i.id = due.id
+		RETURNING `+prefixedColumns(messageBatchItemColumns, "i"),
+		service.MessageBatchItemPending, service.MessageBatchStatusInProgress, limit, int64(lease.Seconds()))
+}
+
+func (r *messageBatchRepository) SaveItemResult(ctx context.Context, item *service.MessageBatchItem) error {
+	var result any
+	if len(item.Result) > 0 {
+		result = []byte(item.Result)
+	}
+	// 仅更新仍为 pending 的请求：期间被取消/过期的请求保持终态
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE message_batch_items
+		SET status = $2, attempts = $3, next_attempt_at = $4, result = $5, updated_at = NOW()
+		WHERE id = $1 AND status = $6
+	`, item.ID, item.Status, item.Attempts, item.NextAttemptAt, result, service.MessageBatchItemPending)
+	return err
+}
+
+func (r *messageBatchRepository) FinalizeBatches(ctx context.Context) (int, error) {
+	// 执行中的请求 next_attempt_at 处于租约期内，不会被取消或过期
+	if _, err := r.sql.ExecContext(ctx, `
+		UPDATE message_batch_items AS i
+		SET status = $1, updated_at = NOW()
+		FROM message_batches b
+		WHERE b.id = i.batch_id AND b.processing_status = $2
+			AND i.status = $3 AND i.next_attempt_at <= NOW()
+	`, service.MessageBatchItemCanceled, service.MessageBatchStatusCanceling, service.MessageBatchItemPending); err != nil {
+		return 0, err
+	}
+	if _, err := r.sql.ExecContext(ctx, `
+		UPDATE message_batch_items AS i
+		SET status = $1, updated_at = NOW()
+		FROM message_batches b
+		WHERE b.id = i.batch_id AND b.processing_status <> $2 AND b.expires_at <= NOW()
+			AND i.status = $3 AND i.next_attempt_at <= NOW()
+	`, service.MessageBatchItemExpired, service.MessageBatchStatusEnded, service.MessageBatchItemPending); err != nil {
+		return 0, err
+	}
+	res, err := r.sql.ExecContext(ctx, `
+		UPDATE message_batches AS b
+		SET processing_status = $1, ended_at = NOW(), updated_at = NOW()
+		WHERE b.processing_status <> $1
+			AND NOT EXISTS (SELECT 1 FROM message_batch_items i WHERE i.batch_id = b.id AND i.status = $2)
+	`, service.MessageBatchStatusEnded, service.MessageBatchItemPending)
+	if err != nil {
+		return 0, err
+	}
+	ended, err := res.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	return int(ended), nil
+}
+
+func (r *messageBatchRepository) DeleteEndedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.sql.ExecContext(ctx, `
+		DELETE FROM message_batches WHERE processing_status = $1 AND ended_at < $2
+	`, service.MessageBatchStatusEnded, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *messageBatchRepository) queryItems(ctx context.Context, query string, args ...any) ([]service.MessageBatchItem, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	items := make([]service.MessageBatchItem, 0)
+	for rows.Next() {
+		item, err := scanMessageBatchItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+// prefixedColumns 为逗号分隔的列名加上表别名前缀
+func prefixedColumns(columns, alias string) string {
+	cols := strings.Split(columns, ",")
+	for i, col := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(col)
+	}
+	return strings.Join(cols, ", ")
+}
+
+func scanMessageBatch(scanner interface{ Scan(...any) error }) (*service.MessageBatch, error) {
+	var (
+		batch             service.MessageBatch
+		groupID           sql.NullInt64
+		cancelInitiatedAt sql.NullTime
+		endedAt           sql.NullTime
+	)
+	if err := scanner.Scan(
+		&batch.ID,
+		&batch.UserID,
+		&batch.APIKeyID,
+		&groupID,
+		&batch.ProcessingStatus,
+		&batch.AnthropicBeta,
+		&batch.ExpiresAt,
+		&cancelInitiatedAt,
+		&endedAt,
+		&batch.CreatedAt,
+		&batch.RequestCounts.Processing,
+		&batch.RequestCounts.Succeeded,
+		&batch.RequestCounts.Errored,
+		&batch.RequestCounts.Canceled,
+		&batch.RequestCounts.Expired,
+	); err != nil {
+		return nil, err
+	}
+	if groupID.Valid {
+		batch.GroupID = &groupID.Int64
+	}
+	if cancelInitiatedAt.Valid {
+		batch.CancelInitiatedAt = &cancelInitiatedAt.Time
+	}
+	if endedAt.Valid {
+		batch.EndedAt = &endedAt.Time
+	}
+	return &batch, nil
+}
+
+func scanMessageBatchItem(scanner interface{ Scan(...any) error }) (*service.MessageBatchItem, error) {
+	var (
+		item   service.MessageBatchItem
+		params []byte
+		result []byte
+	)
+	if err := scanner.Scan(
+		&item.ID,
+		&item.BatchID,
+		&item.CustomID,
+		&params,
+		&item.Status,
+		&item.Attempts,
+		&item.NextAttemptAt,
+		&result,
+	); err != nil {
+		return nil, err
+	}
+	item.Params = params
+	if len(result) > 0 {
+		item.Result = result
+	}
+	return &item, nil
+}
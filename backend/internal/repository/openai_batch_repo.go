@@ -0,0 +1,502 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+// openAIBatchRepository 实现 service.OpenAIBatchRepository 接口。
+// 使用原生 SQL 操作 openai_batches / openai_batch_items 表。
+type openAIBatchRepository struct {
+	sql *sql.DB
+}
+
+// NewOpenAIBatchRepository 创建 OpenAI Batch 仓储实例。
+func NewOpenAIBatchRepository(sqlDB *sql.DB) service.OpenAIBatchRepository {
+	return &openAIBatchRepository{sql: sqlDB}
+}
+
+// openAIBatchInsertChunk 单条 INSERT 写入的批内请求数，避免超出参数个数上限
+const openAIBatchInsertChunk = 1000
+
+// openAIBatchSelect 批次列与按状态实时统计的请求数；
+// failed 包含写入错误文件的全部请求（失败、取消与过期）
+const openAIBatchSelect = `
+	SELECT b.id, b.user_id, b.api_key_id, b.group_id, b.endpoint, b.input_file_id, b.completion_window, b.status,
+		b.output_file_id, b.error_file_id, b.errors, b.metadata, b.expires_at,
+		b.in_progress_at, b.finalizing_at, b.completed_at, b.failed_at, b.expired_at, b.cancelling_at, b.cancelled_at,
+		b.created_at,
+		c.total, c.completed, c.failed
+	FROM openai_batches b
+	CROSS JOIN LATERAL (
+		SELECT COUNT(*) AS total,
+			COUNT(*) FILTER (WHERE i.status = 'completed') AS completed,
+			COUNT(*) FILTER (WHERE i.status IN ('failed', 'cancelled', 'expired')) AS failed
+		FROM openai_batch_items i
+		WHERE i.batch_id = b.id
+	) c`
+
+const openAIBatchItemColumns = `id, batch_id, line_no, custom_id, body, status, attempts, next_attempt_at, output`
+
+func (r *openAIBatchRepository) Create(ctx context.Context, batch *service.OpenAIBatch) error {
+	var metadata any
+	if len(batch.Metadata) > 0 {
+		raw, err := json.Marshal(batch.Metadata)
+		if err != nil {
+			return err
+		}
+		metadata = raw
+	}
+	return r.sql.QueryRowContext(ctx, `
+		INSERT INTO openai_batches (id, user_id, api_key_id, group_id, endpoint, input_file_id, completion_window,
+			status, metadata, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING created_at
+	`, batch.ID, batch.UserID, batch.APIKeyID, batch.GroupID, batch.Endpoint, batch.InputFileID, batch.CompletionWindow,
+		batch.Status, metadata, batch.ExpiresAt, batch.CreatedAt,
+	).Scan(&batch.CreatedAt)
+}
+
+func (r *openAIBatchRepository) GetByID(ctx context.Context, id string) (*service.OpenAIBatch, error) {
+	batch, err := scanOpenAIBatch(r.sql.QueryRowContext(ctx, openAIBatchSelect+` WHERE b.id = $1`, id))
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOpenAIBatchNotFound, nil)
+	}
+	return batch, nil
+}
+
+func (r *openAIBatchRepository) List(ctx context.Context, userID int64, params service.OpenAIBatchListParams) ([]service.OpenAIBatch, bool, error) {
+	var (
+		query string
+		args  []any
+	)
+	// 多取一条用于判断 has_more
+	limit := params.Limit + 1
+	if params.After != "" {
+		query = openAIBatchSelect + `
+			WHERE b.user_id = $1 AND b.seq < (SELECT seq FROM openai_batches WHERE id = $2 AND user_id = $1)
+			ORDER BY b.seq DESC
+			LIMIT $3`
+		args = []any{userID, params.After, limit}
+	} else {
+		query = openAIBatchSelect + `
+			WHERE b.user_id = $1
+			ORDER BY b.seq DESC
+			LIMIT $2`
+		args = []any{userID, limit}
+	}
+
+	batches, err := r.queryBatches(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	hasMore := len(batches) > params.Limit
+	if hasMore {
+		batches = batches[:params.Limit]
+	}
+	return batches, hasMore, nil
+}
+
+func (r *openAIBatchRepository) RequestCancel(ctx context.Context, id string) error {
+	tx, err := r.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE openai_batches
+		SET status = $2, cancelling_at = NOW(), locked_until = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status IN ($3, $4)
+	`, id, service.OpenAIBatchStatusCancelling, service.OpenAIBatchStatusValidating, service.OpenAIBatchStatusInProgress)
+	if err != nil {
+		return err
+	}
+	if n, err := res.RowsAffected(); err != nil || n == 0 {
+		return err
+	}
+	// 执行中的请求 next_attempt_at 处于租约期内，完成后由 AdvanceBatches 继续处理
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE openai_batch_items
+		SET status = $2, updated_at = NOW()
+		WHERE batch_id = $1 AND status = $3 AND next_attempt_at <= NOW()
+	`, id, service.OpenAIBatchItemCancelled, service.OpenAIBatchItemPending); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *openAIBatchRepository) ListItems(ctx context.Context, batchID string, afterID int64, limit int) ([]service.OpenAIBatchItem, error) {
+	return r.queryItems(ctx, `
+		SELECT `+openAIBatchItemColumns+`
+		FROM openai_batch_items
+		WHERE batch_id = $1 AND id > $2
+		ORDER BY id ASC
+		LIMIT $3
+	`, batchID, afterID, limit)
+}
+
+func (r *openAIBatchRepository) ClaimValidating(ctx context.Context, limit int, lease time.Duration) ([]service.OpenAIBatch, error) {
+	return r.claimBatches(ctx, `
+		SELECT id FROM openai_batches
+		WHERE status = $1 AND locked_until <= NOW()
+		ORDER BY seq ASC
+		LIMIT $2
+		FOR UPDATE SKIP LOCKED
+	`, lease, service.OpenAIBatchStatusValidating, limit)
+}
+
+func (r *openAIBatchRepository) StartBatch(ctx context.Context, id string, items []service.OpenAIBatchItem) (bool, error) {
+	tx, err := r.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	res, err := tx.ExecContext(ctx, `
+		UPDATE openai_batches
+		SET status = $2, in_progress_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $3
+	`, id, service.OpenAIBatchStatusInProgress, service.OpenAIBatchStatusValidating)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	if err != nil || n == 0 {
+		return false, err
+	}
+
+	for start := 0; start < len(items); start += openAIBatchInsertChunk {
+		end := min(start+openAIBatchInsertChunk, len(items))
+		const cols = 5
+		placeholders := make([]string, 0, end-start)
+		args := make([]any, 0, (end-start)*cols)
+		for i, item := range items[start:end] {
+			base := i * cols
+			placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d)", base+1, base+2, base+3, base+4, base+5))
+			args = append(args, id, item.LineNo, item.CustomID, []byte(item.Body), item.Status)
+		}
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO openai_batch_items (batch_id, line_no, custom_id, body, status)
+			VALUES `+strings.Join(placeholders, ", "), args...); err != nil {
+			return false, err
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+func (r *openAIBatchRepository) FailBatch(ctx context.Context, id string, errs []service.OpenAIBatchError) error {
+	raw, err := json.Marshal(errs)
+	if err != nil {
+		return err
+	}
+	_, err = r.sql.ExecContext(ctx, `
+		UPDATE openai_batches
+		SET status = $2, errors = $3, failed_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $4
+	`, id, service.OpenAIBatchStatusFailed, raw, service.OpenAIBatchStatusValidating)
+	return err
+}
+
+func (r *openAIBatchRepository) ClaimDueItems(ctx context.Context, limit int, lease time.Duration) ([]service.OpenAIBatchItem, error) {
+	if limit <= 0 {
+		limit = 20
+	}
+	return r.queryItems(ctx, `
+		WITH due AS (
+			SELECT i.id
+			FROM openai_batch_items i
+			JOIN openai_batches b ON b.id = i.batch_id
+			WHERE i.status = $1 AND i.next_attempt_at <= NOW()
+				AND b.status = $2 AND b.expires_at > NOW()
+			ORDER BY i.next_attempt_at ASC, i.id ASC
+			LIMIT $3
+			FOR UPDATE OF i SKIP LOCKED
+		)
+		UPDATE openai_batch_items AS i
+		SET attempts = i.attempts + 1,
+			next_attempt_at = NOW() + ($4 * interval '1 second'),
+			updated_at = NOW()
+		FROM due
+		WHERE i.id = due.id
+		RETURNING `+prefixedColumns(openAIBatchItemColumns, "i"),
+		service.OpenAIBatchItemPending, service.OpenAIBatchStatusInProgress, limit, int64(lease.Seconds()))
+}
+
+func (r *openAIBatchRepository) SaveItemResult(ctx context.Context, item *service.OpenAIBatchItem) error {
+	var output any
+	if len(item.Output) > 0 {
+		output = []byte(item.Output)
+	}
+	// 仅更新仍为 pending 的请求：期间被取消/过期的请求保持终态
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE openai_batch_items
+		SET status = $2, attempts = $3, next_attempt_at = $4, output = $5, updated_at = NOW()
+		WHERE id = $1 AND status = $6
+	`, item.ID, item.Status, item.Attempts, item.NextAttemptAt, output, service.OpenAIBatchItemPending)
+	return err
+}
+
+func (r *openAIBatchRepository) AdvanceBatches(ctx context.Context) error {
+	// 执行中的请求 next_attempt_at 处于租约期内，不会被取消或过期
+	if _, err := r.sql.ExecContext(ctx, `
+		UPDATE openai_batch_items AS i
+		SET status = $1, updated_at = NOW()
+		FROM openai_batches b
+		WHERE b.id = i.batch_id AND b.status = $2
+			AND i.status = $3 AND i.next_attempt_at <= NOW()
+	`, service.OpenAIBatchItemCancelled, service.OpenAIBatchStatusCancelling, service.OpenAIBatchItemPending); err != nil {
+		return err
+	}
+	if _, err := r.sql.ExecContext(ctx, `
+		UPDATE openai_batch_items AS i
+		SET status = $1, updated_at = NOW()
+		FROM openai_batches b
+		WHERE b.id = i.batch_id AND b.status = $2 AND b.expires_at <= NOW()
+			AND i.status = $3 AND i.next_attempt_at <= NOW()
+	`, service.OpenAIBatchItemExpired, service.OpenAIBatchStatusInProgress, service.OpenAIBatchItemPending); err != nil {
+		return err
+	}
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE openai_batches AS b
+		SET status = $1, finalizing_at = NOW(), locked_until = NOW(), updated_at = NOW()
+		WHERE b.status = $2
+			AND NOT EXISTS (SELECT 1 FROM openai_batch_items i WHERE i.batch_id = b.id AND i.status = $3)
+	`, service.OpenAIBatchStatusFinalizing, service.OpenAIBatchStatusInProgress, service.OpenAIBatchItemPending)
+	return err
+}
+
+func (r *openAIBatchRepository) ClaimClosable(ctx context.Context, limit int, lease time.Duration) ([]service.OpenAIBatch, error) {
+	return r.claimBatches(ctx, `
+		SELECT b.id FROM openai_batches b
+		WHERE b.locked_until <= NOW()
+			AND (b.status = $1 OR (b.status = $2
+				AND NOT EXISTS (SELECT 1 FROM openai_batch_items i WHERE i.batch_id = b.id AND i.status = $3)))
+		ORDER BY b.seq ASC
+		LIMIT $4
+		FOR UPDATE OF b SKIP LOCKED
+	`, lease, service.OpenAIBatchStatusFinalizing, service.OpenAIBatchStatusCancelling, service.OpenAIBatchItemPending, limit)
+}
+
+func (r *openAIBatchRepository) CloseBatch(ctx context.Context, batch *service.OpenAIBatch, fromStatus string) error {
+	res, err := r.sql.ExecContext(ctx, `
+		UPDATE openai_batches
+		SET status = $2, output_file_id = $3, error_file_id = $4,
+			completed_at = CASE WHEN $2 = $6 THEN NOW() ELSE completed_at END,
+			expired_at = CASE WHEN $2 = $7 THEN NOW() ELSE expired_at END,
+			cancelled_at = CASE WHEN $2 = $8 THEN NOW() ELSE cancelled_at END,
+			updated_at = NOW()
+		WHERE id = $1 AND status = $5
+	`, batch.ID, batch.Status, batch.OutputFileID, batch.ErrorFileID, fromStatus,
+		service.OpenAIBatchStatusCompleted, service.OpenAIBatchStatusExpired, service.OpenAIBatchStatusCancelled)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return fmt.Errorf("openai batch %s is no longer %s", batch.ID, fromStatus)
+	}
+	return nil
+}
+
+func (r *openAIBatchRepository) DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error) {
+	res, err := r.sql.ExecContext(ctx, `
+		DELETE FROM openai_batches WHERE status IN ($1, $2, $3, $4) AND updated_at < $5
+	`, service.OpenAIBatchStatusCompleted, service.OpenAIBatchStatusFailed, service.OpenAIBatchStatusExpired,
+		service.OpenAIBatchStatusCancelled, cutoff)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+// claimBatches 以 selectIDs（FOR UPDATE SKIP LOCKED）选出批次并将 locked_until 后延 lease，返回批次详情
+func (r *openAIBatchRepository) claimBatches(ctx context.Context, selectIDs string, lease time.Duration, args ...any) ([]service.OpenAIBatch, error) {
+	leaseArg := len(args) + 1
+	rows, err := r.sql.QueryContext(ctx, `
+		WITH due AS (`+selectIDs+`)
+		UPDATE openai_batches AS b
+		SET locked_until = NOW() + ($`+fmt.Sprint(leaseArg)+` * interval '1 second'), updated_at = NOW()
+		FROM due
+		WHERE b.id = due.id
+		RETURNING b.id
+	`, append(args, int64(lease.Seconds()))...)
+	if err != nil {
+		return nil, err
+	}
+	ids := make([]string, 0)
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	_ = rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(ids) == 0 {
+		return nil, nil
+	}
+	return r.queryBatches(ctx, openAIBatchSelect+` WHERE b.id = ANY($1) ORDER BY b.seq ASC`, pq.Array(ids))
+}
+
+func (r *openAIBatchRepository) queryBatches(ctx context.Context, query string, args ...any) ([]service.OpenAIBatch, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	batches := make([]service.OpenAIBatch, 0)
+	for rows.Next() {
+		batch, err := scanOpenAIBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, *batch)
+	}
+	return batches, rows.Err()
+}
+
+func (r *openAIBatchRepository) queryItems(ctx context.Context, query string, args ...any) ([]service.OpenAIBatchItem, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	items := make([]service.OpenAIBatchItem, 0)
+	for rows.Next() {
+		item, err := scanOpenAIBatchItem(rows)
+		if err != nil {
+			return nil, err
+		}
+		items = append(items, *item)
+	}
+	return items, rows.Err()
+}
+
+func scanOpenAIBatch(scanner interface{ Scan(...any) error }) (*service.OpenAIBatch, error) {
+	var (
+		batch        service.OpenAIBatch
+		groupID      sql.NullInt64
+		outputFileID sql.NullString
+		errorFileID  sql.NullString
+		errs         []byte
+		metadata     []byte
+		inProgressAt sql.NullTime
+		finalizingAt sql.NullTime
+		completedAt  sql.NullTime
+		failedAt     sql.NullTime
+		expiredAt    sql.NullTime
+		cancellingAt sql.NullTime
+		cancelledAt  sql.NullTime
+	)
+	if err := scanner.Scan(
+		&batch.ID,
+		&batch.UserID,
+		&batch.APIKeyID,
+		&groupID,
+		&batch.Endpoint,
+		&batch.InputFileID,
+		&batch.CompletionWindow,
+		&batch.Status,
+		&outputFileID,
+		&errorFileID,
+		&errs,
+		&metadata,
+		&batch.ExpiresAt,
+		&inProgressAt,
+		&finalizingAt,
+		&completedAt,
+		&failedAt,
+		&expiredAt,
+		&cancellingAt,
+		&cancelledAt,
+		&batch.CreatedAt,
+		&batch.RequestCounts.Total,
+		&batch.RequestCounts.Completed,
+		&batch.RequestCounts.Failed,
+	); err != nil {
+		return nil, err
+	}
+	if groupID.Valid {
+		batch.GroupID = &groupID.Int64
+	}
+	if outputFileID.Valid {
+		batch.OutputFileID = &outputFileID.String
+	}
+	if errorFileID.Valid {
+		batch.ErrorFileID = &errorFileID.String
+	}
+	if len(errs) > 0 {
+		if err := json.Unmarshal(errs, &batch.Errors); err != nil {
+			return nil, fmt.Errorf("decode batch errors: %w", err)
+		}
+	}
+	if len(metadata) > 0 {
+		if err := json.Unmarshal(metadata, &batch.Metadata); err != nil {
+			return nil, fmt.Errorf("decode batch metadata: %w", err)
+		}
+	}
+	for _, ts := range []struct {
+		src sql.NullTime
+		dst **time.Time
+	}{
+		{inProgressAt, &batch.InProgressAt},
+		{finalizingAt, &batch.FinalizingAt},
+		{completedAt, &batch.CompletedAt},
+		{failedAt, &batch.FailedAt},
+		{expiredAt, &batch.ExpiredAt},
+		{cancellingAt, &batch.CancellingAt},
+		{cancelledAt, &batch.CancelledAt},
+	} {
+		if ts.src.Valid {
+			t := ts.src.Time
+			*ts.dst = &t
+		}
+	}
+	return &batch, nil
+}
+
+func scanOpenAIBatchItem(scanner interface{ Scan(...any) error }) (*service.OpenAIBatchItem, error) {
+	var (
+		item   service.OpenAIBatchItem
+		body   []byte
+		output []byte
+	)
+	if err := scanner.Scan(
+		&item.ID,
+		&item.BatchID,
+		&item.LineNo,
+		&item.CustomID,
+		&body,
+		&item.Status,
+		&item.Attempts,
+		&item.NextAttemptAt,
+		&output,
+	); err != nil {
+		return nil, err
+	}
+	item.Body = body
+	if len(output) > 0 {
+		item.Output = output
+	}
+	return &item, nil
+}
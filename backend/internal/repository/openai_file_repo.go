@@ -0,0 +1,122 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// openAIFileRepository 实现 service.OpenAIFileRepository 接口。
+// 使用原生 SQL 操作 openai_files 表。
+type openAIFileRepository struct {
+	sql *sql.DB
+}
+
+// NewOpenAIFileRepository 创建 OpenAI Files 仓储实例。
+func NewOpenAIFileRepository(sqlDB *sql.DB) service.OpenAIFileRepository {
+	return &openAIFileRepository{sql: sqlDB}
+}
+
+const openAIFileColumns = `id, user_id, purpose, filename, bytes, storage, object_key, expires_at, created_at`
+
+func (r *openAIFileRepository) Create(ctx context.Context, file *service.OpenAIFile) error {
+	return r.sql.QueryRowContext(ctx, `
+		INSERT INTO openai_files (id, user_id, purpose, filename, bytes, storage, object_key, expires_at, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9)
+		RETURNING created_at
+	`, file.ID, file.UserID, file.Purpose, file.Filename, file.Bytes, file.Storage, file.ObjectKey,
+		file.ExpiresAt, file.CreatedAt,
+	).Scan(&file.CreatedAt)
+}
+
+func (r *openAIFileRepository) GetByID(ctx context.Context, id string) (*service.OpenAIFile, error) {
+	file, err := scanOpenAIFile(r.sql.QueryRowContext(ctx, `SELECT `+openAIFileColumns+` FROM openai_files WHERE id = $1`, id))
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOpenAIFileNotFound, nil)
+	}
+	return file, nil
+}
+
+func (r *openAIFileRepository) List(ctx context.Context, userID int64, params service.OpenAIFileListParams) ([]service.OpenAIFile, bool, error) {
+	order, cmp := "DESC", "<"
+	if params.Order == "asc" {
+		order, cmp = "ASC", ">"
+	}
+	query := `SELECT ` + openAIFileColumns + ` FROM openai_files WHERE user_id = $1`
+	args := []any{userID}
+	if params.Purpose != "" {
+		args = append(args, params.Purpose)
+		query += fmt.Sprintf(" AND purpose = $%d", len(args))
+	}
+	if params.After != "" {
+		args = append(args, params.After)
+		query += fmt.Sprintf(" AND seq %s (SELECT seq FROM openai_files WHERE id = $%d AND user_id = $1)", cmp, len(args))
+	}
+	// 多取一条用于判断 has_more
+	args = append(args, params.Limit+1)
+	query += fmt.Sprintf(" ORDER BY seq %s LIMIT $%d", order, len(args))
+
+	files, err := r.query(ctx, query, args...)
+	if err != nil {
+		return nil, false, err
+	}
+	hasMore := len(files) > params.Limit
+	if hasMore {
+		files = files[:params.Limit]
+	}
+	return files, hasMore, nil
+}
+
+func (r *openAIFileRepository) Delete(ctx context.Context, id string) error {
+	_, err := r.sql.ExecContext(ctx, `DELETE FROM openai_files WHERE id = $1`, id)
+	return err
+}
+
+func (r *openAIFileRepository) ListExpired(ctx context.Context, now time.Time, limit int) ([]service.OpenAIFile, error) {
+	return r.query(ctx, `
+		SELECT `+openAIFileColumns+`
+		FROM openai_files
+		WHERE expires_at <= $1
+		ORDER BY expires_at ASC
+		LIMIT $2
+	`, now, limit)
+}
+
+func (r *openAIFileRepository) query(ctx context.Context, query string, args ...any) ([]service.OpenAIFile, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	files := make([]service.OpenAIFile, 0)
+	for rows.Next() {
+		file, err := scanOpenAIFile(rows)
+		if err != nil {
+			return nil, err
+		}
+		files = append(files, *file)
+	}
+	return files, rows.Err()
+}
+
+func scanOpenAIFile(scanner interface{ Scan(...any) error }) (*service.OpenAIFile, error) {
+	var file service.OpenAIFile
+	if err := scanner.Scan(
+		&file.ID,
+		&file.UserID,
+		&file.Purpose,
+		&file.Filename,
+		&file.Bytes,
+		&file.Storage,
+		&file.ObjectKey,
+		&file.ExpiresAt,
+		&file.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &file, nil
+}
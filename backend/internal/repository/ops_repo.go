@@ -67,9 +67,10 @@ INSERT INTO ops_error_logs (
   request_headers,
   is_retryable,
   retry_count,
-  created_at
+  created_at,
+  trace_id
 ) VALUES (
-  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22,$23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38,$39
 ) RETURNING id`
 
 	var id int64
@@ -114,6 +115,7 @@ INSERT INTO ops_error_logs (
 		input.IsRetryable,
 		input.RetryCount,
 		input.CreatedAt,
+		opsNullString(input.TraceID),
 	).Scan(&id)
 	if err != nil {
 		return 0, err
@@ -172,6 +174,7 @@ SELECT
   e.resolved_retry_id,
   COALESCE(e.client_request_id, ''),
   COALESCE(e.request_id, ''),
+  COALESCE(e.trace_id, ''),
   COALESCE(e.error_message, ''),
   e.user_id,
   COALESCE(u.email, ''),
@@ -234,6 +237,7 @@ LIMIT $` + itoa(len(args)+1) + ` OFFSET $` + itoa(len(args)+2)
 			&resolvedRetryID,
 			&item.ClientRequestID,
 			&item.RequestID,
+			&item.TraceID,
 			&item.Message,
 			&userID,
 			&userEmail,
@@ -327,6 +331,7 @@ SELECT
   e.resolved_retry_id,
   COALESCE(e.client_request_id, ''),
   COALESCE(e.request_id, ''),
+  COALESCE(e.trace_id, ''),
   COALESCE(e.error_message, ''),
   COALESCE(e.error_body, ''),
   e.upstream_status_code,
@@ -398,6 +403,7 @@ LIMIT 1`
 		&resolvedRetryID,
 		&out.ClientRequestID,
 		&out.RequestID,
+		&out.TraceID,
 		&out.Message,
 		&out.ErrorBody,
 		&upstreamStatusCode,
@@ -1278,7 +1284,7 @@ func buildOpsErrorLogsWhere(filter *service.OpsErrorLogFilter) (string, []any) {
 		like := "%" + q + "%"
 		args = append(args, like)
 		n := itoa(len(args))
-		clauses = append(clauses, "(e.request_id ILIKE $"+n+" OR e.client_request_id ILIKE $"+n+" OR e.error_message ILIKE $"+n+")")
+		clauses = append(clauses, "(e.request_id ILIKE $"+n+" OR e.client_request_id ILIKE $"+n+" OR e.trace_id ILIKE $"+n+" OR e.error_message ILIKE $"+n+")")
 	}
 
 	if userQuery := strings.TrimSpace(filter.UserQuery); userQuery != "" {
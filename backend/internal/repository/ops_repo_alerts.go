@@ -30,6 +30,7 @@ SELECT
   sustained_minutes,
   cooldown_minutes,
   COALESCE(notify_email, true),
+  COALESCE(notify_channels, '[]'::jsonb),
   filters,
   last_triggered_at,
   created_at,
@@ -46,6 +47,7 @@ ORDER BY id DESC`
 	out := []*service.OpsAlertRule{}
 	for rows.Next() {
 		var rule service.OpsAlertRule
+		var channelsRaw []byte
 		var filtersRaw []byte
 		var lastTriggeredAt sql.NullTime
 		if err := rows.Scan(
@@ -61,6 +63,7 @@ ORDER BY id DESC`
 			&rule.SustainedMinutes,
 			&rule.CooldownMinutes,
 			&rule.NotifyEmail,
+			&channelsRaw,
 			&filtersRaw,
 			&lastTriggeredAt,
 			&rule.CreatedAt,
@@ -72,6 +75,7 @@ ORDER BY id DESC`
 			v := lastTriggeredAt.Time
 			rule.LastTriggeredAt = &v
 		}
+		rule.NotifyChannels = decodeOpsNotifyChannels(channelsRaw)
 		if len(filtersRaw) > 0 && string(filtersRaw) != "null" {
 			var decoded map[string]any
 			if err := json.Unmarshal(filtersRaw, &decoded); err == nil {
@@ -98,6 +102,10 @@ func (r *opsRepository) CreateAlertRule(ctx context.Context, input *service.OpsA
 	if err != nil {
 		return nil, err
 	}
+	channelsArg, err := opsJSONNotifyChannels(input.NotifyChannels)
+	if err != nil {
+		return nil, err
+	}
 
 	q := `
 INSERT INTO ops_alert_rules (
@@ -112,11 +120,12 @@ INSERT INTO ops_alert_rules (
   sustained_minutes,
   cooldown_minutes,
   notify_email,
+  notify_channels,
   filters,
   created_at,
   updated_at
 ) VALUES (
-  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,NOW(),NOW()
+  $1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,NOW(),NOW()
 )
 RETURNING
   id,
@@ -131,12 +140,14 @@ RETURNING
   sustained_minutes,
   cooldown_minutes,
   COALESCE(notify_email, true),
+  COALESCE(notify_channels, '[]'::jsonb),
   filters,
   last_triggered_at,
   created_at,
   updated_at`
 
 	var out service.OpsAlertRule
+	var channelsRaw []byte
 	var filtersRaw []byte
 	var lastTriggeredAt sql.NullTime
 
@@ -154,6 +165,7 @@ RETURNING
 		input.SustainedMinutes,
 		input.CooldownMinutes,
 		input.NotifyEmail,
+		channelsArg,
 		filtersArg,
 	).Scan(
 		&out.ID,
@@ -168,6 +180,7 @@ RETURNING
 		&out.SustainedMinutes,
 		&out.CooldownMinutes,
 		&out.NotifyEmail,
+		&channelsRaw,
 		&filtersRaw,
 		&lastTriggeredAt,
 		&out.CreatedAt,
@@ -179,6 +192,7 @@ RETURNING
 		v := lastTriggeredAt.Time
 		out.LastTriggeredAt = &v
 	}
+	out.NotifyChannels = decodeOpsNotifyChannels(channelsRaw)
 	if len(filtersRaw) > 0 && string(filtersRaw) != "null" {
 		var decoded map[string]any
 		if err := json.Unmarshal(filtersRaw, &decoded); err == nil {
@@ -204,6 +218,10 @@ func (r *opsRepository) UpdateAlertRule(ctx context.Context, input *service.OpsA
 	if err != nil {
 		return nil, err
 	}
+	channelsArg, err := opsJSONNotifyChannels(input.NotifyChannels)
+	if err != nil {
+		return nil, err
+	}
 
 	q := `
 UPDATE ops_alert_rules
@@ -219,7 +237,8 @@ SET
   sustained_minutes = $10,
   cooldown_minutes = $11,
   notify_email = $12,
-  filters = $13,
+  notify_channels = $13,
+  filters = $14,
   updated_at = NOW()
 WHERE id = $1
 RETURNING
@@ -235,12 +254,14 @@ RETURNING
   sustained_minutes,
   cooldown_minutes,
   COALESCE(notify_email, true),
+  COALESCE(notify_channels, '[]'::jsonb),
   filters,
   last_triggered_at,
   created_at,
   updated_at`
 
 	var out service.OpsAlertRule
+	var channelsRaw []byte
 	var filtersRaw []byte
 	var lastTriggeredAt sql.NullTime
 
@@ -259,6 +280,7 @@ RETURNING
 		input.SustainedMinutes,
 		input.CooldownMinutes,
 		input.NotifyEmail,
+		channelsArg,
 		filtersArg,
 	).Scan(
 		&out.ID,
@@ -273,6 +295,7 @@ RETURNING
 		&out.SustainedMinutes,
 		&out.CooldownMinutes,
 		&out.NotifyEmail,
+		&channelsRaw,
 		&filtersRaw,
 		&lastTriggeredAt,
 		&out.CreatedAt,
@@ -285,6 +308,7 @@ RETURNING
 		v := lastTriggeredAt.Time
 		out.LastTriggeredAt = &v
 	}
+	out.NotifyChannels = decodeOpsNotifyChannels(channelsRaw)
 	if len(filtersRaw) > 0 && string(filtersRaw) != "null" {
 		var decoded map[string]any
 		if err := json.Unmarshal(filtersRaw, &decoded); err == nil {
@@ -851,3 +875,23 @@ func opsNullJSONMap(v map[string]any) (any, error) {
 	}
 	return sql.NullString{String: string(b), Valid: true}, nil
 }
+
+func opsJSONNotifyChannels(v []service.OpsAlertNotifyChannel) (string, error) {
+	if len(v) == 0 {
+		return "[]", nil
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	return string(b), nil
+}
+
+func decodeOpsNotifyChannels(raw []byte) []service.OpsAlertNotifyChannel {
+	out := []service.OpsAlertNotifyChannel{}
+	if len(raw) == 0 || string(raw) == "null" {
+		return out
+	}
+	_ = json.Unmarshal(raw, &out)
+	return out
+}
@@ -0,0 +1,465 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"time"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/apikey"
+	"github.com/Wei-Shaw/sub2api/ent/organization"
+	"github.com/Wei-Shaw/sub2api/ent/organizationmember"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+type organizationRepository struct {
+	client *dbent.Client
+	sql    sqlExecutor
+}
+
+func NewOrganizationRepository(client *dbent.Client, sqlDB *sql.DB) service.OrganizationRepository {
+	return &organizationRepository{client: client, sql: sqlDB}
+}
+
+func (r *organizationRepository) Create(ctx context.Context, org *service.Organization) error {
+	tx, err := r.client.Tx(ctx)
+	if err != nil && !errors.Is(err, dbent.ErrTxStarted) {
+		return err
+	}
+	client := clientFromContext(ctx, r.client)
+	if err == nil {
+		defer func() { _ = tx.Rollback() }()
+		client = tx.Client()
+	}
+
+	created, err := client.Organization.Create().
+		SetName(org.Name).
+		SetOwnerUserID(org.OwnerUserID).
+		SetBalance(org.Balance).
+		SetStatus(org.Status).
+		Save(ctx)
+	if err != nil {
+		return translatePersistenceError(err, nil, nil)
+	}
+	if _, err := client.OrganizationMember.Create().
+		SetOrganizationID(created.ID).
+		SetUserID(org.OwnerUserID).
+		SetRole(service.OrganizationRoleOwner).
+		Save(ctx); err != nil {
+		return translatePersistenceError(err, nil, service.ErrOrganizationMemberExists)
+	}
+
+	if tx != nil {
+		if err := tx.Commit(); err != nil {
+			return err
+		}
+	}
+	org.ID = created.ID
+	org.CreatedAt = created.CreatedAt
+	org.UpdatedAt = created.UpdatedAt
+	org.MemberCount = 1
+	return nil
+}
+
+func (r *organizationRepository) GetByID(ctx context.Context, id int64) (*service.Organization, error) {
+	client := clientFromContext(ctx, r.client)
+	m, err := client.Organization.Query().
+		Where(organization.IDEQ(id)).
+		WithOwner().
+		Only(ctx)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOrganizationNotFound, nil)
+	}
+	return organizationEntityToService(m), nil
+}
+
+func (r *organizationRepository) Update(ctx context.Context, org *service.Organization) error {
+	client := clientFromContext(ctx, r.client)
+	updated, err := client.Organization.UpdateOneID(org.ID).
+		SetName(org.Name).
+		SetStatus(org.Status).
+		Save(ctx)
+	if err != nil {
+		return translatePersistenceError(err, service.ErrOrganizationNotFound, nil)
+	}
+	org.UpdatedAt = updated.UpdatedAt
+	return nil
+}
+
+func (r *organizationRepository) Delete(ctx context.Context, id int64) error {
+	client := clientFromContext(ctx, r.client)
+	err := client.Organization.DeleteOneID(id).Exec(ctx)
+	return translatePersistenceError(err, service.ErrOrganizationNotFound, nil)
+}
+
+func (r *organizationRepository) List(ctx context.Context, params pagination.PaginationParams, filters service.OrganizationListFilters) ([]service.Organization, *pagination.PaginationResult, error) {
+	client := clientFromContext(ctx, r.client)
+	q := client.Organization.Query()
+	if filters.Search != "" {
+		q = q.Where(organization.NameContainsFold(filters.Search))
+	}
+	if filters.Status != "" {
+		q = q.Where(organization.StatusEQ(filters.Status))
+	}
+
+	total, err := q.Count(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	orgs, err := q.
+		WithOwner().
+		Order(dbent.Desc(organization.FieldID)).
+		Offset(params.Offset()).
+		Limit(params.Limit()).
+		All(ctx)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	ids := make([]int64, 0, len(orgs))
+	for _, o := range orgs {
+		ids = append(ids, o.ID)
+	}
+	counts, err := r.memberCounts(ctx, client, ids)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	out := make([]service.Organization, 0, len(orgs))
+	for _, o := range orgs {
+		svc := organizationEntityToService(o)
+		svc.MemberCount = counts[o.ID]
+		out = append(out, *svc)
+	}
+	return out, paginationResultFromTotal(int64(total), params), nil
+}
+
+func (r *organizationRepository) memberCounts(ctx context.Context, client *dbent.Client, orgIDs []int64) (map[int64]int, error) {
+	counts := make(map[int64]int, len(orgIDs))
+	if len(orgIDs) == 0 {
+		return counts, nil
+	}
+	var rows []struct {
+		OrganizationID int64 `json:"organization_id"`
+		Count          int   `json:"count"`
+	}
+	err := client.OrganizationMember.Query().
+		Where(organizationmember.OrganizationIDIn(orgIDs...)).
+		GroupBy(organizationmember.FieldOrganizationID).
+		Aggregate(dbent.Count()).
+		Scan(ctx, &rows)
+	if err != nil {
+		return nil, err
+	}
+	for _, row := range rows {
+		counts[row.OrganizationID] = row.Count
+	}
+	return counts, nil
+}
+
+func (r *organizationRepository) UpdateBalance(ctx context.Context, id int64, amount float64) (float64, error) {
+	client := clientFromContext(ctx, r.client)
+	updated, err := client.Organization.UpdateOneID(id).
+		AddBalance(amount).
+		Save(ctx)
+	if err != nil {
+		return 0, translatePersistenceError(err, service.ErrOrganizationNotFound, nil)
+	}
+	return updated.Balance, nil
+}
+
+func (r *organizationRepository) DeductBalance(ctx context.Context, id int64, amount float64) error {
+	client := clientFromContext(ctx, r.client)
+	n, err := client.Organization.Update().
+		Where(organization.IDEQ(id)).
+		AddBalance(-amount).
+		Save(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return service.ErrOrganizationNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) ListMembershipsByUserID(ctx context.Context, userID int64) ([]service.OrganizationMember, error) {
+	client := clientFromContext(ctx, r.client)
+	members, err := client.OrganizationMember.Query().
+		Where(
+			organizationmember.UserIDEQ(userID),
+			// 软删除的组织不会被 WithOrganization 加载，这里显式过滤
+			organizationmember.HasOrganizationWith(organization.DeletedAtIsNil()),
+		).
+		WithOrganization().
+		Order(dbent.Asc(organizationmember.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]service.OrganizationMember, 0, len(members))
+	for _, m := range members {
+		out = append(out, *organizationMemberEntityToService(m))
+	}
+	return out, nil
+}
+
+func (r *organizationRepository) GetMember(ctx context.Context, orgID, userID int64) (*service.OrganizationMember, error) {
+	client := clientFromContext(ctx, r.client)
+	m, err := client.OrganizationMember.Query().
+		Where(
+			organizationmember.OrganizationIDEQ(orgID),
+			organizationmember.UserIDEQ(userID),
+			organizationmember.HasOrganizationWith(organization.DeletedAtIsNil()),
+		).
+		WithUser().
+		Only(ctx)
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrOrganizationMemberNotFound, nil)
+	}
+	return organizationMemberEntityToService(m), nil
+}
+
+func (r *organizationRepository) ListMembers(ctx context.Context, orgID int64) ([]service.OrganizationMember, error) {
+	client := clientFromContext(ctx, r.client)
+	members, err := client.OrganizationMember.Query().
+		Where(organizationmember.OrganizationIDEQ(orgID)).
+		WithUser().
+		Order(dbent.Asc(organizationmember.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]service.OrganizationMember, 0, len(members))
+	for _, m := range members {
+		out = append(out, *organizationMemberEntityToService(m))
+	}
+	return out, nil
+}
+
+func (r *organizationRepository) AddMember(ctx context.Context, member *service.OrganizationMember) error {
+	client := clientFromContext(ctx, r.client)
+	created, err := client.OrganizationMember.Create().
+		SetOrganizationID(member.OrganizationID).
+		SetUserID(member.UserID).
+		SetRole(member.Role).
+		SetMonthlyLimitUsd(member.MonthlyLimitUSD).
+		Save(ctx)
+	if err != nil {
+		return translatePersistenceError(err, nil, service.ErrOrganizationMemberExists)
+	}
+	member.ID = created.ID
+	member.CreatedAt = created.CreatedAt
+	member.UpdatedAt = created.UpdatedAt
+	return nil
+}
+
+func (r *organizationRepository) UpdateMember(ctx context.Context, member *service.OrganizationMember) error {
+	client := clientFromContext(ctx, r.client)
+	updated, err := client.OrganizationMember.UpdateOneID(member.ID).
+		SetRole(member.Role).
+		SetMonthlyLimitUsd(member.MonthlyLimitUSD).
+		Save(ctx)
+	if err != nil {
+		return translatePersistenceError(err, service.ErrOrganizationMemberNotFound, nil)
+	}
+	member.UpdatedAt = updated.UpdatedAt
+	return nil
+}
+
+func (r *organizationRepository) RemoveMember(ctx context.Context, orgID, userID int64) error {
+	client := clientFromContext(ctx, r.client)
+	n, err := client.OrganizationMember.Delete().
+		Where(
+			organizationmember.OrganizationIDEQ(orgID),
+			organizationmember.UserIDEQ(userID),
+		).
+		Exec(ctx)
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return service.ErrOrganizationMemberNotFound
+	}
+	return nil
+}
+
+func (r *organizationRepository) IncrementMemberUsage(ctx context.Context, orgID, userID int64, amount float64, monthStart time.Time) error {
+	// 自然月切换时在同一条 UPDATE 内清零，避免额外的窗口重置任务
+	const query = `
+		UPDATE organization_members
+		SET
+			monthly_usage_usd = CASE
+				WHEN monthly_window_start IS NULL OR monthly_window_start < $4 THEN $3
+				ELSE monthly_usage_usd + $3
+			END,
+			monthly_window_start = CASE
+				WHEN monthly_window_start IS NULL OR monthly_window_start < $4 THEN $4
+				ELSE monthly_window_start
+			END,
+			updated_at = NOW()
+		WHERE organization_id = $1 AND user_id = $2
+	`
+	_, err := r.sql.ExecContext(ctx, query, orgID, userID, amount, monthStart)
+	return err
+}
+
+func (r *organizationRepository) ListAPIKeys(ctx context.Context, orgID, userID int64) ([]service.APIKey, error) {
+	client := clientFromContext(ctx, r.client)
+	q := client.APIKey.Query().
+		Where(apikey.OrganizationIDEQ(orgID), apikey.DeletedAtIsNil())
+	if userID > 0 {
+		q = q.Where(apikey.UserIDEQ(userID))
+	}
+	keys, err := q.
+		WithUser().
+		WithGroup().
+		Order(dbent.Desc(apikey.FieldID)).
+		All(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out := make([]service.APIKey, 0, len(keys))
+	for _, k := range keys {
+		out = append(out, *apiKeyEntityToService(k))
+	}
+	return out, nil
+}
+
+func (r *organizationRepository) ListKeyHashesByOrganizationID(ctx context.Context, orgID int64) ([]string, error) {
+	client := clientFromContext(ctx, r.client)
+	return client.APIKey.Query().
+		Where(apikey.OrganizationIDEQ(orgID), apikey.DeletedAtIsNil()).
+		Select(apikey.FieldKeyHash).
+		Strings(ctx)
+}
+
+func (r *organizationRepository) GetUsageSummary(ctx context.Context, orgID int64, startTime, endTime time.Time) (summary *service.OrganizationUsageSummary, err error) {
+	summary = &service.OrganizationUsageSummary{
+		StartTime: startTime,
+		EndTime:   endTime,
+		Members:   make([]service.OrganizationMemberUsage, 0),
+		Daily:     make([]service.OrganizationDailyUsage, 0),
+	}
+
+	// 按组织 Key 聚合：Key 的 organization_id 创建后不再变更，JOIN 即可得到组织口径
+	memberQuery := `
+		SELECT
+			ul.user_id,
+			COALESCE(u.email, '') as email,
+			COUNT(*) as requests,
+			COALESCE(SUM(ul.input_tokens + ul.output_tokens + ul.cache_creation_tokens + ul.cache_read_tokens), 0) as tokens,
+			COALESCE(SUM(ul.total_cost), 0) as cost,
+			COALESCE(SUM(ul.actual_cost), 0) as actual_cost
+		FROM usage_logs ul
+		JOIN api_keys ak ON ak.id = ul.api_key_id
+		LEFT JOIN users u ON u.id = ul.user_id
+		WHERE ak.organization_id = $1 AND ul.created_at >= $2 AND ul.created_at < $3
+		GROUP BY ul.user_id, u.email
+		ORDER BY actual_cost DESC
+	`
+	rows, err := r.sql.QueryContext(ctx, memberQuery, orgID, startTime, endTime)
+	if err != nil {
+		return nil, err
+	}
+	for rows.Next() {
+		var row service.OrganizationMemberUsage
+		if err = rows.Scan(&row.UserID, &row.Email, &row.Requests, &row.Tokens, &row.Cost, &row.ActualCost); err != nil {
+			_ = rows.Close()
+			return nil, err
+		}
+		summary.Members = append(summary.Members, row)
+		summary.TotalRequests += row.Requests
+		summary.TotalTokens += row.Tokens
+		summary.TotalCost += row.Cost
+		summary.TotalActualCost += row.ActualCost
+	}
+	if err = rows.Close(); err != nil {
+		return nil, err
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+
+	dailyQuery := `
+		SELECT
+			-- 使用应用时区分组，避免数据库会话时区导致日边界偏移。
+			TO_CHAR(ul.created_at AT TIME ZONE $4, 'YYYY-MM-DD') as date,
+			COUNT(*) as requests,
+			COALESCE(SUM(ul.input_tokens + ul.output_tokens + ul.cache_creation_tokens + ul.cache_read_tokens), 0) as tokens,
+			COALESCE(SUM(ul.total_cost), 0) as cost,
+			COALESCE(SUM(ul.actual_cost), 0) as actual_cost
+		FROM usage_logs ul
+		JOIN api_keys ak ON ak.id = ul.api_key_id
+		WHERE ak.organization_id = $1 AND ul.created_at >= $2 AND ul.created_at < $3
+		GROUP BY 1
+		ORDER BY 1
+	`
+	rows, err = r.sql.QueryContext(ctx, dailyQuery, orgID, startTime, endTime, resolveUsageStatsTimezone())
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		if closeErr := rows.Close(); closeErr != nil && err == nil {
+			err = closeErr
+			summary = nil
+		}
+	}()
+	for rows.Next() {
+		var row service.OrganizationDailyUsage
+		if err = rows.Scan(&row.Date, &row.Requests, &row.Tokens, &row.Cost, &row.ActualCost); err != nil {
+			return nil, err
+		}
+		summary.Daily = append(summary.Daily, row)
+	}
+	if err = rows.Err(); err != nil {
+		return nil, err
+	}
+	return summary, nil
+}
+
+func organizationEntityToService(m *dbent.Organization) *service.Organization {
+	if m == nil {
+		return nil
+	}
+	out := &service.Organization{
+		ID:          m.ID,
+		Name:        m.Name,
+		OwnerUserID: m.OwnerUserID,
+		Balance:     m.Balance,
+		Status:      m.Status,
+		CreatedAt:   m.CreatedAt,
+		UpdatedAt:   m.UpdatedAt,
+	}
+	if m.Edges.Owner != nil {
+		out.Owner = userEntityToService(m.Edges.Owner)
+	}
+	return out
+}
+
+func organizationMemberEntityToService(m *dbent.OrganizationMember) *service.OrganizationMember {
+	if m == nil {
+		return nil
+	}
+	out := &service.OrganizationMember{
+		ID:                 m.ID,
+		OrganizationID:     m.OrganizationID,
+		UserID:             m.UserID,
+		Role:               m.Role,
+		MonthlyLimitUSD:    m.MonthlyLimitUsd,
+		MonthlyUsageUSD:    m.MonthlyUsageUsd,
+		MonthlyWindowStart: m.MonthlyWindowStart,
+		CreatedAt:          m.CreatedAt,
+		UpdatedAt:          m.UpdatedAt,
+	}
+	if m.Edges.User != nil {
+		out.User = userEntityToService(m.Edges.User)
+	}
+	if m.Edges.Organization != nil {
+		out.Organization = organizationEntityToService(m.Edges.Organization)
+	}
+	return out
+}
@@ -0,0 +1,273 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+// proxyPoolRepository 实现 service.ProxyPoolRepository 接口。
+// 使用原生 SQL 操作 proxy_pools / proxy_pool_members，并维护 accounts.proxy_pool_id。
+type proxyPoolRepository struct {
+	sql *sql.DB
+}
+
+// NewProxyPoolRepository 创建代理池仓储实例。
+func NewProxyPoolRepository(sqlDB *sql.DB) service.ProxyPoolRepository {
+	return &proxyPoolRepository{sql: sqlDB}
+}
+
+const proxyPoolColumns = `p.id, p.name, p.description, p.status, p.created_at, p.updated_at,
+	(SELECT COUNT(*) FROM accounts a WHERE a.proxy_pool_id = p.id AND a.deleted_at IS NULL)`
+
+const proxyPoolMemberColumns = `m.pool_id, m.proxy_id, m.healthy, m.fail_count, m.latency_ms, m.last_error, m.last_checked_at,
+	x.name, x.protocol, x.host, x.port, x.username, x.password, x.status, x.created_at, x.updated_at`
+
+func (r *proxyPoolRepository) Create(ctx context.Context, pool *service.ProxyPool) error {
+	err := r.sql.QueryRowContext(ctx, `
+		INSERT INTO proxy_pools (name, description, status)
+		VALUES ($1, $2, $3)
+		RETURNING id, created_at, updated_at
+	`, pool.Name, pool.Description, pool.Status).Scan(&pool.ID, &pool.CreatedAt, &pool.UpdatedAt)
+	return translatePersistenceError(err, nil, service.ErrProxyPoolExists)
+}
+
+func (r *proxyPoolRepository) GetByID(ctx context.Context, id int64) (*service.ProxyPool, error) {
+	rows, err := r.sql.QueryContext(ctx, "SELECT "+proxyPoolColumns+" FROM proxy_pools p WHERE p.id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	pools, err := scanProxyPools(rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(pools) == 0 {
+		return nil, service.ErrProxyPoolNotFound
+	}
+	if err := r.loadMembers(ctx, pools); err != nil {
+		return nil, err
+	}
+	return &pools[0], nil
+}
+
+func (r *proxyPoolRepository) Update(ctx context.Context, pool *service.ProxyPool) error {
+	err := r.sql.QueryRowContext(ctx, `
+		UPDATE proxy_pools SET name = $2, description = $3, status = $4, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, pool.ID, pool.Name, pool.Description, pool.Status).Scan(&pool.UpdatedAt)
+	return translatePersistenceError(err, service.ErrProxyPoolNotFound, service.ErrProxyPoolExists)
+}
+
+func (r *proxyPoolRepository) Delete(ctx context.Context, id int64) error {
+	res, err := r.sql.ExecContext(ctx, `DELETE FROM proxy_pools WHERE id = $1`, id)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrProxyPoolNotFound
+	}
+	return nil
+}
+
+func (r *proxyPoolRepository) List(ctx context.Context, params pagination.PaginationParams, search string) ([]service.ProxyPool, *pagination.PaginationResult, error) {
+	conditions := make([]string, 0, 1)
+	args := make([]any, 0, 3)
+	if search != "" {
+		args = append(args, "%"+search+"%")
+		conditions = append(conditions, fmt.Sprintf("p.name ILIKE $%d", len(args)))
+	}
+	whereClause := buildWhere(conditions)
+
+	var total int64
+	if err := scanSingleRow(ctx, r.sql, "SELECT COUNT(*) FROM proxy_pools p "+whereClause, args, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.ProxyPool{}, paginationResultFromTotal(0, params), nil
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM proxy_pools p %s ORDER BY p.id DESC LIMIT $%d OFFSET $%d`,
+		proxyPoolColumns, whereClause, len(args)+1, len(args)+2)
+	rows, err := r.sql.QueryContext(ctx, query, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	pools, err := scanProxyPools(rows)
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := r.loadMembers(ctx, pools); err != nil {
+		return nil, nil, err
+	}
+	return pools, paginationResultFromTotal(total, params), nil
+}
+
+func (r *proxyPoolRepository) ListAll(ctx context.Context) ([]service.ProxyPool, error) {
+	rows, err := r.sql.QueryContext(ctx, "SELECT "+proxyPoolColumns+" FROM proxy_pools p ORDER BY p.id")
+	if err != nil {
+		return nil, err
+	}
+	pools, err := scanProxyPools(rows)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.loadMembers(ctx, pools); err != nil {
+		return nil, err
+	}
+	return pools, nil
+}
+
+// loadMembers 批量加载成员；已软删除的代理不返回
+func (r *proxyPoolRepository) loadMembers(ctx context.Context, pools []service.ProxyPool) error {
+	if len(pools) == 0 {
+		return nil
+	}
+	ids := make([]int64, 0, len(pools))
+	index := make(map[int64]int, len(pools))
+	for i := range pools {
+		ids = append(ids, pools[i].ID)
+		index[pools[i].ID] = i
+	}
+
+	rows, err := r.sql.QueryContext(ctx, `
+		SELECT `+proxyPoolMemberColumns+`
+		FROM proxy_pool_members m
+		JOIN proxies x ON x.id = m.proxy_id AND x.deleted_at IS NULL
+		WHERE m.pool_id = ANY($1)
+		ORDER BY m.pool_id, m.proxy_id
+	`, pq.Array(ids))
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+
+	for rows.Next() {
+		member, err := scanProxyPoolMember(rows)
+		if err != nil {
+			return err
+		}
+		if i, ok := index[member.PoolID]; ok {
+			pools[i].Members = append(pools[i].Members, *member)
+		}
+	}
+	return rows.Err()
+}
+
+func (r *proxyPoolRepository) SetMembers(ctx context.Context, poolID int64, proxyIDs []int64) error {
+	tx, err := r.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	if _, err := tx.ExecContext(ctx, `
+		DELETE FROM proxy_pool_members WHERE pool_id = $1 AND NOT (proxy_id = ANY($2))
+	`, poolID, pq.Array(proxyIDs)); err != nil {
+		return err
+	}
+	if len(proxyIDs) > 0 {
+		if _, err := tx.ExecContext(ctx, `
+			INSERT INTO proxy_pool_members (pool_id, proxy_id)
+			SELECT $1, UNNEST($2::bigint[])
+			ON CONFLICT (pool_id, proxy_id) DO NOTHING
+		`, poolID, pq.Array(proxyIDs)); err != nil {
+			return err
+		}
+	}
+	if _, err := tx.ExecContext(ctx, `UPDATE proxy_pools SET updated_at = NOW() WHERE id = $1`, poolID); err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+func (r *proxyPoolRepository) UpdateMemberHealth(ctx context.Context, health service.ProxyPoolMemberHealth) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE proxy_pool_members
+		SET healthy = $2, fail_count = $3, latency_ms = COALESCE($4, latency_ms), last_error = $5, last_checked_at = $6
+		WHERE proxy_id = $1
+	`, health.ProxyID, health.Healthy, health.FailCount, health.LatencyMs, health.LastError, health.CheckedAt)
+	return err
+}
+
+func (r *proxyPoolRepository) ListAccountBindings(ctx context.Context) (map[int64]int64, error) {
+	rows, err := r.sql.QueryContext(ctx, `
+		SELECT id, proxy_pool_id FROM accounts WHERE proxy_pool_id IS NOT NULL AND deleted_at IS NULL
+	`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make(map[int64]int64)
+	for rows.Next() {
+		var accountID, poolID int64
+		if err := rows.Scan(&accountID, &poolID); err != nil {
+			return nil, err
+		}
+		out[accountID] = poolID
+	}
+	return out, rows.Err()
+}
+
+func (r *proxyPoolRepository) AssignAccounts(ctx context.Context, poolID *int64, accountIDs []int64) (int64, error) {
+	var pool sql.NullInt64
+	if poolID != nil {
+		pool = sql.NullInt64{Int64: *poolID, Valid: true}
+	}
+	res, err := r.sql.ExecContext(ctx, `
+		UPDATE accounts SET proxy_pool_id = $1, updated_at = NOW()
+		WHERE id = ANY($2) AND deleted_at IS NULL
+	`, pool, pq.Array(accountIDs))
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func scanProxyPools(rows *sql.Rows) ([]service.ProxyPool, error) {
+	defer func() { _ = rows.Close() }()
+	out := make([]service.ProxyPool, 0)
+	for rows.Next() {
+		var pool service.ProxyPool
+		if err := rows.Scan(&pool.ID, &pool.Name, &pool.Description, &pool.Status, &pool.CreatedAt, &pool.UpdatedAt, &pool.AccountCount); err != nil {
+			return nil, err
+		}
+		out = append(out, pool)
+	}
+	return out, rows.Err()
+}
+
+func scanProxyPoolMember(scanner interface{ Scan(...any) error }) (*service.ProxyPoolMember, error) {
+	var (
+		member      service.ProxyPoolMember
+		proxy       service.Proxy
+		latencyMs   sql.NullInt64
+		lastChecked sql.NullTime
+		username    sql.NullString
+		password    sql.NullString
+	)
+	if err := scanner.Scan(
+		&member.PoolID, &member.ProxyID, &member.Healthy, &member.FailCount, &latencyMs, &member.LastError, &lastChecked,
+		&proxy.Name, &proxy.Protocol, &proxy.Host, &proxy.Port, &username, &password, &proxy.Status, &proxy.CreatedAt, &proxy.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if latencyMs.Valid {
+		v := latencyMs.Int64
+		member.LatencyMs = &v
+	}
+	if lastChecked.Valid {
+		t := lastChecked.Time
+		member.LastCheckedAt = &t
+	}
+	proxy.ID = member.ProxyID
+	proxy.Username = username.String
+	proxy.Password = password.String
+	member.Proxy = &proxy
+	return &member, nil
+}
@@ -0,0 +1,49 @@
+package repository
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/redis/go-redis/v9"
+)
+
+const responseCacheKeyPrefix = "response_cache:v1:"
+
+type responseCache struct {
+	rdb *redis.Client
+}
+
+// NewResponseCache 创建基于 Redis 的精确响应缓存
+func NewResponseCache(rdb *redis.Client) service.ResponseCache {
+	return &responseCache{rdb: rdb}
+}
+
+func responseCacheKey(key string) string {
+	return responseCacheKeyPrefix + key
+}
+
+func (c *responseCache) Get(ctx context.Context, key string) (*service.ResponseCacheEntry, error) {
+	raw, err := c.rdb.Get(ctx, responseCacheKey(key)).Bytes()
+	if err != nil {
+		if errors.Is(err, redis.Nil) {
+			return nil, service.ErrResponseCacheMiss
+		}
+		return nil, err
+	}
+	var entry service.ResponseCacheEntry
+	if err := json.Unmarshal(raw, &entry); err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+func (c *responseCache) Set(ctx context.Context, key string, entry *service.ResponseCacheEntry, ttl time.Duration) error {
+	raw, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return c.rdb.Set(ctx, responseCacheKey(key), raw, ttl).Err()
+}
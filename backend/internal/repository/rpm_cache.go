@@ -31,6 +31,11 @@ const (
 	// 格式: rpm:{accountID}:{minuteTimestamp}
 	rpmKeyPrefix = "rpm:"
 
+	// API Key 级 RPM/TPM 计数器键前缀
+	// 格式: rpm:api_key:{apiKeyID}:{minuteTimestamp} / tpm:api_key:{apiKeyID}:{minuteTimestamp}
+	apiKeyRPMKeyPrefix = "rpm:api_key:"
+	apiKeyTPMKeyPrefix = "tpm:api_key:"
+
 	// RPM 计数器 TTL（120 秒，覆盖当前分钟窗口 + 冗余）
 	rpmKeyTTL = 120 * time.Second
 )
@@ -139,3 +144,70 @@ func (c *RPMCacheImpl) GetRPMBatch(ctx context.Context, accountIDs []int64) (map
 	}
 	return result, nil
 }
+
+// currentMinuteWindow 返回当前分钟时间戳以及距下一分钟的剩余时间（用于 Retry-After）
+// 使用 rdb.Time() 获取 Redis 服务端时间
+func (c *RPMCacheImpl) currentMinuteWindow(ctx context.Context) (int64, time.Duration, error) {
+	serverTime, err := c.rdb.Time(ctx).Result()
+	if err != nil {
+		return 0, 0, fmt.Errorf("redis TIME: %w", err)
+	}
+	minuteTS := serverTime.Unix() / 60
+	resetIn := time.Unix((minuteTS+1)*60, 0).Sub(serverTime)
+	return minuteTS, resetIn, nil
+}
+
+// IncrementAPIKeyRPM 原子递增 API Key 当前分钟的请求计数
+func (c *RPMCacheImpl) IncrementAPIKeyRPM(ctx context.Context, apiKeyID int64) (int, time.Duration, error) {
+	minuteTS, resetIn, err := c.currentMinuteWindow(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("api key rpm increment: %w", err)
+	}
+	key := fmt.Sprintf("%s%d:%d", apiKeyRPMKeyPrefix, apiKeyID, minuteTS)
+
+	pipe := c.rdb.TxPipeline()
+	incrCmd := pipe.Incr(ctx, key)
+	pipe.Expire(ctx, key, rpmKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return 0, 0, fmt.Errorf("api key rpm increment: %w", err)
+	}
+	return int(incrCmd.Val()), resetIn, nil
+}
+
+// AddAPIKeyTokens 累加 API Key 当前分钟的 Token 用量
+func (c *RPMCacheImpl) AddAPIKeyTokens(ctx context.Context, apiKeyID int64, tokens int) error {
+	if tokens <= 0 {
+		return nil
+	}
+	minuteTS, _, err := c.currentMinuteWindow(ctx)
+	if err != nil {
+		return fmt.Errorf("api key tpm add: %w", err)
+	}
+	key := fmt.Sprintf("%s%d:%d", apiKeyTPMKeyPrefix, apiKeyID, minuteTS)
+
+	pipe := c.rdb.TxPipeline()
+	pipe.IncrBy(ctx, key, int64(tokens))
+	pipe.Expire(ctx, key, rpmKeyTTL)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("api key tpm add: %w", err)
+	}
+	return nil
+}
+
+// GetAPIKeyTokens 获取 API Key 当前分钟的 Token 用量
+func (c *RPMCacheImpl) GetAPIKeyTokens(ctx context.Context, apiKeyID int64) (int, time.Duration, error) {
+	minuteTS, resetIn, err := c.currentMinuteWindow(ctx)
+	if err != nil {
+		return 0, 0, fmt.Errorf("api key tpm get: %w", err)
+	}
+	key := fmt.Sprintf("%s%d:%d", apiKeyTPMKeyPrefix, apiKeyID, minuteTS)
+
+	val, err := c.rdb.Get(ctx, key).Int()
+	if errors.Is(err, redis.Nil) {
+		return 0, resetIn, nil
+	}
+	if err != nil {
+		return 0, 0, fmt.Errorf("api key tpm get: %w", err)
+	}
+	return val, resetIn, nil
+}
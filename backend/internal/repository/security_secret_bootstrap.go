@@ -18,6 +18,7 @@ import (
 
 const (
 	securitySecretKeyJWT        = "jwt_secret"
+	securitySecretKeyAPIKeyHash = "api_key_hash_secret"
 	securitySecretReadRetryMax  = 5
 	securitySecretReadRetryWait = 10 * time.Millisecond
 )
@@ -32,6 +33,13 @@ func ensureBootstrapSecrets(ctx context.Context, client *ent.Client, cfg *config
 		return fmt.Errorf("nil config")
 	}
 
+	if err := ensureJWTSecret(ctx, client, cfg); err != nil {
+		return err
+	}
+	return ensureAPIKeyHashSecret(ctx, client, cfg)
+}
+
+func ensureJWTSecret(ctx context.Context, client *ent.Client, cfg *config.Config) error {
 	cfg.JWT.Secret = strings.TrimSpace(cfg.JWT.Secret)
 	if cfg.JWT.Secret != "" {
 		storedSecret, err := createSecuritySecretIfAbsent(ctx, client, securitySecretKeyJWT, cfg.JWT.Secret)
@@ -57,6 +65,30 @@ func ensureBootstrapSecrets(ctx context.Context, client *ent.Client, cfg *config
 	return nil
 }
 
+// ensureAPIKeyHashSecret 确保 API Key 哈希密钥在所有实例间一致。
+// 与 JWT 不同，该密钥一旦用于签发 Key 便不能轮换，因此始终以数据库中的持久化值为准。
+func ensureAPIKeyHashSecret(ctx context.Context, client *ent.Client, cfg *config.Config) error {
+	cfg.Security.APIKeyHashSecret = strings.TrimSpace(cfg.Security.APIKeyHashSecret)
+	if cfg.Security.APIKeyHashSecret != "" {
+		storedSecret, err := createSecuritySecretIfAbsent(ctx, client, securitySecretKeyAPIKeyHash, cfg.Security.APIKeyHashSecret)
+		if err != nil {
+			return fmt.Errorf("persist api key hash secret: %w", err)
+		}
+		if storedSecret != cfg.Security.APIKeyHashSecret {
+			log.Println("Warning: configured API key hash secret mismatches persisted value; using persisted secret so existing keys keep working.")
+		}
+		cfg.Security.APIKeyHashSecret = storedSecret
+		return nil
+	}
+
+	secret, _, err := getOrCreateGeneratedSecuritySecret(ctx, client, securitySecretKeyAPIKeyHash, 32)
+	if err != nil {
+		return fmt.Errorf("ensure api key hash secret: %w", err)
+	}
+	cfg.Security.APIKeyHashSecret = secret
+	return nil
+}
+
 func getOrCreateGeneratedSecuritySecret(ctx context.Context, client *ent.Client, key string, byteLength int) (string, bool, error) {
 	existing, err := client.SecuritySecret.Query().Where(securitysecret.KeyEQ(key)).Only(ctx)
 	if err == nil {
@@ -127,6 +127,35 @@ func TestEnsureBootstrapSecretsConfiguredSecretDuplicateIgnored(t *testing.T) {
 	require.Equal(t, "existing-jwt-secret-32bytes-long!!!!", cfg.JWT.Secret)
 }
 
+func TestEnsureBootstrapSecretsGenerateAndPersistAPIKeyHashSecret(t *testing.T) {
+	client := newSecuritySecretTestClient(t)
+	cfg := &config.Config{}
+
+	err := ensureBootstrapSecrets(context.Background(), client, cfg)
+	require.NoError(t, err)
+	require.NotEmpty(t, cfg.Security.APIKeyHashSecret)
+
+	stored, err := client.SecuritySecret.Query().Where(securitysecret.KeyEQ(securitySecretKeyAPIKeyHash)).Only(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, cfg.Security.APIKeyHashSecret, stored.Value)
+
+	// 再次启动应复用已持久化的密钥，否则已有 Key 将全部失效
+	cfg2 := &config.Config{}
+	require.NoError(t, ensureBootstrapSecrets(context.Background(), client, cfg2))
+	require.Equal(t, cfg.Security.APIKeyHashSecret, cfg2.Security.APIKeyHashSecret)
+}
+
+func TestEnsureBootstrapSecretsStoredAPIKeyHashSecretWins(t *testing.T) {
+	client := newSecuritySecretTestClient(t)
+	_, err := client.SecuritySecret.Create().SetKey(securitySecretKeyAPIKeyHash).SetValue("stored-api-key-hash-secret-32bytes!!").Save(context.Background())
+	require.NoError(t, err)
+
+	cfg := &config.Config{Security: config.SecurityConfig{APIKeyHashSecret: " configured-api-key-hash-secret-32bytes "}}
+	err = ensureBootstrapSecrets(context.Background(), client, cfg)
+	require.NoError(t, err)
+	require.Equal(t, "stored-api-key-hash-secret-32bytes!!", cfg.Security.APIKeyHashSecret)
+}
+
 func TestGetOrCreateGeneratedSecuritySecretTrimmedExistingValue(t *testing.T) {
 	client := newSecuritySecretTestClient(t)
 	_, err := client.SecuritySecret.Create().
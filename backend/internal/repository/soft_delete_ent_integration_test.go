@@ -43,10 +43,10 @@ func TestEntSoftDelete_ApiKey_DefaultFilterAndSkip(t *testing.T) {
 
 	repo := NewAPIKeyRepository(client, integrationDB)
 	key := &service.APIKey{
-		UserID: u.ID,
-		Key:    uniqueSoftDeleteValue(t, "sk-soft-delete"),
-		Name:   "soft-delete",
-		Status: service.StatusActive,
+		UserID:  u.ID,
+		KeyHash: testAPIKeyHash(uniqueSoftDeleteValue(t, "sk-soft-delete")),
+		Name:    "soft-delete",
+		Status:  service.StatusActive,
 	}
 	require.NoError(t, repo.Create(ctx, key), "create api key")
 
@@ -75,10 +75,10 @@ func TestEntSoftDelete_ApiKey_DeleteIdempotent(t *testing.T) {
 
 	repo := NewAPIKeyRepository(client, integrationDB)
 	key := &service.APIKey{
-		UserID: u.ID,
-		Key:    uniqueSoftDeleteValue(t, "sk-soft-delete2"),
-		Name:   "soft-delete2",
-		Status: service.StatusActive,
+		UserID:  u.ID,
+		KeyHash: testAPIKeyHash(uniqueSoftDeleteValue(t, "sk-soft-delete2")),
+		Name:    "soft-delete2",
+		Status:  service.StatusActive,
 	}
 	require.NoError(t, repo.Create(ctx, key), "create api key")
 
@@ -95,10 +95,10 @@ func TestEntSoftDelete_ApiKey_HardDeleteViaSkipSoftDelete(t *testing.T) {
 
 	repo := NewAPIKeyRepository(client, integrationDB)
 	key := &service.APIKey{
-		UserID: u.ID,
-		Key:    uniqueSoftDeleteValue(t, "sk-soft-delete3"),
-		Name:   "soft-delete3",
-		Status: service.StatusActive,
+		UserID:  u.ID,
+		KeyHash: testAPIKeyHash(uniqueSoftDeleteValue(t, "sk-soft-delete3")),
+		Name:    "soft-delete3",
+		Status:  service.StatusActive,
 	}
 	require.NoError(t, repo.Create(ctx, key), "create api key")
 
@@ -0,0 +1,319 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// soraGenerationJobRepository 实现 service.SoraGenerationJobRepository 接口。
+// 使用原生 SQL 操作 sora_generation_jobs 表。
+type soraGenerationJobRepository struct {
+	sql *sql.DB
+}
+
+// NewSoraGenerationJobRepository 创建 Sora 生成任务队列仓储实例。
+func NewSoraGenerationJobRepository(sqlDB *sql.DB) service.SoraGenerationJobRepository {
+	return &soraGenerationJobRepository{sql: sqlDB}
+}
+
+const soraGenerationJobColumns = `id, generation_id, user_id, group_id, model, prompt, media_type, image_input, video_count,
+	status, attempts, max_attempts, run_after, lease_owner, lease_expires_at, heartbeat_at,
+	account_id, upstream_task_id, storage_type, storage_paths, storage_bytes, usage_added,
+	last_error, finished_at, created_at, updated_at`
+
+func (r *soraGenerationJobRepository) Enqueue(ctx context.Context, job *service.SoraGenerationJob) error {
+	return r.sql.QueryRowContext(ctx, `
+		INSERT INTO sora_generation_jobs (
+			generation_id, user_id, group_id, model, prompt, media_type, image_input, video_count,
+			status, max_attempts, run_after
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11)
+		RETURNING id, created_at, updated_at
+	`,
+		job.GenerationID, job.UserID, job.GroupID, job.Model, job.Prompt, job.MediaType, job.ImageInput, job.VideoCount,
+		job.Status, job.MaxAttempts, job.RunAfter,
+	).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+}
+
+func (r *soraGenerationJobRepository) ClaimNext(ctx context.Context, owner string, lease time.Duration) (*service.SoraGenerationJob, error) {
+	job, err := scanSoraGenerationJob(r.sql.QueryRowContext(ctx, `
+		WITH next AS (
+			SELECT id
+			FROM sora_generation_jobs
+			WHERE (status = $1 AND run_after <= NOW())
+				OR (status = $2 AND lease_expires_at < NOW() AND attempts < max_attempts)
+			ORDER BY run_after ASC, id ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE sora_generation_jobs AS jobs
+		SET status = $2,
+			attempts = jobs.attempts + 1,
+			lease_owner = $3,
+			lease_expires_at = NOW() + ($4 * interval '1 millisecond'),
+			heartbeat_at = NOW(),
+			updated_at = NOW()
+		FROM next
+		WHERE jobs.id = next.id
+		RETURNING `+prefixedSoraGenerationJobColumns("jobs"),
+		service.SoraJobStatusQueued, service.SoraJobStatusRunning, owner, lease.Milliseconds()))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *soraGenerationJobRepository) Heartbeat(ctx context.Context, id int64, owner string, lease time.Duration) (bool, error) {
+	result, err := r.sql.ExecContext(ctx, `
+		UPDATE sora_generation_jobs
+		SET lease_expires_at = NOW() + ($4 * interval '1 millisecond'), heartbeat_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $2 AND lease_owner = $3
+	`, id, service.SoraJobStatusRunning, owner, lease.Milliseconds())
+	if err != nil {
+		return false, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+	return affected > 0, nil
+}
+
+// RecordUpstreamTask 同时写入任务与生成记录，生成记录的 upstream_task_id 便于用户侧排查。
+func (r *soraGenerationJobRepository) RecordUpstreamTask(ctx context.Context, id int64, owner string, accountID int64, taskID string) error {
+	_, err := r.sql.ExecContext(ctx, `
+		WITH job AS (
+			UPDATE sora_generation_jobs
+			SET account_id = $4, upstream_task_id = $5, updated_at = NOW()
+			WHERE id = $1 AND status = $2 AND lease_owner = $3
+			RETURNING generation_id
+		)
+		UPDATE sora_generations AS gen
+		SET upstream_task_id = $5
+		FROM job
+		WHERE gen.id = job.generation_id AND gen.status IN ($6, $7)
+	`, id, service.SoraJobStatusRunning, owner, accountID, taskID,
+		service.SoraGenStatusPending, service.SoraGenStatusGenerating)
+	return err
+}
+
+func (r *soraGenerationJobRepository) RecordStorage(ctx context.Context, id int64, owner string, storageType string, paths []string, bytes int64, usageAdded bool) error {
+	var pathsJSON []byte
+	if len(paths) > 0 {
+		var err error
+		if pathsJSON, err = json.Marshal(paths); err != nil {
+			return err
+		}
+	}
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE sora_generation_jobs
+		SET storage_type = $4, storage_paths = $5, storage_bytes = $6, usage_added = $7, updated_at = NOW()
+		WHERE id = $1 AND status = $2 AND lease_owner = $3
+	`, id, service.SoraJobStatusRunning, owner, storageType, pathsJSON, bytes, usageAdded)
+	return err
+}
+
+func (r *soraGenerationJobRepository) MarkSucceeded(ctx context.Context, id int64, owner string) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE sora_generation_jobs
+		SET status = $4, lease_owner = '', lease_expires_at = NULL, last_error = '',
+			finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $2 AND lease_owner = $3
+	`, id, service.SoraJobStatusRunning, owner, service.SoraJobStatusSucceeded)
+	return err
+}
+
+func (r *soraGenerationJobRepository) MarkRetry(ctx context.Context, id int64, owner string, runAfter time.Time, errMsg string) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE sora_generation_jobs
+		SET status = $4, run_after = $5, last_error = $6, lease_owner = '', lease_expires_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = $2 AND lease_owner = $3
+	`, id, service.SoraJobStatusRunning, owner, service.SoraJobStatusQueued, runAfter, errMsg)
+	return err
+}
+
+func (r *soraGenerationJobRepository) MarkFailed(ctx context.Context, id int64, owner string, errMsg string) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE sora_generation_jobs
+		SET status = $4, last_error = $5, lease_owner = '', lease_expires_at = NULL,
+			finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = $2 AND lease_owner = $3
+	`, id, service.SoraJobStatusRunning, owner, service.SoraJobStatusFailed, errMsg)
+	return err
+}
+
+func (r *soraGenerationJobRepository) Release(ctx context.Context, id int64, owner string) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE sora_generation_jobs
+		SET status = $4, attempts = GREATEST(attempts - 1, 0), run_after = NOW(),
+			lease_owner = '', lease_expires_at = NULL, updated_at = NOW()
+		WHERE id = $1 AND status = $2 AND lease_owner = $3
+	`, id, service.SoraJobStatusRunning, owner, service.SoraJobStatusQueued)
+	return err
+}
+
+func (r *soraGenerationJobRepository) ClaimExhausted(ctx context.Context, errMsg string, limit int) ([]*service.SoraGenerationJob, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	return r.queryJobs(ctx, `
+		WITH stale AS (
+			SELECT id
+			FROM sora_generation_jobs
+			WHERE status = $1 AND lease_expires_at < NOW() AND attempts >= max_attempts
+			ORDER BY lease_expires_at ASC
+			LIMIT $4
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE sora_generation_jobs AS jobs
+		SET status = $2, last_error = $3, finished_at = NOW(), updated_at = NOW()
+		FROM stale
+		WHERE jobs.id = stale.id
+		RETURNING `+prefixedSoraGenerationJobColumns("jobs"),
+		service.SoraJobStatusRunning, service.SoraJobStatusFailed, errMsg, limit)
+}
+
+func (r *soraGenerationJobRepository) ListOrphanGenerationIDs(ctx context.Context, createdBefore time.Time, limit int) ([]int64, error) {
+	if limit <= 0 {
+		limit = 100
+	}
+	rows, err := r.sql.QueryContext(ctx, `
+		SELECT gen.id
+		FROM sora_generations AS gen
+		WHERE gen.status IN ($1, $2)
+			AND gen.created_at < $3
+			AND NOT EXISTS (
+				SELECT 1 FROM sora_generation_jobs AS jobs
+				WHERE jobs.generation_id = gen.id AND jobs.status IN ($4, $5)
+			)
+		ORDER BY gen.id ASC
+		LIMIT $6
+	`, service.SoraGenStatusPending, service.SoraGenStatusGenerating, createdBefore,
+		service.SoraJobStatusQueued, service.SoraJobStatusRunning, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := make([]int64, 0)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *soraGenerationJobRepository) DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error) {
+	result, err := r.sql.ExecContext(ctx, `
+		DELETE FROM sora_generation_jobs
+		WHERE status IN ($1, $2) AND finished_at IS NOT NULL AND finished_at < $3
+	`, service.SoraJobStatusSucceeded, service.SoraJobStatusFailed, before)
+	if err != nil {
+		return 0, err
+	}
+	return result.RowsAffected()
+}
+
+func (r *soraGenerationJobRepository) queryJobs(ctx context.Context, query string, args ...any) ([]*service.SoraGenerationJob, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	jobs := make([]*service.SoraGenerationJob, 0)
+	for rows.Next() {
+		job, err := scanSoraGenerationJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, job)
+	}
+	return jobs, rows.Err()
+}
+
+// prefixedSoraGenerationJobColumns 为 UPDATE ... FROM 的 RETURNING 子句加表别名，避免列名歧义。
+func prefixedSoraGenerationJobColumns(alias string) string {
+	cols := strings.Split(soraGenerationJobColumns, ",")
+	for i, col := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(col)
+	}
+	return strings.Join(cols, ", ")
+}
+
+func scanSoraGenerationJob(scanner interface{ Scan(...any) error }) (*service.SoraGenerationJob, error) {
+	var (
+		job            service.SoraGenerationJob
+		groupID        sql.NullInt64
+		accountID      sql.NullInt64
+		leaseExpiresAt sql.NullTime
+		heartbeatAt    sql.NullTime
+		finishedAt     sql.NullTime
+		pathsJSON      []byte
+	)
+	if err := scanner.Scan(
+		&job.ID,
+		&job.GenerationID,
+		&job.UserID,
+		&groupID,
+		&job.Model,
+		&job.Prompt,
+		&job.MediaType,
+		&job.ImageInput,
+		&job.VideoCount,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.RunAfter,
+		&job.LeaseOwner,
+		&leaseExpiresAt,
+		&heartbeatAt,
+		&accountID,
+		&job.UpstreamTaskID,
+		&job.StorageType,
+		&pathsJSON,
+		&job.StorageBytes,
+		&job.UsageAdded,
+		&job.LastError,
+		&finishedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if groupID.Valid {
+		v := groupID.Int64
+		job.GroupID = &v
+	}
+	if accountID.Valid {
+		v := accountID.Int64
+		job.AccountID = &v
+	}
+	if leaseExpiresAt.Valid {
+		t := leaseExpiresAt.Time
+		job.LeaseExpiresAt = &t
+	}
+	if heartbeatAt.Valid {
+		t := heartbeatAt.Time
+		job.HeartbeatAt = &t
+	}
+	if finishedAt.Valid {
+		t := finishedAt.Time
+		job.FinishedAt = &t
+	}
+	if len(pathsJSON) > 0 {
+		_ = json.Unmarshal(pathsJSON, &job.StoragePaths)
+	}
+	return &job, nil
+}
@@ -0,0 +1,412 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// statementRepository 实现 service.StatementRepository 接口。
+// 使用原生 SQL 操作 billing_statements / billing_statement_lines 表，并从
+// usage_logs、subscription_orders、redeem_codes、promo_code_usages 聚合账期数据。
+type statementRepository struct {
+	sql *sql.DB
+}
+
+// NewStatementRepository 创建月度账单仓储实例。
+func NewStatementRepository(sqlDB *sql.DB) service.StatementRepository {
+	return &statementRepository{sql: sqlDB}
+}
+
+const statementColumns = `id, user_id, period, period_start, period_end, timezone, currency, user_email, username,
+	request_count, usage_total_cost, usage_balance_cost, usage_subscription_cost, subscription_fees, top_ups,
+	refunds, credits, line_count, emailed_at, created_at`
+
+const statementLineColumns = `line_no, line_type, description, group_id, group_name, model, billing_type, reference,
+	request_count, input_tokens, output_tokens, cache_creation_tokens, cache_read_tokens, image_count, video_count,
+	total_cost, amount, occurred_at`
+
+func (r *statementRepository) Create(ctx context.Context, st *service.Statement) error {
+	tx, err := r.sql.BeginTx(ctx, nil)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = tx.Rollback() }()
+
+	err = tx.QueryRowContext(ctx, `
+		INSERT INTO billing_statements (
+			user_id, period, period_start, period_end, timezone, currency, user_email, username,
+			request_count, usage_total_cost, usage_balance_cost, usage_subscription_cost,
+			subscription_fees, top_ups, refunds, credits, line_count
+		) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
+		ON CONFLICT (user_id, period) DO NOTHING
+		RETURNING id, created_at
+	`, st.UserID, st.Period, st.PeriodStart, st.PeriodEnd, st.Timezone, st.Currency, st.UserEmail, st.Username,
+		st.RequestCount, st.UsageTotalCost, st.UsageBalanceCost, st.UsageSubscriptionCost,
+		st.SubscriptionFees, st.TopUps, st.Refunds, st.Credits, st.LineCount,
+	).Scan(&st.ID, &st.CreatedAt)
+	if errors.Is(err, sql.ErrNoRows) {
+		return service.ErrStatementExists
+	}
+	if err != nil {
+		return err
+	}
+
+	// 明细分批插入，避免超出参数数量上限
+	const cols = 19
+	const chunk = 500
+	for offset := 0; offset < len(st.Lines); offset += chunk {
+		end := offset + chunk
+		if end > len(st.Lines) {
+			end = len(st.Lines)
+		}
+		values := make([]string, 0, end-offset)
+		args := make([]any, 0, (end-offset)*cols)
+		for i := offset; i < end; i++ {
+			line := &st.Lines[i]
+			base := len(args)
+			placeholders := make([]string, cols)
+			for j := range placeholders {
+				placeholders[j] = fmt.Sprintf("$%d", base+j+1)
+			}
+			values = append(values, "("+strings.Join(placeholders, ", ")+")")
+			args = append(args,
+				st.ID, line.LineNo, line.LineType, truncateStatementText(line.Description, 255), line.GroupID,
+				truncateStatementText(line.GroupName, 100), line.Model, line.BillingType, line.Reference,
+				line.RequestCount, line.InputTokens, line.OutputTokens, line.CacheCreationTokens, line.CacheReadTokens,
+				line.ImageCount, line.VideoCount, line.TotalCost, line.Amount, line.OccurredAt,
+			)
+		}
+		if _, err := tx.ExecContext(ctx, `INSERT INTO billing_statement_lines (statement_id, `+statementLineColumns+`) VALUES `+strings.Join(values, ", "), args...); err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+func (r *statementRepository) GetByID(ctx context.Context, id int64) (*service.Statement, error) {
+	st, err := scanStatement(r.sql.QueryRowContext(ctx,
+		`SELECT `+statementColumns+` FROM billing_statements WHERE id = $1`, id))
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrStatementNotFound, nil)
+	}
+
+	rows, err := r.sql.QueryContext(ctx,
+		`SELECT `+statementLineColumns+` FROM billing_statement_lines WHERE statement_id = $1 ORDER BY line_no ASC`, id)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	st.Lines = make([]service.StatementLine, 0, st.LineCount)
+	for rows.Next() {
+		line, err := scanStatementLine(rows)
+		if err != nil {
+			return nil, err
+		}
+		st.Lines = append(st.Lines, *line)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return st, nil
+}
+
+func (r *statementRepository) List(ctx context.Context, params pagination.PaginationParams, filter service.StatementFilter) ([]service.Statement, *pagination.PaginationResult, error) {
+	conditions := make([]string, 0, 2)
+	args := make([]any, 0, 4)
+	if filter.UserID != nil {
+		args = append(args, *filter.UserID)
+		conditions = append(conditions, fmt.Sprintf("user_id = $%d", len(args)))
+	}
+	if filter.Period != "" {
+		args = append(args, filter.Period)
+		conditions = append(conditions, fmt.Sprintf("period = $%d", len(args)))
+	}
+	whereClause := buildWhere(conditions)
+
+	var total int64
+	if err := scanSingleRow(ctx, r.sql, "SELECT COUNT(*) FROM billing_statements "+whereClause, args, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.Statement{}, paginationResultFromTotal(0, params), nil
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM billing_statements %s ORDER BY period DESC, id DESC LIMIT $%d OFFSET $%d`,
+		statementColumns, whereClause, len(args)+1, len(args)+2)
+	rows, err := r.sql.QueryContext(ctx, query, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.Statement, 0, params.Limit())
+	for rows.Next() {
+		st, err := scanStatement(rows)
+		if err != nil {
+			return nil, nil, err
+		}
+		out = append(out, *st)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, nil, err
+	}
+	return out, paginationResultFromTotal(total, params), nil
+}
+
+func (r *statementRepository) MarkEmailed(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.sql.ExecContext(ctx, `UPDATE billing_statements SET emailed_at = $2 WHERE id = $1`, id, at)
+	return err
+}
+
+func (r *statementRepository) ListUsersPendingStatement(ctx context.Context, period string, start, end time.Time, limit int) ([]int64, error) {
+	rows, err := r.sql.QueryContext(ctx, `
+		SELECT u.id
+		FROM users u
+		WHERE u.deleted_at IS NULL
+			AND NOT EXISTS (SELECT 1 FROM billing_statements bs WHERE bs.user_id = u.id AND bs.period = $1)
+			AND (
+				EXISTS (SELECT 1 FROM usage_logs ul WHERE ul.user_id = u.id AND ul.created_at >= $2 AND ul.created_at < $3)
+				OR EXISTS (
+					SELECT 1 FROM subscription_orders so
+					WHERE so.user_id = u.id
+						AND ((so.paid_at >= $2 AND so.paid_at < $3) OR (so.refunded_at >= $2 AND so.refunded_at < $3))
+				)
+				OR EXISTS (
+					SELECT 1 FROM redeem_codes rc
+					WHERE rc.used_by = u.id AND rc.used_at >= $2 AND rc.used_at < $3 AND rc.type IN ($5, $6, $7)
+				)
+				OR EXISTS (SELECT 1 FROM promo_code_usages pu WHERE pu.user_id = u.id AND pu.used_at >= $2 AND pu.used_at < $3)
+			)
+		ORDER BY u.id ASC
+		LIMIT $4
+	`, period, start, end, limit, service.RedeemTypeBalance, service.AdjustmentTypeAdminBalance, service.RedeemTypeSubscription)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	ids := make([]int64, 0, limit)
+	for rows.Next() {
+		var id int64
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
+
+func (r *statementRepository) LoadSource(ctx context.Context, userID int64, start, end time.Time) (*service.StatementSource, error) {
+	source := &service.StatementSource{}
+
+	usageRows, err := r.sql.QueryContext(ctx, `
+		SELECT
+			ul.group_id,
+			COALESCE(g.name, ''),
+			ul.model,
+			ul.billing_type,
+			COUNT(*),
+			COALESCE(SUM(ul.input_tokens), 0),
+			COALESCE(SUM(ul.output_tokens), 0),
+			COALESCE(SUM(ul.cache_creation_tokens), 0),
+			COALESCE(SUM(ul.cache_read_tokens), 0),
+			COALESCE(SUM(CASE WHEN ul.media_type = 'video' THEN 0 ELSE COALESCE(ul.image_count, 0) END), 0),
+			COUNT(*) FILTER (WHERE ul.media_type = 'video'),
+			COALESCE(SUM(ul.total_cost), 0),
+			COALESCE(SUM(ul.actual_cost), 0)
+		FROM usage_logs ul
+		LEFT JOIN groups g ON g.id = ul.group_id
+		WHERE ul.user_id = $1 AND ul.created_at >= $2 AND ul.created_at < $3
+		GROUP BY ul.group_id, g.name, ul.model, ul.billing_type
+		ORDER BY COALESCE(g.name, ''), ul.model, ul.billing_type
+	`, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = usageRows.Close() }()
+	for usageRows.Next() {
+		var (
+			line        service.StatementLine
+			groupID     sql.NullInt64
+			billingType int16
+		)
+		if err := usageRows.Scan(
+			&groupID, &line.GroupName, &line.Model, &billingType,
+			&line.RequestCount, &line.InputTokens, &line.OutputTokens, &line.CacheCreationTokens, &line.CacheReadTokens,
+			&line.ImageCount, &line.VideoCount, &line.TotalCost, &line.Amount,
+		); err != nil {
+			return nil, err
+		}
+		if groupID.Valid {
+			line.GroupID = &groupID.Int64
+		}
+		bt := int8(billingType)
+		line.BillingType = &bt
+		line.LineType = service.StatementLineUsage
+		source.Usage = append(source.Usage, line)
+	}
+	if err := usageRows.Err(); err != nil {
+		return nil, err
+	}
+
+	// 订单 / 退款 / 兑换码 / 优惠码统一为 (类型, 描述, 分组, 引用, 金额, 时间)
+	creditRows, err := r.sql.QueryContext(ctx, `
+		SELECT line_type, description, group_id, group_name, reference, amount, occurred_at FROM (
+			SELECT
+				CASE WHEN so.order_type = $4 THEN 'top_up' ELSE 'subscription' END AS line_type,
+				CASE WHEN so.currency <> 'USD' THEN 'Paid ' || TO_CHAR(so.amount, 'FM999999990.00') || ' ' || so.currency ELSE '' END AS description,
+				so.group_id, COALESCE(g.name, '') AS group_name, so.order_no AS reference,
+				CASE WHEN so.order_type = $4 THEN so.credit_amount ELSE so.amount END AS amount,
+				so.paid_at AS occurred_at
+			FROM subscription_orders so
+			LEFT JOIN groups g ON g.id = so.group_id
+			WHERE so.user_id = $1 AND so.paid_at >= $2 AND so.paid_at < $3 AND so.status IN ($5, $6, $10)
+			UNION ALL
+			SELECT
+				'refund', '', so.group_id, COALESCE(g.name, ''), so.order_no,
+				-(CASE WHEN so.order_type = $4 THEN so.credit_amount ELSE so.amount END),
+				so.refunded_at
+			FROM subscription_orders so
+			LEFT JOIN groups g ON g.id = so.group_id
+			WHERE so.user_id = $1 AND so.refunded_at >= $2 AND so.refunded_at < $3 AND so.status = $6
+			UNION ALL
+			SELECT
+				CASE rc.type WHEN $7 THEN 'adjustment' WHEN $8 THEN 'subscription' ELSE 'redeem' END,
+				CASE WHEN rc.type = $8 THEN 'Redeemed ' || rc.validity_days || ' days' ELSE '' END,
+				rc.group_id, COALESCE(g.name, ''),
+				CASE WHEN rc.type = $7 THEN '' ELSE rc.code END,
+				CASE WHEN rc.type = $8 THEN 0 ELSE rc.value END,
+				rc.used_at
+			FROM redeem_codes rc
+			LEFT JOIN groups g ON g.id = rc.group_id
+			WHERE rc.used_by = $1 AND rc.used_at >= $2 AND rc.used_at < $3 AND rc.type IN ($9, $7, $8)
+			UNION ALL
+			SELECT 'promo', '', NULL, '', pc.code, pu.bonus_amount, pu.used_at
+			FROM promo_code_usages pu
+			JOIN promo_codes pc ON pc.id = pu.promo_code_id
+			WHERE pu.user_id = $1 AND pu.used_at >= $2 AND pu.used_at < $3
+		) credits
+		ORDER BY occurred_at ASC, line_type ASC
+	`, userID, start, end,
+		service.OrderTypeBalance, service.OrderStatusPaid, service.OrderStatusRefunded,
+		service.AdjustmentTypeAdminBalance, service.RedeemTypeSubscription, service.RedeemTypeBalance,
+		service.OrderStatusRefunding)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = creditRows.Close() }()
+	for creditRows.Next() {
+		var (
+			line       service.StatementLine
+			groupID    sql.NullInt64
+			occurredAt sql.NullTime
+		)
+		if err := creditRows.Scan(&line.LineType, &line.Description, &groupID, &line.GroupName, &line.Reference, &line.Amount, &occurredAt); err != nil {
+			return nil, err
+		}
+		if groupID.Valid {
+			line.GroupID = &groupID.Int64
+		}
+		if occurredAt.Valid {
+			line.OccurredAt = &occurredAt.Time
+		}
+		source.Credits = append(source.Credits, line)
+	}
+	if err := creditRows.Err(); err != nil {
+		return nil, err
+	}
+	return source, nil
+}
+
+func scanStatement(scanner interface{ Scan(...any) error }) (*service.Statement, error) {
+	var (
+		st        service.Statement
+		emailedAt sql.NullTime
+	)
+	if err := scanner.Scan(
+		&st.ID,
+		&st.UserID,
+		&st.Period,
+		&st.PeriodStart,
+		&st.PeriodEnd,
+		&st.Timezone,
+		&st.Currency,
+		&st.UserEmail,
+		&st.Username,
+		&st.RequestCount,
+		&st.UsageTotalCost,
+		&st.UsageBalanceCost,
+		&st.UsageSubscriptionCost,
+		&st.SubscriptionFees,
+		&st.TopUps,
+		&st.Refunds,
+		&st.Credits,
+		&st.LineCount,
+		&emailedAt,
+		&st.CreatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if emailedAt.Valid {
+		st.EmailedAt = &emailedAt.Time
+	}
+	return &st, nil
+}
+
+func scanStatementLine(scanner interface{ Scan(...any) error }) (*service.StatementLine, error) {
+	var (
+		line        service.StatementLine
+		groupID     sql.NullInt64
+		billingType sql.NullInt16
+		occurredAt  sql.NullTime
+	)
+	if err := scanner.Scan(
+		&line.LineNo,
+		&line.LineType,
+		&line.Description,
+		&groupID,
+		&line.GroupName,
+		&line.Model,
+		&billingType,
+		&line.Reference,
+		&line.RequestCount,
+		&line.InputTokens,
+		&line.OutputTokens,
+		&line.CacheCreationTokens,
+		&line.CacheReadTokens,
+		&line.ImageCount,
+		&line.VideoCount,
+		&line.TotalCost,
+		&line.Amount,
+		&occurredAt,
+	); err != nil {
+		return nil, err
+	}
+	if groupID.Valid {
+		line.GroupID = &groupID.Int64
+	}
+	if billingType.Valid {
+		bt := int8(billingType.Int16)
+		line.BillingType = &bt
+	}
+	if occurredAt.Valid {
+		line.OccurredAt = &occurredAt.Time
+	}
+	return &line, nil
+}
+
+func truncateStatementText(s string, max int) string {
+	r := []rune(s)
+	if len(r) <= max {
+		return s
+	}
+	return string(r[:max])
+}
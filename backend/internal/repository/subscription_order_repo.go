@@ -27,7 +27,8 @@ func (r *subscriptionOrderRepository) Create(ctx context.Context, order *service
 	created, err := client.SubscriptionOrder.Create().
 		SetOrderNo(order.OrderNo).
 		SetUserID(order.UserID).
-		SetGroupID(order.GroupID).
+		SetNillableGroupID(order.GroupID).
+		SetOrderType(orderTypeOrDefault(order.OrderType)).
 		SetNillableSubscriptionID(order.SubscriptionID).
 		SetPaymentProvider(order.PaymentProvider).
 		SetNillablePaymentURL(nullableString(order.PaymentURL)).
@@ -39,8 +40,10 @@ func (r *subscriptionOrderRepository) Create(ctx context.Context, order *service
 		SetAmount(order.Amount).
 		SetCurrency(order.Currency).
 		SetValidityDays(order.ValidityDays).
+		SetCreditAmount(order.CreditAmount).
 		SetNillablePaidAt(order.PaidAt).
 		SetNillableCanceledAt(order.CanceledAt).
+		SetNillableRefundedAt(order.RefundedAt).
 		SetNotes(order.Notes).
 		Save(ctx)
 	if err != nil {
@@ -93,6 +96,9 @@ func (r *subscriptionOrderRepository) List(ctx context.Context, params paginatio
 	if filters.GroupID != nil {
 		q = q.Where(subscriptionorder.GroupIDEQ(*filters.GroupID))
 	}
+	if filters.OrderType != "" {
+		q = q.Where(subscriptionorder.OrderTypeEQ(filters.OrderType))
+	}
 
 	total, err := q.Count(ctx)
 	if err != nil {
@@ -129,6 +135,7 @@ func (r *subscriptionOrderRepository) Update(ctx context.Context, order *service
 		SetAmount(order.Amount).
 		SetCurrency(order.Currency).
 		SetValidityDays(order.ValidityDays).
+		SetCreditAmount(order.CreditAmount).
 		SetNotes(order.Notes)
 
 	if order.PaymentURL != "" {
@@ -172,6 +179,11 @@ func (r *subscriptionOrderRepository) Update(ctx context.Context, order *service
 	} else {
 		builder = builder.ClearCanceledAt()
 	}
+	if order.RefundedAt != nil {
+		builder = builder.SetRefundedAt(*order.RefundedAt)
+	} else {
+		builder = builder.ClearRefundedAt()
+	}
 
 	_, err := builder.Save(ctx)
 	return translatePersistenceError(err, service.ErrOrderNotFound, nil)
@@ -194,6 +206,18 @@ func (r *subscriptionOrderRepository) UpdateStatus(ctx context.Context, id int64
 	return translatePersistenceError(err, service.ErrOrderNotFound, nil)
 }
 
+func (r *subscriptionOrderRepository) TransitionStatus(ctx context.Context, id int64, from []string, to string) (bool, error) {
+	client := clientFromContext(ctx, r.client)
+	n, err := client.SubscriptionOrder.Update().
+		Where(subscriptionorder.IDEQ(id), subscriptionorder.StatusIn(from...)).
+		SetStatus(to).
+		Save(ctx)
+	if err != nil {
+		return false, err
+	}
+	return n == 1, nil
+}
+
 func (r *subscriptionOrderRepository) SetSubscriptionID(ctx context.Context, id int64, subscriptionID int64) error {
 	client := clientFromContext(ctx, r.client)
 	_, err := client.SubscriptionOrder.UpdateOneID(id).
@@ -226,8 +250,14 @@ func applySubscriptionOrderEntityToService(out *service.SubscriptionOrder, m *en
 	}
 	out.ID = m.ID
 	out.OrderNo = m.OrderNo
+	out.OrderType = m.OrderType
 	out.UserID = m.UserID
-	out.GroupID = m.GroupID
+	if m.GroupID != nil {
+		value := *m.GroupID
+		out.GroupID = &value
+	} else {
+		out.GroupID = nil
+	}
 	if m.SubscriptionID != nil {
 		value := *m.SubscriptionID
 		out.SubscriptionID = &value
@@ -244,13 +274,22 @@ func applySubscriptionOrderEntityToService(out *service.SubscriptionOrder, m *en
 	out.Amount = m.Amount
 	out.Currency = m.Currency
 	out.ValidityDays = m.ValidityDays
+	out.CreditAmount = m.CreditAmount
 	out.PaidAt = m.PaidAt
 	out.CanceledAt = m.CanceledAt
+	out.RefundedAt = m.RefundedAt
 	out.Notes = derefString(m.Notes)
 	out.CreatedAt = m.CreatedAt
 	out.UpdatedAt = m.UpdatedAt
 }
 
+func orderTypeOrDefault(orderType string) string {
+	if orderType == "" {
+		return service.OrderTypeSubscription
+	}
+	return orderType
+}
+
 func nullableString(value string) *string {
 	if strings.TrimSpace(value) == "" {
 		return nil
@@ -0,0 +1,76 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/enttest"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/stretchr/testify/require"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	_ "modernc.org/sqlite"
+)
+
+func newSubscriptionOrderEntRepo(t *testing.T) (*subscriptionOrderRepository, *dbent.Client) {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:subscription_order_repo?mode=memory&cache=shared")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec("PRAGMA foreign_keys = ON")
+	require.NoError(t, err)
+
+	drv := entsql.OpenDB(dialect.SQLite, db)
+	client := enttest.NewClient(t, enttest.WithOptions(dbent.Driver(drv)))
+	t.Cleanup(func() { _ = client.Close() })
+
+	return &subscriptionOrderRepository{client: client}, client
+}
+
+func TestSubscriptionOrderRepositoryTransitionStatus(t *testing.T) {
+	repo, client := newSubscriptionOrderEntRepo(t)
+	ctx := context.Background()
+	user, err := client.User.Create().
+		SetEmail("order-transition@test.com").
+		SetPasswordHash("test-password-hash").
+		SetRole(service.RoleUser).
+		SetStatus(service.StatusActive).
+		Save(ctx)
+	require.NoError(t, err)
+
+	order := &service.SubscriptionOrder{
+		OrderNo:         "SO-TRANSITION",
+		UserID:          user.ID,
+		OrderType:       service.OrderTypeBalance,
+		PaymentProvider: service.PaymentProviderManual,
+		Status:          service.OrderStatusPending,
+		Amount:          10,
+		Currency:        "CNY",
+		CreditAmount:    10,
+	}
+	require.NoError(t, repo.Create(ctx, order))
+
+	ok, err := repo.TransitionStatus(ctx, order.ID, []string{service.OrderStatusPending}, service.OrderStatusPaid)
+	require.NoError(t, err)
+	require.True(t, ok)
+
+	// 状态已变化，再次流转不应命中
+	ok, err = repo.TransitionStatus(ctx, order.ID, []string{service.OrderStatusPending}, service.OrderStatusPaid)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	ok, err = repo.TransitionStatus(ctx, order.ID, []string{service.OrderStatusPending, service.OrderStatusCanceled}, service.OrderStatusPaid)
+	require.NoError(t, err)
+	require.False(t, ok)
+
+	got, err := repo.GetByID(ctx, order.ID)
+	require.NoError(t, err)
+	require.Equal(t, service.OrderStatusPaid, got.Status)
+
+	ok, err = repo.TransitionStatus(ctx, order.ID+1000, []string{service.OrderStatusPaid}, service.OrderStatusRefunded)
+	require.NoError(t, err)
+	require.False(t, ok)
+}
@@ -0,0 +1,221 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// usageExportRepository 实现 service.UsageExportRepository 接口。
+// 使用原生 SQL 操作 usage_export_jobs 表。
+type usageExportRepository struct {
+	sql *sql.DB
+}
+
+// NewUsageExportRepository 创建使用记录导出任务仓储实例。
+func NewUsageExportRepository(sqlDB *sql.DB) service.UsageExportRepository {
+	return &usageExportRepository{sql: sqlDB}
+}
+
+const usageExportJobColumns = `id, scope, format, status, filters, created_by, row_count, file_size, storage, object_key,
+	error_message, started_at, finished_at, expires_at, created_at, updated_at`
+
+func (r *usageExportRepository) CreateJob(ctx context.Context, job *service.UsageExportJob) error {
+	filtersJSON, err := json.Marshal(job.Filters)
+	if err != nil {
+		return fmt.Errorf("marshal export filters: %w", err)
+	}
+	return r.sql.QueryRowContext(ctx, `
+		INSERT INTO usage_export_jobs (scope, format, status, filters, created_by)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at, updated_at
+	`, job.Scope, job.Format, job.Status, filtersJSON, job.CreatedBy,
+	).Scan(&job.ID, &job.CreatedAt, &job.UpdatedAt)
+}
+
+func (r *usageExportRepository) GetJob(ctx context.Context, id int64) (*service.UsageExportJob, error) {
+	job, err := scanUsageExportJob(r.sql.QueryRowContext(ctx,
+		`SELECT `+usageExportJobColumns+` FROM usage_export_jobs WHERE id = $1`, id))
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrUsageExportJobNotFound, nil)
+	}
+	return job, nil
+}
+
+func (r *usageExportRepository) ListJobs(ctx context.Context, params pagination.PaginationParams, filter service.UsageExportJobFilter) ([]service.UsageExportJob, *pagination.PaginationResult, error) {
+	conditions := []string{"scope = $1"}
+	args := []any{filter.Scope}
+	if filter.CreatedBy != nil {
+		conditions = append(conditions, fmt.Sprintf("created_by = $%d", len(args)+1))
+		args = append(args, *filter.CreatedBy)
+	}
+	whereClause := buildWhere(conditions)
+
+	var total int64
+	if err := scanSingleRow(ctx, r.sql, "SELECT COUNT(*) FROM usage_export_jobs "+whereClause, args, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.UsageExportJob{}, paginationResultFromTotal(0, params), nil
+	}
+
+	query := fmt.Sprintf(`SELECT %s FROM usage_export_jobs %s ORDER BY created_at DESC, id DESC LIMIT $%d OFFSET $%d`,
+		usageExportJobColumns, whereClause, len(args)+1, len(args)+2)
+	jobs, err := r.queryJobs(ctx, query, append(args, params.Limit(), params.Offset())...)
+	if err != nil {
+		return nil, nil, err
+	}
+	return jobs, paginationResultFromTotal(total, params), nil
+}
+
+func (r *usageExportRepository) ClaimNextPendingJob(ctx context.Context, staleRunningAfterSeconds int64) (*service.UsageExportJob, error) {
+	if staleRunningAfterSeconds <= 0 {
+		staleRunningAfterSeconds = 3600
+	}
+	job, err := scanUsageExportJob(r.sql.QueryRowContext(ctx, `
+		WITH next AS (
+			SELECT id
+			FROM usage_export_jobs
+			WHERE status = $1
+				OR (
+					status = $2
+					AND started_at IS NOT NULL
+					AND started_at < NOW() - ($3 * interval '1 second')
+				)
+			ORDER BY created_at ASC
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE usage_export_jobs AS jobs
+		SET status = $2,
+			row_count = 0,
+			started_at = NOW(),
+			finished_at = NULL,
+			error_message = NULL,
+			updated_at = NOW()
+		FROM next
+		WHERE jobs.id = next.id
+		RETURNING jobs.id, jobs.scope, jobs.format, jobs.status, jobs.filters, jobs.created_by, jobs.row_count,
+			jobs.file_size, jobs.storage, jobs.object_key, jobs.error_message, jobs.started_at, jobs.finished_at,
+			jobs.expires_at, jobs.created_at, jobs.updated_at
+	`, service.UsageExportStatusPending, service.UsageExportStatusRunning, staleRunningAfterSeconds))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return job, nil
+}
+
+func (r *usageExportRepository) UpdateJobProgress(ctx context.Context, id int64, rowCount int64) error {
+	_, err := r.sql.ExecContext(ctx,
+		`UPDATE usage_export_jobs SET row_count = $2, updated_at = NOW() WHERE id = $1`, id, rowCount)
+	return err
+}
+
+func (r *usageExportRepository) MarkJobSucceeded(ctx context.Context, job *service.UsageExportJob) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE usage_export_jobs
+		SET status = $2, row_count = $3, file_size = $4, storage = $5, object_key = $6,
+			expires_at = $7, finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, job.ID, service.UsageExportStatusSucceeded, job.RowCount, job.FileSize, job.Storage, job.ObjectKey, job.ExpiresAt)
+	return err
+}
+
+func (r *usageExportRepository) MarkJobFailed(ctx context.Context, id int64, rowCount int64, errorMsg string) error {
+	_, err := r.sql.ExecContext(ctx, `
+		UPDATE usage_export_jobs
+		SET status = $2, row_count = $3, error_message = $4, finished_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`, id, service.UsageExportStatusFailed, rowCount, errorMsg)
+	return err
+}
+
+func (r *usageExportRepository) ListExpiredJobs(ctx context.Context, now time.Time, limit int) ([]service.UsageExportJob, error) {
+	return r.queryJobs(ctx, `
+		SELECT `+usageExportJobColumns+`
+		FROM usage_export_jobs
+		WHERE status = $1 AND expires_at IS NOT NULL AND expires_at <= $2
+		ORDER BY expires_at ASC
+		LIMIT $3
+	`, service.UsageExportStatusSucceeded, now, limit)
+}
+
+func (r *usageExportRepository) MarkJobExpired(ctx context.Context, id int64) error {
+	_, err := r.sql.ExecContext(ctx,
+		`UPDATE usage_export_jobs SET status = $2, updated_at = NOW() WHERE id = $1`, id, service.UsageExportStatusExpired)
+	return err
+}
+
+func (r *usageExportRepository) queryJobs(ctx context.Context, query string, args ...any) ([]service.UsageExportJob, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	jobs := make([]service.UsageExportJob, 0)
+	for rows.Next() {
+		job, err := scanUsageExportJob(rows)
+		if err != nil {
+			return nil, err
+		}
+		jobs = append(jobs, *job)
+	}
+	return jobs, rows.Err()
+}
+
+func scanUsageExportJob(scanner interface{ Scan(...any) error }) (*service.UsageExportJob, error) {
+	var (
+		job         service.UsageExportJob
+		filtersJSON []byte
+		errMsg      sql.NullString
+		startedAt   sql.NullTime
+		finishedAt  sql.NullTime
+		expiresAt   sql.NullTime
+	)
+	if err := scanner.Scan(
+		&job.ID,
+		&job.Scope,
+		&job.Format,
+		&job.Status,
+		&filtersJSON,
+		&job.CreatedBy,
+		&job.RowCount,
+		&job.FileSize,
+		&job.Storage,
+		&job.ObjectKey,
+		&errMsg,
+		&startedAt,
+		&finishedAt,
+		&expiresAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if err := json.Unmarshal(filtersJSON, &job.Filters); err != nil {
+		return nil, fmt.Errorf("parse export filters: %w", err)
+	}
+	if errMsg.Valid {
+		job.ErrorMsg = &errMsg.String
+	}
+	if startedAt.Valid {
+		job.StartedAt = &startedAt.Time
+	}
+	if finishedAt.Valid {
+		job.FinishedAt = &finishedAt.Time
+	}
+	if expiresAt.Valid {
+		job.ExpiresAt = &expiresAt.Time
+	}
+	return &job, nil
+}
@@ -1438,6 +1438,48 @@ type UsageLogFilters = usagestats.UsageLogFilters
 
 // ListWithFilters lists usage logs with optional filters (for admin)
 func (r *usageLogRepository) ListWithFilters(ctx context.Context, params pagination.PaginationParams, filters UsageLogFilters) ([]service.UsageLog, *pagination.PaginationResult, error) {
+	conditions, args := buildUsageLogFilterConditions(filters)
+
+	whereClause := buildWhere(conditions)
+	var (
+		logs []service.UsageLog
+		page *pagination.PaginationResult
+		err  error
+	)
+	if shouldUseFastUsageLogTotal(filters) {
+		logs, page, err = r.listUsageLogsWithFastPagination(ctx, whereClause, args, params)
+	} else {
+		logs, page, err = r.listUsageLogsWithPagination(ctx, whereClause, args, params)
+	}
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if err := r.hydrateUsageLogAssociations(ctx, logs); err != nil {
+		return nil, nil, err
+	}
+	return logs, page, nil
+}
+
+// ListForExport 按 id 升序游标读取一批使用记录（keyset 分页），用于大范围导出。
+func (r *usageLogRepository) ListForExport(ctx context.Context, filters UsageLogFilters, afterID int64, limit int) ([]service.UsageLog, error) {
+	conditions, args := buildUsageLogFilterConditions(filters)
+	conditions = append(conditions, fmt.Sprintf("id > $%d", len(args)+1))
+	args = append(args, afterID)
+	args = append(args, limit)
+	query := fmt.Sprintf("SELECT %s FROM usage_logs %s ORDER BY id ASC LIMIT $%d", usageLogSelectColumns, buildWhere(conditions), len(args))
+
+	logs, err := r.queryUsageLogs(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	if err := r.hydrateUsageLogAssociations(ctx, logs); err != nil {
+		return nil, err
+	}
+	return logs, nil
+}
+
+func buildUsageLogFilterConditions(filters UsageLogFilters) ([]string, []any) {
 	conditions := make([]string, 0, 8)
 	args := make([]any, 0, 8)
 
@@ -1474,26 +1516,7 @@ func (r *usageLogRepository) ListWithFilters(ctx context.Context, params paginat
 		conditions = append(conditions, fmt.Sprintf("created_at <= $%d", len(args)+1))
 		args = append(args, *filters.EndTime)
 	}
-
-	whereClause := buildWhere(conditions)
-	var (
-		logs []service.UsageLog
-		page *pagination.PaginationResult
-		err  error
-	)
-	if shouldUseFastUsageLogTotal(filters) {
-		logs, page, err = r.listUsageLogsWithFastPagination(ctx, whereClause, args, params)
-	} else {
-		logs, page, err = r.listUsageLogsWithPagination(ctx, whereClause, args, params)
-	}
-	if err != nil {
-		return nil, nil, err
-	}
-
-	if err := r.hydrateUsageLogAssociations(ctx, logs); err != nil {
-		return nil, nil, err
-	}
-	return logs, page, nil
+	return conditions, args
 }
 
 func shouldUseFastUsageLogTotal(filters UsageLogFilters) bool {
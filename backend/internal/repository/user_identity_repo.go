@@ -0,0 +1,103 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+)
+
+// userIdentityRepository 实现 service.UserIdentityRepository 接口。
+// 使用原生 SQL 操作 user_identities 表。
+type userIdentityRepository struct {
+	sql *sql.DB
+}
+
+// NewUserIdentityRepository 创建 SSO 身份绑定仓储实例。
+func NewUserIdentityRepository(sqlDB *sql.DB) service.UserIdentityRepository {
+	return &userIdentityRepository{sql: sqlDB}
+}
+
+const userIdentityColumns = `id, user_id, provider, subject, email, created_at, last_login_at`
+
+func (r *userIdentityRepository) Create(ctx context.Context, identity *service.UserIdentity) error {
+	err := r.sql.QueryRowContext(ctx, `
+		INSERT INTO user_identities (user_id, provider, subject, email, last_login_at)
+		VALUES ($1, $2, $3, $4, $5)
+		RETURNING id, created_at
+	`, identity.UserID, identity.Provider, identity.Subject, identity.Email, identity.LastLoginAt,
+	).Scan(&identity.ID, &identity.CreatedAt)
+	return translatePersistenceError(err, nil, service.ErrUserIdentityExists)
+}
+
+func (r *userIdentityRepository) GetByProviderSubject(ctx context.Context, provider, subject string) (*service.UserIdentity, error) {
+	identity, err := scanUserIdentity(r.sql.QueryRowContext(ctx,
+		`SELECT `+userIdentityColumns+` FROM user_identities WHERE provider = $1 AND subject = $2`,
+		provider, subject))
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrUserIdentityNotFound, nil)
+	}
+	return identity, nil
+}
+
+func (r *userIdentityRepository) ListByUser(ctx context.Context, userID int64) ([]service.UserIdentity, error) {
+	rows, err := r.sql.QueryContext(ctx,
+		`SELECT `+userIdentityColumns+` FROM user_identities WHERE user_id = $1 ORDER BY id`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	identities := make([]service.UserIdentity, 0)
+	for rows.Next() {
+		identity, err := scanUserIdentity(rows)
+		if err != nil {
+			return nil, err
+		}
+		identities = append(identities, *identity)
+	}
+	return identities, rows.Err()
+}
+
+func (r *userIdentityRepository) Delete(ctx context.Context, userID, id int64) error {
+	res, err := r.sql.ExecContext(ctx, `DELETE FROM user_identities WHERE id = $1 AND user_id = $2`, id, userID)
+	if err != nil {
+		return err
+	}
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return service.ErrUserIdentityNotFound
+	}
+	return nil
+}
+
+func (r *userIdentityRepository) TouchLastLogin(ctx context.Context, id int64, at time.Time) error {
+	_, err := r.sql.ExecContext(ctx, `UPDATE user_identities SET last_login_at = $2 WHERE id = $1`, id, at)
+	return err
+}
+
+type userIdentityScanner interface {
+	Scan(dest ...any) error
+}
+
+func scanUserIdentity(row userIdentityScanner) (*service.UserIdentity, error) {
+	var (
+		identity    service.UserIdentity
+		lastLoginAt sql.NullTime
+	)
+	if err := row.Scan(
+		&identity.ID, &identity.UserID, &identity.Provider, &identity.Subject, &identity.Email,
+		&identity.CreatedAt, &lastLoginAt,
+	); err != nil {
+		return nil, err
+	}
+	if lastLoginAt.Valid {
+		t := lastLoginAt.Time
+		identity.LastLoginAt = &t
+	}
+	return &identity, nil
+}
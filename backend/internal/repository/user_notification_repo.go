@@ -0,0 +1,145 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+// userNotificationRepository 实现 service.UserNotificationRepository 接口。
+// 使用原生 SQL 操作 user_notification_settings / user_notification_states 表。
+type userNotificationRepository struct {
+	sql *sql.DB
+}
+
+// NewUserNotificationRepository 创建用户通知仓储实例。
+func NewUserNotificationRepository(sqlDB *sql.DB) service.UserNotificationRepository {
+	return &userNotificationRepository{sql: sqlDB}
+}
+
+const userNotificationSettingsColumns = `user_id, enabled, balance_threshold, api_key_quota_percent,
+	subscription_usage_percent, expiry_notice_days, unsubscribe_token, created_at, updated_at`
+
+func (r *userNotificationRepository) GetSettings(ctx context.Context, userID int64) (*service.UserNotificationSettings, error) {
+	settings, err := scanUserNotificationSettings(r.sql.QueryRowContext(ctx,
+		`SELECT `+userNotificationSettingsColumns+` FROM user_notification_settings WHERE user_id = $1`, userID))
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrUserNotificationSettingsNotFound, nil)
+	}
+	return settings, nil
+}
+
+func (r *userNotificationRepository) UpsertSettings(ctx context.Context, settings *service.UserNotificationSettings) error {
+	return r.sql.QueryRowContext(ctx, `
+		INSERT INTO user_notification_settings (user_id, enabled, balance_threshold, api_key_quota_percent,
+			subscription_usage_percent, expiry_notice_days, unsubscribe_token)
+		VALUES ($1, $2, $3, $4, $5, $6, $7)
+		ON CONFLICT (user_id) DO UPDATE SET
+			enabled = EXCLUDED.enabled,
+			balance_threshold = EXCLUDED.balance_threshold,
+			api_key_quota_percent = EXCLUDED.api_key_quota_percent,
+			subscription_usage_percent = EXCLUDED.subscription_usage_percent,
+			expiry_notice_days = EXCLUDED.expiry_notice_days,
+			updated_at = NOW()
+		RETURNING unsubscribe_token, created_at, updated_at
+	`, settings.UserID, settings.Enabled, settings.BalanceThreshold, settings.APIKeyQuotaPercent,
+		settings.SubscriptionUsagePercent, settings.ExpiryNoticeDays, settings.UnsubscribeToken,
+	).Scan(&settings.UnsubscribeToken, &settings.CreatedAt, &settings.UpdatedAt)
+}
+
+func (r *userNotificationRepository) ListEnabledSettings(ctx context.Context) ([]service.UserNotificationSettings, error) {
+	rows, err := r.sql.QueryContext(ctx,
+		`SELECT `+userNotificationSettingsColumns+` FROM user_notification_settings WHERE enabled ORDER BY user_id`)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	out := make([]service.UserNotificationSettings, 0)
+	for rows.Next() {
+		settings, err := scanUserNotificationSettings(rows)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, *settings)
+	}
+	return out, rows.Err()
+}
+
+func (r *userNotificationRepository) DisableByToken(ctx context.Context, token string) error {
+	res, err := r.sql.ExecContext(ctx, `
+		UPDATE user_notification_settings
+		SET enabled = FALSE, updated_at = NOW()
+		WHERE unsubscribe_token = $1
+	`, token)
+	if err != nil {
+		return err
+	}
+	n, err := res.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if n == 0 {
+		return service.ErrUserNotificationInvalidToken
+	}
+	return nil
+}
+
+func (r *userNotificationRepository) ListStateKeys(ctx context.Context, userID int64) ([]string, error) {
+	rows, err := r.sql.QueryContext(ctx, `SELECT notify_key FROM user_notification_states WHERE user_id = $1`, userID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *userNotificationRepository) AddStateKey(ctx context.Context, userID int64, key string) (bool, error) {
+	res, err := r.sql.ExecContext(ctx, `
+		INSERT INTO user_notification_states (user_id, notify_key)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id, notify_key) DO NOTHING
+	`, userID, key)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *userNotificationRepository) DeleteStateKeys(ctx context.Context, userID int64, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := r.sql.ExecContext(ctx,
+		`DELETE FROM user_notification_states WHERE user_id = $1 AND notify_key = ANY($2)`, userID, pq.Array(keys))
+	return err
+}
+
+func scanUserNotificationSettings(scanner interface{ Scan(...any) error }) (*service.UserNotificationSettings, error) {
+	var settings service.UserNotificationSettings
+	if err := scanner.Scan(
+		&settings.UserID,
+		&settings.Enabled,
+		&settings.BalanceThreshold,
+		&settings.APIKeyQuotaPercent,
+		&settings.SubscriptionUsagePercent,
+		&settings.ExpiryNoticeDays,
+		&settings.UnsubscribeToken,
+		&settings.CreatedAt,
+		&settings.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	return &settings, nil
+}
@@ -0,0 +1,382 @@
+package repository
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/lib/pq"
+)
+
+// userWebhookRepository 实现 service.UserWebhookRepository 接口。
+// 使用原生 SQL 操作 user_webhooks / user_webhook_deliveries / user_webhook_alert_states 表。
+type userWebhookRepository struct {
+	sql *sql.DB
+}
+
+// NewUserWebhookRepository 创建用户 Webhook 仓储实例。
+func NewUserWebhookRepository(sqlDB *sql.DB) service.UserWebhookRepository {
+	return &userWebhookRepository{sql: sqlDB}
+}
+
+const userWebhookColumns = `id, user_id, api_key_id, name, url, secret, events, enabled, balance_threshold,
+	usage_threshold_percent, expiry_notice_days, last_delivery_at, last_delivery_status, created_at, updated_at`
+
+const userWebhookDeliveryColumns = `id, webhook_id, user_id, event_id, event_type, payload, status, attempts, max_attempts,
+	next_attempt_at, response_status, last_error, duration_ms, delivered_at, created_at, updated_at`
+
+func (r *userWebhookRepository) Create(ctx context.Context, hook *service.UserWebhook) error {
+	return r.sql.QueryRowContext(ctx, `
+		INSERT INTO user_webhooks (user_id, api_key_id, name, url, secret, events, enabled, balance_threshold,
+			usage_threshold_percent, expiry_notice_days)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		RETURNING id, created_at, updated_at
+	`, hook.UserID, hook.APIKeyID, hook.Name, hook.URL, hook.Secret, pq.Array(hook.Events), hook.Enabled,
+		hook.BalanceThreshold, hook.UsagePercentThreshold, hook.ExpiryNoticeDays,
+	).Scan(&hook.ID, &hook.CreatedAt, &hook.UpdatedAt)
+}
+
+func (r *userWebhookRepository) GetByID(ctx context.Context, id int64) (*service.UserWebhook, error) {
+	hook, err := scanUserWebhook(r.sql.QueryRowContext(ctx,
+		`SELECT `+userWebhookColumns+` FROM user_webhooks WHERE id = $1`, id))
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrUserWebhookNotFound, nil)
+	}
+	return hook, nil
+}
+
+func (r *userWebhookRepository) Update(ctx context.Context, hook *service.UserWebhook) error {
+	err := r.sql.QueryRowContext(ctx, `
+		UPDATE user_webhooks
+		SET api_key_id = $2, name = $3, url = $4, secret = $5, events = $6, enabled = $7, balance_threshold = $8,
+			usage_threshold_percent = $9, expiry_notice_days = $10, updated_at = NOW()
+		WHERE id = $1
+		RETURNING updated_at
+	`, hook.ID, hook.APIKeyID, hook.Name, hook.URL, hook.Secret, pq.Array(hook.Events), hook.Enabled,
+		hook.BalanceThreshold, hook.UsagePercentThreshold, hook.ExpiryNoticeDays,
+	).Scan(&hook.UpdatedAt)
+	return translatePersistenceError(err, service.ErrUserWebhookNotFound, nil)
+}
+
+func (r *userWebhookRepository) Delete(ctx context.Context, id int64) error {
+	_, err := r.sql.ExecContext(ctx, `DELETE FROM user_webhooks WHERE id = $1`, id)
+	return err
+}
+
+func (r *userWebhookRepository) ListByUser(ctx context.Context, userID int64) ([]service.UserWebhook, error) {
+	return r.queryWebhooks(ctx,
+		`SELECT `+userWebhookColumns+` FROM user_webhooks WHERE user_id = $1 ORDER BY id ASC`, userID)
+}
+
+func (r *userWebhookRepository) CountByUser(ctx context.Context, userID int64) (int64, error) {
+	var count int64
+	if err := scanSingleRow(ctx, r.sql, `SELECT COUNT(*) FROM user_webhooks WHERE user_id = $1`, []any{userID}, &count); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+func (r *userWebhookRepository) ListSubscribed(ctx context.Context, userID int64, eventType string) ([]service.UserWebhook, error) {
+	return r.queryWebhooks(ctx, `
+		SELECT `+userWebhookColumns+`
+		FROM user_webhooks
+		WHERE user_id = $1 AND enabled AND $2 = ANY(events)
+		ORDER BY id ASC
+	`, userID, eventType)
+}
+
+func (r *userWebhookRepository) ListSubscribedToAny(ctx context.Context, eventTypes []string) ([]service.UserWebhook, error) {
+	return r.queryWebhooks(ctx, `
+		SELECT `+userWebhookColumns+`
+		FROM user_webhooks
+		WHERE enabled AND events && $1::text[]
+		ORDER BY user_id ASC, id ASC
+	`, pq.Array(eventTypes))
+}
+
+func (r *userWebhookRepository) CreateDeliveries(ctx context.Context, deliveries []*service.UserWebhookDelivery) error {
+	if len(deliveries) == 0 {
+		return nil
+	}
+	const cols = 8
+	placeholders := make([]string, 0, len(deliveries))
+	args := make([]any, 0, len(deliveries)*cols)
+	for i, d := range deliveries {
+		base := i * cols
+		placeholders = append(placeholders, fmt.Sprintf("($%d, $%d, $%d, $%d, $%d, $%d, $%d, $%d)",
+			base+1, base+2, base+3, base+4, base+5, base+6, base+7, base+8))
+		args = append(args, d.WebhookID, d.UserID, d.EventID, d.EventType, []byte(d.Payload), d.Attempts, d.MaxAttempts, d.NextAttemptAt)
+	}
+	rows, err := r.sql.QueryContext(ctx, `
+		INSERT INTO user_webhook_deliveries (webhook_id, user_id, event_id, event_type, payload, attempts, max_attempts, next_attempt_at)
+		VALUES `+strings.Join(placeholders, ", ")+`
+		RETURNING id, status, created_at, updated_at
+	`, args...)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = rows.Close() }()
+	// INSERT ... RETURNING 按 VALUES 顺序返回
+	for i := 0; rows.Next(); i++ {
+		if i >= len(deliveries) {
+			break
+		}
+		d := deliveries[i]
+		if err := rows.Scan(&d.ID, &d.Status, &d.CreatedAt, &d.UpdatedAt); err != nil {
+			return err
+		}
+	}
+	return rows.Err()
+}
+
+func (r *userWebhookRepository) ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]service.UserWebhookDelivery, error) {
+	if limit <= 0 {
+		limit = 50
+	}
+	return r.queryDeliveries(ctx, `
+		WITH due AS (
+			SELECT id
+			FROM user_webhook_deliveries
+			WHERE status = $1 AND next_attempt_at <= NOW()
+			ORDER BY next_attempt_at ASC, id ASC
+			LIMIT $2
+			FOR UPDATE SKIP LOCKED
+		)
+		UPDATE user_webhook_deliveries AS d
+		SET attempts = d.attempts + 1,
+			next_attempt_at = NOW() + ($3 * interval '1 second'),
+			updated_at = NOW()
+		FROM due
+		WHERE d.id = due.id
+		RETURNING `+prefixedUserWebhookDeliveryColumns("d"),
+		service.UserWebhookDeliveryPending, limit, int64(lease.Seconds()))
+}
+
+func (r *userWebhookRepository) SaveDeliveryResult(ctx context.Context, d *service.UserWebhookDelivery) error {
+	_, err := r.sql.ExecContext(ctx, `
+		WITH updated AS (
+			UPDATE user_webhook_deliveries
+			SET status = $2, next_attempt_at = $3, response_status = $4, last_error = $5,
+				duration_ms = $6, delivered_at = $7, updated_at = NOW()
+			WHERE id = $1
+			RETURNING webhook_id
+		)
+		UPDATE user_webhooks AS w
+		SET last_delivery_at = NOW(),
+			last_delivery_status = CASE WHEN $2 = $8 THEN $8 ELSE $9 END
+		FROM updated
+		WHERE w.id = updated.webhook_id
+	`, d.ID, d.Status, d.NextAttemptAt, d.ResponseStatus, d.LastError, d.DurationMs, d.DeliveredAt,
+		service.UserWebhookDeliverySucceeded, service.UserWebhookDeliveryFailed)
+	return err
+}
+
+func (r *userWebhookRepository) GetDelivery(ctx context.Context, id int64) (*service.UserWebhookDelivery, error) {
+	d, err := scanUserWebhookDelivery(r.sql.QueryRowContext(ctx,
+		`SELECT `+userWebhookDeliveryColumns+` FROM user_webhook_deliveries WHERE id = $1`, id))
+	if err != nil {
+		return nil, translatePersistenceError(err, service.ErrUserWebhookDeliveryNotFound, nil)
+	}
+	return d, nil
+}
+
+func (r *userWebhookRepository) ListDeliveries(ctx context.Context, webhookID int64, params pagination.PaginationParams) ([]service.UserWebhookDelivery, *pagination.PaginationResult, error) {
+	var total int64
+	if err := scanSingleRow(ctx, r.sql, `SELECT COUNT(*) FROM user_webhook_deliveries WHERE webhook_id = $1`, []any{webhookID}, &total); err != nil {
+		return nil, nil, err
+	}
+	if total == 0 {
+		return []service.UserWebhookDelivery{}, paginationResultFromTotal(0, params), nil
+	}
+	deliveries, err := r.queryDeliveries(ctx, `
+		SELECT `+userWebhookDeliveryColumns+`
+		FROM user_webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC, id DESC
+		LIMIT $2 OFFSET $3
+	`, webhookID, params.Limit(), params.Offset())
+	if err != nil {
+		return nil, nil, err
+	}
+	return deliveries, paginationResultFromTotal(total, params), nil
+}
+
+func (r *userWebhookRepository) RequeueDelivery(ctx context.Context, id int64, maxAttempts int) error {
+	res, err := r.sql.ExecContext(ctx, `
+		UPDATE user_webhook_deliveries
+		SET status = $2, attempts = 0, max_attempts = $3, next_attempt_at = NOW(), last_error = '', updated_at = NOW()
+		WHERE id = $1
+	`, id, service.UserWebhookDeliveryPending, maxAttempts)
+	if err != nil {
+		return err
+	}
+	if n, _ := res.RowsAffected(); n == 0 {
+		return service.ErrUserWebhookDeliveryNotFound
+	}
+	return nil
+}
+
+func (r *userWebhookRepository) DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error) {
+	res, err := r.sql.ExecContext(ctx,
+		`DELETE FROM user_webhook_deliveries WHERE created_at < $1 AND status <> $2`, before, service.UserWebhookDeliveryPending)
+	if err != nil {
+		return 0, err
+	}
+	return res.RowsAffected()
+}
+
+func (r *userWebhookRepository) ListAlertKeys(ctx context.Context, webhookID int64) ([]string, error) {
+	rows, err := r.sql.QueryContext(ctx, `SELECT alert_key FROM user_webhook_alert_states WHERE webhook_id = $1`, webhookID)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+	keys := make([]string, 0)
+	for rows.Next() {
+		var key string
+		if err := rows.Scan(&key); err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	return keys, rows.Err()
+}
+
+func (r *userWebhookRepository) AddAlertKey(ctx context.Context, webhookID int64, key string) (bool, error) {
+	res, err := r.sql.ExecContext(ctx, `
+		INSERT INTO user_webhook_alert_states (webhook_id, alert_key)
+		VALUES ($1, $2)
+		ON CONFLICT (webhook_id, alert_key) DO NOTHING
+	`, webhookID, key)
+	if err != nil {
+		return false, err
+	}
+	n, err := res.RowsAffected()
+	return n > 0, err
+}
+
+func (r *userWebhookRepository) DeleteAlertKeys(ctx context.Context, webhookID int64, keys []string) error {
+	if len(keys) == 0 {
+		return nil
+	}
+	_, err := r.sql.ExecContext(ctx,
+		`DELETE FROM user_webhook_alert_states WHERE webhook_id = $1 AND alert_key = ANY($2)`, webhookID, pq.Array(keys))
+	return err
+}
+
+func (r *userWebhookRepository) queryWebhooks(ctx context.Context, query string, args ...any) ([]service.UserWebhook, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	hooks := make([]service.UserWebhook, 0)
+	for rows.Next() {
+		hook, err := scanUserWebhook(rows)
+		if err != nil {
+			return nil, err
+		}
+		hooks = append(hooks, *hook)
+	}
+	return hooks, rows.Err()
+}
+
+func (r *userWebhookRepository) queryDeliveries(ctx context.Context, query string, args ...any) ([]service.UserWebhookDelivery, error) {
+	rows, err := r.sql.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = rows.Close() }()
+
+	deliveries := make([]service.UserWebhookDelivery, 0)
+	for rows.Next() {
+		d, err := scanUserWebhookDelivery(rows)
+		if err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, *d)
+	}
+	return deliveries, rows.Err()
+}
+
+func prefixedUserWebhookDeliveryColumns(alias string) string {
+	cols := strings.Split(userWebhookDeliveryColumns, ",")
+	for i, col := range cols {
+		cols[i] = alias + "." + strings.TrimSpace(col)
+	}
+	return strings.Join(cols, ", ")
+}
+
+func scanUserWebhook(scanner interface{ Scan(...any) error }) (*service.UserWebhook, error) {
+	var (
+		hook           service.UserWebhook
+		apiKeyID       sql.NullInt64
+		events         pq.StringArray
+		lastDeliveryAt sql.NullTime
+	)
+	if err := scanner.Scan(
+		&hook.ID,
+		&hook.UserID,
+		&apiKeyID,
+		&hook.Name,
+		&hook.URL,
+		&hook.Secret,
+		&events,
+		&hook.Enabled,
+		&hook.BalanceThreshold,
+		&hook.UsagePercentThreshold,
+		&hook.ExpiryNoticeDays,
+		&lastDeliveryAt,
+		&hook.LastDeliveryStatus,
+		&hook.CreatedAt,
+		&hook.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	if apiKeyID.Valid {
+		hook.APIKeyID = &apiKeyID.Int64
+	}
+	hook.Events = []string(events)
+	if lastDeliveryAt.Valid {
+		hook.LastDeliveryAt = &lastDeliveryAt.Time
+	}
+	return &hook, nil
+}
+
+func scanUserWebhookDelivery(scanner interface{ Scan(...any) error }) (*service.UserWebhookDelivery, error) {
+	var (
+		d           service.UserWebhookDelivery
+		payload     []byte
+		deliveredAt sql.NullTime
+	)
+	if err := scanner.Scan(
+		&d.ID,
+		&d.WebhookID,
+		&d.UserID,
+		&d.EventID,
+		&d.EventType,
+		&payload,
+		&d.Status,
+		&d.Attempts,
+		&d.MaxAttempts,
+		&d.NextAttemptAt,
+		&d.ResponseStatus,
+		&d.LastError,
+		&d.DurationMs,
+		&deliveredAt,
+		&d.CreatedAt,
+		&d.UpdatedAt,
+	); err != nil {
+		return nil, err
+	}
+	d.Payload = payload
+	if deliveredAt.Valid {
+		d.DeliveredAt = &deliveredAt.Time
+	}
+	return &d, nil
+}
@@ -3,6 +3,8 @@ package repository
 import (
 	"database/sql"
 	"errors"
+	"strings"
+	"time"
 
 	entsql "entgo.io/ent/dialect/sql"
 	"github.com/Wei-Shaw/sub2api/ent"
@@ -37,6 +39,16 @@ func ProvidePricingRemoteClient(cfg *config.Config) service.PricingRemoteClient
 	return NewPricingRemoteClient(cfg.Update.ProxyURL, cfg.Security.ProxyFallback.AllowDirectOnError)
 }
 
+// ProvideDigestSessionCache 创建摘要会话存储
+// gateway.digest_session.store=redis 时使用 Redis（多副本共享），否则使用进程内存储
+func ProvideDigestSessionCache(rdb *redis.Client, cfg *config.Config) service.DigestSessionCache {
+	ttl := time.Duration(cfg.Gateway.DigestSession.TTLSeconds) * time.Second
+	if strings.EqualFold(strings.TrimSpace(cfg.Gateway.DigestSession.Store), "redis") {
+		return NewDigestSessionCache(rdb, ttl)
+	}
+	return service.NewDigestSessionStoreWithTTL(ttl)
+}
+
 // ProvideSessionLimitCache 创建会话限制缓存
 // 用于 Anthropic OAuth/SetupToken 账号的并发会话数量控制
 func ProvideSessionLimitCache(rdb *redis.Client, cfg *config.Config) service.SessionLimitCache {
@@ -47,13 +59,35 @@ func ProvideSessionLimitCache(rdb *redis.Client, cfg *config.Config) service.Ses
 	return NewSessionLimitCache(rdb, defaultIdleTimeoutMinutes)
 }
 
+// ProvideHTTPUpstream 创建通用 HTTP 上游服务，并叠加代理池选择
+// 绑定代理池的账号由代理池决定出口代理，其余账号使用调用方传入的代理
+func ProvideHTTPUpstream(cfg *config.Config, proxyPoolService *service.ProxyPoolService) service.HTTPUpstream {
+	return service.NewProxyPoolUpstream(NewHTTPUpstream(cfg), proxyPoolService)
+}
+
+// ProvideCredentialCipher 账号仓储使用密钥环进行凭证加解密
+func ProvideCredentialCipher(keyRing service.CredentialKeyRing) service.CredentialCipher {
+	return keyRing
+}
+
 // ProviderSet is the Wire provider set for all repositories
 var ProviderSet = wire.NewSet(
 	NewUserRepository,
 	NewAPIKeyRepository,
+	NewOrganizationRepository,
+	NewAdminAPITokenRepository,
+	NewAdminAuditRepository,
+	NewUserIdentityRepository,
 	NewGroupRepository,
 	NewAccountRepository,
+	NewAccountCredentialRepository,   // 凭证重加密使用的原始凭证读写
 	NewSoraAccountRepository,         // Sora 账号扩展表仓储
+	NewSoraGenerationJobRepository,   // Sora 生成任务队列仓储
+	NewUserWebhookRepository,         // 用户出站 Webhook 与投递日志
+	NewUserNotificationRepository,    // 用户通知邮件设置与去重状态
+	NewMessageBatchRepository,        // Message Batches 批次与批内请求
+	NewOpenAIFileRepository,          // OpenAI Files 文件元数据
+	NewOpenAIBatchRepository,         // OpenAI Batch 批次与批内请求
 	NewScheduledTestPlanRepository,   // 定时测试计划仓储
 	NewScheduledTestResultRepository, // 定时测试结果仓储
 	NewProxyRepository,
@@ -64,6 +98,9 @@ var ProviderSet = wire.NewSet(
 	NewUsageLogRepository,
 	NewIdempotencyRepository,
 	NewUsageCleanupRepository,
+	NewUsageExportRepository,
+	NewStatementRepository,
+	NewProxyPoolRepository,
 	NewDashboardAggregationRepository,
 	NewSettingRepository,
 	NewOpsRepository,
@@ -83,8 +120,10 @@ var ProviderSet = wire.NewSet(
 	ProvideConcurrencyCache,
 	ProvideSessionLimitCache,
 	NewRPMCache,
+	ProvideDigestSessionCache,
 	NewUserMsgQueueCache,
 	NewDashboardCache,
+	NewResponseCache,
 	NewEmailCache,
 	NewIdentityCache,
 	NewRedeemCache,
@@ -99,6 +138,8 @@ var ProviderSet = wire.NewSet(
 
 	// Encryptors
 	NewAESEncryptor,
+	NewCredentialKeyRing,
+	ProvideCredentialCipher,
 
 	// HTTP service ports (DI Strategy A: return interface directly)
 	NewTurnstileVerifier,
@@ -107,7 +148,7 @@ var ProviderSet = wire.NewSet(
 	NewProxyExitInfoProber,
 	NewClaudeUsageFetcher,
 	NewClaudeOAuthClient,
-	NewHTTPUpstream,
+	ProvideHTTPUpstream,
 	NewOpenAIOAuthClient,
 	NewGeminiOAuthClient,
 	NewGeminiCliCodeAssistClient,
@@ -78,8 +78,10 @@ func TestAPIContracts(t *testing.T) {
 					"id": 100,
 					"user_id": 1,
 					"key": "sk_custom_1234567890",
+					"key_prefix": "sk_custom_",
 					"name": "Key One",
 					"group_id": null,
+					"organization_id": null,
 					"status": "active",
 					"ip_whitelist": null,
 					"ip_blacklist": null,
@@ -89,6 +91,12 @@ func TestAPIContracts(t *testing.T) {
 					"rate_limit_5h": 0,
 					"rate_limit_1d": 0,
 					"rate_limit_7d": 0,
+					"rpm_limit": 0,
+					"tpm_limit": 0,
+					"max_concurrency": 0,
+					"allowed_models": null,
+					"denied_models": null,
+					"model_aliases": null,
 					"usage_5h": 0,
 					"usage_1d": 0,
 					"usage_7d": 0,
@@ -108,7 +116,8 @@ func TestAPIContracts(t *testing.T) {
 				deps.apiKeyRepo.MustSeed(&service.APIKey{
 					ID:        100,
 					UserID:    1,
-					Key:       "sk_custom_1234567890",
+					KeyHash:   service.HashAPIKey("", "sk_custom_1234567890"),
+					KeyPrefix: "sk_custom_",
 					Name:      "Key One",
 					Status:    service.StatusActive,
 					CreatedAt: deps.now,
@@ -126,9 +135,10 @@ func TestAPIContracts(t *testing.T) {
 						{
 							"id": 100,
 							"user_id": 1,
-							"key": "sk_custom_1234567890",
+							"key_prefix": "sk_custom_",
 							"name": "Key One",
 							"group_id": null,
+							"organization_id": null,
 							"status": "active",
 							"ip_whitelist": null,
 							"ip_blacklist": null,
@@ -138,6 +148,12 @@ func TestAPIContracts(t *testing.T) {
 							"rate_limit_5h": 0,
 							"rate_limit_1d": 0,
 							"rate_limit_7d": 0,
+							"rpm_limit": 0,
+							"tpm_limit": 0,
+							"max_concurrency": 0,
+							"allowed_models": null,
+							"denied_models": null,
+							"model_aliases": null,
 							"usage_5h": 0,
 							"usage_1d": 0,
 							"usage_7d": 0,
@@ -216,6 +232,7 @@ func TestAPIContracts(t *testing.T) {
 							"claude_code_only": false,
 						"fallback_group_id": null,
 						"fallback_group_id_on_invalid_request": null,
+						"allow_messages_dispatch": false,
 						"created_at": "2025-01-02T03:04:05Z",
 						"updated_at": "2025-01-02T03:04:05Z"
 					}
@@ -547,7 +564,29 @@ func TestAPIContracts(t *testing.T) {
 					"xunhupay_gateway": "https://api.xunhupay.com/payment/do.html",
 					"xunhupay_notify_url": "",
 					"xunhupay_plugins": "",
-					"xunhupay_return_url": ""
+					"xunhupay_return_url": "",
+					"stripe_secret_key_configured": false,
+					"stripe_webhook_secret_configured": false,
+					"stripe_currency": "usd",
+					"stripe_success_url": "",
+					"stripe_cancel_url": "",
+					"alipay_app_id": "",
+					"alipay_private_key_configured": false,
+					"alipay_public_key": "",
+					"alipay_gateway": "https://openapi.alipay.com/gateway.do",
+					"alipay_notify_url": "",
+					"alipay_return_url": "",
+					"wechatpay_app_id": "",
+					"wechatpay_mch_id": "",
+					"wechatpay_serial_no": "",
+					"wechatpay_private_key_configured": false,
+					"wechatpay_api_v3_key_configured": false,
+					"wechatpay_platform_public_key": "",
+					"wechatpay_notify_url": "",
+					"balance_topup_enabled": false,
+					"balance_topup_min_amount": 1,
+					"balance_topup_max_amount": 0,
+					"balance_topup_rate": 1
 				}
 			}`,
 		},
@@ -658,7 +697,7 @@ func newContractDeps(t *testing.T) *contractDeps {
 	adminService := service.NewAdminService(userRepo, groupRepo, &accountRepo, nil, proxyRepo, apiKeyRepo, redeemRepo, nil, nil, nil, nil, nil, nil, nil, nil)
 	authHandler := handler.NewAuthHandler(cfg, nil, userService, settingService, nil, redeemService, nil)
 	apiKeyHandler := handler.NewAPIKeyHandler(apiKeyService)
-	usageHandler := handler.NewUsageHandler(usageService, apiKeyService)
+	usageHandler := handler.NewUsageHandler(usageService, apiKeyService, nil)
 	adminSettingHandler := adminhandler.NewSettingHandler(settingService, nil, nil, nil, nil)
 	adminAccountHandler := adminhandler.NewAccountHandler(adminService, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil, nil)
 
@@ -1340,7 +1379,7 @@ type stubApiKeyRepo struct {
 
 	nextID int64
 	byID   map[int64]*service.APIKey
-	byKey  map[string]*service.APIKey
+	byHash map[string]*service.APIKey
 }
 
 func newStubApiKeyRepo(now time.Time) *stubApiKeyRepo {
@@ -1348,7 +1387,7 @@ func newStubApiKeyRepo(now time.Time) *stubApiKeyRepo {
 		now:    now,
 		nextID: 100,
 		byID:   make(map[int64]*service.APIKey),
-		byKey:  make(map[string]*service.APIKey),
+		byHash: make(map[string]*service.APIKey),
 	}
 }
 
@@ -1358,7 +1397,7 @@ func (r *stubApiKeyRepo) MustSeed(key *service.APIKey) {
 	}
 	clone := *key
 	r.byID[clone.ID] = &clone
-	r.byKey[clone.Key] = &clone
+	r.byHash[clone.KeyHash] = &clone
 }
 
 func (r *stubApiKeyRepo) Create(ctx context.Context, key *service.APIKey) error {
@@ -1377,7 +1416,7 @@ func (r *stubApiKeyRepo) Create(ctx context.Context, key *service.APIKey) error
 	}
 	clone := *key
 	r.byID[clone.ID] = &clone
-	r.byKey[clone.Key] = &clone
+	r.byHash[clone.KeyHash] = &clone
 	return nil
 }
 
@@ -1390,16 +1429,16 @@ func (r *stubApiKeyRepo) GetByID(ctx context.Context, id int64) (*service.APIKey
 	return &clone, nil
 }
 
-func (r *stubApiKeyRepo) GetKeyAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
+func (r *stubApiKeyRepo) GetKeyHashAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
 	key, ok := r.byID[id]
 	if !ok {
 		return "", 0, service.ErrAPIKeyNotFound
 	}
-	return key.Key, key.UserID, nil
+	return key.KeyHash, key.UserID, nil
 }
 
-func (r *stubApiKeyRepo) GetByKey(ctx context.Context, key string) (*service.APIKey, error) {
-	found, ok := r.byKey[key]
+func (r *stubApiKeyRepo) GetByKeyHash(ctx context.Context, keyHash string) (*service.APIKey, error) {
+	found, ok := r.byHash[keyHash]
 	if !ok {
 		return nil, service.ErrAPIKeyNotFound
 	}
@@ -1407,8 +1446,8 @@ func (r *stubApiKeyRepo) GetByKey(ctx context.Context, key string) (*service.API
 	return &clone, nil
 }
 
-func (r *stubApiKeyRepo) GetByKeyForAuth(ctx context.Context, key string) (*service.APIKey, error) {
-	return r.GetByKey(ctx, key)
+func (r *stubApiKeyRepo) GetByKeyHashForAuth(ctx context.Context, keyHash string) (*service.APIKey, error) {
+	return r.GetByKeyHash(ctx, keyHash)
 }
 
 func (r *stubApiKeyRepo) Update(ctx context.Context, key *service.APIKey) error {
@@ -1423,7 +1462,7 @@ func (r *stubApiKeyRepo) Update(ctx context.Context, key *service.APIKey) error
 	}
 	clone := *key
 	r.byID[clone.ID] = &clone
-	r.byKey[clone.Key] = &clone
+	r.byHash[clone.KeyHash] = &clone
 	return nil
 }
 
@@ -1433,7 +1472,7 @@ func (r *stubApiKeyRepo) Delete(ctx context.Context, id int64) error {
 		return service.ErrAPIKeyNotFound
 	}
 	delete(r.byID, id)
-	delete(r.byKey, key.Key)
+	delete(r.byHash, key.KeyHash)
 	return nil
 }
 
@@ -1504,8 +1543,8 @@ func (r *stubApiKeyRepo) CountByUserID(ctx context.Context, userID int64) (int64
 	return count, nil
 }
 
-func (r *stubApiKeyRepo) ExistsByKey(ctx context.Context, key string) (bool, error) {
-	_, ok := r.byKey[key]
+func (r *stubApiKeyRepo) ExistsByKeyHash(ctx context.Context, keyHash string) (bool, error) {
+	_, ok := r.byHash[keyHash]
 	return ok, nil
 }
 
@@ -1525,11 +1564,11 @@ func (r *stubApiKeyRepo) CountByGroupID(ctx context.Context, groupID int64) (int
 	return 0, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) ListKeysByUserID(ctx context.Context, userID int64) ([]string, error) {
+func (r *stubApiKeyRepo) ListKeyHashesByUserID(ctx context.Context, userID int64) ([]string, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) ListKeysByGroupID(ctx context.Context, groupID int64) ([]string, error) {
+func (r *stubApiKeyRepo) ListKeyHashesByGroupID(ctx context.Context, groupID int64) ([]string, error) {
 	return nil, errors.New("not implemented")
 }
 
@@ -1547,7 +1586,7 @@ func (r *stubApiKeyRepo) UpdateLastUsed(ctx context.Context, id int64, usedAt ti
 	key.UpdatedAt = usedAt
 	clone := *key
 	r.byID[id] = &clone
-	r.byKey[clone.Key] = &clone
+	r.byHash[clone.KeyHash] = &clone
 	return nil
 }
 
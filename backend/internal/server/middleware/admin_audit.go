@@ -0,0 +1,108 @@
+package middleware
+
+import (
+	"bytes"
+	"context"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"go.uber.org/zap"
+)
+
+const (
+	// adminAuditMaxBodyBytes 超过该大小的请求体不记录（批量导入等）
+	adminAuditMaxBodyBytes = 64 << 10
+	adminAuditWriteTimeout = 5 * time.Second
+)
+
+// NewAdminAuditMiddleware 记录所有管理员写操作（POST/PUT/PATCH/DELETE）的审计日志。
+// 必须在 AdminAuth 中间件之后使用；审计写入失败只记录日志，不影响请求结果。
+func NewAdminAuditMiddleware(auditService *service.AdminAuditService) AdminAuditMiddleware {
+	return func(c *gin.Context) {
+		if !auditService.Enabled() || isReadOnlyMethod(c.Request.Method) {
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		targetType, targetID := resolveAdminAuditTarget(route, c.Param)
+		body := captureAdminAuditBody(c)
+		before := auditService.Snapshot(c.Request.Context(), route, targetType, targetID)
+
+		c.Next()
+
+		status := c.Writer.Status()
+		var after any
+		if status < http.StatusBadRequest && c.Request.Method != http.MethodDelete {
+			after = auditService.Snapshot(c.Request.Context(), route, targetType, targetID)
+		}
+
+		input := &service.AdminAuditRecordInput{
+			ActorType:   c.GetString("auth_method"),
+			Method:      c.Request.Method,
+			Route:       route,
+			Path:        c.Request.URL.Path,
+			TargetType:  targetType,
+			TargetID:    targetID,
+			StatusCode:  status,
+			ClientIP:    ip.GetTrustedClientIP(c),
+			RequestBody: body,
+			Before:      before,
+			After:       after,
+		}
+		if subject, ok := GetAuthSubjectFromContext(c); ok {
+			userID := subject.UserID
+			input.ActorUserID = &userID
+		}
+		if token, ok := GetAdminAPITokenFromContext(c); ok {
+			tokenID := token.ID
+			input.ActorTokenID = &tokenID
+			input.ActorTokenName = token.Name
+		}
+
+		// 请求上下文可能已被客户端取消，审计写入使用独立的超时上下文
+		ctx, cancel := context.WithTimeout(context.Background(), adminAuditWriteTimeout)
+		defer cancel()
+		if err := auditService.Record(ctx, input); err != nil {
+			logger.FromContext(c.Request.Context()).Warn("admin audit record failed",
+				zap.String("method", input.Method),
+				zap.String("path", input.Path),
+				zap.Error(err),
+			)
+		}
+	}
+}
+
+// resolveAdminAuditTarget 从路由模板解析目标对象：
+// 带参数的路由取第一个参数之前的路径段作为类型、参数值作为 ID（如 /ops/alert-rules/:id → ops/alert-rules）；
+// 不带参数的路由取第一个路径段（如 /accounts/batch → accounts）。
+func resolveAdminAuditTarget(route string, param func(string) string) (targetType, targetID string) {
+	rest := strings.Trim(strings.TrimPrefix(route, "/api/v1/admin"), "/")
+	segments := strings.Split(rest, "/")
+	for i, segment := range segments {
+		if strings.HasPrefix(segment, ":") || strings.HasPrefix(segment, "*") {
+			return strings.Join(segments[:i], "/"), param(segment[1:])
+		}
+	}
+	return segments[0], ""
+}
+
+// captureAdminAuditBody 读取 JSON 请求体用于审计，并把原始内容放回请求供后续 handler 使用。
+func captureAdminAuditBody(c *gin.Context) []byte {
+	if c.Request.Body == nil || !strings.Contains(c.ContentType(), "json") {
+		return nil
+	}
+	buf, err := io.ReadAll(io.LimitReader(c.Request.Body, adminAuditMaxBodyBytes+1))
+	c.Request.Body = io.NopCloser(io.MultiReader(bytes.NewReader(buf), c.Request.Body))
+	if err != nil || len(buf) > adminAuditMaxBodyBytes {
+		return nil
+	}
+	return buf
+}
@@ -0,0 +1,111 @@
+//go:build unit
+
+package middleware
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+type adminAuditRepoStub struct {
+	logs []service.AdminAuditLog
+}
+
+func (r *adminAuditRepoStub) Create(_ context.Context, log *service.AdminAuditLog) error {
+	r.logs = append(r.logs, *log)
+	return nil
+}
+
+func (r *adminAuditRepoStub) List(context.Context, pagination.PaginationParams, service.AdminAuditLogFilter) ([]service.AdminAuditLog, *pagination.PaginationResult, error) {
+	return nil, nil, nil
+}
+
+func (r *adminAuditRepoStub) ListAfterID(context.Context, service.AdminAuditLogFilter, int64, int) ([]service.AdminAuditLog, error) {
+	return nil, nil
+}
+
+func TestResolveAdminAuditTarget(t *testing.T) {
+	params := map[string]string{"id": "42", "idx": "3"}
+	param := func(key string) string { return params[key] }
+
+	cases := []struct {
+		route, wantType, wantID string
+	}{
+		{"/api/v1/admin/accounts/:id", "accounts", "42"},
+		{"/api/v1/admin/users/:id/balance", "users", "42"},
+		{"/api/v1/admin/ops/alert-rules/:id", "ops/alert-rules", "42"},
+		{"/api/v1/admin/accounts/batch", "accounts", ""},
+		{"/api/v1/admin/settings", "settings", ""},
+	}
+	for _, tc := range cases {
+		gotType, gotID := resolveAdminAuditTarget(tc.route, param)
+		require.Equal(t, tc.wantType, gotType, tc.route)
+		require.Equal(t, tc.wantID, gotID, tc.route)
+	}
+}
+
+func TestAdminAuditMiddleware(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	repo := &adminAuditRepoStub{}
+	auditService := service.NewAdminAuditService(repo, nil)
+
+	state := map[string]any{"name": "before", "password": "old"}
+	auditService.RegisterSnapshotLoader("proxies", func(context.Context, int64) (any, error) {
+		return state, nil
+	})
+
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(string(ContextKeyUser), AuthSubject{UserID: 1})
+		c.Set("auth_method", service.AdminAuditActorAdminToken)
+		c.Set(string(ContextKeyAdminAPIToken), &service.AdminAPIToken{ID: 9, Name: "ci"})
+		c.Next()
+	})
+	router.Use(gin.HandlerFunc(NewAdminAuditMiddleware(auditService)))
+	var handlerBody string
+	router.PUT("/api/v1/admin/proxies/:id", func(c *gin.Context) {
+		raw, _ := io.ReadAll(c.Request.Body)
+		handlerBody = string(raw)
+		state = map[string]any{"name": "after", "password": "new"}
+		c.Status(http.StatusOK)
+	})
+	router.GET("/api/v1/admin/proxies/:id", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, httptest.NewRequest(http.MethodGet, "/api/v1/admin/proxies/5", nil))
+	require.Empty(t, repo.logs, "read-only requests are not audited")
+
+	payload := `{"name":"after","password":"new"}`
+	req := httptest.NewRequest(http.MethodPut, "/api/v1/admin/proxies/5", strings.NewReader(payload))
+	req.Header.Set("Content-Type", "application/json")
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, payload, handlerBody, "handler must still see the full body")
+	require.Len(t, repo.logs, 1)
+	log := repo.logs[0]
+	require.Equal(t, service.AdminAuditActorAdminToken, log.ActorType)
+	require.Equal(t, int64(1), *log.ActorUserID)
+	require.Equal(t, int64(9), *log.ActorTokenID)
+	require.Equal(t, "ci", log.ActorTokenName)
+	require.Equal(t, "/api/v1/admin/proxies/:id", log.Route)
+	require.Equal(t, "proxies", log.TargetType)
+	require.Equal(t, "5", log.TargetID)
+	require.Equal(t, http.StatusOK, log.StatusCode)
+	require.Equal(t, service.AdminAuditChange{Before: "before", After: "after"}, log.Diff["name"])
+	require.Equal(t, service.AdminAuditChange{Before: "[REDACTED]", After: "[REDACTED]"}, log.Diff["password"])
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(log.RequestBody, &body))
+	require.Equal(t, "[REDACTED]", body["password"])
+}
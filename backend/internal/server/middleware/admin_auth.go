@@ -6,6 +6,7 @@ import (
 	"errors"
 	"strings"
 
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
 	"github.com/Wei-Shaw/sub2api/internal/service"
 
 	"github.com/gin-gonic/gin"
@@ -16,18 +17,20 @@ func NewAdminAuthMiddleware(
 	authService *service.AuthService,
 	userService *service.UserService,
 	settingService *service.SettingService,
+	adminTokenService *service.AdminAPITokenService,
 ) AdminAuthMiddleware {
-	return AdminAuthMiddleware(adminAuth(authService, userService, settingService))
+	return AdminAuthMiddleware(adminAuth(authService, userService, settingService, adminTokenService))
 }
 
 // adminAuth 管理员认证中间件实现
 // 支持两种认证方式（通过不同的 header 区分）：
-// 1. Admin API Key: x-api-key: <admin-api-key>
+// 1. Admin API Key: x-api-key: <admin-api-key>（全局 Key 拥有全部权限，具名 Token 按 scope 授权）
 // 2. JWT Token: Authorization: Bearer <jwt-token> (需要管理员角色)
 func adminAuth(
 	authService *service.AuthService,
 	userService *service.UserService,
 	settingService *service.SettingService,
+	adminTokenService *service.AdminAPITokenService,
 ) gin.HandlerFunc {
 	return func(c *gin.Context) {
 		// WebSocket upgrade requests cannot set Authorization headers in browsers.
@@ -47,7 +50,7 @@ func adminAuth(
 		// 检查 x-api-key header（Admin API Key 认证）
 		apiKey := c.GetHeader("x-api-key")
 		if apiKey != "" {
-			if !validateAdminAPIKey(c, apiKey, settingService, userService) {
+			if !validateAdminAPIKey(c, apiKey, settingService, adminTokenService, userService) {
 				return
 			}
 			c.Next()
@@ -116,10 +119,12 @@ func extractJWTFromWebSocketSubprotocol(c *gin.Context) string {
 }
 
 // validateAdminAPIKey 验证管理员 API Key
+// 先匹配全局 Admin API Key（全部权限），未命中再查找具名 Admin API Token（按 scope 授权）
 func validateAdminAPIKey(
 	c *gin.Context,
 	key string,
 	settingService *service.SettingService,
+	adminTokenService *service.AdminAPITokenService,
 	userService *service.UserService,
 ) bool {
 	storedKey, err := settingService.GetAdminAPIKey(c.Request.Context())
@@ -128,10 +133,29 @@ func validateAdminAPIKey(
 		return false
 	}
 
-	// 未配置或不匹配，统一返回相同错误（避免信息泄露）
+	authMethod := "admin_api_key"
+	var token *service.AdminAPIToken
 	if storedKey == "" || subtle.ConstantTimeCompare([]byte(key), []byte(storedKey)) != 1 {
-		AbortWithError(c, 401, "INVALID_ADMIN_KEY", "Invalid admin API key")
-		return false
+		// 未配置或不匹配，统一返回相同错误（避免信息泄露）
+		if adminTokenService == nil {
+			AbortWithError(c, 401, "INVALID_ADMIN_KEY", "Invalid admin API key")
+			return false
+		}
+		token, err = adminTokenService.Authenticate(c.Request.Context(), key, ip.GetTrustedClientIP(c))
+		if err != nil {
+			switch {
+			case errors.Is(err, service.ErrAdminAPITokenInvalid):
+				AbortWithError(c, 401, "INVALID_ADMIN_KEY", "Invalid admin API key")
+			case errors.Is(err, service.ErrAdminAPITokenExpired):
+				AbortWithError(c, 401, "ADMIN_TOKEN_EXPIRED", "Admin API token has expired")
+			case errors.Is(err, service.ErrAdminAPITokenIPDenied):
+				AbortWithError(c, 403, "ACCESS_DENIED", "Access denied")
+			default:
+				AbortWithError(c, 500, "INTERNAL_ERROR", "Internal server error")
+			}
+			return false
+		}
+		authMethod = "admin_api_token"
 	}
 
 	// 获取真实的管理员用户
@@ -146,7 +170,10 @@ func validateAdminAPIKey(
 		Concurrency: admin.Concurrency,
 	})
 	c.Set(string(ContextKeyUserRole), admin.Role)
-	c.Set("auth_method", "admin_api_key")
+	c.Set("auth_method", authMethod)
+	if token != nil {
+		c.Set(string(ContextKeyAdminAPIToken), token)
+	}
 	return true
 }
 
@@ -0,0 +1,90 @@
+package middleware
+
+import (
+	"net/http"
+
+	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+)
+
+// GetAdminAPITokenFromContext 返回当前请求使用的具名管理员 API Token（JWT/全局 Key 认证时不存在）
+func GetAdminAPITokenFromContext(c *gin.Context) (*service.AdminAPIToken, bool) {
+	value, exists := c.Get(string(ContextKeyAdminAPIToken))
+	if !exists {
+		return nil, false
+	}
+	token, ok := value.(*service.AdminAPIToken)
+	return token, ok && token != nil
+}
+
+// RequireAdminScope 按资源校验管理员 API Token 的 scope
+// 必须在 AdminAuth 中间件之后使用；GET/HEAD/OPTIONS 需要 <resource>:read，其余方法需要 <resource>:write。
+// JWT 登录与全局 Admin API Key 拥有全部权限，不受影响。
+func RequireAdminScope(resource string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token, ok := GetAdminAPITokenFromContext(c)
+		if !ok {
+			c.Next()
+			return
+		}
+
+		write := !isReadOnlyMethod(c.Request.Method)
+		if !token.HasScope(resource, write) {
+			access := "read"
+			if write {
+				access = "write"
+			}
+			AbortWithError(c, http.StatusForbidden, "INSUFFICIENT_SCOPE", "Admin API token lacks scope "+resource+":"+access)
+			return
+		}
+		c.Next()
+	}
+}
+
+// DenyAdminAPIToken 禁止具名管理员 API Token 访问（用于凭据管理、上游凭证导出等敏感接口，防止 Token 自我提权）
+func DenyAdminAPIToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetAdminAPITokenFromContext(c); ok {
+			AbortWithError(c, http.StatusForbidden, "INSUFFICIENT_SCOPE", "Admin API tokens cannot access credential endpoints")
+			return
+		}
+		c.Next()
+	}
+}
+
+// DenyAdminAPITokenFields 禁止具名管理员 API Token 在 JSON 请求体中设置指定字段
+// （如修改用户密码、邮箱、角色可接管管理员账号），其余字段仍按 scope 放行。
+// 请求体读取失败时直接拒绝，避免绕过检查。
+func DenyAdminAPITokenFields(fields ...string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if _, ok := GetAdminAPITokenFromContext(c); !ok || c.Request.Body == nil || c.Request.Body == http.NoBody {
+			c.Next()
+			return
+		}
+		body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
+		if err != nil {
+			AbortWithError(c, http.StatusBadRequest, "INVALID_REQUEST", "Failed to read request body")
+			return
+		}
+		restoreRequestBody(c.Request, body)
+		for _, field := range fields {
+			value := gjson.GetBytes(body, field)
+			if value.Exists() && value.Type != gjson.Null && value.String() != "" {
+				AbortWithError(c, http.StatusForbidden, "INSUFFICIENT_SCOPE", "Admin API tokens cannot change "+field)
+				return
+			}
+		}
+		c.Next()
+	}
+}
+
+func isReadOnlyMethod(method string) bool {
+	switch method {
+	case http.MethodGet, http.MethodHead, http.MethodOptions:
+		return true
+	}
+	return false
+}
@@ -0,0 +1,96 @@
+//go:build unit
+
+package middleware
+
+import (
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+)
+
+func newAdminScopeTestRouter(token *service.AdminAPIToken) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		if token != nil {
+			c.Set(string(ContextKeyAdminAPIToken), token)
+		}
+		c.Next()
+	})
+
+	accounts := router.Group("/accounts", RequireAdminScope(service.AdminScopeResourceAccounts))
+	accounts.GET("", func(c *gin.Context) { c.Status(http.StatusOK) })
+	accounts.POST("", func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	router.GET("/credentials", DenyAdminAPIToken(), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	users := router.Group("/users", RequireAdminScope(service.AdminScopeResourceUsers))
+	users.PUT("/:id", DenyAdminAPITokenFields("password", "email", "role"), func(c *gin.Context) {
+		// handler 仍能读取完整请求体
+		body, _ := io.ReadAll(c.Request.Body)
+		if len(body) == 0 {
+			c.Status(http.StatusBadRequest)
+			return
+		}
+		c.Status(http.StatusOK)
+	})
+	return router
+}
+
+func serveAdminScope(router *gin.Engine, method, path string) int {
+	return serveAdminScopeBody(router, method, path, "")
+}
+
+func serveAdminScopeBody(router *gin.Engine, method, path, body string) int {
+	w := httptest.NewRecorder()
+	var reader io.Reader
+	if body != "" {
+		reader = strings.NewReader(body)
+	}
+	router.ServeHTTP(w, httptest.NewRequest(method, path, reader))
+	return w.Code
+}
+
+func TestRequireAdminScope(t *testing.T) {
+	t.Run("jwt_or_global_key_unrestricted", func(t *testing.T) {
+		router := newAdminScopeTestRouter(nil)
+		require.Equal(t, http.StatusOK, serveAdminScope(router, http.MethodGet, "/accounts"))
+		require.Equal(t, http.StatusOK, serveAdminScope(router, http.MethodPost, "/accounts"))
+		require.Equal(t, http.StatusOK, serveAdminScope(router, http.MethodGet, "/credentials"))
+	})
+
+	t.Run("read_scope_blocks_write", func(t *testing.T) {
+		router := newAdminScopeTestRouter(&service.AdminAPIToken{Scopes: []string{"accounts:read"}})
+		require.Equal(t, http.StatusOK, serveAdminScope(router, http.MethodGet, "/accounts"))
+		require.Equal(t, http.StatusForbidden, serveAdminScope(router, http.MethodPost, "/accounts"))
+	})
+
+	t.Run("other_resource_denied", func(t *testing.T) {
+		router := newAdminScopeTestRouter(&service.AdminAPIToken{Scopes: []string{"ops:write"}})
+		require.Equal(t, http.StatusForbidden, serveAdminScope(router, http.MethodGet, "/accounts"))
+	})
+
+	t.Run("tokens_cannot_manage_credentials", func(t *testing.T) {
+		router := newAdminScopeTestRouter(&service.AdminAPIToken{Scopes: []string{service.AdminScopeAll}})
+		require.Equal(t, http.StatusOK, serveAdminScope(router, http.MethodPost, "/accounts"))
+		require.Equal(t, http.StatusForbidden, serveAdminScope(router, http.MethodGet, "/credentials"))
+	})
+
+	t.Run("users_write_cannot_change_password_or_role", func(t *testing.T) {
+		router := newAdminScopeTestRouter(&service.AdminAPIToken{Scopes: []string{"users:write"}})
+		require.Equal(t, http.StatusOK, serveAdminScopeBody(router, http.MethodPut, "/users/1", `{"status":"disabled","password":""}`))
+		require.Equal(t, http.StatusForbidden, serveAdminScopeBody(router, http.MethodPut, "/users/1", `{"password":"new-password"}`))
+		require.Equal(t, http.StatusForbidden, serveAdminScopeBody(router, http.MethodPut, "/users/1", `{"role":"admin"}`))
+		require.Equal(t, http.StatusForbidden, serveAdminScopeBody(router, http.MethodPut, "/users/1", `{"email":"attacker@example.com"}`))
+
+		// JWT 登录不受限制
+		unrestricted := newAdminScopeTestRouter(nil)
+		require.Equal(t, http.StatusOK, serveAdminScopeBody(unrestricted, http.MethodPut, "/users/1", `{"password":"new-password","role":"admin"}`))
+	})
+}
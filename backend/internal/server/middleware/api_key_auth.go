@@ -27,6 +27,9 @@ func NewAPIKeyAuthMiddleware(apiKeyService *service.APIKeyService, subscriptionS
 // /v1/usage 端点只需鉴权，不需要计费执行（允许过期/配额耗尽的 Key 查询自身用量）。
 func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscriptionService *service.SubscriptionService, cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		authSpan := startAuthSpan(c, "auth.api_key")
+		defer endAuthSpan(c, authSpan)
+
 		// ── 1. 提取 API Key ──────────────────────────────────────────
 
 		queryKey := strings.TrimSpace(c.Query("key"))
@@ -109,6 +112,17 @@ func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscripti
 			return
 		}
 
+		// 组织 Key：组织已删除（未加载到组织）或被禁用时拦截
+		if apiKey.OrganizationID != nil && (apiKey.Organization == nil || !apiKey.Organization.IsActive()) {
+			AbortWithError(c, 403, "ORGANIZATION_DISABLED", "Organization is disabled")
+			return
+		}
+
+		// 检查模型策略（别名改写 + 白/黑名单），必须在账号调度之前执行
+		if !applyAPIKeyModelPolicy(c, apiKey, denyModelDefault) {
+			return
+		}
+
 		// ── 4. SimpleMode → early return ─────────────────────────────
 
 		if cfg.RunMode == config.RunModeSimple {
@@ -120,6 +134,7 @@ func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscripti
 			c.Set(string(ContextKeyUserRole), apiKey.User.Role)
 			setGroupContext(c, apiKey.Group)
 			_ = apiKeyService.TouchLastUsed(c.Request.Context(), apiKey.ID)
+			endAuthSpan(c, authSpan)
 			c.Next()
 			return
 		}
@@ -133,9 +148,14 @@ func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscripti
 		isSubscriptionType := apiKey.Group != nil && apiKey.Group.IsSubscriptionType()
 
 		if isSubscriptionType && subscriptionService != nil {
+			// 组织 Key 使用组织 owner 名下的订阅
+			subscriptionUserID := apiKey.User.ID
+			if apiKey.IsOrganizationKey() {
+				subscriptionUserID = apiKey.SubscriptionUserID()
+			}
 			sub, subErr := subscriptionService.GetActiveSubscription(
 				c.Request.Context(),
-				apiKey.User.ID,
+				subscriptionUserID,
 				apiKey.Group.ID,
 			)
 			if subErr != nil {
@@ -195,7 +215,12 @@ func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscripti
 				}
 			} else {
 				// 非订阅模式 或 订阅模式但 subscriptionService 未注入：回退到余额检查
-				if apiKey.User.Balance <= 0 {
+				// 组织 Key 使用组织共享余额
+				balance := apiKey.User.Balance
+				if apiKey.IsOrganizationKey() && apiKey.Organization != nil {
+					balance = apiKey.Organization.Balance
+				}
+				if balance <= 0 {
 					AbortWithError(c, 403, "INSUFFICIENT_BALANCE", "Insufficient account balance")
 					return
 				}
@@ -216,6 +241,7 @@ func apiKeyAuthWithSubscription(apiKeyService *service.APIKeyService, subscripti
 		setGroupContext(c, apiKey.Group)
 		_ = apiKeyService.TouchLastUsed(c.Request.Context(), apiKey.ID)
 
+		endAuthSpan(c, authSpan)
 		c.Next()
 	}
 }
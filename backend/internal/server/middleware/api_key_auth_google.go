@@ -22,6 +22,9 @@ func APIKeyAuthGoogle(apiKeyService *service.APIKeyService, cfg *config.Config)
 // It is intended for Gemini native endpoints (/v1beta) to match Gemini SDK expectations.
 func APIKeyAuthWithSubscriptionGoogle(apiKeyService *service.APIKeyService, subscriptionService *service.SubscriptionService, cfg *config.Config) gin.HandlerFunc {
 	return func(c *gin.Context) {
+		authSpan := startAuthSpan(c, "auth.api_key_google")
+		defer endAuthSpan(c, authSpan)
+
 		if v := strings.TrimSpace(c.Query("api_key")); v != "" {
 			abortWithGoogleError(c, 400, "Query parameter api_key is deprecated. Use Authorization header or key instead.")
 			return
@@ -54,6 +57,16 @@ func APIKeyAuthWithSubscriptionGoogle(apiKeyService *service.APIKeyService, subs
 			abortWithGoogleError(c, 401, "User account is not active")
 			return
 		}
+		// 组织 Key：组织已删除（未加载到组织）或被禁用时拦截
+		if apiKey.OrganizationID != nil && (apiKey.Organization == nil || !apiKey.Organization.IsActive()) {
+			abortWithGoogleError(c, 403, "Organization is disabled")
+			return
+		}
+
+		// 检查模型策略（别名改写 + 白/黑名单），必须在账号调度之前执行
+		if !applyAPIKeyModelPolicy(c, apiKey, denyModelGoogle) {
+			return
+		}
 
 		// 简易模式：跳过余额和订阅检查
 		if cfg.RunMode == config.RunModeSimple {
@@ -65,15 +78,21 @@ func APIKeyAuthWithSubscriptionGoogle(apiKeyService *service.APIKeyService, subs
 			c.Set(string(ContextKeyUserRole), apiKey.User.Role)
 			setGroupContext(c, apiKey.Group)
 			_ = apiKeyService.TouchLastUsed(c.Request.Context(), apiKey.ID)
+			endAuthSpan(c, authSpan)
 			c.Next()
 			return
 		}
 
 		isSubscriptionType := apiKey.Group != nil && apiKey.Group.IsSubscriptionType()
 		if isSubscriptionType && subscriptionService != nil {
+			// 组织 Key 使用组织 owner 名下的订阅
+			subscriptionUserID := apiKey.User.ID
+			if apiKey.IsOrganizationKey() {
+				subscriptionUserID = apiKey.SubscriptionUserID()
+			}
 			subscription, err := subscriptionService.GetActiveSubscription(
 				c.Request.Context(),
-				apiKey.User.ID,
+				subscriptionUserID,
 				apiKey.Group.ID,
 			)
 			if err != nil {
@@ -100,7 +119,12 @@ func APIKeyAuthWithSubscriptionGoogle(apiKeyService *service.APIKeyService, subs
 				subscriptionService.DoWindowMaintenance(&maintenanceCopy)
 			}
 		} else {
-			if apiKey.User.Balance <= 0 {
+			// 组织 Key 使用组织共享余额；成员月度上限与其他入口一致，由 handler 的 CheckBillingEligibility 校验
+			balance := apiKey.User.Balance
+			if apiKey.IsOrganizationKey() && apiKey.Organization != nil {
+				balance = apiKey.Organization.Balance
+			}
+			if balance <= 0 {
 				abortWithGoogleError(c, 403, "Insufficient account balance")
 				return
 			}
@@ -114,6 +138,7 @@ func APIKeyAuthWithSubscriptionGoogle(apiKeyService *service.APIKeyService, subs
 		c.Set(string(ContextKeyUserRole), apiKey.User.Role)
 		setGroupContext(c, apiKey.Group)
 		_ = apiKeyService.TouchLastUsed(c.Request.Context(), apiKey.ID)
+		endAuthSpan(c, authSpan)
 		c.Next()
 	}
 }
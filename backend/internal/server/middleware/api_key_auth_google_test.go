@@ -19,7 +19,7 @@ import (
 )
 
 type fakeAPIKeyRepo struct {
-	getByKey       func(ctx context.Context, key string) (*service.APIKey, error)
+	getByKeyHash   func(ctx context.Context, keyHash string) (*service.APIKey, error)
 	updateLastUsed func(ctx context.Context, id int64, usedAt time.Time) error
 }
 
@@ -38,17 +38,17 @@ func (f fakeAPIKeyRepo) Create(ctx context.Context, key *service.APIKey) error {
 func (f fakeAPIKeyRepo) GetByID(ctx context.Context, id int64) (*service.APIKey, error) {
 	return nil, errors.New("not implemented")
 }
-func (f fakeAPIKeyRepo) GetKeyAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
+func (f fakeAPIKeyRepo) GetKeyHashAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
 	return "", 0, errors.New("not implemented")
 }
-func (f fakeAPIKeyRepo) GetByKey(ctx context.Context, key string) (*service.APIKey, error) {
-	if f.getByKey == nil {
+func (f fakeAPIKeyRepo) GetByKeyHash(ctx context.Context, keyHash string) (*service.APIKey, error) {
+	if f.getByKeyHash == nil {
 		return nil, errors.New("unexpected call")
 	}
-	return f.getByKey(ctx, key)
+	return f.getByKeyHash(ctx, keyHash)
 }
-func (f fakeAPIKeyRepo) GetByKeyForAuth(ctx context.Context, key string) (*service.APIKey, error) {
-	return f.GetByKey(ctx, key)
+func (f fakeAPIKeyRepo) GetByKeyHashForAuth(ctx context.Context, keyHash string) (*service.APIKey, error) {
+	return f.GetByKeyHash(ctx, keyHash)
 }
 func (f fakeAPIKeyRepo) Update(ctx context.Context, key *service.APIKey) error {
 	return errors.New("not implemented")
@@ -65,7 +65,7 @@ func (f fakeAPIKeyRepo) VerifyOwnership(ctx context.Context, userID int64, apiKe
 func (f fakeAPIKeyRepo) CountByUserID(ctx context.Context, userID int64) (int64, error) {
 	return 0, errors.New("not implemented")
 }
-func (f fakeAPIKeyRepo) ExistsByKey(ctx context.Context, key string) (bool, error) {
+func (f fakeAPIKeyRepo) ExistsByKeyHash(ctx context.Context, keyHash string) (bool, error) {
 	return false, errors.New("not implemented")
 }
 func (f fakeAPIKeyRepo) ListByGroupID(ctx context.Context, groupID int64, params pagination.PaginationParams) ([]service.APIKey, *pagination.PaginationResult, error) {
@@ -80,10 +80,10 @@ func (f fakeAPIKeyRepo) ClearGroupIDByGroupID(ctx context.Context, groupID int64
 func (f fakeAPIKeyRepo) CountByGroupID(ctx context.Context, groupID int64) (int64, error) {
 	return 0, errors.New("not implemented")
 }
-func (f fakeAPIKeyRepo) ListKeysByUserID(ctx context.Context, userID int64) ([]string, error) {
+func (f fakeAPIKeyRepo) ListKeyHashesByUserID(ctx context.Context, userID int64) ([]string, error) {
 	return nil, errors.New("not implemented")
 }
-func (f fakeAPIKeyRepo) ListKeysByGroupID(ctx context.Context, groupID int64) ([]string, error) {
+func (f fakeAPIKeyRepo) ListKeyHashesByGroupID(ctx context.Context, groupID int64) ([]string, error) {
 	return nil, errors.New("not implemented")
 }
 func (f fakeAPIKeyRepo) IncrementQuotaUsed(ctx context.Context, id int64, amount float64) (float64, error) {
@@ -192,6 +192,11 @@ type googleErrorResponse struct {
 	} `json:"error"`
 }
 
+// testKeyHash 计算测试配置（未设置 api_key_hash_secret）下 Key 的存储哈希
+func testKeyHash(key string) string {
+	return service.HashAPIKey("", key)
+}
+
 func newTestAPIKeyService(repo service.APIKeyRepository) *service.APIKeyService {
 	return service.NewAPIKeyService(
 		repo,
@@ -209,7 +214,7 @@ func TestApiKeyAuthWithSubscriptionGoogle_MissingKey(t *testing.T) {
 
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
 			return nil, errors.New("should not be called")
 		},
 	})
@@ -233,7 +238,7 @@ func TestApiKeyAuthWithSubscriptionGoogle_QueryApiKeyRejected(t *testing.T) {
 
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
 			return nil, errors.New("should not be called")
 		},
 	})
@@ -281,8 +286,8 @@ func TestApiKeyAuthWithSubscriptionGoogleSetsGroupContext(t *testing.T) {
 
 	apiKeyService := service.NewAPIKeyService(
 		fakeAPIKeyRepo{
-			getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-				if key != apiKey.Key {
+			getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+				if keyHash != testKeyHash(apiKey.Key) {
 					return nil, service.ErrAPIKeyNotFound
 				}
 				clone := *apiKey
@@ -322,10 +327,9 @@ func TestApiKeyAuthWithSubscriptionGoogle_QueryKeyAllowedOnV1Beta(t *testing.T)
 
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
 			return &service.APIKey{
 				ID:     1,
-				Key:    key,
 				Status: service.StatusActive,
 				User: &service.User{
 					ID:     123,
@@ -350,7 +354,7 @@ func TestApiKeyAuthWithSubscriptionGoogle_InvalidKey(t *testing.T) {
 
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
 			return nil, service.ErrAPIKeyNotFound
 		},
 	})
@@ -375,7 +379,7 @@ func TestApiKeyAuthWithSubscriptionGoogle_RepoError(t *testing.T) {
 
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
 			return nil, errors.New("db down")
 		},
 	})
@@ -400,10 +404,9 @@ func TestApiKeyAuthWithSubscriptionGoogle_DisabledKey(t *testing.T) {
 
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
 			return &service.APIKey{
 				ID:     1,
-				Key:    key,
 				Status: service.StatusDisabled,
 				User: &service.User{
 					ID:     123,
@@ -433,10 +436,9 @@ func TestApiKeyAuthWithSubscriptionGoogle_InsufficientBalance(t *testing.T) {
 
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
 			return &service.APIKey{
 				ID:     1,
-				Key:    key,
 				Status: service.StatusActive,
 				User: &service.User{
 					ID:      123,
@@ -484,8 +486,8 @@ func TestApiKeyAuthWithSubscriptionGoogle_TouchesLastUsedOnSuccess(t *testing.T)
 	var touchedAt time.Time
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -532,8 +534,8 @@ func TestApiKeyAuthWithSubscriptionGoogle_TouchFailureDoesNotBlock(t *testing.T)
 	touchCalls := 0
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -578,8 +580,8 @@ func TestApiKeyAuthWithSubscriptionGoogle_TouchesLastUsedInStandardMode(t *testi
 	touchCalls := 0
 	r := gin.New()
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -634,8 +636,8 @@ func TestApiKeyAuthWithSubscriptionGoogle_SubscriptionLimitExceededReturns429(t
 	apiKey.GroupID = &group.ID
 
 	apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -684,3 +686,101 @@ func TestApiKeyAuthWithSubscriptionGoogle_SubscriptionLimitExceededReturns429(t
 	require.Equal(t, "RESOURCE_EXHAUSTED", resp.Error.Status)
 	require.Contains(t, resp.Error.Message, "daily usage limit exceeded")
 }
+
+func TestApiKeyAuthWithSubscriptionGoogle_OrganizationKey(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	owner := int64(888)
+	newOrgKey := func(org *service.Organization, group *service.Group) *service.APIKey {
+		user := &service.User{ID: 123, Role: service.RoleUser, Status: service.StatusActive, Balance: 0, Concurrency: 2}
+		key := &service.APIKey{
+			ID:     9,
+			UserID: user.ID,
+			Key:    "google-org-key",
+			Status: service.StatusActive,
+			User:   user,
+			Group:  group,
+		}
+		orgID := int64(55)
+		key.OrganizationID = &orgID
+		key.Organization = org
+		if group != nil {
+			key.GroupID = &group.ID
+		}
+		return key
+	}
+	serve := func(apiKey *service.APIKey, subscriptionService *service.SubscriptionService) (*httptest.ResponseRecorder, googleErrorResponse) {
+		apiKeyService := newTestAPIKeyService(fakeAPIKeyRepo{
+			getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+				clone := *apiKey
+				return &clone, nil
+			},
+		})
+		r := gin.New()
+		r.Use(APIKeyAuthWithSubscriptionGoogle(apiKeyService, subscriptionService, &config.Config{RunMode: config.RunModeStandard}))
+		r.GET("/v1beta/test", func(c *gin.Context) { c.JSON(200, gin.H{"ok": true}) })
+
+		req := httptest.NewRequest(http.MethodGet, "/v1beta/test", nil)
+		req.Header.Set("x-goog-api-key", apiKey.Key)
+		rec := httptest.NewRecorder()
+		r.ServeHTTP(rec, req)
+		var resp googleErrorResponse
+		if rec.Code != http.StatusOK {
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &resp))
+		}
+		return rec, resp
+	}
+
+	t.Run("disabled organization", func(t *testing.T) {
+		rec, resp := serve(newOrgKey(&service.Organization{ID: 55, OwnerUserID: owner, Balance: 50, Status: service.StatusDisabled}, nil), nil)
+		require.Equal(t, http.StatusForbidden, rec.Code)
+		require.Equal(t, "Organization is disabled", resp.Error.Message)
+	})
+
+	t.Run("deleted organization", func(t *testing.T) {
+		rec, resp := serve(newOrgKey(nil, nil), nil)
+		require.Equal(t, http.StatusForbidden, rec.Code)
+		require.Equal(t, "Organization is disabled", resp.Error.Message)
+	})
+
+	t.Run("uses organization balance", func(t *testing.T) {
+		rec, _ := serve(newOrgKey(&service.Organization{ID: 55, OwnerUserID: owner, Balance: 50, Status: service.StatusActive}, nil), nil)
+		require.Equal(t, http.StatusOK, rec.Code, "成员个人余额为 0 时组织 Key 仍可使用组织余额")
+
+		rec, resp := serve(newOrgKey(&service.Organization{ID: 55, OwnerUserID: owner, Balance: 0, Status: service.StatusActive}, nil), nil)
+		require.Equal(t, http.StatusForbidden, rec.Code)
+		require.Equal(t, "Insufficient account balance", resp.Error.Message)
+	})
+
+	t.Run("uses owner subscription", func(t *testing.T) {
+		group := &service.Group{
+			ID:               77,
+			Name:             "gemini-sub",
+			Status:           service.StatusActive,
+			Platform:         service.PlatformGemini,
+			Hydrated:         true,
+			SubscriptionType: service.SubscriptionTypeSubscription,
+		}
+		var lookedUp int64
+		subscriptionService := service.NewSubscriptionService(nil, fakeGoogleSubscriptionRepo{
+			getActive: func(ctx context.Context, userID, groupID int64) (*service.UserSubscription, error) {
+				lookedUp = userID
+				if userID != owner {
+					return nil, service.ErrSubscriptionNotFound
+				}
+				return &service.UserSubscription{
+					ID:        601,
+					UserID:    owner,
+					GroupID:   groupID,
+					Status:    service.SubscriptionStatusActive,
+					ExpiresAt: time.Now().Add(24 * time.Hour),
+				}, nil
+			},
+			activateWindow: func(ctx context.Context, id int64, start time.Time) error { return nil },
+		}, nil, nil, &config.Config{RunMode: config.RunModeStandard})
+
+		rec, _ := serve(newOrgKey(&service.Organization{ID: 55, OwnerUserID: owner, Status: service.StatusActive}, group), subscriptionService)
+		require.Equal(t, http.StatusOK, rec.Code)
+		require.Equal(t, owner, lookedUp)
+	})
+}
@@ -48,8 +48,8 @@ func TestSimpleModeBypassesQuotaCheck(t *testing.T) {
 	apiKey.GroupID = &group.ID
 
 	apiKeyRepo := &stubApiKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -205,8 +205,8 @@ func TestAPIKeyAuthSetsGroupContext(t *testing.T) {
 	apiKey.GroupID = &group.ID
 
 	apiKeyRepo := &stubApiKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -263,8 +263,8 @@ func TestAPIKeyAuthOverwritesInvalidContextGroup(t *testing.T) {
 	apiKey.GroupID = &group.ID
 
 	apiKeyRepo := &stubApiKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -320,8 +320,8 @@ func TestAPIKeyAuthIPRestrictionDoesNotTrustSpoofedForwardHeaders(t *testing.T)
 	}
 
 	apiKeyRepo := &stubApiKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -372,8 +372,8 @@ func TestAPIKeyAuthTouchesLastUsedOnSuccess(t *testing.T) {
 	var touchedID int64
 	var touchedAt time.Time
 	apiKeyRepo := &stubApiKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -420,8 +420,8 @@ func TestAPIKeyAuthTouchLastUsedFailureDoesNotBlock(t *testing.T) {
 
 	touchCalls := 0
 	apiKeyRepo := &stubApiKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -466,8 +466,8 @@ func TestAPIKeyAuthTouchesLastUsedInStandardMode(t *testing.T) {
 
 	touchCalls := 0
 	apiKeyRepo := &stubApiKeyRepo{
-		getByKey: func(ctx context.Context, key string) (*service.APIKey, error) {
-			if key != apiKey.Key {
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
 				return nil, service.ErrAPIKeyNotFound
 			}
 			clone := *apiKey
@@ -502,7 +502,7 @@ func newAuthTestRouter(apiKeyService *service.APIKeyService, subscriptionService
 }
 
 type stubApiKeyRepo struct {
-	getByKey       func(ctx context.Context, key string) (*service.APIKey, error)
+	getByKeyHash   func(ctx context.Context, keyHash string) (*service.APIKey, error)
 	updateLastUsed func(ctx context.Context, id int64, usedAt time.Time) error
 }
 
@@ -514,19 +514,19 @@ func (r *stubApiKeyRepo) GetByID(ctx context.Context, id int64) (*service.APIKey
 	return nil, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) GetKeyAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
+func (r *stubApiKeyRepo) GetKeyHashAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
 	return "", 0, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) GetByKey(ctx context.Context, key string) (*service.APIKey, error) {
-	if r.getByKey != nil {
-		return r.getByKey(ctx, key)
+func (r *stubApiKeyRepo) GetByKeyHash(ctx context.Context, keyHash string) (*service.APIKey, error) {
+	if r.getByKeyHash != nil {
+		return r.getByKeyHash(ctx, keyHash)
 	}
 	return nil, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) GetByKeyForAuth(ctx context.Context, key string) (*service.APIKey, error) {
-	return r.GetByKey(ctx, key)
+func (r *stubApiKeyRepo) GetByKeyHashForAuth(ctx context.Context, keyHash string) (*service.APIKey, error) {
+	return r.GetByKeyHash(ctx, keyHash)
 }
 
 func (r *stubApiKeyRepo) Update(ctx context.Context, key *service.APIKey) error {
@@ -549,7 +549,7 @@ func (r *stubApiKeyRepo) CountByUserID(ctx context.Context, userID int64) (int64
 	return 0, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) ExistsByKey(ctx context.Context, key string) (bool, error) {
+func (r *stubApiKeyRepo) ExistsByKeyHash(ctx context.Context, keyHash string) (bool, error) {
 	return false, errors.New("not implemented")
 }
 
@@ -569,11 +569,11 @@ func (r *stubApiKeyRepo) CountByGroupID(ctx context.Context, groupID int64) (int
 	return 0, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) ListKeysByUserID(ctx context.Context, userID int64) ([]string, error) {
+func (r *stubApiKeyRepo) ListKeyHashesByUserID(ctx context.Context, userID int64) ([]string, error) {
 	return nil, errors.New("not implemented")
 }
 
-func (r *stubApiKeyRepo) ListKeysByGroupID(ctx context.Context, groupID int64) ([]string, error) {
+func (r *stubApiKeyRepo) ListKeyHashesByGroupID(ctx context.Context, groupID int64) ([]string, error) {
 	return nil, errors.New("not implemented")
 }
 
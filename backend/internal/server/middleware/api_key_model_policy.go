@@ -0,0 +1,130 @@
+package middleware
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	pkghttputil "github.com/Wei-Shaw/sub2api/internal/pkg/httputil"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// modelPolicyDenyWriter 输出模型被拒绝的错误（不同协议使用不同格式）
+type modelPolicyDenyWriter func(c *gin.Context, model string)
+
+func denyModelDefault(c *gin.Context, model string) {
+	AbortWithError(c, http.StatusForbidden, "MODEL_NOT_ALLOWED", modelNotAllowedMessage(model))
+}
+
+func denyModelGoogle(c *gin.Context, model string) {
+	abortWithGoogleError(c, http.StatusForbidden, modelNotAllowedMessage(model))
+}
+
+func modelNotAllowedMessage(model string) string {
+	return fmt.Sprintf("Model %q is not allowed for this API key", model)
+}
+
+// applyAPIKeyModelPolicy 在账号调度之前执行 API Key 模型策略。
+//
+//   - Gemini 原生路由：模型位于 URL（/models/{model}:{action}），别名直接改写路由参数
+//   - 其他路由：模型位于 JSON 请求体的 model 字段，别名改写后回填请求体
+//
+// 白/黑名单按别名解析后的真实模型校验；未配置策略的 Key 不读取请求体。
+// 请求体读取失败时不在此处报错，而是把同一个错误留给 handler，保持其原有的 413/400 响应。
+// 返回 false 表示请求已被拒绝并中断。
+func applyAPIKeyModelPolicy(c *gin.Context, apiKey *service.APIKey, deny modelPolicyDenyWriter) bool {
+	if !apiKey.HasModelPolicy() || c.Request == nil {
+		return true
+	}
+
+	if handled, ok := applyModelPolicyToPathParams(c, apiKey, deny); handled {
+		return ok
+	}
+
+	if c.Request.Method != http.MethodPost || c.Request.Body == nil || c.Request.Body == http.NoBody {
+		return true
+	}
+	// multipart 上传（如 /v1/files）不携带模型字段，且可能很大，不读入内存
+	if strings.HasPrefix(strings.ToLower(c.GetHeader("Content-Type")), "multipart/") {
+		return true
+	}
+	body, err := pkghttputil.ReadRequestBodyWithPrealloc(c.Request)
+	if err != nil {
+		c.Request.Body = io.NopCloser(failingReader{err: err})
+		return true
+	}
+
+	model := gjson.GetBytes(body, "model")
+	if model.Type == gjson.String && model.String() != "" {
+		requested := model.String()
+		resolved := apiKey.ResolveModelAlias(requested)
+		if !apiKey.IsModelAllowed(resolved) {
+			restoreRequestBody(c.Request, body)
+			deny(c, requested)
+			return false
+		}
+		if resolved != requested {
+			if rewritten, setErr := sjson.SetBytes(body, "model", resolved); setErr == nil {
+				body = rewritten
+			}
+		}
+	}
+	restoreRequestBody(c.Request, body)
+	return true
+}
+
+// applyModelPolicyToPathParams 处理 Gemini 风格的 URL 模型参数。
+// handled=false 表示当前路由不携带模型参数，需要继续检查请求体。
+func applyModelPolicyToPathParams(c *gin.Context, apiKey *service.APIKey, deny modelPolicyDenyWriter) (handled bool, ok bool) {
+	for i := range c.Params {
+		param := &c.Params[i]
+		if param.Key != "modelAction" && param.Key != "model" {
+			continue
+		}
+		raw := strings.TrimPrefix(param.Value, "/")
+		requested, action, hasAction := strings.Cut(raw, ":")
+		if param.Key == "model" {
+			requested, action, hasAction = raw, "", false
+		}
+		if requested == "" {
+			return true, true
+		}
+		resolved := apiKey.ResolveModelAlias(requested)
+		if !apiKey.IsModelAllowed(resolved) {
+			deny(c, requested)
+			return true, false
+		}
+		if resolved != requested {
+			value := resolved
+			if hasAction {
+				value += ":" + action
+			}
+			if strings.HasPrefix(param.Value, "/") {
+				value = "/" + value
+			}
+			param.Value = value
+		}
+		return true, true
+	}
+	return false, true
+}
+
+func restoreRequestBody(req *http.Request, body []byte) {
+	req.Body = io.NopCloser(bytes.NewReader(body))
+	req.ContentLength = int64(len(body))
+}
+
+// failingReader 重放读取请求体时遇到的错误（如 *http.MaxBytesError）
+type failingReader struct {
+	err error
+}
+
+func (r failingReader) Read([]byte) (int, error) {
+	return 0, r.err
+}
@@ -0,0 +1,174 @@
+//go:build unit
+
+package middleware
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+func newModelPolicyTestAPIKeyService(t *testing.T, apiKey *service.APIKey) (*service.APIKeyService, *config.Config) {
+	t.Helper()
+	apiKeyRepo := &stubApiKeyRepo{
+		getByKeyHash: func(ctx context.Context, keyHash string) (*service.APIKey, error) {
+			if keyHash != testKeyHash(apiKey.Key) {
+				return nil, service.ErrAPIKeyNotFound
+			}
+			clone := *apiKey
+			return &clone, nil
+		},
+	}
+	cfg := &config.Config{RunMode: config.RunModeSimple}
+	return service.NewAPIKeyService(apiKeyRepo, nil, nil, nil, nil, nil, cfg), cfg
+}
+
+func newModelPolicyTestAPIKey() *service.APIKey {
+	user := &service.User{ID: 7, Role: service.RoleUser, Status: service.StatusActive, Balance: 10, Concurrency: 3}
+	return &service.APIKey{
+		ID:            100,
+		UserID:        user.ID,
+		Key:           "test-key",
+		Status:        service.StatusActive,
+		User:          user,
+		AllowedModels: []string{"claude-*-haiku*", "claude-sonnet-*"},
+		DeniedModels:  []string{"claude-sonnet-4-5-preview"},
+		ModelAliases:  map[string]string{"fast": "claude-3-5-haiku-20241022", "big": "claude-opus-4-1"},
+	}
+}
+
+func TestAPIKeyAuthModelPolicy_Body(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	apiKeyService, cfg := newModelPolicyTestAPIKeyService(t, newModelPolicyTestAPIKey())
+
+	var seenBody string
+	router := gin.New()
+	router.Use(gin.HandlerFunc(NewAPIKeyAuthMiddleware(apiKeyService, nil, cfg)))
+	router.POST("/v1/messages", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		seenBody = string(body)
+		c.Status(http.StatusOK)
+	})
+
+	send := func(body string) *httptest.ResponseRecorder {
+		w := httptest.NewRecorder()
+		req := httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(body))
+		req.Header.Set("x-api-key", "test-key")
+		router.ServeHTTP(w, req)
+		return w
+	}
+
+	tests := []struct {
+		name      string
+		body      string
+		wantCode  int
+		wantModel string
+	}{
+		{name: "allowed by wildcard", body: `{"model":"claude-3-5-haiku-20241022"}`, wantCode: http.StatusOK, wantModel: "claude-3-5-haiku-20241022"},
+		{name: "alias rewritten", body: `{"model":"fast","stream":true}`, wantCode: http.StatusOK, wantModel: "claude-3-5-haiku-20241022"},
+		{name: "not in allowlist", body: `{"model":"claude-opus-4-1"}`, wantCode: http.StatusForbidden},
+		{name: "alias target not allowed", body: `{"model":"big"}`, wantCode: http.StatusForbidden},
+		{name: "denylist wins", body: `{"model":"claude-sonnet-4-5-preview"}`, wantCode: http.StatusForbidden},
+		{name: "no model left to handler", body: `{"messages":[]}`, wantCode: http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			seenBody = ""
+			w := send(tt.body)
+			require.Equal(t, tt.wantCode, w.Code)
+			if tt.wantCode == http.StatusForbidden {
+				require.Contains(t, w.Body.String(), "MODEL_NOT_ALLOWED")
+				return
+			}
+			require.Equal(t, tt.wantModel, gjson.Get(seenBody, "model").String())
+		})
+	}
+}
+
+func TestAPIKeyAuthModelPolicy_GeminiPath(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	apiKey := newModelPolicyTestAPIKey()
+	apiKey.AllowedModels = []string{"gemini-2.5-*"}
+	apiKey.DeniedModels = nil
+	apiKey.ModelAliases = map[string]string{"flash": "gemini-2.5-flash"}
+	apiKeyService, cfg := newModelPolicyTestAPIKeyService(t, apiKey)
+
+	var seenParam string
+	router := gin.New()
+	router.Use(APIKeyAuthWithSubscriptionGoogle(apiKeyService, nil, cfg))
+	router.POST("/v1beta/models/*modelAction", func(c *gin.Context) {
+		seenParam = c.Param("modelAction")
+		c.Status(http.StatusOK)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1beta/models/flash:streamGenerateContent", strings.NewReader(`{}`))
+	req.Header.Set("x-goog-api-key", "test-key")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, "/gemini-2.5-flash:streamGenerateContent", seenParam)
+
+	w = httptest.NewRecorder()
+	req = httptest.NewRequest(http.MethodPost, "/v1beta/models/gemini-1.5-pro:generateContent", strings.NewReader(`{}`))
+	req.Header.Set("x-goog-api-key", "test-key")
+	router.ServeHTTP(w, req)
+	require.Equal(t, http.StatusForbidden, w.Code)
+	require.Equal(t, "PERMISSION_DENIED", gjson.Get(w.Body.String(), "error.status").String())
+}
+
+func TestAPIKeyAuthModelPolicy_BodyReadErrorLeftToHandler(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	apiKeyService, cfg := newModelPolicyTestAPIKeyService(t, newModelPolicyTestAPIKey())
+
+	var readErr error
+	router := gin.New()
+	router.Use(RequestBodyLimit(4))
+	router.Use(gin.HandlerFunc(NewAPIKeyAuthMiddleware(apiKeyService, nil, cfg)))
+	router.POST("/v1/messages", func(c *gin.Context) {
+		_, readErr = io.ReadAll(c.Request.Body)
+		c.Status(http.StatusRequestEntityTooLarge)
+	})
+
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/messages", strings.NewReader(`{"model":"claude-opus-4-1"}`))
+	req.Header.Set("x-api-key", "test-key")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusRequestEntityTooLarge, w.Code)
+	var maxErr *http.MaxBytesError
+	require.ErrorAs(t, readErr, &maxErr)
+}
+
+func TestAPIKeyAuthModelPolicy_MultipartSkipped(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	apiKeyService, cfg := newModelPolicyTestAPIKeyService(t, newModelPolicyTestAPIKey())
+
+	var seenBody string
+	router := gin.New()
+	router.Use(gin.HandlerFunc(NewAPIKeyAuthMiddleware(apiKeyService, nil, cfg)))
+	router.POST("/v1/files", func(c *gin.Context) {
+		body, _ := io.ReadAll(c.Request.Body)
+		seenBody = string(body)
+		c.Status(http.StatusOK)
+	})
+
+	// 上传内容中的 model 字段不受模型策略约束，请求体原样交给 handler
+	body := `{"model":"claude-opus-4-1"}`
+	w := httptest.NewRecorder()
+	req := httptest.NewRequest(http.MethodPost, "/v1/files", strings.NewReader(body))
+	req.Header.Set("x-api-key", "test-key")
+	req.Header.Set("Content-Type", "multipart/form-data; boundary=x")
+	router.ServeHTTP(w, req)
+
+	require.Equal(t, http.StatusOK, w.Code)
+	require.Equal(t, body, seenBody)
+}
@@ -0,0 +1,39 @@
+package middleware
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+)
+
+// APIKeyThrottle 执行 API Key 级 RPM/TPM/并发限制，需注册在 API Key 认证之后。
+// 超限时返回 429 并设置 Retry-After，错误体格式由 writeError 决定；
+// 并发槽位在整个请求（含流式响应、WebSocket 会话）结束后释放。
+func APIKeyThrottle(throttle *service.APIKeyThrottleService, writeError GatewayErrorWriter) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		apiKey, ok := GetAPIKeyFromContext(c)
+		if !ok || !apiKey.HasThroughputLimits() {
+			c.Next()
+			return
+		}
+
+		release, err := throttle.Acquire(c.Request.Context(), apiKey)
+		if err != nil {
+			var throttleErr *service.APIKeyThrottleError
+			if errors.As(err, &throttleErr) {
+				c.Header("Retry-After", strconv.Itoa(throttleErr.RetryAfterSeconds()))
+				writeError(c, http.StatusTooManyRequests, throttleErr.Error())
+			} else {
+				writeError(c, http.StatusInternalServerError, "Failed to check API key rate limit")
+			}
+			c.Abort()
+			return
+		}
+		defer release()
+
+		c.Next()
+	}
+}
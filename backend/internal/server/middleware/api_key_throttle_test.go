@@ -0,0 +1,83 @@
+//go:build unit
+
+package middleware
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/Wei-Shaw/sub2api/internal/testutil"
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type throttleRPMCacheStub struct {
+	service.RPMCache
+	count int
+}
+
+func (s *throttleRPMCacheStub) IncrementAPIKeyRPM(context.Context, int64) (int, time.Duration, error) {
+	s.count++
+	return s.count, 42 * time.Second, nil
+}
+
+func newThrottleTestRouter(apiKey *service.APIKey, writer GatewayErrorWriter, path string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	throttle := service.NewAPIKeyThrottleService(service.NewConcurrencyService(testutil.StubConcurrencyCache{}), &throttleRPMCacheStub{})
+	router := gin.New()
+	router.Use(func(c *gin.Context) {
+		c.Set(string(ContextKeyAPIKey), apiKey)
+		c.Next()
+	})
+	router.Use(APIKeyThrottle(throttle, writer))
+	router.POST(path, func(c *gin.Context) { c.Status(http.StatusOK) })
+	return router
+}
+
+func TestAPIKeyThrottle_ErrorShapes(t *testing.T) {
+	tests := []struct {
+		name      string
+		path      string
+		writer    GatewayErrorWriter
+		typePath  string
+		wantValue string
+	}{
+		{name: "anthropic messages", path: "/v1/messages", writer: V1ErrorWriter, typePath: "error.type", wantValue: "rate_limit_error"},
+		{name: "openai responses", path: "/v1/responses", writer: V1ErrorWriter, typePath: "error.type", wantValue: "rate_limit_error"},
+		{name: "openai chat completions", path: "/v1/chat/completions", writer: V1ErrorWriter, typePath: "error.type", wantValue: "rate_limit_error"},
+		{name: "gemini", path: "/v1beta/models/gemini:generateContent", writer: GoogleErrorWriter, typePath: "error.status", wantValue: "RESOURCE_EXHAUSTED"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			router := newThrottleTestRouter(&service.APIKey{ID: 1, RPMLimit: 1}, tt.writer, tt.path)
+
+			w := httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, nil))
+			require.Equal(t, http.StatusOK, w.Code)
+
+			w = httptest.NewRecorder()
+			router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, tt.path, nil))
+			require.Equal(t, http.StatusTooManyRequests, w.Code)
+			require.Equal(t, "42", w.Header().Get("Retry-After"))
+			require.Equal(t, tt.wantValue, gjson.Get(w.Body.String(), tt.typePath).String())
+
+			// Anthropic 格式带顶层 type=error，OpenAI 格式没有
+			isAnthropic := tt.path == "/v1/messages"
+			require.Equal(t, isAnthropic, gjson.Get(w.Body.String(), "type").String() == "error")
+		})
+	}
+}
+
+func TestAPIKeyThrottle_SkipsKeysWithoutLimits(t *testing.T) {
+	router := newThrottleTestRouter(&service.APIKey{ID: 2}, V1ErrorWriter, "/v1/messages")
+	for i := 0; i < 3; i++ {
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, "/v1/messages", nil))
+		require.Equal(t, http.StatusOK, w.Code)
+	}
+}
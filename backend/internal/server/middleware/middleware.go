@@ -3,6 +3,7 @@ package middleware
 import (
 	"context"
 	"net/http"
+	"strings"
 
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/googleapi"
@@ -24,6 +25,8 @@ const (
 	ContextKeySubscription ContextKey = "subscription"
 	// ContextKeyForcePlatform 强制平台（用于 /antigravity 路由）
 	ContextKeyForcePlatform ContextKey = "force_platform"
+	// ContextKeyAdminAPIToken 通过具名管理员 API Token 认证时的 Token（*service.AdminAPIToken）
+	ContextKeyAdminAPIToken ContextKey = "admin_api_token"
 )
 
 // ForcePlatform 返回设置强制平台的中间件
@@ -86,10 +89,40 @@ type GatewayErrorWriter func(c *gin.Context, status int, message string)
 func AnthropicErrorWriter(c *gin.Context, status int, message string) {
 	c.JSON(status, gin.H{
 		"type":  "error",
-		"error": gin.H{"type": "permission_error", "message": message},
+		"error": gin.H{"type": gatewayErrorType(status), "message": message},
 	})
 }
 
+// OpenAIErrorWriter 按 OpenAI API 规范输出错误
+func OpenAIErrorWriter(c *gin.Context, status int, message string) {
+	c.JSON(status, gin.H{
+		"error": gin.H{"type": gatewayErrorType(status), "message": message},
+	})
+}
+
+// V1ErrorWriter 按 /v1 下的具体端点选择错误格式：
+// Responses / Chat Completions / Files / Batches 为 OpenAI 格式，其余（Messages 等）为 Anthropic 格式
+func V1ErrorWriter(c *gin.Context, status int, message string) {
+	path := c.Request.URL.Path
+	if strings.Contains(path, "/responses") || strings.HasSuffix(path, "/chat/completions") ||
+		strings.HasPrefix(path, "/v1/files") || strings.HasPrefix(path, "/v1/batches") {
+		OpenAIErrorWriter(c, status, message)
+		return
+	}
+	AnthropicErrorWriter(c, status, message)
+}
+
+func gatewayErrorType(status int) string {
+	switch {
+	case status == http.StatusTooManyRequests:
+		return "rate_limit_error"
+	case status >= http.StatusInternalServerError:
+		return "api_error"
+	default:
+		return "permission_error"
+	}
+}
+
 // GoogleErrorWriter 按 Google API 规范输出错误
 func GoogleErrorWriter(c *gin.Context, status int, message string) {
 	c.JSON(status, gin.H{
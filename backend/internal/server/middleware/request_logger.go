@@ -6,6 +6,7 @@ import (
 
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/gin-gonic/gin"
 	"github.com/google/uuid"
 	"go.uber.org/zap"
@@ -30,13 +31,17 @@ func RequestLogger() gin.HandlerFunc {
 		ctx := context.WithValue(c.Request.Context(), ctxkey.RequestID, requestID)
 		clientRequestID, _ := ctx.Value(ctxkey.ClientRequestID).(string)
 
-		requestLogger := logger.With(
+		fields := []zap.Field{
 			zap.String("component", "http"),
 			zap.String("request_id", requestID),
 			zap.String("client_request_id", strings.TrimSpace(clientRequestID)),
 			zap.String("path", c.Request.URL.Path),
 			zap.String("method", c.Request.Method),
-		)
+		}
+		if traceID := tracing.TraceIDFromContext(ctx); traceID != "" {
+			fields = append(fields, zap.String("trace_id", traceID))
+		}
+		requestLogger := logger.With(fields...)
 
 		ctx = logger.IntoContext(ctx, requestLogger)
 		c.Request = c.Request.WithContext(ctx)
@@ -0,0 +1,81 @@
+package middleware
+
+import (
+	"net/http"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"github.com/Wei-Shaw/sub2api/internal/service"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// Tracing 为每个请求创建 server span，并继承客户端传入的 W3C traceparent。
+// 追踪未启用时 span 为 no-op，但请求上下文中仍保留客户端的 trace id（用于 ops 错误日志关联）。
+func Tracing() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if c.Request == nil {
+			c.Next()
+			return
+		}
+
+		route := c.FullPath()
+		if route == "" {
+			route = c.Request.URL.Path
+		}
+		ctx := tracing.Extract(c.Request.Context(), c.Request.Header)
+		ctx, span := tracing.Tracer().Start(ctx, c.Request.Method+" "+route,
+			trace.WithSpanKind(trace.SpanKindServer),
+			trace.WithAttributes(
+				attribute.String("http.request.method", c.Request.Method),
+				attribute.String("http.route", route),
+				attribute.String("url.path", c.Request.URL.Path),
+			),
+		)
+		defer span.End()
+
+		c.Request = c.Request.WithContext(ctx)
+		c.Next()
+
+		status := c.Writer.Status()
+		span.SetAttributes(attribute.Int("http.response.status_code", status))
+		if requestID, _ := c.Request.Context().Value(ctxkey.RequestID).(string); requestID != "" {
+			span.SetAttributes(attribute.String("request_id", requestID))
+		}
+		if apiKey, ok := GetAPIKeyFromContext(c); ok && apiKey != nil {
+			span.SetAttributes(attribute.Int64("api_key.id", apiKey.ID))
+			if apiKey.GroupID != nil {
+				span.SetAttributes(attribute.Int64("group.id", *apiKey.GroupID))
+			}
+		}
+		if status >= http.StatusInternalServerError {
+			span.SetStatus(codes.Error, http.StatusText(status))
+		}
+	}
+}
+
+// startAuthSpan 开始鉴权 span。鉴权通过时须在 c.Next() 之前调用 endAuthSpan，
+// 否则 span 会覆盖整个下游处理；失败分支由调用方 defer 兜底（重复 End 为 no-op）。
+func startAuthSpan(c *gin.Context, name string) trace.Span {
+	_, span := tracing.Start(c.Request.Context(), name)
+	return span
+}
+
+func endAuthSpan(c *gin.Context, span trace.Span) {
+	if !span.IsRecording() {
+		span.End()
+		return
+	}
+	if apiKey, ok := c.Get(string(ContextKeyAPIKey)); ok {
+		if k, ok := apiKey.(*service.APIKey); ok && k != nil {
+			span.SetAttributes(attribute.Int64("api_key.id", k.ID), attribute.Int64("user.id", k.UserID))
+		}
+	}
+	if c.IsAborted() {
+		span.SetStatus(codes.Error, http.StatusText(c.Writer.Status()))
+		span.SetAttributes(attribute.Int("http.response.status_code", c.Writer.Status()))
+	}
+	span.End()
+}
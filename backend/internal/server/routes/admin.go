@@ -4,6 +4,7 @@ package routes
 import (
 	"github.com/Wei-Shaw/sub2api/internal/handler"
 	"github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
 
 	"github.com/gin-gonic/gin"
 )
@@ -13,80 +14,98 @@ func RegisterAdminRoutes(
 	v1 *gin.RouterGroup,
 	h *handler.Handlers,
 	adminAuth middleware.AdminAuthMiddleware,
+	adminAudit middleware.AdminAuditMiddleware,
 ) {
 	admin := v1.Group("/admin")
 	admin.Use(gin.HandlerFunc(adminAuth))
+	admin.Use(gin.HandlerFunc(adminAudit))
 	{
 		// 仪表盘
-		registerDashboardRoutes(admin, h)
+		registerDashboardRoutes(adminScope(admin, service.AdminScopeResourceDashboard), h)
 
 		// 用户管理
-		registerUserManagementRoutes(admin, h)
+		registerUserManagementRoutes(adminScope(admin, service.AdminScopeResourceUsers), h)
 
 		// 分组管理
-		registerGroupRoutes(admin, h)
+		registerGroupRoutes(adminScope(admin, service.AdminScopeResourceGroups), h)
 
 		// 账号管理
-		registerAccountRoutes(admin, h)
+		registerAccountRoutes(adminScope(admin, service.AdminScopeResourceAccounts), h)
 
 		// 公告管理
-		registerAnnouncementRoutes(admin, h)
+		registerAnnouncementRoutes(adminScope(admin, service.AdminScopeResourceAnnouncements), h)
 
 		// OpenAI OAuth
-		registerOpenAIOAuthRoutes(admin, h)
+		registerOpenAIOAuthRoutes(adminScope(admin, service.AdminScopeResourceAccounts), h)
 		// Sora OAuth（实现复用 OpenAI OAuth 服务，入口独立）
-		registerSoraOAuthRoutes(admin, h)
+		registerSoraOAuthRoutes(adminScope(admin, service.AdminScopeResourceAccounts), h)
 
 		// Gemini OAuth
-		registerGeminiOAuthRoutes(admin, h)
+		registerGeminiOAuthRoutes(adminScope(admin, service.AdminScopeResourceAccounts), h)
 
 		// Antigravity OAuth
-		registerAntigravityOAuthRoutes(admin, h)
+		registerAntigravityOAuthRoutes(adminScope(admin, service.AdminScopeResourceAccounts), h)
 
 		// 代理管理
-		registerProxyRoutes(admin, h)
+		registerProxyRoutes(adminScope(admin, service.AdminScopeResourceProxies), h)
+		registerProxyPoolRoutes(adminScope(admin, service.AdminScopeResourceProxies), h)
 
 		// 卡密管理
-		registerRedeemCodeRoutes(admin, h)
+		registerRedeemCodeRoutes(adminScope(admin, service.AdminScopeResourceBilling), h)
 
 		// 优惠码管理
-		registerPromoCodeRoutes(admin, h)
+		registerPromoCodeRoutes(adminScope(admin, service.AdminScopeResourceBilling), h)
 
 		// 系统设置
-		registerSettingsRoutes(admin, h)
+		registerSettingsRoutes(adminScope(admin, service.AdminScopeResourceSettings), h)
 
 		// 数据管理
-		registerDataManagementRoutes(admin, h)
+		registerDataManagementRoutes(adminScope(admin, service.AdminScopeResourceSystem), h)
 
 		// 运维监控（Ops）
-		registerOpsRoutes(admin, h)
+		registerOpsRoutes(adminScope(admin, service.AdminScopeResourceOps), h)
 
 		// 系统管理
-		registerSystemRoutes(admin, h)
+		registerSystemRoutes(adminScope(admin, service.AdminScopeResourceSystem), h)
 
 		// 订阅管理
-		registerSubscriptionRoutes(admin, h)
+		registerSubscriptionRoutes(adminScope(admin, service.AdminScopeResourceBilling), h)
 
 		// 订单管理
-		registerOrderRoutes(admin, h)
+		registerOrderRoutes(adminScope(admin, service.AdminScopeResourceBilling), h)
+
+		// 组织管理
+		registerOrganizationRoutes(adminScope(admin, service.AdminScopeResourceBilling), h)
 
 		// 使用记录管理
-		registerUsageRoutes(admin, h)
+		registerUsageRoutes(adminScope(admin, service.AdminScopeResourceUsage), h)
 
 		// 用户属性管理
-		registerUserAttributeRoutes(admin, h)
+		registerUserAttributeRoutes(adminScope(admin, service.AdminScopeResourceUsers), h)
 
 		// 错误透传规则管理
-		registerErrorPassthroughRoutes(admin, h)
+		registerErrorPassthroughRoutes(adminScope(admin, service.AdminScopeResourceSettings), h)
 
 		// API Key 管理
-		registerAdminAPIKeyRoutes(admin, h)
+		registerAdminAPIKeyRoutes(adminScope(admin, service.AdminScopeResourceUsers), h)
 
 		// 定时测试计划
-		registerScheduledTestRoutes(admin, h)
+		registerScheduledTestRoutes(adminScope(admin, service.AdminScopeResourceAccounts), h)
+
+		// 操作审计日志
+		registerAuditLogRoutes(adminScope(admin, service.AdminScopeResourceAudit), h)
+
+		// 月度账单
+		registerStatementRoutes(adminScope(admin, service.AdminScopeResourceBilling), h)
 	}
 }
 
+// adminScope 返回要求管理员 API Token 具备指定资源 scope 的路由组
+// （JWT 登录与全局 Admin API Key 不受限制）
+func adminScope(admin *gin.RouterGroup, resource string) *gin.RouterGroup {
+	return admin.Group("", middleware.RequireAdminScope(resource))
+}
+
 func registerAdminAPIKeyRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 	apiKeys := admin.Group("/api-keys")
 	{
@@ -102,6 +121,7 @@ func registerOpsRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		ops.GET("/user-concurrency", h.Admin.Ops.GetUserConcurrencyStats)
 		ops.GET("/account-availability", h.Admin.Ops.GetAccountAvailability)
 		ops.GET("/realtime-traffic", h.Admin.Ops.GetRealtimeTrafficSummary)
+		ops.GET("/scheduler-metrics", h.Admin.Ops.GetSchedulerMetrics)
 
 		// Alerts (rules + events)
 		ops.GET("/alert-rules", h.Admin.Ops.ListAlertRules)
@@ -207,7 +227,8 @@ func registerUserManagementRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		users.GET("", h.Admin.User.List)
 		users.GET("/:id", h.Admin.User.GetByID)
 		users.POST("", h.Admin.User.Create)
-		users.PUT("/:id", h.Admin.User.Update)
+		// 修改密码/邮箱/角色可直接接管管理员账号，仅限 JWT 登录与全局 Admin API Key
+		users.PUT("/:id", middleware.DenyAdminAPITokenFields("password", "email", "role"), h.Admin.User.Update)
 		users.DELETE("/:id", h.Admin.User.Delete)
 		users.POST("/:id/balance", h.Admin.User.UpdateBalance)
 		users.GET("/:id/api-keys", h.Admin.User.GetUserAPIKeys)
@@ -261,7 +282,8 @@ func registerAccountRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		accounts.POST("/:id/schedulable", h.Admin.Account.SetSchedulable)
 		accounts.GET("/:id/models", h.Admin.Account.GetAvailableModels)
 		accounts.POST("/batch", h.Admin.Account.BatchCreate)
-		accounts.GET("/data", h.Admin.Account.ExportData)
+		// 导出内容包含上游账号凭证
+		accounts.GET("/data", middleware.DenyAdminAPIToken(), h.Admin.Account.ExportData)
 		accounts.POST("/data", h.Admin.Account.ImportData)
 		accounts.POST("/batch-update-credentials", h.Admin.Account.BatchUpdateCredentials)
 		accounts.POST("/batch-refresh-tier", h.Admin.Account.BatchRefreshTier)
@@ -325,6 +347,20 @@ func registerGeminiOAuthRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 	}
 }
 
+func registerProxyPoolRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	pools := admin.Group("/proxy-pools")
+	{
+		pools.GET("", h.Admin.ProxyPool.List)
+		pools.POST("", h.Admin.ProxyPool.Create)
+		pools.POST("/unbind-accounts", h.Admin.ProxyPool.UnbindAccounts)
+		pools.GET("/:id", h.Admin.ProxyPool.GetByID)
+		pools.PUT("/:id", h.Admin.ProxyPool.Update)
+		pools.DELETE("/:id", h.Admin.ProxyPool.Delete)
+		pools.POST("/:id/check", h.Admin.ProxyPool.Check)
+		pools.POST("/:id/accounts", h.Admin.ProxyPool.BindAccounts)
+	}
+}
+
 func registerAntigravityOAuthRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 	antigravity := admin.Group("/antigravity")
 	{
@@ -361,6 +397,19 @@ func registerOrderRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		orders.GET("/:id", h.Admin.Order.GetByID)
 		orders.POST("/:id/mark-paid", h.Admin.Order.MarkPaid)
 		orders.POST("/:id/cancel", h.Admin.Order.Cancel)
+		orders.POST("/:id/refund", h.Admin.Order.Refund)
+	}
+}
+
+func registerOrganizationRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	organizations := admin.Group("/organizations")
+	{
+		organizations.GET("", h.Admin.Organization.List)
+		organizations.GET("/:id", h.Admin.Organization.GetByID)
+		organizations.GET("/:id/members", h.Admin.Organization.ListMembers)
+		organizations.GET("/:id/usage", h.Admin.Organization.Usage)
+		organizations.POST("/:id/balance", h.Admin.Organization.UpdateBalance)
+		organizations.PUT("/:id/status", h.Admin.Organization.UpdateStatus)
 	}
 }
 
@@ -398,10 +447,22 @@ func registerSettingsRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		adminSettings.PUT("", h.Admin.Setting.UpdateSettings)
 		adminSettings.POST("/test-smtp", h.Admin.Setting.TestSMTPConnection)
 		adminSettings.POST("/send-test-email", h.Admin.Setting.SendTestEmail)
-		// Admin API Key 管理
-		adminSettings.GET("/admin-api-key", h.Admin.Setting.GetAdminAPIKey)
-		adminSettings.POST("/admin-api-key/regenerate", h.Admin.Setting.RegenerateAdminAPIKey)
-		adminSettings.DELETE("/admin-api-key", h.Admin.Setting.DeleteAdminAPIKey)
+		// Admin API Key / 具名 Admin API Token 管理（仅限 JWT 登录或全局 Key，防止 Token 自我提权）
+		credentials := adminSettings.Group("", middleware.DenyAdminAPIToken())
+		{
+			credentials.GET("/admin-api-key", h.Admin.Setting.GetAdminAPIKey)
+			credentials.POST("/admin-api-key/regenerate", h.Admin.Setting.RegenerateAdminAPIKey)
+			credentials.DELETE("/admin-api-key", h.Admin.Setting.DeleteAdminAPIKey)
+			credentials.GET("/admin-tokens/scopes", h.Admin.AdminToken.Scopes)
+			credentials.GET("/admin-tokens", h.Admin.AdminToken.List)
+			credentials.POST("/admin-tokens", h.Admin.AdminToken.Create)
+			credentials.PUT("/admin-tokens/:id", h.Admin.AdminToken.Update)
+			credentials.DELETE("/admin-tokens/:id", h.Admin.AdminToken.Delete)
+			// 上游账号凭证加密密钥轮换
+			credentials.GET("/credential-encryption", h.Admin.CredentialEncryption.GetStatus)
+			credentials.POST("/credential-encryption/rotate", h.Admin.CredentialEncryption.Rotate)
+			credentials.POST("/credential-encryption/reencrypt", h.Admin.CredentialEncryption.Reencrypt)
+		}
 		// 流超时处理配置
 		adminSettings.GET("/stream-timeout", h.Admin.Setting.GetStreamTimeoutSettings)
 		adminSettings.PUT("/stream-timeout", h.Admin.Setting.UpdateStreamTimeoutSettings)
@@ -480,6 +541,10 @@ func registerUsageRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
 		usage.GET("/cleanup-tasks", h.Admin.Usage.ListCleanupTasks)
 		usage.POST("/cleanup-tasks", h.Admin.Usage.CreateCleanupTask)
 		usage.POST("/cleanup-tasks/:id/cancel", h.Admin.Usage.CancelCleanupTask)
+		usage.GET("/export", h.Admin.Usage.Export)
+		usage.POST("/exports", h.Admin.Usage.CreateExportJob)
+		usage.GET("/exports", h.Admin.Usage.ListExportJobs)
+		usage.GET("/exports/:id", h.Admin.Usage.GetExportJob)
 	}
 }
 
@@ -517,3 +582,22 @@ func registerErrorPassthroughRoutes(admin *gin.RouterGroup, h *handler.Handlers)
 		rules.DELETE("/:id", h.Admin.ErrorPassthrough.Delete)
 	}
 }
+
+func registerAuditLogRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	auditLogs := admin.Group("/audit-logs")
+	{
+		auditLogs.GET("", h.Admin.AuditLog.List)
+		auditLogs.GET("/export", h.Admin.AuditLog.Export)
+	}
+}
+
+func registerStatementRoutes(admin *gin.RouterGroup, h *handler.Handlers) {
+	statements := admin.Group("/statements")
+	{
+		statements.GET("", h.Admin.Statement.List)
+		statements.POST("/generate", h.Admin.Statement.Generate)
+		statements.GET("/:id", h.Admin.Statement.GetByID)
+		statements.GET("/:id/html", h.Admin.Statement.HTML)
+		statements.POST("/:id/send-email", h.Admin.Statement.SendEmail)
+	}
+}
@@ -61,6 +61,9 @@ func RegisterAuthRoutes(
 		}), h.Auth.ResetPassword)
 		auth.GET("/oauth/linuxdo/start", h.Auth.LinuxDoOAuthStart)
 		auth.GET("/oauth/linuxdo/callback", h.Auth.LinuxDoOAuthCallback)
+		// 通用 OIDC / GitHub / Google SSO
+		auth.GET("/sso/:provider/start", h.SSO.Start)
+		auth.GET("/sso/:provider/callback", h.SSO.Callback)
 	}
 
 	// 公开设置（无需认证）
@@ -72,7 +75,22 @@ func RegisterAuthRoutes(
 	// 支付回调（无需认证）
 	payment := v1.Group("/payment")
 	{
-		payment.POST("/xunhupay/notify", h.Payment.XunhuPayNotify)
+		payment.POST("/:provider/notify", h.Payment.Notify)
+	}
+
+	// 导出文件下载（无需认证，凭签名链接访问）
+	usageExports := v1.Group("/usage-exports")
+	{
+		usageExports.GET("/:id/download", h.Usage.DownloadExport)
+	}
+
+	// 通知邮件退订（无需认证，凭邮件中的退订令牌）
+	notifications := v1.Group("/notifications")
+	{
+		notifications.GET("/unsubscribe", h.Notification.UnsubscribePage)
+		notifications.POST("/unsubscribe", rateLimiter.LimitWithOptions("notification-unsubscribe", 20, time.Minute, middleware.RateLimitOptions{
+			FailureMode: middleware.RateLimitFailClose,
+		}), h.Notification.Unsubscribe)
 	}
 
 	// 需要认证的当前用户信息
@@ -3,6 +3,9 @@ package routes
 import (
 	"net/http"
 
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/handler"
+
 	"github.com/gin-gonic/gin"
 )
 
@@ -30,3 +33,11 @@ func RegisterCommonRoutes(r *gin.Engine) {
 		})
 	})
 }
+
+// RegisterMetricsRoutes 注册 Prometheus 指标端点（metrics.enabled=true 时生效，需 Bearer token）
+func RegisterMetricsRoutes(r *gin.Engine, h *handler.Handlers, cfg *config.Config) {
+	if !cfg.Metrics.Enabled || !h.Metrics.Enabled() {
+		return
+	}
+	r.GET(cfg.Metrics.Path, h.Metrics.Scrape)
+}
@@ -2,6 +2,7 @@ package routes
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler"
@@ -17,6 +18,7 @@ func RegisterGatewayRoutes(
 	h *handler.Handlers,
 	apiKeyAuth middleware.APIKeyAuthMiddleware,
 	apiKeyService *service.APIKeyService,
+	apiKeyThrottle *service.APIKeyThrottleService,
 	subscriptionService *service.SubscriptionService,
 	opsService *service.OpsService,
 	settingService *service.SettingService,
@@ -30,21 +32,28 @@ func RegisterGatewayRoutes(
 	soraBodyLimit := middleware.RequestBodyLimit(soraMaxBodySize)
 	clientRequestID := middleware.ClientRequestID()
 	opsErrorLogger := handler.OpsErrorLoggerMiddleware(opsService)
+	gatewayMetrics := h.Metrics.GatewayMiddleware()
 
 	// 未分组 Key 拦截中间件（按协议格式区分错误响应）
 	requireGroupAnthropic := middleware.RequireGroupAssignment(settingService, middleware.AnthropicErrorWriter)
 	requireGroupGoogle := middleware.RequireGroupAssignment(settingService, middleware.GoogleErrorWriter)
 
+	// API Key 级 RPM/TPM/并发限制（按端点协议输出 429 错误体）
+	// 仅挂在生成类路由上：模型列表、用量查询、count_tokens、批处理与文件管理不占用配额
+	throttleV1 := middleware.APIKeyThrottle(apiKeyThrottle, middleware.V1ErrorWriter)
+	throttleGoogle := throttleGeminiGenerate(middleware.APIKeyThrottle(apiKeyThrottle, middleware.GoogleErrorWriter))
+
 	// API网关（Claude API兼容）
 	gateway := r.Group("/v1")
 	gateway.Use(bodyLimit)
 	gateway.Use(clientRequestID)
 	gateway.Use(opsErrorLogger)
+	gateway.Use(gatewayMetrics)
 	gateway.Use(gin.HandlerFunc(apiKeyAuth))
 	gateway.Use(requireGroupAnthropic)
 	{
 		// /v1/messages: auto-route based on group platform
-		gateway.POST("/messages", func(c *gin.Context) {
+		gateway.POST("/messages", throttleV1, func(c *gin.Context) {
 			if getGroupPlatform(c) == service.PlatformOpenAI {
 				h.OpenAIGateway.Messages(c)
 				return
@@ -65,41 +74,74 @@ func RegisterGatewayRoutes(
 			}
 			h.Gateway.CountTokens(c)
 		})
+		// /v1/messages/batches: Anthropic Message Batches, OpenAI groups get 404
+		batches := gateway.Group("/messages/batches", requireAnthropicMessagesPlatform)
+		{
+			batches.POST("", h.MessageBatch.Create)
+			batches.GET("", h.MessageBatch.List)
+			batches.GET("/:id", h.MessageBatch.Get)
+			batches.POST("/:id/cancel", h.MessageBatch.Cancel)
+			batches.GET("/:id/results", h.MessageBatch.Results)
+		}
+		// /v1/files, /v1/batches: OpenAI Files / Batch API, other platforms get 404
+		files := gateway.Group("/files", requireOpenAIPlatform)
+		{
+			files.GET("", h.OpenAIBatch.ListFiles)
+			files.GET("/:id", h.OpenAIBatch.GetFile)
+			files.GET("/:id/content", h.OpenAIBatch.FileContent)
+			files.DELETE("/:id", h.OpenAIBatch.DeleteFile)
+		}
+		openaiBatches := gateway.Group("/batches", requireOpenAIPlatform)
+		{
+			openaiBatches.POST("", h.OpenAIBatch.CreateBatch)
+			openaiBatches.GET("", h.OpenAIBatch.ListBatches)
+			openaiBatches.GET("/:id", h.OpenAIBatch.GetBatch)
+			openaiBatches.POST("/:id/cancel", h.OpenAIBatch.CancelBatch)
+		}
 		gateway.GET("/models", h.Gateway.Models)
 		gateway.GET("/usage", h.Gateway.Usage)
 		// OpenAI Responses API
-		gateway.POST("/responses", h.OpenAIGateway.Responses)
-		gateway.POST("/responses/*subpath", h.OpenAIGateway.Responses)
-		gateway.GET("/responses", h.OpenAIGateway.ResponsesWebSocket)
-		// 明确阻止旧协议入口：OpenAI 仅支持 Responses API，避免客户端误解为会自动路由到其它平台。
-		gateway.POST("/chat/completions", func(c *gin.Context) {
-			c.JSON(http.StatusBadRequest, gin.H{
-				"error": gin.H{
-					"type":    "invalid_request_error",
-					"message": "Unsupported legacy protocol: /v1/chat/completions is not supported. Please use /v1/responses.",
-				},
-			})
+		gateway.POST("/responses", throttleV1, h.OpenAIGateway.Responses)
+		gateway.POST("/responses/*subpath", throttleV1, h.OpenAIGateway.Responses)
+		gateway.GET("/responses", throttleV1, h.OpenAIGateway.ResponsesWebSocket)
+		// OpenAI Chat Completions API: OpenAI groups go through the Responses
+		// API, Sora groups use their native handler, other platforms are
+		// converted to Anthropic Messages.
+		gateway.POST("/chat/completions", throttleV1, func(c *gin.Context) {
+			switch getGroupPlatform(c) {
+			case service.PlatformOpenAI:
+				h.OpenAIGateway.ChatCompletions(c)
+			case service.PlatformSora:
+				h.SoraGateway.ChatCompletions(c)
+			default:
+				h.Gateway.ChatCompletions(c)
+			}
 		})
 	}
 
+	// 文件上传不受 gateway.max_body_size 限制，按 openai_batch.max_file_size_mb 另设上限（含 multipart 开销）
+	fileUploadLimit := max(cfg.Gateway.MaxBodySize, int64(cfg.OpenAIBatch.MaxFileSizeMB)<<20+openAIFileUploadOverhead)
+	r.POST("/v1/files", middleware.RequestBodyLimit(fileUploadLimit), clientRequestID, opsErrorLogger, gatewayMetrics, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, requireOpenAIPlatform, h.OpenAIBatch.UploadFile)
+
 	// Gemini 原生 API 兼容层（Gemini SDK/CLI 直连）
 	gemini := r.Group("/v1beta")
 	gemini.Use(bodyLimit)
 	gemini.Use(clientRequestID)
 	gemini.Use(opsErrorLogger)
+	gemini.Use(gatewayMetrics)
 	gemini.Use(middleware.APIKeyAuthWithSubscriptionGoogle(apiKeyService, subscriptionService, cfg))
 	gemini.Use(requireGroupGoogle)
 	{
 		gemini.GET("/models", h.Gateway.GeminiV1BetaListModels)
 		gemini.GET("/models/:model", h.Gateway.GeminiV1BetaGetModel)
 		// Gin treats ":" as a param marker, but Gemini uses "{model}:{action}" in the same segment.
-		gemini.POST("/models/*modelAction", h.Gateway.GeminiV1BetaModels)
+		gemini.POST("/models/*modelAction", throttleGoogle, h.Gateway.GeminiV1BetaModels)
 	}
 
 	// OpenAI Responses API（不带v1前缀的别名）
-	r.POST("/responses", bodyLimit, clientRequestID, opsErrorLogger, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, h.OpenAIGateway.Responses)
-	r.POST("/responses/*subpath", bodyLimit, clientRequestID, opsErrorLogger, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, h.OpenAIGateway.Responses)
-	r.GET("/responses", bodyLimit, clientRequestID, opsErrorLogger, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, h.OpenAIGateway.ResponsesWebSocket)
+	r.POST("/responses", bodyLimit, clientRequestID, opsErrorLogger, gatewayMetrics, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, throttleV1, h.OpenAIGateway.Responses)
+	r.POST("/responses/*subpath", bodyLimit, clientRequestID, opsErrorLogger, gatewayMetrics, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, throttleV1, h.OpenAIGateway.Responses)
+	r.GET("/responses", bodyLimit, clientRequestID, opsErrorLogger, gatewayMetrics, gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, throttleV1, h.OpenAIGateway.ResponsesWebSocket)
 
 	// Antigravity 模型列表
 	r.GET("/antigravity/models", gin.HandlerFunc(apiKeyAuth), requireGroupAnthropic, h.Gateway.AntigravityModels)
@@ -109,11 +151,12 @@ func RegisterGatewayRoutes(
 	antigravityV1.Use(bodyLimit)
 	antigravityV1.Use(clientRequestID)
 	antigravityV1.Use(opsErrorLogger)
+	antigravityV1.Use(gatewayMetrics)
 	antigravityV1.Use(middleware.ForcePlatform(service.PlatformAntigravity))
 	antigravityV1.Use(gin.HandlerFunc(apiKeyAuth))
 	antigravityV1.Use(requireGroupAnthropic)
 	{
-		antigravityV1.POST("/messages", h.Gateway.Messages)
+		antigravityV1.POST("/messages", throttleV1, h.Gateway.Messages)
 		antigravityV1.POST("/messages/count_tokens", h.Gateway.CountTokens)
 		antigravityV1.GET("/models", h.Gateway.AntigravityModels)
 		antigravityV1.GET("/usage", h.Gateway.Usage)
@@ -123,13 +166,14 @@ func RegisterGatewayRoutes(
 	antigravityV1Beta.Use(bodyLimit)
 	antigravityV1Beta.Use(clientRequestID)
 	antigravityV1Beta.Use(opsErrorLogger)
+	antigravityV1Beta.Use(gatewayMetrics)
 	antigravityV1Beta.Use(middleware.ForcePlatform(service.PlatformAntigravity))
 	antigravityV1Beta.Use(middleware.APIKeyAuthWithSubscriptionGoogle(apiKeyService, subscriptionService, cfg))
 	antigravityV1Beta.Use(requireGroupGoogle)
 	{
 		antigravityV1Beta.GET("/models", h.Gateway.GeminiV1BetaListModels)
 		antigravityV1Beta.GET("/models/:model", h.Gateway.GeminiV1BetaGetModel)
-		antigravityV1Beta.POST("/models/*modelAction", h.Gateway.GeminiV1BetaModels)
+		antigravityV1Beta.POST("/models/*modelAction", throttleGoogle, h.Gateway.GeminiV1BetaModels)
 	}
 
 	// Sora 专用路由（强制使用 sora 平台）
@@ -137,11 +181,12 @@ func RegisterGatewayRoutes(
 	soraV1.Use(soraBodyLimit)
 	soraV1.Use(clientRequestID)
 	soraV1.Use(opsErrorLogger)
+	soraV1.Use(gatewayMetrics)
 	soraV1.Use(middleware.ForcePlatform(service.PlatformSora))
 	soraV1.Use(gin.HandlerFunc(apiKeyAuth))
 	soraV1.Use(requireGroupAnthropic)
 	{
-		soraV1.POST("/chat/completions", h.SoraGateway.ChatCompletions)
+		soraV1.POST("/chat/completions", throttleV1, h.SoraGateway.ChatCompletions)
 		soraV1.GET("/models", h.Gateway.Models)
 	}
 
@@ -163,3 +208,47 @@ func getGroupPlatform(c *gin.Context) string {
 	}
 	return apiKey.Group.Platform
 }
+
+// throttleGeminiGenerate 仅对 Gemini 生成类动作限流；countTokens 不产生生成用量，直接放行
+func throttleGeminiGenerate(throttle gin.HandlerFunc) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		action := c.Param("modelAction")
+		if strings.HasSuffix(action, ":countTokens") || strings.HasSuffix(action, "/countTokens") {
+			c.Next()
+			return
+		}
+		throttle(c)
+	}
+}
+
+// openAIFileUploadOverhead multipart 表单字段与边界的额外字节
+const openAIFileUploadOverhead = 1 << 20
+
+// requireOpenAIPlatform 拦截非 OpenAI 分组访问仅 OpenAI 调度支持的端点（Files / Batch）
+func requireOpenAIPlatform(c *gin.Context) {
+	if getGroupPlatform(c) != service.PlatformOpenAI {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"error": gin.H{
+				"type":    "invalid_request_error",
+				"message": "Files and batches are only supported for OpenAI groups",
+			},
+		})
+		return
+	}
+	c.Next()
+}
+
+// requireAnthropicMessagesPlatform 拦截 OpenAI 分组访问仅 Claude Messages 调度支持的端点
+func requireAnthropicMessagesPlatform(c *gin.Context) {
+	if getGroupPlatform(c) == service.PlatformOpenAI {
+		c.AbortWithStatusJSON(http.StatusNotFound, gin.H{
+			"type": "error",
+			"error": gin.H{
+				"type":    "not_found_error",
+				"message": "Message batches are not supported for this platform",
+			},
+		})
+		return
+	}
+	c.Next()
+}
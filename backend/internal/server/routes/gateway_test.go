@@ -1,14 +1,18 @@
 package routes
 
 import (
+	"context"
+	"io"
 	"net/http"
 	"net/http/httptest"
 	"strings"
 	"testing"
+	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/handler"
 	servermiddleware "github.com/Wei-Shaw/sub2api/internal/server/middleware"
+	"github.com/Wei-Shaw/sub2api/internal/service"
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/require"
 )
@@ -31,6 +35,7 @@ func newGatewayRoutesTestRouter() *gin.Engine {
 		nil,
 		nil,
 		nil,
+		nil,
 		&config.Config{},
 	)
 
@@ -49,3 +54,116 @@ func TestGatewayRoutesOpenAIResponsesCompactPathIsRegistered(t *testing.T) {
 		require.NotEqual(t, http.StatusNotFound, w.Code, "path=%s should hit OpenAI responses handler", path)
 	}
 }
+
+func TestGatewayRoutesChatCompletionsIsRegistered(t *testing.T) {
+	router := newGatewayRoutesTestRouter()
+
+	req := httptest.NewRequest(http.MethodPost, "/v1/chat/completions", strings.NewReader(`{"model":"claude-sonnet-4-5"}`))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+
+	router.ServeHTTP(w, req)
+	require.NotEqual(t, http.StatusNotFound, w.Code)
+	require.NotContains(t, w.Body.String(), "Unsupported legacy protocol")
+}
+
+type countingRPMCache struct {
+	service.RPMCache
+	calls int
+}
+
+func (c *countingRPMCache) IncrementAPIKeyRPM(context.Context, int64) (int, time.Duration, error) {
+	c.calls++
+	return 1, time.Minute, nil
+}
+
+func TestGatewayRoutesThrottleOnlyGenerationRoutes(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	tests := []struct {
+		method    string
+		path      string
+		platform  string
+		throttled bool
+	}{
+		{http.MethodPost, "/v1/messages", service.PlatformAnthropic, true},
+		{http.MethodPost, "/v1/chat/completions", service.PlatformAnthropic, true},
+		{http.MethodPost, "/v1/responses", service.PlatformOpenAI, true},
+		{http.MethodPost, "/responses", service.PlatformOpenAI, true},
+		{http.MethodPost, "/antigravity/v1/messages", service.PlatformAntigravity, true},
+		{http.MethodPost, "/sora/v1/chat/completions", service.PlatformSora, true},
+		{http.MethodPost, "/v1/messages/count_tokens", service.PlatformAnthropic, false},
+		{http.MethodGet, "/v1/models", service.PlatformAnthropic, false},
+		{http.MethodGet, "/v1/usage", service.PlatformAnthropic, false},
+		{http.MethodPost, "/v1/messages/batches", service.PlatformAnthropic, false},
+		{http.MethodGet, "/v1/files", service.PlatformOpenAI, false},
+		{http.MethodPost, "/v1/files", service.PlatformOpenAI, false},
+		{http.MethodPost, "/v1/batches", service.PlatformOpenAI, false},
+		{http.MethodPost, "/antigravity/v1/messages/count_tokens", service.PlatformAntigravity, false},
+		{http.MethodGet, "/antigravity/v1/models", service.PlatformAntigravity, false},
+		{http.MethodGet, "/sora/v1/models", service.PlatformSora, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.method+" "+tt.path, func(t *testing.T) {
+			groupID := int64(1)
+			apiKey := &service.APIKey{
+				ID:       1,
+				RPMLimit: 10,
+				GroupID:  &groupID,
+				Group:    &service.Group{ID: groupID, Platform: tt.platform},
+			}
+			rpm := &countingRPMCache{}
+			router := gin.New()
+			// 处理器依赖均为空，这里只关心限流中间件是否执行
+			router.Use(gin.RecoveryWithWriter(io.Discard))
+			RegisterGatewayRoutes(
+				router,
+				&handler.Handlers{
+					Gateway:       &handler.GatewayHandler{},
+					OpenAIGateway: &handler.OpenAIGatewayHandler{},
+					SoraGateway:   &handler.SoraGatewayHandler{},
+				},
+				servermiddleware.APIKeyAuthMiddleware(func(c *gin.Context) {
+					c.Set(string(servermiddleware.ContextKeyAPIKey), apiKey)
+					c.Next()
+				}),
+				nil,
+				service.NewAPIKeyThrottleService(nil, rpm),
+				nil,
+				nil,
+				nil,
+				&config.Config{},
+			)
+
+			router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(tt.method, tt.path, strings.NewReader(`{}`)))
+			if tt.throttled {
+				require.Equal(t, 1, rpm.calls)
+			} else {
+				require.Zero(t, rpm.calls)
+			}
+		})
+	}
+}
+
+func TestThrottleGeminiGenerateSkipsCountTokens(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+	calls := 0
+	router := gin.New()
+	router.POST("/v1beta/models/*modelAction", throttleGeminiGenerate(func(c *gin.Context) {
+		calls++
+		c.Next()
+	}), func(c *gin.Context) { c.Status(http.StatusOK) })
+
+	for path, want := range map[string]int{
+		"/v1beta/models/gemini-2.5-pro:generateContent":       1,
+		"/v1beta/models/gemini-2.5-pro:streamGenerateContent": 1,
+		"/v1beta/models/gemini-2.5-pro:countTokens":           0,
+		"/v1beta/models/gemini-2.5-pro/countTokens":           0,
+	} {
+		calls = 0
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, httptest.NewRequest(http.MethodPost, path, nil))
+		require.Equal(t, http.StatusOK, w.Code, path)
+		require.Equal(t, want, calls, path)
+	}
+}
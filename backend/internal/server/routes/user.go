@@ -33,6 +33,18 @@ func RegisterUserRoutes(
 				totp.POST("/enable", h.Totp.Enable)
 				totp.POST("/disable", h.Totp.Disable)
 			}
+
+			// 第三方 SSO 身份绑定
+			sso := user.Group("/sso")
+			{
+				sso.GET("/identities", h.SSO.ListIdentities)
+				sso.DELETE("/identities/:id", h.SSO.Unlink)
+				sso.POST("/:provider/link", h.SSO.StartLink)
+			}
+
+			// 通知邮件设置（余额不足、额度将尽、订阅到期）
+			user.GET("/notifications", h.Notification.GetSettings)
+			user.PUT("/notifications", h.Notification.UpdateSettings)
 		}
 
 		// API Key管理
@@ -45,6 +57,45 @@ func RegisterUserRoutes(
 			keys.DELETE("/:id", h.APIKey.Delete)
 		}
 
+		// 组织（团队）
+		organizations := authenticated.Group("/organizations")
+		{
+			organizations.GET("", h.Organization.List)
+			organizations.POST("", h.Organization.Create)
+			organizations.GET("/:id", h.Organization.GetByID)
+			organizations.PUT("/:id", h.Organization.Update)
+			organizations.DELETE("/:id", h.Organization.Delete)
+			organizations.GET("/:id/members", h.Organization.ListMembers)
+			organizations.POST("/:id/members", h.Organization.AddMember)
+			organizations.PUT("/:id/members/:user_id", h.Organization.UpdateMember)
+			organizations.DELETE("/:id/members/:user_id", h.Organization.RemoveMember)
+			organizations.GET("/:id/keys", h.Organization.ListAPIKeys)
+			organizations.GET("/:id/usage", h.Organization.Usage)
+		}
+
+		// 月度账单
+		statements := authenticated.Group("/statements")
+		{
+			statements.GET("", h.Statement.List)
+			statements.GET("/:id", h.Statement.GetByID)
+			statements.GET("/:id/html", h.Statement.HTML)
+		}
+
+		// 出站 Webhook（事件回调）
+		webhooks := authenticated.Group("/webhooks")
+		{
+			webhooks.GET("", h.Webhook.List)
+			webhooks.POST("", h.Webhook.Create)
+			webhooks.GET("/events", h.Webhook.Events)
+			webhooks.GET("/:id", h.Webhook.GetByID)
+			webhooks.PUT("/:id", h.Webhook.Update)
+			webhooks.DELETE("/:id", h.Webhook.Delete)
+			webhooks.POST("/:id/rotate-secret", h.Webhook.RotateSecret)
+			webhooks.POST("/:id/test", h.Webhook.SendTest)
+			webhooks.GET("/:id/deliveries", h.Webhook.ListDeliveries)
+			webhooks.POST("/:id/deliveries/:delivery_id/redeliver", h.Webhook.Redeliver)
+		}
+
 		// 用户可用分组（非管理员接口）
 		groups := authenticated.Group("/groups")
 		{
@@ -58,6 +109,11 @@ func RegisterUserRoutes(
 			usage.GET("", h.Usage.List)
 			usage.GET("/:id", h.Usage.GetByID)
 			usage.GET("/stats", h.Usage.Stats)
+			// 导出：小范围流式导出，大范围创建异步任务
+			usage.GET("/export", h.Usage.Export)
+			usage.POST("/exports", h.Usage.CreateExportJob)
+			usage.GET("/exports", h.Usage.ListExportJobs)
+			usage.GET("/exports/:id", h.Usage.GetExportJob)
 			// User dashboard endpoints
 			usage.GET("/dashboard/stats", h.Usage.DashboardStats)
 			usage.GET("/dashboard/trend", h.Usage.DashboardTrend)
@@ -95,6 +151,8 @@ func RegisterUserRoutes(
 			purchase.GET("/orders", h.Purchase.ListOrders)
 			purchase.POST("/orders", h.Purchase.CreateOrder)
 			purchase.GET("/orders/:id", h.Purchase.GetOrder)
+			purchase.GET("/topup", h.Purchase.GetTopUpConfig)
+			purchase.POST("/topup", h.Purchase.CreateTopUpOrder)
 		}
 	}
 }
@@ -23,6 +23,7 @@ type Account struct {
 	Credentials map[string]any
 	Extra       map[string]any
 	ProxyID     *int64
+	ProxyPoolID *int64 // 代理池（优先于 ProxyID，由 HTTPUpstream 按账号选择成员）
 	Concurrency int
 	Priority    int
 	// RateMultiplier 账号计费倍率（>=0，允许 0 表示该账号计费为 0）。
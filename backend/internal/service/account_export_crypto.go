@@ -0,0 +1,148 @@
+package service
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"golang.org/x/crypto/scrypt"
+)
+
+// 账号导出文件的口令加密参数（scrypt 派生密钥 + AES-256-GCM）
+const (
+	AccountExportCipherAlgorithm = "aes-256-gcm"
+	AccountExportKDFScrypt       = "scrypt"
+
+	accountExportScryptN   = 1 << 15
+	accountExportScryptR   = 8
+	accountExportScryptP   = 1
+	accountExportSaltBytes = 16
+	// 导入时允许的最大 scrypt 成本，防止恶意文件消耗过多 CPU/内存；
+	// scrypt 需要 128·N·r 字节内存，上限为导出默认值（32 MiB）的 4 倍
+	accountExportScryptMaxN      = 1 << 20
+	accountExportScryptMaxMemory = 128 << 20
+
+	accountExportMinPassphraseLength = 8
+)
+
+var (
+	ErrAccountExportPassphraseRequired = infraerrors.BadRequest("ACCOUNT_EXPORT_PASSPHRASE_REQUIRED", "passphrase is required for encrypted account data")
+	ErrAccountExportPassphraseTooShort = infraerrors.BadRequest("ACCOUNT_EXPORT_PASSPHRASE_TOO_SHORT", "passphrase must be at least 8 characters")
+	ErrAccountExportPassphraseInvalid  = infraerrors.BadRequest("ACCOUNT_EXPORT_PASSPHRASE_INVALID", "wrong passphrase or corrupted account data")
+	ErrAccountExportEncryptionInvalid  = infraerrors.BadRequest("ACCOUNT_EXPORT_ENCRYPTION_INVALID", "unsupported account data encryption parameters")
+)
+
+// AccountExportEncryption 导出文件中的加密参数
+type AccountExportEncryption struct {
+	Algorithm string `json:"algorithm"`
+	KDF       string `json:"kdf"`
+	Salt      string `json:"salt"`
+	N         int    `json:"n"`
+	R         int    `json:"r"`
+	P         int    `json:"p"`
+}
+
+// AccountExportSealer 使用口令加密/解密导出文件中的账号凭证
+type AccountExportSealer struct {
+	aead   cipher.AEAD
+	params AccountExportEncryption
+}
+
+// NewAccountExportSealer 为导出生成随机盐并派生密钥
+func NewAccountExportSealer(passphrase string) (*AccountExportSealer, error) {
+	if len([]rune(strings.TrimSpace(passphrase))) < accountExportMinPassphraseLength {
+		return nil, ErrAccountExportPassphraseTooShort
+	}
+	salt := make([]byte, accountExportSaltBytes)
+	if _, err := io.ReadFull(rand.Reader, salt); err != nil {
+		return nil, fmt.Errorf("generate salt: %w", err)
+	}
+	return newAccountExportSealer(passphrase, AccountExportEncryption{
+		Algorithm: AccountExportCipherAlgorithm,
+		KDF:       AccountExportKDFScrypt,
+		Salt:      base64.StdEncoding.EncodeToString(salt),
+		N:         accountExportScryptN,
+		R:         accountExportScryptR,
+		P:         accountExportScryptP,
+	})
+}
+
+// OpenAccountExportSealer 按导出文件中的参数派生密钥用于导入
+func OpenAccountExportSealer(passphrase string, params *AccountExportEncryption) (*AccountExportSealer, error) {
+	if params == nil {
+		return nil, ErrAccountExportEncryptionInvalid
+	}
+	if passphrase == "" {
+		return nil, ErrAccountExportPassphraseRequired
+	}
+	if params.Algorithm != AccountExportCipherAlgorithm || params.KDF != AccountExportKDFScrypt ||
+		params.N <= 1 || params.N > accountExportScryptMaxN || params.N&(params.N-1) != 0 ||
+		params.R <= 0 || params.R > 32 || params.P <= 0 || params.P > 16 ||
+		128*int64(params.N)*int64(params.R) > accountExportScryptMaxMemory {
+		return nil, ErrAccountExportEncryptionInvalid
+	}
+	return newAccountExportSealer(passphrase, *params)
+}
+
+func newAccountExportSealer(passphrase string, params AccountExportEncryption) (*AccountExportSealer, error) {
+	salt, err := base64.StdEncoding.DecodeString(params.Salt)
+	if err != nil || len(salt) == 0 {
+		return nil, ErrAccountExportEncryptionInvalid
+	}
+	key, err := scrypt.Key([]byte(passphrase), salt, params.N, params.R, params.P, 32)
+	if err != nil {
+		return nil, ErrAccountExportEncryptionInvalid
+	}
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, err
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, err
+	}
+	return &AccountExportSealer{aead: aead, params: params}, nil
+}
+
+// Params 返回写入导出文件的加密参数
+func (s *AccountExportSealer) Params() *AccountExportEncryption {
+	params := s.params
+	return &params
+}
+
+// SealCredentials 加密单个账号的凭证，输出 base64(nonce + ciphertext + tag)
+func (s *AccountExportSealer) SealCredentials(credentials map[string]any) (string, error) {
+	plaintext, err := json.Marshal(credentials)
+	if err != nil {
+		return "", err
+	}
+	nonce := make([]byte, s.aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("generate nonce: %w", err)
+	}
+	return base64.StdEncoding.EncodeToString(s.aead.Seal(nonce, nonce, plaintext, nil)), nil
+}
+
+// OpenCredentials 解密单个账号的凭证
+func (s *AccountExportSealer) OpenCredentials(sealed string) (map[string]any, error) {
+	data, err := base64.StdEncoding.DecodeString(sealed)
+	if err != nil || len(data) < s.aead.NonceSize() {
+		return nil, ErrAccountExportPassphraseInvalid
+	}
+	nonce, ciphertext := data[:s.aead.NonceSize()], data[s.aead.NonceSize():]
+	plaintext, err := s.aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, ErrAccountExportPassphraseInvalid
+	}
+	credentials := map[string]any{}
+	if err := json.Unmarshal(plaintext, &credentials); err != nil {
+		return nil, ErrAccountExportPassphraseInvalid
+	}
+	return credentials, nil
+}
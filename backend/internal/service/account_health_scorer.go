@@ -0,0 +1,578 @@
+package service
+
+import (
+	"math"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+)
+
+const (
+	accountScheduleLayerLoadBalance = "load_balance"
+	accountScheduleLayerLegacyOrder = "legacy_order"
+	accountScheduleLayerFallback    = "fallback_wait"
+
+	defaultAccountHealthDecayHalfLife = 5 * time.Minute
+	accountHealthScoreEpsilon         = 1e-9
+)
+
+// accountRuntimeStats 平台无关的账号运行时统计：
+//   - 错误率 / TTFT：EWMA（alpha=0.2），由网关请求结果上报
+//   - 429/529 密度、token 刷新失败：按半衰期指数衰减的计数
+//
+// OpenAI 调度器与 Anthropic/Gemini/Antigravity 负载感知层共用同一份统计。
+type accountRuntimeStats struct {
+	accounts     sync.Map
+	accountCount atomic.Int64
+	halfLife     time.Duration
+}
+
+type accountRuntimeStat struct {
+	errorRateEWMABits atomic.Uint64
+	ttftEWMABits      atomic.Uint64
+
+	mu              sync.Mutex
+	overload        decayedCounter
+	refreshFailures decayedCounter
+}
+
+// decayedCounter 指数衰减计数，value 为 updatedAt 时刻的值
+type decayedCounter struct {
+	value     float64
+	updatedAt time.Time
+}
+
+func (c *decayedCounter) valueAt(now time.Time, halfLife time.Duration) float64 {
+	if c.value == 0 || c.updatedAt.IsZero() {
+		return 0
+	}
+	elapsed := now.Sub(c.updatedAt)
+	if elapsed <= 0 || halfLife <= 0 {
+		return c.value
+	}
+	return c.value * math.Exp2(-elapsed.Seconds()/halfLife.Seconds())
+}
+
+func (c *decayedCounter) add(now time.Time, halfLife time.Duration, delta float64) {
+	c.value = c.valueAt(now, halfLife) + delta
+	c.updatedAt = now
+}
+
+func (c *decayedCounter) reset() {
+	c.value = 0
+	c.updatedAt = time.Time{}
+}
+
+// accountRuntimeHealth 单账号运行时健康快照
+type accountRuntimeHealth struct {
+	ErrorRate       float64
+	TTFT            float64
+	HasTTFT         bool
+	OverloadDensity float64
+	RefreshFailures float64
+}
+
+func newAccountRuntimeStats() *accountRuntimeStats {
+	return newAccountRuntimeStatsWithHalfLife(defaultAccountHealthDecayHalfLife)
+}
+
+func newAccountRuntimeStatsWithHalfLife(halfLife time.Duration) *accountRuntimeStats {
+	if halfLife <= 0 {
+		halfLife = defaultAccountHealthDecayHalfLife
+	}
+	return &accountRuntimeStats{halfLife: halfLife}
+}
+
+func (s *accountRuntimeStats) loadOrCreate(accountID int64) *accountRuntimeStat {
+	if value, ok := s.accounts.Load(accountID); ok {
+		stat, _ := value.(*accountRuntimeStat)
+		if stat != nil {
+			return stat
+		}
+	}
+
+	stat := &accountRuntimeStat{}
+	stat.ttftEWMABits.Store(math.Float64bits(math.NaN()))
+	actual, loaded := s.accounts.LoadOrStore(accountID, stat)
+	if !loaded {
+		s.accountCount.Add(1)
+		return stat
+	}
+	existing, _ := actual.(*accountRuntimeStat)
+	if existing != nil {
+		return existing
+	}
+	return stat
+}
+
+func (s *accountRuntimeStats) load(accountID int64) *accountRuntimeStat {
+	if s == nil || accountID <= 0 {
+		return nil
+	}
+	value, ok := s.accounts.Load(accountID)
+	if !ok {
+		return nil
+	}
+	stat, _ := value.(*accountRuntimeStat)
+	return stat
+}
+
+func updateEWMAAtomic(target *atomic.Uint64, sample float64, alpha float64) {
+	for {
+		oldBits := target.Load()
+		oldValue := math.Float64frombits(oldBits)
+		newValue := alpha*sample + (1-alpha)*oldValue
+		if target.CompareAndSwap(oldBits, math.Float64bits(newValue)) {
+			return
+		}
+	}
+}
+
+func (s *accountRuntimeStats) report(accountID int64, success bool, firstTokenMs *int) {
+	if s == nil || accountID <= 0 {
+		return
+	}
+	const alpha = 0.2
+	stat := s.loadOrCreate(accountID)
+
+	errorSample := 1.0
+	if success {
+		errorSample = 0.0
+	}
+	updateEWMAAtomic(&stat.errorRateEWMABits, errorSample, alpha)
+
+	if firstTokenMs != nil && *firstTokenMs > 0 {
+		ttft := float64(*firstTokenMs)
+		ttftBits := math.Float64bits(ttft)
+		for {
+			oldBits := stat.ttftEWMABits.Load()
+			oldValue := math.Float64frombits(oldBits)
+			if math.IsNaN(oldValue) {
+				if stat.ttftEWMABits.CompareAndSwap(oldBits, ttftBits) {
+					break
+				}
+				continue
+			}
+			newValue := alpha*ttft + (1-alpha)*oldValue
+			if stat.ttftEWMABits.CompareAndSwap(oldBits, math.Float64bits(newValue)) {
+				break
+			}
+		}
+	}
+}
+
+// reportOverload 记录一次 429/529
+func (s *accountRuntimeStats) reportOverload(accountID int64, now time.Time) {
+	if s == nil || accountID <= 0 {
+		return
+	}
+	stat := s.loadOrCreate(accountID)
+	stat.mu.Lock()
+	stat.overload.add(now, s.halfLife, 1)
+	stat.mu.Unlock()
+}
+
+// reportTokenRefresh 记录 token 刷新结果；成功即清零失败计数
+func (s *accountRuntimeStats) reportTokenRefresh(accountID int64, success bool, now time.Time) {
+	if s == nil || accountID <= 0 {
+		return
+	}
+	if success {
+		// 成功且没有历史记录时不必创建条目
+		if stat := s.load(accountID); stat != nil {
+			stat.mu.Lock()
+			stat.refreshFailures.reset()
+			stat.mu.Unlock()
+		}
+		return
+	}
+	stat := s.loadOrCreate(accountID)
+	stat.mu.Lock()
+	stat.refreshFailures.add(now, s.halfLife, 1)
+	stat.mu.Unlock()
+}
+
+func (s *accountRuntimeStats) snapshot(accountID int64) (errorRate float64, ttft float64, hasTTFT bool) {
+	stat := s.load(accountID)
+	if stat == nil {
+		return 0, 0, false
+	}
+	errorRate = clamp01(math.Float64frombits(stat.errorRateEWMABits.Load()))
+	ttftValue := math.Float64frombits(stat.ttftEWMABits.Load())
+	if math.IsNaN(ttftValue) {
+		return errorRate, 0, false
+	}
+	return errorRate, ttftValue, true
+}
+
+func (s *accountRuntimeStats) health(accountID int64, now time.Time) accountRuntimeHealth {
+	stat := s.load(accountID)
+	if stat == nil {
+		return accountRuntimeHealth{}
+	}
+	errorRate, ttft, hasTTFT := s.snapshot(accountID)
+	stat.mu.Lock()
+	overload := stat.overload.valueAt(now, s.halfLife)
+	refreshFailures := stat.refreshFailures.valueAt(now, s.halfLife)
+	stat.mu.Unlock()
+	return accountRuntimeHealth{
+		ErrorRate:       errorRate,
+		TTFT:            ttft,
+		HasTTFT:         hasTTFT,
+		OverloadDensity: overload,
+		RefreshFailures: refreshFailures,
+	}
+}
+
+func (s *accountRuntimeStats) size() int {
+	if s == nil {
+		return 0
+	}
+	return int(s.accountCount.Load())
+}
+
+// AccountHealthScorer 平台无关的账号健康评分组件。
+// 汇总网关请求结果、429/529 与 token 刷新失败，为各网关的负载感知选择提供同一口径的评分，
+// 并记录调度决策指标供运维监控与 Prometheus 使用。
+type AccountHealthScorer struct {
+	enabled bool
+	weights config.GatewaySchedulingHealthScoreWeights
+	stats   *accountRuntimeStats
+	metrics accountHealthSchedulerMetrics
+	now     func() time.Time
+}
+
+// NewAccountHealthScorer 创建健康评分组件
+func NewAccountHealthScorer(cfg *config.Config) *AccountHealthScorer {
+	halfLife := defaultAccountHealthDecayHalfLife
+	scorer := &AccountHealthScorer{
+		weights: config.GatewaySchedulingHealthScoreWeights{
+			Load:         1.0,
+			Queue:        0.7,
+			ErrorRate:    0.8,
+			TTFT:         0.5,
+			Overload:     1.0,
+			TokenRefresh: 0.6,
+		},
+		now: time.Now,
+	}
+	if cfg != nil {
+		hc := cfg.Gateway.Scheduling.HealthScore
+		scorer.enabled = hc.Enabled
+		if hc.DecayHalfLifeSeconds > 0 {
+			halfLife = time.Duration(hc.DecayHalfLifeSeconds) * time.Second
+		}
+		w := hc.Weights
+		if w.Load+w.Queue+w.ErrorRate+w.TTFT+w.Overload+w.TokenRefresh > 0 {
+			scorer.weights = w
+		}
+	}
+	scorer.stats = newAccountRuntimeStatsWithHalfLife(halfLife)
+	return scorer
+}
+
+// Enabled 负载感知层是否启用健康评分排序
+func (h *AccountHealthScorer) Enabled() bool {
+	return h != nil && h.enabled
+}
+
+func (h *AccountHealthScorer) runtimeStats() *accountRuntimeStats {
+	if h == nil {
+		return nil
+	}
+	return h.stats
+}
+
+// ReportResult 上报一次网关转发结果（成功/失败与首 token 耗时）
+func (h *AccountHealthScorer) ReportResult(accountID int64, success bool, firstTokenMs *int) {
+	if h == nil {
+		return
+	}
+	h.stats.report(accountID, success, firstTokenMs)
+}
+
+// ReportOverload 上报上游 429/529，其他状态码忽略
+func (h *AccountHealthScorer) ReportOverload(accountID int64, statusCode int) {
+	if h == nil || (statusCode != 429 && statusCode != 529) {
+		return
+	}
+	h.stats.reportOverload(accountID, h.now())
+	h.metrics.overloadEventTotal.Add(1)
+}
+
+// ReportTokenRefresh 上报后台 token 刷新结果
+func (h *AccountHealthScorer) ReportTokenRefresh(accountID int64, success bool) {
+	if h == nil {
+		return
+	}
+	h.stats.reportTokenRefresh(accountID, success, h.now())
+	if !success {
+		h.metrics.tokenRefreshFailureTotal.Add(1)
+	}
+}
+
+// AccountHealthSnapshot 单账号健康快照。
+// HealthScore 只由与负载无关的运行时因子（错误率、429/529 密度、token 刷新失败）加权得出，范围 [0,1]。
+type AccountHealthSnapshot struct {
+	AccountID            int64    `json:"account_id"`
+	ErrorRate            float64  `json:"error_rate"`
+	TTFTMs               *float64 `json:"ttft_ms,omitempty"`
+	OverloadDensity      float64  `json:"overload_density"`
+	TokenRefreshFailures float64  `json:"token_refresh_failures"`
+	HealthScore          float64  `json:"health_score"`
+}
+
+// Snapshot 返回单账号健康快照
+func (h *AccountHealthScorer) Snapshot(accountID int64) AccountHealthSnapshot {
+	out := AccountHealthSnapshot{AccountID: accountID, HealthScore: 1}
+	if h == nil {
+		return out
+	}
+	rt := h.stats.health(accountID, h.now())
+	out.ErrorRate = rt.ErrorRate
+	if rt.HasTTFT {
+		ttft := rt.TTFT
+		out.TTFTMs = &ttft
+	}
+	out.OverloadDensity = rt.OverloadDensity
+	out.TokenRefreshFailures = rt.RefreshFailures
+
+	w := h.weights
+	sum := w.ErrorRate + w.Overload + w.TokenRefresh
+	if sum > 0 {
+		out.HealthScore = (w.ErrorRate*(1-rt.ErrorRate) +
+			w.Overload*decayedPenaltyFactor(rt.OverloadDensity) +
+			w.TokenRefresh*decayedPenaltyFactor(rt.RefreshFailures)) / sum
+	}
+	return out
+}
+
+// ListAccountHealth 返回有运行时统计的账号快照，按 HealthScore 升序（最差在前）；limit<=0 表示不限制
+func (h *AccountHealthScorer) ListAccountHealth(limit int) []AccountHealthSnapshot {
+	if h == nil {
+		return []AccountHealthSnapshot{}
+	}
+	out := make([]AccountHealthSnapshot, 0, h.stats.size())
+	h.stats.accounts.Range(func(key, _ any) bool {
+		if id, ok := key.(int64); ok {
+			out = append(out, h.Snapshot(id))
+		}
+		return true
+	})
+	sort.Slice(out, func(i, j int) bool {
+		if out[i].HealthScore != out[j].HealthScore {
+			return out[i].HealthScore < out[j].HealthScore
+		}
+		return out[i].AccountID < out[j].AccountID
+	})
+	if limit > 0 && len(out) > limit {
+		out = out[:limit]
+	}
+	return out
+}
+
+// runtimeHealthScore 返回与负载无关的运行时因子（429/529 密度、token 刷新失败）的加权健康得分：
+// 无异常事件时最高（两项权重之和），异常越多越低。它是加分项而非惩罚项，
+// OpenAI 调度器应直接加到自身评分上；评分关闭时返回 0，保持原有排序。
+func (h *AccountHealthScorer) runtimeHealthScore(accountID int64) float64 {
+	if !h.Enabled() {
+		return 0
+	}
+	rt := h.stats.health(accountID, h.now())
+	return h.weights.Overload*decayedPenaltyFactor(rt.OverloadDensity) +
+		h.weights.TokenRefresh*decayedPenaltyFactor(rt.RefreshFailures)
+}
+
+// decayedPenaltyFactor 将衰减计数映射到 (0,1]：0 次为 1，1 次为 0.5，3 次为 0.25
+func decayedPenaltyFactor(count float64) float64 {
+	if count <= 0 {
+		return 1
+	}
+	return 1 / (1 + count)
+}
+
+// scoreCandidates 计算同一优先级内候选账号的加权得分（归一化到 [0,1]）。
+// TTFT 按候选集合内的最小/最大值归一化；无样本的账号取中性值 0.5。
+func (h *AccountHealthScorer) scoreCandidates(items []accountWithLoad) []float64 {
+	scores := make([]float64, len(items))
+	if len(items) == 0 {
+		return scores
+	}
+	now := h.now()
+	healths := make([]accountRuntimeHealth, len(items))
+	maxWaiting := 1
+	minTTFT, maxTTFT := 0.0, 0.0
+	hasTTFTSample := false
+	for i, item := range items {
+		healths[i] = h.stats.health(item.account.ID, now)
+		if item.loadInfo != nil && item.loadInfo.WaitingCount > maxWaiting {
+			maxWaiting = item.loadInfo.WaitingCount
+		}
+		if rt := healths[i]; rt.HasTTFT && rt.TTFT > 0 {
+			if !hasTTFTSample {
+				minTTFT, maxTTFT = rt.TTFT, rt.TTFT
+				hasTTFTSample = true
+			} else {
+				minTTFT = math.Min(minTTFT, rt.TTFT)
+				maxTTFT = math.Max(maxTTFT, rt.TTFT)
+			}
+		}
+	}
+
+	w := h.weights
+	weightSum := w.Load + w.Queue + w.ErrorRate + w.TTFT + w.Overload + w.TokenRefresh
+	if weightSum <= 0 {
+		weightSum = 1
+	}
+	for i, item := range items {
+		var loadRate, waiting int
+		if item.loadInfo != nil {
+			loadRate, waiting = item.loadInfo.LoadRate, item.loadInfo.WaitingCount
+		}
+		rt := healths[i]
+		loadFactor := 1 - clamp01(float64(loadRate)/100.0)
+		queueFactor := 1 - clamp01(float64(waiting)/float64(maxWaiting))
+		errorFactor := 1 - clamp01(rt.ErrorRate)
+		ttftFactor := 0.5
+		if rt.HasTTFT && hasTTFTSample && maxTTFT > minTTFT {
+			ttftFactor = 1 - clamp01((rt.TTFT-minTTFT)/(maxTTFT-minTTFT))
+		}
+		scores[i] = (w.Load*loadFactor +
+			w.Queue*queueFactor +
+			w.ErrorRate*errorFactor +
+			w.TTFT*ttftFactor +
+			w.Overload*decayedPenaltyFactor(rt.OverloadDensity) +
+			w.TokenRefresh*decayedPenaltyFactor(rt.RefreshFailures)) / weightSum
+	}
+	return scores
+}
+
+// filterByBestScore 过滤出得分最高的账号集合（同分交给 LRU 打散），并返回最高分
+func (h *AccountHealthScorer) filterByBestScore(items []accountWithLoad) ([]accountWithLoad, float64) {
+	if len(items) == 0 {
+		return items, 0
+	}
+	scores := h.scoreCandidates(items)
+	best := scores[0]
+	for _, score := range scores[1:] {
+		if score > best {
+			best = score
+		}
+	}
+	result := make([]accountWithLoad, 0, len(items))
+	for i, item := range items {
+		if best-scores[i] <= accountHealthScoreEpsilon {
+			result = append(result, item)
+		}
+	}
+	return result, best
+}
+
+// AccountSchedulerMetricsSnapshot Anthropic/Gemini/Antigravity 负载感知调度决策指标
+type AccountSchedulerMetricsSnapshot struct {
+	HealthScoreEnabled bool `json:"health_score_enabled"`
+
+	SelectTotal      int64 `json:"select_total"`
+	SelectErrorTotal int64 `json:"select_error_total"`
+	// StickyOrRoutedTotal: 粘性会话、模型路由等前置层命中（总数减去其余各层）
+	StickyOrRoutedTotal    int64 `json:"sticky_or_routed_total"`
+	LoadBalanceSelectTotal int64 `json:"load_balance_select_total"`
+	LegacyOrderSelectTotal int64 `json:"legacy_order_select_total"`
+	FallbackWaitTotal      int64 `json:"fallback_wait_total"`
+
+	HealthScoredSelectTotal int64   `json:"health_scored_select_total"`
+	SelectedScoreAvg        float64 `json:"selected_score_avg"`
+	CandidateCountAvg       float64 `json:"candidate_count_avg"`
+
+	SchedulerLatencyMsTotal int64   `json:"scheduler_latency_ms_total"`
+	SchedulerLatencyMsAvg   float64 `json:"scheduler_latency_ms_avg"`
+
+	OverloadEventTotal       int64 `json:"overload_event_total"`
+	TokenRefreshFailureTotal int64 `json:"token_refresh_failure_total"`
+	RuntimeStatsAccountCount int   `json:"runtime_stats_account_count"`
+}
+
+type accountHealthSchedulerMetrics struct {
+	selectTotal              atomic.Int64
+	selectErrorTotal         atomic.Int64
+	loadBalanceSelectTotal   atomic.Int64
+	legacyOrderSelectTotal   atomic.Int64
+	fallbackWaitTotal        atomic.Int64
+	scoredSelectTotal        atomic.Int64
+	scoreMilliTotal          atomic.Int64
+	candidateTotal           atomic.Int64
+	latencyMsTotal           atomic.Int64
+	overloadEventTotal       atomic.Int64
+	tokenRefreshFailureTotal atomic.Int64
+}
+
+// recordSelect 记录一次选择的耗时与成败（所有层都会经过）
+func (h *AccountHealthScorer) recordSelect(latency time.Duration, err error) {
+	if h == nil {
+		return
+	}
+	h.metrics.selectTotal.Add(1)
+	h.metrics.latencyMsTotal.Add(latency.Milliseconds())
+	if err != nil {
+		h.metrics.selectErrorTotal.Add(1)
+	}
+}
+
+// recordLayer 记录命中的调度层；scored 表示经过健康评分排序，score 为选中账号得分
+func (h *AccountHealthScorer) recordLayer(layer string, candidateCount int, scored bool, score float64) {
+	if h == nil {
+		return
+	}
+	switch layer {
+	case accountScheduleLayerLoadBalance:
+		h.metrics.loadBalanceSelectTotal.Add(1)
+		h.metrics.candidateTotal.Add(int64(candidateCount))
+	case accountScheduleLayerLegacyOrder:
+		h.metrics.legacyOrderSelectTotal.Add(1)
+	case accountScheduleLayerFallback:
+		h.metrics.fallbackWaitTotal.Add(1)
+	}
+	if scored {
+		h.metrics.scoredSelectTotal.Add(1)
+		h.metrics.scoreMilliTotal.Add(int64(math.Round(score * 1000)))
+	}
+}
+
+// SnapshotMetrics 返回调度决策指标快照
+func (h *AccountHealthScorer) SnapshotMetrics() AccountSchedulerMetricsSnapshot {
+	if h == nil {
+		return AccountSchedulerMetricsSnapshot{}
+	}
+	m := &h.metrics
+	snapshot := AccountSchedulerMetricsSnapshot{
+		HealthScoreEnabled:       h.enabled,
+		SelectTotal:              m.selectTotal.Load(),
+		SelectErrorTotal:         m.selectErrorTotal.Load(),
+		LoadBalanceSelectTotal:   m.loadBalanceSelectTotal.Load(),
+		LegacyOrderSelectTotal:   m.legacyOrderSelectTotal.Load(),
+		FallbackWaitTotal:        m.fallbackWaitTotal.Load(),
+		HealthScoredSelectTotal:  m.scoredSelectTotal.Load(),
+		SchedulerLatencyMsTotal:  m.latencyMsTotal.Load(),
+		OverloadEventTotal:       m.overloadEventTotal.Load(),
+		TokenRefreshFailureTotal: m.tokenRefreshFailureTotal.Load(),
+		RuntimeStatsAccountCount: h.stats.size(),
+	}
+	snapshot.StickyOrRoutedTotal = snapshot.SelectTotal - snapshot.SelectErrorTotal -
+		snapshot.LoadBalanceSelectTotal - snapshot.LegacyOrderSelectTotal - snapshot.FallbackWaitTotal
+	if snapshot.StickyOrRoutedTotal < 0 {
+		snapshot.StickyOrRoutedTotal = 0
+	}
+	if snapshot.SelectTotal > 0 {
+		snapshot.SchedulerLatencyMsAvg = float64(snapshot.SchedulerLatencyMsTotal) / float64(snapshot.SelectTotal)
+	}
+	if snapshot.HealthScoredSelectTotal > 0 {
+		snapshot.SelectedScoreAvg = float64(m.scoreMilliTotal.Load()) / 1000 / float64(snapshot.HealthScoredSelectTotal)
+	}
+	if snapshot.LoadBalanceSelectTotal > 0 {
+		snapshot.CandidateCountAvg = float64(m.candidateTotal.Load()) / float64(snapshot.LoadBalanceSelectTotal)
+	}
+	return snapshot
+}
@@ -0,0 +1,163 @@
+//go:build unit
+
+package service
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestAccountHealthScorer(t *testing.T) (*AccountHealthScorer, *time.Time) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Gateway.Scheduling.HealthScore = config.GatewaySchedulingHealthScoreConfig{
+		Enabled:              true,
+		DecayHalfLifeSeconds: 60,
+		Weights: config.GatewaySchedulingHealthScoreWeights{
+			Load: 1.0, Queue: 0.7, ErrorRate: 0.8, TTFT: 0.5, Overload: 1.0, TokenRefresh: 0.6,
+		},
+	}
+	scorer := NewAccountHealthScorer(cfg)
+	now := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	scorer.now = func() time.Time { return now }
+	return scorer, &now
+}
+
+func healthCandidate(id int64, loadRate int) accountWithLoad {
+	return accountWithLoad{
+		account:  &Account{ID: id, Priority: 1},
+		loadInfo: &AccountLoadInfo{AccountID: id, LoadRate: loadRate},
+	}
+}
+
+func TestAccountHealthScorer_OverloadDecaysByHalfLife(t *testing.T) {
+	scorer, now := newTestAccountHealthScorer(t)
+
+	scorer.ReportOverload(1, 429)
+	scorer.ReportOverload(1, 529)
+	scorer.ReportOverload(1, 500) // 非 429/529 忽略
+	require.InDelta(t, 2.0, scorer.Snapshot(1).OverloadDensity, 1e-9)
+
+	*now = now.Add(time.Minute)
+	require.InDelta(t, 1.0, scorer.Snapshot(1).OverloadDensity, 1e-9)
+
+	*now = now.Add(time.Minute)
+	scorer.ReportOverload(1, 429)
+	require.InDelta(t, 1.5, scorer.Snapshot(1).OverloadDensity, 1e-9)
+	require.Equal(t, int64(3), scorer.SnapshotMetrics().OverloadEventTotal)
+}
+
+func TestAccountHealthScorer_TokenRefreshSuccessResets(t *testing.T) {
+	scorer, _ := newTestAccountHealthScorer(t)
+
+	scorer.ReportTokenRefresh(7, true)
+	require.Equal(t, 0, scorer.stats.size(), "a success without history creates no entry")
+
+	scorer.ReportTokenRefresh(7, false)
+	scorer.ReportTokenRefresh(7, false)
+	snap := scorer.Snapshot(7)
+	require.InDelta(t, 2.0, snap.TokenRefreshFailures, 1e-9)
+	require.Less(t, snap.HealthScore, 1.0)
+
+	scorer.ReportTokenRefresh(7, true)
+	snap = scorer.Snapshot(7)
+	require.Zero(t, snap.TokenRefreshFailures)
+	require.InDelta(t, 1.0, snap.HealthScore, 1e-9)
+	require.Equal(t, int64(2), scorer.SnapshotMetrics().TokenRefreshFailureTotal)
+}
+
+func TestAccountHealthScorer_FilterByBestScorePrefersHealthyAccount(t *testing.T) {
+	scorer, _ := newTestAccountHealthScorer(t)
+	items := []accountWithLoad{healthCandidate(1, 10), healthCandidate(2, 10), healthCandidate(3, 10)}
+
+	// 没有运行时统计时同分，全部交给 LRU
+	best, _ := scorer.filterByBestScore(items)
+	require.Len(t, best, 3)
+
+	scorer.ReportOverload(1, 429)
+	scorer.ReportResult(2, false, nil)
+	best, score := scorer.filterByBestScore(items)
+	require.Len(t, best, 1)
+	require.Equal(t, int64(3), best[0].account.ID)
+	require.Greater(t, score, 0.0)
+	require.LessOrEqual(t, score, 1.0)
+}
+
+func TestAccountHealthScorer_LoadStillMatters(t *testing.T) {
+	scorer, _ := newTestAccountHealthScorer(t)
+	// 一次 429 的惩罚小于 90% 的负载差
+	scorer.ReportOverload(1, 429)
+	best, _ := scorer.filterByBestScore([]accountWithLoad{healthCandidate(1, 0), healthCandidate(2, 90)})
+	require.Len(t, best, 1)
+	require.Equal(t, int64(1), best[0].account.ID)
+}
+
+func TestAccountHealthScorer_TTFTNormalizedWithinCandidates(t *testing.T) {
+	scorer, _ := newTestAccountHealthScorer(t)
+	fast, slow := 200, 2000
+	scorer.ReportResult(1, true, &slow)
+	scorer.ReportResult(2, true, &fast)
+
+	scores := scorer.scoreCandidates([]accountWithLoad{healthCandidate(1, 0), healthCandidate(2, 0), healthCandidate(3, 0)})
+	require.Greater(t, scores[1], scores[2], "fastest beats the neutral no-sample account")
+	require.Greater(t, scores[2], scores[0], "neutral no-sample account beats the slowest")
+}
+
+func TestAccountHealthScorer_MetricsAndHealthList(t *testing.T) {
+	scorer, _ := newTestAccountHealthScorer(t)
+
+	scorer.recordSelect(4*time.Millisecond, nil)
+	scorer.recordLayer(accountScheduleLayerLoadBalance, 4, true, 0.8)
+	scorer.recordSelect(2*time.Millisecond, nil)
+	scorer.recordLayer(accountScheduleLayerFallback, 4, false, 0)
+	scorer.recordSelect(0, nil) // 粘性会话命中
+	scorer.recordSelect(0, errors.New("no available accounts"))
+
+	m := scorer.SnapshotMetrics()
+	require.True(t, m.HealthScoreEnabled)
+	require.Equal(t, int64(4), m.SelectTotal)
+	require.Equal(t, int64(1), m.SelectErrorTotal)
+	require.Equal(t, int64(1), m.LoadBalanceSelectTotal)
+	require.Equal(t, int64(1), m.FallbackWaitTotal)
+	require.Equal(t, int64(1), m.StickyOrRoutedTotal)
+	require.InDelta(t, 0.8, m.SelectedScoreAvg, 1e-9)
+	require.InDelta(t, 4.0, m.CandidateCountAvg, 1e-9)
+	require.InDelta(t, 1.5, m.SchedulerLatencyMsAvg, 1e-9)
+
+	scorer.ReportResult(10, true, nil)
+	scorer.ReportOverload(11, 529)
+	list := scorer.ListAccountHealth(0)
+	require.Len(t, list, 2)
+	require.Equal(t, int64(11), list[0].AccountID, "worst account first")
+	require.Len(t, scorer.ListAccountHealth(1), 1)
+}
+
+func TestAccountHealthScorer_RuntimeHealthScore(t *testing.T) {
+	scorer, _ := newTestAccountHealthScorer(t)
+	require.InDelta(t, 1.6, scorer.runtimeHealthScore(1), 1e-9)
+
+	scorer.ReportOverload(1, 429)
+	scorer.ReportTokenRefresh(1, false)
+	require.InDelta(t, 0.5+0.3, scorer.runtimeHealthScore(1), 1e-9)
+
+	scorer.enabled = false
+	require.Zero(t, scorer.runtimeHealthScore(1))
+}
+
+func TestAccountHealthScorer_NilSafe(t *testing.T) {
+	var scorer *AccountHealthScorer
+	require.False(t, scorer.Enabled())
+	scorer.ReportResult(1, true, nil)
+	scorer.ReportOverload(1, 429)
+	scorer.ReportTokenRefresh(1, false)
+	scorer.recordSelect(time.Millisecond, nil)
+	scorer.recordLayer(accountScheduleLayerLoadBalance, 1, true, 1)
+	require.Nil(t, scorer.runtimeStats())
+	require.Zero(t, scorer.runtimeHealthScore(1))
+	require.Equal(t, AccountSchedulerMetricsSnapshot{}, scorer.SnapshotMetrics())
+	require.Empty(t, scorer.ListAccountHealth(10))
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"strings"
+	"time"
+)
+
+// 管理员 API Token 的授权资源（scope 形如 "<resource>:read" / "<resource>:write"）
+const (
+	AdminScopeResourceDashboard     = "dashboard"
+	AdminScopeResourceUsers         = "users"
+	AdminScopeResourceGroups        = "groups"
+	AdminScopeResourceAccounts      = "accounts"
+	AdminScopeResourceProxies       = "proxies"
+	AdminScopeResourceBilling       = "billing"
+	AdminScopeResourceUsage         = "usage"
+	AdminScopeResourceOps           = "ops"
+	AdminScopeResourceAnnouncements = "announcements"
+	AdminScopeResourceSettings      = "settings"
+	AdminScopeResourceSystem        = "system"
+	AdminScopeResourceAudit         = "audit"
+
+	// AdminScopeAll 授予全部资源的读写权限
+	AdminScopeAll = "*"
+)
+
+// AdminScopeResources 所有可授权的资源（按管理后台模块划分）
+var AdminScopeResources = []string{
+	AdminScopeResourceDashboard,
+	AdminScopeResourceUsers,
+	AdminScopeResourceGroups,
+	AdminScopeResourceAccounts,
+	AdminScopeResourceProxies,
+	AdminScopeResourceBilling,
+	AdminScopeResourceUsage,
+	AdminScopeResourceOps,
+	AdminScopeResourceAnnouncements,
+	AdminScopeResourceSettings,
+	AdminScopeResourceSystem,
+	AdminScopeResourceAudit,
+}
+
+// AdminAPIToken 具名的管理员 API Token，只能访问 Scopes 授权的管理接口
+type AdminAPIToken struct {
+	ID          int64
+	Name        string
+	TokenHash   string
+	TokenPrefix string
+	Scopes      []string
+	IPWhitelist []string
+	ExpiresAt   *time.Time
+	LastUsedAt  *time.Time
+	LastUsedIP  string
+	CreatedBy   *int64
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// IsExpired 是否已过期
+func (t *AdminAPIToken) IsExpired(now time.Time) bool {
+	return t.ExpiresAt != nil && !now.Before(*t.ExpiresAt)
+}
+
+// HasScope 检查是否拥有资源的读/写权限，write 隐含 read
+func (t *AdminAPIToken) HasScope(resource string, write bool) bool {
+	for _, scope := range t.Scopes {
+		if scope == AdminScopeAll || scope == resource+":write" {
+			return true
+		}
+		if !write && scope == resource+":read" {
+			return true
+		}
+	}
+	return false
+}
+
+// isValidAdminScope 校验 scope 格式与资源名
+func isValidAdminScope(scope string) bool {
+	if scope == AdminScopeAll {
+		return true
+	}
+	resource, access, ok := strings.Cut(scope, ":")
+	if !ok || (access != "read" && access != "write") {
+		return false
+	}
+	for _, r := range AdminScopeResources {
+		if r == resource {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,193 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ip"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+var (
+	ErrAdminAPITokenNotFound      = infraerrors.NotFound("ADMIN_TOKEN_NOT_FOUND", "admin api token not found")
+	ErrAdminAPITokenInvalid       = infraerrors.Unauthorized("INVALID_ADMIN_KEY", "Invalid admin API key")
+	ErrAdminAPITokenExpired       = infraerrors.Unauthorized("ADMIN_TOKEN_EXPIRED", "Admin API token has expired")
+	ErrAdminAPITokenIPDenied      = infraerrors.Forbidden("ACCESS_DENIED", "Access denied")
+	ErrAdminAPITokenNameRequired  = infraerrors.BadRequest("ADMIN_TOKEN_NAME_REQUIRED", "token name is required")
+	ErrAdminAPITokenNoScopes      = infraerrors.BadRequest("ADMIN_TOKEN_SCOPES_REQUIRED", "at least one scope is required")
+	ErrAdminAPITokenInvalidScope  = infraerrors.BadRequest("ADMIN_TOKEN_INVALID_SCOPE", "invalid admin token scope")
+	ErrAdminAPITokenInvalidExpiry = infraerrors.BadRequest("ADMIN_TOKEN_INVALID_EXPIRY", "expiry must be in the future")
+)
+
+// adminAPITokenTouchInterval 最后使用时间的最小写入间隔，避免每个请求都写库
+const adminAPITokenTouchInterval = time.Minute
+
+// AdminAPITokenRepository 管理员 API Token 持久化
+type AdminAPITokenRepository interface {
+	Create(ctx context.Context, token *AdminAPIToken) error
+	GetByID(ctx context.Context, id int64) (*AdminAPIToken, error)
+	GetByHash(ctx context.Context, tokenHash string) (*AdminAPIToken, error)
+	List(ctx context.Context) ([]AdminAPIToken, error)
+	Update(ctx context.Context, token *AdminAPIToken) error
+	Delete(ctx context.Context, id int64) error
+	TouchLastUsed(ctx context.Context, id int64, usedAt time.Time, clientIP string) error
+}
+
+// AdminAPITokenInput 创建/更新 Token 的参数（更新为全量替换）
+type AdminAPITokenInput struct {
+	Name        string
+	Scopes      []string
+	IPWhitelist []string
+	ExpiresAt   *time.Time
+}
+
+// AdminAPITokenService 管理具名、按 scope 授权的管理员 API Token
+type AdminAPITokenService struct {
+	repo AdminAPITokenRepository
+}
+
+// NewAdminAPITokenService 创建管理员 API Token 服务
+func NewAdminAPITokenService(repo AdminAPITokenRepository) *AdminAPITokenService {
+	return &AdminAPITokenService{repo: repo}
+}
+
+// List 列出所有 Token（不含明文）
+func (s *AdminAPITokenService) List(ctx context.Context) ([]AdminAPIToken, error) {
+	return s.repo.List(ctx)
+}
+
+// Create 创建 Token，明文只在此处返回一次
+func (s *AdminAPITokenService) Create(ctx context.Context, createdBy int64, input AdminAPITokenInput) (*AdminAPIToken, string, error) {
+	token := &AdminAPIToken{}
+	if err := applyAdminAPITokenInput(token, input, time.Now()); err != nil {
+		return nil, "", err
+	}
+
+	plaintext, err := generateAdminAPITokenValue()
+	if err != nil {
+		return nil, "", err
+	}
+	token.TokenHash = hashAdminAPIToken(plaintext)
+	token.TokenPrefix = plaintext[:len(AdminAPIKeyPrefix)+8]
+	if createdBy > 0 {
+		token.CreatedBy = &createdBy
+	}
+
+	if err := s.repo.Create(ctx, token); err != nil {
+		return nil, "", fmt.Errorf("create admin api token: %w", err)
+	}
+	return token, plaintext, nil
+}
+
+// Update 更新 Token 的名称、scope、IP 白名单与过期时间
+func (s *AdminAPITokenService) Update(ctx context.Context, id int64, input AdminAPITokenInput) (*AdminAPIToken, error) {
+	token, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if err := applyAdminAPITokenInput(token, input, time.Now()); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, token); err != nil {
+		return nil, fmt.Errorf("update admin api token: %w", err)
+	}
+	return token, nil
+}
+
+// Delete 吊销 Token
+func (s *AdminAPITokenService) Delete(ctx context.Context, id int64) error {
+	return s.repo.Delete(ctx, id)
+}
+
+// Authenticate 校验明文 Token（存在、未过期、来源 IP 在白名单内）并记录最后使用
+func (s *AdminAPITokenService) Authenticate(ctx context.Context, plaintext, clientIP string) (*AdminAPIToken, error) {
+	token, err := s.repo.GetByHash(ctx, hashAdminAPIToken(plaintext))
+	if err != nil {
+		if infraerrors.IsNotFound(err) {
+			return nil, ErrAdminAPITokenInvalid
+		}
+		return nil, err
+	}
+
+	now := time.Now()
+	if token.IsExpired(now) {
+		return nil, ErrAdminAPITokenExpired
+	}
+	if len(token.IPWhitelist) > 0 {
+		if allowed, _ := ip.CheckIPRestriction(clientIP, token.IPWhitelist, nil); !allowed {
+			return nil, ErrAdminAPITokenIPDenied
+		}
+	}
+
+	if token.LastUsedAt == nil || now.Sub(*token.LastUsedAt) >= adminAPITokenTouchInterval {
+		if err := s.repo.TouchLastUsed(ctx, token.ID, now, clientIP); err != nil {
+			logger.LegacyPrintf("service.admin_api_token", "Warning: touch admin api token %d failed: %v", token.ID, err)
+		} else {
+			token.LastUsedAt = &now
+			token.LastUsedIP = clientIP
+		}
+	}
+	return token, nil
+}
+
+func applyAdminAPITokenInput(token *AdminAPIToken, input AdminAPITokenInput, now time.Time) error {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return ErrAdminAPITokenNameRequired
+	}
+
+	scopes := make([]string, 0, len(input.Scopes))
+	seen := make(map[string]struct{}, len(input.Scopes))
+	for _, scope := range input.Scopes {
+		scope = strings.TrimSpace(scope)
+		if scope == "" {
+			continue
+		}
+		if !isValidAdminScope(scope) {
+			return fmt.Errorf("%w: %s", ErrAdminAPITokenInvalidScope, scope)
+		}
+		if _, ok := seen[scope]; ok {
+			continue
+		}
+		seen[scope] = struct{}{}
+		scopes = append(scopes, scope)
+	}
+	if len(scopes) == 0 {
+		return ErrAdminAPITokenNoScopes
+	}
+
+	if invalid := ip.ValidateIPPatterns(input.IPWhitelist); len(invalid) > 0 {
+		return fmt.Errorf("%w: %v", ErrInvalidIPPattern, invalid)
+	}
+	if input.ExpiresAt != nil && !input.ExpiresAt.After(now) {
+		return ErrAdminAPITokenInvalidExpiry
+	}
+
+	token.Name = name
+	token.Scopes = scopes
+	token.IPWhitelist = input.IPWhitelist
+	if token.IPWhitelist == nil {
+		token.IPWhitelist = []string{}
+	}
+	token.ExpiresAt = input.ExpiresAt
+	return nil
+}
+
+func generateAdminAPITokenValue() (string, error) {
+	bytes := make([]byte, 32)
+	if _, err := rand.Read(bytes); err != nil {
+		return "", fmt.Errorf("generate random bytes: %w", err)
+	}
+	return AdminAPIKeyPrefix + hex.EncodeToString(bytes), nil
+}
+
+func hashAdminAPIToken(plaintext string) string {
+	sum := sha256.Sum256([]byte(plaintext))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,155 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type adminAPITokenRepoStub struct {
+	tokens     map[int64]*AdminAPIToken
+	nextID     int64
+	touchCalls int
+}
+
+func newAdminAPITokenRepoStub() *adminAPITokenRepoStub {
+	return &adminAPITokenRepoStub{tokens: make(map[int64]*AdminAPIToken)}
+}
+
+func (r *adminAPITokenRepoStub) Create(_ context.Context, token *AdminAPIToken) error {
+	r.nextID++
+	token.ID = r.nextID
+	cp := *token
+	r.tokens[token.ID] = &cp
+	return nil
+}
+
+func (r *adminAPITokenRepoStub) GetByID(_ context.Context, id int64) (*AdminAPIToken, error) {
+	token, ok := r.tokens[id]
+	if !ok {
+		return nil, ErrAdminAPITokenNotFound
+	}
+	cp := *token
+	return &cp, nil
+}
+
+func (r *adminAPITokenRepoStub) GetByHash(_ context.Context, tokenHash string) (*AdminAPIToken, error) {
+	for _, token := range r.tokens {
+		if token.TokenHash == tokenHash {
+			cp := *token
+			return &cp, nil
+		}
+	}
+	return nil, ErrAdminAPITokenNotFound
+}
+
+func (r *adminAPITokenRepoStub) List(_ context.Context) ([]AdminAPIToken, error) {
+	out := make([]AdminAPIToken, 0, len(r.tokens))
+	for _, token := range r.tokens {
+		out = append(out, *token)
+	}
+	return out, nil
+}
+
+func (r *adminAPITokenRepoStub) Update(_ context.Context, token *AdminAPIToken) error {
+	cp := *token
+	r.tokens[token.ID] = &cp
+	return nil
+}
+
+func (r *adminAPITokenRepoStub) Delete(_ context.Context, id int64) error {
+	delete(r.tokens, id)
+	return nil
+}
+
+func (r *adminAPITokenRepoStub) TouchLastUsed(_ context.Context, id int64, usedAt time.Time, clientIP string) error {
+	r.touchCalls++
+	r.tokens[id].LastUsedAt = &usedAt
+	r.tokens[id].LastUsedIP = clientIP
+	return nil
+}
+
+func TestAdminAPIToken_HasScope(t *testing.T) {
+	token := &AdminAPIToken{Scopes: []string{"accounts:read", "users:write"}}
+
+	require.True(t, token.HasScope(AdminScopeResourceAccounts, false))
+	require.False(t, token.HasScope(AdminScopeResourceAccounts, true))
+	require.True(t, token.HasScope(AdminScopeResourceUsers, false), "write implies read")
+	require.True(t, token.HasScope(AdminScopeResourceUsers, true))
+	require.False(t, token.HasScope(AdminScopeResourceOps, false))
+
+	all := &AdminAPIToken{Scopes: []string{AdminScopeAll}}
+	require.True(t, all.HasScope(AdminScopeResourceSystem, true))
+}
+
+func TestAdminAPITokenService_CreateValidatesInput(t *testing.T) {
+	ctx := context.Background()
+	svc := NewAdminAPITokenService(newAdminAPITokenRepoStub())
+
+	_, _, err := svc.Create(ctx, 1, AdminAPITokenInput{Name: " ", Scopes: []string{"ops:read"}})
+	require.ErrorIs(t, err, ErrAdminAPITokenNameRequired)
+
+	_, _, err = svc.Create(ctx, 1, AdminAPITokenInput{Name: "bot"})
+	require.ErrorIs(t, err, ErrAdminAPITokenNoScopes)
+
+	_, _, err = svc.Create(ctx, 1, AdminAPITokenInput{Name: "bot", Scopes: []string{"accounts:delete"}})
+	require.ErrorIs(t, err, ErrAdminAPITokenInvalidScope)
+
+	_, _, err = svc.Create(ctx, 1, AdminAPITokenInput{Name: "bot", Scopes: []string{"payments:read"}})
+	require.ErrorIs(t, err, ErrAdminAPITokenInvalidScope)
+
+	_, _, err = svc.Create(ctx, 1, AdminAPITokenInput{Name: "bot", Scopes: []string{"ops:read"}, IPWhitelist: []string{"not-an-ip"}})
+	require.ErrorIs(t, err, ErrInvalidIPPattern)
+
+	past := time.Now().Add(-time.Hour)
+	_, _, err = svc.Create(ctx, 1, AdminAPITokenInput{Name: "bot", Scopes: []string{"ops:read"}, ExpiresAt: &past})
+	require.ErrorIs(t, err, ErrAdminAPITokenInvalidExpiry)
+
+	token, plaintext, err := svc.Create(ctx, 1, AdminAPITokenInput{Name: " bot ", Scopes: []string{"ops:read", "ops:read", "billing:write"}})
+	require.NoError(t, err)
+	require.Equal(t, "bot", token.Name)
+	require.Equal(t, []string{"ops:read", "billing:write"}, token.Scopes)
+	require.Equal(t, []string{}, token.IPWhitelist)
+	require.Equal(t, hashAdminAPIToken(plaintext), token.TokenHash)
+	require.NotContains(t, token.TokenHash, plaintext)
+	require.Equal(t, plaintext[:len(token.TokenPrefix)], token.TokenPrefix)
+}
+
+func TestAdminAPITokenService_Authenticate(t *testing.T) {
+	ctx := context.Background()
+	repo := newAdminAPITokenRepoStub()
+	svc := NewAdminAPITokenService(repo)
+
+	token, plaintext, err := svc.Create(ctx, 1, AdminAPITokenInput{
+		Name:        "monitor",
+		Scopes:      []string{"ops:read"},
+		IPWhitelist: []string{"10.0.0.0/8"},
+	})
+	require.NoError(t, err)
+
+	_, err = svc.Authenticate(ctx, plaintext+"x", "10.1.2.3")
+	require.ErrorIs(t, err, ErrAdminAPITokenInvalid)
+
+	_, err = svc.Authenticate(ctx, plaintext, "192.168.1.1")
+	require.ErrorIs(t, err, ErrAdminAPITokenIPDenied)
+
+	got, err := svc.Authenticate(ctx, plaintext, "10.1.2.3")
+	require.NoError(t, err)
+	require.Equal(t, token.ID, got.ID)
+	require.Equal(t, 1, repo.touchCalls)
+	require.Equal(t, "10.1.2.3", repo.tokens[token.ID].LastUsedIP)
+
+	// 最后使用时间在间隔内不重复写库
+	_, err = svc.Authenticate(ctx, plaintext, "10.1.2.3")
+	require.NoError(t, err)
+	require.Equal(t, 1, repo.touchCalls)
+
+	expired := time.Now().Add(-time.Minute)
+	repo.tokens[token.ID].ExpiresAt = &expired
+	_, err = svc.Authenticate(ctx, plaintext, "10.1.2.3")
+	require.ErrorIs(t, err, ErrAdminAPITokenExpired)
+}
@@ -0,0 +1,189 @@
+package service
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"strings"
+	"time"
+)
+
+// 审计日志中的操作者类型（与 AdminAuth 中间件的 auth_method 保持一致）
+const (
+	AdminAuditActorJWT        = "jwt"
+	AdminAuditActorAPIKey     = "admin_api_key"
+	AdminAuditActorAdminToken = "admin_api_token"
+)
+
+// adminAuditRedacted 敏感字段脱敏后的占位值
+const adminAuditRedacted = "[REDACTED]"
+
+// AdminAuditLog 一条管理员写操作审计记录（只追加）
+type AdminAuditLog struct {
+	ID             int64
+	ActorType      string
+	ActorUserID    *int64
+	ActorTokenID   *int64
+	ActorTokenName string
+	Method         string
+	Route          string
+	Path           string
+	TargetType     string
+	TargetID       string
+	StatusCode     int
+	ClientIP       string
+	RequestBody    json.RawMessage
+	Diff           map[string]AdminAuditChange
+	CreatedAt      time.Time
+}
+
+// AdminAuditChange 单个字段的变更；新建时 Before 为空，删除时 After 为空
+type AdminAuditChange struct {
+	Before any `json:"before,omitempty"`
+	After  any `json:"after,omitempty"`
+}
+
+// AdminAuditLogFilter 审计日志查询条件
+type AdminAuditLogFilter struct {
+	ActorUserID *int64
+	ActorType   string
+	TargetType  string
+	TargetID    string
+	Method      string
+	StartTime   *time.Time
+	EndTime     *time.Time
+}
+
+// isSensitiveAuditKey 判断字段名是否需要脱敏（忽略大小写与下划线/连字符）
+func isSensitiveAuditKey(key string) bool {
+	k := strings.ToLower(key)
+	k = strings.NewReplacer("_", "", "-", "").Replace(k)
+	for _, word := range []string{"password", "secret", "credential", "private", "authorization", "cookie"} {
+		if strings.Contains(k, word) {
+			return true
+		}
+	}
+	return strings.HasSuffix(k, "token") || strings.HasSuffix(k, "key")
+}
+
+// isSensitiveAuditPath 路径中任一段为敏感字段即视为敏感（如 credentials.access_token）
+func isSensitiveAuditPath(path string) bool {
+	for _, segment := range strings.Split(path, ".") {
+		if isSensitiveAuditKey(segment) {
+			return true
+		}
+	}
+	return false
+}
+
+// redactAuditValue 递归替换 JSON 值中的敏感字段
+func redactAuditValue(v any) any {
+	switch val := v.(type) {
+	case map[string]any:
+		out := make(map[string]any, len(val))
+		for k, item := range val {
+			if isSensitiveAuditKey(k) && !isEmptyAuditValue(item) {
+				out[k] = adminAuditRedacted
+				continue
+			}
+			out[k] = redactAuditValue(item)
+		}
+		return out
+	case []any:
+		out := make([]any, len(val))
+		for i, item := range val {
+			out[i] = redactAuditValue(item)
+		}
+		return out
+	default:
+		return v
+	}
+}
+
+func isEmptyAuditValue(v any) bool {
+	if v == nil {
+		return true
+	}
+	s, ok := v.(string)
+	return ok && s == ""
+}
+
+// normalizeAuditValue 将任意结构体转换为通用 JSON 值（map/slice/json.Number...），
+// 用于在变更发生前固定快照，并让 diff 与字段的 json 名称一致。
+func normalizeAuditValue(v any) (any, error) {
+	if v == nil {
+		return nil, nil
+	}
+	raw, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return decodeAuditJSON(raw)
+}
+
+func decodeAuditJSON(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var out any
+	if err := dec.Decode(&out); err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// flattenAuditValue 将嵌套对象展开为 "a.b.c" 路径；数组与标量作为叶子节点整体比较
+func flattenAuditValue(prefix string, v any, out map[string]any) {
+	obj, ok := v.(map[string]any)
+	if !ok || (len(obj) == 0 && prefix != "") {
+		if prefix != "" {
+			out[prefix] = v
+		}
+		return
+	}
+	for k, item := range obj {
+		path := k
+		if prefix != "" {
+			path = prefix + "." + k
+		}
+		flattenAuditValue(path, item, out)
+	}
+}
+
+// diffAuditValues 计算 before/after 两个已规范化快照之间的字段级差异，敏感字段只记录"发生了变更"。
+func diffAuditValues(before, after any) map[string]AdminAuditChange {
+	beforeFlat := make(map[string]any)
+	afterFlat := make(map[string]any)
+	flattenAuditValue("", before, beforeFlat)
+	flattenAuditValue("", after, afterFlat)
+
+	diff := make(map[string]AdminAuditChange)
+	for path, b := range beforeFlat {
+		a, exists := afterFlat[path]
+		if exists && reflect.DeepEqual(a, b) {
+			continue
+		}
+		diff[path] = redactAuditChange(path, AdminAuditChange{Before: b, After: a})
+	}
+	for path, a := range afterFlat {
+		if _, exists := beforeFlat[path]; exists {
+			continue
+		}
+		diff[path] = redactAuditChange(path, AdminAuditChange{After: a})
+	}
+	return diff
+}
+
+func redactAuditChange(path string, change AdminAuditChange) AdminAuditChange {
+	if isSensitiveAuditPath(path) {
+		if !isEmptyAuditValue(change.Before) {
+			change.Before = adminAuditRedacted
+		}
+		if !isEmptyAuditValue(change.After) {
+			change.After = adminAuditRedacted
+		}
+		return change
+	}
+	change.Before = redactAuditValue(change.Before)
+	change.After = redactAuditValue(change.After)
+	return change
+}
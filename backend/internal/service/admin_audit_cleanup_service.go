@@ -0,0 +1,138 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	adminAuditCleanupLeaderLockKey = "admin_audit:cleanup:leader"
+	adminAuditCleanupLeaderLockTTL = 30 * time.Minute
+)
+
+// AdminAuditCleanupService periodically deletes audit logs older than admin_audit.retention_days.
+//
+// Mirrors OpsCleanupService: 5-field cron schedule, best-effort Redis leader lock with DB advisory
+// lock fallback, and batched deletes to avoid long transactions.
+type AdminAuditCleanupService struct {
+	db          *sql.DB
+	redisClient *redis.Client
+	cfg         *config.Config
+
+	instanceID string
+
+	cron *cron.Cron
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+func NewAdminAuditCleanupService(db *sql.DB, redisClient *redis.Client, cfg *config.Config) *AdminAuditCleanupService {
+	return &AdminAuditCleanupService{
+		db:          db,
+		redisClient: redisClient,
+		cfg:         cfg,
+		instanceID:  uuid.NewString(),
+	}
+}
+
+func (s *AdminAuditCleanupService) Start() {
+	if s == nil || s.db == nil || s.cfg == nil {
+		return
+	}
+	if s.cfg.AdminAudit.RetentionDays <= 0 {
+		logger.LegacyPrintf("service.admin_audit_cleanup", "[AdminAuditCleanup] not started (retention disabled)")
+		return
+	}
+
+	s.startOnce.Do(func() {
+		schedule := "30 3 * * *"
+		if strings.TrimSpace(s.cfg.AdminAudit.CleanupSchedule) != "" {
+			schedule = strings.TrimSpace(s.cfg.AdminAudit.CleanupSchedule)
+		}
+
+		loc := time.Local
+		if strings.TrimSpace(s.cfg.Timezone) != "" {
+			if parsed, err := time.LoadLocation(strings.TrimSpace(s.cfg.Timezone)); err == nil && parsed != nil {
+				loc = parsed
+			}
+		}
+
+		c := cron.New(cron.WithParser(opsCleanupCronParser), cron.WithLocation(loc))
+		if _, err := c.AddFunc(schedule, func() { s.runScheduled() }); err != nil {
+			logger.LegacyPrintf("service.admin_audit_cleanup", "[AdminAuditCleanup] not started (invalid schedule=%q): %v", schedule, err)
+			return
+		}
+		s.cron = c
+		s.cron.Start()
+		logger.LegacyPrintf("service.admin_audit_cleanup", "[AdminAuditCleanup] started (schedule=%q tz=%s retention_days=%d)", schedule, loc.String(), s.cfg.AdminAudit.RetentionDays)
+	})
+}
+
+func (s *AdminAuditCleanupService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.cron != nil {
+			ctx := s.cron.Stop()
+			select {
+			case <-ctx.Done():
+			case <-time.After(3 * time.Second):
+				logger.LegacyPrintf("service.admin_audit_cleanup", "[AdminAuditCleanup] cron stop timed out")
+			}
+		}
+	})
+}
+
+func (s *AdminAuditCleanupService) runScheduled() {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Minute)
+	defer cancel()
+
+	release, ok := s.tryAcquireLeaderLock(ctx)
+	if !ok {
+		return
+	}
+	if release != nil {
+		defer release()
+	}
+
+	cutoff := time.Now().UTC().AddDate(0, 0, -s.cfg.AdminAudit.RetentionDays)
+	deleted, err := deleteOldRowsByID(ctx, s.db, "admin_audit_logs", "created_at", cutoff, 5000, false)
+	if err != nil {
+		logger.LegacyPrintf("service.admin_audit_cleanup", "[AdminAuditCleanup] cleanup failed: %v", err)
+		return
+	}
+	logger.LegacyPrintf("service.admin_audit_cleanup", "[AdminAuditCleanup] cleanup complete: deleted=%d", deleted)
+}
+
+func (s *AdminAuditCleanupService) tryAcquireLeaderLock(ctx context.Context) (func(), bool) {
+	// In simple run mode, assume single instance.
+	if s.cfg.RunMode == config.RunModeSimple {
+		return nil, true
+	}
+
+	if s.redisClient != nil {
+		ok, err := s.redisClient.SetNX(ctx, adminAuditCleanupLeaderLockKey, s.instanceID, adminAuditCleanupLeaderLockTTL).Result()
+		if err == nil {
+			if !ok {
+				return nil, false
+			}
+			return func() {
+				_, _ = opsCleanupReleaseScript.Run(ctx, s.redisClient, []string{adminAuditCleanupLeaderLockKey}, s.instanceID).Result()
+			}, true
+		}
+		// Redis error: fall back to DB advisory lock.
+	}
+
+	return tryAcquireDBAdvisoryLock(ctx, s.db, hashAdvisoryLockID(adminAuditCleanupLeaderLockKey))
+}
@@ -0,0 +1,175 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strconv"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// adminAuditExportBatchSize 导出时每批读取的记录数
+const adminAuditExportBatchSize = 1000
+
+// AdminAuditRepository 审计日志存储（只追加；过期数据由 AdminAuditCleanupService 按表批量删除）
+type AdminAuditRepository interface {
+	Create(ctx context.Context, log *AdminAuditLog) error
+	List(ctx context.Context, params pagination.PaginationParams, filter AdminAuditLogFilter) ([]AdminAuditLog, *pagination.PaginationResult, error)
+	// ListAfterID 按 id 升序返回 id > afterID 的记录，用于导出时分批遍历
+	ListAfterID(ctx context.Context, filter AdminAuditLogFilter, afterID int64, limit int) ([]AdminAuditLog, error)
+}
+
+// AdminAuditSnapshotLoader 按 ID 加载目标对象当前状态，用于生成变更 diff
+type AdminAuditSnapshotLoader func(ctx context.Context, id int64) (any, error)
+
+// AdminAuditRecordInput 中间件采集到的一次写操作
+type AdminAuditRecordInput struct {
+	ActorType      string
+	ActorUserID    *int64
+	ActorTokenID   *int64
+	ActorTokenName string
+	Method         string
+	Route          string
+	Path           string
+	TargetType     string
+	TargetID       string
+	StatusCode     int
+	ClientIP       string
+	// RequestBody 原始 JSON 请求体（非 JSON 或超长时为空）
+	RequestBody []byte
+	// Before/After 由 Snapshot 返回的规范化快照
+	Before any
+	After  any
+}
+
+// AdminAuditService 记录并查询管理员写操作审计日志
+type AdminAuditService struct {
+	repo AdminAuditRepository
+	cfg  *config.Config
+
+	loaders      map[string]AdminAuditSnapshotLoader
+	routeLoaders map[string]func(ctx context.Context) (any, error)
+}
+
+// NewAdminAuditService creates a new AdminAuditService
+func NewAdminAuditService(repo AdminAuditRepository, cfg *config.Config) *AdminAuditService {
+	return &AdminAuditService{
+		repo:         repo,
+		cfg:          cfg,
+		loaders:      make(map[string]AdminAuditSnapshotLoader),
+		routeLoaders: make(map[string]func(ctx context.Context) (any, error)),
+	}
+}
+
+// RegisterSnapshotLoader 为目标类型（如 accounts）注册按 ID 加载快照的函数。
+// 仅在启动装配阶段调用，非并发安全。
+func (s *AdminAuditService) RegisterSnapshotLoader(targetType string, loader AdminAuditSnapshotLoader) {
+	s.loaders[targetType] = loader
+}
+
+// RegisterRouteSnapshotLoader 为没有 ID 的单例资源（如系统设置）按路由模板注册快照加载函数。
+// 仅在启动装配阶段调用，非并发安全。
+func (s *AdminAuditService) RegisterRouteSnapshotLoader(route string, loader func(ctx context.Context) (any, error)) {
+	s.routeLoaders[route] = loader
+}
+
+// Enabled 是否记录审计日志
+func (s *AdminAuditService) Enabled() bool {
+	if s == nil || s.repo == nil {
+		return false
+	}
+	return s.cfg == nil || s.cfg.AdminAudit.Enabled
+}
+
+// Snapshot 加载目标对象的当前状态并规范化为 JSON 值；没有对应加载器或加载失败时返回 nil。
+func (s *AdminAuditService) Snapshot(ctx context.Context, route, targetType, targetID string) any {
+	var (
+		state any
+		err   error
+	)
+	if loader, ok := s.routeLoaders[route]; ok {
+		state, err = loader(ctx)
+	} else if loader, ok := s.loaders[targetType]; ok && targetID != "" {
+		id, parseErr := strconv.ParseInt(targetID, 10, 64)
+		if parseErr != nil || id <= 0 {
+			return nil
+		}
+		state, err = loader(ctx, id)
+	} else {
+		return nil
+	}
+	if err != nil {
+		// 目标不存在（如新建失败或已删除）时不记录快照
+		return nil
+	}
+	normalized, err := normalizeAuditValue(state)
+	if err != nil {
+		logger.LegacyPrintf("service.admin_audit", "[AdminAudit] snapshot %s/%s failed: %v", targetType, targetID, err)
+		return nil
+	}
+	return normalized
+}
+
+// Record 写入一条审计记录：请求体与 diff 在落库前脱敏。
+func (s *AdminAuditService) Record(ctx context.Context, input *AdminAuditRecordInput) error {
+	log := &AdminAuditLog{
+		ActorType:      input.ActorType,
+		ActorUserID:    input.ActorUserID,
+		ActorTokenID:   input.ActorTokenID,
+		ActorTokenName: input.ActorTokenName,
+		Method:         input.Method,
+		Route:          input.Route,
+		Path:           input.Path,
+		TargetType:     input.TargetType,
+		TargetID:       input.TargetID,
+		StatusCode:     input.StatusCode,
+		ClientIP:       input.ClientIP,
+	}
+	if len(input.RequestBody) > 0 {
+		if body, err := decodeAuditJSON(input.RequestBody); err == nil {
+			if raw, err := json.Marshal(redactAuditValue(body)); err == nil {
+				log.RequestBody = raw
+			}
+		}
+	}
+	if input.Before != nil || input.After != nil {
+		if diff := diffAuditValues(input.Before, input.After); len(diff) > 0 {
+			log.Diff = diff
+		}
+	}
+	return s.repo.Create(ctx, log)
+}
+
+// List 分页查询审计日志（按时间倒序）
+func (s *AdminAuditService) List(ctx context.Context, params pagination.PaginationParams, filter AdminAuditLogFilter) ([]AdminAuditLog, *pagination.PaginationResult, error) {
+	return s.repo.List(ctx, params, filter)
+}
+
+// Export 按 id 升序遍历符合条件的审计日志，最多 maxRows 条；fn 返回错误时中止。
+func (s *AdminAuditService) Export(ctx context.Context, filter AdminAuditLogFilter, maxRows int, fn func(*AdminAuditLog) error) error {
+	var afterID int64
+	exported := 0
+	for exported < maxRows {
+		limit := adminAuditExportBatchSize
+		if remaining := maxRows - exported; remaining < limit {
+			limit = remaining
+		}
+		logs, err := s.repo.ListAfterID(ctx, filter, afterID, limit)
+		if err != nil {
+			return err
+		}
+		for i := range logs {
+			if err := fn(&logs[i]); err != nil {
+				return err
+			}
+		}
+		exported += len(logs)
+		if len(logs) < limit {
+			break
+		}
+		afterID = logs[len(logs)-1].ID
+	}
+	return nil
+}
@@ -0,0 +1,122 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+type adminAuditRepoStub struct {
+	logs []AdminAuditLog
+}
+
+func (r *adminAuditRepoStub) Create(_ context.Context, log *AdminAuditLog) error {
+	log.ID = int64(len(r.logs) + 1)
+	r.logs = append(r.logs, *log)
+	return nil
+}
+
+func (r *adminAuditRepoStub) List(_ context.Context, params pagination.PaginationParams, _ AdminAuditLogFilter) ([]AdminAuditLog, *pagination.PaginationResult, error) {
+	return r.logs, &pagination.PaginationResult{Total: int64(len(r.logs)), Page: params.Page, PageSize: params.PageSize}, nil
+}
+
+func (r *adminAuditRepoStub) ListAfterID(_ context.Context, _ AdminAuditLogFilter, afterID int64, limit int) ([]AdminAuditLog, error) {
+	out := make([]AdminAuditLog, 0, limit)
+	for _, log := range r.logs {
+		if log.ID > afterID && len(out) < limit {
+			out = append(out, log)
+		}
+	}
+	return out, nil
+}
+
+func TestIsSensitiveAuditKey(t *testing.T) {
+	for _, key := range []string{"password", "PasswordHash", "client_secret", "Credentials", "access_token", "api_key", "private_key", "SMTPPassword"} {
+		require.True(t, isSensitiveAuditKey(key), key)
+	}
+	for _, key := range []string{"name", "max_tokens", "status", "TokenPrefix", "concurrency"} {
+		require.False(t, isSensitiveAuditKey(key), key)
+	}
+}
+
+func TestDiffAuditValues_RedactsSensitiveFields(t *testing.T) {
+	before, err := normalizeAuditValue(map[string]any{
+		"name":        "acc",
+		"priority":    1,
+		"credentials": map[string]any{"access_token": "old", "base_url": "https://a"},
+		"extra":       map[string]any{"note": "same"},
+	})
+	require.NoError(t, err)
+	after, err := normalizeAuditValue(map[string]any{
+		"name":        "acc",
+		"priority":    2,
+		"credentials": map[string]any{"access_token": "new", "base_url": "https://a"},
+		"extra":       map[string]any{"note": "same"},
+		"proxy":       map[string]any{"password": "pw"},
+	})
+	require.NoError(t, err)
+
+	diff := diffAuditValues(before, after)
+	require.Len(t, diff, 3)
+	require.Equal(t, json.Number("1"), diff["priority"].Before)
+	require.Equal(t, json.Number("2"), diff["priority"].After)
+	require.Equal(t, AdminAuditChange{Before: adminAuditRedacted, After: adminAuditRedacted}, diff["credentials.access_token"])
+	require.Equal(t, AdminAuditChange{After: adminAuditRedacted}, diff["proxy.password"])
+}
+
+func TestAdminAuditService_Record(t *testing.T) {
+	repo := &adminAuditRepoStub{}
+	svc := NewAdminAuditService(repo, nil)
+	svc.RegisterSnapshotLoader("groups", func(_ context.Context, id int64) (any, error) {
+		if id != 7 {
+			return nil, errors.New("not found")
+		}
+		return &Group{ID: 7, Name: "g"}, nil
+	})
+
+	require.Nil(t, svc.Snapshot(context.Background(), "/api/v1/admin/groups/:id", "groups", "8"))
+	require.Nil(t, svc.Snapshot(context.Background(), "/api/v1/admin/ops/:id", "ops", "7"))
+	before := svc.Snapshot(context.Background(), "/api/v1/admin/groups/:id", "groups", "7")
+	require.NotNil(t, before)
+
+	err := svc.Record(context.Background(), &AdminAuditRecordInput{
+		ActorType:   AdminAuditActorJWT,
+		Method:      "PUT",
+		TargetType:  "groups",
+		TargetID:    "7",
+		RequestBody: []byte(`{"name":"g2","api_key":"sk-123","nested":{"secret":"x"}}`),
+		Before:      before,
+		After:       map[string]any{"ID": json.Number("7"), "Name": "g2"},
+	})
+	require.NoError(t, err)
+	require.Len(t, repo.logs, 1)
+
+	var body map[string]any
+	require.NoError(t, json.Unmarshal(repo.logs[0].RequestBody, &body))
+	require.Equal(t, "g2", body["name"])
+	require.Equal(t, adminAuditRedacted, body["api_key"])
+	require.Equal(t, adminAuditRedacted, body["nested"].(map[string]any)["secret"])
+	require.Equal(t, AdminAuditChange{Before: "g", After: "g2"}, repo.logs[0].Diff["Name"])
+}
+
+func TestAdminAuditService_ExportPages(t *testing.T) {
+	repo := &adminAuditRepoStub{}
+	for i := 0; i < 5; i++ {
+		require.NoError(t, repo.Create(context.Background(), &AdminAuditLog{Method: "POST"}))
+	}
+	svc := NewAdminAuditService(repo, nil)
+
+	var ids []int64
+	err := svc.Export(context.Background(), AdminAuditLogFilter{}, 3, func(l *AdminAuditLog) error {
+		ids = append(ids, l.ID)
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []int64{1, 2, 3}, ids)
+}
@@ -10,6 +10,7 @@ import (
 	"time"
 
 	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
 	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/httpclient"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
@@ -46,6 +47,7 @@ type AdminService interface {
 
 	// API Key management (admin)
 	AdminUpdateAPIKeyGroupID(ctx context.Context, keyID int64, groupID *int64) (*AdminUpdateAPIKeyGroupIDResult, error)
+	AdminUpdateAPIKeyModelPolicy(ctx context.Context, keyID int64, update APIKeyModelPolicyUpdate) (*APIKey, error)
 
 	// Account management
 	ListAccounts(ctx context.Context, page, pageSize int, platform, accountType, status, search string, groupID int64) ([]Account, int64, error)
@@ -152,6 +154,12 @@ type CreateGroupInput struct {
 	// OpenAI Messages 调度配置（仅 openai 平台使用）
 	AllowMessagesDispatch bool
 	DefaultMappedModel    string
+	// 精确响应缓存配置
+	ResponseCacheEnabled    bool
+	ResponseCacheTTLSeconds int
+	ResponseCachePriceRatio *float64 // nil 时使用默认比例
+	// 模型降级链
+	ModelFallbackChains []ModelFallbackChain
 	// 从指定分组复制账号（创建分组后在同一事务内绑定）
 	CopyAccountsFromGroupIDs []int64
 }
@@ -195,6 +203,12 @@ type UpdateGroupInput struct {
 	// OpenAI Messages 调度配置（仅 openai 平台使用）
 	AllowMessagesDispatch *bool
 	DefaultMappedModel    *string
+	// 精确响应缓存配置
+	ResponseCacheEnabled    *bool
+	ResponseCacheTTLSeconds *int
+	ResponseCachePriceRatio *float64
+	// 模型降级链（nil 表示不修改，空数组表示清除）
+	ModelFallbackChains *[]ModelFallbackChain
 	// 从指定分组复制账号（同步操作：先清空当前分组的账号绑定，再绑定源分组的账号）
 	CopyAccountsFromGroupIDs []int64
 }
@@ -873,6 +887,19 @@ func (s *adminServiceImpl) CreateGroup(ctx context.Context, input *CreateGroupIn
 		mcpXMLInject = *input.MCPXMLInject
 	}
 
+	// 响应缓存命中计费比例：默认 DefaultResponseCachePriceRatio
+	responseCachePriceRatio := DefaultResponseCachePriceRatio
+	if input.ResponseCachePriceRatio != nil {
+		responseCachePriceRatio = *input.ResponseCachePriceRatio
+	}
+	if err := validateResponseCacheSettings(input.ResponseCacheTTLSeconds, responseCachePriceRatio); err != nil {
+		return nil, err
+	}
+	modelFallbackChains, err := domain.NormalizeModelFallbackChains(input.ModelFallbackChains)
+	if err != nil {
+		return nil, err
+	}
+
 	// 如果指定了复制账号的源分组，先获取账号 ID 列表
 	var accountIDsToCopy []int64
 	if len(input.CopyAccountsFromGroupIDs) > 0 {
@@ -945,6 +972,10 @@ func (s *adminServiceImpl) CreateGroup(ctx context.Context, input *CreateGroupIn
 		SoraStorageQuotaBytes:           input.SoraStorageQuotaBytes,
 		AllowMessagesDispatch:           input.AllowMessagesDispatch,
 		DefaultMappedModel:              input.DefaultMappedModel,
+		ResponseCacheEnabled:            input.ResponseCacheEnabled,
+		ResponseCacheTTLSeconds:         input.ResponseCacheTTLSeconds,
+		ResponseCachePriceRatio:         responseCachePriceRatio,
+		ModelFallbackChains:             modelFallbackChains,
 	}
 	if err := s.groupRepo.Create(ctx, group); err != nil {
 		return nil, err
@@ -1209,6 +1240,27 @@ func (s *adminServiceImpl) UpdateGroup(ctx context.Context, id int64, input *Upd
 		group.DefaultMappedModel = *input.DefaultMappedModel
 	}
 
+	// 精确响应缓存配置
+	if input.ResponseCacheEnabled != nil {
+		group.ResponseCacheEnabled = *input.ResponseCacheEnabled
+	}
+	if input.ResponseCacheTTLSeconds != nil {
+		group.ResponseCacheTTLSeconds = *input.ResponseCacheTTLSeconds
+	}
+	if input.ResponseCachePriceRatio != nil {
+		group.ResponseCachePriceRatio = *input.ResponseCachePriceRatio
+	}
+	if err := validateResponseCacheSettings(group.ResponseCacheTTLSeconds, group.ResponseCachePriceRatio); err != nil {
+		return nil, err
+	}
+	if input.ModelFallbackChains != nil {
+		chains, err := domain.NormalizeModelFallbackChains(*input.ModelFallbackChains)
+		if err != nil {
+			return nil, err
+		}
+		group.ModelFallbackChains = chains
+	}
+
 	if err := s.groupRepo.Update(ctx, group); err != nil {
 		return nil, err
 	}
@@ -1267,11 +1319,11 @@ func (s *adminServiceImpl) UpdateGroup(ctx context.Context, id int64, input *Upd
 }
 
 func (s *adminServiceImpl) DeleteGroup(ctx context.Context, id int64) error {
-	var groupKeys []string
+	var groupKeyHashes []string
 	if s.authCacheInvalidator != nil {
-		keys, err := s.apiKeyRepo.ListKeysByGroupID(ctx, id)
+		keyHashes, err := s.apiKeyRepo.ListKeyHashesByGroupID(ctx, id)
 		if err == nil {
-			groupKeys = keys
+			groupKeyHashes = keyHashes
 		}
 	}
 
@@ -1295,8 +1347,8 @@ func (s *adminServiceImpl) DeleteGroup(ctx context.Context, id int64) error {
 		}()
 	}
 	if s.authCacheInvalidator != nil {
-		for _, key := range groupKeys {
-			s.authCacheInvalidator.InvalidateAuthCacheByKey(ctx, key)
+		for _, keyHash := range groupKeyHashes {
+			s.authCacheInvalidator.InvalidateAuthCacheByKeyHash(ctx, keyHash)
 		}
 	}
 
@@ -1391,7 +1443,7 @@ func (s *adminServiceImpl) AdminUpdateAPIKeyGroupID(ctx context.Context, keyID i
 
 			// 失效认证缓存（在事务提交后执行）
 			if s.authCacheInvalidator != nil {
-				s.authCacheInvalidator.InvalidateAuthCacheByKey(ctx, apiKey.Key)
+				s.authCacheInvalidator.InvalidateAuthCacheByKeyHash(ctx, apiKey.KeyHash)
 			}
 
 			result.APIKey = apiKey
@@ -1406,13 +1458,34 @@ func (s *adminServiceImpl) AdminUpdateAPIKeyGroupID(ctx context.Context, keyID i
 
 	// 失效认证缓存
 	if s.authCacheInvalidator != nil {
-		s.authCacheInvalidator.InvalidateAuthCacheByKey(ctx, apiKey.Key)
+		s.authCacheInvalidator.InvalidateAuthCacheByKeyHash(ctx, apiKey.KeyHash)
 	}
 
 	result.APIKey = apiKey
 	return result, nil
 }
 
+// AdminUpdateAPIKeyModelPolicy 管理员修改 API Key 模型策略（白名单/黑名单/别名）
+func (s *adminServiceImpl) AdminUpdateAPIKeyModelPolicy(ctx context.Context, keyID int64, update APIKeyModelPolicyUpdate) (*APIKey, error) {
+	apiKey, err := s.apiKeyRepo.GetByID(ctx, keyID)
+	if err != nil {
+		return nil, err
+	}
+	if update.IsEmpty() {
+		return apiKey, nil
+	}
+	if err := update.applyTo(apiKey); err != nil {
+		return nil, err
+	}
+	if err := s.apiKeyRepo.Update(ctx, apiKey); err != nil {
+		return nil, fmt.Errorf("update api key: %w", err)
+	}
+	if s.authCacheInvalidator != nil {
+		s.authCacheInvalidator.InvalidateAuthCacheByKeyHash(ctx, apiKey.KeyHash)
+	}
+	return apiKey, nil
+}
+
 // Account management implementations
 func (s *adminServiceImpl) ListAccounts(ctx context.Context, page, pageSize int, platform, accountType, status, search string, groupID int64) ([]Account, int64, error) {
 	params := pagination.PaginationParams{Page: page, PageSize: pageSize}
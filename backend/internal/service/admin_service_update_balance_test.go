@@ -50,7 +50,7 @@ type authCacheInvalidatorStub struct {
 	keys     []string
 }
 
-func (s *authCacheInvalidatorStub) InvalidateAuthCacheByKey(ctx context.Context, key string) {
+func (s *authCacheInvalidatorStub) InvalidateAuthCacheByKeyHash(ctx context.Context, key string) {
 	s.keys = append(s.keys, key)
 }
 
@@ -1280,6 +1280,13 @@ func isModelNotFoundError(statusCode int, body []byte) bool {
 //	          ├─ 成功 → 正常返回
 //	          └─ 失败 → 设置模型限流 + 清除粘性绑定 → 切换账号
 func (s *AntigravityGatewayService) Forward(ctx context.Context, c *gin.Context, account *Account, body []byte, isStickySession bool) (*ForwardResult, error) {
+	ctx, span := startUpstreamAttemptSpan(ctx, c, account)
+	result, err := s.forward(ctx, c, account, body, isStickySession)
+	endUpstreamAttemptSpan(span, c, err)
+	return result, err
+}
+
+func (s *AntigravityGatewayService) forward(ctx context.Context, c *gin.Context, account *Account, body []byte, isStickySession bool) (*ForwardResult, error) {
 	// 上游透传账号直接转发，不走 OAuth token 刷新
 	if account.Type == AccountTypeUpstream {
 		return s.ForwardUpstream(ctx, c, account, body)
@@ -1935,6 +1942,13 @@ func stripSignatureSensitiveBlocksFromClaudeRequest(req *antigravity.ClaudeReque
 //	          ├─ 成功 → 正常返回
 //	          └─ 失败 → 设置模型限流 + 清除粘性绑定 → 切换账号
 func (s *AntigravityGatewayService) ForwardGemini(ctx context.Context, c *gin.Context, account *Account, originalModel string, action string, stream bool, body []byte, isStickySession bool) (*ForwardResult, error) {
+	ctx, span := startUpstreamAttemptSpan(ctx, c, account)
+	result, err := s.forwardGemini(ctx, c, account, originalModel, action, stream, body, isStickySession)
+	endUpstreamAttemptSpan(span, c, err)
+	return result, err
+}
+
+func (s *AntigravityGatewayService) forwardGemini(ctx context.Context, c *gin.Context, account *Account, originalModel string, action string, stream bool, body []byte, isStickySession bool) (*ForwardResult, error) {
 	startTime := time.Now()
 
 	sessionID := getSessionID(c)
@@ -255,7 +255,10 @@ func isNonRetryableAntigravityOAuthError(err error) bool {
 }
 
 // RefreshAccountToken 刷新账户的 token
-func (s *AntigravityOAuthService) RefreshAccountToken(ctx context.Context, account *Account) (*AntigravityTokenInfo, error) {
+func (s *AntigravityOAuthService) RefreshAccountToken(ctx context.Context, account *Account) (_ *AntigravityTokenInfo, err error) {
+	ctx, span := startTokenRefreshSpan(ctx, account)
+	defer func() { endTokenRefreshSpan(span, err) }()
+
 	if account.Platform != PlatformAntigravity || account.Type != AccountTypeOAuth {
 		return nil, fmt.Errorf("非 Antigravity OAuth 账户")
 	}
@@ -27,9 +27,12 @@ func IsWindowExpired(windowStart *time.Time, duration time.Duration) bool {
 }
 
 type APIKey struct {
-	ID          int64
-	UserID      int64
+	ID     int64
+	UserID int64
+	// Key 明文仅在创建时（以及认证请求内）可用，数据库只保存 KeyHash 与 KeyPrefix
 	Key         string
+	KeyHash     string
+	KeyPrefix   string
 	Name        string
 	GroupID     *int64
 	Status      string
@@ -44,6 +47,11 @@ type APIKey struct {
 	User                *User
 	Group               *Group
 
+	// Model policy fields（白名单/黑名单支持 * 通配符，别名在名单校验之前解析）
+	AllowedModels []string          // Allowed model patterns (empty = all)
+	DeniedModels  []string          // Denied model patterns (checked first)
+	ModelAliases  map[string]string // Client alias -> real model name
+
 	// Quota fields
 	Quota     float64    // Quota limit in USD (0 = unlimited)
 	QuotaUsed float64    // Used quota amount
@@ -59,6 +67,15 @@ type APIKey struct {
 	Window5hStart *time.Time // Start of current 5h window
 	Window1dStart *time.Time // Start of current 1d window
 	Window7dStart *time.Time // Start of current 7d window
+
+	// Throughput limit fields（按分钟/并发在 Redis 中计数，0 = unlimited）
+	RPMLimit       int // Max requests per minute
+	TPMLimit       int // Max tokens per minute
+	MaxConcurrency int // Max in-flight requests
+
+	// Organization fields（组织 Key 从组织共享余额扣费，nil = 个人 Key）
+	OrganizationID *int64
+	Organization   *Organization
 }
 
 func (k *APIKey) IsActive() bool {
@@ -70,6 +87,24 @@ func (k *APIKey) HasRateLimits() bool {
 	return k.RateLimit5h > 0 || k.RateLimit1d > 0 || k.RateLimit7d > 0
 }
 
+// HasThroughputLimits returns true if any RPM/TPM/concurrency limit is configured
+func (k *APIKey) HasThroughputLimits() bool {
+	return k.RPMLimit > 0 || k.TPMLimit > 0 || k.MaxConcurrency > 0
+}
+
+// IsOrganizationKey returns true if the key is billed against an organization
+func (k *APIKey) IsOrganizationKey() bool {
+	return k.OrganizationID != nil
+}
+
+// SubscriptionUserID 返回订阅归属用户：组织 Key 使用组织 owner 名下的订阅
+func (k *APIKey) SubscriptionUserID() int64 {
+	if k.OrganizationID != nil && k.Organization != nil {
+		return k.Organization.OwnerUserID
+	}
+	return k.UserID
+}
+
 // IsExpired checks if the API key has expired
 func (k *APIKey) IsExpired() bool {
 	if k.ExpiresAt == nil {
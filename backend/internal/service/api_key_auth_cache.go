@@ -13,6 +13,11 @@ type APIKeyAuthSnapshot struct {
 	User        APIKeyAuthUserSnapshot   `json:"user"`
 	Group       *APIKeyAuthGroupSnapshot `json:"group,omitempty"`
 
+	// Model policy fields（在认证中间件中执行，必须进入快照）
+	AllowedModels []string          `json:"allowed_models,omitempty"`
+	DeniedModels  []string          `json:"denied_models,omitempty"`
+	ModelAliases  map[string]string `json:"model_aliases,omitempty"`
+
 	// Quota fields for API Key independent quota feature
 	Quota     float64 `json:"quota"`      // Quota limit in USD (0 = unlimited)
 	QuotaUsed float64 `json:"quota_used"` // Used quota amount
@@ -24,6 +29,15 @@ type APIKeyAuthSnapshot struct {
 	RateLimit5h float64 `json:"rate_limit_5h"`
 	RateLimit1d float64 `json:"rate_limit_1d"`
 	RateLimit7d float64 `json:"rate_limit_7d"`
+
+	// Throughput limits (counters live in Redis)
+	RPMLimit       int `json:"rpm_limit,omitempty"`
+	TPMLimit       int `json:"tpm_limit,omitempty"`
+	MaxConcurrency int `json:"max_concurrency,omitempty"`
+
+	// Organization fields（组织 Key 从组织余额扣费）
+	OrganizationID *int64                          `json:"organization_id,omitempty"`
+	Organization   *APIKeyAuthOrganizationSnapshot `json:"organization,omitempty"`
 }
 
 // APIKeyAuthUserSnapshot 用户快照
@@ -35,6 +49,14 @@ type APIKeyAuthUserSnapshot struct {
 	Concurrency int     `json:"concurrency"`
 }
 
+// APIKeyAuthOrganizationSnapshot 组织快照
+type APIKeyAuthOrganizationSnapshot struct {
+	ID          int64   `json:"id"`
+	OwnerUserID int64   `json:"owner_user_id"`
+	Status      string  `json:"status"`
+	Balance     float64 `json:"balance"`
+}
+
 // APIKeyAuthGroupSnapshot 分组快照
 type APIKeyAuthGroupSnapshot struct {
 	ID                              int64    `json:"id"`
@@ -69,6 +91,14 @@ type APIKeyAuthGroupSnapshot struct {
 	// OpenAI Messages 调度配置（仅 openai 平台使用）
 	AllowMessagesDispatch bool   `json:"allow_messages_dispatch"`
 	DefaultMappedModel    string `json:"default_mapped_model,omitempty"`
+
+	// 精确响应缓存配置（网关热路径读取，需进入快照）
+	ResponseCacheEnabled    bool    `json:"response_cache_enabled,omitempty"`
+	ResponseCacheTTLSeconds int     `json:"response_cache_ttl_seconds,omitempty"`
+	ResponseCachePriceRatio float64 `json:"response_cache_price_ratio,omitempty"`
+
+	// 模型降级链（在网关 failover 循环中使用）
+	ModelFallbackChains []ModelFallbackChain `json:"model_fallback_chains,omitempty"`
 }
 
 // APIKeyAuthCacheEntry 缓存条目，支持负缓存
@@ -2,8 +2,6 @@ package service
 
 import (
 	"context"
-	"crypto/sha256"
-	"encoding/hex"
 	"errors"
 	"fmt"
 	"math/rand/v2"
@@ -101,9 +99,9 @@ func (s *APIKeyService) StartAuthCacheInvalidationSubscriber(ctx context.Context
 	}
 }
 
+// authCacheKey 认证缓存直接以 Key 的存储哈希为键，便于按库中的哈希精确失效
 func (s *APIKeyService) authCacheKey(key string) string {
-	sum := sha256.Sum256([]byte(key))
-	return hex.EncodeToString(sum[:])
+	return s.hashKey(key)
 }
 
 func (s *APIKeyService) getAuthCacheEntry(ctx context.Context, cacheKey string) (*APIKeyAuthCacheEntry, bool) {
@@ -161,7 +159,7 @@ func (s *APIKeyService) deleteAuthCache(ctx context.Context, cacheKey string) {
 }
 
 func (s *APIKeyService) loadAuthCacheEntry(ctx context.Context, key, cacheKey string) (*APIKeyAuthCacheEntry, error) {
-	apiKey, err := s.apiKeyRepo.GetByKeyForAuth(ctx, key)
+	apiKey, err := s.apiKeyRepo.GetByKeyHashForAuth(ctx, cacheKey)
 	if err != nil {
 		if errors.Is(err, ErrAPIKeyNotFound) {
 			entry := &APIKeyAuthCacheEntry{NotFound: true}
@@ -200,18 +198,26 @@ func (s *APIKeyService) snapshotFromAPIKey(apiKey *APIKey) *APIKeyAuthSnapshot {
 		return nil
 	}
 	snapshot := &APIKeyAuthSnapshot{
-		APIKeyID:    apiKey.ID,
-		UserID:      apiKey.UserID,
-		GroupID:     apiKey.GroupID,
-		Status:      apiKey.Status,
-		IPWhitelist: apiKey.IPWhitelist,
-		IPBlacklist: apiKey.IPBlacklist,
-		Quota:       apiKey.Quota,
-		QuotaUsed:   apiKey.QuotaUsed,
-		ExpiresAt:   apiKey.ExpiresAt,
-		RateLimit5h: apiKey.RateLimit5h,
-		RateLimit1d: apiKey.RateLimit1d,
-		RateLimit7d: apiKey.RateLimit7d,
+		APIKeyID:      apiKey.ID,
+		UserID:        apiKey.UserID,
+		GroupID:       apiKey.GroupID,
+		Status:        apiKey.Status,
+		IPWhitelist:   apiKey.IPWhitelist,
+		IPBlacklist:   apiKey.IPBlacklist,
+		AllowedModels: apiKey.AllowedModels,
+		DeniedModels:  apiKey.DeniedModels,
+		ModelAliases:  apiKey.ModelAliases,
+		Quota:         apiKey.Quota,
+		QuotaUsed:     apiKey.QuotaUsed,
+		ExpiresAt:     apiKey.ExpiresAt,
+		RateLimit5h:   apiKey.RateLimit5h,
+		RateLimit1d:   apiKey.RateLimit1d,
+		RateLimit7d:   apiKey.RateLimit7d,
+
+		RPMLimit:       apiKey.RPMLimit,
+		TPMLimit:       apiKey.TPMLimit,
+		MaxConcurrency: apiKey.MaxConcurrency,
+		OrganizationID: apiKey.OrganizationID,
 		User: APIKeyAuthUserSnapshot{
 			ID:          apiKey.User.ID,
 			Status:      apiKey.User.Status,
@@ -247,6 +253,18 @@ func (s *APIKeyService) snapshotFromAPIKey(apiKey *APIKey) *APIKeyAuthSnapshot {
 			SupportedModelScopes:            apiKey.Group.SupportedModelScopes,
 			AllowMessagesDispatch:           apiKey.Group.AllowMessagesDispatch,
 			DefaultMappedModel:              apiKey.Group.DefaultMappedModel,
+			ResponseCacheEnabled:            apiKey.Group.ResponseCacheEnabled,
+			ResponseCacheTTLSeconds:         apiKey.Group.ResponseCacheTTLSeconds,
+			ResponseCachePriceRatio:         apiKey.Group.ResponseCachePriceRatio,
+			ModelFallbackChains:             apiKey.Group.ModelFallbackChains,
+		}
+	}
+	if apiKey.Organization != nil {
+		snapshot.Organization = &APIKeyAuthOrganizationSnapshot{
+			ID:          apiKey.Organization.ID,
+			OwnerUserID: apiKey.Organization.OwnerUserID,
+			Status:      apiKey.Organization.Status,
+			Balance:     apiKey.Organization.Balance,
 		}
 	}
 	return snapshot
@@ -257,19 +275,27 @@ func (s *APIKeyService) snapshotToAPIKey(key string, snapshot *APIKeyAuthSnapsho
 		return nil
 	}
 	apiKey := &APIKey{
-		ID:          snapshot.APIKeyID,
-		UserID:      snapshot.UserID,
-		GroupID:     snapshot.GroupID,
-		Key:         key,
-		Status:      snapshot.Status,
-		IPWhitelist: snapshot.IPWhitelist,
-		IPBlacklist: snapshot.IPBlacklist,
-		Quota:       snapshot.Quota,
-		QuotaUsed:   snapshot.QuotaUsed,
-		ExpiresAt:   snapshot.ExpiresAt,
-		RateLimit5h: snapshot.RateLimit5h,
-		RateLimit1d: snapshot.RateLimit1d,
-		RateLimit7d: snapshot.RateLimit7d,
+		ID:            snapshot.APIKeyID,
+		UserID:        snapshot.UserID,
+		GroupID:       snapshot.GroupID,
+		Key:           key,
+		Status:        snapshot.Status,
+		IPWhitelist:   snapshot.IPWhitelist,
+		IPBlacklist:   snapshot.IPBlacklist,
+		AllowedModels: snapshot.AllowedModels,
+		DeniedModels:  snapshot.DeniedModels,
+		ModelAliases:  snapshot.ModelAliases,
+		Quota:         snapshot.Quota,
+		QuotaUsed:     snapshot.QuotaUsed,
+		ExpiresAt:     snapshot.ExpiresAt,
+		RateLimit5h:   snapshot.RateLimit5h,
+		RateLimit1d:   snapshot.RateLimit1d,
+		RateLimit7d:   snapshot.RateLimit7d,
+
+		RPMLimit:       snapshot.RPMLimit,
+		TPMLimit:       snapshot.TPMLimit,
+		MaxConcurrency: snapshot.MaxConcurrency,
+		OrganizationID: snapshot.OrganizationID,
 		User: &User{
 			ID:          snapshot.User.ID,
 			Status:      snapshot.User.Status,
@@ -306,6 +332,18 @@ func (s *APIKeyService) snapshotToAPIKey(key string, snapshot *APIKeyAuthSnapsho
 			SupportedModelScopes:            snapshot.Group.SupportedModelScopes,
 			AllowMessagesDispatch:           snapshot.Group.AllowMessagesDispatch,
 			DefaultMappedModel:              snapshot.Group.DefaultMappedModel,
+			ResponseCacheEnabled:            snapshot.Group.ResponseCacheEnabled,
+			ResponseCacheTTLSeconds:         snapshot.Group.ResponseCacheTTLSeconds,
+			ResponseCachePriceRatio:         snapshot.Group.ResponseCachePriceRatio,
+			ModelFallbackChains:             snapshot.Group.ModelFallbackChains,
+		}
+	}
+	if snapshot.Organization != nil {
+		apiKey.Organization = &Organization{
+			ID:          snapshot.Organization.ID,
+			OwnerUserID: snapshot.Organization.OwnerUserID,
+			Status:      snapshot.Organization.Status,
+			Balance:     snapshot.Organization.Balance,
 		}
 	}
 	s.compileAPIKeyIPRules(apiKey)
@@ -0,0 +1,36 @@
+package service
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+)
+
+// apiKeyDisplayPrefixMaxLen 展示前缀的最大长度；同时不超过 Key 长度的一半，避免短自定义 Key 泄露过多
+const apiKeyDisplayPrefixMaxLen = 10
+
+// HashAPIKey 计算 API Key 的存储哈希（HMAC-SHA256，十六进制）。
+// 数据库只保存该哈希，认证时对请求携带的 Key 做同样计算后按哈希查找。
+func HashAPIKey(secret, key string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(key))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// APIKeyDisplayPrefix 返回用于列表展示与搜索的 Key 前缀
+func APIKeyDisplayPrefix(key string) string {
+	runes := []rune(key)
+	n := apiKeyDisplayPrefixMaxLen
+	if half := len(runes) / 2; half < n {
+		n = half
+	}
+	return string(runes[:n])
+}
+
+func (s *APIKeyService) hashKey(key string) string {
+	secret := ""
+	if s.cfg != nil {
+		secret = s.cfg.Security.APIKeyHashSecret
+	}
+	return HashAPIKey(secret, key)
+}
@@ -0,0 +1,221 @@
+package service
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// API Key 模型策略
+//
+// 执行顺序（认证中间件内、账号调度之前）：
+//  1. 别名解析：请求模型命中 ModelAliases 时改写为真实模型名
+//  2. 黑名单：真实模型命中 DeniedModels 任一 pattern → 拒绝
+//  3. 白名单：AllowedModels 非空且真实模型未命中任何 pattern → 拒绝
+//
+// pattern 支持任意位置的 * 通配符（如 "claude-*-haiku*"），匹配不区分大小写。
+
+const (
+	apiKeyMaxModelPatterns  = 100
+	apiKeyMaxModelAliases   = 100
+	apiKeyMaxModelNameLen   = 128
+	apiKeyModelWildcardChar = '*'
+)
+
+var (
+	ErrInvalidModelPattern = infraerrors.BadRequest("INVALID_MODEL_PATTERN", "invalid model pattern")
+	ErrInvalidModelAlias   = infraerrors.BadRequest("INVALID_MODEL_ALIAS", "invalid model alias")
+)
+
+// HasModelPolicy 是否配置了任何模型策略（未配置时认证热路径无需读取请求体）
+func (k *APIKey) HasModelPolicy() bool {
+	return k != nil && (len(k.AllowedModels) > 0 || len(k.DeniedModels) > 0 || len(k.ModelAliases) > 0)
+}
+
+// ResolveModelAlias 返回别名对应的真实模型名；未命中别名时原样返回
+func (k *APIKey) ResolveModelAlias(model string) string {
+	if k == nil || len(k.ModelAliases) == 0 {
+		return model
+	}
+	if target, ok := k.ModelAliases[model]; ok && target != "" {
+		return target
+	}
+	return model
+}
+
+// IsModelAllowed 检查（别名解析后的）真实模型是否允许被该 Key 调用
+func (k *APIKey) IsModelAllowed(model string) bool {
+	if k == nil {
+		return true
+	}
+	for _, pattern := range k.DeniedModels {
+		if matchModelGlob(pattern, model) {
+			return false
+		}
+	}
+	if len(k.AllowedModels) == 0 {
+		return true
+	}
+	for _, pattern := range k.AllowedModels {
+		if matchModelGlob(pattern, model) {
+			return true
+		}
+	}
+	return false
+}
+
+// VisibleModelAliases 返回目标模型可用的别名（按名称排序），用于 /v1/models 展示
+func (k *APIKey) VisibleModelAliases() []string {
+	if k == nil || len(k.ModelAliases) == 0 {
+		return nil
+	}
+	aliases := make([]string, 0, len(k.ModelAliases))
+	for alias, target := range k.ModelAliases {
+		if k.IsModelAllowed(target) {
+			aliases = append(aliases, alias)
+		}
+	}
+	sort.Strings(aliases)
+	return aliases
+}
+
+// matchModelGlob 通配符匹配（* 可出现在任意位置，不区分大小写）
+func matchModelGlob(pattern, model string) bool {
+	p := strings.ToLower(pattern)
+	s := strings.ToLower(model)
+
+	// 经典贪心回溯：记录最近一次 * 的位置，失配时让 * 多吞一个字符
+	pi, si := 0, 0
+	starIdx, matchIdx := -1, 0
+	for si < len(s) {
+		switch {
+		case pi < len(p) && p[pi] == apiKeyModelWildcardChar:
+			starIdx = pi
+			matchIdx = si
+			pi++
+		case pi < len(p) && p[pi] == s[si]:
+			pi++
+			si++
+		case starIdx >= 0:
+			pi = starIdx + 1
+			matchIdx++
+			si = matchIdx
+		default:
+			return false
+		}
+	}
+	for pi < len(p) && p[pi] == apiKeyModelWildcardChar {
+		pi++
+	}
+	return pi == len(p)
+}
+
+// normalizeModelPatterns 去除空白与重复项，并校验 pattern 合法性
+func normalizeModelPatterns(patterns []string) ([]string, error) {
+	if len(patterns) == 0 {
+		return nil, nil
+	}
+	if len(patterns) > apiKeyMaxModelPatterns {
+		return nil, fmt.Errorf("%w: at most %d patterns allowed", ErrInvalidModelPattern, apiKeyMaxModelPatterns)
+	}
+	out := make([]string, 0, len(patterns))
+	seen := make(map[string]struct{}, len(patterns))
+	for _, raw := range patterns {
+		pattern := strings.TrimSpace(raw)
+		if pattern == "" {
+			continue
+		}
+		if err := validateModelName(pattern); err != nil {
+			return nil, fmt.Errorf("%w: %q %s", ErrInvalidModelPattern, pattern, err.Error())
+		}
+		key := strings.ToLower(pattern)
+		if _, ok := seen[key]; ok {
+			continue
+		}
+		seen[key] = struct{}{}
+		out = append(out, pattern)
+	}
+	if len(out) == 0 {
+		return nil, nil
+	}
+	return out, nil
+}
+
+// normalizeModelAliases 去除空白并校验别名映射（别名不允许通配符，也不允许指向自身）
+func normalizeModelAliases(aliases map[string]string) (map[string]string, error) {
+	if len(aliases) == 0 {
+		return nil, nil
+	}
+	if len(aliases) > apiKeyMaxModelAliases {
+		return nil, fmt.Errorf("%w: at most %d aliases allowed", ErrInvalidModelAlias, apiKeyMaxModelAliases)
+	}
+	out := make(map[string]string, len(aliases))
+	for rawAlias, rawTarget := range aliases {
+		alias := strings.TrimSpace(rawAlias)
+		target := strings.TrimSpace(rawTarget)
+		if alias == "" || target == "" {
+			return nil, fmt.Errorf("%w: alias and target model must not be empty", ErrInvalidModelAlias)
+		}
+		if strings.ContainsRune(alias, apiKeyModelWildcardChar) || strings.ContainsRune(target, apiKeyModelWildcardChar) {
+			return nil, fmt.Errorf("%w: %q must not contain wildcards", ErrInvalidModelAlias, alias)
+		}
+		if err := validateModelName(alias); err != nil {
+			return nil, fmt.Errorf("%w: %q %s", ErrInvalidModelAlias, alias, err.Error())
+		}
+		if err := validateModelName(target); err != nil {
+			return nil, fmt.Errorf("%w: %q %s", ErrInvalidModelAlias, target, err.Error())
+		}
+		if alias == target {
+			return nil, fmt.Errorf("%w: %q must not map to itself", ErrInvalidModelAlias, alias)
+		}
+		out[alias] = target
+	}
+	return out, nil
+}
+
+func validateModelName(name string) error {
+	if len(name) > apiKeyMaxModelNameLen {
+		return fmt.Errorf("exceeds %d characters", apiKeyMaxModelNameLen)
+	}
+	if strings.ContainsAny(name, " \t\r\n\"") {
+		return fmt.Errorf("contains invalid characters")
+	}
+	return nil
+}
+
+// APIKeyModelPolicyUpdate 模型策略更新（字段为 nil 表示不修改，空数组/对象表示清空）
+type APIKeyModelPolicyUpdate struct {
+	AllowedModels *[]string
+	DeniedModels  *[]string
+	ModelAliases  *map[string]string
+}
+
+// IsEmpty 是否没有任何需要更新的字段
+func (u APIKeyModelPolicyUpdate) IsEmpty() bool {
+	return u.AllowedModels == nil && u.DeniedModels == nil && u.ModelAliases == nil
+}
+
+// applyTo 校验并写入 Key；任一字段非法时不修改 Key
+func (u APIKeyModelPolicyUpdate) applyTo(k *APIKey) error {
+	allowed, denied, aliases := k.AllowedModels, k.DeniedModels, k.ModelAliases
+	var err error
+	if u.AllowedModels != nil {
+		if allowed, err = normalizeModelPatterns(*u.AllowedModels); err != nil {
+			return err
+		}
+	}
+	if u.DeniedModels != nil {
+		if denied, err = normalizeModelPatterns(*u.DeniedModels); err != nil {
+			return err
+		}
+	}
+	if u.ModelAliases != nil {
+		if aliases, err = normalizeModelAliases(*u.ModelAliases); err != nil {
+			return err
+		}
+	}
+	k.AllowedModels, k.DeniedModels, k.ModelAliases = allowed, denied, aliases
+	return nil
+}
@@ -0,0 +1,120 @@
+//go:build unit
+
+package service
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestMatchModelGlob(t *testing.T) {
+	tests := []struct {
+		pattern string
+		model   string
+		want    bool
+	}{
+		{"claude-sonnet-4-5", "claude-sonnet-4-5", true},
+		{"claude-sonnet-4-5", "CLAUDE-SONNET-4-5", true},
+		{"claude-*", "claude-opus-4-1", true},
+		{"*-haiku-*", "claude-3-5-haiku-20241022", true},
+		{"claude-*-haiku*", "claude-3-5-haiku-20241022", true},
+		{"claude-*-haiku*", "claude-sonnet-4-5", false},
+		{"gpt-5*", "gpt-4o", false},
+		{"*", "anything", true},
+		{"gpt-5", "gpt-5-mini", false},
+	}
+	for _, tt := range tests {
+		require.Equal(t, tt.want, matchModelGlob(tt.pattern, tt.model), "%s vs %s", tt.pattern, tt.model)
+	}
+}
+
+func TestAPIKeyIsModelAllowed(t *testing.T) {
+	var nilKey *APIKey
+	require.True(t, nilKey.IsModelAllowed("claude-opus-4-1"))
+	require.False(t, nilKey.HasModelPolicy())
+
+	key := &APIKey{}
+	require.True(t, key.IsModelAllowed("claude-opus-4-1"))
+
+	key.DeniedModels = []string{"claude-opus-*"}
+	require.False(t, key.IsModelAllowed("claude-opus-4-1"))
+	require.True(t, key.IsModelAllowed("claude-sonnet-4-5"))
+
+	key.AllowedModels = []string{"claude-*"}
+	require.False(t, key.IsModelAllowed("claude-opus-4-1"), "denylist must win over allowlist")
+	require.True(t, key.IsModelAllowed("claude-sonnet-4-5"))
+	require.False(t, key.IsModelAllowed("gpt-5"))
+}
+
+func TestAPIKeyModelAliases(t *testing.T) {
+	key := &APIKey{
+		AllowedModels: []string{"claude-sonnet-*"},
+		ModelAliases: map[string]string{
+			"smart": "claude-sonnet-4-5",
+			"big":   "claude-opus-4-1",
+		},
+	}
+	require.True(t, key.HasModelPolicy())
+	require.Equal(t, "claude-sonnet-4-5", key.ResolveModelAlias("smart"))
+	require.Equal(t, "gpt-5", key.ResolveModelAlias("gpt-5"))
+	require.Equal(t, []string{"smart"}, key.VisibleModelAliases())
+}
+
+func TestNormalizeModelPatterns(t *testing.T) {
+	out, err := normalizeModelPatterns([]string{" claude-* ", "", "CLAUDE-*", "gpt-5"})
+	require.NoError(t, err)
+	require.Equal(t, []string{"claude-*", "gpt-5"}, out)
+
+	out, err = normalizeModelPatterns([]string{" ", ""})
+	require.NoError(t, err)
+	require.Nil(t, out)
+
+	_, err = normalizeModelPatterns([]string{"claude sonnet"})
+	require.ErrorIs(t, err, ErrInvalidModelPattern)
+
+	_, err = normalizeModelPatterns([]string{strings.Repeat("a", apiKeyMaxModelNameLen+1)})
+	require.ErrorIs(t, err, ErrInvalidModelPattern)
+}
+
+func TestNormalizeModelAliases(t *testing.T) {
+	out, err := normalizeModelAliases(map[string]string{" fast ": " claude-3-5-haiku-20241022 "})
+	require.NoError(t, err)
+	require.Equal(t, map[string]string{"fast": "claude-3-5-haiku-20241022"}, out)
+
+	for _, invalid := range []map[string]string{
+		{"fast": ""},
+		{"fast*": "claude-3-5-haiku-20241022"},
+		{"fast": "claude-*"},
+		{"same": "same"},
+	} {
+		_, err := normalizeModelAliases(invalid)
+		require.ErrorIs(t, err, ErrInvalidModelAlias, "%v", invalid)
+	}
+}
+
+func TestAPIKeyModelPolicyUpdateApplyTo(t *testing.T) {
+	key := &APIKey{
+		AllowedModels: []string{"claude-*"},
+		DeniedModels:  []string{"claude-opus-*"},
+	}
+
+	require.True(t, APIKeyModelPolicyUpdate{}.IsEmpty())
+
+	// nil 字段不修改，空数组清空
+	empty := []string{}
+	aliases := map[string]string{"fast": "claude-3-5-haiku-20241022"}
+	require.NoError(t, APIKeyModelPolicyUpdate{DeniedModels: &empty, ModelAliases: &aliases}.applyTo(key))
+	require.Equal(t, []string{"claude-*"}, key.AllowedModels)
+	require.Nil(t, key.DeniedModels)
+	require.Equal(t, aliases, key.ModelAliases)
+
+	// 任一字段非法时整体不生效
+	allowed := []string{"gpt-5"}
+	badAliases := map[string]string{"x": ""}
+	err := APIKeyModelPolicyUpdate{AllowedModels: &allowed, ModelAliases: &badAliases}.applyTo(key)
+	require.ErrorIs(t, err, ErrInvalidModelAlias)
+	require.Equal(t, []string{"claude-*"}, key.AllowedModels)
+	require.Equal(t, aliases, key.ModelAliases)
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"strconv"
 	"sync"
@@ -47,24 +48,24 @@ const (
 type APIKeyRepository interface {
 	Create(ctx context.Context, key *APIKey) error
 	GetByID(ctx context.Context, id int64) (*APIKey, error)
-	// GetKeyAndOwnerID 仅获取 API Key 的 key 与所有者 ID，用于删除等轻量场景
-	GetKeyAndOwnerID(ctx context.Context, id int64) (string, int64, error)
-	GetByKey(ctx context.Context, key string) (*APIKey, error)
-	// GetByKeyForAuth 认证专用查询，返回最小字段集
-	GetByKeyForAuth(ctx context.Context, key string) (*APIKey, error)
+	// GetKeyHashAndOwnerID 仅获取 API Key 的哈希与所有者 ID，用于删除等轻量场景
+	GetKeyHashAndOwnerID(ctx context.Context, id int64) (string, int64, error)
+	GetByKeyHash(ctx context.Context, keyHash string) (*APIKey, error)
+	// GetByKeyHashForAuth 认证专用查询，返回最小字段集
+	GetByKeyHashForAuth(ctx context.Context, keyHash string) (*APIKey, error)
 	Update(ctx context.Context, key *APIKey) error
 	Delete(ctx context.Context, id int64) error
 
 	ListByUserID(ctx context.Context, userID int64, params pagination.PaginationParams, filters APIKeyListFilters) ([]APIKey, *pagination.PaginationResult, error)
 	VerifyOwnership(ctx context.Context, userID int64, apiKeyIDs []int64) ([]int64, error)
 	CountByUserID(ctx context.Context, userID int64) (int64, error)
-	ExistsByKey(ctx context.Context, key string) (bool, error)
+	ExistsByKeyHash(ctx context.Context, keyHash string) (bool, error)
 	ListByGroupID(ctx context.Context, groupID int64, params pagination.PaginationParams) ([]APIKey, *pagination.PaginationResult, error)
 	SearchAPIKeys(ctx context.Context, userID int64, keyword string, limit int) ([]APIKey, error)
 	ClearGroupIDByGroupID(ctx context.Context, groupID int64) (int64, error)
 	CountByGroupID(ctx context.Context, groupID int64) (int64, error)
-	ListKeysByUserID(ctx context.Context, userID int64) ([]string, error)
-	ListKeysByGroupID(ctx context.Context, groupID int64) ([]string, error)
+	ListKeyHashesByUserID(ctx context.Context, userID int64) ([]string, error)
+	ListKeyHashesByGroupID(ctx context.Context, groupID int64) ([]string, error)
 
 	// Quota methods
 	IncrementQuotaUsed(ctx context.Context, id int64, amount float64) (float64, error)
@@ -130,7 +131,7 @@ type APIKeyCache interface {
 
 // APIKeyAuthCacheInvalidator 提供认证缓存失效能力
 type APIKeyAuthCacheInvalidator interface {
-	InvalidateAuthCacheByKey(ctx context.Context, key string)
+	InvalidateAuthCacheByKeyHash(ctx context.Context, keyHash string)
 	InvalidateAuthCacheByUserID(ctx context.Context, userID int64)
 	InvalidateAuthCacheByGroupID(ctx context.Context, groupID int64)
 }
@@ -143,6 +144,11 @@ type CreateAPIKeyRequest struct {
 	IPWhitelist []string `json:"ip_whitelist"` // IP 白名单
 	IPBlacklist []string `json:"ip_blacklist"` // IP 黑名单
 
+	// Model policy fields (empty = no restriction)
+	AllowedModels []string          `json:"allowed_models"`
+	DeniedModels  []string          `json:"denied_models"`
+	ModelAliases  map[string]string `json:"model_aliases"`
+
 	// Quota fields
 	Quota         float64 `json:"quota"`           // Quota limit in USD (0 = unlimited)
 	ExpiresInDays *int    `json:"expires_in_days"` // Days until expiry (nil = never expires)
@@ -151,6 +157,14 @@ type CreateAPIKeyRequest struct {
 	RateLimit5h float64 `json:"rate_limit_5h"`
 	RateLimit1d float64 `json:"rate_limit_1d"`
 	RateLimit7d float64 `json:"rate_limit_7d"`
+
+	// Throughput limit fields (0 = unlimited)
+	RPMLimit       int `json:"rpm_limit"`
+	TPMLimit       int `json:"tpm_limit"`
+	MaxConcurrency int `json:"max_concurrency"`
+
+	// 组织 Key：用量从组织余额扣费（nil = 个人 Key）
+	OrganizationID *int64 `json:"organization_id"`
 }
 
 // UpdateAPIKeyRequest 更新API Key请求
@@ -161,6 +175,11 @@ type UpdateAPIKeyRequest struct {
 	IPWhitelist []string `json:"ip_whitelist"` // IP 白名单（空数组清空）
 	IPBlacklist []string `json:"ip_blacklist"` // IP 黑名单（空数组清空）
 
+	// Model policy fields (nil = no change, empty = clear)
+	AllowedModels *[]string          `json:"allowed_models"`
+	DeniedModels  *[]string          `json:"denied_models"`
+	ModelAliases  *map[string]string `json:"model_aliases"`
+
 	// Quota fields
 	Quota           *float64   `json:"quota"`       // Quota limit in USD (nil = no change, 0 = unlimited)
 	ExpiresAt       *time.Time `json:"expires_at"`  // Expiration time (nil = no change)
@@ -172,6 +191,11 @@ type UpdateAPIKeyRequest struct {
 	RateLimit1d         *float64 `json:"rate_limit_1d"`
 	RateLimit7d         *float64 `json:"rate_limit_7d"`
 	ResetRateLimitUsage *bool    `json:"reset_rate_limit_usage"` // Reset all usage counters to 0
+
+	// Throughput limit fields (nil = no change, 0 = unlimited)
+	RPMLimit       *int `json:"rpm_limit"`
+	TPMLimit       *int `json:"tpm_limit"`
+	MaxConcurrency *int `json:"max_concurrency"`
 }
 
 // APIKeyService API Key服务
@@ -188,6 +212,8 @@ type APIKeyService struct {
 	userGroupRateRepo     UserGroupRateRepository
 	cache                 APIKeyCache
 	rateLimitCacheInvalid RateLimitCacheInvalidator // optional: invalidate Redis rate limit cache
+	throttle              *APIKeyThrottleService    // optional: per-key RPM/TPM/concurrency counters
+	orgRepo               OrganizationRepository    // optional: organization keys
 	cfg                   *config.Config
 	authCacheL1           *ristretto.Cache
 	authCfg               apiKeyAuthCacheConfig
@@ -225,6 +251,18 @@ func (s *APIKeyService) SetRateLimitCacheInvalidator(inv RateLimitCacheInvalidat
 	s.rateLimitCacheInvalid = inv
 }
 
+// SetThrottleService sets the optional per-key throughput limiter used for TPM accounting.
+// Called after construction (e.g. in wire) to avoid circular dependencies.
+func (s *APIKeyService) SetThrottleService(throttle *APIKeyThrottleService) {
+	s.throttle = throttle
+}
+
+// SetOrganizationRepository sets the optional organization repository used for organization keys.
+// Called after construction (e.g. in wire) to avoid circular dependencies.
+func (s *APIKeyService) SetOrganizationRepository(repo OrganizationRepository) {
+	s.orgRepo = repo
+}
+
 func (s *APIKeyService) compileAPIKeyIPRules(apiKey *APIKey) {
 	if apiKey == nil {
 		return
@@ -313,6 +351,37 @@ func (s *APIKeyService) canUserBindGroup(ctx context.Context, user *User, group
 	return user.CanBindGroup(group.ID, group.IsExclusive)
 }
 
+// canKeyBindGroup 检查 Key 是否可以绑定指定分组
+// 组织 Key 的订阅类型分组使用组织 owner 名下的订阅
+func (s *APIKeyService) canKeyBindGroup(ctx context.Context, user *User, group *Group, org *Organization) bool {
+	if org != nil && group.IsSubscriptionType() {
+		_, err := s.userSubRepo.GetActiveByUserIDAndGroupID(ctx, org.OwnerUserID, group.ID)
+		return err == nil
+	}
+	return s.canUserBindGroup(ctx, user, group)
+}
+
+// resolveKeyOrganization 校验用户是可用组织的成员，返回组织
+func (s *APIKeyService) resolveKeyOrganization(ctx context.Context, userID, orgID int64) (*Organization, error) {
+	if s.orgRepo == nil {
+		return nil, ErrOrganizationNotFound
+	}
+	if _, err := s.orgRepo.GetMember(ctx, orgID, userID); err != nil {
+		if errors.Is(err, ErrOrganizationMemberNotFound) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, fmt.Errorf("get organization member: %w", err)
+	}
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !org.IsActive() {
+		return nil, ErrOrganizationDisabled
+	}
+	return org, nil
+}
+
 // Create 创建API Key
 func (s *APIKeyService) Create(ctx context.Context, userID int64, req CreateAPIKeyRequest) (*APIKey, error) {
 	// 验证用户存在
@@ -335,6 +404,29 @@ func (s *APIKeyService) Create(ctx context.Context, userID int64, req CreateAPIK
 		}
 	}
 
+	// 验证模型策略
+	allowedModels, err := normalizeModelPatterns(req.AllowedModels)
+	if err != nil {
+		return nil, err
+	}
+	deniedModels, err := normalizeModelPatterns(req.DeniedModels)
+	if err != nil {
+		return nil, err
+	}
+	modelAliases, err := normalizeModelAliases(req.ModelAliases)
+	if err != nil {
+		return nil, err
+	}
+
+	// 验证组织成员身份（组织 Key）
+	var org *Organization
+	if req.OrganizationID != nil {
+		org, err = s.resolveKeyOrganization(ctx, userID, *req.OrganizationID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
 	// 验证分组权限（如果指定了分组）
 	if req.GroupID != nil {
 		group, err := s.groupRepo.GetByID(ctx, *req.GroupID)
@@ -343,7 +435,7 @@ func (s *APIKeyService) Create(ctx context.Context, userID int64, req CreateAPIK
 		}
 
 		// 检查用户是否可以绑定该分组
-		if !s.canUserBindGroup(ctx, user, group) {
+		if !s.canKeyBindGroup(ctx, user, group, org) {
 			return nil, ErrGroupNotAllowed
 		}
 	}
@@ -363,7 +455,7 @@ func (s *APIKeyService) Create(ctx context.Context, userID int64, req CreateAPIK
 		}
 
 		// 检查Key是否已存在
-		exists, err := s.apiKeyRepo.ExistsByKey(ctx, *req.CustomKey)
+		exists, err := s.apiKeyRepo.ExistsByKeyHash(ctx, s.hashKey(*req.CustomKey))
 		if err != nil {
 			return nil, fmt.Errorf("check key exists: %w", err)
 		}
@@ -383,20 +475,34 @@ func (s *APIKeyService) Create(ctx context.Context, userID int64, req CreateAPIK
 		}
 	}
 
-	// 创建API Key记录
+	// 创建API Key记录（仅落库哈希与前缀，明文随本次响应返回一次）
 	apiKey := &APIKey{
-		UserID:      userID,
-		Key:         key,
-		Name:        req.Name,
-		GroupID:     req.GroupID,
-		Status:      StatusActive,
-		IPWhitelist: req.IPWhitelist,
-		IPBlacklist: req.IPBlacklist,
-		Quota:       req.Quota,
-		QuotaUsed:   0,
-		RateLimit5h: req.RateLimit5h,
-		RateLimit1d: req.RateLimit1d,
-		RateLimit7d: req.RateLimit7d,
+		UserID:        userID,
+		Key:           key,
+		KeyHash:       s.hashKey(key),
+		KeyPrefix:     APIKeyDisplayPrefix(key),
+		Name:          req.Name,
+		GroupID:       req.GroupID,
+		Status:        StatusActive,
+		IPWhitelist:   req.IPWhitelist,
+		IPBlacklist:   req.IPBlacklist,
+		AllowedModels: allowedModels,
+		DeniedModels:  deniedModels,
+		ModelAliases:  modelAliases,
+		Quota:         req.Quota,
+		QuotaUsed:     0,
+		RateLimit5h:   req.RateLimit5h,
+		RateLimit1d:   req.RateLimit1d,
+		RateLimit7d:   req.RateLimit7d,
+
+		RPMLimit:       req.RPMLimit,
+		TPMLimit:       req.TPMLimit,
+		MaxConcurrency: req.MaxConcurrency,
+
+		OrganizationID: req.OrganizationID,
+	}
+	if !apiKey.validThroughputLimits() {
+		return nil, ErrAPIKeyInvalidThroughputLimit
 	}
 
 	// Set expiration time if specified
@@ -409,7 +515,7 @@ func (s *APIKeyService) Create(ctx context.Context, userID int64, req CreateAPIK
 		return nil, fmt.Errorf("create api key: %w", err)
 	}
 
-	s.InvalidateAuthCacheByKey(ctx, apiKey.Key)
+	s.InvalidateAuthCacheByKeyHash(ctx, apiKey.KeyHash)
 	s.compileAPIKeyIPRules(apiKey)
 
 	return apiKey, nil
@@ -489,7 +595,7 @@ func (s *APIKeyService) GetByKey(ctx context.Context, key string) (*APIKey, erro
 		}
 	}
 
-	apiKey, err := s.apiKeyRepo.GetByKeyForAuth(ctx, key)
+	apiKey, err := s.apiKeyRepo.GetByKeyHashForAuth(ctx, cacheKey)
 	if err != nil {
 		return nil, fmt.Errorf("get api key: %w", err)
 	}
@@ -541,7 +647,15 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 			return nil, fmt.Errorf("get group: %w", err)
 		}
 
-		if !s.canUserBindGroup(ctx, user, group) {
+		var org *Organization
+		if apiKey.OrganizationID != nil && s.orgRepo != nil {
+			org, err = s.orgRepo.GetByID(ctx, *apiKey.OrganizationID)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		if !s.canKeyBindGroup(ctx, user, group, org) {
 			return nil, ErrGroupNotAllowed
 		}
 
@@ -589,6 +703,16 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 	apiKey.IPWhitelist = req.IPWhitelist
 	apiKey.IPBlacklist = req.IPBlacklist
 
+	// 更新模型策略（nil 不修改，空值清空）
+	policyUpdate := APIKeyModelPolicyUpdate{
+		AllowedModels: req.AllowedModels,
+		DeniedModels:  req.DeniedModels,
+		ModelAliases:  req.ModelAliases,
+	}
+	if err := policyUpdate.applyTo(apiKey); err != nil {
+		return nil, err
+	}
+
 	// Update rate limit configuration
 	if req.RateLimit5h != nil {
 		apiKey.RateLimit5h = *req.RateLimit5h
@@ -599,6 +723,18 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 	if req.RateLimit7d != nil {
 		apiKey.RateLimit7d = *req.RateLimit7d
 	}
+	if req.RPMLimit != nil {
+		apiKey.RPMLimit = *req.RPMLimit
+	}
+	if req.TPMLimit != nil {
+		apiKey.TPMLimit = *req.TPMLimit
+	}
+	if req.MaxConcurrency != nil {
+		apiKey.MaxConcurrency = *req.MaxConcurrency
+	}
+	if !apiKey.validThroughputLimits() {
+		return nil, ErrAPIKeyInvalidThroughputLimit
+	}
 	resetRateLimit := req.ResetRateLimitUsage != nil && *req.ResetRateLimitUsage
 	if resetRateLimit {
 		apiKey.Usage5h = 0
@@ -613,7 +749,7 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 		return nil, fmt.Errorf("update api key: %w", err)
 	}
 
-	s.InvalidateAuthCacheByKey(ctx, apiKey.Key)
+	s.InvalidateAuthCacheByKeyHash(ctx, apiKey.KeyHash)
 	s.compileAPIKeyIPRules(apiKey)
 
 	// Invalidate Redis rate limit cache so reset takes effect immediately
@@ -626,7 +762,7 @@ func (s *APIKeyService) Update(ctx context.Context, id int64, userID int64, req
 
 // Delete 删除API Key
 func (s *APIKeyService) Delete(ctx context.Context, id int64, userID int64) error {
-	key, ownerID, err := s.apiKeyRepo.GetKeyAndOwnerID(ctx, id)
+	keyHash, ownerID, err := s.apiKeyRepo.GetKeyHashAndOwnerID(ctx, id)
 	if err != nil {
 		return fmt.Errorf("get api key: %w", err)
 	}
@@ -640,7 +776,7 @@ func (s *APIKeyService) Delete(ctx context.Context, id int64, userID int64) erro
 	if s.cache != nil {
 		_ = s.cache.DeleteCreateAttemptCount(ctx, userID)
 	}
-	s.InvalidateAuthCacheByKey(ctx, key)
+	s.InvalidateAuthCacheByKeyHash(ctx, keyHash)
 
 	if err := s.apiKeyRepo.Delete(ctx, id); err != nil {
 		return fmt.Errorf("delete api key: %w", err)
@@ -836,7 +972,7 @@ func (s *APIKeyService) UpdateQuotaUsed(ctx context.Context, apiKeyID int64, cos
 			return nil // Don't fail the request
 		}
 		// Invalidate cache so next request sees the new status
-		s.InvalidateAuthCacheByKey(ctx, apiKey.Key)
+		s.InvalidateAuthCacheByKeyHash(ctx, apiKey.KeyHash)
 	}
 
 	return nil
@@ -854,3 +990,8 @@ func (s *APIKeyService) UpdateRateLimitUsage(ctx context.Context, apiKeyID int64
 	}
 	return s.apiKeyRepo.IncrementRateLimitUsage(ctx, apiKeyID, cost)
 }
+
+// RecordTokenUsage accumulates per-minute token usage for keys with a TPM limit.
+func (s *APIKeyService) RecordTokenUsage(ctx context.Context, apiKey *APIKey, tokens int) {
+	s.throttle.RecordTokenUsage(ctx, apiKey, tokens)
+}
@@ -17,9 +17,9 @@ import (
 )
 
 type authRepoStub struct {
-	getByKeyForAuth   func(ctx context.Context, key string) (*APIKey, error)
-	listKeysByUserID  func(ctx context.Context, userID int64) ([]string, error)
-	listKeysByGroupID func(ctx context.Context, groupID int64) ([]string, error)
+	getByKeyHashForAuth    func(ctx context.Context, keyHash string) (*APIKey, error)
+	listKeyHashesByUserID  func(ctx context.Context, userID int64) ([]string, error)
+	listKeyHashesByGroupID func(ctx context.Context, groupID int64) ([]string, error)
 }
 
 func (s *authRepoStub) Create(ctx context.Context, key *APIKey) error {
@@ -30,19 +30,19 @@ func (s *authRepoStub) GetByID(ctx context.Context, id int64) (*APIKey, error) {
 	panic("unexpected GetByID call")
 }
 
-func (s *authRepoStub) GetKeyAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
-	panic("unexpected GetKeyAndOwnerID call")
+func (s *authRepoStub) GetKeyHashAndOwnerID(ctx context.Context, id int64) (string, int64, error) {
+	panic("unexpected GetKeyHashAndOwnerID call")
 }
 
-func (s *authRepoStub) GetByKey(ctx context.Context, key string) (*APIKey, error) {
-	panic("unexpected GetByKey call")
+func (s *authRepoStub) GetByKeyHash(ctx context.Context, keyHash string) (*APIKey, error) {
+	panic("unexpected GetByKeyHash call")
 }
 
-func (s *authRepoStub) GetByKeyForAuth(ctx context.Context, key string) (*APIKey, error) {
-	if s.getByKeyForAuth == nil {
-		panic("unexpected GetByKeyForAuth call")
+func (s *authRepoStub) GetByKeyHashForAuth(ctx context.Context, keyHash string) (*APIKey, error) {
+	if s.getByKeyHashForAuth == nil {
+		panic("unexpected GetByKeyHashForAuth call")
 	}
-	return s.getByKeyForAuth(ctx, key)
+	return s.getByKeyHashForAuth(ctx, keyHash)
 }
 
 func (s *authRepoStub) Update(ctx context.Context, key *APIKey) error {
@@ -65,8 +65,8 @@ func (s *authRepoStub) CountByUserID(ctx context.Context, userID int64) (int64,
 	panic("unexpected CountByUserID call")
 }
 
-func (s *authRepoStub) ExistsByKey(ctx context.Context, key string) (bool, error) {
-	panic("unexpected ExistsByKey call")
+func (s *authRepoStub) ExistsByKeyHash(ctx context.Context, keyHash string) (bool, error) {
+	panic("unexpected ExistsByKeyHash call")
 }
 
 func (s *authRepoStub) ListByGroupID(ctx context.Context, groupID int64, params pagination.PaginationParams) ([]APIKey, *pagination.PaginationResult, error) {
@@ -85,18 +85,18 @@ func (s *authRepoStub) CountByGroupID(ctx context.Context, groupID int64) (int64
 	panic("unexpected CountByGroupID call")
 }
 
-func (s *authRepoStub) ListKeysByUserID(ctx context.Context, userID int64) ([]string, error) {
-	if s.listKeysByUserID == nil {
-		panic("unexpected ListKeysByUserID call")
+func (s *authRepoStub) ListKeyHashesByUserID(ctx context.Context, userID int64) ([]string, error) {
+	if s.listKeyHashesByUserID == nil {
+		panic("unexpected ListKeyHashesByUserID call")
 	}
-	return s.listKeysByUserID(ctx, userID)
+	return s.listKeyHashesByUserID(ctx, userID)
 }
 
-func (s *authRepoStub) ListKeysByGroupID(ctx context.Context, groupID int64) ([]string, error) {
-	if s.listKeysByGroupID == nil {
-		panic("unexpected ListKeysByGroupID call")
+func (s *authRepoStub) ListKeyHashesByGroupID(ctx context.Context, groupID int64) ([]string, error) {
+	if s.listKeyHashesByGroupID == nil {
+		panic("unexpected ListKeyHashesByGroupID call")
 	}
-	return s.listKeysByGroupID(ctx, groupID)
+	return s.listKeyHashesByGroupID(ctx, groupID)
 }
 
 func (s *authRepoStub) IncrementQuotaUsed(ctx context.Context, id int64, amount float64) (float64, error) {
@@ -170,7 +170,7 @@ func (s *authCacheStub) SubscribeAuthCacheInvalidation(ctx context.Context, hand
 func TestAPIKeyService_GetByKey_UsesL2Cache(t *testing.T) {
 	cache := &authCacheStub{}
 	repo := &authRepoStub{
-		getByKeyForAuth: func(ctx context.Context, key string) (*APIKey, error) {
+		getByKeyHashForAuth: func(ctx context.Context, key string) (*APIKey, error) {
 			return nil, errors.New("unexpected repo call")
 		},
 	}
@@ -226,7 +226,7 @@ func TestAPIKeyService_GetByKey_UsesL2Cache(t *testing.T) {
 func TestAPIKeyService_GetByKey_NegativeCache(t *testing.T) {
 	cache := &authCacheStub{}
 	repo := &authRepoStub{
-		getByKeyForAuth: func(ctx context.Context, key string) (*APIKey, error) {
+		getByKeyHashForAuth: func(ctx context.Context, key string) (*APIKey, error) {
 			return nil, errors.New("unexpected repo call")
 		},
 	}
@@ -248,7 +248,7 @@ func TestAPIKeyService_GetByKey_NegativeCache(t *testing.T) {
 func TestAPIKeyService_GetByKey_CacheMissStoresL2(t *testing.T) {
 	cache := &authCacheStub{}
 	repo := &authRepoStub{
-		getByKeyForAuth: func(ctx context.Context, key string) (*APIKey, error) {
+		getByKeyHashForAuth: func(ctx context.Context, key string) (*APIKey, error) {
 			return &APIKey{
 				ID:     5,
 				UserID: 7,
@@ -284,7 +284,7 @@ func TestAPIKeyService_GetByKey_UsesL1Cache(t *testing.T) {
 	var calls int32
 	cache := &authCacheStub{}
 	repo := &authRepoStub{
-		getByKeyForAuth: func(ctx context.Context, key string) (*APIKey, error) {
+		getByKeyHashForAuth: func(ctx context.Context, key string) (*APIKey, error) {
 			atomic.AddInt32(&calls, 1)
 			return &APIKey{
 				ID:     21,
@@ -323,7 +323,7 @@ func TestAPIKeyService_GetByKey_UsesL1Cache(t *testing.T) {
 func TestAPIKeyService_InvalidateAuthCacheByUserID(t *testing.T) {
 	cache := &authCacheStub{}
 	repo := &authRepoStub{
-		listKeysByUserID: func(ctx context.Context, userID int64) ([]string, error) {
+		listKeyHashesByUserID: func(ctx context.Context, userID int64) ([]string, error) {
 			return []string{"k1", "k2"}, nil
 		},
 	}
@@ -342,7 +342,7 @@ func TestAPIKeyService_InvalidateAuthCacheByUserID(t *testing.T) {
 func TestAPIKeyService_InvalidateAuthCacheByGroupID(t *testing.T) {
 	cache := &authCacheStub{}
 	repo := &authRepoStub{
-		listKeysByGroupID: func(ctx context.Context, groupID int64) ([]string, error) {
+		listKeyHashesByGroupID: func(ctx context.Context, groupID int64) ([]string, error) {
 			return []string{"k1", "k2"}, nil
 		},
 	}
@@ -357,10 +357,10 @@ func TestAPIKeyService_InvalidateAuthCacheByGroupID(t *testing.T) {
 	require.Len(t, cache.deleteAuthKeys, 2)
 }
 
-func TestAPIKeyService_InvalidateAuthCacheByKey(t *testing.T) {
+func TestAPIKeyService_InvalidateAuthCacheByKeyHash(t *testing.T) {
 	cache := &authCacheStub{}
 	repo := &authRepoStub{
-		listKeysByUserID: func(ctx context.Context, userID int64) ([]string, error) {
+		listKeyHashesByUserID: func(ctx context.Context, userID int64) ([]string, error) {
 			return nil, nil
 		},
 	}
@@ -371,14 +371,51 @@ func TestAPIKeyService_InvalidateAuthCacheByKey(t *testing.T) {
 	}
 	svc := NewAPIKeyService(repo, nil, nil, nil, nil, cache, cfg)
 
-	svc.InvalidateAuthCacheByKey(context.Background(), "k1")
-	require.Len(t, cache.deleteAuthKeys, 1)
+	svc.InvalidateAuthCacheByKeyHash(context.Background(), svc.authCacheKey("k1"))
+	require.Equal(t, []string{svc.authCacheKey("k1")}, cache.deleteAuthKeys)
+}
+
+func TestAPIKeyService_GetByKey_LooksUpByHMACHash(t *testing.T) {
+	cache := &authCacheStub{}
+	var gotHash string
+	repo := &authRepoStub{
+		getByKeyHashForAuth: func(ctx context.Context, keyHash string) (*APIKey, error) {
+			gotHash = keyHash
+			return &APIKey{
+				ID:     1,
+				UserID: 2,
+				Status: StatusActive,
+				User:   &User{ID: 2, Status: StatusActive, Role: RoleUser},
+			}, nil
+		},
+	}
+	cfg := &config.Config{
+		Security:   config.SecurityConfig{APIKeyHashSecret: "hash-secret"},
+		APIKeyAuth: config.APIKeyAuthCacheConfig{L2TTLSeconds: 60},
+	}
+	svc := NewAPIKeyService(repo, nil, nil, nil, nil, cache, cfg)
+
+	apiKey, err := svc.GetByKey(context.Background(), "sk-plain")
+	require.NoError(t, err)
+	require.Equal(t, "sk-plain", apiKey.Key)
+	require.Equal(t, HashAPIKey("hash-secret", "sk-plain"), gotHash)
+	require.NotEqual(t, HashAPIKey("", "sk-plain"), gotHash)
+
+	// 认证缓存以存储哈希为键，库中读出的哈希可直接用于失效
+	svc.InvalidateAuthCacheByKeyHash(context.Background(), gotHash)
+	require.Equal(t, []string{gotHash}, cache.deleteAuthKeys)
+}
+
+func TestAPIKeyDisplayPrefix(t *testing.T) {
+	require.Equal(t, "sk-0123456", APIKeyDisplayPrefix("sk-0123456789abcdef0123456789abcdef"))
+	require.Equal(t, "custom-k", APIKeyDisplayPrefix("custom-key-16chr"))
+	require.Equal(t, "", APIKeyDisplayPrefix(""))
 }
 
 func TestAPIKeyService_GetByKey_CachesNegativeOnRepoMiss(t *testing.T) {
 	cache := &authCacheStub{}
 	repo := &authRepoStub{
-		getByKeyForAuth: func(ctx context.Context, key string) (*APIKey, error) {
+		getByKeyHashForAuth: func(ctx context.Context, key string) (*APIKey, error) {
 			return nil, ErrAPIKeyNotFound
 		},
 	}
@@ -402,7 +439,7 @@ func TestAPIKeyService_GetByKey_SingleflightCollapses(t *testing.T) {
 	var calls int32
 	cache := &authCacheStub{}
 	repo := &authRepoStub{
-		getByKeyForAuth: func(ctx context.Context, key string) (*APIKey, error) {
+		getByKeyHashForAuth: func(ctx context.Context, key string) (*APIKey, error) {
 			atomic.AddInt32(&calls, 1)
 			time.Sleep(50 * time.Millisecond)
 			return &APIKey{
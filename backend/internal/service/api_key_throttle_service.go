@@ -0,0 +1,135 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+// API Key 吞吐限制（RPM / TPM / 并发）
+//
+// 与 rate_limit_5h/1d/7d（按费用的长窗口）不同，这里限制的是单个 Key 的瞬时吞吐，
+// 避免同一用户下某个失控的 Key 占满用户级并发、饿死其他 Key：
+//   - RPM：每分钟请求数，按 Redis 服务端时间的自然分钟计数，请求进入时递增
+//   - TPM：每分钟 Token 数，请求结束记录用量时累加（input + output + cache），
+//     请求进入时若当前分钟已达上限则拒绝
+//   - 并发：同时处理中的请求数，复用并发控制的有序集合槽位，超限立即拒绝（不排队）
+//
+// Redis 不可用时放行（fail open），与用户级等待队列的处理保持一致。
+
+var ErrAPIKeyInvalidThroughputLimit = infraerrors.BadRequest("API_KEY_INVALID_THROUGHPUT_LIMIT", "rpm_limit, tpm_limit and max_concurrency must be >= 0")
+
+// apiKeyConcurrencyRetryAfter 并发超限时建议的重试间隔
+const apiKeyConcurrencyRetryAfter = time.Second
+
+// APIKeyThrottleKind 触发的限制类型
+type APIKeyThrottleKind string
+
+const (
+	APIKeyThrottleRPM         APIKeyThrottleKind = "rpm"
+	APIKeyThrottleTPM         APIKeyThrottleKind = "tpm"
+	APIKeyThrottleConcurrency APIKeyThrottleKind = "concurrency"
+)
+
+// APIKeyThrottleError API Key 吞吐超限错误（网关按协议格式输出 429）
+type APIKeyThrottleError struct {
+	Kind       APIKeyThrottleKind
+	Limit      int
+	RetryAfter time.Duration
+}
+
+func (e *APIKeyThrottleError) Error() string {
+	switch e.Kind {
+	case APIKeyThrottleRPM:
+		return fmt.Sprintf("API key rate limit exceeded: %d requests per minute", e.Limit)
+	case APIKeyThrottleTPM:
+		return fmt.Sprintf("API key rate limit exceeded: %d tokens per minute", e.Limit)
+	default:
+		return fmt.Sprintf("API key concurrency limit exceeded: %d concurrent requests", e.Limit)
+	}
+}
+
+// RetryAfterSeconds 返回 Retry-After 头的秒数（向上取整，至少 1 秒）
+func (e *APIKeyThrottleError) RetryAfterSeconds() int {
+	secs := int((e.RetryAfter + time.Second - 1) / time.Second)
+	if secs < 1 {
+		return 1
+	}
+	return secs
+}
+
+func (k *APIKey) validThroughputLimits() bool {
+	return k.RPMLimit >= 0 && k.TPMLimit >= 0 && k.MaxConcurrency >= 0
+}
+
+// APIKeyThrottleService 执行 API Key 级 RPM/TPM/并发限制
+type APIKeyThrottleService struct {
+	concurrencyService *ConcurrencyService
+	rpmCache           RPMCache
+}
+
+// NewAPIKeyThrottleService 创建 API Key 吞吐限制服务
+func NewAPIKeyThrottleService(concurrencyService *ConcurrencyService, rpmCache RPMCache) *APIKeyThrottleService {
+	return &APIKeyThrottleService{
+		concurrencyService: concurrencyService,
+		rpmCache:           rpmCache,
+	}
+}
+
+// Acquire 在请求进入时检查并占用 API Key 的吞吐配额。
+// 成功时返回的 release 必须在请求结束后调用；超限时返回 *APIKeyThrottleError。
+func (s *APIKeyThrottleService) Acquire(ctx context.Context, apiKey *APIKey) (func(), error) {
+	noop := func() {}
+	if s == nil || apiKey == nil || !apiKey.HasThroughputLimits() {
+		return noop, nil
+	}
+
+	// 1. TPM：只读检查，用量在请求结束后累加
+	if apiKey.TPMLimit > 0 && s.rpmCache != nil {
+		tokens, resetIn, err := s.rpmCache.GetAPIKeyTokens(ctx, apiKey.ID)
+		if err != nil {
+			logger.LegacyPrintf("service.api_key_throttle", "Warning: get tpm failed for api key %d: %v", apiKey.ID, err)
+		} else if tokens >= apiKey.TPMLimit {
+			return nil, &APIKeyThrottleError{Kind: APIKeyThrottleTPM, Limit: apiKey.TPMLimit, RetryAfter: resetIn}
+		}
+	}
+
+	// 2. 并发：先占槽位，避免被拒绝的请求消耗 RPM 计数
+	release := noop
+	if apiKey.MaxConcurrency > 0 && s.concurrencyService != nil {
+		result, err := s.concurrencyService.AcquireAPIKeySlot(ctx, apiKey.ID, apiKey.MaxConcurrency)
+		if err != nil {
+			logger.LegacyPrintf("service.api_key_throttle", "Warning: acquire concurrency slot failed for api key %d: %v", apiKey.ID, err)
+		} else if !result.Acquired {
+			return nil, &APIKeyThrottleError{Kind: APIKeyThrottleConcurrency, Limit: apiKey.MaxConcurrency, RetryAfter: apiKeyConcurrencyRetryAfter}
+		} else {
+			release = result.ReleaseFunc
+		}
+	}
+
+	// 3. RPM
+	if apiKey.RPMLimit > 0 && s.rpmCache != nil {
+		count, resetIn, err := s.rpmCache.IncrementAPIKeyRPM(ctx, apiKey.ID)
+		if err != nil {
+			logger.LegacyPrintf("service.api_key_throttle", "Warning: increment rpm failed for api key %d: %v", apiKey.ID, err)
+		} else if count > apiKey.RPMLimit {
+			release()
+			return nil, &APIKeyThrottleError{Kind: APIKeyThrottleRPM, Limit: apiKey.RPMLimit, RetryAfter: resetIn}
+		}
+	}
+
+	return release, nil
+}
+
+// RecordTokenUsage 累加 API Key 当前分钟的 Token 用量（仅配置了 TPM 限制的 Key）
+func (s *APIKeyThrottleService) RecordTokenUsage(ctx context.Context, apiKey *APIKey, tokens int) {
+	if s == nil || s.rpmCache == nil || apiKey == nil || apiKey.TPMLimit <= 0 || tokens <= 0 {
+		return
+	}
+	if err := s.rpmCache.AddAPIKeyTokens(ctx, apiKey.ID, tokens); err != nil {
+		logger.LegacyPrintf("service.api_key_throttle", "Warning: add tpm failed for api key %d: %v", apiKey.ID, err)
+	}
+}
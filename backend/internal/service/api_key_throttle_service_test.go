@@ -0,0 +1,148 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type throttleRPMCacheStub struct {
+	RPMCache
+
+	rpm        map[int64]int
+	tokens     map[int64]int
+	resetIn    time.Duration
+	err        error
+	addedCalls int
+}
+
+func newThrottleRPMCacheStub() *throttleRPMCacheStub {
+	return &throttleRPMCacheStub{rpm: map[int64]int{}, tokens: map[int64]int{}, resetIn: 25 * time.Second}
+}
+
+func (s *throttleRPMCacheStub) IncrementAPIKeyRPM(_ context.Context, apiKeyID int64) (int, time.Duration, error) {
+	if s.err != nil {
+		return 0, 0, s.err
+	}
+	s.rpm[apiKeyID]++
+	return s.rpm[apiKeyID], s.resetIn, nil
+}
+
+func (s *throttleRPMCacheStub) AddAPIKeyTokens(_ context.Context, apiKeyID int64, tokens int) error {
+	s.addedCalls++
+	s.tokens[apiKeyID] += tokens
+	return s.err
+}
+
+func (s *throttleRPMCacheStub) GetAPIKeyTokens(_ context.Context, apiKeyID int64) (int, time.Duration, error) {
+	if s.err != nil {
+		return 0, 0, s.err
+	}
+	return s.tokens[apiKeyID], s.resetIn, nil
+}
+
+func requireThrottleError(t *testing.T, err error, kind APIKeyThrottleKind) *APIKeyThrottleError {
+	t.Helper()
+	var throttleErr *APIKeyThrottleError
+	require.True(t, errors.As(err, &throttleErr), "expected APIKeyThrottleError, got %v", err)
+	require.Equal(t, kind, throttleErr.Kind)
+	return throttleErr
+}
+
+func TestAPIKeyThrottle_NoLimits(t *testing.T) {
+	rpm := newThrottleRPMCacheStub()
+	svc := NewAPIKeyThrottleService(NewConcurrencyService(&stubConcurrencyCacheForTest{}), rpm)
+
+	release, err := svc.Acquire(context.Background(), &APIKey{ID: 1})
+	require.NoError(t, err)
+	release()
+	require.Empty(t, rpm.rpm)
+
+	var nilSvc *APIKeyThrottleService
+	release, err = nilSvc.Acquire(context.Background(), &APIKey{ID: 1, RPMLimit: 1})
+	require.NoError(t, err)
+	release()
+}
+
+func TestAPIKeyThrottle_RPM(t *testing.T) {
+	rpm := newThrottleRPMCacheStub()
+	svc := NewAPIKeyThrottleService(nil, rpm)
+	key := &APIKey{ID: 7, RPMLimit: 2}
+
+	for i := 0; i < 2; i++ {
+		release, err := svc.Acquire(context.Background(), key)
+		require.NoError(t, err)
+		release()
+	}
+	_, err := svc.Acquire(context.Background(), key)
+	throttleErr := requireThrottleError(t, err, APIKeyThrottleRPM)
+	require.Equal(t, 2, throttleErr.Limit)
+	require.Equal(t, 25, throttleErr.RetryAfterSeconds())
+	require.Contains(t, throttleErr.Error(), "2 requests per minute")
+}
+
+func TestAPIKeyThrottle_TPM(t *testing.T) {
+	rpm := newThrottleRPMCacheStub()
+	svc := NewAPIKeyThrottleService(nil, rpm)
+	key := &APIKey{ID: 8, TPMLimit: 1000}
+
+	release, err := svc.Acquire(context.Background(), key)
+	require.NoError(t, err)
+	release()
+
+	svc.RecordTokenUsage(context.Background(), key, 1200)
+	_, err = svc.Acquire(context.Background(), key)
+	throttleErr := requireThrottleError(t, err, APIKeyThrottleTPM)
+	require.Equal(t, 1000, throttleErr.Limit)
+
+	// 未配置 TPM 的 Key 不记录用量
+	svc.RecordTokenUsage(context.Background(), &APIKey{ID: 9}, 100)
+	require.Equal(t, 1, rpm.addedCalls)
+}
+
+func TestAPIKeyThrottle_Concurrency(t *testing.T) {
+	cache := &stubConcurrencyCacheForTest{acquireResult: true}
+	rpm := newThrottleRPMCacheStub()
+	svc := NewAPIKeyThrottleService(NewConcurrencyService(cache), rpm)
+	key := &APIKey{ID: 10, MaxConcurrency: 1, RPMLimit: 1}
+
+	release, err := svc.Acquire(context.Background(), key)
+	require.NoError(t, err)
+	release()
+	release() // 重复释放只生效一次
+	require.Len(t, cache.releasedRequestIDs, 1)
+
+	// RPM 超限时释放已占用的并发槽位
+	_, err = svc.Acquire(context.Background(), key)
+	requireThrottleError(t, err, APIKeyThrottleRPM)
+	require.Len(t, cache.releasedRequestIDs, 2)
+
+	// 并发超限时不消耗 RPM 计数
+	cache.acquireResult = false
+	_, err = svc.Acquire(context.Background(), key)
+	throttleErr := requireThrottleError(t, err, APIKeyThrottleConcurrency)
+	require.Equal(t, 1, throttleErr.RetryAfterSeconds())
+	require.Equal(t, 2, rpm.rpm[key.ID])
+}
+
+func TestAPIKeyThrottle_FailOpenOnCacheError(t *testing.T) {
+	cache := &stubConcurrencyCacheForTest{acquireErr: errors.New("redis down")}
+	rpm := newThrottleRPMCacheStub()
+	rpm.err = errors.New("redis down")
+	svc := NewAPIKeyThrottleService(NewConcurrencyService(cache), rpm)
+
+	release, err := svc.Acquire(context.Background(), &APIKey{ID: 11, RPMLimit: 1, TPMLimit: 1, MaxConcurrency: 1})
+	require.NoError(t, err)
+	release()
+}
+
+func TestAPIKeyThrottleError_RetryAfterSecondsRoundsUp(t *testing.T) {
+	require.Equal(t, 1, (&APIKeyThrottleError{}).RetryAfterSeconds())
+	require.Equal(t, 1, (&APIKeyThrottleError{RetryAfter: 200 * time.Millisecond}).RetryAfterSeconds())
+	require.Equal(t, 3, (&APIKeyThrottleError{RetryAfter: 2100 * time.Millisecond}).RetryAfterSeconds())
+}
@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// batchOwner 后台批处理执行时的调度与计费身份：批内请求以创建批次的 API Key 执行。
+// Anthropic Message Batches 与 OpenAI Batch 共用。
+type batchOwner struct {
+	apiKey       *APIKey
+	subscription *UserSubscription
+	// errType 非空时该批次的请求无法执行（Key 已删除/禁用、余额不足等），直接记为失败
+	errType    string
+	errMessage string
+}
+
+func (o *batchOwner) fail(errType, message string) {
+	o.errType, o.errMessage = errType, message
+}
+
+// batchOwnerLoader 加载并校验批次所属 API Key、组织与订阅
+type batchOwnerLoader struct {
+	apiKeyRepo          APIKeyRepository
+	orgRepo             OrganizationRepository
+	subscriptionService *SubscriptionService
+	billingCacheService *BillingCacheService
+}
+
+// load 按请求热路径相同的规则校验 Key、分组、组织、订阅与计费资格。
+// 校验不通过时设置 errType；返回的 error 仅表示内部错误（此时 errType 为 api_error），供调用方记录日志。
+func (l *batchOwnerLoader) load(ctx context.Context, apiKeyID int64, groupID *int64) (batchOwner, error) {
+	var owner batchOwner
+	apiKey, err := l.apiKeyRepo.GetByID(ctx, apiKeyID)
+	if err != nil {
+		if errors.Is(err, ErrAPIKeyNotFound) {
+			owner.fail("authentication_error", "the API key that created this batch no longer exists")
+			return owner, nil
+		}
+		owner.fail("api_error", "internal error")
+		return owner, fmt.Errorf("load api key: %w", err)
+	}
+	if !apiKey.IsActive() || apiKey.IsExpired() || (apiKey.User != nil && !apiKey.User.IsActive()) {
+		owner.fail("permission_error", "the API key that created this batch is disabled")
+		return owner, nil
+	}
+	if apiKey.Group == nil || apiKey.GroupID == nil || groupID == nil || *apiKey.GroupID != *groupID {
+		owner.fail("permission_error", "the API key that created this batch is no longer assigned to the same group")
+		return owner, nil
+	}
+	if apiKey.OrganizationID != nil && apiKey.Organization == nil && l.orgRepo != nil {
+		org, err := l.orgRepo.GetByID(ctx, *apiKey.OrganizationID)
+		if err != nil {
+			owner.fail("api_error", "internal error")
+			return owner, fmt.Errorf("load organization: %w", err)
+		}
+		apiKey.Organization = org
+	}
+	if apiKey.OrganizationID != nil && (apiKey.Organization == nil || !apiKey.Organization.IsActive()) {
+		owner.fail("permission_error", "organization is disabled")
+		return owner, nil
+	}
+	if apiKey.Group.IsSubscriptionType() && l.subscriptionService != nil {
+		sub, err := l.subscriptionService.GetActiveSubscription(ctx, apiKey.SubscriptionUserID(), apiKey.Group.ID)
+		if err != nil {
+			owner.fail("permission_error", "no active subscription for this group")
+			return owner, nil
+		}
+		owner.subscription = sub
+	}
+	if l.billingCacheService != nil {
+		if err := l.billingCacheService.CheckBillingEligibility(ctx, apiKey.User, apiKey, apiKey.Group, owner.subscription); err != nil {
+			owner.fail("billing_error", infraerrors.Message(err))
+			return owner, nil
+		}
+	}
+	owner.apiKey = apiKey
+	return owner, nil
+}
+
+// acquireBatchSpareSlot 为批内请求占用账号槽位：不排队等待，账号其余负载超过阈值
+// 或已有交互请求在排队时立即释放，把容量留给实时请求
+func acquireBatchSpareSlot(ctx context.Context, concurrencyService *ConcurrencyService, selection *AccountSelectionResult, maxLoadPercent int) (func(), bool) {
+	account := selection.Account
+	release := selection.ReleaseFunc
+	if !selection.Acquired {
+		if concurrencyService == nil {
+			return nil, false
+		}
+		acq, err := concurrencyService.AcquireAccountSlot(ctx, account.ID, account.Concurrency)
+		if err != nil || acq == nil || !acq.Acquired {
+			return nil, false
+		}
+		release = acq.ReleaseFunc
+	}
+	if release == nil {
+		release = func() {}
+	}
+	if concurrencyService == nil || account.Concurrency <= 0 {
+		return release, true
+	}
+	loads, err := concurrencyService.GetAccountsLoadBatch(ctx, []AccountWithConcurrency{{ID: account.ID, MaxConcurrency: account.Concurrency}})
+	if err != nil {
+		release()
+		return nil, false
+	}
+	if !batchHasSpareCapacity(loads[account.ID], account.Concurrency, maxLoadPercent) {
+		release()
+		return nil, false
+	}
+	return release, true
+}
+
+// batchHasSpareCapacity 判断账号是否有空闲容量：load 已计入本请求占用的槽位，
+// 按其余请求的占用计算负载，使单并发账号空闲时也能执行批内请求
+func batchHasSpareCapacity(load *AccountLoadInfo, maxConcurrency, maxLoadPercent int) bool {
+	if load == nil || maxConcurrency <= 0 {
+		return true
+	}
+	if load.WaitingCount > 0 {
+		return false
+	}
+	others := max(0, load.CurrentConcurrency-1)
+	return others*100/maxConcurrency <= maxLoadPercent
+}
@@ -41,6 +41,8 @@ const (
 	cacheWriteUpdateSubscriptionUsage
 	cacheWriteDeductBalance
 	cacheWriteUpdateRateLimitUsage
+	cacheWriteDeductOrganizationBalance
+	cacheWriteUpdateOrganizationMemberUsage
 )
 
 // 异步缓存写入工作池配置
@@ -70,6 +72,7 @@ type cacheWriteTask struct {
 	userID           int64
 	groupID          int64
 	apiKeyID         int64
+	organizationID   int64
 	balance          float64
 	amount           float64
 	subscriptionData *subscriptionCacheData
@@ -87,6 +90,7 @@ type BillingCacheService struct {
 	userRepo              UserRepository
 	subRepo               UserSubscriptionRepository
 	apiKeyRateLimitLoader apiKeyRateLimitLoader
+	orgRepo               OrganizationRepository
 	cfg                   *config.Config
 	circuitBreaker        *billingCircuitBreaker
 
@@ -104,11 +108,12 @@ type BillingCacheService struct {
 }
 
 // NewBillingCacheService 创建计费缓存服务
-func NewBillingCacheService(cache BillingCache, userRepo UserRepository, subRepo UserSubscriptionRepository, apiKeyRepo APIKeyRepository, cfg *config.Config) *BillingCacheService {
+func NewBillingCacheService(cache BillingCache, userRepo UserRepository, subRepo UserSubscriptionRepository, apiKeyRepo APIKeyRepository, orgRepo OrganizationRepository, cfg *config.Config) *BillingCacheService {
 	svc := &BillingCacheService{
 		cache:                 cache,
 		userRepo:              userRepo,
 		subRepo:               subRepo,
+		orgRepo:               orgRepo,
 		apiKeyRateLimitLoader: apiKeyRepo,
 		cfg:                   cfg,
 	}
@@ -203,6 +208,10 @@ func (s *BillingCacheService) cacheWriteWorker(ch <-chan cacheWriteTask) {
 					logger.LegacyPrintf("service.billing_cache", "Warning: update rate limit usage cache failed for api key %d: %v", task.apiKeyID, err)
 				}
 			}
+		case cacheWriteDeductOrganizationBalance:
+			s.deductOrganizationBalanceCache(ctx, task.organizationID, task.amount)
+		case cacheWriteUpdateOrganizationMemberUsage:
+			s.updateOrganizationMemberUsageCache(ctx, task.organizationID, task.userID, task.amount)
 		}
 		cancel()
 	}
@@ -221,6 +230,10 @@ func cacheWriteKindName(kind cacheWriteKind) string {
 		return "deduct_balance"
 	case cacheWriteUpdateRateLimitUsage:
 		return "update_rate_limit_usage"
+	case cacheWriteDeductOrganizationBalance:
+		return "deduct_organization_balance"
+	case cacheWriteUpdateOrganizationMemberUsage:
+		return "update_organization_member_usage"
 	default:
 		return "unknown"
 	}
@@ -647,8 +660,19 @@ func (s *BillingCacheService) CheckBillingEligibility(ctx context.Context, user
 	// 判断计费模式
 	isSubscriptionMode := group != nil && group.IsSubscriptionType() && subscription != nil
 
+	isOrganizationKey := apiKey != nil && apiKey.IsOrganizationKey()
+
 	if isSubscriptionMode {
-		if err := s.checkSubscriptionEligibility(ctx, user.ID, group, subscription); err != nil {
+		// 组织 Key 使用 owner 名下的订阅
+		subscriptionUserID := user.ID
+		if isOrganizationKey {
+			subscriptionUserID = apiKey.SubscriptionUserID()
+		}
+		if err := s.checkSubscriptionEligibility(ctx, subscriptionUserID, group, subscription); err != nil {
+			return err
+		}
+	} else if isOrganizationKey {
+		if err := s.checkOrganizationBalanceEligibility(ctx, *apiKey.OrganizationID); err != nil {
 			return err
 		}
 	} else {
@@ -657,6 +681,13 @@ func (s *BillingCacheService) CheckBillingEligibility(ctx context.Context, user
 		}
 	}
 
+	// 组织成员月度消费上限（两种计费模式均生效）
+	if isOrganizationKey {
+		if err := s.checkOrganizationMemberEligibility(ctx, *apiKey.OrganizationID, apiKey.UserID); err != nil {
+			return err
+		}
+	}
+
 	// Check API Key rate limits (applies to both billing modes)
 	if apiKey != nil && apiKey.HasRateLimits() {
 		if err := s.checkAPIKeyRateLimits(ctx, apiKey); err != nil {
@@ -847,6 +878,20 @@ func (b *billingCircuitBreaker) OnSuccess() {
 	}
 }
 
+func (b *billingCircuitBreaker) State() billingCircuitBreakerState {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.state
+}
+
+// CircuitBreakerState 返回计费缓存熔断器状态：closed / open / half-open；未启用时返回 disabled。
+func (s *BillingCacheService) CircuitBreakerState() string {
+	if s == nil || s.circuitBreaker == nil {
+		return "disabled"
+	}
+	return circuitStateString(s.circuitBreaker.State())
+}
+
 func circuitStateString(state billingCircuitBreakerState) string {
 	switch state {
 	case billingCircuitClosed:
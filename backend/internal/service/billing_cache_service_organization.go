@@ -0,0 +1,248 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+)
+
+// OrganizationBillingCache 组织余额与成员月度用量缓存。
+// 作为 BillingCache 的可选能力通过类型断言获取；未实现时计费检查直接回源数据库。
+type OrganizationBillingCache interface {
+	GetOrganizationBalance(ctx context.Context, orgID int64) (float64, error)
+	SetOrganizationBalance(ctx context.Context, orgID int64, balance float64) error
+	DeductOrganizationBalance(ctx context.Context, orgID int64, amount float64) error
+	InvalidateOrganizationBalance(ctx context.Context, orgID int64) error
+
+	GetOrganizationMember(ctx context.Context, orgID, userID int64) (*OrganizationMemberCacheData, error)
+	SetOrganizationMember(ctx context.Context, orgID, userID int64, data *OrganizationMemberCacheData) error
+	// UpdateOrganizationMemberUsage 累加成员用量；缓存中的窗口早于 window 时先清零（缓存不存在时不创建）
+	UpdateOrganizationMemberUsage(ctx context.Context, orgID, userID int64, cost float64, window int64) error
+	InvalidateOrganizationMember(ctx context.Context, orgID, userID int64) error
+}
+
+// OrganizationMemberCacheData 成员月度上限与用量缓存
+type OrganizationMemberCacheData struct {
+	MonthlyLimit float64
+	MonthlyUsage float64
+	Window       int64 // 用量所属自然月的起始时间（Unix 秒，0 = 尚无用量）
+}
+
+func (s *BillingCacheService) organizationCache() OrganizationBillingCache {
+	if s.cache == nil {
+		return nil
+	}
+	oc, _ := s.cache.(OrganizationBillingCache)
+	return oc
+}
+
+// ============================================
+// 组织余额
+// ============================================
+
+// GetOrganizationBalance 获取组织余额（优先从缓存读取）
+func (s *BillingCacheService) GetOrganizationBalance(ctx context.Context, orgID int64) (float64, error) {
+	oc := s.organizationCache()
+	if oc != nil {
+		if balance, err := oc.GetOrganizationBalance(ctx, orgID); err == nil {
+			return balance, nil
+		}
+	}
+
+	value, err, _ := s.balanceLoadSF.Do(fmt.Sprintf("org:%d", orgID), func() (any, error) {
+		loadCtx, cancel := context.WithTimeout(context.Background(), balanceLoadTimeout)
+		defer cancel()
+
+		if s.orgRepo == nil {
+			return nil, errors.New("organization repository not configured")
+		}
+		org, err := s.orgRepo.GetByID(loadCtx, orgID)
+		if err != nil {
+			return nil, fmt.Errorf("get organization balance: %w", err)
+		}
+		if oc != nil {
+			if err := oc.SetOrganizationBalance(loadCtx, orgID, org.Balance); err != nil {
+				logger.LegacyPrintf("service.billing_cache", "Warning: set organization balance cache failed for org %d: %v", orgID, err)
+			}
+		}
+		return org.Balance, nil
+	})
+	if err != nil {
+		return 0, err
+	}
+	balance, ok := value.(float64)
+	if !ok {
+		return 0, fmt.Errorf("unexpected balance type: %T", value)
+	}
+	return balance, nil
+}
+
+// DeductOrganizationBalance 扣减组织余额（数据库同步扣减，缓存异步扣减）
+func (s *BillingCacheService) DeductOrganizationBalance(ctx context.Context, orgID int64, amount float64) error {
+	if s.orgRepo == nil || amount <= 0 {
+		return nil
+	}
+	if err := s.orgRepo.DeductBalance(ctx, orgID, amount); err != nil {
+		return err
+	}
+	if s.organizationCache() == nil {
+		return nil
+	}
+	if s.enqueueCacheWrite(cacheWriteTask{
+		kind:           cacheWriteDeductOrganizationBalance,
+		organizationID: orgID,
+		amount:         amount,
+	}) {
+		return nil
+	}
+	// 队列满时同步回退，避免扣减被静默丢弃
+	cacheCtx, cancel := context.WithTimeout(context.Background(), cacheWriteTimeout)
+	defer cancel()
+	s.deductOrganizationBalanceCache(cacheCtx, orgID, amount)
+	return nil
+}
+
+func (s *BillingCacheService) deductOrganizationBalanceCache(ctx context.Context, orgID int64, amount float64) {
+	oc := s.organizationCache()
+	if oc == nil {
+		return
+	}
+	if err := oc.DeductOrganizationBalance(ctx, orgID, amount); err != nil {
+		logger.LegacyPrintf("service.billing_cache", "Warning: deduct organization balance cache failed for org %d: %v", orgID, err)
+	}
+}
+
+// InvalidateOrganizationBalance 失效组织余额缓存
+func (s *BillingCacheService) InvalidateOrganizationBalance(ctx context.Context, orgID int64) error {
+	oc := s.organizationCache()
+	if oc == nil {
+		return nil
+	}
+	if err := oc.InvalidateOrganizationBalance(ctx, orgID); err != nil {
+		logger.LegacyPrintf("service.billing_cache", "Warning: invalidate organization balance cache failed for org %d: %v", orgID, err)
+		return err
+	}
+	return nil
+}
+
+// checkOrganizationBalanceEligibility 组织 Key 余额模式资格检查
+func (s *BillingCacheService) checkOrganizationBalanceEligibility(ctx context.Context, orgID int64) error {
+	balance, err := s.GetOrganizationBalance(ctx, orgID)
+	if err != nil {
+		if s.circuitBreaker != nil {
+			s.circuitBreaker.OnFailure(err)
+		}
+		logger.LegacyPrintf("service.billing_cache", "ALERT: billing balance check failed for organization %d: %v", orgID, err)
+		return ErrBillingServiceUnavailable.WithCause(err)
+	}
+	if s.circuitBreaker != nil {
+		s.circuitBreaker.OnSuccess()
+	}
+	if balance <= 0 {
+		return ErrInsufficientBalance
+	}
+	return nil
+}
+
+// ============================================
+// 组织成员月度用量
+// ============================================
+
+// checkOrganizationMemberEligibility 校验 Key 创建者仍是组织成员且未超出月度上限
+func (s *BillingCacheService) checkOrganizationMemberEligibility(ctx context.Context, orgID, userID int64) error {
+	data, err := s.getOrganizationMemberData(ctx, orgID, userID)
+	if err != nil {
+		if errors.Is(err, ErrOrganizationMemberNotFound) {
+			return ErrOrganizationMemberNotFound
+		}
+		// 与 API Key 限速一致：数据库异常时不阻塞请求
+		logger.LegacyPrintf("service.billing_cache", "Warning: load organization member %d/%d failed: %v", orgID, userID, err)
+		return nil
+	}
+	if data.MonthlyLimit <= 0 {
+		return nil
+	}
+	usage := data.MonthlyUsage
+	if data.Window < timezone.StartOfMonth(time.Now()).Unix() {
+		usage = 0
+	}
+	if usage >= data.MonthlyLimit {
+		return ErrOrganizationMemberLimitExceeded
+	}
+	return nil
+}
+
+func (s *BillingCacheService) getOrganizationMemberData(ctx context.Context, orgID, userID int64) (*OrganizationMemberCacheData, error) {
+	oc := s.organizationCache()
+	if oc != nil {
+		if data, err := oc.GetOrganizationMember(ctx, orgID, userID); err == nil {
+			return data, nil
+		}
+	}
+	if s.orgRepo == nil {
+		return nil, errors.New("organization repository not configured")
+	}
+	member, err := s.orgRepo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		return nil, err
+	}
+	data := &OrganizationMemberCacheData{
+		MonthlyLimit: member.MonthlyLimitUSD,
+		MonthlyUsage: member.MonthlyUsageUSD,
+	}
+	if member.MonthlyWindowStart != nil {
+		data.Window = member.MonthlyWindowStart.Unix()
+	}
+	if oc != nil {
+		_ = oc.SetOrganizationMember(ctx, orgID, userID, data)
+	}
+	return data, nil
+}
+
+// RecordOrganizationMemberUsage 累加成员当月消费（数据库同步写入，缓存异步更新）
+func (s *BillingCacheService) RecordOrganizationMemberUsage(ctx context.Context, orgID, userID int64, cost float64) error {
+	if s.orgRepo == nil || cost <= 0 {
+		return nil
+	}
+	if err := s.orgRepo.IncrementMemberUsage(ctx, orgID, userID, cost, timezone.StartOfMonth(time.Now())); err != nil {
+		return err
+	}
+	if s.organizationCache() == nil {
+		return nil
+	}
+	s.enqueueCacheWrite(cacheWriteTask{
+		kind:           cacheWriteUpdateOrganizationMemberUsage,
+		organizationID: orgID,
+		userID:         userID,
+		amount:         cost,
+	})
+	return nil
+}
+
+func (s *BillingCacheService) updateOrganizationMemberUsageCache(ctx context.Context, orgID, userID int64, cost float64) {
+	oc := s.organizationCache()
+	if oc == nil {
+		return
+	}
+	window := timezone.StartOfMonth(time.Now()).Unix()
+	if err := oc.UpdateOrganizationMemberUsage(ctx, orgID, userID, cost, window); err != nil {
+		logger.LegacyPrintf("service.billing_cache", "Warning: update organization member usage cache failed for org %d user %d: %v", orgID, userID, err)
+	}
+}
+
+// InvalidateOrganizationMember 失效成员缓存（角色/上限变更或成员移除后调用）
+func (s *BillingCacheService) InvalidateOrganizationMember(ctx context.Context, orgID, userID int64) error {
+	oc := s.organizationCache()
+	if oc == nil {
+		return nil
+	}
+	if err := oc.InvalidateOrganizationMember(ctx, orgID, userID); err != nil {
+		logger.LegacyPrintf("service.billing_cache", "Warning: invalidate organization member cache failed for org %d user %d: %v", orgID, userID, err)
+		return err
+	}
+	return nil
+}
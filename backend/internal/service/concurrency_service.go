@@ -33,6 +33,11 @@ type ConcurrencyCache interface {
 	ReleaseUserSlot(ctx context.Context, userID int64, requestID string) error
 	GetUserConcurrency(ctx context.Context, userID int64) (int, error)
 
+	// API Key 槽位管理
+	// 键格式: concurrency:api_key:{apiKeyID}（有序集合，成员为 requestID）
+	AcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error)
+	ReleaseAPIKeySlot(ctx context.Context, apiKeyID int64, requestID string) error
+
 	// 等待队列计数（只在首次创建时设置 TTL）
 	IncrementWaitCount(ctx context.Context, userID int64, maxWait int) (bool, error)
 	DecrementWaitCount(ctx context.Context, userID int64) error
@@ -74,6 +79,10 @@ const (
 // ConcurrencyService manages concurrent request limiting for accounts and users
 type ConcurrencyService struct {
 	cache ConcurrencyCache
+
+	// 本实例当前持有的槽位数（仅统计本进程获取且未释放的槽位，用于指标导出）
+	heldAccountSlots atomic.Int64
+	heldUserSlots    atomic.Int64
 }
 
 // NewConcurrencyService creates a new ConcurrencyService
@@ -81,6 +90,14 @@ func NewConcurrencyService(cache ConcurrencyCache) *ConcurrencyService {
 	return &ConcurrencyService{cache: cache}
 }
 
+// HeldSlots returns the number of account and user slots currently held by this process.
+func (s *ConcurrencyService) HeldSlots() (account, user int64) {
+	if s == nil {
+		return 0, 0
+	}
+	return s.heldAccountSlots.Load(), s.heldUserSlots.Load()
+}
+
 // AcquireResult represents the result of acquiring a concurrency slot
 type AcquireResult struct {
 	Acquired    bool
@@ -132,9 +149,15 @@ func (s *ConcurrencyService) AcquireAccountSlot(ctx context.Context, accountID i
 	}
 
 	if acquired {
+		s.heldAccountSlots.Add(1)
+		var released atomic.Bool
 		return &AcquireResult{
 			Acquired: true,
 			ReleaseFunc: func() {
+				if !released.CompareAndSwap(false, true) {
+					return
+				}
+				s.heldAccountSlots.Add(-1)
 				bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 				defer cancel()
 				if err := s.cache.ReleaseAccountSlot(bgCtx, accountID, requestID); err != nil {
@@ -171,9 +194,15 @@ func (s *ConcurrencyService) AcquireUserSlot(ctx context.Context, userID int64,
 	}
 
 	if acquired {
+		s.heldUserSlots.Add(1)
+		var released atomic.Bool
 		return &AcquireResult{
 			Acquired: true,
 			ReleaseFunc: func() {
+				if !released.CompareAndSwap(false, true) {
+					return
+				}
+				s.heldUserSlots.Add(-1)
 				bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
 				defer cancel()
 				if err := s.cache.ReleaseUserSlot(bgCtx, userID, requestID); err != nil {
@@ -189,6 +218,42 @@ func (s *ConcurrencyService) AcquireUserSlot(ctx context.Context, userID int64,
 	}, nil
 }
 
+// AcquireAPIKeySlot attempts to acquire a concurrency slot for an API key without waiting.
+// Returns a release function that MUST be called when the request completes.
+func (s *ConcurrencyService) AcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int) (*AcquireResult, error) {
+	if maxConcurrency <= 0 || s.cache == nil {
+		return &AcquireResult{
+			Acquired:    true,
+			ReleaseFunc: func() {}, // no-op
+		}, nil
+	}
+
+	requestID := generateRequestID()
+
+	acquired, err := s.cache.AcquireAPIKeySlot(ctx, apiKeyID, maxConcurrency, requestID)
+	if err != nil {
+		return nil, err
+	}
+	if !acquired {
+		return &AcquireResult{Acquired: false}, nil
+	}
+
+	var released atomic.Bool
+	return &AcquireResult{
+		Acquired: true,
+		ReleaseFunc: func() {
+			if !released.CompareAndSwap(false, true) {
+				return
+			}
+			bgCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			if err := s.cache.ReleaseAPIKeySlot(bgCtx, apiKeyID, requestID); err != nil {
+				logger.LegacyPrintf("service.concurrency", "Warning: failed to release api key slot for %d (req=%s): %v", apiKeyID, requestID, err)
+			}
+		},
+	}, nil
+}
+
 // ============================================
 // Wait Queue Count Methods
 // ============================================
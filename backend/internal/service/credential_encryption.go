@@ -0,0 +1,83 @@
+package service
+
+import (
+	"context"
+	"strings"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// CredentialCiphertextPrefix 加密凭证字段值的前缀，完整格式：
+// enc:v1:<keyID>:<base64(wrapped DEK)>:<base64(nonce+ciphertext)>
+const CredentialCiphertextPrefix = "enc:v1:"
+
+var (
+	ErrCredentialKeyNotFound      = infraerrors.InternalServer("CREDENTIAL_KEY_NOT_FOUND", "credential encryption key not found")
+	ErrCredentialCiphertextFormat = infraerrors.InternalServer("CREDENTIAL_CIPHERTEXT_INVALID", "invalid credential ciphertext")
+	ErrCredentialKeyPinned        = infraerrors.Conflict("CREDENTIAL_KEY_PINNED", "active credential key is pinned by credential_encryption.active_key_id; rotate via config instead")
+	ErrCredentialReencryptRunning = infraerrors.Conflict("CREDENTIAL_REENCRYPT_RUNNING", "credential re-encryption is already running")
+)
+
+// sensitiveCredentialFields 需要静态加密的凭证字段（令牌、密钥、会话等）；
+// project_id、base_url、expires_at 等非机密字段保持明文，便于排查与查询。
+var sensitiveCredentialFields = map[string]struct{}{
+	"access_token":  {},
+	"refresh_token": {},
+	"id_token":      {},
+	"api_key":       {},
+	"session_token": {},
+	"session_key":   {},
+	"client_secret": {},
+	"cookie":        {},
+}
+
+// IsSensitiveCredentialField 判断凭证字段是否需要加密存储
+func IsSensitiveCredentialField(field string) bool {
+	_, ok := sensitiveCredentialFields[field]
+	return ok
+}
+
+// IsEncryptedCredentialValue 判断凭证字段值是否为密文
+func IsEncryptedCredentialValue(v any) bool {
+	s, ok := v.(string)
+	return ok && strings.HasPrefix(s, CredentialCiphertextPrefix)
+}
+
+// CredentialCipher 账号凭证字段级加解密（仓储层透明使用）
+type CredentialCipher interface {
+	// EncryptCredentials 返回敏感字段已加密的副本；未启用加密时原样返回
+	EncryptCredentials(credentials map[string]any) (map[string]any, error)
+	// DecryptCredentials 返回敏感字段已解密的副本；无法解密的字段保留密文并返回错误
+	DecryptCredentials(credentials map[string]any) (map[string]any, error)
+}
+
+// CredentialKeyRing 版本化密钥环：密钥来自配置/环境变量或 security_secrets 表
+type CredentialKeyRing interface {
+	CredentialCipher
+	Enabled() bool
+	ActiveKeyID() string
+	KeyIDs() []string
+	// Reload 从 security_secrets 重新加载密钥（多实例轮换后同步）
+	Reload(ctx context.Context) error
+	// Rotate 生成新密钥写入 security_secrets 并设为当前密钥，返回新密钥 ID
+	Rotate(ctx context.Context) (string, error)
+	// ReencryptCredentials 按当前策略重写凭证：启用时用当前密钥重新封装（明文字段加密），
+	// 关闭时解密为明文。changed=false 表示无需写回。
+	ReencryptCredentials(credentials map[string]any) (out map[string]any, changed bool, err error)
+	// CredentialKeyID 返回密文字段使用的密钥 ID
+	CredentialKeyID(value string) (string, bool)
+}
+
+// AccountCredentialRecord 账号的原始（数据库中的）凭证
+type AccountCredentialRecord struct {
+	AccountID   int64
+	Credentials map[string]any
+}
+
+// AccountCredentialRepository 凭证重加密使用的原始凭证读写
+type AccountCredentialRepository interface {
+	// ListRawCredentials 按 ID 升序分页读取未解密的凭证
+	ListRawCredentials(ctx context.Context, afterID int64, limit int) ([]AccountCredentialRecord, error)
+	// CompareAndSwapCredentials 仅当数据库中的凭证仍等于 expected 时写入，返回是否写入成功
+	CompareAndSwapCredentials(ctx context.Context, accountID int64, expected, updated map[string]any) (bool, error)
+}
@@ -0,0 +1,160 @@
+package service
+
+import (
+	"context"
+	"sync/atomic"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+const credentialReencryptBatchSize = 200
+
+var ErrCredentialEncryptionDisabled = infraerrors.BadRequest("CREDENTIAL_ENCRYPTION_DISABLED", "credential encryption is disabled")
+
+// CredentialEncryptionStatus 凭证加密状态
+type CredentialEncryptionStatus struct {
+	Enabled     bool     `json:"enabled"`
+	ActiveKeyID string   `json:"active_key_id"`
+	KeyIDs      []string `json:"key_ids"`
+	Accounts    int      `json:"accounts"`
+	// PlaintextAccounts 仍有明文敏感字段的账号数
+	PlaintextAccounts int `json:"plaintext_accounts"`
+	// EncryptedFields 各密钥 ID 加密的字段数；未在密钥环中的 ID 表示数据无法解密
+	EncryptedFields map[string]int `json:"encrypted_fields"`
+}
+
+// CredentialReencryptResult 一次重加密的结果
+type CredentialReencryptResult struct {
+	ActiveKeyID string `json:"active_key_id"`
+	Scanned     int    `json:"scanned"`
+	Updated     int    `json:"updated"`
+	// Conflicts 重加密期间凭证被并发修改（如令牌刷新）而跳过的账号数，可再次执行
+	Conflicts int `json:"conflicts"`
+	Failed    int `json:"failed"`
+}
+
+// CredentialEncryptionService 凭证密钥轮换与在线重加密
+type CredentialEncryptionService struct {
+	keyRing CredentialKeyRing
+	repo    AccountCredentialRepository
+	running atomic.Bool
+}
+
+// NewCredentialEncryptionService 创建凭证加密管理服务
+func NewCredentialEncryptionService(keyRing CredentialKeyRing, repo AccountCredentialRepository) *CredentialEncryptionService {
+	return &CredentialEncryptionService{keyRing: keyRing, repo: repo}
+}
+
+// Status 统计当前密钥环与账号凭证的加密情况
+func (s *CredentialEncryptionService) Status(ctx context.Context) (*CredentialEncryptionStatus, error) {
+	if err := s.keyRing.Reload(ctx); err != nil {
+		return nil, err
+	}
+	status := &CredentialEncryptionStatus{
+		Enabled:         s.keyRing.Enabled(),
+		ActiveKeyID:     s.keyRing.ActiveKeyID(),
+		KeyIDs:          s.keyRing.KeyIDs(),
+		EncryptedFields: make(map[string]int),
+	}
+	err := s.forEachRecord(ctx, func(record AccountCredentialRecord) {
+		status.Accounts++
+		plaintext := false
+		for field, value := range record.Credentials {
+			if !IsSensitiveCredentialField(field) {
+				continue
+			}
+			if str, ok := value.(string); ok && IsEncryptedCredentialValue(str) {
+				keyID, _ := s.keyRing.CredentialKeyID(str)
+				status.EncryptedFields[keyID]++
+			} else if value != nil && value != "" {
+				plaintext = true
+			}
+		}
+		if plaintext {
+			status.PlaintextAccounts++
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+// Reencrypt 按当前策略重写所有账号凭证：启用加密时统一使用当前密钥（含历史明文数据），
+// 关闭加密时解密回明文。使用比较写入避免覆盖并发的令牌刷新。
+func (s *CredentialEncryptionService) Reencrypt(ctx context.Context) (*CredentialReencryptResult, error) {
+	if !s.running.CompareAndSwap(false, true) {
+		return nil, ErrCredentialReencryptRunning
+	}
+	defer s.running.Store(false)
+
+	if err := s.keyRing.Reload(ctx); err != nil {
+		return nil, err
+	}
+	result := &CredentialReencryptResult{ActiveKeyID: s.keyRing.ActiveKeyID()}
+	err := s.forEachRecord(ctx, func(record AccountCredentialRecord) {
+		result.Scanned++
+		updated, changed, err := s.keyRing.ReencryptCredentials(record.Credentials)
+		if err != nil {
+			result.Failed++
+			logger.LegacyPrintf("service.credential_encryption", "Reencrypt account %d failed: %v", record.AccountID, err)
+			return
+		}
+		if !changed {
+			return
+		}
+		swapped, err := s.repo.CompareAndSwapCredentials(ctx, record.AccountID, record.Credentials, updated)
+		switch {
+		case err != nil:
+			result.Failed++
+			logger.LegacyPrintf("service.credential_encryption", "Write account %d credentials failed: %v", record.AccountID, err)
+		case !swapped:
+			result.Conflicts++
+		default:
+			result.Updated++
+		}
+	})
+	if err != nil {
+		return result, err
+	}
+	logger.LegacyPrintf("service.credential_encryption", "Reencrypt finished: key=%s scanned=%d updated=%d conflicts=%d failed=%d",
+		result.ActiveKeyID, result.Scanned, result.Updated, result.Conflicts, result.Failed)
+	return result, nil
+}
+
+// Rotate 生成新密钥并设为当前密钥，随后重加密全部账号凭证（旧密钥保留用于解密）
+func (s *CredentialEncryptionService) Rotate(ctx context.Context) (*CredentialReencryptResult, error) {
+	if !s.keyRing.Enabled() {
+		return nil, ErrCredentialEncryptionDisabled
+	}
+	if s.running.Load() {
+		return nil, ErrCredentialReencryptRunning
+	}
+	if err := s.keyRing.Reload(ctx); err != nil {
+		return nil, err
+	}
+	keyID, err := s.keyRing.Rotate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	logger.LegacyPrintf("service.credential_encryption", "Credential key rotated: active=%s", keyID)
+	return s.Reencrypt(ctx)
+}
+
+func (s *CredentialEncryptionService) forEachRecord(ctx context.Context, fn func(AccountCredentialRecord)) error {
+	var afterID int64
+	for {
+		records, err := s.repo.ListRawCredentials(ctx, afterID, credentialReencryptBatchSize)
+		if err != nil {
+			return err
+		}
+		for _, record := range records {
+			fn(record)
+			afterID = record.AccountID
+		}
+		if len(records) < credentialReencryptBatchSize {
+			return nil
+		}
+	}
+}
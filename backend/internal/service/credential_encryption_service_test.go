@@ -0,0 +1,167 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+// credentialKeyRingStub 以 "enc:v1:<active>:" 前缀模拟加密，便于验证重加密流程
+type credentialKeyRingStub struct {
+	enabled bool
+	active  string
+}
+
+func (s *credentialKeyRingStub) EncryptCredentials(c map[string]any) (map[string]any, error) {
+	return c, nil
+}
+func (s *credentialKeyRingStub) DecryptCredentials(c map[string]any) (map[string]any, error) {
+	return c, nil
+}
+func (s *credentialKeyRingStub) Enabled() bool                    { return s.enabled }
+func (s *credentialKeyRingStub) ActiveKeyID() string              { return s.active }
+func (s *credentialKeyRingStub) KeyIDs() []string                 { return []string{s.active} }
+func (s *credentialKeyRingStub) Reload(ctx context.Context) error { return nil }
+func (s *credentialKeyRingStub) Rotate(ctx context.Context) (string, error) {
+	s.active = "k2"
+	return s.active, nil
+}
+func (s *credentialKeyRingStub) CredentialKeyID(value string) (string, bool) {
+	return value[len(CredentialCiphertextPrefix) : len(CredentialCiphertextPrefix)+2], true
+}
+func (s *credentialKeyRingStub) ReencryptCredentials(c map[string]any) (map[string]any, bool, error) {
+	if v, ok := c["fail"].(bool); ok && v {
+		return nil, false, errors.New("boom")
+	}
+	want := CredentialCiphertextPrefix + s.active + ":x"
+	if c["api_key"] == want {
+		return c, false, nil
+	}
+	return map[string]any{"api_key": want}, true, nil
+}
+
+type accountCredentialRepoStub struct {
+	records  []AccountCredentialRecord
+	conflict map[int64]bool
+	written  map[int64]map[string]any
+}
+
+func (s *accountCredentialRepoStub) ListRawCredentials(ctx context.Context, afterID int64, limit int) ([]AccountCredentialRecord, error) {
+	var out []AccountCredentialRecord
+	for _, r := range s.records {
+		if r.AccountID > afterID && len(out) < limit {
+			out = append(out, r)
+		}
+	}
+	return out, nil
+}
+
+func (s *accountCredentialRepoStub) CompareAndSwapCredentials(ctx context.Context, accountID int64, expected, updated map[string]any) (bool, error) {
+	if s.conflict[accountID] {
+		return false, nil
+	}
+	if s.written == nil {
+		s.written = make(map[int64]map[string]any)
+	}
+	s.written[accountID] = updated
+	return true, nil
+}
+
+func TestCredentialEncryptionService_Reencrypt(t *testing.T) {
+	ring := &credentialKeyRingStub{enabled: true, active: "k1"}
+	repo := &accountCredentialRepoStub{
+		records: []AccountCredentialRecord{
+			{AccountID: 1, Credentials: map[string]any{"api_key": "plain"}},
+			{AccountID: 2, Credentials: map[string]any{"api_key": CredentialCiphertextPrefix + "k1:x"}},
+			{AccountID: 3, Credentials: map[string]any{"api_key": "plain"}},
+			{AccountID: 4, Credentials: map[string]any{"fail": true}},
+		},
+		conflict: map[int64]bool{3: true},
+	}
+	svc := NewCredentialEncryptionService(ring, repo)
+
+	result, err := svc.Reencrypt(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, &CredentialReencryptResult{ActiveKeyID: "k1", Scanned: 4, Updated: 1, Conflicts: 1, Failed: 1}, result)
+	require.Contains(t, repo.written, int64(1))
+	require.NotContains(t, repo.written, int64(2))
+}
+
+func TestCredentialEncryptionService_Rotate(t *testing.T) {
+	ring := &credentialKeyRingStub{enabled: true, active: "k1"}
+	repo := &accountCredentialRepoStub{records: []AccountCredentialRecord{
+		{AccountID: 1, Credentials: map[string]any{"api_key": CredentialCiphertextPrefix + "k1:x"}},
+	}}
+	svc := NewCredentialEncryptionService(ring, repo)
+
+	result, err := svc.Rotate(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, "k2", result.ActiveKeyID)
+	require.Equal(t, 1, result.Updated)
+
+	ring.enabled = false
+	_, err = svc.Rotate(context.Background())
+	require.ErrorIs(t, err, ErrCredentialEncryptionDisabled)
+}
+
+func TestCredentialEncryptionService_Status(t *testing.T) {
+	ring := &credentialKeyRingStub{enabled: true, active: "k1"}
+	repo := &accountCredentialRepoStub{records: []AccountCredentialRecord{
+		{AccountID: 1, Credentials: map[string]any{"api_key": "plain", "base_url": "https://x"}},
+		{AccountID: 2, Credentials: map[string]any{"access_token": CredentialCiphertextPrefix + "k1:x", "refresh_token": CredentialCiphertextPrefix + "k0:y"}},
+	}}
+	status, err := NewCredentialEncryptionService(ring, repo).Status(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 2, status.Accounts)
+	require.Equal(t, 1, status.PlaintextAccounts)
+	require.Equal(t, map[string]int{"k1": 1, "k0": 1}, status.EncryptedFields)
+}
+
+func TestAccountExportSealer_RoundTrip(t *testing.T) {
+	_, err := NewAccountExportSealer("short")
+	require.ErrorIs(t, err, ErrAccountExportPassphraseTooShort)
+
+	sealer, err := NewAccountExportSealer("correct horse battery")
+	require.NoError(t, err)
+	sealed, err := sealer.SealCredentials(map[string]any{"refresh_token": "rt", "expires_at": float64(1)})
+	require.NoError(t, err)
+
+	opened, err := OpenAccountExportSealer("correct horse battery", sealer.Params())
+	require.NoError(t, err)
+	creds, err := opened.OpenCredentials(sealed)
+	require.NoError(t, err)
+	require.Equal(t, map[string]any{"refresh_token": "rt", "expires_at": float64(1)}, creds)
+
+	wrong, err := OpenAccountExportSealer("wrong passphrase!", sealer.Params())
+	require.NoError(t, err)
+	_, err = wrong.OpenCredentials(sealed)
+	require.ErrorIs(t, err, ErrAccountExportPassphraseInvalid)
+
+	_, err = OpenAccountExportSealer("", sealer.Params())
+	require.ErrorIs(t, err, ErrAccountExportPassphraseRequired)
+
+	params := sealer.Params()
+	params.N = 1 << 30
+	_, err = OpenAccountExportSealer("correct horse battery", params)
+	require.ErrorIs(t, err, ErrAccountExportEncryptionInvalid)
+}
+
+func TestOpenAccountExportSealer_RejectsExcessiveMemoryCost(t *testing.T) {
+	sealer, err := NewAccountExportSealer("correct horse battery")
+	require.NoError(t, err)
+
+	// N、r 各自在范围内，但 128·N·r 约 4 GiB
+	params := sealer.Params()
+	params.N, params.R = 1<<20, 32
+	_, err = OpenAccountExportSealer("correct horse battery", params)
+	require.ErrorIs(t, err, ErrAccountExportEncryptionInvalid)
+
+	// 默认 r=8 时 N=1<<18 需要 256 MiB，超过 128 MiB 上限
+	params.N, params.R = 1<<18, 8
+	_, err = OpenAccountExportSealer("correct horse battery", params)
+	require.ErrorIs(t, err, ErrAccountExportEncryptionInvalid)
+}
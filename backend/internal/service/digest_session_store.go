@@ -1,6 +1,7 @@
 package service
 
 import (
+	"context"
 	"strconv"
 	"strings"
 	"time"
@@ -11,6 +12,13 @@ import (
 // digestSessionTTL 摘要会话默认 TTL
 const digestSessionTTL = 5 * time.Minute
 
+// DigestSessionCache 摘要会话存储接口（内存 / Redis）。
+// 语义：Save 写入 chain 并删除旧的 matchedChain；Find 从完整 chain 逐段截断（以 "-" 分隔），返回最长匹配。
+type DigestSessionCache interface {
+	FindDigestSession(ctx context.Context, groupID int64, prefixHash, digestChain string) (uuid string, accountID int64, matchedChain string, found bool, err error)
+	SaveDigestSession(ctx context.Context, groupID int64, prefixHash, digestChain, uuid string, accountID int64, oldDigestChain string) error
+}
+
 // sessionEntry flat cache 条目
 type sessionEntry struct {
 	uuid      string
@@ -25,11 +33,31 @@ type DigestSessionStore struct {
 
 // NewDigestSessionStore 创建内存摘要会话存储
 func NewDigestSessionStore() *DigestSessionStore {
+	return NewDigestSessionStoreWithTTL(digestSessionTTL)
+}
+
+// NewDigestSessionStoreWithTTL 创建指定 TTL 的内存摘要会话存储；ttl<=0 使用默认值
+func NewDigestSessionStoreWithTTL(ttl time.Duration) *DigestSessionStore {
+	if ttl <= 0 {
+		ttl = digestSessionTTL
+	}
 	return &DigestSessionStore{
-		cache: gocache.New(digestSessionTTL, time.Minute),
+		cache: gocache.New(ttl, time.Minute),
 	}
 }
 
+// FindDigestSession 实现 DigestSessionCache
+func (s *DigestSessionStore) FindDigestSession(_ context.Context, groupID int64, prefixHash, digestChain string) (string, int64, string, bool, error) {
+	uuid, accountID, matchedChain, found := s.Find(groupID, prefixHash, digestChain)
+	return uuid, accountID, matchedChain, found, nil
+}
+
+// SaveDigestSession 实现 DigestSessionCache
+func (s *DigestSessionStore) SaveDigestSession(_ context.Context, groupID int64, prefixHash, digestChain, uuid string, accountID int64, oldDigestChain string) error {
+	s.Save(groupID, prefixHash, digestChain, uuid, accountID, oldDigestChain)
+	return nil
+}
+
 // Save 保存摘要会话。oldDigestChain 为 Find 返回的 matchedChain，用于删旧 key。
 func (s *DigestSessionStore) Save(groupID int64, prefixHash, digestChain, uuid string, accountID int64, oldDigestChain string) {
 	if digestChain == "" {
@@ -63,6 +91,23 @@ func (s *DigestSessionStore) Find(groupID int64, prefixHash, digestChain string)
 	}
 }
 
+// DigestChainPrefixes 按查找顺序（最长在前）列出 chain 的所有前缀，供外部存储实现复用同一匹配语义
+func DigestChainPrefixes(digestChain string) []string {
+	if digestChain == "" {
+		return nil
+	}
+	out := make([]string, 0, strings.Count(digestChain, "-")+1)
+	chain := digestChain
+	for {
+		out = append(out, chain)
+		i := strings.LastIndex(chain, "-")
+		if i < 0 {
+			return out
+		}
+		chain = chain[:i]
+	}
+}
+
 // buildNS 构建 namespace 前缀
 func buildNS(groupID int64, prefixHash string) string {
 	return strconv.FormatInt(groupID, 10) + ":" + prefixHash + "|"
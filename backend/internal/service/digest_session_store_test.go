@@ -3,6 +3,7 @@
 package service
 
 import (
+	"context"
 	"fmt"
 	"sync"
 	"testing"
@@ -310,3 +311,22 @@ func TestDigestSessionStore_SaveSameChainNoDelete(t *testing.T) {
 	assert.Equal(t, "uuid-1", uuid)
 	assert.Equal(t, int64(100), accountID)
 }
+
+func TestDigestChainPrefixes_LongestFirst(t *testing.T) {
+	assert.Nil(t, DigestChainPrefixes(""))
+	assert.Equal(t, []string{"u:a"}, DigestChainPrefixes("u:a"))
+	assert.Equal(t, []string{"u:a-m:b-u:c", "u:a-m:b", "u:a"}, DigestChainPrefixes("u:a-m:b-u:c"))
+}
+
+func TestDigestSessionStore_ImplementsDigestSessionCache(t *testing.T) {
+	var cache DigestSessionCache = NewDigestSessionStoreWithTTL(time.Minute)
+	ctx := context.Background()
+
+	require.NoError(t, cache.SaveDigestSession(ctx, 1, "prefix", "u:a-m:b", "uuid-1", 100, ""))
+	uuid, accountID, matched, found, err := cache.FindDigestSession(ctx, 1, "prefix", "u:a-m:b-u:c")
+	require.NoError(t, err)
+	require.True(t, found)
+	assert.Equal(t, "uuid-1", uuid)
+	assert.Equal(t, int64(100), accountID)
+	assert.Equal(t, "u:a-m:b", matched)
+}
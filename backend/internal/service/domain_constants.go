@@ -70,15 +70,26 @@ const (
 
 // Subscription order status constants
 const (
-	OrderStatusPending  = "pending"
-	OrderStatusPaid     = "paid"
-	OrderStatusCanceled = "canceled"
+	OrderStatusPending   = "pending"
+	OrderStatusPaid      = "paid"
+	OrderStatusCanceled  = "canceled"
+	OrderStatusRefunding = "refunding" // 已占位退款、等待支付渠道退款完成
+	OrderStatusRefunded  = "refunded"
+)
+
+// Subscription order type constants
+const (
+	OrderTypeSubscription = "subscription" // 购买分组订阅
+	OrderTypeBalance      = "balance"      // 余额充值
 )
 
 // Payment provider constants
 const (
-	PaymentProviderManual   = "manual"
-	PaymentProviderXunhuPay = "xunhupay"
+	PaymentProviderManual    = "manual"
+	PaymentProviderXunhuPay  = "xunhupay"
+	PaymentProviderStripe    = "stripe"
+	PaymentProviderAlipay    = "alipay"
+	PaymentProviderWeChatPay = "wechatpay"
 )
 
 // Currency constants
@@ -135,14 +146,36 @@ const (
 	SettingKeyPurchaseSubscriptionEnabled = "purchase_subscription_enabled" // 是否展示“购买订阅”页面入口
 	SettingKeyPurchaseSubscriptionURL     = "purchase_subscription_url"     // “购买订阅”页面 URL（作为 iframe src）
 	SettingKeyPurchaseInstructions        = "purchase_instructions"         // 购买订阅页面说明（支持 Markdown/HTML）
-	SettingKeyPaymentProvider             = "payment_provider"              // 支付渠道（manual/xunhupay）
+	SettingKeyPaymentProvider             = "payment_provider"              // 支付渠道（manual/xunhupay/stripe/alipay/wechatpay）
 	SettingKeyXunhuPayAppID               = "xunhupay_appid"
 	SettingKeyXunhuPayAppSecret           = "xunhupay_appsecret"
 	SettingKeyXunhuPayGateway             = "xunhupay_gateway"
 	SettingKeyXunhuPayNotifyURL           = "xunhupay_notify_url"
 	SettingKeyXunhuPayReturnURL           = "xunhupay_return_url"
 	SettingKeyXunhuPayPlugins             = "xunhupay_plugins"
-	SettingKeyCustomMenuItems             = "custom_menu_items" // 自定义菜单项（JSON 数组）
+	SettingKeyStripeSecretKey             = "stripe_secret_key"
+	SettingKeyStripeWebhookSecret         = "stripe_webhook_secret"
+	SettingKeyStripeCurrency              = "stripe_currency" // Checkout 结算币种（ISO 4217 小写，如 usd）
+	SettingKeyStripeSuccessURL            = "stripe_success_url"
+	SettingKeyStripeCancelURL             = "stripe_cancel_url"
+	SettingKeyAlipayAppID                 = "alipay_app_id"
+	SettingKeyAlipayPrivateKey            = "alipay_private_key" // 应用私钥（RSA2）
+	SettingKeyAlipayPublicKey             = "alipay_public_key"  // 支付宝公钥（用于验签）
+	SettingKeyAlipayGateway               = "alipay_gateway"
+	SettingKeyAlipayNotifyURL             = "alipay_notify_url"
+	SettingKeyAlipayReturnURL             = "alipay_return_url"
+	SettingKeyWeChatPayAppID              = "wechatpay_app_id"
+	SettingKeyWeChatPayMchID              = "wechatpay_mch_id"
+	SettingKeyWeChatPaySerialNo           = "wechatpay_serial_no"           // 商户 API 证书序列号
+	SettingKeyWeChatPayPrivateKey         = "wechatpay_private_key"         // 商户 API 私钥
+	SettingKeyWeChatPayAPIv3Key           = "wechatpay_api_v3_key"          // APIv3 密钥（回调解密）
+	SettingKeyWeChatPayPlatformPublicKey  = "wechatpay_platform_public_key" // 微信支付平台公钥/证书（用于验签）
+	SettingKeyWeChatPayNotifyURL          = "wechatpay_notify_url"
+	SettingKeyBalanceTopUpEnabled         = "balance_topup_enabled"    // 是否允许在线充值余额
+	SettingKeyBalanceTopUpMinAmount       = "balance_topup_min_amount" // 单笔充值最小金额
+	SettingKeyBalanceTopUpMaxAmount       = "balance_topup_max_amount" // 单笔充值最大金额（0 表示不限）
+	SettingKeyBalanceTopUpRate            = "balance_topup_rate"       // 每支付 1 单位货币到账的余额
+	SettingKeyCustomMenuItems             = "custom_menu_items"        // 自定义菜单项（JSON 数组）
 
 	// 默认配置
 	SettingKeyDefaultConcurrency   = "default_concurrency"   // 新用户默认并发量
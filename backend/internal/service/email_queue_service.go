@@ -13,14 +13,17 @@ import (
 const (
 	TaskTypeVerifyCode    = "verify_code"
 	TaskTypePasswordReset = "password_reset"
+	TaskTypeNotification  = "notification"
 )
 
 // EmailTask 邮件发送任务
 type EmailTask struct {
 	Email    string
 	SiteName string
-	TaskType string // "verify_code" or "password_reset"
+	TaskType string // "verify_code", "password_reset" or "notification"
 	ResetURL string // Only used for password_reset task type
+	Subject  string // Only used for notification task type
+	Body     string // Only used for notification task type (HTML)
 }
 
 // EmailQueueService 异步邮件队列服务
@@ -93,6 +96,12 @@ func (s *EmailQueueService) processTask(workerID int, task EmailTask) {
 		} else {
 			logger.LegacyPrintf("service.email_queue", "[EmailQueue] Worker %d sent password reset to %s", workerID, task.Email)
 		}
+	case TaskTypeNotification:
+		if err := s.emailService.SendEmail(ctx, task.Email, task.Subject, task.Body); err != nil {
+			logger.LegacyPrintf("service.email_queue", "[EmailQueue] Worker %d failed to send notification to %s: %v", workerID, task.Email, err)
+		} else {
+			logger.LegacyPrintf("service.email_queue", "[EmailQueue] Worker %d sent notification to %s", workerID, task.Email)
+		}
 	default:
 		logger.LegacyPrintf("service.email_queue", "[EmailQueue] Worker %d unknown task type: %s", workerID, task.TaskType)
 	}
@@ -133,6 +142,24 @@ func (s *EmailQueueService) EnqueuePasswordReset(email, siteName, resetURL strin
 	}
 }
 
+// EnqueueNotification 将通知邮件（已渲染的 HTML 正文）加入队列
+func (s *EmailQueueService) EnqueueNotification(email, subject, body string) error {
+	task := EmailTask{
+		Email:    email,
+		TaskType: TaskTypeNotification,
+		Subject:  subject,
+		Body:     body,
+	}
+
+	select {
+	case s.taskChan <- task:
+		logger.LegacyPrintf("service.email_queue", "[EmailQueue] Enqueued notification task for %s", email)
+		return nil
+	default:
+		return fmt.Errorf("email queue is full")
+	}
+}
+
 // Stop 停止队列服务
 func (s *EmailQueueService) Stop() {
 	close(s.stopChan)
@@ -1942,6 +1942,14 @@ func (m *mockConcurrencyCache) ReleaseUserSlot(ctx context.Context, userID int64
 	return nil
 }
 
+func (m *mockConcurrencyCache) AcquireAPIKeySlot(ctx context.Context, apiKeyID int64, maxConcurrency int, requestID string) (bool, error) {
+	return true, nil
+}
+
+func (m *mockConcurrencyCache) ReleaseAPIKeySlot(ctx context.Context, apiKeyID int64, requestID string) error {
+	return nil
+}
+
 func (m *mockConcurrencyCache) GetUserConcurrency(ctx context.Context, userID int64) (int, error) {
 	return 0, nil
 }
@@ -180,6 +180,17 @@ func ParseGatewayRequest(body []byte, protocol string) (*ParsedRequest, error) {
 	return parsed, nil
 }
 
+// ReplaceModel 改写请求模型（分组模型降级链使用），同步更新 Body 与 Model。
+func (p *ParsedRequest) ReplaceModel(model string) error {
+	body, err := sjson.SetBytes(p.Body, "model", model)
+	if err != nil {
+		return err
+	}
+	p.Body = body
+	p.Model = model
+	return nil
+}
+
 // sliceRawFromBody 返回 Result.Raw 对应的原始字节切片。
 // 优先使用 Result.Index 直接从 body 切片，避免对大字段（如 messages）产生额外拷贝。
 // 当 Index 不可用时，退化为复制（理论上极少发生）。
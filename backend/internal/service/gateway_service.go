@@ -24,6 +24,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/pkg/claude"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/usagestats"
 	"github.com/Wei-Shaw/sub2api/internal/util/responseheaders"
 	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
@@ -508,7 +509,7 @@ type GatewayService struct {
 	userSubRepo           UserSubscriptionRepository
 	userGroupRateRepo     UserGroupRateRepository
 	cache                 GatewayCache
-	digestStore           *DigestSessionStore
+	digestStore           DigestSessionCache
 	cfg                   *config.Config
 	schedulerSnapshot     *SchedulerSnapshotService
 	billingService        *BillingService
@@ -521,6 +522,7 @@ type GatewayService struct {
 	claudeTokenProvider   *ClaudeTokenProvider
 	sessionLimitCache     SessionLimitCache // 会话数量限制缓存（仅 Anthropic OAuth/SetupToken）
 	rpmCache              RPMCache          // RPM 计数缓存（仅 Anthropic OAuth/SetupToken）
+	healthScorer          *AccountHealthScorer
 	userGroupRateResolver *userGroupRateResolver
 	userGroupRateCache    *gocache.Cache
 	userGroupRateSF       singleflight.Group
@@ -552,7 +554,8 @@ func NewGatewayService(
 	claudeTokenProvider *ClaudeTokenProvider,
 	sessionLimitCache SessionLimitCache,
 	rpmCache RPMCache,
-	digestStore *DigestSessionStore,
+	digestStore DigestSessionCache,
+	healthScorer *AccountHealthScorer,
 ) *GatewayService {
 	userGroupRateTTL := resolveUserGroupRateCacheTTL(cfg)
 	modelsListTTL := resolveModelsListCacheTTL(cfg)
@@ -578,6 +581,7 @@ func NewGatewayService(
 		claudeTokenProvider:  claudeTokenProvider,
 		sessionLimitCache:    sessionLimitCache,
 		rpmCache:             rpmCache,
+		healthScorer:         healthScorer,
 		userGroupRateCache:   gocache.New(userGroupRateTTL, time.Minute),
 		modelsListCache:      gocache.New(modelsListTTL, time.Minute),
 		modelsListCacheTTL:   modelsListTTL,
@@ -680,37 +684,43 @@ func (s *GatewayService) GetCachedSessionAccountID(ctx context.Context, groupID
 
 // FindGeminiSession 查找 Gemini 会话（基于内容摘要链的 Fallback 匹配）
 // 返回最长匹配的会话信息（uuid, accountID）
-func (s *GatewayService) FindGeminiSession(_ context.Context, groupID int64, prefixHash, digestChain string) (uuid string, accountID int64, matchedChain string, found bool) {
-	if digestChain == "" || s.digestStore == nil {
-		return "", 0, "", false
-	}
-	return s.digestStore.Find(groupID, prefixHash, digestChain)
+func (s *GatewayService) FindGeminiSession(ctx context.Context, groupID int64, prefixHash, digestChain string) (uuid string, accountID int64, matchedChain string, found bool) {
+	return s.findDigestSession(ctx, groupID, prefixHash, digestChain)
 }
 
 // SaveGeminiSession 保存 Gemini 会话。oldDigestChain 为 Find 返回的 matchedChain，用于删旧 key。
-func (s *GatewayService) SaveGeminiSession(_ context.Context, groupID int64, prefixHash, digestChain, uuid string, accountID int64, oldDigestChain string) error {
-	if digestChain == "" || s.digestStore == nil {
-		return nil
-	}
-	s.digestStore.Save(groupID, prefixHash, digestChain, uuid, accountID, oldDigestChain)
-	return nil
+func (s *GatewayService) SaveGeminiSession(ctx context.Context, groupID int64, prefixHash, digestChain, uuid string, accountID int64, oldDigestChain string) error {
+	return s.saveDigestSession(ctx, groupID, prefixHash, digestChain, uuid, accountID, oldDigestChain)
 }
 
 // FindAnthropicSession 查找 Anthropic 会话（基于内容摘要链的 Fallback 匹配）
-func (s *GatewayService) FindAnthropicSession(_ context.Context, groupID int64, prefixHash, digestChain string) (uuid string, accountID int64, matchedChain string, found bool) {
+func (s *GatewayService) FindAnthropicSession(ctx context.Context, groupID int64, prefixHash, digestChain string) (uuid string, accountID int64, matchedChain string, found bool) {
+	return s.findDigestSession(ctx, groupID, prefixHash, digestChain)
+}
+
+// SaveAnthropicSession 保存 Anthropic 会话
+func (s *GatewayService) SaveAnthropicSession(ctx context.Context, groupID int64, prefixHash, digestChain, uuid string, accountID int64, oldDigestChain string) error {
+	return s.saveDigestSession(ctx, groupID, prefixHash, digestChain, uuid, accountID, oldDigestChain)
+}
+
+// findDigestSession 存储异常时按未命中处理（粘性为尽力而为，不阻断请求）
+func (s *GatewayService) findDigestSession(ctx context.Context, groupID int64, prefixHash, digestChain string) (string, int64, string, bool) {
 	if digestChain == "" || s.digestStore == nil {
 		return "", 0, "", false
 	}
-	return s.digestStore.Find(groupID, prefixHash, digestChain)
+	uuid, accountID, matchedChain, found, err := s.digestStore.FindDigestSession(ctx, groupID, prefixHash, digestChain)
+	if err != nil {
+		logger.LegacyPrintf("service.gateway", "[DigestSession] find failed, fallback to miss: group_id=%d err=%v", groupID, err)
+		return "", 0, "", false
+	}
+	return uuid, accountID, matchedChain, found
 }
 
-// SaveAnthropicSession 保存 Anthropic 会话
-func (s *GatewayService) SaveAnthropicSession(_ context.Context, groupID int64, prefixHash, digestChain, uuid string, accountID int64, oldDigestChain string) error {
+func (s *GatewayService) saveDigestSession(ctx context.Context, groupID int64, prefixHash, digestChain, uuid string, accountID int64, oldDigestChain string) error {
 	if digestChain == "" || s.digestStore == nil {
 		return nil
 	}
-	s.digestStore.Save(groupID, prefixHash, digestChain, uuid, accountID, oldDigestChain)
-	return nil
+	return s.digestStore.SaveDigestSession(ctx, groupID, prefixHash, digestChain, uuid, accountID, oldDigestChain)
 }
 
 func (s *GatewayService) extractCacheableContent(parsed *ParsedRequest) string {
@@ -1016,6 +1026,14 @@ func (s *GatewayService) SelectAccountForModel(ctx context.Context, groupID *int
 	return s.SelectAccountForModelWithExclusions(ctx, groupID, sessionHash, requestedModel, nil)
 }
 
+// GetAccountByID 按 ID 获取账号（用于断点恢复等必须沿用原账号的场景）
+func (s *GatewayService) GetAccountByID(ctx context.Context, accountID int64) (*Account, error) {
+	if s.accountRepo == nil {
+		return nil, errors.New("account repository not configured")
+	}
+	return s.accountRepo.GetByID(ctx, accountID)
+}
+
 // SelectAccountForModelWithExclusions selects an account supporting the requested model while excluding specified accounts.
 func (s *GatewayService) SelectAccountForModelWithExclusions(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}) (*Account, error) {
 	// 优先检查 context 中的强制平台（/antigravity 路由）
@@ -1050,6 +1068,30 @@ func (s *GatewayService) SelectAccountForModelWithExclusions(ctx context.Context
 // SelectAccountWithLoadAwareness selects account with load-awareness and wait plan.
 // metadataUserID: 已废弃参数，会话限制现在统一使用 sessionHash
 func (s *GatewayService) SelectAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, metadataUserID string) (*AccountSelectionResult, error) {
+	ctx, span := startSelectAccountSpan(ctx, requestedModel, excludedIDs)
+	start := time.Now()
+	selection, err := s.selectAccountWithLoadAwareness(ctx, groupID, sessionHash, requestedModel, excludedIDs, metadataUserID)
+	s.healthScorer.recordSelect(time.Since(start), err)
+	endSelectAccountSpan(span, selection, err)
+	return selection, err
+}
+
+// ReportAccountScheduleResult 上报转发结果，供健康评分使用（Anthropic/Gemini/Antigravity）
+func (s *GatewayService) ReportAccountScheduleResult(accountID int64, success bool, firstTokenMs *int) {
+	s.healthScorer.ReportResult(accountID, success, firstTokenMs)
+}
+
+// SnapshotAccountSchedulerMetrics 返回负载感知调度决策指标
+func (s *GatewayService) SnapshotAccountSchedulerMetrics() AccountSchedulerMetricsSnapshot {
+	return s.healthScorer.SnapshotMetrics()
+}
+
+// ListAccountHealth 返回账号运行时健康快照（最差在前）
+func (s *GatewayService) ListAccountHealth(limit int) []AccountHealthSnapshot {
+	return s.healthScorer.ListAccountHealth(limit)
+}
+
+func (s *GatewayService) selectAccountWithLoadAwareness(ctx context.Context, groupID *int64, sessionHash string, requestedModel string, excludedIDs map[int64]struct{}, metadataUserID string) (*AccountSelectionResult, error) {
 	// 调试日志：记录调度入口参数
 	excludedIDsList := make([]int64, 0, len(excludedIDs))
 	for id := range excludedIDs {
@@ -1524,6 +1566,7 @@ func (s *GatewayService) SelectAccountWithLoadAwareness(ctx context.Context, gro
 	loadMap, err := s.concurrencyService.GetAccountsLoadBatch(ctx, accountLoads)
 	if err != nil {
 		if result, ok := s.tryAcquireByLegacyOrder(ctx, candidates, groupID, sessionHash, preferOAuth); ok {
+			s.healthScorer.recordLayer(accountScheduleLayerLegacyOrder, len(candidates), false, 0)
 			return result, nil
 		}
 	} else {
@@ -1541,12 +1584,19 @@ func (s *GatewayService) SelectAccountWithLoadAwareness(ctx context.Context, gro
 			}
 		}
 
-		// 分层过滤选择：优先级 → 负载率 → LRU
+		// 分层过滤选择：优先级 → 健康评分（未启用时为负载率） → LRU
+		scored := s.healthScorer.Enabled()
+		candidateCount := len(available)
 		for len(available) > 0 {
 			// 1. 取优先级最小的集合
 			candidates := filterByMinPriority(available)
-			// 2. 取负载率最低的集合
-			candidates = filterByMinLoadRate(candidates)
+			// 2. 取健康评分最高的集合（负载/排队/错误率/TTFT/429-529 密度/token 刷新失败加权）
+			var score float64
+			if scored {
+				candidates, score = s.healthScorer.filterByBestScore(candidates)
+			} else {
+				candidates = filterByMinLoadRate(candidates)
+			}
 			// 3. LRU 选择最久未用的账号
 			selected := selectByLRU(candidates, preferOAuth)
 			if selected == nil {
@@ -1562,6 +1612,7 @@ func (s *GatewayService) SelectAccountWithLoadAwareness(ctx context.Context, gro
 					if sessionHash != "" && s.cache != nil {
 						_ = s.cache.SetSessionAccountID(ctx, derefGroupID(groupID), sessionHash, selected.account.ID, stickySessionTTL)
 					}
+					s.healthScorer.recordLayer(accountScheduleLayerLoadBalance, candidateCount, scored, score)
 					return &AccountSelectionResult{
 						Account:     selected.account,
 						Acquired:    true,
@@ -1589,6 +1640,7 @@ func (s *GatewayService) SelectAccountWithLoadAwareness(ctx context.Context, gro
 		if !s.checkAndRegisterSession(ctx, acc, sessionHash) {
 			continue // 会话限制已满，尝试下一个账号
 		}
+		s.healthScorer.recordLayer(accountScheduleLayerFallback, len(candidates), false, 0)
 		return &AccountSelectionResult{
 			Account: acc,
 			WaitPlan: &AccountWaitPlan{
@@ -3926,6 +3978,13 @@ func removeCacheControlFromThinkingBlocks(data map[string]any) {
 
 // Forward 转发请求到Claude API
 func (s *GatewayService) Forward(ctx context.Context, c *gin.Context, account *Account, parsed *ParsedRequest) (*ForwardResult, error) {
+	ctx, span := startUpstreamAttemptSpan(ctx, c, account)
+	result, err := s.forward(ctx, c, account, parsed)
+	endUpstreamAttemptSpan(span, c, err)
+	return result, err
+}
+
+func (s *GatewayService) forward(ctx context.Context, c *gin.Context, account *Account, parsed *ParsedRequest) (*ForwardResult, error) {
 	startTime := time.Now()
 	if parsed == nil {
 		return nil, fmt.Errorf("parse request: empty request")
@@ -6375,6 +6434,7 @@ type RecordUsageInput struct {
 	UserAgent         string             // 请求的 User-Agent
 	IPAddress         string             // 请求的客户端 IP 地址
 	ForceCacheBilling bool               // 强制缓存计费：将 input_tokens 转为 cache_read 计费（用于粘性会话切换）
+	PriceMultiplier   float64            // 可选：额外计费倍率（如批处理折扣），>0 时叠加到费率倍数
 	APIKeyService     APIKeyQuotaUpdater // 可选：用于更新API Key配额
 }
 
@@ -6382,6 +6442,15 @@ type RecordUsageInput struct {
 type APIKeyQuotaUpdater interface {
 	UpdateQuotaUsed(ctx context.Context, apiKeyID int64, cost float64) error
 	UpdateRateLimitUsage(ctx context.Context, apiKeyID int64, cost float64) error
+	RecordTokenUsage(ctx context.Context, apiKey *APIKey, tokens int)
+}
+
+// recordAPIKeyTokenUsage 累加 API Key 的每分钟 Token 用量（TPM 限制，简易模式同样生效）
+func recordAPIKeyTokenUsage(ctx context.Context, updater APIKeyQuotaUpdater, apiKey *APIKey, usageLog *UsageLog) {
+	if updater == nil || apiKey == nil || apiKey.TPMLimit <= 0 {
+		return
+	}
+	updater.RecordTokenUsage(ctx, apiKey, usageLog.TotalTokens())
 }
 
 // postUsageBillingParams 统一扣费所需的参数
@@ -6397,20 +6466,33 @@ type postUsageBillingParams struct {
 }
 
 // postUsageBilling 统一处理使用量记录后的扣费逻辑：
-//   - 订阅/余额扣费
+//   - 订阅/余额扣费（组织 Key 扣组织余额，并累加成员月度消费）
 //   - API Key 配额更新
 //   - API Key 限速用量更新
 //   - 账号配额用量更新（账号口径：TotalCost × 账号计费倍率）
+//
+// Account 为 nil 时（如响应缓存命中，未请求上游）跳过账号侧统计。
 func postUsageBilling(ctx context.Context, p *postUsageBillingParams, deps *billingDeps) {
 	cost := p.Cost
 
 	// 1. 订阅 / 余额扣费
+	isOrganizationKey := p.APIKey.IsOrganizationKey()
 	if p.IsSubscriptionBill {
 		if cost.TotalCost > 0 {
 			if err := deps.userSubRepo.IncrementUsage(ctx, p.Subscription.ID, cost.TotalCost); err != nil {
 				slog.Error("increment subscription usage failed", "subscription_id", p.Subscription.ID, "error", err)
 			}
-			deps.billingCacheService.QueueUpdateSubscriptionUsage(p.User.ID, *p.APIKey.GroupID, cost.TotalCost)
+			subscriptionUserID := p.User.ID
+			if isOrganizationKey {
+				subscriptionUserID = p.APIKey.SubscriptionUserID()
+			}
+			deps.billingCacheService.QueueUpdateSubscriptionUsage(subscriptionUserID, *p.APIKey.GroupID, cost.TotalCost)
+		}
+	} else if isOrganizationKey {
+		if cost.ActualCost > 0 {
+			if err := deps.billingCacheService.DeductOrganizationBalance(ctx, *p.APIKey.OrganizationID, cost.ActualCost); err != nil {
+				slog.Error("deduct organization balance failed", "organization_id", *p.APIKey.OrganizationID, "error", err)
+			}
 		}
 	} else {
 		if cost.ActualCost > 0 {
@@ -6421,6 +6503,17 @@ func postUsageBilling(ctx context.Context, p *postUsageBillingParams, deps *bill
 		}
 	}
 
+	// 1.1 组织成员月度消费（口径与实际扣费一致）
+	if isOrganizationKey {
+		memberCost := cost.ActualCost
+		if p.IsSubscriptionBill {
+			memberCost = cost.TotalCost
+		}
+		if err := deps.billingCacheService.RecordOrganizationMemberUsage(ctx, *p.APIKey.OrganizationID, p.APIKey.UserID, memberCost); err != nil {
+			slog.Error("record organization member usage failed", "organization_id", *p.APIKey.OrganizationID, "user_id", p.APIKey.UserID, "error", err)
+		}
+	}
+
 	// 2. API Key 配额
 	if cost.ActualCost > 0 && p.APIKey.Quota > 0 && p.APIKeyService != nil {
 		if err := p.APIKeyService.UpdateQuotaUsed(ctx, p.APIKey.ID, cost.ActualCost); err != nil {
@@ -6436,6 +6529,10 @@ func postUsageBilling(ctx context.Context, p *postUsageBillingParams, deps *bill
 		deps.billingCacheService.QueueUpdateAPIKeyRateLimitUsage(p.APIKey.ID, cost.ActualCost)
 	}
 
+	if p.Account == nil {
+		return
+	}
+
 	// 4. 账号配额用量（账号口径：TotalCost × 账号计费倍率）
 	if cost.TotalCost > 0 && p.Account.Type == AccountTypeAPIKey && p.Account.GetQuotaLimit() > 0 {
 		accountCost := cost.TotalCost * p.AccountRateMultiplier
@@ -6469,6 +6566,17 @@ func (s *GatewayService) billingDeps() *billingDeps {
 
 // RecordUsage 记录使用量并扣费（或更新订阅用量）
 func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInput) error {
+	var account *Account
+	if input != nil {
+		account = input.Account
+	}
+	ctx, span := startRecordUsageSpan(ctx, account)
+	err := s.recordUsage(ctx, input)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *GatewayService) recordUsage(ctx context.Context, input *RecordUsageInput) error {
 	result := input.Result
 	apiKey := input.APIKey
 	user := input.User
@@ -6500,6 +6608,9 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 		groupDefault := apiKey.Group.RateMultiplier
 		multiplier = s.getUserGroupRateMultiplier(ctx, user.ID, *apiKey.GroupID, groupDefault)
 	}
+	if input.PriceMultiplier > 0 {
+		multiplier *= input.PriceMultiplier
+	}
 
 	var cost *CostBreakdown
 
@@ -6621,6 +6732,9 @@ func (s *GatewayService) RecordUsage(ctx context.Context, input *RecordUsageInpu
 	if err != nil {
 		logger.LegacyPrintf("service.gateway", "Create usage log failed: %v", err)
 	}
+	if inserted || err != nil {
+		recordAPIKeyTokenUsage(ctx, input.APIKeyService, apiKey, usageLog)
+	}
 
 	if s.cfg != nil && s.cfg.RunMode == config.RunModeSimple {
 		logger.LegacyPrintf("service.gateway", "[SIMPLE MODE] Usage recorded (not billed): user=%d, tokens=%d", usageLog.UserID, usageLog.TotalTokens())
@@ -6795,6 +6909,9 @@ func (s *GatewayService) RecordUsageWithLongContext(ctx context.Context, input *
 	if err != nil {
 		logger.LegacyPrintf("service.gateway", "Create usage log failed: %v", err)
 	}
+	if inserted || err != nil {
+		recordAPIKeyTokenUsage(ctx, input.APIKeyService, apiKey, usageLog)
+	}
 
 	if s.cfg != nil && s.cfg.RunMode == config.RunModeSimple {
 		logger.LegacyPrintf("service.gateway", "[SIMPLE MODE] Usage recorded (not billed): user=%d, tokens=%d", usageLog.UserID, usageLog.TotalTokens())
@@ -0,0 +1,106 @@
+package service
+
+import (
+	"context"
+	"errors"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
+	"github.com/gin-gonic/gin"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// 网关链路 span 名称（调度、上游尝试、用量记录、Token 刷新）。
+const (
+	spanSelectAccount   = "gateway.select_account"
+	spanUpstreamAttempt = "gateway.upstream_attempt"
+	spanRecordUsage     = "gateway.record_usage"
+	spanTokenRefresh    = "oauth.refresh_account_token"
+)
+
+func accountSpanAttributes(account *Account) []attribute.KeyValue {
+	if account == nil {
+		return nil
+	}
+	return []attribute.KeyValue{
+		attribute.Int64("account.id", account.ID),
+		attribute.String("account.platform", account.Platform),
+		attribute.String("account.type", account.Type),
+	}
+}
+
+// startUpstreamAttemptSpan 为单次上游尝试创建 span（failover 时每个账号一个 span）。
+func startUpstreamAttemptSpan(ctx context.Context, c *gin.Context, account *Account) (context.Context, trace.Span) {
+	attrs := accountSpanAttributes(account)
+	if c != nil {
+		if v, ok := c.Get(OpsUpstreamErrorsKey); ok {
+			if events, ok := v.([]*OpsUpstreamErrorEvent); ok {
+				attrs = append(attrs, attribute.Int("gateway.attempt", len(events)+1))
+			}
+		} else {
+			attrs = append(attrs, attribute.Int("gateway.attempt", 1))
+		}
+	}
+	return tracing.Start(ctx, spanUpstreamAttempt, attrs...)
+}
+
+func endUpstreamAttemptSpan(span trace.Span, c *gin.Context, err error) {
+	if span.IsRecording() && err != nil {
+		var failoverErr *UpstreamFailoverError
+		if errors.As(err, &failoverErr) {
+			span.SetAttributes(
+				attribute.Int("upstream.status_code", failoverErr.StatusCode),
+				attribute.Bool("gateway.failover", true),
+			)
+		} else if c != nil {
+			if v, ok := c.Get(OpsUpstreamStatusCodeKey); ok {
+				if code, ok := v.(int); ok && code > 0 {
+					span.SetAttributes(attribute.Int("upstream.status_code", code))
+				}
+			}
+		}
+	}
+	tracing.End(span, err)
+}
+
+func startSelectAccountSpan(ctx context.Context, requestedModel string, excludedIDs map[int64]struct{}) (context.Context, trace.Span) {
+	return tracing.Start(ctx, spanSelectAccount,
+		attribute.String("gateway.requested_model", requestedModel),
+		attribute.Int("gateway.excluded_accounts", len(excludedIDs)),
+	)
+}
+
+func annotateOpenAIScheduleDecision(span trace.Span, decision OpenAIAccountScheduleDecision) {
+	if !span.IsRecording() {
+		return
+	}
+	span.SetAttributes(
+		attribute.String("scheduler.layer", decision.Layer),
+		attribute.Int("scheduler.candidates", decision.CandidateCount),
+		attribute.Bool("scheduler.sticky_previous_hit", decision.StickyPreviousHit),
+		attribute.Bool("scheduler.sticky_session_hit", decision.StickySessionHit),
+	)
+}
+
+func endSelectAccountSpan(span trace.Span, selection *AccountSelectionResult, err error) {
+	if span.IsRecording() && selection != nil {
+		span.SetAttributes(accountSpanAttributes(selection.Account)...)
+		span.SetAttributes(
+			attribute.Bool("concurrency.acquired", selection.Acquired),
+			attribute.Bool("concurrency.wait_plan", selection.WaitPlan != nil),
+		)
+	}
+	tracing.End(span, err)
+}
+
+func startRecordUsageSpan(ctx context.Context, account *Account) (context.Context, trace.Span) {
+	return tracing.Start(ctx, spanRecordUsage, accountSpanAttributes(account)...)
+}
+
+func startTokenRefreshSpan(ctx context.Context, account *Account) (context.Context, trace.Span) {
+	return tracing.Start(ctx, spanTokenRefresh, accountSpanAttributes(account)...)
+}
+
+func endTokenRefreshSpan(span trace.Span, err error) {
+	tracing.End(span, err)
+}
@@ -730,7 +730,10 @@ func isNonRetryableGeminiOAuthError(err error) bool {
 	return false
 }
 
-func (s *GeminiOAuthService) RefreshAccountToken(ctx context.Context, account *Account) (*GeminiTokenInfo, error) {
+func (s *GeminiOAuthService) RefreshAccountToken(ctx context.Context, account *Account) (_ *GeminiTokenInfo, err error) {
+	ctx, span := startTokenRefreshSpan(ctx, account)
+	defer func() { endTokenRefreshSpan(span, err) }()
+
 	if account.Platform != PlatformGemini || account.Type != AccountTypeOAuth {
 		return nil, fmt.Errorf("account is not a Gemini OAuth account")
 	}
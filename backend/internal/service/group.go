@@ -64,6 +64,14 @@ type Group struct {
 	AllowMessagesDispatch bool
 	DefaultMappedModel    string
 
+	// 精确响应缓存配置
+	ResponseCacheEnabled    bool
+	ResponseCacheTTLSeconds int
+	ResponseCachePriceRatio float64
+
+	// 模型降级链（按配置顺序匹配）
+	ModelFallbackChains []ModelFallbackChain
+
 	CreatedAt time.Time
 	UpdatedAt time.Time
 
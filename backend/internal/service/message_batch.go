@@ -0,0 +1,181 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// 批次处理状态（与 Anthropic Message Batches API 一致）
+const (
+	MessageBatchStatusInProgress = "in_progress"
+	MessageBatchStatusCanceling  = "canceling"
+	MessageBatchStatusEnded      = "ended"
+)
+
+// 批内请求状态；pending 包含执行中（租约期内 next_attempt_at 被后延）
+const (
+	MessageBatchItemPending   = "pending"
+	MessageBatchItemSucceeded = "succeeded"
+	MessageBatchItemErrored   = "errored"
+	MessageBatchItemCanceled  = "canceled"
+	MessageBatchItemExpired   = "expired"
+)
+
+const (
+	messageBatchIDPrefix     = "msgbatch_"
+	messageBatchMaxCustomID  = 64
+	MessageBatchListMaxLimit = 1000
+)
+
+var (
+	ErrMessageBatchNotFound    = infraerrors.NotFound("MESSAGE_BATCH_NOT_FOUND", "message batch not found")
+	ErrMessageBatchDisabled    = infraerrors.New(http.StatusServiceUnavailable, "MESSAGE_BATCH_DISABLED", "message batches are disabled")
+	ErrMessageBatchInvalid     = infraerrors.BadRequest("MESSAGE_BATCH_INVALID", "invalid message batch")
+	ErrMessageBatchNotEnded    = infraerrors.BadRequest("MESSAGE_BATCH_NOT_ENDED", "message batch is still processing; results are available once it has ended")
+	ErrMessageBatchUnsupported = infraerrors.BadRequest("MESSAGE_BATCH_UNSUPPORTED", "message batches are not supported for this group")
+)
+
+// MessageBatch Anthropic Message Batches 兼容批次。
+// 批内请求以创建批次的 API Key 身份调度与计费。
+type MessageBatch struct {
+	ID                string
+	UserID            int64
+	APIKeyID          int64
+	GroupID           *int64
+	ProcessingStatus  string
+	AnthropicBeta     string // 创建时的 anthropic-beta 请求头
+	RequestCounts     MessageBatchRequestCounts
+	ExpiresAt         time.Time
+	CancelInitiatedAt *time.Time
+	EndedAt           *time.Time
+	CreatedAt         time.Time
+}
+
+// MessageBatchRequestCounts 各状态的批内请求数；由仓储按 items 实时统计
+type MessageBatchRequestCounts struct {
+	Processing int `json:"processing"`
+	Succeeded  int `json:"succeeded"`
+	Errored    int `json:"errored"`
+	Canceled   int `json:"canceled"`
+	Expired    int `json:"expired"`
+}
+
+// MessageBatchItem 批内单个请求
+type MessageBatchItem struct {
+	ID            int64
+	BatchID       string
+	CustomID      string
+	Params        json.RawMessage
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	Result        json.RawMessage // 终态为 succeeded/errored 时的结果对象
+}
+
+// MessageBatchRequest 创建批次时的单个请求
+type MessageBatchRequest struct {
+	CustomID string          `json:"custom_id"`
+	Params   json.RawMessage `json:"params"`
+}
+
+// MessageBatchResult 结果 JSONL 中的一行
+type MessageBatchResult struct {
+	CustomID string          `json:"custom_id"`
+	Result   json.RawMessage `json:"result"`
+}
+
+// MessageBatchListParams 列表游标分页参数（按创建时间倒序）。
+// AfterID 返回该批次之后（更早）的一页，BeforeID 返回该批次之前（更新）的一页。
+type MessageBatchListParams struct {
+	Limit    int
+	BeforeID string
+	AfterID  string
+}
+
+// MessageBatchRepository 批次与批内请求仓储
+type MessageBatchRepository interface {
+	// Create 在同一事务中写入批次与全部批内请求
+	Create(ctx context.Context, batch *MessageBatch, items []MessageBatchItem) error
+	// GetByID 获取批次（含实时统计的 RequestCounts）
+	GetByID(ctx context.Context, id string) (*MessageBatch, error)
+	// List 按创建时间倒序列出用户的批次，返回是否还有更多
+	List(ctx context.Context, userID int64, params MessageBatchListParams) ([]MessageBatch, bool, error)
+	// RequestCancel 将进行中的批次置为 canceling，并取消尚未开始执行的请求
+	RequestCancel(ctx context.Context, id string) error
+	// ListItems 按 ID 升序分页读取批内请求（afterID 为游标）
+	ListItems(ctx context.Context, batchID string, afterID int64, limit int) ([]MessageBatchItem, error)
+
+	// ClaimDueItems 领取到期待执行的请求（仅限 in_progress 且未过期的批次），
+	// 并将 next_attempt_at 后延 lease，防止多实例重复执行
+	ClaimDueItems(ctx context.Context, limit int, lease time.Duration) ([]MessageBatchItem, error)
+	// SaveItemResult 保存执行结果（状态、尝试次数、下次执行时间与结果）
+	SaveItemResult(ctx context.Context, item *MessageBatchItem) error
+	// FinalizeBatches 取消 canceling 批次中未执行的请求、将过期批次中未执行的请求记为 expired，
+	// 并结束已无待执行请求的批次；返回本次结束的批次数
+	FinalizeBatches(ctx context.Context) (int, error)
+	// DeleteEndedBefore 删除在 cutoff 之前结束的批次及其请求
+	DeleteEndedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// messageBatchSucceededResult 构造 succeeded 结果对象
+func messageBatchSucceededResult(message []byte) json.RawMessage {
+	out, _ := json.Marshal(struct {
+		Type    string          `json:"type"`
+		Message json.RawMessage `json:"message"`
+	}{Type: MessageBatchItemSucceeded, Message: message})
+	return out
+}
+
+// messageBatchErroredResult 构造 errored 结果对象，error 字段与 Messages API 错误响应格式一致
+func messageBatchErroredResult(errType, message string) json.RawMessage {
+	out, _ := json.Marshal(map[string]any{
+		"type": MessageBatchItemErrored,
+		"error": map[string]any{
+			"type": "error",
+			"error": map[string]string{
+				"type":    errType,
+				"message": message,
+			},
+		},
+	})
+	return out
+}
+
+// messageBatchErroredResultFromBody 用上游/网关写出的错误响应体构造 errored 结果；
+// 无法识别为 Messages API 错误格式时回退为 api_error
+func messageBatchErroredResultFromBody(status int, body []byte, fallback string) json.RawMessage {
+	var parsed struct {
+		Type  string `json:"type"`
+		Error struct {
+			Type    string `json:"type"`
+			Message string `json:"message"`
+		} `json:"error"`
+	}
+	if err := json.Unmarshal(body, &parsed); err == nil && parsed.Error.Type != "" {
+		return messageBatchErroredResult(parsed.Error.Type, parsed.Error.Message)
+	}
+	if fallback == "" {
+		fallback = http.StatusText(status)
+	}
+	return messageBatchErroredResult("api_error", fallback)
+}
+
+// ResultObject 返回结果 JSONL 中该请求的 result 对象
+func (item *MessageBatchItem) ResultObject() json.RawMessage {
+	switch item.Status {
+	case MessageBatchItemSucceeded, MessageBatchItemErrored:
+		if len(item.Result) > 0 {
+			return item.Result
+		}
+		return messageBatchErroredResult("api_error", "result unavailable")
+	case MessageBatchItemCanceled, MessageBatchItemExpired:
+		out, _ := json.Marshal(map[string]string{"type": item.Status})
+		return out
+	default:
+		return nil
+	}
+}
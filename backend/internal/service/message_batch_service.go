@@ -0,0 +1,655 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"regexp"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/domain"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	messageBatchWorkerName = "message_batch_worker"
+
+	messageBatchLeaseMargin      = time.Minute
+	messageBatchFinalizeTimeout  = 30 * time.Second
+	messageBatchCleanupInterval  = time.Hour
+	messageBatchResultsPageSize  = 500
+	messageBatchDefaultListLimit = 20
+	messageBatchUserAgent        = "sub2api-message-batch"
+)
+
+var messageBatchCustomIDPattern = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// MessageBatchService 实现 Anthropic Message Batches API：
+// 接收批次并持久化批内请求，由后台 worker 通过常规账号调度异步执行。
+//
+// 批内请求是低优先级流量：只在账号其余请求的负载不超过 max_account_load_percent
+// 且没有排队中的交互请求时才执行，否则延后重试，直到批次过期。
+// 执行成功的请求按创建批次的 API Key 走常规计费流程，并叠加 price_multiplier 折扣。
+type MessageBatchService struct {
+	repo                      MessageBatchRepository
+	owners                    batchOwnerLoader
+	gatewayService            *GatewayService
+	antigravityGatewayService *AntigravityGatewayService
+	concurrencyService        *ConcurrencyService
+	apiKeyService             *APIKeyService
+	timingWheel               *TimingWheelService
+	cfg                       *config.Config
+	now                       func() time.Time
+
+	running     int32
+	started     atomic.Bool
+	startOnce   sync.Once
+	stopOnce    sync.Once
+	lastCleanup atomic.Int64
+
+	workerCtx    context.Context
+	workerCancel context.CancelFunc
+}
+
+// NewMessageBatchService 创建 Message Batches 服务
+func NewMessageBatchService(
+	repo MessageBatchRepository,
+	apiKeyRepo APIKeyRepository,
+	orgRepo OrganizationRepository,
+	gatewayService *GatewayService,
+	antigravityGatewayService *AntigravityGatewayService,
+	concurrencyService *ConcurrencyService,
+	subscriptionService *SubscriptionService,
+	billingCacheService *BillingCacheService,
+	apiKeyService *APIKeyService,
+	timingWheel *TimingWheelService,
+	cfg *config.Config,
+) *MessageBatchService {
+	workerCtx, workerCancel := context.WithCancel(context.Background())
+	return &MessageBatchService{
+		repo: repo,
+		owners: batchOwnerLoader{
+			apiKeyRepo:          apiKeyRepo,
+			orgRepo:             orgRepo,
+			subscriptionService: subscriptionService,
+			billingCacheService: billingCacheService,
+		},
+		gatewayService:            gatewayService,
+		antigravityGatewayService: antigravityGatewayService,
+		concurrencyService:        concurrencyService,
+		apiKeyService:             apiKeyService,
+		timingWheel:               timingWheel,
+		cfg:                       cfg,
+		now:                       time.Now,
+		workerCtx:                 workerCtx,
+		workerCancel:              workerCancel,
+	}
+}
+
+// Enabled 是否开放 Message Batches
+func (s *MessageBatchService) Enabled() bool {
+	return s != nil && s.repo != nil && s.cfg != nil && s.cfg.MessageBatch.Enabled
+}
+
+func (s *MessageBatchService) Start() {
+	if !s.Enabled() {
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] not started (disabled)")
+		return
+	}
+	if s.timingWheel == nil || s.gatewayService == nil {
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] not started (missing deps)")
+		return
+	}
+	s.startOnce.Do(func() {
+		interval := time.Duration(s.cfg.MessageBatch.WorkerIntervalSeconds) * time.Second
+		s.timingWheel.ScheduleRecurring(messageBatchWorkerName, interval, s.runOnce)
+		s.started.Store(true)
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] started (interval=%s concurrency=%d max_account_load=%d%% price_multiplier=%.2f)",
+			interval, s.cfg.MessageBatch.Concurrency, s.cfg.MessageBatch.MaxAccountLoadPercent, s.cfg.MessageBatch.PriceMultiplier)
+	})
+}
+
+func (s *MessageBatchService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		s.started.Store(false)
+		if s.workerCancel != nil {
+			s.workerCancel()
+		}
+		if s.timingWheel != nil {
+			s.timingWheel.Cancel(messageBatchWorkerName)
+		}
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] stopped")
+	})
+}
+
+// =========================
+// 批次 API
+// =========================
+
+// CreateMessageBatchInput 创建批次参数
+type CreateMessageBatchInput struct {
+	APIKey        *APIKey
+	Requests      []MessageBatchRequest
+	AnthropicBeta string
+}
+
+// Create 校验并持久化批次，批内请求随后由 worker 异步执行（计费资格由调用方预先校验）
+func (s *MessageBatchService) Create(ctx context.Context, in CreateMessageBatchInput) (*MessageBatch, error) {
+	if !s.Enabled() {
+		return nil, ErrMessageBatchDisabled
+	}
+	apiKey := in.APIKey
+	if apiKey == nil || apiKey.Group == nil || apiKey.GroupID == nil {
+		return nil, ErrMessageBatchUnsupported
+	}
+	if err := checkMessageBatchGroup(apiKey.Group); err != nil {
+		return nil, err
+	}
+	items, err := s.buildItems(apiKey, in.Requests)
+	if err != nil {
+		return nil, err
+	}
+	id, err := generateMessageBatchID()
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	groupID := *apiKey.GroupID
+	batch := &MessageBatch{
+		ID:               id,
+		UserID:           apiKey.UserID,
+		APIKeyID:         apiKey.ID,
+		GroupID:          &groupID,
+		ProcessingStatus: MessageBatchStatusInProgress,
+		AnthropicBeta:    strings.TrimSpace(in.AnthropicBeta),
+		RequestCounts:    MessageBatchRequestCounts{Processing: len(items)},
+		ExpiresAt:        now.Add(time.Duration(s.cfg.MessageBatch.ExpireHours) * time.Hour),
+		CreatedAt:        now,
+	}
+	for i := range items {
+		items[i].BatchID = id
+	}
+	if err := s.repo.Create(ctx, batch, items); err != nil {
+		return nil, fmt.Errorf("create message batch: %w", err)
+	}
+	s.kick()
+	return batch, nil
+}
+
+// checkMessageBatchGroup 批次只支持走 Claude Messages 调度的分组；
+// 仅限 Claude Code 的分组无法在后台执行（批内请求不是 Claude Code 客户端）
+func checkMessageBatchGroup(group *Group) error {
+	switch group.Platform {
+	case PlatformAnthropic, PlatformAntigravity:
+	default:
+		return ErrMessageBatchUnsupported
+	}
+	if group.ClaudeCodeOnly {
+		return infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchUnsupported.Reason, "message batches are not available for Claude Code only groups")
+	}
+	return nil
+}
+
+func (s *MessageBatchService) buildItems(apiKey *APIKey, requests []MessageBatchRequest) ([]MessageBatchItem, error) {
+	if len(requests) == 0 {
+		return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "requests must contain at least one request")
+	}
+	if limit := s.cfg.MessageBatch.MaxRequestsPerBatch; len(requests) > limit {
+		return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "a batch can contain at most %d requests", limit)
+	}
+
+	seen := make(map[string]struct{}, len(requests))
+	items := make([]MessageBatchItem, 0, len(requests))
+	for i, req := range requests {
+		if !messageBatchCustomIDPattern.MatchString(req.CustomID) {
+			return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason,
+				"requests.%d.custom_id must be 1-%d characters of letters, digits, '-' or '_'", i, messageBatchMaxCustomID)
+		}
+		if _, dup := seen[req.CustomID]; dup {
+			return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "duplicate custom_id %q", req.CustomID)
+		}
+		seen[req.CustomID] = struct{}{}
+
+		params := bytes.TrimSpace(req.Params)
+		if len(params) == 0 || params[0] != '{' {
+			return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "requests.%d.params must be an object", i)
+		}
+		parsed, err := ParseGatewayRequest(params, domain.PlatformAnthropic)
+		if err != nil {
+			return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "requests.%d.params is not a valid Messages request", i)
+		}
+		if parsed.Model == "" {
+			return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "requests.%d.params.model is required", i)
+		}
+		if parsed.Stream {
+			return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "requests.%d.params.stream is not supported in batches", i)
+		}
+		resolved := apiKey.ResolveModelAlias(parsed.Model)
+		if !apiKey.IsModelAllowed(resolved) {
+			return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "requests.%d.params.model %q is not allowed for this API key", i, parsed.Model)
+		}
+		// 与实时 /v1/messages 一致：落库前把别名改写为真实模型，执行器直接按 params.model 转发
+		if resolved != parsed.Model {
+			rewritten, err := sjson.SetBytes(params, "model", resolved)
+			if err != nil {
+				return nil, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "requests.%d.params is not a valid Messages request", i)
+			}
+			params = rewritten
+		}
+		items = append(items, MessageBatchItem{
+			CustomID: req.CustomID,
+			Params:   json.RawMessage(params),
+			Status:   MessageBatchItemPending,
+		})
+	}
+	return items, nil
+}
+
+func generateMessageBatchID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate message batch id: %w", err)
+	}
+	return messageBatchIDPrefix + hex.EncodeToString(buf), nil
+}
+
+// Get 获取用户的批次
+func (s *MessageBatchService) Get(ctx context.Context, userID int64, id string) (*MessageBatch, error) {
+	if !s.Enabled() {
+		return nil, ErrMessageBatchDisabled
+	}
+	if !strings.HasPrefix(id, messageBatchIDPrefix) {
+		return nil, ErrMessageBatchNotFound
+	}
+	batch, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.UserID != userID {
+		return nil, ErrMessageBatchNotFound
+	}
+	return batch, nil
+}
+
+// List 按创建时间倒序列出用户的批次
+func (s *MessageBatchService) List(ctx context.Context, userID int64, params MessageBatchListParams) ([]MessageBatch, bool, error) {
+	if !s.Enabled() {
+		return nil, false, ErrMessageBatchDisabled
+	}
+	if params.Limit <= 0 {
+		params.Limit = messageBatchDefaultListLimit
+	}
+	if params.Limit > MessageBatchListMaxLimit {
+		params.Limit = MessageBatchListMaxLimit
+	}
+	if params.BeforeID != "" && params.AfterID != "" {
+		return nil, false, infraerrors.Newf(http.StatusBadRequest, ErrMessageBatchInvalid.Reason, "before_id and after_id cannot be used together")
+	}
+	return s.repo.List(ctx, userID, params)
+}
+
+// Cancel 取消批次：尚未执行的请求立即记为 canceled，执行中的请求完成后批次结束
+func (s *MessageBatchService) Cancel(ctx context.Context, userID int64, id string) (*MessageBatch, error) {
+	batch, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.ProcessingStatus != MessageBatchStatusInProgress {
+		return batch, nil
+	}
+	if err := s.repo.RequestCancel(ctx, id); err != nil {
+		return nil, fmt.Errorf("cancel message batch: %w", err)
+	}
+	if _, err := s.repo.FinalizeBatches(ctx); err != nil {
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] finalize after cancel failed: batch=%s err=%v", id, err)
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+// Results 按请求顺序逐行输出已结束批次的结果；校验失败时在输出任何内容前返回错误
+func (s *MessageBatchService) Results(ctx context.Context, userID int64, id string, emit func(MessageBatchResult) error) error {
+	batch, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if batch.ProcessingStatus != MessageBatchStatusEnded {
+		return ErrMessageBatchNotEnded
+	}
+	var afterID int64
+	for {
+		items, err := s.repo.ListItems(ctx, id, afterID, messageBatchResultsPageSize)
+		if err != nil {
+			return err
+		}
+		for i := range items {
+			result := items[i].ResultObject()
+			if result == nil {
+				continue
+			}
+			if err := emit(MessageBatchResult{CustomID: items[i].CustomID, Result: result}); err != nil {
+				return err
+			}
+		}
+		if len(items) < messageBatchResultsPageSize {
+			return nil
+		}
+		afterID = items[len(items)-1].ID
+	}
+}
+
+// kick 新批次创建后立即触发一轮执行，而不必等待下一个轮询周期
+func (s *MessageBatchService) kick() {
+	if s.started.Load() {
+		go s.runOnce()
+	}
+}
+
+// =========================
+// 执行
+// =========================
+
+// messageBatchExecContext 单轮执行中同一批次共享的调度与计费上下文
+type messageBatchExecContext struct {
+	batch *MessageBatch
+	batchOwner
+}
+
+// messageBatchOutcome 单个请求的执行结果
+type messageBatchOutcome int
+
+const (
+	messageBatchOutcomeDone     messageBatchOutcome = iota // 已得到终态结果
+	messageBatchOutcomeBusy                                // 无空闲账号，延后且不计入尝试次数
+	messageBatchOutcomeRetry                               // 上游失败，延后并计入尝试次数
+	messageBatchOutcomeCanceled                            // worker 停止，释放租约
+)
+
+func (s *MessageBatchService) runOnce() {
+	if !s.Enabled() {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&s.running, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&s.running, 0)
+
+	s.processDueItems()
+	s.finalize()
+	s.cleanup()
+}
+
+func (s *MessageBatchService) processDueItems() {
+	ctx := s.workerCtx
+	cfg := s.cfg.MessageBatch
+	lease := time.Duration(cfg.RequestTimeoutSeconds)*time.Second + messageBatchLeaseMargin
+	items, err := s.repo.ClaimDueItems(ctx, cfg.ClaimSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.LegacyPrintf("service.message_batch", "[MessageBatch] claim items failed: %v", err)
+		}
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	execs := make(map[string]*messageBatchExecContext)
+	for i := range items {
+		id := items[i].BatchID
+		if _, ok := execs[id]; !ok {
+			execs[id] = s.loadExecContext(ctx, id)
+		}
+	}
+
+	sem := make(chan struct{}, max(1, cfg.Concurrency))
+	var wg sync.WaitGroup
+	for i := range items {
+		item := &items[i]
+		exec := execs[item.BatchID]
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			s.settle(item, s.executeItem(ctx, exec, item))
+		}()
+	}
+	wg.Wait()
+}
+
+// loadExecContext 加载批次所属 API Key、订阅，并校验计费资格
+func (s *MessageBatchService) loadExecContext(ctx context.Context, batchID string) *messageBatchExecContext {
+	exec := &messageBatchExecContext{}
+	batch, err := s.repo.GetByID(ctx, batchID)
+	if err != nil {
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] load batch failed: batch=%s err=%v", batchID, err)
+		exec.fail("api_error", "internal error")
+		return exec
+	}
+	exec.batch = batch
+	exec.batchOwner, err = s.owners.load(ctx, batch.APIKeyID, batch.GroupID)
+	if err != nil {
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] load batch owner failed: batch=%s err=%v", batchID, err)
+	}
+	return exec
+}
+
+// executeItem 执行单个请求：低优先级选号 → 转发 → 计费
+func (s *MessageBatchService) executeItem(ctx context.Context, exec *messageBatchExecContext, item *MessageBatchItem) messageBatchOutcome {
+	if exec.errType != "" {
+		item.Status = MessageBatchItemErrored
+		item.Result = messageBatchErroredResult(exec.errType, exec.errMessage)
+		return messageBatchOutcomeDone
+	}
+	parsed, err := ParseGatewayRequest(item.Params, domain.PlatformAnthropic)
+	if err != nil {
+		item.Status = MessageBatchItemErrored
+		item.Result = messageBatchErroredResult("invalid_request_error", "failed to parse request params")
+		return messageBatchOutcomeDone
+	}
+
+	apiKey := exec.apiKey
+	maxSwitches := 10
+	if s.cfg.Gateway.MaxAccountSwitches > 0 {
+		maxSwitches = s.cfg.Gateway.MaxAccountSwitches
+	}
+	excluded := make(map[int64]struct{})
+	outcome := messageBatchOutcomeBusy
+	var lastFailover *UpstreamFailoverError
+	for switches := 0; switches <= maxSwitches; switches++ {
+		if ctx.Err() != nil {
+			return messageBatchOutcomeCanceled
+		}
+		selection, err := s.gatewayService.SelectAccountWithLoadAwareness(ctx, apiKey.GroupID, "", parsed.Model, excluded, "")
+		if err != nil || selection == nil || selection.Account == nil {
+			break
+		}
+		account := selection.Account
+		release, ok := acquireBatchSpareSlot(ctx, s.concurrencyService, selection, s.cfg.MessageBatch.MaxAccountLoadPercent)
+		if !ok {
+			excluded[account.ID] = struct{}{}
+			continue
+		}
+
+		result, status, body, err := s.forward(ctx, exec.batch, account, parsed)
+		release()
+		if err != nil {
+			var failoverErr *UpstreamFailoverError
+			if errors.As(err, &failoverErr) {
+				excluded[account.ID] = struct{}{}
+				lastFailover = failoverErr
+				outcome = messageBatchOutcomeRetry
+				continue
+			}
+			if ctx.Err() != nil {
+				return messageBatchOutcomeCanceled
+			}
+			var promptTooLongErr *PromptTooLongError
+			if errors.As(err, &promptTooLongErr) {
+				status, body = promptTooLongErr.StatusCode, promptTooLongErr.Body
+			}
+			item.Status = MessageBatchItemErrored
+			item.Result = messageBatchErroredResultFromBody(status, body, err.Error())
+			return messageBatchOutcomeDone
+		}
+		if status >= http.StatusBadRequest {
+			item.Status = MessageBatchItemErrored
+			item.Result = messageBatchErroredResultFromBody(status, body, "")
+			return messageBatchOutcomeDone
+		}
+
+		item.Status = MessageBatchItemSucceeded
+		item.Result = messageBatchSucceededResult(body)
+		s.recordUsage(ctx, exec, account, result)
+		return messageBatchOutcomeDone
+	}
+
+	if outcome == messageBatchOutcomeRetry && item.Attempts >= s.cfg.MessageBatch.MaxAttempts {
+		item.Status = MessageBatchItemErrored
+		message := "upstream unavailable"
+		if lastFailover != nil {
+			item.Result = messageBatchErroredResultFromBody(lastFailover.StatusCode, lastFailover.ResponseBody, message)
+		} else {
+			item.Result = messageBatchErroredResult("overloaded_error", message)
+		}
+		return messageBatchOutcomeDone
+	}
+	return outcome
+}
+
+// forward 以非流式请求转发到上游，返回网关写出的状态码与响应体
+func (s *MessageBatchService) forward(ctx context.Context, batch *MessageBatch, account *Account, parsed *ParsedRequest) (*ForwardResult, int, []byte, error) {
+	timeout := time.Duration(s.cfg.MessageBatch.RequestTimeoutSeconds) * time.Second
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequestWithContext(reqCtx, http.MethodPost, "http://localhost/v1/messages", bytes.NewReader(parsed.Body))
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("anthropic-version", "2023-06-01")
+	req.Header.Set("user-agent", messageBatchUserAgent)
+	if batch != nil && batch.AnthropicBeta != "" {
+		req.Header.Set("anthropic-beta", batch.AnthropicBeta)
+	}
+	c.Request = req
+
+	var (
+		result *ForwardResult
+		err    error
+	)
+	if account.Platform == PlatformAntigravity && account.Type != AccountTypeAPIKey && s.antigravityGatewayService != nil {
+		result, err = s.antigravityGatewayService.Forward(reqCtx, c, account, parsed.Body, false)
+	} else {
+		result, err = s.gatewayService.Forward(reqCtx, c, account, parsed)
+	}
+	return result, w.Code, w.Body.Bytes(), err
+}
+
+func (s *MessageBatchService) recordUsage(ctx context.Context, exec *messageBatchExecContext, account *Account, result *ForwardResult) {
+	if result == nil {
+		return
+	}
+	input := &RecordUsageInput{
+		Result:          result,
+		APIKey:          exec.apiKey,
+		User:            exec.apiKey.User,
+		Account:         account,
+		Subscription:    exec.subscription,
+		UserAgent:       messageBatchUserAgent,
+		PriceMultiplier: s.cfg.MessageBatch.PriceMultiplier,
+	}
+	if s.apiKeyService != nil {
+		input.APIKeyService = s.apiKeyService
+	}
+	// 计费不随 worker 停止而中断
+	usageCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), messageBatchFinalizeTimeout)
+	defer cancel()
+	if err := s.gatewayService.RecordUsage(usageCtx, input); err != nil {
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] record usage failed: batch=%s api_key=%d err=%v",
+			exec.batch.ID, exec.apiKey.ID, err)
+	}
+}
+
+// settle 根据执行结果保存请求状态
+func (s *MessageBatchService) settle(item *MessageBatchItem, outcome messageBatchOutcome) {
+	retryAt := s.now().Add(time.Duration(s.cfg.MessageBatch.RetryDelaySeconds) * time.Second)
+	switch outcome {
+	case messageBatchOutcomeDone:
+	case messageBatchOutcomeBusy:
+		// 账号繁忙不算失败
+		item.Attempts--
+		item.NextAttemptAt = retryAt
+	case messageBatchOutcomeRetry:
+		item.NextAttemptAt = retryAt
+	case messageBatchOutcomeCanceled:
+		item.Attempts--
+		item.NextAttemptAt = s.now()
+	}
+	if item.Attempts < 0 {
+		item.Attempts = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), messageBatchFinalizeTimeout)
+	defer cancel()
+	if err := s.repo.SaveItemResult(ctx, item); err != nil {
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] save item failed: batch=%s item=%d err=%v", item.BatchID, item.ID, err)
+	}
+}
+
+func (s *MessageBatchService) finalize() {
+	ctx, cancel := context.WithTimeout(s.workerCtx, messageBatchFinalizeTimeout)
+	defer cancel()
+	ended, err := s.repo.FinalizeBatches(ctx)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.LegacyPrintf("service.message_batch", "[MessageBatch] finalize batches failed: %v", err)
+		}
+		return
+	}
+	if ended > 0 {
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] %d batch(es) ended", ended)
+	}
+}
+
+// cleanup 每小时删除一次超过保留期的已结束批次
+func (s *MessageBatchService) cleanup() {
+	now := s.now()
+	last := s.lastCleanup.Load()
+	if last > 0 && now.Sub(time.Unix(0, last)) < messageBatchCleanupInterval {
+		return
+	}
+	s.lastCleanup.Store(now.UnixNano())
+
+	ctx, cancel := context.WithTimeout(s.workerCtx, messageBatchFinalizeTimeout)
+	defer cancel()
+	cutoff := now.AddDate(0, 0, -s.cfg.MessageBatch.RetentionDays)
+	deleted, err := s.repo.DeleteEndedBefore(ctx, cutoff)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.LegacyPrintf("service.message_batch", "[MessageBatch] cleanup failed: %v", err)
+		}
+		return
+	}
+	if deleted > 0 {
+		logger.LegacyPrintf("service.message_batch", "[MessageBatch] deleted %d expired batch(es)", deleted)
+	}
+}
@@ -0,0 +1,341 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type messageBatchRepoStub struct {
+	MessageBatchRepository
+
+	mu      sync.Mutex
+	batches map[string]*MessageBatch
+	items   []MessageBatchItem
+	claimed []MessageBatchItem
+	saved   []MessageBatchItem
+}
+
+func newMessageBatchRepoStub() *messageBatchRepoStub {
+	return &messageBatchRepoStub{batches: map[string]*MessageBatch{}}
+}
+
+func (r *messageBatchRepoStub) Create(_ context.Context, batch *MessageBatch, items []MessageBatchItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *batch
+	r.batches[batch.ID] = &cp
+	for i := range items {
+		items[i].ID = int64(len(r.items) + 1)
+		r.items = append(r.items, items[i])
+	}
+	return nil
+}
+
+func (r *messageBatchRepoStub) GetByID(_ context.Context, id string) (*MessageBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.batches[id]
+	if !ok {
+		return nil, ErrMessageBatchNotFound
+	}
+	cp := *b
+	return &cp, nil
+}
+
+func (r *messageBatchRepoStub) ListItems(_ context.Context, batchID string, afterID int64, limit int) ([]MessageBatchItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []MessageBatchItem
+	for _, item := range r.items {
+		if item.BatchID == batchID && item.ID > afterID && len(out) < limit {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func (r *messageBatchRepoStub) ClaimDueItems(context.Context, int, time.Duration) ([]MessageBatchItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.claimed
+	r.claimed = nil
+	return out, nil
+}
+
+func (r *messageBatchRepoStub) SaveItemResult(_ context.Context, item *MessageBatchItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved = append(r.saved, *item)
+	return nil
+}
+
+type messageBatchAPIKeyRepoStub struct {
+	APIKeyRepository
+	keys map[int64]*APIKey
+}
+
+func (r *messageBatchAPIKeyRepoStub) GetByID(_ context.Context, id int64) (*APIKey, error) {
+	key, ok := r.keys[id]
+	if !ok {
+		return nil, ErrAPIKeyNotFound
+	}
+	cp := *key
+	return &cp, nil
+}
+
+func newMessageBatchTestConfig() *config.Config {
+	return &config.Config{MessageBatch: config.MessageBatchConfig{
+		Enabled:               true,
+		MaxRequestsPerBatch:   3,
+		WorkerIntervalSeconds: 5,
+		ClaimSize:             10,
+		Concurrency:           2,
+		MaxAccountLoadPercent: 50,
+		RequestTimeoutSeconds: 60,
+		MaxAttempts:           3,
+		RetryDelaySeconds:     30,
+		ExpireHours:           24,
+		RetentionDays:         29,
+		PriceMultiplier:       0.5,
+	}}
+}
+
+func newMessageBatchTestAPIKey() *APIKey {
+	groupID := int64(7)
+	return &APIKey{
+		ID:      11,
+		UserID:  42,
+		Status:  StatusActive,
+		GroupID: &groupID,
+		Group:   &Group{ID: groupID, Platform: PlatformAnthropic, Status: StatusActive},
+		User:    &User{ID: 42, Status: StatusActive},
+	}
+}
+
+func batchRequest(customID, params string) MessageBatchRequest {
+	return MessageBatchRequest{CustomID: customID, Params: json.RawMessage(params)}
+}
+
+const messageBatchTestParams = `{"model":"claude-sonnet-4-5","max_tokens":16,"messages":[{"role":"user","content":"hi"}]}`
+
+func TestMessageBatchCreate_PersistsItems(t *testing.T) {
+	repo := newMessageBatchRepoStub()
+	svc := NewMessageBatchService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, newMessageBatchTestConfig())
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	batch, err := svc.Create(context.Background(), CreateMessageBatchInput{
+		APIKey:        newMessageBatchTestAPIKey(),
+		Requests:      []MessageBatchRequest{batchRequest("req-1", messageBatchTestParams), batchRequest("req_2", messageBatchTestParams)},
+		AnthropicBeta: " output-128k-2025-02-19 ",
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(batch.ID, messageBatchIDPrefix))
+	require.Equal(t, MessageBatchStatusInProgress, batch.ProcessingStatus)
+	require.Equal(t, 2, batch.RequestCounts.Processing)
+	require.Equal(t, now.Add(24*time.Hour), batch.ExpiresAt)
+	require.Equal(t, "output-128k-2025-02-19", batch.AnthropicBeta)
+	require.Len(t, repo.items, 2)
+	require.Equal(t, batch.ID, repo.items[0].BatchID)
+	require.Equal(t, MessageBatchItemPending, repo.items[0].Status)
+}
+
+func TestMessageBatchCreate_Validation(t *testing.T) {
+	svc := NewMessageBatchService(newMessageBatchRepoStub(), nil, nil, nil, nil, nil, nil, nil, nil, nil, newMessageBatchTestConfig())
+
+	cases := map[string][]MessageBatchRequest{
+		"empty":          nil,
+		"too many":       {batchRequest("a", messageBatchTestParams), batchRequest("b", messageBatchTestParams), batchRequest("c", messageBatchTestParams), batchRequest("d", messageBatchTestParams)},
+		"bad custom id":  {batchRequest("has space", messageBatchTestParams)},
+		"duplicate":      {batchRequest("a", messageBatchTestParams), batchRequest("a", messageBatchTestParams)},
+		"params array":   {batchRequest("a", `[]`)},
+		"missing model":  {batchRequest("a", `{"max_tokens":1,"messages":[]}`)},
+		"stream":         {batchRequest("a", `{"model":"claude-sonnet-4-5","stream":true,"messages":[]}`)},
+		"custom id long": {batchRequest(strings.Repeat("x", 65), messageBatchTestParams)},
+	}
+	for name, requests := range cases {
+		t.Run(name, func(t *testing.T) {
+			_, err := svc.Create(context.Background(), CreateMessageBatchInput{APIKey: newMessageBatchTestAPIKey(), Requests: requests})
+			require.ErrorIs(t, err, ErrMessageBatchInvalid)
+		})
+	}
+}
+
+func TestMessageBatchCreate_RejectsUnsupportedGroups(t *testing.T) {
+	svc := NewMessageBatchService(newMessageBatchRepoStub(), nil, nil, nil, nil, nil, nil, nil, nil, nil, newMessageBatchTestConfig())
+	requests := []MessageBatchRequest{batchRequest("a", messageBatchTestParams)}
+
+	key := newMessageBatchTestAPIKey()
+	key.Group.Platform = PlatformOpenAI
+	_, err := svc.Create(context.Background(), CreateMessageBatchInput{APIKey: key, Requests: requests})
+	require.ErrorIs(t, err, ErrMessageBatchUnsupported)
+
+	key = newMessageBatchTestAPIKey()
+	key.Group.ClaudeCodeOnly = true
+	_, err = svc.Create(context.Background(), CreateMessageBatchInput{APIKey: key, Requests: requests})
+	require.ErrorIs(t, err, ErrMessageBatchUnsupported)
+
+	key = newMessageBatchTestAPIKey()
+	key.DeniedModels = []string{"claude-sonnet-4-5"}
+	_, err = svc.Create(context.Background(), CreateMessageBatchInput{APIKey: key, Requests: requests})
+	require.ErrorIs(t, err, ErrMessageBatchInvalid)
+}
+
+func TestMessageBatchCreate_ResolvesModelAliases(t *testing.T) {
+	repo := newMessageBatchRepoStub()
+	svc := NewMessageBatchService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, newMessageBatchTestConfig())
+
+	key := newMessageBatchTestAPIKey()
+	key.ModelAliases = map[string]string{"fast": "claude-haiku-4-5", "smart": "claude-opus-4-1"}
+	key.DeniedModels = []string{"claude-opus-*"}
+
+	aliased := `{"model":"fast","max_tokens":16,"messages":[{"role":"user","content":"hi"}]}`
+	_, err := svc.Create(context.Background(), CreateMessageBatchInput{APIKey: key, Requests: []MessageBatchRequest{batchRequest("a", aliased)}})
+	require.NoError(t, err)
+	require.Len(t, repo.items, 1)
+	require.Equal(t, "claude-haiku-4-5", gjson.GetBytes(repo.items[0].Params, "model").String())
+	require.Equal(t, int64(16), gjson.GetBytes(repo.items[0].Params, "max_tokens").Int())
+
+	denied := `{"model":"smart","max_tokens":16,"messages":[{"role":"user","content":"hi"}]}`
+	_, err = svc.Create(context.Background(), CreateMessageBatchInput{APIKey: key, Requests: []MessageBatchRequest{batchRequest("b", denied)}})
+	require.ErrorIs(t, err, ErrMessageBatchInvalid)
+	require.Len(t, repo.items, 1)
+}
+
+func TestMessageBatchCreate_Disabled(t *testing.T) {
+	cfg := newMessageBatchTestConfig()
+	cfg.MessageBatch.Enabled = false
+	svc := NewMessageBatchService(newMessageBatchRepoStub(), nil, nil, nil, nil, nil, nil, nil, nil, nil, cfg)
+	_, err := svc.Create(context.Background(), CreateMessageBatchInput{APIKey: newMessageBatchTestAPIKey()})
+	require.ErrorIs(t, err, ErrMessageBatchDisabled)
+}
+
+func TestMessageBatchGet_ScopedToOwner(t *testing.T) {
+	repo := newMessageBatchRepoStub()
+	repo.batches["msgbatch_abc"] = &MessageBatch{ID: "msgbatch_abc", UserID: 42}
+	svc := NewMessageBatchService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, newMessageBatchTestConfig())
+
+	batch, err := svc.Get(context.Background(), 42, "msgbatch_abc")
+	require.NoError(t, err)
+	require.Equal(t, "msgbatch_abc", batch.ID)
+
+	_, err = svc.Get(context.Background(), 43, "msgbatch_abc")
+	require.ErrorIs(t, err, ErrMessageBatchNotFound)
+
+	_, err = svc.Get(context.Background(), 42, "other_abc")
+	require.ErrorIs(t, err, ErrMessageBatchNotFound)
+}
+
+func TestMessageBatchResults(t *testing.T) {
+	repo := newMessageBatchRepoStub()
+	repo.batches["msgbatch_run"] = &MessageBatch{ID: "msgbatch_run", UserID: 42, ProcessingStatus: MessageBatchStatusInProgress}
+	repo.batches["msgbatch_end"] = &MessageBatch{ID: "msgbatch_end", UserID: 42, ProcessingStatus: MessageBatchStatusEnded}
+	repo.items = []MessageBatchItem{
+		{ID: 1, BatchID: "msgbatch_end", CustomID: "ok", Status: MessageBatchItemSucceeded, Result: messageBatchSucceededResult([]byte(`{"id":"msg_1"}`))},
+		{ID: 2, BatchID: "msgbatch_end", CustomID: "bad", Status: MessageBatchItemErrored, Result: messageBatchErroredResult("invalid_request_error", "boom")},
+		{ID: 3, BatchID: "msgbatch_end", CustomID: "stop", Status: MessageBatchItemCanceled},
+		{ID: 4, BatchID: "msgbatch_end", CustomID: "late", Status: MessageBatchItemExpired},
+	}
+	svc := NewMessageBatchService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, newMessageBatchTestConfig())
+
+	emitted := 0
+	err := svc.Results(context.Background(), 42, "msgbatch_run", func(MessageBatchResult) error {
+		emitted++
+		return nil
+	})
+	require.ErrorIs(t, err, ErrMessageBatchNotEnded)
+	require.Zero(t, emitted)
+
+	var lines []string
+	err = svc.Results(context.Background(), 42, "msgbatch_end", func(r MessageBatchResult) error {
+		line, err := json.Marshal(r)
+		require.NoError(t, err)
+		lines = append(lines, string(line))
+		return nil
+	})
+	require.NoError(t, err)
+	require.Equal(t, []string{
+		`{"custom_id":"ok","result":{"type":"succeeded","message":{"id":"msg_1"}}}`,
+		`{"custom_id":"bad","result":{"error":{"error":{"message":"boom","type":"invalid_request_error"},"type":"error"},"type":"errored"}}`,
+		`{"custom_id":"stop","result":{"type":"canceled"}}`,
+		`{"custom_id":"late","result":{"type":"expired"}}`,
+	}, lines)
+}
+
+func TestMessageBatchErroredResultFromBody(t *testing.T) {
+	got := messageBatchErroredResultFromBody(400, []byte(`{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long"}}`), "fallback")
+	require.JSONEq(t, `{"type":"errored","error":{"type":"error","error":{"type":"invalid_request_error","message":"prompt is too long"}}}`, string(got))
+
+	got = messageBatchErroredResultFromBody(502, []byte(`<html>bad gateway</html>`), "")
+	require.JSONEq(t, `{"type":"errored","error":{"type":"error","error":{"type":"api_error","message":"Bad Gateway"}}}`, string(got))
+}
+
+func TestBatchHasSpareCapacity(t *testing.T) {
+	// 已计入本请求占用的槽位
+	require.True(t, batchHasSpareCapacity(&AccountLoadInfo{CurrentConcurrency: 1}, 1, 0))
+	require.True(t, batchHasSpareCapacity(&AccountLoadInfo{CurrentConcurrency: 3}, 4, 50))
+	require.False(t, batchHasSpareCapacity(&AccountLoadInfo{CurrentConcurrency: 4}, 4, 50))
+	require.False(t, batchHasSpareCapacity(&AccountLoadInfo{CurrentConcurrency: 1, WaitingCount: 1}, 4, 100))
+	require.True(t, batchHasSpareCapacity(nil, 4, 0))
+}
+
+func TestMessageBatchSettle(t *testing.T) {
+	repo := newMessageBatchRepoStub()
+	svc := NewMessageBatchService(repo, nil, nil, nil, nil, nil, nil, nil, nil, nil, newMessageBatchTestConfig())
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	// 账号繁忙：不计入尝试次数
+	svc.settle(&MessageBatchItem{ID: 1, Status: MessageBatchItemPending, Attempts: 1}, messageBatchOutcomeBusy)
+	// 上游失败：计入尝试次数
+	svc.settle(&MessageBatchItem{ID: 2, Status: MessageBatchItemPending, Attempts: 1}, messageBatchOutcomeRetry)
+
+	require.Len(t, repo.saved, 2)
+	require.Equal(t, 0, repo.saved[0].Attempts)
+	require.Equal(t, now.Add(30*time.Second), repo.saved[0].NextAttemptAt)
+	require.Equal(t, 1, repo.saved[1].Attempts)
+	require.Equal(t, now.Add(30*time.Second), repo.saved[1].NextAttemptAt)
+}
+
+func TestMessageBatchProcessDueItems_ErrorsWhenAPIKeyGone(t *testing.T) {
+	groupID := int64(7)
+	repo := newMessageBatchRepoStub()
+	repo.batches["msgbatch_gone"] = &MessageBatch{ID: "msgbatch_gone", UserID: 42, APIKeyID: 99, GroupID: &groupID, ProcessingStatus: MessageBatchStatusInProgress}
+	repo.batches["msgbatch_off"] = &MessageBatch{ID: "msgbatch_off", UserID: 42, APIKeyID: 11, GroupID: &groupID, ProcessingStatus: MessageBatchStatusInProgress}
+	repo.claimed = []MessageBatchItem{
+		{ID: 1, BatchID: "msgbatch_gone", CustomID: "a", Params: json.RawMessage(messageBatchTestParams), Status: MessageBatchItemPending, Attempts: 1},
+		{ID: 2, BatchID: "msgbatch_off", CustomID: "b", Params: json.RawMessage(messageBatchTestParams), Status: MessageBatchItemPending, Attempts: 1},
+	}
+	disabled := newMessageBatchTestAPIKey()
+	disabled.Status = StatusDisabled
+	apiKeyRepo := &messageBatchAPIKeyRepoStub{keys: map[int64]*APIKey{11: disabled}}
+	svc := NewMessageBatchService(repo, apiKeyRepo, nil, nil, nil, nil, nil, nil, nil, nil, newMessageBatchTestConfig())
+
+	svc.processDueItems()
+
+	require.Len(t, repo.saved, 2)
+	results := map[int64]MessageBatchItem{}
+	for _, item := range repo.saved {
+		results[item.ID] = item
+	}
+	require.Equal(t, MessageBatchItemErrored, results[1].Status)
+	require.Contains(t, string(results[1].Result), `"authentication_error"`)
+	require.Equal(t, MessageBatchItemErrored, results[2].Status)
+	require.Contains(t, string(results[2].Result), `"permission_error"`)
+}
+
+func TestMessageBatchErrorsCarryReason(t *testing.T) {
+	err := checkMessageBatchGroup(&Group{Platform: PlatformAnthropic, ClaudeCodeOnly: true})
+	require.Equal(t, ErrMessageBatchUnsupported.Reason, infraerrors.Reason(err))
+}
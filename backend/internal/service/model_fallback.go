@@ -0,0 +1,52 @@
+package service
+
+import "github.com/Wei-Shaw/sub2api/internal/domain"
+
+type ModelFallbackChain = domain.ModelFallbackChain
+
+const (
+	ModelFallbackConditionRateLimited   = domain.ModelFallbackConditionRateLimited
+	ModelFallbackConditionOverloaded    = domain.ModelFallbackConditionOverloaded
+	ModelFallbackConditionPromptTooLong = domain.ModelFallbackConditionPromptTooLong
+)
+
+// ModelFallbackPlan 单次请求在某条降级链上的进度
+type ModelFallbackPlan struct {
+	chain ModelFallbackChain
+	next  int
+}
+
+// NewModelFallbackPlan 返回请求模型命中的第一条降级链；未配置或已是链尾时返回 nil。
+func (g *Group) NewModelFallbackPlan(model string) *ModelFallbackPlan {
+	if g == nil || model == "" {
+		return nil
+	}
+	for _, chain := range g.ModelFallbackChains {
+		for i, pattern := range chain.Models {
+			if !matchModelPattern(pattern, model) {
+				continue
+			}
+			if i+1 < len(chain.Models) {
+				return &ModelFallbackPlan{chain: chain, next: i + 1}
+			}
+			break
+		}
+	}
+	return nil
+}
+
+// Next 在指定条件下推进到下一个降级模型。
+// allowed 用于跳过 API Key 模型策略不允许的模型（nil 表示不过滤）。
+func (p *ModelFallbackPlan) Next(condition string, allowed func(model string) bool) (string, bool) {
+	if p == nil || !p.chain.AllowsCondition(condition) {
+		return "", false
+	}
+	for p.next < len(p.chain.Models) {
+		model := p.chain.Models[p.next]
+		p.next++
+		if allowed == nil || allowed(model) {
+			return model, true
+		}
+	}
+	return "", false
+}
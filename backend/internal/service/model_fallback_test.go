@@ -0,0 +1,67 @@
+//go:build unit
+
+package service
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestGroupNewModelFallbackPlan(t *testing.T) {
+	group := &Group{ModelFallbackChains: []ModelFallbackChain{
+		{Models: []string{"claude-opus-*", "claude-sonnet-4-5", "claude-haiku-4-5"}},
+		{Models: []string{"gpt-5", "gpt-5-mini"}, Conditions: []string{ModelFallbackConditionOverloaded}},
+	}}
+
+	require.Nil(t, group.NewModelFallbackPlan("claude-3-5-haiku"))
+	require.Nil(t, group.NewModelFallbackPlan("claude-haiku-4-5"), "链尾模型无可降级目标")
+	require.Nil(t, (*Group)(nil).NewModelFallbackPlan("claude-opus-4-1"))
+
+	plan := group.NewModelFallbackPlan("claude-opus-4-1")
+	next, ok := plan.Next(ModelFallbackConditionRateLimited, nil)
+	require.True(t, ok)
+	require.Equal(t, "claude-sonnet-4-5", next)
+	next, ok = plan.Next(ModelFallbackConditionOverloaded, nil)
+	require.True(t, ok)
+	require.Equal(t, "claude-haiku-4-5", next)
+	_, ok = plan.Next(ModelFallbackConditionOverloaded, nil)
+	require.False(t, ok)
+
+	// 从链中间的模型开始
+	plan = group.NewModelFallbackPlan("claude-sonnet-4-5")
+	next, ok = plan.Next(ModelFallbackConditionPromptTooLong, nil)
+	require.True(t, ok)
+	require.Equal(t, "claude-haiku-4-5", next)
+}
+
+func TestModelFallbackPlanNext_ConditionsAndAllowed(t *testing.T) {
+	group := &Group{ModelFallbackChains: []ModelFallbackChain{
+		{Models: []string{"gpt-5", "gpt-5-mini", "gpt-5-nano"}, Conditions: []string{ModelFallbackConditionOverloaded}},
+	}}
+
+	plan := group.NewModelFallbackPlan("gpt-5")
+	_, ok := plan.Next(ModelFallbackConditionRateLimited, nil)
+	require.False(t, ok, "条件不匹配时不降级")
+
+	next, ok := plan.Next(ModelFallbackConditionOverloaded, func(model string) bool { return model != "gpt-5-mini" })
+	require.True(t, ok)
+	require.Equal(t, "gpt-5-nano", next, "跳过 API Key 不允许的模型")
+
+	var nilPlan *ModelFallbackPlan
+	_, ok = nilPlan.Next(ModelFallbackConditionOverloaded, nil)
+	require.False(t, ok)
+}
+
+func TestParsedRequestReplaceModel(t *testing.T) {
+	parsed, err := ParseGatewayRequest([]byte(`{"model":"claude-opus-4-1","max_tokens":10,"messages":[]}`), "anthropic")
+	require.NoError(t, err)
+
+	require.NoError(t, parsed.ReplaceModel("claude-sonnet-4-5"))
+	require.Equal(t, "claude-sonnet-4-5", parsed.Model)
+
+	reparsed, err := ParseGatewayRequest(parsed.Body, "anthropic")
+	require.NoError(t, err)
+	require.Equal(t, "claude-sonnet-4-5", reparsed.Model)
+	require.Equal(t, 10, reparsed.MaxTokens)
+}
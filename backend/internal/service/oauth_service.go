@@ -286,7 +286,10 @@ func (s *OAuthService) RefreshToken(ctx context.Context, refreshToken string, pr
 }
 
 // RefreshAccountToken refreshes token for an account
-func (s *OAuthService) RefreshAccountToken(ctx context.Context, account *Account) (*TokenInfo, error) {
+func (s *OAuthService) RefreshAccountToken(ctx context.Context, account *Account) (_ *TokenInfo, err error) {
+	ctx, span := startTokenRefreshSpan(ctx, account)
+	defer func() { endTokenRefreshSpan(span, err) }()
+
 	refreshToken := account.GetCredential("refresh_token")
 	if refreshToken == "" {
 		return nil, fmt.Errorf("no refresh token available")
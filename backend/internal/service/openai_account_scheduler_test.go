@@ -421,6 +421,51 @@ func TestOpenAIGatewayService_SelectAccountWithScheduler_LoadBalanceTopKFallback
 	}
 }
 
+func TestOpenAIGatewayService_SelectAccountWithScheduler_HealthScorerPenalizesOverload(t *testing.T) {
+	ctx := context.Background()
+	groupID := int64(12)
+	accounts := []Account{
+		{ID: 4001, Platform: PlatformOpenAI, Type: AccountTypeAPIKey, Status: StatusActive, Schedulable: true, Concurrency: 1},
+		{ID: 4002, Platform: PlatformOpenAI, Type: AccountTypeAPIKey, Status: StatusActive, Schedulable: true, Concurrency: 1},
+	}
+
+	selectWith := func(enabled bool) int64 {
+		cfg := &config.Config{}
+		cfg.Gateway.OpenAIWS.LBTopK = 1
+		cfg.Gateway.Scheduling.HealthScore = config.GatewaySchedulingHealthScoreConfig{
+			Enabled:              enabled,
+			DecayHalfLifeSeconds: 300,
+			Weights:              config.GatewaySchedulingHealthScoreWeights{Overload: 1.0, TokenRefresh: 0.6},
+		}
+		scorer := NewAccountHealthScorer(cfg)
+		scorer.ReportOverload(4001, 429)
+		scorer.ReportOverload(4001, 529)
+
+		svc := &OpenAIGatewayService{
+			accountRepo: stubOpenAIAccountRepo{accounts: accounts},
+			cache:       &stubGatewayCache{},
+			cfg:         cfg,
+			concurrencyService: NewConcurrencyService(stubConcurrencyCache{
+				acquireResults: map[int64]bool{4001: true, 4002: true},
+			}),
+			openaiAccountStats: scorer.runtimeStats(),
+			healthScorer:       scorer,
+		}
+		selection, decision, err := svc.SelectAccountWithScheduler(ctx, &groupID, "", "", "gpt-5.1", nil, OpenAIUpstreamTransportAny)
+		require.NoError(t, err)
+		require.NotNil(t, selection)
+		require.Equal(t, openAIAccountScheduleLayerLoadBalance, decision.Layer)
+		if selection.ReleaseFunc != nil {
+			selection.ReleaseFunc()
+		}
+		return selection.Account.ID
+	}
+
+	// 评分关闭时保持原有排序（同分按 ID），开启后 429/529 密度高的账号被降权
+	require.Equal(t, int64(4001), selectWith(false))
+	require.Equal(t, int64(4002), selectWith(true))
+}
+
 func TestOpenAIGatewayService_OpenAIAccountSchedulerMetrics(t *testing.T) {
 	ctx := context.Background()
 	groupID := int64(12)
@@ -464,7 +509,7 @@ func intPtrForTest(v int) *int {
 }
 
 func TestOpenAIAccountRuntimeStats_ReportAndSnapshot(t *testing.T) {
-	stats := newOpenAIAccountRuntimeStats()
+	stats := newAccountRuntimeStats()
 	stats.report(1001, true, nil)
 	firstTTFT := 100
 	stats.report(1001, false, &firstTTFT)
@@ -479,7 +524,7 @@ func TestOpenAIAccountRuntimeStats_ReportAndSnapshot(t *testing.T) {
 }
 
 func TestOpenAIAccountRuntimeStats_ReportConcurrent(t *testing.T) {
-	stats := newOpenAIAccountRuntimeStats()
+	stats := newAccountRuntimeStats()
 
 	const (
 		accountCount = 4
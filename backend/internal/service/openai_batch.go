@@ -0,0 +1,269 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// 文件用途（与 OpenAI Files API 一致）；仅支持批处理相关用途
+const (
+	OpenAIFilePurposeBatch       = "batch"
+	OpenAIFilePurposeBatchOutput = "batch_output"
+)
+
+// 批次状态（与 OpenAI Batch API 一致）
+const (
+	OpenAIBatchStatusValidating = "validating"
+	OpenAIBatchStatusFailed     = "failed"
+	OpenAIBatchStatusInProgress = "in_progress"
+	OpenAIBatchStatusFinalizing = "finalizing"
+	OpenAIBatchStatusCompleted  = "completed"
+	OpenAIBatchStatusExpired    = "expired"
+	OpenAIBatchStatusCancelling = "cancelling"
+	OpenAIBatchStatusCancelled  = "cancelled"
+)
+
+// 批内请求状态；pending 包含执行中（租约期内 next_attempt_at 被后延）。
+// completed 写入结果文件，其余终态写入错误文件。
+const (
+	OpenAIBatchItemPending   = "pending"
+	OpenAIBatchItemCompleted = "completed"
+	OpenAIBatchItemFailed    = "failed"
+	OpenAIBatchItemCancelled = "cancelled"
+	OpenAIBatchItemExpired   = "expired"
+)
+
+// 批处理支持的端点
+const (
+	OpenAIBatchEndpointResponses       = "/v1/responses"
+	OpenAIBatchEndpointChatCompletions = "/v1/chat/completions"
+)
+
+const (
+	OpenAIBatchCompletionWindow = "24h"
+	OpenAIBatchListMaxLimit     = 100
+	OpenAIFileListMaxLimit      = 10000
+
+	openAIFileIDPrefix         = "file-"
+	openAIBatchIDPrefix        = "batch_"
+	openAIBatchRequestIDPrefix = "batch_req_"
+	openAIBatchMaxCustomID     = 512
+	openAIBatchMaxMetadataKeys = 16
+	openAIBatchMaxLineErrors   = 100
+)
+
+var (
+	ErrOpenAIFileNotFound    = infraerrors.NotFound("OPENAI_FILE_NOT_FOUND", "file not found")
+	ErrOpenAIFileInvalid     = infraerrors.BadRequest("OPENAI_FILE_INVALID", "invalid file")
+	ErrOpenAIFileTooLarge    = infraerrors.New(http.StatusRequestEntityTooLarge, "OPENAI_FILE_TOO_LARGE", "file is too large")
+	ErrOpenAIFileStorageDown = infraerrors.New(http.StatusServiceUnavailable, "OPENAI_FILE_STORAGE_UNAVAILABLE", "file storage is not available")
+	ErrOpenAIBatchNotFound   = infraerrors.NotFound("OPENAI_BATCH_NOT_FOUND", "batch not found")
+	ErrOpenAIBatchDisabled   = infraerrors.New(http.StatusServiceUnavailable, "OPENAI_BATCH_DISABLED", "files and batches are disabled")
+	ErrOpenAIBatchInvalid    = infraerrors.BadRequest("OPENAI_BATCH_INVALID", "invalid batch")
+)
+
+// OpenAIFile OpenAI Files 兼容文件，归属于上传它的用户
+type OpenAIFile struct {
+	ID        string
+	UserID    int64
+	Purpose   string
+	Filename  string
+	Bytes     int64
+	Storage   string // local / s3
+	ObjectKey string
+	ExpiresAt time.Time
+	CreatedAt time.Time
+}
+
+// OpenAIBatch OpenAI Batch 兼容批次。
+// 批内请求以创建批次的 API Key 身份调度与计费。
+type OpenAIBatch struct {
+	ID               string
+	UserID           int64
+	APIKeyID         int64
+	GroupID          *int64
+	Endpoint         string
+	InputFileID      string
+	CompletionWindow string
+	Status           string
+	OutputFileID     *string
+	ErrorFileID      *string
+	Errors           []OpenAIBatchError
+	Metadata         map[string]string
+	RequestCounts    OpenAIBatchRequestCounts
+	ExpiresAt        time.Time
+	InProgressAt     *time.Time
+	FinalizingAt     *time.Time
+	CompletedAt      *time.Time
+	FailedAt         *time.Time
+	ExpiredAt        *time.Time
+	CancellingAt     *time.Time
+	CancelledAt      *time.Time
+	CreatedAt        time.Time
+}
+
+// OpenAIBatchRequestCounts 批内请求数；由仓储按 items 实时统计，
+// failed 包含写入错误文件的全部请求（失败、取消与过期）
+type OpenAIBatchRequestCounts struct {
+	Total     int `json:"total"`
+	Completed int `json:"completed"`
+	Failed    int `json:"failed"`
+}
+
+// OpenAIBatchError 输入文件校验错误
+type OpenAIBatchError struct {
+	Code    string  `json:"code"`
+	Message string  `json:"message"`
+	Param   *string `json:"param"`
+	Line    *int    `json:"line"`
+}
+
+// OpenAIBatchItem 批内单个请求
+type OpenAIBatchItem struct {
+	ID            int64
+	BatchID       string
+	LineNo        int
+	CustomID      string
+	Body          json.RawMessage
+	Status        string
+	Attempts      int
+	NextAttemptAt time.Time
+	Output        json.RawMessage // 终态为 completed/failed 时结果文件中的一行
+}
+
+// OpenAIBatchListParams 批次列表游标分页参数（按创建时间倒序）
+type OpenAIBatchListParams struct {
+	Limit int
+	After string
+}
+
+// OpenAIFileListParams 文件列表游标分页参数
+type OpenAIFileListParams struct {
+	Purpose string
+	Limit   int
+	After   string
+	Order   string // asc / desc（默认）
+}
+
+// OpenAIFileRepository 文件元数据仓储
+type OpenAIFileRepository interface {
+	Create(ctx context.Context, file *OpenAIFile) error
+	// GetByID 查询文件；不存在返回 ErrOpenAIFileNotFound
+	GetByID(ctx context.Context, id string) (*OpenAIFile, error)
+	// List 列出用户的文件，返回是否还有更多
+	List(ctx context.Context, userID int64, params OpenAIFileListParams) ([]OpenAIFile, bool, error)
+	Delete(ctx context.Context, id string) error
+	// ListExpired 列出已过保留期的文件
+	ListExpired(ctx context.Context, now time.Time, limit int) ([]OpenAIFile, error)
+}
+
+// OpenAIBatchRepository 批次与批内请求仓储
+type OpenAIBatchRepository interface {
+	Create(ctx context.Context, batch *OpenAIBatch) error
+	// GetByID 获取批次（含实时统计的 RequestCounts）；不存在返回 ErrOpenAIBatchNotFound
+	GetByID(ctx context.Context, id string) (*OpenAIBatch, error)
+	// List 按创建时间倒序列出用户的批次，返回是否还有更多
+	List(ctx context.Context, userID int64, params OpenAIBatchListParams) ([]OpenAIBatch, bool, error)
+	// RequestCancel 将 validating/in_progress 批次置为 cancelling，并取消尚未开始执行的请求
+	RequestCancel(ctx context.Context, id string) error
+	// ListItems 按 ID 升序分页读取批内请求（afterID 为游标）
+	ListItems(ctx context.Context, batchID string, afterID int64, limit int) ([]OpenAIBatchItem, error)
+
+	// ClaimValidating 领取待校验输入文件的批次，并将 locked_until 后延 lease
+	ClaimValidating(ctx context.Context, limit int, lease time.Duration) ([]OpenAIBatch, error)
+	// StartBatch 在同一事务中写入批内请求并将批次从 validating 置为 in_progress；
+	// 批次已不处于 validating（如已被取消）时不写入并返回 false
+	StartBatch(ctx context.Context, id string, items []OpenAIBatchItem) (bool, error)
+	// FailBatch 将 validating 批次置为 failed 并记录校验错误
+	FailBatch(ctx context.Context, id string, errs []OpenAIBatchError) error
+
+	// ClaimDueItems 领取到期待执行的请求（仅限 in_progress 且未过期的批次），
+	// 并将 next_attempt_at 后延 lease，防止多实例重复执行
+	ClaimDueItems(ctx context.Context, limit int, lease time.Duration) ([]OpenAIBatchItem, error)
+	// SaveItemResult 保存执行结果（状态、尝试次数、下次执行时间与结果行）
+	SaveItemResult(ctx context.Context, item *OpenAIBatchItem) error
+	// AdvanceBatches 取消 cancelling 批次中未执行的请求、将过期批次中未执行的请求记为 expired，
+	// 并将已无待执行请求的 in_progress 批次置为 finalizing
+	AdvanceBatches(ctx context.Context) error
+	// ClaimClosable 领取待生成结果文件的批次（finalizing，或已无待执行请求的 cancelling），
+	// 并将 locked_until 后延 lease
+	ClaimClosable(ctx context.Context, limit int, lease time.Duration) ([]OpenAIBatch, error)
+	// CloseBatch 写入结果/错误文件 ID 并将批次置为 batch.Status 指定的终态（completed/expired/cancelled）；
+	// fromStatus 为领取时的状态，状态已变化时不更新
+	CloseBatch(ctx context.Context, batch *OpenAIBatch, fromStatus string) error
+	// DeleteFinishedBefore 删除在 cutoff 之前结束的批次及其请求
+	DeleteFinishedBefore(ctx context.Context, cutoff time.Time) (int64, error)
+}
+
+// OpenAIBatchRequestLine 输入文件中的一行
+type OpenAIBatchRequestLine struct {
+	CustomID string          `json:"custom_id"`
+	Method   string          `json:"method"`
+	URL      string          `json:"url"`
+	Body     json.RawMessage `json:"body"`
+}
+
+// openAIBatchOutputLine 结果/错误文件中的一行
+type openAIBatchOutputLine struct {
+	ID       string                   `json:"id"`
+	CustomID string                   `json:"custom_id"`
+	Response *openAIBatchLineResponse `json:"response"`
+	Error    *openAIBatchLineError    `json:"error"`
+}
+
+type openAIBatchLineResponse struct {
+	StatusCode int             `json:"status_code"`
+	RequestID  string          `json:"request_id"`
+	Body       json.RawMessage `json:"body"`
+}
+
+type openAIBatchLineError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// openAIBatchResponseLine 构造带上游响应的结果行（成功或上游返回错误状态码）
+func openAIBatchResponseLine(lineID, customID string, status int, requestID string, body []byte) json.RawMessage {
+	if !json.Valid(body) {
+		body, _ = json.Marshal(map[string]any{
+			"error": map[string]string{"type": "api_error", "message": http.StatusText(status)},
+		})
+	}
+	out, _ := json.Marshal(openAIBatchOutputLine{
+		ID:       lineID,
+		CustomID: customID,
+		Response: &openAIBatchLineResponse{StatusCode: status, RequestID: requestID, Body: body},
+	})
+	return out
+}
+
+// openAIBatchErrorLine 构造未得到上游响应的错误行（Key 失效、取消、过期等）
+func openAIBatchErrorLine(lineID, customID, code, message string) json.RawMessage {
+	out, _ := json.Marshal(openAIBatchOutputLine{
+		ID:       lineID,
+		CustomID: customID,
+		Error:    &openAIBatchLineError{Code: code, Message: message},
+	})
+	return out
+}
+
+// OutputLine 返回该请求在结果/错误文件中的一行；pending 返回 nil
+func (item *OpenAIBatchItem) OutputLine(lineID string) json.RawMessage {
+	switch item.Status {
+	case OpenAIBatchItemCompleted, OpenAIBatchItemFailed:
+		if len(item.Output) > 0 {
+			return item.Output
+		}
+		return openAIBatchErrorLine(lineID, item.CustomID, "server_error", "result unavailable")
+	case OpenAIBatchItemCancelled:
+		return openAIBatchErrorLine(lineID, item.CustomID, "batch_cancelled", "This request was cancelled because the batch was cancelled.")
+	case OpenAIBatchItemExpired:
+		return openAIBatchErrorLine(lineID, item.CustomID, "batch_expired", "This request could not be executed before the completion window expired.")
+	default:
+		return nil
+	}
+}
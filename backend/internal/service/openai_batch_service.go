@@ -0,0 +1,955 @@
+package service
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/gin-gonic/gin"
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+const (
+	openAIBatchWorkerName = "openai_batch_worker"
+
+	openAIBatchCompletionWindow = 24 * time.Hour
+	openAIBatchLeaseMargin      = time.Minute
+	openAIBatchStageLease       = 15 * time.Minute
+	openAIBatchStageClaimSize   = 2
+	openAIBatchSaveTimeout      = 30 * time.Second
+	openAIBatchCleanupInterval  = time.Hour
+	openAIBatchItemsPageSize    = 500
+	openAIBatchDefaultListLimit = 20
+	openAIBatchUserAgent        = "sub2api-openai-batch"
+)
+
+// OpenAIBatchService 实现 OpenAI Batch API：
+// 校验上传的 JSONL 输入文件并持久化批内请求，由后台 worker 通过 OpenAI 账号调度异步执行，
+// 完成后生成结果文件与错误文件。
+//
+// 批内请求是低优先级流量：只在账号其余请求的负载不超过 max_account_load_percent
+// 且没有排队中的交互请求时才执行，否则延后重试，直到批次过期。
+// 执行成功的请求按创建批次的 API Key 逐行写入使用记录并计费，叠加 price_multiplier 折扣。
+type OpenAIBatchService struct {
+	repo               OpenAIBatchRepository
+	files              *OpenAIFileService
+	owners             batchOwnerLoader
+	gatewayService     *OpenAIGatewayService
+	concurrencyService *ConcurrencyService
+	apiKeyService      *APIKeyService
+	timingWheel        *TimingWheelService
+	cfg                *config.Config
+	now                func() time.Time
+
+	running     int32
+	started     atomic.Bool
+	startOnce   sync.Once
+	stopOnce    sync.Once
+	lastCleanup atomic.Int64
+
+	workerCtx    context.Context
+	workerCancel context.CancelFunc
+}
+
+// NewOpenAIBatchService 创建 OpenAI Batch 服务
+func NewOpenAIBatchService(
+	repo OpenAIBatchRepository,
+	files *OpenAIFileService,
+	apiKeyRepo APIKeyRepository,
+	orgRepo OrganizationRepository,
+	gatewayService *OpenAIGatewayService,
+	concurrencyService *ConcurrencyService,
+	subscriptionService *SubscriptionService,
+	billingCacheService *BillingCacheService,
+	apiKeyService *APIKeyService,
+	timingWheel *TimingWheelService,
+	cfg *config.Config,
+) *OpenAIBatchService {
+	workerCtx, workerCancel := context.WithCancel(context.Background())
+	return &OpenAIBatchService{
+		repo:  repo,
+		files: files,
+		owners: batchOwnerLoader{
+			apiKeyRepo:          apiKeyRepo,
+			orgRepo:             orgRepo,
+			subscriptionService: subscriptionService,
+			billingCacheService: billingCacheService,
+		},
+		gatewayService:     gatewayService,
+		concurrencyService: concurrencyService,
+		apiKeyService:      apiKeyService,
+		timingWheel:        timingWheel,
+		cfg:                cfg,
+		now:                time.Now,
+		workerCtx:          workerCtx,
+		workerCancel:       workerCancel,
+	}
+}
+
+// Enabled 是否开放 Batch API
+func (s *OpenAIBatchService) Enabled() bool {
+	return s != nil && s.repo != nil && s.files.Enabled()
+}
+
+func (s *OpenAIBatchService) Start() {
+	if !s.Enabled() {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] not started (disabled)")
+		return
+	}
+	if s.timingWheel == nil || s.gatewayService == nil {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] not started (missing deps)")
+		return
+	}
+	s.startOnce.Do(func() {
+		interval := time.Duration(s.cfg.OpenAIBatch.WorkerIntervalSeconds) * time.Second
+		s.timingWheel.ScheduleRecurring(openAIBatchWorkerName, interval, s.runOnce)
+		s.started.Store(true)
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] started (interval=%s storage=%s concurrency=%d max_account_load=%d%% price_multiplier=%.2f)",
+			interval, s.cfg.OpenAIBatch.Storage, s.cfg.OpenAIBatch.Concurrency, s.cfg.OpenAIBatch.MaxAccountLoadPercent, s.cfg.OpenAIBatch.PriceMultiplier)
+	})
+}
+
+func (s *OpenAIBatchService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		s.started.Store(false)
+		if s.workerCancel != nil {
+			s.workerCancel()
+		}
+		if s.timingWheel != nil {
+			s.timingWheel.Cancel(openAIBatchWorkerName)
+		}
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] stopped")
+	})
+}
+
+// =========================
+// 批次 API
+// =========================
+
+// CreateOpenAIBatchInput 创建批次参数
+type CreateOpenAIBatchInput struct {
+	APIKey           *APIKey
+	InputFileID      string
+	Endpoint         string
+	CompletionWindow string
+	Metadata         map[string]string
+}
+
+// Create 创建批次；输入文件由 worker 异步校验（计费资格由调用方预先校验）
+func (s *OpenAIBatchService) Create(ctx context.Context, in CreateOpenAIBatchInput) (*OpenAIBatch, error) {
+	if !s.Enabled() {
+		return nil, ErrOpenAIBatchDisabled
+	}
+	apiKey := in.APIKey
+	if apiKey == nil || apiKey.Group == nil || apiKey.GroupID == nil || apiKey.Group.Platform != PlatformOpenAI {
+		return nil, infraerrors.Newf(http.StatusBadRequest, ErrOpenAIBatchInvalid.Reason, "batches are only supported for OpenAI groups")
+	}
+	switch in.Endpoint {
+	case OpenAIBatchEndpointResponses, OpenAIBatchEndpointChatCompletions:
+	default:
+		return nil, infraerrors.Newf(http.StatusBadRequest, ErrOpenAIBatchInvalid.Reason,
+			"endpoint must be one of %s, %s", OpenAIBatchEndpointResponses, OpenAIBatchEndpointChatCompletions)
+	}
+	if in.CompletionWindow != OpenAIBatchCompletionWindow {
+		return nil, infraerrors.Newf(http.StatusBadRequest, ErrOpenAIBatchInvalid.Reason, "completion_window must be %q", OpenAIBatchCompletionWindow)
+	}
+	if err := validateOpenAIBatchMetadata(in.Metadata); err != nil {
+		return nil, err
+	}
+	file, err := s.files.Get(ctx, apiKey.UserID, in.InputFileID)
+	if err != nil {
+		return nil, err
+	}
+	if file.Purpose != OpenAIFilePurposeBatch {
+		return nil, infraerrors.Newf(http.StatusBadRequest, ErrOpenAIBatchInvalid.Reason, "input_file_id must reference a file with purpose %q", OpenAIFilePurposeBatch)
+	}
+
+	id, err := generateOpenAIBatchID(openAIBatchIDPrefix)
+	if err != nil {
+		return nil, err
+	}
+	now := s.now()
+	groupID := *apiKey.GroupID
+	batch := &OpenAIBatch{
+		ID:               id,
+		UserID:           apiKey.UserID,
+		APIKeyID:         apiKey.ID,
+		GroupID:          &groupID,
+		Endpoint:         in.Endpoint,
+		InputFileID:      file.ID,
+		CompletionWindow: in.CompletionWindow,
+		Status:           OpenAIBatchStatusValidating,
+		Metadata:         in.Metadata,
+		ExpiresAt:        now.Add(openAIBatchCompletionWindow),
+		CreatedAt:        now,
+	}
+	if err := s.repo.Create(ctx, batch); err != nil {
+		return nil, fmt.Errorf("create openai batch: %w", err)
+	}
+	s.kick()
+	return batch, nil
+}
+
+func validateOpenAIBatchMetadata(metadata map[string]string) error {
+	if len(metadata) > openAIBatchMaxMetadataKeys {
+		return infraerrors.Newf(http.StatusBadRequest, ErrOpenAIBatchInvalid.Reason, "metadata can have at most %d keys", openAIBatchMaxMetadataKeys)
+	}
+	for k, v := range metadata {
+		if len(k) > 64 || len(v) > 512 {
+			return infraerrors.Newf(http.StatusBadRequest, ErrOpenAIBatchInvalid.Reason, "metadata keys must be at most 64 characters and values at most 512 characters")
+		}
+	}
+	return nil
+}
+
+func generateOpenAIBatchID(prefix string) (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate batch id: %w", err)
+	}
+	return prefix + hex.EncodeToString(buf), nil
+}
+
+// Get 获取用户的批次
+func (s *OpenAIBatchService) Get(ctx context.Context, userID int64, id string) (*OpenAIBatch, error) {
+	if !s.Enabled() {
+		return nil, ErrOpenAIBatchDisabled
+	}
+	if !strings.HasPrefix(id, openAIBatchIDPrefix) {
+		return nil, ErrOpenAIBatchNotFound
+	}
+	batch, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.UserID != userID {
+		return nil, ErrOpenAIBatchNotFound
+	}
+	return batch, nil
+}
+
+// List 按创建时间倒序列出用户的批次
+func (s *OpenAIBatchService) List(ctx context.Context, userID int64, params OpenAIBatchListParams) ([]OpenAIBatch, bool, error) {
+	if !s.Enabled() {
+		return nil, false, ErrOpenAIBatchDisabled
+	}
+	if params.Limit <= 0 {
+		params.Limit = openAIBatchDefaultListLimit
+	}
+	if params.Limit > OpenAIBatchListMaxLimit {
+		params.Limit = OpenAIBatchListMaxLimit
+	}
+	return s.repo.List(ctx, userID, params)
+}
+
+// Cancel 取消批次：尚未执行的请求记为 cancelled，执行中的请求完成后生成结果文件并置为 cancelled
+func (s *OpenAIBatchService) Cancel(ctx context.Context, userID int64, id string) (*OpenAIBatch, error) {
+	batch, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, err
+	}
+	if batch.Status != OpenAIBatchStatusValidating && batch.Status != OpenAIBatchStatusInProgress {
+		return batch, nil
+	}
+	if err := s.repo.RequestCancel(ctx, id); err != nil {
+		return nil, fmt.Errorf("cancel openai batch: %w", err)
+	}
+	s.kick()
+	return s.repo.GetByID(ctx, id)
+}
+
+// kick 新批次创建或取消后立即触发一轮处理，而不必等待下一个轮询周期
+func (s *OpenAIBatchService) kick() {
+	if s.started.Load() {
+		go s.runOnce()
+	}
+}
+
+func (s *OpenAIBatchService) runOnce() {
+	if !s.Enabled() {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&s.running, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&s.running, 0)
+
+	s.validateBatches()
+	s.processDueItems()
+	s.advance()
+	s.closeBatches()
+	s.cleanup()
+}
+
+// =========================
+// 校验输入文件
+// =========================
+
+func (s *OpenAIBatchService) validateBatches() {
+	ctx := s.workerCtx
+	batches, err := s.repo.ClaimValidating(ctx, openAIBatchStageClaimSize, openAIBatchStageLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] claim validating batches failed: %v", err)
+		}
+		return
+	}
+	for i := range batches {
+		s.validateBatch(ctx, &batches[i])
+	}
+}
+
+// validateBatch 解析输入文件：校验通过则写入批内请求并开始执行，否则置为 failed 并记录逐行错误
+func (s *OpenAIBatchService) validateBatch(ctx context.Context, batch *OpenAIBatch) {
+	items, errs, err := s.parseInputFile(ctx, batch)
+	if err != nil {
+		if ctx.Err() != nil {
+			return
+		}
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] read input file failed: batch=%s file=%s err=%v", batch.ID, batch.InputFileID, err)
+		return
+	}
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), openAIBatchSaveTimeout)
+	defer cancel()
+	if len(errs) > 0 {
+		if err := s.repo.FailBatch(saveCtx, batch.ID, errs); err != nil {
+			logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] mark batch failed failed: batch=%s err=%v", batch.ID, err)
+		}
+		return
+	}
+	started, err := s.repo.StartBatch(saveCtx, batch.ID, items)
+	if err != nil {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] start batch failed: batch=%s err=%v", batch.ID, err)
+		return
+	}
+	if started {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] batch validated: batch=%s requests=%d", batch.ID, len(items))
+	}
+}
+
+// parseInputFile 逐行解析输入文件；返回的 error 仅表示读取失败（稍后重试），格式错误以 OpenAIBatchError 返回
+func (s *OpenAIBatchService) parseInputFile(ctx context.Context, batch *OpenAIBatch) ([]OpenAIBatchItem, []OpenAIBatchError, error) {
+	file, err := s.files.repo.GetByID(ctx, batch.InputFileID)
+	if err != nil {
+		if errors.Is(err, ErrOpenAIFileNotFound) {
+			return nil, []OpenAIBatchError{openAIBatchValidationError("invalid_file", "The input file no longer exists.", nil, 0)}, nil
+		}
+		return nil, nil, err
+	}
+	if file.UserID != batch.UserID {
+		return nil, []OpenAIBatchError{openAIBatchValidationError("invalid_file", "The input file no longer exists.", nil, 0)}, nil
+	}
+	rc, err := s.files.openContent(ctx, file)
+	if err != nil {
+		if errors.Is(err, ErrOpenAIFileNotFound) {
+			return nil, []OpenAIBatchError{openAIBatchValidationError("invalid_file", "The input file no longer exists.", nil, 0)}, nil
+		}
+		return nil, nil, err
+	}
+	defer func() { _ = rc.Close() }()
+
+	// Key 失效时仍按格式校验，批内请求会在执行时统一记为失败
+	owner, _ := s.owners.load(ctx, batch.APIKeyID, batch.GroupID)
+	items, errs, err := parseOpenAIBatchInput(rc, batch.Endpoint, owner.apiKey, s.cfg.OpenAIBatch.MaxRequestsPerBatch)
+	if err != nil {
+		return nil, nil, err
+	}
+	for i := range items {
+		items[i].BatchID = batch.ID
+	}
+	return items, errs, nil
+}
+
+// parseOpenAIBatchInput 解析 JSONL 输入：每行须为 POST 到批次 endpoint 的请求，custom_id 唯一、body 含 model 且非流式。
+// apiKey 非 nil 时按 Key 的模型别名改写 model 并校验模型权限。
+func parseOpenAIBatchInput(r io.Reader, endpoint string, apiKey *APIKey, maxRequests int) ([]OpenAIBatchItem, []OpenAIBatchError, error) {
+	var (
+		items []OpenAIBatchItem
+		errs  []OpenAIBatchError
+	)
+	addErr := func(e OpenAIBatchError) {
+		if len(errs) < openAIBatchMaxLineErrors {
+			errs = append(errs, e)
+		}
+	}
+
+	seen := make(map[string]struct{})
+	reader := bufio.NewReader(r)
+	lineNo := 0
+	for {
+		raw, readErr := reader.ReadBytes('\n')
+		if readErr != nil && readErr != io.EOF {
+			return nil, nil, readErr
+		}
+		if len(raw) > 0 {
+			lineNo++
+		}
+		if line := bytes.TrimSpace(raw); len(line) > 0 {
+			item, lineErr := parseOpenAIBatchLine(line, lineNo, endpoint, apiKey, seen)
+			if lineErr != nil {
+				addErr(*lineErr)
+			} else {
+				items = append(items, *item)
+			}
+		}
+		if readErr == io.EOF {
+			break
+		}
+	}
+
+	if len(items) == 0 && len(errs) == 0 {
+		addErr(openAIBatchValidationError("empty_file", "The input file does not contain any requests.", nil, 0))
+	}
+	if len(items) > maxRequests {
+		addErr(openAIBatchValidationError("too_many_requests",
+			fmt.Sprintf("The input file contains %d requests; at most %d are allowed per batch.", len(items), maxRequests), nil, 0))
+	}
+	if len(errs) > 0 {
+		return nil, errs, nil
+	}
+	return items, nil, nil
+}
+
+// parseOpenAIBatchLine 校验单行请求，返回批内请求或该行的校验错误
+func parseOpenAIBatchLine(line []byte, lineNo int, endpoint string, apiKey *APIKey, seen map[string]struct{}) (*OpenAIBatchItem, *OpenAIBatchError) {
+	fail := func(code, message, param string) (*OpenAIBatchItem, *OpenAIBatchError) {
+		var p *string
+		if param != "" {
+			p = &param
+		}
+		e := openAIBatchValidationError(code, message, p, lineNo)
+		return nil, &e
+	}
+
+	var req OpenAIBatchRequestLine
+	if err := json.Unmarshal(line, &req); err != nil {
+		return fail("invalid_json_line", "This line is not parseable as valid JSON.", "")
+	}
+	if req.CustomID == "" || len(req.CustomID) > openAIBatchMaxCustomID {
+		return fail("invalid_request", fmt.Sprintf("custom_id must be a non-empty string of at most %d characters.", openAIBatchMaxCustomID), "custom_id")
+	}
+	if _, dup := seen[req.CustomID]; dup {
+		return fail("duplicate_custom_id", fmt.Sprintf("The custom_id %q is used more than once.", req.CustomID), "custom_id")
+	}
+	seen[req.CustomID] = struct{}{}
+	if req.Method != http.MethodPost {
+		return fail("invalid_request", "method must be POST.", "method")
+	}
+	if req.URL != endpoint {
+		return fail("mismatched_endpoint", fmt.Sprintf("The url %q does not match the batch endpoint %s.", req.URL, endpoint), "url")
+	}
+	body := bytes.TrimSpace(req.Body)
+	if len(body) == 0 || body[0] != '{' {
+		return fail("invalid_request", "body must be a JSON object.", "body")
+	}
+	model := gjson.GetBytes(body, "model")
+	if model.Type != gjson.String || model.String() == "" {
+		return fail("missing_required_parameter", "body.model is required.", "body.model")
+	}
+	if gjson.GetBytes(body, "stream").Bool() {
+		return fail("invalid_request", "Streaming is not supported in batches.", "body.stream")
+	}
+	if apiKey != nil {
+		requested := model.String()
+		resolved := apiKey.ResolveModelAlias(requested)
+		if !apiKey.IsModelAllowed(resolved) {
+			return fail("model_not_allowed", fmt.Sprintf("The model %q is not allowed for this API key.", requested), "body.model")
+		}
+		if resolved != requested {
+			if rewritten, err := sjson.SetBytes(body, "model", resolved); err == nil {
+				body = rewritten
+			}
+		}
+	}
+	return &OpenAIBatchItem{
+		LineNo:   lineNo,
+		CustomID: req.CustomID,
+		Body:     json.RawMessage(body),
+		Status:   OpenAIBatchItemPending,
+	}, nil
+}
+
+func openAIBatchValidationError(code, message string, param *string, line int) OpenAIBatchError {
+	e := OpenAIBatchError{Code: code, Message: message, Param: param}
+	if line > 0 {
+		e.Line = &line
+	}
+	return e
+}
+
+// =========================
+// 执行
+// =========================
+
+// openAIBatchExecContext 单轮执行中同一批次共享的调度与计费上下文
+type openAIBatchExecContext struct {
+	batch *OpenAIBatch
+	batchOwner
+}
+
+// openAIBatchOutcome 单个请求的执行结果
+type openAIBatchOutcome int
+
+const (
+	openAIBatchOutcomeDone     openAIBatchOutcome = iota // 已得到终态结果
+	openAIBatchOutcomeBusy                               // 无空闲账号，延后且不计入尝试次数
+	openAIBatchOutcomeRetry                              // 上游失败，延后并计入尝试次数
+	openAIBatchOutcomeCanceled                           // worker 停止，释放租约
+)
+
+func (s *OpenAIBatchService) processDueItems() {
+	ctx := s.workerCtx
+	cfg := s.cfg.OpenAIBatch
+	lease := time.Duration(cfg.RequestTimeoutSeconds)*time.Second + openAIBatchLeaseMargin
+	items, err := s.repo.ClaimDueItems(ctx, cfg.ClaimSize, lease)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] claim items failed: %v", err)
+		}
+		return
+	}
+	if len(items) == 0 {
+		return
+	}
+
+	execs := make(map[string]*openAIBatchExecContext)
+	for i := range items {
+		id := items[i].BatchID
+		if _, ok := execs[id]; !ok {
+			execs[id] = s.loadExecContext(ctx, id)
+		}
+	}
+
+	sem := make(chan struct{}, max(1, cfg.Concurrency))
+	var wg sync.WaitGroup
+	for i := range items {
+		item := &items[i]
+		exec := execs[item.BatchID]
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			s.settle(item, s.executeItem(ctx, exec, item))
+		}()
+	}
+	wg.Wait()
+}
+
+// loadExecContext 加载批次所属 API Key、订阅，并校验计费资格
+func (s *OpenAIBatchService) loadExecContext(ctx context.Context, batchID string) *openAIBatchExecContext {
+	exec := &openAIBatchExecContext{}
+	batch, err := s.repo.GetByID(ctx, batchID)
+	if err != nil {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] load batch failed: batch=%s err=%v", batchID, err)
+		exec.fail("api_error", "internal error")
+		return exec
+	}
+	exec.batch = batch
+	exec.batchOwner, err = s.owners.load(ctx, batch.APIKeyID, batch.GroupID)
+	if err != nil {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] load batch owner failed: batch=%s err=%v", batchID, err)
+	}
+	return exec
+}
+
+// executeItem 执行单个请求：低优先级选号 → 转发 → 逐行计费
+func (s *OpenAIBatchService) executeItem(ctx context.Context, exec *openAIBatchExecContext, item *OpenAIBatchItem) openAIBatchOutcome {
+	lineID, err := generateOpenAIBatchID(openAIBatchRequestIDPrefix)
+	if err != nil {
+		return openAIBatchOutcomeRetry
+	}
+	if exec.errType != "" {
+		item.Status = OpenAIBatchItemFailed
+		item.Output = openAIBatchErrorLine(lineID, item.CustomID, exec.errType, exec.errMessage)
+		return openAIBatchOutcomeDone
+	}
+
+	apiKey := exec.apiKey
+	model := gjson.GetBytes(item.Body, "model").String()
+	scheduleModel := model
+	defaultMappedModel := apiKey.Group.DefaultMappedModel
+	maxSwitches := 10
+	if s.cfg.Gateway.MaxAccountSwitches > 0 {
+		maxSwitches = s.cfg.Gateway.MaxAccountSwitches
+	}
+	excluded := make(map[int64]struct{})
+	outcome := openAIBatchOutcomeBusy
+	var lastFailover *UpstreamFailoverError
+	for switches := 0; switches <= maxSwitches; switches++ {
+		if ctx.Err() != nil {
+			return openAIBatchOutcomeCanceled
+		}
+		selection, _, err := s.gatewayService.SelectAccountWithScheduler(ctx, apiKey.GroupID, "", "", scheduleModel, excluded, OpenAIUpstreamTransportAny)
+		if err != nil || selection == nil || selection.Account == nil {
+			// 与 Chat Completions 一致：首次调度失败且分组配置了默认映射模型时改用默认模型调度
+			if exec.batch.Endpoint == OpenAIBatchEndpointChatCompletions && len(excluded) == 0 &&
+				defaultMappedModel != "" && scheduleModel != defaultMappedModel {
+				scheduleModel = defaultMappedModel
+				continue
+			}
+			break
+		}
+		account := selection.Account
+		release, ok := acquireBatchSpareSlot(ctx, s.concurrencyService, selection, s.cfg.OpenAIBatch.MaxAccountLoadPercent)
+		if !ok {
+			excluded[account.ID] = struct{}{}
+			continue
+		}
+
+		mappedDefault := defaultMappedModel
+		if scheduleModel != model {
+			mappedDefault = scheduleModel
+		}
+		result, status, body, err := s.forward(ctx, exec, account, item.Body, mappedDefault)
+		release()
+		if err != nil {
+			s.gatewayService.ReportOpenAIAccountScheduleResult(account.ID, false, nil)
+			var failoverErr *UpstreamFailoverError
+			if errors.As(err, &failoverErr) {
+				s.gatewayService.RecordOpenAIAccountSwitch()
+				excluded[account.ID] = struct{}{}
+				lastFailover = failoverErr
+				outcome = openAIBatchOutcomeRetry
+				continue
+			}
+			if ctx.Err() != nil {
+				return openAIBatchOutcomeCanceled
+			}
+			if status < http.StatusBadRequest {
+				status = http.StatusBadGateway
+				body = nil
+			}
+			item.Status = OpenAIBatchItemFailed
+			item.Output = openAIBatchResponseLine(lineID, item.CustomID, status, "", body)
+			return openAIBatchOutcomeDone
+		}
+		var requestID string
+		if result != nil {
+			requestID = result.RequestID
+			if account.Type == AccountTypeOAuth {
+				s.gatewayService.UpdateCodexUsageSnapshotFromHeaders(ctx, account.ID, result.ResponseHeaders)
+			}
+			s.gatewayService.ReportOpenAIAccountScheduleResult(account.ID, true, result.FirstTokenMs)
+		}
+		if status >= http.StatusBadRequest {
+			item.Status = OpenAIBatchItemFailed
+			item.Output = openAIBatchResponseLine(lineID, item.CustomID, status, requestID, body)
+			return openAIBatchOutcomeDone
+		}
+
+		item.Status = OpenAIBatchItemCompleted
+		item.Output = openAIBatchResponseLine(lineID, item.CustomID, status, requestID, body)
+		s.recordUsage(ctx, exec, account, result)
+		return openAIBatchOutcomeDone
+	}
+
+	if outcome == openAIBatchOutcomeRetry && item.Attempts >= s.cfg.OpenAIBatch.MaxAttempts {
+		item.Status = OpenAIBatchItemFailed
+		if lastFailover != nil && len(lastFailover.ResponseBody) > 0 {
+			item.Output = openAIBatchResponseLine(lineID, item.CustomID, lastFailover.StatusCode, "", lastFailover.ResponseBody)
+		} else {
+			item.Output = openAIBatchErrorLine(lineID, item.CustomID, "server_error", "upstream unavailable")
+		}
+		return openAIBatchOutcomeDone
+	}
+	return outcome
+}
+
+// forward 以非流式请求转发到上游，返回网关写出的状态码与响应体
+func (s *OpenAIBatchService) forward(ctx context.Context, exec *openAIBatchExecContext, account *Account, body []byte, defaultMappedModel string) (*OpenAIForwardResult, int, []byte, error) {
+	timeout := time.Duration(s.cfg.OpenAIBatch.RequestTimeoutSeconds) * time.Second
+	reqCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	endpoint := exec.batch.Endpoint
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	req, _ := http.NewRequestWithContext(reqCtx, http.MethodPost, "http://localhost"+endpoint, bytes.NewReader(body))
+	req.Header.Set("content-type", "application/json")
+	req.Header.Set("user-agent", openAIBatchUserAgent)
+	c.Request = req
+	c.Set("api_key", exec.apiKey)
+	SetOpenAIClientTransport(c, OpenAIClientTransportHTTP)
+
+	var (
+		result *OpenAIForwardResult
+		err    error
+	)
+	if endpoint == OpenAIBatchEndpointChatCompletions {
+		result, err = s.gatewayService.ForwardAsChatCompletions(reqCtx, c, account, body, "", defaultMappedModel)
+	} else {
+		result, err = s.gatewayService.Forward(reqCtx, c, account, body)
+	}
+	return result, w.Code, w.Body.Bytes(), err
+}
+
+func (s *OpenAIBatchService) recordUsage(ctx context.Context, exec *openAIBatchExecContext, account *Account, result *OpenAIForwardResult) {
+	if result == nil {
+		return
+	}
+	input := &OpenAIRecordUsageInput{
+		Result:          result,
+		APIKey:          exec.apiKey,
+		User:            exec.apiKey.User,
+		Account:         account,
+		Subscription:    exec.subscription,
+		UserAgent:       openAIBatchUserAgent,
+		PriceMultiplier: s.cfg.OpenAIBatch.PriceMultiplier,
+	}
+	if s.apiKeyService != nil {
+		input.APIKeyService = s.apiKeyService
+	}
+	// 计费不随 worker 停止而中断
+	usageCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), openAIBatchSaveTimeout)
+	defer cancel()
+	if err := s.gatewayService.RecordUsage(usageCtx, input); err != nil {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] record usage failed: batch=%s api_key=%d err=%v",
+			exec.batch.ID, exec.apiKey.ID, err)
+	}
+}
+
+// settle 根据执行结果保存请求状态
+func (s *OpenAIBatchService) settle(item *OpenAIBatchItem, outcome openAIBatchOutcome) {
+	retryAt := s.now().Add(time.Duration(s.cfg.OpenAIBatch.RetryDelaySeconds) * time.Second)
+	switch outcome {
+	case openAIBatchOutcomeDone:
+	case openAIBatchOutcomeBusy:
+		// 账号繁忙不算失败
+		item.Attempts--
+		item.NextAttemptAt = retryAt
+	case openAIBatchOutcomeRetry:
+		item.NextAttemptAt = retryAt
+	case openAIBatchOutcomeCanceled:
+		item.Attempts--
+		item.NextAttemptAt = s.now()
+	}
+	if item.Attempts < 0 {
+		item.Attempts = 0
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), openAIBatchSaveTimeout)
+	defer cancel()
+	if err := s.repo.SaveItemResult(ctx, item); err != nil {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] save item failed: batch=%s item=%d err=%v", item.BatchID, item.ID, err)
+	}
+}
+
+func (s *OpenAIBatchService) advance() {
+	ctx, cancel := context.WithTimeout(s.workerCtx, openAIBatchSaveTimeout)
+	defer cancel()
+	if err := s.repo.AdvanceBatches(ctx); err != nil && ctx.Err() == nil {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] advance batches failed: %v", err)
+	}
+}
+
+// =========================
+// 生成结果文件
+// =========================
+
+func (s *OpenAIBatchService) closeBatches() {
+	ctx := s.workerCtx
+	batches, err := s.repo.ClaimClosable(ctx, openAIBatchStageClaimSize, openAIBatchStageLease)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] claim closable batches failed: %v", err)
+		}
+		return
+	}
+	for i := range batches {
+		if err := s.closeBatch(ctx, &batches[i]); err != nil && ctx.Err() == nil {
+			logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] close batch failed: batch=%s err=%v", batches[i].ID, err)
+		}
+	}
+}
+
+// closeBatch 将批内请求按结果写入结果文件（completed）与错误文件（其余），
+// 登记为 batch_output 文件后将批次置为终态
+func (s *OpenAIBatchService) closeBatch(ctx context.Context, batch *OpenAIBatch) error {
+	fromStatus := batch.Status
+	out, err := s.newOutputWriter()
+	if err != nil {
+		return err
+	}
+	defer out.discard()
+	errOut, err := s.newOutputWriter()
+	if err != nil {
+		return err
+	}
+	defer errOut.discard()
+
+	expired := 0
+	var afterID int64
+	for {
+		items, err := s.repo.ListItems(ctx, batch.ID, afterID, openAIBatchItemsPageSize)
+		if err != nil {
+			return err
+		}
+		for i := range items {
+			item := &items[i]
+			if item.Status == OpenAIBatchItemExpired {
+				expired++
+			}
+			lineID, err := generateOpenAIBatchID(openAIBatchRequestIDPrefix)
+			if err != nil {
+				return err
+			}
+			line := item.OutputLine(lineID)
+			if line == nil {
+				continue
+			}
+			w := errOut
+			if item.Status == OpenAIBatchItemCompleted {
+				w = out
+			}
+			if err := w.writeLine(line); err != nil {
+				return err
+			}
+		}
+		if len(items) < openAIBatchItemsPageSize {
+			break
+		}
+		afterID = items[len(items)-1].ID
+	}
+
+	switch {
+	case fromStatus == OpenAIBatchStatusCancelling:
+		batch.Status = OpenAIBatchStatusCancelled
+	case expired > 0:
+		batch.Status = OpenAIBatchStatusExpired
+	default:
+		batch.Status = OpenAIBatchStatusCompleted
+	}
+
+	saveCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), openAIBatchSaveTimeout)
+	defer cancel()
+	var stored []*OpenAIFile
+	outputFile, err := out.store(saveCtx, s.files, batch.UserID, batch.ID+"_output.jsonl")
+	if err != nil {
+		return err
+	}
+	if outputFile != nil {
+		stored = append(stored, outputFile)
+		batch.OutputFileID = &outputFile.ID
+	}
+	errorFile, err := errOut.store(saveCtx, s.files, batch.UserID, batch.ID+"_error.jsonl")
+	if err != nil {
+		s.discardStored(saveCtx, stored)
+		return err
+	}
+	if errorFile != nil {
+		stored = append(stored, errorFile)
+		batch.ErrorFileID = &errorFile.ID
+	}
+	if err := s.repo.CloseBatch(saveCtx, batch, fromStatus); err != nil {
+		s.discardStored(saveCtx, stored)
+		return err
+	}
+	logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] batch %s: batch=%s output_lines=%d error_lines=%d",
+		batch.Status, batch.ID, out.lines, errOut.lines)
+	return nil
+}
+
+func (s *OpenAIBatchService) discardStored(ctx context.Context, files []*OpenAIFile) {
+	for _, file := range files {
+		if err := s.files.removeContent(ctx, file); err != nil {
+			logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] remove result file failed: file=%s err=%v", file.ID, err)
+		}
+		if err := s.files.repo.Delete(ctx, file.ID); err != nil {
+			logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] delete result file failed: file=%s err=%v", file.ID, err)
+		}
+	}
+}
+
+// openAIBatchOutputWriter 将结果行写入存储目录下的临时文件
+type openAIBatchOutputWriter struct {
+	f     *os.File
+	buf   *bufio.Writer
+	lines int
+}
+
+func (s *OpenAIBatchService) newOutputWriter() (*openAIBatchOutputWriter, error) {
+	f, err := s.files.createTemp()
+	if err != nil {
+		return nil, err
+	}
+	return &openAIBatchOutputWriter{f: f, buf: bufio.NewWriter(f)}, nil
+}
+
+func (w *openAIBatchOutputWriter) writeLine(line []byte) error {
+	w.lines++
+	if _, err := w.buf.Write(line); err != nil {
+		return err
+	}
+	return w.buf.WriteByte('\n')
+}
+
+// store 登记为 batch_output 文件；没有任何行时不生成文件，返回 nil
+func (w *openAIBatchOutputWriter) store(ctx context.Context, files *OpenAIFileService, userID int64, filename string) (*OpenAIFile, error) {
+	if w.lines == 0 {
+		return nil, nil
+	}
+	if err := w.buf.Flush(); err != nil {
+		return nil, err
+	}
+	if err := w.f.Close(); err != nil {
+		return nil, err
+	}
+	path := w.f.Name()
+	w.f = nil
+	return files.storeLocalFile(ctx, userID, OpenAIFilePurposeBatchOutput, filename, path)
+}
+
+// discard 删除未登记的临时文件
+func (w *openAIBatchOutputWriter) discard() {
+	if w.f == nil {
+		return
+	}
+	_ = w.f.Close()
+	_ = os.Remove(w.f.Name())
+}
+
+// cleanup 每小时删除一次超过保留期的已结束批次与文件
+func (s *OpenAIBatchService) cleanup() {
+	now := s.now()
+	last := s.lastCleanup.Load()
+	if last > 0 && now.Sub(time.Unix(0, last)) < openAIBatchCleanupInterval {
+		return
+	}
+	s.lastCleanup.Store(now.UnixNano())
+
+	ctx, cancel := context.WithTimeout(s.workerCtx, openAIBatchSaveTimeout)
+	defer cancel()
+	cutoff := now.AddDate(0, 0, -s.cfg.OpenAIBatch.RetentionDays)
+	deleted, err := s.repo.DeleteFinishedBefore(ctx, cutoff)
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] cleanup batches failed: %v", err)
+		}
+	} else if deleted > 0 {
+		logger.LegacyPrintf("service.openai_batch", "[OpenAIBatch] deleted %d expired batch(es)", deleted)
+	}
+	s.files.deleteExpired(ctx)
+}
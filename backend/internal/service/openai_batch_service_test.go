@@ -0,0 +1,521 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type openAIFileRepoStub struct {
+	OpenAIFileRepository
+
+	mu    sync.Mutex
+	files map[string]*OpenAIFile
+}
+
+func newOpenAIFileRepoStub() *openAIFileRepoStub {
+	return &openAIFileRepoStub{files: map[string]*OpenAIFile{}}
+}
+
+func (r *openAIFileRepoStub) Create(_ context.Context, file *OpenAIFile) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *file
+	r.files[file.ID] = &cp
+	return nil
+}
+
+func (r *openAIFileRepoStub) GetByID(_ context.Context, id string) (*OpenAIFile, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	f, ok := r.files[id]
+	if !ok {
+		return nil, ErrOpenAIFileNotFound
+	}
+	cp := *f
+	return &cp, nil
+}
+
+func (r *openAIFileRepoStub) Delete(_ context.Context, id string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.files, id)
+	return nil
+}
+
+type openAIBatchRepoStub struct {
+	OpenAIBatchRepository
+
+	mu         sync.Mutex
+	batches    map[string]*OpenAIBatch
+	items      []OpenAIBatchItem
+	validating []OpenAIBatch
+	claimed    []OpenAIBatchItem
+	saved      []OpenAIBatchItem
+	failed     map[string][]OpenAIBatchError
+	closed     []OpenAIBatch
+}
+
+func newOpenAIBatchRepoStub() *openAIBatchRepoStub {
+	return &openAIBatchRepoStub{batches: map[string]*OpenAIBatch{}, failed: map[string][]OpenAIBatchError{}}
+}
+
+func (r *openAIBatchRepoStub) Create(_ context.Context, batch *OpenAIBatch) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *batch
+	r.batches[batch.ID] = &cp
+	return nil
+}
+
+func (r *openAIBatchRepoStub) GetByID(_ context.Context, id string) (*OpenAIBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	b, ok := r.batches[id]
+	if !ok {
+		return nil, ErrOpenAIBatchNotFound
+	}
+	cp := *b
+	return &cp, nil
+}
+
+func (r *openAIBatchRepoStub) ClaimValidating(context.Context, int, time.Duration) ([]OpenAIBatch, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.validating
+	r.validating = nil
+	return out, nil
+}
+
+func (r *openAIBatchRepoStub) StartBatch(_ context.Context, id string, items []OpenAIBatchItem) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for i := range items {
+		items[i].ID = int64(len(r.items) + 1)
+		r.items = append(r.items, items[i])
+	}
+	r.batches[id].Status = OpenAIBatchStatusInProgress
+	return true, nil
+}
+
+func (r *openAIBatchRepoStub) FailBatch(_ context.Context, id string, errs []OpenAIBatchError) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.failed[id] = errs
+	return nil
+}
+
+func (r *openAIBatchRepoStub) ListItems(_ context.Context, batchID string, afterID int64, limit int) ([]OpenAIBatchItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []OpenAIBatchItem
+	for _, item := range r.items {
+		if item.BatchID == batchID && item.ID > afterID && len(out) < limit {
+			out = append(out, item)
+		}
+	}
+	return out, nil
+}
+
+func (r *openAIBatchRepoStub) ClaimDueItems(context.Context, int, time.Duration) ([]OpenAIBatchItem, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.claimed
+	r.claimed = nil
+	return out, nil
+}
+
+func (r *openAIBatchRepoStub) SaveItemResult(_ context.Context, item *OpenAIBatchItem) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved = append(r.saved, *item)
+	return nil
+}
+
+func (r *openAIBatchRepoStub) CloseBatch(_ context.Context, batch *OpenAIBatch, _ string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.closed = append(r.closed, *batch)
+	return nil
+}
+
+func newOpenAIBatchTestConfig(t *testing.T) *config.Config {
+	return &config.Config{OpenAIBatch: config.OpenAIBatchConfig{
+		Enabled:               true,
+		Storage:               config.OpenAIFileStorageLocal,
+		LocalDir:              t.TempDir(),
+		MaxFileSizeMB:         1,
+		MaxRequestsPerBatch:   3,
+		WorkerIntervalSeconds: 5,
+		ClaimSize:             10,
+		Concurrency:           2,
+		MaxAccountLoadPercent: 50,
+		RequestTimeoutSeconds: 60,
+		MaxAttempts:           3,
+		RetryDelaySeconds:     30,
+		RetentionDays:         30,
+		PriceMultiplier:       0.5,
+	}}
+}
+
+func newOpenAIBatchTestAPIKey() *APIKey {
+	groupID := int64(8)
+	return &APIKey{
+		ID:      21,
+		UserID:  42,
+		Status:  StatusActive,
+		GroupID: &groupID,
+		Group:   &Group{ID: groupID, Platform: PlatformOpenAI, Status: StatusActive},
+		User:    &User{ID: 42, Status: StatusActive},
+	}
+}
+
+func newOpenAIBatchTestService(t *testing.T, apiKeyRepo APIKeyRepository) (*OpenAIBatchService, *openAIBatchRepoStub, *openAIFileRepoStub) {
+	cfg := newOpenAIBatchTestConfig(t)
+	fileRepo := newOpenAIFileRepoStub()
+	repo := newOpenAIBatchRepoStub()
+	files := NewOpenAIFileService(fileRepo, nil, cfg)
+	svc := NewOpenAIBatchService(repo, files, apiKeyRepo, nil, nil, nil, nil, nil, nil, nil, cfg)
+	return svc, repo, fileRepo
+}
+
+func openAIBatchTestLine(customID, url, body string) string {
+	return `{"custom_id":"` + customID + `","method":"POST","url":"` + url + `","body":` + body + `}`
+}
+
+const openAIBatchTestBody = `{"model":"gpt-5","input":"hi"}`
+
+func uploadOpenAIBatchTestFile(t *testing.T, svc *OpenAIBatchService, userID int64, content string) *OpenAIFile {
+	t.Helper()
+	file, err := svc.files.Upload(context.Background(), UploadOpenAIFileInput{
+		UserID:   userID,
+		Purpose:  OpenAIFilePurposeBatch,
+		Filename: "input.jsonl",
+		Content:  strings.NewReader(content),
+	})
+	require.NoError(t, err)
+	return file
+}
+
+func TestParseOpenAIBatchInput_Valid(t *testing.T) {
+	apiKey := newOpenAIBatchTestAPIKey()
+	apiKey.AllowedModels = []string{"gpt-5*"}
+	apiKey.ModelAliases = map[string]string{"fast": "gpt-5-mini"}
+	input := openAIBatchTestLine("a", OpenAIBatchEndpointResponses, openAIBatchTestBody) + "\n\n" +
+		openAIBatchTestLine("b", OpenAIBatchEndpointResponses, `{"model":"fast","input":"yo"}`)
+
+	items, errs, err := parseOpenAIBatchInput(strings.NewReader(input), OpenAIBatchEndpointResponses, apiKey, 3)
+	require.NoError(t, err)
+	require.Empty(t, errs)
+	require.Len(t, items, 2)
+	require.Equal(t, 1, items[0].LineNo)
+	require.Equal(t, "a", items[0].CustomID)
+	require.Equal(t, OpenAIBatchItemPending, items[0].Status)
+	require.Equal(t, 3, items[1].LineNo)
+	require.Equal(t, "gpt-5-mini", gjson.GetBytes(items[1].Body, "model").String())
+}
+
+func TestParseOpenAIBatchInput_LineErrors(t *testing.T) {
+	apiKey := newOpenAIBatchTestAPIKey()
+	apiKey.DeniedModels = []string{"o1*"}
+	tests := []struct {
+		name     string
+		line     string
+		wantCode string
+	}{
+		{name: "invalid json", line: `{"custom_id":`, wantCode: "invalid_json_line"},
+		{name: "missing custom_id", line: openAIBatchTestLine("", OpenAIBatchEndpointResponses, openAIBatchTestBody), wantCode: "invalid_request"},
+		{name: "wrong method", line: `{"custom_id":"a","method":"GET","url":"/v1/responses","body":{"model":"gpt-5"}}`, wantCode: "invalid_request"},
+		{name: "mismatched endpoint", line: openAIBatchTestLine("a", OpenAIBatchEndpointChatCompletions, openAIBatchTestBody), wantCode: "mismatched_endpoint"},
+		{name: "body not object", line: openAIBatchTestLine("a", OpenAIBatchEndpointResponses, `"hi"`), wantCode: "invalid_request"},
+		{name: "missing model", line: openAIBatchTestLine("a", OpenAIBatchEndpointResponses, `{"input":"hi"}`), wantCode: "missing_required_parameter"},
+		{name: "stream", line: openAIBatchTestLine("a", OpenAIBatchEndpointResponses, `{"model":"gpt-5","stream":true}`), wantCode: "invalid_request"},
+		{name: "model denied", line: openAIBatchTestLine("a", OpenAIBatchEndpointResponses, `{"model":"o1-pro"}`), wantCode: "model_not_allowed"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			input := openAIBatchTestLine("ok", OpenAIBatchEndpointResponses, openAIBatchTestBody) + "\n" + tt.line + "\n"
+			items, errs, err := parseOpenAIBatchInput(strings.NewReader(input), OpenAIBatchEndpointResponses, apiKey, 10)
+			require.NoError(t, err)
+			require.Nil(t, items)
+			require.Len(t, errs, 1)
+			require.Equal(t, tt.wantCode, errs[0].Code)
+			require.NotNil(t, errs[0].Line)
+			require.Equal(t, 2, *errs[0].Line)
+		})
+	}
+}
+
+func TestParseOpenAIBatchInput_FileErrors(t *testing.T) {
+	line := openAIBatchTestLine("a", OpenAIBatchEndpointResponses, openAIBatchTestBody)
+
+	_, errs, err := parseOpenAIBatchInput(strings.NewReader(line+"\n"+line), OpenAIBatchEndpointResponses, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	require.Equal(t, "duplicate_custom_id", errs[0].Code)
+
+	_, errs, err = parseOpenAIBatchInput(strings.NewReader("\n \n"), OpenAIBatchEndpointResponses, nil, 10)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	require.Equal(t, "empty_file", errs[0].Code)
+	require.Nil(t, errs[0].Line)
+
+	var many []string
+	for _, id := range []string{"a", "b", "c"} {
+		many = append(many, openAIBatchTestLine(id, OpenAIBatchEndpointResponses, openAIBatchTestBody))
+	}
+	_, errs, err = parseOpenAIBatchInput(strings.NewReader(strings.Join(many, "\n")), OpenAIBatchEndpointResponses, nil, 2)
+	require.NoError(t, err)
+	require.Len(t, errs, 1)
+	require.Equal(t, "too_many_requests", errs[0].Code)
+}
+
+func TestOpenAIBatchCreate_Validation(t *testing.T) {
+	svc, _, fileRepo := newOpenAIBatchTestService(t, nil)
+	apiKey := newOpenAIBatchTestAPIKey()
+	input := uploadOpenAIBatchTestFile(t, svc, apiKey.UserID, openAIBatchTestLine("a", OpenAIBatchEndpointResponses, openAIBatchTestBody))
+	fileRepo.files["file-output"] = &OpenAIFile{ID: "file-output", UserID: apiKey.UserID, Purpose: OpenAIFilePurposeBatchOutput}
+	fileRepo.files["file-other"] = &OpenAIFile{ID: "file-other", UserID: 99, Purpose: OpenAIFilePurposeBatch}
+
+	anthropicKey := newOpenAIBatchTestAPIKey()
+	anthropicKey.Group.Platform = PlatformAnthropic
+
+	tests := []struct {
+		name   string
+		in     CreateOpenAIBatchInput
+		status int
+	}{
+		{name: "non-openai group", in: CreateOpenAIBatchInput{APIKey: anthropicKey, InputFileID: input.ID, Endpoint: OpenAIBatchEndpointResponses, CompletionWindow: "24h"}, status: 400},
+		{name: "unsupported endpoint", in: CreateOpenAIBatchInput{APIKey: apiKey, InputFileID: input.ID, Endpoint: "/v1/embeddings", CompletionWindow: "24h"}, status: 400},
+		{name: "completion window", in: CreateOpenAIBatchInput{APIKey: apiKey, InputFileID: input.ID, Endpoint: OpenAIBatchEndpointResponses, CompletionWindow: "1h"}, status: 400},
+		{name: "output file as input", in: CreateOpenAIBatchInput{APIKey: apiKey, InputFileID: "file-output", Endpoint: OpenAIBatchEndpointResponses, CompletionWindow: "24h"}, status: 400},
+		{name: "other user's file", in: CreateOpenAIBatchInput{APIKey: apiKey, InputFileID: "file-other", Endpoint: OpenAIBatchEndpointResponses, CompletionWindow: "24h"}, status: 404},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := svc.Create(context.Background(), tt.in)
+			require.Error(t, err)
+			require.Equal(t, tt.status, infraerrors.Code(err))
+		})
+	}
+
+	batch, err := svc.Create(context.Background(), CreateOpenAIBatchInput{
+		APIKey: apiKey, InputFileID: input.ID, Endpoint: OpenAIBatchEndpointResponses, CompletionWindow: "24h",
+		Metadata: map[string]string{"job": "nightly"},
+	})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(batch.ID, "batch_"))
+	require.Equal(t, OpenAIBatchStatusValidating, batch.Status)
+	require.Equal(t, batch.CreatedAt.Add(24*time.Hour), batch.ExpiresAt)
+	require.Equal(t, int64(8), *batch.GroupID)
+}
+
+func TestOpenAIBatchValidateBatches(t *testing.T) {
+	apiKey := newOpenAIBatchTestAPIKey()
+	svc, repo, _ := newOpenAIBatchTestService(t, &messageBatchAPIKeyRepoStub{keys: map[int64]*APIKey{apiKey.ID: apiKey}})
+
+	good := uploadOpenAIBatchTestFile(t, svc, apiKey.UserID,
+		openAIBatchTestLine("a", OpenAIBatchEndpointResponses, openAIBatchTestBody)+"\n"+
+			openAIBatchTestLine("b", OpenAIBatchEndpointResponses, openAIBatchTestBody)+"\n")
+	bad := uploadOpenAIBatchTestFile(t, svc, apiKey.UserID, "not json\n")
+	for _, b := range []*OpenAIBatch{
+		{ID: "batch_good", UserID: apiKey.UserID, APIKeyID: apiKey.ID, GroupID: apiKey.GroupID, Endpoint: OpenAIBatchEndpointResponses, InputFileID: good.ID, Status: OpenAIBatchStatusValidating},
+		{ID: "batch_bad", UserID: apiKey.UserID, APIKeyID: apiKey.ID, GroupID: apiKey.GroupID, Endpoint: OpenAIBatchEndpointResponses, InputFileID: bad.ID, Status: OpenAIBatchStatusValidating},
+		{ID: "batch_gone", UserID: apiKey.UserID, APIKeyID: apiKey.ID, GroupID: apiKey.GroupID, Endpoint: OpenAIBatchEndpointResponses, InputFileID: "file-missing", Status: OpenAIBatchStatusValidating},
+	} {
+		require.NoError(t, repo.Create(context.Background(), b))
+		repo.validating = append(repo.validating, *b)
+	}
+
+	svc.validateBatches()
+
+	require.Equal(t, OpenAIBatchStatusInProgress, repo.batches["batch_good"].Status)
+	require.Len(t, repo.items, 2)
+	require.Equal(t, "batch_good", repo.items[0].BatchID)
+	require.Equal(t, "b", repo.items[1].CustomID)
+
+	require.Len(t, repo.failed["batch_bad"], 1)
+	require.Equal(t, "invalid_json_line", repo.failed["batch_bad"][0].Code)
+	require.Len(t, repo.failed["batch_gone"], 1)
+	require.Equal(t, "invalid_file", repo.failed["batch_gone"][0].Code)
+}
+
+func TestOpenAIBatchItemOutputLine(t *testing.T) {
+	stored := json.RawMessage(`{"id":"batch_req_1","custom_id":"a","response":{"status_code":200,"request_id":"r","body":{}},"error":null}`)
+	require.Equal(t, stored, (&OpenAIBatchItem{CustomID: "a", Status: OpenAIBatchItemCompleted, Output: stored}).OutputLine("batch_req_x"))
+	require.Nil(t, (&OpenAIBatchItem{CustomID: "a", Status: OpenAIBatchItemPending}).OutputLine("batch_req_x"))
+
+	line := (&OpenAIBatchItem{CustomID: "c", Status: OpenAIBatchItemCancelled}).OutputLine("batch_req_x")
+	require.Equal(t, "batch_req_x", gjson.GetBytes(line, "id").String())
+	require.Equal(t, "batch_cancelled", gjson.GetBytes(line, "error.code").String())
+	require.Equal(t, "null", gjson.GetBytes(line, "response").Raw)
+
+	line = (&OpenAIBatchItem{CustomID: "e", Status: OpenAIBatchItemExpired}).OutputLine("batch_req_x")
+	require.Equal(t, "batch_expired", gjson.GetBytes(line, "error.code").String())
+}
+
+func TestOpenAIBatchResponseLine_WrapsNonJSONBody(t *testing.T) {
+	line := openAIBatchResponseLine("batch_req_1", "a", 502, "", []byte("<html>bad gateway</html>"))
+	require.Equal(t, int64(502), gjson.GetBytes(line, "response.status_code").Int())
+	require.Equal(t, "api_error", gjson.GetBytes(line, "response.body.error.type").String())
+}
+
+func TestOpenAIBatchCloseBatch(t *testing.T) {
+	okLine := string(openAIBatchResponseLine("batch_req_1", "ok", 200, "req_1", []byte(`{"id":"resp_1"}`)))
+	failLine := string(openAIBatchResponseLine("batch_req_2", "bad", 400, "req_2", []byte(`{"error":{"message":"nope"}}`)))
+	tests := []struct {
+		name       string
+		fromStatus string
+		statuses   []string
+		wantStatus string
+		wantOutput int
+		wantErrors int
+	}{
+		{name: "completed", fromStatus: OpenAIBatchStatusFinalizing, statuses: []string{OpenAIBatchItemCompleted, OpenAIBatchItemFailed}, wantStatus: OpenAIBatchStatusCompleted, wantOutput: 1, wantErrors: 1},
+		{name: "all succeeded", fromStatus: OpenAIBatchStatusFinalizing, statuses: []string{OpenAIBatchItemCompleted}, wantStatus: OpenAIBatchStatusCompleted, wantOutput: 1},
+		{name: "expired", fromStatus: OpenAIBatchStatusFinalizing, statuses: []string{OpenAIBatchItemCompleted, OpenAIBatchItemExpired}, wantStatus: OpenAIBatchStatusExpired, wantOutput: 1, wantErrors: 1},
+		{name: "cancelled", fromStatus: OpenAIBatchStatusCancelling, statuses: []string{OpenAIBatchItemCancelled, OpenAIBatchItemCancelled}, wantStatus: OpenAIBatchStatusCancelled, wantErrors: 2},
+		{name: "cancelled during validation", fromStatus: OpenAIBatchStatusCancelling, wantStatus: OpenAIBatchStatusCancelled},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			svc, repo, fileRepo := newOpenAIBatchTestService(t, nil)
+			batch := &OpenAIBatch{ID: "batch_x", UserID: 42, Status: tt.fromStatus}
+			for i, status := range tt.statuses {
+				item := OpenAIBatchItem{ID: int64(i + 1), BatchID: batch.ID, CustomID: "c" + string(rune('a'+i)), Status: status}
+				switch status {
+				case OpenAIBatchItemCompleted:
+					item.Output = json.RawMessage(okLine)
+				case OpenAIBatchItemFailed:
+					item.Output = json.RawMessage(failLine)
+				}
+				repo.items = append(repo.items, item)
+			}
+
+			require.NoError(t, svc.closeBatch(context.Background(), batch))
+
+			require.Len(t, repo.closed, 1)
+			closed := repo.closed[0]
+			require.Equal(t, tt.wantStatus, closed.Status)
+			checkFile := func(id *string, wantLines int, wantName string) {
+				if wantLines == 0 {
+					require.Nil(t, id)
+					return
+				}
+				require.NotNil(t, id)
+				file, rc, err := svc.files.Open(context.Background(), 42, *id)
+				require.NoError(t, err)
+				defer func() { _ = rc.Close() }()
+				content, err := io.ReadAll(rc)
+				require.NoError(t, err)
+				require.Equal(t, OpenAIFilePurposeBatchOutput, file.Purpose)
+				require.Equal(t, wantName, file.Filename)
+				require.Equal(t, int64(len(content)), file.Bytes)
+				require.Len(t, strings.Split(strings.TrimSuffix(string(content), "\n"), "\n"), wantLines)
+			}
+			checkFile(closed.OutputFileID, tt.wantOutput, "batch_x_output.jsonl")
+			checkFile(closed.ErrorFileID, tt.wantErrors, "batch_x_error.jsonl")
+			wantFiles := 0
+			for _, lines := range []int{tt.wantOutput, tt.wantErrors} {
+				if lines > 0 {
+					wantFiles++
+				}
+			}
+			require.Len(t, fileRepo.files, wantFiles)
+
+			// 临时文件均已移走
+			tmp, err := os.ReadDir(svc.files.localPath("tmp"))
+			require.NoError(t, err)
+			require.Empty(t, tmp)
+		})
+	}
+}
+
+func TestOpenAIBatchProcessDueItems_FailsWhenAPIKeyGone(t *testing.T) {
+	groupID := int64(8)
+	disabled := newOpenAIBatchTestAPIKey()
+	disabled.Status = StatusDisabled
+	svc, repo, _ := newOpenAIBatchTestService(t, &messageBatchAPIKeyRepoStub{keys: map[int64]*APIKey{21: disabled}})
+	repo.batches["batch_gone"] = &OpenAIBatch{ID: "batch_gone", UserID: 42, APIKeyID: 99, GroupID: &groupID, Endpoint: OpenAIBatchEndpointResponses, Status: OpenAIBatchStatusInProgress}
+	repo.batches["batch_off"] = &OpenAIBatch{ID: "batch_off", UserID: 42, APIKeyID: 21, GroupID: &groupID, Endpoint: OpenAIBatchEndpointResponses, Status: OpenAIBatchStatusInProgress}
+	repo.claimed = []OpenAIBatchItem{
+		{ID: 1, BatchID: "batch_gone", CustomID: "a", Body: json.RawMessage(openAIBatchTestBody), Status: OpenAIBatchItemPending, Attempts: 1},
+		{ID: 2, BatchID: "batch_off", CustomID: "b", Body: json.RawMessage(openAIBatchTestBody), Status: OpenAIBatchItemPending, Attempts: 1},
+	}
+
+	svc.processDueItems()
+
+	require.Len(t, repo.saved, 2)
+	results := map[int64]OpenAIBatchItem{}
+	for _, item := range repo.saved {
+		results[item.ID] = item
+	}
+	require.Equal(t, OpenAIBatchItemFailed, results[1].Status)
+	require.Equal(t, "authentication_error", gjson.GetBytes(results[1].Output, "error.code").String())
+	require.Equal(t, "a", gjson.GetBytes(results[1].Output, "custom_id").String())
+	require.Equal(t, OpenAIBatchItemFailed, results[2].Status)
+	require.Equal(t, "permission_error", gjson.GetBytes(results[2].Output, "error.code").String())
+}
+
+func TestOpenAIBatchSettle(t *testing.T) {
+	svc, repo, _ := newOpenAIBatchTestService(t, nil)
+	now := time.Date(2026, 10, 18, 12, 0, 0, 0, time.UTC)
+	svc.now = func() time.Time { return now }
+
+	svc.settle(&OpenAIBatchItem{ID: 1, Status: OpenAIBatchItemPending, Attempts: 1}, openAIBatchOutcomeBusy)
+	svc.settle(&OpenAIBatchItem{ID: 2, Status: OpenAIBatchItemPending, Attempts: 1}, openAIBatchOutcomeRetry)
+	svc.settle(&OpenAIBatchItem{ID: 3, Status: OpenAIBatchItemPending, Attempts: 2}, openAIBatchOutcomeCanceled)
+
+	require.Len(t, repo.saved, 3)
+	require.Equal(t, 0, repo.saved[0].Attempts)
+	require.Equal(t, now.Add(30*time.Second), repo.saved[0].NextAttemptAt)
+	require.Equal(t, 1, repo.saved[1].Attempts)
+	require.Equal(t, now.Add(30*time.Second), repo.saved[1].NextAttemptAt)
+	require.Equal(t, 1, repo.saved[2].Attempts)
+	require.Equal(t, now, repo.saved[2].NextAttemptAt)
+}
+
+func TestOpenAIFileUpload(t *testing.T) {
+	svc, _, fileRepo := newOpenAIBatchTestService(t, nil)
+	files := svc.files
+	ctx := context.Background()
+
+	_, err := files.Upload(ctx, UploadOpenAIFileInput{UserID: 42, Purpose: "fine-tune", Filename: "a.jsonl", Content: strings.NewReader("x")})
+	require.Equal(t, 400, infraerrors.Code(err))
+	_, err = files.Upload(ctx, UploadOpenAIFileInput{UserID: 42, Purpose: OpenAIFilePurposeBatch, Filename: "a.jsonl", Content: strings.NewReader("")})
+	require.Equal(t, 400, infraerrors.Code(err))
+	_, err = files.Upload(ctx, UploadOpenAIFileInput{UserID: 42, Purpose: OpenAIFilePurposeBatch, Filename: "a.jsonl", Content: strings.NewReader(strings.Repeat("x", 1<<20+1))})
+	require.Equal(t, 413, infraerrors.Code(err))
+	require.Empty(t, fileRepo.files)
+
+	file, err := files.Upload(ctx, UploadOpenAIFileInput{UserID: 42, Purpose: OpenAIFilePurposeBatch, Filename: "../../etc/input.jsonl", Content: strings.NewReader("hello\n")})
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(file.ID, "file-"))
+	require.Equal(t, "input.jsonl", file.Filename)
+	require.Equal(t, int64(6), file.Bytes)
+
+	_, _, err = files.Open(ctx, 7, file.ID)
+	require.ErrorIs(t, err, ErrOpenAIFileNotFound)
+	_, rc, err := files.Open(ctx, 42, file.ID)
+	require.NoError(t, err)
+	content, _ := io.ReadAll(rc)
+	_ = rc.Close()
+	require.Equal(t, "hello\n", string(content))
+
+	require.NoError(t, files.Delete(ctx, 42, file.ID))
+	require.Empty(t, fileRepo.files)
+	_, err = os.Stat(files.localPath(file.ObjectKey))
+	require.True(t, os.IsNotExist(err))
+}
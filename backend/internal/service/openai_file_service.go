@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+const (
+	openAIFileDefaultListLimit = 10000
+	openAIFileExpireBatchSize  = 200
+	openAIFileS3KeyPrefix      = "openai-files/"
+	openAIFileMaxFilenameLen   = 255
+)
+
+// OpenAIFileService 实现 OpenAI Files API（仅批处理用途）：
+// 文件内容保存在本地目录或系统设置中激活的 S3，元数据保存在数据库，超过保留期后删除。
+type OpenAIFileService struct {
+	repo      OpenAIFileRepository
+	s3Storage *SoraS3Storage
+	cfg       *config.Config
+	now       func() time.Time
+}
+
+// NewOpenAIFileService 创建文件服务
+func NewOpenAIFileService(repo OpenAIFileRepository, s3Storage *SoraS3Storage, cfg *config.Config) *OpenAIFileService {
+	return &OpenAIFileService{
+		repo:      repo,
+		s3Storage: s3Storage,
+		cfg:       cfg,
+		now:       time.Now,
+	}
+}
+
+// Enabled 是否开放 Files API
+func (s *OpenAIFileService) Enabled() bool {
+	return s != nil && s.repo != nil && s.cfg != nil && s.cfg.OpenAIBatch.Enabled
+}
+
+// MaxFileSize 单个上传文件的最大字节数
+func (s *OpenAIFileService) MaxFileSize() int64 {
+	return int64(s.cfg.OpenAIBatch.MaxFileSizeMB) << 20
+}
+
+// UploadOpenAIFileInput 上传文件参数
+type UploadOpenAIFileInput struct {
+	UserID   int64
+	Purpose  string
+	Filename string
+	Content  io.Reader
+}
+
+// Upload 保存用户上传的文件；仅接受 purpose=batch
+func (s *OpenAIFileService) Upload(ctx context.Context, in UploadOpenAIFileInput) (*OpenAIFile, error) {
+	if !s.Enabled() {
+		return nil, ErrOpenAIBatchDisabled
+	}
+	if in.Purpose != OpenAIFilePurposeBatch {
+		return nil, infraerrors.Newf(http.StatusBadRequest, ErrOpenAIFileInvalid.Reason, "purpose must be %q", OpenAIFilePurposeBatch)
+	}
+	filename := filepath.Base(strings.TrimSpace(in.Filename))
+	if filename == "" || filename == "." || filename == string(filepath.Separator) {
+		return nil, infraerrors.Newf(http.StatusBadRequest, ErrOpenAIFileInvalid.Reason, "file must have a filename")
+	}
+	if len(filename) > openAIFileMaxFilenameLen {
+		filename = filename[:openAIFileMaxFilenameLen]
+	}
+	if err := s.checkStorage(ctx); err != nil {
+		return nil, err
+	}
+
+	tmp, err := s.createTemp()
+	if err != nil {
+		return nil, err
+	}
+	tmpPath := tmp.Name()
+	maxSize := s.MaxFileSize()
+	written, copyErr := io.Copy(tmp, io.LimitReader(in.Content, maxSize+1))
+	closeErr := tmp.Close()
+	if copyErr == nil {
+		copyErr = closeErr
+	}
+	if copyErr != nil {
+		_ = os.Remove(tmpPath)
+		return nil, fmt.Errorf("write upload: %w", copyErr)
+	}
+	if written > maxSize {
+		_ = os.Remove(tmpPath)
+		return nil, infraerrors.Newf(http.StatusRequestEntityTooLarge, ErrOpenAIFileTooLarge.Reason, "file exceeds the maximum size of %d MB", s.cfg.OpenAIBatch.MaxFileSizeMB)
+	}
+	if written == 0 {
+		_ = os.Remove(tmpPath)
+		return nil, infraerrors.Newf(http.StatusBadRequest, ErrOpenAIFileInvalid.Reason, "file is empty")
+	}
+	return s.store(ctx, in.UserID, OpenAIFilePurposeBatch, filename, tmpPath, written)
+}
+
+// storeLocalFile 将本地临时文件登记为用户文件（批处理结果文件），成功后临时文件被移走或删除
+func (s *OpenAIFileService) storeLocalFile(ctx context.Context, userID int64, purpose, filename, tmpPath string) (*OpenAIFile, error) {
+	info, err := os.Stat(tmpPath)
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	return s.store(ctx, userID, purpose, filename, tmpPath, info.Size())
+}
+
+// store 按配置将临时文件移入本地存储目录或上传到 S3，并写入元数据
+func (s *OpenAIFileService) store(ctx context.Context, userID int64, purpose, filename, tmpPath string, size int64) (*OpenAIFile, error) {
+	id, err := generateOpenAIFileID()
+	if err != nil {
+		_ = os.Remove(tmpPath)
+		return nil, err
+	}
+	now := s.now()
+	relPath := filepath.ToSlash(filepath.Join("files", now.UTC().Format("2006/01/02"), id))
+	file := &OpenAIFile{
+		ID:        id,
+		UserID:    userID,
+		Purpose:   purpose,
+		Filename:  filename,
+		Bytes:     size,
+		Storage:   config.OpenAIFileStorageLocal,
+		ObjectKey: relPath,
+		ExpiresAt: now.AddDate(0, 0, s.cfg.OpenAIBatch.RetentionDays),
+		CreatedAt: now,
+	}
+
+	if s.cfg.OpenAIBatch.Storage == config.OpenAIFileStorageS3 {
+		key, err := s.s3Storage.UploadFile(ctx, openAIFileS3KeyPrefix+relPath, tmpPath, "application/jsonl")
+		_ = os.Remove(tmpPath)
+		if err != nil {
+			return nil, err
+		}
+		file.Storage = config.OpenAIFileStorageS3
+		file.ObjectKey = key
+	} else {
+		dst := s.localPath(relPath)
+		if err := os.MkdirAll(filepath.Dir(dst), 0o755); err != nil {
+			_ = os.Remove(tmpPath)
+			return nil, err
+		}
+		if err := os.Rename(tmpPath, dst); err != nil {
+			_ = os.Remove(tmpPath)
+			return nil, err
+		}
+	}
+
+	if err := s.repo.Create(ctx, file); err != nil {
+		if removeErr := s.removeContent(context.WithoutCancel(ctx), file); removeErr != nil {
+			logger.LegacyPrintf("service.openai_file", "[OpenAIFile] remove orphan content failed: file=%s err=%v", file.ID, removeErr)
+		}
+		return nil, fmt.Errorf("create openai file: %w", err)
+	}
+	return file, nil
+}
+
+func (s *OpenAIFileService) checkStorage(ctx context.Context) error {
+	if s.cfg.OpenAIBatch.Storage == config.OpenAIFileStorageS3 && (s.s3Storage == nil || !s.s3Storage.Enabled(ctx)) {
+		return ErrOpenAIFileStorageDown
+	}
+	return nil
+}
+
+// createTemp 在存储目录下创建临时文件（与最终位置同一文件系统，便于 rename）
+func (s *OpenAIFileService) createTemp() (*os.File, error) {
+	dir := s.localPath("tmp")
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, err
+	}
+	return os.CreateTemp(dir, "upload-*")
+}
+
+func generateOpenAIFileID() (string, error) {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate file id: %w", err)
+	}
+	return openAIFileIDPrefix + hex.EncodeToString(buf), nil
+}
+
+// Get 获取用户的文件
+func (s *OpenAIFileService) Get(ctx context.Context, userID int64, id string) (*OpenAIFile, error) {
+	if !s.Enabled() {
+		return nil, ErrOpenAIBatchDisabled
+	}
+	if !strings.HasPrefix(id, openAIFileIDPrefix) {
+		return nil, ErrOpenAIFileNotFound
+	}
+	file, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if file.UserID != userID {
+		return nil, ErrOpenAIFileNotFound
+	}
+	return file, nil
+}
+
+// List 列出用户的文件
+func (s *OpenAIFileService) List(ctx context.Context, userID int64, params OpenAIFileListParams) ([]OpenAIFile, bool, error) {
+	if !s.Enabled() {
+		return nil, false, ErrOpenAIBatchDisabled
+	}
+	if params.Limit <= 0 {
+		params.Limit = openAIFileDefaultListLimit
+	}
+	if params.Limit > OpenAIFileListMaxLimit {
+		params.Limit = OpenAIFileListMaxLimit
+	}
+	switch params.Order {
+	case "":
+		params.Order = "desc"
+	case "asc", "desc":
+	default:
+		return nil, false, infraerrors.Newf(http.StatusBadRequest, ErrOpenAIFileInvalid.Reason, "order must be asc or desc")
+	}
+	return s.repo.List(ctx, userID, params)
+}
+
+// Open 获取用户文件及其内容，调用方负责关闭返回的 ReadCloser
+func (s *OpenAIFileService) Open(ctx context.Context, userID int64, id string) (*OpenAIFile, io.ReadCloser, error) {
+	file, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return nil, nil, err
+	}
+	rc, err := s.openContent(ctx, file)
+	if err != nil {
+		return nil, nil, err
+	}
+	return file, rc, nil
+}
+
+// Delete 删除用户的文件及其内容
+func (s *OpenAIFileService) Delete(ctx context.Context, userID int64, id string) error {
+	file, err := s.Get(ctx, userID, id)
+	if err != nil {
+		return err
+	}
+	if err := s.removeContent(ctx, file); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, file.ID)
+}
+
+func (s *OpenAIFileService) openContent(ctx context.Context, file *OpenAIFile) (io.ReadCloser, error) {
+	if file.Storage == config.OpenAIFileStorageS3 {
+		if s.s3Storage == nil {
+			return nil, ErrOpenAIFileStorageDown
+		}
+		return s.s3Storage.OpenObject(ctx, file.ObjectKey)
+	}
+	f, err := os.Open(s.localPath(file.ObjectKey))
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return nil, ErrOpenAIFileNotFound
+		}
+		return nil, err
+	}
+	return f, nil
+}
+
+func (s *OpenAIFileService) removeContent(ctx context.Context, file *OpenAIFile) error {
+	if file.Storage == config.OpenAIFileStorageS3 {
+		if s.s3Storage == nil {
+			return ErrOpenAIFileStorageDown
+		}
+		return s.s3Storage.DeleteObjects(ctx, []string{file.ObjectKey})
+	}
+	if err := os.Remove(s.localPath(file.ObjectKey)); err != nil && !os.IsNotExist(err) {
+		return err
+	}
+	return nil
+}
+
+// deleteExpired 删除超过保留期的文件
+func (s *OpenAIFileService) deleteExpired(ctx context.Context) {
+	files, err := s.repo.ListExpired(ctx, s.now(), openAIFileExpireBatchSize)
+	if err != nil {
+		logger.LegacyPrintf("service.openai_file", "[OpenAIFile] list expired files failed: %v", err)
+		return
+	}
+	for i := range files {
+		file := &files[i]
+		if err := s.removeContent(ctx, file); err != nil {
+			logger.LegacyPrintf("service.openai_file", "[OpenAIFile] remove expired file failed: file=%s key=%s err=%v", file.ID, file.ObjectKey, err)
+			continue
+		}
+		if err := s.repo.Delete(ctx, file.ID); err != nil {
+			logger.LegacyPrintf("service.openai_file", "[OpenAIFile] delete expired file failed: file=%s err=%v", file.ID, err)
+		}
+	}
+	if len(files) > 0 {
+		logger.LegacyPrintf("service.openai_file", "[OpenAIFile] deleted %d expired file(s)", len(files))
+	}
+}
+
+func (s *OpenAIFileService) localPath(relPath string) string {
+	return filepath.Join(s.cfg.OpenAIBatch.LocalDir, filepath.FromSlash(relPath))
+}
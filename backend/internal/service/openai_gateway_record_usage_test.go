@@ -54,6 +54,8 @@ func (s *openAIRecordUsageSubRepoStub) IncrementUsage(ctx context.Context, id in
 type openAIRecordUsageAPIKeyQuotaStub struct {
 	quotaCalls     int
 	rateLimitCalls int
+	tokenCalls     int
+	lastTokens     int
 	err            error
 	lastAmount     float64
 }
@@ -70,6 +72,11 @@ func (s *openAIRecordUsageAPIKeyQuotaStub) UpdateRateLimitUsage(ctx context.Cont
 	return s.err
 }
 
+func (s *openAIRecordUsageAPIKeyQuotaStub) RecordTokenUsage(ctx context.Context, apiKey *APIKey, tokens int) {
+	s.tokenCalls++
+	s.lastTokens = tokens
+}
+
 type openAIUserGroupRateRepoStub struct {
 	UserGroupRateRepository
 
@@ -308,6 +315,40 @@ func TestOpenAIGatewayServiceRecordUsage_UpdatesAPIKeyQuotaWhenConfigured(t *tes
 	require.InDelta(t, expected.ActualCost, quotaSvc.lastAmount, 1e-12)
 }
 
+func TestOpenAIGatewayServiceRecordUsage_RecordsTokenUsageForTPMLimitedKey(t *testing.T) {
+	usageRepo := &openAIRecordUsageLogRepoStub{inserted: true}
+	quotaSvc := &openAIRecordUsageAPIKeyQuotaStub{}
+	svc := newOpenAIRecordUsageServiceForTest(usageRepo, &openAIRecordUsageUserRepoStub{}, &openAIRecordUsageSubRepoStub{}, nil)
+
+	input := &OpenAIRecordUsageInput{
+		Result: &OpenAIForwardResult{
+			RequestID: "resp_tpm",
+			Usage:     OpenAIUsage{InputTokens: 10, OutputTokens: 6, CacheReadInputTokens: 2},
+			Model:     "gpt-5.1",
+			Duration:  time.Second,
+		},
+		APIKey:        &APIKey{ID: 1006, TPMLimit: 1000},
+		User:          &User{ID: 2006},
+		Account:       &Account{ID: 3006},
+		APIKeyService: quotaSvc,
+	}
+	require.NoError(t, svc.RecordUsage(context.Background(), input))
+	require.Equal(t, 1, quotaSvc.tokenCalls)
+	// input 已扣除 cache read：8 + 6 + 2
+	require.Equal(t, 16, quotaSvc.lastTokens)
+
+	// 重复的 usage log 不重复累加
+	usageRepo.inserted = false
+	require.NoError(t, svc.RecordUsage(context.Background(), input))
+	require.Equal(t, 1, quotaSvc.tokenCalls)
+
+	// 未配置 TPM 的 Key 不累加
+	usageRepo.inserted = true
+	input.APIKey = &APIKey{ID: 1007}
+	require.NoError(t, svc.RecordUsage(context.Background(), input))
+	require.Equal(t, 1, quotaSvc.tokenCalls)
+}
+
 func TestOpenAIGatewayServiceRecordUsage_ClampsActualInputTokensToZero(t *testing.T) {
 	usageRepo := &openAIRecordUsageLogRepoStub{inserted: true}
 	userRepo := &openAIRecordUsageUserRepoStub{}
@@ -22,6 +22,7 @@ import (
 	"github.com/Wei-Shaw/sub2api/internal/config"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/openai"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/tracing"
 	"github.com/Wei-Shaw/sub2api/internal/util/responseheaders"
 	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
 	"github.com/gin-gonic/gin"
@@ -281,7 +282,8 @@ type OpenAIGatewayService struct {
 	openaiWSStateStore            OpenAIWSStateStore
 	openaiScheduler               OpenAIAccountScheduler
 	openaiWSPassthroughDialer     openAIWSClientDialer
-	openaiAccountStats            *openAIAccountRuntimeStats
+	openaiAccountStats            *accountRuntimeStats
+	healthScorer                  *AccountHealthScorer
 
 	openaiWSFallbackUntil sync.Map // key: int64(accountID), value: time.Time
 	openaiWSRetryMetrics  openAIWSRetryMetrics
@@ -305,6 +307,7 @@ func NewOpenAIGatewayService(
 	httpUpstream HTTPUpstream,
 	deferredService *DeferredService,
 	openAITokenProvider *OpenAITokenProvider,
+	healthScorer *AccountHealthScorer,
 ) *OpenAIGatewayService {
 	svc := &OpenAIGatewayService{
 		accountRepo:         accountRepo,
@@ -332,6 +335,9 @@ func NewOpenAIGatewayService(
 		toolCorrector:        NewCodexToolCorrector(),
 		openaiWSResolver:     NewOpenAIWSProtocolResolver(cfg),
 		responseHeaderFilter: compileResponseHeaderFilter(cfg),
+		// 与其他网关共享运行时统计，429/529 与 token 刷新失败同样计入
+		openaiAccountStats: healthScorer.runtimeStats(),
+		healthScorer:       healthScorer,
 	}
 	svc.logOpenAIWSModeBootstrap()
 	return svc
@@ -707,18 +713,23 @@ func (s *OpenAIGatewayService) detectCodexClientRestriction(c *gin.Context, acco
 }
 
 func getAPIKeyIDFromContext(c *gin.Context) int64 {
+	if apiKey := getAPIKeyFromContext(c); apiKey != nil {
+		return apiKey.ID
+	}
+	return 0
+}
+
+// getAPIKeyFromContext 返回认证中间件写入的 API Key；不存在时返回 nil
+func getAPIKeyFromContext(c *gin.Context) *APIKey {
 	if c == nil {
-		return 0
+		return nil
 	}
 	v, exists := c.Get("api_key")
 	if !exists {
-		return 0
-	}
-	apiKey, ok := v.(*APIKey)
-	if !ok || apiKey == nil {
-		return 0
+		return nil
 	}
-	return apiKey.ID
+	apiKey, _ := v.(*APIKey)
+	return apiKey
 }
 
 func logCodexCLIOnlyDetection(ctx context.Context, c *gin.Context, account *Account, apiKeyID int64, result CodexClientRestrictionDetectionResult, body []byte) {
@@ -1484,6 +1495,13 @@ func (s *OpenAIGatewayService) handleFailoverSideEffects(ctx context.Context, re
 
 // Forward forwards request to OpenAI API
 func (s *OpenAIGatewayService) Forward(ctx context.Context, c *gin.Context, account *Account, body []byte) (*OpenAIForwardResult, error) {
+	ctx, span := startUpstreamAttemptSpan(ctx, c, account)
+	result, err := s.forward(ctx, c, account, body)
+	endUpstreamAttemptSpan(span, c, err)
+	return result, err
+}
+
+func (s *OpenAIGatewayService) forward(ctx context.Context, c *gin.Context, account *Account, body []byte) (*OpenAIForwardResult, error) {
 	startTime := time.Now()
 
 	restrictionResult := s.detectCodexClientRestriction(c, account)
@@ -3589,10 +3607,23 @@ type OpenAIRecordUsageInput struct {
 	UserAgent     string // 请求的 User-Agent
 	IPAddress     string // 请求的客户端 IP 地址
 	APIKeyService APIKeyQuotaUpdater
+
+	PriceMultiplier float64 // 可选：额外计费倍率（如批处理折扣），>0 时叠加到费率倍数
 }
 
 // RecordUsage records usage and deducts balance
 func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRecordUsageInput) error {
+	var account *Account
+	if input != nil {
+		account = input.Account
+	}
+	ctx, span := startRecordUsageSpan(ctx, account)
+	err := s.recordUsage(ctx, input)
+	tracing.End(span, err)
+	return err
+}
+
+func (s *OpenAIGatewayService) recordUsage(ctx context.Context, input *OpenAIRecordUsageInput) error {
 	result := input.Result
 	apiKey := input.APIKey
 	user := input.User
@@ -3623,6 +3654,9 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 		}
 		multiplier = resolver.Resolve(ctx, user.ID, *apiKey.GroupID, apiKey.Group.RateMultiplier)
 	}
+	if input.PriceMultiplier > 0 {
+		multiplier *= input.PriceMultiplier
+	}
 
 	billingModel := result.Model
 	if result.BillingModel != "" {
@@ -3688,6 +3722,9 @@ func (s *OpenAIGatewayService) RecordUsage(ctx context.Context, input *OpenAIRec
 	}
 
 	inserted, err := s.usageLogRepo.Create(ctx, usageLog)
+	if inserted || err != nil {
+		recordAPIKeyTokenUsage(ctx, input.APIKeyService, apiKey, usageLog)
+	}
 	if s.cfg != nil && s.cfg.RunMode == config.RunModeSimple {
 		logger.LegacyPrintf("service.openai_gateway", "[SIMPLE MODE] Usage recorded (not billed): user=%d, tokens=%d", usageLog.UserID, usageLog.TotalTokens())
 		s.deferredService.ScheduleLastUsedUpdate(account.ID)
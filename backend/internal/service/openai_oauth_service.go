@@ -457,7 +457,10 @@ func sanitizeSessionToken(raw string) string {
 }
 
 // RefreshAccountToken refreshes token for an OpenAI/Sora OAuth account
-func (s *OpenAIOAuthService) RefreshAccountToken(ctx context.Context, account *Account) (*OpenAITokenInfo, error) {
+func (s *OpenAIOAuthService) RefreshAccountToken(ctx context.Context, account *Account) (_ *OpenAITokenInfo, err error) {
+	ctx, span := startTokenRefreshSpan(ctx, account)
+	defer func() { endTokenRefreshSpan(span, err) }()
+
 	if account.Platform != PlatformOpenAI && account.Platform != PlatformSora {
 		return nil, infraerrors.New(http.StatusBadRequest, "OPENAI_OAUTH_INVALID_ACCOUNT", "account is not an OpenAI/Sora account")
 	}
@@ -2427,6 +2427,7 @@ func (s *OpenAIGatewayService) ProxyResponsesWebSocketFromClient(
 		return rebuilt, nil
 	}
 
+	apiKey := getAPIKeyFromContext(c)
 	parseClientPayload := func(raw []byte) (openAIWSClientPayload, error) {
 		trimmed := bytes.TrimSpace(raw)
 		if len(trimmed) == 0 {
@@ -2470,6 +2471,16 @@ func (s *OpenAIGatewayService) ProxyResponsesWebSocketFromClient(
 				nil,
 			)
 		}
+		// 握手时认证中间件无法读取模型，每一轮都需要重新执行 Key 模型策略，防止后续轮次切换到被禁止的模型
+		requestedModel := originalModel
+		originalModel = apiKey.ResolveModelAlias(requestedModel)
+		if !apiKey.IsModelAllowed(originalModel) {
+			return openAIWSClientPayload{}, NewOpenAIWSClientCloseError(
+				coderws.StatusPolicyViolation,
+				"model is not allowed for this API key",
+				nil,
+			)
+		}
 		promptCacheKey := strings.TrimSpace(values[2].String())
 		previousResponseID := strings.TrimSpace(values[3].String())
 		previousResponseIDKind := ClassifyOpenAIPreviousResponseIDKind(previousResponseID)
@@ -2491,7 +2502,7 @@ func (s *OpenAIGatewayService) ProxyResponsesWebSocketFromClient(
 		if normalizedModel := normalizeCodexModel(mappedModel); normalizedModel != "" {
 			mappedModel = normalizedModel
 		}
-		if mappedModel != originalModel {
+		if mappedModel != requestedModel {
 			next, setErr := applyPayloadMutation(normalized, "model", mappedModel)
 			if setErr != nil {
 				return openAIWSClientPayload{}, NewOpenAIWSClientCloseError(coderws.StatusPolicyViolation, "invalid websocket request payload", setErr)
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math"
+	"net/http"
 	"strconv"
 	"strings"
 	"sync"
@@ -48,7 +49,11 @@ type OpsAlertEvaluatorService struct {
 	mu         sync.Mutex
 	ruleStates map[int64]*opsAlertRuleState
 
-	emailLimiter *slidingWindowLimiter
+	emailLimiter  *slidingWindowLimiter
+	notifyLimiter *slidingWindowLimiter
+
+	// notifyClient is resolved lazily from cfg; unit tests may preset it.
+	notifyClient *http.Client
 
 	skipLogMu sync.Mutex
 	skipLogAt time.Time
@@ -69,14 +74,15 @@ func NewOpsAlertEvaluatorService(
 	cfg *config.Config,
 ) *OpsAlertEvaluatorService {
 	return &OpsAlertEvaluatorService{
-		opsService:   opsService,
-		opsRepo:      opsRepo,
-		emailService: emailService,
-		redisClient:  redisClient,
-		cfg:          cfg,
-		instanceID:   uuid.NewString(),
-		ruleStates:   map[int64]*opsAlertRuleState{},
-		emailLimiter: newSlidingWindowLimiter(0, time.Hour),
+		opsService:    opsService,
+		opsRepo:       opsRepo,
+		emailService:  emailService,
+		redisClient:   redisClient,
+		cfg:           cfg,
+		instanceID:    uuid.NewString(),
+		ruleStates:    map[int64]*opsAlertRuleState{},
+		emailLimiter:  newSlidingWindowLimiter(0, time.Hour),
+		notifyLimiter: newSlidingWindowLimiter(0, time.Hour),
 	}
 }
 
@@ -196,6 +202,7 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 	eventsCreated := 0
 	eventsResolved := 0
 	emailsSent := 0
+	notificationsSent := 0
 
 	now := time.Now().UTC()
 	safeEnd := now.Truncate(time.Minute)
@@ -292,6 +299,7 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 				if s.maybeSendAlertEmail(ctx, runtimeCfg, rule, created) {
 					emailsSent++
 				}
+				notificationsSent += s.maybeSendAlertNotifications(ctx, runtimeCfg, rule, created)
 			}
 			continue
 		}
@@ -303,11 +311,15 @@ func (s *OpsAlertEvaluatorService) evaluateOnce(interval time.Duration) {
 				logger.LegacyPrintf("service.ops_alert_evaluator", "[OpsAlertEvaluator] resolve event failed (event=%d): %v", activeEvent.ID, err)
 			} else {
 				eventsResolved++
+				resolved := *activeEvent
+				resolved.Status = OpsAlertStatusResolved
+				resolved.ResolvedAt = &resolvedAt
+				notificationsSent += s.maybeSendAlertNotifications(ctx, runtimeCfg, rule, &resolved)
 			}
 		}
 	}
 
-	result := truncateString(fmt.Sprintf("rules=%d enabled=%d evaluated=%d created=%d resolved=%d emails_sent=%d", rulesTotal, rulesEnabled, rulesEvaluated, eventsCreated, eventsResolved, emailsSent), 2048)
+	result := truncateString(fmt.Sprintf("rules=%d enabled=%d evaluated=%d created=%d resolved=%d emails_sent=%d notifications_sent=%d", rulesTotal, rulesEnabled, rulesEvaluated, eventsCreated, eventsResolved, emailsSent, notificationsSent), 2048)
 	s.recordHeartbeatSuccess(runAt, time.Since(startedAt), result)
 }
 
@@ -657,6 +669,70 @@ func (s *OpsAlertEvaluatorService) maybeSendAlertEmail(ctx context.Context, runt
 	return anySent
 }
 
+// maybeSendAlertNotifications delivers a firing or resolved event to the rule's
+// non-email channels. It returns the number of channels that accepted the message.
+func (s *OpsAlertEvaluatorService) maybeSendAlertNotifications(ctx context.Context, runtimeCfg *OpsAlertRuntimeSettings, rule *OpsAlertRule, event *OpsAlertEvent) int {
+	if s == nil || rule == nil || event == nil || len(rule.NotifyChannels) == 0 {
+		return 0
+	}
+	resolved := event.Status != OpsAlertStatusFiring
+
+	now := time.Now().UTC()
+	if runtimeCfg != nil && runtimeCfg.Silencing.Enabled {
+		if isOpsAlertSilenced(now, rule, event, runtimeCfg.Silencing) {
+			return 0
+		}
+	}
+
+	client := s.notifyClient
+	if client == nil {
+		c, err := newOpsAlertNotifyHTTPClient(s.cfg)
+		if err != nil {
+			logger.LegacyPrintf("service.ops_alert_evaluator", "[OpsAlertEvaluator] create notify http client failed: %v", err)
+			return 0
+		}
+		client = c
+	}
+
+	limit := 0
+	if runtimeCfg != nil {
+		limit = runtimeCfg.NotificationRateLimitPerHour
+	}
+	s.notifyLimiter.SetLimit(limit)
+
+	notification := &OpsAlertNotification{Rule: rule, Event: event, SentAt: now}
+	sent := 0
+	for i := range rule.NotifyChannels {
+		channel := &rule.NotifyChannels[i]
+		if !channel.Enabled {
+			continue
+		}
+		if resolved && !channel.NotifyResolved {
+			continue
+		}
+		if !shouldSendOpsAlertEmailByMinSeverity(channel.MinSeverity, strings.TrimSpace(rule.Severity)) {
+			continue
+		}
+		sender, ok := opsAlertChannelSenders[strings.ToLower(strings.TrimSpace(channel.Type))]
+		if !ok {
+			continue
+		}
+		if !s.notifyLimiter.Allow(time.Now().UTC()) {
+			continue
+		}
+		sendCtx, cancel := context.WithTimeout(ctx, opsAlertNotifyTimeout)
+		err := sender.Send(sendCtx, client, channel, notification)
+		cancel()
+		if err != nil {
+			// Best-effort: one broken channel must not block the others.
+			logger.LegacyPrintf("service.ops_alert_evaluator", "[OpsAlertEvaluator] notify %s failed (rule=%d event=%d): %v", channel.Type, rule.ID, event.ID, err)
+			continue
+		}
+		sent++
+	}
+	return sent
+}
+
 func buildOpsAlertEmailBody(rule *OpsAlertRule, event *OpsAlertEvent) string {
 	if rule == nil || event == nil {
 		return ""
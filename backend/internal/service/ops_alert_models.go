@@ -13,6 +13,15 @@ const (
 	OpsAlertStatusManualResolved = "manual_resolved"
 )
 
+// Notification channel types supported by OpsAlertNotifyChannel.Type.
+const (
+	OpsAlertChannelWebhook  = "webhook"
+	OpsAlertChannelSlack    = "slack"
+	OpsAlertChannelTelegram = "telegram"
+	OpsAlertChannelDingTalk = "dingtalk"
+	OpsAlertChannelFeishu   = "feishu"
+)
+
 type OpsAlertRule struct {
 	ID          int64  `json:"id"`
 	Name        string `json:"name"`
@@ -29,7 +38,8 @@ type OpsAlertRule struct {
 	SustainedMinutes int `json:"sustained_minutes"`
 	CooldownMinutes  int `json:"cooldown_minutes"`
 
-	NotifyEmail bool `json:"notify_email"`
+	NotifyEmail    bool                    `json:"notify_email"`
+	NotifyChannels []OpsAlertNotifyChannel `json:"notify_channels"`
 
 	Filters map[string]any `json:"filters,omitempty"`
 
@@ -38,6 +48,29 @@ type OpsAlertRule struct {
 	UpdatedAt       time.Time  `json:"updated_at"`
 }
 
+// OpsAlertNotifyChannel is a non-email delivery target attached to a rule.
+//
+// Which fields are used depends on Type:
+//   - webhook: URL, optional Secret (HMAC-SHA256 signature header)
+//   - slack: URL (incoming webhook)
+//   - telegram: BotToken + ChatID
+//   - dingtalk / feishu: URL (robot webhook), optional Secret (robot signing secret)
+type OpsAlertNotifyChannel struct {
+	Type    string `json:"type"`
+	Name    string `json:"name,omitempty"`
+	Enabled bool   `json:"enabled"`
+
+	// MinSeverity uses the same scale as email alerts: critical / warning / info.
+	// Empty means all severities.
+	MinSeverity    string `json:"min_severity,omitempty"`
+	NotifyResolved bool   `json:"notify_resolved"`
+
+	URL      string `json:"url,omitempty"`
+	Secret   string `json:"secret,omitempty"`
+	BotToken string `json:"bot_token,omitempty"`
+	ChatID   string `json:"chat_id,omitempty"`
+}
+
 type OpsAlertEvent struct {
 	ID       int64  `json:"id"`
 	RuleID   int64  `json:"rule_id"`
@@ -0,0 +1,362 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/httpclient"
+	"github.com/Wei-Shaw/sub2api/internal/util/urlvalidator"
+	"github.com/tidwall/gjson"
+)
+
+// Ops alert notification channels (non-email).
+//
+// Each channel type registers an OpsAlertChannelSender in opsAlertChannelSenders.
+// The evaluator decides *whether* to notify (severity, silencing, rate limit);
+// senders only format and deliver the message.
+
+const (
+	opsAlertNotifyTimeout = 10 * time.Second
+
+	// OpsAlertWebhookSignatureHeader carries "sha256=<hex>" of HMAC-SHA256(secret, timestamp + "." + body).
+	OpsAlertWebhookSignatureHeader = "X-Sub2API-Signature"
+	// OpsAlertWebhookTimestampHeader carries the unix timestamp (seconds) used in the signature.
+	OpsAlertWebhookTimestampHeader = "X-Sub2API-Timestamp"
+	// OpsAlertWebhookEventHeader carries the notification kind (ops_alert.firing / ops_alert.resolved).
+	OpsAlertWebhookEventHeader = "X-Sub2API-Event"
+
+	opsAlertTelegramAPIBase = "https://api.telegram.org"
+	opsAlertResponseMaxSize = 64 << 10
+)
+
+// OpsAlertNotification is the payload handed to channel senders.
+type OpsAlertNotification struct {
+	Rule  *OpsAlertRule
+	Event *OpsAlertEvent
+	// SentAt is also used as the signing timestamp.
+	SentAt time.Time
+}
+
+// Resolved reports whether the notification is for a resolved event.
+func (n *OpsAlertNotification) Resolved() bool {
+	return n != nil && n.Event != nil && n.Event.Status != OpsAlertStatusFiring
+}
+
+// OpsAlertChannelSender delivers one notification to one channel.
+type OpsAlertChannelSender interface {
+	Send(ctx context.Context, client *http.Client, channel *OpsAlertNotifyChannel, n *OpsAlertNotification) error
+}
+
+var opsAlertChannelSenders = map[string]OpsAlertChannelSender{
+	OpsAlertChannelWebhook:  opsAlertWebhookSender{},
+	OpsAlertChannelSlack:    opsAlertSlackSender{},
+	OpsAlertChannelTelegram: opsAlertTelegramSender{apiBase: opsAlertTelegramAPIBase},
+	OpsAlertChannelDingTalk: opsAlertDingTalkSender{},
+	OpsAlertChannelFeishu:   opsAlertFeishuSender{},
+}
+
+func newOpsAlertNotifyHTTPClient(cfg *config.Config) (*http.Client, error) {
+	opts := httpclient.Options{Timeout: opsAlertNotifyTimeout}
+	if cfg != nil {
+		opts.ValidateResolvedIP = cfg.Security.URLAllowlist.Enabled
+		opts.AllowPrivateHosts = cfg.Security.URLAllowlist.AllowPrivateHosts
+	}
+	return httpclient.GetClient(opts)
+}
+
+// =========================
+// Channel config validation
+// =========================
+
+// normalizeOpsAlertNotifyChannels trims and validates rule channels before they are persisted.
+func normalizeOpsAlertNotifyChannels(cfg *config.Config, channels []OpsAlertNotifyChannel) ([]OpsAlertNotifyChannel, error) {
+	out := make([]OpsAlertNotifyChannel, 0, len(channels))
+	for i, ch := range channels {
+		ch.Type = strings.ToLower(strings.TrimSpace(ch.Type))
+		ch.Name = strings.TrimSpace(ch.Name)
+		ch.MinSeverity = strings.ToLower(strings.TrimSpace(ch.MinSeverity))
+		ch.URL = strings.TrimSpace(ch.URL)
+		ch.Secret = strings.TrimSpace(ch.Secret)
+		ch.BotToken = strings.TrimSpace(ch.BotToken)
+		ch.ChatID = strings.TrimSpace(ch.ChatID)
+
+		field := func(name string) string { return fmt.Sprintf("notify_channels[%d].%s", i, name) }
+
+		if _, ok := opsAlertChannelSenders[ch.Type]; !ok {
+			return nil, infraerrors.BadRequest("INVALID_NOTIFY_CHANNEL", field("type")+" must be one of: webhook, slack, telegram, dingtalk, feishu")
+		}
+		switch ch.MinSeverity {
+		case "", "critical", "warning", "info":
+		default:
+			return nil, infraerrors.BadRequest("INVALID_NOTIFY_CHANNEL", field("min_severity")+" must be one of: critical, warning, info")
+		}
+
+		if ch.Type == OpsAlertChannelTelegram {
+			if ch.BotToken == "" || ch.ChatID == "" {
+				return nil, infraerrors.BadRequest("INVALID_NOTIFY_CHANNEL", field("bot_token")+" and chat_id are required for telegram")
+			}
+			ch.URL = ""
+		} else {
+			normalized, err := validateOpsAlertNotifyURL(cfg, ch.URL)
+			if err != nil {
+				return nil, infraerrors.BadRequest("INVALID_NOTIFY_CHANNEL", field("url")+": "+err.Error())
+			}
+			ch.URL = normalized
+			ch.BotToken = ""
+			ch.ChatID = ""
+		}
+		out = append(out, ch)
+	}
+	return out, nil
+}
+
+func validateOpsAlertNotifyURL(cfg *config.Config, raw string) (string, error) {
+	if cfg == nil || !cfg.Security.URLAllowlist.Enabled {
+		allowInsecure := cfg != nil && cfg.Security.URLAllowlist.AllowInsecureHTTP
+		return urlvalidator.ValidateURLFormat(raw, allowInsecure)
+	}
+	return urlvalidator.ValidateHTTPSURL(raw, urlvalidator.ValidationOptions{
+		AllowPrivate: cfg.Security.URLAllowlist.AllowPrivateHosts,
+	})
+}
+
+// =========================
+// Message formatting
+// =========================
+
+func opsAlertNotificationKind(n *OpsAlertNotification) string {
+	if n.Resolved() {
+		return "ops_alert.resolved"
+	}
+	return "ops_alert.firing"
+}
+
+func opsAlertNotificationTitle(n *OpsAlertNotification) string {
+	state := "FIRING"
+	if n.Resolved() {
+		state = "RESOLVED"
+	}
+	return fmt.Sprintf("[Ops Alert][%s][%s] %s", state, strings.TrimSpace(n.Rule.Severity), strings.TrimSpace(n.Rule.Name))
+}
+
+// opsAlertNotificationLines returns the plain-text body lines shared by chat channels.
+func opsAlertNotificationLines(n *OpsAlertNotification) []string {
+	rule, event := n.Rule, n.Event
+	value := "-"
+	if event.MetricValue != nil {
+		value = fmt.Sprintf("%.2f", *event.MetricValue)
+	}
+	threshold := fmt.Sprintf("%.2f", rule.Threshold)
+	if event.ThresholdValue != nil {
+		threshold = fmt.Sprintf("%.2f", *event.ThresholdValue)
+	}
+	lines := []string{
+		fmt.Sprintf("Rule: %s", strings.TrimSpace(rule.Name)),
+		fmt.Sprintf("Severity: %s", strings.TrimSpace(rule.Severity)),
+		fmt.Sprintf("Status: %s", event.Status),
+		fmt.Sprintf("Metric: %s %s %s (current %s)", strings.TrimSpace(rule.MetricType), strings.TrimSpace(rule.Operator), threshold, value),
+		fmt.Sprintf("Fired at: %s", event.FiredAt.UTC().Format(time.RFC3339)),
+	}
+	if event.ResolvedAt != nil {
+		lines = append(lines, fmt.Sprintf("Resolved at: %s", event.ResolvedAt.UTC().Format(time.RFC3339)))
+	}
+	if desc := strings.TrimSpace(event.Description); desc != "" {
+		lines = append(lines, fmt.Sprintf("Description: %s", desc))
+	}
+	return lines
+}
+
+func opsAlertNotificationText(n *OpsAlertNotification) string {
+	return opsAlertNotificationTitle(n) + "\n" + strings.Join(opsAlertNotificationLines(n), "\n")
+}
+
+// =========================
+// Senders
+// =========================
+
+// opsAlertWebhookSender posts a structured JSON document; when a secret is
+// configured the body is signed so receivers can verify the origin.
+type opsAlertWebhookSender struct{}
+
+type opsAlertWebhookPayload struct {
+	Event  string         `json:"event"`
+	SentAt time.Time      `json:"sent_at"`
+	Rule   *OpsAlertRule  `json:"rule"`
+	Alert  *OpsAlertEvent `json:"alert"`
+}
+
+func (opsAlertWebhookSender) Send(ctx context.Context, client *http.Client, channel *OpsAlertNotifyChannel, n *OpsAlertNotification) error {
+	// Never leak channel credentials of the rule to the receiver.
+	rule := *n.Rule
+	rule.NotifyChannels = nil
+
+	body, err := json.Marshal(opsAlertWebhookPayload{
+		Event:  opsAlertNotificationKind(n),
+		SentAt: n.SentAt.UTC(),
+		Rule:   &rule,
+		Alert:  n.Event,
+	})
+	if err != nil {
+		return err
+	}
+	ts := strconv.FormatInt(n.SentAt.Unix(), 10)
+	headers := map[string]string{
+		OpsAlertWebhookEventHeader:     opsAlertNotificationKind(n),
+		OpsAlertWebhookTimestampHeader: ts,
+	}
+	if channel.Secret != "" {
+		headers[OpsAlertWebhookSignatureHeader] = "sha256=" + signOpsAlertWebhook(channel.Secret, ts, body)
+	}
+	_, err = postOpsAlertJSON(ctx, client, channel.URL, headers, body)
+	return err
+}
+
+// signOpsAlertWebhook returns hex(HMAC-SHA256(secret, timestamp + "." + body)).
+func signOpsAlertWebhook(secret, timestamp string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+type opsAlertSlackSender struct{}
+
+func (opsAlertSlackSender) Send(ctx context.Context, client *http.Client, channel *OpsAlertNotifyChannel, n *OpsAlertNotification) error {
+	text := "*" + opsAlertNotificationTitle(n) + "*\n" + strings.Join(opsAlertNotificationLines(n), "\n")
+	body, err := json.Marshal(map[string]any{"text": text})
+	if err != nil {
+		return err
+	}
+	_, err = postOpsAlertJSON(ctx, client, channel.URL, nil, body)
+	return err
+}
+
+type opsAlertTelegramSender struct {
+	apiBase string
+}
+
+func (s opsAlertTelegramSender) Send(ctx context.Context, client *http.Client, channel *OpsAlertNotifyChannel, n *OpsAlertNotification) error {
+	body, err := json.Marshal(map[string]any{
+		"chat_id":                  channel.ChatID,
+		"text":                     opsAlertNotificationText(n),
+		"disable_web_page_preview": true,
+	})
+	if err != nil {
+		return err
+	}
+	endpoint := strings.TrimRight(s.apiBase, "/") + "/bot" + url.PathEscape(channel.BotToken) + "/sendMessage"
+	resp, err := postOpsAlertJSON(ctx, client, endpoint, nil, body)
+	if err != nil {
+		// The endpoint embeds the bot token; don't surface it in logs.
+		return fmt.Errorf("telegram sendMessage failed: %s", strings.ReplaceAll(err.Error(), channel.BotToken, "***"))
+	}
+	if ok := gjson.GetBytes(resp, "ok"); ok.Exists() && !ok.Bool() {
+		return fmt.Errorf("telegram sendMessage failed: %s", gjson.GetBytes(resp, "description").String())
+	}
+	return nil
+}
+
+// opsAlertDingTalkSender posts to a DingTalk custom robot. With a signing
+// secret, timestamp/sign query params are appended as documented by DingTalk.
+type opsAlertDingTalkSender struct{}
+
+func (opsAlertDingTalkSender) Send(ctx context.Context, client *http.Client, channel *OpsAlertNotifyChannel, n *OpsAlertNotification) error {
+	title := opsAlertNotificationTitle(n)
+	body, err := json.Marshal(map[string]any{
+		"msgtype": "markdown",
+		"markdown": map[string]any{
+			"title": title,
+			"text":  "### " + title + "\n\n- " + strings.Join(opsAlertNotificationLines(n), "\n- "),
+		},
+	})
+	if err != nil {
+		return err
+	}
+	endpoint := channel.URL
+	if channel.Secret != "" {
+		ts := strconv.FormatInt(n.SentAt.UnixMilli(), 10)
+		mac := hmac.New(sha256.New, []byte(channel.Secret))
+		_, _ = mac.Write([]byte(ts + "\n" + channel.Secret))
+		sign := base64.StdEncoding.EncodeToString(mac.Sum(nil))
+		sep := "?"
+		if strings.Contains(endpoint, "?") {
+			sep = "&"
+		}
+		endpoint += sep + "timestamp=" + ts + "&sign=" + url.QueryEscape(sign)
+	}
+	resp, err := postOpsAlertJSON(ctx, client, endpoint, nil, body)
+	if err != nil {
+		return err
+	}
+	if code := gjson.GetBytes(resp, "errcode"); code.Exists() && code.Int() != 0 {
+		return fmt.Errorf("dingtalk robot error %d: %s", code.Int(), gjson.GetBytes(resp, "errmsg").String())
+	}
+	return nil
+}
+
+// opsAlertFeishuSender posts to a Feishu/Lark custom bot. With a signing
+// secret, timestamp/sign fields are added to the body as documented by Feishu.
+type opsAlertFeishuSender struct{}
+
+func (opsAlertFeishuSender) Send(ctx context.Context, client *http.Client, channel *OpsAlertNotifyChannel, n *OpsAlertNotification) error {
+	payload := map[string]any{
+		"msg_type": "text",
+		"content":  map[string]any{"text": opsAlertNotificationText(n)},
+	}
+	if channel.Secret != "" {
+		ts := strconv.FormatInt(n.SentAt.Unix(), 10)
+		mac := hmac.New(sha256.New, []byte(ts+"\n"+channel.Secret))
+		payload["timestamp"] = ts
+		payload["sign"] = base64.StdEncoding.EncodeToString(mac.Sum(nil))
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	resp, err := postOpsAlertJSON(ctx, client, channel.URL, nil, body)
+	if err != nil {
+		return err
+	}
+	if code := gjson.GetBytes(resp, "code"); code.Exists() && code.Int() != 0 {
+		return fmt.Errorf("feishu bot error %d: %s", code.Int(), gjson.GetBytes(resp, "msg").String())
+	}
+	return nil
+}
+
+func postOpsAlertJSON(ctx context.Context, client *http.Client, endpoint string, headers map[string]string, body []byte) ([]byte, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sub2api-ops-alert")
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+
+	respBody, _ := io.ReadAll(io.LimitReader(resp.Body, opsAlertResponseMaxSize))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return respBody, fmt.Errorf("unexpected status %d: %s", resp.StatusCode, truncateString(strings.TrimSpace(string(respBody)), 256))
+	}
+	return respBody, nil
+}
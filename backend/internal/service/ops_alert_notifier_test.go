@@ -0,0 +1,235 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+type opsNotifyCapture struct {
+	mu       sync.Mutex
+	requests []*http.Request
+	bodies   [][]byte
+}
+
+func (c *opsNotifyCapture) count() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.requests)
+}
+
+func newOpsNotifyServer(t *testing.T, respBody string) (*httptest.Server, *opsNotifyCapture) {
+	t.Helper()
+	capture := &opsNotifyCapture{}
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		capture.mu.Lock()
+		capture.requests = append(capture.requests, r)
+		capture.bodies = append(capture.bodies, body)
+		capture.mu.Unlock()
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(respBody))
+	}))
+	t.Cleanup(srv.Close)
+	return srv, capture
+}
+
+func newOpsTestNotification(status string) *OpsAlertNotification {
+	value := 42.0
+	firedAt := time.Date(2026, 1, 2, 3, 4, 5, 0, time.UTC)
+	ev := &OpsAlertEvent{
+		ID:          7,
+		RuleID:      3,
+		Severity:    "P0",
+		Status:      status,
+		Description: "error_rate > 10.00",
+		MetricValue: &value,
+		FiredAt:     firedAt,
+	}
+	if status != OpsAlertStatusFiring {
+		resolvedAt := firedAt.Add(5 * time.Minute)
+		ev.ResolvedAt = &resolvedAt
+	}
+	return &OpsAlertNotification{
+		Rule: &OpsAlertRule{
+			ID:         3,
+			Name:       "high error rate",
+			Severity:   "P0",
+			MetricType: "error_rate",
+			Operator:   ">",
+			Threshold:  10,
+			NotifyChannels: []OpsAlertNotifyChannel{
+				{Type: OpsAlertChannelWebhook, URL: "https://example.com", Secret: "do-not-leak"},
+			},
+		},
+		Event:  ev,
+		SentAt: time.Unix(1700000000, 0).UTC(),
+	}
+}
+
+func TestOpsAlertWebhookSender_SignsBody(t *testing.T) {
+	srv, capture := newOpsNotifyServer(t, `{}`)
+	n := newOpsTestNotification(OpsAlertStatusFiring)
+
+	err := opsAlertWebhookSender{}.Send(context.Background(), srv.Client(), &OpsAlertNotifyChannel{URL: srv.URL, Secret: "s3cret"}, n)
+	require.NoError(t, err)
+	require.Equal(t, 1, capture.count())
+
+	req, body := capture.requests[0], capture.bodies[0]
+	require.Equal(t, "ops_alert.firing", req.Header.Get(OpsAlertWebhookEventHeader))
+	require.Equal(t, "1700000000", req.Header.Get(OpsAlertWebhookTimestampHeader))
+
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte("1700000000." + string(body)))
+	require.Equal(t, "sha256="+hex.EncodeToString(mac.Sum(nil)), req.Header.Get(OpsAlertWebhookSignatureHeader))
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(body, &payload))
+	require.Equal(t, "ops_alert.firing", payload["event"])
+	require.NotContains(t, string(body), "do-not-leak")
+}
+
+func TestOpsAlertWebhookSender_ErrorStatus(t *testing.T) {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+	}))
+	defer srv.Close()
+
+	err := opsAlertWebhookSender{}.Send(context.Background(), srv.Client(), &OpsAlertNotifyChannel{URL: srv.URL}, newOpsTestNotification(OpsAlertStatusFiring))
+	require.Error(t, err)
+	require.Contains(t, err.Error(), "502")
+}
+
+func TestOpsAlertDingTalkSender_SignsURL(t *testing.T) {
+	srv, capture := newOpsNotifyServer(t, `{"errcode":0,"errmsg":"ok"}`)
+	n := newOpsTestNotification(OpsAlertStatusResolved)
+
+	err := opsAlertDingTalkSender{}.Send(context.Background(), srv.Client(), &OpsAlertNotifyChannel{URL: srv.URL + "/robot/send?access_token=abc", Secret: "SECabc"}, n)
+	require.NoError(t, err)
+
+	q := capture.requests[0].URL.Query()
+	require.Equal(t, "abc", q.Get("access_token"))
+	require.Equal(t, "1700000000000", q.Get("timestamp"))
+	mac := hmac.New(sha256.New, []byte("SECabc"))
+	mac.Write([]byte("1700000000000\nSECabc"))
+	require.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), q.Get("sign"))
+	require.Contains(t, string(capture.bodies[0]), "RESOLVED")
+}
+
+func TestOpsAlertDingTalkSender_ErrCode(t *testing.T) {
+	srv, _ := newOpsNotifyServer(t, `{"errcode":310000,"errmsg":"sign not match"}`)
+	err := opsAlertDingTalkSender{}.Send(context.Background(), srv.Client(), &OpsAlertNotifyChannel{URL: srv.URL}, newOpsTestNotification(OpsAlertStatusFiring))
+	require.ErrorContains(t, err, "sign not match")
+}
+
+func TestOpsAlertFeishuSender_SignsBody(t *testing.T) {
+	srv, capture := newOpsNotifyServer(t, `{"code":0}`)
+	err := opsAlertFeishuSender{}.Send(context.Background(), srv.Client(), &OpsAlertNotifyChannel{URL: srv.URL, Secret: "fs"}, newOpsTestNotification(OpsAlertStatusFiring))
+	require.NoError(t, err)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(capture.bodies[0], &payload))
+	require.Equal(t, "1700000000", payload["timestamp"])
+	mac := hmac.New(sha256.New, []byte("1700000000\nfs"))
+	require.Equal(t, base64.StdEncoding.EncodeToString(mac.Sum(nil)), payload["sign"])
+	require.Equal(t, "text", payload["msg_type"])
+}
+
+func TestOpsAlertTelegramSender(t *testing.T) {
+	srv, capture := newOpsNotifyServer(t, `{"ok":true}`)
+	sender := opsAlertTelegramSender{apiBase: srv.URL}
+	err := sender.Send(context.Background(), srv.Client(), &OpsAlertNotifyChannel{BotToken: "123:abc", ChatID: "-100"}, newOpsTestNotification(OpsAlertStatusFiring))
+	require.NoError(t, err)
+	require.Equal(t, "/bot123:abc/sendMessage", capture.requests[0].URL.Path)
+
+	var payload map[string]any
+	require.NoError(t, json.Unmarshal(capture.bodies[0], &payload))
+	require.Equal(t, "-100", payload["chat_id"])
+	require.Contains(t, payload["text"], "high error rate")
+
+	failing, _ := newOpsNotifyServer(t, `{"ok":false,"description":"chat not found"}`)
+	err = opsAlertTelegramSender{apiBase: failing.URL}.Send(context.Background(), failing.Client(), &OpsAlertNotifyChannel{BotToken: "123:abc", ChatID: "-100"}, newOpsTestNotification(OpsAlertStatusFiring))
+	require.ErrorContains(t, err, "chat not found")
+}
+
+func TestNormalizeOpsAlertNotifyChannels(t *testing.T) {
+	out, err := normalizeOpsAlertNotifyChannels(nil, []OpsAlertNotifyChannel{
+		{Type: " Slack ", URL: " https://hooks.slack.com/services/x/ ", MinSeverity: "Warning"},
+		{Type: "telegram", BotToken: "t", ChatID: "1", URL: "ignored"},
+	})
+	require.NoError(t, err)
+	require.Equal(t, OpsAlertChannelSlack, out[0].Type)
+	require.Equal(t, "https://hooks.slack.com/services/x", out[0].URL)
+	require.Equal(t, "warning", out[0].MinSeverity)
+	require.Empty(t, out[1].URL)
+
+	_, err = normalizeOpsAlertNotifyChannels(nil, []OpsAlertNotifyChannel{{Type: "pager", URL: "https://x"}})
+	require.Error(t, err)
+	_, err = normalizeOpsAlertNotifyChannels(nil, []OpsAlertNotifyChannel{{Type: "webhook", URL: "http://x"}})
+	require.Error(t, err)
+	_, err = normalizeOpsAlertNotifyChannels(nil, []OpsAlertNotifyChannel{{Type: "telegram", BotToken: "t"}})
+	require.Error(t, err)
+	_, err = normalizeOpsAlertNotifyChannels(nil, []OpsAlertNotifyChannel{{Type: "slack", URL: "https://x", MinSeverity: "P0"}})
+	require.Error(t, err)
+}
+
+func TestMaybeSendAlertNotifications(t *testing.T) {
+	srv, capture := newOpsNotifyServer(t, `{}`)
+	newEvaluator := func() *OpsAlertEvaluatorService {
+		svc := NewOpsAlertEvaluatorService(nil, nil, nil, nil, nil)
+		svc.notifyClient = srv.Client()
+		return svc
+	}
+	rule := &OpsAlertRule{
+		ID:       1,
+		Name:     "r",
+		Severity: "P1", // warning
+		NotifyChannels: []OpsAlertNotifyChannel{
+			{Type: OpsAlertChannelWebhook, Enabled: true, URL: srv.URL, NotifyResolved: true},
+			{Type: OpsAlertChannelSlack, Enabled: true, URL: srv.URL, MinSeverity: "critical"},
+			{Type: OpsAlertChannelSlack, Enabled: false, URL: srv.URL},
+			{Type: OpsAlertChannelSlack, Enabled: true, URL: srv.URL},
+		},
+	}
+	firing := &OpsAlertEvent{ID: 1, RuleID: 1, Severity: "P1", Status: OpsAlertStatusFiring}
+	resolved := &OpsAlertEvent{ID: 1, RuleID: 1, Severity: "P1", Status: OpsAlertStatusResolved}
+
+	t.Run("min severity, enabled and resolved filters", func(t *testing.T) {
+		svc := newEvaluator()
+		before := capture.count()
+		require.Equal(t, 2, svc.maybeSendAlertNotifications(context.Background(), defaultOpsAlertRuntimeSettings(), rule, firing))
+		require.Equal(t, 1, svc.maybeSendAlertNotifications(context.Background(), defaultOpsAlertRuntimeSettings(), rule, resolved))
+		require.Equal(t, before+3, capture.count())
+	})
+
+	t.Run("silenced", func(t *testing.T) {
+		svc := newEvaluator()
+		runtimeCfg := defaultOpsAlertRuntimeSettings()
+		runtimeCfg.Silencing = OpsAlertSilencingSettings{
+			Enabled:            true,
+			GlobalUntilRFC3339: time.Now().Add(time.Hour).UTC().Format(time.RFC3339),
+		}
+		require.Equal(t, 0, svc.maybeSendAlertNotifications(context.Background(), runtimeCfg, rule, firing))
+	})
+
+	t.Run("rate limited", func(t *testing.T) {
+		svc := newEvaluator()
+		runtimeCfg := defaultOpsAlertRuntimeSettings()
+		runtimeCfg.NotificationRateLimitPerHour = 1
+		require.Equal(t, 1, svc.maybeSendAlertNotifications(context.Background(), runtimeCfg, rule, firing))
+		require.Equal(t, 0, svc.maybeSendAlertNotifications(context.Background(), runtimeCfg, rule, firing))
+	})
+}
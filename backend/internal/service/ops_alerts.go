@@ -30,6 +30,11 @@ func (s *OpsService) CreateAlertRule(ctx context.Context, rule *OpsAlertRule) (*
 	if rule == nil {
 		return nil, infraerrors.BadRequest("INVALID_RULE", "invalid rule")
 	}
+	channels, err := normalizeOpsAlertNotifyChannels(s.cfg, rule.NotifyChannels)
+	if err != nil {
+		return nil, err
+	}
+	rule.NotifyChannels = channels
 
 	created, err := s.opsRepo.CreateAlertRule(ctx, rule)
 	if err != nil {
@@ -48,6 +53,11 @@ func (s *OpsService) UpdateAlertRule(ctx context.Context, rule *OpsAlertRule) (*
 	if rule == nil || rule.ID <= 0 {
 		return nil, infraerrors.BadRequest("INVALID_RULE", "invalid rule")
 	}
+	channels, err := normalizeOpsAlertNotifyChannels(s.cfg, rule.NotifyChannels)
+	if err != nil {
+		return nil, err
+	}
+	rule.NotifyChannels = channels
 
 	updated, err := s.opsRepo.UpdateAlertRule(ctx, rule)
 	if err != nil {
@@ -67,6 +67,8 @@ type OpsRepository interface {
 type OpsInsertErrorLogInput struct {
 	RequestID       string
 	ClientRequestID string
+	// TraceID is the W3C trace id of the request span (empty when unavailable).
+	TraceID string
 
 	UserID    *int64
 	APIKeyID  *int64
@@ -0,0 +1,64 @@
+package service
+
+import (
+	"context"
+	"time"
+)
+
+const opsSchedulerAccountHealthDefaultLimit = 50
+
+// OpsOpenAISchedulerMetrics OpenAI 调度器决策指标（运维监控视图）
+type OpsOpenAISchedulerMetrics struct {
+	SelectTotal              int64   `json:"select_total"`
+	StickyPreviousHitTotal   int64   `json:"sticky_previous_hit_total"`
+	StickySessionHitTotal    int64   `json:"sticky_session_hit_total"`
+	LoadBalanceSelectTotal   int64   `json:"load_balance_select_total"`
+	AccountSwitchTotal       int64   `json:"account_switch_total"`
+	SchedulerLatencyMsAvg    float64 `json:"scheduler_latency_ms_avg"`
+	StickyHitRatio           float64 `json:"sticky_hit_ratio"`
+	AccountSwitchRate        float64 `json:"account_switch_rate"`
+	LoadSkewAvg              float64 `json:"load_skew_avg"`
+	RuntimeStatsAccountCount int     `json:"runtime_stats_account_count"`
+}
+
+// OpsSchedulerMetrics 账号调度决策指标与运行时健康快照（进程内统计，多实例部署时为当前实例视图）
+type OpsSchedulerMetrics struct {
+	Gateway   AccountSchedulerMetricsSnapshot `json:"gateway"`
+	OpenAI    OpsOpenAISchedulerMetrics       `json:"openai"`
+	Accounts  []AccountHealthSnapshot         `json:"accounts"`
+	Timestamp time.Time                       `json:"timestamp"`
+}
+
+// GetSchedulerMetrics 返回调度决策指标；accounts 按健康分升序，limit<=0 时取默认值
+func (s *OpsService) GetSchedulerMetrics(ctx context.Context, limit int) (*OpsSchedulerMetrics, error) {
+	if err := s.RequireMonitoringEnabled(ctx); err != nil {
+		return nil, err
+	}
+	if limit <= 0 {
+		limit = opsSchedulerAccountHealthDefaultLimit
+	}
+	out := &OpsSchedulerMetrics{
+		Accounts:  []AccountHealthSnapshot{},
+		Timestamp: time.Now().UTC(),
+	}
+	if s.gatewayService != nil {
+		out.Gateway = s.gatewayService.SnapshotAccountSchedulerMetrics()
+		out.Accounts = s.gatewayService.ListAccountHealth(limit)
+	}
+	if s.openAIGatewayService != nil {
+		m := s.openAIGatewayService.SnapshotOpenAIAccountSchedulerMetrics()
+		out.OpenAI = OpsOpenAISchedulerMetrics{
+			SelectTotal:              m.SelectTotal,
+			StickyPreviousHitTotal:   m.StickyPreviousHitTotal,
+			StickySessionHitTotal:    m.StickySessionHitTotal,
+			LoadBalanceSelectTotal:   m.LoadBalanceSelectTotal,
+			AccountSwitchTotal:       m.AccountSwitchTotal,
+			SchedulerLatencyMsAvg:    m.SchedulerLatencyMsAvg,
+			StickyHitRatio:           m.StickyHitRatio,
+			AccountSwitchRate:        m.AccountSwitchRate,
+			LoadSkewAvg:              m.LoadSkewAvg,
+			RuntimeStatsAccountCount: m.RuntimeStatsAccountCount,
+		}
+	}
+	return out, nil
+}
@@ -333,6 +333,9 @@ func (s *OpsService) UpdateOpsAlertRuntimeSettings(ctx context.Context, cfg *Ops
 			return nil, err
 		}
 	}
+	if cfg.NotificationRateLimitPerHour < 0 {
+		return nil, errors.New("notification_rate_limit_per_hour must be >= 0")
+	}
 
 	defaultCfg := defaultOpsAlertRuntimeSettings()
 	normalizeOpsDistributedLockSettings(&cfg.DistributedLock, opsAlertEvaluatorLeaderLockKeyDefault, defaultCfg.DistributedLock.TTLSeconds)
@@ -397,6 +400,7 @@ func normalizeOpsAdvancedSettings(cfg *OpsAdvancedSettings) {
 	if cfg.AutoRefreshIntervalSec <= 0 {
 		cfg.AutoRefreshIntervalSec = 30
 	}
+	cfg.TraceURLTemplate = strings.TrimSpace(cfg.TraceURLTemplate)
 }
 
 func validateOpsAdvancedSettings(cfg *OpsAdvancedSettings) error {
@@ -415,6 +419,15 @@ func validateOpsAdvancedSettings(cfg *OpsAdvancedSettings) error {
 	if cfg.AutoRefreshIntervalSec < 15 || cfg.AutoRefreshIntervalSec > 300 {
 		return errors.New("auto_refresh_interval_seconds must be between 15 and 300")
 	}
+	if cfg.TraceURLTemplate != "" {
+		lower := strings.ToLower(cfg.TraceURLTemplate)
+		if !strings.HasPrefix(lower, "https://") && !strings.HasPrefix(lower, "http://") {
+			return errors.New("trace_url_template must be an http(s) URL")
+		}
+		if !strings.Contains(cfg.TraceURLTemplate, "{trace_id}") {
+			return errors.New("trace_url_template must contain {trace_id}")
+		}
+	}
 	return nil
 }
 
@@ -88,6 +88,10 @@ type OpsAlertRuntimeSettings struct {
 	DistributedLock OpsDistributedLockSettings `json:"distributed_lock"`
 	Silencing       OpsAlertSilencingSettings  `json:"silencing"`
 	Thresholds      OpsMetricThresholds        `json:"thresholds"` // 指标阈值配置
+
+	// NotificationRateLimitPerHour caps webhook/Slack/Telegram/DingTalk/Feishu
+	// deliveries across all rules (0 = unlimited), like email rate_limit_per_hour.
+	NotificationRateLimitPerHour int `json:"notification_rate_limit_per_hour"`
 }
 
 // OpsAdvancedSettings stores advanced ops configuration (data retention, aggregation).
@@ -100,6 +104,8 @@ type OpsAdvancedSettings struct {
 	IgnoreInvalidApiKeyErrors bool                     `json:"ignore_invalid_api_key_errors"`
 	AutoRefreshEnabled        bool                     `json:"auto_refresh_enabled"`
 	AutoRefreshIntervalSec    int                      `json:"auto_refresh_interval_seconds"`
+	// TraceURLTemplate links error logs to a tracing UI, e.g. "https://jaeger.example.com/trace/{trace_id}".
+	TraceURLTemplate string `json:"trace_url_template"`
 }
 
 type OpsDataRetentionSettings struct {
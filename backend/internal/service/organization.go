@@ -0,0 +1,105 @@
+package service
+
+import (
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+)
+
+// 组织成员角色
+const (
+	OrganizationRoleOwner  = "owner"
+	OrganizationRoleAdmin  = "admin"
+	OrganizationRoleMember = "member"
+)
+
+// Organization 组织（团队），组织 Key 的用量从共享余额扣费
+type Organization struct {
+	ID          int64
+	Name        string
+	OwnerUserID int64
+	Balance     float64
+	Status      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	MemberCount int // 仅列表查询填充
+	Owner       *User
+}
+
+func (o *Organization) IsActive() bool {
+	return o.Status == StatusActive
+}
+
+// OrganizationMember 组织成员
+type OrganizationMember struct {
+	ID                 int64
+	OrganizationID     int64
+	UserID             int64
+	Role               string
+	MonthlyLimitUSD    float64 // 月度消费上限（0 = 不限制）
+	MonthlyUsageUSD    float64
+	MonthlyWindowStart *time.Time
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+
+	User         *User
+	Organization *Organization
+}
+
+// CanManage owner/admin 可以管理成员和组织 Key
+func (m *OrganizationMember) CanManage() bool {
+	return m.Role == OrganizationRoleOwner || m.Role == OrganizationRoleAdmin
+}
+
+// CurrentMonthlyUsage 返回当前自然月的消费（窗口已过期时为 0）
+func (m *OrganizationMember) CurrentMonthlyUsage(now time.Time) float64 {
+	if m.MonthlyWindowStart == nil || m.MonthlyWindowStart.Before(timezone.StartOfMonth(now)) {
+		return 0
+	}
+	return m.MonthlyUsageUSD
+}
+
+// IsOverMonthlyLimit 成员是否已达到月度消费上限
+func (m *OrganizationMember) IsOverMonthlyLimit(now time.Time) bool {
+	return m.MonthlyLimitUSD > 0 && m.CurrentMonthlyUsage(now) >= m.MonthlyLimitUSD
+}
+
+func isValidOrganizationRole(role string) bool {
+	switch role {
+	case OrganizationRoleOwner, OrganizationRoleAdmin, OrganizationRoleMember:
+		return true
+	}
+	return false
+}
+
+// OrganizationMemberUsage 组织内单个成员的用量汇总
+type OrganizationMemberUsage struct {
+	UserID     int64   `json:"user_id"`
+	Email      string  `json:"email"`
+	Requests   int64   `json:"requests"`
+	Tokens     int64   `json:"tokens"`
+	Cost       float64 `json:"cost"`
+	ActualCost float64 `json:"actual_cost"`
+}
+
+// OrganizationDailyUsage 组织按天的用量
+type OrganizationDailyUsage struct {
+	Date       string  `json:"date"`
+	Requests   int64   `json:"requests"`
+	Tokens     int64   `json:"tokens"`
+	Cost       float64 `json:"cost"`
+	ActualCost float64 `json:"actual_cost"`
+}
+
+// OrganizationUsageSummary 组织用量看板（按组织 Key 聚合）
+type OrganizationUsageSummary struct {
+	StartTime       time.Time                 `json:"start_time"`
+	EndTime         time.Time                 `json:"end_time"`
+	TotalRequests   int64                     `json:"total_requests"`
+	TotalTokens     int64                     `json:"total_tokens"`
+	TotalCost       float64                   `json:"total_cost"`
+	TotalActualCost float64                   `json:"total_actual_cost"`
+	Members         []OrganizationMemberUsage `json:"members"`
+	Daily           []OrganizationDailyUsage  `json:"daily"`
+}
@@ -0,0 +1,470 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+var (
+	ErrOrganizationNotFound            = infraerrors.NotFound("ORGANIZATION_NOT_FOUND", "organization not found")
+	ErrOrganizationDisabled            = infraerrors.Forbidden("ORGANIZATION_DISABLED", "organization is disabled")
+	ErrOrganizationForbidden           = infraerrors.Forbidden("ORGANIZATION_FORBIDDEN", "insufficient organization role")
+	ErrOrganizationNameRequired        = infraerrors.BadRequest("ORGANIZATION_NAME_REQUIRED", "organization name is required")
+	ErrOrganizationHasBalance          = infraerrors.Conflict("ORGANIZATION_HAS_BALANCE", "organization still has remaining balance")
+	ErrOrganizationMemberNotFound      = infraerrors.NotFound("ORGANIZATION_MEMBER_NOT_FOUND", "organization member not found")
+	ErrOrganizationMemberExists        = infraerrors.Conflict("ORGANIZATION_MEMBER_EXISTS", "user is already a member of this organization")
+	ErrOrganizationInvalidRole         = infraerrors.BadRequest("ORGANIZATION_INVALID_ROLE", "role must be admin or member")
+	ErrOrganizationOwnerImmutable      = infraerrors.BadRequest("ORGANIZATION_OWNER_IMMUTABLE", "the organization owner cannot be changed or removed")
+	ErrOrganizationInvalidLimit        = infraerrors.BadRequest("ORGANIZATION_INVALID_LIMIT", "monthly limit must be >= 0")
+	ErrOrganizationInvalidBalance      = infraerrors.BadRequest("ORGANIZATION_INVALID_BALANCE", "balance cannot be negative")
+	ErrOrganizationMemberLimitExceeded = infraerrors.TooManyRequests("ORGANIZATION_MEMBER_LIMIT_EXCEEDED", "organization member monthly spending limit exceeded")
+)
+
+// maxOrganizationUsageRange 组织用量看板允许查询的最大时间跨度
+const maxOrganizationUsageRange = 93 * 24 * time.Hour
+
+// OrganizationListFilters 管理端组织列表过滤条件
+type OrganizationListFilters struct {
+	Search string
+	Status string
+}
+
+// OrganizationRepository 组织及成员持久化
+type OrganizationRepository interface {
+	// Create 创建组织，并在同一事务中写入 owner 成员记录
+	Create(ctx context.Context, org *Organization) error
+	GetByID(ctx context.Context, id int64) (*Organization, error)
+	Update(ctx context.Context, org *Organization) error
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context, params pagination.PaginationParams, filters OrganizationListFilters) ([]Organization, *pagination.PaginationResult, error)
+
+	// UpdateBalance 原子增减组织余额（amount 可为负），返回更新后的余额
+	UpdateBalance(ctx context.Context, id int64, amount float64) (float64, error)
+	// DeductBalance 扣减组织余额（计费热路径，允许扣成负数）
+	DeductBalance(ctx context.Context, id int64, amount float64) error
+
+	// ListMembershipsByUserID 返回用户加入的所有组织（成员记录带 Organization）
+	ListMembershipsByUserID(ctx context.Context, userID int64) ([]OrganizationMember, error)
+	GetMember(ctx context.Context, orgID, userID int64) (*OrganizationMember, error)
+	ListMembers(ctx context.Context, orgID int64) ([]OrganizationMember, error)
+	AddMember(ctx context.Context, member *OrganizationMember) error
+	UpdateMember(ctx context.Context, member *OrganizationMember) error
+	RemoveMember(ctx context.Context, orgID, userID int64) error
+	// IncrementMemberUsage 累加成员当月消费，窗口早于 monthStart 时先清零
+	IncrementMemberUsage(ctx context.Context, orgID, userID int64, amount float64, monthStart time.Time) error
+
+	// ListAPIKeys 列出组织 Key（userID > 0 时仅返回该成员创建的 Key）
+	ListAPIKeys(ctx context.Context, orgID, userID int64) ([]APIKey, error)
+	// ListKeyHashesByOrganizationID 返回组织下所有 Key 哈希（用于认证缓存失效）
+	ListKeyHashesByOrganizationID(ctx context.Context, orgID int64) ([]string, error)
+	// GetUsageSummary 按组织 Key 聚合用量（成员维度 + 按天）
+	GetUsageSummary(ctx context.Context, orgID int64, startTime, endTime time.Time) (*OrganizationUsageSummary, error)
+}
+
+// AddOrganizationMemberInput 添加成员参数
+type AddOrganizationMemberInput struct {
+	Email           string
+	Role            string
+	MonthlyLimitUSD float64
+}
+
+// UpdateOrganizationMemberInput 更新成员参数（nil = 不修改）
+type UpdateOrganizationMemberInput struct {
+	Role            *string
+	MonthlyLimitUSD *float64
+}
+
+// OrganizationService 组织管理：成员角色、共享余额与组织 Key
+type OrganizationService struct {
+	orgRepo              OrganizationRepository
+	userRepo             UserRepository
+	billingCacheService  *BillingCacheService
+	authCacheInvalidator APIKeyAuthCacheInvalidator
+}
+
+// NewOrganizationService 创建组织服务
+func NewOrganizationService(
+	orgRepo OrganizationRepository,
+	userRepo UserRepository,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+) *OrganizationService {
+	return &OrganizationService{
+		orgRepo:              orgRepo,
+		userRepo:             userRepo,
+		billingCacheService:  billingCacheService,
+		authCacheInvalidator: authCacheInvalidator,
+	}
+}
+
+// ==================== 用户端 ====================
+
+// Create 创建组织，创建者成为 owner
+func (s *OrganizationService) Create(ctx context.Context, userID int64, name string) (*Organization, error) {
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrOrganizationNameRequired
+	}
+	org := &Organization{
+		Name:        name,
+		OwnerUserID: userID,
+		Status:      StatusActive,
+	}
+	if err := s.orgRepo.Create(ctx, org); err != nil {
+		return nil, fmt.Errorf("create organization: %w", err)
+	}
+	return org, nil
+}
+
+// ListForUser 返回用户加入的组织及其角色
+func (s *OrganizationService) ListForUser(ctx context.Context, userID int64) ([]OrganizationMember, error) {
+	return s.orgRepo.ListMembershipsByUserID(ctx, userID)
+}
+
+// Get 返回组织详情与当前用户的成员记录（非成员返回 NotFound，避免暴露组织存在性）
+func (s *OrganizationService) Get(ctx context.Context, userID, orgID int64) (*Organization, *OrganizationMember, error) {
+	member, err := s.requireMember(ctx, userID, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, nil, err
+	}
+	return org, member, nil
+}
+
+// Rename 修改组织名称（owner/admin）
+func (s *OrganizationService) Rename(ctx context.Context, userID, orgID int64, name string) (*Organization, error) {
+	if _, err := s.requireManager(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return nil, ErrOrganizationNameRequired
+	}
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	org.Name = name
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		return nil, fmt.Errorf("update organization: %w", err)
+	}
+	return org, nil
+}
+
+// Delete 删除组织（仅 owner，且余额须已用完）
+func (s *OrganizationService) Delete(ctx context.Context, userID, orgID int64) error {
+	member, err := s.requireMember(ctx, userID, orgID)
+	if err != nil {
+		return err
+	}
+	if member.Role != OrganizationRoleOwner {
+		return ErrOrganizationForbidden
+	}
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return err
+	}
+	if org.Balance > 0 {
+		return ErrOrganizationHasBalance
+	}
+	// 先取出 Key 列表再删除，删除后组织 Key 因关联组织不存在而被认证拒绝
+	keyHashes, _ := s.orgRepo.ListKeyHashesByOrganizationID(ctx, orgID)
+	if err := s.orgRepo.Delete(ctx, orgID); err != nil {
+		return fmt.Errorf("delete organization: %w", err)
+	}
+	s.invalidateAuthCacheByKeyHashes(ctx, keyHashes)
+	return nil
+}
+
+// ListMembers 列出组织成员（任意成员可见）
+func (s *OrganizationService) ListMembers(ctx context.Context, userID, orgID int64) ([]OrganizationMember, error) {
+	if _, err := s.requireMember(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.ListMembers(ctx, orgID)
+}
+
+// AddMember 按邮箱添加成员。admin 只能添加 member，owner 可以添加 admin。
+func (s *OrganizationService) AddMember(ctx context.Context, userID, orgID int64, input AddOrganizationMemberInput) (*OrganizationMember, error) {
+	actor, err := s.requireManager(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	role := input.Role
+	if role == "" {
+		role = OrganizationRoleMember
+	}
+	if err := checkAssignableRole(actor, role); err != nil {
+		return nil, err
+	}
+	if input.MonthlyLimitUSD < 0 {
+		return nil, ErrOrganizationInvalidLimit
+	}
+
+	user, err := s.userRepo.GetByEmail(ctx, strings.TrimSpace(input.Email))
+	if err != nil {
+		return nil, err
+	}
+	if _, err := s.orgRepo.GetMember(ctx, orgID, user.ID); err == nil {
+		return nil, ErrOrganizationMemberExists
+	}
+
+	member := &OrganizationMember{
+		OrganizationID:  orgID,
+		UserID:          user.ID,
+		Role:            role,
+		MonthlyLimitUSD: input.MonthlyLimitUSD,
+	}
+	if err := s.orgRepo.AddMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("add organization member: %w", err)
+	}
+	member.User = user
+	return member, nil
+}
+
+// UpdateMember 修改成员角色或月度上限
+func (s *OrganizationService) UpdateMember(ctx context.Context, userID, orgID, memberUserID int64, input UpdateOrganizationMemberInput) (*OrganizationMember, error) {
+	actor, err := s.requireManager(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	member, err := s.orgRepo.GetMember(ctx, orgID, memberUserID)
+	if err != nil {
+		return nil, err
+	}
+	// admin 不能修改其他 admin / owner
+	if actor.Role != OrganizationRoleOwner && member.CanManage() && member.UserID != actor.UserID {
+		return nil, ErrOrganizationForbidden
+	}
+
+	if input.Role != nil && *input.Role != member.Role {
+		if member.Role == OrganizationRoleOwner {
+			return nil, ErrOrganizationOwnerImmutable
+		}
+		if err := checkAssignableRole(actor, *input.Role); err != nil {
+			return nil, err
+		}
+		member.Role = *input.Role
+	}
+	if input.MonthlyLimitUSD != nil {
+		if *input.MonthlyLimitUSD < 0 {
+			return nil, ErrOrganizationInvalidLimit
+		}
+		member.MonthlyLimitUSD = *input.MonthlyLimitUSD
+	}
+
+	if err := s.orgRepo.UpdateMember(ctx, member); err != nil {
+		return nil, fmt.Errorf("update organization member: %w", err)
+	}
+	s.invalidateMemberUsage(orgID, memberUserID)
+	return member, nil
+}
+
+// RemoveMember 移除成员（owner/admin 移除他人，或成员自行退出）
+func (s *OrganizationService) RemoveMember(ctx context.Context, userID, orgID, memberUserID int64) error {
+	actor, err := s.requireMember(ctx, userID, orgID)
+	if err != nil {
+		return err
+	}
+	member, err := s.orgRepo.GetMember(ctx, orgID, memberUserID)
+	if err != nil {
+		return err
+	}
+	if member.Role == OrganizationRoleOwner {
+		return ErrOrganizationOwnerImmutable
+	}
+	if member.UserID != actor.UserID {
+		if !actor.CanManage() || (actor.Role != OrganizationRoleOwner && member.CanManage()) {
+			return ErrOrganizationForbidden
+		}
+	}
+
+	if err := s.orgRepo.RemoveMember(ctx, orgID, memberUserID); err != nil {
+		return fmt.Errorf("remove organization member: %w", err)
+	}
+	// 成员记录失效后，计费检查会拒绝其组织 Key
+	s.invalidateMemberUsage(orgID, memberUserID)
+	return nil
+}
+
+// ListAPIKeys 列出组织 Key：owner/admin 可见全部，普通成员仅可见自己的
+func (s *OrganizationService) ListAPIKeys(ctx context.Context, userID, orgID int64) ([]APIKey, error) {
+	member, err := s.requireMember(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	filterUserID := userID
+	if member.CanManage() {
+		filterUserID = 0
+	}
+	return s.orgRepo.ListAPIKeys(ctx, orgID, filterUserID)
+}
+
+// GetUsageSummary 组织用量看板（owner/admin）
+func (s *OrganizationService) GetUsageSummary(ctx context.Context, userID, orgID int64, startTime, endTime time.Time) (*OrganizationUsageSummary, error) {
+	if _, err := s.requireManager(ctx, userID, orgID); err != nil {
+		return nil, err
+	}
+	return s.AdminGetUsageSummary(ctx, orgID, startTime, endTime)
+}
+
+// ==================== 管理端 ====================
+
+// AdminList 管理端分页列出组织
+func (s *OrganizationService) AdminList(ctx context.Context, params pagination.PaginationParams, filters OrganizationListFilters) ([]Organization, *pagination.PaginationResult, error) {
+	return s.orgRepo.List(ctx, params, filters)
+}
+
+// AdminGet 管理端获取组织详情
+func (s *OrganizationService) AdminGet(ctx context.Context, orgID int64) (*Organization, error) {
+	return s.orgRepo.GetByID(ctx, orgID)
+}
+
+// AdminListMembers 管理端列出组织成员
+func (s *OrganizationService) AdminListMembers(ctx context.Context, orgID int64) ([]OrganizationMember, error) {
+	if _, err := s.orgRepo.GetByID(ctx, orgID); err != nil {
+		return nil, err
+	}
+	return s.orgRepo.ListMembers(ctx, orgID)
+}
+
+// AdminUpdateBalance 调整组织共享余额（operation: set / add / subtract）
+func (s *OrganizationService) AdminUpdateBalance(ctx context.Context, orgID int64, amount float64, operation string) (*Organization, error) {
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+
+	var delta float64
+	switch operation {
+	case "set":
+		delta = amount - org.Balance
+	case "add":
+		delta = amount
+	case "subtract":
+		delta = -amount
+	default:
+		return nil, infraerrors.BadRequest("INVALID_OPERATION", "operation must be set, add or subtract")
+	}
+	if org.Balance+delta < 0 {
+		return nil, ErrOrganizationInvalidBalance
+	}
+
+	if delta != 0 {
+		balance, err := s.orgRepo.UpdateBalance(ctx, orgID, delta)
+		if err != nil {
+			return nil, fmt.Errorf("update organization balance: %w", err)
+		}
+		org.Balance = balance
+		s.invalidateOrganization(ctx, orgID)
+	}
+	return org, nil
+}
+
+// AdminUpdateStatus 启用/禁用组织
+func (s *OrganizationService) AdminUpdateStatus(ctx context.Context, orgID int64, status string) (*Organization, error) {
+	if status != StatusActive && status != StatusDisabled {
+		return nil, infraerrors.BadRequest("INVALID_STATUS", "status must be active or disabled")
+	}
+	org, err := s.orgRepo.GetByID(ctx, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if org.Status == status {
+		return org, nil
+	}
+	org.Status = status
+	if err := s.orgRepo.Update(ctx, org); err != nil {
+		return nil, fmt.Errorf("update organization: %w", err)
+	}
+	s.invalidateOrganization(ctx, orgID)
+	return org, nil
+}
+
+// AdminGetUsageSummary 组织用量看板（无成员校验）
+func (s *OrganizationService) AdminGetUsageSummary(ctx context.Context, orgID int64, startTime, endTime time.Time) (*OrganizationUsageSummary, error) {
+	if !endTime.After(startTime) || endTime.Sub(startTime) > maxOrganizationUsageRange {
+		return nil, infraerrors.BadRequest("INVALID_TIME_RANGE", "invalid time range (max 93 days)")
+	}
+	return s.orgRepo.GetUsageSummary(ctx, orgID, startTime, endTime)
+}
+
+// ==================== 内部辅助 ====================
+
+func (s *OrganizationService) requireMember(ctx context.Context, userID, orgID int64) (*OrganizationMember, error) {
+	member, err := s.orgRepo.GetMember(ctx, orgID, userID)
+	if err != nil {
+		if infraerrors.IsNotFound(err) {
+			return nil, ErrOrganizationNotFound
+		}
+		return nil, err
+	}
+	return member, nil
+}
+
+func (s *OrganizationService) requireManager(ctx context.Context, userID, orgID int64) (*OrganizationMember, error) {
+	member, err := s.requireMember(ctx, userID, orgID)
+	if err != nil {
+		return nil, err
+	}
+	if !member.CanManage() {
+		return nil, ErrOrganizationForbidden
+	}
+	return member, nil
+}
+
+// checkAssignableRole owner 只能通过创建产生；admin 只能由 owner 授予
+func checkAssignableRole(actor *OrganizationMember, role string) error {
+	if !isValidOrganizationRole(role) || role == OrganizationRoleOwner {
+		return ErrOrganizationInvalidRole
+	}
+	if role == OrganizationRoleAdmin && actor.Role != OrganizationRoleOwner {
+		return ErrOrganizationForbidden
+	}
+	return nil
+}
+
+// invalidateOrganization 余额/状态变更后失效组织 Key 的认证缓存与余额缓存
+func (s *OrganizationService) invalidateOrganization(ctx context.Context, orgID int64) {
+	if keyHashes, err := s.orgRepo.ListKeyHashesByOrganizationID(ctx, orgID); err == nil {
+		s.invalidateAuthCacheByKeyHashes(ctx, keyHashes)
+	} else {
+		logger.LegacyPrintf("service.organization", "Warning: list keys for organization %d failed: %v", orgID, err)
+	}
+	if s.billingCacheService != nil {
+		go func() {
+			cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			_ = s.billingCacheService.InvalidateOrganizationBalance(cacheCtx, orgID)
+		}()
+	}
+}
+
+func (s *OrganizationService) invalidateAuthCacheByKeyHashes(ctx context.Context, keyHashes []string) {
+	if s.authCacheInvalidator == nil {
+		return
+	}
+	for _, keyHash := range keyHashes {
+		s.authCacheInvalidator.InvalidateAuthCacheByKeyHash(ctx, keyHash)
+	}
+}
+
+func (s *OrganizationService) invalidateMemberUsage(orgID, userID int64) {
+	if s.billingCacheService == nil {
+		return
+	}
+	go func() {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = s.billingCacheService.InvalidateOrganizationMember(cacheCtx, orgID, userID)
+	}()
+}
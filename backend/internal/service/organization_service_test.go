@@ -0,0 +1,251 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// organizationRepoStub 内存实现，仅覆盖测试使用的方法
+type organizationRepoStub struct {
+	OrganizationRepository
+
+	orgs    map[int64]*Organization
+	members map[[2]int64]*OrganizationMember
+
+	deductCalls  int
+	usageCalls   int
+	removedUsers []int64
+}
+
+func newOrganizationRepoStub() *organizationRepoStub {
+	return &organizationRepoStub{
+		orgs:    make(map[int64]*Organization),
+		members: make(map[[2]int64]*OrganizationMember),
+	}
+}
+
+func (r *organizationRepoStub) addMember(orgID, userID int64, role string) *OrganizationMember {
+	m := &OrganizationMember{ID: int64(len(r.members) + 1), OrganizationID: orgID, UserID: userID, Role: role}
+	r.members[[2]int64{orgID, userID}] = m
+	return m
+}
+
+func (r *organizationRepoStub) GetByID(_ context.Context, id int64) (*Organization, error) {
+	org, ok := r.orgs[id]
+	if !ok {
+		return nil, ErrOrganizationNotFound
+	}
+	cp := *org
+	return &cp, nil
+}
+
+func (r *organizationRepoStub) GetMember(_ context.Context, orgID, userID int64) (*OrganizationMember, error) {
+	m, ok := r.members[[2]int64{orgID, userID}]
+	if !ok {
+		return nil, ErrOrganizationMemberNotFound
+	}
+	cp := *m
+	return &cp, nil
+}
+
+func (r *organizationRepoStub) AddMember(_ context.Context, member *OrganizationMember) error {
+	r.members[[2]int64{member.OrganizationID, member.UserID}] = member
+	return nil
+}
+
+func (r *organizationRepoStub) UpdateMember(_ context.Context, member *OrganizationMember) error {
+	r.members[[2]int64{member.OrganizationID, member.UserID}] = member
+	return nil
+}
+
+func (r *organizationRepoStub) RemoveMember(_ context.Context, orgID, userID int64) error {
+	delete(r.members, [2]int64{orgID, userID})
+	r.removedUsers = append(r.removedUsers, userID)
+	return nil
+}
+
+func (r *organizationRepoStub) DeductBalance(_ context.Context, id int64, amount float64) error {
+	r.deductCalls++
+	r.orgs[id].Balance -= amount
+	return nil
+}
+
+func (r *organizationRepoStub) IncrementMemberUsage(_ context.Context, orgID, userID int64, amount float64, monthStart time.Time) error {
+	r.usageCalls++
+	m := r.members[[2]int64{orgID, userID}]
+	if m.MonthlyWindowStart == nil || m.MonthlyWindowStart.Before(monthStart) {
+		m.MonthlyUsageUSD = 0
+		m.MonthlyWindowStart = &monthStart
+	}
+	m.MonthlyUsageUSD += amount
+	return nil
+}
+
+type organizationUserRepoStub struct {
+	UserRepository
+	users map[string]*User
+}
+
+func (r *organizationUserRepoStub) GetByEmail(_ context.Context, email string) (*User, error) {
+	u, ok := r.users[email]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+func newOrganizationServiceForTest() (*OrganizationService, *organizationRepoStub) {
+	repo := newOrganizationRepoStub()
+	repo.orgs[1] = &Organization{ID: 1, Name: "team", OwnerUserID: 10, Status: StatusActive}
+	repo.addMember(1, 10, OrganizationRoleOwner)
+	repo.addMember(1, 20, OrganizationRoleAdmin)
+	repo.addMember(1, 30, OrganizationRoleMember)
+	repo.addMember(1, 31, OrganizationRoleMember)
+	userRepo := &organizationUserRepoStub{users: map[string]*User{
+		"new@example.com": {ID: 40, Email: "new@example.com"},
+	}}
+	return NewOrganizationService(repo, userRepo, nil, nil), repo
+}
+
+func TestOrganizationService_AddMemberRoleRules(t *testing.T) {
+	ctx := context.Background()
+
+	svc, _ := newOrganizationServiceForTest()
+	_, err := svc.AddMember(ctx, 30, 1, AddOrganizationMemberInput{Email: "new@example.com"})
+	require.ErrorIs(t, err, ErrOrganizationForbidden, "member cannot add members")
+
+	_, err = svc.AddMember(ctx, 20, 1, AddOrganizationMemberInput{Email: "new@example.com", Role: OrganizationRoleAdmin})
+	require.ErrorIs(t, err, ErrOrganizationForbidden, "only owner can grant admin")
+
+	_, err = svc.AddMember(ctx, 10, 1, AddOrganizationMemberInput{Email: "new@example.com", Role: OrganizationRoleOwner})
+	require.ErrorIs(t, err, ErrOrganizationInvalidRole)
+
+	_, err = svc.AddMember(ctx, 20, 1, AddOrganizationMemberInput{Email: "new@example.com", MonthlyLimitUSD: -1})
+	require.ErrorIs(t, err, ErrOrganizationInvalidLimit)
+
+	member, err := svc.AddMember(ctx, 20, 1, AddOrganizationMemberInput{Email: "new@example.com", MonthlyLimitUSD: 5})
+	require.NoError(t, err)
+	require.Equal(t, OrganizationRoleMember, member.Role)
+	require.Equal(t, 5.0, member.MonthlyLimitUSD)
+
+	_, err = svc.AddMember(ctx, 10, 1, AddOrganizationMemberInput{Email: "new@example.com"})
+	require.ErrorIs(t, err, ErrOrganizationMemberExists)
+
+	_, err = svc.AddMember(ctx, 99, 1, AddOrganizationMemberInput{Email: "new@example.com"})
+	require.ErrorIs(t, err, ErrOrganizationNotFound, "non-members must not learn the organization exists")
+}
+
+func TestOrganizationService_UpdateMemberRoleRules(t *testing.T) {
+	ctx := context.Background()
+	admin := OrganizationRoleAdmin
+	member := OrganizationRoleMember
+	limit := 10.0
+
+	svc, repo := newOrganizationServiceForTest()
+
+	_, err := svc.UpdateMember(ctx, 20, 1, 10, UpdateOrganizationMemberInput{MonthlyLimitUSD: &limit})
+	require.ErrorIs(t, err, ErrOrganizationForbidden, "admin cannot modify owner")
+
+	_, err = svc.UpdateMember(ctx, 10, 1, 10, UpdateOrganizationMemberInput{Role: &member})
+	require.ErrorIs(t, err, ErrOrganizationOwnerImmutable)
+
+	_, err = svc.UpdateMember(ctx, 20, 1, 30, UpdateOrganizationMemberInput{Role: &admin})
+	require.ErrorIs(t, err, ErrOrganizationForbidden, "admin cannot promote")
+
+	updated, err := svc.UpdateMember(ctx, 20, 1, 30, UpdateOrganizationMemberInput{MonthlyLimitUSD: &limit})
+	require.NoError(t, err)
+	require.Equal(t, limit, updated.MonthlyLimitUSD)
+
+	updated, err = svc.UpdateMember(ctx, 10, 1, 30, UpdateOrganizationMemberInput{Role: &admin})
+	require.NoError(t, err)
+	require.Equal(t, OrganizationRoleAdmin, repo.members[[2]int64{1, 30}].Role)
+	require.Equal(t, OrganizationRoleAdmin, updated.Role)
+
+	_, err = svc.UpdateMember(ctx, 20, 1, 30, UpdateOrganizationMemberInput{MonthlyLimitUSD: &limit})
+	require.ErrorIs(t, err, ErrOrganizationForbidden, "admin cannot modify another admin")
+}
+
+func TestOrganizationService_RemoveMemberRules(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newOrganizationServiceForTest()
+
+	require.ErrorIs(t, svc.RemoveMember(ctx, 20, 1, 10), ErrOrganizationOwnerImmutable)
+	require.ErrorIs(t, svc.RemoveMember(ctx, 10, 1, 10), ErrOrganizationOwnerImmutable, "owner cannot leave")
+	require.ErrorIs(t, svc.RemoveMember(ctx, 30, 1, 31), ErrOrganizationForbidden, "member cannot remove others")
+
+	require.NoError(t, svc.RemoveMember(ctx, 31, 1, 31), "member can leave")
+	require.NoError(t, svc.RemoveMember(ctx, 20, 1, 30))
+	require.NoError(t, svc.RemoveMember(ctx, 10, 1, 20))
+	require.Equal(t, []int64{31, 30, 20}, repo.removedUsers)
+}
+
+func TestOrganizationService_AdminUpdateBalance(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newOrganizationServiceForTest()
+	repo.orgs[1].Balance = 10
+
+	_, err := svc.AdminUpdateBalance(ctx, 1, 20, "subtract")
+	require.ErrorIs(t, err, ErrOrganizationInvalidBalance)
+
+	_, err = svc.AdminUpdateBalance(ctx, 1, 5, "multiply")
+	require.Error(t, err)
+}
+
+func newOrganizationBillingServiceForTest(t *testing.T) (*BillingCacheService, *organizationRepoStub) {
+	t.Helper()
+	repo := newOrganizationRepoStub()
+	repo.orgs[1] = &Organization{ID: 1, OwnerUserID: 10, Balance: 5, Status: StatusActive}
+	repo.addMember(1, 10, OrganizationRoleOwner)
+	repo.addMember(1, 30, OrganizationRoleMember).MonthlyLimitUSD = 2
+
+	svc := NewBillingCacheService(nil, nil, nil, nil, repo, &config.Config{})
+	t.Cleanup(svc.Stop)
+	return svc, repo
+}
+
+func TestBillingCacheService_OrganizationKeyEligibility(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newOrganizationBillingServiceForTest(t)
+
+	orgID := int64(1)
+	user := &User{ID: 30, Balance: 0} // 个人余额为 0 不影响组织 Key
+	apiKey := &APIKey{ID: 1, UserID: 30, OrganizationID: &orgID, Organization: repo.orgs[1]}
+
+	require.NoError(t, svc.CheckBillingEligibility(ctx, user, apiKey, nil, nil))
+
+	// 成员月度消费达到上限
+	require.NoError(t, svc.RecordOrganizationMemberUsage(ctx, orgID, 30, 2))
+	require.Equal(t, 1, repo.usageCalls)
+	require.ErrorIs(t, svc.CheckBillingEligibility(ctx, user, apiKey, nil, nil), ErrOrganizationMemberLimitExceeded)
+
+	// 上月的用量不计入当月
+	lastMonth := time.Now().AddDate(0, -2, 0)
+	repo.members[[2]int64{1, 30}].MonthlyWindowStart = &lastMonth
+	require.NoError(t, svc.CheckBillingEligibility(ctx, user, apiKey, nil, nil))
+
+	// 被移除的成员无法继续使用组织 Key
+	delete(repo.members, [2]int64{1, 30})
+	require.ErrorIs(t, svc.CheckBillingEligibility(ctx, user, apiKey, nil, nil), ErrOrganizationMemberNotFound)
+}
+
+func TestBillingCacheService_OrganizationBalance(t *testing.T) {
+	ctx := context.Background()
+	svc, repo := newOrganizationBillingServiceForTest(t)
+
+	orgID := int64(1)
+	user := &User{ID: 10, Balance: 100}
+	apiKey := &APIKey{ID: 1, UserID: 10, OrganizationID: &orgID, Organization: repo.orgs[1]}
+
+	require.NoError(t, svc.DeductOrganizationBalance(ctx, orgID, 5))
+	require.Equal(t, 1, repo.deductCalls)
+	require.Equal(t, 0.0, repo.orgs[1].Balance)
+
+	// 组织余额耗尽时，即使个人余额充足也拒绝
+	require.ErrorIs(t, svc.CheckBillingEligibility(ctx, user, apiKey, nil, nil), ErrInsufficientBalance)
+}
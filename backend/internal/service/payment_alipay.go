@@ -0,0 +1,255 @@
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const defaultAlipayGateway = "https://openapi.alipay.com/gateway.do"
+
+// alipayLocation 支付宝开放平台要求 timestamp 使用北京时间
+var alipayLocation = time.FixedZone("CST", 8*3600)
+
+// AlipayConfig holds official Alipay (RSA2) configuration.
+type AlipayConfig struct {
+	AppID      string
+	PrivateKey string // 应用私钥
+	PublicKey  string // 支付宝公钥
+	Gateway    string
+	NotifyURL  string
+	ReturnURL  string
+}
+
+// AlipayProvider implements PaymentProvider with alipay.trade.page.pay and alipay.trade.refund.
+type AlipayProvider struct {
+	cfg        AlipayConfig
+	privateKey *rsa.PrivateKey
+	publicKey  *rsa.PublicKey
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewAlipayProvider creates an Alipay provider.
+func NewAlipayProvider(cfg AlipayConfig) (*AlipayProvider, error) {
+	if cfg.AppID == "" || cfg.PrivateKey == "" || cfg.PublicKey == "" || cfg.NotifyURL == "" {
+		return nil, ErrPaymentNotConfigured
+	}
+	if cfg.Gateway == "" {
+		cfg.Gateway = defaultAlipayGateway
+	}
+	privateKey, err := parseRSAPrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, ErrPaymentNotConfigured.WithCause(err)
+	}
+	publicKey, err := parseRSAPublicKey(cfg.PublicKey)
+	if err != nil {
+		return nil, ErrPaymentNotConfigured.WithCause(err)
+	}
+	return &AlipayProvider{
+		cfg:        cfg,
+		privateKey: privateKey,
+		publicKey:  publicKey,
+		httpClient: &http.Client{Timeout: paymentHTTPTimeout},
+		now:        time.Now,
+	}, nil
+}
+
+func (p *AlipayProvider) Name() string { return PaymentProviderAlipay }
+
+func (p *AlipayProvider) Currency() string { return CurrencyCNY }
+
+// CreatePayment builds a signed alipay.trade.page.pay URL (电脑网站支付).
+func (p *AlipayProvider) CreatePayment(_ context.Context, req *PaymentCreateRequest) (*PaymentCreateResult, error) {
+	if req == nil {
+		return nil, ErrOrderNilInput
+	}
+	params, err := p.commonParams("alipay.trade.page.pay", map[string]any{
+		"out_trade_no": req.OrderNo,
+		"total_amount": strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		"subject":      req.Subject,
+		"product_code": "FAST_INSTANT_TRADE_PAY",
+	})
+	if err != nil {
+		return nil, err
+	}
+	params.Set("notify_url", p.cfg.NotifyURL)
+	if p.cfg.ReturnURL != "" {
+		params.Set("return_url", p.cfg.ReturnURL)
+	}
+	if err := p.sign(params); err != nil {
+		return nil, err
+	}
+	return &PaymentCreateResult{URL: p.cfg.Gateway + "?" + params.Encode()}, nil
+}
+
+// ParseNotify verifies an asynchronous notification (form-encoded, RSA2 signed).
+func (p *AlipayProvider) ParseNotify(_ context.Context, req *PaymentNotifyRequest) (*PaymentNotification, error) {
+	form, err := url.ParseQuery(string(req.Body))
+	if err != nil {
+		return nil, ErrPaymentInvalidSignature
+	}
+	if !alipayVerify(alipaySignContent(form, "sign", "sign_type"), form.Get("sign"), p.publicKey) {
+		return nil, ErrPaymentInvalidSignature
+	}
+	if form.Get("app_id") != p.cfg.AppID {
+		return nil, ErrPaymentInvalidSignature
+	}
+	notification := &PaymentNotification{
+		EventID:       form.Get("notify_id"),
+		OrderNo:       form.Get("out_trade_no"),
+		TransactionID: form.Get("trade_no"),
+		Amount:        -1,
+	}
+	if amount, err := strconv.ParseFloat(form.Get("total_amount"), 64); err == nil {
+		notification.Amount = amount
+	}
+	switch form.Get("trade_status") {
+	case "TRADE_SUCCESS", "TRADE_FINISHED":
+		notification.Paid = true
+	}
+	return notification, nil
+}
+
+func (p *AlipayProvider) NotifyAck(err error) PaymentNotifyAck { return textNotifyAck(err) }
+
+// Refund calls alipay.trade.refund and verifies the signed response.
+func (p *AlipayProvider) Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	if req == nil {
+		return nil, ErrOrderNilInput
+	}
+	biz := map[string]any{
+		"out_trade_no":   req.OrderNo,
+		"refund_amount":  strconv.FormatFloat(req.Amount, 'f', 2, 64),
+		"out_request_no": req.RefundNo,
+	}
+	if req.Reason != "" {
+		biz["refund_reason"] = req.Reason
+	}
+	params, err := p.commonParams("alipay.trade.refund", biz)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.sign(params); err != nil {
+		return nil, err
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.Gateway, strings.NewReader(params.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	httpReq.Header.Set("Content-Type", "application/x-www-form-urlencoded;charset=utf-8")
+	resp, err := p.httpClient.Do(httpReq)
+	if err != nil {
+		return nil, ErrPaymentProviderFailed.WithCause(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("alipay response body close error: %v", cerr)
+		}
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return nil, ErrPaymentProviderFailed.WithCause(err)
+	}
+
+	var envelope map[string]json.RawMessage
+	if err := json.Unmarshal(body, &envelope); err != nil {
+		return nil, ErrPaymentProviderFailed.WithCause(fmt.Errorf("decode alipay response: %w", err))
+	}
+	raw := envelope["alipay_trade_refund_response"]
+	var sign string
+	_ = json.Unmarshal(envelope["sign"], &sign)
+	// 验签内容为响应节点的原始 JSON 文本
+	if !alipayVerify(string(raw), sign, p.publicKey) {
+		return nil, ErrPaymentInvalidSignature
+	}
+	var result struct {
+		Code       string `json:"code"`
+		Msg        string `json:"msg"`
+		SubCode    string `json:"sub_code"`
+		SubMsg     string `json:"sub_msg"`
+		TradeNo    string `json:"trade_no"`
+		FundChange string `json:"fund_change"`
+	}
+	if err := json.Unmarshal(raw, &result); err != nil {
+		return nil, ErrPaymentProviderFailed.WithCause(err)
+	}
+	if result.Code != "10000" {
+		return nil, ErrPaymentProviderFailed.WithCause(fmt.Errorf("alipay refund failed: %s %s", result.SubCode, result.SubMsg))
+	}
+	return &PaymentRefundResult{RefundID: req.RefundNo, Status: result.FundChange}, nil
+}
+
+func (p *AlipayProvider) commonParams(method string, biz map[string]any) (url.Values, error) {
+	bizContent, err := json.Marshal(biz)
+	if err != nil {
+		return nil, err
+	}
+	params := url.Values{}
+	params.Set("app_id", p.cfg.AppID)
+	params.Set("method", method)
+	params.Set("format", "JSON")
+	params.Set("charset", "utf-8")
+	params.Set("sign_type", "RSA2")
+	params.Set("timestamp", p.now().In(alipayLocation).Format("2006-01-02 15:04:05"))
+	params.Set("version", "1.0")
+	params.Set("biz_content", string(bizContent))
+	return params, nil
+}
+
+func (p *AlipayProvider) sign(params url.Values) error {
+	sum := sha256.Sum256([]byte(alipaySignContent(params, "sign")))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.privateKey, crypto.SHA256, sum[:])
+	if err != nil {
+		return fmt.Errorf("alipay sign: %w", err)
+	}
+	params.Set("sign", base64.StdEncoding.EncodeToString(sig))
+	return nil
+}
+
+// alipaySignContent 按 key 升序拼接 k=v（跳过空值与排除字段）
+func alipaySignContent(params url.Values, exclude ...string) string {
+	skip := make(map[string]struct{}, len(exclude))
+	for _, key := range exclude {
+		skip[key] = struct{}{}
+	}
+	keys := make([]string, 0, len(params))
+	for key := range params {
+		if _, ok := skip[key]; ok || params.Get(key) == "" {
+			continue
+		}
+		keys = append(keys, key)
+	}
+	sort.Strings(keys)
+	parts := make([]string, 0, len(keys))
+	for _, key := range keys {
+		parts = append(parts, key+"="+params.Get(key))
+	}
+	return strings.Join(parts, "&")
+}
+
+func alipayVerify(content, sign string, publicKey *rsa.PublicKey) bool {
+	if sign == "" || publicKey == nil {
+		return false
+	}
+	sig, err := base64.StdEncoding.DecodeString(sign)
+	if err != nil {
+		return false
+	}
+	sum := sha256.Sum256([]byte(content))
+	return rsa.VerifyPKCS1v15(publicKey, crypto.SHA256, sum[:], sig) == nil
+}
@@ -0,0 +1,259 @@
+package service
+
+import (
+	"context"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math"
+	"net/http"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+var (
+	ErrPaymentRefundUnsupported = infraerrors.BadRequest("PAYMENT_REFUND_UNSUPPORTED", "payment provider does not support refunds")
+	ErrPaymentProviderFailed    = infraerrors.New(http.StatusBadGateway, "PAYMENT_PROVIDER_ERROR", "payment provider request failed")
+)
+
+const paymentHTTPTimeout = 15 * time.Second
+
+// PaymentProvider 是在线支付渠道的统一抽象。
+// 每个实现负责：下单（返回支付链接/二维码）、回调验签与解析、以及退款。
+type PaymentProvider interface {
+	// Name 返回渠道标识（与 SubscriptionOrder.PaymentProvider 一致）
+	Name() string
+	// Currency 返回该渠道的结算币种（ISO 4217 大写）
+	Currency() string
+	// CreatePayment 为订单创建支付，返回支付链接或二维码
+	CreatePayment(ctx context.Context, req *PaymentCreateRequest) (*PaymentCreateResult, error)
+	// ParseNotify 校验回调签名并解析为统一的通知结构。签名无效时返回 ErrPaymentInvalidSignature。
+	ParseNotify(ctx context.Context, req *PaymentNotifyRequest) (*PaymentNotification, error)
+	// NotifyAck 生成回调应答（不同渠道对成功/失败应答格式要求不同）
+	NotifyAck(err error) PaymentNotifyAck
+	// Refund 对已支付订单发起全额退款。不支持退款的渠道返回 ErrPaymentRefundUnsupported。
+	Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error)
+}
+
+// PaymentCreateRequest 下单请求
+type PaymentCreateRequest struct {
+	OrderNo  string
+	Amount   float64
+	Currency string
+	Subject  string
+}
+
+// PaymentCreateResult 下单结果
+type PaymentCreateResult struct {
+	URL         string // 跳转支付链接
+	QRCode      string // 二维码（图片 URL 或待编码的内容，如 weixin://）
+	OpenOrderID string // 渠道侧订单/会话 ID
+	Plugin      string
+}
+
+// PaymentNotifyRequest 原始回调请求
+type PaymentNotifyRequest struct {
+	Header http.Header
+	Body   []byte
+}
+
+// PaymentNotification 验签后的统一回调内容
+type PaymentNotification struct {
+	EventID       string // 渠道回调事件 ID，用于幂等去重
+	OrderNo       string
+	TransactionID string
+	OpenOrderID   string
+	Plugin        string
+	Amount        float64 // 实付金额；小于 0 表示回调未携带金额，跳过金额校验
+	Currency      string  // 实付币种（大写 ISO 代码）；为空表示渠道只支持下单币种，跳过币种校验
+	Paid          bool    // 是否为支付成功事件；其他事件只需应答
+}
+
+// PaymentNotifyAck 回调应答
+type PaymentNotifyAck struct {
+	StatusCode  int
+	ContentType string
+	Body        string
+}
+
+// PaymentRefundRequest 退款请求
+type PaymentRefundRequest struct {
+	OrderNo       string
+	RefundNo      string
+	TransactionID string
+	Amount        float64
+	Currency      string
+	Reason        string
+}
+
+// PaymentRefundResult 退款结果
+type PaymentRefundResult struct {
+	RefundID string
+	Status   string
+}
+
+// newPaymentProviderFromSettings 根据系统设置构建指定渠道的支付实现。
+// 按渠道名称（而非当前选中的渠道）构建，保证切换渠道后旧订单的回调与退款仍可处理。
+func newPaymentProviderFromSettings(name string, settings *SystemSettings, xunhuPayClient *XunhuPayClient) (PaymentProvider, error) {
+	if settings == nil {
+		return nil, ErrPaymentNotConfigured
+	}
+	switch name {
+	case PaymentProviderXunhuPay:
+		if xunhuPayClient == nil {
+			return nil, ErrPaymentNotConfigured
+		}
+		cfg := XunhuPayConfig{
+			AppID:     settings.XunhuPayAppID,
+			AppSecret: settings.XunhuPayAppSecret,
+			Gateway:   settings.XunhuPayGateway,
+			NotifyURL: settings.XunhuPayNotifyURL,
+			ReturnURL: settings.XunhuPayReturnURL,
+			Plugins:   settings.XunhuPayPlugins,
+		}
+		if cfg.AppID == "" || cfg.AppSecret == "" || cfg.Gateway == "" || cfg.NotifyURL == "" {
+			return nil, ErrPaymentNotConfigured
+		}
+		return NewXunhuPayProvider(cfg, xunhuPayClient), nil
+	case PaymentProviderStripe:
+		return NewStripeProvider(StripeConfig{
+			SecretKey:     settings.StripeSecretKey,
+			WebhookSecret: settings.StripeWebhookSecret,
+			Currency:      settings.StripeCurrency,
+			SuccessURL:    settings.StripeSuccessURL,
+			CancelURL:     settings.StripeCancelURL,
+		})
+	case PaymentProviderAlipay:
+		return NewAlipayProvider(AlipayConfig{
+			AppID:      settings.AlipayAppID,
+			PrivateKey: settings.AlipayPrivateKey,
+			PublicKey:  settings.AlipayPublicKey,
+			Gateway:    settings.AlipayGateway,
+			NotifyURL:  settings.AlipayNotifyURL,
+			ReturnURL:  settings.AlipayReturnURL,
+		})
+	case PaymentProviderWeChatPay:
+		return NewWeChatPayProvider(WeChatPayConfig{
+			AppID:             settings.WeChatPayAppID,
+			MchID:             settings.WeChatPayMchID,
+			SerialNo:          settings.WeChatPaySerialNo,
+			PrivateKey:        settings.WeChatPayPrivateKey,
+			APIv3Key:          settings.WeChatPayAPIv3Key,
+			PlatformPublicKey: settings.WeChatPayPlatformPublicKey,
+			NotifyURL:         settings.WeChatPayNotifyURL,
+		})
+	default:
+		return nil, ErrPaymentNotConfigured
+	}
+}
+
+// ValidatePaymentProviderSettings 校验指定渠道的配置能否构建出可用的支付实现（如密钥格式）。
+// 手动渠道与虎皮椒无需额外校验。
+func ValidatePaymentProviderSettings(name string, settings *SystemSettings) error {
+	switch name {
+	case PaymentProviderStripe, PaymentProviderAlipay, PaymentProviderWeChatPay:
+		_, err := newPaymentProviderFromSettings(name, settings, nil)
+		if err != nil {
+			if cause := errors.Unwrap(err); cause != nil {
+				return cause
+			}
+		}
+		return err
+	default:
+		return nil
+	}
+}
+
+// IsOnlinePaymentProvider 判断是否为受支持的在线支付渠道
+func IsOnlinePaymentProvider(name string) bool {
+	switch name {
+	case PaymentProviderXunhuPay, PaymentProviderStripe, PaymentProviderAlipay, PaymentProviderWeChatPay:
+		return true
+	default:
+		return false
+	}
+}
+
+// amountToMinorUnits 将金额转换为最小货币单位（分/cents）
+func amountToMinorUnits(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// minorUnitsToAmount 将最小货币单位转换为金额
+func minorUnitsToAmount(units int64) float64 {
+	return float64(units) / 100
+}
+
+// parseRSAPrivateKey 解析 RSA 私钥，支持 PEM（PKCS#1/PKCS#8）与裸 base64 DER 两种格式
+func parseRSAPrivateKey(raw string) (*rsa.PrivateKey, error) {
+	der, err := decodeKeyMaterial(raw)
+	if err != nil {
+		return nil, err
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKCS8PrivateKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse rsa private key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PrivateKey)
+	if !ok {
+		return nil, errors.New("private key is not RSA")
+	}
+	return key, nil
+}
+
+// parseRSAPublicKey 解析 RSA 公钥，支持 PEM 公钥、X.509 证书与裸 base64 DER
+func parseRSAPublicKey(raw string) (*rsa.PublicKey, error) {
+	der, err := decodeKeyMaterial(raw)
+	if err != nil {
+		return nil, err
+	}
+	if cert, err := x509.ParseCertificate(der); err == nil {
+		if key, ok := cert.PublicKey.(*rsa.PublicKey); ok {
+			return key, nil
+		}
+		return nil, errors.New("certificate public key is not RSA")
+	}
+	if key, err := x509.ParsePKCS1PublicKey(der); err == nil {
+		return key, nil
+	}
+	parsed, err := x509.ParsePKIXPublicKey(der)
+	if err != nil {
+		return nil, fmt.Errorf("parse rsa public key: %w", err)
+	}
+	key, ok := parsed.(*rsa.PublicKey)
+	if !ok {
+		return nil, errors.New("public key is not RSA")
+	}
+	return key, nil
+}
+
+func decodeKeyMaterial(raw string) ([]byte, error) {
+	raw = strings.TrimSpace(raw)
+	if raw == "" {
+		return nil, errors.New("empty key")
+	}
+	if block, _ := pem.Decode([]byte(raw)); block != nil {
+		return block.Bytes, nil
+	}
+	compact := strings.Join(strings.Fields(raw), "")
+	der, err := base64.StdEncoding.DecodeString(compact)
+	if err != nil {
+		return nil, fmt.Errorf("decode key: %w", err)
+	}
+	return der, nil
+}
+
+func textNotifyAck(err error) PaymentNotifyAck {
+	if err != nil {
+		return PaymentNotifyAck{StatusCode: http.StatusBadRequest, ContentType: "text/plain; charset=utf-8", Body: "fail"}
+	}
+	return PaymentNotifyAck{StatusCode: http.StatusOK, ContentType: "text/plain; charset=utf-8", Body: "success"}
+}
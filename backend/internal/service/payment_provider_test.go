@@ -0,0 +1,429 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"encoding/pem"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/require"
+)
+
+func newTestRSAKey(t *testing.T) (*rsa.PrivateKey, string, string) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	privDER, err := x509.MarshalPKCS8PrivateKey(key)
+	require.NoError(t, err)
+	pubDER, err := x509.MarshalPKIXPublicKey(&key.PublicKey)
+	require.NoError(t, err)
+	privPEM := string(pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: privDER}))
+	// 支付宝公钥通常以裸 base64 形式提供
+	return key, privPEM, base64.StdEncoding.EncodeToString(pubDER)
+}
+
+func rsaSHA256Sign(t *testing.T, key *rsa.PrivateKey, content string) string {
+	t.Helper()
+	sum := sha256.Sum256([]byte(content))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, key, crypto.SHA256, sum[:])
+	require.NoError(t, err)
+	return base64.StdEncoding.EncodeToString(sig)
+}
+
+func TestParseRSAKeys_AcceptsPEMAndBareBase64(t *testing.T) {
+	key, privPEM, pubB64 := newTestRSAKey(t)
+
+	priv, err := parseRSAPrivateKey(privPEM)
+	require.NoError(t, err)
+	require.True(t, priv.Equal(key))
+
+	pkcs1 := base64.StdEncoding.EncodeToString(x509.MarshalPKCS1PrivateKey(key))
+	priv, err = parseRSAPrivateKey(pkcs1)
+	require.NoError(t, err)
+	require.True(t, priv.Equal(key))
+
+	pub, err := parseRSAPublicKey(pubB64)
+	require.NoError(t, err)
+	require.True(t, pub.Equal(&key.PublicKey))
+
+	_, err = parseRSAPrivateKey("not-a-key")
+	require.Error(t, err)
+}
+
+func TestValidatePaymentProviderSettings(t *testing.T) {
+	require.NoError(t, ValidatePaymentProviderSettings(PaymentProviderManual, &SystemSettings{}))
+	require.ErrorIs(t, ValidatePaymentProviderSettings(PaymentProviderStripe, &SystemSettings{}), ErrPaymentNotConfigured)
+
+	err := ValidatePaymentProviderSettings(PaymentProviderAlipay, &SystemSettings{
+		AlipayAppID:      "2021000000000000",
+		AlipayPrivateKey: "invalid",
+		AlipayPublicKey:  "invalid",
+		AlipayNotifyURL:  "https://example.com/notify",
+	})
+	require.Error(t, err)
+	require.NotErrorIs(t, err, ErrPaymentNotConfigured)
+}
+
+// --- Stripe ---
+
+func TestStripeProvider_CreateAndRefund(t *testing.T) {
+	var gotSession, gotRefund url.Values
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.Equal(t, "Bearer sk_test", r.Header.Get("Authorization"))
+		require.NotEmpty(t, r.Header.Get("Idempotency-Key"))
+		body, _ := io.ReadAll(r.Body)
+		form, err := url.ParseQuery(string(body))
+		require.NoError(t, err)
+		switch r.URL.Path {
+		case "/v1/checkout/sessions":
+			gotSession = form
+			_, _ = w.Write([]byte(`{"id":"cs_test_1","url":"https://checkout.stripe.test/cs_test_1"}`))
+		case "/v1/refunds":
+			gotRefund = form
+			_, _ = w.Write([]byte(`{"id":"re_1","status":"succeeded"}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+			_, _ = w.Write([]byte(`{"error":{"message":"not found"}}`))
+		}
+	}))
+	defer server.Close()
+
+	provider, err := NewStripeProvider(StripeConfig{
+		SecretKey:     "sk_test",
+		WebhookSecret: "whsec_test",
+		Currency:      "USD",
+		SuccessURL:    "https://example.com/success",
+		APIBase:       server.URL,
+	})
+	require.NoError(t, err)
+	require.Equal(t, "USD", provider.Currency())
+
+	result, err := provider.CreatePayment(context.Background(), &PaymentCreateRequest{OrderNo: "SO1", Amount: 12.34, Subject: "Pro"})
+	require.NoError(t, err)
+	require.Equal(t, "https://checkout.stripe.test/cs_test_1", result.URL)
+	require.Equal(t, "cs_test_1", result.OpenOrderID)
+	require.Equal(t, "1234", gotSession.Get("line_items[0][price_data][unit_amount]"))
+	require.Equal(t, "usd", gotSession.Get("line_items[0][price_data][currency]"))
+	require.Equal(t, "SO1", gotSession.Get("metadata[order_no]"))
+	require.Equal(t, "https://example.com/success", gotSession.Get("cancel_url"))
+
+	refund, err := provider.Refund(context.Background(), &PaymentRefundRequest{OrderNo: "SO1", RefundNo: "SO1-R", TransactionID: "pi_1", Amount: 12.34})
+	require.NoError(t, err)
+	require.Equal(t, "re_1", refund.RefundID)
+	require.Equal(t, "pi_1", gotRefund.Get("payment_intent"))
+	require.Equal(t, "1234", gotRefund.Get("amount"))
+
+	_, err = provider.Refund(context.Background(), &PaymentRefundRequest{OrderNo: "SO1", Amount: 1})
+	require.ErrorIs(t, err, ErrPaymentProviderFailed)
+}
+
+func TestStripeProvider_ParseNotify(t *testing.T) {
+	provider, err := NewStripeProvider(StripeConfig{SecretKey: "sk", WebhookSecret: "whsec_test", SuccessURL: "https://example.com"})
+	require.NoError(t, err)
+	now := time.Unix(1700000000, 0)
+	provider.now = func() time.Time { return now }
+
+	payload := []byte(`{"id":"evt_1","type":"checkout.session.completed","data":{"object":{"id":"cs_1","metadata":{"order_no":"SO1"},"amount_total":999,"currency":"usd","payment_intent":"pi_1","payment_status":"paid"}}}`)
+	ts := strconv.FormatInt(now.Unix(), 10)
+	header := http.Header{}
+	header.Set("Stripe-Signature", "t="+ts+",v1="+stripeSign(ts, payload, "whsec_test"))
+
+	notification, err := provider.ParseNotify(context.Background(), &PaymentNotifyRequest{Header: header, Body: payload})
+	require.NoError(t, err)
+	require.Equal(t, "evt_1", notification.EventID)
+	require.Equal(t, "SO1", notification.OrderNo)
+	require.Equal(t, "pi_1", notification.TransactionID)
+	require.InDelta(t, 9.99, notification.Amount, 1e-9)
+	require.Equal(t, "USD", notification.Currency)
+	require.True(t, notification.Paid)
+
+	// 篡改 payload
+	_, err = provider.ParseNotify(context.Background(), &PaymentNotifyRequest{Header: header, Body: append([]byte(nil), append(payload, ' ')...)})
+	require.ErrorIs(t, err, ErrPaymentInvalidSignature)
+
+	// 支付成功事件缺少币种时拒绝
+	noCurrency := []byte(`{"id":"evt_3","type":"checkout.session.completed","data":{"object":{"id":"cs_3","metadata":{"order_no":"SO3"},"amount_total":999,"payment_status":"paid"}}}`)
+	noCurrencyHeader := http.Header{}
+	noCurrencyHeader.Set("Stripe-Signature", "t="+ts+",v1="+stripeSign(ts, noCurrency, "whsec_test"))
+	_, err = provider.ParseNotify(context.Background(), &PaymentNotifyRequest{Header: noCurrencyHeader, Body: noCurrency})
+	require.ErrorIs(t, err, ErrPaymentCurrencyMismatch)
+
+	// 超出时间容差
+	provider.now = func() time.Time { return now.Add(10 * time.Minute) }
+	_, err = provider.ParseNotify(context.Background(), &PaymentNotifyRequest{Header: header, Body: payload})
+	require.ErrorIs(t, err, ErrPaymentInvalidSignature)
+}
+
+func TestStripeProvider_UnpaidCompletedIsNotPaid(t *testing.T) {
+	provider, err := NewStripeProvider(StripeConfig{SecretKey: "sk", WebhookSecret: "whsec", SuccessURL: "https://example.com"})
+	require.NoError(t, err)
+	payload := []byte(`{"id":"evt_2","type":"checkout.session.completed","data":{"object":{"client_reference_id":"SO2","payment_status":"unpaid"}}}`)
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	header := http.Header{"Stripe-Signature": []string{"t=" + ts + ",v1=" + stripeSign(ts, payload, "whsec")}}
+
+	notification, err := provider.ParseNotify(context.Background(), &PaymentNotifyRequest{Header: header, Body: payload})
+	require.NoError(t, err)
+	require.Equal(t, "SO2", notification.OrderNo)
+	require.False(t, notification.Paid)
+}
+
+// --- Alipay ---
+
+func TestAlipayProvider_CreatePaymentSignsURL(t *testing.T) {
+	appKey, appPrivPEM, _ := newTestRSAKey(t)
+	_, _, alipayPub := newTestRSAKey(t)
+	provider, err := NewAlipayProvider(AlipayConfig{
+		AppID:      "2021000000000000",
+		PrivateKey: appPrivPEM,
+		PublicKey:  alipayPub,
+		NotifyURL:  "https://example.com/api/v1/payment/alipay/notify",
+	})
+	require.NoError(t, err)
+
+	result, err := provider.CreatePayment(context.Background(), &PaymentCreateRequest{OrderNo: "SO1", Amount: 10, Subject: "Pro"})
+	require.NoError(t, err)
+	parsed, err := url.Parse(result.URL)
+	require.NoError(t, err)
+	require.True(t, strings.HasPrefix(result.URL, defaultAlipayGateway+"?"))
+	params := parsed.Query()
+	require.Equal(t, "alipay.trade.page.pay", params.Get("method"))
+	require.Contains(t, params.Get("biz_content"), `"total_amount":"10.00"`)
+	require.True(t, alipayVerify(alipaySignContent(params, "sign"), params.Get("sign"), &appKey.PublicKey))
+}
+
+func TestAlipayProvider_ParseNotify(t *testing.T) {
+	_, appPrivPEM, _ := newTestRSAKey(t)
+	alipayKey, _, alipayPub := newTestRSAKey(t)
+	provider, err := NewAlipayProvider(AlipayConfig{
+		AppID:      "2021000000000000",
+		PrivateKey: appPrivPEM,
+		PublicKey:  alipayPub,
+		NotifyURL:  "https://example.com/notify",
+	})
+	require.NoError(t, err)
+
+	form := url.Values{}
+	form.Set("app_id", "2021000000000000")
+	form.Set("notify_id", "n-1")
+	form.Set("out_trade_no", "SO1")
+	form.Set("trade_no", "2024000001")
+	form.Set("total_amount", "10.00")
+	form.Set("trade_status", "TRADE_SUCCESS")
+	form.Set("sign_type", "RSA2")
+	form.Set("sign", rsaSHA256Sign(t, alipayKey, alipaySignContent(form, "sign", "sign_type")))
+
+	notification, err := provider.ParseNotify(context.Background(), &PaymentNotifyRequest{Body: []byte(form.Encode())})
+	require.NoError(t, err)
+	require.Equal(t, "n-1", notification.EventID)
+	require.Equal(t, "SO1", notification.OrderNo)
+	require.Equal(t, "2024000001", notification.TransactionID)
+	require.InDelta(t, 10.0, notification.Amount, 1e-9)
+	require.True(t, notification.Paid)
+
+	form.Set("total_amount", "0.01")
+	_, err = provider.ParseNotify(context.Background(), &PaymentNotifyRequest{Body: []byte(form.Encode())})
+	require.ErrorIs(t, err, ErrPaymentInvalidSignature)
+}
+
+func TestAlipayProvider_Refund(t *testing.T) {
+	_, appPrivPEM, _ := newTestRSAKey(t)
+	alipayKey, _, alipayPub := newTestRSAKey(t)
+
+	var gotMethod string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		require.NoError(t, r.ParseForm())
+		gotMethod = r.PostForm.Get("method")
+		node := `{"code":"10000","msg":"Success","trade_no":"2024000001","fund_change":"Y"}`
+		sign := rsaSHA256Sign(t, alipayKey, node)
+		_, _ = w.Write([]byte(`{"alipay_trade_refund_response":` + node + `,"sign":"` + sign + `"}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewAlipayProvider(AlipayConfig{
+		AppID:      "2021000000000000",
+		PrivateKey: appPrivPEM,
+		PublicKey:  alipayPub,
+		Gateway:    server.URL,
+		NotifyURL:  "https://example.com/notify",
+	})
+	require.NoError(t, err)
+
+	result, err := provider.Refund(context.Background(), &PaymentRefundRequest{OrderNo: "SO1", RefundNo: "SO1-R", Amount: 10})
+	require.NoError(t, err)
+	require.Equal(t, "alipay.trade.refund", gotMethod)
+	require.Equal(t, "SO1-R", result.RefundID)
+	require.Equal(t, "Y", result.Status)
+}
+
+func TestAlipayProvider_RefundRejectsUnsignedResponse(t *testing.T) {
+	_, appPrivPEM, _ := newTestRSAKey(t)
+	_, _, alipayPub := newTestRSAKey(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"alipay_trade_refund_response":{"code":"10000"},"sign":"Zm9v"}`))
+	}))
+	defer server.Close()
+
+	provider, err := NewAlipayProvider(AlipayConfig{
+		AppID: "app", PrivateKey: appPrivPEM, PublicKey: alipayPub, Gateway: server.URL, NotifyURL: "https://example.com/notify",
+	})
+	require.NoError(t, err)
+	_, err = provider.Refund(context.Background(), &PaymentRefundRequest{OrderNo: "SO1", RefundNo: "SO1-R", Amount: 1})
+	require.ErrorIs(t, err, ErrPaymentInvalidSignature)
+}
+
+// --- WeChat Pay ---
+
+const testWeChatAPIv3Key = "0123456789abcdef0123456789abcdef"
+
+type fakeWeChatPay struct {
+	t           *testing.T
+	platformKey *rsa.PrivateKey
+}
+
+// signedHeaders 模拟微信支付平台对应答/回调的签名
+func (f *fakeWeChatPay) signedHeaders(body []byte) http.Header {
+	ts := strconv.FormatInt(time.Now().Unix(), 10)
+	nonce := "fake-nonce"
+	header := http.Header{}
+	header.Set("Wechatpay-Timestamp", ts)
+	header.Set("Wechatpay-Nonce", nonce)
+	header.Set("Wechatpay-Signature", rsaSHA256Sign(f.t, f.platformKey, ts+"\n"+nonce+"\n"+string(body)+"\n"))
+	return header
+}
+
+func (f *fakeWeChatPay) write(w http.ResponseWriter, body string) {
+	for key, values := range f.signedHeaders([]byte(body)) {
+		w.Header()[key] = values
+	}
+	_, _ = w.Write([]byte(body))
+}
+
+func newTestWeChatPayProvider(t *testing.T, apiBase string) (*WeChatPayProvider, *fakeWeChatPay, *rsa.PrivateKey) {
+	t.Helper()
+	merchantKey, merchantPriv, _ := newTestRSAKey(t)
+	platformKey, _, platformPub := newTestRSAKey(t)
+	provider, err := NewWeChatPayProvider(WeChatPayConfig{
+		AppID:             "wx123",
+		MchID:             "1900000001",
+		SerialNo:          "SERIAL",
+		PrivateKey:        merchantPriv,
+		APIv3Key:          testWeChatAPIv3Key,
+		PlatformPublicKey: platformPub,
+		NotifyURL:         "https://example.com/api/v1/payment/wechatpay/notify",
+		APIBase:           apiBase,
+	})
+	require.NoError(t, err)
+	return provider, &fakeWeChatPay{t: t, platformKey: platformKey}, merchantKey
+}
+
+func TestWeChatPayProvider_CreateAndRefund(t *testing.T) {
+	var fake *fakeWeChatPay
+	var merchantKey *rsa.PrivateKey
+	var gotCreate, gotRefund map[string]any
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		auth := r.Header.Get("Authorization")
+		require.True(t, strings.HasPrefix(auth, "WECHATPAY2-SHA256-RSA2048 "))
+		fields := map[string]string{}
+		for _, part := range strings.Split(strings.TrimPrefix(auth, "WECHATPAY2-SHA256-RSA2048 "), ",") {
+			key, value, _ := strings.Cut(part, "=")
+			fields[key] = strings.Trim(value, `"`)
+		}
+		message := r.Method + "\n" + r.URL.Path + "\n" + fields["timestamp"] + "\n" + fields["nonce_str"] + "\n" + string(body) + "\n"
+		require.True(t, alipayVerify(message, fields["signature"], &merchantKey.PublicKey), "merchant signature must verify")
+
+		switch r.URL.Path {
+		case "/v3/pay/transactions/native":
+			require.NoError(t, json.Unmarshal(body, &gotCreate))
+			fake.write(w, `{"code_url":"weixin://wxpay/bizpayurl?pr=abc"}`)
+		case "/v3/refund/domestic/refunds":
+			require.NoError(t, json.Unmarshal(body, &gotRefund))
+			fake.write(w, `{"refund_id":"50000000","status":"PROCESSING"}`)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	provider, f, mk := newTestWeChatPayProvider(t, server.URL)
+	fake, merchantKey = f, mk
+
+	result, err := provider.CreatePayment(context.Background(), &PaymentCreateRequest{OrderNo: "SO1", Amount: 0.5, Subject: "Pro"})
+	require.NoError(t, err)
+	require.Equal(t, "weixin://wxpay/bizpayurl?pr=abc", result.QRCode)
+	require.Equal(t, "SO1", gotCreate["out_trade_no"])
+	require.EqualValues(t, 50, gotCreate["amount"].(map[string]any)["total"])
+
+	refund, err := provider.Refund(context.Background(), &PaymentRefundRequest{OrderNo: "SO1", RefundNo: "SO1-R", Amount: 0.5})
+	require.NoError(t, err)
+	require.Equal(t, "50000000", refund.RefundID)
+	require.Equal(t, "SO1-R", gotRefund["out_refund_no"])
+}
+
+func TestWeChatPayProvider_RejectsUnsignedResponse(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		_, _ = w.Write([]byte(`{"code_url":"weixin://forged"}`))
+	}))
+	defer server.Close()
+
+	provider, _, _ := newTestWeChatPayProvider(t, server.URL)
+	_, err := provider.CreatePayment(context.Background(), &PaymentCreateRequest{OrderNo: "SO1", Amount: 1, Subject: "Pro"})
+	require.ErrorIs(t, err, ErrPaymentInvalidSignature)
+}
+
+func TestWeChatPayProvider_ParseNotify(t *testing.T) {
+	provider, fake, _ := newTestWeChatPayProvider(t, "")
+
+	plaintext := `{"appid":"wx123","mchid":"1900000001","out_trade_no":"SO1","transaction_id":"4200000001","trade_state":"SUCCESS","amount":{"total":50}}`
+	nonce := "abcdefghijkl"
+	block, err := aes.NewCipher([]byte(testWeChatAPIv3Key))
+	require.NoError(t, err)
+	gcm, err := cipher.NewGCM(block)
+	require.NoError(t, err)
+	ciphertext := base64.StdEncoding.EncodeToString(gcm.Seal(nil, []byte(nonce), []byte(plaintext), []byte("transaction")))
+
+	body, err := json.Marshal(map[string]any{
+		"id":            "evt-1",
+		"event_type":    "TRANSACTION.SUCCESS",
+		"resource_type": "encrypt-resource",
+		"resource": map[string]any{
+			"algorithm":       "AEAD_AES_256_GCM",
+			"ciphertext":      ciphertext,
+			"associated_data": "transaction",
+			"nonce":           nonce,
+		},
+	})
+	require.NoError(t, err)
+
+	notification, err := provider.ParseNotify(context.Background(), &PaymentNotifyRequest{Header: fake.signedHeaders(body), Body: body})
+	require.NoError(t, err)
+	require.Equal(t, "evt-1", notification.EventID)
+	require.Equal(t, "SO1", notification.OrderNo)
+	require.Equal(t, "4200000001", notification.TransactionID)
+	require.InDelta(t, 0.5, notification.Amount, 1e-9)
+	require.True(t, notification.Paid)
+
+	_, err = provider.ParseNotify(context.Background(), &PaymentNotifyRequest{Header: http.Header{}, Body: body})
+	require.ErrorIs(t, err, ErrPaymentInvalidSignature)
+
+	ack := provider.NotifyAck(nil)
+	require.Equal(t, http.StatusOK, ack.StatusCode)
+	require.Contains(t, ack.Body, "SUCCESS")
+}
@@ -0,0 +1,267 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultStripeCurrency = "usd"
+	stripeAPIBase         = "https://api.stripe.com"
+	// stripeSignatureTolerance Stripe-Signature 时间戳允许的最大偏差
+	stripeSignatureTolerance = 5 * time.Minute
+)
+
+// StripeConfig holds Stripe Checkout configuration.
+type StripeConfig struct {
+	SecretKey     string
+	WebhookSecret string
+	Currency      string
+	SuccessURL    string
+	CancelURL     string
+	// APIBase 覆盖 Stripe API 地址（测试用），为空时使用官方地址
+	APIBase string
+}
+
+// StripeProvider implements PaymentProvider with Stripe Checkout Sessions.
+type StripeProvider struct {
+	cfg        StripeConfig
+	httpClient *http.Client
+	now        func() time.Time
+}
+
+// NewStripeProvider creates a Stripe provider.
+func NewStripeProvider(cfg StripeConfig) (*StripeProvider, error) {
+	if cfg.SecretKey == "" || cfg.WebhookSecret == "" || cfg.SuccessURL == "" {
+		return nil, ErrPaymentNotConfigured
+	}
+	cfg.Currency = strings.ToLower(strings.TrimSpace(cfg.Currency))
+	if cfg.Currency == "" {
+		cfg.Currency = defaultStripeCurrency
+	}
+	if cfg.CancelURL == "" {
+		cfg.CancelURL = cfg.SuccessURL
+	}
+	if cfg.APIBase == "" {
+		cfg.APIBase = stripeAPIBase
+	}
+	cfg.APIBase = strings.TrimRight(cfg.APIBase, "/")
+	return &StripeProvider{
+		cfg:        cfg,
+		httpClient: &http.Client{Timeout: paymentHTTPTimeout},
+		now:        time.Now,
+	}, nil
+}
+
+func (p *StripeProvider) Name() string { return PaymentProviderStripe }
+
+func (p *StripeProvider) Currency() string { return strings.ToUpper(p.cfg.Currency) }
+
+// CreatePayment creates a Checkout Session and returns its hosted payment URL.
+func (p *StripeProvider) CreatePayment(ctx context.Context, req *PaymentCreateRequest) (*PaymentCreateResult, error) {
+	if req == nil {
+		return nil, ErrOrderNilInput
+	}
+	form := url.Values{}
+	form.Set("mode", "payment")
+	form.Set("success_url", p.cfg.SuccessURL)
+	form.Set("cancel_url", p.cfg.CancelURL)
+	form.Set("client_reference_id", req.OrderNo)
+	form.Set("metadata[order_no]", req.OrderNo)
+	form.Set("payment_intent_data[metadata][order_no]", req.OrderNo)
+	form.Set("line_items[0][quantity]", "1")
+	form.Set("line_items[0][price_data][currency]", p.cfg.Currency)
+	form.Set("line_items[0][price_data][unit_amount]", strconv.FormatInt(amountToMinorUnits(req.Amount), 10))
+	form.Set("line_items[0][price_data][product_data][name]", req.Subject)
+
+	var session struct {
+		ID  string `json:"id"`
+		URL string `json:"url"`
+	}
+	if err := p.post(ctx, "/v1/checkout/sessions", form, req.OrderNo, &session); err != nil {
+		return nil, err
+	}
+	if session.URL == "" {
+		return nil, ErrPaymentProviderFailed.WithCause(fmt.Errorf("stripe checkout session missing url"))
+	}
+	return &PaymentCreateResult{URL: session.URL, OpenOrderID: session.ID}, nil
+}
+
+// stripeEvent is the subset of a Stripe webhook event we consume.
+type stripeEvent struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+	Data struct {
+		Object struct {
+			ID                string            `json:"id"`
+			ClientReferenceID string            `json:"client_reference_id"`
+			Metadata          map[string]string `json:"metadata"`
+			AmountTotal       int64             `json:"amount_total"`
+			Currency          string            `json:"currency"`
+			PaymentIntent     string            `json:"payment_intent"`
+			PaymentStatus     string            `json:"payment_status"`
+		} `json:"object"`
+	} `json:"data"`
+}
+
+// ParseNotify verifies the Stripe-Signature header and parses Checkout events.
+func (p *StripeProvider) ParseNotify(_ context.Context, req *PaymentNotifyRequest) (*PaymentNotification, error) {
+	if !verifyStripeSignature(req.Header.Get("Stripe-Signature"), req.Body, p.cfg.WebhookSecret, p.now(), stripeSignatureTolerance) {
+		return nil, ErrPaymentInvalidSignature
+	}
+	var event stripeEvent
+	if err := json.Unmarshal(req.Body, &event); err != nil {
+		return nil, ErrPaymentInvalidSignature.WithCause(err)
+	}
+	obj := event.Data.Object
+	orderNo := obj.Metadata["order_no"]
+	if orderNo == "" {
+		orderNo = obj.ClientReferenceID
+	}
+	notification := &PaymentNotification{
+		EventID:       event.ID,
+		OrderNo:       orderNo,
+		TransactionID: obj.PaymentIntent,
+		OpenOrderID:   obj.ID,
+		Amount:        minorUnitsToAmount(obj.AmountTotal),
+		Currency:      strings.ToUpper(obj.Currency),
+	}
+	switch event.Type {
+	case "checkout.session.completed", "checkout.session.async_payment_succeeded":
+		// 异步支付方式（如银行转账）在 completed 时仍为 unpaid，需等待 async_payment_succeeded
+		notification.Paid = obj.PaymentStatus == "paid"
+	}
+	// Stripe 支持多币种，金额必须连同币种一起校验；缺少币种的支付成功事件直接拒绝
+	if notification.Paid && notification.Currency == "" {
+		return nil, ErrPaymentCurrencyMismatch
+	}
+	return notification, nil
+}
+
+// NotifyAck Stripe 只关心 2xx 状态码；签名错误时返回 400 让其重试/告警。
+func (p *StripeProvider) NotifyAck(err error) PaymentNotifyAck {
+	if err != nil {
+		return PaymentNotifyAck{StatusCode: http.StatusBadRequest, ContentType: "application/json", Body: `{"received":false}`}
+	}
+	return PaymentNotifyAck{StatusCode: http.StatusOK, ContentType: "application/json", Body: `{"received":true}`}
+}
+
+// Refund refunds the PaymentIntent of a paid Checkout Session.
+func (p *StripeProvider) Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	if req == nil {
+		return nil, ErrOrderNilInput
+	}
+	if req.TransactionID == "" {
+		return nil, ErrPaymentProviderFailed.WithCause(fmt.Errorf("stripe refund requires payment_intent"))
+	}
+	form := url.Values{}
+	form.Set("payment_intent", req.TransactionID)
+	form.Set("amount", strconv.FormatInt(amountToMinorUnits(req.Amount), 10))
+	form.Set("metadata[order_no]", req.OrderNo)
+	form.Set("reason", "requested_by_customer")
+
+	var refund struct {
+		ID     string `json:"id"`
+		Status string `json:"status"`
+	}
+	if err := p.post(ctx, "/v1/refunds", form, req.RefundNo, &refund); err != nil {
+		return nil, err
+	}
+	if refund.Status == "failed" || refund.Status == "canceled" {
+		return nil, ErrPaymentProviderFailed.WithCause(fmt.Errorf("stripe refund %s status %s", refund.ID, refund.Status))
+	}
+	return &PaymentRefundResult{RefundID: refund.ID, Status: refund.Status}, nil
+}
+
+func (p *StripeProvider) post(ctx context.Context, path string, form url.Values, idempotencyKey string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.cfg.APIBase+path, strings.NewReader(form.Encode()))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+p.cfg.SecretKey)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	if idempotencyKey != "" {
+		req.Header.Set("Idempotency-Key", idempotencyKey)
+	}
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return ErrPaymentProviderFailed.WithCause(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("stripe response body close error: %v", cerr)
+		}
+	}()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return ErrPaymentProviderFailed.WithCause(err)
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Error struct {
+				Message string `json:"message"`
+			} `json:"error"`
+		}
+		_ = json.Unmarshal(body, &apiErr)
+		return ErrPaymentProviderFailed.WithCause(fmt.Errorf("stripe %s: status %d: %s", path, resp.StatusCode, apiErr.Error.Message))
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return ErrPaymentProviderFailed.WithCause(fmt.Errorf("decode stripe response: %w", err))
+	}
+	return nil
+}
+
+// verifyStripeSignature 校验 Stripe-Signature 头（t=时间戳,v1=HMAC-SHA256(t.payload)）
+func verifyStripeSignature(header string, payload []byte, secret string, now time.Time, tolerance time.Duration) bool {
+	if header == "" || secret == "" {
+		return false
+	}
+	var timestamp string
+	var signatures []string
+	for _, part := range strings.Split(header, ",") {
+		key, value, ok := strings.Cut(strings.TrimSpace(part), "=")
+		if !ok {
+			continue
+		}
+		switch key {
+		case "t":
+			timestamp = value
+		case "v1":
+			signatures = append(signatures, value)
+		}
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil || len(signatures) == 0 {
+		return false
+	}
+	if diff := now.Sub(time.Unix(ts, 0)); diff > tolerance || diff < -tolerance {
+		return false
+	}
+	expected := stripeSign(timestamp, payload, secret)
+	for _, sig := range signatures {
+		if hmac.Equal([]byte(sig), []byte(expected)) {
+			return true
+		}
+	}
+	return false
+}
+
+func stripeSign(timestamp string, payload []byte, secret string) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	_, _ = mac.Write([]byte(timestamp))
+	_, _ = mac.Write([]byte("."))
+	_, _ = mac.Write(payload)
+	return hex.EncodeToString(mac.Sum(nil))
+}
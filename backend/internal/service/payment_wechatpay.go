@@ -0,0 +1,306 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto"
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	wechatPayAPIBase = "https://api.mch.weixin.qq.com"
+	// wechatPaySignatureTolerance 应答/回调时间戳允许的最大偏差
+	wechatPaySignatureTolerance = 5 * time.Minute
+)
+
+// WeChatPayConfig holds WeChat Pay APIv3 configuration.
+type WeChatPayConfig struct {
+	AppID             string
+	MchID             string
+	SerialNo          string // 商户 API 证书序列号
+	PrivateKey        string // 商户 API 私钥
+	APIv3Key          string // 32 字节 APIv3 密钥
+	PlatformPublicKey string // 微信支付平台公钥或平台证书
+	NotifyURL         string
+	// APIBase 覆盖微信支付 API 地址（测试用），为空时使用官方地址
+	APIBase string
+}
+
+// WeChatPayProvider implements PaymentProvider with WeChat Pay APIv3 Native payments.
+type WeChatPayProvider struct {
+	cfg            WeChatPayConfig
+	privateKey     *rsa.PrivateKey
+	platformPubKey *rsa.PublicKey
+	httpClient     *http.Client
+	now            func() time.Time
+}
+
+// NewWeChatPayProvider creates a WeChat Pay provider.
+func NewWeChatPayProvider(cfg WeChatPayConfig) (*WeChatPayProvider, error) {
+	if cfg.AppID == "" || cfg.MchID == "" || cfg.SerialNo == "" || cfg.PrivateKey == "" ||
+		cfg.PlatformPublicKey == "" || cfg.NotifyURL == "" || len(cfg.APIv3Key) != 32 {
+		return nil, ErrPaymentNotConfigured
+	}
+	privateKey, err := parseRSAPrivateKey(cfg.PrivateKey)
+	if err != nil {
+		return nil, ErrPaymentNotConfigured.WithCause(err)
+	}
+	platformPubKey, err := parseRSAPublicKey(cfg.PlatformPublicKey)
+	if err != nil {
+		return nil, ErrPaymentNotConfigured.WithCause(err)
+	}
+	if cfg.APIBase == "" {
+		cfg.APIBase = wechatPayAPIBase
+	}
+	cfg.APIBase = strings.TrimRight(cfg.APIBase, "/")
+	return &WeChatPayProvider{
+		cfg:            cfg,
+		privateKey:     privateKey,
+		platformPubKey: platformPubKey,
+		httpClient:     &http.Client{Timeout: paymentHTTPTimeout},
+		now:            time.Now,
+	}, nil
+}
+
+func (p *WeChatPayProvider) Name() string { return PaymentProviderWeChatPay }
+
+func (p *WeChatPayProvider) Currency() string { return CurrencyCNY }
+
+// CreatePayment creates a Native payment and returns its code_url (to be rendered as a QR code).
+func (p *WeChatPayProvider) CreatePayment(ctx context.Context, req *PaymentCreateRequest) (*PaymentCreateResult, error) {
+	if req == nil {
+		return nil, ErrOrderNilInput
+	}
+	body := map[string]any{
+		"appid":        p.cfg.AppID,
+		"mchid":        p.cfg.MchID,
+		"description":  req.Subject,
+		"out_trade_no": req.OrderNo,
+		"notify_url":   p.cfg.NotifyURL,
+		"amount": map[string]any{
+			"total":    amountToMinorUnits(req.Amount),
+			"currency": CurrencyCNY,
+		},
+	}
+	var out struct {
+		CodeURL string `json:"code_url"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/v3/pay/transactions/native", body, &out); err != nil {
+		return nil, err
+	}
+	if out.CodeURL == "" {
+		return nil, ErrPaymentProviderFailed.WithCause(errors.New("wechatpay response missing code_url"))
+	}
+	return &PaymentCreateResult{QRCode: out.CodeURL}, nil
+}
+
+// wechatPayNotifyEnvelope 回调通知外层结构
+type wechatPayNotifyEnvelope struct {
+	ID           string `json:"id"`
+	EventType    string `json:"event_type"`
+	ResourceType string `json:"resource_type"`
+	Resource     struct {
+		Algorithm      string `json:"algorithm"`
+		Ciphertext     string `json:"ciphertext"`
+		AssociatedData string `json:"associated_data"`
+		Nonce          string `json:"nonce"`
+	} `json:"resource"`
+}
+
+// wechatPayTransaction 解密后的支付结果
+type wechatPayTransaction struct {
+	AppID         string `json:"appid"`
+	MchID         string `json:"mchid"`
+	OutTradeNo    string `json:"out_trade_no"`
+	TransactionID string `json:"transaction_id"`
+	TradeState    string `json:"trade_state"`
+	Amount        struct {
+		Total int64 `json:"total"`
+	} `json:"amount"`
+}
+
+// ParseNotify verifies the platform signature and decrypts the AES-256-GCM resource.
+func (p *WeChatPayProvider) ParseNotify(_ context.Context, req *PaymentNotifyRequest) (*PaymentNotification, error) {
+	if err := p.verifySignature(req.Header, req.Body); err != nil {
+		return nil, ErrPaymentInvalidSignature.WithCause(err)
+	}
+	var envelope wechatPayNotifyEnvelope
+	if err := json.Unmarshal(req.Body, &envelope); err != nil {
+		return nil, ErrPaymentInvalidSignature.WithCause(err)
+	}
+	if envelope.Resource.Algorithm != "AEAD_AES_256_GCM" {
+		return nil, ErrPaymentInvalidSignature.WithCause(fmt.Errorf("unsupported algorithm %q", envelope.Resource.Algorithm))
+	}
+	plaintext, err := wechatPayDecrypt(p.cfg.APIv3Key, envelope.Resource.Nonce, envelope.Resource.AssociatedData, envelope.Resource.Ciphertext)
+	if err != nil {
+		return nil, ErrPaymentInvalidSignature.WithCause(err)
+	}
+	var tx wechatPayTransaction
+	if err := json.Unmarshal(plaintext, &tx); err != nil {
+		return nil, ErrPaymentInvalidSignature.WithCause(err)
+	}
+	if tx.MchID != p.cfg.MchID || tx.AppID != p.cfg.AppID {
+		return nil, ErrPaymentInvalidSignature
+	}
+	return &PaymentNotification{
+		EventID:       envelope.ID,
+		OrderNo:       tx.OutTradeNo,
+		TransactionID: tx.TransactionID,
+		Amount:        minorUnitsToAmount(tx.Amount.Total),
+		Paid:          envelope.EventType == "TRANSACTION.SUCCESS" && tx.TradeState == "SUCCESS",
+	}, nil
+}
+
+// NotifyAck 微信支付 APIv3 要求成功返回 2xx，失败返回 4xx/5xx 与 {"code":"FAIL"}
+func (p *WeChatPayProvider) NotifyAck(err error) PaymentNotifyAck {
+	if err != nil {
+		return PaymentNotifyAck{StatusCode: http.StatusBadRequest, ContentType: "application/json", Body: `{"code":"FAIL","message":"失败"}`}
+	}
+	return PaymentNotifyAck{StatusCode: http.StatusOK, ContentType: "application/json", Body: `{"code":"SUCCESS","message":"成功"}`}
+}
+
+// Refund calls the domestic refunds API.
+func (p *WeChatPayProvider) Refund(ctx context.Context, req *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	if req == nil {
+		return nil, ErrOrderNilInput
+	}
+	total := amountToMinorUnits(req.Amount)
+	body := map[string]any{
+		"out_trade_no":  req.OrderNo,
+		"out_refund_no": req.RefundNo,
+		"amount": map[string]any{
+			"refund":   total,
+			"total":    total,
+			"currency": CurrencyCNY,
+		},
+	}
+	if req.Reason != "" {
+		body["reason"] = req.Reason
+	}
+	var out struct {
+		RefundID string `json:"refund_id"`
+		Status   string `json:"status"`
+	}
+	if err := p.do(ctx, http.MethodPost, "/v3/refund/domestic/refunds", body, &out); err != nil {
+		return nil, err
+	}
+	if out.Status == "CLOSED" || out.Status == "ABNORMAL" {
+		return nil, ErrPaymentProviderFailed.WithCause(fmt.Errorf("wechatpay refund %s status %s", out.RefundID, out.Status))
+	}
+	return &PaymentRefundResult{RefundID: out.RefundID, Status: out.Status}, nil
+}
+
+func (p *WeChatPayProvider) do(ctx context.Context, method, path string, payload any, out any) error {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return err
+	}
+	authorization, err := p.authorization(method, path, body)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, method, p.cfg.APIBase+path, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", authorization)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := p.httpClient.Do(req)
+	if err != nil {
+		return ErrPaymentProviderFailed.WithCause(err)
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			log.Printf("wechatpay response body close error: %v", cerr)
+		}
+	}()
+	respBody, err := io.ReadAll(io.LimitReader(resp.Body, 1<<20))
+	if err != nil {
+		return ErrPaymentProviderFailed.WithCause(err)
+	}
+	if resp.StatusCode >= 300 {
+		var apiErr struct {
+			Code    string `json:"code"`
+			Message string `json:"message"`
+		}
+		_ = json.Unmarshal(respBody, &apiErr)
+		return ErrPaymentProviderFailed.WithCause(fmt.Errorf("wechatpay %s: status %d: %s %s", path, resp.StatusCode, apiErr.Code, apiErr.Message))
+	}
+	if err := p.verifySignature(resp.Header, respBody); err != nil {
+		return ErrPaymentInvalidSignature.WithCause(err)
+	}
+	if err := json.Unmarshal(respBody, out); err != nil {
+		return ErrPaymentProviderFailed.WithCause(fmt.Errorf("decode wechatpay response: %w", err))
+	}
+	return nil
+}
+
+// authorization 生成 WECHATPAY2-SHA256-RSA2048 认证头
+func (p *WeChatPayProvider) authorization(method, path string, body []byte) (string, error) {
+	nonce := randomNonce(32)
+	timestamp := strconv.FormatInt(p.now().Unix(), 10)
+	message := method + "\n" + path + "\n" + timestamp + "\n" + nonce + "\n" + string(body) + "\n"
+	sum := sha256.Sum256([]byte(message))
+	sig, err := rsa.SignPKCS1v15(rand.Reader, p.privateKey, crypto.SHA256, sum[:])
+	if err != nil {
+		return "", fmt.Errorf("wechatpay sign: %w", err)
+	}
+	return fmt.Sprintf(`WECHATPAY2-SHA256-RSA2048 mchid="%s",nonce_str="%s",signature="%s",timestamp="%s",serial_no="%s"`,
+		p.cfg.MchID, nonce, base64.StdEncoding.EncodeToString(sig), timestamp, p.cfg.SerialNo), nil
+}
+
+// verifySignature 校验微信支付平台签名（应答与回调共用）
+func (p *WeChatPayProvider) verifySignature(header http.Header, body []byte) error {
+	timestamp := header.Get("Wechatpay-Timestamp")
+	nonce := header.Get("Wechatpay-Nonce")
+	signature := header.Get("Wechatpay-Signature")
+	if timestamp == "" || nonce == "" || signature == "" {
+		return errors.New("missing wechatpay signature headers")
+	}
+	ts, err := strconv.ParseInt(timestamp, 10, 64)
+	if err != nil {
+		return fmt.Errorf("invalid wechatpay timestamp: %w", err)
+	}
+	if diff := p.now().Sub(time.Unix(ts, 0)); diff > wechatPaySignatureTolerance || diff < -wechatPaySignatureTolerance {
+		return errors.New("wechatpay timestamp out of tolerance")
+	}
+	sig, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return fmt.Errorf("decode wechatpay signature: %w", err)
+	}
+	sum := sha256.Sum256([]byte(timestamp + "\n" + nonce + "\n" + string(body) + "\n"))
+	return rsa.VerifyPKCS1v15(p.platformPubKey, crypto.SHA256, sum[:], sig)
+}
+
+// wechatPayDecrypt 使用 APIv3 密钥解密 AEAD_AES_256_GCM 资源
+func wechatPayDecrypt(apiV3Key, nonce, associatedData, ciphertext string) ([]byte, error) {
+	raw, err := base64.StdEncoding.DecodeString(ciphertext)
+	if err != nil {
+		return nil, err
+	}
+	block, err := aes.NewCipher([]byte(apiV3Key))
+	if err != nil {
+		return nil, err
+	}
+	gcm, err := cipher.NewGCMWithNonceSize(block, len(nonce))
+	if err != nil {
+		return nil, err
+	}
+	return gcm.Open(nil, []byte(nonce), raw, []byte(associatedData))
+}
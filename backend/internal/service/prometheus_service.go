@@ -0,0 +1,298 @@
+package service
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+const prometheusNamespace = "sub2api"
+
+// GatewayRequestObservation describes one finished gateway request.
+type GatewayRequestObservation struct {
+	Platform  string
+	GroupID   int64
+	Model     string
+	AccountID int64
+	Stream    bool
+	Status    int
+	Duration  time.Duration
+	// TTFT is nil when the request never produced a first token.
+	TTFT *time.Duration
+	// UpstreamErrors are the failed upstream attempts of this request (retries/failover included).
+	UpstreamErrors []*OpsUpstreamErrorEvent
+}
+
+// PrometheusService owns the Prometheus registry behind the /metrics endpoint.
+//
+// Per-request series are pushed by the gateway middleware via ObserveGatewayRequest;
+// internal counters that already live in other services (scheduler, usage worker pool,
+// idempotency, concurrency, outbox, billing breaker) are read at scrape time.
+type PrometheusService struct {
+	enabled      bool
+	accountLabel bool
+	registry     *prometheus.Registry
+
+	requestsTotal       *prometheus.CounterVec
+	requestDuration     *prometheus.HistogramVec
+	ttft                *prometheus.HistogramVec
+	upstreamErrorsTotal *prometheus.CounterVec
+}
+
+func NewPrometheusService(
+	cfg *config.Config,
+	openAIGatewayService *OpenAIGatewayService,
+	usageRecordWorkerPool *UsageRecordWorkerPool,
+	schedulerSnapshotService *SchedulerSnapshotService,
+	billingCacheService *BillingCacheService,
+	concurrencyService *ConcurrencyService,
+	healthScorer *AccountHealthScorer,
+) *PrometheusService {
+	s := &PrometheusService{}
+	if cfg == nil || !cfg.Metrics.Enabled {
+		return s
+	}
+	s.enabled = true
+	s.accountLabel = cfg.Metrics.AccountLabel
+	s.registry = prometheus.NewRegistry()
+
+	labels := []string{"platform", "group_id", "model", "account_id"}
+	s.requestsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: "gateway",
+		Name:      "requests_total",
+		Help:      "Gateway requests by platform, group, model, account, status code and stream mode.",
+	}, append(append([]string{}, labels...), "status", "stream"))
+	s.requestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: "gateway",
+		Name:      "request_duration_seconds",
+		Help:      "End-to-end gateway request latency (streams measured until the last byte).",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 20, 30, 60, 120, 300},
+	}, labels)
+	s.ttft = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: "gateway",
+		Name:      "time_to_first_token_seconds",
+		Help:      "Time to first token of successful gateway requests.",
+		Buckets:   []float64{0.1, 0.25, 0.5, 1, 1.5, 2, 3, 5, 8, 13, 20, 30},
+	}, labels)
+	s.upstreamErrorsTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Namespace: prometheusNamespace,
+		Subsystem: "gateway",
+		Name:      "upstream_errors_total",
+		Help:      "Failed upstream attempts by platform, account, upstream status code and kind.",
+	}, []string{"platform", "account_id", "status_code", "kind"})
+
+	s.registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		s.requestsTotal,
+		s.requestDuration,
+		s.ttft,
+		s.upstreamErrorsTotal,
+		&gatewayInternalsCollector{
+			openAIGatewayService:     openAIGatewayService,
+			usageRecordWorkerPool:    usageRecordWorkerPool,
+			schedulerSnapshotService: schedulerSnapshotService,
+			billingCacheService:      billingCacheService,
+			concurrencyService:       concurrencyService,
+			healthScorer:             healthScorer,
+		},
+	)
+	return s
+}
+
+// Enabled reports whether metrics.enabled is set.
+func (s *PrometheusService) Enabled() bool {
+	return s != nil && s.enabled
+}
+
+// Handler returns the scrape handler (exposition format negotiated by promhttp).
+func (s *PrometheusService) Handler() http.Handler {
+	if !s.Enabled() {
+		return http.NotFoundHandler()
+	}
+	return promhttp.HandlerFor(s.registry, promhttp.HandlerOpts{})
+}
+
+// ObserveGatewayRequest records request count, latency, TTFT and upstream errors.
+func (s *PrometheusService) ObserveGatewayRequest(o GatewayRequestObservation) {
+	if !s.Enabled() {
+		return
+	}
+	accountID := ""
+	if s.accountLabel && o.AccountID > 0 {
+		accountID = strconv.FormatInt(o.AccountID, 10)
+	}
+	groupID := ""
+	if o.GroupID > 0 {
+		groupID = strconv.FormatInt(o.GroupID, 10)
+	}
+	platform := strings.TrimSpace(o.Platform)
+	model := strings.TrimSpace(o.Model)
+
+	s.requestsTotal.WithLabelValues(platform, groupID, model, accountID, strconv.Itoa(o.Status), strconv.FormatBool(o.Stream)).Inc()
+	s.requestDuration.WithLabelValues(platform, groupID, model, accountID).Observe(o.Duration.Seconds())
+	if o.TTFT != nil && o.Status < http.StatusBadRequest {
+		s.ttft.WithLabelValues(platform, groupID, model, accountID).Observe(o.TTFT.Seconds())
+	}
+
+	for _, ev := range o.UpstreamErrors {
+		if ev == nil {
+			continue
+		}
+		evPlatform := ev.Platform
+		if evPlatform == "" {
+			evPlatform = platform
+		}
+		evAccountID := ""
+		if s.accountLabel && ev.AccountID > 0 {
+			evAccountID = strconv.FormatInt(ev.AccountID, 10)
+		}
+		statusCode := ""
+		if ev.UpstreamStatusCode > 0 {
+			statusCode = strconv.Itoa(ev.UpstreamStatusCode)
+		}
+		s.upstreamErrorsTotal.WithLabelValues(evPlatform, evAccountID, statusCode, ev.Kind).Inc()
+	}
+}
+
+// gatewayInternalsCollector exports process-local counters kept by other services.
+// Values are read at scrape time, so nothing is duplicated on the hot path.
+type gatewayInternalsCollector struct {
+	openAIGatewayService     *OpenAIGatewayService
+	usageRecordWorkerPool    *UsageRecordWorkerPool
+	schedulerSnapshotService *SchedulerSnapshotService
+	billingCacheService      *BillingCacheService
+	concurrencyService       *ConcurrencyService
+	healthScorer             *AccountHealthScorer
+}
+
+func newPrometheusDesc(subsystem, name, help string, labels ...string) *prometheus.Desc {
+	return prometheus.NewDesc(prometheus.BuildFQName(prometheusNamespace, subsystem, name), help, labels, nil)
+}
+
+var (
+	promSchedulerSelectionsDesc  = newPrometheusDesc("openai_scheduler", "selections_total", "OpenAI account scheduler selections by outcome.", "outcome")
+	promSchedulerSwitchesDesc    = newPrometheusDesc("openai_scheduler", "account_switches_total", "OpenAI account switches after failed attempts.")
+	promSchedulerLatencyDesc     = newPrometheusDesc("openai_scheduler", "latency_seconds_total", "Cumulative OpenAI account selection latency.")
+	promSchedulerStatsAcctDesc   = newPrometheusDesc("openai_scheduler", "runtime_stats_accounts", "Accounts tracked by the OpenAI scheduler runtime stats.")
+	promAccountSchedSelectDesc   = newPrometheusDesc("account_scheduler", "selections_total", "Anthropic/Gemini/Antigravity account selections by layer.", "layer")
+	promAccountSchedLatencyDesc  = newPrometheusDesc("account_scheduler", "latency_seconds_total", "Cumulative load-aware account selection latency.")
+	promAccountSchedScoreDesc    = newPrometheusDesc("account_scheduler", "selected_score_avg", "Average health score of accounts picked by the load-balance layer.")
+	promAccountHealthEventsDesc  = newPrometheusDesc("account_scheduler", "health_events_total", "Account health events fed into scheduling scores.", "event")
+	promAccountHealthAcctDesc    = newPrometheusDesc("account_scheduler", "runtime_stats_accounts", "Accounts tracked by the shared runtime health stats.")
+	promUsagePoolWorkersDesc     = newPrometheusDesc("usage_record_pool", "workers", "Usage record worker pool workers.", "state")
+	promUsagePoolWaitingDesc     = newPrometheusDesc("usage_record_pool", "waiting_tasks", "Usage record tasks waiting in the queue.")
+	promUsagePoolTasksDesc       = newPrometheusDesc("usage_record_pool", "tasks_total", "Usage record tasks by result.", "result")
+	promWindowCostPrefetchDesc   = newPrometheusDesc("gateway", "window_cost_prefetch_total", "Window cost prefetch outcomes.", "result")
+	promIdempotencyEventsDesc    = newPrometheusDesc("idempotency", "events_total", "Idempotency events by type.", "event")
+	promIdempotencyDurationDesc  = newPrometheusDesc("idempotency", "processing_duration_seconds_total", "Cumulative idempotent processing duration.")
+	promIdempotencyProcessedDesc = newPrometheusDesc("idempotency", "processing_total", "Idempotent requests processed.")
+	promConcurrencySlotsDesc     = newPrometheusDesc("concurrency", "slots_held", "Concurrency slots currently held by this instance.", "scope")
+	promOutboxLagDesc            = newPrometheusDesc("scheduler", "outbox_lag_seconds", "Lag of the oldest scheduler outbox event seen by the last poll.")
+	promBillingBreakerDesc       = newPrometheusDesc("billing_cache", "circuit_breaker_state", "Billing cache circuit breaker state (1 for the current state).", "state")
+)
+
+func (c *gatewayInternalsCollector) Describe(ch chan<- *prometheus.Desc) {
+	for _, d := range []*prometheus.Desc{
+		promSchedulerSelectionsDesc, promSchedulerSwitchesDesc, promSchedulerLatencyDesc, promSchedulerStatsAcctDesc,
+		promAccountSchedSelectDesc, promAccountSchedLatencyDesc, promAccountSchedScoreDesc, promAccountHealthEventsDesc, promAccountHealthAcctDesc,
+		promUsagePoolWorkersDesc, promUsagePoolWaitingDesc, promUsagePoolTasksDesc,
+		promWindowCostPrefetchDesc,
+		promIdempotencyEventsDesc, promIdempotencyDurationDesc, promIdempotencyProcessedDesc,
+		promConcurrencySlotsDesc, promOutboxLagDesc, promBillingBreakerDesc,
+	} {
+		ch <- d
+	}
+}
+
+func (c *gatewayInternalsCollector) Collect(ch chan<- prometheus.Metric) {
+	counter := func(desc *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.CounterValue, v, labels...)
+	}
+	gauge := func(desc *prometheus.Desc, v float64, labels ...string) {
+		ch <- prometheus.MustNewConstMetric(desc, prometheus.GaugeValue, v, labels...)
+	}
+
+	if c.openAIGatewayService != nil {
+		m := c.openAIGatewayService.SnapshotOpenAIAccountSchedulerMetrics()
+		counter(promSchedulerSelectionsDesc, float64(m.StickyPreviousHitTotal), "sticky_previous_response")
+		counter(promSchedulerSelectionsDesc, float64(m.StickySessionHitTotal), "sticky_session")
+		counter(promSchedulerSelectionsDesc, float64(m.LoadBalanceSelectTotal), "load_balance")
+		counter(promSchedulerSwitchesDesc, float64(m.AccountSwitchTotal))
+		counter(promSchedulerLatencyDesc, float64(m.SchedulerLatencyMsTotal)/1000)
+		gauge(promSchedulerStatsAcctDesc, float64(m.RuntimeStatsAccountCount))
+	}
+
+	if c.healthScorer != nil {
+		m := c.healthScorer.SnapshotMetrics()
+		counter(promAccountSchedSelectDesc, float64(m.StickyOrRoutedTotal), "sticky_or_routed")
+		counter(promAccountSchedSelectDesc, float64(m.LoadBalanceSelectTotal), "load_balance")
+		counter(promAccountSchedSelectDesc, float64(m.LegacyOrderSelectTotal), "legacy_order")
+		counter(promAccountSchedSelectDesc, float64(m.FallbackWaitTotal), "fallback_wait")
+		counter(promAccountSchedSelectDesc, float64(m.SelectErrorTotal), "error")
+		counter(promAccountSchedLatencyDesc, float64(m.SchedulerLatencyMsTotal)/1000)
+		gauge(promAccountSchedScoreDesc, m.SelectedScoreAvg)
+		counter(promAccountHealthEventsDesc, float64(m.OverloadEventTotal), "overload")
+		counter(promAccountHealthEventsDesc, float64(m.TokenRefreshFailureTotal), "token_refresh_failure")
+		gauge(promAccountHealthAcctDesc, float64(m.RuntimeStatsAccountCount))
+	}
+
+	if c.usageRecordWorkerPool != nil {
+		st := c.usageRecordWorkerPool.Stats()
+		gauge(promUsagePoolWorkersDesc, float64(st.RunningWorkers), "running")
+		gauge(promUsagePoolWorkersDesc, float64(st.MaxConcurrency), "max")
+		gauge(promUsagePoolWaitingDesc, float64(st.WaitingTasks))
+		counter(promUsagePoolTasksDesc, float64(st.SubmittedTasks), "submitted")
+		counter(promUsagePoolTasksDesc, float64(st.SuccessfulTasks), "success")
+		counter(promUsagePoolTasksDesc, float64(st.FailedTasks), "failed")
+		counter(promUsagePoolTasksDesc, float64(st.DroppedQueueFull), "dropped_queue_full")
+		counter(promUsagePoolTasksDesc, float64(st.DroppedPoolStopped), "dropped_pool_stopped")
+		counter(promUsagePoolTasksDesc, float64(st.SyncFallbackTasks), "sync_fallback")
+	}
+
+	hit, miss, batchSQL, fallback, errCount := GatewayWindowCostPrefetchStats()
+	counter(promWindowCostPrefetchDesc, float64(hit), "cache_hit")
+	counter(promWindowCostPrefetchDesc, float64(miss), "cache_miss")
+	counter(promWindowCostPrefetchDesc, float64(batchSQL), "batch_sql")
+	counter(promWindowCostPrefetchDesc, float64(fallback), "fallback")
+	counter(promWindowCostPrefetchDesc, float64(errCount), "error")
+
+	idem := GetIdempotencyMetricsSnapshot()
+	counter(promIdempotencyEventsDesc, float64(idem.ClaimTotal), "claim")
+	counter(promIdempotencyEventsDesc, float64(idem.ReplayTotal), "replay")
+	counter(promIdempotencyEventsDesc, float64(idem.ConflictTotal), "conflict")
+	counter(promIdempotencyEventsDesc, float64(idem.RetryBackoffTotal), "retry_backoff")
+	counter(promIdempotencyEventsDesc, float64(idem.StoreUnavailableTotal), "store_unavailable")
+	counter(promIdempotencyDurationDesc, idem.ProcessingDurationTotalMs/1000)
+	counter(promIdempotencyProcessedDesc, float64(idem.ProcessingDurationCount))
+
+	if c.concurrencyService != nil {
+		account, user := c.concurrencyService.HeldSlots()
+		gauge(promConcurrencySlotsDesc, float64(account), "account")
+		gauge(promConcurrencySlotsDesc, float64(user), "user")
+	}
+
+	if c.schedulerSnapshotService != nil {
+		gauge(promOutboxLagDesc, c.schedulerSnapshotService.OutboxLag().Seconds())
+	}
+
+	if c.billingCacheService != nil {
+		current := c.billingCacheService.CircuitBreakerState()
+		for _, state := range []string{"closed", "open", "half-open", "disabled"} {
+			v := 0.0
+			if state == current {
+				v = 1
+			}
+			gauge(promBillingBreakerDesc, v, state)
+		}
+	}
+}
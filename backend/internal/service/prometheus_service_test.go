@@ -0,0 +1,77 @@
+//go:build unit
+
+package service
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/require"
+)
+
+func newTestPrometheusService(accountLabel bool) *PrometheusService {
+	cfg := &config.Config{}
+	cfg.Metrics.Enabled = true
+	cfg.Metrics.AccountLabel = accountLabel
+	return NewPrometheusService(cfg, nil, nil, nil, nil, nil, nil)
+}
+
+func TestPrometheusService_Disabled(t *testing.T) {
+	svc := NewPrometheusService(&config.Config{}, nil, nil, nil, nil, nil, nil)
+	require.False(t, svc.Enabled())
+	svc.ObserveGatewayRequest(GatewayRequestObservation{Platform: PlatformOpenAI, Status: 200})
+
+	rec := httptest.NewRecorder()
+	svc.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusNotFound, rec.Code)
+}
+
+func TestPrometheusService_ObserveGatewayRequest(t *testing.T) {
+	svc := newTestPrometheusService(true)
+	ttft := 800 * time.Millisecond
+	svc.ObserveGatewayRequest(GatewayRequestObservation{
+		Platform:  PlatformAnthropic,
+		GroupID:   3,
+		Model:     "claude-sonnet-4-5",
+		AccountID: 9,
+		Stream:    true,
+		Status:    http.StatusOK,
+		Duration:  2 * time.Second,
+		TTFT:      &ttft,
+		UpstreamErrors: []*OpsUpstreamErrorEvent{
+			{AccountID: 8, UpstreamStatusCode: 529, Kind: "failover"},
+			nil,
+		},
+	})
+
+	require.Equal(t, 1.0, testutil.ToFloat64(svc.requestsTotal.WithLabelValues(PlatformAnthropic, "3", "claude-sonnet-4-5", "9", "200", "true")))
+	require.Equal(t, 1.0, testutil.ToFloat64(svc.upstreamErrorsTotal.WithLabelValues(PlatformAnthropic, "8", "529", "failover")))
+	require.Equal(t, 1, testutil.CollectAndCount(svc.ttft))
+}
+
+func TestPrometheusService_AccountLabelDisabled(t *testing.T) {
+	svc := newTestPrometheusService(false)
+	svc.ObserveGatewayRequest(GatewayRequestObservation{Platform: PlatformOpenAI, AccountID: 9, Status: http.StatusBadGateway})
+
+	require.Equal(t, 1.0, testutil.ToFloat64(svc.requestsTotal.WithLabelValues(PlatformOpenAI, "", "", "", "502", "false")))
+	require.Equal(t, 0, testutil.CollectAndCount(svc.ttft))
+}
+
+func TestPrometheusService_ScrapeIncludesInternals(t *testing.T) {
+	cfg := &config.Config{}
+	cfg.Metrics.Enabled = true
+	svc := NewPrometheusService(cfg, nil, nil, nil, &BillingCacheService{}, &ConcurrencyService{}, nil)
+
+	rec := httptest.NewRecorder()
+	svc.Handler().ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/metrics", nil))
+	require.Equal(t, http.StatusOK, rec.Code)
+	body := rec.Body.String()
+	require.Contains(t, body, `sub2api_billing_cache_circuit_breaker_state{state="disabled"} 1`)
+	require.Contains(t, body, `sub2api_concurrency_slots_held{scope="account"} 0`)
+	require.Contains(t, body, `sub2api_idempotency_events_total{event="claim"}`)
+	require.Contains(t, body, "go_goroutines")
+}
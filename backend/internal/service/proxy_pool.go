@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+var (
+	ErrProxyPoolNotFound    = infraerrors.NotFound("PROXY_POOL_NOT_FOUND", "proxy pool not found")
+	ErrProxyPoolExists      = infraerrors.Conflict("PROXY_POOL_EXISTS", "proxy pool name already exists")
+	ErrProxyPoolInvalid     = infraerrors.BadRequest("PROXY_POOL_INVALID", "invalid proxy pool")
+	ErrProxyPoolUnavailable = infraerrors.ServiceUnavailable("PROXY_POOL_UNAVAILABLE", "no proxy available in pool")
+)
+
+// ProxyPool 代理池：账号绑定代理池后，由 HTTPUpstream 从健康成员中按账号粘性选择出口代理
+type ProxyPool struct {
+	ID          int64
+	Name        string
+	Description string
+	Status      string
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+
+	Members      []ProxyPoolMember
+	AccountCount int64
+}
+
+func (p *ProxyPool) IsActive() bool {
+	return p.Status == StatusActive
+}
+
+// ProxyPoolMember 代理池成员及其健康状态
+type ProxyPoolMember struct {
+	PoolID        int64
+	ProxyID       int64
+	Proxy         *Proxy
+	Healthy       bool
+	FailCount     int
+	LatencyMs     *int64
+	LastError     string
+	LastCheckedAt *time.Time
+}
+
+// ProxyPoolMemberHealth 一次健康检查（或请求失败）后的成员状态
+type ProxyPoolMemberHealth struct {
+	ProxyID   int64
+	Healthy   bool
+	FailCount int
+	LatencyMs *int64
+	LastError string
+	CheckedAt time.Time
+}
+
+// ProxyPoolRepository 代理池存储
+type ProxyPoolRepository interface {
+	Create(ctx context.Context, pool *ProxyPool) error
+	// GetByID 返回代理池及其成员（含代理详情）
+	GetByID(ctx context.Context, id int64) (*ProxyPool, error)
+	Update(ctx context.Context, pool *ProxyPool) error
+	// Delete 删除代理池；绑定该池的账号 proxy_pool_id 置空
+	Delete(ctx context.Context, id int64) error
+	List(ctx context.Context, params pagination.PaginationParams, search string) ([]ProxyPool, *pagination.PaginationResult, error)
+	// ListAll 返回所有代理池及成员（跳过已删除的代理），供运行时选择与健康检查使用
+	ListAll(ctx context.Context) ([]ProxyPool, error)
+
+	// SetMembers 以 proxyIDs 覆盖成员列表；保留已有成员的健康状态
+	SetMembers(ctx context.Context, poolID int64, proxyIDs []int64) error
+	// UpdateMemberHealth 更新代理在所有所属池中的健康状态
+	UpdateMemberHealth(ctx context.Context, health ProxyPoolMemberHealth) error
+
+	// ListAccountBindings 返回 accountID -> poolID
+	ListAccountBindings(ctx context.Context) (map[int64]int64, error)
+	// AssignAccounts 将账号绑定到 poolID（nil 表示解绑），返回受影响的账号数
+	AssignAccounts(ctx context.Context, poolID *int64, accountIDs []int64) (int64, error)
+}
+
+// CreateProxyPoolInput 创建代理池参数
+type CreateProxyPoolInput struct {
+	Name        string
+	Description string
+	ProxyIDs    []int64
+}
+
+// UpdateProxyPoolInput 更新代理池参数（nil 表示不修改）
+type UpdateProxyPoolInput struct {
+	Name        *string
+	Description *string
+	Status      *string
+	ProxyIDs    *[]int64
+}
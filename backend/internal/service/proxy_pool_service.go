@@ -0,0 +1,626 @@
+package service
+
+import (
+	"context"
+	"encoding/binary"
+	"hash/fnv"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"go.uber.org/zap"
+)
+
+const proxyPoolPersistTimeout = 5 * time.Second
+
+// ProxyPoolService 代理池：管理代理池与成员，后台周期性探测成员健康状态，
+// 并为绑定代理池的账号选择出口代理。
+//
+// 选择规则：
+//   - 账号首次请求时在健康成员中按 rendezvous hash 选择，之后粘性绑定，保证出口 IP 稳定；
+//   - 绑定的成员被标记为不健康时（探测失败或请求链路上出现代理连接错误）才切换，
+//     切换事件以 warn 级别写入日志，进入运维系统日志；
+//   - 所有成员均不健康时仍使用原绑定成员，避免回退直连泄露服务器 IP。
+//
+// 运行时状态保存在内存中，随每次健康检查从数据库刷新；多实例各自探测、各自维护绑定。
+type ProxyPoolService struct {
+	repo         ProxyPoolRepository
+	proxyRepo    ProxyRepository
+	prober       ProxyExitInfoProber
+	latencyCache ProxyLatencyCache
+	cfg          *config.Config
+
+	mu           sync.Mutex
+	pools        map[int64]*proxyPoolState
+	accountPools map[int64]int64 // accountID -> poolID
+	bindings     map[int64]int64 // accountID -> proxyID
+
+	now func() time.Time
+
+	stopCh    chan struct{}
+	wg        sync.WaitGroup
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+type proxyPoolState struct {
+	id      int64
+	active  bool
+	members []*proxyPoolMemberState // 按 proxyID 升序
+}
+
+type proxyPoolMemberState struct {
+	proxyID   int64
+	url       string
+	healthy   bool
+	failCount int
+}
+
+// ProxyPoolSelection 一次选择结果
+type ProxyPoolSelection struct {
+	PoolID   int64
+	ProxyID  int64
+	ProxyURL string
+}
+
+func NewProxyPoolService(
+	repo ProxyPoolRepository,
+	proxyRepo ProxyRepository,
+	prober ProxyExitInfoProber,
+	latencyCache ProxyLatencyCache,
+	cfg *config.Config,
+) *ProxyPoolService {
+	return &ProxyPoolService{
+		repo:         repo,
+		proxyRepo:    proxyRepo,
+		prober:       prober,
+		latencyCache: latencyCache,
+		cfg:          cfg,
+		pools:        make(map[int64]*proxyPoolState),
+		accountPools: make(map[int64]int64),
+		bindings:     make(map[int64]int64),
+		now:          time.Now,
+		stopCh:       make(chan struct{}),
+	}
+}
+
+func (s *ProxyPoolService) enabled() bool {
+	return s != nil && s.repo != nil && s.cfg != nil && s.cfg.ProxyPool.Enabled
+}
+
+func (s *ProxyPoolService) Start() {
+	if !s.enabled() {
+		logger.LegacyPrintf("service.proxy_pool", "[ProxyPool] not started (disabled)")
+		return
+	}
+	s.startOnce.Do(func() {
+		interval := time.Duration(s.cfg.ProxyPool.CheckIntervalSeconds) * time.Second
+		s.wg.Add(1)
+		go func() {
+			defer s.wg.Done()
+			s.runCheck()
+			ticker := time.NewTicker(interval)
+			defer ticker.Stop()
+			for {
+				select {
+				case <-ticker.C:
+					s.runCheck()
+				case <-s.stopCh:
+					return
+				}
+			}
+		}()
+		logger.LegacyPrintf("service.proxy_pool", "[ProxyPool] started (interval=%s)", interval)
+	})
+}
+
+func (s *ProxyPoolService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.stopCh != nil {
+			close(s.stopCh)
+		}
+		s.wg.Wait()
+	})
+}
+
+// Select 为账号选择代理池成员；账号未绑定代理池（或代理池已停用）时 ok=false。
+func (s *ProxyPoolService) Select(accountID int64) (ProxyPoolSelection, bool, error) {
+	if !s.enabled() || accountID <= 0 {
+		return ProxyPoolSelection{}, false, nil
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	pool := s.accountPoolLocked(accountID)
+	if pool == nil {
+		return ProxyPoolSelection{}, false, nil
+	}
+	member := s.selectLocked(pool, accountID)
+	if member == nil {
+		return ProxyPoolSelection{PoolID: pool.id}, true, ErrProxyPoolUnavailable
+	}
+	return ProxyPoolSelection{PoolID: pool.id, ProxyID: member.proxyID, ProxyURL: member.url}, true, nil
+}
+
+// ReportFailure 请求链路上出现代理连接错误：立即将成员标记为不健康并为账号切换到其他健康成员。
+func (s *ProxyPoolService) ReportFailure(accountID int64, selection ProxyPoolSelection, cause error) {
+	if !s.enabled() || selection.ProxyID <= 0 {
+		return
+	}
+	errMsg := ""
+	if cause != nil {
+		errMsg = cause.Error()
+	}
+
+	s.mu.Lock()
+	var health *ProxyPoolMemberHealth
+	for _, pool := range s.pools {
+		for _, m := range pool.members {
+			if m.proxyID != selection.ProxyID {
+				continue
+			}
+			if m.healthy {
+				m.healthy = false
+				m.failCount = s.failureThreshold()
+				health = &ProxyPoolMemberHealth{
+					ProxyID:   m.proxyID,
+					Healthy:   false,
+					FailCount: m.failCount,
+					LastError: errMsg,
+					CheckedAt: s.now(),
+				}
+			}
+		}
+	}
+	if bound, ok := s.bindings[accountID]; ok && bound == selection.ProxyID {
+		delete(s.bindings, accountID)
+	}
+	var toProxyID int64
+	if pool := s.accountPoolLocked(accountID); pool != nil {
+		if next := s.selectLocked(pool, accountID); next != nil {
+			toProxyID = next.proxyID
+		}
+	}
+	s.mu.Unlock()
+
+	logger.L().Warn("proxy pool failover",
+		zap.String("component", "service.proxy_pool"),
+		zap.Int64("account_id", accountID),
+		zap.Int64("pool_id", selection.PoolID),
+		zap.Int64("from_proxy_id", selection.ProxyID),
+		zap.Int64("to_proxy_id", toProxyID),
+		zap.String("reason", errMsg),
+	)
+
+	if health != nil {
+		go func(h ProxyPoolMemberHealth) {
+			ctx, cancel := context.WithTimeout(context.Background(), proxyPoolPersistTimeout)
+			defer cancel()
+			if err := s.repo.UpdateMemberHealth(ctx, h); err != nil {
+				logger.LegacyPrintf("service.proxy_pool", "[ProxyPool] persist member health failed: proxy=%d err=%v", h.ProxyID, err)
+			}
+		}(*health)
+	}
+}
+
+func (s *ProxyPoolService) accountPoolLocked(accountID int64) *proxyPoolState {
+	poolID, ok := s.accountPools[accountID]
+	if !ok {
+		return nil
+	}
+	pool := s.pools[poolID]
+	if pool == nil || !pool.active {
+		return nil
+	}
+	return pool
+}
+
+// selectLocked 粘性选择：已绑定且健康则继续使用；否则在健康成员中重新选择并绑定。
+func (s *ProxyPoolService) selectLocked(pool *proxyPoolState, accountID int64) *proxyPoolMemberState {
+	if len(pool.members) == 0 {
+		return nil
+	}
+	var bound *proxyPoolMemberState
+	if proxyID, ok := s.bindings[accountID]; ok {
+		for _, m := range pool.members {
+			if m.proxyID == proxyID {
+				bound = m
+				break
+			}
+		}
+	}
+	if bound != nil && bound.healthy {
+		return bound
+	}
+
+	healthy := make([]*proxyPoolMemberState, 0, len(pool.members))
+	for _, m := range pool.members {
+		if m.healthy {
+			healthy = append(healthy, m)
+		}
+	}
+	var picked *proxyPoolMemberState
+	switch {
+	case len(healthy) > 0:
+		picked = rendezvousProxyPoolMember(healthy, accountID)
+	case bound != nil:
+		picked = bound
+	default:
+		picked = rendezvousProxyPoolMember(pool.members, accountID)
+	}
+	s.bindings[accountID] = picked.proxyID
+	return picked
+}
+
+// rendezvousProxyPoolMember 最高随机权重（HRW）哈希：成员增减时只影响少量账号，且多实例选择一致
+func rendezvousProxyPoolMember(members []*proxyPoolMemberState, accountID int64) *proxyPoolMemberState {
+	var best *proxyPoolMemberState
+	var bestScore uint64
+	var buf [16]byte
+	binary.BigEndian.PutUint64(buf[:8], uint64(accountID))
+	for _, m := range members {
+		binary.BigEndian.PutUint64(buf[8:], uint64(m.proxyID))
+		h := fnv.New64a()
+		_, _ = h.Write(buf[:])
+		score := h.Sum64()
+		if best == nil || score > bestScore {
+			best, bestScore = m, score
+		}
+	}
+	return best
+}
+
+func (s *ProxyPoolService) failureThreshold() int {
+	if s.cfg != nil && s.cfg.ProxyPool.FailureThreshold > 0 {
+		return s.cfg.ProxyPool.FailureThreshold
+	}
+	return 1
+}
+
+func (s *ProxyPoolService) runCheck() {
+	timeout := time.Duration(s.cfg.ProxyPool.CheckIntervalSeconds) * time.Second
+	ctx, cancel := context.WithTimeout(context.Background(), timeout)
+	defer cancel()
+	if err := s.Refresh(ctx); err != nil {
+		logger.LegacyPrintf("service.proxy_pool", "[ProxyPool] refresh failed: %v", err)
+		return
+	}
+	s.CheckHealth(ctx)
+}
+
+// Refresh 从数据库重新加载代理池、成员与账号绑定；保留仍然有效的粘性绑定。
+func (s *ProxyPoolService) Refresh(ctx context.Context) error {
+	if !s.enabled() {
+		return nil
+	}
+	pools, err := s.repo.ListAll(ctx)
+	if err != nil {
+		return err
+	}
+	accountPools, err := s.repo.ListAccountBindings(ctx)
+	if err != nil {
+		return err
+	}
+
+	states := make(map[int64]*proxyPoolState, len(pools))
+	for i := range pools {
+		pool := &pools[i]
+		state := &proxyPoolState{id: pool.ID, active: pool.IsActive()}
+		for _, m := range pool.Members {
+			if m.Proxy == nil || !m.Proxy.IsActive() {
+				continue
+			}
+			state.members = append(state.members, &proxyPoolMemberState{
+				proxyID:   m.ProxyID,
+				url:       m.Proxy.URL(),
+				healthy:   m.Healthy,
+				failCount: m.FailCount,
+			})
+		}
+		sort.Slice(state.members, func(a, b int) bool { return state.members[a].proxyID < state.members[b].proxyID })
+		states[pool.ID] = state
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.pools = states
+	s.accountPools = accountPools
+	for accountID, proxyID := range s.bindings {
+		pool := s.accountPoolLocked(accountID)
+		if pool == nil || !pool.hasMember(proxyID) {
+			delete(s.bindings, accountID)
+		}
+	}
+	return nil
+}
+
+func (p *proxyPoolState) hasMember(proxyID int64) bool {
+	for _, m := range p.members {
+		if m.proxyID == proxyID {
+			return true
+		}
+	}
+	return false
+}
+
+// CheckHealth 探测所有代理池成员（同一代理只探测一次），更新健康状态与延迟缓存。
+func (s *ProxyPoolService) CheckHealth(ctx context.Context) {
+	if !s.enabled() || s.prober == nil {
+		return
+	}
+
+	type target struct {
+		proxyID   int64
+		url       string
+		healthy   bool
+		failCount int
+	}
+	s.mu.Lock()
+	seen := make(map[int64]struct{})
+	targets := make([]target, 0)
+	for _, pool := range s.pools {
+		for _, m := range pool.members {
+			if _, ok := seen[m.proxyID]; ok {
+				continue
+			}
+			seen[m.proxyID] = struct{}{}
+			targets = append(targets, target{proxyID: m.proxyID, url: m.url, healthy: m.healthy, failCount: m.failCount})
+		}
+	}
+	s.mu.Unlock()
+	if len(targets) == 0 {
+		return
+	}
+
+	concurrency := s.cfg.ProxyPool.ProbeConcurrency
+	if concurrency <= 0 {
+		concurrency = 1
+	}
+	probeTimeout := time.Duration(s.cfg.ProxyPool.ProbeTimeoutSeconds) * time.Second
+	results := make([]ProxyPoolMemberHealth, len(targets))
+	sem := make(chan struct{}, concurrency)
+	var wg sync.WaitGroup
+	for i, t := range targets {
+		wg.Add(1)
+		sem <- struct{}{}
+		go func(i int, t target) {
+			defer wg.Done()
+			defer func() { <-sem }()
+			results[i] = s.probe(ctx, t.proxyID, t.url, t.failCount, probeTimeout)
+		}(i, t)
+	}
+	wg.Wait()
+
+	s.mu.Lock()
+	for _, r := range results {
+		for _, pool := range s.pools {
+			for _, m := range pool.members {
+				if m.proxyID == r.ProxyID {
+					m.healthy = r.Healthy
+					m.failCount = r.FailCount
+				}
+			}
+		}
+	}
+	s.mu.Unlock()
+
+	for i, r := range results {
+		switch {
+		case targets[i].healthy && !r.Healthy:
+			logger.LegacyPrintf("service.proxy_pool", "[ProxyPool] warn: proxy=%d marked unhealthy after %d failed probes: %s", r.ProxyID, r.FailCount, r.LastError)
+		case !targets[i].healthy && r.Healthy:
+			logger.LegacyPrintf("service.proxy_pool", "[ProxyPool] proxy=%d recovered", r.ProxyID)
+		}
+		if err := s.repo.UpdateMemberHealth(ctx, r); err != nil {
+			logger.LegacyPrintf("service.proxy_pool", "[ProxyPool] persist member health failed: proxy=%d err=%v", r.ProxyID, err)
+		}
+	}
+}
+
+func (s *ProxyPoolService) probe(ctx context.Context, proxyID int64, proxyURL string, failCount int, timeout time.Duration) ProxyPoolMemberHealth {
+	probeCtx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	health := ProxyPoolMemberHealth{ProxyID: proxyID, CheckedAt: s.now()}
+	exitInfo, latencyMs, err := s.prober.ProbeProxy(probeCtx, proxyURL)
+	if err != nil {
+		health.FailCount = failCount + 1
+		health.Healthy = health.FailCount < s.failureThreshold()
+		health.LastError = err.Error()
+		s.saveLatency(ctx, proxyID, &ProxyLatencyInfo{Success: false, Message: err.Error(), UpdatedAt: health.CheckedAt})
+		return health
+	}
+
+	latency := latencyMs
+	health.Healthy = true
+	health.LatencyMs = &latency
+	info := &ProxyLatencyInfo{Success: true, LatencyMs: &latency, Message: "Proxy is accessible", UpdatedAt: health.CheckedAt}
+	if exitInfo != nil {
+		info.IPAddress = exitInfo.IP
+		info.Country = exitInfo.Country
+		info.CountryCode = exitInfo.CountryCode
+		info.Region = exitInfo.Region
+		info.City = exitInfo.City
+	}
+	s.saveLatency(ctx, proxyID, info)
+	return health
+}
+
+// saveLatency 写入代理延迟缓存（与代理列表页共用），保留已有的质量检测结果
+func (s *ProxyPoolService) saveLatency(ctx context.Context, proxyID int64, info *ProxyLatencyInfo) {
+	if s.latencyCache == nil {
+		return
+	}
+	merged := *info
+	if latencies, err := s.latencyCache.GetProxyLatencies(ctx, []int64{proxyID}); err == nil {
+		if existing := latencies[proxyID]; existing != nil {
+			merged.QualityStatus = existing.QualityStatus
+			merged.QualityScore = existing.QualityScore
+			merged.QualityGrade = existing.QualityGrade
+			merged.QualitySummary = existing.QualitySummary
+			merged.QualityCheckedAt = existing.QualityCheckedAt
+			merged.QualityCFRay = existing.QualityCFRay
+		}
+	}
+	if err := s.latencyCache.SetProxyLatency(ctx, proxyID, &merged); err != nil {
+		logger.LegacyPrintf("service.proxy_pool", "[ProxyPool] save latency failed: proxy=%d err=%v", proxyID, err)
+	}
+}
+
+// ========== 管理接口 ==========
+
+func (s *ProxyPoolService) List(ctx context.Context, params pagination.PaginationParams, search string) ([]ProxyPool, *pagination.PaginationResult, error) {
+	return s.repo.List(ctx, params, strings.TrimSpace(search))
+}
+
+func (s *ProxyPoolService) Get(ctx context.Context, id int64) (*ProxyPool, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ProxyPoolService) Create(ctx context.Context, input CreateProxyPoolInput) (*ProxyPool, error) {
+	name := strings.TrimSpace(input.Name)
+	if name == "" {
+		return nil, ErrProxyPoolInvalid
+	}
+	proxyIDs, err := s.validateProxyIDs(ctx, input.ProxyIDs)
+	if err != nil {
+		return nil, err
+	}
+	pool := &ProxyPool{Name: name, Description: strings.TrimSpace(input.Description), Status: StatusActive}
+	if err := s.repo.Create(ctx, pool); err != nil {
+		return nil, err
+	}
+	if len(proxyIDs) > 0 {
+		if err := s.repo.SetMembers(ctx, pool.ID, proxyIDs); err != nil {
+			return nil, err
+		}
+	}
+	s.refreshAsync()
+	return s.repo.GetByID(ctx, pool.ID)
+}
+
+func (s *ProxyPoolService) Update(ctx context.Context, id int64, input UpdateProxyPoolInput) (*ProxyPool, error) {
+	pool, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if input.Name != nil {
+		name := strings.TrimSpace(*input.Name)
+		if name == "" {
+			return nil, ErrProxyPoolInvalid
+		}
+		pool.Name = name
+	}
+	if input.Description != nil {
+		pool.Description = strings.TrimSpace(*input.Description)
+	}
+	if input.Status != nil {
+		switch *input.Status {
+		case StatusActive, StatusDisabled:
+			pool.Status = *input.Status
+		default:
+			return nil, ErrProxyPoolInvalid
+		}
+	}
+	if err := s.repo.Update(ctx, pool); err != nil {
+		return nil, err
+	}
+	if input.ProxyIDs != nil {
+		proxyIDs, err := s.validateProxyIDs(ctx, *input.ProxyIDs)
+		if err != nil {
+			return nil, err
+		}
+		if err := s.repo.SetMembers(ctx, id, proxyIDs); err != nil {
+			return nil, err
+		}
+	}
+	s.refreshAsync()
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ProxyPoolService) Delete(ctx context.Context, id int64) error {
+	if err := s.repo.Delete(ctx, id); err != nil {
+		return err
+	}
+	s.refreshAsync()
+	return nil
+}
+
+// AssignAccounts 绑定（poolID 非空）或解绑（poolID 为空）账号
+func (s *ProxyPoolService) AssignAccounts(ctx context.Context, poolID *int64, accountIDs []int64) (int64, error) {
+	if len(accountIDs) == 0 {
+		return 0, ErrProxyPoolInvalid
+	}
+	if poolID != nil {
+		if _, err := s.repo.GetByID(ctx, *poolID); err != nil {
+			return 0, err
+		}
+	}
+	affected, err := s.repo.AssignAccounts(ctx, poolID, accountIDs)
+	if err != nil {
+		return 0, err
+	}
+	s.refreshAsync()
+	return affected, nil
+}
+
+// CheckNow 立即执行一次健康检查并返回最新的代理池状态
+func (s *ProxyPoolService) CheckNow(ctx context.Context, id int64) (*ProxyPool, error) {
+	if _, err := s.repo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+	if s.enabled() {
+		if err := s.Refresh(ctx); err != nil {
+			return nil, err
+		}
+		s.CheckHealth(ctx)
+	}
+	return s.repo.GetByID(ctx, id)
+}
+
+func (s *ProxyPoolService) validateProxyIDs(ctx context.Context, ids []int64) ([]int64, error) {
+	seen := make(map[int64]struct{}, len(ids))
+	out := make([]int64, 0, len(ids))
+	for _, id := range ids {
+		if id <= 0 {
+			return nil, ErrProxyPoolInvalid
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+		out = append(out, id)
+	}
+	if len(out) == 0 || s.proxyRepo == nil {
+		return out, nil
+	}
+	proxies, err := s.proxyRepo.ListByIDs(ctx, out)
+	if err != nil {
+		return nil, err
+	}
+	if len(proxies) != len(out) {
+		return nil, ErrProxyNotFound
+	}
+	return out, nil
+}
+
+// refreshAsync 管理操作后尽快让运行时状态生效，不阻塞请求
+func (s *ProxyPoolService) refreshAsync() {
+	if !s.enabled() {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), proxyPoolPersistTimeout)
+		defer cancel()
+		if err := s.Refresh(ctx); err != nil {
+			logger.LegacyPrintf("service.proxy_pool", "[ProxyPool] refresh failed: %v", err)
+		}
+	}()
+}
@@ -0,0 +1,291 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+type proxyPoolRepoStub struct {
+	ProxyPoolRepository
+
+	mu       sync.Mutex
+	pools    []ProxyPool
+	bindings map[int64]int64
+	health   []ProxyPoolMemberHealth
+}
+
+func (r *proxyPoolRepoStub) ListAll(context.Context) ([]ProxyPool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return append([]ProxyPool(nil), r.pools...), nil
+}
+
+func (r *proxyPoolRepoStub) ListAccountBindings(context.Context) (map[int64]int64, error) {
+	out := make(map[int64]int64, len(r.bindings))
+	for k, v := range r.bindings {
+		out[k] = v
+	}
+	return out, nil
+}
+
+func (r *proxyPoolRepoStub) UpdateMemberHealth(_ context.Context, h ProxyPoolMemberHealth) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.health = append(r.health, h)
+	return nil
+}
+
+type proxyProberStub struct {
+	mu     sync.Mutex
+	failed map[string]bool
+}
+
+func (p *proxyProberStub) ProbeProxy(_ context.Context, proxyURL string) (*ProxyExitInfo, int64, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	if p.failed[proxyURL] {
+		return nil, 0, errors.New("dial tcp: connection refused")
+	}
+	return &ProxyExitInfo{IP: "203.0.113.1"}, 42, nil
+}
+
+type proxyLatencyCacheStub struct {
+	mu   sync.Mutex
+	info map[int64]*ProxyLatencyInfo
+}
+
+func (c *proxyLatencyCacheStub) GetProxyLatencies(_ context.Context, ids []int64) (map[int64]*ProxyLatencyInfo, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	out := map[int64]*ProxyLatencyInfo{}
+	for _, id := range ids {
+		if v, ok := c.info[id]; ok {
+			out[id] = v
+		}
+	}
+	return out, nil
+}
+
+func (c *proxyLatencyCacheStub) SetProxyLatency(_ context.Context, id int64, info *ProxyLatencyInfo) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.info[id] = info
+	return nil
+}
+
+func testPoolProxy(id int64) *Proxy {
+	return &Proxy{ID: id, Protocol: "http", Host: fmt.Sprintf("10.0.0.%d", id), Port: 8080, Status: StatusActive}
+}
+
+func newTestProxyPoolService(t *testing.T, memberIDs ...int64) (*ProxyPoolService, *proxyPoolRepoStub, *proxyProberStub, *proxyLatencyCacheStub) {
+	t.Helper()
+	members := make([]ProxyPoolMember, 0, len(memberIDs))
+	for _, id := range memberIDs {
+		members = append(members, ProxyPoolMember{PoolID: 1, ProxyID: id, Proxy: testPoolProxy(id), Healthy: true})
+	}
+	repo := &proxyPoolRepoStub{
+		pools:    []ProxyPool{{ID: 1, Name: "pool", Status: StatusActive, Members: members}},
+		bindings: map[int64]int64{100: 1, 101: 1},
+	}
+	prober := &proxyProberStub{failed: map[string]bool{}}
+	cache := &proxyLatencyCacheStub{info: map[int64]*ProxyLatencyInfo{}}
+	cfg := &config.Config{ProxyPool: config.ProxyPoolConfig{
+		Enabled:              true,
+		CheckIntervalSeconds: 60,
+		ProbeTimeoutSeconds:  5,
+		ProbeConcurrency:     2,
+		FailureThreshold:     2,
+	}}
+	svc := NewProxyPoolService(repo, nil, prober, cache, cfg)
+	require.NoError(t, svc.Refresh(context.Background()))
+	return svc, repo, prober, cache
+}
+
+func TestProxyPoolService_SelectIsStickyPerAccount(t *testing.T) {
+	svc, _, _, _ := newTestProxyPoolService(t, 1, 2, 3)
+
+	first, pooled, err := svc.Select(100)
+	require.NoError(t, err)
+	require.True(t, pooled)
+	require.Equal(t, int64(1), first.PoolID)
+	for i := 0; i < 5; i++ {
+		again, _, err := svc.Select(100)
+		require.NoError(t, err)
+		require.Equal(t, first.ProxyID, again.ProxyID)
+		require.Equal(t, testPoolProxy(first.ProxyID).URL(), again.ProxyURL)
+	}
+
+	_, pooled, err = svc.Select(999)
+	require.NoError(t, err)
+	require.False(t, pooled, "accounts without a pool are not handled")
+}
+
+func TestProxyPoolService_ReportFailureFailsOverAndStaysSticky(t *testing.T) {
+	svc, repo, _, _ := newTestProxyPoolService(t, 1, 2)
+
+	first, _, err := svc.Select(100)
+	require.NoError(t, err)
+	svc.ReportFailure(100, first, errors.New("proxyconnect tcp: connection refused"))
+
+	next, _, err := svc.Select(100)
+	require.NoError(t, err)
+	require.NotEqual(t, first.ProxyID, next.ProxyID)
+
+	require.Eventually(t, func() bool {
+		repo.mu.Lock()
+		defer repo.mu.Unlock()
+		return len(repo.health) == 1 && repo.health[0].ProxyID == first.ProxyID && !repo.health[0].Healthy
+	}, time.Second, 10*time.Millisecond)
+
+	// 原成员恢复后不回切，保持出口稳定
+	repo.mu.Lock()
+	for i := range repo.pools[0].Members {
+		repo.pools[0].Members[i].Healthy = true
+	}
+	repo.mu.Unlock()
+	require.NoError(t, svc.Refresh(context.Background()))
+	after, _, err := svc.Select(100)
+	require.NoError(t, err)
+	require.Equal(t, next.ProxyID, after.ProxyID)
+}
+
+func TestProxyPoolService_AllUnhealthyKeepsBinding(t *testing.T) {
+	svc, _, _, _ := newTestProxyPoolService(t, 1)
+
+	first, _, err := svc.Select(100)
+	require.NoError(t, err)
+	svc.ReportFailure(100, first, errors.New("proxyconnect tcp: timeout"))
+
+	again, pooled, err := svc.Select(100)
+	require.NoError(t, err)
+	require.True(t, pooled)
+	require.Equal(t, first.ProxyID, again.ProxyID, "never fall back to a direct connection")
+}
+
+func TestProxyPoolService_CheckHealthUsesFailureThreshold(t *testing.T) {
+	svc, repo, prober, cache := newTestProxyPoolService(t, 1, 2)
+	prober.failed[testPoolProxy(1).URL()] = true
+
+	svc.CheckHealth(context.Background())
+	require.True(t, svc.pools[1].members[0].healthy, "a single failed probe stays below the threshold")
+	svc.CheckHealth(context.Background())
+	require.False(t, svc.pools[1].members[0].healthy)
+	require.True(t, svc.pools[1].members[1].healthy)
+
+	require.False(t, cache.info[1].Success)
+	require.True(t, cache.info[2].Success)
+	require.Equal(t, "203.0.113.1", cache.info[2].IPAddress)
+
+	// 不健康成员不会被新账号选中
+	for _, accountID := range []int64{100, 101} {
+		sel, _, err := svc.Select(accountID)
+		require.NoError(t, err)
+		require.Equal(t, int64(2), sel.ProxyID)
+	}
+
+	delete(prober.failed, testPoolProxy(1).URL())
+	svc.CheckHealth(context.Background())
+	require.True(t, svc.pools[1].members[0].healthy)
+	require.Equal(t, 0, svc.pools[1].members[0].failCount)
+	require.NotEmpty(t, repo.health)
+}
+
+func TestProxyPoolService_DisabledPoolIsIgnored(t *testing.T) {
+	svc, repo, _, _ := newTestProxyPoolService(t, 1)
+	repo.pools[0].Status = StatusDisabled
+	require.NoError(t, svc.Refresh(context.Background()))
+
+	_, pooled, err := svc.Select(100)
+	require.NoError(t, err)
+	require.False(t, pooled)
+}
+
+type recordingUpstream struct {
+	mu      sync.Mutex
+	proxies []string
+	fail    map[string]error
+	bodies  []string
+}
+
+func (u *recordingUpstream) Do(req *http.Request, proxyURL string, _ int64, _ int) (*http.Response, error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	u.proxies = append(u.proxies, proxyURL)
+	if req.Body != nil {
+		b, _ := io.ReadAll(req.Body)
+		u.bodies = append(u.bodies, string(b))
+	}
+	if err := u.fail[proxyURL]; err != nil {
+		return nil, err
+	}
+	return &http.Response{StatusCode: http.StatusOK, Body: io.NopCloser(strings.NewReader("ok"))}, nil
+}
+
+func (u *recordingUpstream) DoWithTLS(req *http.Request, proxyURL string, accountID int64, concurrency int, _ bool) (*http.Response, error) {
+	return u.Do(req, proxyURL, accountID, concurrency)
+}
+
+func TestProxyPoolUpstream_OverridesProxyAndRetriesOnProxyError(t *testing.T) {
+	svc, _, _, _ := newTestProxyPoolService(t, 1, 2)
+	first, _, err := svc.Select(100)
+	require.NoError(t, err)
+
+	inner := &recordingUpstream{fail: map[string]error{first.ProxyURL: errors.New(`Post "https://api.example.com": proxyconnect tcp: dial tcp 10.0.0.1:8080: connect: connection refused`)}}
+	up := NewProxyPoolUpstream(inner, svc)
+
+	req, err := http.NewRequest(http.MethodPost, "https://api.example.com/v1/messages", strings.NewReader(`{"a":1}`))
+	require.NoError(t, err)
+	resp, err := up.Do(req, "http://account-proxy:1", 100, 1)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+
+	require.Len(t, inner.proxies, 2)
+	require.Equal(t, first.ProxyURL, inner.proxies[0])
+	require.NotEqual(t, first.ProxyURL, inner.proxies[1])
+	require.NotEqual(t, "http://account-proxy:1", inner.proxies[1], "pool takes precedence over proxy_id")
+	require.Equal(t, []string{`{"a":1}`, `{"a":1}`}, inner.bodies, "retry replays the request body")
+}
+
+func TestProxyPoolUpstream_PassThroughAndUpstreamErrors(t *testing.T) {
+	svc, _, _, _ := newTestProxyPoolService(t, 1, 2)
+	first, _, err := svc.Select(100)
+	require.NoError(t, err)
+
+	inner := &recordingUpstream{fail: map[string]error{first.ProxyURL: errors.New("read: connection reset by peer")}}
+	up := NewProxyPoolUpstream(inner, svc)
+
+	// 未绑定代理池的账号原样透传
+	req, _ := http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+	resp, err := up.DoWithTLS(req, "socks5://direct:1", 999, 1, true)
+	require.NoError(t, err)
+	_ = resp.Body.Close()
+	require.Equal(t, "socks5://direct:1", inner.proxies[0])
+
+	// 非代理连接错误不触发切换
+	req, _ = http.NewRequest(http.MethodGet, "https://api.example.com", nil)
+	_, err = up.Do(req, "", 100, 1)
+	require.Error(t, err)
+	require.Len(t, inner.proxies, 2)
+	again, _, _ := svc.Select(100)
+	require.Equal(t, first.ProxyID, again.ProxyID)
+}
+
+func TestIsProxyConnectError(t *testing.T) {
+	require.True(t, isProxyConnectError(errors.New("proxyconnect tcp: dial tcp: i/o timeout")))
+	require.True(t, isProxyConnectError(errors.New("socks connect tcp 1.2.3.4:1080->api:443: unknown error general SOCKS server failure")))
+	require.True(t, isProxyConnectError(errors.New("connect to proxy: dial tcp: connection refused")))
+	require.False(t, isProxyConnectError(errors.New("context canceled")))
+	require.False(t, isProxyConnectError(nil))
+}
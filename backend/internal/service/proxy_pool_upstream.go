@@ -0,0 +1,103 @@
+package service
+
+import (
+	"net/http"
+	"strings"
+)
+
+// proxyPoolUpstream 代理池装饰器：账号绑定代理池时用池中选出的成员替换调用方传入的 proxyURL，
+// 其余账号原样透传。请求链路上出现代理连接错误时上报失败并切换成员，
+// 请求体可重放时用新成员重试一次（此类错误发生在请求到达上游之前，重试是安全的）。
+type proxyPoolUpstream struct {
+	inner HTTPUpstream
+	pools *ProxyPoolService
+}
+
+// NewProxyPoolUpstream 在 inner 外层包装代理池选择；pools 为 nil 时直接返回 inner
+func NewProxyPoolUpstream(inner HTTPUpstream, pools *ProxyPoolService) HTTPUpstream {
+	if pools == nil {
+		return inner
+	}
+	return &proxyPoolUpstream{inner: inner, pools: pools}
+}
+
+func (u *proxyPoolUpstream) Do(req *http.Request, proxyURL string, accountID int64, accountConcurrency int) (*http.Response, error) {
+	return u.do(req, proxyURL, accountID, func(r *http.Request, url string) (*http.Response, error) {
+		return u.inner.Do(r, url, accountID, accountConcurrency)
+	})
+}
+
+func (u *proxyPoolUpstream) DoWithTLS(req *http.Request, proxyURL string, accountID int64, accountConcurrency int, enableTLSFingerprint bool) (*http.Response, error) {
+	return u.do(req, proxyURL, accountID, func(r *http.Request, url string) (*http.Response, error) {
+		return u.inner.DoWithTLS(r, url, accountID, accountConcurrency, enableTLSFingerprint)
+	})
+}
+
+func (u *proxyPoolUpstream) do(req *http.Request, proxyURL string, accountID int64, send func(*http.Request, string) (*http.Response, error)) (*http.Response, error) {
+	selection, pooled, err := u.pools.Select(accountID)
+	if !pooled {
+		return send(req, proxyURL)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	resp, err := send(req, selection.ProxyURL)
+	if err == nil || !isProxyConnectError(err) || req.Context().Err() != nil {
+		return resp, err
+	}
+	u.pools.ReportFailure(accountID, selection, err)
+
+	retryReq, ok := rewindRequest(req)
+	if !ok {
+		return nil, err
+	}
+	next, pooled, selErr := u.pools.Select(accountID)
+	if !pooled || selErr != nil || next.ProxyID == selection.ProxyID {
+		return nil, err
+	}
+	return send(retryReq, next.ProxyURL)
+}
+
+// rewindRequest 构造可重发的请求副本；请求体不可重放时返回 false
+func rewindRequest(req *http.Request) (*http.Request, bool) {
+	if req.Body == nil || req.Body == http.NoBody {
+		return req.Clone(req.Context()), true
+	}
+	if req.GetBody == nil {
+		return nil, false
+	}
+	body, err := req.GetBody()
+	if err != nil {
+		return nil, false
+	}
+	clone := req.Clone(req.Context())
+	clone.Body = body
+	return clone, true
+}
+
+// proxyConnectErrorMarkers 与代理建立连接阶段的错误特征（标准库 Transport、x/net socks 与 TLS 指纹拨号器）
+var proxyConnectErrorMarkers = []string{
+	"proxyconnect",
+	"socks connect",
+	"socks5 connect",
+	"create socks5 dialer",
+	"connect to proxy",
+	"write connect request",
+	"read connect response",
+	"proxy connect failed",
+}
+
+// isProxyConnectError 判断错误是否发生在连接代理阶段（请求尚未到达上游）
+func isProxyConnectError(err error) bool {
+	if err == nil {
+		return false
+	}
+	msg := strings.ToLower(err.Error())
+	for _, marker := range proxyConnectErrorMarkers {
+		if strings.Contains(msg, marker) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,188 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/tidwall/gjson"
+)
+
+// 响应缓存端点：缓存键与 SSE 回放格式按端点区分
+const (
+	ResponseCacheEndpointMessages  = "messages"
+	ResponseCacheEndpointResponses = "responses"
+)
+
+// DefaultResponseCachePriceRatio 缓存命中默认按正常价格的 10% 计费
+const DefaultResponseCachePriceRatio = 0.1
+
+// ResponseCacheHitHeader 命中缓存时附加的响应头
+const ResponseCacheHitHeader = "X-Response-Cache"
+
+var (
+	// ErrResponseCacheMiss 缓存未命中
+	ErrResponseCacheMiss = errors.New("response cache miss")
+	// ErrResponseCacheInvalidSettings 分组缓存配置非法
+	ErrResponseCacheInvalidSettings = infraerrors.BadRequest("RESPONSE_CACHE_INVALID_SETTINGS", "response cache ttl and price ratio must not be negative")
+)
+
+// responseCacheKeyFields 参与缓存键计算的请求字段。
+// 不在列表中的字段（stream、metadata、user、store 等）不影响模型输出，
+// 因此同一请求的流式与非流式调用、不同会话的调用共享同一条缓存。
+var responseCacheKeyFields = map[string][]string{
+	ResponseCacheEndpointMessages: {
+		"model", "system", "messages", "tools", "tool_choice",
+		"max_tokens", "temperature", "top_p", "top_k", "stop_sequences", "thinking",
+	},
+	ResponseCacheEndpointResponses: {
+		"model", "instructions", "input", "tools", "tool_choice", "parallel_tool_calls",
+		"max_output_tokens", "temperature", "top_p", "text", "reasoning", "include", "truncation",
+	},
+}
+
+// responseCacheStatefulFields 引用服务端状态的字段：同样的请求体可能产生不同输出，不可缓存
+var responseCacheStatefulFields = map[string][]string{
+	ResponseCacheEndpointResponses: {"previous_response_id", "conversation", "background", "prompt"},
+}
+
+// ResponseCacheEntry 缓存的完整非流式响应
+type ResponseCacheEntry struct {
+	Endpoint  string      `json:"endpoint"`
+	Model     string      `json:"model"` // 计费模型
+	Body      []byte      `json:"body"`
+	AccountID int64       `json:"account_id"` // 首次生成该响应的账号
+	RequestID string      `json:"request_id,omitempty"`
+	Usage     UsageTokens `json:"usage"`
+	CreatedAt time.Time   `json:"created_at"`
+}
+
+// ResponseCache 响应缓存存储（Redis 实现）
+type ResponseCache interface {
+	// Get 未命中时返回 ErrResponseCacheMiss
+	Get(ctx context.Context, key string) (*ResponseCacheEntry, error)
+	Set(ctx context.Context, key string, entry *ResponseCacheEntry, ttl time.Duration) error
+}
+
+// BuildResponseCacheKey 计算确定性请求的缓存键。
+// 仅显式 temperature=0 且不引用服务端状态的请求可缓存，否则返回 ok=false。
+// 键由分组、端点与规范化后的请求字段哈希组成：对象键排序、数字统一格式，
+// 字符串内容保持原样（精确匹配，不做语义归一化）。
+func BuildResponseCacheKey(groupID int64, endpoint string, body []byte) (string, bool) {
+	fields, ok := responseCacheKeyFields[endpoint]
+	if !ok || !gjson.ValidBytes(body) {
+		return "", false
+	}
+	temperature := gjson.GetBytes(body, "temperature")
+	if temperature.Type != gjson.Number || temperature.Float() != 0 {
+		return "", false
+	}
+	for _, field := range responseCacheStatefulFields[endpoint] {
+		if v := gjson.GetBytes(body, field); v.Exists() && v.Type != gjson.Null && v.Type != gjson.False {
+			return "", false
+		}
+	}
+	if gjson.GetBytes(body, "model").String() == "" {
+		return "", false
+	}
+
+	normalized := make(map[string]any, len(fields))
+	for _, field := range fields {
+		v := gjson.GetBytes(body, field)
+		if !v.Exists() || v.Type == gjson.Null {
+			continue
+		}
+		value, err := normalizeResponseCacheValue([]byte(v.Raw))
+		if err != nil {
+			return "", false
+		}
+		normalized[field] = value
+	}
+	canonical, err := json.Marshal(normalized)
+	if err != nil {
+		return "", false
+	}
+	sum := sha256.Sum256(canonical)
+	return strconv.FormatInt(groupID, 10) + ":" + endpoint + ":" + hex.EncodeToString(sum[:]), true
+}
+
+// normalizeResponseCacheValue 解析 JSON 值并统一数字格式（0 / 0.0 / 0e0 视为相同），
+// 重新序列化时 encoding/json 会对对象键排序。
+func normalizeResponseCacheValue(raw []byte) (any, error) {
+	dec := json.NewDecoder(bytes.NewReader(raw))
+	dec.UseNumber()
+	var v any
+	if err := dec.Decode(&v); err != nil {
+		return nil, err
+	}
+	return canonicalizeResponseCacheNumbers(v), nil
+}
+
+func canonicalizeResponseCacheNumbers(v any) any {
+	switch t := v.(type) {
+	case map[string]any:
+		for k, item := range t {
+			t[k] = canonicalizeResponseCacheNumbers(item)
+		}
+		return t
+	case []any:
+		for i, item := range t {
+			t[i] = canonicalizeResponseCacheNumbers(item)
+		}
+		return t
+	case json.Number:
+		if f, err := t.Float64(); err == nil {
+			return json.Number(strconv.FormatFloat(f, 'g', -1, 64))
+		}
+		return t
+	default:
+		return v
+	}
+}
+
+// isCacheableResponseBody 仅缓存成功完成的响应
+func isCacheableResponseBody(endpoint string, body []byte) bool {
+	if !gjson.ValidBytes(body) {
+		return false
+	}
+	switch endpoint {
+	case ResponseCacheEndpointMessages:
+		return gjson.GetBytes(body, "type").String() == "message"
+	case ResponseCacheEndpointResponses:
+		return gjson.GetBytes(body, "object").String() == "response" &&
+			gjson.GetBytes(body, "status").String() == "completed"
+	default:
+		return false
+	}
+}
+
+// applyResponseCachePriceRatio 按缓存命中比例折算费用
+func applyResponseCachePriceRatio(cost *CostBreakdown, ratio float64) *CostBreakdown {
+	if cost == nil {
+		return &CostBreakdown{}
+	}
+	if ratio < 0 {
+		ratio = 0
+	}
+	return &CostBreakdown{
+		InputCost:         cost.InputCost * ratio,
+		OutputCost:        cost.OutputCost * ratio,
+		CacheCreationCost: cost.CacheCreationCost * ratio,
+		CacheReadCost:     cost.CacheReadCost * ratio,
+		TotalCost:         cost.TotalCost * ratio,
+		ActualCost:        cost.ActualCost * ratio,
+	}
+}
+
+func validateResponseCacheSettings(ttlSeconds int, priceRatio float64) error {
+	if ttlSeconds < 0 || priceRatio < 0 {
+		return ErrResponseCacheInvalidSettings
+	}
+	return nil
+}
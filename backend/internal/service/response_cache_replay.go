@@ -0,0 +1,187 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+
+	"github.com/tidwall/gjson"
+	"github.com/tidwall/sjson"
+)
+
+// RenderResponseCacheSSE 将缓存的完整非流式响应转换为等价的合成 SSE 事件流，
+// 供流式请求命中缓存时回放。整段文本/参数作为单个 delta 下发。
+func RenderResponseCacheSSE(endpoint string, body []byte) ([]byte, error) {
+	switch endpoint {
+	case ResponseCacheEndpointMessages:
+		return renderMessagesReplaySSE(body)
+	case ResponseCacheEndpointResponses:
+		return renderResponsesReplaySSE(body)
+	default:
+		return nil, fmt.Errorf("unsupported response cache endpoint: %s", endpoint)
+	}
+}
+
+type responseCacheSSEWriter struct {
+	buf bytes.Buffer
+	err error
+}
+
+// event 写入一个 SSE 事件：data 为 JSON 模板，sets 为依次覆盖的 path/value（value 为原始 JSON）
+func (w *responseCacheSSEWriter) event(name, data string, sets ...string) {
+	if w.err != nil {
+		return
+	}
+	for i := 0; i+1 < len(sets); i += 2 {
+		data, w.err = sjson.SetRaw(data, sets[i], sets[i+1])
+		if w.err != nil {
+			return
+		}
+	}
+	w.buf.WriteString("event: ")
+	w.buf.WriteString(name)
+	w.buf.WriteString("\ndata: ")
+	w.buf.WriteString(data)
+	w.buf.WriteString("\n\n")
+}
+
+func rawOr(v gjson.Result, fallback string) string {
+	if !v.Exists() {
+		return fallback
+	}
+	return v.Raw
+}
+
+func quoteJSON(s string) string {
+	out, _ := sjson.Set(`{}`, "v", s)
+	return gjson.Get(out, "v").Raw
+}
+
+// renderMessagesReplaySSE 按 Anthropic Messages 流式协议回放：
+// message_start → 各内容块 start/delta/stop → message_delta → message_stop
+func renderMessagesReplaySSE(body []byte) ([]byte, error) {
+	msg := gjson.ParseBytes(body)
+	w := &responseCacheSSEWriter{}
+
+	startMsg, err := sjson.SetRaw(msg.Raw, "content", `[]`)
+	if err != nil {
+		return nil, err
+	}
+	startMsg, _ = sjson.SetRaw(startMsg, "stop_reason", "null")
+	startMsg, _ = sjson.SetRaw(startMsg, "stop_sequence", "null")
+	startMsg, _ = sjson.SetRaw(startMsg, "usage.output_tokens", "0")
+	w.event("message_start", `{"type":"message_start"}`, "message", startMsg)
+
+	for i, block := range msg.Get("content").Array() {
+		index := fmt.Sprint(i)
+		blockType := block.Get("type").String()
+		switch blockType {
+		case "text":
+			w.event("content_block_start", `{"type":"content_block_start"}`,
+				"index", index, "content_block", `{"type":"text","text":""}`)
+			w.event("content_block_delta", `{"type":"content_block_delta","delta":{"type":"text_delta"}}`,
+				"index", index, "delta.text", rawOr(block.Get("text"), `""`))
+		case "thinking":
+			w.event("content_block_start", `{"type":"content_block_start"}`,
+				"index", index, "content_block", `{"type":"thinking","thinking":""}`)
+			w.event("content_block_delta", `{"type":"content_block_delta","delta":{"type":"thinking_delta"}}`,
+				"index", index, "delta.thinking", rawOr(block.Get("thinking"), `""`))
+			if sig := block.Get("signature"); sig.Exists() {
+				w.event("content_block_delta", `{"type":"content_block_delta","delta":{"type":"signature_delta"}}`,
+					"index", index, "delta.signature", sig.Raw)
+			}
+		case "tool_use", "server_tool_use":
+			startBlock, _ := sjson.SetRaw(block.Raw, "input", `{}`)
+			w.event("content_block_start", `{"type":"content_block_start"}`,
+				"index", index, "content_block", startBlock)
+			w.event("content_block_delta", `{"type":"content_block_delta","delta":{"type":"input_json_delta"}}`,
+				"index", index, "delta.partial_json", quoteJSON(rawOr(block.Get("input"), `{}`)))
+		default:
+			// redacted_thinking / web_search_tool_result 等块在 start 事件中完整下发
+			w.event("content_block_start", `{"type":"content_block_start"}`,
+				"index", index, "content_block", block.Raw)
+		}
+		w.event("content_block_stop", `{"type":"content_block_stop"}`, "index", index)
+	}
+
+	w.event("message_delta", `{"type":"message_delta","delta":{}}`,
+		"delta.stop_reason", rawOr(msg.Get("stop_reason"), "null"),
+		"delta.stop_sequence", rawOr(msg.Get("stop_sequence"), "null"),
+		"usage", rawOr(msg.Get("usage"), `{}`))
+	w.event("message_stop", `{"type":"message_stop"}`)
+	return w.buf.Bytes(), w.err
+}
+
+// renderResponsesReplaySSE 按 OpenAI Responses 流式协议回放：
+// response.created → 各输出项 added/delta/done → response.completed
+func renderResponsesReplaySSE(body []byte) ([]byte, error) {
+	resp := gjson.ParseBytes(body)
+	w := &responseCacheSSEWriter{}
+	seq := 0
+	next := func() string {
+		s := fmt.Sprint(seq)
+		seq++
+		return s
+	}
+
+	pending, err := sjson.SetRaw(resp.Raw, "output", `[]`)
+	if err != nil {
+		return nil, err
+	}
+	pending, _ = sjson.Set(pending, "status", "in_progress")
+	pending, _ = sjson.SetRaw(pending, "usage", "null")
+	w.event("response.created", `{"type":"response.created"}`, "sequence_number", next(), "response", pending)
+	w.event("response.in_progress", `{"type":"response.in_progress"}`, "sequence_number", next(), "response", pending)
+
+	for i, item := range resp.Get("output").Array() {
+		outputIndex := fmt.Sprint(i)
+		itemID := rawOr(item.Get("id"), `""`)
+		switch item.Get("type").String() {
+		case "message":
+			added, _ := sjson.SetRaw(item.Raw, "content", `[]`)
+			added, _ = sjson.Set(added, "status", "in_progress")
+			w.event("response.output_item.added", `{"type":"response.output_item.added"}`,
+				"sequence_number", next(), "output_index", outputIndex, "item", added)
+			for j, part := range item.Get("content").Array() {
+				contentIndex := fmt.Sprint(j)
+				emptyPart := part.Raw
+				if part.Get("type").String() == "output_text" {
+					emptyPart, _ = sjson.Set(part.Raw, "text", "")
+				}
+				w.event("response.content_part.added", `{"type":"response.content_part.added"}`,
+					"sequence_number", next(), "item_id", itemID, "output_index", outputIndex,
+					"content_index", contentIndex, "part", emptyPart)
+				if part.Get("type").String() == "output_text" {
+					text := rawOr(part.Get("text"), `""`)
+					w.event("response.output_text.delta", `{"type":"response.output_text.delta"}`,
+						"sequence_number", next(), "item_id", itemID, "output_index", outputIndex,
+						"content_index", contentIndex, "delta", text)
+					w.event("response.output_text.done", `{"type":"response.output_text.done"}`,
+						"sequence_number", next(), "item_id", itemID, "output_index", outputIndex,
+						"content_index", contentIndex, "text", text)
+				}
+				w.event("response.content_part.done", `{"type":"response.content_part.done"}`,
+					"sequence_number", next(), "item_id", itemID, "output_index", outputIndex,
+					"content_index", contentIndex, "part", part.Raw)
+			}
+		case "function_call":
+			added, _ := sjson.Set(item.Raw, "arguments", "")
+			added, _ = sjson.Set(added, "status", "in_progress")
+			w.event("response.output_item.added", `{"type":"response.output_item.added"}`,
+				"sequence_number", next(), "output_index", outputIndex, "item", added)
+			args := rawOr(item.Get("arguments"), `""`)
+			w.event("response.function_call_arguments.delta", `{"type":"response.function_call_arguments.delta"}`,
+				"sequence_number", next(), "item_id", itemID, "output_index", outputIndex, "delta", args)
+			w.event("response.function_call_arguments.done", `{"type":"response.function_call_arguments.done"}`,
+				"sequence_number", next(), "item_id", itemID, "output_index", outputIndex, "arguments", args)
+		default:
+			// reasoning 等输出项在 added 事件中完整下发
+			w.event("response.output_item.added", `{"type":"response.output_item.added"}`,
+				"sequence_number", next(), "output_index", outputIndex, "item", item.Raw)
+		}
+		w.event("response.output_item.done", `{"type":"response.output_item.done"}`,
+			"sequence_number", next(), "output_index", outputIndex, "item", item.Raw)
+	}
+
+	w.event("response.completed", `{"type":"response.completed"}`, "sequence_number", next(), "response", resp.Raw)
+	return w.buf.Bytes(), w.err
+}
@@ -0,0 +1,236 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+)
+
+// ResponseCacheService 精确响应缓存：对分组开启缓存后的确定性请求（temperature=0），
+// 缓存完整的非流式响应；命中时直接返回（流式请求回放为合成 SSE），
+// 并按分组配置的命中价格比例记录 BillingTypeResponseCache 使用日志。
+type ResponseCacheService struct {
+	cache               ResponseCache
+	billingService      *BillingService
+	usageLogRepo        UsageLogRepository
+	userRepo            UserRepository
+	userSubRepo         UserSubscriptionRepository
+	billingCacheService *BillingCacheService
+	rateResolver        *userGroupRateResolver
+	cfg                 *config.Config
+}
+
+// NewResponseCacheService 创建响应缓存服务
+func NewResponseCacheService(
+	cache ResponseCache,
+	billingService *BillingService,
+	usageLogRepo UsageLogRepository,
+	userRepo UserRepository,
+	userSubRepo UserSubscriptionRepository,
+	userGroupRateRepo UserGroupRateRepository,
+	billingCacheService *BillingCacheService,
+	cfg *config.Config,
+) *ResponseCacheService {
+	return &ResponseCacheService{
+		cache:               cache,
+		billingService:      billingService,
+		usageLogRepo:        usageLogRepo,
+		userRepo:            userRepo,
+		userSubRepo:         userSubRepo,
+		billingCacheService: billingCacheService,
+		rateResolver:        newUserGroupRateResolver(userGroupRateRepo, nil, resolveUserGroupRateCacheTTL(cfg), nil, "service.response_cache"),
+		cfg:                 cfg,
+	}
+}
+
+// ResponseCacheRequest 一次可缓存请求的上下文
+type ResponseCacheRequest struct {
+	Key      string
+	Endpoint string
+	Group    *Group
+}
+
+// Prepare 判断请求是否可缓存：全局开关、分组开关与请求确定性均满足时返回非 nil
+func (s *ResponseCacheService) Prepare(group *Group, endpoint string, body []byte) *ResponseCacheRequest {
+	if s == nil || s.cache == nil || s.cfg == nil || !s.cfg.ResponseCache.Enabled {
+		return nil
+	}
+	if group == nil || !group.ResponseCacheEnabled {
+		return nil
+	}
+	key, ok := BuildResponseCacheKey(group.ID, endpoint, body)
+	if !ok {
+		return nil
+	}
+	return &ResponseCacheRequest{Key: key, Endpoint: endpoint, Group: group}
+}
+
+// MaxEntryBytes 单条缓存响应体大小上限
+func (s *ResponseCacheService) MaxEntryBytes() int {
+	if s == nil || s.cfg == nil {
+		return 0
+	}
+	return s.cfg.ResponseCache.MaxEntryBytes
+}
+
+// Lookup 查询缓存；未命中或出错时返回 nil（缓存故障不影响正常转发）
+func (s *ResponseCacheService) Lookup(ctx context.Context, req *ResponseCacheRequest) *ResponseCacheEntry {
+	if req == nil {
+		return nil
+	}
+	entry, err := s.cache.Get(ctx, req.Key)
+	if err != nil {
+		if !errors.Is(err, ErrResponseCacheMiss) {
+			logger.L().Warn("response_cache.get_failed", zap.String("key", req.Key), zap.Error(err))
+		}
+		return nil
+	}
+	if entry.Endpoint != req.Endpoint || len(entry.Body) == 0 {
+		return nil
+	}
+	return entry
+}
+
+// Store 写入缓存：仅缓存成功完成、且不超过大小上限的响应
+func (s *ResponseCacheService) Store(ctx context.Context, req *ResponseCacheRequest, entry *ResponseCacheEntry) {
+	if req == nil || entry == nil {
+		return
+	}
+	if len(entry.Body) == 0 || len(entry.Body) > s.MaxEntryBytes() || !isCacheableResponseBody(req.Endpoint, entry.Body) {
+		return
+	}
+	entry.Endpoint = req.Endpoint
+	if entry.CreatedAt.IsZero() {
+		entry.CreatedAt = time.Now()
+	}
+	if err := s.cache.Set(ctx, req.Key, entry, s.ttl(req.Group)); err != nil {
+		logger.L().Warn("response_cache.set_failed", zap.String("key", req.Key), zap.Error(err))
+	}
+}
+
+// ttl 分组 TTL（0 使用全局默认值），不超过全局上限
+func (s *ResponseCacheService) ttl(group *Group) time.Duration {
+	seconds := s.cfg.ResponseCache.DefaultTTLSeconds
+	if group != nil && group.ResponseCacheTTLSeconds > 0 {
+		seconds = group.ResponseCacheTTLSeconds
+	}
+	if max := s.cfg.ResponseCache.MaxTTLSeconds; max > 0 && seconds > max {
+		seconds = max
+	}
+	return time.Duration(seconds) * time.Second
+}
+
+// RecordResponseCacheHitInput 缓存命中使用量记录参数
+type RecordResponseCacheHitInput struct {
+	Entry         *ResponseCacheEntry
+	APIKey        *APIKey
+	User          *User
+	Subscription  *UserSubscription
+	Stream        bool
+	UserAgent     string
+	IPAddress     string
+	APIKeyService APIKeyQuotaUpdater
+}
+
+// RecordHit 记录缓存命中的使用日志并扣费。
+// 费用 = 正常价格 × 分组命中比例；订阅分组的命中不消耗订阅额度（实际费用记 0），
+// 账号侧配额与最近使用时间不更新（未请求上游）。
+func (s *ResponseCacheService) RecordHit(ctx context.Context, input *RecordResponseCacheHitInput) error {
+	entry := input.Entry
+	apiKey := input.APIKey
+	user := input.User
+
+	multiplier := 1.0
+	if s.cfg != nil {
+		multiplier = s.cfg.Default.RateMultiplier
+	}
+	priceRatio := DefaultResponseCachePriceRatio
+	if apiKey.GroupID != nil && apiKey.Group != nil {
+		multiplier = s.rateResolver.Resolve(ctx, user.ID, *apiKey.GroupID, apiKey.Group.RateMultiplier)
+		priceRatio = apiKey.Group.ResponseCachePriceRatio
+	}
+
+	cost, err := s.billingService.CalculateCost(entry.Model, entry.Usage, multiplier)
+	if err != nil {
+		logger.LegacyPrintf("service.response_cache", "Calculate cost failed: %v", err)
+		cost = &CostBreakdown{}
+	}
+	cost = applyResponseCachePriceRatio(cost, priceRatio)
+
+	isSubscriptionGroup := input.Subscription != nil && apiKey.Group != nil && apiKey.Group.IsSubscriptionType()
+	if isSubscriptionGroup {
+		cost.ActualCost = 0
+	}
+
+	// 每次命中使用独立的 request_id，避免 (request_id, api_key_id) 去重吞掉重复命中
+	requestID := "cache-" + uuid.NewString()
+	// 命中不占用上游账号，账号口径费用记 0
+	accountRateMultiplier := 0.0
+	usageLog := &UsageLog{
+		UserID:                user.ID,
+		APIKeyID:              apiKey.ID,
+		AccountID:             entry.AccountID,
+		RequestID:             requestID,
+		Model:                 entry.Model,
+		InputTokens:           entry.Usage.InputTokens,
+		OutputTokens:          entry.Usage.OutputTokens,
+		CacheCreationTokens:   entry.Usage.CacheCreationTokens,
+		CacheReadTokens:       entry.Usage.CacheReadTokens,
+		CacheCreation5mTokens: entry.Usage.CacheCreation5mTokens,
+		CacheCreation1hTokens: entry.Usage.CacheCreation1hTokens,
+		InputCost:             cost.InputCost,
+		OutputCost:            cost.OutputCost,
+		CacheCreationCost:     cost.CacheCreationCost,
+		CacheReadCost:         cost.CacheReadCost,
+		TotalCost:             cost.TotalCost,
+		ActualCost:            cost.ActualCost,
+		RateMultiplier:        multiplier,
+		AccountRateMultiplier: &accountRateMultiplier,
+		BillingType:           BillingTypeResponseCache,
+		Stream:                input.Stream,
+		CreatedAt:             time.Now(),
+	}
+	if input.UserAgent != "" {
+		usageLog.UserAgent = &input.UserAgent
+	}
+	if input.IPAddress != "" {
+		usageLog.IPAddress = &input.IPAddress
+	}
+	if apiKey.GroupID != nil {
+		usageLog.GroupID = apiKey.GroupID
+	}
+	if input.Subscription != nil {
+		usageLog.SubscriptionID = &input.Subscription.ID
+	}
+
+	inserted, err := s.usageLogRepo.Create(ctx, usageLog)
+	if err != nil {
+		logger.LegacyPrintf("service.response_cache", "Create usage log failed: %v", err)
+	}
+	if inserted || err != nil {
+		recordAPIKeyTokenUsage(ctx, input.APIKeyService, apiKey, usageLog)
+	}
+	if s.cfg != nil && s.cfg.RunMode == config.RunModeSimple {
+		return nil
+	}
+	if !inserted && err == nil {
+		return nil
+	}
+
+	postUsageBilling(ctx, &postUsageBillingParams{
+		Cost:          cost,
+		User:          user,
+		APIKey:        apiKey,
+		APIKeyService: input.APIKeyService,
+	}, &billingDeps{
+		userRepo:            s.userRepo,
+		userSubRepo:         s.userSubRepo,
+		billingCacheService: s.billingCacheService,
+	})
+	return nil
+}
@@ -0,0 +1,268 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+	"github.com/tidwall/gjson"
+)
+
+type responseCacheStub struct {
+	entries map[string]*ResponseCacheEntry
+	lastTTL time.Duration
+}
+
+func (s *responseCacheStub) Get(ctx context.Context, key string) (*ResponseCacheEntry, error) {
+	entry, ok := s.entries[key]
+	if !ok {
+		return nil, ErrResponseCacheMiss
+	}
+	return entry, nil
+}
+
+func (s *responseCacheStub) Set(ctx context.Context, key string, entry *ResponseCacheEntry, ttl time.Duration) error {
+	if s.entries == nil {
+		s.entries = make(map[string]*ResponseCacheEntry)
+	}
+	s.entries[key] = entry
+	s.lastTTL = ttl
+	return nil
+}
+
+func newResponseCacheServiceForTest(cache ResponseCache, usageRepo UsageLogRepository, userRepo UserRepository) *ResponseCacheService {
+	cfg := &config.Config{}
+	cfg.Default.RateMultiplier = 1
+	cfg.ResponseCache = config.ResponseCacheConfig{
+		Enabled:           true,
+		DefaultTTLSeconds: 3600,
+		MaxTTLSeconds:     7200,
+		MaxEntryBytes:     1 << 20,
+	}
+	return NewResponseCacheService(cache, NewBillingService(cfg, nil), usageRepo, userRepo, nil, nil, &BillingCacheService{}, cfg)
+}
+
+func TestBuildResponseCacheKey_RequiresZeroTemperature(t *testing.T) {
+	cases := []struct {
+		name string
+		body string
+		ok   bool
+	}{
+		{"zero", `{"model":"claude-sonnet-4","temperature":0,"messages":[]}`, true},
+		{"zero float", `{"model":"claude-sonnet-4","temperature":0.0,"messages":[]}`, true},
+		{"missing", `{"model":"claude-sonnet-4","messages":[]}`, false},
+		{"non zero", `{"model":"claude-sonnet-4","temperature":0.2,"messages":[]}`, false},
+		{"string", `{"model":"claude-sonnet-4","temperature":"0","messages":[]}`, false},
+		{"no model", `{"temperature":0,"messages":[]}`, false},
+		{"invalid json", `{"model":`, false},
+	}
+	for _, tc := range cases {
+		t.Run(tc.name, func(t *testing.T) {
+			_, ok := BuildResponseCacheKey(1, ResponseCacheEndpointMessages, []byte(tc.body))
+			require.Equal(t, tc.ok, ok)
+		})
+	}
+}
+
+func TestBuildResponseCacheKey_RejectsStatefulResponses(t *testing.T) {
+	_, ok := BuildResponseCacheKey(1, ResponseCacheEndpointResponses,
+		[]byte(`{"model":"gpt-5","temperature":0,"input":"hi","previous_response_id":"resp_1"}`))
+	require.False(t, ok)
+
+	_, ok = BuildResponseCacheKey(1, ResponseCacheEndpointResponses,
+		[]byte(`{"model":"gpt-5","temperature":0,"input":"hi","background":false}`))
+	require.True(t, ok)
+}
+
+func TestBuildResponseCacheKey_IgnoresTransportFields(t *testing.T) {
+	a, ok := BuildResponseCacheKey(1, ResponseCacheEndpointMessages,
+		[]byte(`{"model":"claude-sonnet-4","temperature":0,"max_tokens":100,"messages":[{"role":"user","content":"hi"}]}`))
+	require.True(t, ok)
+	b, ok := BuildResponseCacheKey(1, ResponseCacheEndpointMessages,
+		[]byte(`{"stream":true,"metadata":{"user_id":"u1"},"messages":[{"content":"hi","role":"user"}],"max_tokens":1e2,"temperature":0.0,"model":"claude-sonnet-4"}`))
+	require.True(t, ok)
+	require.Equal(t, a, b)
+	require.True(t, strings.HasPrefix(a, "1:messages:"))
+
+	// 内容不同、分组不同均不共享
+	c, _ := BuildResponseCacheKey(1, ResponseCacheEndpointMessages,
+		[]byte(`{"model":"claude-sonnet-4","temperature":0,"max_tokens":100,"messages":[{"role":"user","content":"hi "}]}`))
+	require.NotEqual(t, a, c)
+	d, _ := BuildResponseCacheKey(2, ResponseCacheEndpointMessages,
+		[]byte(`{"model":"claude-sonnet-4","temperature":0,"max_tokens":100,"messages":[{"role":"user","content":"hi"}]}`))
+	require.NotEqual(t, a, d)
+}
+
+func TestResponseCacheService_PrepareRespectsSwitches(t *testing.T) {
+	svc := newResponseCacheServiceForTest(&responseCacheStub{}, nil, nil)
+	body := []byte(`{"model":"claude-sonnet-4","temperature":0,"messages":[]}`)
+
+	require.Nil(t, svc.Prepare(&Group{ID: 1}, ResponseCacheEndpointMessages, body))
+	require.NotNil(t, svc.Prepare(&Group{ID: 1, ResponseCacheEnabled: true}, ResponseCacheEndpointMessages, body))
+
+	svc.cfg.ResponseCache.Enabled = false
+	require.Nil(t, svc.Prepare(&Group{ID: 1, ResponseCacheEnabled: true}, ResponseCacheEndpointMessages, body))
+
+	var nilSvc *ResponseCacheService
+	require.Nil(t, nilSvc.Prepare(&Group{ID: 1, ResponseCacheEnabled: true}, ResponseCacheEndpointMessages, body))
+}
+
+func TestResponseCacheService_StoreAndLookup(t *testing.T) {
+	cache := &responseCacheStub{}
+	svc := newResponseCacheServiceForTest(cache, nil, nil)
+	group := &Group{ID: 1, ResponseCacheEnabled: true, ResponseCacheTTLSeconds: 86400}
+	req := svc.Prepare(group, ResponseCacheEndpointMessages, []byte(`{"model":"claude-sonnet-4","temperature":0,"messages":[]}`))
+	require.NotNil(t, req)
+
+	// 非成功响应不缓存
+	svc.Store(context.Background(), req, &ResponseCacheEntry{Body: []byte(`{"type":"error"}`)})
+	require.Nil(t, svc.Lookup(context.Background(), req))
+
+	svc.Store(context.Background(), req, &ResponseCacheEntry{Model: "claude-sonnet-4", Body: []byte(`{"type":"message","content":[]}`)})
+	entry := svc.Lookup(context.Background(), req)
+	require.NotNil(t, entry)
+	require.Equal(t, ResponseCacheEndpointMessages, entry.Endpoint)
+	// 分组 TTL 超过全局上限时截断
+	require.Equal(t, 7200*time.Second, cache.lastTTL)
+}
+
+func TestApplyResponseCachePriceRatio(t *testing.T) {
+	cost := applyResponseCachePriceRatio(&CostBreakdown{InputCost: 1, OutputCost: 2, TotalCost: 3, ActualCost: 6}, 0.1)
+	require.InDelta(t, 0.1, cost.InputCost, 1e-12)
+	require.InDelta(t, 0.2, cost.OutputCost, 1e-12)
+	require.InDelta(t, 0.3, cost.TotalCost, 1e-12)
+	require.InDelta(t, 0.6, cost.ActualCost, 1e-12)
+
+	require.Zero(t, applyResponseCachePriceRatio(&CostBreakdown{TotalCost: 3, ActualCost: 3}, -1).ActualCost)
+}
+
+func TestResponseCacheService_RecordHitChargesRatio(t *testing.T) {
+	usageRepo := &openAIRecordUsageLogRepoStub{inserted: true}
+	userRepo := &openAIRecordUsageUserRepoStub{}
+	svc := newResponseCacheServiceForTest(&responseCacheStub{}, usageRepo, userRepo)
+
+	groupID := int64(7)
+	apiKey := &APIKey{
+		ID:      10,
+		GroupID: &groupID,
+		Group:   &Group{ID: groupID, RateMultiplier: 1, ResponseCachePriceRatio: 0.5},
+		User:    &User{ID: 3},
+	}
+	entry := &ResponseCacheEntry{
+		Endpoint:  ResponseCacheEndpointMessages,
+		Model:     "claude-sonnet-4",
+		AccountID: 99,
+		Usage:     UsageTokens{InputTokens: 1000, OutputTokens: 500},
+	}
+
+	err := svc.RecordHit(context.Background(), &RecordResponseCacheHitInput{Entry: entry, APIKey: apiKey, User: apiKey.User, Stream: true})
+	require.NoError(t, err)
+	require.Equal(t, 1, usageRepo.calls)
+	require.Equal(t, BillingTypeResponseCache, usageRepo.lastLog.BillingType)
+	require.True(t, strings.HasPrefix(usageRepo.lastLog.RequestID, "cache-"))
+	require.Equal(t, int64(99), usageRepo.lastLog.AccountID)
+	require.True(t, usageRepo.lastLog.Stream)
+
+	expected := (1000*3e-6 + 500*15e-6) * 0.5
+	require.InDelta(t, expected, usageRepo.lastLog.ActualCost, 1e-10)
+	require.Equal(t, 1, userRepo.deductCalls)
+	require.InDelta(t, expected, userRepo.lastAmount, 1e-10)
+}
+
+func TestResponseCacheService_RecordHitSubscriptionIsFree(t *testing.T) {
+	usageRepo := &openAIRecordUsageLogRepoStub{inserted: true}
+	userRepo := &openAIRecordUsageUserRepoStub{}
+	svc := newResponseCacheServiceForTest(&responseCacheStub{}, usageRepo, userRepo)
+
+	groupID := int64(7)
+	apiKey := &APIKey{
+		ID:      10,
+		GroupID: &groupID,
+		Group:   &Group{ID: groupID, RateMultiplier: 1, SubscriptionType: SubscriptionTypeSubscription, ResponseCachePriceRatio: 0.1},
+		User:    &User{ID: 3},
+	}
+	entry := &ResponseCacheEntry{Model: "claude-sonnet-4", Usage: UsageTokens{InputTokens: 1000, OutputTokens: 500}}
+
+	err := svc.RecordHit(context.Background(), &RecordResponseCacheHitInput{
+		Entry:        entry,
+		APIKey:       apiKey,
+		User:         apiKey.User,
+		Subscription: &UserSubscription{ID: 5},
+	})
+	require.NoError(t, err)
+	require.Zero(t, usageRepo.lastLog.ActualCost)
+	require.Greater(t, usageRepo.lastLog.TotalCost, 0.0)
+	require.Equal(t, 0, userRepo.deductCalls)
+}
+
+func TestRenderResponseCacheSSE_Messages(t *testing.T) {
+	body := []byte(`{"id":"msg_1","type":"message","role":"assistant","model":"claude-sonnet-4",` +
+		`"content":[{"type":"text","text":"hello"},{"type":"tool_use","id":"tu_1","name":"f","input":{"a":1}}],` +
+		`"stop_reason":"tool_use","stop_sequence":null,"usage":{"input_tokens":5,"output_tokens":7}}`)
+	out, err := RenderResponseCacheSSE(ResponseCacheEndpointMessages, body)
+	require.NoError(t, err)
+
+	events := parseResponseCacheSSE(t, out)
+	names := make([]string, 0, len(events))
+	for _, ev := range events {
+		names = append(names, ev.name)
+	}
+	require.Equal(t, []string{
+		"message_start",
+		"content_block_start", "content_block_delta", "content_block_stop",
+		"content_block_start", "content_block_delta", "content_block_stop",
+		"message_delta", "message_stop",
+	}, names)
+	require.Equal(t, "[]", gjson.Get(events[0].data, "message.content").Raw)
+	require.Equal(t, "hello", gjson.Get(events[2].data, "delta.text").String())
+	require.JSONEq(t, `{"a":1}`, gjson.Get(events[5].data, "delta.partial_json").String())
+	require.Equal(t, "tool_use", gjson.Get(events[7].data, "delta.stop_reason").String())
+	require.Equal(t, int64(7), gjson.Get(events[7].data, "usage.output_tokens").Int())
+}
+
+func TestRenderResponseCacheSSE_Responses(t *testing.T) {
+	body := []byte(`{"id":"resp_1","object":"response","status":"completed","model":"gpt-5",` +
+		`"output":[{"id":"msg_1","type":"message","role":"assistant","status":"completed",` +
+		`"content":[{"type":"output_text","text":"hi","annotations":[]}]}],"usage":{"input_tokens":3,"output_tokens":2}}`)
+	out, err := RenderResponseCacheSSE(ResponseCacheEndpointResponses, body)
+	require.NoError(t, err)
+
+	events := parseResponseCacheSSE(t, out)
+	require.Equal(t, "response.created", events[0].name)
+	require.Equal(t, "in_progress", gjson.Get(events[0].data, "response.status").String())
+	last := events[len(events)-1]
+	require.Equal(t, "response.completed", last.name)
+	require.Equal(t, "completed", gjson.Get(last.data, "response.status").String())
+	for i, ev := range events {
+		require.Equal(t, int64(i), gjson.Get(ev.data, "sequence_number").Int())
+		if ev.name == "response.output_text.delta" {
+			require.Equal(t, "hi", gjson.Get(ev.data, "delta").String())
+		}
+	}
+
+	_, err = RenderResponseCacheSSE("unknown", body)
+	require.Error(t, err)
+}
+
+type responseCacheSSEEvent struct {
+	name string
+	data string
+}
+
+func parseResponseCacheSSE(t *testing.T, raw []byte) []responseCacheSSEEvent {
+	t.Helper()
+	var events []responseCacheSSEEvent
+	for _, chunk := range strings.Split(strings.TrimSpace(string(raw)), "\n\n") {
+		lines := strings.SplitN(chunk, "\n", 2)
+		require.Len(t, lines, 2)
+		data := strings.TrimPrefix(lines[1], "data: ")
+		require.True(t, gjson.Valid(data), data)
+		events = append(events, responseCacheSSEEvent{name: strings.TrimPrefix(lines[0], "event: "), data: data})
+	}
+	return events
+}
@@ -1,9 +1,12 @@
 package service
 
-import "context"
+import (
+	"context"
+	"time"
+)
 
 // RPMCache RPM 计数器缓存接口
-// 用于 Anthropic OAuth/SetupToken 账号的每分钟请求数限制
+// 用于 Anthropic OAuth/SetupToken 账号的每分钟请求数限制，以及 API Key 级 RPM/TPM 限制
 type RPMCache interface {
 	// IncrementRPM 原子递增并返回当前分钟的计数
 	// 使用 Redis 服务器时间确定 minute key，避免多实例时钟偏差
@@ -14,4 +17,13 @@ type RPMCache interface {
 
 	// GetRPMBatch 批量获取多个账号的 RPM 计数（使用 Pipeline）
 	GetRPMBatch(ctx context.Context, accountIDs []int64) (map[int64]int, error)
+
+	// IncrementAPIKeyRPM 原子递增 API Key 当前分钟的请求计数，resetIn 为距下一分钟的剩余时间
+	IncrementAPIKeyRPM(ctx context.Context, apiKeyID int64) (count int, resetIn time.Duration, err error)
+
+	// AddAPIKeyTokens 累加 API Key 当前分钟的 Token 用量
+	AddAPIKeyTokens(ctx context.Context, apiKeyID int64, tokens int) error
+
+	// GetAPIKeyTokens 获取 API Key 当前分钟的 Token 用量，resetIn 为距下一分钟的剩余时间
+	GetAPIKeyTokens(ctx context.Context, apiKeyID int64) (tokens int, resetIn time.Duration, err error)
 }
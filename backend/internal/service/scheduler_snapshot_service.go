@@ -7,6 +7,7 @@ import (
 	"log/slog"
 	"strconv"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
@@ -32,6 +33,9 @@ type SchedulerSnapshotService struct {
 	fallbackLimit *fallbackLimiter
 	lagMu         sync.Mutex
 	lagFailures   int
+
+	// outboxLagMs 最近一次轮询时最旧未处理事件的滞后（毫秒），无积压时为 0，用于指标导出。
+	outboxLagMs atomic.Int64
 }
 
 func NewSchedulerSnapshotService(
@@ -232,6 +236,7 @@ func (s *SchedulerSnapshotService) pollOutbox() {
 		return
 	}
 	if len(events) == 0 {
+		s.outboxLagMs.Store(0)
 		return
 	}
 
@@ -543,12 +548,21 @@ func (s *SchedulerSnapshotService) triggerFullRebuild(reason string) error {
 	return s.rebuildBuckets(ctx, buckets, reason)
 }
 
+// OutboxLag returns the lag of the oldest outbox event seen by the last poll.
+func (s *SchedulerSnapshotService) OutboxLag() time.Duration {
+	if s == nil {
+		return 0
+	}
+	return time.Duration(s.outboxLagMs.Load()) * time.Millisecond
+}
+
 func (s *SchedulerSnapshotService) checkOutboxLag(ctx context.Context, oldest SchedulerOutboxEvent, watermark int64) {
 	if oldest.CreatedAt.IsZero() || s.cfg == nil {
 		return
 	}
 
 	lag := time.Since(oldest.CreatedAt)
+	s.outboxLagMs.Store(lag.Milliseconds())
 	if lagSeconds := int(lag.Seconds()); lagSeconds >= s.cfg.Gateway.Scheduling.OutboxLagWarnSeconds && s.cfg.Gateway.Scheduling.OutboxLagWarnSeconds > 0 {
 		logger.LegacyPrintf("service.scheduler_snapshot", "[Scheduler] outbox lag warning: %ds", lagSeconds)
 	}
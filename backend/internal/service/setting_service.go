@@ -174,6 +174,7 @@ func (s *SettingService) GetPublicSettings(ctx context.Context) (*PublicSettings
 		SoraClientEnabled:                settings[SettingKeySoraClientEnabled] == "true",
 		CustomMenuItems:                  settings[SettingKeyCustomMenuItems],
 		LinuxDoOAuthEnabled:              linuxDoEnabled,
+		SSOProviders:                     enabledSSOProviders(s.cfg),
 	}, nil
 }
 
@@ -227,6 +228,8 @@ func (s *SettingService) GetPublicSettingsForInjection(ctx context.Context) (any
 		CustomMenuItems                  json.RawMessage `json:"custom_menu_items"`
 		LinuxDoOAuthEnabled              bool            `json:"linuxdo_oauth_enabled"`
 		Version                          string          `json:"version,omitempty"`
+
+		SSOProviders []SSOProviderInfo `json:"sso_providers"`
 	}{
 		RegistrationEnabled:              settings.RegistrationEnabled,
 		EmailVerifyEnabled:               settings.EmailVerifyEnabled,
@@ -252,6 +255,7 @@ func (s *SettingService) GetPublicSettingsForInjection(ctx context.Context) (any
 		CustomMenuItems:                  filterUserVisibleMenuItems(settings.CustomMenuItems),
 		LinuxDoOAuthEnabled:              settings.LinuxDoOAuthEnabled,
 		Version:                          s.version,
+		SSOProviders:                     settings.SSOProviders,
 	}, nil
 }
 
@@ -440,6 +444,38 @@ func (s *SettingService) UpdateSettings(ctx context.Context, settings *SystemSet
 	updates[SettingKeyXunhuPayNotifyURL] = strings.TrimSpace(settings.XunhuPayNotifyURL)
 	updates[SettingKeyXunhuPayReturnURL] = strings.TrimSpace(settings.XunhuPayReturnURL)
 	updates[SettingKeyXunhuPayPlugins] = strings.TrimSpace(settings.XunhuPayPlugins)
+	if settings.StripeSecretKey != "" {
+		updates[SettingKeyStripeSecretKey] = settings.StripeSecretKey
+	}
+	if settings.StripeWebhookSecret != "" {
+		updates[SettingKeyStripeWebhookSecret] = settings.StripeWebhookSecret
+	}
+	updates[SettingKeyStripeCurrency] = strings.ToLower(strings.TrimSpace(settings.StripeCurrency))
+	updates[SettingKeyStripeSuccessURL] = strings.TrimSpace(settings.StripeSuccessURL)
+	updates[SettingKeyStripeCancelURL] = strings.TrimSpace(settings.StripeCancelURL)
+	updates[SettingKeyAlipayAppID] = strings.TrimSpace(settings.AlipayAppID)
+	if settings.AlipayPrivateKey != "" {
+		updates[SettingKeyAlipayPrivateKey] = settings.AlipayPrivateKey
+	}
+	updates[SettingKeyAlipayPublicKey] = strings.TrimSpace(settings.AlipayPublicKey)
+	updates[SettingKeyAlipayGateway] = strings.TrimSpace(settings.AlipayGateway)
+	updates[SettingKeyAlipayNotifyURL] = strings.TrimSpace(settings.AlipayNotifyURL)
+	updates[SettingKeyAlipayReturnURL] = strings.TrimSpace(settings.AlipayReturnURL)
+	updates[SettingKeyWeChatPayAppID] = strings.TrimSpace(settings.WeChatPayAppID)
+	updates[SettingKeyWeChatPayMchID] = strings.TrimSpace(settings.WeChatPayMchID)
+	updates[SettingKeyWeChatPaySerialNo] = strings.TrimSpace(settings.WeChatPaySerialNo)
+	if settings.WeChatPayPrivateKey != "" {
+		updates[SettingKeyWeChatPayPrivateKey] = settings.WeChatPayPrivateKey
+	}
+	if settings.WeChatPayAPIv3Key != "" {
+		updates[SettingKeyWeChatPayAPIv3Key] = settings.WeChatPayAPIv3Key
+	}
+	updates[SettingKeyWeChatPayPlatformPublicKey] = strings.TrimSpace(settings.WeChatPayPlatformPublicKey)
+	updates[SettingKeyWeChatPayNotifyURL] = strings.TrimSpace(settings.WeChatPayNotifyURL)
+	updates[SettingKeyBalanceTopUpEnabled] = strconv.FormatBool(settings.BalanceTopUpEnabled)
+	updates[SettingKeyBalanceTopUpMinAmount] = strconv.FormatFloat(settings.BalanceTopUpMinAmount, 'f', 2, 64)
+	updates[SettingKeyBalanceTopUpMaxAmount] = strconv.FormatFloat(settings.BalanceTopUpMaxAmount, 'f', 2, 64)
+	updates[SettingKeyBalanceTopUpRate] = strconv.FormatFloat(settings.BalanceTopUpRate, 'f', 8, 64)
 
 	// 默认配置
 	updates[SettingKeyDefaultConcurrency] = strconv.Itoa(settings.DefaultConcurrency)
@@ -674,6 +710,12 @@ func (s *SettingService) InitializeDefaultSettings(ctx context.Context) error {
 		SettingKeyXunhuPayNotifyURL:                "",
 		SettingKeyXunhuPayReturnURL:                "",
 		SettingKeyXunhuPayPlugins:                  "",
+		SettingKeyStripeCurrency:                   defaultStripeCurrency,
+		SettingKeyAlipayGateway:                    defaultAlipayGateway,
+		SettingKeyBalanceTopUpEnabled:              "false",
+		SettingKeyBalanceTopUpMinAmount:            "1.00",
+		SettingKeyBalanceTopUpMaxAmount:            "0.00",
+		SettingKeyBalanceTopUpRate:                 "1",
 		SettingKeySoraClientEnabled:                "false",
 		SettingKeyCustomMenuItems:                  "[]",
 		SettingKeyDefaultConcurrency:               strconv.Itoa(s.cfg.Default.UserConcurrency),
@@ -744,6 +786,20 @@ func (s *SettingService) parseSettings(settings map[string]string) *SystemSettin
 		XunhuPayNotifyURL:                strings.TrimSpace(settings[SettingKeyXunhuPayNotifyURL]),
 		XunhuPayReturnURL:                strings.TrimSpace(settings[SettingKeyXunhuPayReturnURL]),
 		XunhuPayPlugins:                  strings.TrimSpace(settings[SettingKeyXunhuPayPlugins]),
+		StripeCurrency:                   strings.ToLower(s.getStringOrDefault(settings, SettingKeyStripeCurrency, defaultStripeCurrency)),
+		StripeSuccessURL:                 strings.TrimSpace(settings[SettingKeyStripeSuccessURL]),
+		StripeCancelURL:                  strings.TrimSpace(settings[SettingKeyStripeCancelURL]),
+		AlipayAppID:                      strings.TrimSpace(settings[SettingKeyAlipayAppID]),
+		AlipayPublicKey:                  strings.TrimSpace(settings[SettingKeyAlipayPublicKey]),
+		AlipayGateway:                    s.getStringOrDefault(settings, SettingKeyAlipayGateway, defaultAlipayGateway),
+		AlipayNotifyURL:                  strings.TrimSpace(settings[SettingKeyAlipayNotifyURL]),
+		AlipayReturnURL:                  strings.TrimSpace(settings[SettingKeyAlipayReturnURL]),
+		WeChatPayAppID:                   strings.TrimSpace(settings[SettingKeyWeChatPayAppID]),
+		WeChatPayMchID:                   strings.TrimSpace(settings[SettingKeyWeChatPayMchID]),
+		WeChatPaySerialNo:                strings.TrimSpace(settings[SettingKeyWeChatPaySerialNo]),
+		WeChatPayPlatformPublicKey:       strings.TrimSpace(settings[SettingKeyWeChatPayPlatformPublicKey]),
+		WeChatPayNotifyURL:               strings.TrimSpace(settings[SettingKeyWeChatPayNotifyURL]),
+		BalanceTopUpEnabled:              settings[SettingKeyBalanceTopUpEnabled] == "true",
 		SoraClientEnabled:                settings[SettingKeySoraClientEnabled] == "true",
 		CustomMenuItems:                  settings[SettingKeyCustomMenuItems],
 	}
@@ -768,6 +824,19 @@ func (s *SettingService) parseSettings(settings map[string]string) *SystemSettin
 		result.DefaultBalance = s.cfg.Default.UserBalance
 	}
 	result.DefaultSubscriptions = parseDefaultSubscriptions(settings[SettingKeyDefaultSubscriptions])
+	if v, err := strconv.ParseFloat(settings[SettingKeyBalanceTopUpMinAmount], 64); err == nil && v >= 0 {
+		result.BalanceTopUpMinAmount = v
+	} else {
+		result.BalanceTopUpMinAmount = 1
+	}
+	if v, err := strconv.ParseFloat(settings[SettingKeyBalanceTopUpMaxAmount], 64); err == nil && v >= 0 {
+		result.BalanceTopUpMaxAmount = v
+	}
+	if v, err := strconv.ParseFloat(settings[SettingKeyBalanceTopUpRate], 64); err == nil && v > 0 {
+		result.BalanceTopUpRate = v
+	} else {
+		result.BalanceTopUpRate = 1
+	}
 
 	// 敏感信息直接返回，方便测试连接时使用
 	result.SMTPPassword = settings[SettingKeySMTPPassword]
@@ -775,6 +844,16 @@ func (s *SettingService) parseSettings(settings map[string]string) *SystemSettin
 
 	result.XunhuPayAppSecret = strings.TrimSpace(settings[SettingKeyXunhuPayAppSecret])
 	result.XunhuPayAppSecretConfigured = result.XunhuPayAppSecret != ""
+	result.StripeSecretKey = strings.TrimSpace(settings[SettingKeyStripeSecretKey])
+	result.StripeSecretKeyConfigured = result.StripeSecretKey != ""
+	result.StripeWebhookSecret = strings.TrimSpace(settings[SettingKeyStripeWebhookSecret])
+	result.StripeWebhookSecretConfigured = result.StripeWebhookSecret != ""
+	result.AlipayPrivateKey = strings.TrimSpace(settings[SettingKeyAlipayPrivateKey])
+	result.AlipayPrivateKeyConfigured = result.AlipayPrivateKey != ""
+	result.WeChatPayPrivateKey = strings.TrimSpace(settings[SettingKeyWeChatPayPrivateKey])
+	result.WeChatPayPrivateKeyConfigured = result.WeChatPayPrivateKey != ""
+	result.WeChatPayAPIv3Key = strings.TrimSpace(settings[SettingKeyWeChatPayAPIv3Key])
+	result.WeChatPayAPIv3KeyConfigured = result.WeChatPayAPIv3Key != ""
 
 	// LinuxDo Connect 设置：
 	// - 兼容 config.yaml/env（避免老部署因为未迁移到数据库设置而被意外关闭）
@@ -52,6 +52,41 @@ type SystemSettings struct {
 	XunhuPayReturnURL           string
 	XunhuPayPlugins             string
 
+	// Stripe Checkout
+	StripeSecretKey               string
+	StripeSecretKeyConfigured     bool
+	StripeWebhookSecret           string
+	StripeWebhookSecretConfigured bool
+	StripeCurrency                string
+	StripeSuccessURL              string
+	StripeCancelURL               string
+
+	// 支付宝官方（电脑网站支付）
+	AlipayAppID                string
+	AlipayPrivateKey           string
+	AlipayPrivateKeyConfigured bool
+	AlipayPublicKey            string
+	AlipayGateway              string
+	AlipayNotifyURL            string
+	AlipayReturnURL            string
+
+	// 微信支付 APIv3（Native 支付）
+	WeChatPayAppID                string
+	WeChatPayMchID                string
+	WeChatPaySerialNo             string
+	WeChatPayPrivateKey           string
+	WeChatPayPrivateKeyConfigured bool
+	WeChatPayAPIv3Key             string
+	WeChatPayAPIv3KeyConfigured   bool
+	WeChatPayPlatformPublicKey    string
+	WeChatPayNotifyURL            string
+
+	// 余额充值
+	BalanceTopUpEnabled   bool
+	BalanceTopUpMinAmount float64
+	BalanceTopUpMaxAmount float64
+	BalanceTopUpRate      float64
+
 	DefaultConcurrency   int
 	DefaultBalance       float64
 	DefaultSubscriptions []DefaultSubscriptionSetting
@@ -111,6 +146,9 @@ type PublicSettings struct {
 	PurchaseInstructions        string
 	LinuxDoOAuthEnabled         bool
 	Version                     string
+
+	// SSOProviders 已启用的 SSO 提供方（来自配置文件）
+	SSOProviders []SSOProviderInfo
 }
 
 // SoraS3Settings Sora S3 存储配置
@@ -21,6 +21,7 @@ import (
 	"time"
 
 	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/ctxkey"
 	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
 	"github.com/gin-gonic/gin"
 )
@@ -344,6 +345,7 @@ func (s *SoraGatewayService) Forward(ctx context.Context, c *gin.Context, accoun
 	if err != nil {
 		return nil, s.handleSoraRequestError(ctx, account, err, reqModel, c, clientStream)
 	}
+	notifySoraTaskCreated(ctx, taskID)
 
 	if clientStream && c != nil {
 		s.prepareSoraStream(c, taskID)
@@ -432,6 +434,57 @@ func (s *SoraGatewayService) Forward(ctx context.Context, c *gin.Context, accoun
 	}, nil
 }
 
+// ResumeTask 恢复轮询已提交的上游任务并返回媒体 URL（用于异步生成任务在重启/重试后的断点恢复）。
+// 仅支持直连（SDK）账号；apikey 透传账号拿不到上游任务 ID，不会走到这里。
+func (s *SoraGatewayService) ResumeTask(ctx context.Context, account *Account, model, taskID string) ([]string, error) {
+	if account == nil {
+		return nil, errors.New("account is nil")
+	}
+	if strings.TrimSpace(taskID) == "" {
+		return nil, errors.New("upstream task id is empty")
+	}
+	if s.soraClient == nil || !s.soraClient.Enabled() {
+		return nil, errors.New("sora upstream not configured")
+	}
+	if mappedModel := account.GetMappedModel(model); mappedModel != "" {
+		model = mappedModel
+	}
+	modelCfg, ok := GetSoraModelConfig(model)
+	if !ok {
+		return nil, fmt.Errorf("unsupported model: %s", model)
+	}
+
+	var mediaURLs []string
+	switch modelCfg.Type {
+	case "image":
+		urls, err := s.pollImageTask(ctx, nil, account, taskID, false)
+		if err != nil {
+			return nil, err
+		}
+		mediaURLs = urls
+	case "video":
+		status, err := s.pollVideoTaskDetailed(ctx, nil, account, taskID, false)
+		if err != nil {
+			return nil, err
+		}
+		if status != nil {
+			mediaURLs = status.URLs
+		}
+	default:
+		return nil, fmt.Errorf("unsupported model type: %s", modelCfg.Type)
+	}
+	return s.normalizeSoraMediaURLs(mediaURLs), nil
+}
+
+func notifySoraTaskCreated(ctx context.Context, taskID string) {
+	if ctx == nil || strings.TrimSpace(taskID) == "" {
+		return
+	}
+	if hook, ok := ctx.Value(ctxkey.SoraTaskCreatedHook).(func(string)); ok && hook != nil {
+		hook(taskID)
+	}
+}
+
 func (s *SoraGatewayService) withSoraTimeout(ctx context.Context, stream bool) (context.Context, context.CancelFunc) {
 	if s == nil || s.cfg == nil {
 		return ctx, nil
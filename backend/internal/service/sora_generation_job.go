@@ -0,0 +1,128 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+// SoraGenerationJob 代表一条 Sora 客户端生成任务的持久化队列记录。
+// 与 SoraGeneration 一一对应：SoraGeneration 面向用户展示，SoraGenerationJob 负责执行调度。
+type SoraGenerationJob struct {
+	ID           int64
+	GenerationID int64
+	UserID       int64
+	GroupID      *int64
+
+	Model      string
+	Prompt     string
+	MediaType  string
+	ImageInput string
+	VideoCount int
+
+	Status         string
+	Attempts       int
+	MaxAttempts    int
+	RunAfter       time.Time
+	LeaseOwner     string
+	LeaseExpiresAt *time.Time
+	HeartbeatAt    *time.Time
+
+	// 上游进度：非空时重试直接恢复轮询，不重新提交任务
+	AccountID      *int64
+	UpstreamTaskID string
+
+	// 已落盘但尚未挂到生成记录上的存储，任务失败时回滚
+	StorageType  string
+	StoragePaths []string
+	StorageBytes int64
+	UsageAdded   bool
+
+	LastError  string
+	FinishedAt *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// Sora 生成任务队列状态常量
+const (
+	SoraJobStatusQueued    = "queued"
+	SoraJobStatusRunning   = "running"
+	SoraJobStatusSucceeded = "succeeded"
+	SoraJobStatusFailed    = "failed"
+)
+
+// Resumable 任务是否已拿到上游任务 ID，可直接恢复轮询。
+func (j *SoraGenerationJob) Resumable() bool {
+	return j != nil && j.AccountID != nil && *j.AccountID > 0 && j.UpstreamTaskID != ""
+}
+
+// SoraGenerationJobRepository 生成任务队列持久化接口。
+// 所有带 owner 的写操作都以 lease_owner 为条件，租约被其他实例接管后写入不生效。
+type SoraGenerationJobRepository interface {
+	Enqueue(ctx context.Context, job *SoraGenerationJob) error
+	// ClaimNext 领取一条可执行任务（FOR UPDATE SKIP LOCKED）：
+	// queued 且 run_after 已到，或 running 但租约已过期且未超过最大次数。无任务时返回 nil, nil。
+	ClaimNext(ctx context.Context, owner string, lease time.Duration) (*SoraGenerationJob, error)
+	Heartbeat(ctx context.Context, id int64, owner string, lease time.Duration) (bool, error)
+	RecordUpstreamTask(ctx context.Context, id int64, owner string, accountID int64, taskID string) error
+	RecordStorage(ctx context.Context, id int64, owner string, storageType string, paths []string, bytes int64, usageAdded bool) error
+	MarkSucceeded(ctx context.Context, id int64, owner string) error
+	MarkRetry(ctx context.Context, id int64, owner string, runAfter time.Time, errMsg string) error
+	MarkFailed(ctx context.Context, id int64, owner string, errMsg string) error
+	// Release 放弃租约并退回 queued（服务停止时使用，不消耗执行次数）。
+	Release(ctx context.Context, id int64, owner string) error
+	// ClaimExhausted 将租约已过期且执行次数用尽的 running 任务原子标记为 failed 并返回。
+	ClaimExhausted(ctx context.Context, errMsg string, limit int) ([]*SoraGenerationJob, error)
+	// ListOrphanGenerationIDs 返回没有未结束任务、却仍处于 pending/generating 的生成记录。
+	ListOrphanGenerationIDs(ctx context.Context, createdBefore time.Time, limit int) ([]int64, error)
+	DeleteFinishedBefore(ctx context.Context, before time.Time) (int64, error)
+}
+
+// SoraGenerationJobProgress 执行过程中的进度回调，由队列服务持久化。
+type SoraGenerationJobProgress interface {
+	// UpstreamTaskCreated 上游任务已创建，之后的重试只需恢复轮询。
+	UpstreamTaskCreated(accountID int64, taskID string)
+	// MediaStored 媒体已落盘（尚未标记完成），失败时据此回滚存储与配额。
+	MediaStored(storageType string, paths []string, bytes int64, usageAdded bool)
+}
+
+// SoraGenerationJobExecutor 执行单个生成任务。
+// 返回 nil 表示任务已结束（完成或已取消）；返回 SoraGenerationRetryableError 表示可重试，
+// 其余错误视为终态失败，由队列服务标记生成记录失败并回滚存储。
+type SoraGenerationJobExecutor interface {
+	ExecuteSoraGenerationJob(ctx context.Context, job *SoraGenerationJob, progress SoraGenerationJobProgress) error
+}
+
+// SoraGenerationRetryableError 标记可重试的执行错误（选号失败、上游临时错误等）。
+type SoraGenerationRetryableError struct {
+	Err error
+}
+
+func (e *SoraGenerationRetryableError) Error() string {
+	if e == nil || e.Err == nil {
+		return "sora generation retryable error"
+	}
+	return e.Err.Error()
+}
+
+func (e *SoraGenerationRetryableError) Unwrap() error {
+	if e == nil {
+		return nil
+	}
+	return e.Err
+}
+
+// NewSoraGenerationRetryableError 包装可重试错误。
+func NewSoraGenerationRetryableError(err error) error {
+	if err == nil {
+		return nil
+	}
+	return &SoraGenerationRetryableError{Err: err}
+}
+
+// IsSoraGenerationRetryable 判断错误是否可重试。
+func IsSoraGenerationRetryable(err error) bool {
+	var retryable *SoraGenerationRetryableError
+	return errors.As(err, &retryable)
+}
@@ -0,0 +1,548 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+)
+
+const (
+	soraJobReapBatchSize     = 100
+	soraJobErrorMessageLimit = 500
+	soraJobOrphanMessage     = "任务已中断（服务重启或任务丢失），请重新生成"
+	soraJobExhaustedMessage  = "任务执行超时或多次中断，请重新生成"
+)
+
+// SoraGenerationJobService Sora 客户端生成任务的持久化队列。
+//
+// 设计说明：
+//   - 任务入库后由各实例的 worker 通过 FOR UPDATE SKIP LOCKED 领取，持有带过期时间的租约；
+//   - 执行期间按 1/3 租约间隔心跳续期，实例崩溃/升级后租约过期，任务自动被其他实例（或重启后的自身）接管；
+//   - 已拿到 upstream_task_id 的任务重试时只恢复轮询，不重新提交上游；
+//   - 可重试错误按指数退避重新排队，超过最大次数或终态错误则标记失败；
+//   - reaper 定期将执行次数用尽的僵死任务、以及没有队列任务的遗留 pending/generating 记录标记失败，
+//     并回滚已落盘的媒体与存储配额。
+type SoraGenerationJobService struct {
+	repo         SoraGenerationJobRepository
+	genService   *SoraGenerationService
+	quotaService *SoraQuotaService
+	s3Storage    *SoraS3Storage
+	mediaStorage *SoraMediaStorage
+	cfg          *config.Config
+
+	executorMu sync.RWMutex
+	executor   SoraGenerationJobExecutor
+
+	owner  string
+	wakeCh chan struct{}
+	now    func() time.Time
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+	started   atomic.Bool
+	wg        sync.WaitGroup
+
+	workerCtx    context.Context
+	workerCancel context.CancelFunc
+}
+
+// NewSoraGenerationJobService 创建生成任务队列服务。
+func NewSoraGenerationJobService(
+	repo SoraGenerationJobRepository,
+	genService *SoraGenerationService,
+	quotaService *SoraQuotaService,
+	s3Storage *SoraS3Storage,
+	mediaStorage *SoraMediaStorage,
+	cfg *config.Config,
+) *SoraGenerationJobService {
+	workerCtx, workerCancel := context.WithCancel(context.Background())
+	return &SoraGenerationJobService{
+		repo:         repo,
+		genService:   genService,
+		quotaService: quotaService,
+		s3Storage:    s3Storage,
+		mediaStorage: mediaStorage,
+		cfg:          cfg,
+		owner:        newSoraJobOwner(),
+		wakeCh:       make(chan struct{}, 1),
+		now:          time.Now,
+		workerCtx:    workerCtx,
+		workerCancel: workerCancel,
+	}
+}
+
+// SetExecutor 注册任务执行器（由 Sora 客户端 Handler 在构造时注入）。
+func (s *SoraGenerationJobService) SetExecutor(executor SoraGenerationJobExecutor) {
+	if s == nil {
+		return
+	}
+	s.executorMu.Lock()
+	s.executor = executor
+	s.executorMu.Unlock()
+	s.wake()
+}
+
+func (s *SoraGenerationJobService) getExecutor() SoraGenerationJobExecutor {
+	s.executorMu.RLock()
+	defer s.executorMu.RUnlock()
+	return s.executor
+}
+
+// Enabled 是否启用持久化队列；未启用时调用方回退到进程内执行。
+func (s *SoraGenerationJobService) Enabled() bool {
+	return s != nil && s.repo != nil && s.workers() > 0
+}
+
+// Start 启动 worker 与 reaper。
+func (s *SoraGenerationJobService) Start() {
+	if !s.Enabled() {
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] not started (disabled)")
+		return
+	}
+	s.startOnce.Do(func() {
+		s.started.Store(true)
+		for i := 0; i < s.workers(); i++ {
+			s.wg.Add(1)
+			go s.workerLoop()
+		}
+		s.wg.Add(1)
+		go s.reaperLoop()
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] started (owner=%s workers=%d lease=%s max_attempts=%d)", s.owner, s.workers(), s.leaseDuration(), s.maxAttempts())
+	})
+}
+
+// Stop 停止领取新任务；执行中的任务释放租约退回队列，由下次启动或其他实例继续。
+func (s *SoraGenerationJobService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.workerCancel != nil {
+			s.workerCancel()
+		}
+		if s.started.Load() {
+			s.wg.Wait()
+		}
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] stopped")
+	})
+}
+
+// Enqueue 为 pending 生成记录创建队列任务。
+func (s *SoraGenerationJobService) Enqueue(ctx context.Context, gen *SoraGeneration, groupID *int64, imageInput string, videoCount int) (*SoraGenerationJob, error) {
+	if !s.Enabled() {
+		return nil, errors.New("sora generation job queue is disabled")
+	}
+	if gen == nil {
+		return nil, errors.New("generation is nil")
+	}
+	if videoCount <= 0 {
+		videoCount = 1
+	}
+	job := &SoraGenerationJob{
+		GenerationID: gen.ID,
+		UserID:       gen.UserID,
+		GroupID:      groupID,
+		Model:        gen.Model,
+		Prompt:       gen.Prompt,
+		MediaType:    gen.MediaType,
+		ImageInput:   imageInput,
+		VideoCount:   videoCount,
+		Status:       SoraJobStatusQueued,
+		MaxAttempts:  s.maxAttempts(),
+		RunAfter:     s.now(),
+	}
+	if err := s.repo.Enqueue(ctx, job); err != nil {
+		return nil, fmt.Errorf("enqueue sora generation job: %w", err)
+	}
+	logger.LegacyPrintf("service.sora_job", "[SoraJob] 入队 job=%d gen=%d user=%d model=%s", job.ID, job.GenerationID, job.UserID, job.Model)
+	s.wake()
+	return job, nil
+}
+
+func (s *SoraGenerationJobService) wake() {
+	if s == nil || s.wakeCh == nil {
+		return
+	}
+	select {
+	case s.wakeCh <- struct{}{}:
+	default:
+	}
+}
+
+func (s *SoraGenerationJobService) workerLoop() {
+	defer s.wg.Done()
+	interval := s.pollInterval()
+	for {
+		if s.workerCtx.Err() != nil {
+			return
+		}
+		if s.runOnce() {
+			// 刚执行完一个任务，立即尝试下一个
+			continue
+		}
+		select {
+		case <-s.workerCtx.Done():
+			return
+		case <-s.wakeCh:
+		case <-time.After(interval):
+		}
+	}
+}
+
+// runOnce 领取并执行一个任务，返回是否领取到任务。
+func (s *SoraGenerationJobService) runOnce() bool {
+	executor := s.getExecutor()
+	if executor == nil {
+		return false
+	}
+	claimCtx, cancel := context.WithTimeout(s.workerCtx, 5*time.Second)
+	job, err := s.repo.ClaimNext(claimCtx, s.owner, s.leaseDuration())
+	cancel()
+	if err != nil {
+		if s.workerCtx.Err() == nil {
+			logger.LegacyPrintf("service.sora_job", "[SoraJob] claim failed: %v", err)
+		}
+		return false
+	}
+	if job == nil {
+		return false
+	}
+	logger.LegacyPrintf("service.sora_job", "[SoraJob] 领取 job=%d gen=%d attempt=%d/%d resume=%v", job.ID, job.GenerationID, job.Attempts, job.MaxAttempts, job.Resumable())
+	s.execute(executor, job)
+	return true
+}
+
+func (s *SoraGenerationJobService) execute(executor SoraGenerationJobExecutor, job *SoraGenerationJob) {
+	ctx, cancel := context.WithTimeout(s.workerCtx, s.taskTimeout())
+	defer cancel()
+
+	var leaseLost atomic.Bool
+	heartbeatDone := make(chan struct{})
+	go func() {
+		defer close(heartbeatDone)
+		s.heartbeatLoop(ctx, job, func() {
+			leaseLost.Store(true)
+			cancel()
+		})
+	}()
+
+	progress := &soraJobProgressRecorder{svc: s, job: job}
+	err := executor.ExecuteSoraGenerationJob(ctx, job, progress)
+	cancel()
+	<-heartbeatDone
+
+	switch {
+	case leaseLost.Load():
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] 租约已被接管，放弃结果 job=%d gen=%d", job.ID, job.GenerationID)
+	case err == nil:
+		s.updateJob(job, "mark succeeded", func(ctx context.Context) error {
+			return s.repo.MarkSucceeded(ctx, job.ID, s.owner)
+		})
+	case s.workerCtx.Err() != nil:
+		// 服务停止：退回队列，不消耗执行次数
+		s.updateJob(job, "release", func(ctx context.Context) error {
+			return s.repo.Release(ctx, job.ID, s.owner)
+		})
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] 服务停止，任务退回队列 job=%d gen=%d", job.ID, job.GenerationID)
+	case s.shouldRetry(job, err):
+		delay := s.retryBackoff(job.Attempts)
+		msg := truncateSoraJobError(err)
+		s.updateJob(job, "mark retry", func(ctx context.Context) error {
+			return s.repo.MarkRetry(ctx, job.ID, s.owner, s.now().Add(delay), msg)
+		})
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] 稍后重试 job=%d gen=%d attempt=%d/%d delay=%s err=%s", job.ID, job.GenerationID, job.Attempts, job.MaxAttempts, delay, msg)
+	default:
+		msg := truncateSoraJobError(err)
+		s.failGeneration(job, msg)
+		s.updateJob(job, "mark failed", func(ctx context.Context) error {
+			return s.repo.MarkFailed(ctx, job.ID, s.owner, msg)
+		})
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] 任务失败 job=%d gen=%d attempt=%d/%d err=%s", job.ID, job.GenerationID, job.Attempts, job.MaxAttempts, msg)
+	}
+}
+
+func (s *SoraGenerationJobService) shouldRetry(job *SoraGenerationJob, err error) bool {
+	if job.Attempts >= job.MaxAttempts {
+		return false
+	}
+	if IsSoraGenerationRetryable(err) {
+		return true
+	}
+	// 单次执行超时但上游任务仍在进行：下次只需恢复轮询
+	return job.Resumable() && errors.Is(err, context.DeadlineExceeded)
+}
+
+func (s *SoraGenerationJobService) heartbeatLoop(ctx context.Context, job *SoraGenerationJob, onLeaseLost func()) {
+	interval := s.leaseDuration() / 3
+	if interval <= 0 {
+		interval = time.Second
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+			hbCtx, cancel := context.WithTimeout(ctx, 5*time.Second)
+			ok, err := s.repo.Heartbeat(hbCtx, job.ID, s.owner, s.leaseDuration())
+			cancel()
+			if err != nil {
+				if ctx.Err() == nil {
+					logger.LegacyPrintf("service.sora_job", "[SoraJob] heartbeat failed job=%d err=%v", job.ID, err)
+				}
+				continue
+			}
+			if !ok {
+				onLeaseLost()
+				return
+			}
+		}
+	}
+}
+
+func (s *SoraGenerationJobService) reaperLoop() {
+	defer s.wg.Done()
+	ticker := time.NewTicker(s.reapInterval())
+	defer ticker.Stop()
+	s.reapOnce()
+	for {
+		select {
+		case <-s.workerCtx.Done():
+			return
+		case <-ticker.C:
+			s.reapOnce()
+		}
+	}
+}
+
+// reapOnce 标记僵死任务失败、回收遗留记录并清理过期任务。
+func (s *SoraGenerationJobService) reapOnce() {
+	ctx, cancel := context.WithTimeout(s.workerCtx, time.Minute)
+	defer cancel()
+
+	exhausted, err := s.repo.ClaimExhausted(ctx, soraJobExhaustedMessage, soraJobReapBatchSize)
+	if err != nil {
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] reap exhausted jobs failed: %v", err)
+	}
+	for _, job := range exhausted {
+		s.failGeneration(job, soraJobExhaustedMessage)
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] 回收僵死任务 job=%d gen=%d attempts=%d owner=%s", job.ID, job.GenerationID, job.Attempts, job.LeaseOwner)
+	}
+
+	if s.genService != nil {
+		cutoff := s.now().Add(-s.orphanTimeout())
+		ids, err := s.repo.ListOrphanGenerationIDs(ctx, cutoff, soraJobReapBatchSize)
+		if err != nil {
+			logger.LegacyPrintf("service.sora_job", "[SoraJob] list orphan generations failed: %v", err)
+		}
+		for _, id := range ids {
+			if err := s.genService.MarkFailed(ctx, id, soraJobOrphanMessage); err != nil && !errors.Is(err, ErrSoraGenerationStateConflict) {
+				logger.LegacyPrintf("service.sora_job", "[SoraJob] mark orphan generation failed gen=%d err=%v", id, err)
+				continue
+			}
+			logger.LegacyPrintf("service.sora_job", "[SoraJob] 回收遗留生成记录 gen=%d", id)
+		}
+	}
+
+	if days := s.retentionDays(); days > 0 {
+		deleted, err := s.repo.DeleteFinishedBefore(ctx, s.now().AddDate(0, 0, -days))
+		if err != nil {
+			logger.LegacyPrintf("service.sora_job", "[SoraJob] delete finished jobs failed: %v", err)
+		} else if deleted > 0 {
+			logger.LegacyPrintf("service.sora_job", "[SoraJob] 清理已结束任务 count=%d", deleted)
+		}
+	}
+}
+
+// failGeneration 将生成记录标记为失败，并回滚任务记录的已落盘存储与配额。
+// 生成记录已完成时存储归属于记录本身，不做回滚。
+func (s *SoraGenerationJobService) failGeneration(job *SoraGenerationJob, msg string) {
+	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
+	defer cancel()
+
+	if s.genService != nil {
+		if err := s.genService.MarkFailed(ctx, job.GenerationID, msg); err != nil {
+			if !errors.Is(err, ErrSoraGenerationStateConflict) {
+				logger.LegacyPrintf("service.sora_job", "[SoraJob] mark generation failed error gen=%d err=%v", job.GenerationID, err)
+			}
+			gen, getErr := s.genService.GetByID(ctx, job.GenerationID, job.UserID)
+			if getErr == nil && gen.Status == SoraGenStatusCompleted {
+				return
+			}
+		}
+	}
+	s.rollbackStorage(ctx, job)
+}
+
+func (s *SoraGenerationJobService) rollbackStorage(ctx context.Context, job *SoraGenerationJob) {
+	if job.StorageType == "" || len(job.StoragePaths) == 0 {
+		return
+	}
+	switch job.StorageType {
+	case SoraStorageTypeS3:
+		if s.s3Storage != nil {
+			if err := s.s3Storage.DeleteObjects(ctx, job.StoragePaths); err != nil {
+				logger.LegacyPrintf("service.sora_job", "[SoraJob] 回滚 S3 文件失败 job=%d err=%v", job.ID, err)
+			}
+		}
+	case SoraStorageTypeLocal:
+		if s.mediaStorage != nil {
+			if err := s.mediaStorage.DeleteByRelativePaths(job.StoragePaths); err != nil {
+				logger.LegacyPrintf("service.sora_job", "[SoraJob] 回滚本地文件失败 job=%d err=%v", job.ID, err)
+			}
+		}
+	}
+	if job.UsageAdded && job.StorageBytes > 0 && s.quotaService != nil {
+		if err := s.quotaService.ReleaseUsage(ctx, job.UserID, job.StorageBytes); err != nil {
+			logger.LegacyPrintf("service.sora_job", "[SoraJob] 退还配额失败 job=%d user=%d bytes=%d err=%v", job.ID, job.UserID, job.StorageBytes, err)
+		}
+	}
+}
+
+func (s *SoraGenerationJobService) updateJob(job *SoraGenerationJob, action string, fn func(ctx context.Context) error) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := fn(ctx); err != nil {
+		logger.LegacyPrintf("service.sora_job", "[SoraJob] %s failed job=%d err=%v", action, job.ID, err)
+	}
+}
+
+func (s *SoraGenerationJobService) retryBackoff(attempt int) time.Duration {
+	base := time.Duration(s.cfgJobs().RetryBackoffBaseSeconds) * time.Second
+	if base <= 0 {
+		base = 10 * time.Second
+	}
+	maxDelay := time.Duration(s.cfgJobs().RetryBackoffMaxSeconds) * time.Second
+	if maxDelay <= 0 {
+		maxDelay = 5 * time.Minute
+	}
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+func (s *SoraGenerationJobService) cfgJobs() config.SoraJobsConfig {
+	if s == nil || s.cfg == nil {
+		return config.SoraJobsConfig{}
+	}
+	return s.cfg.Sora.Jobs
+}
+
+func (s *SoraGenerationJobService) workers() int {
+	if s == nil || s.cfg == nil {
+		return 0
+	}
+	return s.cfg.Sora.Jobs.Workers
+}
+
+func (s *SoraGenerationJobService) pollInterval() time.Duration {
+	if v := s.cfgJobs().PollIntervalSeconds; v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return 2 * time.Second
+}
+
+func (s *SoraGenerationJobService) leaseDuration() time.Duration {
+	if v := s.cfgJobs().LeaseSeconds; v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return time.Minute
+}
+
+func (s *SoraGenerationJobService) taskTimeout() time.Duration {
+	if v := s.cfgJobs().TaskTimeoutSeconds; v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return 30 * time.Minute
+}
+
+func (s *SoraGenerationJobService) maxAttempts() int {
+	if v := s.cfgJobs().MaxAttempts; v > 0 {
+		return v
+	}
+	return 3
+}
+
+func (s *SoraGenerationJobService) reapInterval() time.Duration {
+	if v := s.cfgJobs().ReapIntervalSeconds; v > 0 {
+		return time.Duration(v) * time.Second
+	}
+	return time.Minute
+}
+
+func (s *SoraGenerationJobService) orphanTimeout() time.Duration {
+	if v := s.cfgJobs().OrphanTimeoutMinutes; v > 0 {
+		return time.Duration(v) * time.Minute
+	}
+	return time.Hour
+}
+
+func (s *SoraGenerationJobService) retentionDays() int {
+	return s.cfgJobs().RetentionDays
+}
+
+// soraJobProgressRecorder 将执行进度同步到内存任务与数据库。
+type soraJobProgressRecorder struct {
+	svc *SoraGenerationJobService
+	job *SoraGenerationJob
+}
+
+func (p *soraJobProgressRecorder) UpstreamTaskCreated(accountID int64, taskID string) {
+	taskID = strings.TrimSpace(taskID)
+	if taskID == "" {
+		return
+	}
+	p.job.AccountID = &accountID
+	p.job.UpstreamTaskID = taskID
+	p.svc.updateJob(p.job, "record upstream task", func(ctx context.Context) error {
+		return p.svc.repo.RecordUpstreamTask(ctx, p.job.ID, p.svc.owner, accountID, taskID)
+	})
+}
+
+func (p *soraJobProgressRecorder) MediaStored(storageType string, paths []string, bytes int64, usageAdded bool) {
+	p.job.StorageType = storageType
+	p.job.StoragePaths = paths
+	p.job.StorageBytes = bytes
+	p.job.UsageAdded = usageAdded
+	p.svc.updateJob(p.job, "record storage", func(ctx context.Context) error {
+		return p.svc.repo.RecordStorage(ctx, p.job.ID, p.svc.owner, storageType, paths, bytes, usageAdded)
+	})
+}
+
+func truncateSoraJobError(err error) string {
+	if err == nil {
+		return ""
+	}
+	msg := strings.TrimSpace(err.Error())
+	// 按 rune 截断，避免写入非法 UTF-8
+	if runes := []rune(msg); len(runes) > soraJobErrorMessageLimit {
+		msg = string(runes[:soraJobErrorMessageLimit])
+	}
+	return msg
+}
+
+func newSoraJobOwner() string {
+	host, _ := os.Hostname()
+	if host == "" {
+		host = "unknown"
+	}
+	buf := make([]byte, 4)
+	_, _ = rand.Read(buf)
+	return fmt.Sprintf("%s:%d:%s", host, os.Getpid(), hex.EncodeToString(buf))
+}
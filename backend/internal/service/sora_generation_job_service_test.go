@@ -0,0 +1,305 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+// ==================== Stub: SoraGenerationJobRepository ====================
+
+var _ SoraGenerationJobRepository = (*stubSoraJobRepo)(nil)
+
+type stubSoraJobRepo struct {
+	mu        sync.Mutex
+	jobs      map[int64]*SoraGenerationJob
+	nextID    int64
+	exhausted []*SoraGenerationJob
+	orphanIDs []int64
+	heartbeat bool
+}
+
+func newStubSoraJobRepo() *stubSoraJobRepo {
+	return &stubSoraJobRepo{jobs: make(map[int64]*SoraGenerationJob), nextID: 1, heartbeat: true}
+}
+
+func (r *stubSoraJobRepo) Enqueue(_ context.Context, job *SoraGenerationJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job.ID = r.nextID
+	r.nextID++
+	cp := *job
+	r.jobs[job.ID] = &cp
+	return nil
+}
+
+func (r *stubSoraJobRepo) ClaimNext(_ context.Context, owner string, _ time.Duration) (*SoraGenerationJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, job := range r.jobs {
+		if job.Status == SoraJobStatusQueued && !job.RunAfter.After(time.Now()) {
+			job.Status = SoraJobStatusRunning
+			job.Attempts++
+			job.LeaseOwner = owner
+			cp := *job
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *stubSoraJobRepo) Heartbeat(context.Context, int64, string, time.Duration) (bool, error) {
+	return r.heartbeat, nil
+}
+
+func (r *stubSoraJobRepo) update(id int64, owner string, fn func(job *SoraGenerationJob)) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if job, ok := r.jobs[id]; ok && job.Status == SoraJobStatusRunning && job.LeaseOwner == owner {
+		fn(job)
+	}
+	return nil
+}
+
+func (r *stubSoraJobRepo) RecordUpstreamTask(_ context.Context, id int64, owner string, accountID int64, taskID string) error {
+	return r.update(id, owner, func(job *SoraGenerationJob) {
+		job.AccountID = &accountID
+		job.UpstreamTaskID = taskID
+	})
+}
+
+func (r *stubSoraJobRepo) RecordStorage(_ context.Context, id int64, owner string, storageType string, paths []string, bytes int64, usageAdded bool) error {
+	return r.update(id, owner, func(job *SoraGenerationJob) {
+		job.StorageType, job.StoragePaths, job.StorageBytes, job.UsageAdded = storageType, paths, bytes, usageAdded
+	})
+}
+
+func (r *stubSoraJobRepo) MarkSucceeded(_ context.Context, id int64, owner string) error {
+	return r.update(id, owner, func(job *SoraGenerationJob) { job.Status = SoraJobStatusSucceeded })
+}
+
+func (r *stubSoraJobRepo) MarkRetry(_ context.Context, id int64, owner string, runAfter time.Time, errMsg string) error {
+	return r.update(id, owner, func(job *SoraGenerationJob) {
+		job.Status, job.RunAfter, job.LastError, job.LeaseOwner = SoraJobStatusQueued, runAfter, errMsg, ""
+	})
+}
+
+func (r *stubSoraJobRepo) MarkFailed(_ context.Context, id int64, owner string, errMsg string) error {
+	return r.update(id, owner, func(job *SoraGenerationJob) {
+		job.Status, job.LastError, job.LeaseOwner = SoraJobStatusFailed, errMsg, ""
+	})
+}
+
+func (r *stubSoraJobRepo) Release(_ context.Context, id int64, owner string) error {
+	return r.update(id, owner, func(job *SoraGenerationJob) {
+		job.Status, job.LeaseOwner = SoraJobStatusQueued, ""
+		job.Attempts--
+	})
+}
+
+func (r *stubSoraJobRepo) ClaimExhausted(context.Context, string, int) ([]*SoraGenerationJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	out := r.exhausted
+	r.exhausted = nil
+	return out, nil
+}
+
+func (r *stubSoraJobRepo) ListOrphanGenerationIDs(context.Context, time.Time, int) ([]int64, error) {
+	return r.orphanIDs, nil
+}
+
+func (r *stubSoraJobRepo) DeleteFinishedBefore(context.Context, time.Time) (int64, error) {
+	return 0, nil
+}
+
+// ==================== Stub: executor ====================
+
+type stubSoraJobExecutor struct {
+	fn func(ctx context.Context, job *SoraGenerationJob, progress SoraGenerationJobProgress) error
+}
+
+func (e *stubSoraJobExecutor) ExecuteSoraGenerationJob(ctx context.Context, job *SoraGenerationJob, progress SoraGenerationJobProgress) error {
+	return e.fn(ctx, job, progress)
+}
+
+func newTestSoraJobService(t *testing.T) (*SoraGenerationJobService, *stubSoraJobRepo, *stubGenRepo, *stubUserRepoForQuota) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.Sora.Jobs = config.SoraJobsConfig{
+		Workers:                 1,
+		LeaseSeconds:            60,
+		MaxAttempts:             3,
+		RetryBackoffBaseSeconds: 10,
+		RetryBackoffMaxSeconds:  60,
+	}
+	jobRepo := newStubSoraJobRepo()
+	genRepo := newStubGenRepo()
+	userRepo := newStubUserRepoForQuota()
+	userRepo.users[1] = &User{ID: 1, SoraStorageUsedBytes: 4096}
+	quotaService := NewSoraQuotaService(userRepo, nil, nil)
+	genService := NewSoraGenerationService(genRepo, nil, quotaService)
+	svc := NewSoraGenerationJobService(jobRepo, genService, quotaService, nil, nil, cfg)
+	t.Cleanup(svc.Stop)
+	return svc, jobRepo, genRepo, userRepo
+}
+
+func enqueueTestSoraJob(t *testing.T, svc *SoraGenerationJobService, genRepo *stubGenRepo, jobRepo *stubSoraJobRepo) *SoraGenerationJob {
+	t.Helper()
+	genRepo.gens[1] = &SoraGeneration{ID: 1, UserID: 1, Model: "sora2-landscape-10s", MediaType: "video", Status: SoraGenStatusPending}
+	_, err := svc.Enqueue(context.Background(), genRepo.gens[1], nil, "", 1)
+	require.NoError(t, err)
+	job, err := jobRepo.ClaimNext(context.Background(), svc.owner, time.Minute)
+	require.NoError(t, err)
+	require.NotNil(t, job)
+	return job
+}
+
+func TestSoraGenerationJobService_RetryableErrorRequeuesWithBackoff(t *testing.T) {
+	svc, jobRepo, genRepo, _ := newTestSoraJobService(t)
+	job := enqueueTestSoraJob(t, svc, genRepo, jobRepo)
+
+	before := time.Now()
+	svc.execute(&stubSoraJobExecutor{fn: func(context.Context, *SoraGenerationJob, SoraGenerationJobProgress) error {
+		return NewSoraGenerationRetryableError(errors.New("选择账号失败: no available accounts"))
+	}}, job)
+
+	stored := jobRepo.jobs[job.ID]
+	require.Equal(t, SoraJobStatusQueued, stored.Status)
+	require.Contains(t, stored.LastError, "选择账号失败")
+	require.WithinDuration(t, before.Add(10*time.Second), stored.RunAfter, 2*time.Second)
+	require.Equal(t, SoraGenStatusPending, genRepo.gens[1].Status, "retry must not fail the generation")
+}
+
+func TestSoraGenerationJobService_TerminalErrorFailsGenerationAndRollsBack(t *testing.T) {
+	svc, jobRepo, genRepo, userRepo := newTestSoraJobService(t)
+	job := enqueueTestSoraJob(t, svc, genRepo, jobRepo)
+	genRepo.gens[1].Status = SoraGenStatusGenerating
+
+	svc.execute(&stubSoraJobExecutor{fn: func(_ context.Context, _ *SoraGenerationJob, progress SoraGenerationJobProgress) error {
+		progress.UpstreamTaskCreated(9, "task_1")
+		progress.MediaStored(SoraStorageTypeLocal, []string{"video/a.mp4"}, 1024, true)
+		return errors.New("生成失败: reject")
+	}}, job)
+
+	stored := jobRepo.jobs[job.ID]
+	require.Equal(t, SoraJobStatusFailed, stored.Status)
+	require.Equal(t, "task_1", stored.UpstreamTaskID)
+	require.Equal(t, SoraGenStatusFailed, genRepo.gens[1].Status)
+	require.Equal(t, "生成失败: reject", genRepo.gens[1].ErrorMessage)
+	require.Equal(t, int64(3072), userRepo.users[1].SoraStorageUsedBytes, "usage recorded on the job is refunded")
+}
+
+func TestSoraGenerationJobService_RetryableErrorOnLastAttemptFails(t *testing.T) {
+	svc, jobRepo, genRepo, _ := newTestSoraJobService(t)
+	job := enqueueTestSoraJob(t, svc, genRepo, jobRepo)
+	job.Attempts = job.MaxAttempts
+	jobRepo.jobs[job.ID].Attempts = job.MaxAttempts
+
+	svc.execute(&stubSoraJobExecutor{fn: func(context.Context, *SoraGenerationJob, SoraGenerationJobProgress) error {
+		return NewSoraGenerationRetryableError(errors.New("生成失败: upstream 502"))
+	}}, job)
+
+	require.Equal(t, SoraJobStatusFailed, jobRepo.jobs[job.ID].Status)
+	require.Equal(t, SoraGenStatusFailed, genRepo.gens[1].Status)
+}
+
+func TestSoraGenerationJobService_ResumableTimeoutIsRetried(t *testing.T) {
+	svc, jobRepo, genRepo, _ := newTestSoraJobService(t)
+	job := enqueueTestSoraJob(t, svc, genRepo, jobRepo)
+
+	svc.execute(&stubSoraJobExecutor{fn: func(_ context.Context, _ *SoraGenerationJob, progress SoraGenerationJobProgress) error {
+		progress.UpstreamTaskCreated(9, "task_1")
+		return context.DeadlineExceeded
+	}}, job)
+
+	stored := jobRepo.jobs[job.ID]
+	require.Equal(t, SoraJobStatusQueued, stored.Status)
+	require.True(t, stored.Resumable())
+}
+
+func TestSoraGenerationJobService_StopReleasesRunningJob(t *testing.T) {
+	svc, jobRepo, genRepo, _ := newTestSoraJobService(t)
+	job := enqueueTestSoraJob(t, svc, genRepo, jobRepo)
+
+	svc.execute(&stubSoraJobExecutor{fn: func(ctx context.Context, _ *SoraGenerationJob, _ SoraGenerationJobProgress) error {
+		svc.workerCancel()
+		<-ctx.Done()
+		return ctx.Err()
+	}}, job)
+
+	stored := jobRepo.jobs[job.ID]
+	require.Equal(t, SoraJobStatusQueued, stored.Status)
+	require.Zero(t, stored.Attempts, "a shutdown does not consume an attempt")
+	require.Equal(t, SoraGenStatusPending, genRepo.gens[1].Status)
+}
+
+func TestSoraGenerationJobService_LeaseLostDiscardsResult(t *testing.T) {
+	svc, jobRepo, genRepo, _ := newTestSoraJobService(t)
+	svc.cfg.Sora.Jobs.LeaseSeconds = 1 // 心跳间隔为租约的 1/3
+	jobRepo.heartbeat = false
+	job := enqueueTestSoraJob(t, svc, genRepo, jobRepo)
+
+	called := false
+	svc.heartbeatLoop(context.Background(), job, func() { called = true })
+	require.True(t, called)
+}
+
+func TestSoraGenerationJobService_ReapOnce(t *testing.T) {
+	svc, jobRepo, genRepo, userRepo := newTestSoraJobService(t)
+	genRepo.gens[1] = &SoraGeneration{ID: 1, UserID: 1, Status: SoraGenStatusGenerating}
+	genRepo.gens[2] = &SoraGeneration{ID: 2, UserID: 1, Status: SoraGenStatusPending}
+	genRepo.gens[3] = &SoraGeneration{ID: 3, UserID: 1, Status: SoraGenStatusCompleted}
+	jobRepo.exhausted = []*SoraGenerationJob{
+		{ID: 10, GenerationID: 1, UserID: 1, StorageType: SoraStorageTypeLocal, StoragePaths: []string{"a.mp4"}, StorageBytes: 1024, UsageAdded: true},
+		// 已完成的记录：存储属于记录本身，不回滚
+		{ID: 11, GenerationID: 3, UserID: 1, StorageType: SoraStorageTypeLocal, StoragePaths: []string{"b.mp4"}, StorageBytes: 1024, UsageAdded: true},
+	}
+	jobRepo.orphanIDs = []int64{2}
+
+	svc.reapOnce()
+
+	require.Equal(t, SoraGenStatusFailed, genRepo.gens[1].Status)
+	require.Equal(t, soraJobExhaustedMessage, genRepo.gens[1].ErrorMessage)
+	require.Equal(t, SoraGenStatusFailed, genRepo.gens[2].Status)
+	require.Equal(t, soraJobOrphanMessage, genRepo.gens[2].ErrorMessage)
+	require.Equal(t, SoraGenStatusCompleted, genRepo.gens[3].Status)
+	require.Equal(t, int64(3072), userRepo.users[1].SoraStorageUsedBytes)
+}
+
+func TestSoraGenerationJobService_RetryBackoff(t *testing.T) {
+	svc, _, _, _ := newTestSoraJobService(t)
+	require.Equal(t, 10*time.Second, svc.retryBackoff(1))
+	require.Equal(t, 20*time.Second, svc.retryBackoff(2))
+	require.Equal(t, 40*time.Second, svc.retryBackoff(3))
+	require.Equal(t, 60*time.Second, svc.retryBackoff(10))
+}
+
+func TestSoraGenerationJobService_DisabledWithoutWorkers(t *testing.T) {
+	svc, _, _, _ := newTestSoraJobService(t)
+	svc.cfg.Sora.Jobs.Workers = 0
+	require.False(t, svc.Enabled())
+	_, err := svc.Enqueue(context.Background(), &SoraGeneration{ID: 1}, nil, "", 1)
+	require.Error(t, err)
+
+	var nilSvc *SoraGenerationJobService
+	require.False(t, nilSvc.Enabled())
+	nilSvc.SetExecutor(nil)
+	nilSvc.Stop()
+}
+
+func TestTruncateSoraJobError_RuneSafe(t *testing.T) {
+	long := make([]rune, soraJobErrorMessageLimit+10)
+	for i := range long {
+		long[i] = '错'
+	}
+	msg := truncateSoraJobError(errors.New(string(long)))
+	require.Len(t, []rune(msg), soraJobErrorMessageLimit)
+}
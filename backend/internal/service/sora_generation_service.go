@@ -38,6 +38,7 @@ type SoraGenerationService struct {
 	genRepo      SoraGenerationRepository
 	s3Storage    *SoraS3Storage
 	quotaService *SoraQuotaService
+	webhooks     *UserWebhookService
 }
 
 // NewSoraGenerationService 创建生成记录服务。
@@ -53,6 +54,11 @@ func NewSoraGenerationService(
 	}
 }
 
+// SetUserWebhookService 注入用户 Webhook 服务，生成完成/失败时推送事件。
+func (s *SoraGenerationService) SetUserWebhookService(webhooks *UserWebhookService) {
+	s.webhooks = webhooks
+}
+
 // CreatePending 创建一条 pending 状态的生成记录。
 func (s *SoraGenerationService) CreatePending(ctx context.Context, userID int64, apiKeyID *int64, model, prompt, mediaType string) (*SoraGeneration, error) {
 	gen := &SoraGeneration{
@@ -123,6 +129,7 @@ func (s *SoraGenerationService) MarkCompleted(ctx context.Context, id int64, med
 		if !updated {
 			return ErrSoraGenerationStateConflict
 		}
+		s.notifyFinished(id)
 		return nil
 	}
 
@@ -140,7 +147,11 @@ func (s *SoraGenerationService) MarkCompleted(ctx context.Context, id int64, med
 	gen.S3ObjectKeys = s3Keys
 	gen.FileSizeBytes = fileSizeBytes
 	gen.CompletedAt = &now
-	return s.genRepo.Update(ctx, gen)
+	if err := s.genRepo.Update(ctx, gen); err != nil {
+		return err
+	}
+	s.notifyFinished(id)
+	return nil
 }
 
 // MarkFailed 标记为失败。
@@ -154,6 +165,7 @@ func (s *SoraGenerationService) MarkFailed(ctx context.Context, id int64, errMsg
 		if !updated {
 			return ErrSoraGenerationStateConflict
 		}
+		s.notifyFinished(id)
 		return nil
 	}
 
@@ -167,7 +179,56 @@ func (s *SoraGenerationService) MarkFailed(ctx context.Context, id int64, errMsg
 	gen.Status = SoraGenStatusFailed
 	gen.ErrorMessage = errMsg
 	gen.CompletedAt = &now
-	return s.genRepo.Update(ctx, gen)
+	if err := s.genRepo.Update(ctx, gen); err != nil {
+		return err
+	}
+	s.notifyFinished(id)
+	return nil
+}
+
+// notifyFinished 异步推送生成完成/失败的 Webhook 事件（重新读取记录以获得完整字段）。
+func (s *SoraGenerationService) notifyFinished(id int64) {
+	if !s.webhooks.Enabled() {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+		defer cancel()
+		gen, err := s.genRepo.GetByID(ctx, id)
+		if err != nil {
+			logger.LegacyPrintf("service.sora_gen", "[SoraGen] 读取记录失败，跳过 Webhook 推送 id=%d err=%v", id, err)
+			return
+		}
+		eventType := UserWebhookEventSoraGenerationCompleted
+		switch gen.Status {
+		case SoraGenStatusCompleted:
+			_ = s.ResolveMediaURLs(ctx, gen)
+		case SoraGenStatusFailed:
+			eventType = UserWebhookEventSoraGenerationFailed
+		default:
+			return
+		}
+		data := map[string]any{
+			"generation_id": gen.ID,
+			"status":        gen.Status,
+			"model":         gen.Model,
+			"media_type":    gen.MediaType,
+			"prompt":        gen.Prompt,
+			"media_url":     gen.MediaURL,
+			"media_urls":    gen.MediaURLs,
+			"error_message": gen.ErrorMessage,
+			"created_at":    gen.CreatedAt.UTC(),
+		}
+		if gen.APIKeyID != nil {
+			data["api_key_id"] = *gen.APIKeyID
+		}
+		if gen.CompletedAt != nil {
+			data["completed_at"] = gen.CompletedAt.UTC()
+		}
+		if err := s.webhooks.Emit(ctx, UserWebhookEvent{UserID: gen.UserID, APIKeyID: gen.APIKeyID, Type: eventType, Data: data}); err != nil {
+			logger.LegacyPrintf("service.sora_gen", "[SoraGen] Webhook 事件入队失败 id=%d err=%v", id, err)
+		}
+	}()
 }
 
 // MarkCancelled 标记为已取消。
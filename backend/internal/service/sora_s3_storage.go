@@ -5,6 +5,7 @@ import (
 	"fmt"
 	"io"
 	"net/http"
+	"os"
 	"path"
 	"strings"
 	"sync"
@@ -285,6 +286,41 @@ func (s *SoraS3Storage) UploadFromURL(ctx context.Context, userID int64, sourceU
 	return objectKey, written, nil
 }
 
+// UploadFile 将本地文件上传到 S3（使用当前激活的 S3 配置），key 会自动加上配置的前缀。
+// 返回完整 object key。
+func (s *SoraS3Storage) UploadFile(ctx context.Context, key, localPath, contentType string) (string, error) {
+	client, cfg, err := s.getClient(ctx)
+	if err != nil {
+		return "", err
+	}
+	f, err := os.Open(localPath)
+	if err != nil {
+		return "", fmt.Errorf("open upload file: %w", err)
+	}
+	defer func() { _ = f.Close() }()
+	info, err := f.Stat()
+	if err != nil {
+		return "", fmt.Errorf("stat upload file: %w", err)
+	}
+
+	objectKey := key
+	if cfg.Prefix != "" {
+		objectKey = strings.TrimRight(cfg.Prefix, "/") + "/" + key
+	}
+	size := info.Size()
+	if _, err := client.PutObject(ctx, &s3.PutObjectInput{
+		Bucket:        &cfg.Bucket,
+		Key:           &objectKey,
+		Body:          f,
+		ContentLength: &size,
+		ContentType:   &contentType,
+	}); err != nil {
+		return "", fmt.Errorf("s3 upload: %w", err)
+	}
+	logger.LegacyPrintf("service.sora_s3", "[SoraS3] 文件上传完成 key=%s size=%d", objectKey, size)
+	return objectKey, nil
+}
+
 func buildSoraS3Client(ctx context.Context, cfg *SoraS3Settings) (*s3.Client, string, error) {
 	if cfg == nil {
 		return nil, "", fmt.Errorf("s3 config is required")
@@ -344,6 +380,22 @@ func (s *SoraS3Storage) DeleteObjects(ctx context.Context, objectKeys []string)
 	return lastErr
 }
 
+// OpenObject 读取 S3 object 内容，调用方负责关闭返回的 ReadCloser。
+func (s *SoraS3Storage) OpenObject(ctx context.Context, objectKey string) (io.ReadCloser, error) {
+	client, cfg, err := s.getClient(ctx)
+	if err != nil {
+		return nil, err
+	}
+	out, err := client.GetObject(ctx, &s3.GetObjectInput{
+		Bucket: &cfg.Bucket,
+		Key:    &objectKey,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("s3 get object: %w", err)
+	}
+	return out.Body, nil
+}
+
 // GetAccessURL 获取 S3 文件的访问 URL。
 // CDN URL 优先，否则生成 24h 预签名 URL。
 func (s *SoraS3Storage) GetAccessURL(ctx context.Context, objectKey string) (string, error) {
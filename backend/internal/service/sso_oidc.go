@@ -0,0 +1,342 @@
+package service
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math/big"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+)
+
+const (
+	// oidcDiscoveryTTL discovery 文档缓存时间
+	oidcDiscoveryTTL = time.Hour
+	// oidcJWKSRefreshMinInterval 遇到未知 kid 时重新拉取 JWKS 的最小间隔，避免被伪造 kid 打爆上游
+	oidcJWKSRefreshMinInterval = 30 * time.Second
+	// oidcClockSkew ID Token 时间校验允许的时钟偏差
+	oidcClockSkew = time.Minute
+	// ssoMaxResponseBytes 读取提供方响应体的上限
+	ssoMaxResponseBytes = 1 << 20
+)
+
+// oidcSigningMethods ID Token 允许的签名算法（不接受 none/HS*）
+var oidcSigningMethods = []string{"RS256", "RS384", "RS512", "PS256", "PS384", "PS512", "ES256", "ES384", "ES512"}
+
+type oidcDiscoveryDocument struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+	UserinfoEndpoint      string `json:"userinfo_endpoint"`
+	JWKSURI               string `json:"jwks_uri"`
+}
+
+type oidcJWK struct {
+	Kty string `json:"kty"`
+	Kid string `json:"kid"`
+	Use string `json:"use"`
+	N   string `json:"n"`
+	E   string `json:"e"`
+	Crv string `json:"crv"`
+	X   string `json:"x"`
+	Y   string `json:"y"`
+}
+
+// oidcClient 单个 OIDC 提供方的客户端：缓存 discovery 文档与 JWKS，负责校验 ID Token。
+type oidcClient struct {
+	cfg        config.SSOProviderConfig
+	httpClient *http.Client
+
+	mu           sync.Mutex
+	discovery    *oidcDiscoveryDocument
+	discoveredAt time.Time
+	keys         map[string]any
+	keysAt       time.Time
+}
+
+func newOIDCClient(cfg config.SSOProviderConfig, httpClient *http.Client) *oidcClient {
+	return &oidcClient{cfg: cfg, httpClient: httpClient}
+}
+
+// endpoints 返回生效的端点：显式配置优先，其次 discovery 文档。
+func (c *oidcClient) endpoints(ctx context.Context) (*oidcDiscoveryDocument, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if c.discovery == nil || time.Since(c.discoveredAt) > oidcDiscoveryTTL {
+		doc, err := c.fetchDiscovery(ctx)
+		if err != nil {
+			if c.discovery == nil {
+				return nil, err
+			}
+			// 刷新失败时继续使用旧文档
+		} else {
+			c.discovery = doc
+			c.discoveredAt = time.Now()
+		}
+	}
+
+	eps := *c.discovery
+	if c.cfg.AuthorizeURL != "" {
+		eps.AuthorizationEndpoint = c.cfg.AuthorizeURL
+	}
+	if c.cfg.TokenURL != "" {
+		eps.TokenEndpoint = c.cfg.TokenURL
+	}
+	if c.cfg.UserInfoURL != "" {
+		eps.UserinfoEndpoint = c.cfg.UserInfoURL
+	}
+	if c.cfg.JWKSURL != "" {
+		eps.JWKSURI = c.cfg.JWKSURL
+	}
+	if eps.AuthorizationEndpoint == "" || eps.TokenEndpoint == "" || eps.JWKSURI == "" {
+		return nil, errors.New("oidc discovery document is missing required endpoints")
+	}
+	return &eps, nil
+}
+
+func (c *oidcClient) fetchDiscovery(ctx context.Context) (*oidcDiscoveryDocument, error) {
+	var doc oidcDiscoveryDocument
+	if err := ssoGetJSON(ctx, c.httpClient, c.cfg.Issuer+"/.well-known/openid-configuration", "", &doc); err != nil {
+		return nil, fmt.Errorf("fetch oidc discovery: %w", err)
+	}
+	// OIDC Discovery 1.0 §4.3：文档中的 issuer 必须与配置一致
+	if strings.TrimRight(doc.Issuer, "/") != c.cfg.Issuer {
+		return nil, fmt.Errorf("oidc discovery issuer mismatch: got %q", doc.Issuer)
+	}
+	return &doc, nil
+}
+
+// verifyIDToken 校验 ID Token 的签名、iss、aud/azp、exp 与 nonce，返回声明。
+func (c *oidcClient) verifyIDToken(ctx context.Context, rawIDToken, nonce string) (jwt.MapClaims, error) {
+	eps, err := c.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	claims := jwt.MapClaims{}
+	_, err = jwt.ParseWithClaims(rawIDToken, claims, func(token *jwt.Token) (any, error) {
+		kid, _ := token.Header["kid"].(string)
+		return c.signingKey(ctx, eps.JWKSURI, kid)
+	},
+		jwt.WithValidMethods(oidcSigningMethods),
+		// 配置中的 issuer 已去掉末尾 "/"，iss 需与 discovery 文档中的原始 issuer 逐字比较
+		jwt.WithIssuer(eps.Issuer),
+		jwt.WithAudience(c.cfg.ClientID),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(oidcClockSkew),
+	)
+	if err != nil {
+		return nil, fmt.Errorf("verify id_token: %w", err)
+	}
+
+	aud, _ := claims.GetAudience()
+	if len(aud) > 1 {
+		if azp, _ := claims["azp"].(string); azp != c.cfg.ClientID {
+			return nil, errors.New("verify id_token: azp does not match client_id")
+		}
+	}
+	if got, _ := claims["nonce"].(string); nonce == "" || got != nonce {
+		return nil, errors.New("verify id_token: nonce mismatch")
+	}
+	if sub, _ := claims.GetSubject(); sub == "" {
+		return nil, errors.New("verify id_token: missing sub")
+	}
+	return claims, nil
+}
+
+// signingKey 按 kid 查找公钥；未命中时（密钥轮换）按最小间隔重新拉取 JWKS。
+func (c *oidcClient) signingKey(ctx context.Context, jwksURL, kid string) (any, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	if key := c.lookupKeyLocked(kid); key != nil {
+		return key, nil
+	}
+	if c.keys != nil && time.Since(c.keysAt) < oidcJWKSRefreshMinInterval {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	keys, err := fetchJWKS(ctx, c.httpClient, jwksURL)
+	if err != nil {
+		return nil, err
+	}
+	c.keys = keys
+	c.keysAt = time.Now()
+	if key := c.lookupKeyLocked(kid); key != nil {
+		return key, nil
+	}
+	return nil, fmt.Errorf("unknown signing key %q", kid)
+}
+
+func (c *oidcClient) lookupKeyLocked(kid string) any {
+	if kid == "" && len(c.keys) == 1 {
+		for _, key := range c.keys {
+			return key
+		}
+	}
+	return c.keys[kid]
+}
+
+func fetchJWKS(ctx context.Context, httpClient *http.Client, jwksURL string) (map[string]any, error) {
+	var set struct {
+		Keys []oidcJWK `json:"keys"`
+	}
+	if err := ssoGetJSON(ctx, httpClient, jwksURL, "", &set); err != nil {
+		return nil, fmt.Errorf("fetch jwks: %w", err)
+	}
+	keys := make(map[string]any, len(set.Keys))
+	for _, jwk := range set.Keys {
+		if jwk.Use != "" && jwk.Use != "sig" {
+			continue
+		}
+		key, err := jwk.publicKey()
+		if err != nil {
+			continue
+		}
+		keys[jwk.Kid] = key
+	}
+	if len(keys) == 0 {
+		return nil, errors.New("jwks contains no usable signing keys")
+	}
+	return keys, nil
+}
+
+func (k oidcJWK) publicKey() (any, error) {
+	switch k.Kty {
+	case "RSA":
+		n, err := base64.RawURLEncoding.DecodeString(k.N)
+		if err != nil {
+			return nil, err
+		}
+		e, err := base64.RawURLEncoding.DecodeString(k.E)
+		if err != nil {
+			return nil, err
+		}
+		exp := new(big.Int).SetBytes(e)
+		if !exp.IsInt64() || exp.Int64() <= 1 || exp.Int64() > 1<<31-1 {
+			return nil, errors.New("invalid rsa exponent")
+		}
+		return &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(exp.Int64())}, nil
+	case "EC":
+		var curve elliptic.Curve
+		switch k.Crv {
+		case "P-256":
+			curve = elliptic.P256()
+		case "P-384":
+			curve = elliptic.P384()
+		case "P-521":
+			curve = elliptic.P521()
+		default:
+			return nil, fmt.Errorf("unsupported ec curve %q", k.Crv)
+		}
+		x, err := base64.RawURLEncoding.DecodeString(k.X)
+		if err != nil {
+			return nil, err
+		}
+		y, err := base64.RawURLEncoding.DecodeString(k.Y)
+		if err != nil {
+			return nil, err
+		}
+		pub := &ecdsa.PublicKey{Curve: curve, X: new(big.Int).SetBytes(x), Y: new(big.Int).SetBytes(y)}
+		if !curve.IsOnCurve(pub.X, pub.Y) {
+			return nil, errors.New("ec point is not on curve")
+		}
+		return pub, nil
+	default:
+		return nil, fmt.Errorf("unsupported key type %q", k.Kty)
+	}
+}
+
+// ssoTokenResponse OAuth2 token 端点响应
+type ssoTokenResponse struct {
+	AccessToken string `json:"access_token"`
+	TokenType   string `json:"token_type"`
+	IDToken     string `json:"id_token"`
+	Error       string `json:"error"`
+	ErrorDesc   string `json:"error_description"`
+}
+
+// ssoExchangeCode 使用授权码换取 token（携带 PKCE code_verifier）。
+func ssoExchangeCode(ctx context.Context, httpClient *http.Client, cfg config.SSOProviderConfig, tokenURL, code, codeVerifier string) (*ssoTokenResponse, error) {
+	form := url.Values{}
+	form.Set("grant_type", "authorization_code")
+	form.Set("code", code)
+	form.Set("redirect_uri", cfg.RedirectURL)
+	form.Set("code_verifier", codeVerifier)
+	if cfg.TokenAuthMethod != "client_secret_basic" {
+		form.Set("client_id", cfg.ClientID)
+	}
+	if cfg.TokenAuthMethod == "client_secret_post" {
+		form.Set("client_secret", cfg.ClientSecret)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, strings.NewReader(form.Encode()))
+	if err != nil {
+		return nil, err
+	}
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded")
+	req.Header.Set("Accept", "application/json")
+	if cfg.TokenAuthMethod == "client_secret_basic" {
+		// RFC 6749 §2.3.1：凭据需先做 form-urlencode
+		req.SetBasicAuth(url.QueryEscape(cfg.ClientID), url.QueryEscape(cfg.ClientSecret))
+	}
+
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("request token: %w", err)
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, ssoMaxResponseBytes))
+	if err != nil {
+		return nil, fmt.Errorf("read token response: %w", err)
+	}
+
+	var token ssoTokenResponse
+	_ = json.Unmarshal(body, &token)
+	// GitHub 出错时也返回 200，需要检查 error 字段
+	if resp.StatusCode/100 != 2 || token.Error != "" {
+		return nil, fmt.Errorf("token exchange status=%d error=%q error_description=%q", resp.StatusCode, token.Error, token.ErrorDesc)
+	}
+	if token.AccessToken == "" {
+		return nil, errors.New("token response is missing access_token")
+	}
+	return &token, nil
+}
+
+// ssoGetJSON 发起 GET 请求并解码 JSON 响应；bearer 非空时附带 Authorization 头。
+func ssoGetJSON(ctx context.Context, httpClient *http.Client, endpoint, bearer string, out any) error {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Accept", "application/json")
+	if bearer != "" {
+		req.Header.Set("Authorization", "Bearer "+bearer)
+	}
+	resp, err := httpClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	body, err := io.ReadAll(io.LimitReader(resp.Body, ssoMaxResponseBytes))
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode/100 != 2 {
+		return fmt.Errorf("GET %s status=%d", endpoint, resp.StatusCode)
+	}
+	return json.Unmarshal(body, out)
+}
@@ -0,0 +1,625 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/mail"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/tidwall/gjson"
+)
+
+var (
+	ErrSSOProviderNotFound    = infraerrors.NotFound("SSO_PROVIDER_NOT_FOUND", "sso provider not found or disabled")
+	ErrSSODomainNotAllowed    = infraerrors.Forbidden("SSO_DOMAIN_NOT_ALLOWED", "a verified email from an allowed domain is required for this sign-in provider")
+	ErrSSOAccountExists       = infraerrors.Conflict("SSO_ACCOUNT_EXISTS", "an account with this email already exists; sign in and link this provider from your profile")
+	ErrSSOProviderLinked      = infraerrors.Conflict("SSO_PROVIDER_ALREADY_LINKED", "this provider is already linked to your account")
+	ErrSSOLastSignInMethod    = infraerrors.BadRequest("SSO_LAST_SIGN_IN_METHOD", "cannot unlink the only sign-in method of this account")
+	ErrSSOInvalidLinkRequest  = infraerrors.BadRequest("SSO_INVALID_LINK_REQUEST", "invalid or expired link request")
+	ErrSSOAuthenticateFailure = infraerrors.New(http.StatusBadGateway, "SSO_AUTHENTICATE_FAILED", "failed to authenticate with sso provider")
+)
+
+// SSOSyntheticEmailDomain 是无已验证邮箱的 SSO 用户的合成邮箱后缀（RFC 保留域名）。
+const SSOSyntheticEmailDomain = "@sso.invalid"
+
+const (
+	ssoHTTPTimeout = 30 * time.Second
+	// ssoLinkIntentTTL 绑定请求的有效期（与授权流程 cookie 一致）
+	ssoLinkIntentTTL = 10 * time.Minute
+)
+
+// SSOProviderInfo 公开给前端的提供方信息
+type SSOProviderInfo struct {
+	ID   string `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+}
+
+// SSOIdentityClaims 从提供方获取并映射后的身份信息
+type SSOIdentityClaims struct {
+	Provider      string
+	Subject       string
+	Email         string
+	EmailVerified bool
+	Username      string
+	Groups        []string
+}
+
+// SSOAuthRequest 发起授权所需的一次性参数（由调用方生成并保存在 cookie 中）
+type SSOAuthRequest struct {
+	State         string
+	Nonce         string
+	CodeChallenge string
+}
+
+// ssoAuthenticator 登录/注册与签发 token，由 AuthService 实现
+type ssoAuthenticator interface {
+	LoginOrRegisterOAuthWithTokenPair(ctx context.Context, email, username string) (*TokenPair, *User, error)
+	GenerateTokenPair(ctx context.Context, user *User, familyID string) (*TokenPair, error)
+}
+
+// ssoTotpGate 与密码登录一致的 TOTP 二次验证，由 TotpService 实现
+type ssoTotpGate interface {
+	LoginRequiresTotp(ctx context.Context, user *User) bool
+	CreateLoginSession(ctx context.Context, userID int64, email string) (string, error)
+}
+
+// SSOLoginResult SSO 登录结果。
+// 用户启用了 TOTP 时不签发 TokenPair，只返回 TotpTempToken，需通过 /auth/login/2fa 完成登录。
+type SSOLoginResult struct {
+	TokenPair     *TokenPair
+	User          *User
+	TotpTempToken string
+}
+
+// SSOService 处理通用 OIDC / GitHub / Google 单点登录与账号绑定。
+type SSOService struct {
+	providers    map[string]config.SSOProviderConfig
+	oidcClients  map[string]*oidcClient
+	httpClient   *http.Client
+	identityRepo UserIdentityRepository
+	userRepo     UserRepository
+	auth         ssoAuthenticator
+	totp         ssoTotpGate
+	linkSecret   []byte
+}
+
+// NewSSOService 创建 SSO 服务；仅加载已启用的提供方。
+func NewSSOService(cfg *config.Config, identityRepo UserIdentityRepository, userRepo UserRepository, authService *AuthService, totpService *TotpService) *SSOService {
+	return newSSOService(cfg, identityRepo, userRepo, authService, totpService, &http.Client{Timeout: ssoHTTPTimeout})
+}
+
+func newSSOService(cfg *config.Config, identityRepo UserIdentityRepository, userRepo UserRepository, auth ssoAuthenticator, totp ssoTotpGate, httpClient *http.Client) *SSOService {
+	s := &SSOService{
+		providers:    make(map[string]config.SSOProviderConfig),
+		oidcClients:  make(map[string]*oidcClient),
+		httpClient:   httpClient,
+		identityRepo: identityRepo,
+		userRepo:     userRepo,
+		auth:         auth,
+		totp:         totp,
+	}
+	if cfg == nil {
+		return s
+	}
+	// 绑定请求签名密钥由 JWT 密钥派生，避免与 JWT 签名直接复用同一密钥
+	mac := hmac.New(sha256.New, []byte(cfg.JWT.Secret))
+	mac.Write([]byte("sub2api-sso-link"))
+	s.linkSecret = mac.Sum(nil)
+
+	for _, p := range cfg.SSO.Providers {
+		if !p.Enabled {
+			continue
+		}
+		s.providers[p.ID] = p
+		if p.Type != config.SSOProviderTypeGitHub {
+			s.oidcClients[p.ID] = newOIDCClient(p, httpClient)
+		}
+	}
+	return s
+}
+
+// enabledSSOProviders 返回已启用的提供方（按配置顺序），供公开设置展示登录按钮
+func enabledSSOProviders(cfg *config.Config) []SSOProviderInfo {
+	out := make([]SSOProviderInfo, 0)
+	if cfg == nil {
+		return out
+	}
+	for _, p := range cfg.SSO.Providers {
+		if p.Enabled {
+			out = append(out, SSOProviderInfo{ID: p.ID, Name: p.DisplayName, Type: p.Type})
+		}
+	}
+	return out
+}
+
+// Provider 返回已启用提供方的配置
+func (s *SSOService) Provider(providerID string) (config.SSOProviderConfig, error) {
+	if s == nil {
+		return config.SSOProviderConfig{}, ErrSSOProviderNotFound
+	}
+	p, ok := s.providers[providerID]
+	if !ok {
+		return config.SSOProviderConfig{}, ErrSSOProviderNotFound
+	}
+	return p, nil
+}
+
+// AuthorizeURL 构造提供方授权地址（始终使用 PKCE S256）。
+func (s *SSOService) AuthorizeURL(ctx context.Context, providerID string, req SSOAuthRequest) (string, error) {
+	p, err := s.Provider(providerID)
+	if err != nil {
+		return "", err
+	}
+	authorizeURL := p.AuthorizeURL
+	if client := s.oidcClients[providerID]; client != nil {
+		eps, err := client.endpoints(ctx)
+		if err != nil {
+			logger.LegacyPrintf("service.sso", "[SSO] provider=%s discovery failed: %v", providerID, err)
+			return "", ErrSSOAuthenticateFailure.WithCause(err)
+		}
+		authorizeURL = eps.AuthorizationEndpoint
+	}
+
+	u, err := url.Parse(authorizeURL)
+	if err != nil {
+		return "", fmt.Errorf("parse authorize url: %w", err)
+	}
+	q := u.Query()
+	q.Set("response_type", "code")
+	q.Set("client_id", p.ClientID)
+	q.Set("redirect_uri", p.RedirectURL)
+	q.Set("scope", p.Scopes)
+	q.Set("state", req.State)
+	q.Set("code_challenge", req.CodeChallenge)
+	q.Set("code_challenge_method", "S256")
+	if p.Type != config.SSOProviderTypeGitHub {
+		q.Set("nonce", req.Nonce)
+	}
+	if p.Type == config.SSOProviderTypeGoogle && len(p.AllowedDomains) == 1 {
+		// 提示 Google 只展示该 Workspace 域的账号（服务端仍会校验）
+		q.Set("hd", p.AllowedDomains[0])
+	}
+	u.RawQuery = q.Encode()
+	return u.String(), nil
+}
+
+// Authenticate 使用授权码换取 token，并获取、校验、映射身份信息。
+func (s *SSOService) Authenticate(ctx context.Context, providerID, code, codeVerifier, nonce string) (*SSOIdentityClaims, error) {
+	p, err := s.Provider(providerID)
+	if err != nil {
+		return nil, err
+	}
+	var claims *SSOIdentityClaims
+	if p.Type == config.SSOProviderTypeGitHub {
+		claims, err = s.authenticateGitHub(ctx, p, code, codeVerifier)
+	} else {
+		claims, err = s.authenticateOIDC(ctx, p, s.oidcClients[providerID], code, codeVerifier, nonce)
+	}
+	if err != nil {
+		logger.LegacyPrintf("service.sso", "[SSO] provider=%s authenticate failed: %v", providerID, err)
+		return nil, ErrSSOAuthenticateFailure.WithCause(err)
+	}
+	if claims.Subject == "" {
+		return nil, ErrSSOAuthenticateFailure
+	}
+	if claims.Email != "" {
+		if addr, err := mail.ParseAddress(claims.Email); err != nil || addr.Address != claims.Email {
+			claims.Email = ""
+			claims.EmailVerified = false
+		}
+	}
+	claims.Provider = providerID
+	return claims, nil
+}
+
+func (s *SSOService) authenticateOIDC(ctx context.Context, p config.SSOProviderConfig, client *oidcClient, code, codeVerifier, nonce string) (*SSOIdentityClaims, error) {
+	eps, err := client.endpoints(ctx)
+	if err != nil {
+		return nil, err
+	}
+	token, err := ssoExchangeCode(ctx, s.httpClient, p, eps.TokenEndpoint, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+	if token.IDToken == "" {
+		return nil, errors.New("token response is missing id_token")
+	}
+	idClaims, err := client.verifyIDToken(ctx, token.IDToken, nonce)
+	if err != nil {
+		return nil, err
+	}
+
+	merged := map[string]any(idClaims)
+	if eps.UserinfoEndpoint != "" {
+		var info map[string]any
+		if err := ssoGetJSON(ctx, s.httpClient, eps.UserinfoEndpoint, token.AccessToken, &info); err != nil {
+			return nil, fmt.Errorf("fetch userinfo: %w", err)
+		}
+		// OIDC Core §5.3.2：userinfo 的 sub 必须与 ID Token 一致
+		if sub, _ := info["sub"].(string); sub != "" && sub != idClaims["sub"] {
+			return nil, errors.New("userinfo sub does not match id_token")
+		}
+		merged = make(map[string]any, len(idClaims)+len(info))
+		for k, v := range info {
+			merged[k] = v
+		}
+		for k, v := range idClaims {
+			merged[k] = v
+		}
+	}
+	raw, err := json.Marshal(merged)
+	if err != nil {
+		return nil, err
+	}
+	return mapOIDCClaims(string(raw), p), nil
+}
+
+// mapOIDCClaims 按配置的声明路径提取身份信息。
+func mapOIDCClaims(body string, p config.SSOProviderConfig) *SSOIdentityClaims {
+	claims := &SSOIdentityClaims{
+		Subject: gjson.Get(body, "sub").String(),
+		Email:   strings.TrimSpace(gjson.Get(body, p.EmailClaim).String()),
+	}
+	// 缺少 email_verified 视为未验证：不参与邮箱绑定与域名白名单
+	verified := gjson.Get(body, "email_verified")
+	claims.EmailVerified = verified.Type == gjson.True || strings.EqualFold(verified.String(), "true")
+
+	if p.UsernameClaim != "" {
+		claims.Username = gjson.Get(body, p.UsernameClaim).String()
+	} else {
+		claims.Username = firstNonEmptyString(gjson.Get(body, "preferred_username").String(), gjson.Get(body, "name").String())
+	}
+	groups := gjson.Get(body, p.GroupsClaim)
+	if groups.IsArray() {
+		for _, g := range groups.Array() {
+			if v := strings.TrimSpace(g.String()); v != "" {
+				claims.Groups = append(claims.Groups, v)
+			}
+		}
+	} else if v := strings.TrimSpace(groups.String()); v != "" {
+		claims.Groups = []string{v}
+	}
+	return claims
+}
+
+func (s *SSOService) authenticateGitHub(ctx context.Context, p config.SSOProviderConfig, code, codeVerifier string) (*SSOIdentityClaims, error) {
+	token, err := ssoExchangeCode(ctx, s.httpClient, p, p.TokenURL, code, codeVerifier)
+	if err != nil {
+		return nil, err
+	}
+
+	var user struct {
+		ID    int64  `json:"id"`
+		Login string `json:"login"`
+		Name  string `json:"name"`
+	}
+	if err := ssoGetJSON(ctx, s.httpClient, p.APIBaseURL+"/user", token.AccessToken, &user); err != nil {
+		return nil, fmt.Errorf("fetch github user: %w", err)
+	}
+	if user.ID <= 0 {
+		return nil, errors.New("github user response is missing id")
+	}
+	claims := &SSOIdentityClaims{
+		Subject:  strconv.FormatInt(user.ID, 10),
+		Username: firstNonEmptyString(user.Login, user.Name),
+	}
+
+	var emails []struct {
+		Email    string `json:"email"`
+		Primary  bool   `json:"primary"`
+		Verified bool   `json:"verified"`
+	}
+	if err := ssoGetJSON(ctx, s.httpClient, p.APIBaseURL+"/user/emails", token.AccessToken, &emails); err != nil {
+		return nil, fmt.Errorf("fetch github emails: %w", err)
+	}
+	for _, e := range emails {
+		if e.Primary && e.Verified {
+			claims.Email = strings.TrimSpace(e.Email)
+			claims.EmailVerified = true
+			break
+		}
+	}
+
+	if len(p.GroupMappings) > 0 {
+		var orgs []struct {
+			Login string `json:"login"`
+		}
+		if err := ssoGetJSON(ctx, s.httpClient, p.APIBaseURL+"/user/orgs", token.AccessToken, &orgs); err != nil {
+			return nil, fmt.Errorf("fetch github orgs: %w", err)
+		}
+		for _, org := range orgs {
+			claims.Groups = append(claims.Groups, org.Login)
+		}
+	}
+	return claims, nil
+}
+
+// Login 根据身份信息登录：已绑定身份直接登录；否则按邮箱绑定或注册新用户。
+// 登录到已有用户时与密码登录一样执行 TOTP 二次验证。
+func (s *SSOService) Login(ctx context.Context, claims *SSOIdentityClaims) (*SSOLoginResult, error) {
+	p, err := s.Provider(claims.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSSODomain(p, claims); err != nil {
+		return nil, err
+	}
+
+	identity, err := s.identityRepo.GetByProviderSubject(ctx, claims.Provider, claims.Subject)
+	switch {
+	case err == nil:
+		user, err := s.userRepo.GetByID(ctx, identity.UserID)
+		if err != nil {
+			if errors.Is(err, ErrUserNotFound) {
+				return nil, ErrUserNotFound
+			}
+			logger.LegacyPrintf("service.sso", "[SSO] Database error loading linked user: %v", err)
+			return nil, ErrServiceUnavailable
+		}
+		return s.finishLogin(ctx, p, identity, user, claims.Groups)
+	case !errors.Is(err, ErrUserIdentityNotFound):
+		logger.LegacyPrintf("service.sso", "[SSO] Database error loading identity: %v", err)
+		return nil, ErrServiceUnavailable
+	}
+
+	if claims.EmailVerified && claims.Email != "" {
+		existing, err := s.userRepo.GetByEmail(ctx, claims.Email)
+		switch {
+		case err == nil:
+			if !p.LinkExistingUsers {
+				return nil, ErrSSOAccountExists
+			}
+			identity, err := s.createIdentity(ctx, existing.ID, claims)
+			if err != nil {
+				return nil, err
+			}
+			return s.finishLogin(ctx, p, identity, existing, claims.Groups)
+		case !errors.Is(err, ErrUserNotFound):
+			logger.LegacyPrintf("service.sso", "[SSO] Database error looking up user by email: %v", err)
+			return nil, ErrServiceUnavailable
+		}
+	}
+
+	// 首次登录：有已验证邮箱时使用真实邮箱注册，否则使用基于 subject 的合成邮箱
+	email := claims.Email
+	if !claims.EmailVerified || email == "" {
+		email = ssoSyntheticEmail(claims.Provider, claims.Subject)
+	}
+	tokenPair, user, err := s.auth.LoginOrRegisterOAuthWithTokenPair(ctx, email, claims.Username)
+	if err != nil {
+		return nil, err
+	}
+	identity, err = s.createIdentity(ctx, user.ID, claims)
+	if err != nil {
+		return nil, err
+	}
+	s.applyGroupMappings(ctx, p, user.ID, claims.Groups)
+	s.touchIdentity(ctx, identity)
+	return &SSOLoginResult{TokenPair: tokenPair, User: user}, nil
+}
+
+func (s *SSOService) finishLogin(ctx context.Context, p config.SSOProviderConfig, identity *UserIdentity, user *User, groups []string) (*SSOLoginResult, error) {
+	if !user.IsActive() {
+		return nil, ErrUserNotActive
+	}
+	s.applyGroupMappings(ctx, p, user.ID, groups)
+	s.touchIdentity(ctx, identity)
+
+	// 提供方只证明了邮箱/身份归属，不能替代用户自己配置的第二因素
+	if s.totp != nil && s.totp.LoginRequiresTotp(ctx, user) {
+		tempToken, err := s.totp.CreateLoginSession(ctx, user.ID, user.Email)
+		if err != nil {
+			return nil, fmt.Errorf("create 2fa login session: %w", err)
+		}
+		return &SSOLoginResult{User: user, TotpTempToken: tempToken}, nil
+	}
+
+	tokenPair, err := s.auth.GenerateTokenPair(ctx, user, "")
+	if err != nil {
+		return nil, fmt.Errorf("generate token pair: %w", err)
+	}
+	return &SSOLoginResult{TokenPair: tokenPair, User: user}, nil
+}
+
+// Link 将身份绑定到已登录用户。
+func (s *SSOService) Link(ctx context.Context, userID int64, claims *SSOIdentityClaims) (*UserIdentity, error) {
+	p, err := s.Provider(claims.Provider)
+	if err != nil {
+		return nil, err
+	}
+	if err := checkSSODomain(p, claims); err != nil {
+		return nil, err
+	}
+
+	existing, err := s.identityRepo.GetByProviderSubject(ctx, claims.Provider, claims.Subject)
+	if err == nil {
+		if existing.UserID == userID {
+			return existing, nil
+		}
+		return nil, ErrUserIdentityExists
+	}
+	if !errors.Is(err, ErrUserIdentityNotFound) {
+		return nil, err
+	}
+
+	identities, err := s.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	for _, identity := range identities {
+		if identity.Provider == claims.Provider {
+			return nil, ErrSSOProviderLinked
+		}
+	}
+
+	identity, err := s.createIdentity(ctx, userID, claims)
+	if err != nil {
+		return nil, err
+	}
+	s.applyGroupMappings(ctx, p, userID, claims.Groups)
+	return identity, nil
+}
+
+// ListIdentities 列出用户已绑定的身份
+func (s *SSOService) ListIdentities(ctx context.Context, userID int64) ([]UserIdentity, error) {
+	return s.identityRepo.ListByUser(ctx, userID)
+}
+
+// Unlink 解除绑定；合成邮箱用户（无法使用密码登录）不能解除最后一个身份。
+func (s *SSOService) Unlink(ctx context.Context, userID, identityID int64) error {
+	identities, err := s.identityRepo.ListByUser(ctx, userID)
+	if err != nil {
+		return err
+	}
+	found := false
+	for _, identity := range identities {
+		if identity.ID == identityID {
+			found = true
+			break
+		}
+	}
+	if !found {
+		return ErrUserIdentityNotFound
+	}
+	if len(identities) == 1 {
+		user, err := s.userRepo.GetByID(ctx, userID)
+		if err != nil {
+			return err
+		}
+		if isReservedEmail(user.Email) {
+			return ErrSSOLastSignInMethod
+		}
+	}
+	return s.identityRepo.Delete(ctx, userID, identityID)
+}
+
+// SignLinkIntent 签发绑定请求凭证：将用户与本次授权的 state 绑定，防止回调被他人复用。
+func (s *SSOService) SignLinkIntent(userID int64, providerID, state string) string {
+	expiresAt := time.Now().Add(ssoLinkIntentTTL).Unix()
+	payload := fmt.Sprintf("%d.%s.%d", userID, providerID, expiresAt)
+	return payload + "." + s.linkSignature(payload, state)
+}
+
+// VerifyLinkIntent 校验绑定请求凭证，返回发起绑定的用户 ID。
+func (s *SSOService) VerifyLinkIntent(token, providerID, state string) (int64, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 4 || parts[1] != providerID {
+		return 0, ErrSSOInvalidLinkRequest
+	}
+	payload := strings.Join(parts[:3], ".")
+	if !hmac.Equal([]byte(parts[3]), []byte(s.linkSignature(payload, state))) {
+		return 0, ErrSSOInvalidLinkRequest
+	}
+	expiresAt, err := strconv.ParseInt(parts[2], 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return 0, ErrSSOInvalidLinkRequest
+	}
+	userID, err := strconv.ParseInt(parts[0], 10, 64)
+	if err != nil || userID <= 0 {
+		return 0, ErrSSOInvalidLinkRequest
+	}
+	return userID, nil
+}
+
+func (s *SSOService) linkSignature(payload, state string) string {
+	mac := hmac.New(sha256.New, s.linkSecret)
+	mac.Write([]byte(payload))
+	mac.Write([]byte{0})
+	mac.Write([]byte(state))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *SSOService) createIdentity(ctx context.Context, userID int64, claims *SSOIdentityClaims) (*UserIdentity, error) {
+	identity := &UserIdentity{
+		UserID:   userID,
+		Provider: claims.Provider,
+		Subject:  claims.Subject,
+		Email:    claims.Email,
+	}
+	if err := s.identityRepo.Create(ctx, identity); err != nil {
+		if errors.Is(err, ErrUserIdentityExists) {
+			// 并发登录时另一请求已完成绑定
+			existing, getErr := s.identityRepo.GetByProviderSubject(ctx, claims.Provider, claims.Subject)
+			if getErr == nil && existing.UserID == userID {
+				return existing, nil
+			}
+		}
+		return nil, err
+	}
+	return identity, nil
+}
+
+func (s *SSOService) touchIdentity(ctx context.Context, identity *UserIdentity) {
+	if err := s.identityRepo.TouchLastLogin(ctx, identity.ID, time.Now()); err != nil {
+		logger.LegacyPrintf("service.sso", "[SSO] Failed to update identity last login: %v", err)
+	}
+}
+
+// applyGroupMappings 将提供方的组映射为用户可用的专属分组（只增不减）。
+func (s *SSOService) applyGroupMappings(ctx context.Context, p config.SSOProviderConfig, userID int64, groups []string) {
+	if len(p.GroupMappings) == 0 || len(groups) == 0 {
+		return
+	}
+	// viper 会把 map 键转为小写，这里按不区分大小写匹配
+	lowered := make(map[string][]int64, len(p.GroupMappings))
+	for name, ids := range p.GroupMappings {
+		lowered[strings.ToLower(name)] = ids
+	}
+	seen := make(map[int64]struct{})
+	for _, group := range groups {
+		for _, groupID := range lowered[strings.ToLower(group)] {
+			if _, ok := seen[groupID]; ok {
+				continue
+			}
+			seen[groupID] = struct{}{}
+			if err := s.userRepo.AddGroupToAllowedGroups(ctx, userID, groupID); err != nil {
+				logger.LegacyPrintf("service.sso", "[SSO] Failed to add group %d to user %d: %v", groupID, userID, err)
+			}
+		}
+	}
+}
+
+// checkSSODomain 配置了域名白名单时，要求已验证邮箱且域名命中。
+func checkSSODomain(p config.SSOProviderConfig, claims *SSOIdentityClaims) error {
+	if len(p.AllowedDomains) == 0 {
+		return nil
+	}
+	if !claims.EmailVerified || claims.Email == "" {
+		return ErrSSODomainNotAllowed
+	}
+	domain := strings.ToLower(claims.Email[strings.LastIndex(claims.Email, "@")+1:])
+	for _, allowed := range p.AllowedDomains {
+		if domain == allowed {
+			return nil
+		}
+	}
+	return ErrSSODomainNotAllowed
+}
+
+func ssoSyntheticEmail(providerID, subject string) string {
+	sum := sha256.Sum256([]byte(providerID + "\x00" + subject))
+	return "sso-" + providerID + "-" + hex.EncodeToString(sum[:8]) + SSOSyntheticEmailDomain
+}
+
+func firstNonEmptyString(values ...string) string {
+	for _, v := range values {
+		if v = strings.TrimSpace(v); v != "" {
+			return v
+		}
+	}
+	return ""
+}
@@ -0,0 +1,513 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/golang-jwt/jwt/v5"
+	"github.com/stretchr/testify/require"
+)
+
+// fakeOIDCIssuer 本地 OIDC 提供方：discovery / jwks / token / userinfo
+type fakeOIDCIssuer struct {
+	t      *testing.T
+	server *httptest.Server
+	key    *rsa.PrivateKey
+	kid    string
+	// issuerSuffix 追加在 discovery 与 ID Token 的 issuer 之后（如 Auth0 的末尾 "/"）
+	issuerSuffix string
+
+	mu        sync.Mutex
+	claims    map[string]any // 下一次签发 ID Token 的附加声明
+	nonce     string
+	challenge string
+	userinfo  map[string]any
+}
+
+func newFakeOIDCIssuer(t *testing.T) *fakeOIDCIssuer {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	require.NoError(t, err)
+	f := &fakeOIDCIssuer{t: t, key: key, kid: "k1"}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/.well-known/openid-configuration", func(w http.ResponseWriter, r *http.Request) {
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"issuer":                 f.server.URL + f.issuerSuffix,
+			"authorization_endpoint": f.server.URL + "/authorize",
+			"token_endpoint":         f.server.URL + "/token",
+			"userinfo_endpoint":      f.server.URL + "/userinfo",
+			"jwks_uri":               f.server.URL + "/jwks",
+		})
+	})
+	mux.HandleFunc("/jwks", func(w http.ResponseWriter, r *http.Request) {
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(map[string]any{"keys": []map[string]string{{
+			"kty": "RSA",
+			"kid": f.kid,
+			"use": "sig",
+			"n":   base64.RawURLEncoding.EncodeToString(f.key.N.Bytes()),
+			"e":   base64.RawURLEncoding.EncodeToString(big.NewInt(int64(f.key.E)).Bytes()),
+		}}})
+	})
+	mux.HandleFunc("/token", func(w http.ResponseWriter, r *http.Request) {
+		user, pass, ok := r.BasicAuth()
+		if !ok || user != "client-1" || pass != "secret-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			_, _ = w.Write([]byte(`{"error":"invalid_client"}`))
+			return
+		}
+		_ = r.ParseForm()
+		sum := sha256.Sum256([]byte(r.PostForm.Get("code_verifier")))
+		f.mu.Lock()
+		challenge := f.challenge
+		f.mu.Unlock()
+		if r.PostForm.Get("code") != "good-code" || base64.RawURLEncoding.EncodeToString(sum[:]) != challenge {
+			w.WriteHeader(http.StatusBadRequest)
+			_, _ = w.Write([]byte(`{"error":"invalid_grant"}`))
+			return
+		}
+		_ = json.NewEncoder(w).Encode(map[string]string{
+			"access_token": "at-1",
+			"token_type":   "Bearer",
+			"id_token":     f.signIDToken(),
+		})
+	})
+	mux.HandleFunc("/userinfo", func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer at-1" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		f.mu.Lock()
+		defer f.mu.Unlock()
+		_ = json.NewEncoder(w).Encode(f.userinfo)
+	})
+	f.server = httptest.NewServer(mux)
+	t.Cleanup(f.server.Close)
+	return f
+}
+
+func (f *fakeOIDCIssuer) signIDToken() string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	now := time.Now()
+	claims := jwt.MapClaims{
+		"iss":   f.server.URL + f.issuerSuffix,
+		"aud":   "client-1",
+		"sub":   "user-123",
+		"iat":   now.Unix(),
+		"exp":   now.Add(5 * time.Minute).Unix(),
+		"nonce": f.nonce,
+	}
+	for k, v := range f.claims {
+		claims[k] = v
+	}
+	token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+	token.Header["kid"] = f.kid
+	signed, err := token.SignedString(f.key)
+	require.NoError(f.t, err)
+	return signed
+}
+
+// authorize 模拟浏览器走完授权页：记录 PKCE challenge 与 nonce
+func (f *fakeOIDCIssuer) authorize(t *testing.T, authURL string) {
+	t.Helper()
+	u, err := url.Parse(authURL)
+	require.NoError(t, err)
+	require.Equal(t, f.server.URL+"/authorize", u.Scheme+"://"+u.Host+u.Path)
+	q := u.Query()
+	require.Equal(t, "S256", q.Get("code_challenge_method"))
+	require.Equal(t, "client-1", q.Get("client_id"))
+	f.mu.Lock()
+	f.challenge = q.Get("code_challenge")
+	f.nonce = q.Get("nonce")
+	f.mu.Unlock()
+}
+
+type ssoIdentityRepoStub struct {
+	identities map[int64]*UserIdentity
+	nextID     int64
+}
+
+func (r *ssoIdentityRepoStub) Create(_ context.Context, identity *UserIdentity) error {
+	for _, existing := range r.identities {
+		if existing.Provider == identity.Provider && existing.Subject == identity.Subject {
+			return ErrUserIdentityExists
+		}
+	}
+	r.nextID++
+	identity.ID = r.nextID
+	identity.CreatedAt = time.Now()
+	cp := *identity
+	r.identities[identity.ID] = &cp
+	return nil
+}
+
+func (r *ssoIdentityRepoStub) GetByProviderSubject(_ context.Context, provider, subject string) (*UserIdentity, error) {
+	for _, existing := range r.identities {
+		if existing.Provider == provider && existing.Subject == subject {
+			cp := *existing
+			return &cp, nil
+		}
+	}
+	return nil, ErrUserIdentityNotFound
+}
+
+func (r *ssoIdentityRepoStub) ListByUser(_ context.Context, userID int64) ([]UserIdentity, error) {
+	var out []UserIdentity
+	for id := int64(1); id <= r.nextID; id++ {
+		if existing, ok := r.identities[id]; ok && existing.UserID == userID {
+			out = append(out, *existing)
+		}
+	}
+	return out, nil
+}
+
+func (r *ssoIdentityRepoStub) Delete(_ context.Context, userID, id int64) error {
+	existing, ok := r.identities[id]
+	if !ok || existing.UserID != userID {
+		return ErrUserIdentityNotFound
+	}
+	delete(r.identities, id)
+	return nil
+}
+
+func (r *ssoIdentityRepoStub) TouchLastLogin(_ context.Context, id int64, at time.Time) error {
+	if existing, ok := r.identities[id]; ok {
+		existing.LastLoginAt = &at
+	}
+	return nil
+}
+
+type ssoUserRepoStub struct {
+	userRepoStub
+	users         map[int64]*User
+	allowedGroups map[int64][]int64
+}
+
+func (r *ssoUserRepoStub) GetByID(_ context.Context, id int64) (*User, error) {
+	if u, ok := r.users[id]; ok {
+		return u, nil
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *ssoUserRepoStub) GetByEmail(_ context.Context, email string) (*User, error) {
+	for _, u := range r.users {
+		if strings.EqualFold(u.Email, email) {
+			return u, nil
+		}
+	}
+	return nil, ErrUserNotFound
+}
+
+func (r *ssoUserRepoStub) AddGroupToAllowedGroups(_ context.Context, userID, groupID int64) error {
+	r.allowedGroups[userID] = append(r.allowedGroups[userID], groupID)
+	return nil
+}
+
+// ssoAuthStub 模拟 AuthService：按邮箱登录或注册
+type ssoAuthStub struct {
+	repo   *ssoUserRepoStub
+	nextID int64
+}
+
+func (a *ssoAuthStub) LoginOrRegisterOAuthWithTokenPair(ctx context.Context, email, username string) (*TokenPair, *User, error) {
+	user, err := a.repo.GetByEmail(ctx, email)
+	if err != nil {
+		a.nextID++
+		user = &User{ID: a.nextID, Email: email, Username: username, Status: StatusActive}
+		a.repo.users[user.ID] = user
+	}
+	pair, _ := a.GenerateTokenPair(ctx, user, "")
+	return pair, user, nil
+}
+
+func (a *ssoAuthStub) GenerateTokenPair(_ context.Context, user *User, _ string) (*TokenPair, error) {
+	return &TokenPair{AccessToken: "access-" + user.Email}, nil
+}
+
+// ssoTotpStub 模拟 TotpService：站点开启 TOTP 时按用户设置要求二次验证
+type ssoTotpStub struct {
+	disabled bool
+	sessions map[string]int64
+}
+
+func (s *ssoTotpStub) LoginRequiresTotp(_ context.Context, user *User) bool {
+	return !s.disabled && user.TotpEnabled
+}
+
+func (s *ssoTotpStub) CreateLoginSession(_ context.Context, userID int64, _ string) (string, error) {
+	token := fmt.Sprintf("temp-%d", userID)
+	s.sessions[token] = userID
+	return token, nil
+}
+
+type ssoTestEnv struct {
+	svc        *SSOService
+	issuer     *fakeOIDCIssuer
+	users      *ssoUserRepoStub
+	identities *ssoIdentityRepoStub
+	totp       *ssoTotpStub
+}
+
+func newSSOTestEnv(t *testing.T, mutate func(p *config.SSOProviderConfig)) *ssoTestEnv {
+	t.Helper()
+	issuer := newFakeOIDCIssuer(t)
+	provider := config.SSOProviderConfig{
+		ID:           "corp",
+		Type:         config.SSOProviderTypeOIDC,
+		Enabled:      true,
+		ClientID:     "client-1",
+		ClientSecret: "secret-1",
+		Issuer:       issuer.server.URL,
+		Scopes:       "openid email profile",
+		RedirectURL:  "https://sub2api.test/api/v1/auth/sso/corp/callback",
+		// 以下为 normalizeSSOConfig 填充的默认值
+		TokenAuthMethod: "client_secret_basic",
+		EmailClaim:      "email",
+		GroupsClaim:     "groups",
+		GroupMappings: map[string][]int64{
+			"engineering": {7},
+		},
+	}
+	if mutate != nil {
+		mutate(&provider)
+	}
+	cfg := &config.Config{SSO: config.SSOConfig{Providers: []config.SSOProviderConfig{provider}}}
+	cfg.JWT.Secret = "jwt-secret"
+
+	users := &ssoUserRepoStub{users: map[int64]*User{}, allowedGroups: map[int64][]int64{}}
+	identities := &ssoIdentityRepoStub{identities: map[int64]*UserIdentity{}}
+	auth := &ssoAuthStub{repo: users, nextID: 100}
+	totp := &ssoTotpStub{sessions: map[string]int64{}}
+	svc := newSSOService(cfg, identities, users, auth, totp, issuer.server.Client())
+	return &ssoTestEnv{svc: svc, issuer: issuer, users: users, identities: identities, totp: totp}
+}
+
+// authenticate 走完一次完整的授权码流程
+func (e *ssoTestEnv) authenticate(t *testing.T) (*SSOIdentityClaims, error) {
+	t.Helper()
+	verifier := "verifier-0123456789-0123456789-0123456789"
+	sum := sha256.Sum256([]byte(verifier))
+	authURL, err := e.svc.AuthorizeURL(context.Background(), "corp", SSOAuthRequest{
+		State:         "state-1",
+		Nonce:         "nonce-1",
+		CodeChallenge: base64.RawURLEncoding.EncodeToString(sum[:]),
+	})
+	require.NoError(t, err)
+	e.issuer.authorize(t, authURL)
+	return e.svc.Authenticate(context.Background(), "corp", "good-code", verifier, "nonce-1")
+}
+
+func TestSSOService_OIDCLoginRegistersAndLinksIdentity(t *testing.T) {
+	env := newSSOTestEnv(t, nil)
+	env.issuer.claims = map[string]any{"email": "alice@example.com", "email_verified": true}
+	env.issuer.userinfo = map[string]any{"sub": "user-123", "preferred_username": "alice", "groups": []string{"Engineering", "sales"}}
+
+	claims, err := env.authenticate(t)
+	require.NoError(t, err)
+	require.Equal(t, "corp", claims.Provider)
+	require.Equal(t, "user-123", claims.Subject)
+	require.Equal(t, "alice@example.com", claims.Email)
+	require.True(t, claims.EmailVerified)
+	require.Equal(t, "alice", claims.Username)
+	require.Equal(t, []string{"Engineering", "sales"}, claims.Groups)
+
+	result, err := env.svc.Login(context.Background(), claims)
+	require.NoError(t, err)
+	user := result.User
+	require.Equal(t, "alice@example.com", user.Email)
+	require.Equal(t, "access-alice@example.com", result.TokenPair.AccessToken)
+	require.Equal(t, []int64{7}, env.users.allowedGroups[user.ID], "组映射不区分大小写")
+
+	identity, err := env.identities.GetByProviderSubject(context.Background(), "corp", "user-123")
+	require.NoError(t, err)
+	require.Equal(t, user.ID, identity.UserID)
+	require.NotNil(t, identity.LastLoginAt)
+
+	// 再次登录直接命中已绑定身份
+	again, err := env.svc.Login(context.Background(), claims)
+	require.NoError(t, err)
+	require.Equal(t, user.ID, again.User.ID)
+	require.Len(t, env.identities.identities, 1)
+}
+
+func TestSSOService_AcceptsTrailingSlashIssuer(t *testing.T) {
+	env := newSSOTestEnv(t, nil)
+	env.issuer.issuerSuffix = "/"
+	env.issuer.claims = map[string]any{"email": "alice@example.com", "email_verified": true}
+	env.issuer.userinfo = map[string]any{"sub": "user-123"}
+
+	claims, err := env.authenticate(t)
+	require.NoError(t, err)
+	require.Equal(t, "user-123", claims.Subject)
+
+	// 仍需逐字匹配 discovery 声明的 issuer
+	env.issuer.claims = map[string]any{"iss": env.issuer.server.URL}
+	_, err = env.authenticate(t)
+	require.ErrorIs(t, err, ErrSSOAuthenticateFailure)
+}
+
+func TestSSOService_RejectsInvalidIDToken(t *testing.T) {
+	cases := map[string]map[string]any{
+		"wrong audience": {"aud": "other-client"},
+		"wrong issuer":   {"iss": "https://evil.test"},
+		"expired":        {"exp": time.Now().Add(-time.Hour).Unix()},
+		"wrong nonce":    {"nonce": "replayed"},
+	}
+	for name, override := range cases {
+		t.Run(name, func(t *testing.T) {
+			env := newSSOTestEnv(t, nil)
+			env.issuer.claims = override
+			env.issuer.userinfo = map[string]any{"sub": "user-123"}
+			_, err := env.authenticate(t)
+			require.ErrorIs(t, err, ErrSSOAuthenticateFailure)
+		})
+	}
+
+	t.Run("forged signature", func(t *testing.T) {
+		env := newSSOTestEnv(t, nil)
+		env.issuer.userinfo = map[string]any{"sub": "user-123"}
+		_, err := env.authenticate(t)
+		require.NoError(t, err)
+
+		// 使用同一 kid 但不同的私钥签名，客户端缓存的公钥校验必须失败
+		other, err := rsa.GenerateKey(rand.Reader, 2048)
+		require.NoError(t, err)
+		env.issuer.mu.Lock()
+		env.issuer.key = other
+		env.issuer.mu.Unlock()
+		_, err = env.authenticate(t)
+		require.ErrorIs(t, err, ErrSSOAuthenticateFailure)
+	})
+}
+
+func TestSSOService_DomainAllowlistRequiresVerifiedEmail(t *testing.T) {
+	env := newSSOTestEnv(t, func(p *config.SSOProviderConfig) {
+		p.AllowedDomains = []string{"example.com"}
+	})
+	ctx := context.Background()
+
+	_, err := env.svc.Login(ctx, &SSOIdentityClaims{Provider: "corp", Subject: "s1", Email: "bob@example.com"})
+	require.ErrorIs(t, err, ErrSSODomainNotAllowed, "未验证邮箱不能通过域名白名单")
+
+	_, err = env.svc.Login(ctx, &SSOIdentityClaims{Provider: "corp", Subject: "s1", Email: "bob@other.com", EmailVerified: true})
+	require.ErrorIs(t, err, ErrSSODomainNotAllowed)
+
+	result, err := env.svc.Login(ctx, &SSOIdentityClaims{Provider: "corp", Subject: "s1", Email: "bob@example.com", EmailVerified: true})
+	require.NoError(t, err)
+	require.Equal(t, "bob@example.com", result.User.Email)
+}
+
+func TestSSOService_ExistingEmailUser(t *testing.T) {
+	claims := &SSOIdentityClaims{Provider: "corp", Subject: "s1", Email: "carol@example.com", EmailVerified: true}
+
+	t.Run("rejected without link_existing_users", func(t *testing.T) {
+		env := newSSOTestEnv(t, nil)
+		env.users.users[1] = &User{ID: 1, Email: "carol@example.com", Status: StatusActive}
+		_, err := env.svc.Login(context.Background(), claims)
+		require.ErrorIs(t, err, ErrSSOAccountExists)
+		require.Empty(t, env.identities.identities)
+	})
+
+	t.Run("linked with link_existing_users", func(t *testing.T) {
+		env := newSSOTestEnv(t, func(p *config.SSOProviderConfig) { p.LinkExistingUsers = true })
+		env.users.users[1] = &User{ID: 1, Email: "carol@example.com", Status: StatusActive}
+		result, err := env.svc.Login(context.Background(), claims)
+		require.NoError(t, err)
+		require.Equal(t, int64(1), result.User.ID)
+		require.NotNil(t, result.TokenPair)
+	})
+
+	t.Run("linked user with totp must pass second factor", func(t *testing.T) {
+		env := newSSOTestEnv(t, func(p *config.SSOProviderConfig) { p.LinkExistingUsers = true })
+		env.users.users[1] = &User{ID: 1, Email: "carol@example.com", Status: StatusActive, TotpEnabled: true}
+		result, err := env.svc.Login(context.Background(), claims)
+		require.NoError(t, err)
+		require.Nil(t, result.TokenPair, "启用 TOTP 的用户在验证前不能拿到 token")
+		require.Equal(t, "temp-1", result.TotpTempToken)
+		require.Equal(t, int64(1), env.totp.sessions["temp-1"])
+
+		// 再次登录命中已绑定身份，同样需要二次验证
+		result, err = env.svc.Login(context.Background(), claims)
+		require.NoError(t, err)
+		require.Nil(t, result.TokenPair)
+		require.NotEmpty(t, result.TotpTempToken)
+
+		// 站点关闭 TOTP 时直接登录
+		env.totp.disabled = true
+		result, err = env.svc.Login(context.Background(), claims)
+		require.NoError(t, err)
+		require.NotNil(t, result.TokenPair)
+		require.Empty(t, result.TotpTempToken)
+	})
+
+	t.Run("unverified email never matches existing user", func(t *testing.T) {
+		env := newSSOTestEnv(t, func(p *config.SSOProviderConfig) { p.LinkExistingUsers = true })
+		env.users.users[1] = &User{ID: 1, Email: "carol@example.com", Status: StatusActive}
+		result, err := env.svc.Login(context.Background(), &SSOIdentityClaims{Provider: "corp", Subject: "s1", Email: "carol@example.com"})
+		require.NoError(t, err)
+		user := result.User
+		require.NotEqual(t, int64(1), user.ID)
+		require.True(t, strings.HasSuffix(user.Email, SSOSyntheticEmailDomain))
+		require.True(t, isReservedEmail(user.Email))
+	})
+}
+
+func TestSSOService_LinkAndUnlink(t *testing.T) {
+	env := newSSOTestEnv(t, nil)
+	ctx := context.Background()
+	env.users.users[1] = &User{ID: 1, Email: "dave@example.com", Status: StatusActive}
+	env.users.users[2] = &User{ID: 2, Email: "sso-corp-x" + SSOSyntheticEmailDomain, Status: StatusActive}
+
+	identity, err := env.svc.Link(ctx, 1, &SSOIdentityClaims{Provider: "corp", Subject: "s1", Groups: []string{"engineering"}})
+	require.NoError(t, err)
+	require.Equal(t, []int64{7}, env.users.allowedGroups[1])
+
+	_, err = env.svc.Link(ctx, 2, &SSOIdentityClaims{Provider: "corp", Subject: "s1"})
+	require.ErrorIs(t, err, ErrUserIdentityExists, "同一身份不能绑定到其他用户")
+	_, err = env.svc.Link(ctx, 1, &SSOIdentityClaims{Provider: "corp", Subject: "s2"})
+	require.ErrorIs(t, err, ErrSSOProviderLinked)
+
+	require.ErrorIs(t, env.svc.Unlink(ctx, 2, identity.ID), ErrUserIdentityNotFound)
+	require.NoError(t, env.svc.Unlink(ctx, 1, identity.ID))
+
+	// 合成邮箱用户不能解绑唯一的登录方式
+	synthetic, err := env.svc.Link(ctx, 2, &SSOIdentityClaims{Provider: "corp", Subject: "s3"})
+	require.NoError(t, err)
+	require.ErrorIs(t, env.svc.Unlink(ctx, 2, synthetic.ID), ErrSSOLastSignInMethod)
+}
+
+func TestSSOService_LinkIntent(t *testing.T) {
+	env := newSSOTestEnv(t, nil)
+
+	token := env.svc.SignLinkIntent(42, "corp", "state-1")
+	userID, err := env.svc.VerifyLinkIntent(token, "corp", "state-1")
+	require.NoError(t, err)
+	require.Equal(t, int64(42), userID)
+
+	_, err = env.svc.VerifyLinkIntent(token, "corp", "state-2")
+	require.ErrorIs(t, err, ErrSSOInvalidLinkRequest, "凭证必须与本次授权 state 绑定")
+	_, err = env.svc.VerifyLinkIntent(strings.Replace(token, "42.", "43.", 1), "corp", "state-1")
+	require.ErrorIs(t, err, ErrSSOInvalidLinkRequest)
+	_, err = env.svc.VerifyLinkIntent(token, "other", "state-1")
+	require.ErrorIs(t, err, ErrSSOInvalidLinkRequest)
+}
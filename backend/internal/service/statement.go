@@ -0,0 +1,243 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 账单明细类型
+const (
+	StatementLineUsage        = "usage"        // 按分组 / 模型 / 计费方式聚合的用量
+	StatementLineSubscription = "subscription" // 订阅购买（支付订单或兑换码）
+	StatementLineTopUp        = "top_up"       // 余额充值
+	StatementLineRefund       = "refund"       // 订单退款
+	StatementLineRedeem       = "redeem"       // 余额兑换码
+	StatementLinePromo        = "promo"        // 优惠码赠送
+	StatementLineAdjustment   = "adjustment"   // 管理员调整余额
+)
+
+// StatementCurrency 账单币种（与余额、计费保持一致）
+const StatementCurrency = "USD"
+
+// statementPeriodLayout 账期格式
+const statementPeriodLayout = "2006-01"
+
+var (
+	ErrStatementNotFound      = infraerrors.NotFound("STATEMENT_NOT_FOUND", "statement not found")
+	ErrStatementExists        = infraerrors.Conflict("STATEMENT_EXISTS", "statement already exists for this period")
+	ErrStatementInvalidPeriod = infraerrors.BadRequest("STATEMENT_INVALID_PERIOD", "period must be a closed month in YYYY-MM format")
+	ErrStatementNoEmail       = infraerrors.BadRequest("STATEMENT_NO_EMAIL", "user has no email address")
+)
+
+// Statement 用户月度账单（生成后只读）
+type Statement struct {
+	ID          int64
+	UserID      int64
+	Period      string    // YYYY-MM
+	PeriodStart time.Time // 含
+	PeriodEnd   time.Time // 不含
+	Timezone    string
+	Currency    string
+
+	// 生成时的用户快照
+	UserEmail string
+	Username  string
+
+	RequestCount          int64
+	UsageTotalCost        float64 // 标准价用量费用
+	UsageBalanceCost      float64 // 余额扣费（actual_cost，billing_type=balance）
+	UsageSubscriptionCost float64 // 订阅覆盖（actual_cost，billing_type=subscription）
+	SubscriptionFees      float64
+	TopUps                float64
+	Refunds               float64
+	Credits               float64 // 兑换码 + 优惠码 + 管理员调整
+	LineCount             int
+
+	EmailedAt *time.Time
+	CreatedAt time.Time
+
+	Lines []StatementLine
+}
+
+// StatementLine 账单明细行
+type StatementLine struct {
+	LineNo      int
+	LineType    string
+	Description string
+
+	GroupID     *int64
+	GroupName   string
+	Model       string
+	BillingType *int8
+	Reference   string // 订单号 / 兑换码 / 优惠码
+
+	RequestCount        int64
+	InputTokens         int64
+	OutputTokens        int64
+	CacheCreationTokens int64
+	CacheReadTokens     int64
+	ImageCount          int64
+	VideoCount          int64
+
+	TotalCost  float64 // 标准价（仅 usage）
+	Amount     float64 // usage 为实际扣费；其余为支付 / 余额变动（退款为负数）
+	OccurredAt *time.Time
+}
+
+// StatementFilter 账单列表过滤条件
+type StatementFilter struct {
+	UserID *int64
+	Period string
+}
+
+// StatementSource 生成账单所需的账期原始数据（已按用户与时间范围过滤）
+type StatementSource struct {
+	Usage   []StatementLine // LineType=usage
+	Credits []StatementLine // 订阅 / 充值 / 退款 / 兑换码 / 优惠码 / 调整
+}
+
+// StatementRepository 账单存储与账期数据聚合
+type StatementRepository interface {
+	// Create 在一个事务内写入账单与明细；同一用户同一账期已存在时返回 ErrStatementExists
+	Create(ctx context.Context, statement *Statement) error
+	// GetByID 返回账单及其明细
+	GetByID(ctx context.Context, id int64) (*Statement, error)
+	List(ctx context.Context, params pagination.PaginationParams, filter StatementFilter) ([]Statement, *pagination.PaginationResult, error)
+	MarkEmailed(ctx context.Context, id int64, at time.Time) error
+
+	// ListUsersPendingStatement 返回账期内有活动（用量 / 订单 / 余额变动）但尚无账单的用户
+	ListUsersPendingStatement(ctx context.Context, period string, start, end time.Time, limit int) ([]int64, error)
+	// LoadSource 聚合用户在 [start, end) 内的用量与余额变动
+	LoadSource(ctx context.Context, userID int64, start, end time.Time) (*StatementSource, error)
+}
+
+// ParseStatementPeriod 解析 YYYY-MM 账期，返回账期在 loc 时区下的 [start, end)
+func ParseStatementPeriod(period string, loc *time.Location) (time.Time, time.Time, error) {
+	if loc == nil {
+		loc = time.UTC
+	}
+	t, err := time.ParseInLocation(statementPeriodLayout, period, loc)
+	if err != nil {
+		return time.Time{}, time.Time{}, ErrStatementInvalidPeriod
+	}
+	return t, t.AddDate(0, 1, 0), nil
+}
+
+// PreviousStatementPeriod 返回 now 所在月份的上一个账期（YYYY-MM）
+func PreviousStatementPeriod(now time.Time, loc *time.Location) string {
+	if loc == nil {
+		loc = time.UTC
+	}
+	local := now.In(loc)
+	firstOfMonth := time.Date(local.Year(), local.Month(), 1, 0, 0, 0, 0, loc)
+	return firstOfMonth.AddDate(0, -1, 0).Format(statementPeriodLayout)
+}
+
+// Title 账单标题
+func (s *Statement) Title() string {
+	return fmt.Sprintf("Statement %s", s.Period)
+}
+
+// NetPayments 账期内实际支付净额（订阅费 + 充值 - 退款）
+func (s *Statement) NetPayments() float64 {
+	return s.SubscriptionFees + s.TopUps - s.Refunds
+}
+
+// buildStatement 将账期原始数据汇总为账单（明细按 用量 → 其他 顺序编号）
+func buildStatement(user *User, period string, start, end time.Time, source *StatementSource) *Statement {
+	st := &Statement{
+		UserID:      user.ID,
+		Period:      period,
+		PeriodStart: start,
+		PeriodEnd:   end,
+		Timezone:    start.Location().String(),
+		Currency:    StatementCurrency,
+		UserEmail:   user.Email,
+		Username:    user.Username,
+	}
+	if source == nil {
+		return st
+	}
+
+	lines := make([]StatementLine, 0, len(source.Usage)+len(source.Credits))
+	for _, line := range source.Usage {
+		line.LineType = StatementLineUsage
+		st.RequestCount += line.RequestCount
+		st.UsageTotalCost += line.TotalCost
+		if line.BillingType != nil && *line.BillingType == BillingTypeSubscription {
+			st.UsageSubscriptionCost += line.Amount
+		} else {
+			st.UsageBalanceCost += line.Amount
+		}
+		lines = append(lines, line)
+	}
+	for _, line := range source.Credits {
+		switch line.LineType {
+		case StatementLineSubscription:
+			st.SubscriptionFees += line.Amount
+		case StatementLineTopUp:
+			st.TopUps += line.Amount
+		case StatementLineRefund:
+			st.Refunds -= line.Amount // 退款明细金额为负
+		case StatementLineRedeem, StatementLinePromo, StatementLineAdjustment:
+			st.Credits += line.Amount
+		}
+		lines = append(lines, line)
+	}
+	for i := range lines {
+		lines[i].LineNo = i + 1
+		if lines[i].Description == "" {
+			lines[i].Description = describeStatementLine(&lines[i])
+		}
+	}
+	st.Lines = lines
+	st.LineCount = len(lines)
+	return st
+}
+
+// statementBillingLabel 使用日志计费方式的展示名称
+func statementBillingLabel(billingType *int8) string {
+	if billingType == nil {
+		return "balance"
+	}
+	switch *billingType {
+	case BillingTypeSubscription:
+		return "subscription"
+	case BillingTypeResponseCache:
+		return "response cache"
+	default:
+		return "balance"
+	}
+}
+
+func describeStatementLine(line *StatementLine) string {
+	switch line.LineType {
+	case StatementLineUsage:
+		group := line.GroupName
+		if group == "" {
+			group = "Default"
+		}
+		return fmt.Sprintf("%s / %s (%s)", group, line.Model, statementBillingLabel(line.BillingType))
+	case StatementLineSubscription:
+		if line.GroupName != "" {
+			return "Subscription: " + line.GroupName
+		}
+		return "Subscription"
+	case StatementLineTopUp:
+		return "Balance top-up"
+	case StatementLineRefund:
+		return "Refund"
+	case StatementLineRedeem:
+		return "Redeem code"
+	case StatementLinePromo:
+		return "Promo code bonus"
+	case StatementLineAdjustment:
+		return "Balance adjustment"
+	default:
+		return line.LineType
+	}
+}
@@ -0,0 +1,153 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+	"strconv"
+	"time"
+)
+
+// statementHTMLTemplate 账单 HTML 模板：内联样式，适配邮件客户端，浏览器打印即可导出 PDF
+var statementHTMLTemplate = template.Must(template.New("statement").Funcs(template.FuncMap{
+	"money":   func(v float64) string { return fmt.Sprintf("%.2f", v) },
+	"cost":    func(v float64) string { return fmt.Sprintf("%.6f", v) },
+	"count":   formatStatementCount,
+	"date":    func(t time.Time) string { return t.Format("2006-01-02") },
+	"before":  func(t time.Time) string { return t.Add(-time.Second).Format("2006-01-02") },
+	"billing": statementBillingLabel,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>{{.SiteName}} - {{.S.Title}}</title>
+<style>
+  body { font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; color: #1f2937; margin: 0; padding: 24px; background: #fff; }
+  .sheet { max-width: 960px; margin: 0 auto; }
+  h1 { font-size: 22px; margin: 0 0 4px; }
+  h2 { font-size: 16px; margin: 28px 0 8px; }
+  .muted { color: #6b7280; font-size: 13px; }
+  table { width: 100%; border-collapse: collapse; font-size: 12px; }
+  th, td { border-bottom: 1px solid #e5e7eb; padding: 6px 8px; text-align: left; }
+  th { background: #f9fafb; font-weight: 600; }
+  td.num, th.num { text-align: right; font-variant-numeric: tabular-nums; }
+  .summary td { font-size: 13px; }
+  .summary td.num { font-weight: 600; }
+  @media print { body { padding: 0; } h2 { page-break-after: avoid; } tr { page-break-inside: avoid; } }
+</style>
+</head>
+<body>
+<div class="sheet">
+  <h1>{{.SiteName}} · {{.S.Title}}</h1>
+  <div class="muted">
+    #{{.S.ID}} · {{date .Start}} – {{before .End}} ({{.S.Timezone}}) · {{.S.Currency}}<br>
+    {{if .S.Username}}{{.S.Username}} · {{end}}{{.S.UserEmail}} (ID {{.S.UserID}})
+  </div>
+
+  <h2>Summary</h2>
+  <table class="summary">
+    <tr><td>Requests</td><td class="num">{{count .S.RequestCount}}</td></tr>
+    <tr><td>Usage at standard price</td><td class="num">{{cost .S.UsageTotalCost}}</td></tr>
+    <tr><td>Usage charged to balance</td><td class="num">{{cost .S.UsageBalanceCost}}</td></tr>
+    <tr><td>Usage covered by subscriptions</td><td class="num">{{cost .S.UsageSubscriptionCost}}</td></tr>
+    <tr><td>Subscription fees</td><td class="num">{{money .S.SubscriptionFees}}</td></tr>
+    <tr><td>Top-ups</td><td class="num">{{money .S.TopUps}}</td></tr>
+    <tr><td>Refunds</td><td class="num">{{money .S.Refunds}}</td></tr>
+    <tr><td>Credits (redeem / promo / adjustments)</td><td class="num">{{money .S.Credits}}</td></tr>
+  </table>
+
+  {{if .Usage}}
+  <h2>Usage</h2>
+  <table>
+    <tr>
+      <th>#</th><th>Group</th><th>Model</th><th>Billing</th>
+      <th class="num">Requests</th><th class="num">Input</th><th class="num">Output</th>
+      <th class="num">Cache write</th><th class="num">Cache read</th><th class="num">Images</th><th class="num">Videos</th>
+      <th class="num">Standard</th><th class="num">Charged</th>
+    </tr>
+    {{range .Usage}}
+    <tr>
+      <td>{{.LineNo}}</td><td>{{if .GroupName}}{{.GroupName}}{{else}}-{{end}}</td><td>{{.Model}}</td>
+      <td>{{billing .BillingType}}</td>
+      <td class="num">{{count .RequestCount}}</td><td class="num">{{count .InputTokens}}</td><td class="num">{{count .OutputTokens}}</td>
+      <td class="num">{{count .CacheCreationTokens}}</td><td class="num">{{count .CacheReadTokens}}</td>
+      <td class="num">{{count .ImageCount}}</td><td class="num">{{count .VideoCount}}</td>
+      <td class="num">{{cost .TotalCost}}</td><td class="num">{{cost .Amount}}</td>
+    </tr>
+    {{end}}
+  </table>
+  {{end}}
+
+  {{if .Other}}
+  <h2>Payments &amp; credits</h2>
+  <table>
+    <tr><th>#</th><th>Date</th><th>Type</th><th>Description</th><th>Reference</th><th class="num">Amount</th></tr>
+    {{range .Other}}
+    <tr>
+      <td>{{.LineNo}}</td><td>{{if .OccurredAt}}{{date .OccurredAt}}{{end}}</td><td>{{.LineType}}</td>
+      <td>{{.Description}}</td><td>{{.Reference}}</td><td class="num">{{money .Amount}}</td>
+    </tr>
+    {{end}}
+  </table>
+  {{end}}
+
+  <p class="muted">Generated {{date .S.CreatedAt}}. This statement is final and will not change.</p>
+</div>
+</body>
+</html>
+`))
+
+type statementHTMLData struct {
+	SiteName string
+	S        *Statement
+	Start    time.Time
+	End      time.Time
+	Usage    []StatementLine
+	Other    []StatementLine
+}
+
+func renderStatementHTML(siteName string, statement *Statement) (string, error) {
+	data := statementHTMLData{SiteName: siteName, S: statement}
+	// 日期按账单时区展示
+	loc := time.UTC
+	if parsed, err := time.LoadLocation(statement.Timezone); err == nil {
+		loc = parsed
+	}
+	data.Start = statement.PeriodStart.In(loc)
+	data.End = statement.PeriodEnd.In(loc)
+	for _, line := range statement.Lines {
+		if line.OccurredAt != nil {
+			t := line.OccurredAt.In(loc)
+			line.OccurredAt = &t
+		}
+		if line.LineType == StatementLineUsage {
+			data.Usage = append(data.Usage, line)
+		} else {
+			data.Other = append(data.Other, line)
+		}
+	}
+	var buf bytes.Buffer
+	if err := statementHTMLTemplate.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("render statement: %w", err)
+	}
+	return buf.String(), nil
+}
+
+func formatStatementCount(v int64) string {
+	s := strconv.FormatInt(v, 10)
+	neg := v < 0
+	if neg {
+		s = s[1:]
+	}
+	out := make([]byte, 0, len(s)+len(s)/3)
+	for i := range s {
+		if i > 0 && (len(s)-i)%3 == 0 {
+			out = append(out, ',')
+		}
+		out = append(out, s[i])
+	}
+	if neg {
+		return "-" + string(out)
+	}
+	return string(out)
+}
@@ -0,0 +1,320 @@
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/timezone"
+	"github.com/google/uuid"
+	"github.com/redis/go-redis/v9"
+	"github.com/robfig/cron/v3"
+)
+
+const (
+	statementCloseLeaderLockKey = "statement:close:leader"
+	statementCloseLeaderLockTTL = 2 * time.Hour
+	statementCloseBatchSize     = 200
+)
+
+// StatementService 月度账单：按自然月结算每个用户的用量与余额变动，生成只读账单，
+// 提供 JSON / HTML 渲染与邮件发送。
+//
+// 结算由 cron 驱动（statement.close_schedule），每次为上一账期尚无账单的用户补齐，
+// 多实例下通过 Redis 锁（失败时回退到 DB advisory lock）保证只有一个实例执行。
+type StatementService struct {
+	repo           StatementRepository
+	userRepo       UserRepository
+	emailService   *EmailService
+	settingService *SettingService
+	db             *sql.DB
+	redisClient    *redis.Client
+	cfg            *config.Config
+
+	instanceID string
+	now        func() time.Time
+
+	cron *cron.Cron
+
+	startOnce sync.Once
+	stopOnce  sync.Once
+}
+
+func NewStatementService(
+	repo StatementRepository,
+	userRepo UserRepository,
+	emailService *EmailService,
+	settingService *SettingService,
+	db *sql.DB,
+	redisClient *redis.Client,
+	cfg *config.Config,
+) *StatementService {
+	return &StatementService{
+		repo:           repo,
+		userRepo:       userRepo,
+		emailService:   emailService,
+		settingService: settingService,
+		db:             db,
+		redisClient:    redisClient,
+		cfg:            cfg,
+		instanceID:     uuid.NewString(),
+		now:            time.Now,
+	}
+}
+
+func (s *StatementService) Start() {
+	if s == nil || s.repo == nil || s.cfg == nil {
+		return
+	}
+	if !s.cfg.Statement.Enabled {
+		logger.LegacyPrintf("service.statement", "[Statement] not started (disabled)")
+		return
+	}
+
+	s.startOnce.Do(func() {
+		schedule := "20 0 * * *"
+		if strings.TrimSpace(s.cfg.Statement.CloseSchedule) != "" {
+			schedule = strings.TrimSpace(s.cfg.Statement.CloseSchedule)
+		}
+
+		c := cron.New(cron.WithParser(opsCleanupCronParser), cron.WithLocation(s.location()))
+		if _, err := c.AddFunc(schedule, func() { s.runScheduled() }); err != nil {
+			logger.LegacyPrintf("service.statement", "[Statement] not started (invalid schedule=%q): %v", schedule, err)
+			return
+		}
+		s.cron = c
+		s.cron.Start()
+		logger.LegacyPrintf("service.statement", "[Statement] started (schedule=%q tz=%s email=%v)", schedule, s.location().String(), s.cfg.Statement.EmailEnabled)
+	})
+}
+
+func (s *StatementService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.cron != nil {
+			ctx := s.cron.Stop()
+			select {
+			case <-ctx.Done():
+			case <-time.After(3 * time.Second):
+				logger.LegacyPrintf("service.statement", "[Statement] cron stop timed out")
+			}
+		}
+	})
+}
+
+// location 账期切分使用的时区（与全局 timezone 配置一致）
+func (s *StatementService) location() *time.Location {
+	if s.cfg != nil && strings.TrimSpace(s.cfg.Timezone) != "" {
+		if loc, err := time.LoadLocation(strings.TrimSpace(s.cfg.Timezone)); err == nil && loc != nil {
+			return loc
+		}
+	}
+	return timezone.Location()
+}
+
+func (s *StatementService) runScheduled() {
+	ctx, cancel := context.WithTimeout(context.Background(), statementCloseLeaderLockTTL)
+	defer cancel()
+
+	release, ok := s.tryAcquireLeaderLock(ctx)
+	if !ok {
+		return
+	}
+	if release != nil {
+		defer release()
+	}
+
+	period := PreviousStatementPeriod(s.now(), s.location())
+	created, err := s.ClosePeriod(ctx, period)
+	if err != nil {
+		logger.LegacyPrintf("service.statement", "[Statement] close period=%s failed: created=%d err=%v", period, created, err)
+		return
+	}
+	if created > 0 {
+		logger.LegacyPrintf("service.statement", "[Statement] close period=%s complete: created=%d", period, created)
+	}
+}
+
+// ClosePeriod 为账期内有活动但尚无账单的所有用户生成账单，返回新生成的数量。
+// 单个用户失败只记录日志并跳过，不影响其他用户。
+func (s *StatementService) ClosePeriod(ctx context.Context, period string) (int, error) {
+	start, end, err := s.closedPeriodRange(period)
+	if err != nil {
+		return 0, err
+	}
+
+	created := 0
+	failed := make(map[int64]struct{})
+	for {
+		userIDs, err := s.repo.ListUsersPendingStatement(ctx, period, start, end, statementCloseBatchSize+len(failed))
+		if err != nil {
+			return created, err
+		}
+		progressed := false
+		for _, userID := range userIDs {
+			if _, skip := failed[userID]; skip {
+				continue
+			}
+			progressed = true
+			if _, err := s.generate(ctx, userID, period, start, end); err != nil {
+				if errors.Is(err, ErrStatementExists) {
+					continue
+				}
+				failed[userID] = struct{}{}
+				logger.LegacyPrintf("service.statement", "[Statement] generate failed: user=%d period=%s err=%v", userID, period, err)
+				continue
+			}
+			created++
+		}
+		if !progressed {
+			return created, nil
+		}
+		if err := ctx.Err(); err != nil {
+			return created, err
+		}
+	}
+}
+
+// GenerateForUser 立即为单个用户生成指定账期的账单（账期必须已结束）
+func (s *StatementService) GenerateForUser(ctx context.Context, userID int64, period string) (*Statement, error) {
+	start, end, err := s.closedPeriodRange(period)
+	if err != nil {
+		return nil, err
+	}
+	return s.generate(ctx, userID, period, start, end)
+}
+
+func (s *StatementService) closedPeriodRange(period string) (time.Time, time.Time, error) {
+	start, end, err := ParseStatementPeriod(strings.TrimSpace(period), s.location())
+	if err != nil {
+		return start, end, err
+	}
+	if end.After(s.now()) {
+		return start, end, ErrStatementInvalidPeriod
+	}
+	return start, end, nil
+}
+
+func (s *StatementService) generate(ctx context.Context, userID int64, period string, start, end time.Time) (*Statement, error) {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	source, err := s.repo.LoadSource(ctx, userID, start, end)
+	if err != nil {
+		return nil, err
+	}
+	statement := buildStatement(user, period, start, end, source)
+	if err := s.repo.Create(ctx, statement); err != nil {
+		return nil, err
+	}
+
+	if s.cfg != nil && s.cfg.Statement.EmailEnabled && statement.UserEmail != "" {
+		if err := s.deliver(ctx, statement); err != nil {
+			logger.LegacyPrintf("service.statement", "[Statement] email failed: statement=%d user=%d err=%v", statement.ID, userID, err)
+		}
+	}
+	return statement, nil
+}
+
+// Get 返回账单及明细（管理员）
+func (s *StatementService) Get(ctx context.Context, id int64) (*Statement, error) {
+	return s.repo.GetByID(ctx, id)
+}
+
+// GetForUser 返回属于 userID 的账单；他人账单视为不存在
+func (s *StatementService) GetForUser(ctx context.Context, id, userID int64) (*Statement, error) {
+	statement, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if statement.UserID != userID {
+		return nil, ErrStatementNotFound
+	}
+	return statement, nil
+}
+
+func (s *StatementService) List(ctx context.Context, params pagination.PaginationParams, filter StatementFilter) ([]Statement, *pagination.PaginationResult, error) {
+	return s.repo.List(ctx, params, filter)
+}
+
+// RenderHTML 渲染可打印（可直接另存为 PDF）的账单 HTML
+func (s *StatementService) RenderHTML(ctx context.Context, statement *Statement) (string, error) {
+	siteName := "Sub2API"
+	if s.settingService != nil {
+		siteName = s.settingService.GetSiteName(ctx)
+	}
+	return renderStatementHTML(siteName, statement)
+}
+
+// SendEmail 将账单发送到用户快照邮箱并记录发送时间
+func (s *StatementService) SendEmail(ctx context.Context, id int64) (*Statement, error) {
+	statement, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if statement.UserEmail == "" {
+		return nil, ErrStatementNoEmail
+	}
+	if err := s.deliver(ctx, statement); err != nil {
+		return nil, err
+	}
+	return statement, nil
+}
+
+func (s *StatementService) deliver(ctx context.Context, statement *Statement) error {
+	if s.emailService == nil {
+		return ErrEmailNotConfigured
+	}
+	body, err := s.RenderHTML(ctx, statement)
+	if err != nil {
+		return err
+	}
+	siteName := "Sub2API"
+	if s.settingService != nil {
+		siteName = s.settingService.GetSiteName(ctx)
+	}
+	subject := "[" + siteName + "] " + statement.Title()
+	if err := s.emailService.SendEmail(ctx, statement.UserEmail, subject, body); err != nil {
+		return err
+	}
+	now := s.now()
+	if err := s.repo.MarkEmailed(ctx, statement.ID, now); err != nil {
+		return err
+	}
+	statement.EmailedAt = &now
+	return nil
+}
+
+func (s *StatementService) tryAcquireLeaderLock(ctx context.Context) (func(), bool) {
+	// In simple run mode, assume single instance.
+	if s.cfg.RunMode == config.RunModeSimple {
+		return nil, true
+	}
+
+	if s.redisClient != nil {
+		ok, err := s.redisClient.SetNX(ctx, statementCloseLeaderLockKey, s.instanceID, statementCloseLeaderLockTTL).Result()
+		if err == nil {
+			if !ok {
+				return nil, false
+			}
+			return func() {
+				_, _ = opsCleanupReleaseScript.Run(ctx, s.redisClient, []string{statementCloseLeaderLockKey}, s.instanceID).Result()
+			}, true
+		}
+		// Redis error: fall back to DB advisory lock.
+	}
+
+	if s.db == nil {
+		return nil, false
+	}
+	return tryAcquireDBAdvisoryLock(ctx, s.db, hashAdvisoryLockID(statementCloseLeaderLockKey))
+}
@@ -0,0 +1,277 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+type statementRepoStub struct {
+	statements map[int64]*Statement
+	byPeriod   map[string]int64 // "userID/period" -> id
+	pending    []int64
+	sources    map[int64]*StatementSource
+	loadErr    map[int64]error
+	emailed    []int64
+	nextID     int64
+}
+
+func newStatementRepoStub() *statementRepoStub {
+	return &statementRepoStub{
+		statements: map[int64]*Statement{},
+		byPeriod:   map[string]int64{},
+		sources:    map[int64]*StatementSource{},
+		loadErr:    map[int64]error{},
+	}
+}
+
+func statementKey(userID int64, period string) string {
+	return fmt.Sprintf("%d/%s", userID, period)
+}
+
+func (r *statementRepoStub) Create(_ context.Context, st *Statement) error {
+	key := statementKey(st.UserID, st.Period)
+	if _, ok := r.byPeriod[key]; ok {
+		return ErrStatementExists
+	}
+	r.nextID++
+	st.ID = r.nextID
+	st.CreatedAt = time.Now()
+	cp := *st
+	r.statements[st.ID] = &cp
+	r.byPeriod[key] = st.ID
+	return nil
+}
+
+func (r *statementRepoStub) GetByID(_ context.Context, id int64) (*Statement, error) {
+	st, ok := r.statements[id]
+	if !ok {
+		return nil, ErrStatementNotFound
+	}
+	cp := *st
+	return &cp, nil
+}
+
+func (r *statementRepoStub) List(context.Context, pagination.PaginationParams, StatementFilter) ([]Statement, *pagination.PaginationResult, error) {
+	panic("unexpected List call")
+}
+
+func (r *statementRepoStub) MarkEmailed(_ context.Context, id int64, at time.Time) error {
+	r.emailed = append(r.emailed, id)
+	r.statements[id].EmailedAt = &at
+	return nil
+}
+
+func (r *statementRepoStub) ListUsersPendingStatement(_ context.Context, period string, _, _ time.Time, limit int) ([]int64, error) {
+	out := make([]int64, 0, limit)
+	for _, id := range r.pending {
+		if _, done := r.byPeriod[statementKey(id, period)]; done {
+			continue
+		}
+		out = append(out, id)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+func (r *statementRepoStub) LoadSource(_ context.Context, userID int64, _, _ time.Time) (*StatementSource, error) {
+	if err := r.loadErr[userID]; err != nil {
+		return nil, err
+	}
+	return r.sources[userID], nil
+}
+
+type statementUserRepoStub struct {
+	UserRepository
+	users map[int64]*User
+}
+
+func (r *statementUserRepoStub) GetByID(_ context.Context, id int64) (*User, error) {
+	u, ok := r.users[id]
+	if !ok {
+		return nil, ErrUserNotFound
+	}
+	return u, nil
+}
+
+func newStatementServiceForTest(now time.Time) (*StatementService, *statementRepoStub) {
+	repo := newStatementRepoStub()
+	users := &statementUserRepoStub{users: map[int64]*User{
+		1: {ID: 1, Email: "a@example.com", Username: "alice"},
+		2: {ID: 2, Email: "b@example.com"},
+		3: {ID: 3, Email: "c@example.com"},
+	}}
+	cfg := &config.Config{Timezone: "Asia/Shanghai"}
+	svc := NewStatementService(repo, users, nil, nil, nil, nil, cfg)
+	svc.now = func() time.Time { return now }
+	return svc, repo
+}
+
+func statementTestSource() *StatementSource {
+	balance, sub := BillingTypeBalance, BillingTypeSubscription
+	groupID := int64(5)
+	paidAt := time.Date(2026, 2, 10, 3, 0, 0, 0, time.UTC)
+	return &StatementSource{
+		Usage: []StatementLine{
+			{GroupID: &groupID, GroupName: "pro", Model: "claude-sonnet-4", BillingType: &balance, RequestCount: 10, InputTokens: 1200, OutputTokens: 800, TotalCost: 1.5, Amount: 1.2},
+			{GroupID: &groupID, GroupName: "pro", Model: "claude-sonnet-4", BillingType: &sub, RequestCount: 4, TotalCost: 0.6, Amount: 0.6},
+			{Model: "sora2-landscape-10s", BillingType: &balance, RequestCount: 2, VideoCount: 2, TotalCost: 0.4, Amount: 0.4},
+		},
+		Credits: []StatementLine{
+			{LineType: StatementLineTopUp, Reference: "S1", Amount: 20, OccurredAt: &paidAt},
+			{LineType: StatementLineSubscription, GroupName: "pro", Reference: "S2", Amount: 9.9, OccurredAt: &paidAt},
+			{LineType: StatementLineRefund, Reference: "S2", Amount: -9.9, OccurredAt: &paidAt},
+			{LineType: StatementLineRedeem, Reference: "CODE<1>", Amount: 5, OccurredAt: &paidAt},
+			{LineType: StatementLinePromo, Reference: "WELCOME", Amount: 1, OccurredAt: &paidAt},
+			{LineType: StatementLineAdjustment, Amount: -2, OccurredAt: &paidAt},
+		},
+	}
+}
+
+func TestStatementPeriodHelpers(t *testing.T) {
+	loc, err := time.LoadLocation("Asia/Shanghai")
+	require.NoError(t, err)
+
+	start, end, err := ParseStatementPeriod("2026-01", loc)
+	require.NoError(t, err)
+	require.Equal(t, time.Date(2026, 1, 1, 0, 0, 0, 0, loc), start)
+	require.Equal(t, time.Date(2026, 2, 1, 0, 0, 0, 0, loc), end)
+
+	_, _, err = ParseStatementPeriod("2026-13", loc)
+	require.ErrorIs(t, err, ErrStatementInvalidPeriod)
+
+	// 2026-03-01 02:00 Asia/Shanghai 仍是 UTC 的 2 月，账期按配置时区取上月
+	now := time.Date(2026, 2, 28, 18, 0, 0, 0, time.UTC)
+	require.Equal(t, "2026-02", PreviousStatementPeriod(now, loc))
+	require.Equal(t, "2026-01", PreviousStatementPeriod(now, time.UTC))
+	require.Equal(t, "2025-12", PreviousStatementPeriod(time.Date(2026, 1, 15, 0, 0, 0, 0, time.UTC), time.UTC))
+}
+
+func TestBuildStatement_TotalsAndLineNumbers(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	start, end, _ := ParseStatementPeriod("2026-02", loc)
+	st := buildStatement(&User{ID: 1, Email: "a@example.com", Username: "alice"}, "2026-02", start, end, statementTestSource())
+
+	require.Equal(t, "Asia/Shanghai", st.Timezone)
+	require.Equal(t, StatementCurrency, st.Currency)
+	require.Equal(t, int64(16), st.RequestCount)
+	require.InDelta(t, 2.5, st.UsageTotalCost, 1e-9)
+	require.InDelta(t, 1.6, st.UsageBalanceCost, 1e-9)
+	require.InDelta(t, 0.6, st.UsageSubscriptionCost, 1e-9)
+	require.InDelta(t, 9.9, st.SubscriptionFees, 1e-9)
+	require.InDelta(t, 20, st.TopUps, 1e-9)
+	require.InDelta(t, 9.9, st.Refunds, 1e-9)
+	require.InDelta(t, 4, st.Credits, 1e-9)
+	require.InDelta(t, 20, st.NetPayments(), 1e-9)
+
+	require.Equal(t, 9, st.LineCount)
+	for i, line := range st.Lines {
+		require.Equal(t, i+1, line.LineNo)
+		require.NotEmpty(t, line.Description)
+	}
+	require.Equal(t, StatementLineUsage, st.Lines[0].LineType)
+	require.Equal(t, "pro / claude-sonnet-4 (balance)", st.Lines[0].Description)
+	require.Equal(t, "pro / claude-sonnet-4 (subscription)", st.Lines[1].Description)
+	require.Equal(t, "Default / sora2-landscape-10s (balance)", st.Lines[2].Description)
+	require.Equal(t, "Subscription: pro", st.Lines[4].Description)
+}
+
+func TestStatementService_ClosePeriodIsIdempotentAndSkipsFailures(t *testing.T) {
+	now := time.Date(2026, 3, 1, 0, 30, 0, 0, time.UTC)
+	svc, repo := newStatementServiceForTest(now)
+	repo.pending = []int64{1, 2, 3, 4} // 4 不存在
+	repo.sources[1] = statementTestSource()
+	repo.loadErr[3] = errors.New("db down")
+
+	created, err := svc.ClosePeriod(context.Background(), "2026-02")
+	require.NoError(t, err)
+	require.Equal(t, 2, created)
+	require.Len(t, repo.statements, 2)
+	require.Equal(t, 9, repo.statements[1].LineCount)
+	require.Equal(t, "alice", repo.statements[1].Username)
+	require.Equal(t, 0, repo.statements[2].LineCount)
+	require.Empty(t, repo.emailed, "email disabled by default")
+
+	// 重复执行不会重复生成
+	created, err = svc.ClosePeriod(context.Background(), "2026-02")
+	require.NoError(t, err)
+	require.Equal(t, 0, created)
+
+	_, err = svc.GenerateForUser(context.Background(), 1, "2026-02")
+	require.ErrorIs(t, err, ErrStatementExists)
+}
+
+func TestStatementService_RejectsOpenOrInvalidPeriod(t *testing.T) {
+	// 2026-03-01 00:30 UTC = 2026-03-01 08:30 Asia/Shanghai：2 月已结束，3 月未结束
+	svc, _ := newStatementServiceForTest(time.Date(2026, 3, 1, 0, 30, 0, 0, time.UTC))
+
+	_, err := svc.ClosePeriod(context.Background(), "2026-03")
+	require.ErrorIs(t, err, ErrStatementInvalidPeriod)
+	_, err = svc.GenerateForUser(context.Background(), 1, "03/2026")
+	require.ErrorIs(t, err, ErrStatementInvalidPeriod)
+	_, err = svc.GenerateForUser(context.Background(), 1, "2026-02")
+	require.NoError(t, err)
+}
+
+func TestStatementService_GetForUserHidesOtherUsers(t *testing.T) {
+	svc, _ := newStatementServiceForTest(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	st, err := svc.GenerateForUser(context.Background(), 1, "2026-02")
+	require.NoError(t, err)
+
+	got, err := svc.GetForUser(context.Background(), st.ID, 1)
+	require.NoError(t, err)
+	require.Equal(t, st.ID, got.ID)
+
+	_, err = svc.GetForUser(context.Background(), st.ID, 2)
+	require.ErrorIs(t, err, ErrStatementNotFound)
+}
+
+func TestStatementService_SendEmailRequiresEmailService(t *testing.T) {
+	svc, repo := newStatementServiceForTest(time.Date(2026, 3, 2, 0, 0, 0, 0, time.UTC))
+	st, err := svc.GenerateForUser(context.Background(), 1, "2026-02")
+	require.NoError(t, err)
+
+	_, err = svc.SendEmail(context.Background(), st.ID)
+	require.ErrorIs(t, err, ErrEmailNotConfigured)
+	require.Empty(t, repo.emailed)
+}
+
+func TestRenderStatementHTML(t *testing.T) {
+	loc, _ := time.LoadLocation("Asia/Shanghai")
+	start, end, _ := ParseStatementPeriod("2026-02", loc)
+	st := buildStatement(&User{ID: 1, Email: "a@example.com", Username: "<script>x</script>"}, "2026-02", start, end, statementTestSource())
+	st.ID = 42
+	// 模拟从数据库读取：时间为 UTC，时区字段保留配置时区
+	st.PeriodStart = start.UTC()
+	st.PeriodEnd = end.UTC()
+
+	html, err := renderStatementHTML("My Site", st)
+	require.NoError(t, err)
+	require.Contains(t, html, "My Site · Statement 2026-02")
+	require.Contains(t, html, "2026-02-01 – 2026-02-28 (Asia/Shanghai)")
+	require.Contains(t, html, "1,200")
+	require.Contains(t, html, "sora2-landscape-10s")
+	require.Contains(t, html, "<td>subscription</td>")
+	require.Contains(t, html, "20.00")
+	require.Contains(t, html, "-9.90")
+	require.Contains(t, html, "CODE&lt;1&gt;")
+	require.NotContains(t, html, "<script>")
+}
+
+func TestFormatStatementCount(t *testing.T) {
+	require.Equal(t, "0", formatStatementCount(0))
+	require.Equal(t, "999", formatStatementCount(999))
+	require.Equal(t, "1,000", formatStatementCount(1000))
+	require.Equal(t, "12,345,678", formatStatementCount(12345678))
+	require.Equal(t, "-1,000", formatStatementCount(-1000))
+}
@@ -6,12 +6,13 @@ import (
 	"time"
 )
 
-// SubscriptionOrder represents a purchase order for a subscription plan.
+// SubscriptionOrder represents a purchase order for a subscription plan or a balance top-up.
 type SubscriptionOrder struct {
 	ID                   int64
 	OrderNo              string
+	OrderType            string // OrderTypeSubscription / OrderTypeBalance
 	UserID               int64
-	GroupID              int64
+	GroupID              *int64 // 充值订单为空
 	SubscriptionID       *int64
 	PaymentProvider      string
 	PaymentURL           string
@@ -23,8 +24,10 @@ type SubscriptionOrder struct {
 	Amount               float64
 	Currency             string
 	ValidityDays         int
+	CreditAmount         float64 // 充值订单到账余额
 	PaidAt               *time.Time
 	CanceledAt           *time.Time
+	RefundedAt           *time.Time
 	Notes                string
 	CreatedAt            time.Time
 	UpdatedAt            time.Time
@@ -9,10 +9,11 @@ import (
 
 // SubscriptionOrderFilters represents filters for listing orders.
 type SubscriptionOrderFilters struct {
-	OrderNo string
-	Status  string
-	UserID  *int64
-	GroupID *int64
+	OrderNo   string
+	OrderType string
+	Status    string
+	UserID    *int64
+	GroupID   *int64
 }
 
 // SubscriptionOrderRepository provides persistence for subscription orders.
@@ -23,5 +24,8 @@ type SubscriptionOrderRepository interface {
 	List(ctx context.Context, params pagination.PaginationParams, filters SubscriptionOrderFilters) ([]SubscriptionOrder, *pagination.PaginationResult, error)
 	Update(ctx context.Context, order *SubscriptionOrder) error
 	UpdateStatus(ctx context.Context, id int64, status string, paidAt, canceledAt *time.Time) error
+	// TransitionStatus 仅当订单当前状态属于 from 时将其更新为 to，返回是否实际更新；
+	// 在事务内调用时同时持有行锁，用于保证同一订单只会被激活/退款一次。
+	TransitionStatus(ctx context.Context, id int64, from []string, to string) (bool, error)
 	SetSubscriptionID(ctx context.Context, id int64, subscriptionID int64) error
 }
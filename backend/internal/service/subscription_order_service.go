@@ -2,8 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"math"
+	"net/http"
 	"strconv"
 	"strings"
 	"time"
@@ -22,18 +25,34 @@ var (
 	ErrPaymentNotConfigured    = infraerrors.BadRequest("PAYMENT_NOT_CONFIGURED", "payment provider not configured")
 	ErrPaymentInvalidSignature = infraerrors.BadRequest("PAYMENT_INVALID_SIGNATURE", "payment signature invalid")
 	ErrPaymentAmountMismatch   = infraerrors.BadRequest("PAYMENT_AMOUNT_MISMATCH", "payment amount mismatch")
+	ErrPaymentCurrencyMismatch = infraerrors.BadRequest("PAYMENT_CURRENCY_MISMATCH", "payment currency mismatch")
+	ErrPaymentProviderMismatch = infraerrors.BadRequest("PAYMENT_PROVIDER_MISMATCH", "payment provider does not match order")
+	ErrTopUpDisabled           = infraerrors.Forbidden("TOPUP_DISABLED", "balance top-up is disabled")
+	ErrTopUpInvalidAmount      = infraerrors.BadRequest("TOPUP_INVALID_AMOUNT", "invalid top-up amount")
+	ErrRefundInsufficientFunds = infraerrors.BadRequest("REFUND_INSUFFICIENT_BALANCE", "user balance is lower than the credited amount")
 )
 
-const orderPaymentTimeout = 5 * time.Minute
+const (
+	orderPaymentTimeout = 5 * time.Minute
+	// paymentNotifyIdempotencyTTL 覆盖各渠道的回调重试窗口（支付宝约 25 小时，微信约 24 小时）
+	paymentNotifyIdempotencyTTL = 48 * time.Hour
+)
 
-// SubscriptionOrderService handles purchase orders for subscriptions.
+// SubscriptionOrderService handles purchase orders for subscriptions and balance top-ups.
 type SubscriptionOrderService struct {
-	entClient           *ent.Client
-	groupRepo           GroupRepository
-	orderRepo           SubscriptionOrderRepository
-	subscriptionService *SubscriptionService
-	settingService      *SettingService
-	xunhuPayClient      *XunhuPayClient
+	entClient            *ent.Client
+	groupRepo            GroupRepository
+	orderRepo            SubscriptionOrderRepository
+	userRepo             UserRepository
+	subscriptionService  *SubscriptionService
+	billingCacheService  *BillingCacheService
+	authCacheInvalidator APIKeyAuthCacheInvalidator
+	settingService       *SettingService
+	xunhuPayClient       *XunhuPayClient
+	webhooks             *UserWebhookService
+
+	// providerFactory 按渠道名称构建支付实现（测试中可替换为指向本地假服务的实现）
+	providerFactory func(name string, settings *SystemSettings) (PaymentProvider, error)
 }
 
 // NewSubscriptionOrderService creates a new order service.
@@ -41,18 +60,33 @@ func NewSubscriptionOrderService(
 	entClient *ent.Client,
 	groupRepo GroupRepository,
 	orderRepo SubscriptionOrderRepository,
+	userRepo UserRepository,
 	subscriptionService *SubscriptionService,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
 	settingService *SettingService,
 	xunhuPayClient *XunhuPayClient,
 ) *SubscriptionOrderService {
-	return &SubscriptionOrderService{
-		entClient:           entClient,
-		groupRepo:           groupRepo,
-		orderRepo:           orderRepo,
-		subscriptionService: subscriptionService,
-		settingService:      settingService,
-		xunhuPayClient:      xunhuPayClient,
-	}
+	s := &SubscriptionOrderService{
+		entClient:            entClient,
+		groupRepo:            groupRepo,
+		orderRepo:            orderRepo,
+		userRepo:             userRepo,
+		subscriptionService:  subscriptionService,
+		billingCacheService:  billingCacheService,
+		authCacheInvalidator: authCacheInvalidator,
+		settingService:       settingService,
+		xunhuPayClient:       xunhuPayClient,
+	}
+	s.providerFactory = func(name string, settings *SystemSettings) (PaymentProvider, error) {
+		return newPaymentProviderFromSettings(name, settings, s.xunhuPayClient)
+	}
+	return s
+}
+
+// SetUserWebhookService wires user webhooks so that paid orders emit order.paid events.
+func (s *SubscriptionOrderService) SetUserWebhookService(webhooks *UserWebhookService) {
+	s.webhooks = webhooks
 }
 
 // ListPlans returns purchasable subscription plans.
@@ -60,7 +94,38 @@ func (s *SubscriptionOrderService) ListPlans(ctx context.Context) ([]Group, erro
 	return s.groupRepo.ListPurchasePlans(ctx)
 }
 
-// CreateOrder creates a new order. Paid plans remain pending until payment is confirmed.
+// TopUpConfig describes the balance top-up options shown to users.
+type TopUpConfig struct {
+	Enabled   bool
+	MinAmount float64
+	MaxAmount float64 // 0 表示不限
+	Rate      float64 // 每支付 1 单位货币到账的余额
+	Currency  string
+}
+
+// GetTopUpConfig returns the current balance top-up configuration.
+func (s *SubscriptionOrderService) GetTopUpConfig(ctx context.Context) (*TopUpConfig, error) {
+	if s.settingService == nil {
+		return &TopUpConfig{Currency: CurrencyCNY, Rate: 1}, nil
+	}
+	settings, err := s.settingService.GetAllSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	cfg := &TopUpConfig{
+		Enabled:   settings.BalanceTopUpEnabled,
+		MinAmount: settings.BalanceTopUpMinAmount,
+		MaxAmount: settings.BalanceTopUpMaxAmount,
+		Rate:      settings.BalanceTopUpRate,
+		Currency:  CurrencyCNY,
+	}
+	if provider, err := s.providerFactory(settings.PaymentProvider, settings); err == nil {
+		cfg.Currency = provider.Currency()
+	}
+	return cfg, nil
+}
+
+// CreateOrder creates a new subscription order. Paid plans remain pending until payment is confirmed.
 func (s *SubscriptionOrderService) CreateOrder(ctx context.Context, userID, groupID int64, notes string) (*SubscriptionOrder, error) {
 	group, err := s.groupRepo.GetByID(ctx, groupID)
 	if err != nil {
@@ -78,18 +143,23 @@ func (s *SubscriptionOrderService) CreateOrder(ctx context.Context, userID, grou
 		return nil, err
 	}
 
-	paymentProvider, cfg, err := s.resolvePaymentConfig(ctx, amount)
+	providerName, provider, err := s.resolvePaymentProvider(ctx, amount)
 	if err != nil {
 		return nil, err
 	}
+	currency := CurrencyCNY
+	if provider != nil {
+		currency = provider.Currency()
+	}
 	order := &SubscriptionOrder{
 		OrderNo:         orderNo,
+		OrderType:       OrderTypeSubscription,
 		UserID:          userID,
-		GroupID:         groupID,
-		PaymentProvider: paymentProvider,
+		GroupID:         &groupID,
+		PaymentProvider: providerName,
 		Status:          OrderStatusPending,
 		Amount:          amount,
-		Currency:        CurrencyCNY,
+		Currency:        currency,
 		ValidityDays:    validityDays,
 		Notes:           notes,
 	}
@@ -103,30 +173,82 @@ func (s *SubscriptionOrderService) CreateOrder(ctx context.Context, userID, grou
 		return s.activateOrder(ctx, order, info)
 	}
 
-	if paymentProvider == PaymentProviderManual {
-		return s.orderRepo.GetByID(ctx, order.ID)
+	return s.startPayment(ctx, order, provider, fmt.Sprintf("%s %d天", group.Name, validityDays))
+}
+
+// CreateTopUpOrder creates a pending balance top-up order for the given payment amount.
+func (s *SubscriptionOrderService) CreateTopUpOrder(ctx context.Context, userID int64, amount float64, notes string) (*SubscriptionOrder, error) {
+	if s.settingService == nil {
+		return nil, ErrTopUpDisabled
+	}
+	settings, err := s.settingService.GetAllSettings(ctx)
+	if err != nil {
+		return nil, err
+	}
+	if !settings.BalanceTopUpEnabled {
+		return nil, ErrTopUpDisabled
+	}
+	amount = math.Round(amount*100) / 100
+	if amount <= 0 || amount < settings.BalanceTopUpMinAmount ||
+		(settings.BalanceTopUpMaxAmount > 0 && amount > settings.BalanceTopUpMaxAmount) {
+		return nil, ErrTopUpInvalidAmount
 	}
 
-	if paymentProvider == PaymentProviderXunhuPay {
-		title := fmt.Sprintf("%s %d天", group.Name, validityDays)
-		payResp, err := s.xunhuPayClient.CreatePayment(ctx, *cfg, order, title)
-		if err != nil {
-			canceledAt := time.Now()
-			_ = s.orderRepo.UpdateStatus(ctx, order.ID, OrderStatusCanceled, nil, &canceledAt)
-			return nil, err
-		}
-		order.PaymentURL = payResp.URL
-		order.PaymentQRCode = payResp.URLQRCode
-		if cfg.Plugins != "" {
-			order.PaymentPlugin = cfg.Plugins
-		}
-		if err := s.orderRepo.Update(ctx, order); err != nil {
-			return nil, err
-		}
-		return s.orderRepo.GetByID(ctx, order.ID)
+	orderNo, err := GenerateOrderNo()
+	if err != nil {
+		return nil, err
+	}
+	providerName, provider, err := s.resolvePaymentProvider(ctx, amount)
+	if err != nil {
+		return nil, err
 	}
+	currency := CurrencyCNY
+	if provider != nil {
+		currency = provider.Currency()
+	}
+	credit := math.Round(amount*settings.BalanceTopUpRate*1e8) / 1e8
+	order := &SubscriptionOrder{
+		OrderNo:         orderNo,
+		OrderType:       OrderTypeBalance,
+		UserID:          userID,
+		PaymentProvider: providerName,
+		Status:          OrderStatusPending,
+		Amount:          amount,
+		Currency:        currency,
+		CreditAmount:    credit,
+		Notes:           notes,
+	}
+	if err := s.orderRepo.Create(ctx, order); err != nil {
+		return nil, err
+	}
+	return s.startPayment(ctx, order, provider, fmt.Sprintf("余额充值 %s", strconv.FormatFloat(credit, 'f', -1, 64)))
+}
 
-	return nil, ErrPaymentNotConfigured
+// startPayment creates the payment at the provider and stores the payment URLs on the order.
+// Manual orders stay pending until an admin marks them paid.
+func (s *SubscriptionOrderService) startPayment(ctx context.Context, order *SubscriptionOrder, provider PaymentProvider, subject string) (*SubscriptionOrder, error) {
+	if provider == nil {
+		return s.orderRepo.GetByID(ctx, order.ID)
+	}
+	payResp, err := provider.CreatePayment(ctx, &PaymentCreateRequest{
+		OrderNo:  order.OrderNo,
+		Amount:   order.Amount,
+		Currency: order.Currency,
+		Subject:  subject,
+	})
+	if err != nil {
+		canceledAt := time.Now()
+		_ = s.orderRepo.UpdateStatus(ctx, order.ID, OrderStatusCanceled, nil, &canceledAt)
+		return nil, err
+	}
+	order.PaymentURL = payResp.URL
+	order.PaymentQRCode = payResp.QRCode
+	order.PaymentOpenOrderID = payResp.OpenOrderID
+	order.PaymentPlugin = payResp.Plugin
+	if err := s.orderRepo.Update(ctx, order); err != nil {
+		return nil, err
+	}
+	return s.orderRepo.GetByID(ctx, order.ID)
 }
 
 // ListUserOrders lists orders for a user.
@@ -188,76 +310,212 @@ func (s *SubscriptionOrderService) Cancel(ctx context.Context, id int64) (*Subsc
 	return s.orderRepo.GetByID(ctx, id)
 }
 
-// HandleXunhuPayNotify handles payment notify callback.
-func (s *SubscriptionOrderService) HandleXunhuPayNotify(ctx context.Context, payload XunhuPayNotifyPayload) error {
+// HandlePaymentNotify verifies and applies a payment provider callback.
+// It always returns the acknowledgement the provider expects, together with the processing error (if any).
+// Duplicate deliveries of the same provider event are deduplicated through the idempotency records.
+func (s *SubscriptionOrderService) HandlePaymentNotify(ctx context.Context, providerName string, req *PaymentNotifyRequest) (PaymentNotifyAck, error) {
 	if err := s.cancelExpiredPending(ctx); err != nil {
 		log.Printf("[Order] cancel expired pending failed: %v", err)
 	}
-	if s.settingService == nil || s.xunhuPayClient == nil {
-		return ErrPaymentNotConfigured
+	provider, err := s.paymentProvider(ctx, providerName)
+	if err != nil {
+		return textNotifyAck(err), err
 	}
-	settings, err := s.settingService.GetAllSettings(ctx)
+	notification, err := provider.ParseNotify(ctx, req)
+	if err != nil {
+		return provider.NotifyAck(err), err
+	}
+	if !notification.Paid {
+		return provider.NotifyAck(nil), nil
+	}
+
+	apply := func(ctx context.Context) (any, error) {
+		if err := s.applyPaymentNotification(ctx, provider.Name(), notification); err != nil {
+			return nil, err
+		}
+		return map[string]string{"order_no": notification.OrderNo}, nil
+	}
+	coordinator := DefaultIdempotencyCoordinator()
+	if coordinator == nil || notification.EventID == "" {
+		_, err = apply(ctx)
+		return provider.NotifyAck(err), err
+	}
+	_, err = coordinator.Execute(ctx, IdempotencyExecuteOptions{
+		Scope:          "payment.notify." + provider.Name(),
+		ActorScope:     "payment:" + provider.Name(),
+		Method:         http.MethodPost,
+		Route:          "/api/v1/payment/" + provider.Name() + "/notify",
+		IdempotencyKey: HashIdempotencyKey(provider.Name() + ":" + notification.EventID),
+		Payload:        notification,
+		TTL:            paymentNotifyIdempotencyTTL,
+	}, apply)
+	return provider.NotifyAck(err), err
+}
+
+func (s *SubscriptionOrderService) applyPaymentNotification(ctx context.Context, providerName string, n *PaymentNotification) error {
+	order, err := s.orderRepo.GetByOrderNo(ctx, n.OrderNo)
 	if err != nil {
 		return err
 	}
-	if settings.PaymentProvider != PaymentProviderXunhuPay {
-		return ErrPaymentNotConfigured
+	if order.Status == OrderStatusPaid || order.Status == OrderStatusRefunding || order.Status == OrderStatusRefunded {
+		return nil
 	}
-	if settings.XunhuPayAppSecret == "" {
-		return ErrPaymentNotConfigured
+	if order.PaymentProvider != "" && order.PaymentProvider != providerName {
+		return ErrPaymentProviderMismatch
 	}
-	if payload.AppID != settings.XunhuPayAppID {
-		return ErrPaymentInvalidSignature
+	if n.Amount >= 0 && !amountMatches(order.Amount, n.Amount) {
+		return ErrPaymentAmountMismatch
 	}
-	if !s.xunhuPayClient.VerifyNotify(payload, settings.XunhuPayAppSecret) {
-		return ErrPaymentInvalidSignature
+	if n.Currency != "" && !strings.EqualFold(n.Currency, order.Currency) {
+		return ErrPaymentCurrencyMismatch
 	}
-	if payload.Status != "OD" {
-		return nil
+
+	_, err = s.activateOrder(ctx, order, &PaymentResult{
+		Provider:      providerName,
+		TransactionID: n.TransactionID,
+		OpenOrderID:   n.OpenOrderID,
+		Plugin:        n.Plugin,
+		// 支付超时被自动取消后仍可能收到真实的支付成功回调，此时按已支付处理
+		resumeCanceled: true,
+	})
+	return err
+}
+
+// Refund refunds a paid order in full through its payment provider and reverses the granted benefit:
+// balance orders deduct the credited balance, subscription orders shorten the subscription.
+func (s *SubscriptionOrderService) Refund(ctx context.Context, id int64, reason string) (*SubscriptionOrder, error) {
+	order, err := s.orderRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if order.Status != OrderStatusPaid {
+		return nil, ErrOrderInvalidStatus
+	}
+	if order.OrderType == OrderTypeBalance && s.userRepo != nil {
+		user, err := s.userRepo.GetByID(ctx, order.UserID)
+		if err != nil {
+			return nil, err
+		}
+		if user.Balance < order.CreditAmount {
+			return nil, ErrRefundInsufficientFunds
+		}
 	}
 
-	order, err := s.orderRepo.GetByOrderNo(ctx, payload.TradeOrderID)
+	// 先把订单条件流转到 refunding 占位，再调用支付渠道退款：并发退款只有一个能抢到该订单；
+	// 渠道已退款但后续落库失败时订单停留在 refunding，不会回到 paid 被再次退款，留待人工对账
+	claimed, err := s.orderRepo.TransitionStatus(ctx, order.ID, []string{OrderStatusPaid}, OrderStatusRefunding)
 	if err != nil {
-		return err
+		return nil, err
 	}
-	if order.Status == OrderStatusPaid {
-		return nil
+	if !claimed {
+		return nil, ErrOrderInvalidStatus
 	}
-	if order.Status != OrderStatusPending {
-		return ErrOrderInvalidStatus
+
+	refundedUpstream := false
+	if order.Amount > 0 && IsOnlinePaymentProvider(order.PaymentProvider) {
+		provider, err := s.paymentProvider(ctx, order.PaymentProvider)
+		if err != nil {
+			s.releaseRefundClaim(ctx, order)
+			return nil, err
+		}
+		if _, err := provider.Refund(ctx, &PaymentRefundRequest{
+			OrderNo:       order.OrderNo,
+			RefundNo:      "R" + order.OrderNo,
+			TransactionID: order.PaymentTransactionID,
+			Amount:        order.Amount,
+			Currency:      order.Currency,
+			Reason:        reason,
+		}); err != nil {
+			s.releaseRefundClaim(ctx, order)
+			return nil, err
+		}
+		refundedUpstream = true
 	}
 
-	if payload.TotalFee != "" {
-		if amount, err := strconv.ParseFloat(payload.TotalFee, 64); err == nil {
-			if !amountMatches(order.Amount, amount) {
-				return ErrPaymentAmountMismatch
-			}
+	// 渠道退款已发生后不再受调用方取消影响
+	if err := s.completeRefund(context.WithoutCancel(ctx), order); err != nil {
+		if refundedUpstream {
+			log.Printf("[Order] order %s was refunded by %s but finishing the refund failed, left in %s: %v",
+				order.OrderNo, order.PaymentProvider, OrderStatusRefunding, err)
+		} else {
+			s.releaseRefundClaim(ctx, order)
 		}
+		return nil, err
 	}
 
-	info := &PaymentResult{
-		Provider:      PaymentProviderXunhuPay,
-		TransactionID: payload.TransactionID,
-		OpenOrderID:   payload.OpenOrderID,
-		Plugin:        payload.Plugins,
+	if order.OrderType == OrderTypeBalance {
+		s.invalidateBalanceCaches(ctx, order.UserID)
+	} else if order.SubscriptionID != nil && s.subscriptionService != nil {
+		s.revokeSubscriptionDays(ctx, order)
 	}
-	returnValue, err := s.activateOrder(ctx, order, info)
+	return s.orderRepo.GetByID(ctx, order.ID)
+}
+
+// completeRefund 在事务内把 refunding 订单落为 refunded，并扣回充值发放的余额
+func (s *SubscriptionOrderService) completeRefund(ctx context.Context, order *SubscriptionOrder) error {
+	tx, err := s.entClient.Tx(ctx)
+	if err != nil {
+		return fmt.Errorf("begin transaction: %w", err)
+	}
+	defer func() { _ = tx.Rollback() }()
+	txCtx := ent.NewTxContext(ctx, tx)
+
+	claimed, err := s.orderRepo.TransitionStatus(txCtx, order.ID, []string{OrderStatusRefunding}, OrderStatusRefunded)
 	if err != nil {
 		return err
 	}
-	_ = returnValue
+	if !claimed {
+		return ErrOrderInvalidStatus
+	}
+
+	if order.OrderType == OrderTypeBalance && order.CreditAmount > 0 {
+		if err := s.userRepo.DeductBalance(txCtx, order.UserID, order.CreditAmount); err != nil {
+			return fmt.Errorf("deduct user balance: %w", err)
+		}
+	}
+	refundedAt := time.Now()
+	order.Status = OrderStatusRefunded
+	order.RefundedAt = &refundedAt
+	if err := s.orderRepo.Update(txCtx, order); err != nil {
+		return err
+	}
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("commit transaction: %w", err)
+	}
 	return nil
 }
 
+// releaseRefundClaim 支付渠道未退款时把占位的订单恢复为 paid，允许重新发起退款
+func (s *SubscriptionOrderService) releaseRefundClaim(ctx context.Context, order *SubscriptionOrder) {
+	if _, err := s.orderRepo.TransitionStatus(context.WithoutCancel(ctx), order.ID, []string{OrderStatusRefunding}, OrderStatusPaid); err != nil {
+		log.Printf("[Order] release refund claim for order %s failed: %v", order.OrderNo, err)
+	}
+}
+
+// revokeSubscriptionDays 退款后回收订单发放的订阅时长；剩余时长不足时直接撤销订阅
+func (s *SubscriptionOrderService) revokeSubscriptionDays(ctx context.Context, order *SubscriptionOrder) {
+	subID := *order.SubscriptionID
+	_, err := s.subscriptionService.ExtendSubscription(ctx, subID, -order.ValidityDays)
+	if errors.Is(err, ErrAdjustWouldExpire) {
+		err = s.subscriptionService.RevokeSubscription(ctx, subID)
+	}
+	if err != nil {
+		log.Printf("[Order] revoke subscription days for refunded order %s failed: %v", order.OrderNo, err)
+	}
+}
+
 type PaymentResult struct {
 	Provider      string
 	TransactionID string
 	OpenOrderID   string
 	Plugin        string
+
+	resumeCanceled bool
 }
 
 func (s *SubscriptionOrderService) activateOrder(ctx context.Context, order *SubscriptionOrder, info *PaymentResult) (*SubscriptionOrder, error) {
-	if order.Status != OrderStatusPending {
+	resumable := info != nil && info.resumeCanceled && order.Status == OrderStatusCanceled
+	if order.Status != OrderStatusPending && !resumable {
 		return nil, ErrOrderInvalidStatus
 	}
 	paidAt := time.Now()
@@ -269,20 +527,45 @@ func (s *SubscriptionOrderService) activateOrder(ctx context.Context, order *Sub
 	defer func() { _ = tx.Rollback() }()
 	txCtx := ent.NewTxContext(ctx, tx)
 
-	sub, _, err := s.subscriptionService.AssignOrExtendSubscription(txCtx, &AssignSubscriptionInput{
-		UserID:       order.UserID,
-		GroupID:      order.GroupID,
-		ValidityDays: order.ValidityDays,
-		AssignedBy:   0,
-		Notes:        fmt.Sprintf("订单 %s", order.OrderNo),
-	})
+	// 先做条件状态流转再发放余额/订阅：并发的支付回调、手动标记已支付只有一个能抢到该订单，
+	// 其余在行锁释放后因状态不匹配而失败，避免重复发放。
+	from := []string{OrderStatusPending}
+	if resumable {
+		from = append(from, OrderStatusCanceled)
+	}
+	claimed, err := s.orderRepo.TransitionStatus(txCtx, order.ID, from, OrderStatusPaid)
 	if err != nil {
 		return nil, err
 	}
+	if !claimed {
+		return nil, ErrOrderInvalidStatus
+	}
+
+	switch order.OrderType {
+	case OrderTypeBalance:
+		if err := s.userRepo.UpdateBalance(txCtx, order.UserID, order.CreditAmount); err != nil {
+			return nil, fmt.Errorf("update user balance: %w", err)
+		}
+	default:
+		if order.GroupID == nil {
+			return nil, ErrOrderInvalidPlan
+		}
+		sub, _, err := s.subscriptionService.AssignOrExtendSubscription(txCtx, &AssignSubscriptionInput{
+			UserID:       order.UserID,
+			GroupID:      *order.GroupID,
+			ValidityDays: order.ValidityDays,
+			AssignedBy:   0,
+			Notes:        fmt.Sprintf("订单 %s", order.OrderNo),
+		})
+		if err != nil {
+			return nil, err
+		}
+		order.SubscriptionID = &sub.ID
+	}
 
 	order.Status = OrderStatusPaid
 	order.PaidAt = &paidAt
-	order.SubscriptionID = &sub.ID
+	order.CanceledAt = nil
 	if info != nil {
 		if info.Provider != "" {
 			order.PaymentProvider = info.Provider
@@ -306,42 +589,74 @@ func (s *SubscriptionOrderService) activateOrder(ctx context.Context, order *Sub
 		return nil, fmt.Errorf("commit transaction: %w", err)
 	}
 
+	if order.OrderType == OrderTypeBalance {
+		s.invalidateBalanceCaches(ctx, order.UserID)
+	}
+	s.webhooks.EmitAsync(UserWebhookEvent{UserID: order.UserID, Type: UserWebhookEventOrderPaid, Data: map[string]any{
+		"order_id":        order.ID,
+		"order_no":        order.OrderNo,
+		"order_type":      order.OrderType,
+		"amount":          order.Amount,
+		"currency":        order.Currency,
+		"credit_amount":   order.CreditAmount,
+		"group_id":        order.GroupID,
+		"subscription_id": order.SubscriptionID,
+		"validity_days":   order.ValidityDays,
+		"payment_method":  order.PaymentProvider,
+		"paid_at":         paidAt.UTC(),
+	}})
 	return s.orderRepo.GetByID(ctx, order.ID)
 }
 
-func (s *SubscriptionOrderService) resolvePaymentConfig(ctx context.Context, amount float64) (string, *XunhuPayConfig, error) {
+// invalidateBalanceCaches 余额变动后失效认证与计费缓存
+func (s *SubscriptionOrderService) invalidateBalanceCaches(ctx context.Context, userID int64) {
+	if s.authCacheInvalidator != nil {
+		s.authCacheInvalidator.InvalidateAuthCacheByUserID(ctx, userID)
+	}
+	if s.billingCacheService == nil {
+		return
+	}
+	go func() {
+		cacheCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = s.billingCacheService.InvalidateUserBalance(cacheCtx, userID)
+	}()
+}
+
+// resolvePaymentProvider returns the currently selected provider for new orders.
+// A nil provider means the order is settled manually (free plans or the manual provider).
+func (s *SubscriptionOrderService) resolvePaymentProvider(ctx context.Context, amount float64) (string, PaymentProvider, error) {
 	if amount <= 0 {
 		return PaymentProviderManual, nil, nil
 	}
-	if s.settingService == nil || s.xunhuPayClient == nil {
+	if s.settingService == nil {
 		return "", nil, ErrPaymentNotConfigured
 	}
 	settings, err := s.settingService.GetAllSettings(ctx)
 	if err != nil {
 		return "", nil, err
 	}
-	provider := strings.TrimSpace(settings.PaymentProvider)
-	if provider == "" {
-		provider = PaymentProviderManual
-	}
-	if provider == PaymentProviderManual {
-		return provider, nil, nil
+	name := strings.TrimSpace(settings.PaymentProvider)
+	if name == "" || name == PaymentProviderManual {
+		return PaymentProviderManual, nil, nil
 	}
-	if provider != PaymentProviderXunhuPay {
-		return provider, nil, ErrPaymentNotConfigured
+	provider, err := s.providerFactory(name, settings)
+	if err != nil {
+		return name, nil, err
 	}
-	cfg := &XunhuPayConfig{
-		AppID:     settings.XunhuPayAppID,
-		AppSecret: settings.XunhuPayAppSecret,
-		Gateway:   settings.XunhuPayGateway,
-		NotifyURL: settings.XunhuPayNotifyURL,
-		ReturnURL: settings.XunhuPayReturnURL,
-		Plugins:   settings.XunhuPayPlugins,
+	return name, provider, nil
+}
+
+// paymentProvider builds the named provider from the current settings (used for notify and refunds).
+func (s *SubscriptionOrderService) paymentProvider(ctx context.Context, name string) (PaymentProvider, error) {
+	if s.settingService == nil || !IsOnlinePaymentProvider(name) {
+		return nil, ErrPaymentNotConfigured
 	}
-	if cfg.AppID == "" || cfg.AppSecret == "" || cfg.Gateway == "" || cfg.NotifyURL == "" {
-		return provider, nil, ErrPaymentNotConfigured
+	settings, err := s.settingService.GetAllSettings(ctx)
+	if err != nil {
+		return nil, err
 	}
-	return provider, cfg, nil
+	return s.providerFactory(name, settings)
 }
 
 func (s *SubscriptionOrderService) cancelExpiredPending(ctx context.Context) error {
@@ -0,0 +1,404 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"slices"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"entgo.io/ent/dialect"
+	entsql "entgo.io/ent/dialect/sql"
+	dbent "github.com/Wei-Shaw/sub2api/ent"
+	"github.com/Wei-Shaw/sub2api/ent/enttest"
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+
+	_ "modernc.org/sqlite"
+)
+
+type orderRepoStub struct {
+	mu          sync.Mutex
+	orders      map[int64]*SubscriptionOrder
+	nextID      int64
+	lookupCount int
+}
+
+func newOrderRepoStub() *orderRepoStub {
+	return &orderRepoStub{orders: map[int64]*SubscriptionOrder{}}
+}
+
+func (r *orderRepoStub) Create(_ context.Context, order *SubscriptionOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	order.ID = r.nextID
+	cp := *order
+	r.orders[order.ID] = &cp
+	return nil
+}
+
+func (r *orderRepoStub) GetByID(_ context.Context, id int64) (*SubscriptionOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.orders[id]
+	if !ok {
+		return nil, ErrOrderNotFound
+	}
+	cp := *order
+	return &cp, nil
+}
+
+func (r *orderRepoStub) GetByOrderNo(_ context.Context, orderNo string) (*SubscriptionOrder, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.lookupCount++
+	for _, order := range r.orders {
+		if order.OrderNo == orderNo {
+			cp := *order
+			return &cp, nil
+		}
+	}
+	return nil, ErrOrderNotFound
+}
+
+func (r *orderRepoStub) List(context.Context, pagination.PaginationParams, SubscriptionOrderFilters) ([]SubscriptionOrder, *pagination.PaginationResult, error) {
+	panic("unexpected List call")
+}
+
+func (r *orderRepoStub) Update(_ context.Context, order *SubscriptionOrder) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *order
+	r.orders[order.ID] = &cp
+	return nil
+}
+
+func (r *orderRepoStub) UpdateStatus(_ context.Context, id int64, status string, paidAt, canceledAt *time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order := r.orders[id]
+	order.Status = status
+	order.PaidAt = paidAt
+	order.CanceledAt = canceledAt
+	return nil
+}
+
+func (r *orderRepoStub) TransitionStatus(_ context.Context, id int64, from []string, to string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	order, ok := r.orders[id]
+	if !ok || !slices.Contains(from, order.Status) {
+		return false, nil
+	}
+	order.Status = to
+	return true, nil
+}
+
+func (r *orderRepoStub) SetSubscriptionID(context.Context, int64, int64) error {
+	panic("unexpected SetSubscriptionID call")
+}
+
+type paymentSettingRepoStub struct {
+	settingPublicRepoStub
+}
+
+func (s *paymentSettingRepoStub) GetAll(context.Context) (map[string]string, error) {
+	return s.values, nil
+}
+
+// fakePaymentProvider 直接返回预设通知，不做签名校验
+type fakePaymentProvider struct {
+	name         string
+	notification *PaymentNotification
+	created      []*PaymentCreateRequest
+	refundErr    error
+	onRefund     func()
+}
+
+func (p *fakePaymentProvider) Name() string     { return p.name }
+func (p *fakePaymentProvider) Currency() string { return "USD" }
+func (p *fakePaymentProvider) CreatePayment(_ context.Context, req *PaymentCreateRequest) (*PaymentCreateResult, error) {
+	p.created = append(p.created, req)
+	return &PaymentCreateResult{URL: "https://pay.test/" + req.OrderNo, OpenOrderID: "open-" + req.OrderNo}, nil
+}
+func (p *fakePaymentProvider) ParseNotify(context.Context, *PaymentNotifyRequest) (*PaymentNotification, error) {
+	cp := *p.notification
+	return &cp, nil
+}
+func (p *fakePaymentProvider) NotifyAck(err error) PaymentNotifyAck { return textNotifyAck(err) }
+func (p *fakePaymentProvider) Refund(context.Context, *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	if p.onRefund != nil {
+		p.onRefund()
+	}
+	if p.refundErr != nil {
+		return nil, p.refundErr
+	}
+	return &PaymentRefundResult{RefundID: "re_1"}, nil
+}
+
+func newPaymentTestService(t *testing.T, settings map[string]string, provider *fakePaymentProvider) (*SubscriptionOrderService, *orderRepoStub) {
+	t.Helper()
+	repo := newOrderRepoStub()
+	settingService := NewSettingService(&paymentSettingRepoStub{settingPublicRepoStub{values: settings}}, &config.Config{})
+	svc := NewSubscriptionOrderService(nil, nil, repo, nil, nil, nil, nil, settingService, nil)
+	svc.providerFactory = func(name string, _ *SystemSettings) (PaymentProvider, error) {
+		if name != provider.name {
+			return nil, ErrPaymentNotConfigured
+		}
+		return provider, nil
+	}
+	return svc, repo
+}
+
+func TestSubscriptionOrderService_CreateTopUpOrder(t *testing.T) {
+	provider := &fakePaymentProvider{name: PaymentProviderStripe}
+	svc, repo := newPaymentTestService(t, map[string]string{
+		SettingKeyPaymentProvider:       PaymentProviderStripe,
+		SettingKeyBalanceTopUpEnabled:   "true",
+		SettingKeyBalanceTopUpMinAmount: "5",
+		SettingKeyBalanceTopUpMaxAmount: "100",
+		SettingKeyBalanceTopUpRate:      "1.5",
+	}, provider)
+
+	order, err := svc.CreateTopUpOrder(context.Background(), 7, 10.004, "")
+	require.NoError(t, err)
+	require.Equal(t, OrderTypeBalance, order.OrderType)
+	require.Nil(t, order.GroupID)
+	require.Equal(t, OrderStatusPending, order.Status)
+	require.Equal(t, "USD", order.Currency)
+	require.InDelta(t, 10.0, order.Amount, 1e-9)
+	require.InDelta(t, 15.0, order.CreditAmount, 1e-9)
+	require.Equal(t, "https://pay.test/"+order.OrderNo, order.PaymentURL)
+	require.Len(t, provider.created, 1)
+	require.Len(t, repo.orders, 1)
+
+	_, err = svc.CreateTopUpOrder(context.Background(), 7, 1, "")
+	require.ErrorIs(t, err, ErrTopUpInvalidAmount)
+	_, err = svc.CreateTopUpOrder(context.Background(), 7, 101, "")
+	require.ErrorIs(t, err, ErrTopUpInvalidAmount)
+
+	cfg, err := svc.GetTopUpConfig(context.Background())
+	require.NoError(t, err)
+	require.True(t, cfg.Enabled)
+	require.Equal(t, "USD", cfg.Currency)
+}
+
+func TestSubscriptionOrderService_CreateTopUpOrderDisabled(t *testing.T) {
+	provider := &fakePaymentProvider{name: PaymentProviderStripe}
+	svc, _ := newPaymentTestService(t, map[string]string{
+		SettingKeyPaymentProvider: PaymentProviderStripe,
+	}, provider)
+
+	_, err := svc.CreateTopUpOrder(context.Background(), 7, 10, "")
+	require.ErrorIs(t, err, ErrTopUpDisabled)
+}
+
+func TestSubscriptionOrderService_HandlePaymentNotifyRejectsMismatch(t *testing.T) {
+	provider := &fakePaymentProvider{name: PaymentProviderStripe}
+	svc, repo := newPaymentTestService(t, map[string]string{SettingKeyPaymentProvider: PaymentProviderStripe}, provider)
+	require.NoError(t, repo.Create(context.Background(), &SubscriptionOrder{
+		OrderNo: "SO1", OrderType: OrderTypeBalance, PaymentProvider: PaymentProviderStripe,
+		Status: OrderStatusPending, Amount: 10,
+	}))
+	require.NoError(t, repo.Create(context.Background(), &SubscriptionOrder{
+		OrderNo: "SO2", OrderType: OrderTypeBalance, PaymentProvider: PaymentProviderAlipay,
+		Status: OrderStatusPending, Amount: 10,
+	}))
+	require.NoError(t, repo.Create(context.Background(), &SubscriptionOrder{
+		OrderNo: "SO3", OrderType: OrderTypeBalance, PaymentProvider: PaymentProviderStripe,
+		Status: OrderStatusPending, Amount: 10, Currency: "USD",
+	}))
+
+	provider.notification = &PaymentNotification{OrderNo: "SO1", Amount: 9.5, Paid: true}
+	ack, err := svc.HandlePaymentNotify(context.Background(), PaymentProviderStripe, &PaymentNotifyRequest{})
+	require.ErrorIs(t, err, ErrPaymentAmountMismatch)
+	require.Equal(t, "fail", ack.Body)
+
+	provider.notification = &PaymentNotification{OrderNo: "SO2", Amount: 10, Paid: true}
+	_, err = svc.HandlePaymentNotify(context.Background(), PaymentProviderStripe, &PaymentNotifyRequest{})
+	require.ErrorIs(t, err, ErrPaymentProviderMismatch)
+
+	// 金额相同但币种不同
+	provider.notification = &PaymentNotification{OrderNo: "SO3", Amount: 10, Currency: "EUR", Paid: true}
+	ack, err = svc.HandlePaymentNotify(context.Background(), PaymentProviderStripe, &PaymentNotifyRequest{})
+	require.ErrorIs(t, err, ErrPaymentCurrencyMismatch)
+	require.Equal(t, "fail", ack.Body)
+	order, err := repo.GetByOrderNo(context.Background(), "SO3")
+	require.NoError(t, err)
+	require.Equal(t, OrderStatusPending, order.Status)
+
+	// 非支付成功事件只应答，不触碰订单
+	provider.notification = &PaymentNotification{OrderNo: "SO1", Amount: 1}
+	ack, err = svc.HandlePaymentNotify(context.Background(), PaymentProviderStripe, &PaymentNotifyRequest{})
+	require.NoError(t, err)
+	require.Equal(t, "success", ack.Body)
+}
+
+func TestSubscriptionOrderService_HandlePaymentNotifyDeduplicatesEvents(t *testing.T) {
+	prev := DefaultIdempotencyCoordinator()
+	SetDefaultIdempotencyCoordinator(NewIdempotencyCoordinator(newInMemoryIdempotencyRepo(), DefaultIdempotencyConfig()))
+	t.Cleanup(func() { SetDefaultIdempotencyCoordinator(prev) })
+
+	provider := &fakePaymentProvider{name: PaymentProviderStripe}
+	svc, repo := newPaymentTestService(t, map[string]string{SettingKeyPaymentProvider: PaymentProviderStripe}, provider)
+	paidAt := time.Now()
+	require.NoError(t, repo.Create(context.Background(), &SubscriptionOrder{
+		OrderNo: "SO1", OrderType: OrderTypeBalance, PaymentProvider: PaymentProviderStripe,
+		Status: OrderStatusPaid, Amount: 10, PaidAt: &paidAt,
+	}))
+
+	provider.notification = &PaymentNotification{EventID: "evt_1", OrderNo: "SO1", Amount: 10, Paid: true}
+	for i := 0; i < 3; i++ {
+		ack, err := svc.HandlePaymentNotify(context.Background(), PaymentProviderStripe, &PaymentNotifyRequest{})
+		require.NoError(t, err)
+		require.Equal(t, "success", ack.Body)
+	}
+	require.Equal(t, 1, repo.lookupCount, "重复投递的同一事件只应处理一次")
+
+	provider.notification = &PaymentNotification{EventID: "evt_2", OrderNo: "SO1", Amount: 10, Paid: true}
+	_, err := svc.HandlePaymentNotify(context.Background(), PaymentProviderStripe, &PaymentNotifyRequest{})
+	require.NoError(t, err)
+	require.Equal(t, 2, repo.lookupCount)
+}
+
+// orderBalanceUserRepoStub 记录订单激活/退款时的余额变动
+type orderBalanceUserRepoStub struct {
+	UserRepository
+
+	mu       sync.Mutex
+	balance  float64
+	credits  int
+	deducted int
+}
+
+func (r *orderBalanceUserRepoStub) GetByID(_ context.Context, id int64) (*User, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return &User{ID: id, Balance: r.balance}, nil
+}
+
+func (r *orderBalanceUserRepoStub) UpdateBalance(_ context.Context, _ int64, amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.balance += amount
+	r.credits++
+	return nil
+}
+
+func (r *orderBalanceUserRepoStub) DeductBalance(_ context.Context, _ int64, amount float64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.balance -= amount
+	r.deducted++
+	return nil
+}
+
+func newOrderTestEntClient(t *testing.T) *dbent.Client {
+	t.Helper()
+	db, err := sql.Open("sqlite", "file:subscription_order_service?mode=memory&cache=shared")
+	require.NoError(t, err)
+	t.Cleanup(func() { _ = db.Close() })
+	_, err = db.Exec("PRAGMA foreign_keys = ON")
+	require.NoError(t, err)
+	client := enttest.NewClient(t, enttest.WithOptions(dbent.Driver(entsql.OpenDB(dialect.SQLite, db))))
+	t.Cleanup(func() { _ = client.Close() })
+	return client
+}
+
+func TestSubscriptionOrderService_ConcurrentActivationPaysOnce(t *testing.T) {
+	repo := newOrderRepoStub()
+	users := &orderBalanceUserRepoStub{}
+	svc := NewSubscriptionOrderService(newOrderTestEntClient(t), nil, repo, users, nil, nil, nil, nil, nil)
+	require.NoError(t, repo.Create(context.Background(), &SubscriptionOrder{
+		OrderNo: "SO1", UserID: 7, OrderType: OrderTypeBalance, PaymentProvider: PaymentProviderManual,
+		Status: OrderStatusPending, Amount: 10, CreditAmount: 15,
+	}))
+
+	const workers = 8
+	var (
+		wg        sync.WaitGroup
+		start     = make(chan struct{})
+		errs      = make(chan error, workers)
+		succeeded atomic.Int32
+	)
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			<-start
+			_, err := svc.MarkPaid(context.Background(), 1)
+			if err != nil {
+				errs <- err
+				return
+			}
+			succeeded.Add(1)
+		}()
+	}
+	close(start)
+	wg.Wait()
+	close(errs)
+
+	require.Equal(t, int32(1), succeeded.Load())
+	for err := range errs {
+		require.ErrorIs(t, err, ErrOrderInvalidStatus)
+	}
+	require.Equal(t, 1, users.credits, "余额只能发放一次")
+	require.InDelta(t, 15.0, users.balance, 1e-9)
+	require.Equal(t, OrderStatusPaid, repo.orders[1].Status)
+
+	// 重复退款同样只扣回一次
+	for i := 0; i < 2; i++ {
+		_, err := svc.Refund(context.Background(), 1, "")
+		if i == 0 {
+			require.NoError(t, err)
+		} else {
+			require.ErrorIs(t, err, ErrOrderInvalidStatus)
+		}
+	}
+	require.Equal(t, 1, users.deducted)
+	require.InDelta(t, 0.0, users.balance, 1e-9)
+}
+
+func TestSubscriptionOrderService_RefundClaimsOrderBeforeProvider(t *testing.T) {
+	repo := newOrderRepoStub()
+	users := &orderBalanceUserRepoStub{balance: 15}
+	provider := &fakePaymentProvider{name: PaymentProviderStripe}
+	settingService := NewSettingService(&paymentSettingRepoStub{settingPublicRepoStub{values: map[string]string{}}}, &config.Config{})
+	svc := NewSubscriptionOrderService(newOrderTestEntClient(t), nil, repo, users, nil, nil, nil, settingService, nil)
+	svc.providerFactory = func(string, *SystemSettings) (PaymentProvider, error) { return provider, nil }
+	require.NoError(t, repo.Create(context.Background(), &SubscriptionOrder{
+		OrderNo: "SO1", UserID: 7, OrderType: OrderTypeBalance, PaymentProvider: PaymentProviderStripe,
+		Status: OrderStatusPaid, Amount: 10, CreditAmount: 15,
+	}))
+
+	var statusAtRefund string
+	provider.onRefund = func() {
+		order, err := repo.GetByID(context.Background(), 1)
+		require.NoError(t, err)
+		statusAtRefund = order.Status
+	}
+
+	// 渠道退款失败：占位释放回 paid，余额不动
+	provider.refundErr = errors.New("provider unavailable")
+	_, err := svc.Refund(context.Background(), 1, "")
+	require.ErrorIs(t, err, provider.refundErr)
+	require.Equal(t, OrderStatusRefunding, statusAtRefund, "调用渠道退款前必须已占位")
+	require.Equal(t, OrderStatusPaid, repo.orders[1].Status)
+	require.Zero(t, users.deducted)
+
+	provider.refundErr = nil
+	order, err := svc.Refund(context.Background(), 1, "")
+	require.NoError(t, err)
+	require.Equal(t, OrderStatusRefunding, statusAtRefund)
+	require.Equal(t, OrderStatusRefunded, order.Status)
+	require.NotNil(t, order.RefundedAt)
+	require.Equal(t, 1, users.deducted)
+	require.InDelta(t, 0.0, users.balance, 1e-9)
+}
@@ -20,6 +20,7 @@ type TokenRefreshService struct {
 	cacheInvalidator TokenCacheInvalidator
 	schedulerCache   SchedulerCache   // 用于同步更新调度器缓存，解决 token 刷新后缓存不一致问题
 	tempUnschedCache TempUnschedCache // 用于清除 Redis 中的临时不可调度缓存
+	healthScorer     *AccountHealthScorer
 
 	stopCh chan struct{}
 	wg     sync.WaitGroup
@@ -72,6 +73,11 @@ func (s *TokenRefreshService) SetSoraAccountRepo(repo SoraAccountRepository) {
 	}
 }
 
+// SetHealthScorer 设置账号健康评分组件，刷新失败会降低账号调度得分
+func (s *TokenRefreshService) SetHealthScorer(scorer *AccountHealthScorer) {
+	s.healthScorer = scorer
+}
+
 // Start 启动后台刷新服务
 func (s *TokenRefreshService) Start() {
 	if !s.cfg.Enabled {
@@ -159,7 +165,9 @@ func (s *TokenRefreshService) processRefresh() {
 			needsRefresh++
 
 			// 执行刷新
-			if err := s.refreshWithRetry(ctx, account, refresher); err != nil {
+			err := s.refreshWithRetry(ctx, account, refresher)
+			s.healthScorer.ReportTokenRefresh(account.ID, err == nil)
+			if err != nil {
 				slog.Warn("token_refresh.account_refresh_failed",
 					"account_id", account.ID,
 					"account_name", account.Name,
@@ -426,6 +426,11 @@ func (s *TotpService) DeleteLoginSession(ctx context.Context, tempToken string)
 	return s.cache.DeleteLoginSession(ctx, tempToken)
 }
 
+// LoginRequiresTotp reports whether logging in as the user must pass the TOTP second factor
+func (s *TotpService) LoginRequiresTotp(ctx context.Context, user *User) bool {
+	return user != nil && user.TotpEnabled && s.settingService != nil && s.settingService.IsTotpEnabled(ctx)
+}
+
 // IsTotpEnabledForUser checks if TOTP is enabled for a specific user
 func (s *TotpService) IsTotpEnabledForUser(ctx context.Context, userID int64) (bool, error) {
 	user, err := s.userRepo.GetByID(ctx, userID)
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/usagestats"
+)
+
+const (
+	UsageExportScopeAdmin = "admin"
+	UsageExportScopeUser  = "user"
+
+	UsageExportFormatCSV   = "csv"
+	UsageExportFormatJSONL = "jsonl"
+
+	UsageExportStatusPending   = "pending"
+	UsageExportStatusRunning   = "running"
+	UsageExportStatusSucceeded = "succeeded"
+	UsageExportStatusFailed    = "failed"
+	UsageExportStatusExpired   = "expired"
+)
+
+var ErrUsageExportJobNotFound = infraerrors.NotFound("USAGE_EXPORT_JOB_NOT_FOUND", "usage export job not found")
+
+// UsageExportFilters 导出过滤条件（与使用记录列表一致），JSON 序列化后存入任务。
+// 时间范围为必填，其他字段 nil 表示未设置。
+type UsageExportFilters struct {
+	StartTime   time.Time `json:"start_time"`
+	EndTime     time.Time `json:"end_time"`
+	UserID      *int64    `json:"user_id,omitempty"`
+	APIKeyID    *int64    `json:"api_key_id,omitempty"`
+	AccountID   *int64    `json:"account_id,omitempty"`
+	GroupID     *int64    `json:"group_id,omitempty"`
+	Model       *string   `json:"model,omitempty"`
+	RequestType *int16    `json:"request_type,omitempty"`
+	Stream      *bool     `json:"stream,omitempty"`
+	BillingType *int8     `json:"billing_type,omitempty"`
+}
+
+// UsageLogFilters 转换为使用记录查询条件
+func (f UsageExportFilters) UsageLogFilters() usagestats.UsageLogFilters {
+	start, end := f.StartTime, f.EndTime
+	out := usagestats.UsageLogFilters{
+		RequestType: f.RequestType,
+		Stream:      f.Stream,
+		BillingType: f.BillingType,
+		StartTime:   &start,
+		EndTime:     &end,
+	}
+	if f.UserID != nil {
+		out.UserID = *f.UserID
+	}
+	if f.APIKeyID != nil {
+		out.APIKeyID = *f.APIKeyID
+	}
+	if f.AccountID != nil {
+		out.AccountID = *f.AccountID
+	}
+	if f.GroupID != nil {
+		out.GroupID = *f.GroupID
+	}
+	if f.Model != nil {
+		out.Model = *f.Model
+	}
+	return out
+}
+
+// UsageExportJob 使用记录异步导出任务
+// 状态包含 pending/running/succeeded/failed/expired
+type UsageExportJob struct {
+	ID         int64
+	Scope      string
+	Format     string
+	Status     string
+	Filters    UsageExportFilters
+	CreatedBy  int64
+	RowCount   int64
+	FileSize   int64
+	Storage    string
+	ObjectKey  string
+	ErrorMsg   *string
+	StartedAt  *time.Time
+	FinishedAt *time.Time
+	ExpiresAt  *time.Time
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+// FileName 下载时使用的文件名
+func (j *UsageExportJob) FileName() string {
+	return "usage_" + j.Filters.StartTime.UTC().Format("20060102") + "_" + j.Filters.EndTime.UTC().Format("20060102") +
+		"_" + strconv.FormatInt(j.ID, 10) + "." + j.Format
+}
+
+// UsageExportJobFilter 任务列表过滤条件
+type UsageExportJobFilter struct {
+	Scope     string
+	CreatedBy *int64
+}
+
+// UsageExportRepository 定义导出任务持久层接口
+type UsageExportRepository interface {
+	CreateJob(ctx context.Context, job *UsageExportJob) error
+	// GetJob 查询任务；不存在返回 ErrUsageExportJobNotFound
+	GetJob(ctx context.Context, id int64) (*UsageExportJob, error)
+	ListJobs(ctx context.Context, params pagination.PaginationParams, filter UsageExportJobFilter) ([]UsageExportJob, *pagination.PaginationResult, error)
+	// ClaimNextPendingJob 抢占下一条可执行任务：优先 pending，running 超过 staleRunningAfterSeconds 的任务允许重新抢占（从头导出）
+	ClaimNextPendingJob(ctx context.Context, staleRunningAfterSeconds int64) (*UsageExportJob, error)
+	UpdateJobProgress(ctx context.Context, id int64, rowCount int64) error
+	MarkJobSucceeded(ctx context.Context, job *UsageExportJob) error
+	MarkJobFailed(ctx context.Context, id int64, rowCount int64, errorMsg string) error
+	// ListExpiredJobs 列出文件已过期但尚未清理的成功任务
+	ListExpiredJobs(ctx context.Context, now time.Time, limit int) ([]UsageExportJob, error)
+	MarkJobExpired(ctx context.Context, id int64) error
+}
+
+// UsageLogExportReader 按 id 游标顺序读取使用记录（keyset 分页，避免大 OFFSET）。
+// 由使用记录仓储实现，导出服务通过类型断言获取。
+type UsageLogExportReader interface {
+	ListForExport(ctx context.Context, filters usagestats.UsageLogFilters, afterID int64, limit int) ([]UsageLog, error)
+}
+
+// usageExportColumn 导出列定义；CSV 与 JSONL 共用同一组列，保证两种格式字段一致。
+type usageExportColumn struct {
+	name      string
+	adminOnly bool
+	value     func(l *UsageLog) any
+}
+
+var usageExportColumns = []usageExportColumn{
+	{name: "id", value: func(l *UsageLog) any { return l.ID }},
+	{name: "created_at", value: func(l *UsageLog) any { return l.CreatedAt.UTC().Format(time.RFC3339) }},
+	{name: "request_id", value: func(l *UsageLog) any { return l.RequestID }},
+	{name: "user_id", value: func(l *UsageLog) any { return l.UserID }},
+	{name: "user_email", value: func(l *UsageLog) any {
+		if l.User == nil {
+			return ""
+		}
+		return l.User.Email
+	}},
+	{name: "api_key_id", value: func(l *UsageLog) any { return l.APIKeyID }},
+	{name: "api_key_name", value: func(l *UsageLog) any {
+		if l.APIKey == nil {
+			return ""
+		}
+		return l.APIKey.Name
+	}},
+	{name: "group_id", value: func(l *UsageLog) any { return l.GroupID }},
+	{name: "group_name", value: func(l *UsageLog) any {
+		if l.Group == nil {
+			return ""
+		}
+		return l.Group.Name
+	}},
+	{name: "account_id", adminOnly: true, value: func(l *UsageLog) any { return l.AccountID }},
+	{name: "account_name", adminOnly: true, value: func(l *UsageLog) any {
+		if l.Account == nil {
+			return ""
+		}
+		return l.Account.Name
+	}},
+	{name: "model", value: func(l *UsageLog) any { return l.Model }},
+	{name: "request_type", value: func(l *UsageLog) any { return l.EffectiveRequestType().String() }},
+	{name: "billing_type", value: func(l *UsageLog) any { return l.BillingType }},
+	{name: "input_tokens", value: func(l *UsageLog) any { return l.InputTokens }},
+	{name: "output_tokens", value: func(l *UsageLog) any { return l.OutputTokens }},
+	{name: "cache_creation_tokens", value: func(l *UsageLog) any { return l.CacheCreationTokens }},
+	{name: "cache_read_tokens", value: func(l *UsageLog) any { return l.CacheReadTokens }},
+	{name: "input_cost", value: func(l *UsageLog) any { return l.InputCost }},
+	{name: "output_cost", value: func(l *UsageLog) any { return l.OutputCost }},
+	{name: "cache_creation_cost", value: func(l *UsageLog) any { return l.CacheCreationCost }},
+	{name: "cache_read_cost", value: func(l *UsageLog) any { return l.CacheReadCost }},
+	{name: "total_cost", value: func(l *UsageLog) any { return l.TotalCost }},
+	{name: "actual_cost", value: func(l *UsageLog) any { return l.ActualCost }},
+	{name: "rate_multiplier", value: func(l *UsageLog) any { return l.RateMultiplier }},
+	{name: "account_rate_multiplier", adminOnly: true, value: func(l *UsageLog) any { return l.AccountRateMultiplier }},
+	{name: "duration_ms", value: func(l *UsageLog) any { return l.DurationMs }},
+	{name: "first_token_ms", value: func(l *UsageLog) any { return l.FirstTokenMs }},
+	{name: "image_count", value: func(l *UsageLog) any { return l.ImageCount }},
+	{name: "image_size", value: func(l *UsageLog) any { return l.ImageSize }},
+	{name: "ip_address", adminOnly: true, value: func(l *UsageLog) any { return l.IPAddress }},
+	{name: "user_agent", value: func(l *UsageLog) any { return l.UserAgent }},
+}
+
+// UsageExportEncoder 将使用记录逐行编码为 CSV 或 JSONL
+type UsageExportEncoder struct {
+	format  string
+	columns []usageExportColumn
+	csv     *csv.Writer
+	json    *json.Encoder
+}
+
+// NewUsageExportEncoder 创建编码器；普通用户（scope=user）不输出账号、IP 等管理员字段。
+func NewUsageExportEncoder(w io.Writer, scope, format string) *UsageExportEncoder {
+	enc := &UsageExportEncoder{format: format}
+	for _, col := range usageExportColumns {
+		if col.adminOnly && scope != UsageExportScopeAdmin {
+			continue
+		}
+		enc.columns = append(enc.columns, col)
+	}
+	if format == UsageExportFormatJSONL {
+		enc.json = json.NewEncoder(w)
+	} else {
+		enc.csv = csv.NewWriter(w)
+	}
+	return enc
+}
+
+// WriteHeader 写入 CSV 表头（JSONL 无表头）
+func (e *UsageExportEncoder) WriteHeader() error {
+	if e.csv == nil {
+		return nil
+	}
+	header := make([]string, 0, len(e.columns))
+	for _, col := range e.columns {
+		header = append(header, col.name)
+	}
+	return e.csv.Write(header)
+}
+
+// Write 编码一条使用记录
+func (e *UsageExportEncoder) Write(l *UsageLog) error {
+	if e.json != nil {
+		row := make(map[string]any, len(e.columns))
+		for _, col := range e.columns {
+			row[col.name] = col.value(l)
+		}
+		return e.json.Encode(row)
+	}
+	record := make([]string, 0, len(e.columns))
+	for _, col := range e.columns {
+		record = append(record, formatUsageExportCell(col.value(l)))
+	}
+	return e.csv.Write(record)
+}
+
+// Flush 刷新缓冲区
+func (e *UsageExportEncoder) Flush() error {
+	if e.csv == nil {
+		return nil
+	}
+	e.csv.Flush()
+	return e.csv.Error()
+}
+
+func formatUsageExportCell(v any) string {
+	switch val := v.(type) {
+	case nil:
+		return ""
+	case string:
+		return sanitizeCSVCell(val)
+	case *string:
+		if val == nil {
+			return ""
+		}
+		return sanitizeCSVCell(*val)
+	case int:
+		return strconv.Itoa(val)
+	case int8:
+		return strconv.Itoa(int(val))
+	case int64:
+		return strconv.FormatInt(val, 10)
+	case *int:
+		if val == nil {
+			return ""
+		}
+		return strconv.Itoa(*val)
+	case *int64:
+		if val == nil {
+			return ""
+		}
+		return strconv.FormatInt(*val, 10)
+	case float64:
+		return strconv.FormatFloat(val, 'f', -1, 64)
+	case *float64:
+		if val == nil {
+			return ""
+		}
+		return strconv.FormatFloat(*val, 'f', -1, 64)
+	default:
+		raw, _ := json.Marshal(val)
+		return string(raw)
+	}
+}
+
+// sanitizeCSVCell 防止表格软件把用户可控内容（如 User-Agent、Key 名称）当作公式执行
+func sanitizeCSVCell(s string) string {
+	if s != "" && strings.ContainsRune("=+-@\t\r", rune(s[0])) {
+		return "'" + s
+	}
+	return s
+}
@@ -0,0 +1,487 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+const (
+	usageExportWorkerName = "usage_export_worker"
+
+	// UsageExportDownloadPathFormat 本地存储文件的签名下载地址（无需登录，凭签名访问）
+	UsageExportDownloadPathFormat = "/api/v1/usage-exports/%d/download"
+
+	usageExportProgressEveryBatches = 10
+	usageExportExpireBatchSize      = 100
+)
+
+var (
+	ErrUsageExportDisabled     = infraerrors.New(http.StatusServiceUnavailable, "USAGE_EXPORT_DISABLED", "usage export is disabled")
+	ErrUsageExportInvalidLink  = infraerrors.Forbidden("USAGE_EXPORT_INVALID_LINK", "download link is invalid or expired")
+	ErrUsageExportNotReady     = infraerrors.Conflict("USAGE_EXPORT_NOT_READY", "usage export job has not finished")
+	ErrUsageExportS3NotEnabled = infraerrors.New(http.StatusServiceUnavailable, "USAGE_EXPORT_S3_NOT_CONFIGURED", "s3 storage is not configured")
+)
+
+// UsageExportService 负责使用记录的流式导出与异步导出任务
+type UsageExportService struct {
+	repo        UsageExportRepository
+	usageRepo   UsageLogRepository
+	s3Storage   *SoraS3Storage
+	timingWheel *TimingWheelService
+	cfg         *config.Config
+	linkSecret  []byte
+
+	running   int32
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	workerCtx    context.Context
+	workerCancel context.CancelFunc
+}
+
+func NewUsageExportService(repo UsageExportRepository, usageRepo UsageLogRepository, s3Storage *SoraS3Storage, timingWheel *TimingWheelService, cfg *config.Config) *UsageExportService {
+	workerCtx, workerCancel := context.WithCancel(context.Background())
+	svc := &UsageExportService{
+		repo:         repo,
+		usageRepo:    usageRepo,
+		s3Storage:    s3Storage,
+		timingWheel:  timingWheel,
+		cfg:          cfg,
+		workerCtx:    workerCtx,
+		workerCancel: workerCancel,
+	}
+	if cfg != nil {
+		mac := hmac.New(sha256.New, []byte(cfg.JWT.Secret))
+		mac.Write([]byte("sub2api-usage-export"))
+		svc.linkSecret = mac.Sum(nil)
+	}
+	return svc
+}
+
+func (s *UsageExportService) Start() {
+	if s == nil {
+		return
+	}
+	if !s.enabled() {
+		logger.LegacyPrintf("service.usage_export", "[UsageExport] not started (disabled)")
+		return
+	}
+	if s.repo == nil || s.timingWheel == nil {
+		logger.LegacyPrintf("service.usage_export", "[UsageExport] not started (missing deps)")
+		return
+	}
+	interval := time.Duration(s.cfg.UsageExport.WorkerIntervalSeconds) * time.Second
+	s.startOnce.Do(func() {
+		s.timingWheel.ScheduleRecurring(usageExportWorkerName, interval, s.runOnce)
+		logger.LegacyPrintf("service.usage_export", "[UsageExport] started (interval=%s storage=%s batch_size=%d task_timeout=%s)", interval, s.cfg.UsageExport.Storage, s.batchSize(), s.taskTimeout())
+	})
+}
+
+func (s *UsageExportService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.workerCancel != nil {
+			s.workerCancel()
+		}
+		if s.timingWheel != nil {
+			s.timingWheel.Cancel(usageExportWorkerName)
+		}
+		logger.LegacyPrintf("service.usage_export", "[UsageExport] stopped")
+	})
+}
+
+func (s *UsageExportService) enabled() bool {
+	return s != nil && s.cfg != nil && s.cfg.UsageExport.Enabled
+}
+
+// Stream 同步流式导出：按 id 游标分批读取并逐批写出，内存占用与总行数无关。
+// 仅允许 max_sync_range_days 以内的时间范围，更大范围请创建异步任务。
+func (s *UsageExportService) Stream(ctx context.Context, w io.Writer, scope, format string, filters UsageExportFilters) (int64, error) {
+	if err := s.ValidateSyncExport(format, filters); err != nil {
+		return 0, err
+	}
+	return s.writeAll(ctx, w, scope, format, filters, nil)
+}
+
+// ValidateSyncExport 校验同步导出参数；调用方应在写出响应头之前调用，以便返回规范的错误响应。
+func (s *UsageExportService) ValidateSyncExport(format string, filters UsageExportFilters) error {
+	if !s.enabled() {
+		return ErrUsageExportDisabled
+	}
+	if err := validateUsageExportFormat(format); err != nil {
+		return err
+	}
+	return validateUsageExportRange(filters, s.cfg.UsageExport.MaxSyncRangeDays, "USAGE_EXPORT_RANGE_TOO_LARGE_FOR_SYNC", "use an async export job for ranges over %d days")
+}
+
+// writeAll 写出全部匹配记录；每批写完后刷新输出（若支持），并回调 onBatch 上报进度。
+func (s *UsageExportService) writeAll(ctx context.Context, w io.Writer, scope, format string, filters UsageExportFilters, onBatch func(rows int64)) (int64, error) {
+	reader, ok := s.usageRepo.(UsageLogExportReader)
+	if !ok {
+		return 0, fmt.Errorf("usage log repository does not support export")
+	}
+	enc := NewUsageExportEncoder(w, scope, format)
+	if err := enc.WriteHeader(); err != nil {
+		return 0, err
+	}
+
+	logFilters := filters.UsageLogFilters()
+	batchSize := s.batchSize()
+	var afterID, total int64
+	for {
+		if err := ctx.Err(); err != nil {
+			return total, err
+		}
+		logs, err := reader.ListForExport(ctx, logFilters, afterID, batchSize)
+		if err != nil {
+			return total, err
+		}
+		for i := range logs {
+			if err := enc.Write(&logs[i]); err != nil {
+				return total, err
+			}
+		}
+		if err := enc.Flush(); err != nil {
+			return total, err
+		}
+		if f, ok := w.(interface{ Flush() }); ok {
+			f.Flush()
+		}
+		total += int64(len(logs))
+		if onBatch != nil {
+			onBatch(total)
+		}
+		if len(logs) < batchSize {
+			return total, nil
+		}
+		afterID = logs[len(logs)-1].ID
+	}
+}
+
+// CreateJob 创建异步导出任务
+func (s *UsageExportService) CreateJob(ctx context.Context, scope, format string, filters UsageExportFilters, createdBy int64) (*UsageExportJob, error) {
+	if !s.enabled() {
+		return nil, ErrUsageExportDisabled
+	}
+	if err := validateUsageExportFormat(format); err != nil {
+		return nil, err
+	}
+	if err := validateUsageExportRange(filters, s.cfg.UsageExport.MaxRangeDays, "USAGE_EXPORT_RANGE_TOO_LARGE", "date range exceeds %d days"); err != nil {
+		return nil, err
+	}
+	if s.cfg.UsageExport.Storage == config.UsageExportStorageS3 && (s.s3Storage == nil || !s.s3Storage.Enabled(ctx)) {
+		return nil, ErrUsageExportS3NotEnabled
+	}
+
+	if scope == UsageExportScopeUser {
+		// 普通用户只能导出自己的记录
+		filters.UserID = &createdBy
+		filters.AccountID = nil
+	}
+
+	job := &UsageExportJob{
+		Scope:     scope,
+		Format:    format,
+		Status:    UsageExportStatusPending,
+		Filters:   filters,
+		CreatedBy: createdBy,
+	}
+	if err := s.repo.CreateJob(ctx, job); err != nil {
+		return nil, fmt.Errorf("create usage export job: %w", err)
+	}
+	logger.LegacyPrintf("service.usage_export", "[UsageExport] job created: job=%d scope=%s format=%s operator=%d", job.ID, scope, format, createdBy)
+	go s.runOnce()
+	return job, nil
+}
+
+// ListJobs 列出任务；普通用户只能看到自己创建的任务，管理员看到全部管理员导出任务。
+func (s *UsageExportService) ListJobs(ctx context.Context, params pagination.PaginationParams, scope string, userID int64) ([]UsageExportJob, *pagination.PaginationResult, error) {
+	filter := UsageExportJobFilter{Scope: scope}
+	if scope == UsageExportScopeUser {
+		filter.CreatedBy = &userID
+	}
+	return s.repo.ListJobs(ctx, params, filter)
+}
+
+// GetJob 查询任务并校验访问权限
+func (s *UsageExportService) GetJob(ctx context.Context, id int64, scope string, userID int64) (*UsageExportJob, error) {
+	job, err := s.repo.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if job.Scope != scope || (scope == UsageExportScopeUser && job.CreatedBy != userID) {
+		return nil, ErrUsageExportJobNotFound
+	}
+	return job, nil
+}
+
+// DownloadURL 生成下载链接：S3 使用预签名 URL，本地存储使用带 HMAC 签名的下载地址。
+func (s *UsageExportService) DownloadURL(ctx context.Context, job *UsageExportJob) (string, *time.Time, error) {
+	if job.Status != UsageExportStatusSucceeded {
+		return "", nil, ErrUsageExportNotReady
+	}
+	ttl := time.Duration(s.cfg.UsageExport.LinkTTLSeconds) * time.Second
+	expiresAt := time.Now().Add(ttl)
+	if job.ExpiresAt != nil && job.ExpiresAt.Before(expiresAt) {
+		expiresAt = *job.ExpiresAt
+		ttl = time.Until(expiresAt)
+	}
+
+	if job.Storage == config.UsageExportStorageS3 {
+		if s.s3Storage == nil {
+			return "", nil, ErrUsageExportS3NotEnabled
+		}
+		link, err := s.s3Storage.GeneratePresignedURL(ctx, job.ObjectKey, ttl)
+		if err != nil {
+			return "", nil, err
+		}
+		return link, &expiresAt, nil
+	}
+
+	exp := strconv.FormatInt(expiresAt.Unix(), 10)
+	q := url.Values{}
+	q.Set("expires", exp)
+	q.Set("sig", s.linkSignature(job.ID, exp))
+	return fmt.Sprintf(UsageExportDownloadPathFormat, job.ID) + "?" + q.Encode(), &expiresAt, nil
+}
+
+// OpenLocalDownload 校验签名下载地址，返回本地文件路径与下载文件名。
+func (s *UsageExportService) OpenLocalDownload(ctx context.Context, id int64, expires, sig string) (string, string, error) {
+	if !s.enabled() || s.linkSecret == nil {
+		return "", "", ErrUsageExportInvalidLink
+	}
+	if !hmac.Equal([]byte(sig), []byte(s.linkSignature(id, expires))) {
+		return "", "", ErrUsageExportInvalidLink
+	}
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || time.Now().Unix() > expiresAt {
+		return "", "", ErrUsageExportInvalidLink
+	}
+	job, err := s.repo.GetJob(ctx, id)
+	if err != nil {
+		if errors.Is(err, ErrUsageExportJobNotFound) {
+			return "", "", ErrUsageExportInvalidLink
+		}
+		return "", "", err
+	}
+	if job.Status != UsageExportStatusSucceeded || job.Storage != config.UsageExportStorageLocal {
+		return "", "", ErrUsageExportInvalidLink
+	}
+	return s.localPath(job.ObjectKey), job.FileName(), nil
+}
+
+func (s *UsageExportService) linkSignature(id int64, expires string) string {
+	mac := hmac.New(sha256.New, s.linkSecret)
+	mac.Write([]byte(strconv.FormatInt(id, 10)))
+	mac.Write([]byte{0})
+	mac.Write([]byte(expires))
+	return base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func (s *UsageExportService) runOnce() {
+	if s == nil {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&s.running, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&s.running, 0)
+
+	parent := context.Background()
+	if s.workerCtx != nil {
+		parent = s.workerCtx
+	}
+	ctx, cancel := context.WithTimeout(parent, s.taskTimeout())
+	defer cancel()
+
+	s.expireFiles(ctx)
+
+	job, err := s.repo.ClaimNextPendingJob(ctx, int64(s.taskTimeout().Seconds()))
+	if err != nil {
+		logger.LegacyPrintf("service.usage_export", "[UsageExport] claim pending job failed: %v", err)
+		return
+	}
+	if job == nil {
+		slog.Debug("[UsageExport] run_once done: no_job=true")
+		return
+	}
+	logger.LegacyPrintf("service.usage_export", "[UsageExport] job claimed: job=%d scope=%s format=%s created_by=%d", job.ID, job.Scope, job.Format, job.CreatedBy)
+	s.executeJob(ctx, job)
+}
+
+func (s *UsageExportService) executeJob(ctx context.Context, job *UsageExportJob) {
+	start := time.Now()
+	relPath := filepath.Join("usage", start.UTC().Format("2006/01/02"), job.FileName())
+	localPath := s.localPath(relPath)
+	if err := os.MkdirAll(filepath.Dir(localPath), 0o755); err != nil {
+		s.markJobFailed(job.ID, 0, err)
+		return
+	}
+	f, err := os.Create(localPath)
+	if err != nil {
+		s.markJobFailed(job.ID, 0, err)
+		return
+	}
+
+	batches := 0
+	rows, writeErr := s.writeAll(ctx, f, job.Scope, job.Format, job.Filters, func(rows int64) {
+		batches++
+		if batches%usageExportProgressEveryBatches != 0 {
+			return
+		}
+		updateCtx, cancel := context.WithTimeout(context.Background(), 3*time.Second)
+		defer cancel()
+		if err := s.repo.UpdateJobProgress(updateCtx, job.ID, rows); err != nil {
+			logger.LegacyPrintf("service.usage_export", "[UsageExport] job progress update failed: job=%d rows=%d err=%v", job.ID, rows, err)
+		}
+	})
+	closeErr := f.Close()
+	if writeErr == nil {
+		writeErr = closeErr
+	}
+	if writeErr != nil {
+		_ = os.Remove(localPath)
+		if errors.Is(writeErr, context.Canceled) || errors.Is(writeErr, context.DeadlineExceeded) {
+			// 服务停止/超时：保持 running，由 stale reclaim 重新导出。
+			logger.LegacyPrintf("service.usage_export", "[UsageExport] job interrupted: job=%d rows=%d err=%v", job.ID, rows, writeErr)
+			return
+		}
+		s.markJobFailed(job.ID, rows, writeErr)
+		return
+	}
+
+	info, err := os.Stat(localPath)
+	if err != nil {
+		s.markJobFailed(job.ID, rows, err)
+		return
+	}
+	job.RowCount = rows
+	job.FileSize = info.Size()
+	job.Storage = config.UsageExportStorageLocal
+	job.ObjectKey = filepath.ToSlash(relPath)
+
+	if s.cfg.UsageExport.Storage == config.UsageExportStorageS3 {
+		contentType := "text/csv"
+		if job.Format == UsageExportFormatJSONL {
+			contentType = "application/x-ndjson"
+		}
+		key, err := s.s3Storage.UploadFile(ctx, "usage-exports/"+job.ObjectKey, localPath, contentType)
+		_ = os.Remove(localPath)
+		if err != nil {
+			s.markJobFailed(job.ID, rows, err)
+			return
+		}
+		job.Storage = config.UsageExportStorageS3
+		job.ObjectKey = key
+	}
+
+	expiresAt := time.Now().Add(time.Duration(s.cfg.UsageExport.RetentionHours) * time.Hour)
+	job.ExpiresAt = &expiresAt
+	updateCtx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.repo.MarkJobSucceeded(updateCtx, job); err != nil {
+		logger.LegacyPrintf("service.usage_export", "[UsageExport] update job succeeded failed: job=%d err=%v", job.ID, err)
+		return
+	}
+	logger.LegacyPrintf("service.usage_export", "[UsageExport] job succeeded: job=%d rows=%d size=%d storage=%s duration=%s", job.ID, rows, job.FileSize, job.Storage, time.Since(start))
+}
+
+// expireFiles 删除过期导出文件并将任务标记为 expired
+func (s *UsageExportService) expireFiles(ctx context.Context) {
+	jobs, err := s.repo.ListExpiredJobs(ctx, time.Now(), usageExportExpireBatchSize)
+	if err != nil {
+		logger.LegacyPrintf("service.usage_export", "[UsageExport] list expired jobs failed: %v", err)
+		return
+	}
+	for i := range jobs {
+		job := &jobs[i]
+		var removeErr error
+		if job.Storage == config.UsageExportStorageS3 {
+			if s.s3Storage != nil {
+				removeErr = s.s3Storage.DeleteObjects(ctx, []string{job.ObjectKey})
+			}
+		} else if err := os.Remove(s.localPath(job.ObjectKey)); err != nil && !os.IsNotExist(err) {
+			removeErr = err
+		}
+		if removeErr != nil {
+			logger.LegacyPrintf("service.usage_export", "[UsageExport] remove expired file failed: job=%d key=%s err=%v", job.ID, job.ObjectKey, removeErr)
+			continue
+		}
+		if err := s.repo.MarkJobExpired(ctx, job.ID); err != nil {
+			logger.LegacyPrintf("service.usage_export", "[UsageExport] mark job expired failed: job=%d err=%v", job.ID, err)
+		}
+	}
+}
+
+func (s *UsageExportService) markJobFailed(jobID int64, rows int64, err error) {
+	msg := strings.TrimSpace(err.Error())
+	if len(msg) > 500 {
+		msg = msg[:500]
+	}
+	logger.LegacyPrintf("service.usage_export", "[UsageExport] job failed: job=%d rows=%d err=%s", jobID, rows, msg)
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if updateErr := s.repo.MarkJobFailed(ctx, jobID, rows, msg); updateErr != nil {
+		logger.LegacyPrintf("service.usage_export", "[UsageExport] update job failed failed: job=%d err=%v", jobID, updateErr)
+	}
+}
+
+func (s *UsageExportService) localPath(relPath string) string {
+	return filepath.Join(s.cfg.UsageExport.LocalDir, filepath.FromSlash(relPath))
+}
+
+func (s *UsageExportService) batchSize() int {
+	if s == nil || s.cfg == nil || s.cfg.UsageExport.BatchSize <= 0 {
+		return 5000
+	}
+	return s.cfg.UsageExport.BatchSize
+}
+
+func (s *UsageExportService) taskTimeout() time.Duration {
+	if s == nil || s.cfg == nil || s.cfg.UsageExport.TaskTimeoutSeconds <= 0 {
+		return time.Hour
+	}
+	return time.Duration(s.cfg.UsageExport.TaskTimeoutSeconds) * time.Second
+}
+
+func validateUsageExportFormat(format string) error {
+	if format != UsageExportFormatCSV && format != UsageExportFormatJSONL {
+		return infraerrors.BadRequest("USAGE_EXPORT_INVALID_FORMAT", "format must be csv or jsonl")
+	}
+	return nil
+}
+
+func validateUsageExportRange(filters UsageExportFilters, maxDays int, reason, msgFormat string) error {
+	if filters.StartTime.IsZero() || filters.EndTime.IsZero() {
+		return infraerrors.BadRequest("USAGE_EXPORT_MISSING_RANGE", "start_date and end_date are required")
+	}
+	if filters.EndTime.Before(filters.StartTime) {
+		return infraerrors.BadRequest("USAGE_EXPORT_INVALID_RANGE", "end_date must be after start_date")
+	}
+	if maxDays > 0 && filters.EndTime.Sub(filters.StartTime) > time.Duration(maxDays)*24*time.Hour {
+		return infraerrors.BadRequest(reason, fmt.Sprintf(msgFormat, maxDays))
+	}
+	return nil
+}
@@ -0,0 +1,303 @@
+//go:build unit
+
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/url"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/usagestats"
+	"github.com/stretchr/testify/require"
+)
+
+type usageExportLogRepoStub struct {
+	UsageLogRepository
+	logs     []UsageLog
+	afterIDs []int64
+}
+
+func (r *usageExportLogRepoStub) ListForExport(_ context.Context, filters usagestats.UsageLogFilters, afterID int64, limit int) ([]UsageLog, error) {
+	r.afterIDs = append(r.afterIDs, afterID)
+	out := make([]UsageLog, 0, limit)
+	for _, l := range r.logs {
+		if l.ID <= afterID || (filters.UserID > 0 && l.UserID != filters.UserID) {
+			continue
+		}
+		out = append(out, l)
+		if len(out) == limit {
+			break
+		}
+	}
+	return out, nil
+}
+
+type usageExportJobRepoStub struct {
+	mu     sync.Mutex
+	jobs   map[int64]*UsageExportJob
+	nextID int64
+}
+
+func newUsageExportJobRepoStub() *usageExportJobRepoStub {
+	return &usageExportJobRepoStub{jobs: map[int64]*UsageExportJob{}}
+}
+
+func (r *usageExportJobRepoStub) CreateJob(_ context.Context, job *UsageExportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.nextID++
+	job.ID = r.nextID
+	job.CreatedAt = time.Now()
+	cp := *job
+	r.jobs[job.ID] = &cp
+	return nil
+}
+
+func (r *usageExportJobRepoStub) GetJob(_ context.Context, id int64) (*UsageExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	job, ok := r.jobs[id]
+	if !ok {
+		return nil, ErrUsageExportJobNotFound
+	}
+	cp := *job
+	return &cp, nil
+}
+
+func (r *usageExportJobRepoStub) ListJobs(context.Context, pagination.PaginationParams, UsageExportJobFilter) ([]UsageExportJob, *pagination.PaginationResult, error) {
+	panic("unexpected ListJobs call")
+}
+
+func (r *usageExportJobRepoStub) ClaimNextPendingJob(context.Context, int64) (*UsageExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for id := int64(1); id <= r.nextID; id++ {
+		if job, ok := r.jobs[id]; ok && job.Status == UsageExportStatusPending {
+			job.Status = UsageExportStatusRunning
+			cp := *job
+			return &cp, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *usageExportJobRepoStub) UpdateJobProgress(_ context.Context, id int64, rowCount int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[id].RowCount = rowCount
+	return nil
+}
+
+func (r *usageExportJobRepoStub) MarkJobSucceeded(_ context.Context, job *UsageExportJob) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	cp := *job
+	cp.Status = UsageExportStatusSucceeded
+	r.jobs[job.ID] = &cp
+	return nil
+}
+
+func (r *usageExportJobRepoStub) MarkJobFailed(_ context.Context, id int64, rowCount int64, errorMsg string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[id].Status = UsageExportStatusFailed
+	r.jobs[id].RowCount = rowCount
+	r.jobs[id].ErrorMsg = &errorMsg
+	return nil
+}
+
+func (r *usageExportJobRepoStub) ListExpiredJobs(_ context.Context, now time.Time, _ int) ([]UsageExportJob, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []UsageExportJob
+	for _, job := range r.jobs {
+		if job.Status == UsageExportStatusSucceeded && job.ExpiresAt != nil && !job.ExpiresAt.After(now) {
+			out = append(out, *job)
+		}
+	}
+	return out, nil
+}
+
+func (r *usageExportJobRepoStub) MarkJobExpired(_ context.Context, id int64) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.jobs[id].Status = UsageExportStatusExpired
+	return nil
+}
+
+func newUsageExportTestService(t *testing.T, logs []UsageLog) (*UsageExportService, *usageExportJobRepoStub, *usageExportLogRepoStub) {
+	t.Helper()
+	cfg := &config.Config{}
+	cfg.JWT.Secret = "test-secret"
+	cfg.UsageExport = config.UsageExportConfig{
+		Enabled:          true,
+		Storage:          config.UsageExportStorageLocal,
+		LocalDir:         t.TempDir(),
+		MaxSyncRangeDays: 31,
+		MaxRangeDays:     366,
+		BatchSize:        2,
+		LinkTTLSeconds:   600,
+		RetentionHours:   72,
+	}
+	jobs := newUsageExportJobRepoStub()
+	usage := &usageExportLogRepoStub{logs: logs}
+	return NewUsageExportService(jobs, usage, nil, nil, cfg), jobs, usage
+}
+
+func usageExportTestLogs() []UsageLog {
+	created := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	ua := "=HYPERLINK(\"http://evil\")"
+	ip := "10.0.0.1"
+	logs := make([]UsageLog, 0, 5)
+	for i := int64(1); i <= 5; i++ {
+		logs = append(logs, UsageLog{
+			ID:          i,
+			UserID:      7,
+			APIKeyID:    3,
+			AccountID:   9,
+			Model:       "claude-sonnet-4",
+			InputTokens: int(i * 10),
+			TotalCost:   0.0015,
+			ActualCost:  0.0015,
+			UserAgent:   &ua,
+			IPAddress:   &ip,
+			CreatedAt:   created.Add(time.Duration(i) * time.Minute),
+			User:        &User{ID: 7, Email: "finance@example.com"},
+			Account:     &Account{ID: 9, Name: "upstream-1"},
+		})
+	}
+	return logs
+}
+
+func usageExportMarchFilters(days int) UsageExportFilters {
+	start := time.Date(2026, 3, 1, 0, 0, 0, 0, time.UTC)
+	return UsageExportFilters{StartTime: start, EndTime: start.Add(time.Duration(days)*24*time.Hour - time.Nanosecond)}
+}
+
+func TestUsageExportService_StreamCSVUsesKeysetBatches(t *testing.T) {
+	svc, _, usage := newUsageExportTestService(t, usageExportTestLogs())
+
+	var buf bytes.Buffer
+	rows, err := svc.Stream(context.Background(), &buf, UsageExportScopeUser, UsageExportFormatCSV, usageExportMarchFilters(31))
+	require.NoError(t, err)
+	require.Equal(t, int64(5), rows)
+	require.Equal(t, []int64{0, 2, 4}, usage.afterIDs)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 6)
+	require.True(t, strings.HasPrefix(lines[0], "id,created_at,request_id,user_id,user_email,"))
+	// 普通用户导出不包含管理员字段
+	require.NotContains(t, lines[0], "account_name")
+	require.NotContains(t, lines[0], "ip_address")
+	require.NotContains(t, lines[0], "account_rate_multiplier")
+	require.Contains(t, lines[1], "finance@example.com")
+	// 公式注入防护
+	require.Contains(t, lines[1], `'=HYPERLINK`)
+}
+
+func TestUsageExportService_StreamJSONLAdminColumns(t *testing.T) {
+	svc, _, _ := newUsageExportTestService(t, usageExportTestLogs())
+
+	var buf bytes.Buffer
+	_, err := svc.Stream(context.Background(), &buf, UsageExportScopeAdmin, UsageExportFormatJSONL, usageExportMarchFilters(1))
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSpace(buf.String()), "\n")
+	require.Len(t, lines, 5)
+	var row map[string]any
+	require.NoError(t, json.Unmarshal([]byte(lines[0]), &row))
+	require.Equal(t, float64(1), row["id"])
+	require.Equal(t, "upstream-1", row["account_name"])
+	require.Equal(t, "10.0.0.1", row["ip_address"])
+	require.Equal(t, 0.0015, row["actual_cost"])
+	// JSONL 原样输出，不做 CSV 公式转义
+	require.Equal(t, `=HYPERLINK("http://evil")`, row["user_agent"])
+}
+
+func TestUsageExportService_RangeLimits(t *testing.T) {
+	svc, _, _ := newUsageExportTestService(t, nil)
+
+	err := svc.ValidateSyncExport(UsageExportFormatCSV, usageExportMarchFilters(32))
+	require.Error(t, err)
+	require.Equal(t, "USAGE_EXPORT_RANGE_TOO_LARGE_FOR_SYNC", infraerrors.Reason(err))
+
+	err = svc.ValidateSyncExport("xlsx", usageExportMarchFilters(1))
+	require.Equal(t, "USAGE_EXPORT_INVALID_FORMAT", infraerrors.Reason(err))
+
+	err = svc.ValidateSyncExport(UsageExportFormatCSV, UsageExportFilters{})
+	require.Equal(t, "USAGE_EXPORT_MISSING_RANGE", infraerrors.Reason(err))
+
+	_, err = svc.CreateJob(context.Background(), UsageExportScopeAdmin, UsageExportFormatCSV, usageExportMarchFilters(400), 1)
+	require.Equal(t, "USAGE_EXPORT_RANGE_TOO_LARGE", infraerrors.Reason(err))
+}
+
+func TestUsageExportService_LocalJobSignedDownloadAndExpiry(t *testing.T) {
+	svc, jobs, _ := newUsageExportTestService(t, usageExportTestLogs())
+	ctx := context.Background()
+
+	job := &UsageExportJob{
+		Scope:     UsageExportScopeUser,
+		Format:    UsageExportFormatCSV,
+		Status:    UsageExportStatusPending,
+		Filters:   usageExportMarchFilters(90),
+		CreatedBy: 7,
+	}
+	require.NoError(t, jobs.CreateJob(ctx, job))
+
+	svc.runOnce()
+
+	done, err := svc.GetJob(ctx, job.ID, UsageExportScopeUser, 7)
+	require.NoError(t, err)
+	require.Equal(t, UsageExportStatusSucceeded, done.Status)
+	require.Equal(t, int64(5), done.RowCount)
+	require.Equal(t, config.UsageExportStorageLocal, done.Storage)
+	require.NotNil(t, done.ExpiresAt)
+
+	// 其他用户 / 管理员范围均不可见
+	_, err = svc.GetJob(ctx, job.ID, UsageExportScopeUser, 8)
+	require.ErrorIs(t, err, ErrUsageExportJobNotFound)
+	_, err = svc.GetJob(ctx, job.ID, UsageExportScopeAdmin, 0)
+	require.ErrorIs(t, err, ErrUsageExportJobNotFound)
+
+	link, linkExpiresAt, err := svc.DownloadURL(ctx, done)
+	require.NoError(t, err)
+	require.NotNil(t, linkExpiresAt)
+	u, err := url.Parse(link)
+	require.NoError(t, err)
+	require.Equal(t, "/api/v1/usage-exports/"+strconv.FormatInt(job.ID, 10)+"/download", u.Path)
+
+	path, filename, err := svc.OpenLocalDownload(ctx, job.ID, u.Query().Get("expires"), u.Query().Get("sig"))
+	require.NoError(t, err)
+	require.Equal(t, done.FileName(), filename)
+	content, err := os.ReadFile(path)
+	require.NoError(t, err)
+	require.Len(t, strings.Split(strings.TrimSpace(string(content)), "\n"), 6)
+
+	// 篡改签名、修改过期时间、签名用于其他任务均失败
+	_, _, err = svc.OpenLocalDownload(ctx, job.ID, u.Query().Get("expires"), "bogus")
+	require.ErrorIs(t, err, ErrUsageExportInvalidLink)
+	_, _, err = svc.OpenLocalDownload(ctx, job.ID, strconv.FormatInt(time.Now().Add(time.Hour*48).Unix(), 10), u.Query().Get("sig"))
+	require.ErrorIs(t, err, ErrUsageExportInvalidLink)
+	_, _, err = svc.OpenLocalDownload(ctx, job.ID+1, u.Query().Get("expires"), u.Query().Get("sig"))
+	require.ErrorIs(t, err, ErrUsageExportInvalidLink)
+
+	// 到期后删除文件并标记 expired
+	past := time.Now().Add(-time.Minute)
+	jobs.jobs[job.ID].ExpiresAt = &past
+	svc.expireFiles(ctx)
+	require.Equal(t, UsageExportStatusExpired, jobs.jobs[job.ID].Status)
+	_, err = os.Stat(path)
+	require.True(t, os.IsNotExist(err))
+	_, _, err = svc.OpenLocalDownload(ctx, job.ID, u.Query().Get("expires"), u.Query().Get("sig"))
+	require.ErrorIs(t, err, ErrUsageExportInvalidLink)
+}
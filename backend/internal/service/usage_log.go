@@ -7,8 +7,9 @@ import (
 )
 
 const (
-	BillingTypeBalance      int8 = 0 // 钱包余额
-	BillingTypeSubscription int8 = 1 // 订阅套餐
+	BillingTypeBalance       int8 = 0 // 钱包余额
+	BillingTypeSubscription  int8 = 1 // 订阅套餐
+	BillingTypeResponseCache int8 = 2 // 响应缓存命中
 )
 
 type RequestType int16
@@ -0,0 +1,35 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+var (
+	ErrUserIdentityNotFound = infraerrors.NotFound("SSO_IDENTITY_NOT_FOUND", "linked identity not found")
+	ErrUserIdentityExists   = infraerrors.Conflict("SSO_IDENTITY_ALREADY_LINKED", "this identity is already linked to an account")
+)
+
+// UserIdentity 用户绑定的第三方 SSO 身份
+type UserIdentity struct {
+	ID          int64
+	UserID      int64
+	Provider    string // sso.providers[].id
+	Subject     string // 提供方侧的稳定用户标识
+	Email       string // 绑定时提供方返回的邮箱，仅用于展示
+	CreatedAt   time.Time
+	LastLoginAt *time.Time
+}
+
+// UserIdentityRepository SSO 身份绑定持久化
+type UserIdentityRepository interface {
+	// Create 创建绑定；(provider, subject) 已存在时返回 ErrUserIdentityExists
+	Create(ctx context.Context, identity *UserIdentity) error
+	GetByProviderSubject(ctx context.Context, provider, subject string) (*UserIdentity, error)
+	ListByUser(ctx context.Context, userID int64) ([]UserIdentity, error)
+	// Delete 删除指定用户的绑定；不存在或不属于该用户时返回 ErrUserIdentityNotFound
+	Delete(ctx context.Context, userID, id int64) error
+	TouchLastLogin(ctx context.Context, id int64, at time.Time) error
+}
@@ -0,0 +1,78 @@
+package service
+
+import (
+	"context"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+)
+
+// 通知邮件默认阈值（用户首次打开设置时展示）
+const (
+	userNotificationDefaultQuotaPercent        = 90
+	userNotificationDefaultSubscriptionPercent = 90
+	userNotificationDefaultExpiryDays          = 3
+)
+
+// 通知类型（同时作为去重 key 的前缀）
+const (
+	UserNotificationBalanceLow           = "balance_low"
+	UserNotificationAPIKeyQuota          = "api_key_quota"
+	UserNotificationSubscriptionUsage    = "subscription_usage"
+	UserNotificationSubscriptionExpiring = "subscription_expiring"
+)
+
+var (
+	ErrUserNotificationSettingsNotFound = infraerrors.NotFound("NOTIFICATION_SETTINGS_NOT_FOUND", "notification settings not found")
+	ErrUserNotificationInvalidToken     = infraerrors.NotFound("NOTIFICATION_INVALID_TOKEN", "invalid or expired unsubscribe link")
+	ErrUserNotificationInvalidSettings  = infraerrors.BadRequest("NOTIFICATION_INVALID_SETTINGS", "invalid notification settings")
+)
+
+// UserNotificationSettings 用户通知邮件设置；各阈值为 0 表示不提醒该项
+type UserNotificationSettings struct {
+	UserID                   int64
+	Enabled                  bool
+	BalanceThreshold         float64 // 余额低于该值（USD）时提醒
+	APIKeyQuotaPercent       int     // API Key 额度用量达到该百分比时提醒
+	SubscriptionUsagePercent int     // 订阅日/周/月用量达到分组限额该百分比时提醒
+	ExpiryNoticeDays         int     // 订阅到期前 N 天提醒
+	UnsubscribeToken         string
+	CreatedAt                time.Time
+	UpdatedAt                time.Time
+}
+
+// DefaultUserNotificationSettings 尚未保存过设置的用户使用的默认值（未开启）
+func DefaultUserNotificationSettings(userID int64) *UserNotificationSettings {
+	return &UserNotificationSettings{
+		UserID:                   userID,
+		APIKeyQuotaPercent:       userNotificationDefaultQuotaPercent,
+		SubscriptionUsagePercent: userNotificationDefaultSubscriptionPercent,
+		ExpiryNoticeDays:         userNotificationDefaultExpiryDays,
+	}
+}
+
+// UpdateUserNotificationSettingsInput 更新通知设置参数（nil 表示不修改）
+type UpdateUserNotificationSettingsInput struct {
+	Enabled                  *bool
+	BalanceThreshold         *float64
+	APIKeyQuotaPercent       *int
+	SubscriptionUsagePercent *int
+	ExpiryNoticeDays         *int
+}
+
+// UserNotificationRepository 通知设置与去重状态持久层
+type UserNotificationRepository interface {
+	// GetSettings 未保存过设置返回 ErrUserNotificationSettingsNotFound
+	GetSettings(ctx context.Context, userID int64) (*UserNotificationSettings, error)
+	// UpsertSettings 保存设置；已存在的退订令牌保持不变
+	UpsertSettings(ctx context.Context, settings *UserNotificationSettings) error
+	// ListEnabledSettings 列出已开启通知的用户设置
+	ListEnabledSettings(ctx context.Context) ([]UserNotificationSettings, error)
+	// DisableByToken 按退订令牌关闭通知；令牌不存在返回 ErrUserNotificationInvalidToken
+	DisableByToken(ctx context.Context, token string) error
+
+	ListStateKeys(ctx context.Context, userID int64) ([]string, error)
+	// AddStateKey 记录已发送的提醒；已存在返回 false
+	AddStateKey(ctx context.Context, userID int64, key string) (bool, error)
+	DeleteStateKeys(ctx context.Context, userID int64, keys []string) error
+}
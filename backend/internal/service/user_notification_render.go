@@ -0,0 +1,95 @@
+package service
+
+import (
+	"bytes"
+	"fmt"
+	"html/template"
+)
+
+// userNotificationEmailTemplate 通知邮件模板：内联样式，适配邮件客户端
+var userNotificationEmailTemplate = template.Must(template.New("notification").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<title>{{.SiteName}}</title>
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; color: #1f2937; margin: 0; padding: 24px; background: #f9fafb;">
+<div style="max-width: 600px; margin: 0 auto; background: #fff; border: 1px solid #e5e7eb; border-radius: 8px; padding: 24px;">
+  <h1 style="font-size: 20px; margin: 0 0 16px;">{{.SiteName}}</h1>
+  <p style="font-size: 14px; margin: 0 0 16px;">Hi{{if .Username}} {{.Username}}{{end}}, the following account alerts were triggered:</p>
+  {{range .Items}}
+  <div style="border-left: 3px solid #f59e0b; padding: 8px 12px; margin: 0 0 12px; background: #fffbeb;">
+    <div style="font-weight: 600; font-size: 14px;">{{.Title}}</div>
+    <div style="font-size: 13px; color: #4b5563; margin-top: 4px;">{{.Detail}}</div>
+  </div>
+  {{end}}
+  <p style="font-size: 12px; color: #6b7280; margin: 24px 0 0;">
+    Each alert is sent once per period until the condition clears.
+    {{if .SettingsURL}}Change thresholds in your <a href="{{.SettingsURL}}" style="color: #2563eb;">profile settings</a>.{{else}}Change thresholds in your profile settings.{{end}}
+    {{if .UnsubscribeURL}}<br><a href="{{.UnsubscribeURL}}" style="color: #6b7280;">Unsubscribe from these emails</a>{{end}}
+  </p>
+</div>
+</body>
+</html>
+`))
+
+type userNotificationEmailView struct {
+	SiteName       string
+	Username       string
+	Items          []userNotification
+	SettingsURL    string
+	UnsubscribeURL string
+}
+
+func renderUserNotificationEmail(view userNotificationEmailView) (string, error) {
+	var buf bytes.Buffer
+	if err := userNotificationEmailTemplate.Execute(&buf, view); err != nil {
+		return "", fmt.Errorf("render notification email: %w", err)
+	}
+	return buf.String(), nil
+}
+
+// userNotificationUnsubscribeTemplate 退订页：GET 展示确认按钮，POST 执行退订后展示结果，
+// 避免邮件客户端预取链接时误退订。
+var userNotificationUnsubscribeTemplate = template.Must(template.New("unsubscribe").Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="UTF-8">
+<meta name="viewport" content="width=device-width, initial-scale=1">
+<title>{{.SiteName}} - Unsubscribe</title>
+</head>
+<body style="font-family: -apple-system, BlinkMacSystemFont, 'Segoe UI', Roboto, sans-serif; color: #1f2937; margin: 0; padding: 48px 24px; background: #f9fafb;">
+<div style="max-width: 480px; margin: 0 auto; background: #fff; border: 1px solid #e5e7eb; border-radius: 8px; padding: 24px; text-align: center;">
+  <h1 style="font-size: 18px; margin: 0 0 16px;">{{.SiteName}}</h1>
+  {{if .Done}}
+  <p style="font-size: 14px;">You have been unsubscribed from account alert emails. You can turn them back on in your profile settings.</p>
+  {{else if .Error}}
+  <p style="font-size: 14px; color: #b91c1c;">{{.Error}}</p>
+  {{else}}
+  <p style="font-size: 14px;">Stop receiving low-balance, quota and subscription alert emails?</p>
+  <form method="POST">
+    <input type="hidden" name="token" value="{{.Token}}">
+    <button type="submit" style="background: #2563eb; color: #fff; border: 0; border-radius: 6px; padding: 8px 16px; font-size: 14px; cursor: pointer;">Unsubscribe</button>
+  </form>
+  {{end}}
+</div>
+</body>
+</html>
+`))
+
+// UserNotificationUnsubscribeView 退订页渲染参数
+type UserNotificationUnsubscribeView struct {
+	SiteName string
+	Token    string
+	Done     bool
+	Error    string
+}
+
+// RenderUserNotificationUnsubscribePage 渲染退订页
+func RenderUserNotificationUnsubscribePage(view UserNotificationUnsubscribeView) (string, error) {
+	var buf bytes.Buffer
+	if err := userNotificationUnsubscribeTemplate.Execute(&buf, view); err != nil {
+		return "", fmt.Errorf("render unsubscribe page: %w", err)
+	}
+	return buf.String(), nil
+}
@@ -0,0 +1,447 @@
+package service
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"math"
+	"net/url"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+const (
+	userNotificationWorkerName      = "user_notification_evaluate"
+	userNotificationEvaluateTimeout = 5 * time.Minute
+	userNotificationAPIKeyPageSize  = 1000
+)
+
+// UserNotificationService 用户通知邮件：余额不足、API Key 额度将尽、订阅日/周/月用量将尽、订阅即将到期。
+//
+// 阈值由后台定期检查（不占用计费热路径）；每个提醒以 key 去重，条件解除前只发送一次，
+// 用量类 key 带窗口起点，因此每个计费周期最多提醒一次。同一轮内的多个提醒合并为一封邮件，
+// 经 EmailQueueService 异步发送。
+type UserNotificationService struct {
+	repo           UserNotificationRepository
+	userRepo       UserRepository
+	apiKeyRepo     APIKeyRepository
+	userSubRepo    UserSubscriptionRepository
+	emailService   *EmailService
+	settingService *SettingService
+	timingWheel    *TimingWheelService
+	cfg            *config.Config
+	now            func() time.Time
+
+	// enqueueEmail 默认写入 EmailQueueService，测试可替换
+	enqueueEmail func(email, subject, body string) error
+
+	running   int32
+	startOnce sync.Once
+	stopOnce  sync.Once
+
+	workerCtx    context.Context
+	workerCancel context.CancelFunc
+}
+
+// NewUserNotificationService 创建用户通知邮件服务
+func NewUserNotificationService(
+	repo UserNotificationRepository,
+	userRepo UserRepository,
+	apiKeyRepo APIKeyRepository,
+	userSubRepo UserSubscriptionRepository,
+	emailService *EmailService,
+	emailQueue *EmailQueueService,
+	settingService *SettingService,
+	timingWheel *TimingWheelService,
+	cfg *config.Config,
+) *UserNotificationService {
+	workerCtx, workerCancel := context.WithCancel(context.Background())
+	s := &UserNotificationService{
+		repo:           repo,
+		userRepo:       userRepo,
+		apiKeyRepo:     apiKeyRepo,
+		userSubRepo:    userSubRepo,
+		emailService:   emailService,
+		settingService: settingService,
+		timingWheel:    timingWheel,
+		cfg:            cfg,
+		now:            time.Now,
+		workerCtx:      workerCtx,
+		workerCancel:   workerCancel,
+	}
+	if emailQueue != nil {
+		s.enqueueEmail = emailQueue.EnqueueNotification
+	}
+	return s
+}
+
+// Enabled 是否启用通知邮件
+func (s *UserNotificationService) Enabled() bool {
+	return s != nil && s.repo != nil && s.cfg != nil && s.cfg.Notification.Enabled
+}
+
+func (s *UserNotificationService) Start() {
+	if !s.Enabled() {
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] not started (disabled)")
+		return
+	}
+	if s.timingWheel == nil || s.enqueueEmail == nil {
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] not started (missing deps)")
+		return
+	}
+	s.startOnce.Do(func() {
+		interval := time.Duration(s.cfg.Notification.EvaluateIntervalSeconds) * time.Second
+		s.timingWheel.ScheduleRecurring(userNotificationWorkerName, interval, s.runOnce)
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] started (interval=%s max_emails_per_run=%d)", interval, s.cfg.Notification.MaxEmailsPerRun)
+	})
+}
+
+func (s *UserNotificationService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		if s.workerCancel != nil {
+			s.workerCancel()
+		}
+		if s.timingWheel != nil {
+			s.timingWheel.Cancel(userNotificationWorkerName)
+		}
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] stopped")
+	})
+}
+
+// =========================
+// 设置
+// =========================
+
+// GetSettings 获取用户通知设置；未保存过时返回默认值（未开启）
+func (s *UserNotificationService) GetSettings(ctx context.Context, userID int64) (*UserNotificationSettings, error) {
+	settings, err := s.repo.GetSettings(ctx, userID)
+	if errors.Is(err, ErrUserNotificationSettingsNotFound) {
+		return DefaultUserNotificationSettings(userID), nil
+	}
+	return settings, err
+}
+
+// UpdateSettings 更新用户通知设置；首次保存时生成退订令牌
+func (s *UserNotificationService) UpdateSettings(ctx context.Context, userID int64, in UpdateUserNotificationSettingsInput) (*UserNotificationSettings, error) {
+	settings, err := s.GetSettings(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if in.Enabled != nil {
+		settings.Enabled = *in.Enabled
+	}
+	if in.BalanceThreshold != nil {
+		settings.BalanceThreshold = *in.BalanceThreshold
+	}
+	if in.APIKeyQuotaPercent != nil {
+		settings.APIKeyQuotaPercent = *in.APIKeyQuotaPercent
+	}
+	if in.SubscriptionUsagePercent != nil {
+		settings.SubscriptionUsagePercent = *in.SubscriptionUsagePercent
+	}
+	if in.ExpiryNoticeDays != nil {
+		settings.ExpiryNoticeDays = *in.ExpiryNoticeDays
+	}
+	if err := validateUserNotificationSettings(settings); err != nil {
+		return nil, err
+	}
+	if settings.UnsubscribeToken == "" {
+		token, err := generateUserNotificationToken()
+		if err != nil {
+			return nil, err
+		}
+		settings.UnsubscribeToken = token
+	}
+	if err := s.repo.UpsertSettings(ctx, settings); err != nil {
+		return nil, fmt.Errorf("save notification settings: %w", err)
+	}
+	return settings, nil
+}
+
+// Unsubscribe 通过邮件中的退订链接关闭通知（无需登录）
+func (s *UserNotificationService) Unsubscribe(ctx context.Context, token string) error {
+	token = strings.TrimSpace(token)
+	if token == "" {
+		return ErrUserNotificationInvalidToken
+	}
+	return s.repo.DisableByToken(ctx, token)
+}
+
+// SiteName 邮件与退订页展示的站点名称
+func (s *UserNotificationService) SiteName(ctx context.Context) string {
+	if s.settingService != nil {
+		return s.settingService.GetSiteName(ctx)
+	}
+	return "Sub2API"
+}
+
+func validateUserNotificationSettings(settings *UserNotificationSettings) error {
+	if settings.BalanceThreshold < 0 || math.IsNaN(settings.BalanceThreshold) || math.IsInf(settings.BalanceThreshold, 0) {
+		return ErrUserNotificationInvalidSettings.WithMetadata(map[string]string{"field": "balance_threshold"})
+	}
+	if settings.APIKeyQuotaPercent < 0 || settings.APIKeyQuotaPercent > 100 {
+		return ErrUserNotificationInvalidSettings.WithMetadata(map[string]string{"field": "api_key_quota_percent"})
+	}
+	if settings.SubscriptionUsagePercent < 0 || settings.SubscriptionUsagePercent > 100 {
+		return ErrUserNotificationInvalidSettings.WithMetadata(map[string]string{"field": "subscription_usage_percent"})
+	}
+	if settings.ExpiryNoticeDays < 0 || settings.ExpiryNoticeDays > 365 {
+		return ErrUserNotificationInvalidSettings.WithMetadata(map[string]string{"field": "expiry_notice_days"})
+	}
+	return nil
+}
+
+func generateUserNotificationToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", infraerrors.InternalServer("NOTIFICATION_TOKEN_FAILED", "failed to generate unsubscribe token").WithCause(err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// =========================
+// 阈值检查
+// =========================
+
+// userNotificationSnapshot 单个用户在一次检查中使用的数据
+type userNotificationSnapshot struct {
+	user          *User
+	apiKeys       []APIKey
+	subscriptions []UserSubscription
+}
+
+// userNotification 当前成立的提醒；Key 用于去重
+type userNotification struct {
+	Key    string
+	Title  string
+	Detail string
+}
+
+func (s *UserNotificationService) runOnce() {
+	if !s.Enabled() {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&s.running, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&s.running, 0)
+
+	ctx, cancel := context.WithTimeout(s.workerCtx, userNotificationEvaluateTimeout)
+	defer cancel()
+
+	// SMTP 未配置时不消耗去重状态，配置后再发送
+	if s.emailService != nil {
+		if _, err := s.emailService.GetSMTPConfig(ctx); err != nil {
+			return
+		}
+	}
+
+	settingsList, err := s.repo.ListEnabledSettings(ctx)
+	if err != nil {
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] list settings failed: %v", err)
+		return
+	}
+	sent := 0
+	for i := range settingsList {
+		if ctx.Err() != nil || sent >= s.cfg.Notification.MaxEmailsPerRun {
+			return
+		}
+		if s.evaluateUser(ctx, &settingsList[i]) {
+			sent++
+		}
+	}
+	if sent > 0 {
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] enqueued %d notification emails", sent)
+	}
+}
+
+// evaluateUser 检查单个用户：新成立的提醒合并为一封邮件发送，已解除的清除去重状态以便下次重新提醒。
+// 返回是否入队了邮件。
+func (s *UserNotificationService) evaluateUser(ctx context.Context, settings *UserNotificationSettings) bool {
+	snap := s.loadSnapshot(ctx, settings.UserID)
+	if snap == nil {
+		return false
+	}
+	notices := userNotifications(settings, snap, s.now())
+
+	existing, err := s.repo.ListStateKeys(ctx, settings.UserID)
+	if err != nil {
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] list states failed: user=%d err=%v", settings.UserID, err)
+		return false
+	}
+	active := make(map[string]struct{}, len(notices))
+	var fresh []userNotification
+	for _, n := range notices {
+		active[n.Key] = struct{}{}
+		inserted, err := s.repo.AddStateKey(ctx, settings.UserID, n.Key)
+		if err != nil {
+			logger.LegacyPrintf("service.user_notification", "[UserNotification] save state failed: user=%d key=%s err=%v", settings.UserID, n.Key, err)
+			continue
+		}
+		if inserted {
+			fresh = append(fresh, n)
+		}
+	}
+
+	var resolved []string
+	for _, key := range existing {
+		if _, ok := active[key]; !ok {
+			resolved = append(resolved, key)
+		}
+	}
+	if len(resolved) > 0 {
+		if err := s.repo.DeleteStateKeys(ctx, settings.UserID, resolved); err != nil {
+			logger.LegacyPrintf("service.user_notification", "[UserNotification] clear states failed: user=%d err=%v", settings.UserID, err)
+		}
+	}
+
+	if len(fresh) == 0 {
+		return false
+	}
+	if err := s.send(ctx, settings, snap.user, fresh); err != nil {
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] enqueue email failed: user=%d err=%v", settings.UserID, err)
+		// 入队失败时撤销去重状态，下一轮重试
+		keys := make([]string, 0, len(fresh))
+		for _, n := range fresh {
+			keys = append(keys, n.Key)
+		}
+		_ = s.repo.DeleteStateKeys(ctx, settings.UserID, keys)
+		return false
+	}
+	return true
+}
+
+func (s *UserNotificationService) loadSnapshot(ctx context.Context, userID int64) *userNotificationSnapshot {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		if !errors.Is(err, ErrUserNotFound) {
+			logger.LegacyPrintf("service.user_notification", "[UserNotification] load user failed: user=%d err=%v", userID, err)
+		}
+		return nil
+	}
+	if user.Status != StatusActive || strings.TrimSpace(user.Email) == "" {
+		return nil
+	}
+	keys, _, err := s.apiKeyRepo.ListByUserID(ctx, userID, pagination.PaginationParams{Page: 1, PageSize: userNotificationAPIKeyPageSize}, APIKeyListFilters{})
+	if err != nil {
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] load api keys failed: user=%d err=%v", userID, err)
+		return nil
+	}
+	subs, err := s.userSubRepo.ListActiveByUserID(ctx, userID)
+	if err != nil {
+		logger.LegacyPrintf("service.user_notification", "[UserNotification] load subscriptions failed: user=%d err=%v", userID, err)
+		return nil
+	}
+	return &userNotificationSnapshot{user: user, apiKeys: keys, subscriptions: subs}
+}
+
+func (s *UserNotificationService) send(ctx context.Context, settings *UserNotificationSettings, user *User, notices []userNotification) error {
+	siteName := s.SiteName(ctx)
+	view := userNotificationEmailView{
+		SiteName: siteName,
+		Username: user.Username,
+		Items:    notices,
+	}
+	if base := strings.TrimRight(strings.TrimSpace(s.cfg.Server.FrontendURL), "/"); base != "" {
+		view.SettingsURL = base + "/profile"
+		view.UnsubscribeURL = base + "/api/v1/notifications/unsubscribe?token=" + url.QueryEscape(settings.UnsubscribeToken)
+	}
+	body, err := renderUserNotificationEmail(view)
+	if err != nil {
+		return err
+	}
+	subject := fmt.Sprintf("[%s] %s", siteName, notices[0].Title)
+	if len(notices) > 1 {
+		subject = fmt.Sprintf("[%s] %d account alerts", siteName, len(notices))
+	}
+	return s.enqueueEmail(user.Email, subject, body)
+}
+
+// userNotifications 计算用户当前成立的提醒
+func userNotifications(settings *UserNotificationSettings, snap *userNotificationSnapshot, now time.Time) []userNotification {
+	var out []userNotification
+
+	if settings.BalanceThreshold > 0 && snap.user.Balance < settings.BalanceThreshold {
+		out = append(out, userNotification{
+			Key:    UserNotificationBalanceLow,
+			Title:  "Low balance",
+			Detail: fmt.Sprintf("Your balance is $%.2f, below your alert threshold of $%.2f. Requests billed to balance will be rejected once it runs out.", snap.user.Balance, settings.BalanceThreshold),
+		})
+	}
+
+	if settings.APIKeyQuotaPercent > 0 {
+		ratio := float64(settings.APIKeyQuotaPercent) / 100
+		for i := range snap.apiKeys {
+			key := &snap.apiKeys[i]
+			if key.Quota <= 0 || key.QuotaUsed < key.Quota*ratio {
+				continue
+			}
+			out = append(out, userNotification{
+				Key:    fmt.Sprintf("%s:%d", UserNotificationAPIKeyQuota, key.ID),
+				Title:  "API key quota nearly used up",
+				Detail: fmt.Sprintf("API key \"%s\" has used $%.2f of its $%.2f quota (%.0f%%).", key.Name, key.QuotaUsed, key.Quota, key.QuotaUsed/key.Quota*100),
+			})
+		}
+	}
+
+	for i := range snap.subscriptions {
+		sub := &snap.subscriptions[i]
+		if sub.Status != SubscriptionStatusActive || !now.Before(sub.ExpiresAt) {
+			continue
+		}
+		groupName := fmt.Sprintf("#%d", sub.GroupID)
+		if sub.Group != nil && sub.Group.Name != "" {
+			groupName = sub.Group.Name
+		}
+
+		if settings.SubscriptionUsagePercent > 0 && sub.Group != nil {
+			ratio := float64(settings.SubscriptionUsagePercent) / 100
+			for _, w := range []struct {
+				name     string
+				limit    *float64
+				usage    float64
+				start    *time.Time
+				duration time.Duration
+			}{
+				{"daily", sub.Group.DailyLimitUSD, sub.DailyUsageUSD, sub.DailyWindowStart, 24 * time.Hour},
+				{"weekly", sub.Group.WeeklyLimitUSD, sub.WeeklyUsageUSD, sub.WeeklyWindowStart, 7 * 24 * time.Hour},
+				{"monthly", sub.Group.MonthlyLimitUSD, sub.MonthlyUsageUSD, sub.MonthlyWindowStart, 30 * 24 * time.Hour},
+			} {
+				// 窗口已过期时用量即将被重置，不提醒
+				if w.limit == nil || *w.limit <= 0 || w.start == nil || now.Sub(*w.start) >= w.duration || w.usage < *w.limit*ratio {
+					continue
+				}
+				out = append(out, userNotification{
+					// 带上窗口起点：每个周期只提醒一次
+					Key:   fmt.Sprintf("%s:%d:%s:%d", UserNotificationSubscriptionUsage, sub.ID, w.name, w.start.Unix()),
+					Title: fmt.Sprintf("Subscription %s limit nearly reached", w.name),
+					Detail: fmt.Sprintf("Your \"%s\" subscription has used $%.2f of its $%.2f %s limit (%.0f%%). The limit resets at %s.",
+						groupName, w.usage, *w.limit, w.name, w.usage / *w.limit * 100, w.start.Add(w.duration).UTC().Format("2006-01-02 15:04 UTC")),
+				})
+			}
+		}
+
+		remaining := sub.ExpiresAt.Sub(now)
+		if settings.ExpiryNoticeDays > 0 && remaining <= time.Duration(settings.ExpiryNoticeDays)*24*time.Hour {
+			out = append(out, userNotification{
+				Key:   fmt.Sprintf("%s:%d:%d", UserNotificationSubscriptionExpiring, sub.ID, sub.ExpiresAt.Unix()),
+				Title: "Subscription expiring soon",
+				Detail: fmt.Sprintf("Your \"%s\" subscription expires on %s (in %d day(s)).",
+					groupName, sub.ExpiresAt.UTC().Format("2006-01-02 15:04 UTC"), int(math.Ceil(remaining.Hours()/24))),
+			})
+		}
+	}
+	return out
+}
@@ -0,0 +1,291 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+	"github.com/stretchr/testify/require"
+)
+
+type userNotificationRepoStub struct {
+	UserNotificationRepository
+	settings map[int64]*UserNotificationSettings
+	states   map[int64]map[string]struct{}
+}
+
+func newUserNotificationRepoStub() *userNotificationRepoStub {
+	return &userNotificationRepoStub{settings: map[int64]*UserNotificationSettings{}, states: map[int64]map[string]struct{}{}}
+}
+
+func (r *userNotificationRepoStub) GetSettings(_ context.Context, userID int64) (*UserNotificationSettings, error) {
+	s, ok := r.settings[userID]
+	if !ok {
+		return nil, ErrUserNotificationSettingsNotFound
+	}
+	cp := *s
+	return &cp, nil
+}
+
+func (r *userNotificationRepoStub) UpsertSettings(_ context.Context, settings *UserNotificationSettings) error {
+	if existing, ok := r.settings[settings.UserID]; ok {
+		settings.UnsubscribeToken = existing.UnsubscribeToken
+	}
+	cp := *settings
+	r.settings[settings.UserID] = &cp
+	return nil
+}
+
+func (r *userNotificationRepoStub) DisableByToken(_ context.Context, token string) error {
+	for _, s := range r.settings {
+		if s.UnsubscribeToken == token {
+			s.Enabled = false
+			return nil
+		}
+	}
+	return ErrUserNotificationInvalidToken
+}
+
+func (r *userNotificationRepoStub) ListStateKeys(_ context.Context, userID int64) ([]string, error) {
+	var out []string
+	for k := range r.states[userID] {
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+func (r *userNotificationRepoStub) AddStateKey(_ context.Context, userID int64, key string) (bool, error) {
+	if r.states[userID] == nil {
+		r.states[userID] = map[string]struct{}{}
+	}
+	if _, ok := r.states[userID][key]; ok {
+		return false, nil
+	}
+	r.states[userID][key] = struct{}{}
+	return true, nil
+}
+
+func (r *userNotificationRepoStub) DeleteStateKeys(_ context.Context, userID int64, keys []string) error {
+	for _, k := range keys {
+		delete(r.states[userID], k)
+	}
+	return nil
+}
+
+type userNotificationUserRepoStub struct {
+	UserRepository
+	user *User
+}
+
+func (r *userNotificationUserRepoStub) GetByID(_ context.Context, _ int64) (*User, error) {
+	cp := *r.user
+	return &cp, nil
+}
+
+type userNotificationAPIKeyRepoStub struct {
+	APIKeyRepository
+	keys []APIKey
+}
+
+func (r *userNotificationAPIKeyRepoStub) ListByUserID(_ context.Context, _ int64, _ pagination.PaginationParams, _ APIKeyListFilters) ([]APIKey, *pagination.PaginationResult, error) {
+	return r.keys, &pagination.PaginationResult{Total: int64(len(r.keys))}, nil
+}
+
+type userNotificationSubRepoStub struct {
+	UserSubscriptionRepository
+	subs []UserSubscription
+}
+
+func (r *userNotificationSubRepoStub) ListActiveByUserID(_ context.Context, _ int64) ([]UserSubscription, error) {
+	return r.subs, nil
+}
+
+type sentNotificationEmail struct {
+	to, subject, body string
+}
+
+func newUserNotificationTestService(repo UserNotificationRepository, user *User, now time.Time) (*UserNotificationService, *[]sentNotificationEmail) {
+	cfg := &config.Config{}
+	cfg.Notification = config.NotificationConfig{Enabled: true, EvaluateIntervalSeconds: 600, MaxEmailsPerRun: 10}
+	cfg.Server.FrontendURL = "https://example.com"
+	svc := NewUserNotificationService(repo, &userNotificationUserRepoStub{user: user}, &userNotificationAPIKeyRepoStub{}, &userNotificationSubRepoStub{}, nil, nil, nil, nil, cfg)
+	svc.now = func() time.Time { return now }
+	sent := &[]sentNotificationEmail{}
+	svc.enqueueEmail = func(email, subject, body string) error {
+		*sent = append(*sent, sentNotificationEmail{to: email, subject: subject, body: body})
+		return nil
+	}
+	return svc, sent
+}
+
+func notificationKeys(notices []userNotification) []string {
+	keys := make([]string, 0, len(notices))
+	for _, n := range notices {
+		keys = append(keys, n.Key)
+	}
+	return keys
+}
+
+func TestUserNotifications_BalanceAndQuota(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	settings := &UserNotificationSettings{Enabled: true, BalanceThreshold: 5, APIKeyQuotaPercent: 80}
+	snap := &userNotificationSnapshot{
+		user: &User{ID: 1, Balance: 4.5},
+		apiKeys: []APIKey{
+			{ID: 1, Name: "near", Quota: 10, QuotaUsed: 8},
+			{ID: 2, Name: "fine", Quota: 10, QuotaUsed: 5},
+			{ID: 3, Name: "unlimited", QuotaUsed: 100},
+		},
+	}
+	notices := userNotifications(settings, snap, now)
+	require.Equal(t, []string{UserNotificationBalanceLow, "api_key_quota:1"}, notificationKeys(notices))
+	require.Contains(t, notices[0].Detail, "$4.50")
+	require.Contains(t, notices[1].Detail, `"near"`)
+
+	// 阈值为 0 表示不提醒
+	settings.BalanceThreshold = 0
+	settings.APIKeyQuotaPercent = 0
+	require.Empty(t, userNotifications(settings, snap, now))
+}
+
+func TestUserNotifications_SubscriptionUsageAndExpiry(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	daily, weekly := 10.0, 50.0
+	dayStart := now.Add(-2 * time.Hour)
+	staleWeekStart := now.Add(-8 * 24 * time.Hour)
+	settings := &UserNotificationSettings{Enabled: true, SubscriptionUsagePercent: 90, ExpiryNoticeDays: 3}
+	snap := &userNotificationSnapshot{
+		user: &User{ID: 1},
+		subscriptions: []UserSubscription{
+			{
+				ID:                7,
+				GroupID:           3,
+				Status:            SubscriptionStatusActive,
+				ExpiresAt:         now.Add(36 * time.Hour),
+				DailyWindowStart:  &dayStart,
+				DailyUsageUSD:     9.5,
+				WeeklyWindowStart: &staleWeekStart,
+				WeeklyUsageUSD:    49,
+				Group:             &Group{Name: "Pro", DailyLimitUSD: &daily, WeeklyLimitUSD: &weekly},
+			},
+			{
+				ID:        8,
+				GroupID:   4,
+				Status:    SubscriptionStatusActive,
+				ExpiresAt: now.Add(10 * 24 * time.Hour),
+				Group:     &Group{Name: "Basic"},
+			},
+		},
+	}
+	notices := userNotifications(settings, snap, now)
+	require.Equal(t, []string{
+		"subscription_usage:7:daily:" + strconv.FormatInt(dayStart.Unix(), 10),
+		"subscription_expiring:7:" + strconv.FormatInt(now.Add(36*time.Hour).Unix(), 10),
+	}, notificationKeys(notices))
+	require.Contains(t, notices[0].Detail, `"Pro"`)
+	require.Contains(t, notices[1].Detail, "in 2 day(s)")
+}
+
+func TestUserNotificationService_EvaluateUserDeduplicatesPerPeriod(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newUserNotificationRepoStub()
+	user := &User{ID: 1, Email: "u@example.com", Username: "alice", Status: StatusActive, Balance: 1}
+	svc, sent := newUserNotificationTestService(repo, user, now)
+	settings := &UserNotificationSettings{UserID: 1, Enabled: true, BalanceThreshold: 5, UnsubscribeToken: "tok"}
+	ctx := context.Background()
+
+	require.True(t, svc.evaluateUser(ctx, settings))
+	require.False(t, svc.evaluateUser(ctx, settings))
+	require.Len(t, *sent, 1)
+	mail := (*sent)[0]
+	require.Equal(t, "u@example.com", mail.to)
+	require.Equal(t, "[Sub2API] Low balance", mail.subject)
+	require.Contains(t, mail.body, "alice")
+	require.Contains(t, mail.body, "https://example.com/api/v1/notifications/unsubscribe?token=tok")
+
+	// 余额恢复后清除状态，再次低于阈值时重新提醒
+	svc.userRepo.(*userNotificationUserRepoStub).user.Balance = 10
+	require.False(t, svc.evaluateUser(ctx, settings))
+	require.Empty(t, repo.states[1])
+	svc.userRepo.(*userNotificationUserRepoStub).user.Balance = 2
+	require.True(t, svc.evaluateUser(ctx, settings))
+	require.Len(t, *sent, 2)
+}
+
+func TestUserNotificationService_EvaluateUserMergesAndRetriesOnEnqueueFailure(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	repo := newUserNotificationRepoStub()
+	user := &User{ID: 1, Email: "u@example.com", Status: StatusActive, Balance: 1}
+	svc, sent := newUserNotificationTestService(repo, user, now)
+	svc.apiKeyRepo = &userNotificationAPIKeyRepoStub{keys: []APIKey{{ID: 9, Name: "k", Quota: 10, QuotaUsed: 10}}}
+	settings := &UserNotificationSettings{UserID: 1, Enabled: true, BalanceThreshold: 5, APIKeyQuotaPercent: 90}
+	ctx := context.Background()
+
+	enqueue := svc.enqueueEmail
+	svc.enqueueEmail = func(string, string, string) error { return errors.New("email queue is full") }
+	require.False(t, svc.evaluateUser(ctx, settings))
+	require.Empty(t, repo.states[1])
+
+	svc.enqueueEmail = enqueue
+	require.True(t, svc.evaluateUser(ctx, settings))
+	require.Len(t, *sent, 1)
+	require.Equal(t, "[Sub2API] 2 account alerts", (*sent)[0].subject)
+	require.True(t, strings.Contains((*sent)[0].body, "Low balance") && strings.Contains((*sent)[0].body, "API key quota nearly used up"))
+}
+
+func TestUserNotificationService_EvaluateUserSkipsInactiveUser(t *testing.T) {
+	repo := newUserNotificationRepoStub()
+	user := &User{ID: 1, Email: "u@example.com", Status: "disabled", Balance: 1}
+	svc, sent := newUserNotificationTestService(repo, user, time.Now())
+	require.False(t, svc.evaluateUser(context.Background(), &UserNotificationSettings{UserID: 1, Enabled: true, BalanceThreshold: 5}))
+	require.Empty(t, *sent)
+}
+
+func TestUserNotificationService_SettingsAndUnsubscribe(t *testing.T) {
+	repo := newUserNotificationRepoStub()
+	svc, _ := newUserNotificationTestService(repo, &User{ID: 1}, time.Now())
+	ctx := context.Background()
+
+	defaults, err := svc.GetSettings(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, defaults.Enabled)
+	require.Equal(t, 90, defaults.APIKeyQuotaPercent)
+	require.Equal(t, 3, defaults.ExpiryNoticeDays)
+
+	enabled, threshold := true, 12.5
+	settings, err := svc.UpdateSettings(ctx, 1, UpdateUserNotificationSettingsInput{Enabled: &enabled, BalanceThreshold: &threshold})
+	require.NoError(t, err)
+	require.True(t, settings.Enabled)
+	require.Len(t, settings.UnsubscribeToken, 48)
+	token := settings.UnsubscribeToken
+
+	badPercent := 101
+	_, err = svc.UpdateSettings(ctx, 1, UpdateUserNotificationSettingsInput{APIKeyQuotaPercent: &badPercent})
+	require.ErrorIs(t, err, ErrUserNotificationInvalidSettings)
+
+	// 再次保存不更换退订令牌
+	settings, err = svc.UpdateSettings(ctx, 1, UpdateUserNotificationSettingsInput{BalanceThreshold: &threshold})
+	require.NoError(t, err)
+	require.Equal(t, token, settings.UnsubscribeToken)
+
+	require.ErrorIs(t, svc.Unsubscribe(ctx, "wrong"), ErrUserNotificationInvalidToken)
+	require.ErrorIs(t, svc.Unsubscribe(ctx, " "), ErrUserNotificationInvalidToken)
+	require.NoError(t, svc.Unsubscribe(ctx, token))
+	settings, err = svc.GetSettings(ctx, 1)
+	require.NoError(t, err)
+	require.False(t, settings.Enabled)
+}
+
+func TestRenderUserNotificationUnsubscribePage_EscapesToken(t *testing.T) {
+	body, err := RenderUserNotificationUnsubscribePage(UserNotificationUnsubscribeView{SiteName: "S", Token: `"><script>`})
+	require.NoError(t, err)
+	require.NotContains(t, body, "<script>")
+	require.Contains(t, body, `<form method="POST">`)
+}
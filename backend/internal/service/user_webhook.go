@@ -0,0 +1,207 @@
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+// 用户 Webhook 事件类型
+const (
+	UserWebhookEventSoraGenerationCompleted = "sora.generation.completed"
+	UserWebhookEventSoraGenerationFailed    = "sora.generation.failed"
+	UserWebhookEventBalanceLow              = "balance.low"
+	UserWebhookEventAPIKeyQuotaLow          = "api_key.quota_low"
+	UserWebhookEventAPIKeyRateLimitLow      = "api_key.rate_limit_low"
+	UserWebhookEventSubscriptionExpiring    = "subscription.expiring"
+	UserWebhookEventSubscriptionExpired     = "subscription.expired"
+	UserWebhookEventOrderPaid               = "order.paid"
+	// UserWebhookEventPing 仅由"发送测试事件"产生，不可订阅
+	UserWebhookEventPing = "webhook.ping"
+)
+
+// UserWebhookEvents 可订阅的事件类型（顺序即前端展示顺序）
+var UserWebhookEvents = []string{
+	UserWebhookEventSoraGenerationCompleted,
+	UserWebhookEventSoraGenerationFailed,
+	UserWebhookEventBalanceLow,
+	UserWebhookEventAPIKeyQuotaLow,
+	UserWebhookEventAPIKeyRateLimitLow,
+	UserWebhookEventSubscriptionExpiring,
+	UserWebhookEventSubscriptionExpired,
+	UserWebhookEventOrderPaid,
+}
+
+// userWebhookThresholdEvents 由后台定期检查触发的阈值类事件
+var userWebhookThresholdEvents = []string{
+	UserWebhookEventBalanceLow,
+	UserWebhookEventAPIKeyQuotaLow,
+	UserWebhookEventAPIKeyRateLimitLow,
+	UserWebhookEventSubscriptionExpiring,
+	UserWebhookEventSubscriptionExpired,
+}
+
+// 投递状态
+const (
+	UserWebhookDeliveryPending   = "pending"
+	UserWebhookDeliverySucceeded = "succeeded"
+	UserWebhookDeliveryFailed    = "failed"
+)
+
+const (
+	// UserWebhookDeliveryHeader 携带投递 ID；事件类型、时间戳与签名头与运维告警 Webhook 一致
+	UserWebhookDeliveryHeader = "X-Sub2API-Delivery"
+
+	userWebhookDefaultUsagePercent = 90
+	userWebhookDefaultExpiryDays   = 3
+)
+
+var (
+	ErrUserWebhookNotFound         = infraerrors.NotFound("WEBHOOK_NOT_FOUND", "webhook not found")
+	ErrUserWebhookDeliveryNotFound = infraerrors.NotFound("WEBHOOK_DELIVERY_NOT_FOUND", "webhook delivery not found")
+	ErrUserWebhookDisabled         = infraerrors.New(http.StatusServiceUnavailable, "WEBHOOK_DISABLED", "webhooks are disabled")
+	ErrUserWebhookLimitExceeded    = infraerrors.BadRequest("WEBHOOK_LIMIT_EXCEEDED", "webhook limit exceeded")
+	ErrUserWebhookInvalidEvents    = infraerrors.BadRequest("WEBHOOK_INVALID_EVENTS", "events must be a non-empty list of supported event types")
+	ErrUserWebhookInvalidAPIKey    = infraerrors.BadRequest("WEBHOOK_INVALID_API_KEY", "api key not found")
+)
+
+// UserWebhook 用户配置的出站 Webhook。
+// APIKeyID 非空时只推送与该 Key 相关的事件（余额、订阅、订单等用户级事件不推送）。
+type UserWebhook struct {
+	ID       int64
+	UserID   int64
+	APIKeyID *int64
+	Name     string
+	URL      string
+	Secret   string
+	Events   []string
+	Enabled  bool
+
+	// 阈值类事件参数
+	BalanceThreshold      float64 // balance.low：余额低于该值（USD）时触发
+	UsagePercentThreshold int     // api_key.quota_low / api_key.rate_limit_low：用量达到该百分比时触发
+	ExpiryNoticeDays      int     // subscription.expiring：到期前 N 天触发
+
+	LastDeliveryAt     *time.Time
+	LastDeliveryStatus string
+	CreatedAt          time.Time
+	UpdatedAt          time.Time
+}
+
+// Subscribes 是否订阅了指定事件
+func (w *UserWebhook) Subscribes(eventType string) bool {
+	for _, e := range w.Events {
+		if e == eventType {
+			return true
+		}
+	}
+	return false
+}
+
+// Accepts 判断事件是否应投递到该 Webhook（订阅且 API Key 范围匹配）
+func (w *UserWebhook) Accepts(event *UserWebhookEvent) bool {
+	if !w.Enabled || !w.Subscribes(event.Type) {
+		return false
+	}
+	if w.APIKeyID == nil {
+		return true
+	}
+	return event.APIKeyID != nil && *event.APIKeyID == *w.APIKeyID
+}
+
+// UserWebhookEvent 待推送的事件
+type UserWebhookEvent struct {
+	UserID   int64
+	APIKeyID *int64 // 与事件相关的 API Key（如有），用于匹配 Key 级 Webhook
+	Type     string
+	Data     any
+}
+
+// UserWebhookPayload 投递的请求体
+type UserWebhookPayload struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// UserWebhookDelivery 单次事件到单个 Webhook 的投递记录，同时充当重试队列
+type UserWebhookDelivery struct {
+	ID             int64
+	WebhookID      int64
+	UserID         int64
+	EventID        string
+	EventType      string
+	Payload        json.RawMessage
+	Status         string
+	Attempts       int
+	MaxAttempts    int
+	NextAttemptAt  time.Time
+	ResponseStatus int
+	LastError      string
+	DurationMs     int64
+	DeliveredAt    *time.Time
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+}
+
+// CreateUserWebhookInput 创建 Webhook 参数
+type CreateUserWebhookInput struct {
+	Name                  string
+	URL                   string
+	APIKeyID              *int64
+	Events                []string
+	Enabled               *bool
+	BalanceThreshold      *float64
+	UsagePercentThreshold *int
+	ExpiryNoticeDays      *int
+}
+
+// UpdateUserWebhookInput 更新 Webhook 参数（nil 表示不修改；ClearAPIKey 表示改为用户级）
+type UpdateUserWebhookInput struct {
+	Name                  *string
+	URL                   *string
+	APIKeyID              *int64
+	ClearAPIKey           bool
+	Events                []string
+	Enabled               *bool
+	BalanceThreshold      *float64
+	UsagePercentThreshold *int
+	ExpiryNoticeDays      *int
+}
+
+// UserWebhookRepository 用户 Webhook 持久层
+type UserWebhookRepository interface {
+	Create(ctx context.Context, hook *UserWebhook) error
+	// GetByID 不存在返回 ErrUserWebhookNotFound
+	GetByID(ctx context.Context, id int64) (*UserWebhook, error)
+	Update(ctx context.Context, hook *UserWebhook) error
+	Delete(ctx context.Context, id int64) error
+	ListByUser(ctx context.Context, userID int64) ([]UserWebhook, error)
+	CountByUser(ctx context.Context, userID int64) (int64, error)
+	// ListSubscribed 列出用户已启用且订阅了 eventType 的 Webhook
+	ListSubscribed(ctx context.Context, userID int64, eventType string) ([]UserWebhook, error)
+	// ListSubscribedToAny 列出所有已启用且订阅了 eventTypes 中任一事件的 Webhook（阈值检查使用）
+	ListSubscribedToAny(ctx context.Context, eventTypes []string) ([]UserWebhook, error)
+
+	CreateDeliveries(ctx context.Context, deliveries []*UserWebhookDelivery) error
+	// ClaimDueDeliveries 抢占到期的 pending 投递：attempts+1，并将 next_attempt_at 后延 lease 防止其他实例重复投递
+	ClaimDueDeliveries(ctx context.Context, limit int, lease time.Duration) ([]UserWebhookDelivery, error)
+	// SaveDeliveryResult 保存投递结果，并同步更新 Webhook 的最近投递状态
+	SaveDeliveryResult(ctx context.Context, delivery *UserWebhookDelivery) error
+	// GetDelivery 不存在返回 ErrUserWebhookDeliveryNotFound
+	GetDelivery(ctx context.Context, id int64) (*UserWebhookDelivery, error)
+	ListDeliveries(ctx context.Context, webhookID int64, params pagination.PaginationParams) ([]UserWebhookDelivery, *pagination.PaginationResult, error)
+	// RequeueDelivery 将投递重置为 pending 并立即可投递（手动重投）
+	RequeueDelivery(ctx context.Context, id int64, maxAttempts int) error
+	DeleteDeliveriesBefore(ctx context.Context, before time.Time) (int64, error)
+
+	ListAlertKeys(ctx context.Context, webhookID int64) ([]string, error)
+	// AddAlertKey 记录已触发的阈值事件；已存在返回 false
+	AddAlertKey(ctx context.Context, webhookID int64, key string) (bool, error)
+	DeleteAlertKeys(ctx context.Context, webhookID int64, keys []string) error
+}
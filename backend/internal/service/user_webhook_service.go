@@ -0,0 +1,1010 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+	"unicode/utf8"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	infraerrors "github.com/Wei-Shaw/sub2api/internal/pkg/errors"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/logger"
+	"github.com/Wei-Shaw/sub2api/internal/pkg/pagination"
+)
+
+const (
+	userWebhookDeliveryWorkerName = "user_webhook_delivery"
+	userWebhookEvaluateWorkerName = "user_webhook_evaluate"
+
+	userWebhookSecretPrefix       = "whsec_"
+	userWebhookDNSLookupTimeout   = 5 * time.Second
+	userWebhookErrorLimit         = 500
+	userWebhookEmitTimeout        = 10 * time.Second
+	userWebhookEvaluateTimeout    = 2 * time.Minute
+	userWebhookExpiredLookback    = 24 * time.Hour
+	userWebhookAPIKeyScanPageSize = 1000
+)
+
+// UserWebhookService 管理用户出站 Webhook：配置 CRUD、事件入队、签名投递与失败重试。
+//
+// 事件分两类：
+//   - 即时事件（Sora 生成完成/失败、订单支付）由业务代码调用 Emit/EmitAsync 入队；
+//   - 阈值事件（余额不足、API Key 额度/限速窗口将尽、订阅即将到期/已到期）由后台定期检查，
+//     条件成立时只推送一次，条件解除后重新布防，不占用计费热路径。
+type UserWebhookService struct {
+	repo        UserWebhookRepository
+	userRepo    UserRepository
+	apiKeyRepo  APIKeyRepository
+	userSubRepo UserSubscriptionRepository
+	timingWheel *TimingWheelService
+	cfg         *config.Config
+	now         func() time.Time
+
+	delivering int32
+	evaluating int32
+	started    atomic.Bool
+	startOnce  sync.Once
+	stopOnce   sync.Once
+
+	clientOnce sync.Once
+	client     *http.Client
+
+	workerCtx    context.Context
+	workerCancel context.CancelFunc
+}
+
+// NewUserWebhookService 创建用户 Webhook 服务
+func NewUserWebhookService(
+	repo UserWebhookRepository,
+	userRepo UserRepository,
+	apiKeyRepo APIKeyRepository,
+	userSubRepo UserSubscriptionRepository,
+	timingWheel *TimingWheelService,
+	cfg *config.Config,
+) *UserWebhookService {
+	workerCtx, workerCancel := context.WithCancel(context.Background())
+	return &UserWebhookService{
+		repo:         repo,
+		userRepo:     userRepo,
+		apiKeyRepo:   apiKeyRepo,
+		userSubRepo:  userSubRepo,
+		timingWheel:  timingWheel,
+		cfg:          cfg,
+		now:          time.Now,
+		workerCtx:    workerCtx,
+		workerCancel: workerCancel,
+	}
+}
+
+// Enabled 是否启用用户 Webhook
+func (s *UserWebhookService) Enabled() bool {
+	return s != nil && s.repo != nil && s.cfg != nil && s.cfg.Webhook.Enabled
+}
+
+func (s *UserWebhookService) Start() {
+	if !s.Enabled() {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] not started (disabled)")
+		return
+	}
+	if s.timingWheel == nil {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] not started (missing deps)")
+		return
+	}
+	s.startOnce.Do(func() {
+		deliverInterval := time.Duration(s.cfg.Webhook.WorkerIntervalSeconds) * time.Second
+		evaluateInterval := time.Duration(s.cfg.Webhook.EvaluateIntervalSeconds) * time.Second
+		s.timingWheel.ScheduleRecurring(userWebhookDeliveryWorkerName, deliverInterval, s.deliverOnce)
+		s.timingWheel.ScheduleRecurring(userWebhookEvaluateWorkerName, evaluateInterval, s.evaluateOnce)
+		s.started.Store(true)
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] started (deliver_interval=%s evaluate_interval=%s max_attempts=%d)", deliverInterval, evaluateInterval, s.cfg.Webhook.MaxAttempts)
+	})
+}
+
+func (s *UserWebhookService) Stop() {
+	if s == nil {
+		return
+	}
+	s.stopOnce.Do(func() {
+		s.started.Store(false)
+		if s.workerCancel != nil {
+			s.workerCancel()
+		}
+		if s.timingWheel != nil {
+			s.timingWheel.Cancel(userWebhookDeliveryWorkerName)
+			s.timingWheel.Cancel(userWebhookEvaluateWorkerName)
+		}
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] stopped")
+	})
+}
+
+// =========================
+// Webhook 配置
+// =========================
+
+// List 列出用户的 Webhook
+func (s *UserWebhookService) List(ctx context.Context, userID int64) ([]UserWebhook, error) {
+	if !s.Enabled() {
+		return nil, ErrUserWebhookDisabled
+	}
+	return s.repo.ListByUser(ctx, userID)
+}
+
+// Get 获取用户的 Webhook（非本人返回不存在）
+func (s *UserWebhookService) Get(ctx context.Context, id, userID int64) (*UserWebhook, error) {
+	if !s.Enabled() {
+		return nil, ErrUserWebhookDisabled
+	}
+	hook, err := s.repo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if hook.UserID != userID {
+		return nil, ErrUserWebhookNotFound
+	}
+	return hook, nil
+}
+
+// Create 创建 Webhook，并生成签名密钥
+func (s *UserWebhookService) Create(ctx context.Context, userID int64, in CreateUserWebhookInput) (*UserWebhook, error) {
+	if !s.Enabled() {
+		return nil, ErrUserWebhookDisabled
+	}
+	count, err := s.repo.CountByUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if count >= int64(s.cfg.Webhook.MaxPerUser) {
+		return nil, ErrUserWebhookLimitExceeded.WithMetadata(map[string]string{"max": strconv.Itoa(s.cfg.Webhook.MaxPerUser)})
+	}
+
+	secret, err := generateUserWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	hook := &UserWebhook{
+		UserID:                userID,
+		APIKeyID:              in.APIKeyID,
+		Name:                  in.Name,
+		URL:                   in.URL,
+		Secret:                secret,
+		Events:                in.Events,
+		Enabled:               true,
+		UsagePercentThreshold: userWebhookDefaultUsagePercent,
+		ExpiryNoticeDays:      userWebhookDefaultExpiryDays,
+	}
+	if in.Enabled != nil {
+		hook.Enabled = *in.Enabled
+	}
+	if in.BalanceThreshold != nil {
+		hook.BalanceThreshold = *in.BalanceThreshold
+	}
+	if in.UsagePercentThreshold != nil {
+		hook.UsagePercentThreshold = *in.UsagePercentThreshold
+	}
+	if in.ExpiryNoticeDays != nil {
+		hook.ExpiryNoticeDays = *in.ExpiryNoticeDays
+	}
+	if err := s.normalize(ctx, hook); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Create(ctx, hook); err != nil {
+		return nil, fmt.Errorf("create webhook: %w", err)
+	}
+	logger.LegacyPrintf("service.user_webhook", "[UserWebhook] created: webhook=%d user=%d events=%s", hook.ID, userID, strings.Join(hook.Events, ","))
+	return hook, nil
+}
+
+// Update 更新 Webhook 配置
+func (s *UserWebhookService) Update(ctx context.Context, id, userID int64, in UpdateUserWebhookInput) (*UserWebhook, error) {
+	hook, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	if in.Name != nil {
+		hook.Name = *in.Name
+	}
+	if in.URL != nil {
+		hook.URL = *in.URL
+	}
+	if in.ClearAPIKey {
+		hook.APIKeyID = nil
+	} else if in.APIKeyID != nil {
+		hook.APIKeyID = in.APIKeyID
+	}
+	if in.Events != nil {
+		hook.Events = in.Events
+	}
+	if in.Enabled != nil {
+		hook.Enabled = *in.Enabled
+	}
+	if in.BalanceThreshold != nil {
+		hook.BalanceThreshold = *in.BalanceThreshold
+	}
+	if in.UsagePercentThreshold != nil {
+		hook.UsagePercentThreshold = *in.UsagePercentThreshold
+	}
+	if in.ExpiryNoticeDays != nil {
+		hook.ExpiryNoticeDays = *in.ExpiryNoticeDays
+	}
+	if err := s.normalize(ctx, hook); err != nil {
+		return nil, err
+	}
+	if err := s.repo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// Delete 删除 Webhook（投递记录级联删除）
+func (s *UserWebhookService) Delete(ctx context.Context, id, userID int64) error {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return err
+	}
+	return s.repo.Delete(ctx, id)
+}
+
+// RotateSecret 重新生成签名密钥，旧密钥立即失效
+func (s *UserWebhookService) RotateSecret(ctx context.Context, id, userID int64) (*UserWebhook, error) {
+	hook, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	secret, err := generateUserWebhookSecret()
+	if err != nil {
+		return nil, err
+	}
+	hook.Secret = secret
+	if err := s.repo.Update(ctx, hook); err != nil {
+		return nil, err
+	}
+	return hook, nil
+}
+
+// normalize 校验并规范化 Webhook 配置
+func (s *UserWebhookService) normalize(ctx context.Context, hook *UserWebhook) error {
+	hook.Name = strings.TrimSpace(hook.Name)
+	if utf8.RuneCountInString(hook.Name) > 100 {
+		return infraerrors.BadRequest("WEBHOOK_INVALID_NAME", "name must be at most 100 characters")
+	}
+
+	normalizedURL, err := validateUserWebhookURL(ctx, s.cfg, strings.TrimSpace(hook.URL))
+	if err != nil {
+		return infraerrors.BadRequest("WEBHOOK_INVALID_URL", "url: "+err.Error())
+	}
+	hook.URL = normalizedURL
+
+	events, err := normalizeUserWebhookEvents(hook.Events)
+	if err != nil {
+		return err
+	}
+	hook.Events = events
+
+	if hook.APIKeyID != nil {
+		_, ownerID, err := s.apiKeyRepo.GetKeyHashAndOwnerID(ctx, *hook.APIKeyID)
+		if err != nil {
+			if errors.Is(err, ErrAPIKeyNotFound) {
+				return ErrUserWebhookInvalidAPIKey
+			}
+			return err
+		}
+		if ownerID != hook.UserID {
+			return ErrUserWebhookInvalidAPIKey
+		}
+	}
+
+	if hook.BalanceThreshold < 0 || math.IsNaN(hook.BalanceThreshold) || math.IsInf(hook.BalanceThreshold, 0) {
+		return infraerrors.BadRequest("WEBHOOK_INVALID_THRESHOLD", "balance_threshold must be non-negative")
+	}
+	if hook.Subscribes(UserWebhookEventBalanceLow) && hook.BalanceThreshold <= 0 {
+		return infraerrors.BadRequest("WEBHOOK_INVALID_THRESHOLD", "balance_threshold must be positive when subscribing to balance.low")
+	}
+	if hook.UsagePercentThreshold < 1 || hook.UsagePercentThreshold > 100 {
+		return infraerrors.BadRequest("WEBHOOK_INVALID_THRESHOLD", "usage_threshold_percent must be between 1 and 100")
+	}
+	if hook.ExpiryNoticeDays < 1 || hook.ExpiryNoticeDays > 365 {
+		return infraerrors.BadRequest("WEBHOOK_INVALID_THRESHOLD", "expiry_notice_days must be between 1 and 365")
+	}
+	return nil
+}
+
+func normalizeUserWebhookEvents(events []string) ([]string, error) {
+	supported := make(map[string]struct{}, len(UserWebhookEvents))
+	for _, e := range UserWebhookEvents {
+		supported[e] = struct{}{}
+	}
+	seen := make(map[string]struct{}, len(events))
+	out := make([]string, 0, len(events))
+	for _, e := range events {
+		e = strings.ToLower(strings.TrimSpace(e))
+		if _, ok := supported[e]; !ok {
+			return nil, ErrUserWebhookInvalidEvents.WithMetadata(map[string]string{"event": e})
+		}
+		if _, dup := seen[e]; dup {
+			continue
+		}
+		seen[e] = struct{}{}
+		out = append(out, e)
+	}
+	if len(out) == 0 {
+		return nil, ErrUserWebhookInvalidEvents
+	}
+	return out, nil
+}
+
+func generateUserWebhookSecret() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("generate webhook secret: %w", err)
+	}
+	return userWebhookSecretPrefix + hex.EncodeToString(buf), nil
+}
+
+func generateUserWebhookEventID() string {
+	buf := make([]byte, 12)
+	if _, err := rand.Read(buf); err != nil {
+		return fmt.Sprintf("evt_%d", time.Now().UnixNano())
+	}
+	return "evt_" + hex.EncodeToString(buf)
+}
+
+// =========================
+// 投递日志
+// =========================
+
+// ListDeliveries 列出 Webhook 的投递记录（最新在前）
+func (s *UserWebhookService) ListDeliveries(ctx context.Context, id, userID int64, params pagination.PaginationParams) ([]UserWebhookDelivery, *pagination.PaginationResult, error) {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return nil, nil, err
+	}
+	return s.repo.ListDeliveries(ctx, id, params)
+}
+
+// Redeliver 手动重投一条已结束的投递（重新计算重试次数）
+func (s *UserWebhookService) Redeliver(ctx context.Context, id, deliveryID, userID int64) (*UserWebhookDelivery, error) {
+	if _, err := s.Get(ctx, id, userID); err != nil {
+		return nil, err
+	}
+	delivery, err := s.repo.GetDelivery(ctx, deliveryID)
+	if err != nil {
+		return nil, err
+	}
+	if delivery.WebhookID != id {
+		return nil, ErrUserWebhookDeliveryNotFound
+	}
+	if delivery.Status == UserWebhookDeliveryPending {
+		return nil, infraerrors.Conflict("WEBHOOK_DELIVERY_PENDING", "delivery is still pending")
+	}
+	if err := s.repo.RequeueDelivery(ctx, deliveryID, s.cfg.Webhook.MaxAttempts); err != nil {
+		return nil, err
+	}
+	s.kick()
+	return s.repo.GetDelivery(ctx, deliveryID)
+}
+
+// SendTest 向 Webhook 同步发送一条 webhook.ping 测试事件并返回投递结果（不重试）
+func (s *UserWebhookService) SendTest(ctx context.Context, id, userID int64) (*UserWebhookDelivery, error) {
+	hook, err := s.Get(ctx, id, userID)
+	if err != nil {
+		return nil, err
+	}
+	deliveries, err := s.buildDeliveries([]UserWebhook{*hook}, &UserWebhookEvent{
+		UserID: userID,
+		Type:   UserWebhookEventPing,
+		Data: map[string]any{
+			"webhook_id": hook.ID,
+			"message":    "This is a test event from sub2api.",
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+	delivery := deliveries[0]
+	// 同步投递：先占用本次尝试并后延 next_attempt_at，避免后台 worker 同时投递
+	delivery.Attempts = 1
+	delivery.MaxAttempts = 1
+	delivery.NextAttemptAt = s.now().Add(s.deliveryLease())
+	if err := s.repo.CreateDeliveries(ctx, []*UserWebhookDelivery{delivery}); err != nil {
+		return nil, fmt.Errorf("create webhook delivery: %w", err)
+	}
+	s.attempt(ctx, hook, delivery)
+	s.saveResult(delivery)
+	return delivery, nil
+}
+
+// =========================
+// 事件入队
+// =========================
+
+// Emit 将事件写入订阅者的投递队列；未启用或无订阅者时直接返回。
+func (s *UserWebhookService) Emit(ctx context.Context, event UserWebhookEvent) error {
+	if !s.Enabled() || event.UserID <= 0 {
+		return nil
+	}
+	hooks, err := s.repo.ListSubscribed(ctx, event.UserID, event.Type)
+	if err != nil {
+		return fmt.Errorf("list subscribed webhooks: %w", err)
+	}
+	targets := hooks[:0]
+	for i := range hooks {
+		if hooks[i].Accepts(&event) {
+			targets = append(targets, hooks[i])
+		}
+	}
+	if len(targets) == 0 {
+		return nil
+	}
+	return s.enqueue(ctx, targets, &event)
+}
+
+// EmitAsync 异步入队，供业务主流程调用（不阻塞、不返回错误）
+func (s *UserWebhookService) EmitAsync(event UserWebhookEvent) {
+	if !s.Enabled() {
+		return
+	}
+	go func() {
+		ctx, cancel := context.WithTimeout(context.Background(), userWebhookEmitTimeout)
+		defer cancel()
+		if err := s.Emit(ctx, event); err != nil {
+			logger.LegacyPrintf("service.user_webhook", "[UserWebhook] emit failed: user=%d event=%s err=%v", event.UserID, event.Type, err)
+		}
+	}()
+}
+
+func (s *UserWebhookService) enqueue(ctx context.Context, hooks []UserWebhook, event *UserWebhookEvent) error {
+	deliveries, err := s.buildDeliveries(hooks, event)
+	if err != nil {
+		return err
+	}
+	if err := s.repo.CreateDeliveries(ctx, deliveries); err != nil {
+		return fmt.Errorf("create webhook deliveries: %w", err)
+	}
+	s.kick()
+	return nil
+}
+
+// buildDeliveries 为每个目标 Webhook 生成一条投递；同一事件共享 event id 与请求体
+func (s *UserWebhookService) buildDeliveries(hooks []UserWebhook, event *UserWebhookEvent) ([]*UserWebhookDelivery, error) {
+	now := s.now()
+	payload := UserWebhookPayload{
+		ID:        generateUserWebhookEventID(),
+		Type:      event.Type,
+		CreatedAt: now.UTC(),
+		Data:      event.Data,
+	}
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return nil, fmt.Errorf("marshal webhook payload: %w", err)
+	}
+	deliveries := make([]*UserWebhookDelivery, 0, len(hooks))
+	for i := range hooks {
+		deliveries = append(deliveries, &UserWebhookDelivery{
+			WebhookID:     hooks[i].ID,
+			UserID:        hooks[i].UserID,
+			EventID:       payload.ID,
+			EventType:     event.Type,
+			Payload:       body,
+			Status:        UserWebhookDeliveryPending,
+			MaxAttempts:   s.cfg.Webhook.MaxAttempts,
+			NextAttemptAt: now,
+		})
+	}
+	return deliveries, nil
+}
+
+// kick 有新投递时立即触发一轮投递，而不必等待下一个轮询周期
+func (s *UserWebhookService) kick() {
+	if s.started.Load() {
+		go s.deliverOnce()
+	}
+}
+
+// =========================
+// 投递
+// =========================
+
+func (s *UserWebhookService) deliverOnce() {
+	if !s.Enabled() {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&s.delivering, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&s.delivering, 0)
+
+	ctx := s.workerCtx
+	deliveries, err := s.repo.ClaimDueDeliveries(ctx, s.cfg.Webhook.BatchSize, s.deliveryLease())
+	if err != nil {
+		if ctx.Err() == nil {
+			logger.LegacyPrintf("service.user_webhook", "[UserWebhook] claim deliveries failed: %v", err)
+		}
+		return
+	}
+	if len(deliveries) == 0 {
+		return
+	}
+
+	hooks := make(map[int64]*UserWebhook)
+	for i := range deliveries {
+		id := deliveries[i].WebhookID
+		if _, ok := hooks[id]; ok {
+			continue
+		}
+		hook, err := s.repo.GetByID(ctx, id)
+		if err != nil && !errors.Is(err, ErrUserWebhookNotFound) {
+			logger.LegacyPrintf("service.user_webhook", "[UserWebhook] load webhook failed: webhook=%d err=%v", id, err)
+		}
+		hooks[id] = hook
+	}
+
+	sem := make(chan struct{}, max(1, s.cfg.Webhook.Concurrency))
+	var wg sync.WaitGroup
+	for i := range deliveries {
+		delivery := &deliveries[i]
+		hook := hooks[delivery.WebhookID]
+		sem <- struct{}{}
+		wg.Add(1)
+		go func() {
+			defer func() {
+				<-sem
+				wg.Done()
+			}()
+			if hook == nil || !hook.Enabled {
+				delivery.Status = UserWebhookDeliveryFailed
+				delivery.LastError = "webhook deleted or disabled"
+			} else {
+				s.attempt(ctx, hook, delivery)
+			}
+			s.saveResult(delivery)
+		}()
+	}
+	wg.Wait()
+}
+
+// attempt 执行一次投递尝试，并根据结果设置状态与下次重试时间
+func (s *UserWebhookService) attempt(ctx context.Context, hook *UserWebhook, delivery *UserWebhookDelivery) {
+	start := time.Now()
+	status, err := s.post(ctx, hook, delivery)
+	delivery.DurationMs = time.Since(start).Milliseconds()
+	delivery.ResponseStatus = status
+	if err == nil {
+		now := s.now()
+		delivery.Status = UserWebhookDeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+		return
+	}
+
+	delivery.LastError = truncateUserWebhookText(err.Error(), userWebhookErrorLimit)
+	if delivery.Attempts < delivery.MaxAttempts {
+		delivery.Status = UserWebhookDeliveryPending
+		delivery.NextAttemptAt = s.now().Add(s.retryBackoff(delivery.Attempts))
+		return
+	}
+	delivery.Status = UserWebhookDeliveryFailed
+	logger.LegacyPrintf("service.user_webhook", "[UserWebhook] delivery failed permanently: delivery=%d webhook=%d event=%s attempts=%d err=%s",
+		delivery.ID, delivery.WebhookID, delivery.EventType, delivery.Attempts, delivery.LastError)
+}
+
+// post 发送签名请求：X-Sub2API-Signature = "sha256=" + hex(HMAC-SHA256(secret, timestamp + "." + body))，
+// 与运维告警 Webhook 使用同一签名方案。
+// 只返回状态码，不读取响应体：接收方响应对用户可见，避免 Webhook 被用作探测内部服务的回显通道。
+func (s *UserWebhookService) post(ctx context.Context, hook *UserWebhook, delivery *UserWebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, hook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, err
+	}
+	ts := strconv.FormatInt(s.now().Unix(), 10)
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "sub2api-webhook/1.0")
+	req.Header.Set(OpsAlertWebhookEventHeader, delivery.EventType)
+	req.Header.Set(OpsAlertWebhookTimestampHeader, ts)
+	req.Header.Set(OpsAlertWebhookSignatureHeader, "sha256="+signOpsAlertWebhook(hook.Secret, ts, delivery.Payload))
+	req.Header.Set(UserWebhookDeliveryHeader, strconv.FormatInt(delivery.ID, 10))
+
+	resp, err := s.httpClient().Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer func() { _ = resp.Body.Close() }()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 4096))
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return resp.StatusCode, fmt.Errorf("unexpected status %d", resp.StatusCode)
+	}
+	return resp.StatusCode, nil
+}
+
+// httpClient 用户 Webhook 专用客户端：不走代理，拨号时校验实际连接的 IP，
+// 因此校验通过后域名被重新解析到内网（DNS Rebinding）或重定向到内网地址都会被拒绝。
+// 该限制与全局 URL allowlist 配置无关，用户配置的目标始终不允许访问内网。
+func (s *UserWebhookService) httpClient() *http.Client {
+	s.clientOnce.Do(func() {
+		dialer := &net.Dialer{
+			Timeout:   10 * time.Second,
+			KeepAlive: 30 * time.Second,
+			Control: func(_, address string, _ syscall.RawConn) error {
+				host, _, err := net.SplitHostPort(address)
+				if err != nil {
+					return err
+				}
+				return userWebhookCheckIP(net.ParseIP(host))
+			},
+		}
+		s.client = &http.Client{
+			Timeout: time.Duration(s.cfg.Webhook.RequestTimeoutSeconds) * time.Second,
+			Transport: &http.Transport{
+				DialContext:         dialer.DialContext,
+				MaxIdleConns:        100,
+				MaxIdleConnsPerHost: 4,
+				IdleConnTimeout:     90 * time.Second,
+				TLSHandshakeTimeout: 10 * time.Second,
+			},
+		}
+	})
+	return s.client
+}
+
+func (s *UserWebhookService) saveResult(delivery *UserWebhookDelivery) {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if err := s.repo.SaveDeliveryResult(ctx, delivery); err != nil {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] save delivery result failed: delivery=%d err=%v", delivery.ID, err)
+	}
+}
+
+// deliveryLease 投递中记录的占用时长，超过后视为实例崩溃，由其他实例重新投递
+func (s *UserWebhookService) deliveryLease() time.Duration {
+	return time.Duration(s.cfg.Webhook.RequestTimeoutSeconds)*time.Second + time.Minute
+}
+
+func (s *UserWebhookService) retryBackoff(attempt int) time.Duration {
+	base := time.Duration(s.cfg.Webhook.RetryBackoffBaseSeconds) * time.Second
+	if base <= 0 {
+		base = 30 * time.Second
+	}
+	maxDelay := time.Duration(s.cfg.Webhook.RetryBackoffMaxSeconds) * time.Second
+	if maxDelay < base {
+		maxDelay = base
+	}
+	delay := base
+	for i := 1; i < attempt && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if delay > maxDelay {
+		delay = maxDelay
+	}
+	return delay
+}
+
+// userWebhookCheckIP 校验用户 Webhook 实际连接的 IP；单测中替换以允许本地 httptest 服务。
+var userWebhookCheckIP = checkUserWebhookIP
+
+// userWebhookBlockedCIDRs 用户 Webhook 禁止访问的地址段：回环、私网、链路本地（含 169.254.169.254 云元数据）、
+// 运营商级 NAT（部分云厂商元数据位于 100.100.100.200）、组播与保留地址
+var userWebhookBlockedCIDRs = mustParseCIDRs([]string{
+	"0.0.0.0/8",
+	"10.0.0.0/8",
+	"100.64.0.0/10",
+	"127.0.0.0/8",
+	"169.254.0.0/16",
+	"172.16.0.0/12",
+	"192.0.0.0/24",
+	"192.168.0.0/16",
+	"198.18.0.0/15",
+	"224.0.0.0/4",
+	"240.0.0.0/4",
+	"::/128",
+	"::1/128",
+	"fc00::/7",
+	"fe80::/10",
+	"ff00::/8",
+})
+
+func checkUserWebhookIP(ip net.IP) error {
+	if ip == nil {
+		return errors.New("webhook target ip is invalid")
+	}
+	for _, cidr := range userWebhookBlockedCIDRs {
+		if cidr.Contains(ip) {
+			return fmt.Errorf("webhook target ip %s is not allowed", ip.String())
+		}
+	}
+	return nil
+}
+
+// validateUserWebhookURL 校验用户 Webhook 地址：先复用告警 Webhook 的格式/allowlist 校验，
+// 再无条件拒绝 localhost、内网字面量 IP 以及解析到内网的域名（不受 allow_private_hosts 影响）。
+// 解析失败时放行，实际投递时由拨号阶段的 IP 校验兜底。
+func validateUserWebhookURL(ctx context.Context, cfg *config.Config, raw string) (string, error) {
+	normalized, err := validateOpsAlertNotifyURL(cfg, raw)
+	if err != nil {
+		return "", err
+	}
+	parsed, err := url.Parse(normalized)
+	if err != nil {
+		return "", err
+	}
+	host := strings.ToLower(strings.TrimSuffix(parsed.Hostname(), "."))
+	if host == "localhost" || strings.HasSuffix(host, ".localhost") {
+		return "", fmt.Errorf("host is not allowed: %s", host)
+	}
+	if ip := net.ParseIP(host); ip != nil {
+		if err := checkUserWebhookIP(ip); err != nil {
+			return "", err
+		}
+		return normalized, nil
+	}
+
+	lookupCtx, cancel := context.WithTimeout(ctx, userWebhookDNSLookupTimeout)
+	defer cancel()
+	ips, err := net.DefaultResolver.LookupIP(lookupCtx, "ip", host)
+	if err != nil {
+		return normalized, nil
+	}
+	for _, ip := range ips {
+		if err := checkUserWebhookIP(ip); err != nil {
+			return "", err
+		}
+	}
+	return normalized, nil
+}
+
+func truncateUserWebhookText(text string, limit int) string {
+	text = strings.ToValidUTF8(strings.TrimSpace(text), "")
+	if len(text) <= limit {
+		return text
+	}
+	cut := limit
+	for cut > 0 && !utf8.RuneStart(text[cut]) {
+		cut--
+	}
+	return text[:cut]
+}
+
+// =========================
+// 阈值事件检查
+// =========================
+
+// userWebhookSnapshot 单个用户在一次检查中使用的数据快照（同一用户的多个 Webhook 共用）
+type userWebhookSnapshot struct {
+	user          *User
+	apiKeys       []APIKey
+	subscriptions []UserSubscription
+}
+
+// userWebhookAlert 当前成立的阈值条件；key 用于去重，同一 key 在条件解除前只推送一次
+type userWebhookAlert struct {
+	key   string
+	event UserWebhookEvent
+}
+
+func (s *UserWebhookService) evaluateOnce() {
+	if !s.Enabled() {
+		return
+	}
+	if !atomic.CompareAndSwapInt32(&s.evaluating, 0, 1) {
+		return
+	}
+	defer atomic.StoreInt32(&s.evaluating, 0)
+
+	ctx, cancel := context.WithTimeout(s.workerCtx, userWebhookEvaluateTimeout)
+	defer cancel()
+
+	if deleted, err := s.repo.DeleteDeliveriesBefore(ctx, s.now().AddDate(0, 0, -s.cfg.Webhook.RetentionDays)); err != nil {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] cleanup deliveries failed: %v", err)
+	} else if deleted > 0 {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] cleaned up %d expired deliveries", deleted)
+	}
+
+	hooks, err := s.repo.ListSubscribedToAny(ctx, userWebhookThresholdEvents)
+	if err != nil {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] list threshold webhooks failed: %v", err)
+		return
+	}
+	snapshots := make(map[int64]*userWebhookSnapshot)
+	for i := range hooks {
+		if ctx.Err() != nil {
+			return
+		}
+		hook := &hooks[i]
+		snap, ok := snapshots[hook.UserID]
+		if !ok {
+			snap = s.loadSnapshot(ctx, hook.UserID)
+			snapshots[hook.UserID] = snap
+		}
+		if snap == nil {
+			continue
+		}
+		s.evaluateWebhook(ctx, hook, userWebhookAlerts(hook, snap, s.now()))
+	}
+}
+
+func (s *UserWebhookService) loadSnapshot(ctx context.Context, userID int64) *userWebhookSnapshot {
+	user, err := s.userRepo.GetByID(ctx, userID)
+	if err != nil {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] load user failed: user=%d err=%v", userID, err)
+		return nil
+	}
+	snap := &userWebhookSnapshot{user: user}
+	keys, _, err := s.apiKeyRepo.ListByUserID(ctx, userID, pagination.PaginationParams{Page: 1, PageSize: userWebhookAPIKeyScanPageSize}, APIKeyListFilters{})
+	if err != nil {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] load api keys failed: user=%d err=%v", userID, err)
+		return nil
+	}
+	snap.apiKeys = keys
+	subs, err := s.userSubRepo.ListByUserID(ctx, userID)
+	if err != nil {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] load subscriptions failed: user=%d err=%v", userID, err)
+		return nil
+	}
+	snap.subscriptions = subs
+	return snap
+}
+
+// evaluateWebhook 对比当前成立的条件与已触发记录：新成立的推送一次，已解除的删除记录以便重新触发
+func (s *UserWebhookService) evaluateWebhook(ctx context.Context, hook *UserWebhook, alerts []userWebhookAlert) {
+	existing, err := s.repo.ListAlertKeys(ctx, hook.ID)
+	if err != nil {
+		logger.LegacyPrintf("service.user_webhook", "[UserWebhook] list alert states failed: webhook=%d err=%v", hook.ID, err)
+		return
+	}
+	active := make(map[string]struct{}, len(alerts))
+	for i := range alerts {
+		alert := &alerts[i]
+		active[alert.key] = struct{}{}
+		inserted, err := s.repo.AddAlertKey(ctx, hook.ID, alert.key)
+		if err != nil {
+			logger.LegacyPrintf("service.user_webhook", "[UserWebhook] save alert state failed: webhook=%d key=%s err=%v", hook.ID, alert.key, err)
+			continue
+		}
+		if !inserted {
+			continue
+		}
+		if err := s.enqueue(ctx, []UserWebhook{*hook}, &alert.event); err != nil {
+			logger.LegacyPrintf("service.user_webhook", "[UserWebhook] enqueue alert failed: webhook=%d key=%s err=%v", hook.ID, alert.key, err)
+			// 入队失败时撤销触发记录，下一轮重试
+			_ = s.repo.DeleteAlertKeys(ctx, hook.ID, []string{alert.key})
+		}
+	}
+
+	var resolved []string
+	for _, key := range existing {
+		if _, ok := active[key]; !ok {
+			resolved = append(resolved, key)
+		}
+	}
+	if len(resolved) > 0 {
+		if err := s.repo.DeleteAlertKeys(ctx, hook.ID, resolved); err != nil {
+			logger.LegacyPrintf("service.user_webhook", "[UserWebhook] clear alert states failed: webhook=%d err=%v", hook.ID, err)
+		}
+	}
+}
+
+// userWebhookAlerts 计算 Webhook 当前成立的阈值条件
+func userWebhookAlerts(hook *UserWebhook, snap *userWebhookSnapshot, now time.Time) []userWebhookAlert {
+	var alerts []userWebhookAlert
+	userID := hook.UserID
+	// 余额与订阅属于用户级事件，仅推送给用户级 Webhook
+	userLevel := hook.APIKeyID == nil
+	ratio := float64(hook.UsagePercentThreshold) / 100
+
+	if userLevel && hook.Subscribes(UserWebhookEventBalanceLow) && hook.BalanceThreshold > 0 &&
+		snap.user != nil && snap.user.Balance < hook.BalanceThreshold {
+		alerts = append(alerts, userWebhookAlert{
+			key: UserWebhookEventBalanceLow,
+			event: UserWebhookEvent{UserID: userID, Type: UserWebhookEventBalanceLow, Data: map[string]any{
+				"balance":   snap.user.Balance,
+				"threshold": hook.BalanceThreshold,
+			}},
+		})
+	}
+
+	for i := range snap.apiKeys {
+		key := &snap.apiKeys[i]
+		if hook.APIKeyID != nil && *hook.APIKeyID != key.ID {
+			continue
+		}
+		keyID := key.ID
+		if hook.Subscribes(UserWebhookEventAPIKeyQuotaLow) && key.Quota > 0 && key.QuotaUsed >= key.Quota*ratio {
+			alerts = append(alerts, userWebhookAlert{
+				key: fmt.Sprintf("%s:%d", UserWebhookEventAPIKeyQuotaLow, key.ID),
+				event: UserWebhookEvent{UserID: userID, APIKeyID: &keyID, Type: UserWebhookEventAPIKeyQuotaLow, Data: map[string]any{
+					"api_key_id":        key.ID,
+					"api_key_name":      key.Name,
+					"quota":             key.Quota,
+					"quota_used":        key.QuotaUsed,
+					"used_percent":      userWebhookPercent(key.QuotaUsed, key.Quota),
+					"threshold_percent": hook.UsagePercentThreshold,
+				}},
+			})
+		}
+		if !hook.Subscribes(UserWebhookEventAPIKeyRateLimitLow) {
+			continue
+		}
+		for _, w := range []struct {
+			name     string
+			limit    float64
+			usage    float64
+			start    *time.Time
+			duration time.Duration
+		}{
+			{"5h", key.RateLimit5h, key.Usage5h, key.Window5hStart, RateLimitWindow5h},
+			{"1d", key.RateLimit1d, key.Usage1d, key.Window1dStart, RateLimitWindow1d},
+			{"7d", key.RateLimit7d, key.Usage7d, key.Window7dStart, RateLimitWindow7d},
+		} {
+			if w.limit <= 0 || w.start == nil || now.Sub(*w.start) >= w.duration || w.usage < w.limit*ratio {
+				continue
+			}
+			alerts = append(alerts, userWebhookAlert{
+				// 带上窗口起点：每个窗口只推送一次，窗口重置后自然重新布防
+				key: fmt.Sprintf("%s:%d:%s:%d", UserWebhookEventAPIKeyRateLimitLow, key.ID, w.name, w.start.Unix()),
+				event: UserWebhookEvent{UserID: userID, APIKeyID: &keyID, Type: UserWebhookEventAPIKeyRateLimitLow, Data: map[string]any{
+					"api_key_id":        key.ID,
+					"api_key_name":      key.Name,
+					"window":            w.name,
+					"limit":             w.limit,
+					"usage":             w.usage,
+					"used_percent":      userWebhookPercent(w.usage, w.limit),
+					"threshold_percent": hook.UsagePercentThreshold,
+					"window_start":      w.start.UTC(),
+					"window_resets_at":  w.start.Add(w.duration).UTC(),
+				}},
+			})
+		}
+	}
+
+	if !userLevel {
+		return alerts
+	}
+	for i := range snap.subscriptions {
+		sub := &snap.subscriptions[i]
+		if sub.Status != SubscriptionStatusActive && sub.Status != SubscriptionStatusExpired {
+			continue
+		}
+		data := map[string]any{
+			"subscription_id": sub.ID,
+			"group_id":        sub.GroupID,
+			"expires_at":      sub.ExpiresAt.UTC(),
+		}
+		if sub.Group != nil {
+			data["group_name"] = sub.Group.Name
+		}
+		remaining := sub.ExpiresAt.Sub(now)
+		switch {
+		case remaining > 0 && sub.Status == SubscriptionStatusActive && hook.Subscribes(UserWebhookEventSubscriptionExpiring) &&
+			remaining <= time.Duration(hook.ExpiryNoticeDays)*24*time.Hour:
+			data["days_left"] = int(math.Ceil(remaining.Hours() / 24))
+			alerts = append(alerts, userWebhookAlert{
+				key:   fmt.Sprintf("%s:%d:%d", UserWebhookEventSubscriptionExpiring, sub.ID, sub.ExpiresAt.Unix()),
+				event: UserWebhookEvent{UserID: userID, Type: UserWebhookEventSubscriptionExpiring, Data: data},
+			})
+		case remaining <= 0 && -remaining < userWebhookExpiredLookback && hook.Subscribes(UserWebhookEventSubscriptionExpired):
+			alerts = append(alerts, userWebhookAlert{
+				key:   fmt.Sprintf("%s:%d:%d", UserWebhookEventSubscriptionExpired, sub.ID, sub.ExpiresAt.Unix()),
+				event: UserWebhookEvent{UserID: userID, Type: UserWebhookEventSubscriptionExpired, Data: data},
+			})
+		}
+	}
+	return alerts
+}
+
+func userWebhookPercent(used, limit float64) float64 {
+	if limit <= 0 {
+		return 0
+	}
+	return math.Round(used/limit*10000) / 100
+}
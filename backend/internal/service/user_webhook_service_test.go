@@ -0,0 +1,490 @@
+//go:build unit
+
+package service
+
+import (
+	"context"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/Wei-Shaw/sub2api/internal/config"
+	"github.com/stretchr/testify/require"
+)
+
+type userWebhookRepoStub struct {
+	UserWebhookRepository
+
+	mu         sync.Mutex
+	hooks      map[int64]*UserWebhook
+	deliveries []*UserWebhookDelivery
+	saved      []UserWebhookDelivery
+	alertKeys  map[int64]map[string]struct{}
+	nextID     int64
+}
+
+func newUserWebhookRepoStub(hooks ...*UserWebhook) *userWebhookRepoStub {
+	r := &userWebhookRepoStub{hooks: map[int64]*UserWebhook{}, alertKeys: map[int64]map[string]struct{}{}}
+	for _, h := range hooks {
+		r.hooks[h.ID] = h
+	}
+	return r
+}
+
+func (r *userWebhookRepoStub) GetByID(_ context.Context, id int64) (*UserWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	h, ok := r.hooks[id]
+	if !ok {
+		return nil, ErrUserWebhookNotFound
+	}
+	cp := *h
+	return &cp, nil
+}
+
+func (r *userWebhookRepoStub) ListSubscribed(_ context.Context, userID int64, eventType string) ([]UserWebhook, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []UserWebhook
+	for _, h := range r.hooks {
+		if h.UserID == userID && h.Enabled && h.Subscribes(eventType) {
+			out = append(out, *h)
+		}
+	}
+	return out, nil
+}
+
+func (r *userWebhookRepoStub) CreateDeliveries(_ context.Context, deliveries []*UserWebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, d := range deliveries {
+		r.nextID++
+		d.ID = r.nextID
+		r.deliveries = append(r.deliveries, d)
+	}
+	return nil
+}
+
+func (r *userWebhookRepoStub) SaveDeliveryResult(_ context.Context, delivery *UserWebhookDelivery) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.saved = append(r.saved, *delivery)
+	return nil
+}
+
+func (r *userWebhookRepoStub) ListAlertKeys(_ context.Context, webhookID int64) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	var out []string
+	for k := range r.alertKeys[webhookID] {
+		out = append(out, k)
+	}
+	return out, nil
+}
+
+func (r *userWebhookRepoStub) AddAlertKey(_ context.Context, webhookID int64, key string) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.alertKeys[webhookID] == nil {
+		r.alertKeys[webhookID] = map[string]struct{}{}
+	}
+	if _, ok := r.alertKeys[webhookID][key]; ok {
+		return false, nil
+	}
+	r.alertKeys[webhookID][key] = struct{}{}
+	return true, nil
+}
+
+func (r *userWebhookRepoStub) DeleteAlertKeys(_ context.Context, webhookID int64, keys []string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, k := range keys {
+		delete(r.alertKeys[webhookID], k)
+	}
+	return nil
+}
+
+func newUserWebhookTestConfig() *config.Config {
+	cfg := &config.Config{}
+	cfg.Webhook = config.WebhookConfig{
+		Enabled:                 true,
+		MaxPerUser:              10,
+		BatchSize:               50,
+		Concurrency:             4,
+		RequestTimeoutSeconds:   5,
+		MaxAttempts:             3,
+		RetryBackoffBaseSeconds: 30,
+		RetryBackoffMaxSeconds:  300,
+		RetentionDays:           14,
+	}
+	return cfg
+}
+
+func newUserWebhookTestService(repo UserWebhookRepository, now time.Time) *UserWebhookService {
+	svc := NewUserWebhookService(repo, nil, nil, nil, nil, newUserWebhookTestConfig())
+	svc.now = func() time.Time { return now }
+	return svc
+}
+
+// allowUserWebhookLoopback 放行 httptest 本地服务（生产环境下用户 Webhook 禁止访问回环地址）
+func allowUserWebhookLoopback(t *testing.T) {
+	t.Helper()
+	orig := userWebhookCheckIP
+	userWebhookCheckIP = func(ip net.IP) error {
+		if ip.IsLoopback() {
+			return nil
+		}
+		return orig(ip)
+	}
+	t.Cleanup(func() { userWebhookCheckIP = orig })
+}
+
+func alertKeysOf(alerts []userWebhookAlert) []string {
+	keys := make([]string, 0, len(alerts))
+	for _, a := range alerts {
+		keys = append(keys, a.key)
+	}
+	return keys
+}
+
+func TestNormalizeUserWebhookEvents(t *testing.T) {
+	events, err := normalizeUserWebhookEvents([]string{" balance.low ", "order.paid", "balance.low"})
+	require.NoError(t, err)
+	require.Equal(t, []string{UserWebhookEventBalanceLow, UserWebhookEventOrderPaid}, events)
+
+	_, err = normalizeUserWebhookEvents(nil)
+	require.ErrorIs(t, err, ErrUserWebhookInvalidEvents)
+
+	_, err = normalizeUserWebhookEvents([]string{"unknown.event"})
+	require.ErrorIs(t, err, ErrUserWebhookInvalidEvents)
+
+	// ping 仅用于测试投递，不可订阅
+	_, err = normalizeUserWebhookEvents([]string{UserWebhookEventPing})
+	require.ErrorIs(t, err, ErrUserWebhookInvalidEvents)
+}
+
+func TestUserWebhookAccepts_APIKeyScope(t *testing.T) {
+	keyID, otherKeyID := int64(7), int64(8)
+	userHook := &UserWebhook{Enabled: true, Events: []string{UserWebhookEventSoraGenerationCompleted}}
+	keyHook := &UserWebhook{Enabled: true, APIKeyID: &keyID, Events: []string{UserWebhookEventSoraGenerationCompleted}}
+
+	event := &UserWebhookEvent{Type: UserWebhookEventSoraGenerationCompleted, APIKeyID: &keyID}
+	require.True(t, userHook.Accepts(event))
+	require.True(t, keyHook.Accepts(event))
+
+	require.False(t, keyHook.Accepts(&UserWebhookEvent{Type: UserWebhookEventSoraGenerationCompleted, APIKeyID: &otherKeyID}))
+	require.False(t, keyHook.Accepts(&UserWebhookEvent{Type: UserWebhookEventSoraGenerationCompleted}))
+	require.False(t, userHook.Accepts(&UserWebhookEvent{Type: UserWebhookEventOrderPaid}))
+
+	userHook.Enabled = false
+	require.False(t, userHook.Accepts(event))
+}
+
+func TestUserWebhookService_RetryBackoff(t *testing.T) {
+	svc := newUserWebhookTestService(newUserWebhookRepoStub(), time.Now())
+	require.Equal(t, 30*time.Second, svc.retryBackoff(1))
+	require.Equal(t, 60*time.Second, svc.retryBackoff(2))
+	require.Equal(t, 120*time.Second, svc.retryBackoff(3))
+	require.Equal(t, 240*time.Second, svc.retryBackoff(4))
+	require.Equal(t, 300*time.Second, svc.retryBackoff(5))
+	require.Equal(t, 300*time.Second, svc.retryBackoff(20))
+}
+
+func TestUserWebhookAlerts_BalanceAndQuota(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	hook := &UserWebhook{
+		ID:                    1,
+		UserID:                42,
+		Enabled:               true,
+		Events:                []string{UserWebhookEventBalanceLow, UserWebhookEventAPIKeyQuotaLow},
+		BalanceThreshold:      5,
+		UsagePercentThreshold: 80,
+	}
+	snap := &userWebhookSnapshot{
+		user: &User{ID: 42, Balance: 3},
+		apiKeys: []APIKey{
+			{ID: 1, Name: "low", Quota: 10, QuotaUsed: 8},
+			{ID: 2, Name: "fine", Quota: 10, QuotaUsed: 7.9},
+			{ID: 3, Name: "unlimited", Quota: 0, QuotaUsed: 100},
+		},
+	}
+
+	alerts := userWebhookAlerts(hook, snap, now)
+	require.Equal(t, []string{"balance.low", "api_key.quota_low:1"}, alertKeysOf(alerts))
+	require.Equal(t, int64(1), *alerts[1].event.APIKeyID)
+	require.Equal(t, float64(80), alerts[1].event.Data.(map[string]any)["used_percent"])
+
+	snap.user.Balance = 5
+	require.Equal(t, []string{"api_key.quota_low:1"}, alertKeysOf(userWebhookAlerts(hook, snap, now)))
+}
+
+func TestUserWebhookAlerts_KeyScopedSkipsUserLevelEvents(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	keyID := int64(2)
+	hook := &UserWebhook{
+		ID:                    1,
+		UserID:                42,
+		APIKeyID:              &keyID,
+		Enabled:               true,
+		Events:                []string{UserWebhookEventBalanceLow, UserWebhookEventAPIKeyQuotaLow, UserWebhookEventSubscriptionExpiring},
+		BalanceThreshold:      5,
+		UsagePercentThreshold: 50,
+		ExpiryNoticeDays:      3,
+	}
+	snap := &userWebhookSnapshot{
+		user: &User{ID: 42, Balance: 1},
+		apiKeys: []APIKey{
+			{ID: 1, Quota: 10, QuotaUsed: 9},
+			{ID: 2, Quota: 10, QuotaUsed: 9},
+		},
+		subscriptions: []UserSubscription{
+			{ID: 9, Status: SubscriptionStatusActive, ExpiresAt: now.Add(24 * time.Hour)},
+		},
+	}
+
+	require.Equal(t, []string{"api_key.quota_low:2"}, alertKeysOf(userWebhookAlerts(hook, snap, now)))
+}
+
+func TestUserWebhookAlerts_RateLimitWindows(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	current := now.Add(-time.Hour)
+	expired := now.Add(-6 * time.Hour)
+	hook := &UserWebhook{
+		ID:                    1,
+		UserID:                42,
+		Enabled:               true,
+		Events:                []string{UserWebhookEventAPIKeyRateLimitLow},
+		UsagePercentThreshold: 90,
+	}
+	snap := &userWebhookSnapshot{
+		user: &User{ID: 42},
+		apiKeys: []APIKey{{
+			ID:            5,
+			RateLimit5h:   10,
+			Usage5h:       9.5,
+			Window5hStart: &current,
+			RateLimit1d:   100,
+			Usage1d:       50,
+			Window1dStart: &current,
+		}},
+	}
+
+	alerts := userWebhookAlerts(hook, snap, now)
+	require.Len(t, alerts, 1)
+	require.Equal(t, "api_key.rate_limit_low:5:5h:"+strconv.FormatInt(current.Unix(), 10), alerts[0].key)
+	require.Equal(t, "5h", alerts[0].event.Data.(map[string]any)["window"])
+
+	// 窗口已过期（用量即将被重置）不触发
+	snap.apiKeys[0].Window5hStart = &expired
+	require.Empty(t, userWebhookAlerts(hook, snap, now))
+}
+
+func TestUserWebhookAlerts_Subscriptions(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	hook := &UserWebhook{
+		ID:               1,
+		UserID:           42,
+		Enabled:          true,
+		Events:           []string{UserWebhookEventSubscriptionExpiring, UserWebhookEventSubscriptionExpired},
+		ExpiryNoticeDays: 3,
+	}
+	soon := now.Add(36 * time.Hour)
+	later := now.Add(10 * 24 * time.Hour)
+	justExpired := now.Add(-time.Hour)
+	longExpired := now.Add(-72 * time.Hour)
+	snap := &userWebhookSnapshot{
+		user: &User{ID: 42},
+		subscriptions: []UserSubscription{
+			{ID: 1, GroupID: 10, Status: SubscriptionStatusActive, ExpiresAt: soon, Group: &Group{Name: "pro"}},
+			{ID: 2, GroupID: 10, Status: SubscriptionStatusActive, ExpiresAt: later},
+			{ID: 3, GroupID: 11, Status: SubscriptionStatusExpired, ExpiresAt: justExpired},
+			{ID: 4, GroupID: 11, Status: SubscriptionStatusExpired, ExpiresAt: longExpired},
+			{ID: 5, GroupID: 12, Status: SubscriptionStatusSuspended, ExpiresAt: soon},
+		},
+	}
+
+	alerts := userWebhookAlerts(hook, snap, now)
+	require.Equal(t, []string{
+		"subscription.expiring:1:" + strconv.FormatInt(soon.Unix(), 10),
+		"subscription.expired:3:" + strconv.FormatInt(justExpired.Unix(), 10),
+	}, alertKeysOf(alerts))
+	data := alerts[0].event.Data.(map[string]any)
+	require.Equal(t, 2, data["days_left"])
+	require.Equal(t, "pro", data["group_name"])
+}
+
+func TestUserWebhookService_EvaluateWebhookDeduplicates(t *testing.T) {
+	now := time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)
+	hook := &UserWebhook{ID: 1, UserID: 42, Enabled: true, Events: []string{UserWebhookEventBalanceLow}, BalanceThreshold: 5}
+	repo := newUserWebhookRepoStub(hook)
+	svc := newUserWebhookTestService(repo, now)
+	ctx := context.Background()
+
+	low := &userWebhookSnapshot{user: &User{ID: 42, Balance: 1}}
+	svc.evaluateWebhook(ctx, hook, userWebhookAlerts(hook, low, now))
+	svc.evaluateWebhook(ctx, hook, userWebhookAlerts(hook, low, now))
+	require.Len(t, repo.deliveries, 1)
+	require.Equal(t, UserWebhookEventBalanceLow, repo.deliveries[0].EventType)
+	require.Equal(t, 3, repo.deliveries[0].MaxAttempts)
+
+	// 条件解除后清除触发记录，再次低于阈值时重新推送
+	ok := &userWebhookSnapshot{user: &User{ID: 42, Balance: 10}}
+	svc.evaluateWebhook(ctx, hook, userWebhookAlerts(hook, ok, now))
+	keys, _ := repo.ListAlertKeys(ctx, hook.ID)
+	require.Empty(t, keys)
+
+	svc.evaluateWebhook(ctx, hook, userWebhookAlerts(hook, low, now))
+	require.Len(t, repo.deliveries, 2)
+}
+
+func TestUserWebhookService_EmitFiltersByScope(t *testing.T) {
+	keyID, otherKeyID := int64(7), int64(8)
+	repo := newUserWebhookRepoStub(
+		&UserWebhook{ID: 1, UserID: 42, Enabled: true, Events: []string{UserWebhookEventSoraGenerationCompleted}},
+		&UserWebhook{ID: 2, UserID: 42, APIKeyID: &keyID, Enabled: true, Events: []string{UserWebhookEventSoraGenerationCompleted}},
+		&UserWebhook{ID: 3, UserID: 42, APIKeyID: &otherKeyID, Enabled: true, Events: []string{UserWebhookEventSoraGenerationCompleted}},
+		&UserWebhook{ID: 4, UserID: 43, Enabled: true, Events: []string{UserWebhookEventSoraGenerationCompleted}},
+	)
+	svc := newUserWebhookTestService(repo, time.Now())
+
+	err := svc.Emit(context.Background(), UserWebhookEvent{
+		UserID:   42,
+		APIKeyID: &keyID,
+		Type:     UserWebhookEventSoraGenerationCompleted,
+		Data:     map[string]any{"generation_id": 1},
+	})
+	require.NoError(t, err)
+	require.Len(t, repo.deliveries, 2)
+
+	targets := map[int64]bool{}
+	for _, d := range repo.deliveries {
+		targets[d.WebhookID] = true
+		require.Equal(t, repo.deliveries[0].EventID, d.EventID)
+	}
+	require.Equal(t, map[int64]bool{1: true, 2: true}, targets)
+}
+
+func TestUserWebhookService_SendTestSignsRequest(t *testing.T) {
+	var (
+		gotBody      []byte
+		gotHeaders   http.Header
+		receivedOnce sync.Once
+	)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		receivedOnce.Do(func() {
+			gotBody, _ = io.ReadAll(r.Body)
+			gotHeaders = r.Header.Clone()
+		})
+		_, _ = w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+	allowUserWebhookLoopback(t)
+
+	now := time.Unix(1760000000, 0)
+	hook := &UserWebhook{ID: 1, UserID: 42, URL: server.URL, Secret: "whsec_test", Enabled: true, Events: []string{UserWebhookEventOrderPaid}}
+	repo := newUserWebhookRepoStub(hook)
+	svc := newUserWebhookTestService(repo, now)
+
+	delivery, err := svc.SendTest(context.Background(), hook.ID, hook.UserID)
+	require.NoError(t, err)
+	require.Equal(t, UserWebhookDeliverySucceeded, delivery.Status)
+	require.Equal(t, http.StatusOK, delivery.ResponseStatus)
+	require.Len(t, repo.saved, 1)
+
+	var payload UserWebhookPayload
+	require.NoError(t, json.Unmarshal(gotBody, &payload))
+	require.Equal(t, UserWebhookEventPing, payload.Type)
+	require.True(t, strings.HasPrefix(payload.ID, "evt_"))
+
+	ts := gotHeaders.Get(OpsAlertWebhookTimestampHeader)
+	require.Equal(t, "1760000000", ts)
+	require.Equal(t, UserWebhookEventPing, gotHeaders.Get(OpsAlertWebhookEventHeader))
+	require.Equal(t, strconv.FormatInt(delivery.ID, 10), gotHeaders.Get(UserWebhookDeliveryHeader))
+	require.Equal(t, "sha256="+signOpsAlertWebhook("whsec_test", ts, gotBody), gotHeaders.Get(OpsAlertWebhookSignatureHeader))
+
+	// 其他用户的 Webhook 不可见
+	_, err = svc.SendTest(context.Background(), hook.ID, 43)
+	require.ErrorIs(t, err, ErrUserWebhookNotFound)
+}
+
+func TestUserWebhookService_AttemptRetriesThenFails(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		w.WriteHeader(http.StatusBadGateway)
+		_, _ = w.Write([]byte("upstream down"))
+	}))
+	defer server.Close()
+	allowUserWebhookLoopback(t)
+
+	now := time.Unix(1760000000, 0)
+	svc := newUserWebhookTestService(newUserWebhookRepoStub(), now)
+	hook := &UserWebhook{ID: 1, URL: server.URL, Secret: "s", Enabled: true}
+	delivery := &UserWebhookDelivery{ID: 1, WebhookID: 1, EventType: UserWebhookEventOrderPaid, Payload: []byte(`{}`), Attempts: 1, MaxAttempts: 2}
+
+	svc.attempt(context.Background(), hook, delivery)
+	require.Equal(t, UserWebhookDeliveryPending, delivery.Status)
+	require.Equal(t, http.StatusBadGateway, delivery.ResponseStatus)
+	require.Contains(t, delivery.LastError, "502")
+	require.Equal(t, now.Add(30*time.Second), delivery.NextAttemptAt)
+
+	delivery.Attempts = 2
+	svc.attempt(context.Background(), hook, delivery)
+	require.Equal(t, UserWebhookDeliveryFailed, delivery.Status)
+	require.Nil(t, delivery.DeliveredAt)
+}
+
+func TestUserWebhookService_NormalizeRejectsInternalTargets(t *testing.T) {
+	svc := newUserWebhookTestService(newUserWebhookRepoStub(), time.Unix(1760000000, 0))
+	// 默认配置（未启用 URL allowlist）下也必须拒绝内网目标
+	for _, raw := range []string{
+		"https://127.0.0.1/hook",
+		"https://localhost:8443/hook",
+		"https://10.0.0.5/hook",
+		"https://192.168.1.1/hook",
+		"https://169.254.169.254/latest/meta-data",
+		"https://100.100.100.200/latest/meta-data",
+		"https://[::1]/hook",
+		"https://[fd00:ec2::254]/hook",
+		"https://[::ffff:127.0.0.1]/hook",
+	} {
+		hook := &UserWebhook{UserID: 1, URL: raw, Events: []string{UserWebhookEventOrderPaid}, UsagePercentThreshold: 90, ExpiryNoticeDays: 3}
+		err := svc.normalize(context.Background(), hook)
+		require.Error(t, err, raw)
+		require.Contains(t, err.Error(), "WEBHOOK_INVALID_URL", raw)
+	}
+
+	svc.cfg.Security.URLAllowlist.AllowPrivateHosts = true
+	hook := &UserWebhook{UserID: 1, URL: "https://10.0.0.5/hook", Events: []string{UserWebhookEventOrderPaid}, UsagePercentThreshold: 90, ExpiryNoticeDays: 3}
+	require.Error(t, svc.normalize(context.Background(), hook), "allow_private_hosts must not apply to user webhooks")
+
+	hook = &UserWebhook{UserID: 1, URL: "https://93.184.216.34/hook/", Events: []string{UserWebhookEventOrderPaid}, UsagePercentThreshold: 90, ExpiryNoticeDays: 3}
+	require.NoError(t, svc.normalize(context.Background(), hook))
+	require.Equal(t, "https://93.184.216.34/hook", hook.URL)
+}
+
+func TestUserWebhookService_DialRejectsInternalTargets(t *testing.T) {
+	var hits int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, _ *http.Request) {
+		atomic.AddInt32(&hits, 1)
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	// 绕过保存时校验（模拟 DNS Rebinding：保存时解析为公网，投递时解析为回环地址）
+	svc := newUserWebhookTestService(newUserWebhookRepoStub(), time.Unix(1760000000, 0))
+	hook := &UserWebhook{ID: 1, URL: server.URL, Secret: "s", Enabled: true}
+	delivery := &UserWebhookDelivery{ID: 1, WebhookID: 1, EventType: UserWebhookEventOrderPaid, Payload: []byte(`{}`), Attempts: 1, MaxAttempts: 1}
+
+	svc.attempt(context.Background(), hook, delivery)
+	require.Equal(t, UserWebhookDeliveryFailed, delivery.Status)
+	require.Zero(t, delivery.ResponseStatus)
+	require.Contains(t, delivery.LastError, "not allowed")
+	require.Zero(t, atomic.LoadInt32(&hits))
+}
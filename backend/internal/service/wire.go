@@ -50,10 +50,12 @@ func ProvideTokenRefreshService(
 	schedulerCache SchedulerCache,
 	cfg *config.Config,
 	tempUnschedCache TempUnschedCache,
+	healthScorer *AccountHealthScorer,
 ) *TokenRefreshService {
 	svc := NewTokenRefreshService(accountRepo, oauthService, openaiOAuthService, geminiOAuthService, antigravityOAuthService, cacheInvalidator, schedulerCache, cfg, tempUnschedCache)
 	// 注入 Sora 账号扩展表仓储，用于 OpenAI Token 刷新时同步 sora_accounts 表
 	svc.SetSoraAccountRepo(soraAccountRepo)
+	svc.SetHealthScorer(healthScorer)
 	svc.Start()
 	return svc
 }
@@ -72,6 +74,103 @@ func ProvideUsageCleanupService(repo UsageCleanupRepository, timingWheel *Timing
 	return svc
 }
 
+// ProvideUsageExportService 创建并启动使用记录导出服务
+func ProvideUsageExportService(repo UsageExportRepository, usageRepo UsageLogRepository, s3Storage *SoraS3Storage, timingWheel *TimingWheelService, cfg *config.Config) *UsageExportService {
+	svc := NewUsageExportService(repo, usageRepo, s3Storage, timingWheel, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideSoraGenerationJobService 创建并启动 Sora 生成任务队列（执行器由 Sora 客户端 Handler 注入）
+func ProvideSoraGenerationJobService(
+	repo SoraGenerationJobRepository,
+	genService *SoraGenerationService,
+	quotaService *SoraQuotaService,
+	s3Storage *SoraS3Storage,
+	mediaStorage *SoraMediaStorage,
+	cfg *config.Config,
+) *SoraGenerationJobService {
+	svc := NewSoraGenerationJobService(repo, genService, quotaService, s3Storage, mediaStorage, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideUserWebhookService creates and starts UserWebhookService, and hooks it into the
+// Sora generation and order services so they can emit events.
+func ProvideUserWebhookService(
+	repo UserWebhookRepository,
+	userRepo UserRepository,
+	apiKeyRepo APIKeyRepository,
+	userSubRepo UserSubscriptionRepository,
+	soraGenerationService *SoraGenerationService,
+	orderService *SubscriptionOrderService,
+	timingWheel *TimingWheelService,
+	cfg *config.Config,
+) *UserWebhookService {
+	svc := NewUserWebhookService(repo, userRepo, apiKeyRepo, userSubRepo, timingWheel, cfg)
+	soraGenerationService.SetUserWebhookService(svc)
+	orderService.SetUserWebhookService(svc)
+	svc.Start()
+	return svc
+}
+
+// ProvideMessageBatchService creates and starts MessageBatchService.
+func ProvideMessageBatchService(
+	repo MessageBatchRepository,
+	apiKeyRepo APIKeyRepository,
+	orgRepo OrganizationRepository,
+	gatewayService *GatewayService,
+	antigravityGatewayService *AntigravityGatewayService,
+	concurrencyService *ConcurrencyService,
+	subscriptionService *SubscriptionService,
+	billingCacheService *BillingCacheService,
+	apiKeyService *APIKeyService,
+	timingWheel *TimingWheelService,
+	cfg *config.Config,
+) *MessageBatchService {
+	svc := NewMessageBatchService(repo, apiKeyRepo, orgRepo, gatewayService, antigravityGatewayService, concurrencyService,
+		subscriptionService, billingCacheService, apiKeyService, timingWheel, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideOpenAIBatchService creates and starts OpenAIBatchService.
+func ProvideOpenAIBatchService(
+	repo OpenAIBatchRepository,
+	files *OpenAIFileService,
+	apiKeyRepo APIKeyRepository,
+	orgRepo OrganizationRepository,
+	gatewayService *OpenAIGatewayService,
+	concurrencyService *ConcurrencyService,
+	subscriptionService *SubscriptionService,
+	billingCacheService *BillingCacheService,
+	apiKeyService *APIKeyService,
+	timingWheel *TimingWheelService,
+	cfg *config.Config,
+) *OpenAIBatchService {
+	svc := NewOpenAIBatchService(repo, files, apiKeyRepo, orgRepo, gatewayService, concurrencyService,
+		subscriptionService, billingCacheService, apiKeyService, timingWheel, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideUserNotificationService creates and starts UserNotificationService.
+func ProvideUserNotificationService(
+	repo UserNotificationRepository,
+	userRepo UserRepository,
+	apiKeyRepo APIKeyRepository,
+	userSubRepo UserSubscriptionRepository,
+	emailService *EmailService,
+	emailQueue *EmailQueueService,
+	settingService *SettingService,
+	timingWheel *TimingWheelService,
+	cfg *config.Config,
+) *UserNotificationService {
+	svc := NewUserNotificationService(repo, userRepo, apiKeyRepo, userSubRepo, emailService, emailQueue, settingService, timingWheel, cfg)
+	svc.Start()
+	return svc
+}
+
 // ProvideAccountExpiryService creates and starts AccountExpiryService.
 func ProvideAccountExpiryService(accountRepo AccountRepository) *AccountExpiryService {
 	svc := NewAccountExpiryService(accountRepo, time.Minute)
@@ -119,6 +218,26 @@ func ProvideConcurrencyService(cache ConcurrencyCache, accountRepo AccountReposi
 	return svc
 }
 
+// ProvideAPIKeyThrottleService creates APIKeyThrottleService and hooks it into APIKeyService for TPM accounting.
+func ProvideAPIKeyThrottleService(concurrencyService *ConcurrencyService, rpmCache RPMCache, apiKeyService *APIKeyService) *APIKeyThrottleService {
+	svc := NewAPIKeyThrottleService(concurrencyService, rpmCache)
+	apiKeyService.SetThrottleService(svc)
+	return svc
+}
+
+// ProvideOrganizationService creates OrganizationService and hooks the organization repository
+// into APIKeyService (setter injection avoids widening its constructor).
+func ProvideOrganizationService(
+	orgRepo OrganizationRepository,
+	userRepo UserRepository,
+	apiKeyService *APIKeyService,
+	billingCacheService *BillingCacheService,
+	authCacheInvalidator APIKeyAuthCacheInvalidator,
+) *OrganizationService {
+	apiKeyService.SetOrganizationRepository(orgRepo)
+	return NewOrganizationService(orgRepo, userRepo, billingCacheService, authCacheInvalidator)
+}
+
 // ProvideUserMessageQueueService 创建用户消息串行队列服务并启动清理 worker
 func ProvideUserMessageQueueService(cache UserMsgQueueCache, rpmCache RPMCache, cfg *config.Config) *UserMessageQueueService {
 	svc := NewUserMessageQueueService(cache, rpmCache, &cfg.Gateway.UserMessageQueue)
@@ -151,11 +270,13 @@ func ProvideRateLimitService(
 	timeoutCounterCache TimeoutCounterCache,
 	settingService *SettingService,
 	tokenCacheInvalidator TokenCacheInvalidator,
+	healthScorer *AccountHealthScorer,
 ) *RateLimitService {
 	svc := NewRateLimitService(accountRepo, usageRepo, cfg, geminiQuotaService, tempUnschedCache)
 	svc.SetTimeoutCounterCache(timeoutCounterCache)
 	svc.SetSettingService(settingService)
 	svc.SetTokenCacheInvalidator(tokenCacheInvalidator)
+	svc.SetHealthScorer(healthScorer)
 	return svc
 }
 
@@ -282,6 +403,65 @@ func ProvideIdempotencyCleanupService(repo IdempotencyRepository, cfg *config.Co
 	return svc
 }
 
+// ProvideAdminAuditService creates AdminAuditService and registers snapshot loaders
+// for the admin resources whose changes are recorded as before/after diffs.
+func ProvideAdminAuditService(
+	repo AdminAuditRepository,
+	adminService AdminService,
+	settingService *SettingService,
+	promoService *PromoService,
+	subscriptionService *SubscriptionService,
+	errorPassthroughService *ErrorPassthroughService,
+	cfg *config.Config,
+) *AdminAuditService {
+	svc := NewAdminAuditService(repo, cfg)
+	svc.RegisterSnapshotLoader("users", func(ctx context.Context, id int64) (any, error) { return adminService.GetUser(ctx, id) })
+	svc.RegisterSnapshotLoader("groups", func(ctx context.Context, id int64) (any, error) { return adminService.GetGroup(ctx, id) })
+	svc.RegisterSnapshotLoader("accounts", func(ctx context.Context, id int64) (any, error) { return adminService.GetAccount(ctx, id) })
+	svc.RegisterSnapshotLoader("proxies", func(ctx context.Context, id int64) (any, error) { return adminService.GetProxy(ctx, id) })
+	svc.RegisterSnapshotLoader("redeem-codes", func(ctx context.Context, id int64) (any, error) { return adminService.GetRedeemCode(ctx, id) })
+	svc.RegisterSnapshotLoader("promo-codes", func(ctx context.Context, id int64) (any, error) { return promoService.GetByID(ctx, id) })
+	svc.RegisterSnapshotLoader("subscriptions", func(ctx context.Context, id int64) (any, error) { return subscriptionService.GetByID(ctx, id) })
+	svc.RegisterSnapshotLoader("error-passthrough-rules", func(ctx context.Context, id int64) (any, error) { return errorPassthroughService.GetByID(ctx, id) })
+	svc.RegisterRouteSnapshotLoader("/api/v1/admin/settings", func(ctx context.Context) (any, error) { return settingService.GetAllSettings(ctx) })
+	return svc
+}
+
+// ProvideAdminAuditCleanupService creates and starts AdminAuditCleanupService (cron scheduled).
+func ProvideAdminAuditCleanupService(db *sql.DB, redisClient *redis.Client, cfg *config.Config) *AdminAuditCleanupService {
+	svc := NewAdminAuditCleanupService(db, redisClient, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideStatementService creates and starts StatementService (cron scheduled monthly close).
+func ProvideStatementService(
+	repo StatementRepository,
+	userRepo UserRepository,
+	emailService *EmailService,
+	settingService *SettingService,
+	db *sql.DB,
+	redisClient *redis.Client,
+	cfg *config.Config,
+) *StatementService {
+	svc := NewStatementService(repo, userRepo, emailService, settingService, db, redisClient, cfg)
+	svc.Start()
+	return svc
+}
+
+// ProvideProxyPoolService creates and starts ProxyPoolService (periodic member health checks).
+func ProvideProxyPoolService(
+	repo ProxyPoolRepository,
+	proxyRepo ProxyRepository,
+	prober ProxyExitInfoProber,
+	latencyCache ProxyLatencyCache,
+	cfg *config.Config,
+) *ProxyPoolService {
+	svc := NewProxyPoolService(repo, proxyRepo, prober, latencyCache, cfg)
+	svc.Start()
+	return svc
+}
+
 // ProvideScheduledTestService creates ScheduledTestService.
 func ProvideScheduledTestService(
 	planRepo ScheduledTestPlanRepository,
@@ -336,6 +516,14 @@ var ProviderSet = wire.NewSet(
 	NewUserService,
 	NewAPIKeyService,
 	ProvideAPIKeyAuthCacheInvalidator,
+	ProvideAPIKeyThrottleService,
+	ProvideOrganizationService,
+	NewAdminAPITokenService,
+	NewSSOService,
+	ProvideAdminAuditService,
+	ProvideAdminAuditCleanupService,
+	ProvideStatementService,
+	ProvideProxyPoolService,
 	NewGroupService,
 	NewAccountService,
 	NewProxyService,
@@ -348,6 +536,7 @@ var ProviderSet = wire.NewSet(
 	NewBillingCacheService,
 	NewAnnouncementService,
 	NewAdminService,
+	NewAccountHealthScorer,
 	NewGatewayService,
 	ProvideSoraMediaStorage,
 	ProvideSoraMediaCleanupService,
@@ -355,6 +544,8 @@ var ProviderSet = wire.NewSet(
 	wire.Bind(new(SoraClient), new(*SoraSDKClient)),
 	NewSoraGatewayService,
 	NewOpenAIGatewayService,
+	NewResponseCacheService,
+	NewCredentialEncryptionService,
 	NewOAuthService,
 	NewOpenAIOAuthService,
 	NewGeminiOAuthService,
@@ -390,6 +581,7 @@ var ProviderSet = wire.NewSet(
 	ProvideConcurrencyService,
 	ProvideUserMessageQueueService,
 	NewUsageRecordWorkerPool,
+	NewPrometheusService,
 	ProvideSchedulerSnapshotService,
 	NewIdentityService,
 	NewCRSSyncService,
@@ -401,13 +593,19 @@ var ProviderSet = wire.NewSet(
 	ProvideTimingWheelService,
 	ProvideDashboardAggregationService,
 	ProvideUsageCleanupService,
+	ProvideUsageExportService,
+	ProvideSoraGenerationJobService,
+	ProvideUserWebhookService,
+	ProvideMessageBatchService,
+	NewOpenAIFileService,
+	ProvideOpenAIBatchService,
+	ProvideUserNotificationService,
 	ProvideDeferredService,
 	NewAntigravityQuotaFetcher,
 	NewUserAttributeService,
 	NewUsageCache,
 	NewTotpService,
 	NewErrorPassthroughService,
-	NewDigestSessionStore,
 	ProvideIdempotencyCoordinator,
 	ProvideSystemOperationLockService,
 	ProvideIdempotencyCleanupService,
@@ -250,3 +250,82 @@ func toInt(value any) (int, error) {
 		return 0, fmt.Errorf("unsupported int value")
 	}
 }
+
+// XunhuPayProvider adapts XunhuPayClient to the PaymentProvider interface.
+type XunhuPayProvider struct {
+	cfg    XunhuPayConfig
+	client *XunhuPayClient
+}
+
+// NewXunhuPayProvider creates a XunhuPay payment provider.
+func NewXunhuPayProvider(cfg XunhuPayConfig, client *XunhuPayClient) *XunhuPayProvider {
+	return &XunhuPayProvider{cfg: cfg, client: client}
+}
+
+func (p *XunhuPayProvider) Name() string { return PaymentProviderXunhuPay }
+
+func (p *XunhuPayProvider) Currency() string { return CurrencyCNY }
+
+// CreatePayment creates a XunhuPay payment and returns payment URLs.
+func (p *XunhuPayProvider) CreatePayment(ctx context.Context, req *PaymentCreateRequest) (*PaymentCreateResult, error) {
+	if req == nil {
+		return nil, ErrOrderNilInput
+	}
+	order := &SubscriptionOrder{OrderNo: req.OrderNo, Amount: req.Amount}
+	resp, err := p.client.CreatePayment(ctx, p.cfg, order, req.Subject)
+	if err != nil {
+		return nil, err
+	}
+	return &PaymentCreateResult{
+		URL:    resp.URL,
+		QRCode: resp.URLQRCode,
+		Plugin: p.cfg.Plugins,
+	}, nil
+}
+
+// ParseNotify verifies a form-encoded XunhuPay notify callback.
+func (p *XunhuPayProvider) ParseNotify(_ context.Context, req *PaymentNotifyRequest) (*PaymentNotification, error) {
+	form, err := url.ParseQuery(string(req.Body))
+	if err != nil {
+		return nil, ErrPaymentInvalidSignature
+	}
+	payload := XunhuPayNotifyPayload{
+		AppID:         form.Get("appid"),
+		TradeOrderID:  form.Get("trade_order_id"),
+		TotalFee:      form.Get("total_fee"),
+		TransactionID: form.Get("transaction_id"),
+		OpenOrderID:   form.Get("open_order_id"),
+		OrderTitle:    form.Get("order_title"),
+		Status:        form.Get("status"),
+		Plugins:       form.Get("plugins"),
+		Attach:        form.Get("attach"),
+		Time:          form.Get("time"),
+		NonceStr:      form.Get("nonce_str"),
+		Hash:          form.Get("hash"),
+	}
+	if payload.AppID != p.cfg.AppID || !p.client.VerifyNotify(payload, p.cfg.AppSecret) {
+		return nil, ErrPaymentInvalidSignature
+	}
+	notification := &PaymentNotification{
+		EventID:       payload.TradeOrderID + ":" + payload.TransactionID,
+		OrderNo:       payload.TradeOrderID,
+		TransactionID: payload.TransactionID,
+		OpenOrderID:   payload.OpenOrderID,
+		Plugin:        payload.Plugins,
+		Paid:          payload.Status == "OD",
+		Amount:        -1,
+	}
+	if payload.TotalFee != "" {
+		if amount, err := strconv.ParseFloat(payload.TotalFee, 64); err == nil {
+			notification.Amount = amount
+		}
+	}
+	return notification, nil
+}
+
+func (p *XunhuPayProvider) NotifyAck(err error) PaymentNotifyAck { return textNotifyAck(err) }
+
+// Refund is not supported through the XunhuPay API; refund in the merchant console instead.
+func (p *XunhuPayProvider) Refund(context.Context, *PaymentRefundRequest) (*PaymentRefundResult, error) {
+	return nil, ErrPaymentRefundUnsupported
+}
@@ -45,6 +45,12 @@ func (c StubConcurrencyCache) AcquireUserSlot(_ context.Context, _ int64, _ int,
 func (c StubConcurrencyCache) ReleaseUserSlot(_ context.Context, _ int64, _ string) error {
 	return nil
 }
+func (c StubConcurrencyCache) AcquireAPIKeySlot(_ context.Context, _ int64, _ int, _ string) (bool, error) {
+	return true, nil
+}
+func (c StubConcurrencyCache) ReleaseAPIKeySlot(_ context.Context, _ int64, _ string) error {
+	return nil
+}
 func (c StubConcurrencyCache) GetUserConcurrency(_ context.Context, _ int64) (int, error) {
 	return 0, nil
 }
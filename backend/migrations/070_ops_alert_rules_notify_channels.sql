@@ -0,0 +1,5 @@
+-- 070_ops_alert_rules_notify_channels.sql
+-- 告警规则的非邮件通知渠道（webhook / Slack / Telegram / 钉钉 / 飞书）
+
+ALTER TABLE ops_alert_rules
+    ADD COLUMN IF NOT EXISTS notify_channels JSONB NOT NULL DEFAULT '[]'::jsonb;
@@ -0,0 +1,9 @@
+-- 071_ops_error_logs_trace_id.sql
+-- 错误日志关联 OpenTelemetry trace id（W3C traceparent），用于运维面板跳转到链路追踪
+
+ALTER TABLE ops_error_logs
+    ADD COLUMN IF NOT EXISTS trace_id VARCHAR(32);
+
+CREATE INDEX IF NOT EXISTS idx_ops_error_logs_trace_id
+    ON ops_error_logs (trace_id)
+    WHERE trace_id IS NOT NULL;
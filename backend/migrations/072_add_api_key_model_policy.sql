@@ -0,0 +1,12 @@
+-- Add per-key model policy fields to api_keys table
+-- allowed_models: JSON array of allowed model patterns (if set, only matching models can be requested)
+-- denied_models: JSON array of denied model patterns (always blocked, checked before allowed_models)
+-- model_aliases: JSON object mapping client-facing alias -> real model name
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS allowed_models JSONB DEFAULT NULL;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS denied_models JSONB DEFAULT NULL;
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS model_aliases JSONB DEFAULT NULL;
+
+COMMENT ON COLUMN api_keys.allowed_models IS 'JSON array of allowed model patterns (* wildcard), e.g. ["claude-*-haiku*", "gpt-5*"]';
+COMMENT ON COLUMN api_keys.denied_models IS 'JSON array of denied model patterns (* wildcard), e.g. ["claude-opus-*"]';
+COMMENT ON COLUMN api_keys.model_aliases IS 'JSON object of model aliases, e.g. {"fast": "claude-haiku-4-5"}';
@@ -0,0 +1,47 @@
+-- 组织（团队）：共享余额、成员角色与按成员的月度消费上限
+CREATE TABLE IF NOT EXISTS organizations (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    owner_user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE RESTRICT,
+    balance DECIMAL(20,8) NOT NULL DEFAULT 0,
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    deleted_at TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE TABLE IF NOT EXISTS organization_members (
+    id BIGSERIAL PRIMARY KEY,
+    organization_id BIGINT NOT NULL REFERENCES organizations(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    role VARCHAR(20) NOT NULL DEFAULT 'member',
+    monthly_limit_usd DECIMAL(20,8) NOT NULL DEFAULT 0,
+    monthly_usage_usd DECIMAL(20,10) NOT NULL DEFAULT 0,
+    monthly_window_start TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE(organization_id, user_id)
+);
+
+-- 组织 Key：为空表示个人 Key
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS organization_id BIGINT DEFAULT NULL REFERENCES organizations(id) ON DELETE SET NULL;
+
+-- 索引
+CREATE INDEX IF NOT EXISTS idx_organizations_owner_user_id ON organizations(owner_user_id);
+CREATE INDEX IF NOT EXISTS idx_organizations_status ON organizations(status);
+CREATE INDEX IF NOT EXISTS idx_organizations_deleted_at ON organizations(deleted_at);
+CREATE INDEX IF NOT EXISTS idx_organization_members_user_id ON organization_members(user_id);
+CREATE INDEX IF NOT EXISTS idx_api_keys_organization_id ON api_keys(organization_id);
+
+COMMENT ON TABLE organizations IS '组织（团队），组织 Key 的用量从共享余额扣费';
+COMMENT ON COLUMN organizations.owner_user_id IS '组织所有者；订阅类分组的组织 Key 使用其名下订阅';
+COMMENT ON COLUMN organizations.balance IS '组织共享余额（USD）';
+COMMENT ON COLUMN organizations.status IS '状态: active, disabled';
+
+COMMENT ON TABLE organization_members IS '组织成员';
+COMMENT ON COLUMN organization_members.role IS '角色: owner, admin, member';
+COMMENT ON COLUMN organization_members.monthly_limit_usd IS '成员月度消费上限（USD，0 表示不限制）';
+COMMENT ON COLUMN organization_members.monthly_usage_usd IS '成员当前自然月消费（USD）';
+COMMENT ON COLUMN organization_members.monthly_window_start IS 'monthly_usage_usd 所属自然月的起始时间';
+
+COMMENT ON COLUMN api_keys.organization_id IS '所属组织（为空表示个人 Key）';
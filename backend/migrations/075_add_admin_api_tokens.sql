@@ -0,0 +1,24 @@
+-- 多个具名管理员 API Token（按 scope 授权，替代单一全局 Admin API Key）
+CREATE TABLE IF NOT EXISTS admin_api_tokens (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    token_hash VARCHAR(64) NOT NULL,
+    token_prefix VARCHAR(20) NOT NULL,
+    scopes JSONB NOT NULL DEFAULT '[]'::jsonb,
+    ip_whitelist JSONB NOT NULL DEFAULT '[]'::jsonb,
+    expires_at TIMESTAMPTZ DEFAULT NULL,
+    last_used_at TIMESTAMPTZ DEFAULT NULL,
+    last_used_ip VARCHAR(64) DEFAULT NULL,
+    created_by BIGINT DEFAULT NULL REFERENCES users(id) ON DELETE SET NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_admin_api_tokens_token_hash ON admin_api_tokens(token_hash);
+
+COMMENT ON TABLE admin_api_tokens IS '管理员 API Token，供外部自动化系统按 scope 访问管理接口';
+COMMENT ON COLUMN admin_api_tokens.token_hash IS 'Token 的 SHA-256（hex），明文只在创建时返回一次';
+COMMENT ON COLUMN admin_api_tokens.token_prefix IS 'Token 前缀，仅用于展示与识别';
+COMMENT ON COLUMN admin_api_tokens.scopes IS '授权范围，如 ["accounts:read","users:write"]；write 隐含 read';
+COMMENT ON COLUMN admin_api_tokens.ip_whitelist IS '来源 IP/CIDR 白名单，为空表示不限制';
+COMMENT ON COLUMN admin_api_tokens.expires_at IS '过期时间，NULL 表示永不过期';
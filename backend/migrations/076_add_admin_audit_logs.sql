@@ -0,0 +1,29 @@
+-- 管理员操作审计日志（只追加，不提供修改/删除接口；过期数据由定时任务清理）
+CREATE TABLE IF NOT EXISTS admin_audit_logs (
+    id BIGSERIAL PRIMARY KEY,
+    actor_type VARCHAR(20) NOT NULL,
+    actor_user_id BIGINT DEFAULT NULL,
+    actor_token_id BIGINT DEFAULT NULL,
+    actor_token_name VARCHAR(100) DEFAULT NULL,
+    method VARCHAR(10) NOT NULL,
+    route VARCHAR(255) NOT NULL,
+    path VARCHAR(512) NOT NULL,
+    target_type VARCHAR(50) NOT NULL DEFAULT '',
+    target_id VARCHAR(64) NOT NULL DEFAULT '',
+    status_code INT NOT NULL DEFAULT 0,
+    client_ip VARCHAR(64) NOT NULL DEFAULT '',
+    request_body JSONB DEFAULT NULL,
+    diff JSONB DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_created_at ON admin_audit_logs(created_at);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_actor_user_id ON admin_audit_logs(actor_user_id, created_at);
+CREATE INDEX IF NOT EXISTS idx_admin_audit_logs_target ON admin_audit_logs(target_type, target_id, created_at);
+
+COMMENT ON TABLE admin_audit_logs IS '管理员写操作审计日志';
+COMMENT ON COLUMN admin_audit_logs.actor_type IS '操作者类型：jwt / admin_api_key / admin_api_token';
+COMMENT ON COLUMN admin_audit_logs.route IS 'Gin 路由模板，如 /api/v1/admin/accounts/:id';
+COMMENT ON COLUMN admin_audit_logs.target_type IS '目标对象类型，如 accounts / groups / settings';
+COMMENT ON COLUMN admin_audit_logs.request_body IS '脱敏后的请求体';
+COMMENT ON COLUMN admin_audit_logs.diff IS '脱敏后的字段级变更：{"path": {"before": x, "after": y}}；新建时 before 缺省，删除时 after 缺省';
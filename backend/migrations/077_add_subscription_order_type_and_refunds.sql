@@ -0,0 +1,11 @@
+-- Support balance top-up orders and refunds on subscription_orders.
+-- Top-up orders are not bound to a group, so group_id becomes nullable.
+ALTER TABLE subscription_orders ALTER COLUMN group_id DROP NOT NULL;
+
+-- order_type: subscription (buy a group plan) | balance (top up user balance)
+ALTER TABLE subscription_orders ADD COLUMN IF NOT EXISTS order_type VARCHAR(20) NOT NULL DEFAULT 'subscription';
+-- credit_amount: balance credited to the user when a top-up order is paid
+ALTER TABLE subscription_orders ADD COLUMN IF NOT EXISTS credit_amount DECIMAL(20, 8) NOT NULL DEFAULT 0;
+ALTER TABLE subscription_orders ADD COLUMN IF NOT EXISTS refunded_at TIMESTAMPTZ;
+
+CREATE INDEX IF NOT EXISTS idx_subscription_orders_order_type ON subscription_orders(order_type);
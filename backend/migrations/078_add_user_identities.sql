@@ -0,0 +1,19 @@
+-- 第三方 SSO 身份绑定（OIDC / GitHub / Google）。
+-- 一个用户可绑定多个提供方身份；同一提供方的同一 subject 只能绑定到一个用户。
+CREATE TABLE IF NOT EXISTS user_identities (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    provider VARCHAR(32) NOT NULL,
+    subject VARCHAR(255) NOT NULL,
+    email VARCHAR(255) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    last_login_at TIMESTAMPTZ DEFAULT NULL
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_identities_provider_subject ON user_identities(provider, subject);
+CREATE INDEX IF NOT EXISTS idx_user_identities_user_id ON user_identities(user_id);
+
+COMMENT ON TABLE user_identities IS '用户绑定的第三方 SSO 身份';
+COMMENT ON COLUMN user_identities.provider IS 'SSO 提供方 ID（配置 sso.providers[].id）';
+COMMENT ON COLUMN user_identities.subject IS '提供方侧的稳定用户标识（OIDC sub / GitHub user id）';
+COMMENT ON COLUMN user_identities.email IS '绑定时提供方返回的邮箱（仅展示用）';
@@ -0,0 +1,37 @@
+-- 使用记录异步导出任务（CSV / JSONL）。
+-- 大范围导出由后台任务分批写入本地文件或 S3，完成后通过签名链接下载。
+CREATE TABLE IF NOT EXISTS usage_export_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    scope VARCHAR(16) NOT NULL,
+    format VARCHAR(16) NOT NULL,
+    status VARCHAR(20) NOT NULL,
+    filters JSONB NOT NULL,
+    created_by BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    row_count BIGINT NOT NULL DEFAULT 0,
+    file_size BIGINT NOT NULL DEFAULT 0,
+    storage VARCHAR(16) NOT NULL DEFAULT '',
+    object_key TEXT NOT NULL DEFAULT '',
+    error_message TEXT,
+    started_at TIMESTAMPTZ,
+    finished_at TIMESTAMPTZ,
+    expires_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_usage_export_jobs_status_created_at
+    ON usage_export_jobs(status, created_at);
+
+CREATE INDEX IF NOT EXISTS idx_usage_export_jobs_created_by_created_at
+    ON usage_export_jobs(created_by, created_at DESC);
+
+CREATE INDEX IF NOT EXISTS idx_usage_export_jobs_expires_at
+    ON usage_export_jobs(expires_at)
+    WHERE status = 'succeeded';
+
+COMMENT ON TABLE usage_export_jobs IS '使用记录异步导出任务';
+COMMENT ON COLUMN usage_export_jobs.scope IS '导出范围：admin（全部用户）/ user（仅本人）';
+COMMENT ON COLUMN usage_export_jobs.format IS '导出格式：csv / jsonl';
+COMMENT ON COLUMN usage_export_jobs.storage IS '文件存储位置：local / s3';
+COMMENT ON COLUMN usage_export_jobs.object_key IS '本地相对路径或 S3 object key';
+COMMENT ON COLUMN usage_export_jobs.expires_at IS '文件过期时间，到期后删除文件并标记为 expired';
@@ -0,0 +1,63 @@
+-- 月度账单（对账单）：每个用户每个账期一份；只追加，不提供修改接口（仅记录邮件发送时间）
+CREATE TABLE IF NOT EXISTS billing_statements (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    period VARCHAR(7) NOT NULL,
+    period_start TIMESTAMPTZ NOT NULL,
+    period_end TIMESTAMPTZ NOT NULL,
+    timezone VARCHAR(64) NOT NULL DEFAULT 'UTC',
+    currency VARCHAR(10) NOT NULL DEFAULT 'USD',
+    user_email VARCHAR(255) NOT NULL DEFAULT '',
+    username VARCHAR(100) NOT NULL DEFAULT '',
+    request_count BIGINT NOT NULL DEFAULT 0,
+    usage_total_cost DECIMAL(20,10) NOT NULL DEFAULT 0,
+    usage_balance_cost DECIMAL(20,10) NOT NULL DEFAULT 0,
+    usage_subscription_cost DECIMAL(20,10) NOT NULL DEFAULT 0,
+    subscription_fees DECIMAL(20,8) NOT NULL DEFAULT 0,
+    top_ups DECIMAL(20,8) NOT NULL DEFAULT 0,
+    refunds DECIMAL(20,8) NOT NULL DEFAULT 0,
+    credits DECIMAL(20,8) NOT NULL DEFAULT 0,
+    line_count INT NOT NULL DEFAULT 0,
+    emailed_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (user_id, period)
+);
+
+CREATE INDEX IF NOT EXISTS idx_billing_statements_period ON billing_statements(period);
+CREATE INDEX IF NOT EXISTS idx_billing_statements_user_created ON billing_statements(user_id, created_at DESC);
+
+CREATE TABLE IF NOT EXISTS billing_statement_lines (
+    id BIGSERIAL PRIMARY KEY,
+    statement_id BIGINT NOT NULL REFERENCES billing_statements(id) ON DELETE CASCADE,
+    line_no INT NOT NULL,
+    line_type VARCHAR(20) NOT NULL,
+    description VARCHAR(255) NOT NULL DEFAULT '',
+    group_id BIGINT DEFAULT NULL,
+    group_name VARCHAR(100) NOT NULL DEFAULT '',
+    model VARCHAR(100) NOT NULL DEFAULT '',
+    billing_type SMALLINT DEFAULT NULL,
+    reference VARCHAR(64) NOT NULL DEFAULT '',
+    request_count BIGINT NOT NULL DEFAULT 0,
+    input_tokens BIGINT NOT NULL DEFAULT 0,
+    output_tokens BIGINT NOT NULL DEFAULT 0,
+    cache_creation_tokens BIGINT NOT NULL DEFAULT 0,
+    cache_read_tokens BIGINT NOT NULL DEFAULT 0,
+    image_count BIGINT NOT NULL DEFAULT 0,
+    video_count BIGINT NOT NULL DEFAULT 0,
+    total_cost DECIMAL(20,10) NOT NULL DEFAULT 0,
+    amount DECIMAL(20,10) NOT NULL DEFAULT 0,
+    occurred_at TIMESTAMPTZ DEFAULT NULL,
+    UNIQUE (statement_id, line_no)
+);
+
+CREATE INDEX IF NOT EXISTS idx_billing_statement_lines_statement_id ON billing_statement_lines(statement_id);
+
+COMMENT ON TABLE billing_statements IS '用户月度账单（生成后不可修改）';
+COMMENT ON COLUMN billing_statements.period IS '账期，格式 YYYY-MM（按 timezone 配置切分）';
+COMMENT ON COLUMN billing_statements.period_end IS '账期结束时间（不含）';
+COMMENT ON COLUMN billing_statements.usage_total_cost IS '账期内按标准价计算的用量费用';
+COMMENT ON COLUMN billing_statements.usage_balance_cost IS '账期内从余额扣除的用量费用（actual_cost，billing_type=0）';
+COMMENT ON COLUMN billing_statements.usage_subscription_cost IS '账期内由订阅覆盖的用量费用（actual_cost，billing_type=1）';
+COMMENT ON COLUMN billing_statements.credits IS '兑换码 / 优惠码 / 管理员调整带来的余额变动合计';
+COMMENT ON COLUMN billing_statement_lines.line_type IS '明细类型：usage / subscription / top_up / refund / redeem / promo / adjustment';
+COMMENT ON COLUMN billing_statement_lines.amount IS '明细金额：usage 为实际扣费，其余为余额 / 支付变动';
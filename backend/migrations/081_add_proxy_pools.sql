@@ -0,0 +1,30 @@
+-- 代理池：账号可绑定代理池，请求时从健康成员中按账号粘性选择出口代理
+CREATE TABLE IF NOT EXISTS proxy_pools (
+    id BIGSERIAL PRIMARY KEY,
+    name VARCHAR(100) NOT NULL,
+    description TEXT NOT NULL DEFAULT '',
+    status VARCHAR(20) NOT NULL DEFAULT 'active',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_proxy_pools_name ON proxy_pools(name);
+
+-- 成员健康状态由后台探测与请求链路失败共同维护
+CREATE TABLE IF NOT EXISTS proxy_pool_members (
+    pool_id BIGINT NOT NULL REFERENCES proxy_pools(id) ON DELETE CASCADE,
+    proxy_id BIGINT NOT NULL REFERENCES proxies(id) ON DELETE CASCADE,
+    healthy BOOLEAN NOT NULL DEFAULT TRUE,
+    fail_count INT NOT NULL DEFAULT 0,
+    latency_ms BIGINT DEFAULT NULL,
+    last_error TEXT NOT NULL DEFAULT '',
+    last_checked_at TIMESTAMPTZ DEFAULT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (pool_id, proxy_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_proxy_pool_members_proxy ON proxy_pool_members(proxy_id);
+
+ALTER TABLE accounts ADD COLUMN IF NOT EXISTS proxy_pool_id BIGINT DEFAULT NULL REFERENCES proxy_pools(id) ON DELETE SET NULL;
+
+CREATE INDEX IF NOT EXISTS idx_accounts_proxy_pool_id ON accounts(proxy_pool_id);
@@ -0,0 +1,63 @@
+-- Sora 客户端生成任务持久化队列。
+-- 替代进程内 goroutine：实例重启/升级后任务可被任意实例接管（租约 + 心跳），
+-- 已拿到 upstream_task_id 的任务直接恢复轮询，僵死任务由 reaper 标记失败并退还配额。
+CREATE TABLE IF NOT EXISTS sora_generation_jobs (
+    id BIGSERIAL PRIMARY KEY,
+    generation_id BIGINT NOT NULL REFERENCES sora_generations(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    group_id BIGINT,
+
+    -- 生成参数（执行所需的完整输入）
+    model VARCHAR(64) NOT NULL,
+    prompt TEXT NOT NULL DEFAULT '',
+    media_type VARCHAR(16) NOT NULL DEFAULT 'video',
+    image_input TEXT NOT NULL DEFAULT '',
+    video_count INT NOT NULL DEFAULT 1,
+
+    -- 队列状态
+    status VARCHAR(16) NOT NULL DEFAULT 'queued',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 3,
+    run_after TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    lease_owner VARCHAR(128) NOT NULL DEFAULT '',
+    lease_expires_at TIMESTAMPTZ,
+    heartbeat_at TIMESTAMPTZ,
+
+    -- 上游进度（用于断点恢复）
+    account_id BIGINT,
+    upstream_task_id VARCHAR(128) NOT NULL DEFAULT '',
+
+    -- 已落盘但尚未完成的存储（用于失败回滚与配额退还）
+    storage_type VARCHAR(16) NOT NULL DEFAULT '',
+    storage_paths JSONB,
+    storage_bytes BIGINT NOT NULL DEFAULT 0,
+    usage_added BOOLEAN NOT NULL DEFAULT FALSE,
+
+    last_error TEXT NOT NULL DEFAULT '',
+    finished_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_sora_generation_jobs_generation_id
+    ON sora_generation_jobs(generation_id);
+
+-- 拉取队列：queued 按 run_after 取，running 按租约过期接管
+CREATE INDEX IF NOT EXISTS idx_sora_generation_jobs_queued
+    ON sora_generation_jobs(run_after, id)
+    WHERE status = 'queued';
+
+CREATE INDEX IF NOT EXISTS idx_sora_generation_jobs_running_lease
+    ON sora_generation_jobs(lease_expires_at)
+    WHERE status = 'running';
+
+CREATE INDEX IF NOT EXISTS idx_sora_generation_jobs_finished_at
+    ON sora_generation_jobs(finished_at)
+    WHERE finished_at IS NOT NULL;
+
+COMMENT ON TABLE sora_generation_jobs IS 'Sora 客户端生成任务队列';
+COMMENT ON COLUMN sora_generation_jobs.status IS 'queued / running / succeeded / failed';
+COMMENT ON COLUMN sora_generation_jobs.lease_owner IS '持有租约的 worker 标识（hostname:pid:random）';
+COMMENT ON COLUMN sora_generation_jobs.upstream_task_id IS '上游任务 ID，非空时重试直接恢复轮询而不重新提交';
+COMMENT ON COLUMN sora_generation_jobs.storage_paths IS '已落盘的 S3 object key 或本地相对路径，任务失败时回滚';
+COMMENT ON COLUMN sora_generation_jobs.usage_added IS '是否已计入存储配额，任务失败时退还';
@@ -0,0 +1,75 @@
+-- 用户出站 Webhook：用户（或单个 API Key）订阅事件，服务端以 HMAC 签名的 HTTP 回调推送，
+-- 失败按指数退避重试，每次投递保留日志。
+CREATE TABLE IF NOT EXISTS user_webhooks (
+    id BIGSERIAL PRIMARY KEY,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    api_key_id BIGINT DEFAULT NULL REFERENCES api_keys(id) ON DELETE CASCADE,
+    name VARCHAR(100) NOT NULL DEFAULT '',
+    url TEXT NOT NULL,
+    secret VARCHAR(128) NOT NULL,
+    events TEXT[] NOT NULL DEFAULT '{}',
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    balance_threshold DECIMAL(20,8) NOT NULL DEFAULT 0,
+    usage_threshold_percent INT NOT NULL DEFAULT 90,
+    expiry_notice_days INT NOT NULL DEFAULT 3,
+    last_delivery_at TIMESTAMPTZ,
+    last_delivery_status VARCHAR(20) NOT NULL DEFAULT '',
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_webhooks_user_id ON user_webhooks(user_id);
+CREATE INDEX IF NOT EXISTS idx_user_webhooks_events ON user_webhooks USING GIN (events) WHERE enabled;
+
+COMMENT ON TABLE user_webhooks IS '用户出站 Webhook 订阅';
+COMMENT ON COLUMN user_webhooks.api_key_id IS '非空时仅推送与该 API Key 相关的事件';
+COMMENT ON COLUMN user_webhooks.secret IS 'HMAC-SHA256 签名密钥';
+COMMENT ON COLUMN user_webhooks.events IS '订阅的事件类型列表';
+COMMENT ON COLUMN user_webhooks.balance_threshold IS 'balance.low 事件的余额阈值（USD）';
+COMMENT ON COLUMN user_webhooks.usage_threshold_percent IS 'API Key 额度/限速窗口将尽事件的用量百分比阈值';
+COMMENT ON COLUMN user_webhooks.expiry_notice_days IS 'subscription.expiring 事件的提前天数';
+
+-- 投递记录：既是重试队列，也是投递日志
+CREATE TABLE IF NOT EXISTS user_webhook_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    webhook_id BIGINT NOT NULL REFERENCES user_webhooks(id) ON DELETE CASCADE,
+    user_id BIGINT NOT NULL,
+    event_id VARCHAR(64) NOT NULL,
+    event_type VARCHAR(64) NOT NULL,
+    payload JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 1,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    response_status INT NOT NULL DEFAULT 0,
+    response_body TEXT NOT NULL DEFAULT '',
+    last_error TEXT NOT NULL DEFAULT '',
+    duration_ms BIGINT NOT NULL DEFAULT 0,
+    delivered_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_webhook_deliveries_pending
+    ON user_webhook_deliveries(next_attempt_at, id)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_user_webhook_deliveries_webhook_created
+    ON user_webhook_deliveries(webhook_id, created_at DESC, id DESC);
+
+CREATE INDEX IF NOT EXISTS idx_user_webhook_deliveries_created_at
+    ON user_webhook_deliveries(created_at);
+
+COMMENT ON TABLE user_webhook_deliveries IS '用户 Webhook 投递记录（重试队列与投递日志）';
+COMMENT ON COLUMN user_webhook_deliveries.event_id IS '事件 ID，同一事件投递到多个 Webhook 时相同，接收方可据此去重';
+COMMENT ON COLUMN user_webhook_deliveries.status IS 'pending / succeeded / failed';
+COMMENT ON COLUMN user_webhook_deliveries.next_attempt_at IS '下次可投递时间；投递中会临时后延，防止多实例重复投递';
+COMMENT ON COLUMN user_webhook_deliveries.response_body IS '接收方响应体（截断）';
+
+-- 阈值类事件的触发状态：条件成立时写入（只推送一次），条件解除后删除以便下次重新触发
+CREATE TABLE IF NOT EXISTS user_webhook_alert_states (
+    webhook_id BIGINT NOT NULL REFERENCES user_webhooks(id) ON DELETE CASCADE,
+    alert_key VARCHAR(128) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (webhook_id, alert_key)
+);
@@ -0,0 +1,35 @@
+-- 用户通知邮件：余额不足、API Key 额度将尽、订阅用量将尽、订阅即将到期。
+-- 用户在个人设置中开启并配置阈值；邮件内附退订链接（凭 unsubscribe_token，无需登录）。
+CREATE TABLE IF NOT EXISTS user_notification_settings (
+    user_id BIGINT PRIMARY KEY REFERENCES users(id) ON DELETE CASCADE,
+    enabled BOOLEAN NOT NULL DEFAULT TRUE,
+    balance_threshold DECIMAL(20,8) NOT NULL DEFAULT 0,
+    api_key_quota_percent INT NOT NULL DEFAULT 90,
+    subscription_usage_percent INT NOT NULL DEFAULT 90,
+    expiry_notice_days INT NOT NULL DEFAULT 3,
+    unsubscribe_token VARCHAR(64) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE UNIQUE INDEX IF NOT EXISTS idx_user_notification_settings_unsubscribe_token
+    ON user_notification_settings(unsubscribe_token);
+
+CREATE INDEX IF NOT EXISTS idx_user_notification_settings_enabled
+    ON user_notification_settings(user_id)
+    WHERE enabled;
+
+COMMENT ON TABLE user_notification_settings IS '用户通知邮件设置';
+COMMENT ON COLUMN user_notification_settings.enabled IS '是否接收通知邮件（退订即置为 false）';
+COMMENT ON COLUMN user_notification_settings.balance_threshold IS '余额低于该值（USD）时提醒，0 表示不提醒';
+COMMENT ON COLUMN user_notification_settings.api_key_quota_percent IS 'API Key 额度用量达到该百分比时提醒，0 表示不提醒';
+COMMENT ON COLUMN user_notification_settings.subscription_usage_percent IS '订阅日/周/月用量达到分组限额该百分比时提醒，0 表示不提醒';
+COMMENT ON COLUMN user_notification_settings.expiry_notice_days IS '订阅到期前 N 天提醒，0 表示不提醒';
+
+-- 已发送的提醒：同一 key 在条件解除前只发送一次；用量类 key 含窗口起点，即按周期去重
+CREATE TABLE IF NOT EXISTS user_notification_states (
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    notify_key VARCHAR(128) NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    PRIMARY KEY (user_id, notify_key)
+);
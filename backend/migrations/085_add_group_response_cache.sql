@@ -0,0 +1,8 @@
+-- 分组级精确响应缓存：对 temperature=0 的确定性请求缓存完整响应，命中按比例计费
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS response_cache_enabled BOOLEAN NOT NULL DEFAULT false;
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS response_cache_ttl_seconds INTEGER NOT NULL DEFAULT 0;
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS response_cache_price_ratio DECIMAL(10,4) NOT NULL DEFAULT 0.1;
+
+COMMENT ON COLUMN groups.response_cache_enabled IS '是否对确定性请求启用精确响应缓存';
+COMMENT ON COLUMN groups.response_cache_ttl_seconds IS '响应缓存 TTL（秒），0 表示使用全局默认值';
+COMMENT ON COLUMN groups.response_cache_price_ratio IS '缓存命中计费比例（相对正常价格）';
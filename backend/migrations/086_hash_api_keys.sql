@@ -0,0 +1,14 @@
+-- API Key 改为存储 HMAC-SHA256 哈希 + 展示前缀，不再保存明文
+-- key_hash 由应用启动时使用持久化的 HMAC 密钥回填（见 repository.backfillAPIKeyHashes），
+-- 回填完成后 key 列会被置空；待所有实例升级后可在后续迁移中删除 key 列。
+
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS key_hash VARCHAR(64);
+ALTER TABLE api_keys ADD COLUMN IF NOT EXISTS key_prefix VARCHAR(16) NOT NULL DEFAULT '';
+ALTER TABLE api_keys ALTER COLUMN key DROP NOT NULL;
+
+CREATE UNIQUE INDEX IF NOT EXISTS api_keys_key_hash_key ON api_keys (key_hash);
+CREATE INDEX IF NOT EXISTS idx_api_keys_key_prefix ON api_keys (key_prefix);
+
+COMMENT ON COLUMN api_keys.key_hash IS 'HMAC-SHA256(api_key_hash_secret, key) 的十六进制值';
+COMMENT ON COLUMN api_keys.key_prefix IS 'API Key 前若干字符，用于展示与搜索';
+COMMENT ON COLUMN api_keys.key IS '已废弃：明文 Key，回填 key_hash 后置空';
@@ -0,0 +1,6 @@
+-- 分组级模型降级链：所有账号限流 / 上游过载 / prompt 过长时，按序切换到链中的下一个模型
+-- 格式: [{"models": ["claude-opus-*", "claude-sonnet-4-5", "claude-haiku-4-5"], "conditions": ["rate_limited", "overloaded"]}]
+-- conditions 为空表示任一条件均触发降级
+ALTER TABLE groups ADD COLUMN IF NOT EXISTS model_fallback_chains JSONB;
+
+COMMENT ON COLUMN groups.model_fallback_chains IS '分组内有序模型降级链（JSON 数组）';
@@ -0,0 +1,53 @@
+-- Anthropic Message Batches API 兼容：批次与批内请求持久化，由后台 worker 以低优先级异步执行。
+CREATE TABLE IF NOT EXISTS message_batches (
+    id VARCHAR(64) PRIMARY KEY,
+    seq BIGSERIAL NOT NULL UNIQUE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    api_key_id BIGINT NOT NULL,
+    group_id BIGINT DEFAULT NULL,
+    processing_status VARCHAR(20) NOT NULL DEFAULT 'in_progress',
+    anthropic_beta TEXT NOT NULL DEFAULT '',
+    expires_at TIMESTAMPTZ NOT NULL,
+    cancel_initiated_at TIMESTAMPTZ,
+    ended_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_batches_user_seq ON message_batches(user_id, seq DESC);
+CREATE INDEX IF NOT EXISTS idx_message_batches_open ON message_batches(expires_at) WHERE processing_status <> 'ended';
+CREATE INDEX IF NOT EXISTS idx_message_batches_ended_at ON message_batches(ended_at) WHERE processing_status = 'ended';
+
+COMMENT ON TABLE message_batches IS 'Anthropic Message Batches 兼容批次';
+COMMENT ON COLUMN message_batches.seq IS '创建顺序，用于列表游标分页';
+COMMENT ON COLUMN message_batches.api_key_id IS '创建批次的 API Key，批内请求按该 Key 调度与计费';
+COMMENT ON COLUMN message_batches.processing_status IS 'in_progress / canceling / ended';
+COMMENT ON COLUMN message_batches.anthropic_beta IS '创建时的 anthropic-beta 请求头，执行批内请求时透传';
+
+CREATE TABLE IF NOT EXISTS message_batch_items (
+    id BIGSERIAL PRIMARY KEY,
+    batch_id VARCHAR(64) NOT NULL REFERENCES message_batches(id) ON DELETE CASCADE,
+    custom_id VARCHAR(64) NOT NULL,
+    params JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    result JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (batch_id, custom_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_message_batch_items_pending
+    ON message_batch_items(next_attempt_at, id)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_message_batch_items_batch_status
+    ON message_batch_items(batch_id, status);
+
+COMMENT ON TABLE message_batch_items IS 'Message Batches 批内请求（执行队列与结果）';
+COMMENT ON COLUMN message_batch_items.params IS 'Messages API 请求体';
+COMMENT ON COLUMN message_batch_items.status IS 'pending / succeeded / errored / canceled / expired';
+COMMENT ON COLUMN message_batch_items.attempts IS '因上游错误失败的执行次数（账号繁忙导致的延后不计入）';
+COMMENT ON COLUMN message_batch_items.next_attempt_at IS '下次可执行时间；执行中会临时后延，防止多实例重复执行';
+COMMENT ON COLUMN message_batch_items.result IS '终态结果：{"type":"succeeded","message":...} 或 {"type":"errored","error":...}';
@@ -0,0 +1,93 @@
+-- OpenAI Files 与 Batch API 兼容：文件元数据、批次与批内请求持久化，由后台 worker 以低优先级异步执行。
+CREATE TABLE IF NOT EXISTS openai_files (
+    id VARCHAR(64) PRIMARY KEY,
+    seq BIGSERIAL NOT NULL UNIQUE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    purpose VARCHAR(32) NOT NULL,
+    filename VARCHAR(255) NOT NULL,
+    bytes BIGINT NOT NULL DEFAULT 0,
+    storage VARCHAR(16) NOT NULL,
+    object_key TEXT NOT NULL,
+    expires_at TIMESTAMPTZ NOT NULL,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_openai_files_user_seq ON openai_files(user_id, seq DESC);
+CREATE INDEX IF NOT EXISTS idx_openai_files_expires_at ON openai_files(expires_at);
+
+COMMENT ON TABLE openai_files IS 'OpenAI Files API 兼容文件（批处理输入与结果文件）';
+COMMENT ON COLUMN openai_files.seq IS '创建顺序，用于列表游标分页';
+COMMENT ON COLUMN openai_files.purpose IS 'batch（用户上传的批处理输入）/ batch_output（批处理结果与错误文件）';
+COMMENT ON COLUMN openai_files.storage IS '文件存储位置：local / s3';
+COMMENT ON COLUMN openai_files.object_key IS '本地相对路径或 S3 object key';
+
+CREATE TABLE IF NOT EXISTS openai_batches (
+    id VARCHAR(64) PRIMARY KEY,
+    seq BIGSERIAL NOT NULL UNIQUE,
+    user_id BIGINT NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+    api_key_id BIGINT NOT NULL,
+    group_id BIGINT DEFAULT NULL,
+    endpoint VARCHAR(64) NOT NULL,
+    input_file_id VARCHAR(64) NOT NULL,
+    completion_window VARCHAR(16) NOT NULL DEFAULT '24h',
+    status VARCHAR(20) NOT NULL DEFAULT 'validating',
+    output_file_id VARCHAR(64),
+    error_file_id VARCHAR(64),
+    errors JSONB,
+    metadata JSONB,
+    locked_until TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    expires_at TIMESTAMPTZ NOT NULL,
+    in_progress_at TIMESTAMPTZ,
+    finalizing_at TIMESTAMPTZ,
+    completed_at TIMESTAMPTZ,
+    failed_at TIMESTAMPTZ,
+    expired_at TIMESTAMPTZ,
+    cancelling_at TIMESTAMPTZ,
+    cancelled_at TIMESTAMPTZ,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_openai_batches_user_seq ON openai_batches(user_id, seq DESC);
+CREATE INDEX IF NOT EXISTS idx_openai_batches_open
+    ON openai_batches(status, locked_until)
+    WHERE status IN ('validating', 'in_progress', 'finalizing', 'cancelling');
+CREATE INDEX IF NOT EXISTS idx_openai_batches_updated_at
+    ON openai_batches(updated_at)
+    WHERE status IN ('completed', 'failed', 'expired', 'cancelled');
+
+COMMENT ON TABLE openai_batches IS 'OpenAI Batch API 兼容批次';
+COMMENT ON COLUMN openai_batches.api_key_id IS '创建批次的 API Key，批内请求按该 Key 调度与计费';
+COMMENT ON COLUMN openai_batches.status IS 'validating / failed / in_progress / finalizing / completed / expired / cancelling / cancelled';
+COMMENT ON COLUMN openai_batches.errors IS '输入文件校验失败的错误列表（status=failed）';
+COMMENT ON COLUMN openai_batches.locked_until IS '校验输入文件、生成结果文件等阶段任务的租约，防止多实例重复处理';
+
+CREATE TABLE IF NOT EXISTS openai_batch_items (
+    id BIGSERIAL PRIMARY KEY,
+    batch_id VARCHAR(64) NOT NULL REFERENCES openai_batches(id) ON DELETE CASCADE,
+    line_no INT NOT NULL,
+    custom_id VARCHAR(512) NOT NULL,
+    body JSONB NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending',
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    output JSONB,
+    created_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    updated_at TIMESTAMPTZ NOT NULL DEFAULT NOW(),
+    UNIQUE (batch_id, custom_id)
+);
+
+CREATE INDEX IF NOT EXISTS idx_openai_batch_items_pending
+    ON openai_batch_items(next_attempt_at, id)
+    WHERE status = 'pending';
+
+CREATE INDEX IF NOT EXISTS idx_openai_batch_items_batch_status
+    ON openai_batch_items(batch_id, status);
+
+COMMENT ON TABLE openai_batch_items IS 'OpenAI Batch 批内请求（执行队列与结果）';
+COMMENT ON COLUMN openai_batch_items.line_no IS '输入文件中的行号（从 1 开始）';
+COMMENT ON COLUMN openai_batch_items.body IS '请求体（endpoint 由批次决定）';
+COMMENT ON COLUMN openai_batch_items.status IS 'pending / completed / failed / cancelled / expired';
+COMMENT ON COLUMN openai_batch_items.attempts IS '因上游错误失败的执行次数（账号繁忙导致的延后不计入）';
+COMMENT ON COLUMN openai_batch_items.next_attempt_at IS '下次可执行时间；执行中会临时后延，防止多实例重复执行';
+COMMENT ON COLUMN openai_batch_items.output IS '结果文件中的一行：{"id","custom_id","response","error"}';
@@ -0,0 +1,6 @@
+-- 090: 删除 user_webhook_deliveries.response_body 列
+--
+-- 用户 Webhook 投递记录对用户可见，保存接收方响应体会让 Webhook 成为探测内部服务的回显通道；
+-- 投递结果现在只记录状态码与错误摘要。
+
+ALTER TABLE user_webhook_deliveries DROP COLUMN IF EXISTS response_body;
@@ -488,6 +488,7 @@ export async function exportData(options?: {
     search?: string
   }
   includeProxies?: boolean
+  passphrase?: string
 }): Promise<AdminDataPayload> {
   const params: Record<string, string> = {}
   if (options?.ids && options.ids.length > 0) {
@@ -502,17 +503,27 @@ export async function exportData(options?: {
   if (options?.includeProxies === false) {
     params.include_proxies = 'false'
   }
-  const { data } = await apiClient.get<AdminDataPayload>('/admin/accounts/data', { params })
+  // 口令通过请求头传递，避免出现在 URL 与访问日志中
+  const headers: Record<string, string> = {}
+  if (options?.passphrase) {
+    headers['X-Export-Passphrase'] = options.passphrase
+  }
+  const { data } = await apiClient.get<AdminDataPayload>('/admin/accounts/data', {
+    params,
+    headers
+  })
   return data
 }
 
 export async function importData(payload: {
   data: AdminDataPayload
   skip_default_group_bind?: boolean
+  passphrase?: string
 }): Promise<AdminDataImportResult> {
   const { data } = await apiClient.post<AdminDataImportResult>('/admin/accounts/data', {
     data: payload.data,
-    skip_default_group_bind: payload.skip_default_group_bind
+    skip_default_group_bind: payload.skip_default_group_bind,
+    passphrase: payload.passphrase
   })
   return data
 }
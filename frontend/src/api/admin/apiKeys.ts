@@ -4,7 +4,7 @@
  */
 
 import { apiClient } from '../client'
-import type { ApiKey } from '@/types'
+import type { ApiKey, ApiKeyModelPolicy } from '@/types'
 
 export interface UpdateApiKeyGroupResult {
   api_key: ApiKey
@@ -26,8 +26,20 @@ export async function updateApiKeyGroup(id: number, groupId: number | null): Pro
   return data
 }
 
+/**
+ * Update an API key's model allowlist/denylist/aliases
+ * @param id - API Key ID
+ * @param policy - Fields to update (omitted = unchanged, empty = cleared)
+ * @returns Updated API key
+ */
+export async function updateApiKeyModelPolicy(id: number, policy: ApiKeyModelPolicy): Promise<UpdateApiKeyGroupResult> {
+  const { data } = await apiClient.put<UpdateApiKeyGroupResult>(`/admin/api-keys/${id}`, policy)
+  return data
+}
+
 export const apiKeysAPI = {
-  updateApiKeyGroup
+  updateApiKeyGroup,
+  updateApiKeyModelPolicy
 }
 
 export default apiKeysAPI
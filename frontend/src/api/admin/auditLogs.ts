@@ -0,0 +1,77 @@
+/**
+ * Admin Audit Log API endpoints
+ */
+
+import { apiClient } from '../client'
+import type { PaginatedResponse } from '@/types'
+
+export interface AdminAuditChange {
+  before?: unknown
+  after?: unknown
+}
+
+export interface AdminAuditLog {
+  id: number
+  actor_type: 'jwt' | 'admin_api_key' | 'admin_api_token'
+  actor_user_id: number | null
+  actor_token_id: number | null
+  actor_token_name: string
+  method: string
+  route: string
+  path: string
+  target_type: string
+  target_id: string
+  status_code: number
+  client_ip: string
+  request_body: unknown
+  diff: Record<string, AdminAuditChange> | null
+  created_at: string
+}
+
+export interface AdminAuditLogFilters {
+  actor_user_id?: number
+  actor_type?: string
+  target_type?: string
+  target_id?: string
+  method?: string
+  start_date?: string
+  end_date?: string
+  timezone?: string
+}
+
+export async function list(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: AdminAuditLogFilters
+): Promise<PaginatedResponse<AdminAuditLog>> {
+  const { data } = await apiClient.get<PaginatedResponse<AdminAuditLog>>('/admin/audit-logs', {
+    params: {
+      page,
+      page_size: pageSize,
+      ...filters
+    }
+  })
+  return data
+}
+
+/**
+ * Export audit logs (oldest first)
+ * @returns CSV or JSONL data as blob
+ */
+export async function exportLogs(
+  format: 'csv' | 'jsonl',
+  filters?: AdminAuditLogFilters
+): Promise<Blob> {
+  const response = await apiClient.get('/admin/audit-logs/export', {
+    params: { format, ...filters },
+    responseType: 'blob'
+  })
+  return response.data
+}
+
+export const auditLogsAPI = {
+  list,
+  exportLogs
+}
+
+export default auditLogsAPI
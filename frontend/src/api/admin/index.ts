@@ -24,6 +24,10 @@ import dataManagementAPI from './dataManagement'
 import apiKeysAPI from './apiKeys'
 import scheduledTestsAPI from './scheduledTests'
 import ordersAPI from './orders'
+import organizationsAPI from './organizations'
+import auditLogsAPI from './auditLogs'
+import statementsAPI from './statements'
+import proxyPoolsAPI from './proxyPools'
 
 /**
  * Unified admin API object for convenient access
@@ -49,7 +53,11 @@ export const adminAPI = {
   dataManagement: dataManagementAPI,
   apiKeys: apiKeysAPI,
   scheduledTests: scheduledTestsAPI,
-  orders: ordersAPI
+  orders: ordersAPI,
+  organizations: organizationsAPI,
+  auditLogs: auditLogsAPI,
+  statements: statementsAPI,
+  proxyPools: proxyPoolsAPI
 }
 
 export {
@@ -73,7 +81,11 @@ export {
   dataManagementAPI,
   apiKeysAPI,
   scheduledTestsAPI,
-  ordersAPI
+  ordersAPI,
+  organizationsAPI,
+  auditLogsAPI,
+  statementsAPI,
+  proxyPoolsAPI
 }
 
 export default adminAPI
@@ -82,3 +94,6 @@ export default adminAPI
 export type { BalanceHistoryItem } from './users'
 export type { ErrorPassthroughRule, CreateRuleRequest, UpdateRuleRequest } from './errorPassthrough'
 export type { BackupAgentHealth, DataManagementConfig } from './dataManagement'
+export type { AdminAuditLog, AdminAuditChange, AdminAuditLogFilters } from './auditLogs'
+export type { StatementFilters, GenerateStatementsResult } from './statements'
+export type { CreateProxyPoolRequest, UpdateProxyPoolRequest } from './proxyPools'
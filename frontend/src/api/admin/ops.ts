@@ -507,6 +507,62 @@ export async function getRealtimeTrafficSummary(
   return data
 }
 
+export interface OpsGatewaySchedulerMetrics {
+  health_score_enabled: boolean
+  select_total: number
+  select_error_total: number
+  sticky_or_routed_total: number
+  load_balance_select_total: number
+  legacy_order_select_total: number
+  fallback_wait_total: number
+  health_scored_select_total: number
+  selected_score_avg: number
+  candidate_count_avg: number
+  scheduler_latency_ms_total: number
+  scheduler_latency_ms_avg: number
+  overload_event_total: number
+  token_refresh_failure_total: number
+  runtime_stats_account_count: number
+}
+
+export interface OpsOpenAISchedulerMetrics {
+  select_total: number
+  sticky_previous_hit_total: number
+  sticky_session_hit_total: number
+  load_balance_select_total: number
+  account_switch_total: number
+  scheduler_latency_ms_avg: number
+  sticky_hit_ratio: number
+  account_switch_rate: number
+  load_skew_avg: number
+  runtime_stats_account_count: number
+}
+
+export interface OpsAccountHealthSnapshot {
+  account_id: number
+  error_rate: number
+  ttft_ms?: number
+  overload_density: number
+  token_refresh_failures: number
+  health_score: number
+}
+
+export interface OpsSchedulerMetricsResponse {
+  gateway: OpsGatewaySchedulerMetrics
+  openai: OpsOpenAISchedulerMetrics
+  accounts: OpsAccountHealthSnapshot[]
+  timestamp: string
+}
+
+export async function getSchedulerMetrics(limit?: number): Promise<OpsSchedulerMetricsResponse> {
+  const params: Record<string, any> = {}
+  if (typeof limit === 'number' && limit > 0) {
+    params.limit = limit
+  }
+  const { data } = await apiClient.get<OpsSchedulerMetricsResponse>('/admin/ops/scheduler-metrics', { params })
+  return data
+}
+
 /**
  * Subscribe to realtime QPS updates via WebSocket.
  *
@@ -757,12 +813,27 @@ export interface AlertRule {
   severity: OpsSeverity
   cooldown_minutes: number
   notify_email: boolean
+  notify_channels?: AlertNotifyChannel[]
   filters?: Record<string, any>
   created_at?: string
   updated_at?: string
   last_triggered_at?: string | null
 }
 
+export type AlertNotifyChannelType = 'webhook' | 'slack' | 'telegram' | 'dingtalk' | 'feishu'
+
+export interface AlertNotifyChannel {
+  type: AlertNotifyChannelType
+  name?: string
+  enabled: boolean
+  min_severity?: 'critical' | 'warning' | 'info' | ''
+  notify_resolved: boolean
+  url?: string
+  secret?: string
+  bot_token?: string
+  chat_id?: string
+}
+
 export interface AlertEvent {
   id: number
   rule_id: number
@@ -832,6 +903,7 @@ export interface OpsAlertRuntimeSettings {
     }>
   }
   thresholds: OpsMetricThresholds // 指标阈值配置
+  notification_rate_limit_per_hour?: number
 }
 
 export interface OpsAdvancedSettings {
@@ -843,6 +915,7 @@ export interface OpsAdvancedSettings {
   ignore_invalid_api_key_errors: boolean
   auto_refresh_enabled: boolean
   auto_refresh_interval_seconds: number
+  trace_url_template?: string
 }
 
 export interface OpsDataRetentionSettings {
@@ -953,6 +1026,7 @@ export interface OpsErrorLog {
 
   client_request_id: string
   request_id: string
+  trace_id?: string
   message: string
 
   user_id?: number | null
@@ -1365,6 +1439,7 @@ export const opsAPI = {
   getUserConcurrencyStats,
   getAccountAvailabilityStats,
   getRealtimeTrafficSummary,
+  getSchedulerMetrics,
   subscribeQPS,
 
   // Legacy unified endpoints
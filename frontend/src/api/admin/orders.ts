@@ -10,6 +10,7 @@ export async function list(
   pageSize: number = 20,
   filters?: {
     status?: string
+    order_type?: string
     order_no?: string
     user_id?: number
     group_id?: number
@@ -40,6 +41,11 @@ export async function cancel(id: number): Promise<AdminSubscriptionOrder> {
   return data
 }
 
-export const ordersAPI = { list, getById, markPaid, cancel }
+export async function refund(id: number, reason?: string): Promise<AdminSubscriptionOrder> {
+  const { data } = await apiClient.post<AdminSubscriptionOrder>(`/admin/orders/${id}/refund`, { reason })
+  return data
+}
+
+export const ordersAPI = { list, getById, markPaid, cancel, refund }
 
 export default ordersAPI
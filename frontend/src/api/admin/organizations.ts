@@ -0,0 +1,83 @@
+/**
+ * Admin Organizations API endpoints
+ */
+
+import { apiClient } from '../client'
+import type {
+  PaginatedResponse,
+  Organization,
+  OrganizationMember,
+  OrganizationUsageSummary
+} from '@/types'
+
+export async function list(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: {
+    search?: string
+    status?: string
+  }
+): Promise<PaginatedResponse<Organization>> {
+  const { data } = await apiClient.get<PaginatedResponse<Organization>>('/admin/organizations', {
+    params: {
+      page,
+      page_size: pageSize,
+      ...filters
+    }
+  })
+  return data
+}
+
+export async function getById(id: number): Promise<Organization> {
+  const { data } = await apiClient.get<Organization>(`/admin/organizations/${id}`)
+  return data
+}
+
+export async function listMembers(id: number): Promise<OrganizationMember[]> {
+  const { data } = await apiClient.get<OrganizationMember[]>(`/admin/organizations/${id}/members`)
+  return data
+}
+
+export async function updateBalance(
+  id: number,
+  amount: number,
+  operation: 'set' | 'add' | 'subtract'
+): Promise<Organization> {
+  const { data } = await apiClient.post<Organization>(`/admin/organizations/${id}/balance`, {
+    amount,
+    operation
+  })
+  return data
+}
+
+export async function updateStatus(
+  id: number,
+  status: 'active' | 'disabled'
+): Promise<Organization> {
+  const { data } = await apiClient.put<Organization>(`/admin/organizations/${id}/status`, {
+    status
+  })
+  return data
+}
+
+export async function getUsage(
+  id: number,
+  params?: { start_date?: string; end_date?: string }
+): Promise<OrganizationUsageSummary> {
+  const { data } = await apiClient.get<OrganizationUsageSummary>(
+    `/admin/organizations/${id}/usage`,
+    { params }
+  )
+  return data
+}
+
+export const organizationsAPI = {
+  list,
+  getById,
+  listMembers,
+  updateBalance,
+  updateStatus,
+  getUsage
+}
+
+export default organizationsAPI
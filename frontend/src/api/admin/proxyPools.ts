@@ -0,0 +1,109 @@
+/**
+ * Admin proxy pool API endpoints
+ * Accounts bound to a pool egress through a healthy member, sticky per account
+ */
+
+import { apiClient } from '../client'
+import type { PaginatedResponse, ProxyPool } from '@/types'
+
+export interface CreateProxyPoolRequest {
+  name: string
+  description?: string
+  proxy_ids?: number[]
+}
+
+export interface UpdateProxyPoolRequest {
+  name?: string
+  description?: string
+  status?: 'active' | 'disabled'
+  proxy_ids?: number[]
+}
+
+/**
+ * List proxy pools
+ */
+export async function list(
+  page: number = 1,
+  pageSize: number = 20,
+  search?: string,
+  options?: { signal?: AbortSignal }
+): Promise<PaginatedResponse<ProxyPool>> {
+  const { data } = await apiClient.get<PaginatedResponse<ProxyPool>>('/admin/proxy-pools', {
+    params: { page, page_size: pageSize, search },
+    signal: options?.signal
+  })
+  return data
+}
+
+/**
+ * Get a proxy pool with member health
+ */
+export async function getById(id: number): Promise<ProxyPool> {
+  const { data } = await apiClient.get<ProxyPool>(`/admin/proxy-pools/${id}`)
+  return data
+}
+
+/**
+ * Create a proxy pool
+ */
+export async function create(payload: CreateProxyPoolRequest): Promise<ProxyPool> {
+  const { data } = await apiClient.post<ProxyPool>('/admin/proxy-pools', payload)
+  return data
+}
+
+/**
+ * Update a proxy pool; proxy_ids replaces the member list
+ */
+export async function update(id: number, payload: UpdateProxyPoolRequest): Promise<ProxyPool> {
+  const { data } = await apiClient.put<ProxyPool>(`/admin/proxy-pools/${id}`, payload)
+  return data
+}
+
+/**
+ * Delete a proxy pool (bound accounts are unbound)
+ */
+export async function deletePool(id: number): Promise<{ message: string }> {
+  const { data } = await apiClient.delete<{ message: string }>(`/admin/proxy-pools/${id}`)
+  return data
+}
+
+/**
+ * Run a health check on all pool members now
+ */
+export async function check(id: number): Promise<ProxyPool> {
+  const { data } = await apiClient.post<ProxyPool>(`/admin/proxy-pools/${id}/check`)
+  return data
+}
+
+/**
+ * Bind accounts to a proxy pool
+ */
+export async function bindAccounts(id: number, accountIds: number[]): Promise<{ affected: number }> {
+  const { data } = await apiClient.post<{ affected: number }>(`/admin/proxy-pools/${id}/accounts`, {
+    account_ids: accountIds
+  })
+  return data
+}
+
+/**
+ * Remove the proxy pool binding from accounts
+ */
+export async function unbindAccounts(accountIds: number[]): Promise<{ affected: number }> {
+  const { data } = await apiClient.post<{ affected: number }>('/admin/proxy-pools/unbind-accounts', {
+    account_ids: accountIds
+  })
+  return data
+}
+
+export const proxyPoolsAPI = {
+  list,
+  getById,
+  create,
+  update,
+  delete: deletePool,
+  check,
+  bindAccounts,
+  unbindAccounts
+}
+
+export default proxyPoolsAPI
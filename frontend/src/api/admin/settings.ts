@@ -49,6 +49,28 @@ export interface SystemSettings {
   xunhupay_notify_url: string
   xunhupay_return_url: string
   xunhupay_plugins: string
+  stripe_secret_key_configured: boolean
+  stripe_webhook_secret_configured: boolean
+  stripe_currency: string
+  stripe_success_url: string
+  stripe_cancel_url: string
+  alipay_app_id: string
+  alipay_private_key_configured: boolean
+  alipay_public_key: string
+  alipay_gateway: string
+  alipay_notify_url: string
+  alipay_return_url: string
+  wechatpay_app_id: string
+  wechatpay_mch_id: string
+  wechatpay_serial_no: string
+  wechatpay_private_key_configured: boolean
+  wechatpay_api_v3_key_configured: boolean
+  wechatpay_platform_public_key: string
+  wechatpay_notify_url: string
+  balance_topup_enabled: boolean
+  balance_topup_min_amount: number
+  balance_topup_max_amount: number
+  balance_topup_rate: number
   // SMTP settings
   smtp_host: string
   smtp_port: number
@@ -123,6 +145,28 @@ export interface UpdateSettingsRequest {
   xunhupay_notify_url?: string
   xunhupay_return_url?: string
   xunhupay_plugins?: string
+  stripe_secret_key?: string
+  stripe_webhook_secret?: string
+  stripe_currency?: string
+  stripe_success_url?: string
+  stripe_cancel_url?: string
+  alipay_app_id?: string
+  alipay_private_key?: string
+  alipay_public_key?: string
+  alipay_gateway?: string
+  alipay_notify_url?: string
+  alipay_return_url?: string
+  wechatpay_app_id?: string
+  wechatpay_mch_id?: string
+  wechatpay_serial_no?: string
+  wechatpay_private_key?: string
+  wechatpay_api_v3_key?: string
+  wechatpay_platform_public_key?: string
+  wechatpay_notify_url?: string
+  balance_topup_enabled?: boolean
+  balance_topup_min_amount?: number
+  balance_topup_max_amount?: number
+  balance_topup_rate?: number
   smtp_host?: string
   smtp_port?: number
   smtp_username?: string
@@ -254,6 +298,79 @@ export async function deleteAdminApiKey(): Promise<{ message: string }> {
   return data
 }
 
+/**
+ * Named admin API token with scoped access
+ */
+export interface AdminApiToken {
+  id: number
+  name: string
+  token_prefix: string
+  scopes: string[]
+  ip_whitelist: string[]
+  expires_at: string | null
+  last_used_at: string | null
+  last_used_ip: string
+  created_by: number | null
+  created_at: string
+  updated_at: string
+}
+
+export interface AdminApiTokenRequest {
+  name: string
+  scopes: string[]
+  ip_whitelist?: string[]
+  expires_at?: string | null
+}
+
+/**
+ * List grantable scope resources (each supports ":read" and ":write")
+ */
+export async function getAdminTokenScopes(): Promise<{ resources: string[] }> {
+  const { data } = await apiClient.get<{ resources: string[] }>('/admin/settings/admin-tokens/scopes')
+  return data
+}
+
+/**
+ * List named admin API tokens
+ */
+export async function listAdminTokens(): Promise<AdminApiToken[]> {
+  const { data } = await apiClient.get<AdminApiToken[]>('/admin/settings/admin-tokens')
+  return data
+}
+
+/**
+ * Create a named admin API token
+ * @returns The token and its full key (only shown once)
+ */
+export async function createAdminToken(
+  request: AdminApiTokenRequest
+): Promise<{ token: AdminApiToken; key: string }> {
+  const { data } = await apiClient.post<{ token: AdminApiToken; key: string }>(
+    '/admin/settings/admin-tokens',
+    request
+  )
+  return data
+}
+
+/**
+ * Update a named admin API token (replaces name, scopes, IP allowlist and expiry)
+ */
+export async function updateAdminToken(
+  id: number,
+  request: AdminApiTokenRequest
+): Promise<AdminApiToken> {
+  const { data } = await apiClient.put<AdminApiToken>(`/admin/settings/admin-tokens/${id}`, request)
+  return data
+}
+
+/**
+ * Revoke a named admin API token
+ */
+export async function deleteAdminToken(id: number): Promise<{ message: string }> {
+  const { data } = await apiClient.delete<{ message: string }>(`/admin/settings/admin-tokens/${id}`)
+  return data
+}
+
 /**
  * Stream timeout settings interface
  */
@@ -433,6 +550,11 @@ export const settingsAPI = {
   getAdminApiKey,
   regenerateAdminApiKey,
   deleteAdminApiKey,
+  getAdminTokenScopes,
+  listAdminTokens,
+  createAdminToken,
+  updateAdminToken,
+  deleteAdminToken,
   getStreamTimeoutSettings,
   updateStreamTimeoutSettings,
   getSoraS3Settings,
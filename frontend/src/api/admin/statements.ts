@@ -0,0 +1,81 @@
+/**
+ * Admin monthly statement API endpoints
+ */
+
+import { apiClient } from '../client'
+import type { PaginatedResponse, Statement } from '@/types'
+
+export interface StatementFilters {
+  user_id?: number
+  period?: string
+}
+
+export interface GenerateStatementsResult {
+  period: string
+  created: number
+  statement?: Statement
+}
+
+/**
+ * List statements
+ */
+export async function list(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: StatementFilters,
+  options?: { signal?: AbortSignal }
+): Promise<PaginatedResponse<Statement>> {
+  const { data } = await apiClient.get<PaginatedResponse<Statement>>('/admin/statements', {
+    params: { page, page_size: pageSize, ...filters },
+    signal: options?.signal
+  })
+  return data
+}
+
+/**
+ * Get a statement with line items
+ */
+export async function getById(id: number): Promise<Statement> {
+  const { data } = await apiClient.get<Statement>(`/admin/statements/${id}`)
+  return data
+}
+
+/**
+ * Get a statement rendered as a printable HTML document
+ */
+export async function getHtml(id: number): Promise<string> {
+  const { data } = await apiClient.get<string>(`/admin/statements/${id}/html`, {
+    responseType: 'text'
+  })
+  return data
+}
+
+/**
+ * Close a finished month now (all users with activity, or a single user)
+ * @param period - YYYY-MM, must be a month that has already ended
+ */
+export async function generate(period: string, userId?: number): Promise<GenerateStatementsResult> {
+  const { data } = await apiClient.post<GenerateStatementsResult>('/admin/statements/generate', {
+    period,
+    user_id: userId
+  })
+  return data
+}
+
+/**
+ * (Re)send a statement to the user's email address
+ */
+export async function sendEmail(id: number): Promise<Statement> {
+  const { data } = await apiClient.post<Statement>(`/admin/statements/${id}/send-email`)
+  return data
+}
+
+export const statementsAPI = {
+  list,
+  getById,
+  getHtml,
+  generate,
+  sendEmail
+}
+
+export default statementsAPI
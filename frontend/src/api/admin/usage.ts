@@ -4,7 +4,14 @@
  */
 
 import { apiClient } from '../client'
-import type { AdminUsageLog, UsageQueryParams, PaginatedResponse, UsageRequestType } from '@/types'
+import type {
+  AdminUsageLog,
+  UsageQueryParams,
+  PaginatedResponse,
+  UsageRequestType,
+  AdminUsageExportParams,
+  UsageExportJob
+} from '@/types'
 
 // ==================== Types ====================
 
@@ -189,6 +196,55 @@ export async function cancelCleanupTask(taskId: number): Promise<{ id: number; s
   return data
 }
 
+/**
+ * Export usage records (streamed, oldest first; admin only)
+ * @param params - Date range and filters (range limited by usage_export.max_sync_range_days)
+ * @returns CSV or JSONL data as blob
+ */
+export async function exportUsage(params: AdminUsageExportParams): Promise<Blob> {
+  const response = await apiClient.get('/admin/usage/export', {
+    params,
+    responseType: 'blob'
+  })
+  return response.data
+}
+
+/**
+ * Create an async usage export job (admin only)
+ * @param payload - Date range and filters
+ * @returns Created export job
+ */
+export async function createExportJob(payload: AdminUsageExportParams): Promise<UsageExportJob> {
+  const { data } = await apiClient.post<UsageExportJob>('/admin/usage/exports', payload)
+  return data
+}
+
+/**
+ * List usage export jobs (admin only)
+ * @param params - Query parameters for pagination
+ * @returns Paginated list of export jobs
+ */
+export async function listExportJobs(
+  params: { page?: number; page_size?: number },
+  options?: { signal?: AbortSignal }
+): Promise<PaginatedResponse<UsageExportJob>> {
+  const { data } = await apiClient.get<PaginatedResponse<UsageExportJob>>('/admin/usage/exports', {
+    params,
+    signal: options?.signal
+  })
+  return data
+}
+
+/**
+ * Get a usage export job with its download link (admin only)
+ * @param id - Export job ID
+ * @returns Export job details
+ */
+export async function getExportJob(id: number): Promise<UsageExportJob> {
+  const { data } = await apiClient.get<UsageExportJob>(`/admin/usage/exports/${id}`)
+  return data
+}
+
 export const adminUsageAPI = {
   list,
   getStats,
@@ -196,7 +252,11 @@ export const adminUsageAPI = {
   searchApiKeys,
   listCleanupTasks,
   createCleanupTask,
-  cancelCleanupTask
+  cancelCleanupTask,
+  exportUsage,
+  createExportJob,
+  listExportJobs,
+  getExportJob
 }
 
 export default adminUsageAPI
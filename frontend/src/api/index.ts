@@ -16,8 +16,12 @@ export { userAPI } from './user'
 export { redeemAPI, type RedeemHistoryItem } from './redeem'
 export { userGroupsAPI } from './groups'
 export { totpAPI } from './totp'
+export { ssoAPI } from './sso'
 export { default as announcementsAPI } from './announcements'
 export { purchaseAPI } from './purchase'
+export { organizationsAPI } from './organizations'
+export { statementsAPI } from './statements'
+export { webhooksAPI } from './webhooks'
 
 // Admin APIs
 export { adminAPI } from './admin'
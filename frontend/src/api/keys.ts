@@ -4,7 +4,14 @@
  */
 
 import { apiClient } from './client'
-import type { ApiKey, CreateApiKeyRequest, UpdateApiKeyRequest, PaginatedResponse } from '@/types'
+import type {
+  ApiKey,
+  ApiKeyModelPolicy,
+  ApiKeyThroughputLimits,
+  CreateApiKeyRequest,
+  UpdateApiKeyRequest,
+  PaginatedResponse
+} from '@/types'
 
 /**
  * List all API keys for current user
@@ -49,6 +56,8 @@ export async function getById(id: number): Promise<ApiKey> {
  * @param quota - Optional quota limit in USD (0 = unlimited)
  * @param expiresInDays - Optional days until expiry (undefined = never expires)
  * @param rateLimitData - Optional rate limit fields
+ * @param modelPolicy - Optional model allowlist/denylist/aliases
+ * @param organizationId - Optional organization ID (organization key billed from the shared balance)
  * @returns Created API key
  */
 export async function create(
@@ -59,7 +68,9 @@ export async function create(
   ipBlacklist?: string[],
   quota?: number,
   expiresInDays?: number,
-  rateLimitData?: { rate_limit_5h?: number; rate_limit_1d?: number; rate_limit_7d?: number }
+  rateLimitData?: { rate_limit_5h?: number; rate_limit_1d?: number; rate_limit_7d?: number } & ApiKeyThroughputLimits,
+  modelPolicy?: ApiKeyModelPolicy,
+  organizationId?: number | null
 ): Promise<ApiKey> {
   const payload: CreateApiKeyRequest = { name }
   if (groupId !== undefined) {
@@ -89,6 +100,28 @@ export async function create(
   if (rateLimitData?.rate_limit_7d && rateLimitData.rate_limit_7d > 0) {
     payload.rate_limit_7d = rateLimitData.rate_limit_7d
   }
+  if (rateLimitData?.rpm_limit && rateLimitData.rpm_limit > 0) {
+    payload.rpm_limit = rateLimitData.rpm_limit
+  }
+  if (rateLimitData?.tpm_limit && rateLimitData.tpm_limit > 0) {
+    payload.tpm_limit = rateLimitData.tpm_limit
+  }
+  if (rateLimitData?.max_concurrency && rateLimitData.max_concurrency > 0) {
+    payload.max_concurrency = rateLimitData.max_concurrency
+  }
+
+  if (modelPolicy?.allowed_models && modelPolicy.allowed_models.length > 0) {
+    payload.allowed_models = modelPolicy.allowed_models
+  }
+  if (modelPolicy?.denied_models && modelPolicy.denied_models.length > 0) {
+    payload.denied_models = modelPolicy.denied_models
+  }
+  if (modelPolicy?.model_aliases && Object.keys(modelPolicy.model_aliases).length > 0) {
+    payload.model_aliases = modelPolicy.model_aliases
+  }
+  if (organizationId) {
+    payload.organization_id = organizationId
+  }
 
   const { data } = await apiClient.post<ApiKey>('/keys', payload)
   return data
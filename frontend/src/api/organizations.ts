@@ -0,0 +1,100 @@
+/**
+ * Organization (team) API endpoints
+ * Shared balance, member roles and per-member spending caps
+ */
+
+import { apiClient } from './client'
+import type {
+  Organization,
+  OrganizationApiKey,
+  OrganizationDetail,
+  OrganizationMember,
+  OrganizationUsageSummary
+} from '@/types'
+
+/**
+ * List organizations the current user belongs to (with the user's membership)
+ */
+export async function list(): Promise<OrganizationMember[]> {
+  const { data } = await apiClient.get<OrganizationMember[]>('/organizations')
+  return data
+}
+
+export async function create(name: string): Promise<Organization> {
+  const { data } = await apiClient.post<Organization>('/organizations', { name })
+  return data
+}
+
+export async function getById(id: number): Promise<OrganizationDetail> {
+  const { data } = await apiClient.get<OrganizationDetail>(`/organizations/${id}`)
+  return data
+}
+
+export async function rename(id: number, name: string): Promise<Organization> {
+  const { data } = await apiClient.put<Organization>(`/organizations/${id}`, { name })
+  return data
+}
+
+export async function remove(id: number): Promise<void> {
+  await apiClient.delete(`/organizations/${id}`)
+}
+
+export async function listMembers(id: number): Promise<OrganizationMember[]> {
+  const { data } = await apiClient.get<OrganizationMember[]>(`/organizations/${id}/members`)
+  return data
+}
+
+export async function addMember(
+  id: number,
+  payload: { email: string; role?: 'admin' | 'member'; monthly_limit_usd?: number }
+): Promise<OrganizationMember> {
+  const { data } = await apiClient.post<OrganizationMember>(`/organizations/${id}/members`, payload)
+  return data
+}
+
+export async function updateMember(
+  id: number,
+  userId: number,
+  payload: { role?: 'admin' | 'member'; monthly_limit_usd?: number }
+): Promise<OrganizationMember> {
+  const { data } = await apiClient.put<OrganizationMember>(
+    `/organizations/${id}/members/${userId}`,
+    payload
+  )
+  return data
+}
+
+export async function removeMember(id: number, userId: number): Promise<void> {
+  await apiClient.delete(`/organizations/${id}/members/${userId}`)
+}
+
+export async function listKeys(id: number): Promise<OrganizationApiKey[]> {
+  const { data } = await apiClient.get<OrganizationApiKey[]>(`/organizations/${id}/keys`)
+  return data
+}
+
+export async function getUsage(
+  id: number,
+  params?: { start_date?: string; end_date?: string }
+): Promise<OrganizationUsageSummary> {
+  const { data } = await apiClient.get<OrganizationUsageSummary>(`/organizations/${id}/usage`, {
+    params
+  })
+  return data
+}
+
+export const organizationsAPI = {
+  list,
+  create,
+  getById,
+  rename,
+  remove,
+  listMembers,
+  addMember,
+  updateMember,
+  removeMember,
+  listKeys,
+  getUsage
+}
+
+export default organizationsAPI
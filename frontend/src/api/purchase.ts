@@ -3,7 +3,7 @@
  */
 
 import { apiClient } from './client'
-import type { Group, PaginatedResponse, SubscriptionOrder } from '@/types'
+import type { Group, PaginatedResponse, SubscriptionOrder, TopUpConfig } from '@/types'
 
 export async function listPlans(): Promise<Group[]> {
   const { data } = await apiClient.get<Group[]>('/purchase/plans')
@@ -31,6 +31,16 @@ export async function getOrder(id: number): Promise<SubscriptionOrder> {
   return data
 }
 
-export const purchaseAPI = { listPlans, createOrder, listOrders, getOrder }
+export async function getTopUpConfig(): Promise<TopUpConfig> {
+  const { data } = await apiClient.get<TopUpConfig>('/purchase/topup')
+  return data
+}
+
+export async function createTopUpOrder(payload: { amount: number; notes?: string }): Promise<SubscriptionOrder> {
+  const { data } = await apiClient.post<SubscriptionOrder>('/purchase/topup', payload)
+  return data
+}
+
+export const purchaseAPI = { listPlans, createOrder, listOrders, getOrder, getTopUpConfig, createTopUpOrder }
 
 export default purchaseAPI
@@ -0,0 +1,58 @@
+/**
+ * SSO API endpoints
+ * Handles generic OIDC / GitHub / Google sign-in and identity linking
+ */
+
+import { apiClient } from './client'
+import type { UserIdentity } from '@/types'
+
+function apiBase(): string {
+  const base = (import.meta.env.VITE_API_BASE_URL as string | undefined) || '/api/v1'
+  return base.replace(/\/$/, '')
+}
+
+/**
+ * Build the URL that starts the SSO sign-in flow (full-page navigation)
+ * @param providerId - Provider ID from public settings
+ * @param redirect - Frontend path to return to after sign-in
+ */
+export function getStartURL(providerId: string, redirect: string): string {
+  return `${apiBase()}/auth/sso/${encodeURIComponent(providerId)}/start?redirect=${encodeURIComponent(redirect)}`
+}
+
+/**
+ * List SSO identities linked to the current user
+ */
+export async function listIdentities(): Promise<UserIdentity[]> {
+  const { data } = await apiClient.get<UserIdentity[]>('/user/sso/identities')
+  return data
+}
+
+/**
+ * Start linking a provider to the current user
+ * @returns Provider authorization URL to navigate to
+ */
+export async function startLink(providerId: string, redirect = '/profile'): Promise<{ auth_url: string }> {
+  const { data } = await apiClient.post<{ auth_url: string }>(
+    `/user/sso/${encodeURIComponent(providerId)}/link`,
+    { redirect }
+  )
+  return data
+}
+
+/**
+ * Unlink an SSO identity from the current user
+ */
+export async function unlinkIdentity(id: number): Promise<{ message: string }> {
+  const { data } = await apiClient.delete<{ message: string }>(`/user/sso/identities/${id}`)
+  return data
+}
+
+export const ssoAPI = {
+  getStartURL,
+  listIdentities,
+  startLink,
+  unlinkIdentity
+}
+
+export default ssoAPI
@@ -0,0 +1,49 @@
+/**
+ * Monthly statement API endpoints
+ * Closed billing periods with per-model usage and balance line items
+ */
+
+import { apiClient } from './client'
+import type { PaginatedResponse, Statement } from '@/types'
+
+/**
+ * List the current user's statements (newest period first, without line items)
+ */
+export async function list(
+  page: number = 1,
+  pageSize: number = 20,
+  filters?: { period?: string },
+  options?: { signal?: AbortSignal }
+): Promise<PaginatedResponse<Statement>> {
+  const { data } = await apiClient.get<PaginatedResponse<Statement>>('/statements', {
+    params: { page, page_size: pageSize, ...filters },
+    signal: options?.signal
+  })
+  return data
+}
+
+/**
+ * Get a statement with line items
+ */
+export async function getById(id: number): Promise<Statement> {
+  const { data } = await apiClient.get<Statement>(`/statements/${id}`)
+  return data
+}
+
+/**
+ * Get a statement rendered as a printable HTML document (print to PDF in the browser)
+ */
+export async function getHtml(id: number): Promise<string> {
+  const { data } = await apiClient.get<string>(`/statements/${id}/html`, {
+    responseType: 'text'
+  })
+  return data
+}
+
+export const statementsAPI = {
+  list,
+  getById,
+  getHtml
+}
+
+export default statementsAPI
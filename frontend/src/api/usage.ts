@@ -10,7 +10,9 @@ import type {
   UsageStatsResponse,
   PaginatedResponse,
   TrendDataPoint,
-  ModelStat
+  ModelStat,
+  UsageExportParams,
+  UsageExportJob
 } from '@/types'
 
 // ==================== Dashboard Types ====================
@@ -257,6 +259,57 @@ export async function getDashboardApiKeysUsage(
   return data
 }
 
+// ==================== Export API ====================
+
+/**
+ * Export current user's usage records (streamed, oldest first)
+ * @param params - Date range and filters (range limited by usage_export.max_sync_range_days)
+ * @returns CSV or JSONL data as blob
+ */
+export async function exportUsage(params: UsageExportParams): Promise<Blob> {
+  const response = await apiClient.get('/usage/export', {
+    params,
+    responseType: 'blob'
+  })
+  return response.data
+}
+
+/**
+ * Create an async usage export job for larger date ranges
+ * @param payload - Date range and filters
+ * @returns Created export job
+ */
+export async function createExportJob(payload: UsageExportParams): Promise<UsageExportJob> {
+  const { data } = await apiClient.post<UsageExportJob>('/usage/exports', payload)
+  return data
+}
+
+/**
+ * List current user's usage export jobs
+ * @param params - Query parameters for pagination
+ * @returns Paginated list of export jobs
+ */
+export async function listExportJobs(
+  params: { page?: number; page_size?: number },
+  options?: { signal?: AbortSignal }
+): Promise<PaginatedResponse<UsageExportJob>> {
+  const { data } = await apiClient.get<PaginatedResponse<UsageExportJob>>('/usage/exports', {
+    params,
+    signal: options?.signal
+  })
+  return data
+}
+
+/**
+ * Get a usage export job (includes a signed download_url once succeeded)
+ * @param id - Export job ID
+ * @returns Export job details
+ */
+export async function getExportJob(id: number): Promise<UsageExportJob> {
+  const { data } = await apiClient.get<UsageExportJob>(`/usage/exports/${id}`)
+  return data
+}
+
 export const usageAPI = {
   list,
   query,
@@ -268,7 +321,12 @@ export const usageAPI = {
   getDashboardStats,
   getDashboardTrend,
   getDashboardModels,
-  getDashboardApiKeysUsage
+  getDashboardApiKeysUsage,
+  // Export
+  exportUsage,
+  createExportJob,
+  listExportJobs,
+  getExportJob
 }
 
 export default usageAPI
@@ -4,7 +4,7 @@
  */
 
 import { apiClient } from './client'
-import type { User, ChangePasswordRequest } from '@/types'
+import type { User, ChangePasswordRequest, UserNotificationSettings } from '@/types'
 
 /**
  * Get current user profile
@@ -45,10 +45,33 @@ export async function changePassword(
   return data
 }
 
+/**
+ * Get current user's alert email settings
+ * @returns Notification settings (defaults with enabled=false if never saved)
+ */
+export async function getNotificationSettings(): Promise<UserNotificationSettings> {
+  const { data } = await apiClient.get<UserNotificationSettings>('/user/notifications')
+  return data
+}
+
+/**
+ * Update current user's alert email settings (0 disables a threshold)
+ * @param settings - Fields to update
+ * @returns Updated notification settings
+ */
+export async function updateNotificationSettings(
+  settings: Partial<UserNotificationSettings>
+): Promise<UserNotificationSettings> {
+  const { data } = await apiClient.put<UserNotificationSettings>('/user/notifications', settings)
+  return data
+}
+
 export const userAPI = {
   getProfile,
   updateProfile,
-  changePassword
+  changePassword,
+  getNotificationSettings,
+  updateNotificationSettings
 }
 
 export default userAPI
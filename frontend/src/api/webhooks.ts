@@ -0,0 +1,122 @@
+/**
+ * Outbound webhook API endpoints
+ * Signed HTTP callbacks for user-facing events, with delivery log and redelivery
+ */
+
+import { apiClient } from './client'
+import type {
+  CreateUserWebhookRequest,
+  PaginatedResponse,
+  UpdateUserWebhookRequest,
+  UserWebhook,
+  UserWebhookDelivery,
+  UserWebhookEventType
+} from '@/types'
+
+/**
+ * List the event types that can be subscribed to
+ */
+export async function listEvents(): Promise<UserWebhookEventType[]> {
+  const { data } = await apiClient.get<UserWebhookEventType[]>('/webhooks/events')
+  return data
+}
+
+/**
+ * List the current user's webhooks
+ */
+export async function list(): Promise<UserWebhook[]> {
+  const { data } = await apiClient.get<UserWebhook[]>('/webhooks')
+  return data
+}
+
+/**
+ * Get a webhook by ID
+ */
+export async function getById(id: number): Promise<UserWebhook> {
+  const { data } = await apiClient.get<UserWebhook>(`/webhooks/${id}`)
+  return data
+}
+
+/**
+ * Create a webhook; the signing secret is only returned in this response
+ */
+export async function create(payload: CreateUserWebhookRequest): Promise<UserWebhook> {
+  const { data } = await apiClient.post<UserWebhook>('/webhooks', payload)
+  return data
+}
+
+/**
+ * Update a webhook
+ */
+export async function update(id: number, payload: UpdateUserWebhookRequest): Promise<UserWebhook> {
+  const { data } = await apiClient.put<UserWebhook>(`/webhooks/${id}`, payload)
+  return data
+}
+
+/**
+ * Delete a webhook together with its delivery log
+ */
+export async function deleteWebhook(id: number): Promise<{ message: string }> {
+  const { data } = await apiClient.delete<{ message: string }>(`/webhooks/${id}`)
+  return data
+}
+
+/**
+ * Regenerate the signing secret; the new secret is only returned in this response
+ */
+export async function rotateSecret(id: number): Promise<UserWebhook> {
+  const { data } = await apiClient.post<UserWebhook>(`/webhooks/${id}/rotate-secret`)
+  return data
+}
+
+/**
+ * Send a webhook.ping event synchronously and return the delivery result
+ */
+export async function sendTest(id: number): Promise<UserWebhookDelivery> {
+  const { data } = await apiClient.post<UserWebhookDelivery>(`/webhooks/${id}/test`)
+  return data
+}
+
+/**
+ * List a webhook's delivery log (newest first)
+ */
+export async function listDeliveries(
+  id: number,
+  page: number = 1,
+  pageSize: number = 20,
+  options?: { signal?: AbortSignal }
+): Promise<PaginatedResponse<UserWebhookDelivery>> {
+  const { data } = await apiClient.get<PaginatedResponse<UserWebhookDelivery>>(
+    `/webhooks/${id}/deliveries`,
+    {
+      params: { page, page_size: pageSize },
+      signal: options?.signal
+    }
+  )
+  return data
+}
+
+/**
+ * Re-queue a finished delivery
+ */
+export async function redeliver(id: number, deliveryId: number): Promise<UserWebhookDelivery> {
+  const { data } = await apiClient.post<UserWebhookDelivery>(
+    `/webhooks/${id}/deliveries/${deliveryId}/redeliver`
+  )
+  return data
+}
+
+export const webhooksAPI = {
+  listEvents,
+  list,
+  getById,
+  create,
+  update,
+  delete: deleteWebhook,
+  rotateSecret,
+  sendTest,
+  listDeliveries,
+  redeliver
+}
+
+export default webhooksAPI
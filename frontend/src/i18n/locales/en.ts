@@ -352,7 +352,9 @@ export default {
     mySubscriptions: 'My Subscriptions',
     buySubscription: 'Recharge / Subscription',
     docs: 'Docs',
-    sora: 'Sora Studio'
+    sora: 'Sora Studio',
+    organizations: 'Organizations',
+    auditLogs: 'Audit Log'
   },
 
   // Auth
@@ -436,6 +438,14 @@ export default {
       callbackMissingToken: 'Missing login token, please try again.',
       backToLogin: 'Back to Login'
     },
+    sso: {
+      signInWith: 'Continue with {name}',
+      callbackTitle: 'Signing you in',
+      callbackProcessing: 'Completing sign-in, please wait...',
+      callbackHint: 'If you are not redirected automatically, go back to the login page and try again.',
+      callbackMissingToken: 'Missing login token, please try again.',
+      backToProfile: 'Back to Profile'
+    },
     oauth: {
       code: 'Code',
       state: 'State',
@@ -552,7 +562,15 @@ export default {
     saving: 'Saving...',
     noKeysYet: 'No API keys yet',
     createFirstKey: 'Create your first API key to get started with the API.',
+    organizationLabel: 'Organization',
+    organizationHint: 'Organization keys are billed to the shared organization balance',
+    personalKey: 'Personal (my balance)',
     keyCreatedSuccess: 'API key created successfully',
+    keyCreatedTitle: 'Save your API key',
+    keyCreatedWarning: 'This is the only time the full key will be shown. Copy it and store it somewhere safe; if you lose it, delete this key and create a new one.',
+    keySavedConfirm: 'I have saved it',
+    keyPrefixHint: 'Only the key prefix is stored; the full key was shown once at creation',
+    fullKeyPlaceholder: 'YOUR_API_KEY',
     keyUpdatedSuccess: 'API key updated successfully',
     keyDeletedSuccess: 'API key deleted successfully',
     keyEnabledSuccess: 'API key enabled successfully',
@@ -624,6 +642,17 @@ export default {
     ipBlacklist: 'IP Blacklist',
     ipBlacklistPlaceholder: '1.2.3.4\n5.6.0.0/16',
     ipBlacklistHint: 'One IP or CIDR per line. These IPs will be blocked from using this key.',
+    modelRestriction: 'Model Restriction',
+    allowedModels: 'Allowed Models',
+    allowedModelsPlaceholder: 'claude-*-haiku*\ngpt-5*',
+    allowedModelsHint: 'One model pattern per line, * matches any characters. Leave empty to allow all models.',
+    deniedModels: 'Denied Models',
+    deniedModelsPlaceholder: 'claude-opus-*',
+    deniedModelsHint: 'One model pattern per line. Denied models take precedence over the allowlist.',
+    modelAliases: 'Model Aliases',
+    modelAliasesPlaceholder: 'fast=claude-haiku-4-5\nsmart=claude-sonnet-4-5',
+    modelAliasesHint: 'One alias=model pair per line. Requests for the alias are sent as the target model, and the lists above apply to the target model.',
+    modelAliasesInvalid: 'Invalid model aliases: use one alias=model pair per line',
     ipRestrictionEnabled: 'IP restriction enabled',
     ccSwitchNotInstalled: 'CC-Switch is not installed or the protocol handler is not registered. Please install CC-Switch first or manually copy the API key.',
     ccsClientSelect: {
@@ -653,6 +682,11 @@ export default {
     rateLimit1d: 'Daily Limit (USD)',
     rateLimit7d: '7-Day Limit (USD)',
     rateLimitHint: 'Set the maximum spending for this key within each time window. 0 = unlimited.',
+    throughputLimits: 'Throughput Limits',
+    throughputLimitsHint: 'Requests over these limits are rejected with 429 and a Retry-After header. 0 = unlimited.',
+    rpmLimit: 'Requests / Minute',
+    tpmLimit: 'Tokens / Minute',
+    maxConcurrency: 'Max Concurrency',
     rateLimitUsage: 'Rate Limit Usage',
     resetRateLimitUsage: 'Reset Rate Limit Usage',
     resetRateLimitTitle: 'Confirm Reset Rate Limit',
@@ -809,6 +843,22 @@ export default {
     passwordTooShort: 'Password must be at least 8 characters long',
     passwordChangeSuccess: 'Password changed successfully',
     passwordChangeFailed: 'Failed to change password',
+    // SSO identities
+    sso: {
+      title: 'Linked Sign-in Methods',
+      description: 'Link external accounts to sign in with single sign-on',
+      notLinked: 'Not linked',
+      noEmail: 'No email',
+      lastLogin: 'Last sign-in',
+      link: 'Link',
+      unlink: 'Unlink',
+      unlinkTitle: 'Unlink Account',
+      unlinkConfirm: 'You will no longer be able to sign in with {name}. Continue?',
+      linkSuccess: 'Account linked successfully',
+      linkFailed: 'Failed to start linking',
+      unlinkSuccess: 'Account unlinked',
+      unlinkFailed: 'Failed to unlink account'
+    },
     // TOTP 2FA
     totp: {
       title: 'Two-Factor Authentication (2FA)',
@@ -908,6 +958,68 @@ export default {
     selectDateRange: 'Select date range'
   },
 
+  // Organizations
+  organizations: {
+    title: 'Organizations',
+    description: 'Share balance and API keys with your team',
+    create: 'Create Organization',
+    empty: 'No organizations yet',
+    emptyDesc: 'Create an organization to share balance and keys with your team',
+    balance: 'Shared Balance',
+    myMonthlyUsage: 'My usage this month',
+    disabled: 'Disabled',
+    rename: 'Rename',
+    delete: 'Delete',
+    leave: 'Leave',
+    tabs: {
+      members: 'Members',
+      keys: 'API Keys',
+      usage: 'Usage'
+    },
+    addMember: 'Add Member',
+    editMember: 'Edit Member',
+    removeMember: 'Remove Member',
+    removeMemberConfirm: 'Are you sure you want to remove this member? Their organization keys will stop working.',
+    roles: {
+      owner: 'Owner',
+      admin: 'Admin',
+      member: 'Member'
+    },
+    unlimited: 'Unlimited',
+    keysHint: 'Create organization keys from the API Keys page by choosing this organization',
+    usageManagerOnly: 'Only owners and admins can view the usage dashboard',
+    usage: {
+      requests: 'Requests',
+      tokens: 'Tokens',
+      actualCost: 'Actual Cost',
+      date: 'Date'
+    },
+    columns: {
+      user: 'User',
+      role: 'Role',
+      monthlyUsage: 'Monthly Usage',
+      actions: 'Actions',
+      name: 'Name',
+      key: 'Key',
+      createdBy: 'Created By',
+      group: 'Group',
+      status: 'Status',
+      lastUsedAt: 'Last Used'
+    },
+    name: 'Organization Name',
+    memberEmail: 'Member Email',
+    memberEmailHint: 'The user must already have an account',
+    role: 'Role',
+    monthlyLimit: 'Monthly Spending Cap (USD)',
+    monthlyLimitHint: '0 means unlimited',
+    deleteConfirm: 'Are you sure you want to delete this organization? All organization keys will stop working.',
+    loadFailed: 'Failed to load organizations',
+    saved: 'Saved successfully',
+    created: 'Organization created',
+    deleted: 'Organization deleted',
+    saveFailed: 'Failed to save'
+  },
+
   // Admin
   admin: {
     // Dashboard
@@ -1185,6 +1297,10 @@ export default {
       groupChangedSuccess: 'Group updated successfully',
       groupChangedWithGrant: 'Group updated. User auto-granted access to "{group}"',
       groupChangeFailed: 'Failed to update group',
+      modelPolicy: 'Models',
+      modelPolicyConfigured: 'Restricted',
+      modelPolicyUpdated: 'Model restriction updated',
+      modelPolicyUpdateFailed: 'Failed to update model restriction',
       noUsersYet: 'No users yet',
       createFirstUser: 'Create your first user to get started.',
       userCreated: 'User created successfully',
@@ -1462,6 +1578,27 @@ export default {
         defaultModelPlaceholder: 'e.g., gpt-4.1',
         defaultModelHint: 'When account has no model mapping configured, all request models will be mapped to this model'
       },
+      responseCache: {
+        title: 'Exact Response Cache',
+        enabled: 'Cache deterministic responses',
+        enabledHint: 'Byte-identical temperature 0 requests to /v1/messages and /v1/responses are answered from cache; streaming requests get a replayed stream',
+        ttl: 'Cache TTL (seconds)',
+        ttlHint: '0 uses the server default',
+        priceRatio: 'Cache hit price ratio',
+        priceRatioHint: 'Fraction of the normal price charged per hit, e.g. 0.1 = 10%; 0 = free. Hits never consume subscription quota'
+      },
+      modelFallback: {
+        title: 'Model Fallback Chains',
+        hint: 'When the requested model cannot be served, retry with the next model in the chain (e.g. claude-opus-* > claude-sonnet-4-5 > claude-haiku-4-5). Only the first model may end with *. Leave all conditions unchecked to fall back on any of them. Responses carry an X-Model-Fallback-From header.',
+        modelsPlaceholder: 'claude-opus-* > claude-sonnet-4-5 > claude-haiku-4-5',
+        conditions: {
+          rate_limited: 'All accounts rate limited',
+          overloaded: 'Upstream overloaded',
+          prompt_too_long: 'Prompt too long'
+        },
+        addChain: 'Add Chain',
+        removeChain: 'Remove Chain'
+      },
       invalidRequestFallback: {
         title: 'Invalid Request Fallback Group',
         hint: 'Triggered only when upstream explicitly returns prompt too long. Leave empty to disable fallback.',
@@ -1584,6 +1721,76 @@ export default {
     },
 
     // Orders
+    organizations: {
+      title: 'Organization Management',
+      description: 'Manage organizations, shared balances and members',
+      searchPlaceholder: 'Search organization name',
+      allStatus: 'All Status',
+      status: {
+        active: 'Active',
+        disabled: 'Disabled'
+      },
+      columns: {
+        name: 'Organization',
+        owner: 'Owner',
+        balance: 'Balance',
+        members: 'Members',
+        status: 'Status',
+        createdAt: 'Created At',
+        actions: 'Actions'
+      },
+      members: 'Members',
+      adjustBalance: 'Adjust Balance',
+      currentBalance: 'Current balance',
+      operation: 'Operation',
+      operations: {
+        add: 'Add',
+        subtract: 'Subtract',
+        set: 'Set'
+      },
+      amount: 'Amount (USD)',
+      enable: 'Enable',
+      disable: 'Disable',
+      changeStatus: 'Change Status',
+      enableConfirm: 'Enable organization "{name}"?',
+      disableConfirm: 'Disable organization "{name}"? All of its keys will be rejected.',
+      balanceUpdated: 'Balance updated',
+      statusUpdated: 'Status updated',
+      loadFailed: 'Failed to load organizations',
+      actionFailed: 'Operation failed'
+    },
+    auditLogs: {
+      title: 'Audit Log',
+      description: 'Append-only record of every mutating admin action, with redacted field-level changes',
+      exportCsv: 'Export CSV',
+      exportJsonl: 'Export JSONL',
+      exportFailed: 'Failed to export audit logs',
+      loadFailed: 'Failed to load audit logs',
+      targetTypePlaceholder: 'Target type, e.g. accounts',
+      targetIdPlaceholder: 'Target ID',
+      allActors: 'All actors',
+      allMethods: 'All methods',
+      viewDetail: 'Details',
+      changes: 'Changes',
+      noChanges: 'No field-level changes recorded',
+      field: 'Field',
+      before: 'Before',
+      after: 'After',
+      requestBody: 'Request Body (redacted)',
+      actorTypes: {
+        jwt: 'Admin login',
+        admin_api_key: 'Global Admin API Key',
+        admin_api_token: 'Admin API Token'
+      },
+      columns: {
+        time: 'Time',
+        actor: 'Actor',
+        request: 'Request',
+        target: 'Target',
+        status: 'Status',
+        actions: 'Actions'
+      }
+    },
     orders: {
       title: 'Order Management',
       description: 'Manage subscription purchase orders',
@@ -1602,10 +1809,24 @@ export default {
       confirmPaidMessage: 'Mark order {orderNo} as paid?',
       confirmCancelTitle: 'Confirm Cancel',
       confirmCancelMessage: 'Cancel order {orderNo}?',
+      allTypes: 'All Types',
+      orderType: {
+        subscription: 'Subscription',
+        balance: 'Balance top-up'
+      },
+      refund: 'Refund',
+      refundSuccess: 'Order refunded',
+      confirmRefundTitle: 'Confirm Refund',
+      confirmRefundMessage: 'Refund order {orderNo} in full? The granted subscription days will be revoked.',
+      confirmRefundBalanceMessage: 'Refund order {orderNo} in full? The credited balance will be deducted.',
+      refundReason: 'Refund reason',
+      refundReasonPlaceholder: 'Optional, sent to the payment provider',
       status: {
         pending: 'Pending',
         paid: 'Paid',
-        canceled: 'Canceled'
+        canceled: 'Canceled',
+        refunding: 'Refunding',
+        refunded: 'Refunded'
       },
       columns: {
         orderNo: 'Order No.',
@@ -1638,6 +1859,11 @@ export default {
       dataExport: 'Export',
       dataExportSelected: 'Export Selected',
       dataExportIncludeProxies: 'Include proxies linked to the exported accounts',
+      dataPassphrase: 'Passphrase',
+      dataExportPassphrasePlaceholder: 'Optional, at least 8 characters',
+      dataExportPassphraseHint: 'When set, account credentials in the file are encrypted and the same passphrase is required to import it',
+      dataImportPassphrasePlaceholder: 'Required for encrypted exports',
+      dataImportPassphraseHint: 'Leave empty for unencrypted files',
       dataImport: 'Import',
       dataExportConfirmMessage: 'The exported data contains sensitive account and proxy information. Store it securely.',
       dataExportConfirm: 'Confirm Export',
@@ -2913,6 +3139,7 @@ export default {
       allBillingTypes: 'All Billing Types',
       billingTypeBalance: 'Balance',
       billingTypeSubscription: 'Subscription',
+      billingTypeResponseCache: 'Response Cache',
       ipAddress: 'IP',
       cleanup: {
         button: 'Cleanup',
@@ -3234,6 +3461,8 @@ export default {
         },
         loading: 'Loading…',
         requestId: 'Request ID',
+        traceId: 'Trace ID',
+        openTrace: 'Open trace',
         time: 'Time',
         phase: 'Phase',
         status: 'Status',
@@ -3629,6 +3858,9 @@ export default {
         refreshInterval15s: '15 seconds',
         refreshInterval30s: '30 seconds',
         refreshInterval60s: '60 seconds',
+        tracing: 'Tracing',
+        traceUrlTemplate: 'Trace URL template',
+        traceUrlTemplateHint: 'Link error logs to your tracing UI. {placeholder} is replaced with the trace ID, e.g. {example}',
         autoRefreshCountdown: 'Auto refresh: {seconds}s',
         validation: {
           title: 'Please fix the following issues',
@@ -3875,7 +4107,45 @@ export default {
         xunhuReturnUrlPlaceholder: 'https://your-domain.com/purchase',
         xunhuPlugins: 'Payment Channel (plugins)',
         xunhuPluginsPlaceholder: 'wechat,alipay (optional)',
-        pluginsHint: 'Leave empty to allow default channel'
+        pluginsHint: 'Leave empty to allow default channel',
+        providerStripe: 'Stripe Checkout',
+        providerAlipay: 'Alipay (official)',
+        providerWechat: 'WeChat Pay (official, Native QR)',
+        stripeSecretKey: 'Stripe Secret Key',
+        stripeWebhookSecret: 'Webhook Signing Secret',
+        stripeWebhookHint: 'Point the Stripe webhook to https://your-domain.com/api/v1/payment/stripe/notify and subscribe to checkout.session events',
+        stripeCurrency: 'Currency',
+        stripeCurrencyPlaceholder: 'usd',
+        stripeSuccessUrl: 'Success URL',
+        stripeSuccessUrlPlaceholder: 'https://your-domain.com/purchase',
+        stripeCancelUrl: 'Cancel URL',
+        stripeCancelUrlPlaceholder: 'Defaults to the success URL',
+        alipayAppId: 'Alipay App ID',
+        alipayPrivateKey: 'App Private Key (RSA2)',
+        alipayPublicKey: 'Alipay Public Key',
+        alipayPublicKeyPlaceholder: 'Alipay public key (PEM or base64)',
+        alipayGateway: 'Gateway URL',
+        alipayGatewayPlaceholder: 'https://openapi.alipay.com/gateway.do',
+        alipayNotifyUrl: 'Notify URL',
+        alipayNotifyUrlPlaceholder: 'https://your-domain.com/api/v1/payment/alipay/notify',
+        alipayReturnUrl: 'Return URL',
+        wechatAppId: 'WeChat App ID',
+        wechatMchId: 'Merchant ID',
+        wechatSerialNo: 'Merchant Certificate Serial No.',
+        wechatPrivateKey: 'Merchant API Private Key',
+        wechatApiV3Key: 'APIv3 Key',
+        wechatApiV3KeyHint: 'Exactly 32 characters. Leave empty to keep existing key',
+        wechatPlatformPublicKey: 'Platform Public Key / Certificate',
+        wechatPlatformPublicKeyPlaceholder: 'WeChat Pay platform public key or certificate (PEM)',
+        wechatNotifyUrl: 'Notify URL',
+        wechatNotifyUrlPlaceholder: 'https://your-domain.com/api/v1/payment/wechatpay/notify',
+        topUpEnabled: 'Enable Balance Top-up',
+        topUpEnabledHint: 'Allow users to top up their balance through the selected payment provider',
+        topUpMinAmount: 'Minimum Amount',
+        topUpMaxAmount: 'Maximum Amount',
+        topUpMaxAmountHint: '0 means no limit',
+        topUpRate: 'Credit Rate',
+        topUpRateHint: 'Balance credited per unit of currency paid'
       },
       soraClient: {
         title: 'Sora Client',
@@ -3975,6 +4245,48 @@ export default {
         securityWarning: 'Warning: This key provides full admin access. Keep it secure.',
         usage: 'Usage: Add to request header - x-api-key: <your-admin-api-key>'
       },
+      adminTokens: {
+        title: 'Admin API Tokens',
+        description:
+          'Named tokens with scoped access for automation (billing reconciliation, provisioning, monitoring). Send them in the x-api-key header.',
+        create: 'Create Token',
+        edit: 'Edit Token',
+        empty: 'No admin API tokens yet',
+        name: 'Name',
+        scopes: 'Scopes',
+        fullAccess: 'Full access (all resources, read and write)',
+        access: {
+          none: 'None',
+          read: 'Read',
+          write: 'Read / Write'
+        },
+        resources: {
+          dashboard: 'Dashboard',
+          users: 'Users',
+          groups: 'Groups',
+          accounts: 'Accounts',
+          proxies: 'Proxies',
+          billing: 'Billing',
+          usage: 'Usage',
+          ops: 'Ops',
+          announcements: 'Announcements',
+          settings: 'Settings',
+          system: 'System',
+          audit: 'Audit Log'
+        },
+        ipWhitelist: 'IP Allowlist',
+        ipWhitelistHint: 'One IP or CIDR per line. Leave empty to allow any source.',
+        expiresAt: 'Expires',
+        expiresAtHint: 'Leave empty for a token that never expires',
+        lastUsed: 'Last used',
+        never: 'Never',
+        expired: 'Expired',
+        revoke: 'Revoke',
+        revokeConfirm: 'Revoke token "{name}"? Integrations using it will stop working immediately.',
+        revoked: 'Token revoked',
+        saved: 'Token updated',
+        scopesRequired: 'Select at least one scope'
+      },
       soraS3: {
         title: 'Sora S3 Storage',
         description: 'Manage multiple Sora S3 endpoints and switch the active profile',
@@ -4227,11 +4539,24 @@ export default {
       createdAt: 'Created At',
       actions: 'Actions'
     },
+    topUp: {
+      title: 'Top Up Balance',
+      description: 'Pay online to add balance to your account ({rate} balance per unit paid).',
+      amount: 'Amount',
+      credit: 'You will receive',
+      submit: 'Top Up',
+      range: 'Between {min} and {max}',
+      minOnly: 'Minimum {min}',
+      orderLabel: 'Balance top-up',
+      success: 'Payment successful. Your balance has been updated.'
+    },
     payNow: 'Pay Now',
     orderStatus: {
       pending: 'Pending',
       paid: 'Paid',
-      canceled: 'Canceled'
+      canceled: 'Canceled',
+      refunding: 'Refunding',
+      refunded: 'Refunded'
     }
   },
 
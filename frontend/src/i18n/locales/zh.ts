@@ -352,7 +352,9 @@ export default {
     mySubscriptions: '我的订阅',
     buySubscription: '充值/订阅',
     docs: '文档',
-    sora: 'Sora 创作'
+    sora: 'Sora 创作',
+    organizations: '组织',
+    auditLogs: '审计日志'
   },
 
   // Auth
@@ -435,6 +437,14 @@ export default {
       callbackMissingToken: '登录信息缺失，请返回重试。',
       backToLogin: '返回登录'
     },
+    sso: {
+      signInWith: '使用 {name} 登录',
+      callbackTitle: '正在完成登录',
+      callbackProcessing: '正在验证登录信息，请稍候...',
+      callbackHint: '如果页面未自动跳转，请返回登录页重试。',
+      callbackMissingToken: '登录信息缺失，请返回重试。',
+      backToProfile: '返回个人资料'
+    },
     oauth: {
       code: '授权码',
       state: '状态',
@@ -552,7 +562,15 @@ export default {
     saving: '保存中...',
     noKeysYet: '暂无 API 密钥',
     createFirstKey: '创建您的第一个 API 密钥以开始使用 API。',
+    organizationLabel: '所属组织',
+    organizationHint: '组织密钥从组织共享余额中扣费',
+    personalKey: '个人（使用我的余额）',
     keyCreatedSuccess: 'API 密钥创建成功',
+    keyCreatedTitle: '保存您的 API 密钥',
+    keyCreatedWarning: '完整密钥仅在此处显示一次，请立即复制并妥善保存；如果遗失，请删除该密钥并重新创建。',
+    keySavedConfirm: '我已保存',
+    keyPrefixHint: '系统仅保存密钥前缀，完整密钥只在创建时显示一次',
+    fullKeyPlaceholder: 'YOUR_API_KEY',
     keyUpdatedSuccess: 'API 密钥更新成功',
     keyDeletedSuccess: 'API 密钥删除成功',
     keyEnabledSuccess: 'API 密钥已启用',
@@ -628,6 +646,17 @@ export default {
     ipBlacklist: 'IP 黑名单',
     ipBlacklistPlaceholder: '1.2.3.4\n5.6.0.0/16',
     ipBlacklistHint: '每行一个 IP 或 CIDR，这些 IP 将被禁止使用此密钥',
+    modelRestriction: '模型限制',
+    allowedModels: '模型白名单',
+    allowedModelsPlaceholder: 'claude-*-haiku*\ngpt-5*',
+    allowedModelsHint: '每行一个模型规则，* 匹配任意字符；留空表示允许所有模型',
+    deniedModels: '模型黑名单',
+    deniedModelsPlaceholder: 'claude-opus-*',
+    deniedModelsHint: '每行一个模型规则，黑名单优先于白名单',
+    modelAliases: '模型别名',
+    modelAliasesPlaceholder: 'fast=claude-haiku-4-5\nsmart=claude-sonnet-4-5',
+    modelAliasesHint: '每行一个 别名=模型，请求别名时会改写为目标模型，黑白名单按目标模型校验',
+    modelAliasesInvalid: '模型别名格式错误：每行一个 别名=模型',
     ipRestrictionEnabled: '已配置 IP 限制',
     ccSwitchNotInstalled:
       'CC-Switch 未安装或协议处理程序未注册。请先安装 CC-Switch 或手动复制 API 密钥。',
@@ -658,6 +687,11 @@ export default {
     rateLimit1d: '日限额 (USD)',
     rateLimit7d: '7天限额 (USD)',
     rateLimitHint: '设置此密钥在指定时间窗口内的最大消费额。0 = 无限制。',
+    throughputLimits: '吞吐限制',
+    throughputLimitsHint: '超过限制的请求将返回 429 并携带 Retry-After 头。0 = 无限制。',
+    rpmLimit: '每分钟请求数',
+    tpmLimit: '每分钟 Token 数',
+    maxConcurrency: '最大并发数',
     rateLimitUsage: '速率限制用量',
     resetRateLimitUsage: '重置速率限制用量',
     resetRateLimitTitle: '确认重置速率限制',
@@ -814,6 +848,22 @@ export default {
     passwordTooShort: '密码至少需要 8 个字符',
     passwordChangeSuccess: '密码修改成功',
     passwordChangeFailed: '密码修改失败',
+    // SSO identities
+    sso: {
+      title: '第三方登录绑定',
+      description: '绑定外部账号后可通过单点登录进入',
+      notLinked: '未绑定',
+      noEmail: '无邮箱',
+      lastLogin: '最近登录',
+      link: '绑定',
+      unlink: '解除绑定',
+      unlinkTitle: '解除绑定',
+      unlinkConfirm: '解除后将无法再使用 {name} 登录，确定继续吗？',
+      linkSuccess: '绑定成功',
+      linkFailed: '发起绑定失败',
+      unlinkSuccess: '已解除绑定',
+      unlinkFailed: '解除绑定失败'
+    },
     // TOTP 2FA
     totp: {
       title: '双因素认证 (2FA)',
@@ -913,6 +963,68 @@ export default {
     selectDateRange: '选择日期范围'
   },
 
+  // Organizations
+  organizations: {
+    title: '组织',
+    description: '与团队共享余额和 API 密钥',
+    create: '创建组织',
+    empty: '暂无组织',
+    emptyDesc: '创建组织后即可与团队共享余额和密钥',
+    balance: '共享余额',
+    myMonthlyUsage: '我的本月用量',
+    disabled: '已停用',
+    rename: '重命名',
+    delete: '删除',
+    leave: '退出',
+    tabs: {
+      members: '成员',
+      keys: 'API 密钥',
+      usage: '用量'
+    },
+    addMember: '添加成员',
+    editMember: '编辑成员',
+    removeMember: '移除成员',
+    removeMemberConfirm: '确定要移除该成员吗？其组织密钥将立即失效。',
+    roles: {
+      owner: '所有者',
+      admin: '管理员',
+      member: '成员'
+    },
+    unlimited: '不限',
+    keysHint: '在 API 密钥页面创建密钥时选择该组织即可创建组织密钥',
+    usageManagerOnly: '仅所有者和管理员可查看用量看板',
+    usage: {
+      requests: '请求数',
+      tokens: 'Token 数',
+      actualCost: '实际费用',
+      date: '日期'
+    },
+    columns: {
+      user: '用户',
+      role: '角色',
+      monthlyUsage: '本月用量',
+      actions: '操作',
+      name: '名称',
+      key: '密钥',
+      createdBy: '创建者',
+      group: '分组',
+      status: '状态',
+      lastUsedAt: '最后使用'
+    },
+    name: '组织名称',
+    memberEmail: '成员邮箱',
+    memberEmailHint: '该用户需已注册账号',
+    role: '角色',
+    monthlyLimit: '月度消费上限（USD）',
+    monthlyLimitHint: '0 表示不限制',
+    deleteConfirm: '确定要删除该组织吗？所有组织密钥将失效。',
+    loadFailed: '加载组织失败',
+    saved: '保存成功',
+    created: '组织已创建',
+    deleted: '组织已删除',
+    saveFailed: '保存失败'
+  },
+
   // Admin
   admin: {
     // Dashboard
@@ -1212,6 +1324,10 @@ export default {
       groupChangedSuccess: '分组修改成功',
       groupChangedWithGrant: '分组修改成功，已自动为用户添加「{group}」分组权限',
       groupChangeFailed: '分组修改失败',
+      modelPolicy: '模型',
+      modelPolicyConfigured: '已限制',
+      modelPolicyUpdated: '模型限制已更新',
+      modelPolicyUpdateFailed: '模型限制更新失败',
       noUsersYet: '暂无用户',
       createFirstUser: '创建您的第一个用户以开始使用系统',
       userCreated: '用户创建成功',
@@ -1539,6 +1655,27 @@ export default {
         defaultModelPlaceholder: '例如: gpt-4.1',
         defaultModelHint: '当账号未配置模型映射时，所有请求模型将映射到此模型'
       },
+      responseCache: {
+        title: '精确响应缓存',
+        enabled: '缓存确定性响应',
+        enabledHint: '启用后，发往 /v1/messages 与 /v1/responses 且 temperature 为 0 的完全相同请求将直接返回缓存；流式请求回放为合成流',
+        ttl: '缓存有效期（秒）',
+        ttlHint: '0 表示使用服务端默认值',
+        priceRatio: '命中计费比例',
+        priceRatioHint: '命中时按正常价格的比例计费，如 0.1 表示 10%，0 表示免费；命中不消耗订阅额度'
+      },
+      modelFallback: {
+        title: '模型降级链',
+        hint: '请求模型无法服务时，按链路顺序改用下一个模型重试（如 claude-opus-* > claude-sonnet-4-5 > claude-haiku-4-5）。仅第一个模型支持末尾 * 通配。条件全部不勾选表示任一条件均触发。降级后的响应会带上 X-Model-Fallback-From 响应头。',
+        modelsPlaceholder: 'claude-opus-* > claude-sonnet-4-5 > claude-haiku-4-5',
+        conditions: {
+          rate_limited: '账号全部限流',
+          overloaded: '上游过载',
+          prompt_too_long: '提示词过长'
+        },
+        addChain: '添加降级链',
+        removeChain: '删除降级链'
+      },
       invalidRequestFallback: {
         title: '无效请求兜底分组',
         hint: '仅当上游明确返回 prompt too long 时才会触发，留空表示不兜底',
@@ -1661,6 +1798,76 @@ export default {
     },
 
     // Orders
+    organizations: {
+      title: '组织管理',
+      description: '管理组织、共享余额和成员',
+      searchPlaceholder: '搜索组织名称',
+      allStatus: '全部状态',
+      status: {
+        active: '正常',
+        disabled: '已停用'
+      },
+      columns: {
+        name: '组织',
+        owner: '所有者',
+        balance: '余额',
+        members: '成员数',
+        status: '状态',
+        createdAt: '创建时间',
+        actions: '操作'
+      },
+      members: '成员',
+      adjustBalance: '调整余额',
+      currentBalance: '当前余额',
+      operation: '操作类型',
+      operations: {
+        add: '增加',
+        subtract: '扣减',
+        set: '设置为'
+      },
+      amount: '金额（USD）',
+      enable: '启用',
+      disable: '停用',
+      changeStatus: '修改状态',
+      enableConfirm: '确定启用组织「{name}」吗？',
+      disableConfirm: '确定停用组织「{name}」吗？其所有密钥将被拒绝。',
+      balanceUpdated: '余额已更新',
+      statusUpdated: '状态已更新',
+      loadFailed: '加载组织失败',
+      actionFailed: '操作失败'
+    },
+    auditLogs: {
+      title: '审计日志',
+      description: '记录所有管理员写操作（只追加），包含脱敏后的字段级变更',
+      exportCsv: '导出 CSV',
+      exportJsonl: '导出 JSONL',
+      exportFailed: '导出审计日志失败',
+      loadFailed: '加载审计日志失败',
+      targetTypePlaceholder: '目标类型，如 accounts',
+      targetIdPlaceholder: '目标 ID',
+      allActors: '全部操作者',
+      allMethods: '全部方法',
+      viewDetail: '详情',
+      changes: '变更内容',
+      noChanges: '未记录字段级变更',
+      field: '字段',
+      before: '变更前',
+      after: '变更后',
+      requestBody: '请求体（已脱敏）',
+      actorTypes: {
+        jwt: '管理员登录',
+        admin_api_key: '全局 Admin API Key',
+        admin_api_token: '管理员 API Token'
+      },
+      columns: {
+        time: '时间',
+        actor: '操作者',
+        request: '请求',
+        target: '目标',
+        status: '状态',
+        actions: '操作'
+      }
+    },
     orders: {
       title: '订单管理',
       description: '管理订阅购买订单',
@@ -1679,10 +1886,24 @@ export default {
       confirmPaidMessage: '确认将订单 {orderNo} 标记为已支付？',
       confirmCancelTitle: '确认取消',
       confirmCancelMessage: '确认取消订单 {orderNo}？',
+      allTypes: '全部类型',
+      orderType: {
+        subscription: '订阅',
+        balance: '余额充值'
+      },
+      refund: '退款',
+      refundSuccess: '订单已退款',
+      confirmRefundTitle: '确认退款',
+      confirmRefundMessage: '确定全额退款订单 {orderNo} 吗？已发放的订阅天数将被收回。',
+      confirmRefundBalanceMessage: '确定全额退款订单 {orderNo} 吗？已到账的余额将被扣回。',
+      refundReason: '退款原因',
+      refundReasonPlaceholder: '可选，将提交给支付渠道',
       status: {
         pending: '待支付',
         paid: '已支付',
-        canceled: '已取消'
+        canceled: '已取消',
+        refunding: '退款中',
+        refunded: '已退款'
       },
       columns: {
         orderNo: '订单号',
@@ -1715,6 +1936,11 @@ export default {
       dataExport: '导出',
       dataExportSelected: '导出选中',
       dataExportIncludeProxies: '导出代理（导出账号关联的代理）',
+      dataPassphrase: '加密口令',
+      dataExportPassphrasePlaceholder: '可选，至少 8 个字符',
+      dataExportPassphraseHint: '设置后文件中的账号凭证将被加密，导入时需要输入相同口令',
+      dataImportPassphrasePlaceholder: '导入加密文件时必填',
+      dataImportPassphraseHint: '未加密的文件无需填写',
       dataImport: '导入',
       dataExportConfirmMessage: '导出的数据包含账号与代理的敏感信息，请妥善保存。',
       dataExportConfirm: '确认导出',
@@ -3071,6 +3297,7 @@ export default {
       allBillingTypes: '全部计费类型',
       billingTypeBalance: '钱包余额',
       billingTypeSubscription: '订阅套餐',
+      billingTypeResponseCache: '响应缓存',
       ipAddress: 'IP',
       cleanup: {
         button: '清理',
@@ -3398,6 +3625,8 @@ export default {
         },
         loading: '加载中…',
         requestId: '请求 ID',
+        traceId: 'Trace ID',
+        openTrace: '查看链路',
         time: '时间',
         phase: '阶段',
         status: '状态码',
@@ -3794,6 +4023,9 @@ export default {
         refreshInterval15s: '15 秒',
         refreshInterval30s: '30 秒',
         refreshInterval60s: '60 秒',
+        tracing: '链路追踪',
+        traceUrlTemplate: 'Trace 链接模板',
+        traceUrlTemplateHint: '用于从错误日志跳转到链路追踪系统，{placeholder} 会被替换为 Trace ID，例如 {example}',
         autoRefreshCountdown: '自动刷新：{seconds}s',
         validation: {
           title: '请先修正以下问题',
@@ -4040,7 +4272,45 @@ export default {
         xunhuReturnUrlPlaceholder: 'https://your-domain.com/purchase',
         xunhuPlugins: '支付渠道（plugins）',
         xunhuPluginsPlaceholder: 'wechat,alipay（可选）',
-        pluginsHint: '留空由虎皮椒默认选择'
+        pluginsHint: '留空由虎皮椒默认选择',
+        providerStripe: 'Stripe Checkout',
+        providerAlipay: '支付宝（官方）',
+        providerWechat: '微信支付（官方 Native 扫码）',
+        stripeSecretKey: 'Stripe Secret Key',
+        stripeWebhookSecret: 'Webhook 签名密钥',
+        stripeWebhookHint: '在 Stripe 中将 Webhook 指向 https://your-domain.com/api/v1/payment/stripe/notify，并订阅 checkout.session 相关事件',
+        stripeCurrency: '币种',
+        stripeCurrencyPlaceholder: 'usd',
+        stripeSuccessUrl: '支付成功跳转地址',
+        stripeSuccessUrlPlaceholder: 'https://your-domain.com/purchase',
+        stripeCancelUrl: '取消支付跳转地址',
+        stripeCancelUrlPlaceholder: '留空则与成功地址相同',
+        alipayAppId: '支付宝应用 ID',
+        alipayPrivateKey: '应用私钥（RSA2）',
+        alipayPublicKey: '支付宝公钥',
+        alipayPublicKeyPlaceholder: '支付宝公钥（PEM 或 base64）',
+        alipayGateway: '网关地址',
+        alipayGatewayPlaceholder: 'https://openapi.alipay.com/gateway.do',
+        alipayNotifyUrl: '异步通知地址',
+        alipayNotifyUrlPlaceholder: 'https://your-domain.com/api/v1/payment/alipay/notify',
+        alipayReturnUrl: '同步跳转地址',
+        wechatAppId: '微信 AppID',
+        wechatMchId: '商户号',
+        wechatSerialNo: '商户 API 证书序列号',
+        wechatPrivateKey: '商户 API 私钥',
+        wechatApiV3Key: 'APIv3 密钥',
+        wechatApiV3KeyHint: '固定 32 位，留空表示不修改',
+        wechatPlatformPublicKey: '平台公钥 / 平台证书',
+        wechatPlatformPublicKeyPlaceholder: '微信支付平台公钥或平台证书（PEM）',
+        wechatNotifyUrl: '回调通知地址',
+        wechatNotifyUrlPlaceholder: 'https://your-domain.com/api/v1/payment/wechatpay/notify',
+        topUpEnabled: '启用余额充值',
+        topUpEnabledHint: '允许用户通过当前支付渠道在线充值余额',
+        topUpMinAmount: '单笔最小金额',
+        topUpMaxAmount: '单笔最大金额',
+        topUpMaxAmountHint: '0 表示不限',
+        topUpRate: '到账比例',
+        topUpRateHint: '每支付 1 单位货币到账的余额'
       },
       soraClient: {
         title: 'Sora 客户端',
@@ -4139,6 +4409,48 @@ export default {
         securityWarning: '警告：此密钥拥有完整的管理员权限，请妥善保管。',
         usage: '使用方法：在请求头中添加 x-api-key: <your-admin-api-key>'
       },
+      adminTokens: {
+        title: '管理员 API Token',
+        description:
+          '按 scope 授权的具名 Token，供对账、开通、监控等自动化系统使用，通过 x-api-key 请求头传递。',
+        create: '创建 Token',
+        edit: '编辑 Token',
+        empty: '暂无管理员 API Token',
+        name: '名称',
+        scopes: '权限范围',
+        fullAccess: '完全访问（所有资源的读写权限）',
+        access: {
+          none: '无',
+          read: '只读',
+          write: '读写'
+        },
+        resources: {
+          dashboard: '仪表盘',
+          users: '用户',
+          groups: '分组',
+          accounts: '账号',
+          proxies: '代理',
+          billing: '计费',
+          usage: '使用记录',
+          ops: '运维监控',
+          announcements: '公告',
+          settings: '系统设置',
+          system: '系统管理',
+          audit: '审计日志'
+        },
+        ipWhitelist: 'IP 白名单',
+        ipWhitelistHint: '每行一个 IP 或 CIDR，留空表示不限制来源',
+        expiresAt: '过期时间',
+        expiresAtHint: '留空表示永不过期',
+        lastUsed: '最后使用',
+        never: '从未',
+        expired: '已过期',
+        revoke: '吊销',
+        revokeConfirm: '确定吊销 Token「{name}」吗？使用它的集成将立即失效。',
+        revoked: 'Token 已吊销',
+        saved: 'Token 已更新',
+        scopesRequired: '请至少选择一个权限范围'
+      },
       soraS3: {
         title: 'Sora S3 存储配置',
         description: '以多配置列表方式管理 Sora S3 端点，并可切换生效配置',
@@ -4390,11 +4702,24 @@ export default {
       createdAt: '创建时间',
       actions: '操作'
     },
+    topUp: {
+      title: '余额充值',
+      description: '在线支付为账户充值余额（每支付 1 单位货币到账 {rate} 余额）。',
+      amount: '充值金额',
+      credit: '预计到账',
+      submit: '立即充值',
+      range: '金额范围 {min} ~ {max}',
+      minOnly: '最低 {min}',
+      orderLabel: '余额充值',
+      success: '支付成功，余额已到账。'
+    },
     payNow: '立即支付',
     orderStatus: {
       pending: '待支付',
       paid: '已支付',
-      canceled: '已取消'
+      canceled: '已取消',
+      refunding: '退款中',
+      refunded: '已退款'
     }
   },
 
@@ -83,6 +83,15 @@ const routes: RouteRecordRaw[] = [
       title: 'LinuxDo OAuth Callback'
     }
   },
+  {
+    path: '/auth/sso/callback',
+    name: 'SSOCallback',
+    component: () => import('@/views/auth/SSOCallbackView.vue'),
+    meta: {
+      requiresAuth: false,
+      title: 'SSO Callback'
+    }
+  },
   {
     path: '/forgot-password',
     name: 'ForgotPassword',
@@ -189,6 +198,18 @@ const routes: RouteRecordRaw[] = [
       descriptionKey: 'userSubscriptions.description'
     }
   },
+  {
+    path: '/organizations',
+    name: 'Organizations',
+    component: () => import('@/views/user/OrganizationsView.vue'),
+    meta: {
+      requiresAuth: true,
+      requiresAdmin: false,
+      title: 'Organizations',
+      titleKey: 'organizations.title',
+      descriptionKey: 'organizations.description'
+    }
+  },
   {
     path: '/purchase',
     name: 'PurchaseSubscription',
@@ -302,6 +323,30 @@ const routes: RouteRecordRaw[] = [
       descriptionKey: 'admin.orders.description'
     }
   },
+  {
+    path: '/admin/organizations',
+    name: 'AdminOrganizations',
+    component: () => import('@/views/admin/OrganizationsView.vue'),
+    meta: {
+      requiresAuth: true,
+      requiresAdmin: true,
+      title: 'Organization Management',
+      titleKey: 'admin.organizations.title',
+      descriptionKey: 'admin.organizations.description'
+    }
+  },
+  {
+    path: '/admin/audit-logs',
+    name: 'AdminAuditLogs',
+    component: () => import('@/views/admin/AuditLogsView.vue'),
+    meta: {
+      requiresAuth: true,
+      requiresAdmin: true,
+      title: 'Audit Log',
+      titleKey: 'admin.auditLogs.title',
+      descriptionKey: 'admin.auditLogs.description'
+    }
+  },
   {
     path: '/admin/accounts',
     name: 'AdminAccounts',
@@ -506,8 +551,10 @@ router.beforeEach((to, _from, next) => {
       '/admin/groups',
       '/admin/subscriptions',
       '/admin/orders',
+      '/admin/organizations',
       '/admin/redeem',
       '/subscriptions',
+      '/organizations',
       '/redeem'
     ]
 
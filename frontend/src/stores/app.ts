@@ -331,6 +331,7 @@ export const useAppStore = defineStore('app', () => {
         custom_menu_items: [],
         purchase_instructions: '',
         linuxdo_oauth_enabled: false,
+        sso_providers: [],
         sora_client_enabled: false,
         version: siteVersion.value
       }
@@ -106,10 +106,19 @@ export interface PublicSettings {
   custom_menu_items: CustomMenuItem[]
   purchase_instructions: string
   linuxdo_oauth_enabled: boolean
+  sso_providers?: SSOProvider[]
   sora_client_enabled: boolean
   version: string
 }
 
+export type SSOProviderType = 'oidc' | 'github' | 'google'
+
+export interface SSOProvider {
+  id: string
+  name: string
+  type: SSOProviderType
+}
+
 export interface AuthResponse {
   access_token: string
   refresh_token?: string  // New: Refresh Token for token renewal
@@ -400,14 +409,31 @@ export interface Group {
   updated_at: string
 }
 
+export type ModelFallbackCondition = 'rate_limited' | 'overloaded' | 'prompt_too_long'
+
+// 分组内有序模型降级链：请求模型命中第 i 项时依次降级到后续模型
+export interface ModelFallbackChain {
+  models: string[]
+  // 为空表示任一条件均触发
+  conditions?: ModelFallbackCondition[]
+}
+
 export interface AdminGroup extends Group {
   // 模型路由配置（仅管理员可见，内部信息）
   model_routing: Record<string, number[]> | null
   model_routing_enabled: boolean
 
+  // 模型降级链（仅 anthropic/antigravity 平台使用）
+  model_fallback_chains?: ModelFallbackChain[] | null
+
   // MCP XML 协议注入（仅 antigravity 平台使用）
   mcp_xml_inject: boolean
 
+  // 精确响应缓存（temperature=0 的确定性请求，命中按比例计费）
+  response_cache_enabled?: boolean
+  response_cache_ttl_seconds?: number
+  response_cache_price_ratio?: number
+
   // 支持的模型系列（仅 antigravity 平台使用）
   supported_model_scopes?: string[]
 
@@ -424,7 +450,8 @@ export interface AdminGroup extends Group {
 export interface ApiKey {
   id: number
   user_id: number
-  key: string
+  key?: string // Full key, only returned once on creation
+  key_prefix: string // Display prefix for listing and search
   name: string
   group_id: number | null
   status: 'active' | 'inactive' | 'quota_exhausted' | 'expired'
@@ -446,9 +473,29 @@ export interface ApiKey {
   window_5h_start: string | null
   window_1d_start: string | null
   window_7d_start: string | null
+  allowed_models: string[] | null // Allowed model patterns (* wildcard), empty = all
+  denied_models: string[] | null // Denied model patterns (* wildcard)
+  model_aliases: Record<string, string> | null // alias -> real model
+  rpm_limit: number // Max requests per minute (0 = unlimited)
+  tpm_limit: number // Max tokens per minute (0 = unlimited)
+  max_concurrency: number // Max in-flight requests (0 = unlimited)
+  organization_id: number | null // Organization key: billed from the shared organization balance
+  organization?: Organization
 }
 
-export interface CreateApiKeyRequest {
+export interface ApiKeyThroughputLimits {
+  rpm_limit?: number
+  tpm_limit?: number
+  max_concurrency?: number
+}
+
+export interface ApiKeyModelPolicy {
+  allowed_models?: string[]
+  denied_models?: string[]
+  model_aliases?: Record<string, string>
+}
+
+export interface CreateApiKeyRequest extends ApiKeyModelPolicy, ApiKeyThroughputLimits {
   name: string
   group_id?: number | null
   custom_key?: string // Optional custom API Key
@@ -459,9 +506,10 @@ export interface CreateApiKeyRequest {
   rate_limit_5h?: number
   rate_limit_1d?: number
   rate_limit_7d?: number
+  organization_id?: number | null
 }
 
-export interface UpdateApiKeyRequest {
+export interface UpdateApiKeyRequest extends ApiKeyModelPolicy, ApiKeyThroughputLimits {
   name?: string
   group_id?: number | null
   status?: 'active' | 'inactive'
@@ -503,6 +551,10 @@ export interface CreateGroupRequest {
   fallback_group_id_on_invalid_request?: number | null
   mcp_xml_inject?: boolean
   supported_model_scopes?: string[]
+  response_cache_enabled?: boolean
+  response_cache_ttl_seconds?: number
+  response_cache_price_ratio?: number
+  model_fallback_chains?: ModelFallbackChain[]
   // 从指定分组复制账号
   copy_accounts_from_group_ids?: number[]
 }
@@ -535,16 +587,23 @@ export interface UpdateGroupRequest {
   fallback_group_id_on_invalid_request?: number | null
   mcp_xml_inject?: boolean
   supported_model_scopes?: string[]
+  response_cache_enabled?: boolean
+  response_cache_ttl_seconds?: number
+  response_cache_price_ratio?: number
+  model_fallback_chains?: ModelFallbackChain[]
   copy_accounts_from_group_ids?: number[]
 }
 
-export type SubscriptionOrderStatus = 'pending' | 'paid' | 'canceled'
+export type SubscriptionOrderStatus = 'pending' | 'paid' | 'canceled' | 'refunding' | 'refunded'
+
+export type SubscriptionOrderType = 'subscription' | 'balance'
 
 export interface SubscriptionOrder {
   id: number
   order_no: string
+  order_type: SubscriptionOrderType
   user_id: number
-  group_id: number
+  group_id?: number | null
   subscription_id?: number | null
   payment_provider: string
   payment_url: string
@@ -555,9 +614,11 @@ export interface SubscriptionOrder {
   status: SubscriptionOrderStatus
   amount: number
   currency: string
+  credit_amount: number
   validity_days: number
   paid_at?: string | null
   canceled_at?: string | null
+  refunded_at?: string | null
   notes: string
   created_at: string
   updated_at: string
@@ -569,6 +630,97 @@ export interface AdminSubscriptionOrder extends SubscriptionOrder {
   user?: AdminUser
 }
 
+export interface TopUpConfig {
+  enabled: boolean
+  min_amount: number
+  max_amount: number
+  rate: number
+  currency: string
+}
+
+// ==================== Organization Types ====================
+
+export type OrganizationRole = 'owner' | 'admin' | 'member'
+
+export interface OrganizationUser {
+  id: number
+  email: string
+  username: string
+}
+
+export interface Organization {
+  id: number
+  name: string
+  owner_user_id: number
+  balance: number
+  status: 'active' | 'disabled'
+  member_count?: number
+  created_at: string
+  updated_at: string
+  owner?: OrganizationUser
+}
+
+export interface OrganizationMember {
+  id: number
+  organization_id: number
+  user_id: number
+  role: OrganizationRole
+  monthly_limit_usd: number // 0 = unlimited
+  current_monthly_usage: number
+  created_at: string
+  updated_at: string
+  user?: OrganizationUser
+  organization?: Organization
+}
+
+export interface OrganizationDetail {
+  organization: Organization
+  membership: OrganizationMember
+}
+
+export interface OrganizationApiKey {
+  id: number
+  user_id: number
+  name: string
+  masked_key: string
+  group_id: number | null
+  status: string
+  quota: number
+  quota_used: number
+  last_used_at: string | null
+  created_at: string
+  user?: OrganizationUser
+  group?: Group
+}
+
+export interface OrganizationMemberUsage {
+  user_id: number
+  email: string
+  requests: number
+  tokens: number
+  cost: number
+  actual_cost: number
+}
+
+export interface OrganizationDailyUsage {
+  date: string
+  requests: number
+  tokens: number
+  cost: number
+  actual_cost: number
+}
+
+export interface OrganizationUsageSummary {
+  start_time: string
+  end_time: string
+  total_requests: number
+  total_tokens: number
+  total_cost: number
+  total_actual_cost: number
+  members: OrganizationMemberUsage[]
+  daily: OrganizationDailyUsage[]
+}
+
 // ==================== Account & Proxy Types ====================
 
 export type AccountPlatform = 'anthropic' | 'openai' | 'gemini' | 'antigravity' | 'sora'
@@ -611,6 +763,29 @@ export interface Proxy {
   updated_at: string
 }
 
+export interface ProxyPoolMember {
+  proxy_id: number
+  proxy?: Proxy
+  healthy: boolean
+  fail_count: number
+  latency_ms?: number
+  last_error?: string
+  last_checked_at?: string
+}
+
+export interface ProxyPool {
+  id: number
+  name: string
+  description: string
+  status: 'active' | 'disabled'
+  account_count: number
+  member_count: number
+  healthy_count: number
+  members: ProxyPoolMember[]
+  created_at: string
+  updated_at: string
+}
+
 export interface ProxyAccountSummary {
   id: number
   name: string
@@ -706,6 +881,7 @@ export interface Account {
     model_rate_limits?: Record<string, { rate_limited_at: string; rate_limit_reset_at: string }>
   } & Record<string, unknown>)
   proxy_id: number | null
+  proxy_pool_id?: number | null // Proxy pool (takes precedence over proxy_id)
   concurrency: number
   load_factor?: number | null
   current_concurrency?: number // Real-time concurrency count from Redis
@@ -913,10 +1089,20 @@ export interface AdminDataPayload {
   type?: string
   version?: number
   exported_at: string
+  encryption?: AdminDataEncryption
   proxies: AdminDataProxy[]
   accounts: AdminDataAccount[]
 }
 
+export interface AdminDataEncryption {
+  algorithm: string
+  kdf: string
+  salt: string
+  n: number
+  r: number
+  p: number
+}
+
 export interface AdminDataProxy {
   proxy_key: string
   name: string
@@ -933,7 +1119,8 @@ export interface AdminDataAccount {
   notes?: string | null
   platform: AccountPlatform
   type: AccountType
-  credentials: Record<string, unknown>
+  credentials?: Record<string, unknown>
+  encrypted_credentials?: string
   extra?: Record<string, unknown>
   proxy_key?: string | null
   concurrency: number
@@ -1060,6 +1247,47 @@ export interface UsageCleanupTask {
   updated_at: string
 }
 
+export type UsageExportFormat = 'csv' | 'jsonl'
+
+export type UsageExportJobStatus = 'pending' | 'running' | 'succeeded' | 'failed' | 'expired'
+
+export interface UsageExportParams {
+  format?: UsageExportFormat
+  start_date: string
+  end_date: string
+  api_key_id?: number
+  model?: string
+  request_type?: UsageRequestType
+  stream?: boolean
+  billing_type?: number | null
+  timezone?: string
+}
+
+export interface AdminUsageExportParams extends UsageExportParams {
+  user_id?: number
+  account_id?: number
+  group_id?: number
+}
+
+export interface UsageExportJob {
+  id: number
+  scope: 'user' | 'admin'
+  format: UsageExportFormat
+  status: UsageExportJobStatus
+  filters: UsageCleanupFilters
+  created_by: number
+  row_count: number
+  file_size: number
+  storage?: string
+  error_message?: string | null
+  started_at?: string | null
+  finished_at?: string | null
+  expires_at?: string | null
+  created_at: string
+  download_url?: string
+  download_url_expires_at?: string | null
+}
+
 export interface RedeemCode {
   id: number
   code: string
@@ -1473,6 +1701,17 @@ export interface UpdatePromoCodeRequest {
   notes?: string
 }
 
+// ==================== SSO Identity Types ====================
+
+export interface UserIdentity {
+  id: number
+  provider: string
+  provider_name: string
+  email: string
+  created_at: string
+  last_login_at: string | null
+}
+
 // ==================== TOTP (2FA) Types ====================
 
 export interface TotpStatus {
@@ -1563,3 +1802,143 @@ export interface UpdateScheduledTestPlanRequest {
   enabled?: boolean
   max_results?: number
 }
+
+// ==================== Statement Types ====================
+
+export type StatementLineType =
+  | 'usage'
+  | 'subscription'
+  | 'top_up'
+  | 'refund'
+  | 'redeem'
+  | 'promo'
+  | 'adjustment'
+
+export interface StatementLine {
+  line_no: number
+  line_type: StatementLineType
+  description: string
+  group_id?: number
+  group_name?: string
+  model?: string
+  billing_type?: number
+  reference?: string
+  request_count: number
+  input_tokens: number
+  output_tokens: number
+  cache_creation_tokens: number
+  cache_read_tokens: number
+  image_count: number
+  video_count: number
+  total_cost: number
+  amount: number
+  occurred_at?: string
+}
+
+export interface Statement {
+  id: number
+  user_id: number
+  period: string // YYYY-MM
+  period_start: string
+  period_end: string
+  timezone: string
+  currency: string
+  user_email: string
+  username: string
+  request_count: number
+  usage_total_cost: number
+  usage_balance_cost: number
+  usage_subscription_cost: number
+  subscription_fees: number
+  top_ups: number
+  refunds: number
+  credits: number
+  net_payments: number
+  line_count: number
+  emailed_at?: string
+  created_at: string
+  lines?: StatementLine[]
+}
+
+// ==================== Webhook Types ====================
+
+export type UserWebhookEventType =
+  | 'sora.generation.completed'
+  | 'sora.generation.failed'
+  | 'balance.low'
+  | 'api_key.quota_low'
+  | 'api_key.rate_limit_low'
+  | 'subscription.expiring'
+  | 'subscription.expired'
+  | 'order.paid'
+  | 'webhook.ping'
+
+export type UserWebhookDeliveryStatus = 'pending' | 'succeeded' | 'failed'
+
+export interface UserWebhook {
+  id: number
+  api_key_id: number | null // null = all events of the user
+  name: string
+  url: string
+  secret?: string // only returned on create and rotate-secret
+  secret_hint: string
+  events: UserWebhookEventType[]
+  enabled: boolean
+  balance_threshold: number
+  usage_threshold_percent: number
+  expiry_notice_days: number
+  last_delivery_at?: string
+  last_delivery_status?: UserWebhookDeliveryStatus
+  created_at: string
+  updated_at: string
+}
+
+export interface CreateUserWebhookRequest {
+  name?: string
+  url: string
+  api_key_id?: number | null
+  events: UserWebhookEventType[]
+  enabled?: boolean
+  balance_threshold?: number
+  usage_threshold_percent?: number
+  expiry_notice_days?: number
+}
+
+export interface UpdateUserWebhookRequest {
+  name?: string
+  url?: string
+  api_key_id?: number // 0 = switch to user level
+  events?: UserWebhookEventType[]
+  enabled?: boolean
+  balance_threshold?: number
+  usage_threshold_percent?: number
+  expiry_notice_days?: number
+}
+
+export interface UserWebhookDelivery {
+  id: number
+  webhook_id: number
+  event_id: string
+  event_type: UserWebhookEventType
+  payload: Record<string, unknown>
+  status: UserWebhookDeliveryStatus
+  attempts: number
+  max_attempts: number
+  next_attempt_at?: string
+  response_status: number
+  response_body: string
+  last_error: string
+  duration_ms: number
+  delivered_at?: string
+  created_at: string
+}
+
+// ==================== Notification Types ====================
+
+export interface UserNotificationSettings {
+  enabled: boolean
+  balance_threshold: number // USD, 0 = off
+  api_key_quota_percent: number // 0 = off
+  subscription_usage_percent: number // 0 = off
+  expiry_notice_days: number // 0 = off
+}